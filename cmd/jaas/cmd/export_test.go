@@ -46,3 +46,12 @@ func NewGrantCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginPro
 
 	return modelcmd.WrapBase(cmd)
 }
+
+func NewWhoamiCommandForTesting(store jujuclient.ClientStore, lp jujuapi.LoginProvider) cmd.Command {
+	cmd := &whoamiCommand{
+		store:    store,
+		dialOpts: cmdtest.TestDialOpts(lp),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var (
+	whoamiCommandDoc = `
+whoami shows the identity you are authenticated to JAAS as, together with
+a summary of your effective permissions: the groups you are a member of,
+whether you are a JAAS administrator, your model quota and the number of
+models, controllers and clouds you can access.
+
+This is a quick sanity check when debugging access problems. As Juju has
+its own whoami command this command is run as "juju jaas whoami".
+`
+
+	whoamiExamples = `
+    juju jaas whoami
+    juju jaas whoami --format yaml
+`
+)
+
+// NewWhoamiCommand returns a command to show the authenticated identity
+// and a summary of its effective permissions.
+func NewWhoamiCommand() cmd.Command {
+	cmd := &whoamiCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// whoamiCommand shows the authenticated identity and a summary of its
+// effective permissions.
+type whoamiCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+func (c *whoamiCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:     "whoami",
+		Purpose:  "Show your identity and effective permissions",
+		Doc:      whoamiCommandDoc,
+		Examples: whoamiExamples,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *whoamiCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatWhoamiTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *whoamiCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *whoamiCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	summary, err := client.Whoami()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, summary)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// formatWhoamiTabular writes a summary of the authenticated identity.
+func formatWhoamiTabular(writer io.Writer, value interface{}) error {
+	summary, ok := value.(*apiparams.IdentitySummary)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", summary, value))
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{TabWriter: tw}
+
+	groups := "-"
+	if len(summary.Groups) > 0 {
+		groups = strings.Join(summary.Groups, ", ")
+	}
+	w.Println("User:", summary.UserTag)
+	w.Println("Display name:", summary.DisplayName)
+	w.Println("Groups:", groups)
+	w.Println("Administrator:", summary.ControllerAdmin)
+	w.Println("Models:", summary.Models)
+	w.Println("Controllers:", summary.Controllers)
+	w.Println("Clouds:", summary.Clouds)
+	w.Println("Model quota:", formatQuotaUsage(summary.Quota.Models))
+	w.Println("Machine quota:", formatQuotaUsage(summary.Quota.Machines))
+	w.Println("Core quota:", formatQuotaUsage(summary.Quota.Cores))
+
+	tw.Flush()
+	return nil
+}
+
+func formatQuotaUsage(u apiparams.QuotaUsage) string {
+	if u.Limit <= 0 {
+		return fmt.Sprintf("%d used, unlimited", u.Used)
+	}
+	return fmt.Sprintf("%d/%d used", u.Used, u.Limit)
+}
@@ -0,0 +1,66 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jaas/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type whoamiSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&whoamiSuite{})
+
+func (s *whoamiSuite) TestWhoami(c *gc.C) {
+	ctx := context.Background()
+	group, err := s.JIMM.Database.AddGroup(ctx, "test-group")
+	c.Assert(err, gc.IsNil)
+	err = s.OFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("bob@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, gc.IsNil)
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewWhoamiCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `User:\s+user-bob@canonical.com
+Display name:\s+bob
+Groups:\s+test-group
+Administrator:\s+false
+Models:\s+0
+Controllers:\s+0
+Clouds:\s+\d+
+Model quota:\s+0 used, unlimited
+Machine quota:\s+0 used, unlimited
+Core quota:\s+0 used, unlimited
+`)
+}
+
+func (s *whoamiSuite) TestWhoamiAdministrator(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewWhoamiCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)user-tag: user-alice@canonical.com
+display-name: alice
+groups: \[\]
+controller-admin: true
+.*`)
+}
+
+func (s *whoamiSuite) TestWhoamiTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewWhoamiCommandForTesting(s.ClientStore(), bClient), "bob")
+	c.Assert(err, gc.ErrorMatches, "too many args")
+}
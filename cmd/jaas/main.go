@@ -30,6 +30,7 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 	serviceAccountCmd.Register(cmd.NewListServiceAccountCredentialsCommand())
 	serviceAccountCmd.Register(cmd.NewUpdateCredentialCommand())
 	serviceAccountCmd.Register(cmd.NewGrantCommand())
+	serviceAccountCmd.Register(cmd.NewWhoamiCommand())
 	return serviceAccountCmd
 }
 
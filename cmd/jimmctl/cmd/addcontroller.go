@@ -23,9 +23,15 @@ var (
 	addControllerCommandDoc = `
 	add-controller command adds a controller to jimm.
 
+	With --dry-run the controller is contacted and validated, and the
+	controller that would be added is printed, but nothing is stored.
+	This checks the connectivity and credentials, the clouds, version
+	and facade support of the controller before it is added.
+
 	Example:
 		jimmctl add-controller <filename> 
 		jimmctl add-controller <filename> --format json
+		jimmctl add-controller <filename> --dry-run
 `
 )
 
@@ -46,6 +52,7 @@ type addControllerCommand struct {
 	store    jujuclient.ClientStore
 	dialOpts *jujuapi.DialOpts
 	file     cmd.FileVar
+	dryRun   bool
 }
 
 func (c *addControllerCommand) Info() *cmd.Info {
@@ -64,6 +71,7 @@ func (c *addControllerCommand) SetFlags(f *gnuflag.FlagSet) {
 		"json": cmd.FormatJson,
 	})
 	c.file.StdinMarkers = stdinMarkers
+	f.BoolVar(&c.dryRun, "dry-run", false, "validate the controller without adding it")
 }
 
 // Init implements the cmd.Command interface.
@@ -94,6 +102,9 @@ func (c *addControllerCommand) Run(ctxt *cmd.Context) error {
 	if err = unmarshalYAMLFile(ctxt, &params, c.file); err != nil {
 		return errors.E(err)
 	}
+	if c.dryRun {
+		params.DryRun = true
+	}
 
 	client := api.NewClient(apiCaller)
 	info, err := client.AddController(&params)
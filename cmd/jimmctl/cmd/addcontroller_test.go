@@ -4,6 +4,7 @@ package cmd_test
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 
@@ -13,6 +14,8 @@ import (
 
 	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
 	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
@@ -117,3 +120,43 @@ func writeYAMLTempFile(c *gc.C, payload interface{}) (string, string) {
 	c.Assert(err, gc.Equals, nil)
 	return dir, tmpfn
 }
+
+func (s *addControllerSuite) TestAddControllerDryRun(c *gc.C) {
+	info := s.APIInfo(c)
+	params := apiparams.AddControllerRequest{
+		Name:          "controller-1",
+		CACertificate: info.CACert,
+		APIAddresses:  info.Addrs,
+		Username:      info.Tag.Id(),
+		Password:      info.Password,
+	}
+	tmpdir, tmpfile := writeYAMLTempFile(c, params)
+	defer os.RemoveAll(tmpdir)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	ctx, err := cmdtesting.RunCommand(c, cmd.NewAddControllerCommandForTesting(s.ClientStore(), bClient), tmpfile, "--dry-run", "--format", "json")
+	c.Assert(err, gc.IsNil)
+	var ci apiparams.ControllerInfo
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(ctx)), &ci)
+	c.Assert(err, gc.IsNil)
+	c.Check(ci.Name, gc.Equals, "controller-1")
+	c.Check(ci.UUID, gc.Equals, info.ControllerUUID)
+	c.Check(ci.CloudTag, gc.Equals, "cloud-"+jimmtest.TestCloudName)
+	c.Check(ci.CloudRegion, gc.Equals, jimmtest.TestCloudRegionName)
+	c.Check(ci.AgentVersion, gc.Not(gc.Equals), "")
+
+	// Nothing was stored.
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = s.JIMM.Database.GetController(context.Background(), &ctl)
+	c.Check(errors.ErrorCode(err), gc.Equals, errors.CodeNotFound)
+	username, _, err := s.JIMM.CredentialStore.GetControllerCredentials(context.Background(), "controller-1")
+	c.Assert(err, gc.IsNil)
+	c.Check(username, gc.Equals, "")
+
+	// The controller can then be added.
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddControllerCommandForTesting(s.ClientStore(), bClient), tmpfile)
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.GetController(context.Background(), &ctl)
+	c.Assert(err, gc.IsNil)
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const agentVersionReportDoc = `
+	agent-version-report reports the juju agent versions running in every
+	model, as last seen by JIMM, compared with the version of the
+	controller hosting the model. A model is outdated if the model, or
+	any of its machine or unit agents, runs an older version than its
+	controller. Models are attributed to cost centers as in usage-report.
+
+	The tabular format shows the totals for each cost center. With
+	--outdated only the outdated models are reported, and the tabular
+	format lists them.
+
+	Example:
+		jimmctl agent-version-report
+		jimmctl agent-version-report --outdated
+		jimmctl agent-version-report --format yaml
+`
+
+// NewAgentVersionReportCommand returns a command to report the agent
+// versions running in every model.
+func NewAgentVersionReportCommand() cmd.Command {
+	cmd := &agentVersionReportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// agentVersionReportCommand reports the agent versions running in every
+// model.
+type agentVersionReportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	outdated bool
+}
+
+// Info implements Command.Info.
+func (c *agentVersionReportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "agent-version-report",
+		Purpose: "Report the agent versions running in every model.",
+		Doc:     agentVersionReportDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *agentVersionReportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAgentVersionReportTabular,
+	})
+	f.BoolVar(&c.outdated, "outdated", false, "only report outdated models")
+}
+
+// Init implements the cmd.Command interface.
+func (c *agentVersionReportCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *agentVersionReportCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	report, err := client.AgentVersionReport()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	var value interface{} = report
+	if c.outdated {
+		models := []apiparams.ModelAgentVersions{}
+		for _, m := range report.Models {
+			if m.Outdated {
+				models = append(models, m)
+			}
+		}
+		value = models
+	}
+	err = c.out.Write(ctxt, value)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatAgentVersionReportTabular(writer io.Writer, value interface{}) error {
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	switch v := value.(type) {
+	case *apiparams.AgentVersionReport:
+		table.AddRow("Cost center", "Models", "Outdated models", "Outdated agents")
+		for _, cc := range v.CostCenters {
+			name := cc.CostCenter
+			if name == "" {
+				name = "-"
+			}
+			table.AddRow(name, cc.Models, cc.OutdatedModels, cc.OutdatedAgents)
+		}
+	case []apiparams.ModelAgentVersions:
+		table.AddRow("Model", "Owner", "Controller", "Controller version", "Model version", "Outdated agents")
+		for _, m := range v {
+			table.AddRow(m.Name, m.Owner, m.Controller, m.ControllerVersion, m.ModelVersion, m.OutdatedAgents)
+		}
+	default:
+		return errors.E(fmt.Sprintf("unexpected value of type %T", value))
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type agentVersionReportSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&agentVersionReportSuite{})
+
+func (s *agentVersionReportSuite) TestAgentVersionReport(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err := s.JIMM.Database.GetController(ctx, &ctl)
+	c.Assert(err, gc.IsNil)
+	ctl.AgentVersion = "3.5.1"
+	err = s.JIMM.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, gc.IsNil)
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err = s.JIMM.Database.GetModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	m.Status.Version = "3.5.1"
+	m.AgentVersions = dbmodel.Int64Map{"3.5.1": 1, "3.4.0": 2}
+	err = s.JIMM.Database.UpdateModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewAgentVersionReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Cost center +Models +Outdated models +Outdated agents\s*
+- +1 +1 +2\s*
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewAgentVersionReportCommandForTesting(s.ClientStore(), bClient), "--outdated")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +Owner +Controller +Controller version +Model version +Outdated agents\s*
+model-1 +charlie@canonical.com +controller-1 +3.5.1 +3.5.1 +2\s*
+`)
+}
+
+func (s *agentVersionReportSuite) TestAgentVersionReportUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAgentVersionReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *agentVersionReportSuite) TestAgentVersionReportInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAgentVersionReportCommandForTesting(s.ClientStore(), bClient), "a")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
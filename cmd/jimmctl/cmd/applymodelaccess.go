@@ -0,0 +1,163 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const applyModelAccessDoc = `
+	apply-model-access makes the access users and groups hold on models
+	match a YAML document, as written by export-model-access:
+
+		models:
+		- model: alice@canonical.com/model-1
+		  users:
+		    bob@canonical.com: write
+		  groups:
+		    ops: admin
+
+	Users and groups not listed for a model lose their access to it, the
+	access of the model's owner is left unchanged. The whole document is
+	checked before any change is made, and applying a document that
+	already matches makes no changes. With --dry-run the changes are
+	reported without being made. The document is read from standard input
+	if the file is "-".
+
+	Example:
+		jimmctl apply-model-access access.yaml --dry-run
+		jimmctl apply-model-access access.yaml
+`
+
+// NewApplyModelAccessCommand returns a command to apply a model access
+// document.
+func NewApplyModelAccessCommand() cmd.Command {
+	cmd := &applyModelAccessCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// applyModelAccessCommand applies a model access document.
+type applyModelAccessCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	document cmd.FileVar
+	dryRun   bool
+}
+
+// Info implements Command.Info.
+func (c *applyModelAccessCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "apply-model-access",
+		Args:    "<filename>",
+		Purpose: "Make the access held on models match a document.",
+		Doc:     applyModelAccessDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *applyModelAccessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatApplyModelAccessTabular,
+	})
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the changes that would be made without making them")
+}
+
+// Init implements the cmd.Command interface.
+func (c *applyModelAccessCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("filename not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.document.Path = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *applyModelAccessCommand) Run(ctxt *cmd.Context) error {
+	req := apiparams.ApplyModelAccessRequest{
+		DryRun: c.dryRun,
+	}
+	if err := unmarshalYAMLFile(ctxt, &req.Document, c.document); err != nil {
+		return errors.E(err, "cannot read model access document")
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ApplyModelAccess(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatApplyModelAccessTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ApplyModelAccessResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+	if len(resp.Changes) == 0 {
+		fmt.Fprint(writer, "no changes")
+		return nil
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Model", "Kind", "Name", "From", "To")
+	for _, c := range resp.Changes {
+		table.AddRow(c.Model, c.Kind, c.Name, accessOrNone(c.From), accessOrNone(c.To))
+	}
+	fmt.Fprint(writer, table)
+	if resp.DryRun {
+		fmt.Fprint(writer, "\ndry run, no changes made")
+	}
+	return nil
+}
+
+// accessOrNone returns the given access, or "none" if it is empty.
+func accessOrNone(access string) string {
+	if access == "" {
+		return "none"
+	}
+	return access
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const auditControllerAccessDoc = `
+	audit-controller-access reports access held directly on the
+	controllers that was not granted through JIMM: local controller users
+	other than JIMM's own, and model access held by users other than the
+	model owner and JIMM. Nothing is changed on the controllers, use
+	resync-model-access --apply to revoke direct model access. The
+	controllers are queried at a limited rate so this may take some time
+	if there are many models.
+
+	Example:
+		jimmctl audit-controller-access
+		jimmctl audit-controller-access --controller <controller name>
+`
+
+// NewAuditControllerAccessCommand returns a command to audit the access
+// held directly on the controllers.
+func NewAuditControllerAccessCommand() cmd.Command {
+	cmd := &auditControllerAccessCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// auditControllerAccessCommand audits the access held directly on the
+// controllers.
+type auditControllerAccessCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+}
+
+// Info implements Command.Info.
+func (c *auditControllerAccessCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "audit-controller-access",
+		Purpose: "Audit access granted directly on the controllers.",
+		Doc:     auditControllerAccessDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *auditControllerAccessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.controller, "controller", "", "only audit the named controller")
+}
+
+// Init implements the cmd.Command interface.
+func (c *auditControllerAccessCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *auditControllerAccessCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.AuditControllerAccess(&apiparams.AuditControllerAccessRequest{
+		Controller: c.controller,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type auditControllerAccessSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&auditControllerAccessSuite{})
+
+func (s *auditControllerAccessSuite) TestAuditControllerAccess(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewAuditControllerAccessCommandForTesting(s.ClientStore(), bClient), "--controller", "controller-1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "controllers-checked: 1\nmodels-checked: 1\n")
+}
+
+func (s *auditControllerAccessSuite) TestAuditControllerAccessUnknownController(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAuditControllerAccessCommandForTesting(s.ClientStore(), bClient), "--controller", "no-such-controller")
+	c.Assert(err, gc.ErrorMatches, `controller not found.*`)
+}
+
+func (s *auditControllerAccessSuite) TestAuditControllerAccessUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAuditControllerAccessCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *auditControllerAccessSuite) TestAuditControllerAccessTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAuditControllerAccessCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
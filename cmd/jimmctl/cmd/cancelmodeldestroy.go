@@ -0,0 +1,96 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const cancelModelDestroyDoc = `
+	cancel-model-destroy cancels the pending destroy of a model, keeping
+	the model. Only the model's owner and JIMM administrators may cancel a
+	destroy. The model may be given by its UUID or as <owner>/<name>.
+
+	Example:
+		jimmctl cancel-model-destroy 00000002-0000-0000-0000-000000000001
+		jimmctl cancel-model-destroy alice@canonical.com/production
+`
+
+// NewCancelModelDestroyCommand returns a command to cancel the pending
+// destroy of a model.
+func NewCancelModelDestroyCommand() cmd.Command {
+	cmd := &cancelModelDestroyCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// cancelModelDestroyCommand cancels the pending destroy of a model.
+type cancelModelDestroyCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	model    string
+}
+
+// Info implements Command.Info.
+func (c *cancelModelDestroyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "cancel-model-destroy",
+		Args:    "<model>",
+		Purpose: "Cancel the pending destroy of a model.",
+		Doc:     cancelModelDestroyDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *cancelModelDestroyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+}
+
+// Init implements the cmd.Command interface.
+func (c *cancelModelDestroyCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("missing model uuid")
+	}
+	model, args := args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	var err error
+	c.model, err = parseModelRef(model)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *cancelModelDestroyCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.CancelModelDestroy(&apiparams.CancelModelDestroyRequest{
+		ModelTag: c.model,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
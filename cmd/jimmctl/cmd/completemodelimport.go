@@ -0,0 +1,113 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const completeModelImportDoc = `
+	complete-model-import completes the import of a model prepared with
+	prepare-model-import once the model's migration to this JIMM's
+	controller has finished. The model is added to JIMM and its users and
+	groups are granted their access to it.
+
+	Example:
+		jimmctl complete-model-import 1
+`
+
+// NewCompleteModelImportCommand returns a command to complete the import
+// of a model from another JIMM deployment.
+func NewCompleteModelImportCommand() cmd.Command {
+	cmd := &completeModelImportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// completeModelImportCommand completes the import of a model from
+// another JIMM deployment.
+type completeModelImportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	id uint
+}
+
+// Info implements Command.Info.
+func (c *completeModelImportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "complete-model-import",
+		Args:    "<id>",
+		Purpose: "Complete the import of a model from another JIMM deployment.",
+		Doc:     completeModelImportDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *completeModelImportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *completeModelImportCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("import ID not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil || id == 0 {
+		return errors.E(fmt.Sprintf("invalid import ID %q", args[0]))
+	}
+	c.id = uint(id)
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *completeModelImportCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	mi, err := client.CompleteModelImport(&apiparams.CompleteModelImportRequest{
+		ID: c.id,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, mi)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
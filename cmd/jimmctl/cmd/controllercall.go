@@ -0,0 +1,136 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"encoding/json"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var controllerCallDoc = `
+	controller-call calls a controller facade method on a controller
+	connected to JIMM, so that routine controller administration does not
+	require the controller's credentials. Only a restricted set of facade
+	methods may be called, and every call is recorded in the audit log.
+	Controller ConfigSet may only set operational settings, such as log
+	sizes and rate limits, not those controlling who may log in to the
+	controller or its audit log, and requires a recently confirmed
+	identity.
+
+	The method's arguments may be given as a JSON object with --params.
+
+	Example:
+		jimmctl controller-call <controller> Controller ControllerConfig --version 11
+		jimmctl controller-call <controller> Controller ConfigSet --version 11 --params '{"config": {"max-debug-log-duration": "24h"}}'
+`
+
+// NewControllerCallCommand returns a command used to call a controller
+// facade method on a controller.
+func NewControllerCallCommand() cmd.Command {
+	cmd := &controllerCallCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// controllerCallCommand calls a controller facade method on a
+// controller.
+type controllerCallCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+	facade     string
+	method     string
+	version    int
+	params     string
+}
+
+func (c *controllerCallCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "controller-call",
+		Args:    "<controller> <facade> <method>",
+		Purpose: "Call a facade method on a controller.",
+		Doc:     controllerCallDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *controllerCallCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.IntVar(&c.version, "version", 0, "version of the facade")
+	f.StringVar(&c.params, "params", "", "arguments of the method as a JSON object")
+}
+
+// Init implements the cmd.Command interface.
+func (c *controllerCallCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.E("controller, facade and method must be specified")
+	}
+	c.controller, c.facade, c.method, args = args[0], args[1], args[2], args[3:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.params != "" && !json.Valid([]byte(c.params)) {
+		return errors.E("invalid params: not valid JSON")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *controllerCallCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	req := apiparams.ControllerCallRequest{
+		Controller: c.controller,
+		Facade:     c.facade,
+		Version:    c.version,
+		Method:     c.method,
+	}
+	if c.params != "" {
+		req.Params = json.RawMessage(c.params)
+	}
+	resp, err := client.ControllerCall(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	var result interface{}
+	if len(resp.Result) > 0 {
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return errors.E(err)
+		}
+	}
+	err = c.out.Write(ctxt, result)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
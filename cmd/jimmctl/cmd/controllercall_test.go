@@ -0,0 +1,51 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type controllerCallSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&controllerCallSuite{})
+
+func (s *controllerCallSuite) TestControllerCallUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewControllerCallCommandForTesting(s.ClientStore(), bClient), "controller-1", "Controller", "ControllerConfig", "--version", "11")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *controllerCallSuite) TestControllerCallNotAllowed(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewControllerCallCommandForTesting(s.ClientStore(), bClient), "controller-1", "UserManager", "AddUser", "--version", "3")
+	c.Assert(err, gc.ErrorMatches, `UserManager.AddUser may not be called on a controller \(bad request\)`)
+}
+
+func (s *controllerCallSuite) TestControllerCallInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	for _, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		args:        []string{"controller-1", "Controller"},
+		expectError: "controller, facade and method must be specified",
+	}, {
+		args:        []string{"controller-1", "Controller", "ControllerConfig", "extra"},
+		expectError: "too many args",
+	}, {
+		args:        []string{"controller-1", "Controller", "ConfigSet", "--params", "{not json"},
+		expectError: "invalid params: not valid JSON",
+	}} {
+		_, err := cmdtesting.RunCommand(c, cmd.NewControllerCallCommandForTesting(s.ClientStore(), bClient), test.args...)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const controllerCertificatesDoc = `
+	controller-certificates lists the controllers by the time until the
+	TLS certificates they present expire, soonest first. The expiry is
+	the earliest of the certificates in the chain presented when JIMM
+	last connected to the controller. Certificates that expire soon are
+	marked as expiring.
+
+	Example:
+		jimmctl controller-certificates
+		jimmctl controller-certificates --format yaml
+`
+
+// NewControllerCertificatesCommand returns a command to list the expiry
+// of the controller certificates.
+func NewControllerCertificatesCommand() cmd.Command {
+	cmd := &controllerCertificatesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// controllerCertificatesCommand lists the expiry of the controller
+// certificates.
+type controllerCertificatesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *controllerCertificatesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "controller-certificates",
+		Purpose: "List controllers by certificate expiry.",
+		Doc:     controllerCertificatesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *controllerCertificatesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatControllerCertificatesTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *controllerCertificatesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *controllerCertificatesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListControllerCertificates()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatControllerCertificatesTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListControllerCertificatesResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Expires", "Days")
+	for _, cert := range resp.Certificates {
+		days := "-"
+		if cert.DaysToExpiry != nil {
+			days = fmt.Sprint(*cert.DaysToExpiry)
+		}
+		expires := formatOptionalTime(cert.Expiry)
+		if cert.Expiring {
+			expires += " (expiring)"
+		}
+		table.AddRow(cert.Controller, expires, days)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type controllerCertificatesSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&controllerCertificatesSuite{})
+
+func (s *controllerCertificatesSuite) TestControllerCertificates(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewControllerCertificatesCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)certificates:
+- controller: controller-1
+  expiry: .*
+  days-to-expiry: .*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewControllerCertificatesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Controller +Expires +Days\s*\ncontroller-1 +\S+.* +-?\d+\s*`)
+}
+
+func (s *controllerCertificatesSuite) TestControllerCertificatesUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewControllerCertificatesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *controllerCertificatesSuite) TestControllerCertificatesTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewControllerCertificatesCommandForTesting(s.ClientStore(), bClient), "a")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,264 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	controllerCredentialsDoc = `
+	controller-credentials lists the cloud credential used by the
+	controller model of every controller, usually the credential the
+	controller was bootstrapped with, together with whether the
+	controller considers it valid and when it expires. Credentials that
+	expire soon are marked as expiring.
+
+	Example:
+		jimmctl controller-credentials
+		jimmctl controller-credentials --format yaml
+`
+
+	rotateControllerCredentialDoc = `
+	rotate-controller-credential replaces the content of the cloud
+	credential used by the controller model of the given controller. The
+	new content is read from the given file, or from stdin if the file is
+	"-", in the form:
+
+		auth-type: access-key
+		attrs:
+		  access-key: <key>
+		  secret-key: <secret>
+
+	The time the new credential expires may be given with --expires. If
+	no file is given only the recorded expiry is changed.
+
+	Example:
+		jimmctl rotate-controller-credential mycontroller cred.yaml --expires 2025-01-31T00:00:00Z
+		jimmctl rotate-controller-credential mycontroller --expires 2025-01-31T00:00:00Z
+`
+)
+
+// NewControllerCredentialsCommand returns a command to list the controller
+// model credentials.
+func NewControllerCredentialsCommand() cmd.Command {
+	cmd := &controllerCredentialsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// controllerCredentialsCommand lists the controller model credentials.
+type controllerCredentialsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *controllerCredentialsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "controller-credentials",
+		Purpose: "List the credentials used by the controller models.",
+		Doc:     controllerCredentialsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *controllerCredentialsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatControllerCredentialsTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *controllerCredentialsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *controllerCredentialsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListControllerModelCredentials()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatControllerCredentialsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListControllerModelCredentialsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Credential", "Valid", "Expires", "Rotated")
+	for _, cred := range resp.Credentials {
+		valid := "unknown"
+		if cred.Valid != nil {
+			valid = fmt.Sprint(*cred.Valid)
+		}
+		expires := formatOptionalTime(cred.ExpiresAt)
+		if cred.Expiring {
+			expires += " (expiring)"
+		}
+		table.AddRow(cred.Controller, cred.Credential, valid, expires, formatOptionalTime(cred.RotatedAt))
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// NewRotateControllerCredentialCommand returns a command to rotate a
+// controller model credential.
+func NewRotateControllerCredentialCommand() cmd.Command {
+	cmd := &rotateControllerCredentialCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// rotateControllerCredentialCommand rotates a controller model credential.
+type rotateControllerCredentialCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+	file       cmd.FileVar
+	expires    string
+}
+
+// Info implements Command.Info.
+func (c *rotateControllerCredentialCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rotate-controller-credential",
+		Args:    "<controller name> [<filename>]",
+		Purpose: "Rotate the credential used by a controller model.",
+		Doc:     rotateControllerCredentialDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rotateControllerCredentialCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.expires, "expires", "", "the time the credential expires, in RFC3339 format")
+	c.file.StdinMarkers = stdinMarkers
+}
+
+// Init implements the cmd.Command interface.
+func (c *rotateControllerCredentialCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("controller name not specified")
+	}
+	c.controller = args[0]
+	if len(args) > 1 {
+		c.file.Path = args[1]
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	if c.file.Path == "" && c.expires == "" {
+		return errors.E("filename or expiry must be specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *rotateControllerCredentialCommand) Run(ctxt *cmd.Context) error {
+	req := apiparams.RotateControllerModelCredentialRequest{
+		Controller: c.controller,
+	}
+	if c.expires != "" {
+		t, err := time.Parse(time.RFC3339, c.expires)
+		if err != nil {
+			return errors.E(err, "invalid expiry")
+		}
+		req.ExpiresAt = &t
+	}
+	if c.file.Path != "" {
+		var cred jujuparams.CloudCredential
+		if err := unmarshalYAMLFile(ctxt, &cred, c.file); err != nil {
+			return errors.E(err)
+		}
+		req.Credential = &cred
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RotateControllerModelCredential(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type controllerCredentialsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&controllerCredentialsSuite{})
+
+func (s *controllerCredentialsSuite) TestControllerCredentials(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewControllerCredentialsCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `credentials:
+- controller: controller-1
+  credential: cloudcred-.*
+  checked-at: .*
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewRotateControllerCredentialCommandForTesting(s.ClientStore(), bClient), "controller-1", "--expires", "2000-01-01T00:00:00Z")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)controller: controller-1
+credential: cloudcred-.*
+expires-at: "?2000-01-01T00:00:00Z"?
+expiring: true
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewControllerCredentialsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Controller +Credential +Valid +Expires +Rotated\s*\ncontroller-1 +cloudcred-\S+ +\S+ +2000-01-01T00:00:00Z \(expiring\) +-.*`)
+}
+
+func (s *controllerCredentialsSuite) TestControllerCredentialsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewControllerCredentialsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRotateControllerCredentialCommandForTesting(s.ClientStore(), bClient), "controller-1", "--expires", "2000-01-01T00:00:00Z")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *controllerCredentialsSuite) TestRotateControllerCredentialInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRotateControllerCredentialCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `controller name not specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRotateControllerCredentialCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `filename or expiry must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRotateControllerCredentialCommandForTesting(s.ClientStore(), bClient), "controller-1", "a", "b")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRotateControllerCredentialCommandForTesting(s.ClientStore(), bClient), "controller-1", "--expires", "tomorrow")
+	c.Assert(err, gc.ErrorMatches, `.*invalid expiry.*`)
+}
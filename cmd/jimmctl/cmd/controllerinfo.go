@@ -4,6 +4,7 @@ package cmd
 
 import (
 	"os"
+	"strings"
 
 	"github.com/juju/cmd/v3"
 	"github.com/juju/errors"
@@ -27,11 +28,25 @@ var (
 	
 	Use the --local flag if the server is not configured with a public cert.
 
+	If JIMM cannot reach the controller directly, use --proxy-url to
+	connect through a SOCKS5 or HTTP CONNECT proxy, or --ssh-jump-host
+	and --ssh-jump-host-key to tunnel through an SSH jump host.
+
+	When --local is used JIMM only trusts the controller's CA certificate.
+	Use --tls-system-ca-fallback to also trust the system CAs, and
+	--tls-min-version to require TLS 1.3.
+
+	If JIMM reaches the controller over a private network, use
+	--client-addresses to give the comma separated addresses clients
+	should be given to connect to the controller directly.
+
 	See examples below for usage.
 
 	Examples:
 		jimmctl controller-info <name> <filename> <public address> 
 		jimmctl controller-info <name> <filename> --local
+		jimmctl controller-info <name> <filename> <public address> --proxy-url socks5://proxy:1080
+		jimmctl controller-info <name> <filename> --local --client-addresses 203.0.113.10:17070,203.0.113.11:17070
 `
 )
 
@@ -50,12 +65,18 @@ func NewControllerInfoCommand() cmd.Command {
 type controllerInfoCommand struct {
 	modelcmd.ControllerCommandBase
 
-	store          jujuclient.ClientStore
-	controllerName string
-	publicAddress  string
-	file           cmd.FileVar
-	local          bool
-	tlsHostname    string
+	store               jujuclient.ClientStore
+	controllerName      string
+	publicAddress       string
+	file                cmd.FileVar
+	local               bool
+	tlsHostname         string
+	proxyURL            string
+	sshJumpHost         string
+	sshJumpHostKey      string
+	tlsMinVersion       string
+	tlsSystemCAFallback bool
+	clientAddresses     string
 }
 
 func (c *controllerInfoCommand) Info() *cmd.Info {
@@ -71,6 +92,12 @@ func (c *controllerInfoCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
 	f.BoolVar(&c.local, "local", false, "If local flag is specified, then the local API address and CA cert of the controller will be used.")
 	f.StringVar(&c.tlsHostname, "tls-hostname", "", "Specify the hostname for TLS verfiication.")
+	f.StringVar(&c.proxyURL, "proxy-url", "", "Specify a SOCKS5 or HTTP CONNECT proxy to connect to the controller through.")
+	f.StringVar(&c.sshJumpHost, "ssh-jump-host", "", "Specify an SSH jump host, as user@host[:port], to connect to the controller through.")
+	f.StringVar(&c.sshJumpHostKey, "ssh-jump-host-key", "", "Specify the public key of the SSH jump host in authorized_keys format.")
+	f.StringVar(&c.tlsMinVersion, "tls-min-version", "", "Specify the minimum TLS version, 1.2 or 1.3, to accept from the controller.")
+	f.BoolVar(&c.tlsSystemCAFallback, "tls-system-ca-fallback", false, "If specified, controller certificates signed by the system CAs are accepted as well as the controller's CA certificate.")
+	f.StringVar(&c.clientAddresses, "client-addresses", "", "Specify the comma separated addresses given to clients, if they differ from the addresses JIMM connects to.")
 }
 
 // Init implements the cmd.Command interface.
@@ -116,6 +143,16 @@ func (c *controllerInfoCommand) Run(ctxt *cmd.Context) error {
 
 	info.TLSHostname = c.tlsHostname
 	info.PublicAddress = c.publicAddress
+	info.ProxyURL = c.proxyURL
+	info.SSHJumpHost = c.sshJumpHost
+	info.SSHJumpHostKey = c.sshJumpHostKey
+	info.TLSMinVersion = c.tlsMinVersion
+	info.TLSSystemCAFallback = c.tlsSystemCAFallback
+	for _, addr := range strings.Split(c.clientAddresses, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			info.ClientAddresses = append(info.ClientAddresses, addr)
+		}
+	}
 	if c.local {
 		info.CACertificate = controller.CACert
 	}
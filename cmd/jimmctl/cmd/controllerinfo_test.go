@@ -303,3 +303,105 @@ username: test-user
 uuid: 982b16d9-a945-4762-b684-fd4fd885aa11
 `)
 }
+
+func (s *controllerInfoSuite) TestControllerInfoWithProxyFlag(c *gc.C) {
+	store := s.ClientStore()
+	store.Controllers["controller-1"] = jujuclient.ControllerDetails{
+		ControllerUUID: "982b16d9-a945-4762-b684-fd4fd885aa11",
+		APIEndpoints:   []string{"127.0.0.1:17070"},
+		PublicDNSName:  "controller1.example.com",
+	}
+	store.Accounts["controller-1"] = jujuclient.AccountDetails{
+		User:     "test-user",
+		Password: "super-secret-password",
+	}
+	dir, err := os.MkdirTemp("", "controller-info-test")
+	c.Assert(err, gc.Equals, nil)
+	defer os.RemoveAll(dir)
+
+	fname := path.Join(dir, "test.yaml")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewControllerInfoCommandForTesting(store), "controller-1", fname, "controller1.example.com", "--proxy-url", "socks5://proxy.example.com:1080")
+	c.Assert(err, gc.IsNil)
+
+	data, err := os.ReadFile(fname)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Matches, `api-addresses:
+- 127.0.0.1:17070
+name: controller-1
+password: super-secret-password
+proxy-url: socks5://proxy.example.com:1080
+public-address: controller1.example.com
+username: test-user
+uuid: 982b16d9-a945-4762-b684-fd4fd885aa11
+`)
+}
+
+func (s *controllerInfoSuite) TestControllerInfoWithTLSFlags(c *gc.C) {
+	store := s.ClientStore()
+	store.Controllers["controller-1"] = jujuclient.ControllerDetails{
+		ControllerUUID: "982b16d9-a945-4762-b684-fd4fd885aa11",
+		APIEndpoints:   []string{"127.0.0.1:17070"},
+		CACert:         "ca-cert",
+	}
+	store.Accounts["controller-1"] = jujuclient.AccountDetails{
+		User:     "test-user",
+		Password: "super-secret-password",
+	}
+	dir, err := os.MkdirTemp("", "controller-info-test")
+	c.Assert(err, gc.Equals, nil)
+	defer os.RemoveAll(dir)
+
+	fname := path.Join(dir, "test.yaml")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewControllerInfoCommandForTesting(store), "controller-1", fname, "--local", "--tls-min-version", "1.3", "--tls-system-ca-fallback")
+	c.Assert(err, gc.IsNil)
+
+	data, err := os.ReadFile(fname)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Matches, `api-addresses:
+- 127.0.0.1:17070
+ca-certificate: ca-cert
+name: controller-1
+password: super-secret-password
+tls-min-version: "1.3"
+tls-system-ca-fallback: true
+username: test-user
+uuid: 982b16d9-a945-4762-b684-fd4fd885aa11
+`)
+}
+
+func (s *controllerInfoSuite) TestControllerInfoWithClientAddresses(c *gc.C) {
+	store := s.ClientStore()
+	store.Controllers["controller-1"] = jujuclient.ControllerDetails{
+		ControllerUUID: "982b16d9-a945-4762-b684-fd4fd885aa11",
+		APIEndpoints:   []string{"10.0.0.1:17070"},
+		CACert:         "ca-cert",
+	}
+	store.Accounts["controller-1"] = jujuclient.AccountDetails{
+		User:     "test-user",
+		Password: "super-secret-password",
+	}
+	dir, err := os.MkdirTemp("", "controller-info-test")
+	c.Assert(err, gc.Equals, nil)
+	defer os.RemoveAll(dir)
+
+	fname := path.Join(dir, "test.yaml")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewControllerInfoCommandForTesting(store), "controller-1", fname, "--local", "--client-addresses", "203.0.113.10:17070, 203.0.113.11:17070")
+	c.Assert(err, gc.IsNil)
+
+	data, err := os.ReadFile(fname)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Matches, `api-addresses:
+- 10.0.0.1:17070
+ca-certificate: ca-cert
+client-addresses:
+- 203.0.113.10:17070
+- 203.0.113.11:17070
+name: controller-1
+password: super-secret-password
+username: test-user
+uuid: 982b16d9-a945-4762-b684-fd4fd885aa11
+`)
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	setCostCenterDoc = `
+	set-cost-center attaches a cost center, or billing code, to a user,
+	group or model. Models are attributed to their own cost center, or
+	failing that to their owner's, or failing that to that of a group
+	their owner is a member of. An empty cost center removes the cost
+	center from the entity.
+
+	Example:
+		jimmctl set-cost-center user-alice@canonical.com CC-1234
+		jimmctl set-cost-center group-finance CC-1234
+		jimmctl set-cost-center model-alice@canonical.com/mymodel CC-5678
+		jimmctl set-cost-center model-alice@canonical.com/mymodel ""
+`
+
+	usageReportDoc = `
+	usage-report reports the resources used by every model along with the
+	cost center each model is attributed to. The tabular format shows the
+	totals for each cost center, the csv format lists every model for
+	export to other systems.
+
+	Example:
+		jimmctl usage-report
+		jimmctl usage-report --format csv
+		jimmctl usage-report --format yaml
+`
+)
+
+// NewSetCostCenterCommand returns a command to attach a cost center to a
+// user, group or model.
+func NewSetCostCenterCommand() cmd.Command {
+	cmd := &setCostCenterCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setCostCenterCommand attaches a cost center to a user, group or model.
+type setCostCenterCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	entity     string
+	costCenter string
+}
+
+// Info implements Command.Info.
+func (c *setCostCenterCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-cost-center",
+		Args:    "<entity> <cost center>",
+		Purpose: "Attach a cost center to a user, group or model.",
+		Doc:     setCostCenterDoc,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *setCostCenterCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("entity and cost center must be specified")
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	c.entity, c.costCenter = args[0], args[1]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setCostCenterCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.SetCostCenter(&apiparams.SetCostCenterRequest{
+		Entity:     c.entity,
+		CostCenter: c.costCenter,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// NewUsageReportCommand returns a command to report the resources used
+// by every model.
+func NewUsageReportCommand() cmd.Command {
+	cmd := &usageReportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// usageReportCommand reports the resources used by every model.
+type usageReportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *usageReportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "usage-report",
+		Purpose: "Report the resources used by every model by cost center.",
+		Doc:     usageReportDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *usageReportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatUsageReportTabular,
+		"csv":     formatUsageReportCSV,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *usageReportCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *usageReportCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	report, err := client.UsageReport()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, report)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatUsageReportTabular(writer io.Writer, value interface{}) error {
+	report, ok := value.(*apiparams.UsageReport)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", report, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Cost center", "Models", "Machines", "Cores", "Units")
+	for _, cc := range report.CostCenters {
+		name := cc.CostCenter
+		if name == "" {
+			name = "-"
+		}
+		table.AddRow(name, cc.Models, cc.Machines, cc.Cores, cc.Units)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
+
+func formatUsageReportCSV(writer io.Writer, value interface{}) error {
+	report, ok := value.(*apiparams.UsageReport)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", report, value))
+	}
+
+	w := csv.NewWriter(writer)
+	records := [][]string{{"model", "name", "owner", "controller", "cloud", "region", "machines", "cores", "units", "cost-center", "cost-center-source"}}
+	for _, m := range report.Models {
+		records = append(records, []string{
+			m.ModelTag,
+			m.Name,
+			m.Owner,
+			m.Controller,
+			m.Cloud,
+			m.Region,
+			strconv.FormatInt(m.Machines, 10),
+			strconv.FormatInt(m.Cores, 10),
+			strconv.FormatInt(m.Units, 10),
+			m.CostCenter,
+			m.CostCenterSource,
+		})
+	}
+	if err := w.WriteAll(records); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type costCenterSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&costCenterSuite{})
+
+func (s *costCenterSuite) addModel(c *gc.C) *dbmodel.Model {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	ctx := context.Background()
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err := s.JIMM.Database.GetModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	m.Machines = 2
+	m.Cores = 4
+	m.Units = 3
+	err = s.JIMM.Database.UpdateModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	return &m
+}
+
+func (s *costCenterSuite) TestCostCenters(c *gc.C) {
+	m := s.addModel(c)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewUsageReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Cost center +Models +Machines +Cores +Units\s*
+- +1 +2 +4 +3\s*
+`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetCostCenterCommandForTesting(s.ClientStore(), bClient), "user-charlie@canonical.com", "CC-1")
+	c.Assert(err, gc.IsNil)
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewUsageReportCommandForTesting(s.ClientStore(), bClient), "--format", "csv")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, fmt.Sprintf(`model,name,owner,controller,cloud,region,machines,cores,units,cost-center,cost-center-source
+%s,model-1,charlie@canonical.com,controller-1,%s,%s,2,4,3,CC-1,user
+`, m.ResourceTag(), jimmtest.TestCloudName, jimmtest.TestCloudRegionName))
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetCostCenterCommandForTesting(s.ClientStore(), bClient), "model-charlie@canonical.com/model-1", "CC-2")
+	c.Assert(err, gc.IsNil)
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewUsageReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Cost center +Models +Machines +Cores +Units\s*
+CC-2 +1 +2 +4 +3\s*
+`)
+}
+
+func (s *costCenterSuite) TestCostCentersUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetCostCenterCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com", "CC-1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewUsageReportCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *costCenterSuite) TestSetCostCenterInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetCostCenterCommandForTesting(s.ClientStore(), bClient), "user-bob@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `entity and cost center must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetCostCenterCommandForTesting(s.ClientStore(), bClient), "a", "b", "c")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewUsageReportCommandForTesting(s.ClientStore(), bClient), "a")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const credentialPropagationsDoc = `
+	credential-propagations lists the controllers cloud credentials have
+	been copied to, when they were last copied and by which version of
+	JIMM. For revoked credentials the time of the revocation and the time
+	each controller confirmed its copy was removed are shown. Removals
+	are verified in the background, for removals not yet confirmed the
+	number of failed verifications and the last error are shown. With
+	--unconfirmed only the copies of revoked credentials that have not
+	been confirmed removed are listed.
+
+	Example:
+		jimmctl credential-propagations
+		jimmctl credential-propagations aws/alice@canonical.com/cred
+		jimmctl credential-propagations --unconfirmed
+`
+
+// NewCredentialPropagationsCommand returns a command to list the
+// controllers cloud credentials have been copied to.
+func NewCredentialPropagationsCommand() cmd.Command {
+	cmd := &credentialPropagationsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// credentialPropagationsCommand lists the controllers cloud credentials
+// have been copied to.
+type credentialPropagationsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	credential  string
+	unconfirmed bool
+}
+
+// Info implements Command.Info.
+func (c *credentialPropagationsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "credential-propagations",
+		Args:    "[<cloud>/<owner>/<credential>]",
+		Purpose: "List the controllers cloud credentials have been copied to.",
+		Doc:     credentialPropagationsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *credentialPropagationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCredentialPropagationsTabular,
+	})
+	f.BoolVar(&c.unconfirmed, "unconfirmed", false, "only list copies of revoked credentials not confirmed removed")
+}
+
+// Init implements the cmd.Command interface.
+func (c *credentialPropagationsCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if len(args) == 1 {
+		if !names.IsValidCloudCredential(args[0]) {
+			return errors.E("invalid cloud credential")
+		}
+		c.credential = args[0]
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *credentialPropagationsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	req := apiparams.ListCredentialPropagationsRequest{
+		Unconfirmed: c.unconfirmed,
+	}
+	if c.credential != "" {
+		req.CredentialTag = names.NewCloudCredentialTag(c.credential).String()
+	}
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListCredentialPropagations(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatCredentialPropagationsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListCredentialPropagationsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.Format(time.RFC3339)
+	}
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Credential", "Controller", "Propagated", "JIMM version", "Revoked", "Removal confirmed", "Verification")
+	for _, p := range resp.Propagations {
+		propagated := p.PropagatedAt
+		verification := "-"
+		if p.VerificationAttempts > 0 {
+			verification = fmt.Sprintf("%d failed: %s", p.VerificationAttempts, p.VerificationError)
+		}
+		table.AddRow(p.CredentialTag, p.Controller, formatTime(&propagated), p.JIMMVersion, formatTime(p.RevokedAt), formatTime(p.RemovalConfirmedAt), verification)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type credentialPropagationsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&credentialPropagationsSuite{})
+
+func (s *credentialPropagationsSuite) TestCredentialPropagations(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewCredentialPropagationsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `Credential +Controller +Propagated +JIMM version +Revoked +Removal confirmed +Verification\s*
+`+cct.String()+` +controller-1 +\S+ +.* +- +- +-\s*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewCredentialPropagationsCommandForTesting(s.ClientStore(), bClient), "--unconfirmed")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `Credential +Controller +Propagated +JIMM version +Revoked +Removal confirmed +Verification\s*`)
+}
+
+func (s *credentialPropagationsSuite) TestCredentialPropagationsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewCredentialPropagationsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *credentialPropagationsSuite) TestCredentialPropagationsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewCredentialPropagationsCommandForTesting(s.ClientStore(), bClient), "not-a-credential")
+	c.Check(err, gc.ErrorMatches, "invalid cloud credential")
+	_, err = cmdtesting.RunCommand(c, cmd.NewCredentialPropagationsCommandForTesting(s.ClientStore(), bClient), "a/b@canonical.com/c", "extra")
+	c.Check(err, gc.ErrorMatches, "too many args")
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const deletedModelsDoc = `
+	deleted-models lists the models removed from JIMM whose records are
+	still retained, the most recently removed first. If a model UUID is
+	given only the record of that model is shown. JIMM administrators see
+	every removed model, other users see the removed models they owned.
+
+	Example:
+		jimmctl deleted-models
+		jimmctl deleted-models 00000002-0000-0000-0000-000000000001 --format yaml
+`
+
+// NewDeletedModelsCommand returns a command to list the models removed
+// from JIMM.
+func NewDeletedModelsCommand() cmd.Command {
+	cmd := &deletedModelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// deletedModelsCommand lists the models removed from JIMM.
+type deletedModelsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	model    string
+}
+
+// Info implements Command.Info.
+func (c *deletedModelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "deleted-models",
+		Args:    "[<model uuid>]",
+		Purpose: "List models removed from JIMM.",
+		Doc:     deletedModelsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *deletedModelsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatDeletedModelsTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *deletedModelsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.model, args = args[0], args[1:]
+		if !names.IsValidModel(c.model) {
+			return errors.E("invalid model uuid")
+		}
+	}
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *deletedModelsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	var resp interface{}
+	if c.model != "" {
+		resp, err = client.GetDeletedModel(&apiparams.DeletedModelRequest{
+			ModelTag: names.NewModelTag(c.model).String(),
+		})
+	} else {
+		resp, err = client.ListDeletedModels()
+	}
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatDeletedModelsTabular(writer io.Writer, value interface{}) error {
+	var models []apiparams.DeletedModel
+	switch v := value.(type) {
+	case *apiparams.ListDeletedModelsResponse:
+		models = v.Models
+	case *apiparams.DeletedModel:
+		models = []apiparams.DeletedModel{*v}
+	default:
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", &apiparams.ListDeletedModelsResponse{}, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Model", "Owner", "Controller", "Cloud/Region", "Deleted at")
+	for _, m := range models {
+		table.AddRow(m.Name, m.Owner, m.Controller, m.Cloud+"/"+m.CloudRegion, m.DeletedAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type deletedModelsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&deletedModelsSuite{})
+
+func (s *deletedModelsSuite) TestDeletedModelsAndRestore(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+	var model dbmodel.Model
+	model.SetTag(mt)
+	err := s.JIMM.Database.GetModel(ctx, &model)
+	c.Assert(err, gc.Equals, nil)
+	err = s.JIMM.Database.DeleteModel(ctx, &model)
+	c.Assert(err, gc.Equals, nil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewDeletedModelsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Model +Owner +Controller +Cloud/Region +Deleted at\s*\nmodel-2 +charlie@canonical.com +controller-1 +`+jimmtest.TestCloudName+`/`+jimmtest.TestCloudRegionName+` +.*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewDeletedModelsCommandForTesting(s.ClientStore(), bClient), mt.Id(), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)model-tag: `+mt.String()+`\nname: model-2\nowner: charlie@canonical.com\ncontroller: controller-1\n.*`)
+
+	// bob did not own the model.
+	bobClient := s.SetupCLIAccess(c, "bob")
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewDeletedModelsCommandForTesting(s.ClientStore(), bobClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "models: []\n")
+	_, err = cmdtesting.RunCommand(c, cmd.NewRestoreModelCommandForTesting(s.ClientStore(), bobClient), mt.Id())
+	c.Assert(err, gc.ErrorMatches, `unauthorized.*`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRestoreModelCommandForTesting(s.ClientStore(), bClient), mt.Id())
+	c.Assert(err, gc.IsNil)
+
+	model = dbmodel.Model{}
+	model.SetTag(mt)
+	err = s.JIMM.Database.GetModel(ctx, &model)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(model.OwnerIdentityName, gc.Equals, "charlie@canonical.com")
+	c.Check(model.Origin.Kind, gc.Equals, dbmodel.ModelOriginRestored)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewDeletedModelsCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "models: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRestoreModelCommandForTesting(s.ClientStore(), bClient), mt.Id())
+	c.Assert(err, gc.ErrorMatches, `.*not found.*`)
+}
+
+func (s *deletedModelsSuite) TestDeletedModelsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewDeletedModelsCommandForTesting(s.ClientStore(), bClient), "not-a-model")
+	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewDeletedModelsCommandForTesting(s.ClientStore(), bClient), "00000002-0000-0000-0000-000000000001", "b")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRestoreModelCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `missing model uuid`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRestoreModelCommandForTesting(s.ClientStore(), bClient), "not-a-model")
+	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var exportBundleCommandDoc = `
+	export-bundle command exports a model as a bundle, including the
+	model's applications, relations, machine placement and offers.
+
+	The --strip-cloud-constraints flag removes constraints that only
+	apply to the cloud hosting the model, such as instance types and
+	availability zones, so that the bundle can be deployed to other
+	clouds.
+
+	The model may be specified by its UUID or as <owner>/<name>.
+
+	Example:
+		jimmctl export-bundle <model uuid>
+		jimmctl export-bundle alice@canonical.com/mymodel --strip-cloud-constraints --filename bundle.yaml
+`
+
+// NewExportBundleCommand returns a command to export a model as a bundle.
+func NewExportBundleCommand() cmd.Command {
+	cmd := &exportBundleCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// exportBundleCommand exports a model as a bundle.
+type exportBundleCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store                 jujuclient.ClientStore
+	dialOpts              *jujuapi.DialOpts
+	model                 string
+	filename              string
+	includeCharmDefaults  bool
+	stripCloudConstraints bool
+}
+
+func (c *exportBundleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export-bundle",
+		Purpose: "Exports a model as a bundle",
+		Doc:     exportBundleCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.filename, "filename", "", "Write the bundle to the given file instead of standard output.")
+	f.BoolVar(&c.includeCharmDefaults, "include-charm-defaults", false, "If specified, the default values of charm configuration settings are included in the bundle.")
+	f.BoolVar(&c.stripCloudConstraints, "strip-cloud-constraints", false, "If specified, cloud-specific constraints are removed from the bundle.")
+}
+
+// Init implements the cmd.Command interface.
+func (c *exportBundleCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("missing model uuid")
+	}
+	model, args := args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	var err error
+	c.model, err = parseModelRef(model)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *exportBundleCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ExportModelBundle(&apiparams.ExportModelBundleRequest{
+		ModelTag:              c.model,
+		IncludeCharmDefaults:  c.includeCharmDefaults,
+		StripCloudConstraints: c.stripCloudConstraints,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	if resp.DownloadURL != "" {
+		fmt.Fprintf(ctxt.Stderr, "Bundle too large to return inline, download it from %s\n", resp.DownloadURL)
+		return nil
+	}
+
+	if c.filename == "" {
+		_, err = fmt.Fprint(ctxt.Stdout, resp.Bundle)
+		if err != nil {
+			return errors.E(err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(ctxt.AbsPath(c.filename), []byte(resp.Bundle), 0644); err != nil {
+		return errors.E(err)
+	}
+	fmt.Fprintf(ctxt.Stderr, "Bundle successfully exported to %s\n", c.filename)
+	return nil
+}
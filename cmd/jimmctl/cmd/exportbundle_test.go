@@ -0,0 +1,59 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type exportBundleSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&exportBundleSuite{})
+
+func (s *exportBundleSuite) addModel(c *gc.C) names.ModelTag {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+	return s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-2", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+}
+
+func (s *exportBundleSuite) TestExportBundleEmptyModel(c *gc.C) {
+	mt := s.addModel(c)
+
+	// The model is exported by the hosting controller, which refuses to
+	// export a model without applications.
+	bClient := s.SetupCLIAccess(c, "charlie")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportBundleCommandForTesting(s.ClientStore(), bClient), mt.Id(), "--strip-cloud-constraints")
+	c.Assert(err, gc.ErrorMatches, `.*nothing to export as there are no applications.*`)
+}
+
+func (s *exportBundleSuite) TestExportBundleUnauthorized(c *gc.C) {
+	mt := s.addModel(c)
+
+	// bob has no access to the model
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportBundleCommandForTesting(s.ClientStore(), bClient), mt.Id())
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *exportBundleSuite) TestExportBundleInvalidArguments(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportBundleCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `missing model uuid`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewExportBundleCommandForTesting(s.ClientStore(), bClient), "not-a-uuid")
+	c.Assert(err, gc.ErrorMatches, `not-a-uuid is not a valid model uuid or <owner>/<name> path`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewExportBundleCommandForTesting(s.ClientStore(), bClient), "00000002-0000-0000-0000-000000000001", "extra")
+	c.Assert(err, gc.ErrorMatches, `unknown arguments`)
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const exportModelDoc = `
+	export-model writes the JIMM metadata of a model, its owner, cloud and
+	the access users and groups hold on it, as a YAML document. The
+	document is given to prepare-model-import on the JIMM the model is
+	moving to.
+
+	Moving a model to another JIMM deployment takes four steps:
+
+		1. export-model, on this JIMM.
+		2. prepare-model-import, on the other JIMM.
+		3. migrate-model-external, on this JIMM.
+		4. complete-model-import, on the other JIMM.
+
+	Example:
+		jimmctl export-model alice@canonical.com/model-1 > model-1.yaml
+`
+
+// NewExportModelCommand returns a command to export a model's JIMM
+// metadata.
+func NewExportModelCommand() cmd.Command {
+	cmd := &exportModelCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// exportModelCommand exports a model's JIMM metadata.
+type exportModelCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	model string
+}
+
+// Info implements Command.Info.
+func (c *exportModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export-model",
+		Args:    "<model>",
+		Purpose: "Export a model's metadata for another JIMM deployment.",
+		Doc:     exportModelDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *exportModelCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("model not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.model = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *exportModelCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	exp, err := client.ExportModel(&apiparams.ExportModelRequest{
+		ModelTag: c.model,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, exp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
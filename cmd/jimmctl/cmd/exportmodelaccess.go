@@ -0,0 +1,112 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const exportModelAccessDoc = `
+	export-model-access writes a YAML document declaring the access users
+	and groups hold on a model, or with --owner on every model of an
+	owner. The access of each model's owner is left out. The document may
+	be kept under version control, edited and applied with
+	apply-model-access.
+
+	Example:
+		jimmctl export-model-access alice@canonical.com/model-1
+		jimmctl export-model-access --owner alice@canonical.com > access.yaml
+`
+
+// NewExportModelAccessCommand returns a command to export the access held
+// on models.
+func NewExportModelAccessCommand() cmd.Command {
+	cmd := &exportModelAccessCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// exportModelAccessCommand exports the access held on models.
+type exportModelAccessCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	model string
+	owner string
+}
+
+// Info implements Command.Info.
+func (c *exportModelAccessCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export-model-access",
+		Args:    "[<model>]",
+		Purpose: "Export the access held on models as a document.",
+		Doc:     exportModelAccessDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportModelAccessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.owner, "owner", "", "export every model owned by this identity")
+}
+
+// Init implements the cmd.Command interface.
+func (c *exportModelAccessCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if len(args) == 1 {
+		c.model = args[0]
+	}
+	if (c.model == "") == (c.owner == "") {
+		return errors.E("exactly one of a model and --owner must be specified")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *exportModelAccessCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	doc, err := client.ExportModelAccess(&apiparams.ExportModelAccessRequest{
+		ModelTag: c.model,
+		Owner:    c.owner,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, doc)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const findOffersDoc = `
+	find-offers searches the directory of application offers known to
+	JIMM, across all controllers, for the offers the current user can
+	read. If a query is given only offers whose name, application name or
+	description contain the query are returned.
+
+	Example:
+		jimmctl find-offers
+		jimmctl find-offers postgres
+		jimmctl find-offers --interface postgresql_client --cloud aws
+		jimmctl find-offers --owner alice@canonical.com --format yaml
+`
+
+// NewFindOffersCommand returns a command to search the directory of
+// application offers.
+func NewFindOffersCommand() cmd.Command {
+	cmd := &findOffersCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// findOffersCommand searches the directory of application offers.
+type findOffersCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.FindOffersRequest
+}
+
+// Info implements Command.Info.
+func (c *findOffersCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "find-offers",
+		Args:    "[<query>]",
+		Purpose: "Search the directory of application offers.",
+		Doc:     findOffersDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *findOffersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatOffersTabular,
+	})
+	f.StringVar(&c.req.Interface, "interface", "", "only find offers with an endpoint using this interface")
+	f.StringVar(&c.req.Owner, "owner", "", "only find offers in models owned by this user")
+	f.StringVar(&c.req.Cloud, "cloud", "", "only find offers in models on this cloud")
+	f.IntVar(&c.req.Limit, "limit", 0, "the maximum number of offers to return")
+}
+
+// Init implements the cmd.Command interface.
+func (c *findOffersCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.req.Query, args = args[0], args[1:]
+	}
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.req.Owner != "" && !names.IsValidUser(c.req.Owner) {
+		return errors.E("invalid owner " + c.req.Owner)
+	}
+	if c.req.Limit < 0 {
+		return errors.E("limit cannot be negative")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *findOffersCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.FindOffers(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatOffersTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.FindOffersResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Offer", "Application", "Interfaces", "Cloud", "Access", "Connections")
+	for _, offer := range resp.Offers {
+		table.AddRow(
+			offer.OfferURL,
+			offer.ApplicationName,
+			strings.Join(offer.Interfaces, ","),
+			offer.Cloud,
+			offer.Access,
+			fmt.Sprintf("%d/%d", offer.ActiveConnections, offer.TotalConnections),
+		)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type findOffersSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&findOffersSuite{})
+
+func (s *findOffersSuite) TestFindOffers(c *gc.C) {
+	ctx := context.Background()
+	env := initializeEnvironment(c, ctx, &s.JIMM.Database, *s.AdminUser)
+	offer := env.applicationOffers[0]
+	err := s.OFGAClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(env.users[1].ResourceTag()),
+		Relation: ofganames.ConsumerRelation,
+		Target:   ofganames.ConvertTag(offer.ResourceTag()),
+	})
+	c.Assert(err, gc.IsNil)
+
+	// eve may consume the offer.
+	bClient := s.SetupCLIAccess(c, "eve")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "TEST", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, `offers:
+- offer-url: test-controller-1:alice@canonical.com/test-model-1.testoffer1
+  offer-uuid: 436b2264-d8f8-4e24-b16f-dd43c4116528
+  offer-name: testoffer1
+  application-name: test-app
+  owner: alice@canonical.com
+  model-name: test-model-1
+  cloud: test-cloud
+  cloud-region: test-region-1
+  access: consume
+  active-connections: 0
+  total-connections: 0
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "--cloud", "test-cloud")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Offer +Application +Interfaces +Cloud +Access +Connections\s*\ntest-controller-1:alice@canonical.com/test-model-1.testoffer1 +test-app +test-cloud +consume +0/0\s*\n`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "--interface", "postgresql", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "offers: []\n")
+
+	// bob cannot read the offer.
+	bClient = s.SetupCLIAccess(c, "bob")
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "offers: []\n")
+}
+
+func (s *findOffersSuite) TestFindOffersInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "--owner", "not a user")
+	c.Assert(err, gc.ErrorMatches, `invalid owner not a user`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewFindOffersCommandForTesting(s.ClientStore(), bClient), "--limit", "-1")
+	c.Assert(err, gc.ErrorMatches, `limit cannot be negative`)
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const idleModelsDoc = `
+	idle-models lists the models that have had no unit changes and no
+	user connections through JIMM for a number of days, the longest idle
+	first. If --days is not specified the idle period configured in JIMM
+	is used.
+
+	Example:
+		jimmctl idle-models
+		jimmctl idle-models --days 60 --format yaml
+`
+
+// NewIdleModelsCommand returns a command to list the idle models.
+func NewIdleModelsCommand() cmd.Command {
+	cmd := &idleModelsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// idleModelsCommand lists the idle models.
+type idleModelsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	days     int
+}
+
+// Info implements Command.Info.
+func (c *idleModelsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "idle-models",
+		Purpose: "List models that have not been used.",
+		Doc:     idleModelsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *idleModelsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatIdleModelsTabular,
+	})
+	f.IntVar(&c.days, "days", 0, "number of days without activity after which a model is idle")
+}
+
+// Init implements the cmd.Command interface.
+func (c *idleModelsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.days < 0 {
+		return errors.E("days must not be negative")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *idleModelsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListIdleModels(&apiparams.ListIdleModelsRequest{
+		IdleDays: c.days,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatIdleModelsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListIdleModelsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Model", "Owner", "Controller", "Last unit change", "Last connection", "Idle days")
+	for _, m := range resp.Models {
+		table.AddRow(m.Name, m.Owner, m.Controller, formatOptionalTime(m.LastUnitChange), formatOptionalTime(m.LastConnection), m.IdleDays)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
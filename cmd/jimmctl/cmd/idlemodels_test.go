@@ -0,0 +1,44 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type idleModelsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&idleModelsSuite{})
+
+func (s *idleModelsSuite) TestIdleModels(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewIdleModelsCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "models: []\n")
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewIdleModelsCommandForTesting(s.ClientStore(), bClient), "--days", "7")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +Owner +Controller +Last unit change +Last connection +Idle days\s*`)
+}
+
+func (s *idleModelsSuite) TestIdleModelsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewIdleModelsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *idleModelsSuite) TestIdleModelsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewIdleModelsCommandForTesting(s.ClientStore(), bClient), "a")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewIdleModelsCommandForTesting(s.ClientStore(), bClient), "--days", "-1")
+	c.Assert(err, gc.ErrorMatches, `days must not be negative`)
+}
@@ -26,9 +26,14 @@ const importModelCommandDoc = `
 	local user and it will switch the model owner to the desired external user.
 	E.g. --owner my-user@canonical.com
 
+	The --origin option records how the model came to be on the controller,
+	one of "adopted" (the default), "migrated" for models migrated from a
+	controller outside JIMM or "restored" for models restored from a backup.
+
 	Example:
 		jimmctl import-model <controller name> <model-uuid>
 		jimmctl import-model <controller name> <model-uuid> --owner <username>
+		jimmctl import-model <controller name> <model-uuid> --origin migrated
 `
 
 // NewImportModelCommand returns a command to import a model.
@@ -62,6 +67,7 @@ func (c *importModelCommand) Info() *cmd.Info {
 // SetFlags implements Command.SetFlags.
 func (c *importModelCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.req.Owner, "owner", "", "switch the model owner to the desired user")
+	f.StringVar(&c.req.Origin, "origin", "", "how the model came to be on the controller: adopted, migrated or restored")
 }
 
 // Init implements the cmd.Command interface.
@@ -81,6 +87,11 @@ func (c *importModelCommand) Init(args []string) error {
 		return errors.E("invalid model uuid")
 	}
 	c.req.ModelTag = names.NewModelTag(args[1]).String()
+	switch c.req.Origin {
+	case "", apiparams.ModelOriginAdopted, apiparams.ModelOriginMigrated, apiparams.ModelOriginRestored:
+	default:
+		return errors.E("invalid origin " + c.req.Origin)
+	}
 	return nil
 }
 
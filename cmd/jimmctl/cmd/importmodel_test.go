@@ -52,6 +52,8 @@ func (s *importModelSuite) TestImportModelSuperuser(c *gc.C) {
 	err = s.JIMM.Database.GetModel(context.Background(), &model2)
 	c.Assert(err, gc.Equals, nil)
 	c.Check(model2.OwnerIdentityName, gc.Equals, "charlie@canonical.com")
+	c.Check(model2.Origin.Kind, gc.Equals, dbmodel.ModelOriginAdopted)
+	c.Check(model2.Origin.IdentityName, gc.Equals, "alice@canonical.com")
 }
 
 func (s *importModelSuite) TestImportModelFromLocalUser(c *gc.C) {
@@ -126,6 +128,12 @@ func (s *importModelSuite) TestImportModelInvalidModelUUID(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
 }
 
+func (s *importModelSuite) TestImportModelInvalidOrigin(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewImportModelCommandForTesting(s.ClientStore(), bClient), "controller-id", "00000002-0000-0000-0000-000000000001", "--origin", "stolen")
+	c.Assert(err, gc.ErrorMatches, `invalid origin stolen`)
+}
+
 func (s *importModelSuite) TestImportModelTooManyArgs(c *gc.C) {
 	bClient := s.SetupCLIAccess(c, "bob")
 	_, err := cmdtesting.RunCommand(c, cmd.NewImportModelCommandForTesting(s.ClientStore(), bClient), "controller-id", "not-a-uuid", "spare-argument")
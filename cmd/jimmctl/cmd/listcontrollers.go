@@ -3,6 +3,12 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gosuri/uitable"
 	"github.com/juju/cmd/v3"
 	"github.com/juju/gnuflag"
 	jujuapi "github.com/juju/juju/api"
@@ -12,15 +18,21 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 var listControllersComandDoc = `
 	list-controllers command displays controller information
 	for all controllers known to JIMM.
 
+	With --watch the availability, monitoring lease owner and
+	watcher lag of every controller are shown, and updated as they
+	change, until the command is interrupted.
+
 	Example:
 		jimmctl controllers 
 		jimmctl controllers --format json --output ~/tmp/controllers.json
+		jimmctl controllers --watch
 `
 
 // NewListControllersCommand returns a command to list controller information.
@@ -40,6 +52,11 @@ type listControllersCommand struct {
 
 	store    jujuclient.ClientStore
 	dialOpts *jujuapi.DialOpts
+	watch    bool
+
+	// maxUpdates, if non-zero, limits the number of updates shown in
+	// watch mode.
+	maxUpdates int
 }
 
 func (c *listControllersCommand) Info() *cmd.Info {
@@ -58,6 +75,7 @@ func (c *listControllersCommand) SetFlags(f *gnuflag.FlagSet) {
 		"yaml": cmd.FormatYaml,
 		"json": cmd.FormatJson,
 	})
+	f.BoolVar(&c.watch, "watch", false, "continuously show the health of the controllers")
 }
 
 // Run implements Command.Run.
@@ -72,6 +90,9 @@ func (c *listControllersCommand) Run(ctxt *cmd.Context) error {
 	}
 
 	client := api.NewClient(apiCaller)
+	if c.watch {
+		return c.watchControllers(ctxt, client)
+	}
 	controllers, err := client.ListControllers()
 	if err != nil {
 		return errors.E(err)
@@ -83,3 +104,77 @@ func (c *listControllersCommand) Run(ctxt *cmd.Context) error {
 	}
 	return nil
 }
+
+// watchControllers shows the state of the controllers each time it
+// changes. When writing to a terminal the screen is cleared before each
+// update.
+func (c *listControllersCommand) watchControllers(ctxt *cmd.Context, client *api.Client) error {
+	id, err := client.WatchControllerHealth()
+	if err != nil {
+		return errors.E(err)
+	}
+	defer client.ControllerHealthWatcherStop(id)
+
+	clearScreen := isTerminal(ctxt.Stdout)
+	for n := 0; c.maxUpdates == 0 || n < c.maxUpdates; n++ {
+		states, err := client.ControllerHealthWatcherNext(id)
+		if err != nil {
+			return errors.E(err)
+		}
+		if clearScreen {
+			fmt.Fprint(ctxt.Stdout, "\x1b[H\x1b[2J")
+		}
+		fmt.Fprintf(ctxt.Stdout, "Controllers at %s\n\n", time.Now().Format(time.RFC3339))
+		formatControllerHealthTabular(ctxt.Stdout, states)
+	}
+	return nil
+}
+
+// formatControllerHealthTabular writes the given controller states as a
+// table.
+func formatControllerHealthTabular(w io.Writer, states []apiparams.ControllerHealthState) {
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("Controller", "Status", "Since", "Lease owner", "Heartbeat", "Health", "Watcher lag")
+	for _, st := range states {
+		since, heartbeat, health, lag := "-", "-", "-", "-"
+		if st.Since != nil {
+			since = st.Since.Format(time.RFC3339)
+		}
+		if st.MonitorHeartbeat != nil {
+			heartbeat = st.MonitorHeartbeat.Format(time.RFC3339)
+		}
+		if st.Health != nil {
+			health = fmt.Sprintf("%.2f", st.Health.Score)
+			if st.Health.Degraded {
+				health += " (degraded)"
+			}
+			lag = (time.Duration(st.Health.WatcherLagMS) * time.Millisecond).String()
+		}
+		monitor := st.Monitor
+		if monitor == "" {
+			monitor = "-"
+		}
+		status := st.Status
+		if st.UnavailableReason != "" {
+			status += " (" + st.UnavailableReason + ")"
+		}
+		table.AddRow(st.Name, status, since, monitor, heartbeat, health, lag)
+	}
+	fmt.Fprintln(w, table)
+}
+
+// isTerminal reports whether the given writer is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -92,3 +92,25 @@ func (s *listControllersSuite) TestListControllers(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(cmdtesting.Stdout(context), gc.Matches, expectedOutput)
 }
+
+func (s *listControllersSuite) TestWatchControllers(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	context, err := cmdtesting.RunCommand(c, cmd.NewWatchControllersCommandForTesting(s.ClientStore(), bClient, 1), "--watch")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(context), gc.Matches, `(?s)Controllers at .*
+Controller\s+Status\s+Since\s+Lease owner\s+Heartbeat\s+Health\s+Watcher lag\s*
+controller-1\s+available\s+-\s+.*
+`)
+}
+
+func (s *listControllersSuite) TestWatchControllersUnauthorized(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewWatchControllersCommandForTesting(s.ClientStore(), bClient, 1), "--watch")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const listOperationsDoc = `
+	list-operations lists the long-running operations started by the
+	current user, most recently started first. JIMM administrators can
+	list the operations started by every user with --all. Use
+	show-operation to see the step log and result of an operation.
+
+	Example:
+		jimmctl list-operations
+		jimmctl list-operations --all --format yaml
+`
+
+// NewListOperationsCommand returns a command to list operations.
+func NewListOperationsCommand() cmd.Command {
+	cmd := &listOperationsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listOperationsCommand lists operations.
+type listOperationsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	all bool
+}
+
+// Info implements Command.Info.
+func (c *listOperationsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "list-operations",
+		Purpose: "List long-running operations.",
+		Doc:     listOperationsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listOperationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatOperationsTabular,
+	})
+	f.BoolVar(&c.all, "all", false, "list the operations started by every user")
+}
+
+// Init implements the cmd.Command interface.
+func (c *listOperationsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listOperationsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	ops, err := client.ListOperations(&apiparams.ListOperationsRequest{
+		All: c.all,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, ops)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatOperationsTabular(writer io.Writer, value interface{}) error {
+	ops, ok := value.([]apiparams.Operation)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", ops, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("ID", "Kind", "Started by", "Status", "Progress", "Started")
+	for _, o := range ops {
+		table.AddRow(o.ID, o.Kind, o.StartedBy, o.Status, fmt.Sprintf("%d%%", o.Progress), o.Started.UTC().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const listOutdatedCharmsDoc = `
+	list-outdated-charms lists the applications, in every model known to
+	JIMM, that use an older revision of a Charmhub charm than the latest
+	revision released to Charmhub. JIMM must be configured with a
+	Charmhub URL.
+
+	Example:
+		jimmctl list-outdated-charms
+		jimmctl list-outdated-charms --format yaml
+`
+
+// NewListOutdatedCharmsCommand returns a command to list the applications
+// using outdated charms.
+func NewListOutdatedCharmsCommand() cmd.Command {
+	cmd := &listOutdatedCharmsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// listOutdatedCharmsCommand lists the applications using outdated charms.
+type listOutdatedCharmsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *listOutdatedCharmsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "list-outdated-charms",
+		Purpose: "List the applications using outdated charm revisions.",
+		Doc:     listOutdatedCharmsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listOutdatedCharmsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatOutdatedCharmsTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *listOutdatedCharmsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listOutdatedCharmsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListOutdatedCharms()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatOutdatedCharmsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListOutdatedCharmsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Model", "Application", "Charm", "Revision", "Latest")
+	for _, oc := range resp.Charms {
+		table.AddRow(oc.Controller, oc.Model, oc.Application, oc.Charm, oc.Revision, oc.LatestRevision)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
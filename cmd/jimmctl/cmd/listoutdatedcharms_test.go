@@ -0,0 +1,93 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/charmhub"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type listOutdatedCharmsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&listOutdatedCharmsSuite{})
+
+type testCharmhub map[string]*charmhub.CharmInfo
+
+func (ch testCharmhub) CharmInfo(_ context.Context, name string) (*charmhub.CharmInfo, error) {
+	if info, ok := ch[name]; ok {
+		return info, nil
+	}
+	return nil, errors.E(errors.CodeNotFound, "charm not found")
+}
+
+func (s *listOutdatedCharmsSuite) TestListOutdatedCharms(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err := s.JIMM.Database.GetModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         m.ID,
+		ApplicationName: "db",
+		CharmURL:        "ch:amd64/jammy/postgresql-345",
+	})
+	c.Assert(err, gc.IsNil)
+	s.JIMM.Charmhub = testCharmhub{
+		"postgresql": {Name: "postgresql", LatestRevision: 363},
+	}
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewListOutdatedCharmsCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, `charms:
+- controller: controller-1
+  model-uuid: `+mt.Id()+`
+  model: model-1
+  application: db
+  charm: postgresql
+  revision: 345
+  latest-revision: 363
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewListOutdatedCharmsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Controller +Model +Application +Charm +Revision +Latest\s*\ncontroller-1 +model-1 +db +postgresql +345 +363\s*`)
+}
+
+func (s *listOutdatedCharmsSuite) TestListOutdatedCharmsNotConfigured(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListOutdatedCharmsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `charmhub not configured \(not supported\)`)
+}
+
+func (s *listOutdatedCharmsSuite) TestListOutdatedCharmsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListOutdatedCharmsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *listOutdatedCharmsSuite) TestListOutdatedCharmsTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListOutdatedCharmsCommandForTesting(s.ClientStore(), bClient), "model-1")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -3,15 +3,12 @@
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/juju/cmd/v3"
 	"github.com/juju/gnuflag"
 	jujuapi "github.com/juju/juju/api"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/jujuclient"
-	"github.com/juju/names/v5"
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/pkg/api"
@@ -20,14 +17,16 @@ import (
 
 var migrateModelCommandDoc = `
 	The migrate command migrates a model(s) to a new controller. Specify
-	a model-uuid to migrate and the destination controller name.
+	a model to migrate, by its UUID or as <owner>/<name>, and the
+	destination controller name.
 
 	Note that multiple models can be targeted for migration by supplying
-	multiple model uuids.
+	multiple models.
 
 	Example:
 		jimmctl migrate <controller-name> <model-uuid> 
 		jimmctl migrate <controller-name> <model-uuid> <model-uuid> <model-uuid>
+		jimmctl migrate <controller-name> alice@canonical.com/mymodel
 `
 
 // NewMigrateModelCommand returns a command to migrate models.
@@ -77,12 +76,11 @@ func (c *migrateModelCommand) Init(args []string) error {
 			c.targetController = arg
 			continue
 		}
-		mt := names.NewModelTag(arg)
-		_, err := names.ParseModelTag(mt.String())
+		model, err := parseModelRef(arg)
 		if err != nil {
-			return errors.E(err, fmt.Sprintf("%s is not a valid model uuid", arg))
+			return err
 		}
-		c.modelTags = append(c.modelTags, mt.String())
+		c.modelTags = append(c.modelTags, model)
 	}
 	return nil
 }
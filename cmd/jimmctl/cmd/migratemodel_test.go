@@ -63,7 +63,7 @@ func (s *migrateModelSuite) TestMigrateModelCommandFailsWithInvalidModelTag(c *g
 	// alice is superuser
 	bClient := s.SetupCLIAccess(c, "alice")
 	_, err := cmdtesting.RunCommand(c, cmd.NewMigrateModelCommandForTesting(s.ClientStore(), bClient), "controller-1", "001", "002")
-	c.Assert(err, gc.ErrorMatches, ".* is not a valid model uuid")
+	c.Assert(err, gc.ErrorMatches, "001 is not a valid model uuid or <owner>/<name> path")
 }
 
 func (s *migrateModelSuite) TestMigrateModelCommandFailsWithMissingArgs(c *gc.C) {
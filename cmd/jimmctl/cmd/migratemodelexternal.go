@@ -0,0 +1,115 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const migrateModelExternalDoc = `
+	migrate-model-external migrates a model to a controller managed by
+	another JIMM deployment, using the import document written by
+	prepare-model-import on that JIMM. Once the model has left its
+	controller it is removed from this JIMM, and its import is completed
+	with complete-model-import on the other JIMM. The import document is
+	read from standard input if the file is "-".
+
+	Example:
+		jimmctl migrate-model-external alice@canonical.com/model-1 import.yaml
+`
+
+// NewMigrateModelExternalCommand returns a command to migrate a model to
+// another JIMM deployment.
+func NewMigrateModelExternalCommand() cmd.Command {
+	cmd := &migrateModelExternalCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// migrateModelExternalCommand migrates a model to another JIMM
+// deployment.
+type migrateModelExternalCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	model      string
+	importSpec cmd.FileVar
+}
+
+// Info implements Command.Info.
+func (c *migrateModelExternalCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "migrate-model-external",
+		Args:    "<model> <filename>",
+		Purpose: "Migrate a model to another JIMM deployment.",
+		Doc:     migrateModelExternalDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *migrateModelExternalCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *migrateModelExternalCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("model and filename must be specified")
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	c.model = args[0]
+	c.importSpec.Path = args[1]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *migrateModelExternalCommand) Run(ctxt *cmd.Context) error {
+	req := apiparams.MigrateModelExternalRequest{
+		ModelTag: c.model,
+	}
+	if err := unmarshalYAMLFile(ctxt, &req.Import, c.importSpec); err != nil {
+		return errors.E(err, "cannot read model import")
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.MigrateModelExternal(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type modelAccessSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelAccessSuite{})
+
+const modelAccessDocument = `models:
+- model: charlie@canonical.com/model-1
+  users:
+    everyone@external: read
+`
+
+func (s *modelAccessSuite) TestApplyAndExportModelAccess(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	document := filepath.Join(c.MkDir(), "access.yaml")
+	err := os.WriteFile(document, []byte(modelAccessDocument), 0600)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewApplyModelAccessCommandForTesting(s.ClientStore(), bClient), document, "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +Kind +Name +From +To\s*
+charlie@canonical.com/model-1 +user +everyone@external +none +read\s*
+dry run, no changes made`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewApplyModelAccessCommandForTesting(s.ClientStore(), bClient), document)
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +Kind +Name +From +To\s*
+charlie@canonical.com/model-1 +user +everyone@external +none +read\s*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewExportModelAccessCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com/model-1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, modelAccessDocument)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewApplyModelAccessCommandForTesting(s.ClientStore(), bClient), document)
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "no changes\n")
+}
+
+func (s *modelAccessSuite) TestExportModelAccessUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportModelAccessCommandForTesting(s.ClientStore(), bClient), "--owner", "charlie@canonical.com")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *modelAccessSuite) TestModelAccessInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportModelAccessCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, "exactly one of a model and --owner must be specified")
+	_, err = cmdtesting.RunCommand(c, cmd.NewExportModelAccessCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com/model-1", "--owner", "charlie@canonical.com")
+	c.Check(err, gc.ErrorMatches, "exactly one of a model and --owner must be specified")
+	_, err = cmdtesting.RunCommand(c, cmd.NewApplyModelAccessCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, "filename not specified")
+}
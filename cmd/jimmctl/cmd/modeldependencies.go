@@ -0,0 +1,296 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const modelDependenciesDoc = `
+	model-dependencies shows the models a model depends on, the models
+	that depend on it and every model that would be affected if it were
+	destroyed, either directly or through other models. The model may be
+	given by its UUID or as <owner>/<name>.
+
+	Example:
+		jimmctl model-dependencies alice@canonical.com/shared-db
+		jimmctl model-dependencies 00000002-0000-0000-0000-000000000001 --format yaml
+`
+
+const addModelDependencyDoc = `
+	add-model-dependency declares that a model depends on another model,
+	for example because it consumes an offer from that model. A model that
+	other models depend on is only destroyed with force. Declaring an
+	existing dependency replaces its description. Only administrators of
+	the dependent model may declare its dependencies.
+
+	Example:
+		jimmctl add-model-dependency alice@canonical.com/app alice@canonical.com/shared-db --description "consumes the postgresql offer"
+`
+
+const removeModelDependencyDoc = `
+	remove-model-dependency removes a model's dependency on another model.
+	Only administrators of the dependent model may remove its
+	dependencies.
+
+	Example:
+		jimmctl remove-model-dependency alice@canonical.com/app alice@canonical.com/shared-db
+`
+
+// NewModelDependenciesCommand returns a command to show the dependencies
+// of a model.
+func NewModelDependenciesCommand() cmd.Command {
+	cmd := &modelDependenciesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// modelDependenciesCommand shows the dependencies of a model.
+type modelDependenciesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	model    string
+}
+
+// Info implements Command.Info.
+func (c *modelDependenciesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "model-dependencies",
+		Args:    "<model>",
+		Purpose: "Show the dependencies of a model.",
+		Doc:     modelDependenciesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *modelDependenciesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatModelDependenciesTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *modelDependenciesCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("missing model")
+	}
+	model, args := args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	var err error
+	c.model, err = parseModelRef(model)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *modelDependenciesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ModelDependencies(&apiparams.ModelDependenciesRequest{
+		ModelTag: c.model,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatModelDependenciesTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(apiparams.ModelDependencies)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Model", "Depends on", "Description", "Created by")
+	for _, d := range resp.DependsOn {
+		table.AddRow(d.ModelName, d.DependsOnModelName, d.Description, d.CreatedBy)
+	}
+	for _, d := range resp.Dependents {
+		table.AddRow(d.ModelName, d.DependsOnModelName, d.Description, d.CreatedBy)
+	}
+	fmt.Fprint(writer, table)
+	if len(resp.AffectedModels) > 0 {
+		fmt.Fprintf(writer, "\n\nAffected by destroy: %s", strings.Join(resp.AffectedModels, ", "))
+	}
+	return nil
+}
+
+// NewAddModelDependencyCommand returns a command to declare that a model
+// depends on another model.
+func NewAddModelDependencyCommand() cmd.Command {
+	cmd := &addModelDependencyCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// addModelDependencyCommand declares that a model depends on another
+// model.
+type addModelDependencyCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	req      apiparams.AddModelDependencyRequest
+}
+
+// Info implements Command.Info.
+func (c *addModelDependencyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-model-dependency",
+		Args:    "<model> <depends-on-model>",
+		Purpose: "Declare that a model depends on another model.",
+		Doc:     addModelDependencyDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addModelDependencyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.req.Description, "description", "", "why the model depends on the other model")
+}
+
+// Init implements the cmd.Command interface.
+func (c *addModelDependencyCommand) Init(args []string) (err error) {
+	c.req.ModelTag, c.req.DependsOnModelTag, err = parseModelDependencyArgs(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *addModelDependencyCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if _, err := client.AddModelDependency(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// NewRemoveModelDependencyCommand returns a command to remove a model's
+// dependency on another model.
+func NewRemoveModelDependencyCommand() cmd.Command {
+	cmd := &removeModelDependencyCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// removeModelDependencyCommand removes a model's dependency on another
+// model.
+type removeModelDependencyCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	req      apiparams.RemoveModelDependencyRequest
+}
+
+// Info implements Command.Info.
+func (c *removeModelDependencyCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-model-dependency",
+		Args:    "<model> <depends-on-model>",
+		Purpose: "Remove a model's dependency on another model.",
+		Doc:     removeModelDependencyDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *removeModelDependencyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+}
+
+// Init implements the cmd.Command interface.
+func (c *removeModelDependencyCommand) Init(args []string) (err error) {
+	c.req.ModelTag, c.req.DependsOnModelTag, err = parseModelDependencyArgs(args)
+	return err
+}
+
+// Run implements Command.Run.
+func (c *removeModelDependencyCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	if err := client.RemoveModelDependency(&c.req); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// parseModelDependencyArgs parses the dependent model and the model it
+// depends on from the given command line arguments.
+func parseModelDependencyArgs(args []string) (model, dependsOn string, err error) {
+	switch {
+	case len(args) < 2:
+		return "", "", errors.E("missing model and the model it depends on")
+	case len(args) > 2:
+		return "", "", errors.E("unknown arguments")
+	}
+	if model, err = parseModelRef(args[0]); err != nil {
+		return "", "", err
+	}
+	if dependsOn, err = parseModelRef(args[1]); err != nil {
+		return "", "", err
+	}
+	return model, dependsOn, nil
+}
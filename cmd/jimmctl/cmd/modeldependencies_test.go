@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type modelDependenciesSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelDependenciesSuite{})
+
+func (s *modelDependenciesSuite) TestModelDependencies(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty", Attributes: map[string]string{"key": "value"}})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "shared-db", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "app", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewAddModelDependencyCommandForTesting(s.ClientStore(), bClient), "charlie/app", "charlie/shared-db", "--description", "consumes the postgresql offer")
+	c.Assert(err, gc.IsNil)
+
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewModelDependenciesCommandForTesting(s.ClientStore(), bClient), "charlie/shared-db")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +Depends on +Description +Created by\s*
+charlie@canonical.com/app +charlie@canonical.com/shared-db +consumes the postgresql offer +alice@canonical.com\s*
+
+Affected by destroy: charlie@canonical.com/app\s*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewModelDependenciesCommandForTesting(s.ClientStore(), bClient), "charlie/app", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)model-tag: model-.*
+depends-on:
+- model-tag: model-.*
+  model-name: charlie@canonical.com/app
+  depends-on-model-tag: model-.*
+  depends-on-model-name: charlie@canonical.com/shared-db
+  description: consumes the postgresql offer
+  created-by: alice@canonical.com
+  created-at: .*
+dependents: \[\]
+`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddModelDependencyCommandForTesting(s.ClientStore(), bClient), "charlie/shared-db", "charlie/app")
+	c.Check(err, gc.ErrorMatches, `charlie@canonical.com/app already depends on charlie@canonical.com/shared-db \(bad request\)`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelDependencyCommandForTesting(s.ClientStore(), bClient), "charlie/app", "charlie/shared-db")
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelDependencyCommandForTesting(s.ClientStore(), bClient), "charlie/app", "charlie/shared-db")
+	c.Check(err, gc.ErrorMatches, `model dependency not found \(not found\)`)
+}
+
+func (s *modelDependenciesSuite) TestModelDependenciesInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewModelDependenciesCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, `missing model`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewModelDependenciesCommandForTesting(s.ClientStore(), bClient), "a/b", "c")
+	c.Check(err, gc.ErrorMatches, `unknown arguments`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewAddModelDependencyCommandForTesting(s.ClientStore(), bClient), "a/b")
+	c.Check(err, gc.ErrorMatches, `missing model and the model it depends on`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelDependencyCommandForTesting(s.ClientStore(), bClient), "a/b", "c/d", "e")
+	c.Check(err, gc.ErrorMatches, `unknown arguments`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemoveModelDependencyCommandForTesting(s.ClientStore(), bClient), "a/b", "not-a-model")
+	c.Check(err, gc.ErrorMatches, `not-a-model is not a valid model uuid or <owner>/<name> path`)
+}
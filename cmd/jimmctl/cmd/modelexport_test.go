@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type modelExportSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelExportSuite{})
+
+func (s *modelExportSuite) TestExportModel(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewExportModelCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com/model-1")
+	c.Assert(err, gc.IsNil)
+	out := cmdtesting.Stdout(cmdContext)
+	c.Check(out, gc.Matches, `(?s)source-jimm: controller-.*
+model-tag: `+mt.String()+`
+name: model-1
+owner: charlie@canonical.com
+cloud: `+jimmtest.TestCloudName+`
+cloud-region: `+jimmtest.TestCloudRegionName+`
+cloud-credential: cred
+access:
+  model: charlie@canonical.com/model-1
+exported-at: .*`)
+
+	// A model cannot be imported into the JIMM it was exported from.
+	export := filepath.Join(c.MkDir(), "model-1.yaml")
+	err = os.WriteFile(export, []byte(out), 0600)
+	c.Assert(err, gc.IsNil)
+	_, err = cmdtesting.RunCommand(c, cmd.NewPrepareModelImportCommandForTesting(s.ClientStore(), bClient), "controller-1", export)
+	c.Assert(err, gc.ErrorMatches, `cannot import a model exported from this JIMM \(bad request\)`)
+}
+
+func (s *modelExportSuite) TestModelImportUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewCompleteModelImportCommandForTesting(s.ClientStore(), bClient), "1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *modelExportSuite) TestModelExportInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewExportModelCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `model not specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewPrepareModelImportCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `controller and filename must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewPrepareModelImportCommandForTesting(s.ClientStore(), bClient), "controller-1", "model-1.yaml", "--map-user", "alice@example.com")
+	c.Assert(err, gc.ErrorMatches, `invalid mapping "alice@example.com"`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewMigrateModelExternalCommandForTesting(s.ClientStore(), bClient), "model-1")
+	c.Assert(err, gc.ErrorMatches, `model and filename must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewCompleteModelImportCommandForTesting(s.ClientStore(), bClient), "one")
+	c.Assert(err, gc.ErrorMatches, `invalid import ID "one"`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewCompleteModelImportCommandForTesting(s.ClientStore(), bClient), "1", "2")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// parseModelRef converts a model given on the command line, either as a
+// model UUID or as a path of the form <owner>/<name>, to a reference that
+// JIMM resolves to the model. The owner in a path may be given without its
+// domain when that is unambiguous.
+func parseModelRef(ref string) (string, error) {
+	if names.IsValidModel(ref) {
+		return names.NewModelTag(ref).String(), nil
+	}
+	if owner, name, ok := strings.Cut(ref, "/"); ok && owner != "" && names.IsValidModelName(name) {
+		return ref, nil
+	}
+	return "", errors.E(fmt.Sprintf("%s is not a valid model uuid or <owner>/<name> path", ref))
+}
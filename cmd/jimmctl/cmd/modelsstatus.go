@@ -0,0 +1,163 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const modelsStatusCommandDoc = `
+	models-status command displays an overview of the controllers known to
+	JIMM and the models they host. For each controller the availability,
+	number of models and number of machines is shown. For each model the
+	life, status, number of machines and units, and the workload health
+	derived from the status of its units is shown.
+
+	The overview is built from the information JIMM holds about the
+	controllers, so no controller is contacted. The command must be run by
+	a JIMM administrator.
+
+	Example:
+		jimmctl models-status
+		jimmctl models-status --controller <controller name>
+		jimmctl models-status --format yaml
+`
+
+// NewModelsStatusCommand returns a command to display an overview of the
+// status of the controllers and models known to JIMM.
+func NewModelsStatusCommand() cmd.Command {
+	cmd := &modelsStatusCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// modelsStatusCommand displays an overview of the status of the
+// controllers and models known to JIMM.
+type modelsStatusCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+}
+
+// Info implements Command.Info.
+func (c *modelsStatusCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "models-status",
+		Purpose: "Display an overview of the status of controllers and models.",
+		Doc:     modelsStatusCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *modelsStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatModelsStatusTabular,
+	})
+	f.StringVar(&c.controller, "controller", "", "only display the named controller and its models")
+}
+
+// Init implements the cmd.Command interface.
+func (c *modelsStatusCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *modelsStatusCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ModelsStatus(&apiparams.ModelsStatusRequest{
+		Controller: c.controller,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatModelsStatusTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ModelsStatusResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("Controller", "Available", "Version", "Models", "Machines")
+	for _, ctl := range resp.Controllers {
+		available := "yes"
+		if !ctl.Available {
+			available = "no"
+			if ctl.UnavailableSince != nil {
+				available = "no, since " + ctl.UnavailableSince.UTC().Format("2006-01-02 15:04:05")
+			}
+			if ctl.UnavailableReason != "" {
+				available += " (" + ctl.UnavailableReason + ")"
+			}
+		}
+		if ctl.Deprecated {
+			available += " (deprecated)"
+		}
+		table.AddRow(ctl.Name, available, ctl.AgentVersion, ctl.ModelCount, ctl.MachineCount)
+	}
+	fmt.Fprintln(writer, table)
+	if len(resp.Models) == 0 {
+		return nil
+	}
+
+	table = uitable.New()
+	table.MaxColWidth = 50
+	table.Wrap = true
+
+	table.AddRow("Model", "Controller", "Life", "Status", "Machines", "Units", "Health")
+	for _, m := range resp.Models {
+		health := m.Health
+		if m.UnhealthyUnitCount > 0 {
+			health = fmt.Sprintf("%s (%d/%d units)", health, m.UnhealthyUnitCount, m.UnitCount)
+		}
+		table.AddRow(m.Owner+"/"+m.Name, m.Controller, m.Life, m.Status, m.MachineCount, m.UnitCount, health)
+	}
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, table)
+	return nil
+}
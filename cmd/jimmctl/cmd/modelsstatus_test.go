@@ -0,0 +1,107 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type modelsStatusSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&modelsStatusSuite{})
+
+func (s *modelsStatusSuite) addModel(c *gc.C) *dbmodel.Model {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	ctx := context.Background()
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err := s.JIMM.Database.GetModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	m.Machines = 2
+	m.Units = 3
+	m.WorkloadStatus = "blocked"
+	m.UnhealthyUnits = 1
+	err = s.JIMM.Database.UpdateModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	return &m
+}
+
+func (s *modelsStatusSuite) TestModelsStatus(c *gc.C) {
+	m := s.addModel(c)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewModelsStatusCommandForTesting(s.ClientStore(), bClient), "--controller", "controller-1", "--format", "json")
+	c.Assert(err, gc.IsNil)
+
+	var resp apiparams.ModelsStatusResponse
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(cmdContext)), &resp)
+	c.Assert(err, gc.IsNil)
+	c.Assert(resp.Controllers, gc.HasLen, 1)
+	c.Check(resp.Controllers[0].Name, gc.Equals, "controller-1")
+	c.Check(resp.Controllers[0].Available, gc.Equals, true)
+	c.Check(resp.Controllers[0].ModelCount, gc.Equals, int64(1))
+	c.Check(resp.Controllers[0].MachineCount, gc.Equals, int64(2))
+	c.Assert(resp.Models, gc.HasLen, 1)
+	c.Check(resp.Models[0], gc.DeepEquals, apiparams.ModelStatusOverview{
+		ModelTag:           m.ResourceTag().String(),
+		Name:               "model-1",
+		Owner:              "charlie@canonical.com",
+		Controller:         "controller-1",
+		Life:               m.Life,
+		Status:             m.Status.Status,
+		MachineCount:       2,
+		UnitCount:          3,
+		UnhealthyUnitCount: 1,
+		WorkloadStatus:     "blocked",
+		Health:             apiparams.WorkloadUnhealthy,
+	})
+}
+
+func (s *modelsStatusSuite) TestModelsStatusTabular(c *gc.C) {
+	s.addModel(c)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewModelsStatusCommandForTesting(s.ClientStore(), bClient), "--controller", "controller-1")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Controller\s+Available\s+Version\s+Models\s+Machines\s*\ncontroller-1\s+yes\s+\S+\s+1\s+2\s*\n\nModel\s+Controller\s+Life\s+Status\s+Machines\s+Units\s+Health\s*\ncharlie@canonical.com/model-1\s+controller-1\s+alive\s+(\S+\s+)?2\s+3\s+unhealthy \(1/3 units\)\s*\n`)
+}
+
+func (s *modelsStatusSuite) TestModelsStatusUnknownController(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewModelsStatusCommandForTesting(s.ClientStore(), bClient), "--controller", "no-such-controller")
+	c.Assert(err, gc.ErrorMatches, `controller not found.*`)
+}
+
+func (s *modelsStatusSuite) TestModelsStatusUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewModelsStatusCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *modelsStatusSuite) TestModelsStatusTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewModelsStatusCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -9,7 +9,6 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/jujuclient"
-	"github.com/juju/names/v5"
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/pkg/api"
@@ -19,9 +18,12 @@ import (
 var modelStatusCommandDoc = `
 	model-status command displays full model status.
 
+	The model may be specified by its UUID or as <owner>/<name>.
+
 	Example:
 		jimmctl model-status <model uuid> 
 		jimmctl model-status <model uuid> --format yaml
+		jimmctl model-status alice@canonical.com/mymodel
 `
 
 // NewModelStatusCommand returns a command to display full model status.
@@ -39,9 +41,9 @@ type modelStatusCommand struct {
 	modelcmd.ControllerCommandBase
 	out cmd.Output
 
-	store     jujuclient.ClientStore
-	dialOpts  *jujuapi.DialOpts
-	modelUUID string
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	model    string
 }
 
 func (c *modelStatusCommand) Info() *cmd.Info {
@@ -66,11 +68,13 @@ func (c *modelStatusCommand) Init(args []string) error {
 	if len(args) < 1 {
 		return errors.E("missing model uuid")
 	}
-	c.modelUUID, args = args[0], args[1:]
+	model, args := args[0], args[1:]
 	if len(args) > 0 {
 		return errors.E("unknown arguments")
 	}
-	return nil
+	var err error
+	c.model, err = parseModelRef(model)
+	return err
 }
 
 // Run implements Command.Run.
@@ -80,7 +84,6 @@ func (c *modelStatusCommand) Run(ctxt *cmd.Context) error {
 		return errors.E(err, "could not determine controller")
 	}
 
-	modelTag := names.NewModelTag(c.modelUUID)
 	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
 	if err != nil {
 		return err
@@ -88,7 +91,7 @@ func (c *modelStatusCommand) Run(ctxt *cmd.Context) error {
 
 	client := api.NewClient(apiCaller)
 	status, err := client.FullModelStatus(&apiparams.FullModelStatusRequest{
-		ModelTag: modelTag.String(),
+		ModelTag: c.model,
 	})
 	if err != nil {
 		return errors.E(err)
@@ -65,6 +65,11 @@ func (s *modelStatusSuite) TestModelStatusSuperuser(c *gc.C) {
 	context, err := cmdtesting.RunCommand(c, cmd.NewModelStatusCommandForTesting(s.ClientStore(), bClient), mt.Id())
 	c.Assert(err, gc.IsNil)
 	c.Assert(cmdtesting.Stdout(context), gc.Matches, expectedModelStatusOutput)
+
+	// The model can also be given as <owner>/<name>.
+	context, err = cmdtesting.RunCommand(c, cmd.NewModelStatusCommandForTesting(s.ClientStore(), bClient), "charlie/model-2")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(context), gc.Matches, expectedModelStatusOutput)
 }
 
 func (s *modelStatusSuite) TestModelStatus(c *gc.C) {
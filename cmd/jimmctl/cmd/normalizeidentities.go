@@ -0,0 +1,127 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const normalizeIdentitiesDoc = `
+	normalize-identities renames the existing identities whose names do
+	not match the identity normalization configured in JIMM, for example
+	after identities from a legacy domain are mapped to a new domain. The
+	records, access and cloud credentials of each identity are moved to
+	the normalized name. Identities whose normalized name already exists
+	are not renamed and the conflict is reported.
+
+	Example:
+		jimmctl normalize-identities --dry-run
+		jimmctl normalize-identities --format yaml
+`
+
+// NewNormalizeIdentitiesCommand returns a command to rename the existing
+// identities to their normalized names.
+func NewNormalizeIdentitiesCommand() cmd.Command {
+	cmd := &normalizeIdentitiesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// normalizeIdentitiesCommand renames the existing identities to their
+// normalized names.
+type normalizeIdentitiesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	dryRun   bool
+}
+
+// Info implements Command.Info.
+func (c *normalizeIdentitiesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "normalize-identities",
+		Purpose: "Rename identities to their normalized names.",
+		Doc:     normalizeIdentitiesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *normalizeIdentitiesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatNormalizeIdentitiesTabular,
+	})
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the renames without making them")
+}
+
+// Init implements the cmd.Command interface.
+func (c *normalizeIdentitiesCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *normalizeIdentitiesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.NormalizeIdentities(&apiparams.NormalizeIdentitiesRequest{
+		DryRun: c.dryRun,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatNormalizeIdentitiesTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.NormalizeIdentitiesResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("From", "To", "Error")
+	for _, r := range resp.Renames {
+		table.AddRow(r.From, r.To, r.Error)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
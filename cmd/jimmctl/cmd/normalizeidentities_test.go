@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type normalizeIdentitiesSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&normalizeIdentitiesSuite{})
+
+func (s *normalizeIdentitiesSuite) TestNormalizeIdentities(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewNormalizeIdentitiesCommandForTesting(s.ClientStore(), bClient), "--dry-run", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "renames: []\n")
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewNormalizeIdentitiesCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `From +To +Error\s*`)
+}
+
+func (s *normalizeIdentitiesSuite) TestNormalizeIdentitiesUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewNormalizeIdentitiesCommandForTesting(s.ClientStore(), bClient), "--dry-run")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *normalizeIdentitiesSuite) TestNormalizeIdentitiesTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewNormalizeIdentitiesCommandForTesting(s.ClientStore(), bClient), "a")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+)
+
+type operationsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&operationsSuite{})
+
+func (s *operationsSuite) addOperation(c *gc.C, uuid, identityName string) {
+	o := dbmodel.Operation{
+		UUID:         uuid,
+		Kind:         "rotate-cloud-credentials",
+		IdentityName: identityName,
+		Status:       dbmodel.OperationRunning,
+		Progress:     50,
+	}
+	err := s.JIMM.Database.AddOperation(context.Background(), &o)
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.AddOperationStep(context.Background(), &dbmodel.OperationStep{
+		OperationID: o.ID,
+		Message:     "rotated a credential",
+	})
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *operationsSuite) TestShowOperation(c *gc.C) {
+	s.addOperation(c, "00000000-0000-0000-0000-000000000001", "bob@canonical.com")
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewShowOperationCommandForTesting(s.ClientStore(), bClient), "00000000-0000-0000-0000-000000000001")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)id: 00000000-0000-0000-0000-000000000001
+kind: rotate-cloud-credentials
+started-by: bob@canonical.com
+status: running
+progress: 50
+steps:
+- time: .*
+  message: rotated a credential
+started: .*`)
+}
+
+func (s *operationsSuite) TestShowOperationNotFound(c *gc.C) {
+	s.addOperation(c, "00000000-0000-0000-0000-000000000001", "alice@canonical.com")
+
+	// bob cannot see operations started by others.
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewShowOperationCommandForTesting(s.ClientStore(), bClient), "00000000-0000-0000-0000-000000000001")
+	c.Assert(err, gc.ErrorMatches, `operation not found.*`)
+}
+
+func (s *operationsSuite) TestShowOperationInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewShowOperationCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `operation id not specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewShowOperationCommandForTesting(s.ClientStore(), bClient), "id-1", "extra")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
+
+func (s *operationsSuite) TestListOperations(c *gc.C) {
+	s.addOperation(c, "00000000-0000-0000-0000-000000000001", "alice@canonical.com")
+	s.addOperation(c, "00000000-0000-0000-0000-000000000002", "bob@canonical.com")
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewListOperationsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `ID +Kind +Started by +Status +Progress +Started\s*
+00000000-0000-0000-0000-000000000002 +rotate-cloud-credentials +bob@canonical.com +running +50% +.*`)
+
+	// alice is superuser
+	aClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewListOperationsCommandForTesting(s.ClientStore(), aClient), "--all")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)ID +Kind +Started by +Status +Progress +Started\s*
+00000000-0000-0000-0000-000000000002 .*
+00000000-0000-0000-0000-000000000001 .*`)
+}
+
+func (s *operationsSuite) TestListOperationsAllUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewListOperationsCommandForTesting(s.ClientStore(), bClient), "--all")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
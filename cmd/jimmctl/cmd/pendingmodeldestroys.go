@@ -0,0 +1,121 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const pendingModelDestroysDoc = `
+	pending-model-destroys lists the models waiting to be destroyed at the
+	end of their destroy confirmation window, the earliest first. JIMM
+	administrators see every pending destroy, other users see the pending
+	destroys of the models they own.
+
+	Example:
+		jimmctl pending-model-destroys
+		jimmctl pending-model-destroys --format yaml
+`
+
+// NewPendingModelDestroysCommand returns a command to list the models
+// waiting to be destroyed.
+func NewPendingModelDestroysCommand() cmd.Command {
+	cmd := &pendingModelDestroysCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// pendingModelDestroysCommand lists the models waiting to be destroyed.
+type pendingModelDestroysCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *pendingModelDestroysCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "pending-model-destroys",
+		Purpose: "List models waiting to be destroyed.",
+		Doc:     pendingModelDestroysDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *pendingModelDestroysCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPendingModelDestroysTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *pendingModelDestroysCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *pendingModelDestroysCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListPendingModelDestroys()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatPendingModelDestroysTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.ListPendingModelDestroysResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Model", "Owner", "Requested by", "Requested at", "Destroy at")
+	for _, d := range resp.Destroys {
+		table.AddRow(d.Name, d.Owner, d.RequestedBy, d.RequestedAt.UTC().Format(time.RFC3339), d.DestroyAt.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
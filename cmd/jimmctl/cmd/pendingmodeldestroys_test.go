@@ -0,0 +1,50 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type pendingModelDestroysSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&pendingModelDestroysSuite{})
+
+func (s *pendingModelDestroysSuite) TestPendingModelDestroys(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewPendingModelDestroysCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "destroys: []\n")
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewPendingModelDestroysCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +Owner +Requested by +Requested at +Destroy at\s*`)
+}
+
+func (s *pendingModelDestroysSuite) TestPendingModelDestroysInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewPendingModelDestroysCommandForTesting(s.ClientStore(), bClient), "a")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
+
+func (s *pendingModelDestroysSuite) TestCancelModelDestroyNotFound(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewCancelModelDestroyCommandForTesting(s.ClientStore(), bClient), "00000002-0000-0000-0000-000000000099")
+	c.Assert(err, gc.ErrorMatches, `.*not found.*`)
+}
+
+func (s *pendingModelDestroysSuite) TestCancelModelDestroyInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewCancelModelDestroyCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `missing model uuid`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewCancelModelDestroyCommandForTesting(s.ClientStore(), bClient), "not-a-model")
+	c.Assert(err, gc.ErrorMatches, `not-a-model is not a valid model uuid or <owner>/<name> path`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewCancelModelDestroyCommandForTesting(s.ClientStore(), bClient), "00000002-0000-0000-0000-000000000001", "b")
+	c.Assert(err, gc.ErrorMatches, `unknown arguments`)
+}
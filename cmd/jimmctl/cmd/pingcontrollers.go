@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const pingControllersDoc = `
+	ping-controllers contacts every controller known to JIMM, logging in
+	and checking the controller supports the API versions JIMM uses, and
+	reports how long each controller took to respond or why it could not
+	be reached. Several controllers are contacted at once.
+
+	Example:
+		jimmctl ping-controllers
+		jimmctl ping-controllers --format yaml
+`
+
+// NewPingControllersCommand returns a command to probe every controller.
+func NewPingControllersCommand() cmd.Command {
+	cmd := &pingControllersCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// pingControllersCommand probes every controller.
+type pingControllersCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *pingControllersCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "ping-controllers",
+		Purpose: "Check the connectivity of every controller.",
+		Doc:     pingControllersDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *pingControllersCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatPingControllersTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *pingControllersCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *pingControllersCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.PingControllers()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatPingControllersTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.PingControllersResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Status", "Latency", "Version", "Error")
+	for _, r := range resp.Results {
+		status := "unreachable"
+		if r.Reachable {
+			status = "ok"
+		}
+		table.AddRow(r.Controller, status, fmt.Sprintf("%dms", r.LatencyMS), r.AgentVersion, r.Error)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
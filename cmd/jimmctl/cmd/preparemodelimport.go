@@ -0,0 +1,155 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const prepareModelImportDoc = `
+	prepare-model-import prepares the import of a model exported from
+	another JIMM deployment with export-model. The model will be migrated
+	to the given controller. Users and groups named differently in this
+	JIMM are mapped with --map-user and --map-group, which take comma
+	separated <source name>=<name> pairs. The groups must already exist
+	and the model's owner must hold a credential for the model's cloud.
+
+	The import is written as a YAML document, which is given to
+	migrate-model-external on the JIMM the model is exported from. The
+	document holds the controller's admin credentials and must be kept
+	secret. The export is read from standard input if the file is "-".
+
+	Example:
+		jimmctl prepare-model-import controller-1 model-1.yaml --map-user alice@example.com=alice@canonical.com > import.yaml
+`
+
+// NewPrepareModelImportCommand returns a command to prepare the import
+// of a model exported from another JIMM deployment.
+func NewPrepareModelImportCommand() cmd.Command {
+	cmd := &prepareModelImportCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// prepareModelImportCommand prepares the import of a model exported from
+// another JIMM deployment.
+type prepareModelImportCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+	export     cmd.FileVar
+	mapUsers   string
+	mapGroups  string
+
+	identities map[string]string
+	groups     map[string]string
+}
+
+// Info implements Command.Info.
+func (c *prepareModelImportCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "prepare-model-import",
+		Args:    "<controller> <filename>",
+		Purpose: "Prepare the import of a model from another JIMM deployment.",
+		Doc:     prepareModelImportDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *prepareModelImportCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.mapUsers, "map-user", "", "comma separated <source name>=<name> user mappings")
+	f.StringVar(&c.mapGroups, "map-group", "", "comma separated <source name>=<name> group mappings")
+}
+
+// Init implements the cmd.Command interface.
+func (c *prepareModelImportCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("controller and filename must be specified")
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	c.controller = args[0]
+	c.export.Path = args[1]
+	var err error
+	if c.identities, err = parseNameMappings(c.mapUsers); err != nil {
+		return err
+	}
+	if c.groups, err = parseNameMappings(c.mapGroups); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parseNameMappings parses comma separated <from>=<to> pairs.
+func parseNameMappings(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	m := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" || to == "" {
+			return nil, errors.E(fmt.Sprintf("invalid mapping %q", pair))
+		}
+		m[from] = to
+	}
+	return m, nil
+}
+
+// Run implements Command.Run.
+func (c *prepareModelImportCommand) Run(ctxt *cmd.Context) error {
+	req := apiparams.PrepareModelImportRequest{
+		Controller: c.controller,
+		Identities: c.identities,
+		Groups:     c.groups,
+	}
+	if err := unmarshalYAMLFile(ctxt, &req.Export, c.export); err != nil {
+		return errors.E(err, "cannot read model export")
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	mi, err := client.PrepareModelImport(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, mi)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const provisionControllerServiceUserDoc = `
+	provision-controller-service-user creates a dedicated JIMM service
+	user on the named controller, using the credentials JIMM currently
+	holds for it, and switches JIMM to connecting to the controller as
+	that user. Controllers added while JIMM provisions service users
+	already have one, the command upgrades controllers added before then.
+	Running the command again resets the service user's password.
+	Controllers on which the service user could not be provisioned when
+	they were added are listed with service-user-pending set.
+
+	The service user is a controller superuser. This is not least
+	privilege: Juju has no controller access level between add-model and
+	superuser, and JIMM cannot manage a controller with less. The service
+	user only replaces the controller's shared admin account with one
+	used by JIMM alone.
+
+	Example:
+		jimmctl provision-controller-service-user mycontroller
+`
+
+// NewProvisionControllerServiceUserCommand returns a command to provision
+// the JIMM service user on a controller.
+func NewProvisionControllerServiceUserCommand() cmd.Command {
+	cmd := &provisionControllerServiceUserCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// provisionControllerServiceUserCommand provisions the JIMM service user
+// on a controller.
+type provisionControllerServiceUserCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+}
+
+// Info implements Command.Info.
+func (c *provisionControllerServiceUserCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "provision-controller-service-user",
+		Args:    "<controller name>",
+		Purpose: "Provision the JIMM service user on a controller.",
+		Doc:     provisionControllerServiceUserDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *provisionControllerServiceUserCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *provisionControllerServiceUserCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("controller name not specified")
+	}
+	c.controller = args[0]
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *provisionControllerServiceUserCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ProvisionControllerServiceUser(&apiparams.ProvisionControllerServiceUserRequest{
+		Controller: c.controller,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
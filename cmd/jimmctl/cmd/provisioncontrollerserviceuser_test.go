@@ -0,0 +1,35 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type provisionControllerServiceUserSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&provisionControllerServiceUserSuite{})
+
+func (s *provisionControllerServiceUserSuite) TestProvisionControllerServiceUserUnauthorized(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewProvisionControllerServiceUserCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *provisionControllerServiceUserSuite) TestProvisionControllerServiceUserInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewProvisionControllerServiceUserCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `controller name not specified`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewProvisionControllerServiceUserCommandForTesting(s.ClientStore(), bClient), "controller-1", "extra")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,175 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const rebalanceDoc = `
+	rebalance compares the placement of models against the load on each
+	available controller and recommends model migrations that would
+	balance the models across the controllers. Models are moved off
+	deprecated controllers, and controllers hosting more than the maximum
+	number of models, first. The number of models and machines each
+	controller would host after the migrations is shown.
+
+	The recommendations are not checked with the target controllers, use
+	--apply to start the recommended migrations.
+
+	Example:
+		jimmctl rebalance
+		jimmctl rebalance --limit 5 --format yaml
+		jimmctl rebalance --limit 5 --apply
+`
+
+// NewRebalanceCommand returns a command to recommend, and optionally
+// start, model migrations that balance the controllers.
+func NewRebalanceCommand() cmd.Command {
+	cmd := &rebalanceCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// rebalanceCommand recommends model migrations that balance the
+// controllers.
+type rebalanceCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	limit    int
+	apply    bool
+}
+
+// Info implements Command.Info.
+func (c *rebalanceCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rebalance",
+		Purpose: "Recommend model migrations that balance the controllers.",
+		Doc:     rebalanceDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rebalanceCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRebalanceTabular,
+	})
+	f.IntVar(&c.limit, "limit", 0, "the maximum number of migrations to recommend")
+	f.BoolVar(&c.apply, "apply", false, "start the recommended migrations")
+}
+
+// Init implements the cmd.Command interface.
+func (c *rebalanceCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.limit < 0 {
+		return errors.E("limit cannot be negative")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *rebalanceCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	report, err := client.RebalanceRecommendations(&apiparams.RebalanceRecommendationsRequest{Limit: c.limit})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, report)
+	if err != nil {
+		return errors.E(err)
+	}
+	if !c.apply || len(report.Recommendations) == 0 {
+		return nil
+	}
+
+	var req apiparams.MigrateModelRequest
+	for _, r := range report.Recommendations {
+		req.Specs = append(req.Specs, apiparams.MigrateModelInfo{
+			ModelTag:         r.ModelTag,
+			TargetController: r.TargetController,
+		})
+	}
+	results, err := client.MigrateModel(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+	var failed int
+	for i, result := range results.Results {
+		r := report.Recommendations[i]
+		if result.Error != nil {
+			ctxt.Warningf("cannot migrate %s to %s: %s", r.Model, r.TargetController, result.Error)
+			failed++
+			continue
+		}
+		ctxt.Infof("migrating %s to %s", r.Model, r.TargetController)
+	}
+	if failed > 0 {
+		return errors.E(fmt.Sprintf("%d of %d migrations failed to start", failed, len(results.Results)))
+	}
+	return nil
+}
+
+func formatRebalanceTabular(writer io.Writer, value interface{}) error {
+	report, ok := value.(*apiparams.RebalanceReport)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", report, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Models", "Machines", "Models after", "Machines after")
+	for _, l := range report.Controllers {
+		name := l.Name
+		if l.Deprecated {
+			name += " (deprecated)"
+		}
+		table.AddRow(name, l.Models, l.Machines, l.ModelsAfter, l.MachinesAfter)
+	}
+	table.AddRow("", "", "", "", "")
+	if len(report.Recommendations) == 0 {
+		table.AddRow("No migrations recommended.")
+	} else {
+		table.AddRow("Model", "From", "To", "Machines", "Reason")
+		for _, r := range report.Recommendations {
+			table.AddRow(r.Model, r.SourceController, r.TargetController, r.Machines, r.Reason)
+		}
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type rebalanceSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&rebalanceSuite{})
+
+func (s *rebalanceSuite) TestRebalance(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewRebalanceCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)controllers:
+.*- name: controller-1
+  models: \d+
+  machines: \d+
+  models-after: \d+
+  machines-after: \d+
+.*recommendations: \[\]
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewRebalanceCommandForTesting(s.ClientStore(), bClient), "--apply")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Controller +Models +Machines +Models after +Machines after\s*\n.*controller-1 +\d+ +\d+ +\d+ +\d+.*No migrations recommended.*`)
+}
+
+func (s *rebalanceSuite) TestRebalanceUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRebalanceCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *rebalanceSuite) TestRebalanceInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRebalanceCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRebalanceCommandForTesting(s.ClientStore(), bClient), "--limit", "-1")
+	c.Assert(err, gc.ErrorMatches, `limit cannot be negative`)
+}
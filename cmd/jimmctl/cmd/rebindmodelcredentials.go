@@ -0,0 +1,145 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const rebindModelCredentialsDoc = `
+	rebind-model-credentials moves the models using cloud credentials
+	owned by a departing user onto credentials shared with a group, so
+	that the models keep working once the user's account is deactivated.
+	For each cloud the first credential the group's members have been
+	allowed to use is chosen, and it is validated by the controller
+	hosting each model before the model is changed. With --dry-run the
+	models that would be rebound are reported without changing them.
+
+	Example:
+		jimmctl rebind-model-credentials alice@canonical.com platform-team --dry-run
+		jimmctl rebind-model-credentials alice@canonical.com platform-team
+`
+
+// NewRebindModelCredentialsCommand returns a command to rebind the models
+// of a departing user to credentials shared with a group.
+func NewRebindModelCredentialsCommand() cmd.Command {
+	cmd := &rebindModelCredentialsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// rebindModelCredentialsCommand rebinds the models of a departing user to
+// credentials shared with a group.
+type rebindModelCredentialsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	user   string
+	group  string
+	dryRun bool
+}
+
+// Info implements Command.Info.
+func (c *rebindModelCredentialsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rebind-model-credentials",
+		Args:    "<user> <group>",
+		Purpose: "Rebind the models of a departing user to credentials shared with a group.",
+		Doc:     rebindModelCredentialsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rebindModelCredentialsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRebindModelCredentialsTabular,
+	})
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the models that would be rebound without changing them")
+}
+
+// Init implements the cmd.Command interface.
+func (c *rebindModelCredentialsCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.E("user and group must be specified")
+	}
+	if len(args) > 2 {
+		return errors.E("too many args")
+	}
+	c.user, c.group = args[0], args[1]
+	if !names.IsValidUser(c.user) {
+		return errors.E("invalid user name")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *rebindModelCredentialsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RebindModelCredentials(&apiparams.RebindModelCredentialsRequest{
+		UserTag: names.NewUserTag(c.user).String(),
+		Group:   c.group,
+		DryRun:  c.dryRun,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatRebindModelCredentialsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.RebindModelCredentialsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Model", "From", "To", "Error")
+	for _, r := range resp.Rebinds {
+		table.AddRow(r.ModelTag, r.FromCredential, r.ToCredential, r.Error)
+	}
+	fmt.Fprint(writer, table)
+	if resp.DryRun {
+		fmt.Fprint(writer, "\ndry run, no changes made")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+type rebindModelCredentialsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&rebindModelCredentialsSuite{})
+
+func (s *rebindModelCredentialsSuite) TestRebindModelCredentialsDryRun(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	shared := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/dave@canonical.com/shared")
+	s.UpdateCloudCredential(c, shared, jujuparams.CloudCredential{AuthType: "empty"})
+	group, err := s.JIMM.Database.AddGroup(ctx, "platform")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.OpenFGAClient.SetCloudCredentialAccess(ctx, shared, ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation))
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewRebindModelCredentialsCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com", "platform", "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Model +From +To +Error\s*
+`+mt.String()+` +`+cct.String()+` +`+shared.String()+`\s*
+dry run, no changes made`)
+}
+
+func (s *rebindModelCredentialsSuite) TestRebindModelCredentialsUnauthorized(c *gc.C) {
+	_, err := s.JIMM.Database.AddGroup(context.Background(), "platform")
+	c.Assert(err, gc.IsNil)
+
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err = cmdtesting.RunCommand(c, cmd.NewRebindModelCredentialsCommandForTesting(s.ClientStore(), bClient), "charlie@canonical.com", "platform")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *rebindModelCredentialsSuite) TestRebindModelCredentialsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	for _, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		args:        []string{"charlie@canonical.com"},
+		expectError: "user and group must be specified",
+	}, {
+		args:        []string{"charlie@canonical.com", "platform", "extra"},
+		expectError: "too many args",
+	}, {
+		args:        []string{"not a user!", "platform"},
+		expectError: "invalid user name",
+	}} {
+		_, err := cmdtesting.RunCommand(c, cmd.NewRebindModelCredentialsCommandForTesting(s.ClientStore(), bClient), test.args...)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
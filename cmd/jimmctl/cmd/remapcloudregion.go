@@ -0,0 +1,118 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const remapCloudRegionDoc = `
+	remap-cloud-region remaps a cloud region in JIMM's records after the
+	cloud provider renamed or split the region. Every controller serving
+	the region must already know the new region, for example after
+	updating the cloud on the controllers.
+
+	By default the whole region is remapped: its models and controllers
+	are moved to the new region and the old region name is kept as an
+	alias, so that requests using the old name keep working. When a
+	region is split the models to move can be given with --models, the
+	remaining models stay in the old region.
+
+	Example:
+		jimmctl remap-cloud-region <cloud> <region> <new region>
+		jimmctl remap-cloud-region <cloud> <region> <new region> --models <model uuid>,<model uuid>
+`
+
+// NewRemapCloudRegionCommand returns a command to remap a cloud region.
+func NewRemapCloudRegionCommand() cmd.Command {
+	cmd := &remapCloudRegionCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// remapCloudRegionCommand remaps a cloud region.
+type remapCloudRegionCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req    apiparams.RemapCloudRegionRequest
+	models string
+}
+
+// Info implements Command.Info.
+func (c *remapCloudRegionCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remap-cloud-region",
+		Args:    "<cloud> <region> <new region>",
+		Purpose: "Remap a cloud region after it was renamed or split.",
+		Doc:     remapCloudRegionDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *remapCloudRegionCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.models, "models", "", "comma separated UUIDs of the models to move to the new region")
+}
+
+// Init implements the cmd.Command interface.
+func (c *remapCloudRegionCommand) Init(args []string) error {
+	if len(args) < 3 {
+		return errors.E("cloud, region and new region must be specified")
+	}
+	if len(args) > 3 {
+		return errors.E("too many args")
+	}
+	c.req.Cloud, c.req.Region, c.req.NewRegion = args[0], args[1], args[2]
+	for _, m := range strings.Split(c.models, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			c.req.Models = append(c.req.Models, m)
+		}
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *remapCloudRegionCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RemapCloudRegion(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
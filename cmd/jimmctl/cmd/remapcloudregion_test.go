@@ -0,0 +1,51 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type remapCloudRegionSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&remapCloudRegionSuite{})
+
+func (s *remapCloudRegionSuite) TestRemapCloudRegionUnknownToController(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRemapCloudRegionCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, jimmtest.TestCloudRegionName, "no-such-region")
+	c.Assert(err, gc.ErrorMatches, `controller controller-1 does not have cloud region `+jimmtest.TestCloudName+`/no-such-region \(bad request\)`)
+}
+
+func (s *remapCloudRegionSuite) TestRemapCloudRegionNotFound(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRemapCloudRegionCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, "no-such-region", jimmtest.TestCloudRegionName)
+	c.Assert(err, gc.ErrorMatches, `cloud region `+jimmtest.TestCloudName+`/no-such-region not found \(not found\)`)
+}
+
+func (s *remapCloudRegionSuite) TestRemapCloudRegionUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRemapCloudRegionCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, jimmtest.TestCloudRegionName, "new-region")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *remapCloudRegionSuite) TestRemapCloudRegionInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRemapCloudRegionCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, jimmtest.TestCloudRegionName)
+	c.Assert(err, gc.ErrorMatches, `cloud, region and new region must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewRemapCloudRegionCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, jimmtest.TestCloudRegionName, "new-region", "extra")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const repairDenormalizedFieldsDoc = `
+	repair-denormalized-fields recomputes the denormalized fields JIMM
+	holds on models and controllers from their authoritative sources, and
+	repairs those that have drifted. The entity counts and workload status
+	of each model are recomputed from the state recorded by its watcher,
+	and the cloud region of each controller from the region it is deployed
+	in. With --dry-run the repairs are reported without being made.
+
+	Example:
+		jimmctl repair-denormalized-fields --dry-run
+		jimmctl repair-denormalized-fields
+`
+
+// NewRepairDenormalizedFieldsCommand returns a command to repair the
+// denormalized fields held on models and controllers.
+func NewRepairDenormalizedFieldsCommand() cmd.Command {
+	cmd := &repairDenormalizedFieldsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// repairDenormalizedFieldsCommand repairs the denormalized fields held on
+// models and controllers.
+type repairDenormalizedFieldsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	dryRun bool
+}
+
+// Info implements Command.Info.
+func (c *repairDenormalizedFieldsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "repair-denormalized-fields",
+		Purpose: "Repair the denormalized fields held on models and controllers.",
+		Doc:     repairDenormalizedFieldsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *repairDenormalizedFieldsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRepairDenormalizedFieldsTabular,
+	})
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the repairs that would be made without making them")
+}
+
+// Init implements the cmd.Command interface.
+func (c *repairDenormalizedFieldsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *repairDenormalizedFieldsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RepairDenormalizedFields(&apiparams.RepairDenormalizedFieldsRequest{
+		DryRun: c.dryRun,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatRepairDenormalizedFieldsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.RepairDenormalizedFieldsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+	if len(resp.Repairs) == 0 {
+		fmt.Fprint(writer, "no repairs needed")
+		return nil
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Kind", "Name", "Field", "From", "To")
+	for _, r := range resp.Repairs {
+		table.AddRow(r.Kind, r.Name, r.Field, r.From, r.To)
+	}
+	fmt.Fprint(writer, table)
+	if resp.DryRun {
+		fmt.Fprint(writer, "\ndry run, no repairs made")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type repairDenormalizedFieldsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&repairDenormalizedFieldsSuite{})
+
+func (s *repairDenormalizedFieldsSuite) TestRepairDenormalizedFields(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewRepairDenormalizedFieldsCommandForTesting(s.ClientStore(), bClient), "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "no repairs needed\n")
+}
+
+func (s *repairDenormalizedFieldsSuite) TestRepairDenormalizedFieldsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRepairDenormalizedFieldsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *repairDenormalizedFieldsSuite) TestRepairDenormalizedFieldsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRepairDenormalizedFieldsCommandForTesting(s.ClientStore(), bClient), "extra")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
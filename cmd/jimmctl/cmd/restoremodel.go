@@ -0,0 +1,99 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const restoreModelDoc = `
+	restore-model restores a model that was removed from JIMM, importing
+	it again from the controller that hosted it with its original owner.
+	The model must still exist on the controller and its record must not
+	yet have been pruned, see deleted-models. Only JIMM administrators may
+	restore models.
+
+	Example:
+		jimmctl restore-model 00000002-0000-0000-0000-000000000001
+`
+
+// NewRestoreModelCommand returns a command to restore a model removed
+// from JIMM.
+func NewRestoreModelCommand() cmd.Command {
+	cmd := &restoreModelCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// restoreModelCommand restores a model removed from JIMM.
+type restoreModelCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+	model    string
+}
+
+// Info implements Command.Info.
+func (c *restoreModelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "restore-model",
+		Args:    "<model uuid>",
+		Purpose: "Restore a model removed from JIMM.",
+		Doc:     restoreModelDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreModelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+}
+
+// Init implements the cmd.Command interface.
+func (c *restoreModelCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("missing model uuid")
+	}
+	c.model, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	if !names.IsValidModel(c.model) {
+		return errors.E("invalid model uuid")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *restoreModelCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.RestoreModel(&apiparams.DeletedModelRequest{
+		ModelTag: names.NewModelTag(c.model).String(),
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
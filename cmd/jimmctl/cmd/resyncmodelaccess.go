@@ -0,0 +1,112 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const resyncModelAccessDoc = `
+	resync-model-access compares JIMM's view of model access with the
+	access held on the controllers hosting the models. The controllers
+	are queried at a limited rate so this may take some time if there are
+	many models, the re-sync runs in the background as an operation whose
+	ID is printed. The differences found are reported in the operation's
+	result, see show-operation.
+
+	By default the differences are only reported. With --apply JIMM's
+	admin access to each model is restored if missing and any access
+	granted directly on a controller is revoked.
+
+	Example:
+		jimmctl resync-model-access
+		jimmctl resync-model-access --controller <controller name>
+		jimmctl resync-model-access --apply
+`
+
+// NewResyncModelAccessCommand returns a command to re-sync model access
+// to the controllers.
+func NewResyncModelAccessCommand() cmd.Command {
+	cmd := &resyncModelAccessCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// resyncModelAccessCommand re-syncs model access to the controllers.
+type resyncModelAccessCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+	apply      bool
+}
+
+// Info implements Command.Info.
+func (c *resyncModelAccessCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "resync-model-access",
+		Purpose: "Re-sync model access to the controllers.",
+		Doc:     resyncModelAccessDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *resyncModelAccessCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.StringVar(&c.controller, "controller", "", "only re-sync the models on the named controller")
+	f.BoolVar(&c.apply, "apply", false, "fix the differences found rather than only reporting them")
+}
+
+// Init implements the cmd.Command interface.
+func (c *resyncModelAccessCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *resyncModelAccessCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ResyncModelAccess(&apiparams.ResyncModelAccessRequest{
+		Controller: c.controller,
+		Apply:      c.apply,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"strings"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type resyncModelAccessSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&resyncModelAccessSuite{})
+
+func (s *resyncModelAccessSuite) TestResyncModelAccess(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewResyncModelAccessCommandForTesting(s.ClientStore(), bClient), "--controller", "controller-1")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, "operation: [0-9a-f-]+\n")
+	id := strings.TrimSuffix(strings.TrimPrefix(cmdtesting.Stdout(cmdContext), "operation: "), "\n")
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewShowOperationCommandForTesting(s.ClientStore(), bClient), id)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)id: `+id+`\nkind: resync-model-access\nstarted-by: alice@canonical.com\n.*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewResyncModelAccessCommandForTesting(s.ClientStore(), bClient), "--controller", "controller-1", "--apply")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, "applied: true\noperation: [0-9a-f-]+\n")
+}
+
+func (s *resyncModelAccessSuite) TestResyncModelAccessUnknownController(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewResyncModelAccessCommandForTesting(s.ClientStore(), bClient), "--controller", "no-such-controller")
+	c.Assert(err, gc.ErrorMatches, `controller not found.*`)
+}
+
+func (s *resyncModelAccessSuite) TestResyncModelAccessUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewResyncModelAccessCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *resyncModelAccessSuite) TestResyncModelAccessTooManyArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewResyncModelAccessCommandForTesting(s.ClientStore(), bClient), "controller-1")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
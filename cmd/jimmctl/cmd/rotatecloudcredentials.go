@@ -0,0 +1,182 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const rotateCloudCredentialsDoc = `
+	rotate-cloud-credentials updates many cloud credentials for a cloud in
+	a single run, for example when an organisation rolls over its cloud
+	keys. The rotations are read from a YAML file holding a list of
+	attribute values to replace:
+
+		- attribute: access-key
+		  old: AKIAOLD
+		  new: AKIANEW
+
+	Every credential with an attribute holding an old value has it
+	replaced. If no rotations are given the rotation hook configured in
+	JIMM for the cloud's provider computes the new attributes. Rotated
+	credentials are checked and updated on every controller using them.
+	With --dry-run the credentials that would be rotated are reported
+	without changing them. With --async the rotation runs in the
+	background and the ID of its operation is reported, the progress of
+	the rotation can then be followed with show-operation.
+
+	Example:
+		jimmctl rotate-cloud-credentials aws --rotations keys.yaml --dry-run
+		jimmctl rotate-cloud-credentials aws --rotations keys.yaml --concurrency 10
+		jimmctl rotate-cloud-credentials aws --rotations keys.yaml --async
+`
+
+// NewRotateCloudCredentialsCommand returns a command to rotate the cloud
+// credentials for a cloud.
+func NewRotateCloudCredentialsCommand() cmd.Command {
+	cmd := &rotateCloudCredentialsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// rotateCloudCredentialsCommand rotates the cloud credentials for a
+// cloud.
+type rotateCloudCredentialsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	cloud       string
+	rotations   cmd.FileVar
+	authType    string
+	concurrency int
+	dryRun      bool
+	async       bool
+}
+
+// Info implements Command.Info.
+func (c *rotateCloudCredentialsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rotate-cloud-credentials",
+		Args:    "<cloud>",
+		Purpose: "Rotate the cloud credentials for a cloud.",
+		Doc:     rotateCloudCredentialsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rotateCloudCredentialsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatRotateCloudCredentialsTabular,
+	})
+	f.Var(&c.rotations, "rotations", "path to a YAML file holding the attribute values to replace")
+	f.StringVar(&c.authType, "auth-type", "", "only rotate credentials with this auth type")
+	f.IntVar(&c.concurrency, "concurrency", 0, "maximum number of credentials rotated at once")
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the credentials that would be rotated without changing them")
+	f.BoolVar(&c.async, "async", false, "rotate the credentials in the background, reporting the operation ID")
+}
+
+// Init implements the cmd.Command interface.
+func (c *rotateCloudCredentialsCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("cloud not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.cloud = args[0]
+	if !names.IsValidCloud(c.cloud) {
+		return errors.E("invalid cloud name")
+	}
+	if c.concurrency < 0 {
+		return errors.E("concurrency must not be negative")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *rotateCloudCredentialsCommand) Run(ctxt *cmd.Context) error {
+	req := apiparams.RotateCloudCredentialsRequest{
+		CloudTag:    names.NewCloudTag(c.cloud).String(),
+		AuthType:    c.authType,
+		Concurrency: c.concurrency,
+		DryRun:      c.dryRun,
+		Async:       c.async,
+	}
+	if c.rotations.Path != "" {
+		if err := unmarshalYAMLFile(ctxt, &req.Rotations, c.rotations); err != nil {
+			return errors.E(err, "cannot read rotations")
+		}
+	}
+
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RotateCloudCredentials(&req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatRotateCloudCredentialsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.RotateCloudCredentialsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	if resp.Operation != "" {
+		fmt.Fprintf(writer, "rotation started as operation %s", resp.Operation)
+		return nil
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Credential", "Attributes", "Error")
+	for _, r := range resp.Results {
+		table.AddRow(r.CredentialTag, strings.Join(r.Attributes, ","), r.Error)
+	}
+	fmt.Fprint(writer, table)
+	fmt.Fprintf(writer, "\n%d credentials unchanged", resp.Unchanged)
+	if resp.DryRun {
+		fmt.Fprint(writer, "\ndry run, no changes made")
+	}
+	return nil
+}
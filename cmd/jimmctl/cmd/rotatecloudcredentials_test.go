@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type rotateCloudCredentialsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&rotateCloudCredentialsSuite{})
+
+func (s *rotateCloudCredentialsSuite) TestRotateCloudCredentialsDryRun(c *gc.C) {
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{
+		AuthType:   "userpass",
+		Attributes: map[string]string{"username": "charlie", "password": "old-password"},
+	})
+	other := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/dave@canonical.com/cred")
+	s.UpdateCloudCredential(c, other, jujuparams.CloudCredential{
+		AuthType:   "userpass",
+		Attributes: map[string]string{"username": "dave", "password": "other-password"},
+	})
+
+	rotations := filepath.Join(c.MkDir(), "rotations.yaml")
+	err := os.WriteFile(rotations, []byte("- attribute: password\n  old: old-password\n  new: new-password\n"), 0600)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewRotateCloudCredentialsCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, "--rotations", rotations, "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Credential +Attributes +Error\s*
+`+cct.String()+` +password\s*
+1 credentials unchanged
+dry run, no changes made`)
+}
+
+func (s *rotateCloudCredentialsSuite) TestRotateCloudCredentialsAsync(c *gc.C) {
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{
+		AuthType:   "userpass",
+		Attributes: map[string]string{"username": "charlie", "password": "old-password"},
+	})
+
+	rotations := filepath.Join(c.MkDir(), "rotations.yaml")
+	err := os.WriteFile(rotations, []byte("- attribute: password\n  old: old-password\n  new: new-password\n"), 0600)
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewRotateCloudCredentialsCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, "--rotations", rotations, "--dry-run", "--async")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `rotation started as operation [0-9a-f-]+`)
+	id := strings.TrimPrefix(cmdtesting.Stdout(cmdContext), "rotation started as operation ")
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewShowOperationCommandForTesting(s.ClientStore(), bClient), id)
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)id: `+id+`\nkind: rotate-cloud-credentials\nstarted-by: alice@canonical.com\n.*`)
+}
+
+func (s *rotateCloudCredentialsSuite) TestRotateCloudCredentialsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRotateCloudCredentialsCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName)
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *rotateCloudCredentialsSuite) TestRotateCloudCredentialsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	for _, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		args:        []string{},
+		expectError: "cloud not specified",
+	}, {
+		args:        []string{jimmtest.TestCloudName, "extra"},
+		expectError: "too many args",
+	}, {
+		args:        []string{"not a cloud!"},
+		expectError: "invalid cloud name",
+	}} {
+		_, err := cmdtesting.RunCommand(c, cmd.NewRotateCloudCredentialsCommandForTesting(s.ClientStore(), bClient), test.args...)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
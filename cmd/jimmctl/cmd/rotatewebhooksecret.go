@@ -0,0 +1,111 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const rotateWebhookSecretDoc = `
+	rotate-webhook-secret generates a new secret for signing the
+	deliveries to a webhook, printing the hex encoded secret. The secret
+	is only shown once and cannot be read back.
+
+	Once a webhook has a secret each delivery is signed with the
+	X-JIMM-Signature, X-JIMM-Timestamp and X-JIMM-Nonce headers. For a
+	grace period after a rotation deliveries are signed with both the new
+	and the previous secret, so that receivers can be updated without
+	rejecting deliveries.
+
+	The access request webhook is named access-requests, notification
+	webhooks are named in their channel configuration.
+
+	Example:
+		jimmctl rotate-webhook-secret access-requests
+`
+
+// NewRotateWebhookSecretCommand returns a command to rotate a webhook's
+// signing secret.
+func NewRotateWebhookSecretCommand() cmd.Command {
+	cmd := &rotateWebhookSecretCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// rotateWebhookSecretCommand rotates a webhook's signing secret.
+type rotateWebhookSecretCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.RotateWebhookSecretRequest
+}
+
+// Info implements Command.Info.
+func (c *rotateWebhookSecretCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rotate-webhook-secret",
+		Args:    "<webhook>",
+		Purpose: "Rotate the secret webhook deliveries are signed with.",
+		Doc:     rotateWebhookSecretDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *rotateWebhookSecretCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *rotateWebhookSecretCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("webhook not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Webhook = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *rotateWebhookSecretCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.RotateWebhookSecret(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
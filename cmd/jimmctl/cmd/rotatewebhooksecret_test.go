@@ -0,0 +1,71 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+type rotateWebhookSecretSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&rotateWebhookSecretSuite{})
+
+func (s *rotateWebhookSecretSuite) TestRotateWebhookSecret(c *gc.C) {
+	s.JIMM.AccessRequestNotifier = &jimm.WebhookNotifier{
+		URL:    "http://localhost/access-requests",
+		Name:   jimm.AccessRequestWebhook,
+		Signer: s.JIMM,
+	}
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewRotateWebhookSecretCommandForTesting(s.ClientStore(), bClient), jimm.AccessRequestWebhook)
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `webhook: access-requests\nsecret: [0-9a-f]{64}\nrotated-at: .*\n`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewRotateWebhookSecretCommandForTesting(s.ClientStore(), bClient), "no-such-webhook")
+	c.Assert(err, gc.ErrorMatches, `webhook "no-such-webhook" not found \(not found\)`)
+}
+
+func (s *rotateWebhookSecretSuite) TestWebhookDeliveries(c *gc.C) {
+	err := s.JIMM.Database.AddWebhookDelivery(context.Background(), &dbmodel.WebhookDelivery{
+		Webhook:   jimm.AccessRequestWebhook,
+		Nonce:     "0123456789abcdef",
+		Timestamp: 1700000000,
+		Signature: "v1=abcdef",
+	})
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewWebhookDeliveriesCommandForTesting(s.ClientStore(), bClient), jimm.AccessRequestWebhook)
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `deliveries:\n- webhook: access-requests\n  nonce: 0123456789abcdef\n  timestamp: 1700000000\n  signature: v1=abcdef\n  created-at: .*\n`)
+}
+
+func (s *rotateWebhookSecretSuite) TestWebhookSecretUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRotateWebhookSecretCommandForTesting(s.ClientStore(), bClient), jimm.AccessRequestWebhook)
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewWebhookDeliveriesCommandForTesting(s.ClientStore(), bClient), jimm.AccessRequestWebhook)
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *rotateWebhookSecretSuite) TestWebhookSecretInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewRotateWebhookSecretCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `webhook not specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewWebhookDeliveriesCommandForTesting(s.ClientStore(), bClient), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+}
@@ -0,0 +1,284 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const scheduledJobsDoc = `
+	scheduled-jobs lists the periodic jobs JIMM runs in the background,
+	their schedules, whether they are enabled, when they next run and the
+	result of their last run. If a job is given the job's most recent runs
+	are listed instead. Only JIMM administrators may list scheduled jobs.
+
+	Example:
+		jimmctl scheduled-jobs
+		jimmctl scheduled-jobs data-retention --limit 5
+`
+
+const enableScheduledJobDoc = `
+	enable-scheduled-job enables a periodic job JIMM runs in the
+	background that was disabled, the job runs again at its next
+	scheduled time. Only JIMM administrators may enable scheduled jobs.
+
+	Example:
+		jimmctl enable-scheduled-job data-retention
+`
+
+const disableScheduledJobDoc = `
+	disable-scheduled-job disables a periodic job JIMM runs in the
+	background, the job is skipped at its scheduled times until it is
+	enabled. A run already in progress is not interrupted. Only JIMM
+	administrators may disable scheduled jobs.
+
+	Example:
+		jimmctl disable-scheduled-job data-retention
+`
+
+// NewScheduledJobsCommand returns a command to list the scheduled jobs,
+// or the run history of one of them.
+func NewScheduledJobsCommand() cmd.Command {
+	cmd := &scheduledJobsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// scheduledJobsCommand lists the scheduled jobs, or the run history of
+// one of them.
+type scheduledJobsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	job   string
+	limit int
+}
+
+// Info implements Command.Info.
+func (c *scheduledJobsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "scheduled-jobs",
+		Args:    "[<job>]",
+		Purpose: "List scheduled jobs, or the runs of a job.",
+		Doc:     scheduledJobsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *scheduledJobsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatScheduledJobsTabular,
+	})
+	f.IntVar(&c.limit, "limit", 0, "the maximum number of runs of the job to list")
+}
+
+// Init implements the cmd.Command interface.
+func (c *scheduledJobsCommand) Init(args []string) error {
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	if len(args) == 1 {
+		c.job = args[0]
+	}
+	if c.limit < 0 {
+		return errors.E("limit must not be negative")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *scheduledJobsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	var resp interface{}
+	if c.job == "" {
+		jobs, err := client.ListScheduledJobs()
+		if err != nil {
+			return errors.E(err)
+		}
+		resp = &apiparams.ListScheduledJobsResponse{Jobs: jobs}
+	} else {
+		runs, err := client.ScheduledJobHistory(&apiparams.ScheduledJobHistoryRequest{
+			Job:   c.job,
+			Limit: c.limit,
+		})
+		if err != nil {
+			return errors.E(err)
+		}
+		resp = &apiparams.ScheduledJobHistoryResponse{Runs: runs}
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatScheduledJobsTabular(writer io.Writer, value interface{}) error {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return "-"
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	formatResult := func(r *apiparams.ScheduledJobRun) string {
+		switch {
+		case r == nil:
+			return "-"
+		case r.FinishedAt == nil:
+			return "unfinished"
+		case r.Error != "":
+			return "failed: " + r.Error
+		default:
+			return "ok"
+		}
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	switch resp := value.(type) {
+	case *apiparams.ListScheduledJobsResponse:
+		table.AddRow("Job", "Schedule", "Enabled", "Next run", "Last run", "Last result")
+		for _, j := range resp.Jobs {
+			var lastRun *time.Time
+			if j.LastRun != nil {
+				lastRun = &j.LastRun.StartedAt
+			}
+			table.AddRow(j.Name, j.Schedule, j.Enabled, formatTime(j.NextRun), formatTime(lastRun), formatResult(j.LastRun))
+		}
+	case *apiparams.ScheduledJobHistoryResponse:
+		table.AddRow("Started", "Finished", "Holder", "Result")
+		for _, r := range resp.Runs {
+			r := r
+			table.AddRow(formatTime(&r.StartedAt), formatTime(r.FinishedAt), r.Holder, formatResult(&r))
+		}
+	default:
+		return errors.E(fmt.Sprintf("unexpected value of type %T", value))
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
+
+// NewEnableScheduledJobCommand returns a command to enable a scheduled
+// job.
+func NewEnableScheduledJobCommand() cmd.Command {
+	cmd := &setScheduledJobEnabledCommand{
+		store:   jujuclient.NewFileClientStore(),
+		enabled: true,
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// NewDisableScheduledJobCommand returns a command to disable a scheduled
+// job.
+func NewDisableScheduledJobCommand() cmd.Command {
+	cmd := &setScheduledJobEnabledCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setScheduledJobEnabledCommand enables, or disables, a scheduled job.
+type setScheduledJobEnabledCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	enabled bool
+	job     string
+}
+
+// Info implements Command.Info.
+func (c *setScheduledJobEnabledCommand) Info() *cmd.Info {
+	if c.enabled {
+		return jujucmd.Info(&cmd.Info{
+			Name:    "enable-scheduled-job",
+			Args:    "<job>",
+			Purpose: "Enable a scheduled job.",
+			Doc:     enableScheduledJobDoc,
+		})
+	}
+	return jujucmd.Info(&cmd.Info{
+		Name:    "disable-scheduled-job",
+		Args:    "<job>",
+		Purpose: "Disable a scheduled job.",
+		Doc:     disableScheduledJobDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setScheduledJobEnabledCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+}
+
+// Init implements the cmd.Command interface.
+func (c *setScheduledJobEnabledCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("missing job name")
+	}
+	c.job, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setScheduledJobEnabledCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.SetScheduledJobEnabled(&apiparams.SetScheduledJobEnabledRequest{
+		Job:     c.job,
+		Enabled: c.enabled,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
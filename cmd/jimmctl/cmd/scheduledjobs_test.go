@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+)
+
+type scheduledJobsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&scheduledJobsSuite{})
+
+func (s *scheduledJobsSuite) TestScheduledJobs(c *gc.C) {
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Job +Schedule +Enabled +Next run +Last run +Last result\s*
+.*identity-session-expiry +@every 1h0m0s +true +\S+ +- +-\s*
+.*`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewDisableScheduledJobCommandForTesting(s.ClientStore(), bClient), "identity-session-expiry")
+	c.Assert(err, gc.IsNil)
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s).*identity-session-expiry +@every 1h0m0s +false +- +- +-\s*
+.*`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewEnableScheduledJobCommandForTesting(s.ClientStore(), bClient), "identity-session-expiry")
+	c.Assert(err, gc.IsNil)
+
+	// The job has not run.
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient), "identity-session-expiry", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Check(cmdtesting.Stdout(cmdContext), gc.Equals, "runs: []\n")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient), "no-such-job")
+	c.Check(err, gc.ErrorMatches, `scheduled job "no-such-job" not found \(not found\)`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewDisableScheduledJobCommandForTesting(s.ClientStore(), bClient), "no-such-job")
+	c.Check(err, gc.ErrorMatches, `scheduled job "no-such-job" not found \(not found\)`)
+}
+
+func (s *scheduledJobsSuite) TestScheduledJobsUnauthorized(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "charlie")
+	_, err := cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewDisableScheduledJobCommandForTesting(s.ClientStore(), bClient), "identity-session-expiry")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *scheduledJobsSuite) TestScheduledJobsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient), "a", "b")
+	c.Check(err, gc.ErrorMatches, "too many args")
+	_, err = cmdtesting.RunCommand(c, cmd.NewScheduledJobsCommandForTesting(s.ClientStore(), bClient), "--limit", "-1")
+	c.Check(err, gc.ErrorMatches, "limit must not be negative")
+	_, err = cmdtesting.RunCommand(c, cmd.NewEnableScheduledJobCommandForTesting(s.ClientStore(), bClient))
+	c.Check(err, gc.ErrorMatches, "missing job name")
+	_, err = cmdtesting.RunCommand(c, cmd.NewEnableScheduledJobCommandForTesting(s.ClientStore(), bClient), "a", "b")
+	c.Check(err, gc.ErrorMatches, "unknown arguments")
+}
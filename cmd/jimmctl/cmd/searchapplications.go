@@ -0,0 +1,149 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const searchApplicationsDoc = `
+	search-applications searches all the models the current user can see
+	for applications with an endpoint using the given interface, or with
+	an endpoint of the given name. Endpoints are found from the relations
+	seen in each model and from the application offers known to JIMM.
+
+	Example:
+		jimmctl search-applications postgresql_client
+		jimmctl search-applications postgresql_client --role provider --exposed
+		jimmctl search-applications --endpoint database --format yaml
+`
+
+// NewSearchApplicationsCommand returns a command to search for
+// applications by endpoint interface or name.
+func NewSearchApplicationsCommand() cmd.Command {
+	cmd := &searchApplicationsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// searchApplicationsCommand searches for applications by endpoint
+// interface or name.
+type searchApplicationsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.SearchApplicationsRequest
+}
+
+// Info implements Command.Info.
+func (c *searchApplicationsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "search-applications",
+		Args:    "[<interface>]",
+		Purpose: "Search all models for applications by endpoint interface or name.",
+		Doc:     searchApplicationsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *searchApplicationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatApplicationSearchTabular,
+	})
+	f.StringVar(&c.req.Endpoint, "endpoint", "", "only find applications with an endpoint of this name")
+	f.StringVar(&c.req.Role, "role", "", "only find endpoints with this role (provider, requirer or peer)")
+	f.BoolVar(&c.req.ExposedOnly, "exposed", false, "only find applications that are exposed or offered")
+}
+
+// Init implements the cmd.Command interface.
+func (c *searchApplicationsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.req.Interface, args = args[0], args[1:]
+	}
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	if c.req.Interface == "" && c.req.Endpoint == "" {
+		return errors.E("interface or endpoint must be specified")
+	}
+	switch c.req.Role {
+	case "", "provider", "requirer", "peer":
+	default:
+		return errors.E("invalid role " + c.req.Role)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *searchApplicationsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.SearchApplications(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatApplicationSearchTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.SearchApplicationsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Controller", "Model", "Application", "Endpoint", "Role", "Interface", "Exposed", "Offers")
+	for _, r := range resp.Results {
+		table.AddRow(
+			r.Controller,
+			r.Owner+"/"+r.ModelName,
+			r.Application,
+			r.Endpoint,
+			r.Role,
+			r.Interface,
+			r.Exposed,
+			strings.Join(r.OfferURLs, ","),
+		)
+	}
+	fmt.Fprint(writer, table)
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type searchApplicationsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&searchApplicationsSuite{})
+
+func (s *searchApplicationsSuite) TestSearchApplications(c *gc.C) {
+	ctx := context.Background()
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/charlie@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+	mt := s.AddModel(c, names.NewUserTag("charlie@canonical.com"), "model-1", names.NewCloudTag(jimmtest.TestCloudName), jimmtest.TestCloudRegionName, cct)
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err := s.JIMM.Database.GetModel(ctx, &m)
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.SetApplicationEndpoints(ctx, []dbmodel.ApplicationEndpoint{{
+		ModelID:         m.ID,
+		ApplicationName: "db",
+		Name:            "database",
+		Role:            "provider",
+		Interface:       "postgresql_client",
+	}})
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         m.ID,
+		ApplicationName: "db",
+		CharmURL:        "ch:amd64/jammy/postgresql-345",
+		Exposed:         true,
+	})
+	c.Assert(err, gc.IsNil)
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewSearchApplicationsCommandForTesting(s.ClientStore(), bClient), "postgresql_client", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, `results:
+- controller: controller-1
+  model-uuid: `+mt.Id()+`
+  model-name: model-1
+  owner: charlie@canonical.com
+  application: db
+  charm-url: ch:amd64/jammy/postgresql-345
+  exposed: true
+  endpoint: database
+  role: provider
+  interface: postgresql_client
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewSearchApplicationsCommandForTesting(s.ClientStore(), bClient), "--endpoint", "database", "--exposed")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `(?s)Controller +Model +Application +Endpoint +Role +Interface +Exposed +Offers\s*\ncontroller-1 +charlie@canonical.com/model-1 +db +database +provider +postgresql_client +true\s*`)
+
+	// bob cannot read the model.
+	bClient = s.SetupCLIAccess(c, "bob")
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewSearchApplicationsCommandForTesting(s.ClientStore(), bClient), "postgresql_client", "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Equals, "results: []\n")
+}
+
+func (s *searchApplicationsSuite) TestSearchApplicationsInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSearchApplicationsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `interface or endpoint must be specified`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSearchApplicationsCommandForTesting(s.ClientStore(), bClient), "a", "b")
+	c.Assert(err, gc.ErrorMatches, `too many args`)
+	_, err = cmdtesting.RunCommand(c, cmd.NewSearchApplicationsCommandForTesting(s.ClientStore(), bClient), "pgsql", "--role", "owner")
+	c.Assert(err, gc.ErrorMatches, `invalid role owner`)
+}
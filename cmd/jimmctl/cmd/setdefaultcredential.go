@@ -0,0 +1,112 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+var setDefaultCredentialDoc = `
+	set-default-credential sets the credential used for your models on a
+	cloud when a model is created without specifying a credential and you
+	have more than one credential for the cloud. Without a default the
+	credential last used in the model's region is chosen. Use --unset to
+	remove the default.
+
+	Example:
+		jimmctl set-default-credential <cloud> <credential>
+		jimmctl set-default-credential <cloud> --unset
+`
+
+// NewSetDefaultCredentialCommand returns a command used to set the
+// default cloud credential for a cloud.
+func NewSetDefaultCredentialCommand() cmd.Command {
+	cmd := &setDefaultCredentialCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// setDefaultCredentialCommand sets the default cloud credential for a
+// cloud.
+type setDefaultCredentialCommand struct {
+	modelcmd.ControllerCommandBase
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	cloud      string
+	credential string
+	unset      bool
+}
+
+// Info implements Command.Info.
+func (c *setDefaultCredentialCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-default-credential",
+		Args:    "<cloud> [<credential>]",
+		Purpose: "Sets the default credential for a cloud.",
+		Doc:     setDefaultCredentialDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setDefaultCredentialCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.BoolVar(&c.unset, "unset", false, "remove the default credential for the cloud")
+}
+
+// Init implements the cmd.Command interface.
+func (c *setDefaultCredentialCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.E("missing cloud name")
+	}
+	c.cloud, args = args[0], args[1:]
+	if !names.IsValidCloud(c.cloud) {
+		return errors.E("invalid cloud name")
+	}
+	if len(args) > 0 {
+		c.credential, args = args[0], args[1:]
+	}
+	if len(args) > 0 {
+		return errors.E("unknown arguments")
+	}
+	if c.unset == (c.credential != "") {
+		return errors.E("specify either a credential name or --unset")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *setDefaultCredentialCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	err = client.SetDefaultCloudCredential(&apiparams.SetDefaultCloudCredentialRequest{
+		CloudTag:       names.NewCloudTag(c.cloud).String(),
+		CredentialName: c.credential,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
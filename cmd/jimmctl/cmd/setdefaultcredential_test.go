@@ -0,0 +1,74 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type setDefaultCredentialSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&setDefaultCredentialSuite{})
+
+func (s *setDefaultCredentialSuite) TestSetDefaultCredential(c *gc.C) {
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/bob@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetDefaultCredentialCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, "cred")
+	c.Assert(err, gc.IsNil)
+
+	def := dbmodel.DefaultCloudCredential{
+		IdentityName: "bob@canonical.com",
+		CloudName:    jimmtest.TestCloudName,
+	}
+	err = s.JIMM.Database.GetDefaultCloudCredential(context.Background(), &def)
+	c.Assert(err, gc.IsNil)
+	c.Check(def.CloudCredential.Name, gc.Equals, "cred")
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewSetDefaultCredentialCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, "--unset")
+	c.Assert(err, gc.IsNil)
+	err = s.JIMM.Database.GetDefaultCloudCredential(context.Background(), &def)
+	c.Check(errors.ErrorCode(err), gc.Equals, errors.CodeNotFound)
+}
+
+func (s *setDefaultCredentialSuite) TestSetDefaultCredentialNotFound(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSetDefaultCredentialCommandForTesting(s.ClientStore(), bClient), jimmtest.TestCloudName, "no-such-cred")
+	c.Assert(err, gc.ErrorMatches, `.*not found.*`)
+}
+
+func (s *setDefaultCredentialSuite) TestSetDefaultCredentialInvalidArgs(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "bob")
+	for _, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		expectError: "missing cloud name",
+	}, {
+		args:        []string{jimmtest.TestCloudName},
+		expectError: "specify either a credential name or --unset",
+	}, {
+		args:        []string{jimmtest.TestCloudName, "cred", "--unset"},
+		expectError: "specify either a credential name or --unset",
+	}, {
+		args:        []string{jimmtest.TestCloudName, "cred", "extra"},
+		expectError: "unknown arguments",
+	}} {
+		_, err := cmdtesting.RunCommand(c, cmd.NewSetDefaultCredentialCommandForTesting(s.ClientStore(), bClient), test.args...)
+		c.Check(err, gc.ErrorMatches, test.expectError)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const showOperationDoc = `
+	show-operation shows the progress of a long-running operation, such
+	as an asynchronous cloud credential rotation: its status, percentage
+	done, step log and, once it has finished, its result or error. Users
+	can show the operations they started, JIMM administrators can show
+	any operation.
+
+	Example:
+		jimmctl show-operation 6f5a7c1e-3c2b-4a1d-9e8f-0b1c2d3e4f5a
+		jimmctl show-operation 6f5a7c1e-3c2b-4a1d-9e8f-0b1c2d3e4f5a --format json
+`
+
+// NewShowOperationCommand returns a command to show the progress of an
+// operation.
+func NewShowOperationCommand() cmd.Command {
+	cmd := &showOperationCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// showOperationCommand shows the progress of an operation.
+type showOperationCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	id string
+}
+
+// Info implements Command.Info.
+func (c *showOperationCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "show-operation",
+		Args:    "<operation id>",
+		Purpose: "Show the progress of a long-running operation.",
+		Doc:     showOperationDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showOperationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *showOperationCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("operation id not specified")
+	}
+	c.id = args[0]
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *showOperationCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GetOperation(&apiparams.GetOperationRequest{
+		ID: c.id,
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
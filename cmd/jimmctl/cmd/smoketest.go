@@ -0,0 +1,305 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	cloudapi "github.com/juju/juju/api/client/cloud"
+	"github.com/juju/juju/api/client/modelmanager"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const smokeTestPollInterval = time.Second
+
+var smokeTestCommandDoc = `
+	smoke-test command verifies a JIMM deployment by exercising the
+	critical path end to end. It logs in, lists the clouds, creates a
+	model in the cloud region of the given controller, checks that the
+	model was placed on that controller, waits for the model to become
+	available and then destroys it. If JIMM places the model on another
+	controller hosting the same cloud region the smoke test fails.
+
+	The time taken by each step is reported. The command exits with an
+	error if any step fails, the model is destroyed even if it never
+	becomes available. The command must be run by a JIMM administrator.
+
+	Example:
+		jimmctl smoke-test <controller name>
+		jimmctl smoke-test <controller name> --credential <credential name> --timeout 10m
+		jimmctl smoke-test <controller name> --format json
+`
+
+// NewSmokeTestCommand returns a command to run a smoke test against JIMM.
+func NewSmokeTestCommand() cmd.Command {
+	cmd := &smokeTestCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// smokeTestCommand runs a smoke test against JIMM.
+type smokeTestCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	controller string
+	credential string
+	timeout    time.Duration
+}
+
+// smokeTestResult holds the result of a smoke test.
+type smokeTestResult struct {
+	Success    bool            `json:"success" yaml:"success"`
+	Controller string          `json:"controller" yaml:"controller"`
+	Model      string          `json:"model,omitempty" yaml:"model,omitempty"`
+	DurationMS int64           `json:"duration-ms" yaml:"duration-ms"`
+	Steps      []smokeTestStep `json:"steps" yaml:"steps"`
+}
+
+// smokeTestStep holds the result of a single step of a smoke test.
+type smokeTestStep struct {
+	Name       string `json:"name" yaml:"name"`
+	DurationMS int64  `json:"duration-ms" yaml:"duration-ms"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func (c *smokeTestCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "smoke-test",
+		Args:    "<controller name>",
+		Purpose: "Verify a JIMM deployment end to end.",
+		Doc:     smokeTestCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *smokeTestCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSmokeTestTabular,
+	})
+	f.StringVar(&c.credential, "credential", "", "name of the cloud credential used to create the model")
+	f.DurationVar(&c.timeout, "timeout", 5*time.Minute, "maximum time to wait for the model to become available")
+}
+
+// Init implements the cmd.Command interface.
+func (c *smokeTestCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("controller name not specified")
+	}
+	c.controller, args = args[0], args[1:]
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *smokeTestCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	result := smokeTestResult{
+		Controller: c.controller,
+	}
+	start := time.Now()
+	// step runs a single step of the smoke test, recording its duration
+	// and any error.
+	step := func(name string, f func() error) bool {
+		stepStart := time.Now()
+		err := f()
+		s := smokeTestStep{
+			Name:       name,
+			DurationMS: time.Since(stepStart).Milliseconds(),
+		}
+		if err != nil {
+			s.Error = err.Error()
+		}
+		result.Steps = append(result.Steps, s)
+		return err == nil
+	}
+
+	var apiCaller jujuapi.Connection
+	var ctl *apiparams.ControllerInfo
+	var mt names.ModelTag
+	ok := step("login", func() error {
+		apiCaller, err = c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+		return err
+	}) && step("list-clouds", func() error {
+		_, err := cloudapi.NewClient(apiCaller).Clouds()
+		return err
+	}) && step("find-controller", func() error {
+		ctl, err = findController(api.NewClient(apiCaller), c.controller)
+		return err
+	}) && step("create-model", func() error {
+		mt, err = c.createModel(apiCaller, ctl)
+		if err == nil {
+			result.Model = mt.Id()
+		}
+		return err
+	}) && step("check-controller", func() error {
+		return checkModelController(apiCaller, mt, ctl)
+	})
+	ok = ok && step("wait-for-model", func() error {
+		return waitForModelAvailable(modelmanager.NewClient(apiCaller), mt, c.timeout)
+	})
+	if mt.Id() != "" {
+		// Always clean up the model, even if it never became available.
+		ok = step("destroy-model", func() error {
+			destroyStorage := true
+			return modelmanager.NewClient(apiCaller).DestroyModel(mt, &destroyStorage, nil, nil, nil)
+		}) && ok
+	}
+	if apiCaller != nil {
+		apiCaller.Close()
+	}
+	result.Success = ok
+	result.DurationMS = time.Since(start).Milliseconds()
+
+	if err := c.out.Write(ctxt, result); err != nil {
+		return errors.E(err)
+	}
+	if !ok {
+		return errors.E("smoke test failed")
+	}
+	return nil
+}
+
+// createModel creates a new model, owned by the logged in user, in the
+// cloud region of the given controller.
+func (c *smokeTestCommand) createModel(apiCaller jujuapi.Connection, ctl *apiparams.ControllerInfo) (names.ModelTag, error) {
+	ct, err := names.ParseCloudTag(ctl.CloudTag)
+	if err != nil {
+		return names.ModelTag{}, errors.E(err, fmt.Sprintf("controller %s has an invalid cloud", ctl.Name))
+	}
+	owner := apiCaller.AuthTag().Id()
+	var credential names.CloudCredentialTag
+	if c.credential != "" {
+		credential = names.NewCloudCredentialTag(fmt.Sprintf("%s/%s/%s", ct.Id(), owner, c.credential))
+	}
+	name := "smoke-test-" + uuid.NewString()[:8]
+	mi, err := modelmanager.NewClient(apiCaller).CreateModel(name, owner, ct.Id(), ctl.CloudRegion, credential, nil)
+	if err != nil {
+		return names.ModelTag{}, err
+	}
+	return names.NewModelTag(mi.UUID), nil
+}
+
+// checkModelController checks that the given model was placed on the
+// given controller. JIMM chooses the controller a model is created on
+// from those hosting the cloud region, so a model created for the smoke
+// test may land on another controller, in which case the smoke test
+// would not have exercised the named controller.
+func checkModelController(apiCaller jujuapi.Connection, mt names.ModelTag, ctl *apiparams.ControllerInfo) error {
+	// JIMM reports its own UUID as the controller of every model
+	// unless masking is disabled.
+	if err := api.NewClient(apiCaller).DisableControllerUUIDMasking(); err != nil {
+		return err
+	}
+	results, err := modelmanager.NewClient(apiCaller).ModelInfo([]names.ModelTag{mt})
+	if err != nil {
+		return err
+	}
+	if len(results) != 1 {
+		return errors.E(fmt.Sprintf("expected 1 result, got %d", len(results)))
+	}
+	if results[0].Error != nil {
+		return results[0].Error
+	}
+	if got := results[0].Result.ControllerUUID; got != ctl.UUID {
+		return errors.E(fmt.Sprintf("model created on controller %s, not on %s (%s)", got, ctl.Name, ctl.UUID))
+	}
+	return nil
+}
+
+// findController returns the controller with the given name.
+func findController(client *api.Client, name string) (*apiparams.ControllerInfo, error) {
+	controllers, err := client.ListControllers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range controllers {
+		if controllers[i].Name == name {
+			return &controllers[i], nil
+		}
+	}
+	return nil, errors.E(fmt.Sprintf("controller %s not found", name))
+}
+
+// waitForModelAvailable waits until the given model has a status of
+// available, or until the timeout expires.
+func waitForModelAvailable(client *modelmanager.Client, mt names.ModelTag, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		results, err := client.ModelInfo([]names.ModelTag{mt})
+		if err != nil {
+			return err
+		}
+		if len(results) != 1 {
+			return errors.E(fmt.Sprintf("expected 1 result, got %d", len(results)))
+		}
+		if results[0].Error != nil {
+			return results[0].Error
+		}
+		s := results[0].Result.Status.Status
+		if s == status.Available {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.E(fmt.Sprintf("model not available after %s, status %q", timeout, s))
+		}
+		time.Sleep(smokeTestPollInterval)
+	}
+}
+
+func formatSmokeTestTabular(writer io.Writer, value interface{}) error {
+	result, ok := value.(smokeTestResult)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", result, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Step", "Duration", "Result")
+	for _, s := range result.Steps {
+		res := "ok"
+		if s.Error != "" {
+			res = s.Error
+		}
+		table.AddRow(s.Name, time.Duration(s.DurationMS)*time.Millisecond, res)
+	}
+	fmt.Fprintln(writer, table)
+
+	res := "PASSED"
+	if !result.Success {
+		res = "FAILED"
+	}
+	fmt.Fprintf(writer, "\nSmoke test %s in %s\n", res, time.Duration(result.DurationMS)*time.Millisecond)
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+type smokeTestSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&smokeTestSuite{})
+
+type smokeTestOutput struct {
+	Success    bool   `json:"success"`
+	Controller string `json:"controller"`
+	Model      string `json:"model"`
+	Steps      []struct {
+		Name  string `json:"name"`
+		Error string `json:"error"`
+	} `json:"steps"`
+}
+
+func (s *smokeTestSuite) TestSmokeTest(c *gc.C) {
+	s.AddController(c, "controller-1", s.APIInfo(c))
+	cct := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/alice@canonical.com/cred")
+	s.UpdateCloudCredential(c, cct, jujuparams.CloudCredential{AuthType: "empty"})
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewSmokeTestCommandForTesting(s.ClientStore(), bClient), "controller-1", "--credential", "cred", "--format", "json")
+	c.Assert(err, gc.IsNil)
+
+	var out smokeTestOutput
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(cmdContext)), &out)
+	c.Assert(err, gc.IsNil)
+	c.Check(out.Success, gc.Equals, true)
+	c.Check(out.Controller, gc.Equals, "controller-1")
+	var steps []string
+	for _, step := range out.Steps {
+		c.Check(step.Error, gc.Equals, "", gc.Commentf("step %s", step.Name))
+		steps = append(steps, step.Name)
+	}
+	c.Check(steps, gc.DeepEquals, []string{"login", "list-clouds", "find-controller", "create-model", "check-controller", "wait-for-model", "destroy-model"})
+
+	var m dbmodel.Model
+	m.SetTag(names.NewModelTag(out.Model))
+	err = s.JIMM.Database.GetModel(context.Background(), &m)
+	c.Assert(err, gc.IsNil)
+	c.Check(m.Life, gc.Equals, "dying")
+}
+
+func (s *smokeTestSuite) TestSmokeTestUnknownController(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewSmokeTestCommandForTesting(s.ClientStore(), bClient), "no-such-controller", "--format", "json")
+	c.Assert(err, gc.ErrorMatches, "smoke test failed")
+
+	var out smokeTestOutput
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(cmdContext)), &out)
+	c.Assert(err, gc.IsNil)
+	c.Check(out.Success, gc.Equals, false)
+	c.Assert(out.Steps, gc.HasLen, 3)
+	c.Check(out.Steps[2].Name, gc.Equals, "find-controller")
+	c.Check(out.Steps[2].Error, gc.Equals, "controller no-such-controller not found")
+}
+
+func (s *smokeTestSuite) TestSmokeTestMissingController(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSmokeTestCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, "controller name not specified")
+}
@@ -0,0 +1,267 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gosuri/uitable"
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const syncGroupsDoc = `
+	sync-groups synchronises the membership of JIMM groups with the groups
+	in the external directory JIMM is configured to use, creating any
+	groups that are missing. Only the groups named by JIMM's group mapping
+	rules are changed. If JIMM is configured with a credential group, the
+	models of users who are no longer members of any synchronised group
+	are rebound to credentials shared with that group, see
+	rebind-model-credentials. The changes made are reported. With
+	--dry-run the changes that would be made are reported without making
+	them.
+
+	A synchronisation that would remove more group memberships than JIMM
+	is configured to allow is refused without making any changes, in case
+	the directory is misconfigured. Check the changes with --dry-run and
+	use --force to make them.
+
+	Example:
+		jimmctl sync-groups --dry-run
+		jimmctl sync-groups --force
+		jimmctl sync-groups --format yaml
+`
+
+// NewSyncGroupsCommand returns a command to synchronise group memberships
+// from the external directory.
+func NewSyncGroupsCommand() cmd.Command {
+	cmd := &syncGroupsCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// syncGroupsCommand synchronises group memberships from the external
+// directory.
+type syncGroupsCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	dryRun bool
+	force  bool
+}
+
+// Info implements Command.Info.
+func (c *syncGroupsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "sync-groups",
+		Purpose: "Synchronise group memberships from the external directory.",
+		Doc:     syncGroupsDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *syncGroupsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatSyncGroupsTabular,
+	})
+	f.BoolVar(&c.dryRun, "dry-run", false, "report the changes without making them")
+	f.BoolVar(&c.force, "force", false, "make the changes even if they remove more group memberships than allowed")
+}
+
+// Init implements the cmd.Command interface.
+func (c *syncGroupsCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *syncGroupsCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.SyncGroups(&apiparams.SyncGroupsRequest{DryRun: c.dryRun, Force: c.force})
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatSyncGroupsTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.SyncGroupsResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Group", "Member", "Action")
+	for _, group := range resp.CreatedGroups {
+		table.AddRow(group, "", "create")
+	}
+	for _, change := range resp.Changes {
+		table.AddRow(change.Group, change.Member, change.Action)
+	}
+	fmt.Fprint(writer, table)
+	if len(resp.Rebinds) > 0 {
+		rebinds := uitable.New()
+		rebinds.MaxColWidth = 80
+		rebinds.Wrap = true
+		rebinds.AddRow("Model", "From", "To", "Error")
+		for _, r := range resp.Rebinds {
+			rebinds.AddRow(r.ModelTag, r.FromCredential, r.ToCredential, r.Error)
+		}
+		fmt.Fprintf(writer, "\n\n%s", rebinds)
+	}
+	for _, e := range resp.Errors {
+		fmt.Fprintf(writer, "\nerror: %s", e)
+	}
+	if resp.DryRun {
+		fmt.Fprint(writer, "\ndry run, no changes made")
+	}
+	return nil
+}
+
+const groupSyncStatusDoc = `
+	group-sync-status reports the outcome of the most recent
+	synchronisation of group memberships from the external directory,
+	whether it was run periodically by JIMM or with sync-groups. Dry runs
+	are not reported.
+
+	Example:
+		jimmctl group-sync-status
+		jimmctl group-sync-status --format yaml
+`
+
+// NewGroupSyncStatusCommand returns a command to report the outcome of
+// the most recent group synchronisation.
+func NewGroupSyncStatusCommand() cmd.Command {
+	cmd := &groupSyncStatusCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// groupSyncStatusCommand reports the outcome of the most recent group
+// synchronisation.
+type groupSyncStatusCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+}
+
+// Info implements Command.Info.
+func (c *groupSyncStatusCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "group-sync-status",
+		Purpose: "Report the outcome of the most recent group synchronisation.",
+		Doc:     groupSyncStatusDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *groupSyncStatusCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatGroupSyncStatusTabular,
+	})
+}
+
+// Init implements the cmd.Command interface.
+func (c *groupSyncStatusCommand) Init(args []string) error {
+	if len(args) > 0 {
+		return errors.E("too many args")
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *groupSyncStatusCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.GroupSyncStatus()
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+func formatGroupSyncStatusTabular(writer io.Writer, value interface{}) error {
+	resp, ok := value.(*apiparams.GroupSyncStatusResponse)
+	if !ok {
+		return errors.E(fmt.Sprintf("expected value of type %T, got %T", resp, value))
+	}
+
+	table := uitable.New()
+	table.MaxColWidth = 80
+	table.Wrap = true
+
+	table.AddRow("Directory", "Time", "Created", "Added", "Removed", "Error")
+	table.AddRow(
+		resp.Directory,
+		resp.Time.Format(time.RFC3339),
+		strings.Join(resp.CreatedGroups, ","),
+		resp.Added,
+		resp.Removed,
+		resp.Error,
+	)
+	fmt.Fprint(writer, table)
+	for _, e := range resp.Errors {
+		fmt.Fprintf(writer, "\nerror: %s", e)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Canonical.
+
+package cmd_test
+
+import (
+	"context"
+	"slices"
+
+	"github.com/juju/cmd/v3/cmdtesting"
+	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/cmd/jimmctl/cmd"
+	"github.com/canonical/jimm/v3/internal/cmdtest"
+	"github.com/canonical/jimm/v3/internal/groupsync"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+type staticDirectory map[string][]string
+
+func (d staticDirectory) Groups(context.Context) (map[string][]string, error) {
+	return d, nil
+}
+
+func (d staticDirectory) UserActive(_ context.Context, name string) (bool, error) {
+	for _, members := range d {
+		if slices.Contains(members, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type syncGroupsSuite struct {
+	cmdtest.JimmCmdSuite
+}
+
+var _ = gc.Suite(&syncGroupsSuite{})
+
+func (s *syncGroupsSuite) TestSyncGroups(c *gc.C) {
+	s.JIMM.GroupSync = jimm.GroupSyncConfig{
+		Name: "test-directory",
+		Directory: staticDirectory{
+			"Engineering": {"bob@canonical.com"},
+		},
+		Mappings: []groupsync.Mapping{{Source: "Engineering", Target: "engineering"}},
+	}
+
+	// alice is superuser
+	bClient := s.SetupCLIAccess(c, "alice")
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient), "--dry-run")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Group +Member +Action\s*
+engineering +create\s*
+engineering +bob@canonical.com +add\s*
+dry run, no changes made`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewGroupSyncStatusCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `.*not found.*`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient), "--format", "yaml")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `time: .*
+created-groups:
+- engineering
+changes:
+- group: engineering
+  member: bob@canonical.com
+  action: add
+`)
+
+	cmdContext, err = cmdtesting.RunCommand(c, cmd.NewGroupSyncStatusCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Directory +Time +Created +Added +Removed +Error\s*
+test-directory +\S+ +engineering +1 +0\s*
+`)
+}
+
+func (s *syncGroupsSuite) TestSyncGroupsForce(c *gc.C) {
+	s.JIMM.GroupSync = jimm.GroupSyncConfig{
+		Name: "test-directory",
+		Directory: staticDirectory{
+			"Engineering": {"bob@canonical.com", "carol@canonical.com"},
+		},
+		Mappings:    []groupsync.Mapping{{Source: "Engineering", Target: "engineering"}},
+		MaxRemovals: 1,
+	}
+
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.IsNil)
+
+	// The directory loses both members of Engineering.
+	s.JIMM.GroupSync.Directory = staticDirectory{
+		"Engineering": {},
+	}
+	_, err = cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `synchronisation would remove 2 group memberships, more than the maximum of 1.*`)
+
+	cmdContext, err := cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient), "--force")
+	c.Assert(err, gc.IsNil)
+	c.Assert(cmdtesting.Stdout(cmdContext), gc.Matches, `Group +Member +Action\s*
+engineering +bob@canonical.com +remove\s*
+engineering +carol@canonical.com +remove\s*`)
+}
+
+func (s *syncGroupsSuite) TestSyncGroupsNotConfigured(c *gc.C) {
+	bClient := s.SetupCLIAccess(c, "alice")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `group synchronisation not configured.*`)
+}
+
+func (s *syncGroupsSuite) TestSyncGroupsUnauthorized(c *gc.C) {
+	// bob is not superuser
+	bClient := s.SetupCLIAccess(c, "bob")
+	_, err := cmdtesting.RunCommand(c, cmd.NewSyncGroupsCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = cmdtesting.RunCommand(c, cmd.NewGroupSyncStatusCommandForTesting(s.ClientStore(), bClient))
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
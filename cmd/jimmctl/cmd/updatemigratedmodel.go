@@ -8,7 +8,6 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/jujuclient"
-	"github.com/juju/names/v5"
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/pkg/api"
@@ -21,6 +20,7 @@ const updateMigratedModelCommandDoc = `
 
 	Example:
 		jimmctl update-migrated-model <controller name> <model-uuid>
+		jimmctl update-migrated-model <controller name> alice@canonical.com/mymodel
 `
 
 // NewUpdateMigratedModelCommand returns a command to update the controller
@@ -65,11 +65,9 @@ func (c *updateMigratedModelCommand) Init(args []string) error {
 	}
 
 	c.req.TargetController = args[0]
-	if !names.IsValidModel(args[1]) {
-		return errors.E("invalid model uuid")
-	}
-	c.req.ModelTag = names.NewModelTag(args[1]).String()
-	return nil
+	var err error
+	c.req.ModelTag, err = parseModelRef(args[1])
+	return err
 }
 
 // Run implements Command.Run.
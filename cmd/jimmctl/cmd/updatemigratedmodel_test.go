@@ -75,7 +75,7 @@ func (s *updateMigratedModelSuite) TestUpdateMigratedModelNoModelUUID(c *gc.C) {
 func (s *updateMigratedModelSuite) TestUpdateMigratedModelInvalidModelUUID(c *gc.C) {
 	bClient := s.SetupCLIAccess(c, "bob")
 	_, err := cmdtesting.RunCommand(c, cmd.NewUpdateMigratedModelCommandForTesting(s.ClientStore(), bClient), "controller-id", "not-a-uuid")
-	c.Assert(err, gc.ErrorMatches, `invalid model uuid`)
+	c.Assert(err, gc.ErrorMatches, `not-a-uuid is not a valid model uuid or <owner>/<name> path`)
 }
 
 func (s *updateMigratedModelSuite) TestUpdateMigratedModelTooManyArgs(c *gc.C) {
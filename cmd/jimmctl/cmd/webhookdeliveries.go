@@ -0,0 +1,105 @@
+// Copyright 2024 Canonical.
+
+package cmd
+
+import (
+	"github.com/juju/cmd/v3"
+	"github.com/juju/gnuflag"
+	jujuapi "github.com/juju/juju/api"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/pkg/api"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const webhookDeliveriesDoc = `
+	webhook-deliveries lists the signed deliveries to a webhook, newest
+	first, with the nonce, timestamp and signature each was sent with.
+	Deliveries are only recorded once the webhook has a signing secret,
+	see rotate-webhook-secret.
+
+	Example:
+		jimmctl webhook-deliveries access-requests
+		jimmctl webhook-deliveries access-requests --limit 10 --format json
+`
+
+// NewWebhookDeliveriesCommand returns a command to list the signed
+// deliveries to a webhook.
+func NewWebhookDeliveriesCommand() cmd.Command {
+	cmd := &webhookDeliveriesCommand{
+		store: jujuclient.NewFileClientStore(),
+	}
+
+	return modelcmd.WrapBase(cmd)
+}
+
+// webhookDeliveriesCommand lists the signed deliveries to a webhook.
+type webhookDeliveriesCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+
+	store    jujuclient.ClientStore
+	dialOpts *jujuapi.DialOpts
+
+	req apiparams.ListWebhookDeliveriesRequest
+}
+
+// Info implements Command.Info.
+func (c *webhookDeliveriesCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "webhook-deliveries",
+		Args:    "<webhook>",
+		Purpose: "List the signed deliveries to a webhook.",
+		Doc:     webhookDeliveriesDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *webhookDeliveriesCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+	f.IntVar(&c.req.Limit, "limit", 50, "the maximum number of deliveries to list")
+}
+
+// Init implements the cmd.Command interface.
+func (c *webhookDeliveriesCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.E("webhook not specified")
+	}
+	if len(args) > 1 {
+		return errors.E("too many args")
+	}
+	c.req.Webhook = args[0]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *webhookDeliveriesCommand) Run(ctxt *cmd.Context) error {
+	currentController, err := c.store.CurrentController()
+	if err != nil {
+		return errors.E(err, "could not determine controller")
+	}
+
+	apiCaller, err := c.NewAPIRootWithDialOpts(c.store, currentController, "", c.dialOpts)
+	if err != nil {
+		return err
+	}
+
+	client := api.NewClient(apiCaller)
+	resp, err := client.ListWebhookDeliveries(&c.req)
+	if err != nil {
+		return errors.E(err)
+	}
+
+	err = c.out.Write(ctxt, resp)
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -28,16 +28,64 @@ func NewSuperCommand() *jujucmd.SuperCommand {
 	jimmcmd.Register(cmd.NewListAuditEventsCommand())
 	jimmcmd.Register(cmd.NewListControllersCommand())
 	jimmcmd.Register(cmd.NewModelStatusCommand())
+	jimmcmd.Register(cmd.NewExportBundleCommand())
+	jimmcmd.Register(cmd.NewModelsStatusCommand())
 	jimmcmd.Register(cmd.NewRemoveControllerCommand())
 	jimmcmd.Register(cmd.NewRevokeAuditLogAccessCommand())
 	jimmcmd.Register(cmd.NewSetControllerDeprecatedCommand())
+	jimmcmd.Register(cmd.NewSetDefaultCredentialCommand())
 	jimmcmd.Register(cmd.NewUpdateMigratedModelCommand())
 	jimmcmd.Register(cmd.NewAddCloudToControllerCommand())
 	jimmcmd.Register(cmd.NewRemoveCloudFromControllerCommand())
 	jimmcmd.Register(cmd.NewAuthCommand())
 	jimmcmd.Register(cmd.NewCrossModelQueryCommand())
+	jimmcmd.Register(cmd.NewFindOffersCommand())
+	jimmcmd.Register(cmd.NewSearchApplicationsCommand())
 	jimmcmd.Register(cmd.NewPurgeLogsCommand())
+	jimmcmd.Register(cmd.NewResyncModelAccessCommand())
+	jimmcmd.Register(cmd.NewRemapCloudRegionCommand())
+	jimmcmd.Register(cmd.NewRotateWebhookSecretCommand())
+	jimmcmd.Register(cmd.NewWebhookDeliveriesCommand())
+	jimmcmd.Register(cmd.NewExportModelCommand())
+	jimmcmd.Register(cmd.NewPrepareModelImportCommand())
+	jimmcmd.Register(cmd.NewMigrateModelExternalCommand())
+	jimmcmd.Register(cmd.NewCompleteModelImportCommand())
+	jimmcmd.Register(cmd.NewRepairDenormalizedFieldsCommand())
+	jimmcmd.Register(cmd.NewProvisionControllerServiceUserCommand())
+	jimmcmd.Register(cmd.NewListOperationsCommand())
+	jimmcmd.Register(cmd.NewShowOperationCommand())
+	jimmcmd.Register(cmd.NewAuditControllerAccessCommand())
+	jimmcmd.Register(cmd.NewPingControllersCommand())
+	jimmcmd.Register(cmd.NewListOutdatedCharmsCommand())
+	jimmcmd.Register(cmd.NewControllerCredentialsCommand())
+	jimmcmd.Register(cmd.NewControllerCertificatesCommand())
+	jimmcmd.Register(cmd.NewIdleModelsCommand())
+	jimmcmd.Register(cmd.NewPendingModelDestroysCommand())
+	jimmcmd.Register(cmd.NewCancelModelDestroyCommand())
+	jimmcmd.Register(cmd.NewModelDependenciesCommand())
+	jimmcmd.Register(cmd.NewAddModelDependencyCommand())
+	jimmcmd.Register(cmd.NewRemoveModelDependencyCommand())
+	jimmcmd.Register(cmd.NewDeletedModelsCommand())
+	jimmcmd.Register(cmd.NewRestoreModelCommand())
+	jimmcmd.Register(cmd.NewNormalizeIdentitiesCommand())
+	jimmcmd.Register(cmd.NewRotateControllerCredentialCommand())
+	jimmcmd.Register(cmd.NewSyncGroupsCommand())
+	jimmcmd.Register(cmd.NewSetCostCenterCommand())
+	jimmcmd.Register(cmd.NewUsageReportCommand())
+	jimmcmd.Register(cmd.NewAgentVersionReportCommand())
+	jimmcmd.Register(cmd.NewGroupSyncStatusCommand())
+	jimmcmd.Register(cmd.NewRebindModelCredentialsCommand())
+	jimmcmd.Register(cmd.NewRotateCloudCredentialsCommand())
+	jimmcmd.Register(cmd.NewExportModelAccessCommand())
+	jimmcmd.Register(cmd.NewApplyModelAccessCommand())
+	jimmcmd.Register(cmd.NewCredentialPropagationsCommand())
+	jimmcmd.Register(cmd.NewControllerCallCommand())
 	jimmcmd.Register(cmd.NewMigrateModelCommand())
+	jimmcmd.Register(cmd.NewRebalanceCommand())
+	jimmcmd.Register(cmd.NewSmokeTestCommand())
+	jimmcmd.Register(cmd.NewScheduledJobsCommand())
+	jimmcmd.Register(cmd.NewEnableScheduledJobCommand())
+	jimmcmd.Register(cmd.NewDisableScheduledJobCommand())
 	return jimmcmd
 }
 
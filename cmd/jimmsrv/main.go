@@ -4,20 +4,28 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	service "github.com/canonical/go-service"
+	jujuversion "github.com/juju/version/v2"
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
 	jimmsvc "github.com/canonical/jimm/v3/cmd/jimmsrv/service"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/groupsync"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
 	"github.com/canonical/jimm/v3/version"
 )
 
@@ -48,9 +56,29 @@ func start(ctx context.Context, s *service.Service) error {
 		}
 	}
 	// TODO(mhilton) access logs?
-	addr := os.Getenv("JIMM_LISTEN_ADDR")
-	if addr == "" {
-		addr = ":http-alt"
+	// The public API is always served, the admin and internal APIs are
+	// served by the public listener unless they have listeners of their
+	// own. When the admin API has a listener of its own the
+	// administrative methods of the JIMM facade are only served there.
+	listeners := []listener{{
+		params:  listenerParams("JIMM_"),
+		classes: []jimmsvc.Listener{jimmsvc.ListenerPublic},
+	}}
+	if listeners[0].params.Addr == "" {
+		listeners[0].params.Addr = ":http-alt"
+	}
+	for _, l := range []listener{{
+		params:  listenerParams("JIMM_ADMIN_"),
+		classes: []jimmsvc.Listener{jimmsvc.ListenerAdmin},
+	}, {
+		params:  listenerParams("JIMM_INTERNAL_"),
+		classes: []jimmsvc.Listener{jimmsvc.ListenerInternal},
+	}} {
+		if l.params.Addr == "" {
+			listeners[0].classes = append(listeners[0].classes, l.classes...)
+			continue
+		}
+		listeners = append(listeners, l)
 	}
 	macaroonExpiryDuration := 24 * time.Hour
 	durationString := os.Getenv("JIMM_MACAROON_EXPIRY_DURATION")
@@ -123,6 +151,37 @@ func start(ctx context.Context, s *service.Service) error {
 		insecureSecretStorage = true
 	}
 
+	disableDatabaseIndexBuild := false
+	if _, ok := os.LookupEnv("JIMM_DISABLE_DB_INDEX_BUILD"); ok {
+		disableDatabaseIndexBuild = true
+	}
+
+	notifyIdleModels := false
+	if _, ok := os.LookupEnv("JIMM_NOTIFY_IDLE_MODELS"); ok {
+		notifyIdleModels = true
+	}
+
+	watcherPerModelMetrics := false
+	if _, ok := os.LookupEnv("JIMM_WATCHER_PER_MODEL_METRICS"); ok {
+		watcherPerModelMetrics = true
+	}
+	var watcherModelReconcileInterval time.Duration
+	durationString = os.Getenv("JIMM_WATCHER_MODEL_RECONCILE_INTERVAL")
+	if durationString != "" {
+		watcherModelReconcileInterval, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse watcher model reconcile interval", zap.Error(err))
+			return err
+		}
+	}
+
+	charmPolicy := jimm.CharmPolicy{
+		AllowedPublishers: strings.Fields(os.Getenv("JIMM_CHARM_ALLOWED_PUBLISHERS")),
+	}
+	if _, ok := os.LookupEnv("JIMM_CHARM_BLOCK_UNVERIFIED_PUBLISHERS"); ok {
+		charmPolicy.BlockUnverifiedPublishers = true
+	}
+
 	secureSessionCookies := false
 	if _, ok := os.LookupEnv("JIMM_SECURE_SESSION_COOKIES"); ok {
 		secureSessionCookies = true
@@ -143,10 +202,504 @@ func start(ctx context.Context, s *service.Service) error {
 	}
 
 	corsAllowedOrigins := strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), " ")
+	websocketAllowedOrigins := strings.Fields(os.Getenv("JIMM_WEBSOCKET_ALLOWED_ORIGINS"))
+
+	var minClientVersion jujuversion.Number
+	if v := os.Getenv("JIMM_MIN_CLIENT_VERSION"); v != "" {
+		minClientVersion, err = jujuversion.Parse(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse minimum client version", zap.Error(err))
+			return err
+		}
+	}
+
+	facadeDeprecations, err := jimmRPC.ParseFacadeDeprecations(os.Getenv("JIMM_DEPRECATED_FACADES"))
+	if err != nil {
+		zapctx.Error(ctx, "failed to parse deprecated facades", zap.Error(err))
+		return err
+	}
+
+	var maxConcurrentRequests int
+	if v := os.Getenv("JIMM_MAX_CONCURRENT_REQUESTS"); v != "" {
+		maxConcurrentRequests, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse max concurrent requests", zap.Error(err))
+			return err
+		}
+	}
+	requestPriorityWeights, err := jimmRPC.ParseRequestWeights(os.Getenv("JIMM_REQUEST_PRIORITY_WEIGHTS"))
+	if err != nil {
+		zapctx.Error(ctx, "failed to parse request priority weights", zap.Error(err))
+		return err
+	}
+
+	// JIMM_BLOCKED_USER_AGENTS is comma separated as user agents
+	// usually contain spaces.
+	var blockedUserAgents []string
+	for _, ua := range strings.Split(os.Getenv("JIMM_BLOCKED_USER_AGENTS"), ",") {
+		if ua = strings.TrimSpace(ua); ua != "" {
+			blockedUserAgents = append(blockedUserAgents, ua)
+		}
+	}
+
+	// An empty JIMM_REDACTED_MODEL_FIELDS disables redaction, if it is
+	// unset the default fields are redacted.
+	var redactedModelFields []string
+	if v, ok := os.LookupEnv("JIMM_REDACTED_MODEL_FIELDS"); ok {
+		redactedModelFields = strings.Fields(v)
+	}
+	// Likewise for JIMM_REDACTED_MODEL_CONFIG_KEYS.
+	var redactedModelConfigKeys []string
+	if v, ok := os.LookupEnv("JIMM_REDACTED_MODEL_CONFIG_KEYS"); ok {
+		redactedModelConfigKeys = strings.Fields(v)
+	}
+
+	controllerMetricsPrefixes := strings.Fields(os.Getenv("JIMM_CONTROLLER_METRICS"))
+	controllerMetricLabels := strings.Fields(os.Getenv("JIMM_CONTROLLER_METRIC_LABELS"))
+	modelMetricLabels := strings.Fields(os.Getenv("JIMM_MODEL_METRIC_LABELS"))
+	var metricLabelMaxValues int
+	if v := os.Getenv("JIMM_METRIC_LABEL_MAX_VALUES"); v != "" {
+		metricLabelMaxValues, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse metric label max values", zap.Error(err))
+			return err
+		}
+	}
+
+	modelAccessResyncApply := false
+	if _, ok := os.LookupEnv("JIMM_MODEL_ACCESS_RESYNC_APPLY"); ok {
+		modelAccessResyncApply = true
+	}
+	controllerServiceUsers := false
+	if _, ok := os.LookupEnv("JIMM_CONTROLLER_SERVICE_USERS"); ok {
+		controllerServiceUsers = true
+	}
+	identityCaseFold := false
+	if _, ok := os.LookupEnv("JIMM_IDENTITY_CASE_FOLD"); ok {
+		identityCaseFold = true
+	}
+	// JIMM_IDENTITY_DOMAIN_ALIASES is a space separated list of
+	// alias=domain pairs, for example "external=canonical.com".
+	identityDomainAliases := make(map[string]string)
+	for _, v := range strings.Fields(os.Getenv("JIMM_IDENTITY_DOMAIN_ALIASES")) {
+		alias, domain, ok := strings.Cut(v, "=")
+		if !ok || alias == "" || domain == "" {
+			return errors.E("invalid identity domain alias " + v)
+		}
+		identityDomainAliases[alias] = domain
+	}
+
+	var fanOutSoftDeadline time.Duration
+	durationString = os.Getenv("JIMM_FANOUT_SOFT_DEADLINE")
+	if durationString != "" {
+		fanOutSoftDeadline, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse fan-out soft deadline", zap.Error(err))
+			return err
+		}
+	}
+
+	var modelSummariesDeadline time.Duration
+	durationString = os.Getenv("JIMM_MODEL_SUMMARIES_DEADLINE")
+	if durationString != "" {
+		modelSummariesDeadline, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model summaries deadline", zap.Error(err))
+			return err
+		}
+	}
+
+	var modelAccessResyncPeriod time.Duration
+	durationString = os.Getenv("JIMM_MODEL_ACCESS_RESYNC_PERIOD")
+	if durationString != "" {
+		modelAccessResyncPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model access re-sync period", zap.Error(err))
+			return err
+		}
+	}
+
+	var controllerAccessAuditPeriod time.Duration
+	durationString = os.Getenv("JIMM_CONTROLLER_ACCESS_AUDIT_PERIOD")
+	if durationString != "" {
+		controllerAccessAuditPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse controller access audit period", zap.Error(err))
+			return err
+		}
+	}
+
+	var cacheTTL time.Duration
+	durationString = os.Getenv("JIMM_CACHE_TTL")
+	if durationString != "" {
+		cacheTTL, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse cache TTL", zap.Error(err))
+			return err
+		}
+	}
+
+	var modelAccessCacheTTL time.Duration
+	durationString = os.Getenv("JIMM_MODEL_ACCESS_CACHE_TTL")
+	if durationString != "" {
+		modelAccessCacheTTL, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model access cache TTL", zap.Error(err))
+			return err
+		}
+	}
+
+	var confirmationPeriod time.Duration
+	durationString = os.Getenv("JIMM_CONFIRMATION_PERIOD")
+	if durationString != "" {
+		confirmationPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse confirmation period", zap.Error(err))
+			return err
+		}
+	}
+
+	var dbPool jimmsvc.DBPoolParams
+	if v := os.Getenv("JIMM_DB_MAX_OPEN_CONNS"); v != "" {
+		dbPool.MaxOpenConns, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse database max open connections", zap.Error(err))
+			return err
+		}
+	}
+	if v := os.Getenv("JIMM_DB_MAX_IDLE_CONNS"); v != "" {
+		dbPool.MaxIdleConns, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse database max idle connections", zap.Error(err))
+			return err
+		}
+	}
+	durationString = os.Getenv("JIMM_DB_CONN_MAX_IDLE_TIME")
+	if durationString != "" {
+		dbPool.ConnMaxIdleTime, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse database connection max idle time", zap.Error(err))
+			return err
+		}
+	}
+
+	var quotas jimm.QuotaLimits
+	if v := os.Getenv("JIMM_QUOTA_MODELS"); v != "" {
+		quotas.Models, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model quota", zap.Error(err))
+			return err
+		}
+	}
+	if v := os.Getenv("JIMM_QUOTA_MACHINES"); v != "" {
+		quotas.Machines, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse machine quota", zap.Error(err))
+			return err
+		}
+	}
+	if v := os.Getenv("JIMM_QUOTA_CORES"); v != "" {
+		quotas.Cores, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse core quota", zap.Error(err))
+			return err
+		}
+	}
+
+	var maxControllerModels int
+	if v := os.Getenv("JIMM_MAX_CONTROLLER_MODELS"); v != "" {
+		maxControllerModels, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse maximum controller models", zap.Error(err))
+			return err
+		}
+	}
+
+	var dataRetention jimm.RetentionPolicy
+	if v := os.Getenv("JIMM_DATA_RETENTION"); v != "" {
+		var periods map[string]string
+		if err := json.Unmarshal([]byte(v), &periods); err != nil {
+			zapctx.Error(ctx, "failed to parse data retention", zap.Error(err))
+			return err
+		}
+		dataRetention.Periods = make(map[string]time.Duration, len(periods))
+		for ds, period := range periods {
+			dataRetention.Periods[ds], err = time.ParseDuration(period)
+			if err != nil {
+				zapctx.Error(ctx, "failed to parse data retention period", zap.String("dataset", ds), zap.Error(err))
+				return err
+			}
+		}
+	}
+	if v := os.Getenv("JIMM_DATA_RETENTION_BATCH_SIZE"); v != "" {
+		dataRetention.BatchSize, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse data retention batch size", zap.Error(err))
+			return err
+		}
+	}
+	durationString = os.Getenv("JIMM_DATA_RETENTION_BATCH_INTERVAL")
+	if durationString != "" {
+		dataRetention.BatchInterval, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse data retention batch interval", zap.Error(err))
+			return err
+		}
+	}
+	var dataRetentionPeriod time.Duration
+	durationString = os.Getenv("JIMM_DATA_RETENTION_PERIOD")
+	if durationString != "" {
+		dataRetentionPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse data retention period", zap.Error(err))
+			return err
+		}
+	}
+
+	var notificationChannels []notify.ChannelConfig
+	if v := os.Getenv("JIMM_NOTIFICATION_CHANNELS"); v != "" {
+		if err := json.Unmarshal([]byte(v), &notificationChannels); err != nil {
+			zapctx.Error(ctx, "failed to parse notification channels", zap.Error(err))
+			return err
+		}
+	}
+
+	var groupSyncMappings []groupsync.Mapping
+	if v := os.Getenv("JIMM_GROUP_SYNC_MAPPINGS"); v != "" {
+		if err := json.Unmarshal([]byte(v), &groupSyncMappings); err != nil {
+			zapctx.Error(ctx, "failed to parse group sync mappings", zap.Error(err))
+			return err
+		}
+	}
+	var groupSyncPeriod time.Duration
+	durationString = os.Getenv("JIMM_GROUP_SYNC_PERIOD")
+	if durationString != "" {
+		groupSyncPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse group sync period", zap.Error(err))
+			return err
+		}
+	}
+	var groupSyncMaxRemovals int
+	if v := os.Getenv("JIMM_GROUP_SYNC_MAX_REMOVALS"); v != "" {
+		groupSyncMaxRemovals, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse group sync max removals", zap.Error(err))
+			return err
+		}
+	}
+	var controllerCredentialCheckPeriod time.Duration
+	durationString = os.Getenv("JIMM_CONTROLLER_CREDENTIAL_CHECK_PERIOD")
+	if durationString != "" {
+		controllerCredentialCheckPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse controller credential check period", zap.Error(err))
+			return err
+		}
+	}
+	var controllerProfileCapturePeriod time.Duration
+	durationString = os.Getenv("JIMM_CONTROLLER_PROFILE_CAPTURE_PERIOD")
+	if durationString != "" {
+		controllerProfileCapturePeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse controller profile capture period", zap.Error(err))
+			return err
+		}
+	}
+	var credentialUpdateConcurrency int
+	if v := os.Getenv("JIMM_CREDENTIAL_UPDATE_CONCURRENCY"); v != "" {
+		credentialUpdateConcurrency, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse credential update concurrency", zap.Error(err))
+			return err
+		}
+	}
+	// JIMM_CREDENTIAL_PROPAGATION is a space separated list of
+	// cloud=policy pairs, for example "aws=lazy".
+	credentialPropagationPolicies, err := jimm.ParseCredentialPropagationPolicies(os.Getenv("JIMM_CREDENTIAL_PROPAGATION"))
+	if err != nil {
+		zapctx.Error(ctx, "failed to parse credential propagation policies", zap.Error(err))
+		return err
+	}
+	var credentialUpdateRetryPeriod time.Duration
+	durationString = os.Getenv("JIMM_CREDENTIAL_UPDATE_RETRY_PERIOD")
+	if durationString != "" {
+		credentialUpdateRetryPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse credential update retry period", zap.Error(err))
+			return err
+		}
+	}
+	var credentialRemovalDeadline time.Duration
+	durationString = os.Getenv("JIMM_CREDENTIAL_REMOVAL_DEADLINE")
+	if durationString != "" {
+		credentialRemovalDeadline, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse credential removal deadline", zap.Error(err))
+			return err
+		}
+	}
+	// JIMM_JOB_SCHEDULES is a semicolon separated list of job=schedule
+	// pairs replacing the default schedules of the scheduled jobs, for
+	// example "data-retention=0 3 * * *".
+	jobSchedules, err := jimm.ParseJobSchedules(os.Getenv("JIMM_JOB_SCHEDULES"))
+	if err != nil {
+		zapctx.Error(ctx, "failed to parse job schedules", zap.Error(err))
+		return err
+	}
+	var latencyProbePeriod time.Duration
+	durationString = os.Getenv("JIMM_LATENCY_PROBE_PERIOD")
+	if durationString != "" {
+		latencyProbePeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse latency probe period", zap.Error(err))
+			return err
+		}
+	}
+	var controllerCallCeiling time.Duration
+	durationString = os.Getenv("JIMM_CONTROLLER_CALL_CEILING")
+	if durationString != "" {
+		controllerCallCeiling, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse controller call ceiling", zap.Error(err))
+			return err
+		}
+	}
+	var controllerCredentialExpiryWarning time.Duration
+	durationString = os.Getenv("JIMM_CONTROLLER_CREDENTIAL_EXPIRY_WARNING")
+	if durationString != "" {
+		controllerCredentialExpiryWarning, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse controller credential expiry warning", zap.Error(err))
+			return err
+		}
+	}
+	var controllerCertificateExpiryWarning time.Duration
+	durationString = os.Getenv("JIMM_CONTROLLER_CERTIFICATE_EXPIRY_WARNING")
+	if durationString != "" {
+		controllerCertificateExpiryWarning, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse controller certificate expiry warning", zap.Error(err))
+			return err
+		}
+	}
+	var modelIdlePeriod time.Duration
+	durationString = os.Getenv("JIMM_MODEL_IDLE_PERIOD")
+	if durationString != "" {
+		modelIdlePeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model idle period", zap.Error(err))
+			return err
+		}
+	}
+	var modelDestroyWindow time.Duration
+	durationString = os.Getenv("JIMM_MODEL_DESTROY_WINDOW")
+	if durationString != "" {
+		modelDestroyWindow, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model destroy window", zap.Error(err))
+			return err
+		}
+	}
+	var modelSnapshotPeriod time.Duration
+	durationString = os.Getenv("JIMM_MODEL_SNAPSHOT_PERIOD")
+	if durationString != "" {
+		modelSnapshotPeriod, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse model snapshot period", zap.Error(err))
+			return err
+		}
+	}
+	var idempotencyWindow time.Duration
+	durationString = os.Getenv("JIMM_IDEMPOTENCY_WINDOW")
+	if durationString != "" {
+		idempotencyWindow, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse idempotency window", zap.Error(err))
+			return err
+		}
+	}
+	var sessionIdleTimeout time.Duration
+	durationString = os.Getenv("JIMM_SESSION_IDLE_TIMEOUT")
+	if durationString != "" {
+		sessionIdleTimeout, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse session idle timeout", zap.Error(err))
+			return err
+		}
+	}
+	var migrationTimeout time.Duration
+	durationString = os.Getenv("JIMM_MIGRATION_TIMEOUT")
+	if durationString != "" {
+		migrationTimeout, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse migration timeout", zap.Error(err))
+			return err
+		}
+	}
+
+	objectStore := jimmsvc.ObjectStoreParams{
+		Endpoint: os.Getenv("JIMM_OBJECT_STORE_ENDPOINT"),
+		Region:   os.Getenv("JIMM_OBJECT_STORE_REGION"),
+		Bucket:   os.Getenv("JIMM_OBJECT_STORE_BUCKET"),
+	}
+	if _, ok := os.LookupEnv("JIMM_OBJECT_STORE_PATH_STYLE"); ok {
+		objectStore.PathStyle = true
+	}
+	if v := os.Getenv("JIMM_OBJECT_STORE_LIFECYCLE"); v != "" {
+		var expiries map[string]string
+		if err := json.Unmarshal([]byte(v), &expiries); err != nil {
+			zapctx.Error(ctx, "failed to parse object store lifecycle", zap.Error(err))
+			return err
+		}
+		objectStore.Lifecycle = make(objectstore.LifecyclePolicy, len(expiries))
+		for kind, expiry := range expiries {
+			objectStore.Lifecycle[objectstore.Kind(kind)], err = time.ParseDuration(expiry)
+			if err != nil {
+				zapctx.Error(ctx, "failed to parse object store expiry", zap.String("kind", kind), zap.Error(err))
+				return err
+			}
+		}
+	}
+
+	var deltaExportBufferSize int
+	if v := os.Getenv("JIMM_DELTA_EXPORT_BUFFER_SIZE"); v != "" {
+		deltaExportBufferSize, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse delta export buffer size", zap.Error(err))
+			return err
+		}
+	}
+	var maxOfferConsumers int
+	if v := os.Getenv("JIMM_MAX_OFFER_CONSUMERS"); v != "" {
+		maxOfferConsumers, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse max offer consumers", zap.Error(err))
+			return err
+		}
+	}
+	var maxInlineResultSize int
+	if v := os.Getenv("JIMM_MAX_INLINE_RESULT_SIZE"); v != "" {
+		maxInlineResultSize, err = strconv.Atoi(v)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse max inline result size", zap.Error(err))
+			return err
+		}
+	}
+	var resultDownloadTTL time.Duration
+	durationString = os.Getenv("JIMM_RESULT_DOWNLOAD_TTL")
+	if durationString != "" {
+		resultDownloadTTL, err = time.ParseDuration(durationString)
+		if err != nil {
+			zapctx.Error(ctx, "failed to parse result download TTL", zap.Error(err))
+			return err
+		}
+	}
 
 	jimmsvc, err := jimmsvc.NewService(ctx, jimmsvc.Params{
 		ControllerUUID:    os.Getenv("JIMM_UUID"),
 		DSN:               os.Getenv("JIMM_DSN"),
+		DBPool:            dbPool,
 		ControllerAdmins:  strings.Fields(os.Getenv("JIMM_ADMINS")),
 		VaultRoleID:       os.Getenv("VAULT_ROLE_ID"),
 		VaultRoleSecretID: os.Getenv("VAULT_ROLE_SECRET_ID"),
@@ -168,6 +721,7 @@ func start(ctx context.Context, s *service.Service) error {
 		MacaroonExpiryDuration:        macaroonExpiryDuration,
 		JWTExpiryDuration:             jwtExpiryDuration,
 		InsecureSecretStorage:         insecureSecretStorage,
+		SecretEncryptionKeyFile:       os.Getenv("JIMM_SECRET_ENCRYPTION_KEY_FILE"),
 		OAuthAuthenticatorParams: jimmsvc.OAuthAuthenticatorParams{
 			IssuerURL:            issuerURL,
 			ClientID:             clientID,
@@ -178,53 +732,161 @@ func start(ctx context.Context, s *service.Service) error {
 			JWTSessionKey:        sessionSecretKey,
 			SecureSessionCookies: secureSessionCookies,
 		},
-		DashboardFinalRedirectURL: os.Getenv("JIMM_DASHBOARD_FINAL_REDIRECT_URL"),
-		CookieSessionKey:          []byte(sessionSecretKey),
-		CorsAllowedOrigins:        corsAllowedOrigins,
+		DashboardFinalRedirectURL:          os.Getenv("JIMM_DASHBOARD_FINAL_REDIRECT_URL"),
+		CookieSessionKey:                   []byte(sessionSecretKey),
+		CorsAllowedOrigins:                 corsAllowedOrigins,
+		WebsocketAllowedOrigins:            websocketAllowedOrigins,
+		MinClientVersion:                   minClientVersion,
+		BlockedUserAgents:                  blockedUserAgents,
+		FacadeDeprecations:                 facadeDeprecations,
+		MaxConcurrentRequests:              maxConcurrentRequests,
+		RequestPriorityWeights:             requestPriorityWeights,
+		RedactedModelFields:                redactedModelFields,
+		RedactedModelConfigKeys:            redactedModelConfigKeys,
+		FanOutSoftDeadline:                 fanOutSoftDeadline,
+		ModelSummariesDeadline:             modelSummariesDeadline,
+		ModelAccessResyncPeriod:            modelAccessResyncPeriod,
+		ModelAccessResyncApply:             modelAccessResyncApply,
+		ControllerAccessAuditPeriod:        controllerAccessAuditPeriod,
+		CacheTTL:                           cacheTTL,
+		ModelAccessCacheTTL:                modelAccessCacheTTL,
+		ModelDNSDomain:                     os.Getenv("JIMM_MODEL_DNS_DOMAIN"),
+		Quotas:                             quotas,
+		MaxControllerModels:                maxControllerModels,
+		ConfirmationPeriod:                 confirmationPeriod,
+		DataRetention:                      dataRetention,
+		DataRetentionPeriod:                dataRetentionPeriod,
+		AccessRequestWebhookURL:            os.Getenv("JIMM_ACCESS_REQUEST_WEBHOOK_URL"),
+		NotificationChannels:               notificationChannels,
+		GroupSyncSCIMURL:                   os.Getenv("JIMM_GROUP_SYNC_SCIM_URL"),
+		GroupSyncSCIMToken:                 os.Getenv("JIMM_GROUP_SYNC_SCIM_TOKEN"),
+		GroupSyncSCIMMemberAttribute:       os.Getenv("JIMM_GROUP_SYNC_SCIM_MEMBER_ATTRIBUTE"),
+		GroupSyncMappings:                  groupSyncMappings,
+		GroupSyncPeriod:                    groupSyncPeriod,
+		GroupSyncCredentialGroup:           os.Getenv("JIMM_GROUP_SYNC_CREDENTIAL_GROUP"),
+		GroupSyncMaxRemovals:               groupSyncMaxRemovals,
+		ControllerCredentialCheckPeriod:    controllerCredentialCheckPeriod,
+		ControllerProfileCapturePeriod:     controllerProfileCapturePeriod,
+		ControllerCredentialExpiryWarning:  controllerCredentialExpiryWarning,
+		ControllerCertificateExpiryWarning: controllerCertificateExpiryWarning,
+		ModelIdlePeriod:                    modelIdlePeriod,
+		ModelDestroyWindow:                 modelDestroyWindow,
+		IdentityCaseFold:                   identityCaseFold,
+		ControllerServiceUsers:             controllerServiceUsers,
+		IdentityDomainAliases:              identityDomainAliases,
+		NotifyIdleModels:                   notifyIdleModels,
+		DeltaExportURL:                     os.Getenv("JIMM_DELTA_EXPORT_NATS_URL"),
+		DeltaExportSubject:                 os.Getenv("JIMM_DELTA_EXPORT_SUBJECT"),
+		DeltaExportBufferSize:              deltaExportBufferSize,
+		DisableDatabaseIndexBuild:          disableDatabaseIndexBuild,
+		ModelSnapshotPeriod:                modelSnapshotPeriod,
+		IdempotencyWindow:                  idempotencyWindow,
+		ControllerMetricsPrefixes:          controllerMetricsPrefixes,
+		ControllerMetricLabels:             controllerMetricLabels,
+		ModelMetricLabels:                  modelMetricLabels,
+		MetricLabelMaxValues:               metricLabelMaxValues,
+		WatcherPerModelMetrics:             watcherPerModelMetrics,
+		WatcherModelReconcileInterval:      watcherModelReconcileInterval,
+		CharmhubURL:                        os.Getenv("JIMM_CHARMHUB_URL"),
+		CharmPolicy:                        charmPolicy,
+		CredentialUpdateConcurrency:        credentialUpdateConcurrency,
+		CredentialPropagationPolicies:      credentialPropagationPolicies,
+		CredentialUpdateRetryPeriod:        credentialUpdateRetryPeriod,
+		CredentialRemovalDeadline:          credentialRemovalDeadline,
+		JobSchedules:                       jobSchedules,
+		LatencyProbePeriod:                 latencyProbePeriod,
+		ControllerCallCeiling:              controllerCallCeiling,
+		TunablesFile:                       os.Getenv("JIMM_TUNABLES_FILE"),
+		MaxOfferConsumers:                  maxOfferConsumers,
+		MaxInlineResultSize:                maxInlineResultSize,
+		ResultDownloadTTL:                  resultDownloadTTL,
+		SessionIdleTimeout:                 sessionIdleTimeout,
+		MigrationTimeout:                   migrationTimeout,
+		ObjectStore:                        objectStore,
 	})
 	if err != nil {
 		return err
 	}
-
-	isLeader := os.Getenv("JIMM_IS_LEADER") != ""
-	if isLeader {
-		s.Go(func() error { return jimmsvc.WatchControllers(ctx) }) // Deletes dead/dying models, updates model config.
+	if err := jimmsvc.LoadTunables(ctx); err != nil {
+		return err
 	}
-	s.Go(func() error { return jimmsvc.WatchModelSummaries(ctx) })
-
-	if isLeader {
-		zapctx.Info(ctx, "attempting to start JWKS rotator and generate OAuth secret key")
+	if os.Getenv("JIMM_TUNABLES_FILE") != "" {
+		reload := make(chan os.Signal, 1)
+		signal.Notify(reload, syscall.SIGHUP)
 		s.Go(func() error {
-			if err := jimmsvc.StartJWKSRotator(ctx, time.NewTicker(time.Hour).C, time.Now().UTC().AddDate(0, 3, 0)); err != nil {
-				zapctx.Error(ctx, "failed to start JWKS rotator", zap.Error(err))
-				return err
-			}
+			defer signal.Stop(reload)
+			jimmsvc.WatchTunables(ctx, reload)
 			return nil
 		})
 	}
 
-	if isLeader {
-		// No need for s.Go() since this routine doesn't return an error.
-		go jimmsvc.MonitorResources(ctx)
-	}
+	s.Go(func() error { return jimmsvc.WatchModelSummaries(ctx) })
+	s.Go(func() error { return jimmsvc.RunLeaderWorkers(ctx) })
+	s.Go(func() error {
+		jimmsvc.RunCacheInvalidationBus(ctx)
+		return nil
+	})
+	s.Go(func() error {
+		jimmsvc.ProbeLatencies(ctx)
+		return nil
+	})
+	s.Go(func() error {
+		jimmsvc.DrainMaintenanceControllers(ctx, 30*time.Second)
+		return nil
+	})
 
-	httpsrv := &http.Server{
-		Addr:              addr,
-		Handler:           jimmsvc,
-		ReadHeaderTimeout: time.Second * 5,
+	var servers []*http.Server
+	for _, l := range listeners {
+		tlsConfig, err := l.params.TLSConfig()
+		if err != nil {
+			zapctx.Error(ctx, "failed to configure listener TLS", zap.String("addr", l.params.Addr), zap.Error(err))
+			return err
+		}
+		servers = append(servers, &http.Server{
+			Addr:              l.params.Addr,
+			Handler:           jimmsvc.Handler(l.classes...),
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: time.Second * 5,
+		})
+		zapctx.Info(ctx, "listening", zap.String("addr", l.params.Addr), zap.Any("apis", l.classes), zap.Bool("tls", tlsConfig != nil))
 	}
 	s.OnShutdown(func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
 		zapctx.Warn(ctx, "server shutdown triggered")
-		err = httpsrv.Shutdown(ctx)
-		if err != nil {
-			zapctx.Error(ctx, "failed to shutdown server gracefully", zap.Error(err))
+		for _, httpsrv := range servers {
+			if err := httpsrv.Shutdown(ctx); err != nil {
+				zapctx.Error(ctx, "failed to shutdown server gracefully", zap.String("addr", httpsrv.Addr), zap.Error(err))
+			}
 		}
 		jimmsvc.Cleanup()
 	})
-	s.Go(httpsrv.ListenAndServe)
+	for _, httpsrv := range servers {
+		s.Go(func() error {
+			if httpsrv.TLSConfig != nil {
+				return httpsrv.ListenAndServeTLS("", "")
+			}
+			return httpsrv.ListenAndServe()
+		})
+	}
 	zapctx.Info(ctx, "Successfully started JIMM server")
 	return nil
 }
+
+// A listener is a listener serving some classes of the JIMM API.
+type listener struct {
+	params  jimmsvc.ListenerParams
+	classes []jimmsvc.Listener
+}
+
+// listenerParams returns the configuration of a listener read from the
+// environment variables with the given prefix.
+func listenerParams(prefix string) jimmsvc.ListenerParams {
+	return jimmsvc.ListenerParams{
+		Addr:            os.Getenv(prefix + "LISTEN_ADDR"),
+		TLSCertFile:     os.Getenv(prefix + "TLS_CERT_FILE"),
+		TLSKeyFile:      os.Getenv(prefix + "TLS_KEY_FILE"),
+		TLSClientCAFile: os.Getenv(prefix + "TLS_CLIENT_CA_FILE"),
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Canonical.
+
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jujuapi"
+)
+
+// A Listener identifies a class of the JIMM API. Each class may be served
+// on its own listener, with its own bind address and TLS configuration.
+type Listener string
+
+const (
+	// ListenerPublic is the API used by JAAS users: the juju API, the
+	// dashboard, authentication and the public REST endpoints.
+	ListenerPublic Listener = "public"
+
+	// ListenerAdmin is the API used only by JIMM administrators. The
+	// admin listener also serves the controller API, the administrative
+	// methods of the JIMM facade are only served there.
+	ListenerAdmin Listener = "admin"
+
+	// ListenerInternal is the API used by the infrastructure running
+	// JIMM: metrics and health checks.
+	ListenerInternal Listener = "internal"
+)
+
+// listenerPaths holds the path prefixes of the endpoints that are not
+// part of the public API.
+var listenerPaths = []struct {
+	prefix   string
+	listener Listener
+}{
+	{"/rebac", ListenerAdmin},
+	{"/metrics", ListenerInternal},
+	{"/controller-metrics", ListenerInternal},
+	{"/debug", ListenerInternal},
+}
+
+// adminAPIPaths holds the paths of the public endpoints that are also
+// served by the admin listener.
+var adminAPIPaths = []string{"/api"}
+
+// listenerFor returns the class of API the given request path is part
+// of.
+func listenerFor(path string) Listener {
+	for _, lp := range listenerPaths {
+		if path == lp.prefix || strings.HasPrefix(path, lp.prefix+"/") {
+			return lp.listener
+		}
+	}
+	return ListenerPublic
+}
+
+// Handler returns a handler that serves only the given classes of the
+// JIMM API, requests for any other endpoint receive a 404 response.
+// ServeHTTP serves every class of the API. Unless the admin API is one
+// of the given classes the administrative methods of the JIMM facade are
+// not served, see jujuapi.ContextWithoutAdminMethods.
+func (s *Service) Handler(listeners ...Listener) http.Handler {
+	admin := slices.Contains(listeners, ListenerAdmin)
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case slices.Contains(listeners, listenerFor(req.URL.Path)):
+		case admin && slices.Contains(adminAPIPaths, req.URL.Path):
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		if !admin {
+			req = req.WithContext(jujuapi.ContextWithoutAdminMethods(req.Context()))
+		}
+		s.mux.ServeHTTP(w, req)
+	})
+}
+
+// ListenerParams holds the configuration of a listener.
+type ListenerParams struct {
+	// Addr is the address the listener binds to.
+	Addr string
+
+	// TLSCertFile and TLSKeyFile are the paths of the PEM encoded
+	// certificate and key the listener serves TLS with. If these are
+	// empty the listener serves plain HTTP, TLS is expected to be
+	// terminated in front of JIMM.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TLSClientCAFile is the path of the PEM encoded certificates of the
+	// authorities that sign client certificates. If this is set clients
+	// must present a certificate signed by one of the authorities, if it
+	// is empty client certificates are not required.
+	TLSClientCAFile string
+}
+
+// TLSConfig returns the TLS configuration of the listener, or nil if the
+// listener serves plain HTTP.
+func (p ListenerParams) TLSConfig() (*tls.Config, error) {
+	const op = errors.Op("ListenerParams.TLSConfig")
+
+	if p.TLSCertFile == "" && p.TLSKeyFile == "" {
+		if p.TLSClientCAFile != "" {
+			return nil, errors.E(op, "client certificates cannot be required without a server certificate")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(p.TLSCertFile, p.TLSKeyFile)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if p.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(p.TLSClientCAFile)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.E(op, "no certificates found in "+p.TLSClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Canonical.
+
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	jimmsvc "github.com/canonical/jimm/v3/cmd/jimmsrv/service"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+func TestListenerHandlers(t *testing.T) {
+	c := qt.New(t)
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	p := jimmtest.NewTestJimmParams(c)
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	p.InsecureSecretStorage = true
+	svc, err := jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+
+	get := func(h http.Handler, path string) int {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		return rr.Code
+	}
+
+	public := svc.Handler(jimmsvc.ListenerPublic)
+	c.Check(get(public, "/debug/info"), qt.Equals, http.StatusNotFound)
+	c.Check(get(public, "/metrics"), qt.Equals, http.StatusNotFound)
+	c.Check(get(public, "/rebac/v1/swagger.json"), qt.Equals, http.StatusNotFound)
+	c.Check(get(public, "/swagger.json"), qt.Equals, http.StatusOK)
+
+	internal := svc.Handler(jimmsvc.ListenerInternal)
+	c.Check(get(internal, "/debug/info"), qt.Equals, http.StatusOK)
+	c.Check(get(internal, "/metrics"), qt.Equals, http.StatusOK)
+	c.Check(get(internal, "/swagger.json"), qt.Equals, http.StatusNotFound)
+
+	admin := svc.Handler(jimmsvc.ListenerAdmin)
+	c.Check(get(admin, "/debug/info"), qt.Equals, http.StatusNotFound)
+	c.Check(get(admin, "/swagger.json"), qt.Equals, http.StatusNotFound)
+	// The controller API is served to administrators on the admin
+	// listener as well as on the public listener.
+	c.Check(get(admin, "/api"), qt.Not(qt.Equals), http.StatusNotFound)
+	c.Check(get(public, "/api"), qt.Not(qt.Equals), http.StatusNotFound)
+
+	all := svc.Handler(jimmsvc.ListenerPublic, jimmsvc.ListenerInternal)
+	c.Check(get(all, "/debug/info"), qt.Equals, http.StatusOK)
+	c.Check(get(all, "/swagger.json"), qt.Equals, http.StatusOK)
+}
+
+func TestListenerParamsTLSConfig(t *testing.T) {
+	c := qt.New(t)
+
+	config, err := jimmsvc.ListenerParams{Addr: ":8080"}.TLSConfig()
+	c.Assert(err, qt.IsNil)
+	c.Check(config, qt.IsNil)
+
+	_, err = jimmsvc.ListenerParams{
+		Addr:            ":8080",
+		TLSClientCAFile: filepath.Join(c.TempDir(), "ca.pem"),
+	}.TLSConfig()
+	c.Check(err, qt.ErrorMatches, `client certificates cannot be required without a server certificate`)
+
+	_, err = jimmsvc.ListenerParams{
+		Addr:        ":8080",
+		TLSCertFile: filepath.Join(c.TempDir(), "cert.pem"),
+		TLSKeyFile:  filepath.Join(c.TempDir(), "key.pem"),
+	}.TLSConfig()
+	c.Check(err, qt.ErrorMatches, `open .*cert.pem: no such file or directory`)
+}
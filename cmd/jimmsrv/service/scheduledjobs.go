@@ -0,0 +1,283 @@
+// Copyright 2024 Canonical.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+// every returns a schedule that runs a job at the given period.
+func every(period time.Duration) string {
+	return "@every " + period.String()
+}
+
+// setupScheduler creates the scheduler running the service's periodic
+// jobs. The given schedules, keyed by job name, replace the default
+// schedules of the jobs, a schedule for a job that is not scheduled is
+// an error.
+func (s *Service) setupScheduler(schedules map[string]string) error {
+	s.jimm.Scheduler = &jimm.Scheduler{Database: s.jimm.Database}
+	scheduled := make(map[string]bool)
+	for _, job := range s.scheduledJobs() {
+		if schedule, ok := schedules[job.Name]; ok {
+			job.Schedule = schedule
+		}
+		if err := s.jimm.Scheduler.Add(job); err != nil {
+			return err
+		}
+		scheduled[job.Name] = true
+	}
+	for name := range schedules {
+		if !scheduled[name] {
+			return errors.E(errors.CodeBadRequest, fmt.Sprintf("schedule given for unknown job %q", name))
+		}
+	}
+	return nil
+}
+
+// scheduledJobs returns the periodic jobs run by the service, jobs that
+// are not configured are not included. Each job runs on the JIMM
+// replica holding its lease, see RunLeaderWorkers.
+func (s *Service) scheduledJobs() []jimm.ScheduledJob {
+	jobs := []jimm.ScheduledJob{{
+		Name:        "resource-monitor",
+		Description: "Update the resource metrics.",
+		Schedule:    every(5 * time.Minute),
+		RunAtStart:  true,
+		Run: func(ctx context.Context) error {
+			s.jimm.UpdateMetrics(ctx)
+			return nil
+		},
+	}}
+	if s.modelAccessResyncPeriod > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "model-access-resync",
+			Description: "Re-sync model access to the controllers, see jimm.ResyncAllModelAccess.",
+			Schedule:    every(s.modelAccessResyncPeriod),
+			Run: func(ctx context.Context) error {
+				resp, err := s.jimm.ResyncAllModelAccess(ctx, "", jimm.DefaultModelAccessResyncInterval, s.modelAccessResyncApply)
+				if err != nil {
+					return err
+				}
+				zapctx.Info(ctx, "re-synced model access",
+					zap.Int("models", resp.ModelsChecked),
+					zap.Int("differences", len(resp.Differences)),
+					zap.Bool("applied", resp.Applied),
+					zap.Int("errors", len(resp.Errors)),
+				)
+				return nil
+			},
+		})
+	}
+	if s.controllerAccessAuditPeriod > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "controller-access-audit",
+			Description: "Audit the access held directly on the controllers, see jimm.AuditAllControllerAccess.",
+			Schedule:    every(s.controllerAccessAuditPeriod),
+			Run: func(ctx context.Context) error {
+				resp, err := s.jimm.AuditAllControllerAccess(ctx, "", jimm.DefaultModelAccessResyncInterval)
+				if err != nil {
+					return err
+				}
+				zapctx.Info(ctx, "audited controller access",
+					zap.Int("controllers", resp.ControllersChecked),
+					zap.Int("models", resp.ModelsChecked),
+					zap.Int("findings", len(resp.Findings)),
+					zap.Int("errors", len(resp.Errors)),
+				)
+				return nil
+			},
+		})
+	}
+	if len(s.dataRetention.Periods) > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "data-retention",
+			Description: "Prune historical data older than its retention period.",
+			Schedule:    every(s.dataRetentionPeriod),
+			Run: func(ctx context.Context) error {
+				pruned, err := s.jimm.PruneHistoricalData(ctx, s.dataRetention)
+				fields := make([]zap.Field, 0, len(pruned))
+				for ds, n := range pruned {
+					fields = append(fields, zap.Int64(ds, n))
+				}
+				if err != nil {
+					zapctx.Warn(ctx, "partially pruned historical data", fields...)
+					return err
+				}
+				zapctx.Info(ctx, "pruned historical data", fields...)
+				return nil
+			},
+		})
+	}
+	if s.groupSyncPeriod > 0 && s.jimm.GroupSync.Directory != nil {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "group-sync",
+			Description: "Synchronise group memberships from the external directory.",
+			Schedule:    every(s.groupSyncPeriod),
+			Run: func(ctx context.Context) error {
+				resp, err := s.jimm.RunGroupSync(ctx, false, false)
+				if err != nil {
+					return err
+				}
+				zapctx.Info(ctx, "synchronised groups",
+					zap.Int("created", len(resp.CreatedGroups)),
+					zap.Int("changes", len(resp.Changes)),
+					zap.Int("errors", len(resp.Errors)),
+					zap.Int("rebinds", len(resp.Rebinds)),
+				)
+				return nil
+			},
+		})
+	}
+	if s.controllerCredentialPeriod > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "controller-credential-monitor",
+			Description: "Check the cloud credentials used by the controller models.",
+			Schedule:    every(s.controllerCredentialPeriod),
+			Run:         s.jimm.CheckControllerModelCredentials,
+		})
+	}
+	if s.controllerProfilePeriod > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "controller-bootstrap-profiles",
+			Description: "Capture the controllers' bootstrap profiles.",
+			Schedule:    every(s.controllerProfilePeriod),
+			Run:         s.jimm.CaptureControllerBootstrapProfiles,
+		})
+	}
+	jobs = append(jobs, jimm.ScheduledJob{
+		Name:        "credential-update-retry",
+		Description: "Retry the cloud credential updates that failed on some controllers.",
+		Schedule:    every(s.credentialRetryPeriod),
+		Run:         s.jimm.RetryCredentialUpdates,
+	}, jimm.ScheduledJob{
+		Name:        "credential-removal-verification",
+		Description: "Verify revoked cloud credentials have been removed from the controllers.",
+		Schedule:    every(s.credentialRetryPeriod),
+		Run: func(ctx context.Context) error {
+			n, err := s.jimm.VerifyCredentialRemovals(ctx)
+			if n > 0 {
+				zapctx.Info(ctx, "confirmed credential removals", zap.Int("count", n))
+			}
+			return err
+		},
+	})
+	if s.modelSnapshotPeriod > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "model-resource-snapshots",
+			Description: "Record snapshots of the resources used by each model.",
+			Schedule:    every(s.modelSnapshotPeriod),
+			Run:         s.jimm.RecordModelResourceSnapshots,
+		})
+	}
+	if s.jimm.ResultDownloadURL != "" {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "result-download-expiry",
+			Description: "Remove the oversized results whose download period has passed.",
+			Schedule:    every(time.Minute),
+			Run: func(ctx context.Context) error {
+				n, err := s.jimm.DeleteExpiredResultDownloads(ctx)
+				if err != nil {
+					return err
+				}
+				zapctx.Debug(ctx, "deleted expired result downloads", zap.Int64("count", n))
+				return nil
+			},
+		})
+	}
+	if s.idempotencyWindow > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "idempotency-key-expiry",
+			Description: "Remove the idempotency keys whose deduplication window has passed.",
+			Schedule:    every(time.Hour),
+			Run: func(ctx context.Context) error {
+				n, err := s.jimm.Database.DeleteExpiredIdempotencyKeys(ctx, time.Now())
+				if err != nil {
+					return err
+				}
+				zapctx.Debug(ctx, "deleted expired idempotency keys", zap.Int64("count", n))
+				return nil
+			},
+		})
+	}
+	jobs = append(jobs, jimm.ScheduledJob{
+		Name:        "identity-session-expiry",
+		Description: "Remove the API sessions that have been idle for the session idle timeout.",
+		Schedule:    every(time.Hour),
+		Run: func(ctx context.Context) error {
+			n, err := s.jimm.DeleteIdleIdentitySessions(ctx, s.sessionIdleTimeout)
+			if err != nil {
+				return err
+			}
+			zapctx.Debug(ctx, "deleted idle sessions", zap.Int64("count", n))
+			return nil
+		},
+	}, jimm.ScheduledJob{
+		// Migrations recorded by a replica that stopped are picked up
+		// as soon as this job starts.
+		Name:        "model-migration-monitor",
+		Description: "Check the progress of the model migrations JIMM has initiated.",
+		Schedule:    every(30 * time.Second),
+		RunAtStart:  true,
+		Run: func(ctx context.Context) error {
+			n, err := s.jimm.MonitorModelMigrations(ctx)
+			if n > 0 {
+				zapctx.Info(ctx, "model migrations finished", zap.Int("count", n))
+			}
+			return err
+		},
+	}, jimm.ScheduledJob{
+		Name:        "model-pool-replenisher",
+		Description: "Keep the model pools filled with ready models.",
+		Schedule:    every(time.Minute),
+		RunAtStart:  true,
+		Run: func(ctx context.Context) error {
+			n, err := s.jimm.ReplenishModelPools(ctx)
+			if n > 0 {
+				zapctx.Info(ctx, "replenished model pools", zap.Int("count", n))
+			}
+			return err
+		},
+	}, jimm.ScheduledJob{
+		Name:        "controller-certificate-monitor",
+		Description: "Report the controllers whose certificates are expiring.",
+		Schedule:    "@daily",
+		Jitter:      time.Hour,
+		RunAtStart:  true,
+		Run:         s.jimm.CheckControllerCertificates,
+	})
+	if s.jimm.ModelDestroyWindow > 0 {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "model-destroy-reaper",
+			Description: "Destroy the models whose destroy window has passed.",
+			Schedule:    every(time.Minute),
+			RunAtStart:  true,
+			Run: func(ctx context.Context) error {
+				n, err := s.jimm.ReapPendingModelDestroys(ctx)
+				if n > 0 {
+					zapctx.Info(ctx, "destroyed pending models", zap.Int("count", n))
+				}
+				return err
+			},
+		})
+	}
+	if s.notifyIdleModels {
+		jobs = append(jobs, jimm.ScheduledJob{
+			Name:        "idle-model-notifier",
+			Description: "Send notifications about the idle models.",
+			Schedule:    "@daily",
+			Jitter:      time.Hour,
+			RunAtStart:  true,
+			Run:         s.jimm.NotifyIdleModels,
+		})
+	}
+	return jobs
+}
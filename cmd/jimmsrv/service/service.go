@@ -8,6 +8,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/juju/names/v5"
+	jujuversion "github.com/juju/version/v2"
 	"github.com/juju/zaputil/zapctx"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
@@ -26,11 +28,16 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/canonical/jimm/v3/internal/auth"
+	"github.com/canonical/jimm/v3/internal/charmhub"
 	"github.com/canonical/jimm/v3/internal/dashboard"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/debugapi"
 	"github.com/canonical/jimm/v3/internal/discharger"
+	"github.com/canonical/jimm/v3/internal/envelope"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/eventstream"
+	"github.com/canonical/jimm/v3/internal/faults"
+	"github.com/canonical/jimm/v3/internal/groupsync"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	jimmcreds "github.com/canonical/jimm/v3/internal/jimm/credentials"
 	"github.com/canonical/jimm/v3/internal/jimmhttp"
@@ -39,12 +46,19 @@ import (
 	"github.com/canonical/jimm/v3/internal/jujuclient"
 	"github.com/canonical/jimm/v3/internal/logger"
 	"github.com/canonical/jimm/v3/internal/middleware"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+	"github.com/canonical/jimm/v3/internal/openapi"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
 	"github.com/canonical/jimm/v3/internal/rebac_admin"
+	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
+	"github.com/canonical/jimm/v3/internal/servermon"
 	"github.com/canonical/jimm/v3/internal/vault"
 	"github.com/canonical/jimm/v3/internal/wellknownapi"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	"github.com/canonical/jimm/v3/version"
 )
 
 const (
@@ -61,6 +75,32 @@ type OpenFGAParams struct {
 	Port      string
 }
 
+// Default database connection pool limits, used when the corresponding
+// DBPoolParams field is zero.
+const (
+	DefaultDBMaxOpenConns    = 50
+	DefaultDBMaxIdleConns    = 10
+	DefaultDBConnMaxIdleTime = 5 * time.Minute
+)
+
+// DBPoolParams holds the parameters used to size the pool of database
+// connections. The pool grows as required under load, up to
+// MaxOpenConns connections, and shrinks again once connections have been
+// idle for longer than ConnMaxIdleTime.
+type DBPoolParams struct {
+	// MaxOpenConns is the maximum number of open connections to the
+	// database. If this is zero DefaultDBMaxOpenConns is used.
+	MaxOpenConns int
+
+	// MaxIdleConns is the maximum number of idle connections kept in
+	// the pool. If this is zero DefaultDBMaxIdleConns is used.
+	MaxIdleConns int
+
+	// ConnMaxIdleTime is the time after which an idle connection is
+	// closed. If this is zero DefaultDBConnMaxIdleTime is used.
+	ConnMaxIdleTime time.Duration
+}
+
 // OAuthAuthenticatorParams holds parameters needed to configure an OAuthAuthenticator
 // implementation.
 type OAuthAuthenticatorParams struct {
@@ -106,6 +146,10 @@ type Params struct {
 	// will be used.
 	DSN string
 
+	// DBPool holds the parameters used to size the pool of database
+	// connections.
+	DBPool DBPoolParams
+
 	// ControllerAdmins contains a list of users (or groups)
 	// that will be given the access-level "superuser" when they
 	// authenticate to the controller.
@@ -174,6 +218,14 @@ type Params struct {
 	// instead of dedicated secure storage. SHOULD NOT BE USED IN PRODUCTION.
 	InsecureSecretStorage bool
 
+	// SecretEncryptionKeyFile is the path of a file holding the master
+	// keys used to encrypt secrets stored in JIMM's database, see
+	// envelope.LoadKeyFile for the file format. If this is set secrets
+	// are stored encrypted in the database instead of in vault. The
+	// first key in the file is used to encrypt new secrets, existing
+	// secrets are re-encrypted with it in the background.
+	SecretEncryptionKeyFile string
+
 	// OAuthAuthenticatorParams holds parameters needed to configure an OAuthAuthenticator
 	// implementation.
 	OAuthAuthenticatorParams OAuthAuthenticatorParams
@@ -190,6 +242,363 @@ type Params struct {
 	// CorsAllowedOrigins represents all addresses that are valid for cross-origin
 	// requests. A wildcard '*' is accepted to allow all cross-origin requests.
 	CorsAllowedOrigins []string
+
+	// WebsocketAllowedOrigins holds the origins browser clients may open
+	// websocket connections from, in addition to the CORS restrictions.
+	// If this is empty all origins are allowed.
+	WebsocketAllowedOrigins []string
+
+	// MinClientVersion is the minimum juju client version that may open
+	// websocket connections. If this is zero all versions are allowed.
+	MinClientVersion jujuversion.Number
+
+	// BlockedUserAgents holds strings which, if found in the User-Agent
+	// header of a websocket client, cause the connection to be rejected.
+	BlockedUserAgents []string
+
+	// FacadeDeprecations holds the facade versions scheduled for
+	// removal, see jujuapi.Params.FacadeDeprecations.
+	FacadeDeprecations jimmRPC.FacadeDeprecations
+
+	// MaxConcurrentRequests is the maximum number of controller and
+	// model API requests processed concurrently. Once it is reached
+	// requests wait for capacity, with interactive requests prioritised
+	// over dashboard polling and automation. If this is zero requests
+	// are not limited.
+	MaxConcurrentRequests int
+
+	// RequestPriorityWeights holds the share of capacity given to each
+	// request class when MaxConcurrentRequests is reached. Classes
+	// without a weight use jimmRPC.DefaultRequestWeights.
+	RequestPriorityWeights map[jimmRPC.RequestClass]int
+
+	// RedactedModelFields holds the model fields removed from model
+	// information returned to users with less than admin access to the
+	// model, see the jujuapi.Redact* constants. If this is nil
+	// jujuapi.DefaultRedactedModelFields is used.
+	RedactedModelFields []string
+
+	// RedactedModelConfigKeys holds the model config keys removed from
+	// the model config and model information returned to users with less
+	// than admin access to the model. If this is nil
+	// jujuapi.DefaultRedactedModelConfigKeys is used.
+	RedactedModelConfigKeys []string
+
+	// FanOutSoftDeadline is the time after which API methods that query
+	// a number of controllers return partial results, see
+	// jujuapi.Params.FanOutSoftDeadline. If this is zero all controllers
+	// are waited for.
+	FanOutSoftDeadline time.Duration
+
+	// ModelSummariesDeadline is the time after which ListModelSummaries
+	// returns the summaries it has read, see
+	// jujuapi.Params.ModelSummariesDeadline. If this is zero every
+	// summary is read.
+	ModelSummariesDeadline time.Duration
+
+	// ModelAccessResyncPeriod is the period between scheduled re-syncs
+	// of model access to the controllers. If this is zero model access
+	// is only re-synced when requested by an administrator.
+	ModelAccessResyncPeriod time.Duration
+
+	// ModelAccessResyncApply makes the scheduled re-syncs of model
+	// access fix the differences they find. If this is false the
+	// differences are only reported.
+	ModelAccessResyncApply bool
+
+	// ControllerAccessAuditPeriod is the period between scheduled audits
+	// of the access held directly on the controllers. If this is zero
+	// access is only audited when requested by an administrator.
+	ControllerAccessAuditPeriod time.Duration
+
+	// DataRetention configures how long the historical data JIMM
+	// accumulates is kept, see jimm.RetentionPolicy. If no dataset has
+	// a retention period no data is pruned.
+	DataRetention jimm.RetentionPolicy
+
+	// DataRetentionPeriod is the period between runs of the data
+	// retention pruning. If this is zero DefaultDataRetentionPeriod is
+	// used.
+	DataRetentionPeriod time.Duration
+
+	// CacheTTL is the time for which cloud, controller and group information
+	// read from the database is cached. If this is zero the information
+	// is not cached.
+	CacheTTL time.Duration
+
+	// ModelAccessCacheTTL is the time for which the result of a
+	// successful model access check is cached. If this is zero model
+	// access checks are not cached.
+	ModelAccessCacheTTL time.Duration
+
+	// ModelDNSDomain is the DNS domain under which every model is given
+	// a name of the form <model-uuid>.<ModelDNSDomain>. Requests
+	// addressed to such a name, either via TLS SNI or the Host header,
+	// are routed to the model's endpoints without the /model/<uuid>
+	// path prefix. If this is empty models are only reachable through
+	// their /model/<uuid> paths.
+	ModelDNSDomain string
+
+	// Quotas holds the limits on the resources used by each user's
+	// models, see jimm.QuotaLimits.
+	Quotas jimm.QuotaLimits
+
+	// ConfirmationPeriod is the time for which a confirmation of a
+	// user's identity, with a TOTP code or a recently issued session
+	// token, allows sensitive operations. If this is zero sensitive
+	// operations do not require confirmation.
+	ConfirmationPeriod time.Duration
+
+	// MaxControllerModels is the maximum number of models a controller
+	// may host for migration prechecks to consider it to have capacity
+	// for another model. If this is zero there is no limit.
+	MaxControllerModels int
+
+	// AccessRequestWebhookURL is the URL access request events are
+	// posted to when access requests are made, approved or denied. If
+	// this is empty no notifications are sent. Events are signed once a
+	// secret has been rotated for the access-requests webhook.
+	AccessRequestWebhookURL string
+
+	// NotificationChannels configures the channels operators are
+	// notified on when controllers become unavailable or recover, cloud
+	// credentials repeatedly fail to update on controllers, controller
+	// model credentials are invalid or expiring, or required database
+	// indexes are missing. If this is empty no notifications are sent.
+	NotificationChannels []notify.ChannelConfig
+
+	// GroupSyncSCIMURL is the base URL of the SCIM service group
+	// memberships are synchronised from. If this is empty groups are
+	// not synchronised.
+	GroupSyncSCIMURL string
+
+	// GroupSyncSCIMToken is the bearer token used to authenticate with
+	// the SCIM service.
+	GroupSyncSCIMToken string
+
+	// GroupSyncSCIMMemberAttribute is the attribute of SCIM group
+	// members holding the name they authenticate to JIMM with, see
+	// groupsync.SCIMDirectory.
+	GroupSyncSCIMMemberAttribute string
+
+	// GroupSyncMappings holds the rules mapping directory groups onto
+	// JIMM groups.
+	GroupSyncMappings []groupsync.Mapping
+
+	// GroupSyncPeriod is the period between scheduled synchronisations
+	// of group memberships. If this is zero groups are only
+	// synchronised when requested by an administrator.
+	GroupSyncPeriod time.Duration
+
+	// GroupSyncCredentialGroup is the name of the group whose shared
+	// cloud credentials are used for the models of users who leave all
+	// synchronised groups, see jimm.GroupSyncConfig. If this is empty
+	// models are not rebound.
+	GroupSyncCredentialGroup string
+
+	// GroupSyncMaxRemovals is the largest number of group memberships
+	// a synchronisation removes unless it is forced, see
+	// jimm.GroupSyncConfig.
+	GroupSyncMaxRemovals int
+
+	// ControllerProfileCapturePeriod is the period between scheduled
+	// captures of the controllers' bootstrap profiles. If this is zero
+	// profiles are only captured when controllers are added.
+	ControllerProfileCapturePeriod time.Duration
+
+	// ControllerCredentialCheckPeriod is the period between scheduled
+	// checks of the cloud credentials used by the controller models. If
+	// this is zero the credentials are only recorded when controllers
+	// are added or their credentials rotated.
+	ControllerCredentialCheckPeriod time.Duration
+
+	// ControllerCredentialExpiryWarning is the period before a
+	// controller model credential expires in which it is reported as
+	// expiring, see jimm.JIMM.ControllerCredentialExpiryWarning.
+	ControllerCredentialExpiryWarning time.Duration
+
+	// ControllerCertificateExpiryWarning is the period before a
+	// controller's certificate expires in which it is reported as
+	// expiring, see jimm.JIMM.ControllerCertificateExpiryWarning.
+	ControllerCertificateExpiryWarning time.Duration
+
+	// IdentityCaseFold folds the names of incoming identities to lower
+	// case, see dbmodel.IdentityNormalizer.
+	IdentityCaseFold bool
+
+	// ControllerServiceUsers makes JIMM provision a dedicated service
+	// user on the controllers it adds, see
+	// jimm.JIMM.ControllerServiceUsers.
+	ControllerServiceUsers bool
+
+	// IdentityDomainAliases maps the domains of incoming identity names
+	// to the domain they are an alias of, see dbmodel.IdentityNormalizer.
+	IdentityDomainAliases map[string]string
+
+	// ModelIdlePeriod is the time without unit changes or user
+	// connections after which a model is considered idle, see
+	// jimm.JIMM.ModelIdlePeriod.
+	ModelIdlePeriod time.Duration
+
+	// NotifyIdleModels enables a daily notification about each idle
+	// model, naming the model's owner.
+	NotifyIdleModels bool
+
+	// ModelDestroyWindow is the time a requested model destroy is left
+	// pending before the model is destroyed, see
+	// jimm.JIMM.ModelDestroyWindow. If this is zero models are destroyed
+	// immediately.
+	ModelDestroyWindow time.Duration
+
+	// DeltaExportURL is the URL of the NATS server the watcher deltas of
+	// the models managed by JIMM are exported to. If this is empty
+	// deltas are not exported.
+	DeltaExportURL string
+
+	// DeltaExportSubject is the prefix of the subjects deltas are
+	// exported to, see jimm.DeltaExporter.
+	DeltaExportSubject string
+
+	// DeltaExportBufferSize is the number of deltas that may be queued
+	// for export before further deltas are dropped.
+	DeltaExportBufferSize int
+
+	// DisableDatabaseIndexBuild disables building the database indexes
+	// required by JIMM's frequently run queries when they are found to
+	// be missing at startup. Missing indexes are still reported.
+	DisableDatabaseIndexBuild bool
+
+	// ModelSnapshotPeriod is the period between snapshots of the
+	// resources used by each model, which are kept to graph the history
+	// of a model's resource usage. Snapshots are stored with an hourly
+	// resolution so this should be no longer than an hour. If this is
+	// zero no snapshots are recorded.
+	ModelSnapshotPeriod time.Duration
+
+	// IdempotencyWindow is the time for which JIMM remembers the
+	// responses to mutating REST requests made with an Idempotency-Key
+	// header, retries with the same key within the window receive the
+	// original response. If this is zero idempotency keys are ignored.
+	IdempotencyWindow time.Duration
+
+	// MaxOfferConsumers is the maximum number of models that may
+	// consume any application offer, see jimm.JIMM.MaxOfferConsumers. If
+	// this is zero only the limits set on the offers apply.
+	MaxOfferConsumers int
+
+	// MaxInlineResultSize is the largest size, in bytes, of the result
+	// of an expensive call, such as a model dump or bundle export, that
+	// is returned inline. Larger results are made available for download
+	// from https://<PublicDNSName>/downloads instead, or rejected if no
+	// PublicDNSName is set. If this is zero results are always returned
+	// inline.
+	MaxInlineResultSize int
+
+	// ResultDownloadTTL is the time for which an oversized result may be
+	// downloaded. If this is zero a default of 15 minutes is used.
+	ResultDownloadTTL time.Duration
+
+	// ObjectStore configures the S3-compatible object store holding the
+	// artifacts JIMM produces. If no bucket is configured oversized
+	// results are kept in the database and audit log entries are pruned
+	// without being exported.
+	ObjectStore ObjectStoreParams
+
+	// SessionIdleTimeout is the time after which an API session with no
+	// activity is removed from the session records. Sessions are
+	// normally removed when their connection closes, this removes those
+	// left behind by a replica that stopped. If this is zero a default
+	// of 24 hours is used.
+	SessionIdleTimeout time.Duration
+
+	// MigrationTimeout is the time after which a model migration whose
+	// outcome cannot be determined is abandoned and reported. If this is
+	// zero jimm.DefaultMigrationTimeout is used.
+	MigrationTimeout time.Duration
+
+	// InvalidationBus, if set, is used to share cache invalidations
+	// between the JIMM replicas. If this is nil notifications on the
+	// JIMM database are used.
+	InvalidationBus jimm.InvalidationBus
+
+	// ControllerMetricsPrefixes holds the name prefixes of the metrics,
+	// for example "juju_mgo_" or "juju_apiserver_connections", that are
+	// scraped from each controller and re-exported at
+	// /controller-metrics. If this is empty the endpoint is not served.
+	ControllerMetricsPrefixes []string
+
+	// ControllerMetricLabels and ModelMetricLabels hold the labels, such
+	// as "tier" or "cloud", attached to the metrics exported about
+	// controllers and models, see jimm.NewMetricLabeler.
+	ControllerMetricLabels []string
+	ModelMetricLabels      []string
+
+	// MetricLabelMaxValues is the maximum number of distinct values each
+	// controller or model metric label may take, further values are
+	// reported as "other". If this is zero a default limit is used.
+	MetricLabelMaxValues int
+
+	// WatcherPerModelMetrics enables the watcher gauges that report the
+	// number of units, machines, applications and offers in each model,
+	// rather than only the totals for each controller. This adds a
+	// series per model to the exported metrics.
+	WatcherPerModelMetrics bool
+
+	// WatcherModelReconcileInterval is the interval at which the models
+	// tracked by the watcher of each controller are reconciled with the
+	// database. If this is zero a default interval is used.
+	WatcherModelReconcileInterval time.Duration
+
+	// CharmhubURL is the URL of the Charmhub API used to check the
+	// charms deployed through JIMM and to report applications using
+	// outdated charms. If this is empty charms are not checked.
+	CharmhubURL string
+
+	// CharmPolicy holds the policy applied to the charms deployed
+	// through JIMM. It is only enforced if CharmhubURL is set.
+	CharmPolicy jimm.CharmPolicy
+
+	// TunablesFile is the path of the YAML file holding the settings
+	// that may be reloaded without a restart, see jimm.Tunables. Values
+	// in the file override those in these parameters. If this is empty
+	// the settings cannot be reloaded.
+	TunablesFile string
+
+	// CredentialUpdateConcurrency is the maximum number of controllers
+	// a cloud credential is updated on at once, see
+	// jimm.JIMM.CredentialUpdateConcurrency.
+	CredentialUpdateConcurrency int
+
+	// CredentialPropagationPolicies holds the credential propagation
+	// policy of each cloud, see jimm.JIMM.CredentialPropagationPolicies.
+	CredentialPropagationPolicies map[string]jimm.CredentialPropagationPolicy
+
+	// CredentialUpdateRetryPeriod is the period between retries of the
+	// cloud credential updates that failed on some controllers. If this
+	// is zero the failed updates are retried every minute.
+	CredentialUpdateRetryPeriod time.Duration
+
+	// CredentialRemovalDeadline is the time after a cloud credential is
+	// revoked by which its removal from every controller should be
+	// confirmed, see jimm.JIMM.CredentialRemovalDeadline. The removals
+	// are verified as often as failed credential updates are retried.
+	CredentialRemovalDeadline time.Duration
+
+	// JobSchedules holds schedules, keyed by job name, that replace the
+	// default schedules of the scheduled jobs. Each schedule is a
+	// standard five field cron expression, a descriptor such as @daily,
+	// or @every <duration>.
+	JobSchedules map[string]string
+
+	// LatencyProbePeriod is the period between probes of the latency of
+	// the cloud regions and controllers, used to place latency sensitive
+	// models. If this is zero latencies are not probed.
+	LatencyProbePeriod time.Duration
+
+	// ControllerCallCeiling is the longest a call to a controller may
+	// wait for a response before the connection is considered stuck and
+	// is recycled. If this is zero connections are never recycled.
+	ControllerCallCeiling time.Duration
 }
 
 // A Service is the implementation of a JIMM server.
@@ -198,6 +607,26 @@ type Service struct {
 
 	mux      *chi.Mux
 	cleanups []func() error
+
+	modelAccessResyncPeriod     time.Duration
+	modelAccessResyncApply      bool
+	controllerAccessAuditPeriod time.Duration
+	dataRetention               jimm.RetentionPolicy
+	dataRetentionPeriod         time.Duration
+	groupSyncPeriod             time.Duration
+	controllerCredentialPeriod  time.Duration
+	controllerProfilePeriod     time.Duration
+	buildDatabaseIndexes        bool
+	modelSnapshotPeriod         time.Duration
+	idempotencyWindow           time.Duration
+	sessionIdleTimeout          time.Duration
+	watcherPerModelMetrics      bool
+	watcherReconcileInterval    time.Duration
+	credentialRetryPeriod       time.Duration
+	latencyProbePeriod          time.Duration
+	invalidationBus             jimm.InvalidationBus
+	notifyIdleModels            bool
+	deltaExporter               *jimm.DeltaExporter
 }
 
 func (s *Service) JIMM() *jimm.JIMM {
@@ -216,6 +645,14 @@ func (s *Service) WatchControllers(ctx context.Context) error {
 	w := jimm.Watcher{
 		Database: s.jimm.Database,
 		Dialer:   s.jimm.Dialer,
+		Cache:    s.jimm.Cache,
+		Notifier: s.jimm.Notifier,
+		Health:   s.jimm.Health,
+
+		PerModelMetrics:             s.watcherPerModelMetrics,
+		MetricLabels:                s.jimm.MetricLabels,
+		DeltaExporter:               s.deltaExporter,
+		ModelCountReconcileInterval: s.watcherReconcileInterval,
 	}
 	return w.Watch(ctx, 10*time.Minute)
 }
@@ -229,6 +666,7 @@ func (s *Service) WatchModelSummaries(ctx context.Context) error {
 		Database: s.jimm.Database,
 		Dialer:   s.jimm.Dialer,
 		Pubsub:   s.jimm.Pubsub,
+		Cache:    s.jimm.Cache,
 	}
 	return w.WatchAllModelSummaries(ctx, 10*time.Minute)
 }
@@ -242,20 +680,189 @@ func (s *Service) StartJWKSRotator(ctx context.Context, checkRotateRequired <-ch
 	return s.jimm.JWKService.StartJWKSRotator(ctx, checkRotateRequired, initialRotateRequiredTime)
 }
 
-// MonitorResources periodically updates metrics.
-func (s *Service) MonitorResources(ctx context.Context) {
-	s.jimm.UpdateMetrics(ctx)
-	ticker := time.NewTicker(5 * time.Minute)
+// RunCacheInvalidationBus shares cache invalidations with the other
+// JIMM replicas until the given context is canceled. If the connection
+// to the invalidation bus fails it is retried after a short delay.
+func (s *Service) RunCacheInvalidationBus(ctx context.Context) {
+	for {
+		err := s.jimm.Cache.RunInvalidationBus(ctx, s.invalidationBus)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			zapctx.Error(ctx, "cache invalidation bus failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// ObjectStoreParams holds the parameters of the S3-compatible object
+// store, such as Amazon S3, MinIO or OpenStack Swift, holding the
+// artifacts JIMM produces. The credentials used to access the store are
+// read from vault.
+type ObjectStoreParams struct {
+	// Endpoint is the URL of the object store's S3 API.
+	Endpoint string
+
+	// Region is the region of the bucket. If this is empty us-east-1 is
+	// used.
+	Region string
+
+	// Bucket is the name of the bucket holding the artifacts.
+	Bucket string
+
+	// PathStyle addresses the bucket in the path of request URLs rather
+	// than the host name, as most MinIO and Swift deployments require.
+	PathStyle bool
+
+	// Lifecycle holds the time after which the artifacts of each kind
+	// are expired by the object store.
+	Lifecycle objectstore.LifecyclePolicy
+}
+
+// DefaultDataRetentionPeriod is the period between runs of the data
+// retention pruning if no period is configured.
+const DefaultDataRetentionPeriod = time.Hour
+
+// ProbeLatencies periodically measures the latency of the cloud regions
+// and controllers, see jimm.ProbeLatencies. The measurements are held in
+// memory so every JIMM server probes, not only the leader. If latency
+// probing is not configured ProbeLatencies returns immediately.
+func (s *Service) ProbeLatencies(ctx context.Context) {
+	if s.jimm.Latency == nil {
+		return
+	}
+	probe := func() {
+		if err := s.jimm.ProbeLatencies(ctx); err != nil {
+			zapctx.Error(ctx, "failed to probe latencies", zap.Error(err))
+		}
+	}
+	probe()
+	ticker := time.NewTicker(s.latencyProbePeriod)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			s.jimm.UpdateMetrics(ctx)
+			probe()
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// LoadTunables loads the tunables from the configured file, see
+// jimm.LoadTunables. If no tunables file is configured LoadTunables does
+// nothing.
+func (s *Service) LoadTunables(ctx context.Context) error {
+	if s.jimm.TunablesFile == "" {
+		return nil
+	}
+	_, err := s.jimm.LoadTunables(ctx, s.jimm.ResourceTag())
+	return err
+}
+
+// WatchTunables reloads the tunables every time a value is received on
+// the given channel, typically when the process receives SIGHUP. Errors
+// are logged and leave the current settings in place.
+func (s *Service) WatchTunables(ctx context.Context, reload <-chan os.Signal) {
+	for {
+		select {
+		case <-reload:
+			if err := s.LoadTunables(ctx); err != nil {
+				zapctx.Error(ctx, "failed to reload tunables", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// DrainMaintenanceControllers periodically drains the client sessions
+// this server proxies to controllers in maintenance, see
+// jimm.DrainMaintenanceControllers. It runs on every JIMM server, as
+// each server proxies its own sessions.
+func (s *Service) DrainMaintenanceControllers(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.jimm.DrainMaintenanceControllers(ctx); err != nil {
+				zapctx.Error(ctx, "failed to drain controllers in maintenance", zap.Error(err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunLeaderWorkers runs the background workers that must only run on a
+// single JIMM replica: the controller watcher, the JWKS rotator, the
+// database index check, the document upgrade, the secret key rotation,
+// if configured, and every scheduled job, see scheduledJobs. Each worker
+// runs on whichever replica holds its lease in the database, should that
+// replica stop another replica takes over. RunLeaderWorkers finishes
+// when the given context is canceled.
+func (s *Service) RunLeaderWorkers(ctx context.Context) error {
+	const op = errors.Op("RunLeaderWorkers")
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return errors.E(op, err)
+	}
+	e := jimm.LeaderElector{
+		Database: s.jimm.Database,
+		Holder:   hostname + "-" + uuid.NewString(),
+	}
+	// Deletes dead/dying models, updates model config.
+	e.Register("controller-watcher", s.WatchControllers)
+	e.Register("jwks-rotator", func(ctx context.Context) error {
+		ticker := time.NewTicker(time.Hour)
+		context.AfterFunc(ctx, ticker.Stop)
+		return s.StartJWKSRotator(ctx, ticker.C, time.Now().UTC().AddDate(0, 3, 0))
+	})
+	// Checks, and builds, the required database indexes once, the
+	// worker is restarted if a build fails.
+	e.Register("database-indexes", func(ctx context.Context) error {
+		return s.jimm.CheckDatabaseIndexes(ctx, s.buildDatabaseIndexes)
+	})
+	// Upgrades documents stored in an older format once, the worker is
+	// restarted if it fails.
+	e.Register("document-upgrade", func(ctx context.Context) error {
+		upgraded, err := s.jimm.UpgradeDocuments(ctx, 0, func(p jimm.DocumentUpgradeProgress) {
+			zapctx.Info(ctx, "upgrading documents", zap.String("kind", p.Kind), zap.Int64("upgraded", p.Upgraded), zap.Int64("total", p.Total))
+		})
+		if err != nil {
+			return err
+		}
+		fields := make([]zap.Field, 0, len(upgraded))
+		for kind, n := range upgraded {
+			fields = append(fields, zap.Int64(kind, n))
+		}
+		zapctx.Info(ctx, "documents upgraded", fields...)
+		return nil
+	})
+	if s.jimm.Database.Encrypter != nil {
+		// Re-encrypts secrets with the primary key once, the worker is
+		// restarted if it fails.
+		e.Register("secret-key-rotation", func(ctx context.Context) error {
+			n, err := s.jimm.Database.RewrapSecrets(ctx)
+			if err != nil {
+				return err
+			}
+			zapctx.Info(ctx, "secrets encrypted with primary key", zap.Int("updated", n))
+			return nil
+		})
+	}
+	s.jimm.Scheduler.Register(&e)
+	zapctx.Info(ctx, "starting leader-elected workers", zap.String("holder", e.Holder))
+	return e.Run(ctx)
+}
+
 // Cleanup cleans up resources that need to be released on shutdown.
 func (s *Service) Cleanup() {
 	// Iterating over clean up function in reverse-order to avoid early clean ups.
@@ -278,6 +885,17 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 
 	s := new(Service)
 	s.mux = chi.NewRouter()
+	s.modelAccessResyncPeriod = p.ModelAccessResyncPeriod
+	s.modelAccessResyncApply = p.ModelAccessResyncApply
+	s.controllerAccessAuditPeriod = p.ControllerAccessAuditPeriod
+	if err := p.DataRetention.Validate(); err != nil {
+		return nil, errors.E(op, err)
+	}
+	s.dataRetention = p.DataRetention
+	s.dataRetentionPeriod = p.DataRetentionPeriod
+	if s.dataRetentionPeriod <= 0 {
+		s.dataRetentionPeriod = DefaultDataRetentionPeriod
+	}
 
 	// Setup all dependency services
 
@@ -289,7 +907,120 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 		p.ControllerUUID = controllerUUID.String()
 	}
 	s.jimm.UUID = p.ControllerUUID
-	s.jimm.Pubsub = &pubsub.Hub{MaxConcurrency: 50}
+	s.jimm.Quotas = p.Quotas
+	s.jimm.MaxControllerModels = p.MaxControllerModels
+	s.jimm.TunablesFile = p.TunablesFile
+	s.jimm.MaxOfferConsumers = p.MaxOfferConsumers
+	s.jimm.MaxInlineResultSize = p.MaxInlineResultSize
+	s.jimm.ResultDownloadTTL = p.ResultDownloadTTL
+	if p.MaxInlineResultSize > 0 && p.PublicDNSName != "" {
+		s.jimm.ResultDownloadURL = "https://" + strings.TrimPrefix(p.PublicDNSName, "https://") + "/downloads"
+	}
+	s.jimm.ControllerCredentialExpiryWarning = p.ControllerCredentialExpiryWarning
+	s.jimm.ControllerCertificateExpiryWarning = p.ControllerCertificateExpiryWarning
+	s.jimm.ModelIdlePeriod = p.ModelIdlePeriod
+	s.jimm.ModelDestroyWindow = p.ModelDestroyWindow
+	if p.IdentityCaseFold || len(p.IdentityDomainAliases) > 0 {
+		dbmodel.SetIdentityNormalizer(&dbmodel.IdentityNormalizer{
+			CaseFold:      p.IdentityCaseFold,
+			DomainAliases: p.IdentityDomainAliases,
+		})
+	}
+	s.notifyIdleModels = p.NotifyIdleModels
+	s.controllerCredentialPeriod = p.ControllerCredentialCheckPeriod
+	s.controllerProfilePeriod = p.ControllerProfileCapturePeriod
+	s.buildDatabaseIndexes = !p.DisableDatabaseIndexBuild
+	s.modelSnapshotPeriod = p.ModelSnapshotPeriod
+	s.idempotencyWindow = p.IdempotencyWindow
+	s.sessionIdleTimeout = p.SessionIdleTimeout
+	if s.sessionIdleTimeout <= 0 {
+		s.sessionIdleTimeout = 24 * time.Hour
+	}
+	s.jimm.MigrationTimeout = p.MigrationTimeout
+	s.watcherPerModelMetrics = p.WatcherPerModelMetrics
+	s.watcherReconcileInterval = p.WatcherModelReconcileInterval
+	s.jimm.CredentialUpdateConcurrency = p.CredentialUpdateConcurrency
+	s.jimm.CredentialPropagationPolicies = p.CredentialPropagationPolicies
+	s.jimm.CredentialRemovalDeadline = p.CredentialRemovalDeadline
+	s.jimm.ControllerServiceUsers = p.ControllerServiceUsers
+	s.credentialRetryPeriod = p.CredentialUpdateRetryPeriod
+	if s.credentialRetryPeriod <= 0 {
+		s.credentialRetryPeriod = time.Minute
+	}
+	if p.LatencyProbePeriod > 0 {
+		s.latencyProbePeriod = p.LatencyProbePeriod
+		s.jimm.Latency = jimm.NewLatencyProber(0)
+	}
+	if p.CharmhubURL != "" {
+		s.jimm.Charmhub = &charmhub.Client{URL: p.CharmhubURL}
+		s.jimm.CharmPolicy = p.CharmPolicy
+	}
+	if p.AccessRequestWebhookURL != "" {
+		s.jimm.AccessRequestNotifier = &jimm.WebhookNotifier{
+			URL:    p.AccessRequestWebhookURL,
+			Name:   jimm.AccessRequestWebhook,
+			Signer: &s.jimm,
+		}
+	}
+	if len(p.ControllerMetricLabels) > 0 || len(p.ModelMetricLabels) > 0 {
+		labeler, err := jimm.NewMetricLabeler(p.ControllerMetricLabels, p.ModelMetricLabels, p.MetricLabelMaxValues)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		servermon.SetEntityLabels(labeler.ControllerLabelNames(), labeler.ModelLabelNames())
+		s.jimm.MetricLabels = labeler
+	}
+	if p.DeltaExportURL != "" {
+		stream, err := eventstream.NewNATS(p.DeltaExportURL, nil)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		s.deltaExporter = &jimm.DeltaExporter{
+			Stream:        stream,
+			SubjectPrefix: p.DeltaExportSubject,
+			BufferSize:    p.DeltaExportBufferSize,
+		}
+	}
+	if len(p.NotificationChannels) > 0 {
+		notifier, err := notify.NewWithSigner(p.NotificationChannels, &s.jimm)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		s.jimm.Notifier = notifier
+	}
+	if p.GroupSyncSCIMURL != "" {
+		for _, m := range p.GroupSyncMappings {
+			if err := m.Validate(); err != nil {
+				return nil, errors.E(op, err)
+			}
+		}
+		s.jimm.GroupSync = jimm.GroupSyncConfig{
+			Name: "scim",
+			Directory: &groupsync.SCIMDirectory{
+				URL:             p.GroupSyncSCIMURL,
+				Token:           p.GroupSyncSCIMToken,
+				MemberAttribute: p.GroupSyncSCIMMemberAttribute,
+			},
+			Mappings:        p.GroupSyncMappings,
+			CredentialGroup: p.GroupSyncCredentialGroup,
+			MaxRemovals:     p.GroupSyncMaxRemovals,
+		}
+		s.groupSyncPeriod = p.GroupSyncPeriod
+	}
+	// Subscribers that fall this far behind the published model
+	// summaries are evicted rather than allowed to hold up the hub.
+	s.jimm.Pubsub = &pubsub.Hub{MaxConcurrency: 50, MaxPending: 1000}
+	s.jimm.Cache = jimm.NewResponseCache(jimm.ResponseCacheParams{
+		CloudTTL:       p.CacheTTL,
+		ControllerTTL:  p.CacheTTL,
+		GroupTTL:       p.CacheTTL,
+		ModelAccessTTL: p.ModelAccessCacheTTL,
+	})
+	s.jimm.Health = jimm.NewControllerHealth(jimm.ControllerHealthParams{})
+	s.invalidationBus = p.InvalidationBus
+	if s.invalidationBus == nil {
+		s.invalidationBus = jimm.DatabaseInvalidationBus{Database: &s.jimm.Database}
+	}
 
 	if p.DSN == "" {
 		return nil, errors.E(op, "missing DSN")
@@ -300,6 +1031,15 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
+	if err := configureDBPool(s.jimm.Database.DB, p.DBPool); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if faults.Enabled {
+		zapctx.Warn(ctx, "fault injection is enabled, this build must not be used in production")
+	}
+	if err := faults.RegisterDatabaseCallbacks(s.jimm.Database.DB); err != nil {
+		return nil, errors.E(op, err)
+	}
 	if err := s.jimm.Database.Migrate(ctx, false); err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -330,6 +1070,10 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 		return nil, errors.E(op, err)
 	}
 
+	if err := s.setupObjectStore(ctx, p); err != nil {
+		return nil, errors.E(op, err)
+	}
+
 	sessionStore, err := s.setupSessionStore(ctx, p.CookieSessionKey)
 	if err != nil {
 		return nil, errors.E(op, err)
@@ -379,6 +1123,10 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	s.jimm.Dialer = &jujuclient.Dialer{
 		ControllerCredentialsStore: s.jimm.CredentialStore,
 		JWTService:                 s.jimm.JWTService,
+		CallCeiling:                p.ControllerCallCeiling,
+		OnRecycle: func(rc jujuclient.RecycledConnection) {
+			s.jimm.RecordRecycledConnection(rc.ControllerUUID, rc.Model, rc.Facade, rc.Method, rc.Elapsed, rc.Inflight)
+		},
 	}
 
 	if !p.DisableConnectionCache {
@@ -401,6 +1149,7 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 		AllowCredentials: true,
 	})
 	s.mux.Use(corsOpts.Handler)
+	s.mux.Use(middleware.NewModelHostRouter(p.ModelDNSDomain).Handler)
 
 	// Setup all HTTP handlers.
 	mountHandler := func(path string, h jimmhttp.JIMMHttpHandler) {
@@ -408,8 +1157,27 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	}
 
 	s.mux.Mount("/metrics", promhttp.Handler())
+	if len(p.ControllerMetricsPrefixes) > 0 {
+		mountHandler(
+			"/controller-metrics",
+			jimmhttp.NewControllerMetricsHandler(&s.jimm, p.ControllerMetricsPrefixes),
+		)
+	}
 
-	s.mux.Mount("/rebac", middleware.AuthenticateRebac("/rebac", rebacBackend.Handler(""), &s.jimm))
+	idempotency := &middleware.Idempotency{
+		Store:  &s.jimm.Database,
+		Window: p.IdempotencyWindow,
+	}
+	s.mux.Mount("/rebac", middleware.AuthenticateRebac("/rebac", idempotency.Handler(rebacBackend.Handler("")), &s.jimm))
+
+	// apiDoc describes the REST API, endpoints are added as their
+	// handlers are mounted.
+	apiDoc := openapi.NewDocument(openapi.Info{
+		Title:       "JIMM",
+		Description: "The JIMM REST API.",
+		Version:     version.VersionInfo.Version,
+	})
+	s.mux.Handle("/swagger.json", apiDoc)
 
 	mountHandler(
 		"/debug",
@@ -419,10 +1187,59 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 			},
 		),
 	)
+	apiDoc.AddEndpoint(openapi.Endpoint{
+		Method:   http.MethodGet,
+		Path:     "/debug/info",
+		Summary:  "Returns the version of the server.",
+		Response: version.VersionInfo,
+	})
+	apiDoc.AddEndpoint(openapi.Endpoint{
+		Method:   http.MethodGet,
+		Path:     "/debug/status",
+		Summary:  "Returns the results of the server's status checks.",
+		Response: map[string]interface{}{},
+	})
 	mountHandler(
 		"/.well-known",
 		wellknownapi.NewWellKnownHandler(s.jimm.CredentialStore),
 	)
+	apiDoc.AddEndpoint(openapi.Endpoint{
+		Method:   http.MethodGet,
+		Path:     "/.well-known/jwks.json",
+		Summary:  "Returns the JSON Web Key Set used to verify the tokens JIMM presents to controllers.",
+		Response: map[string]interface{}{},
+	})
+	mountHandler(
+		"/catalog",
+		jimmhttp.NewPublicCatalogHandler(&s.jimm),
+	)
+	apiDoc.AddEndpoint(openapi.Endpoint{
+		Method:   http.MethodGet,
+		Path:     "/catalog",
+		Summary:  "Returns the models and application offers listed in the public catalog. No authentication is required.",
+		Response: apiparams.PublicCatalog{},
+	})
+
+	mountHandler(
+		"/downloads",
+		jimmhttp.NewResultDownloadHandler(&s.jimm),
+	)
+	apiDoc.AddEndpoint(openapi.Endpoint{
+		Method:  http.MethodGet,
+		Path:    "/downloads/{token}",
+		Summary: "Returns a result that was too large to return inline. The token in the download URL is the only authentication required.",
+	})
+
+	mountHandler(
+		"/operations",
+		jimmhttp.NewOperationHandler(&s.jimm),
+	)
+	apiDoc.AddEndpoint(openapi.Endpoint{
+		Method:   http.MethodGet,
+		Path:     "/operations/{id}",
+		Summary:  "Returns the progress of a long-running operation. The operation ID is the only authentication required.",
+		Response: apiparams.Operation{},
+	})
 
 	if p.DashboardFinalRedirectURL == "" {
 		zapctx.Warn(ctx, "OAuth handler not enabled, due to unset dashboard redirect URL")
@@ -439,6 +1256,29 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 			jimmhttp.AuthResourceBasePath,
 			oauthHandler,
 		)
+		apiDoc.AddEndpoint(openapi.Endpoint{
+			Method:  http.MethodGet,
+			Path:    jimmhttp.AuthResourceBasePath + jimmhttp.LoginEndpoint,
+			Summary: "Starts a browser login, redirecting to the identity provider.",
+			Status:  http.StatusTemporaryRedirect,
+		})
+		apiDoc.AddEndpoint(openapi.Endpoint{
+			Method:  http.MethodGet,
+			Path:    jimmhttp.AuthResourceBasePath + jimmhttp.CallbackEndpoint,
+			Summary: "Completes a browser login, redirecting to the dashboard.",
+			Status:  http.StatusPermanentRedirect,
+		})
+		apiDoc.AddEndpoint(openapi.Endpoint{
+			Method:  http.MethodGet,
+			Path:    jimmhttp.AuthResourceBasePath + jimmhttp.LogOutEndpoint,
+			Summary: "Ends the browser session.",
+		})
+		apiDoc.AddEndpoint(openapi.Endpoint{
+			Method:   http.MethodGet,
+			Path:     jimmhttp.AuthResourceBasePath + jimmhttp.WhoAmIEndpoint,
+			Summary:  "Returns the identity of the logged in user.",
+			Response: apiparams.WhoamiResponse{},
+		})
 	}
 
 	macaroonDischarger, err := s.setupDischarger(p)
@@ -447,9 +1287,28 @@ func NewService(ctx context.Context, p Params) (*Service, error) {
 	}
 	s.mux.Handle(localDischargePath+"/*", discharger.GetDischargerMux(macaroonDischarger, localDischargePath))
 
+	if err := jujuapi.ValidateRedactedModelFields(p.RedactedModelFields); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if err := s.setupScheduler(p.JobSchedules); err != nil {
+		return nil, errors.E(op, err)
+	}
+
 	params := jujuapi.Params{
-		ControllerUUID: p.ControllerUUID,
-		PublicDNSName:  p.PublicDNSName,
+		ControllerUUID:          p.ControllerUUID,
+		PublicDNSName:           p.PublicDNSName,
+		RedactedModelFields:     p.RedactedModelFields,
+		RedactedModelConfigKeys: p.RedactedModelConfigKeys,
+		FanOutSoftDeadline:      p.FanOutSoftDeadline,
+		ModelSummariesDeadline:  p.ModelSummariesDeadline,
+		ConfirmationPeriod:      p.ConfirmationPeriod,
+		FacadeDeprecations:      p.FacadeDeprecations,
+		RequestLimiter:          jimmRPC.NewRequestLimiter(p.MaxConcurrentRequests, p.RequestPriorityWeights),
+		ConnectionPolicy: &jimmhttp.ConnectionPolicy{
+			AllowedOrigins:    p.WebsocketAllowedOrigins,
+			MinClientVersion:  p.MinClientVersion,
+			BlockedUserAgents: p.BlockedUserAgents,
+		},
 	}
 
 	// Websockets require extra care when cookies are used for authentication
@@ -536,9 +1395,51 @@ func openDB(ctx context.Context, dsn string) (*gorm.DB, error) {
 	})
 }
 
+// configureDBPool sizes the connection pool of the given database using
+// the given parameters and reports the pool's utilization in the
+// servermon metrics.
+func configureDBPool(db *gorm.DB, p DBPoolParams) error {
+	if p.MaxOpenConns < 0 || p.MaxIdleConns < 0 || p.ConnMaxIdleTime < 0 {
+		return errors.E(errors.CodeServerConfiguration, "invalid database pool configuration")
+	}
+	if p.MaxOpenConns == 0 {
+		p.MaxOpenConns = DefaultDBMaxOpenConns
+	}
+	if p.MaxIdleConns == 0 {
+		p.MaxIdleConns = DefaultDBMaxIdleConns
+	}
+	if p.MaxIdleConns > p.MaxOpenConns {
+		p.MaxIdleConns = p.MaxOpenConns
+	}
+	if p.ConnMaxIdleTime == 0 {
+		p.ConnMaxIdleTime = DefaultDBConnMaxIdleTime
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(p.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(p.MaxIdleConns)
+	sqlDB.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+	servermon.SetDBPool(sqlDB)
+	return nil
+}
+
 func (s *Service) setupCredentialStore(ctx context.Context, p Params) error {
 	const op = errors.Op("newSecretStore")
 
+	if p.SecretEncryptionKeyFile != "" {
+		keys, err := envelope.LoadKeyFile(p.SecretEncryptionKeyFile)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		zapctx.Info(ctx, "using encrypted postgres for secret storage", zap.String("primary-key", keys.PrimaryKeyID()))
+		s.jimm.Database.Encrypter = &envelope.Encrypter{Keys: keys}
+		s.jimm.CredentialStore = &s.jimm.Database
+		return nil
+	}
+
 	// Only enable Postgres storage for secrets if explicitly enabled.
 	if p.InsecureSecretStorage {
 		zapctx.Warn(ctx, "using plaintext postgres for secret storage")
@@ -559,6 +1460,57 @@ func (s *Service) setupCredentialStore(ctx context.Context, p Params) error {
 	return errors.E(op, "jimm cannot start without a credential store")
 }
 
+// setupObjectStore configures the object store holding JIMM's artifacts,
+// if a bucket is configured, and applies its lifecycle policy.
+func (s *Service) setupObjectStore(ctx context.Context, p Params) error {
+	const op = errors.Op("setupObjectStore")
+
+	if p.ObjectStore.Bucket == "" {
+		return nil
+	}
+	if err := p.ObjectStore.Lifecycle.Validate(); err != nil {
+		return errors.E(op, err)
+	}
+	vs, ok := s.jimm.CredentialStore.(*vault.VaultStore)
+	if !ok {
+		cs, err := newVaultStore(ctx, p)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		if cs == nil {
+			return errors.E(op, "object store credentials require vault")
+		}
+		vs = cs.(*vault.VaultStore)
+	}
+	store, err := objectstore.NewS3Store(objectstore.S3Params{
+		Endpoint:  p.ObjectStore.Endpoint,
+		Region:    p.ObjectStore.Region,
+		Bucket:    p.ObjectStore.Bucket,
+		PathStyle: p.ObjectStore.PathStyle,
+		Credentials: func(ctx context.Context) (objectstore.Credentials, error) {
+			accessKeyID, secretAccessKey, err := vs.GetObjectStoreCredentials(ctx)
+			if err != nil {
+				return objectstore.Credentials{}, err
+			}
+			return objectstore.Credentials{
+				AccessKeyID:     accessKeyID,
+				SecretAccessKey: secretAccessKey,
+			}, nil
+		},
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	zapctx.Info(ctx, "using object store", zap.String("endpoint", p.ObjectStore.Endpoint), zap.String("bucket", p.ObjectStore.Bucket))
+	if err := store.ApplyLifecycle(ctx, p.ObjectStore.Lifecycle); err != nil {
+		// The artifacts are still stored, they are just kept until
+		// the lifecycle can be applied.
+		zapctx.Warn(ctx, "cannot apply object store lifecycle", zap.Error(err))
+	}
+	s.jimm.ObjectStore = store
+	return nil
+}
+
 func newVaultStore(ctx context.Context, p Params) (jimmcreds.CredentialStore, error) {
 	if p.VaultRoleID == "" || p.VaultRoleSecretID == "" {
 		return nil, nil
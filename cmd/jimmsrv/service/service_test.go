@@ -4,12 +4,14 @@ package service_test
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 
 	cofga "github.com/canonical/ofga"
@@ -67,6 +69,70 @@ func TestServiceDoesNotStartWithoutCredentialStore(t *testing.T) {
 	c.Assert(err, qt.ErrorMatches, "jimm cannot start without a credential store")
 }
 
+func TestServiceWithSecretEncryptionKeyFile(t *testing.T) {
+	c := qt.New(t)
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	p := jimmtest.NewTestJimmParams(c)
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	p.SecretEncryptionKeyFile = filepath.Join(c.TempDir(), "keys")
+	_, err = jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.ErrorMatches, `open .*keys: no such file or directory`)
+
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	err = os.WriteFile(p.SecretEncryptionKeyFile, []byte("key-1 "+key+"\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	svc, err := jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+}
+
+func TestDBPool(t *testing.T) {
+	c := qt.New(t)
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	p := jimmtest.NewTestJimmParams(c)
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	p.InsecureSecretStorage = true
+	p.DBPool = jimmsvc.DBPoolParams{
+		MaxOpenConns: 5,
+		MaxIdleConns: 10,
+	}
+	svc, err := jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+
+	sqlDB, err := svc.JIMM().Database.DB.DB()
+	c.Assert(err, qt.IsNil)
+	c.Check(sqlDB.Stats().MaxOpenConnections, qt.Equals, 5)
+
+	p.DBPool.MaxOpenConns = -1
+	_, err = jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.ErrorMatches, "invalid database pool configuration")
+}
+
+func TestJobSchedules(t *testing.T) {
+	c := qt.New(t)
+
+	_, _, cofgaParams, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	p := jimmtest.NewTestJimmParams(c)
+	p.OpenFGAParams = cofgaParamsToJIMMOpenFGAParams(*cofgaParams)
+	p.InsecureSecretStorage = true
+
+	// Data retention is not configured, so is not scheduled.
+	p.JobSchedules = map[string]string{"data-retention": "0 3 * * *"}
+	_, err = jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.ErrorMatches, `schedule given for unknown job "data-retention"`)
+
+	p.JobSchedules = map[string]string{"identity-session-expiry": "0 */2 * * *"}
+	svc, err := jimmsvc.NewService(context.Background(), p)
+	c.Assert(err, qt.IsNil)
+	defer svc.Cleanup()
+}
+
 func TestAuthenticator(t *testing.T) {
 	c := qt.New(t)
 
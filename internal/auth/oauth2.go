@@ -309,9 +309,11 @@ func (as *AuthenticationService) Email(idToken *oidc.IDToken) (string, error) {
 func (as *AuthenticationService) MintSessionToken(email string) (string, error) {
 	const op = errors.Op("auth.AuthenticationService.MintAccessToken")
 
+	now := time.Now()
 	token, err := jwt.NewBuilder().
 		Subject(email).
-		Expiration(time.Now().Add(as.sessionTokenExpiry)).
+		IssuedAt(now).
+		Expiration(now.Add(as.sessionTokenExpiry)).
 		Build()
 	if err != nil {
 		return "", errors.E(op, err, "failed to build access token")
@@ -339,6 +341,7 @@ func (as *AuthenticationService) VerifySessionToken(token string) (_ jwt.Token,
 	errorFn := func(message string) error {
 		return errors.E(op, message, errors.CodeSessionTokenInvalid)
 	}
+	defer servermon.DurationObserver(servermon.AuthenticationDurationHistogram, "VerifySessionToken")()
 	defer func() {
 		if err != nil {
 			servermon.AuthenticationFailCount.WithLabelValues("VerifySessionToken").Inc()
@@ -406,6 +409,7 @@ func (as *AuthenticationService) UpdateIdentity(ctx context.Context, email strin
 
 // VerifyClientCredentials verifies the provided client ID and client secret.
 func (as *AuthenticationService) VerifyClientCredentials(ctx context.Context, clientID string, clientSecret string) (err error) {
+	defer servermon.DurationObserver(servermon.AuthenticationDurationHistogram, "VerifyClientCredentials")()
 	defer func() {
 		if err != nil {
 			servermon.AuthenticationFailCount.WithLabelValues("VerifyClientCredentials").Inc()
@@ -472,6 +476,7 @@ func (as *AuthenticationService) CreateBrowserSession(
 // is deleted and an error is returned.
 func (as *AuthenticationService) AuthenticateBrowserSession(ctx context.Context, w http.ResponseWriter, req *http.Request) (_ context.Context, err error) {
 	const op = errors.Op("auth.AuthenticationService.AuthenticateBrowserSession")
+	defer servermon.DurationObserver(servermon.AuthenticationDurationHistogram, "AuthenticateBrowserSession")()
 	defer func() {
 		if err != nil {
 			servermon.AuthenticationFailCount.WithLabelValues("AuthenticateBrowserSession").Inc()
@@ -0,0 +1,71 @@
+// Copyright 2024 Canonical.
+
+package auth_test
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/auth"
+)
+
+func TestTOTPCode(t *testing.T) {
+	c := qt.New(t)
+
+	// Test vectors from RFC 6238 appendix B, truncated to six digits.
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		time int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, test := range tests {
+		c.Check(auth.TOTPCode(key, uint64(test.time/30)), qt.Equals, test.code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	c := qt.New(t)
+
+	secret, err := auth.GenerateTOTPSecret()
+	c.Assert(err, qt.IsNil)
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	c.Assert(err, qt.IsNil)
+	c.Check(key, qt.HasLen, 20)
+
+	now := time.Unix(1700000000, 0)
+	step := uint64(now.Unix() / 30)
+	c.Check(auth.ValidateTOTP(secret, auth.TOTPCode(key, step), now), qt.IsTrue)
+	c.Check(auth.ValidateTOTP(secret, auth.TOTPCode(key, step-1), now), qt.IsTrue)
+	c.Check(auth.ValidateTOTP(secret, auth.TOTPCode(key, step+1), now), qt.IsTrue)
+	c.Check(auth.ValidateTOTP(secret, auth.TOTPCode(key, step-2), now), qt.IsFalse)
+	c.Check(auth.ValidateTOTP(secret, "12345", now), qt.IsFalse)
+	c.Check(auth.ValidateTOTP("not base32!", "123456", now), qt.IsFalse)
+
+	matched, ok := auth.MatchTOTP(secret, auth.TOTPCode(key, step-1), now)
+	c.Check(ok, qt.IsTrue)
+	c.Check(matched, qt.Equals, int64(step-1))
+	_, ok = auth.MatchTOTP(secret, auth.TOTPCode(key, step+2), now)
+	c.Check(ok, qt.IsFalse)
+}
+
+func TestTOTPURI(t *testing.T) {
+	c := qt.New(t)
+
+	u, err := url.Parse(auth.TOTPURI("JIMM", "alice@canonical.com", "ABCDEF"))
+	c.Assert(err, qt.IsNil)
+	c.Check(u.Scheme, qt.Equals, "otpauth")
+	c.Check(u.Host, qt.Equals, "totp")
+	c.Check(u.Path, qt.Equals, "/JIMM:alice@canonical.com")
+	c.Check(u.Query().Get("secret"), qt.Equals, "ABCDEF")
+	c.Check(u.Query().Get("issuer"), qt.Equals, "JIMM")
+}
@@ -0,0 +1,791 @@
+// Copyright 2024 Canonical.
+
+// Package cbor implements the subset of CBOR (RFC 8949) needed to carry
+// JIMM's JSON based API messages in a more compact binary form. Values
+// are encoded following the rules of encoding/json, so that a value
+// encoded in CBOR and decoded again holds the same data as it would had
+// it been sent as JSON.
+package cbor
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// CBOR major types.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Simple values and additional information used by the encoder and
+// decoder.
+const (
+	simpleFalse     = 20
+	simpleTrue      = 21
+	simpleNull      = 22
+	simpleUndefined = 23
+	infoFloat16     = 25
+	infoFloat32     = 26
+	infoFloat64     = 27
+	infoIndefinite  = 31
+	breakByte       = 0xff
+)
+
+// maxDepth is the maximum nesting of arrays and maps Decode accepts.
+const maxDepth = 1000
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	numberType        = reflect.TypeOf(json.Number(""))
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// Marshal returns the CBOR encoding of v. The encoding follows the rules
+// of json.Marshal: struct fields are named, omitted and promoted from
+// embedded structs as directed by their json tags, types implementing
+// json.Marshaler or encoding.TextMarshaler are encoded as the value they
+// marshal to, and nil slices, maps and pointers are encoded as null.
+// Byte slices are encoded as CBOR byte strings.
+func Marshal(v interface{}) ([]byte, error) {
+	var e encodeState
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+type encodeState struct {
+	buf []byte
+}
+
+// head writes the initial bytes of a data item with the given major
+// type and argument.
+func (e *encodeState) head(major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		e.buf = append(e.buf, major|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, major|25, byte(n>>8), byte(n))
+	case n <= math.MaxUint32:
+		e.buf = append(e.buf, major|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		e.buf = append(e.buf, major|27,
+			byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32),
+			byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func (e *encodeState) null() {
+	e.buf = append(e.buf, majorSimple<<5|simpleNull)
+}
+
+func (e *encodeState) text(s string) {
+	e.head(majorText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encodeState) int(n int64) {
+	if n < 0 {
+		e.head(majorNegInt, uint64(-1-n))
+		return
+	}
+	e.head(majorUint, uint64(n))
+}
+
+// float writes f using the smallest of the 32 and 64 bit encodings that
+// represents it exactly.
+func (e *encodeState) float(f float64) {
+	if f32 := float32(f); float64(f32) == f {
+		bits := math.Float32bits(f32)
+		e.buf = append(e.buf, majorSimple<<5|infoFloat32, byte(bits>>24), byte(bits>>16), byte(bits>>8), byte(bits))
+		return
+	}
+	bits := math.Float64bits(f)
+	e.buf = append(e.buf, majorSimple<<5|infoFloat64,
+		byte(bits>>56), byte(bits>>48), byte(bits>>40), byte(bits>>32),
+		byte(bits>>24), byte(bits>>16), byte(bits>>8), byte(bits))
+}
+
+func (e *encodeState) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.null()
+		return nil
+	}
+	t := v.Type()
+	if t == timeType {
+		// Avoid the round trip through JSON for the most common
+		// marshaler.
+		text, err := v.Interface().(time.Time).MarshalText()
+		if err != nil {
+			return errors.E(err)
+		}
+		e.text(string(text))
+		return nil
+	}
+	if t.Implements(jsonMarshalerType) {
+		return e.encodeJSONMarshaler(v)
+	}
+	if t.Kind() != reflect.Pointer && v.CanAddr() && reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return e.encodeJSONMarshaler(v.Addr())
+	}
+	if t.Implements(textMarshalerType) {
+		return e.encodeTextMarshaler(v)
+	}
+	if t.Kind() != reflect.Pointer && v.CanAddr() && reflect.PointerTo(t).Implements(textMarshalerType) {
+		return e.encodeTextMarshaler(v.Addr())
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf = append(e.buf, majorSimple<<5|simpleTrue)
+		} else {
+			e.buf = append(e.buf, majorSimple<<5|simpleFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.head(majorUint, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return errors.E(fmt.Sprintf("unsupported value: %v", f))
+		}
+		e.float(f)
+	case reflect.String:
+		if t == numberType {
+			return e.encodeNumber(json.Number(v.String()))
+		}
+		e.text(v.String())
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			e.null()
+			return nil
+		}
+		return e.encode(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.null()
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !t.Elem().Implements(jsonMarshalerType) && !t.Elem().Implements(textMarshalerType) {
+			e.head(majorBytes, uint64(v.Len()))
+			e.buf = append(e.buf, v.Bytes()...)
+			return nil
+		}
+		return e.encodeArray(v)
+	case reflect.Array:
+		return e.encodeArray(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.null()
+			return nil
+		}
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	default:
+		return errors.E(fmt.Sprintf("unsupported type: %s", t))
+	}
+	return nil
+}
+
+func (e *encodeState) encodeJSONMarshaler(v reflect.Value) error {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		e.null()
+		return nil
+	}
+	data, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return errors.E(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return errors.E(err)
+	}
+	return e.encode(reflect.ValueOf(value))
+}
+
+func (e *encodeState) encodeTextMarshaler(v reflect.Value) error {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		e.null()
+		return nil
+	}
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return errors.E(err)
+	}
+	e.text(string(text))
+	return nil
+}
+
+func (e *encodeState) encodeNumber(n json.Number) error {
+	if n == "" {
+		// encoding/json encodes an empty Number as 0.
+		e.int(0)
+		return nil
+	}
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		e.int(i)
+		return nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		e.head(majorUint, u)
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return errors.E(fmt.Sprintf("invalid number literal %q", n))
+	}
+	e.float(f)
+	return nil
+}
+
+func (e *encodeState) encodeArray(v reflect.Value) error {
+	n := v.Len()
+	e.head(majorArray, uint64(n))
+	for i := 0; i < n; i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encodeState) encodeMap(v reflect.Value) error {
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{key: key, value: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+	e.head(majorMap, uint64(len(entries)))
+	for _, ent := range entries {
+		e.text(ent.key)
+		if err := e.encode(ent.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapKey returns the string a map key is encoded as, following the
+// rules of encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", nil
+		}
+		text, err := tm.MarshalText()
+		if err != nil {
+			return "", errors.E(err)
+		}
+		return string(text), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", errors.E(fmt.Sprintf("unsupported map key type: %s", k.Type()))
+}
+
+func (e *encodeState) encodeStruct(v reflect.Value) error {
+	fields := cachedFields(v.Type())
+	values := make([]reflect.Value, len(fields))
+	n := 0
+	for i, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || (f.omitEmpty && isEmptyValue(fv)) {
+			continue
+		}
+		values[i] = fv
+		n++
+	}
+	e.head(majorMap, uint64(n))
+	for i, f := range fields {
+		if !values[i].IsValid() {
+			continue
+		}
+		e.text(f.name)
+		if err := e.encode(values[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of v with the given index sequence. It
+// returns false if the field is reached through a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// A field is a struct field that is encoded.
+type field struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields returns the fields of t that are encoded, in the order
+// encoding/json encodes them. Fields of embedded structs without a json
+// name are promoted, a field hides fields with the same name embedded
+// more deeply, and fields with the same name at the same depth hide each
+// other unless exactly one of them is named by a json tag.
+func typeFields(t reflect.Type) []field {
+	type candidate struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []field
+	seenName := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	next := []candidate{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		byName := make(map[string][]field)
+		var order []string
+		for _, c := range current {
+			if visited[c.typ] {
+				continue
+			}
+			visited[c.typ] = true
+			for i := 0; i < c.typ.NumField(); i++ {
+				sf := c.typ.Field(i)
+				ft := sf.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if !sf.IsExported() && !(sf.Anonymous && ft.Kind() == reflect.Struct) {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := cutComma(tag)
+				index := make([]int, len(c.index)+1)
+				copy(index, c.index)
+				index[len(c.index)] = i
+				if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+					next = append(next, candidate{typ: ft, index: index})
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+				f := field{
+					name:      name,
+					index:     index,
+					tagged:    name != "",
+					omitEmpty: hasOption(opts, "omitempty"),
+				}
+				if f.name == "" {
+					f.name = sf.Name
+				}
+				if seenName[f.name] {
+					continue
+				}
+				if _, ok := byName[f.name]; !ok {
+					order = append(order, f.name)
+				}
+				byName[f.name] = append(byName[f.name], f)
+			}
+		}
+		for _, name := range order {
+			if f, ok := dominantField(byName[name]); ok {
+				fields = append(fields, f)
+			}
+			seenName[name] = true
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i].index, fields[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return fields
+}
+
+// dominantField returns the field that is encoded out of fields with the
+// same name at the same depth.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) == 1 {
+		return fields[0], true
+	}
+	var dominant []field
+	for _, f := range fields {
+		if f.tagged {
+			dominant = append(dominant, f)
+		}
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	return field{}, false
+}
+
+func cutComma(s string) (before, after string, found bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' {
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+func hasOption(opts, opt string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = cutComma(opts)
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode decodes the single CBOR data item held in data. Values are
+// decoded as the types encoding/json uses when decoding into an
+// interface{}, except that integers are decoded as int64 (or uint64 if
+// they are too large for an int64) and byte strings as []byte. Map keys
+// must be text strings and tags are ignored.
+func Decode(data []byte) (interface{}, error) {
+	d := decodeState{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if d.off != len(d.data) {
+		return nil, errors.E("invalid CBOR: unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+type decodeState struct {
+	data []byte
+	off  int
+}
+
+var errUnexpectedEnd = errors.E("invalid CBOR: unexpected end of data")
+
+// head reads the initial bytes of a data item, returning its major type,
+// additional information and argument.
+func (d *decodeState) head() (major, info byte, n uint64, err error) {
+	if d.off >= len(d.data) {
+		return 0, 0, 0, errUnexpectedEnd
+	}
+	b := d.data[d.off]
+	d.off++
+	major, info = b>>5, b&0x1f
+	var size int
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	case info == infoIndefinite:
+		return major, info, 0, nil
+	default:
+		return 0, 0, 0, errors.E(fmt.Sprintf("invalid CBOR: reserved additional information %d", info))
+	}
+	if len(d.data)-d.off < size {
+		return 0, 0, 0, errUnexpectedEnd
+	}
+	for _, c := range d.data[d.off : d.off+size] {
+		n = n<<8 | uint64(c)
+	}
+	d.off += size
+	return major, info, n, nil
+}
+
+// remaining returns the number of bytes not yet decoded.
+func (d *decodeState) remaining() uint64 {
+	return uint64(len(d.data) - d.off)
+}
+
+func (d *decodeState) atBreak() bool {
+	return d.off < len(d.data) && d.data[d.off] == breakByte
+}
+
+func (d *decodeState) value(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.E("invalid CBOR: maximum nesting depth exceeded")
+	}
+	major, info, n, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	if info == infoIndefinite && (major == majorUint || major == majorNegInt || major == majorTag) {
+		return nil, errors.E(fmt.Sprintf("invalid CBOR: indefinite length not allowed for major type %d", major))
+	}
+	switch major {
+	case majorUint:
+		if n > math.MaxInt64 {
+			return n, nil
+		}
+		return int64(n), nil
+	case majorNegInt:
+		if n > math.MaxInt64 {
+			return nil, errors.E("invalid CBOR: negative integer out of range")
+		}
+		return -1 - int64(n), nil
+	case majorBytes, majorText:
+		b, err := d.string(major, info, n)
+		if err != nil {
+			return nil, err
+		}
+		if major == majorBytes {
+			return b, nil
+		}
+		if !utf8.Valid(b) {
+			return nil, errors.E("invalid CBOR: text string is not valid UTF-8")
+		}
+		return string(b), nil
+	case majorArray:
+		return d.array(info, n, depth)
+	case majorMap:
+		return d.object(info, n, depth)
+	case majorTag:
+		return d.value(depth + 1)
+	default:
+		return d.simple(info, n)
+	}
+}
+
+// string reads the content of a byte or text string with the given
+// head.
+func (d *decodeState) string(major, info byte, n uint64) ([]byte, error) {
+	if info != infoIndefinite {
+		if n > d.remaining() {
+			return nil, errUnexpectedEnd
+		}
+		b := make([]byte, n)
+		copy(b, d.data[d.off:])
+		d.off += int(n)
+		return b, nil
+	}
+	var b []byte
+	for !d.atBreak() {
+		chunkMajor, chunkInfo, chunkLen, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkInfo == infoIndefinite {
+			return nil, errors.E("invalid CBOR: invalid indefinite length string chunk")
+		}
+		if chunkLen > d.remaining() {
+			return nil, errUnexpectedEnd
+		}
+		b = append(b, d.data[d.off:d.off+int(chunkLen)]...)
+		d.off += int(chunkLen)
+	}
+	if d.off >= len(d.data) {
+		return nil, errUnexpectedEnd
+	}
+	d.off++
+	if b == nil {
+		b = []byte{}
+	}
+	return b, nil
+}
+
+func (d *decodeState) array(info byte, n uint64, depth int) (interface{}, error) {
+	if info == infoIndefinite {
+		a := []interface{}{}
+		for !d.atBreak() {
+			v, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			a = append(a, v)
+		}
+		if d.off >= len(d.data) {
+			return nil, errUnexpectedEnd
+		}
+		d.off++
+		return a, nil
+	}
+	// Every item takes at least one byte, so a length longer than the
+	// remaining data is invalid and must not be allocated.
+	if n > d.remaining() {
+		return nil, errUnexpectedEnd
+	}
+	a := make([]interface{}, n)
+	for i := range a {
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func (d *decodeState) object(info byte, n uint64, depth int) (interface{}, error) {
+	m := make(map[string]interface{})
+	entry := func() error {
+		k, err := d.value(depth + 1)
+		if err != nil {
+			return err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return errors.E(fmt.Sprintf("invalid CBOR: unsupported map key type %T", k))
+		}
+		v, err := d.value(depth + 1)
+		if err != nil {
+			return err
+		}
+		m[key] = v
+		return nil
+	}
+	if info == infoIndefinite {
+		for !d.atBreak() {
+			if err := entry(); err != nil {
+				return nil, err
+			}
+		}
+		if d.off >= len(d.data) {
+			return nil, errUnexpectedEnd
+		}
+		d.off++
+		return m, nil
+	}
+	if n > d.remaining()/2 {
+		return nil, errUnexpectedEnd
+	}
+	for i := uint64(0); i < n; i++ {
+		if err := entry(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (d *decodeState) simple(info byte, n uint64) (interface{}, error) {
+	switch info {
+	case simpleFalse:
+		return false, nil
+	case simpleTrue:
+		return true, nil
+	case simpleNull, simpleUndefined:
+		return nil, nil
+	case infoFloat16:
+		return float16ToFloat64(uint16(n)), nil
+	case infoFloat32:
+		return float64(math.Float32frombits(uint32(n))), nil
+	case infoFloat64:
+		return math.Float64frombits(n), nil
+	case infoIndefinite:
+		return nil, errors.E("invalid CBOR: unexpected break")
+	}
+	return nil, errors.E(fmt.Sprintf("invalid CBOR: unsupported simple value %d", n))
+}
+
+// float16ToFloat64 converts an IEEE 754 half precision number to a
+// float64.
+func float16ToFloat64(h uint16) float64 {
+	sign := 1.0
+	if h&0x8000 != 0 {
+		sign = -1
+	}
+	exp := int(h>>10) & 0x1f
+	frac := float64(h & 0x3ff)
+	switch exp {
+	case 0:
+		return sign * math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac == 0 {
+			return math.Inf(int(sign))
+		}
+		return math.NaN()
+	}
+	return sign * math.Ldexp(frac+1024, exp-25)
+}
@@ -0,0 +1,303 @@
+// Copyright 2024 Canonical.
+
+package cbor_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/cbor"
+)
+
+func mustHex(c *qt.C, s string) []byte {
+	b, err := hex.DecodeString(s)
+	c.Assert(err, qt.IsNil)
+	return b
+}
+
+var marshalTests = []struct {
+	about  string
+	value  interface{}
+	expect string
+}{{
+	about:  "small unsigned integer",
+	value:  10,
+	expect: "0a",
+}, {
+	about:  "one byte unsigned integer",
+	value:  uint8(100),
+	expect: "1864",
+}, {
+	about:  "large unsigned integer",
+	value:  uint64(1000000000000),
+	expect: "1b000000e8d4a51000",
+}, {
+	about:  "negative integer",
+	value:  -1000,
+	expect: "3903e7",
+}, {
+	about:  "float",
+	value:  1.1,
+	expect: "fb3ff199999999999a",
+}, {
+	about:  "float representable in 32 bits",
+	value:  100000.0,
+	expect: "fa47c35000",
+}, {
+	about:  "booleans and null",
+	value:  []interface{}{false, true, nil},
+	expect: "83f4f5f6",
+}, {
+	about:  "text",
+	value:  "ü",
+	expect: "62c3bc",
+}, {
+	about:  "bytes",
+	value:  []byte{1, 2, 3, 4},
+	expect: "4401020304",
+}, {
+	about:  "nil slice",
+	value:  []string(nil),
+	expect: "f6",
+}, {
+	about:  "map with sorted keys",
+	value:  map[string]int{"b": 2, "a": 1},
+	expect: "a2616101616202",
+}, {
+	about:  "map with integer keys",
+	value:  map[int]bool{1: true},
+	expect: "a16131f5",
+}, {
+	about:  "json number",
+	value:  json.Number("-24"),
+	expect: "37",
+}, {
+	about:  "time",
+	value:  time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC),
+	expect: "74323031332d30332d32315432303a30343a30305a",
+}, {
+	about:  "raw json",
+	value:  json.RawMessage(`{"a":[1,2]}`),
+	expect: "a16161820102",
+}}
+
+func TestMarshal(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range marshalTests {
+		c.Run(test.about, func(c *qt.C) {
+			data, err := cbor.Marshal(test.value)
+			c.Assert(err, qt.IsNil)
+			c.Check(hex.EncodeToString(data), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := cbor.Marshal(math.NaN())
+	c.Check(err, qt.ErrorMatches, `unsupported value: NaN`)
+	_, err = cbor.Marshal(make(chan int))
+	c.Check(err, qt.ErrorMatches, `unsupported type: chan int`)
+	_, err = cbor.Marshal(map[bool]int{true: 1})
+	c.Check(err, qt.ErrorMatches, `unsupported map key type: bool`)
+}
+
+type Embedded struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+type embeddedUnexported struct {
+	C string `json:"c"`
+}
+
+type testStruct struct {
+	Embedded
+	*embeddedUnexported
+	B          string            `json:"b"`
+	Name       string            `json:"name,omitempty"`
+	Count      int               `json:"count,omitempty"`
+	Ignored    string            `json:"-"`
+	Untagged   int               `json:""`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Time       *time.Time        `json:"time,omitempty"`
+	unexported int
+}
+
+func TestMarshalStructMatchesJSON(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Now().UTC()
+	values := []testStruct{{}, {
+		Embedded:           Embedded{A: "a", B: "hidden"},
+		embeddedUnexported: &embeddedUnexported{C: "c"},
+		B:                  "b",
+		Name:               "name",
+		Count:              -3,
+		Ignored:            "ignored",
+		Untagged:           4,
+		Labels:             map[string]string{"x": "y"},
+		Time:               &now,
+		unexported:         5,
+	}}
+	for _, v := range values {
+		data, err := cbor.Marshal(v)
+		c.Assert(err, qt.IsNil)
+		decoded, err := cbor.Decode(data)
+		c.Assert(err, qt.IsNil)
+		got, err := json.Marshal(decoded)
+		c.Assert(err, qt.IsNil)
+		expect, err := json.Marshal(v)
+		c.Assert(err, qt.IsNil)
+		c.Check(string(got), qt.JSONEquals, json.RawMessage(expect))
+	}
+}
+
+var decodeTests = []struct {
+	about  string
+	data   string
+	expect interface{}
+}{{
+	about:  "unsigned integer",
+	data:   "1903e8",
+	expect: int64(1000),
+}, {
+	about:  "unsigned integer too large for int64",
+	data:   "1bffffffffffffffff",
+	expect: uint64(math.MaxUint64),
+}, {
+	about:  "negative integer",
+	data:   "3863",
+	expect: int64(-100),
+}, {
+	about:  "half precision float",
+	data:   "f93e00",
+	expect: 1.5,
+}, {
+	about:  "single precision float",
+	data:   "fa47c35000",
+	expect: 100000.0,
+}, {
+	about:  "double precision float",
+	data:   "fb3ff199999999999a",
+	expect: 1.1,
+}, {
+	about:  "simple values",
+	data:   "84f4f5f6f7",
+	expect: []interface{}{false, true, nil, nil},
+}, {
+	about:  "bytes",
+	data:   "4401020304",
+	expect: []byte{1, 2, 3, 4},
+}, {
+	about:  "indefinite length bytes",
+	data:   "5f42010243030405ff",
+	expect: []byte{1, 2, 3, 4, 5},
+}, {
+	about:  "indefinite length text",
+	data:   "7f657374726561646d696e67ff",
+	expect: "streaming",
+}, {
+	about:  "nested arrays",
+	data:   "8301820203820405",
+	expect: []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}},
+}, {
+	about:  "indefinite length array",
+	data:   "9f018202039f0405ffff",
+	expect: []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}},
+}, {
+	about:  "map",
+	data:   "a26161016162820203",
+	expect: map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}},
+}, {
+	about:  "indefinite length map",
+	data:   "bf61610161629f0203ffff",
+	expect: map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}},
+}, {
+	about:  "tag",
+	data:   "c074323031332d30332d32315432303a30343a30305a",
+	expect: "2013-03-21T20:04:00Z",
+}}
+
+func TestDecode(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range decodeTests {
+		c.Run(test.about, func(c *qt.C) {
+			v, err := cbor.Decode(mustHex(c, test.data))
+			c.Assert(err, qt.IsNil)
+			c.Check(v, qt.DeepEquals, test.expect)
+		})
+	}
+}
+
+var decodeErrorTests = []struct {
+	about       string
+	data        string
+	expectError string
+}{{
+	about:       "empty",
+	data:        "",
+	expectError: `invalid CBOR: unexpected end of data`,
+}, {
+	about:       "truncated argument",
+	data:        "19",
+	expectError: `invalid CBOR: unexpected end of data`,
+}, {
+	about:       "reserved additional information",
+	data:        "1c",
+	expectError: `invalid CBOR: reserved additional information 28`,
+}, {
+	about:       "array longer than data",
+	data:        "9b00000000ffffffff",
+	expectError: `invalid CBOR: unexpected end of data`,
+}, {
+	about:       "trailing data",
+	data:        "0101",
+	expectError: `invalid CBOR: unexpected data after top-level value`,
+}, {
+	about:       "non-text map key",
+	data:        "a10102",
+	expectError: `invalid CBOR: unsupported map key type int64`,
+}, {
+	about:       "invalid utf-8",
+	data:        "61ff",
+	expectError: `invalid CBOR: text string is not valid UTF-8`,
+}, {
+	about:       "unexpected break",
+	data:        "ff",
+	expectError: `invalid CBOR: unexpected break`,
+}, {
+	about:       "indefinite length integer",
+	data:        "1f",
+	expectError: `invalid CBOR: indefinite length not allowed for major type 0`,
+}}
+
+func TestDecodeErrors(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range decodeErrorTests {
+		c.Run(test.about, func(c *qt.C) {
+			_, err := cbor.Decode(mustHex(c, test.data))
+			c.Check(err, qt.ErrorMatches, test.expectError)
+		})
+	}
+}
+
+func TestDecodeMaximumDepth(t *testing.T) {
+	c := qt.New(t)
+
+	data := make([]byte, 2000)
+	for i := range data {
+		data[i] = 0x81
+	}
+	_, err := cbor.Decode(data)
+	c.Check(err, qt.ErrorMatches, `invalid CBOR: maximum nesting depth exceeded`)
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Canonical.
+
+// Package charmhub queries the Charmhub store for information about the
+// charms published there.
+package charmhub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// DefaultURL is the URL of the public Charmhub API.
+const DefaultURL = "https://api.charmhub.io"
+
+// infoFields are the fields requested from the charm info endpoint.
+var infoFields = []string{
+	"channel-map.channel.name",
+	"channel-map.revision.revision",
+	"result.publisher.display-name",
+	"result.publisher.username",
+	"result.publisher.validation",
+}
+
+// A Publisher is the publisher of a charm.
+type Publisher struct {
+	// Username is the Charmhub username of the publisher.
+	Username string
+
+	// DisplayName is the name of the publisher shown to users.
+	DisplayName string
+
+	// Validation is the account validation state of the publisher, one
+	// of "verified", "starred" or "unproven".
+	Validation string
+}
+
+// Verified reports whether Charmhub has verified the publisher's
+// identity.
+func (p Publisher) Verified() bool {
+	return p.Validation == "verified" || p.Validation == "starred"
+}
+
+// CharmInfo holds the information published about a charm.
+type CharmInfo struct {
+	// Name is the name of the charm.
+	Name string
+
+	// Publisher is the publisher of the charm.
+	Publisher Publisher
+
+	// Channels holds the latest revision released to each channel,
+	// keyed by the channel name, for example "14/stable".
+	Channels map[string]int
+
+	// LatestRevision is the highest revision released to any channel.
+	LatestRevision int
+}
+
+// A Client queries the Charmhub API.
+type Client struct {
+	// URL is the base URL of the Charmhub API. If this is empty
+	// DefaultURL is used.
+	URL string
+
+	// Client is the HTTP client used to query Charmhub. If this is nil
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+// infoResponse is the response from the charm info endpoint.
+type infoResponse struct {
+	Name       string `json:"name"`
+	ChannelMap []struct {
+		Channel struct {
+			Name string `json:"name"`
+		} `json:"channel"`
+		Revision struct {
+			Revision int `json:"revision"`
+		} `json:"revision"`
+	} `json:"channel-map"`
+	Result struct {
+		Publisher struct {
+			DisplayName string `json:"display-name"`
+			Username    string `json:"username"`
+			Validation  string `json:"validation"`
+		} `json:"publisher"`
+	} `json:"result"`
+}
+
+// CharmInfo returns the information published about the named charm. If
+// the charm is not found in Charmhub an error with the code CodeNotFound
+// is returned.
+func (c *Client) CharmInfo(ctx context.Context, name string) (*CharmInfo, error) {
+	const op = errors.Op("charmhub.CharmInfo")
+
+	base := c.URL
+	if base == "" {
+		base = DefaultURL
+	}
+	q := url.Values{"fields": {strings.Join(infoFields, ",")}}
+	u := strings.TrimSuffix(base, "/") + "/v2/charms/info/" + url.PathEscape(name) + "?" + q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, errors.E(op, errors.CodeNotFound, fmt.Sprintf("charm %q not found", name))
+	default:
+		return nil, errors.E(op, fmt.Sprintf("charmhub returned status %s", resp.Status))
+	}
+	var ir infoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ir); err != nil {
+		return nil, errors.E(op, fmt.Sprintf("cannot decode charmhub response: %s", err))
+	}
+
+	info := CharmInfo{
+		Name: ir.Name,
+		Publisher: Publisher{
+			Username:    ir.Result.Publisher.Username,
+			DisplayName: ir.Result.Publisher.DisplayName,
+			Validation:  ir.Result.Publisher.Validation,
+		},
+		Channels: make(map[string]int),
+	}
+	if info.Name == "" {
+		info.Name = name
+	}
+	// The channel map has an entry for every base a channel is
+	// released for, which may have different revisions.
+	for _, cm := range ir.ChannelMap {
+		rev := cm.Revision.Revision
+		if rev > info.Channels[cm.Channel.Name] {
+			info.Channels[cm.Channel.Name] = rev
+		}
+		if rev > info.LatestRevision {
+			info.LatestRevision = rev
+		}
+	}
+	return &info, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Canonical.
+
+package charmhub_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/charmhub"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const postgresqlInfo = `{
+	"name": "postgresql",
+	"channel-map": [
+		{"channel": {"name": "14/stable"}, "revision": {"revision": 363}},
+		{"channel": {"name": "14/stable"}, "revision": {"revision": 351}},
+		{"channel": {"name": "14/edge"}, "revision": {"revision": 400}}
+	],
+	"result": {
+		"publisher": {
+			"display-name": "Canonical",
+			"username": "data-platform",
+			"validation": "verified"
+		}
+	}
+}`
+
+func TestCharmInfo(t *testing.T) {
+	c := qt.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v2/charms/info/postgresql" {
+			http.NotFound(w, req)
+			return
+		}
+		c.Check(req.URL.Query().Get("fields"), qt.Contains, "result.publisher.validation")
+		fmt.Fprint(w, postgresqlInfo)
+	}))
+	defer srv.Close()
+
+	client := charmhub.Client{URL: srv.URL}
+	info, err := client.CharmInfo(context.Background(), "postgresql")
+	c.Assert(err, qt.IsNil)
+	c.Check(info, qt.DeepEquals, &charmhub.CharmInfo{
+		Name: "postgresql",
+		Publisher: charmhub.Publisher{
+			Username:    "data-platform",
+			DisplayName: "Canonical",
+			Validation:  "verified",
+		},
+		Channels: map[string]int{
+			"14/stable": 363,
+			"14/edge":   400,
+		},
+		LatestRevision: 400,
+	})
+	c.Check(info.Publisher.Verified(), qt.IsTrue)
+
+	_, err = client.CharmInfo(context.Background(), "no-such-charm")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	c.Check(err, qt.ErrorMatches, `charm "no-such-charm" not found`)
+}
+
+func TestPublisherVerified(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(charmhub.Publisher{Validation: "verified"}.Verified(), qt.IsTrue)
+	c.Check(charmhub.Publisher{Validation: "starred"}.Verified(), qt.IsTrue)
+	c.Check(charmhub.Publisher{Validation: "unproven"}.Verified(), qt.IsFalse)
+	c.Check(charmhub.Publisher{}.Verified(), qt.IsFalse)
+}
@@ -86,6 +86,8 @@ func (s *JimmCmdSuite) SetUpTest(c *gc.C) {
 	c.Assert(err, gc.Equals, nil)
 	s.Service = srv
 	s.JIMM = srv.JIMM()
+	// The tests add the same juju controller under several names.
+	s.JIMM.AllowDuplicateControllerUUIDs = true
 	s.HTTP.Config = &http.Server{Handler: srv, ReadHeaderTimeout: time.Second * 5}
 
 	err = s.Service.StartJWKSRotator(ctx, time.NewTicker(time.Hour).C, time.Now().UTC().AddDate(0, 3, 0))
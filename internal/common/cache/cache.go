@@ -0,0 +1,115 @@
+// Copyright 2024 Canonical.
+
+// Package cache provides a simple in-memory cache whose entries expire
+// after a fixed time-to-live.
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// A Cache is an in-memory cache of values of type V, keyed by string.
+// Entries expire after the TTL the cache was created with. A nil Cache,
+// or a Cache with a non-positive TTL, is valid and caches nothing. Hits
+// and misses are recorded in the servermon cache metrics using the
+// cache's name.
+type Cache[V any] struct {
+	name string
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry[V]
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// New creates a new Cache with the given name and TTL.
+func New[V any](name string, ttl time.Duration) *Cache[V] {
+	return &Cache[V]{
+		name:    name,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry[V]),
+	}
+}
+
+// Enabled reports whether the cache stores values.
+func (c *Cache[V]) Enabled() bool {
+	return c != nil && c.ttl > 0
+}
+
+// Get returns the value stored for the given key. If there is no
+// unexpired value for the key then false is returned.
+func (c *Cache[V]) Get(key string) (V, bool) {
+	var v V
+	if !c.Enabled() {
+		return v, false
+	}
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if ok && !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if !ok {
+		servermon.CacheMissCount.WithLabelValues(c.name).Inc()
+		return v, false
+	}
+	servermon.CacheHitCount.WithLabelValues(c.name).Inc()
+	return e.value, true
+}
+
+// Set stores the given value for the given key, replacing any existing
+// value.
+func (c *Cache[V]) Set(key string, v V) {
+	if !c.Enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry[V]{value: v, expires: c.now().Add(c.ttl)}
+}
+
+// Delete removes any value stored for the given key.
+func (c *Cache[V]) Delete(key string) {
+	if !c.Enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// DeletePrefix removes all values stored with keys starting with the
+// given prefix.
+func (c *Cache[V]) DeletePrefix(prefix string) {
+	if !c.Enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// Purge removes all values from the cache.
+func (c *Cache[V]) Purge() {
+	if !c.Enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
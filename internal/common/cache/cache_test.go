@@ -0,0 +1,69 @@
+// Copyright 2024 Canonical.
+
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/canonical/jimm/v3/internal/common/cache"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+func TestCache(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ch := cache.New[string](c.Name(), time.Minute)
+	cache.SetNow(ch, func() time.Time { return now })
+
+	_, ok := ch.Get("a")
+	c.Check(ok, qt.IsFalse)
+
+	ch.Set("a", "1")
+	ch.Set("ab", "2")
+	ch.Set("b", "3")
+	v, ok := ch.Get("a")
+	c.Check(ok, qt.IsTrue)
+	c.Check(v, qt.Equals, "1")
+
+	ch.Delete("a")
+	_, ok = ch.Get("a")
+	c.Check(ok, qt.IsFalse)
+
+	ch.DeletePrefix("a")
+	_, ok = ch.Get("ab")
+	c.Check(ok, qt.IsFalse)
+	v, ok = ch.Get("b")
+	c.Check(ok, qt.IsTrue)
+	c.Check(v, qt.Equals, "3")
+
+	now = now.Add(time.Minute)
+	_, ok = ch.Get("b")
+	c.Check(ok, qt.IsFalse)
+
+	ch.Set("c", "4")
+	ch.Purge()
+	_, ok = ch.Get("c")
+	c.Check(ok, qt.IsFalse)
+
+	c.Check(testutil.ToFloat64(servermon.CacheHitCount.WithLabelValues(c.Name())), qt.Equals, float64(2))
+	c.Check(testutil.ToFloat64(servermon.CacheMissCount.WithLabelValues(c.Name())), qt.Equals, float64(5))
+}
+
+func TestDisabledCache(t *testing.T) {
+	c := qt.New(t)
+
+	for _, ch := range []*cache.Cache[string]{nil, cache.New[string](c.Name(), 0)} {
+		ch.Set("a", "1")
+		_, ok := ch.Get("a")
+		c.Check(ok, qt.IsFalse)
+		ch.Delete("a")
+		ch.DeletePrefix("a")
+		ch.Purge()
+	}
+	c.Check(testutil.ToFloat64(servermon.CacheMissCount.WithLabelValues(c.Name())), qt.Equals, float64(0))
+}
@@ -0,0 +1,10 @@
+// Copyright 2024 Canonical.
+
+package cache
+
+import "time"
+
+// SetNow sets the function the cache uses to get the current time.
+func SetNow[V any](c *Cache[V], now func() time.Time) {
+	c.now = now
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AccessRequestFilter can be used to find access requests that match
+// certain criteria.
+type AccessRequestFilter struct {
+	// IdentityName, if set, matches the requests made by the identity
+	// with the given name.
+	IdentityName string
+
+	// TargetTag, if set, matches the requests for access to the given
+	// model or cloud.
+	TargetTag string
+
+	// Status, if set, matches the requests in the given state.
+	Status string
+}
+
+// AddAccessRequest stores the given access request.
+func (d *Database) AddAccessRequest(ctx context.Context, r *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.AddAccessRequest")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetAccessRequest completes the given access request, which is
+// identified by its ID. If the request cannot be found an error with the
+// code CodeNotFound is returned.
+func (d *Database) GetAccessRequest(ctx context.Context, r *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.GetAccessRequest")
+	if r.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "access request not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).First(r, r.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListAccessRequests returns the access requests matching the given
+// filter, ordered from the oldest to the most recent.
+func (d *Database) ListAccessRequests(ctx context.Context, filter AccessRequestFilter) (_ []dbmodel.AccessRequest, err error) {
+	const op = errors.Op("db.ListAccessRequests")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if filter.IdentityName != "" {
+		db = db.Where("identity_name = ?", filter.IdentityName)
+	}
+	if filter.TargetTag != "" {
+		db = db.Where("target_tag = ?", filter.TargetTag)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	var requests []dbmodel.AccessRequest
+	if err := db.Order("id").Find(&requests).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return requests, nil
+}
+
+// UpdateAccessRequest updates the given access request.
+func (d *Database) UpdateAccessRequest(ctx context.Context, r *dbmodel.AccessRequest) (err error) {
+	const op = errors.Op("db.UpdateAccessRequest")
+	if r.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Save(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
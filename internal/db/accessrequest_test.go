@@ -0,0 +1,93 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestAccessRequests(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddAccessRequest(ctx, &dbmodel.AccessRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for _, name := range []string{"alice@canonical.com", "bob@canonical.com"} {
+		i, err := dbmodel.NewIdentity(name)
+		c.Assert(err, qt.IsNil)
+		err = s.Database.GetIdentity(ctx, i)
+		c.Assert(err, qt.IsNil)
+	}
+
+	r1 := dbmodel.AccessRequest{
+		IdentityName: "alice@canonical.com",
+		TargetTag:    "model-00000002-0000-0000-0000-000000000001",
+		Access:       "write",
+		Reason:       "deploying the new release",
+		Status:       dbmodel.AccessRequestPending,
+	}
+	err = s.Database.AddAccessRequest(ctx, &r1)
+	c.Assert(err, qt.IsNil)
+	r2 := dbmodel.AccessRequest{
+		IdentityName: "bob@canonical.com",
+		TargetTag:    "cloud-aws",
+		Access:       "add-model",
+		Status:       dbmodel.AccessRequestPending,
+	}
+	err = s.Database.AddAccessRequest(ctx, &r2)
+	c.Assert(err, qt.IsNil)
+
+	r := dbmodel.AccessRequest{ID: r1.ID}
+	err = s.Database.GetAccessRequest(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	c.Check(r.TargetTag, qt.Equals, r1.TargetTag)
+	c.Check(r.Reason, qt.Equals, "deploying the new release")
+
+	err = s.Database.GetAccessRequest(ctx, &dbmodel.AccessRequest{ID: r2.ID + 1})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.GetAccessRequest(ctx, &dbmodel.AccessRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	requests, err := s.Database.ListAccessRequests(ctx, db.AccessRequestFilter{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 2)
+	c.Check(requests[0].ID, qt.Equals, r1.ID)
+	c.Check(requests[1].ID, qt.Equals, r2.ID)
+
+	requests, err = s.Database.ListAccessRequests(ctx, db.AccessRequestFilter{IdentityName: "bob@canonical.com"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 1)
+	c.Check(requests[0].ID, qt.Equals, r2.ID)
+
+	r.Status = dbmodel.AccessRequestApproved
+	r.ReviewerName = "bob@canonical.com"
+	r.ReviewedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	err = s.Database.UpdateAccessRequest(ctx, &r)
+	c.Assert(err, qt.IsNil)
+
+	requests, err = s.Database.ListAccessRequests(ctx, db.AccessRequestFilter{
+		TargetTag: r1.TargetTag,
+		Status:    dbmodel.AccessRequestPending,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(requests, qt.HasLen, 0)
+	requests, err = s.Database.ListAccessRequests(ctx, db.AccessRequestFilter{Status: dbmodel.AccessRequestApproved})
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 1)
+	c.Check(requests[0].ReviewerName, qt.Equals, "bob@canonical.com")
+	c.Check(requests[0].ReviewedAt.Valid, qt.IsTrue)
+
+	err = s.Database.UpdateAccessRequest(ctx, &dbmodel.AccessRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
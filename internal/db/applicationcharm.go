@@ -0,0 +1,110 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetApplicationCharm records the charm used by an application,
+// replacing any charm already recorded for the application.
+func (d *Database) SetApplicationCharm(ctx context.Context, ac *dbmodel.ApplicationCharm) (err error) {
+	const op = errors.Op("db.SetApplicationCharm")
+	if ac.ModelID == 0 || ac.ApplicationName == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID or application name")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}, {Name: "application_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "charm_url", "exposed"}),
+	})
+	if err := db.Create(ac).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteApplicationCharm removes the charm, and the endpoints, recorded
+// for the application identified by the ModelID and ApplicationName of
+// the given ApplicationCharm. Removing a charm that is not recorded is
+// not an error.
+func (d *Database) DeleteApplicationCharm(ctx context.Context, ac *dbmodel.ApplicationCharm) (err error) {
+	const op = errors.Op("db.DeleteApplicationCharm")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		where := "model_id = ? AND application_name = ?"
+		if err := tx.Where(where, ac.ModelID, ac.ApplicationName).Delete(&dbmodel.ApplicationEndpoint{}).Error; err != nil {
+			return err
+		}
+		return tx.Where(where, ac.ModelID, ac.ApplicationName).Delete(&dbmodel.ApplicationCharm{}).Error
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListApplicationCharms returns the charms recorded for all applications,
+// ordered by model and application name.
+func (d *Database) ListApplicationCharms(ctx context.Context) (_ []dbmodel.ApplicationCharm, err error) {
+	const op = errors.Op("db.ListApplicationCharms")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var charms []dbmodel.ApplicationCharm
+	db := d.DB.WithContext(ctx).Preload("Model").Preload("Model.Controller")
+	if err := db.Order("model_id, application_name").Find(&charms).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return charms, nil
+}
+
+// ListModelApplicationCharms returns the charms recorded for the
+// applications in the models with the given IDs, ordered by model and
+// application name.
+func (d *Database) ListModelApplicationCharms(ctx context.Context, modelIDs []uint) (_ []dbmodel.ApplicationCharm, err error) {
+	const op = errors.Op("db.ListModelApplicationCharms")
+	if len(modelIDs) == 0 {
+		return nil, nil
+	}
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var charms []dbmodel.ApplicationCharm
+	db := d.DB.WithContext(ctx).Where("model_id IN ?", modelIDs)
+	if err := db.Order("model_id, application_name").Find(&charms).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return charms, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestListApplicationCharmsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+	var d db.Database
+
+	_, err := d.ListApplicationCharms(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestApplicationCharm(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         env.model.ID,
+		ApplicationName: "postgresql",
+		CharmURL:        "ch:amd64/jammy/postgresql-345",
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         env.model.ID,
+		ApplicationName: "postgresql",
+		CharmURL:        "ch:amd64/jammy/postgresql-363",
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         env.model.ID,
+		ApplicationName: "app",
+		CharmURL:        "local:jammy/app-0",
+	})
+	c.Assert(err, qt.IsNil)
+
+	charms, err := s.Database.ListApplicationCharms(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(charms, qt.HasLen, 2)
+	c.Check(charms[0].ApplicationName, qt.Equals, "app")
+	c.Check(charms[1].ApplicationName, qt.Equals, "postgresql")
+	c.Check(charms[1].CharmURL, qt.Equals, "ch:amd64/jammy/postgresql-363")
+	c.Check(charms[1].Model.UUID, qt.DeepEquals, env.model.UUID)
+	c.Check(charms[1].Model.Controller.Name, qt.Equals, env.controller.Name)
+	c.Check(charms[1].Charm().Revision, qt.Equals, 363)
+
+	err = s.Database.DeleteApplicationCharm(ctx, &charms[1])
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteApplicationCharm(ctx, &charms[1])
+	c.Assert(err, qt.IsNil)
+	charms, err = s.Database.ListApplicationCharms(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(charms, qt.HasLen, 1)
+	c.Check(charms[0].ApplicationName, qt.Equals, "app")
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetApplicationEndpoints records the given application endpoints,
+// replacing the role and interface of any endpoints already recorded.
+func (d *Database) SetApplicationEndpoints(ctx context.Context, eps []dbmodel.ApplicationEndpoint) (err error) {
+	const op = errors.Op("db.SetApplicationEndpoints")
+	if len(eps) == 0 {
+		return nil
+	}
+	for _, ep := range eps {
+		if ep.ModelID == 0 || ep.ApplicationName == "" || ep.Name == "" {
+			return errors.E(op, errors.CodeBadRequest, "missing model ID, application name or endpoint name")
+		}
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}, {Name: "application_name"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "role", "interface"}),
+	})
+	if err := db.Create(&eps).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// An ApplicationEndpointFilter can be used to find application endpoints
+// that match certain criteria.
+type ApplicationEndpointFilter func(*gorm.DB) *gorm.DB
+
+// ApplicationEndpointFilterByInterface filters application endpoints by
+// the endpoint interface.
+func ApplicationEndpointFilterByInterface(iface string) ApplicationEndpointFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("application_endpoints.interface = ?", iface)
+	}
+}
+
+// ApplicationEndpointFilterByName filters application endpoints by the
+// endpoint name.
+func ApplicationEndpointFilterByName(name string) ApplicationEndpointFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("application_endpoints.name = ?", name)
+	}
+}
+
+// ApplicationEndpointFilterByRole filters application endpoints by the
+// endpoint role.
+func ApplicationEndpointFilterByRole(role string) ApplicationEndpointFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("application_endpoints.role = ?", role)
+	}
+}
+
+// ApplicationEndpointFilterByModelUUID filters application endpoints to
+// those in the models with the given UUIDs.
+func ApplicationEndpointFilterByModelUUID(uuids []string) ApplicationEndpointFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("application_endpoints.model_id IN (SELECT id FROM models WHERE uuid IN ?)", uuids)
+	}
+}
+
+// FindApplicationEndpoints returns the application endpoints matching
+// all the given filters, ordered by model, application and endpoint
+// name. The model, and its controller, are loaded for every endpoint.
+func (d *Database) FindApplicationEndpoints(ctx context.Context, filters ...ApplicationEndpointFilter) (_ []dbmodel.ApplicationEndpoint, err error) {
+	const op = errors.Op("db.FindApplicationEndpoints")
+	if len(filters) == 0 {
+		return nil, errors.E(op, errors.CodeBadRequest, "no filters specified")
+	}
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	for _, f := range filters {
+		db = f(db)
+	}
+	db = db.Preload("Model").Preload("Model.Controller")
+
+	var eps []dbmodel.ApplicationEndpoint
+	if err := db.Order("application_endpoints.model_id, application_endpoints.application_name, application_endpoints.name").Find(&eps).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return eps, nil
+}
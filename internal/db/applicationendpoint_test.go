@@ -0,0 +1,96 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestFindApplicationEndpointsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+	var d db.Database
+
+	_, err := d.FindApplicationEndpoints(context.Background(), db.ApplicationEndpointFilterByInterface("pgsql"))
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestApplicationEndpoints(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.SetApplicationEndpoints(ctx, []dbmodel.ApplicationEndpoint{{ModelID: env.model.ID}})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Database.SetApplicationEndpoints(ctx, []dbmodel.ApplicationEndpoint{{
+		ModelID:         env.model.ID,
+		ApplicationName: "postgresql",
+		Name:            "database",
+		Role:            "provider",
+		Interface:       "postgresql_client",
+	}, {
+		ModelID:         env.model.ID,
+		ApplicationName: "app",
+		Name:            "db",
+		Role:            "requirer",
+		Interface:       "postgresql_client",
+	}})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetApplicationEndpoints(ctx, []dbmodel.ApplicationEndpoint{{
+		ModelID:         env.model.ID,
+		ApplicationName: "postgresql",
+		Name:            "database",
+		Role:            "provider",
+		Interface:       "postgresql_client",
+	}})
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.Database.FindApplicationEndpoints(ctx)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	eps, err := s.Database.FindApplicationEndpoints(ctx, db.ApplicationEndpointFilterByInterface("postgresql_client"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(eps, qt.HasLen, 2)
+	c.Check(eps[0].ApplicationName, qt.Equals, "app")
+	c.Check(eps[1].ApplicationName, qt.Equals, "postgresql")
+	c.Check(eps[1].Model.UUID, qt.DeepEquals, env.model.UUID)
+	c.Check(eps[1].Model.Controller.Name, qt.Equals, env.controller.Name)
+
+	eps, err = s.Database.FindApplicationEndpoints(ctx,
+		db.ApplicationEndpointFilterByInterface("postgresql_client"),
+		db.ApplicationEndpointFilterByRole("provider"),
+		db.ApplicationEndpointFilterByModelUUID([]string{env.model.UUID.String}),
+	)
+	c.Assert(err, qt.IsNil)
+	c.Assert(eps, qt.HasLen, 1)
+	c.Check(eps[0].ApplicationName, qt.Equals, "postgresql")
+
+	eps, err = s.Database.FindApplicationEndpoints(ctx, db.ApplicationEndpointFilterByModelUUID([]string{"00000000-0000-0000-0000-000000000000"}))
+	c.Assert(err, qt.IsNil)
+	c.Check(eps, qt.HasLen, 0)
+
+	err = s.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         env.model.ID,
+		ApplicationName: "postgresql",
+		CharmURL:        "ch:amd64/jammy/postgresql-363",
+		Exposed:         true,
+	})
+	c.Assert(err, qt.IsNil)
+	charms, err := s.Database.ListModelApplicationCharms(ctx, []uint{env.model.ID})
+	c.Assert(err, qt.IsNil)
+	c.Assert(charms, qt.HasLen, 1)
+	c.Check(charms[0].Exposed, qt.IsTrue)
+
+	err = s.Database.DeleteApplicationCharm(ctx, &charms[0])
+	c.Assert(err, qt.IsNil)
+	eps, err = s.Database.FindApplicationEndpoints(ctx, db.ApplicationEndpointFilterByName("database"))
+	c.Assert(err, qt.IsNil)
+	c.Check(eps, qt.HasLen, 0)
+}
@@ -225,3 +225,69 @@ func (d *Database) FindApplicationOffers(ctx context.Context, filters ...Applica
 
 	return offers, nil
 }
+
+// ApplicationOfferFilterByText filters application offers whose name,
+// application name or application description contain the given
+// substring, ignoring case.
+func ApplicationOfferFilterByText(substring string) ApplicationOfferFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		pattern := "%" + substring + "%"
+		return db.Where("offers.name ILIKE ? OR offers.application_name ILIKE ? OR offers.application_description ILIKE ?", pattern, pattern, pattern)
+	}
+}
+
+// ApplicationOfferFilterByInterface filters application offers with an
+// endpoint using the given interface.
+func ApplicationOfferFilterByInterface(iface string) ApplicationOfferFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("offers.id IN (SELECT application_offer_id FROM application_offer_remote_endpoints WHERE interface = ? AND deleted_at IS NULL)", iface)
+	}
+}
+
+// ApplicationOfferFilterByOwner filters application offers in models
+// owned by the identity with the given name.
+func ApplicationOfferFilterByOwner(owner string) ApplicationOfferFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("offers.model_id IN (SELECT id FROM models WHERE owner_identity_name = ?)", owner)
+	}
+}
+
+// ApplicationOfferFilterByCloud filters application offers in models
+// hosted on the cloud with the given name.
+func ApplicationOfferFilterByCloud(cloud string) ApplicationOfferFilter {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("offers.model_id IN (SELECT models.id FROM models JOIN cloud_regions ON cloud_regions.id = models.cloud_region_id WHERE cloud_regions.cloud_name = ?)", cloud)
+	}
+}
+
+// SearchApplicationOffers returns the application offers matching all
+// the given filters, ordered by URL, with their endpoints, connections
+// and the model, controller and cloud-region hosting them. If limit is
+// greater than zero at most limit offers are returned.
+func (d *Database) SearchApplicationOffers(ctx context.Context, limit int, filters ...ApplicationOfferFilter) (_ []dbmodel.ApplicationOffer, err error) {
+	const op = errors.Op("db.SearchApplicationOffers")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Table("application_offers AS offers")
+	for _, filter := range filters {
+		db = filter(db)
+	}
+	db = db.Preload("Connections").Preload("Endpoints")
+	db = db.Preload("Model").Preload("Model.Controller").Preload("Model.CloudRegion")
+	db = db.Order("offers.url")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	var offers []dbmodel.ApplicationOffer
+	if err := db.Find(&offers).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return offers, nil
+}
@@ -5,6 +5,7 @@ package db_test
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"testing"
 	"time"
 
@@ -476,3 +477,68 @@ func (s *dbSuite) TestFindApplicationOffers(c *qt.C) {
 		})
 	}
 }
+
+func (s *dbSuite) TestSearchApplicationOffers(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	for i, iface := range []string{"postgresql_client", "http", "http"} {
+		offer := dbmodel.ApplicationOffer{
+			UUID:                   fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", i+1),
+			Name:                   fmt.Sprintf("offer-%d", i+1),
+			ModelID:                env.model.ID,
+			ApplicationName:        fmt.Sprintf("app-%d", i+1),
+			URL:                    fmt.Sprintf("url-%d", 3-i),
+			ApplicationDescription: "A " + iface + " service",
+			Endpoints: []dbmodel.ApplicationOfferRemoteEndpoint{{
+				Name:      "endpoint-1",
+				Role:      "provider",
+				Interface: iface,
+			}, {
+				Name:      "endpoint-2",
+				Role:      "provider",
+				Interface: iface,
+			}},
+		}
+		c.Assert(s.Database.AddApplicationOffer(ctx, &offer), qt.IsNil)
+	}
+
+	urls := func(offers []dbmodel.ApplicationOffer) []string {
+		var urls []string
+		for _, o := range offers {
+			urls = append(urls, o.URL)
+		}
+		return urls
+	}
+
+	offers, err := s.Database.SearchApplicationOffers(ctx, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(urls(offers), qt.DeepEquals, []string{"url-1", "url-2", "url-3"})
+	c.Check(offers[0].Model.Controller.Name, qt.Equals, "test-controller")
+	c.Check(offers[0].Model.CloudRegion.CloudName, qt.Equals, "test-cloud")
+	c.Check(offers[0].Endpoints, qt.HasLen, 2)
+
+	offers, err = s.Database.SearchApplicationOffers(ctx, 1, db.ApplicationOfferFilterByInterface("http"))
+	c.Assert(err, qt.IsNil)
+	c.Check(urls(offers), qt.DeepEquals, []string{"url-1"})
+
+	offers, err = s.Database.SearchApplicationOffers(ctx, 0, db.ApplicationOfferFilterByText("POSTGRESQL"))
+	c.Assert(err, qt.IsNil)
+	c.Check(urls(offers), qt.DeepEquals, []string{"url-3"})
+
+	offers, err = s.Database.SearchApplicationOffers(ctx, 0,
+		db.ApplicationOfferFilterByOwner("bob@canonical.com"),
+		db.ApplicationOfferFilterByCloud("test-cloud"),
+		db.ApplicationOfferFilterByText("app-2"),
+	)
+	c.Assert(err, qt.IsNil)
+	c.Check(urls(offers), qt.DeepEquals, []string{"url-2"})
+
+	offers, err = s.Database.SearchApplicationOffers(ctx, 0, db.ApplicationOfferFilterByOwner("alice@canonical.com"))
+	c.Assert(err, qt.IsNil)
+	c.Check(offers, qt.HasLen, 0)
+
+	offers, err = s.Database.SearchApplicationOffers(ctx, 0, db.ApplicationOfferFilterByCloud("other-cloud"))
+	c.Assert(err, qt.IsNil)
+	c.Check(offers, qt.HasLen, 0)
+}
@@ -116,6 +116,26 @@ func (d *Database) ForEachCloudCredential(ctx context.Context, identityName, clo
 	return nil
 }
 
+// GetCloudCredentialsByCloud returns all the cloud credentials for the
+// named cloud, ordered by owner and name.
+func (d *Database) GetCloudCredentialsByCloud(ctx context.Context, cloud string) (_ []dbmodel.CloudCredential, err error) {
+	const op = errors.Op("db.GetCloudCredentialsByCloud")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var creds []dbmodel.CloudCredential
+	db := d.DB.WithContext(ctx).Where("cloud_name = ?", cloud).Order("owner_identity_name, name")
+	if err := db.Find(&creds).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return creds, nil
+}
+
 // DeleteCloudCredential removes the given CloudCredential from the database.
 func (d *Database) DeleteCloudCredential(ctx context.Context, cred *dbmodel.CloudCredential) (err error) {
 	const op = errors.Op("db.DeleteCloudCredential")
@@ -302,3 +302,28 @@ func (s *dbSuite) TestForEachCloudCredential(c *qt.C) {
 		})
 	}
 }
+
+func (s *dbSuite) TestGetCloudCredentialsByCloud(c *qt.C) {
+	ctx := context.Background()
+
+	env := jimmtest.ParseEnvironment(c, forEachCloudCredentialEnv)
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDB(c, *s.Database)
+
+	creds, err := s.Database.GetCloudCredentialsByCloud(ctx, "cloud-1")
+	c.Assert(err, qt.IsNil)
+	var tags []string
+	for _, cred := range creds {
+		tags = append(tags, cred.ResourceTag().String())
+	}
+	c.Check(tags, qt.DeepEquals, []string{
+		names.NewCloudCredentialTag("cloud-1/alice@canonical.com/cred-1").String(),
+		names.NewCloudCredentialTag("cloud-1/alice@canonical.com/cred-5").String(),
+		names.NewCloudCredentialTag("cloud-1/bob@canonical.com/cred-2").String(),
+	})
+
+	creds, err = s.Database.GetCloudCredentialsByCloud(ctx, "no-such-cloud")
+	c.Assert(err, qt.IsNil)
+	c.Check(creds, qt.HasLen, 0)
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetDefaultCloudCredential records the given default cloud credential,
+// replacing any default the identity already has for the cloud.
+func (d *Database) SetDefaultCloudCredential(ctx context.Context, def *dbmodel.DefaultCloudCredential) (err error) {
+	const op = errors.Op("db.SetDefaultCloudCredential")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Omit("CloudCredential").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "identity_name"}, {Name: "cloud_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "cloud_credential_id"}),
+	}).Create(def).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetDefaultCloudCredential fills in the default cloud credential for
+// the identity and cloud in the given def. If the identity has no
+// default for the cloud an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetDefaultCloudCredential(ctx context.Context, def *dbmodel.DefaultCloudCredential) (err error) {
+	const op = errors.Op("db.GetDefaultCloudCredential")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Preload("CloudCredential").
+		Where("identity_name = ? AND cloud_name = ?", def.IdentityName, def.CloudName).
+		First(def).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteDefaultCloudCredential removes the identity's default cloud
+// credential for the cloud in the given def, if there is one.
+func (d *Database) DeleteDefaultCloudCredential(ctx context.Context, def *dbmodel.DefaultCloudCredential) (err error) {
+	const op = errors.Op("db.DeleteDefaultCloudCredential")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).
+		Where("identity_name = ? AND cloud_name = ?", def.IdentityName, def.CloudName).
+		Delete(&dbmodel.DefaultCloudCredential{}).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// SetRecentCloudCredential records the cloud credential the identity
+// last used in a cloud region.
+func (d *Database) SetRecentCloudCredential(ctx context.Context, rc *dbmodel.RecentCloudCredential) (err error) {
+	const op = errors.Op("db.SetRecentCloudCredential")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Omit("CloudCredential").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "identity_name"}, {Name: "cloud_name"}, {Name: "region_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "cloud_credential_id"}),
+	}).Create(rc).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetRecentCloudCredential fills in the cloud credential the identity
+// last used in the cloud region in the given rc. If the identity has not
+// created a model in the region an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetRecentCloudCredential(ctx context.Context, rc *dbmodel.RecentCloudCredential) (err error) {
+	const op = errors.Op("db.GetRecentCloudCredential")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Preload("CloudCredential").
+		Where("identity_name = ? AND cloud_name = ? AND region_name = ?", rc.IdentityName, rc.CloudName, rc.RegionName).
+		First(rc).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
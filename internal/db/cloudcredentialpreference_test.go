@@ -0,0 +1,134 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetDefaultCloudCredentialUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetDefaultCloudCredential(context.Background(), &dbmodel.DefaultCloudCredential{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func TestGetRecentCloudCredentialUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.GetRecentCloudCredential(context.Background(), &dbmodel.RecentCloudCredential{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) addPreferenceCredentials(c *qt.C) (dbmodel.Identity, dbmodel.CloudCredential, dbmodel.CloudCredential) {
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(u).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region",
+		}},
+	}
+	c.Assert(s.Database.DB.Create(&cloud).Error, qt.IsNil)
+
+	cred1 := dbmodel.CloudCredential{
+		Name:              "cred-1",
+		CloudName:         cloud.Name,
+		OwnerIdentityName: u.Name,
+		AuthType:          "empty",
+	}
+	c.Assert(s.Database.SetCloudCredential(context.Background(), &cred1), qt.IsNil)
+	cred2 := dbmodel.CloudCredential{
+		Name:              "cred-2",
+		CloudName:         cloud.Name,
+		OwnerIdentityName: u.Name,
+		AuthType:          "empty",
+	}
+	c.Assert(s.Database.SetCloudCredential(context.Background(), &cred2), qt.IsNil)
+	return *u, cred1, cred2
+}
+
+func (s *dbSuite) TestDefaultCloudCredential(c *qt.C) {
+	ctx := context.Background()
+	u, cred1, cred2 := s.addPreferenceCredentials(c)
+
+	def := dbmodel.DefaultCloudCredential{
+		IdentityName: u.Name,
+		CloudName:    "test-cloud",
+	}
+	err := s.Database.GetDefaultCloudCredential(ctx, &def)
+	c.Check(err, qt.ErrorMatches, `record not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	def.CloudCredentialID = cred1.ID
+	err = s.Database.SetDefaultCloudCredential(ctx, &def)
+	c.Assert(err, qt.IsNil)
+
+	def.CloudCredentialID = cred2.ID
+	err = s.Database.SetDefaultCloudCredential(ctx, &def)
+	c.Assert(err, qt.IsNil)
+
+	def2 := dbmodel.DefaultCloudCredential{
+		IdentityName: u.Name,
+		CloudName:    "test-cloud",
+	}
+	err = s.Database.GetDefaultCloudCredential(ctx, &def2)
+	c.Assert(err, qt.IsNil)
+	c.Check(def2.CloudCredentialID, qt.Equals, cred2.ID)
+	c.Check(def2.CloudCredential.Name, qt.Equals, "cred-2")
+
+	err = s.Database.DeleteDefaultCloudCredential(ctx, &def2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetDefaultCloudCredential(ctx, &def2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func (s *dbSuite) TestRecentCloudCredential(c *qt.C) {
+	ctx := context.Background()
+	u, cred1, cred2 := s.addPreferenceCredentials(c)
+
+	rc := dbmodel.RecentCloudCredential{
+		IdentityName:      u.Name,
+		CloudName:         "test-cloud",
+		RegionName:        "test-region",
+		CloudCredentialID: cred1.ID,
+	}
+	err := s.Database.SetRecentCloudCredential(ctx, &rc)
+	c.Assert(err, qt.IsNil)
+
+	rc.CloudCredentialID = cred2.ID
+	err = s.Database.SetRecentCloudCredential(ctx, &rc)
+	c.Assert(err, qt.IsNil)
+
+	rc2 := dbmodel.RecentCloudCredential{
+		IdentityName: u.Name,
+		CloudName:    "test-cloud",
+		RegionName:   "test-region",
+	}
+	err = s.Database.GetRecentCloudCredential(ctx, &rc2)
+	c.Assert(err, qt.IsNil)
+	c.Check(rc2.CloudCredential.Name, qt.Equals, "cred-2")
+
+	// Removing the credential removes the preference.
+	err = s.Database.DeleteCloudCredential(ctx, &cred2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetRecentCloudCredential(ctx, &rc2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
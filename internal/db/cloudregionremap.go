@@ -0,0 +1,215 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// cloudRegionNameColumns holds the columns of the records that refer to
+// a cloud region by name, along with the column holding the name of the
+// cloud. These are all updated when a whole region is remapped.
+var cloudRegionNameColumns = []struct {
+	table       string
+	cloudColumn string
+	column      string
+}{
+	{"controllers", "cloud_name", "cloud_region"},
+	{"domain_default_clouds", "cloud_name", "region_name"},
+}
+
+// RemapCloudRegion remaps the region named from in the given cloud to the
+// region described by to, for example after the cloud provider renamed
+// the region. If modelUUIDs is empty the whole region is remapped: its
+// models, model pools and controllers, and the defaults referring to it,
+// are moved to the new region, the old region is removed and an alias is
+// recorded so that the old name still refers to the new region.
+// Otherwise only the given models, which must be in the old region, are
+// moved, as when a region is split. If the new region does not exist it
+// is created, served by the controllers of the old region. The given
+// region is updated to hold the stored new region and the UUIDs of the
+// models remapped are returned. If the old region does not exist, or one
+// of the given models is not in it, an error with a code of CodeNotFound
+// is returned.
+func (d *Database) RemapCloudRegion(ctx context.Context, cloudName, from string, to *dbmodel.CloudRegion, modelUUIDs []string) (_ []string, err error) {
+	const op = errors.Op("db.RemapCloudRegion")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var remapped []string
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old dbmodel.CloudRegion
+		err := tx.Preload("Controllers").Where("cloud_name = ? AND name = ?", cloudName, from).First(&old).Error
+		if err == gorm.ErrRecordNotFound {
+			return errors.E(errors.CodeNotFound, fmt.Sprintf("cloud region %s/%s not found", cloudName, from))
+		}
+		if err != nil {
+			return err
+		}
+
+		models := func() *gorm.DB {
+			db := tx.Model(&dbmodel.Model{}).Where("cloud_region_id = ?", old.ID)
+			if len(modelUUIDs) > 0 {
+				db = db.Where("uuid IN ?", modelUUIDs)
+			}
+			return db
+		}
+		var uuids []sql.NullString
+		if err := models().Pluck("uuid", &uuids).Error; err != nil {
+			return err
+		}
+		found := make(map[string]bool)
+		for _, uuid := range uuids {
+			if uuid.Valid {
+				remapped = append(remapped, uuid.String)
+				found[uuid.String] = true
+			}
+		}
+		for _, uuid := range modelUUIDs {
+			if !found[uuid] {
+				return errors.E(errors.CodeNotFound, fmt.Sprintf("model %s not found in cloud region %s/%s", uuid, cloudName, from))
+			}
+		}
+
+		var region dbmodel.CloudRegion
+		err = tx.Preload("Controllers").Where("cloud_name = ? AND name = ?", cloudName, to.Name).First(&region).Error
+		switch {
+		case err == gorm.ErrRecordNotFound && len(modelUUIDs) == 0:
+			// The region is renamed in place, the records that
+			// refer to it by ID follow it.
+			region = old
+			region.Name = to.Name
+			region.Endpoint = to.Endpoint
+			region.IdentityEndpoint = to.IdentityEndpoint
+			region.StorageEndpoint = to.StorageEndpoint
+			if err := tx.Omit(clause.Associations).Save(&region).Error; err != nil {
+				return err
+			}
+		case err == gorm.ErrRecordNotFound:
+			region = dbmodel.CloudRegion{
+				CloudName:        cloudName,
+				Name:             to.Name,
+				Endpoint:         to.Endpoint,
+				IdentityEndpoint: to.IdentityEndpoint,
+				StorageEndpoint:  to.StorageEndpoint,
+				Config:           old.Config,
+			}
+			if err := tx.Omit(clause.Associations).Create(&region).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+
+		if region.ID != old.ID {
+			serving := make(map[uint]bool)
+			for _, p := range region.Controllers {
+				serving[p.ControllerID] = true
+			}
+			for _, p := range old.Controllers {
+				if serving[p.ControllerID] {
+					continue
+				}
+				np := dbmodel.CloudRegionControllerPriority{
+					CloudRegionID: region.ID,
+					ControllerID:  p.ControllerID,
+					Priority:      p.Priority,
+				}
+				if err := tx.Omit(clause.Associations).Create(&np).Error; err != nil {
+					return err
+				}
+				region.Controllers = append(region.Controllers, np)
+			}
+			if err := models().Update("cloud_region_id", region.ID).Error; err != nil {
+				return err
+			}
+		}
+		*to = region
+		if len(modelUUIDs) > 0 {
+			return nil
+		}
+
+		if region.ID != old.ID {
+			if err := tx.Model(&dbmodel.ModelPool{}).Where("cloud_region_id = ?", old.ID).Update("cloud_region_id", region.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Delete(&old).Error; err != nil {
+				return err
+			}
+		}
+		for _, c := range cloudRegionNameColumns {
+			err := tx.Table(c.table).Where(c.cloudColumn+" = ? AND "+c.column+" = ?", cloudName, from).Update(c.column, region.Name).Error
+			if err != nil {
+				return err
+			}
+		}
+		// Defaults already held for the new region are kept in
+		// preference to those of the old region.
+		err = tx.Exec(`UPDATE cloud_defaults SET region = ?
+			WHERE region = ? AND cloud_id = (SELECT id FROM clouds WHERE name = ?)
+			AND NOT EXISTS (
+				SELECT 1 FROM cloud_defaults d
+				WHERE d.identity_name = cloud_defaults.identity_name AND d.cloud_id = cloud_defaults.cloud_id AND d.region = ?
+			)`, region.Name, from, cloudName, region.Name).Error
+		if err != nil {
+			return err
+		}
+
+		// Aliases of the old region now refer to the new region, and
+		// the new name is no longer an alias.
+		err = tx.Model(&dbmodel.CloudRegionAlias{}).Where("cloud_name = ? AND region_name = ?", cloudName, from).Update("region_name", region.Name).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("cloud_name = ? AND alias = ?", cloudName, region.Name).Delete(&dbmodel.CloudRegionAlias{}).Error; err != nil {
+			return err
+		}
+		alias := dbmodel.CloudRegionAlias{
+			CloudName:  cloudName,
+			Alias:      from,
+			RegionName: region.Name,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "cloud_name"}, {Name: "alias"}},
+			DoUpdates: clause.AssignmentColumns([]string{"region_name"}),
+		}).Create(&alias).Error
+	})
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return remapped, nil
+}
+
+// GetCloudRegionAlias fills in the given cloud region alias, which is
+// found by its cloud name and alias. If there is no such alias an error
+// with a code of CodeNotFound is returned.
+func (d *Database) GetCloudRegionAlias(ctx context.Context, alias *dbmodel.CloudRegionAlias) (err error) {
+	const op = errors.Op("db.GetCloudRegionAlias")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("cloud_name = ? AND alias = ?", alias.CloudName, alias.Alias).First(alias).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestRemapCloudRegion(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	crp := dbmodel.CloudRegionControllerPriority{
+		CloudRegionID: env.cloud.Regions[0].ID,
+		ControllerID:  env.controller.ID,
+		Priority:      dbmodel.CloudRegionControllerPrioritySupported,
+	}
+	c.Assert(s.Database.DB.Create(&crp).Error, qt.IsNil)
+
+	m1 := env.model
+	m2 := env.model
+	m2.ID = 0
+	m2.Name = "test-model-2"
+	m2.UUID = sql.NullString{String: "00000001-0000-0000-0000-0000-000000000002", Valid: true}
+	err := s.Database.AddModel(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.Database.RemapCloudRegion(ctx, "test-cloud", "no-such-region", &dbmodel.CloudRegion{Name: "test-region-b"}, nil)
+	c.Check(err, qt.ErrorMatches, `cloud region test-cloud/no-such-region not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	_, err = s.Database.RemapCloudRegion(ctx, "test-cloud", "test-region", &dbmodel.CloudRegion{Name: "test-region-b"}, []string{"00000001-0000-0000-0000-0000-000000000003"})
+	c.Check(err, qt.ErrorMatches, `model 00000001-0000-0000-0000-0000-000000000003 not found in cloud region test-cloud/test-region`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Splitting the region moves only the given models.
+	regionB := dbmodel.CloudRegion{Name: "test-region-b", Endpoint: "https://b.example.com"}
+	remapped, err := s.Database.RemapCloudRegion(ctx, "test-cloud", "test-region", &regionB, []string{m2.UUID.String})
+	c.Assert(err, qt.IsNil)
+	c.Check(remapped, qt.DeepEquals, []string{m2.UUID.String})
+	c.Check(regionB.ID, qt.Not(qt.Equals), env.cloud.Regions[0].ID)
+	c.Assert(regionB.Controllers, qt.HasLen, 1)
+	c.Check(regionB.Controllers[0].ControllerID, qt.Equals, env.controller.ID)
+
+	err = s.Database.GetModel(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+	c.Check(m2.CloudRegion.Name, qt.Equals, "test-region-b")
+	err = s.Database.GetModel(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	c.Check(m1.CloudRegion.Name, qt.Equals, "test-region")
+
+	// Remapping the whole region to a new name renames it.
+	regionA := dbmodel.CloudRegion{Name: "test-region-a"}
+	remapped, err = s.Database.RemapCloudRegion(ctx, "test-cloud", "test-region", &regionA, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(remapped, qt.DeepEquals, []string{m1.UUID.String})
+	c.Check(regionA.ID, qt.Equals, env.cloud.Regions[0].ID)
+
+	ctl := dbmodel.Controller{Name: env.controller.Name}
+	err = s.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.CloudRegion, qt.Equals, "test-region-a")
+
+	alias := dbmodel.CloudRegionAlias{CloudName: "test-cloud", Alias: "test-region"}
+	err = s.Database.GetCloudRegionAlias(ctx, &alias)
+	c.Assert(err, qt.IsNil)
+	c.Check(alias.RegionName, qt.Equals, "test-region-a")
+
+	// Remapping the whole region to an existing region merges them.
+	regionB = dbmodel.CloudRegion{Name: "test-region-b"}
+	remapped, err = s.Database.RemapCloudRegion(ctx, "test-cloud", "test-region-a", &regionB, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(remapped, qt.DeepEquals, []string{m1.UUID.String})
+
+	err = s.Database.GetModel(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	c.Check(m1.CloudRegion.Name, qt.Equals, "test-region-b")
+
+	cloud := dbmodel.Cloud{Name: "test-cloud"}
+	err = s.Database.GetCloud(ctx, &cloud)
+	c.Assert(err, qt.IsNil)
+	c.Assert(cloud.Regions, qt.HasLen, 1)
+	c.Check(cloud.Regions[0].Name, qt.Equals, "test-region-b")
+
+	for _, name := range []string{"test-region", "test-region-a"} {
+		alias := dbmodel.CloudRegionAlias{CloudName: "test-cloud", Alias: name}
+		err = s.Database.GetCloudRegionAlias(ctx, &alias)
+		c.Assert(err, qt.IsNil)
+		c.Check(alias.RegionName, qt.Equals, "test-region-b")
+	}
+	alias = dbmodel.CloudRegionAlias{CloudName: "test-cloud", Alias: "test-region-b"}
+	err = s.Database.GetCloudRegionAlias(ctx, &alias)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -31,6 +31,27 @@ func (d *Database) AddController(ctx context.Context, controller *dbmodel.Contro
 	return nil
 }
 
+// LockControllerUUID takes a lock on the given controller UUID that is
+// held until the end of the current transaction, so that concurrent
+// transactions adding controllers with the same UUID are serialised.
+// LockControllerUUID must be called within a transaction.
+func (d *Database) LockControllerUUID(ctx context.Context, uuid string) (err error) {
+	const op = errors.Op("db.LockControllerUUID")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "controller-uuid:"+uuid).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
 // GetController returns controller information based on the
 // controller UUID or name.
 func (d *Database) GetController(ctx context.Context, controller *dbmodel.Controller) (err error) {
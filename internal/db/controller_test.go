@@ -5,6 +5,7 @@ package db_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -98,6 +99,60 @@ func (s *dbSuite) TestGetController(c *qt.C) {
 	c.Assert(eError.Code, qt.Equals, errors.CodeNotFound)
 }
 
+func TestLockControllerUUIDUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.LockControllerUUID(context.Background(), "00000000-0000-0000-0000-0000-0000000000001")
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestLockControllerUUID(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.Equals, nil)
+
+	const uuid = "00000000-0000-0000-0000-0000-0000000000001"
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Database.Transaction(func(tx *db.Database) error {
+			if err := tx.LockControllerUUID(ctx, uuid); err != nil {
+				close(locked)
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- s.Database.Transaction(func(tx *db.Database) error {
+			return tx.LockControllerUUID(ctx, uuid)
+		})
+	}()
+
+	// A different UUID is not blocked.
+	err = s.Database.Transaction(func(tx *db.Database) error {
+		return tx.LockControllerUUID(ctx, "00000000-0000-0000-0000-0000-0000000000002")
+	})
+	c.Assert(err, qt.IsNil)
+
+	select {
+	case err := <-acquired:
+		c.Fatalf("lock acquired while held by another transaction: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	c.Assert(<-done, qt.IsNil)
+	c.Assert(<-acquired, qt.IsNil)
+}
+
 func TestForEachControllerUnconfiguredDatabase(t *testing.T) {
 	c := qt.New(t)
 
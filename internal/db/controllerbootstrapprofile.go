@@ -0,0 +1,82 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddControllerBootstrapProfile stores the given controller bootstrap
+// profile as the next version of the controller's profile. The Version
+// of the given profile is set to the version stored.
+func (d *Database) AddControllerBootstrapProfile(ctx context.Context, p *dbmodel.ControllerBootstrapProfile) (err error) {
+	const op = errors.Op("db.AddControllerBootstrapProfile")
+
+	if p.ControllerName == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing controller name")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		err := tx.Model(&dbmodel.ControllerBootstrapProfile{}).
+			Where("controller_name = ?", p.ControllerName).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		p.Version = latest + 1
+		return tx.Create(p).Error
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetControllerBootstrapProfile fills in the given controller bootstrap
+// profile. The ControllerName must be set. If the Version is zero the
+// latest version is returned. If there is no such profile an error with
+// a code of CodeNotFound is returned.
+func (d *Database) GetControllerBootstrapProfile(ctx context.Context, p *dbmodel.ControllerBootstrapProfile) (err error) {
+	const op = errors.Op("db.GetControllerBootstrapProfile")
+
+	if p.ControllerName == "" {
+		return errors.E(op, errors.CodeNotFound, "controller bootstrap profile not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("controller_name = ?", p.ControllerName)
+	if p.Version > 0 {
+		db = db.Where("version = ?", p.Version)
+	} else {
+		db = db.Order("version DESC")
+	}
+	if err := db.First(p).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, err, "controller bootstrap profile not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
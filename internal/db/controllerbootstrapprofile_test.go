@@ -0,0 +1,75 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestControllerBootstrapProfile(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddControllerBootstrapProfile(ctx, &dbmodel.ControllerBootstrapProfile{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+	}
+	err = s.Database.AddCloud(ctx, &cloud)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddController(ctx, &dbmodel.Controller{
+		Name:      "controller-1",
+		UUID:      "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName: "test-cloud",
+	})
+	c.Assert(err, qt.IsNil)
+
+	p := dbmodel.ControllerBootstrapProfile{ControllerName: "controller-1"}
+	err = s.Database.GetControllerBootstrapProfile(ctx, &p)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	p1 := dbmodel.ControllerBootstrapProfile{
+		ControllerName:   "controller-1",
+		AgentVersion:     "3.5.0",
+		CloudName:        "test-cloud",
+		CloudRegion:      "test-region",
+		ControllerConfig: dbmodel.Map{"api-port": float64(17070)},
+		Cloud:            dbmodel.JSON(`{"type":"ec2"}`),
+		Hash:             "hash-1",
+	}
+	err = s.Database.AddControllerBootstrapProfile(ctx, &p1)
+	c.Assert(err, qt.IsNil)
+	c.Check(p1.Version, qt.Equals, 1)
+
+	p2 := p1
+	p2.ID = 0
+	p2.AgentVersion = "3.5.1"
+	p2.Hash = "hash-2"
+	err = s.Database.AddControllerBootstrapProfile(ctx, &p2)
+	c.Assert(err, qt.IsNil)
+	c.Check(p2.Version, qt.Equals, 2)
+
+	latest := dbmodel.ControllerBootstrapProfile{ControllerName: "controller-1"}
+	err = s.Database.GetControllerBootstrapProfile(ctx, &latest)
+	c.Assert(err, qt.IsNil)
+	c.Check(latest.Version, qt.Equals, 2)
+	c.Check(latest.AgentVersion, qt.Equals, "3.5.1")
+	c.Check(latest.ControllerConfig, qt.DeepEquals, dbmodel.Map{"api-port": float64(17070)})
+	c.Check(string(latest.Cloud), qt.JSONEquals, map[string]any{"type": "ec2"})
+
+	first := dbmodel.ControllerBootstrapProfile{ControllerName: "controller-1", Version: 1}
+	err = s.Database.GetControllerBootstrapProfile(ctx, &first)
+	c.Assert(err, qt.IsNil)
+	c.Check(first.Hash, qt.Equals, "hash-1")
+
+	missing := dbmodel.ControllerBootstrapProfile{ControllerName: "controller-1", Version: 3}
+	err = s.Database.GetControllerBootstrapProfile(ctx, &missing)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetControllerModelCredential fills in the given controller model
+// credential. The ControllerName must be set. If no credential has been
+// recorded for the controller an error with a code of CodeNotFound is
+// returned.
+func (d *Database) GetControllerModelCredential(ctx context.Context, cred *dbmodel.ControllerModelCredential) (err error) {
+	const op = errors.Op("db.GetControllerModelCredential")
+
+	if cred.ControllerName == "" {
+		return errors.E(op, errors.CodeNotFound, "controller model credential not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).First(cred, "controller_name = ?", cred.ControllerName).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ForEachControllerModelCredential iterates through every recorded
+// controller model credential, in order of controller name, calling the
+// given function for each. If the function returns an error the
+// iteration stops and the error is returned.
+func (d *Database) ForEachControllerModelCredential(ctx context.Context, f func(*dbmodel.ControllerModelCredential) error) (err error) {
+	const op = errors.Op("db.ForEachControllerModelCredential")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var creds []dbmodel.ControllerModelCredential
+	if err := d.DB.WithContext(ctx).Order("controller_name").Find(&creds).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	for i := range creds {
+		if err := f(&creds[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertControllerModelCredential stores the given controller model
+// credential, replacing any already recorded for the controller.
+func (d *Database) UpsertControllerModelCredential(ctx context.Context, cred *dbmodel.ControllerModelCredential) (err error) {
+	const op = errors.Op("db.UpsertControllerModelCredential")
+
+	if cred.ControllerName == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing controller name")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "controller_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "credential_tag", "valid", "checked_at", "expires_at", "rotated_at"}),
+	})
+	if err := db.Create(cred).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
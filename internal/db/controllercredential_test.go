@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestControllerModelCredential(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.UpsertControllerModelCredential(ctx, &dbmodel.ControllerModelCredential{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+	}
+	err = s.Database.AddCloud(ctx, &cloud)
+	c.Assert(err, qt.IsNil)
+	for _, name := range []string{"controller-2", "controller-1"} {
+		err = s.Database.AddController(ctx, &dbmodel.Controller{
+			Name:      name,
+			UUID:      "00000000-0000-0000-0000-0000-0000000000001",
+			CloudName: "test-cloud",
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	cred := dbmodel.ControllerModelCredential{
+		ControllerName: "controller-1",
+	}
+	err = s.Database.GetControllerModelCredential(ctx, &cred)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	err = s.Database.UpsertControllerModelCredential(ctx, &dbmodel.ControllerModelCredential{
+		ControllerName: "controller-1",
+		CredentialTag:  "cloudcred-test-cloud_admin_default",
+		CheckedAt:      sql.NullTime{Time: now, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+	expires := now.Add(7 * 24 * time.Hour)
+	err = s.Database.UpsertControllerModelCredential(ctx, &dbmodel.ControllerModelCredential{
+		ControllerName: "controller-1",
+		CredentialTag:  "cloudcred-test-cloud_admin_rotated",
+		Valid:          sql.NullBool{Bool: true, Valid: true},
+		CheckedAt:      sql.NullTime{Time: now, Valid: true},
+		ExpiresAt:      sql.NullTime{Time: expires, Valid: true},
+		RotatedAt:      sql.NullTime{Time: now, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.UpsertControllerModelCredential(ctx, &dbmodel.ControllerModelCredential{
+		ControllerName: "controller-2",
+		CredentialTag:  "cloudcred-test-cloud_admin_default",
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetControllerModelCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+	c.Check(cred.CredentialTag, qt.Equals, "cloudcred-test-cloud_admin_rotated")
+	c.Check(cred.Valid, qt.Equals, sql.NullBool{Bool: true, Valid: true})
+	c.Check(cred.ExpiresAt.Time.UTC(), qt.Equals, expires)
+	c.Check(cred.RotatedAt.Time.UTC(), qt.Equals, now)
+
+	var names []string
+	err = s.Database.ForEachControllerModelCredential(ctx, func(cred *dbmodel.ControllerModelCredential) error {
+		names = append(names, cred.ControllerName)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(names, qt.DeepEquals, []string{"controller-1", "controller-2"})
+
+	// The credential is removed with its controller.
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = s.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetControllerModelCredential(ctx, &cred)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// ClaimControllerMonitor attempts to claim the monitoring of the
+// controller named in the given monitor for the monitor's holder and
+// nonce. The controller is claimed if it is not yet claimed, is already
+// claimed with the same nonce, or the claim's heartbeat is older than
+// staleAfter. Heartbeats are compared using the database clock.
+// ClaimControllerMonitor reports whether the monitor holds the claim
+// after the call.
+func (d *Database) ClaimControllerMonitor(ctx context.Context, m *dbmodel.ControllerMonitor, staleAfter time.Duration) (_ bool, err error) {
+	const op = errors.Op("db.ClaimControllerMonitor")
+
+	if m.ControllerName == "" || m.Nonce == "" {
+		return false, errors.E(op, errors.CodeBadRequest, "missing controller name or nonce")
+	}
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	// Only take over an existing claim when it is already ours or its
+	// holder has stopped sending heartbeats. If neither is the case no
+	// rows are affected.
+	result := d.DB.WithContext(ctx).Exec(`
+		INSERT INTO controller_monitors (controller_name, holder, nonce, claimed_at, heartbeat)
+		VALUES (?, ?, ?, NOW(), NOW())
+		ON CONFLICT (controller_name) DO UPDATE SET
+			holder = excluded.holder,
+			nonce = excluded.nonce,
+			claimed_at = CASE WHEN controller_monitors.nonce = excluded.nonce THEN controller_monitors.claimed_at ELSE excluded.claimed_at END,
+			heartbeat = excluded.heartbeat
+		WHERE controller_monitors.nonce = excluded.nonce OR controller_monitors.heartbeat < NOW() - make_interval(secs => ?)`,
+		m.ControllerName, m.Holder, m.Nonce, staleAfter.Seconds(),
+	)
+	if result.Error != nil {
+		return false, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RenewControllerMonitor updates the heartbeat of the given monitor's
+// claim on its controller. RenewControllerMonitor reports whether the
+// claim is still held with the monitor's nonce, if it is not the
+// heartbeat is not updated.
+func (d *Database) RenewControllerMonitor(ctx context.Context, m *dbmodel.ControllerMonitor) (_ bool, err error) {
+	const op = errors.Op("db.RenewControllerMonitor")
+
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Exec(
+		`UPDATE controller_monitors SET heartbeat = NOW() WHERE controller_name = ? AND nonce = ?`,
+		m.ControllerName, m.Nonce,
+	)
+	if result.Error != nil {
+		return false, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// CheckControllerMonitor returns an error with a code of
+// CodeMonitorConflict if the controller named in the given monitor is
+// not claimed with the monitor's nonce. Watchers call this in the
+// transactions that apply updates so that the updates are only written
+// whilst the claim is held.
+func (d *Database) CheckControllerMonitor(ctx context.Context, m *dbmodel.ControllerMonitor) (err error) {
+	const op = errors.Op("db.CheckControllerMonitor")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var count int64
+	err = d.DB.WithContext(ctx).
+		Model(&dbmodel.ControllerMonitor{}).
+		Where("controller_name = ? AND nonce = ?", m.ControllerName, m.Nonce).
+		Count(&count).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	if count == 0 {
+		return errors.E(op, errors.CodeMonitorConflict, "controller "+m.ControllerName+" is monitored by another watcher")
+	}
+	return nil
+}
+
+// GetControllerMonitor fills in the current claim on the controller named
+// in the given monitor. If the controller is not claimed an error with a
+// code of CodeNotFound is returned.
+func (d *Database) GetControllerMonitor(ctx context.Context, m *dbmodel.ControllerMonitor) (err error) {
+	const op = errors.Op("db.GetControllerMonitor")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("controller_name = ?", m.ControllerName).First(m).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ReleaseControllerMonitor releases the given monitor's claim on its
+// controller if it is still held with the monitor's nonce. Releasing a
+// claim that is not held is not an error.
+func (d *Database) ReleaseControllerMonitor(ctx context.Context, m *dbmodel.ControllerMonitor) (err error) {
+	const op = errors.Op("db.ReleaseControllerMonitor")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("controller_name = ? AND nonce = ?", m.ControllerName, m.Nonce)
+	if err := db.Delete(&dbmodel.ControllerMonitor{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
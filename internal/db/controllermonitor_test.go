@@ -0,0 +1,97 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestClaimControllerMonitorUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.ClaimControllerMonitor(context.Background(), &dbmodel.ControllerMonitor{ControllerName: "controller-1", Nonce: "nonce-1"}, time.Minute)
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestControllerMonitor(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddCloud(ctx, &dbmodel.Cloud{Name: "test-cloud"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddController(ctx, &dbmodel.Controller{
+		Name:      "controller-1",
+		UUID:      "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName: "test-cloud",
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.Database.ClaimControllerMonitor(ctx, &dbmodel.ControllerMonitor{ControllerName: "controller-1"}, time.Minute)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	m1 := dbmodel.ControllerMonitor{ControllerName: "controller-1", Holder: "replica-1", Nonce: "nonce-1"}
+	m2 := dbmodel.ControllerMonitor{ControllerName: "controller-1", Holder: "replica-2", Nonce: "nonce-2"}
+
+	// The first watcher claims the controller.
+	claimed, err := s.Database.ClaimControllerMonitor(ctx, &m1, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Check(claimed, qt.IsTrue)
+	err = s.Database.CheckControllerMonitor(ctx, &m1)
+	c.Check(err, qt.IsNil)
+
+	// Another watcher cannot claim a controller with a current claim.
+	claimed, err = s.Database.ClaimControllerMonitor(ctx, &m2, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Check(claimed, qt.IsFalse)
+	err = s.Database.CheckControllerMonitor(ctx, &m2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeMonitorConflict)
+	held, err := s.Database.RenewControllerMonitor(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+	c.Check(held, qt.IsFalse)
+
+	held, err = s.Database.RenewControllerMonitor(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	c.Check(held, qt.IsTrue)
+
+	// A stale claim is taken over.
+	err = s.Database.DB.Exec(`UPDATE controller_monitors SET heartbeat = NOW() - INTERVAL '2 minutes'`).Error
+	c.Assert(err, qt.IsNil)
+	claimed, err = s.Database.ClaimControllerMonitor(ctx, &m2, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Check(claimed, qt.IsTrue)
+
+	// The previous watcher finds it has lost its claim.
+	held, err = s.Database.RenewControllerMonitor(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	c.Check(held, qt.IsFalse)
+	err = s.Database.CheckControllerMonitor(ctx, &m1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeMonitorConflict)
+
+	m := dbmodel.ControllerMonitor{ControllerName: "controller-1"}
+	err = s.Database.GetControllerMonitor(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Holder, qt.Equals, "replica-2")
+	c.Check(m.Nonce, qt.Equals, "nonce-2")
+
+	// Releasing a lost claim leaves the current claim in place.
+	err = s.Database.ReleaseControllerMonitor(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.CheckControllerMonitor(ctx, &m2)
+	c.Check(err, qt.IsNil)
+
+	err = s.Database.ReleaseControllerMonitor(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetControllerMonitor(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetCostCenterGroups returns every group that has a cost center, ordered
+// by group name.
+func (d *Database) GetCostCenterGroups(ctx context.Context) (_ []dbmodel.GroupEntry, err error) {
+	const op = errors.Op("db.GetCostCenterGroups")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var groups []dbmodel.GroupEntry
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("cost_center <> ''").Order("name asc").Find(&groups).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return groups, nil
+}
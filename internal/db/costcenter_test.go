@@ -0,0 +1,50 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestGetCostCenterGroupsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.GetCostCenterGroups(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestGetCostCenterGroups(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for _, name := range []string{"group-c", "group-b", "group-a"} {
+		_, err := s.Database.AddGroup(ctx, name)
+		c.Assert(err, qt.IsNil)
+	}
+	groups, err := s.Database.GetCostCenterGroups(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(groups, qt.HasLen, 0)
+
+	for _, name := range []string{"group-c", "group-a"} {
+		ge, err := s.Database.AddGroup(ctx, name+"-billed")
+		c.Assert(err, qt.IsNil)
+		ge.CostCenter = "CC-" + name
+		err = s.Database.UpdateGroup(ctx, ge)
+		c.Assert(err, qt.IsNil)
+	}
+	groups, err = s.Database.GetCostCenterGroups(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(groups, qt.HasLen, 2)
+	c.Check(groups[0].Name, qt.Equals, "group-a-billed")
+	c.Check(groups[0].CostCenter, qt.Equals, "CC-group-a")
+	c.Check(groups[1].Name, qt.Equals, "group-c-billed")
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// RecordCredentialPropagation records that the credential in the given
+// propagation has been copied to its controller. Any earlier record for
+// the same credential and controller is replaced, clearing its
+// revocation.
+func (d *Database) RecordCredentialPropagation(ctx context.Context, p *dbmodel.CredentialPropagation) (err error) {
+	const op = errors.Op("db.RecordCredentialPropagation")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+	if p.Credential == "" || p.ControllerName == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing credential or controller")
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "credential"}, {Name: "controller_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "controller_uuid", "propagated_at", "jimm_version", "revoked_at", "removal_confirmed_at", "verification_attempts", "last_verified_at", "verification_error", "alerted_at"}),
+	})
+	if err := db.Create(p).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// RevokeCredentialPropagations records that the credential with the
+// given path was revoked at the given time on every propagation of the
+// credential not already revoked.
+func (d *Database) RevokeCredentialPropagations(ctx context.Context, credential string, t time.Time) (err error) {
+	const op = errors.Op("db.RevokeCredentialPropagations")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(&dbmodel.CredentialPropagation{})
+	db = db.Where("credential = ? AND revoked_at IS NULL", credential)
+	if err := db.Updates(map[string]interface{}{"revoked_at": t, "updated_at": time.Now()}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ConfirmCredentialRemoval records that the named controller confirmed,
+// at the given time, that its copy of the revoked credential with the
+// given path has been removed. Propagations that have not been revoked
+// are not changed.
+func (d *Database) ConfirmCredentialRemoval(ctx context.Context, credential, controllerName string, t time.Time) (err error) {
+	const op = errors.Op("db.ConfirmCredentialRemoval")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(&dbmodel.CredentialPropagation{})
+	db = db.Where("credential = ? AND controller_name = ? AND revoked_at IS NOT NULL", credential, controllerName)
+	if err := db.Updates(map[string]interface{}{"removal_confirmed_at": t, "verification_error": "", "updated_at": time.Now()}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListUnconfirmedCredentialRemovals returns at most limit propagations
+// of revoked credentials whose removal has not been confirmed, those
+// least recently verified first.
+func (d *Database) ListUnconfirmedCredentialRemovals(ctx context.Context, limit int) (_ []dbmodel.CredentialPropagation, err error) {
+	const op = errors.Op("db.ListUnconfirmedCredentialRemovals")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("revoked_at IS NOT NULL AND removal_confirmed_at IS NULL")
+	db = db.Order("last_verified_at NULLS FIRST, id").Limit(limit)
+	var propagations []dbmodel.CredentialPropagation
+	if err := db.Find(&propagations).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return propagations, nil
+}
+
+// UpdateCredentialRemovalVerification stores the verification status of
+// the removal of the revoked credential in the given propagation.
+func (d *Database) UpdateCredentialRemovalVerification(ctx context.Context, p *dbmodel.CredentialPropagation) (err error) {
+	const op = errors.Op("db.UpdateCredentialRemovalVerification")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(p)
+	db = db.Select("updated_at", "verification_attempts", "last_verified_at", "verification_error", "alerted_at")
+	if err := db.Updates(p).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListCredentialPropagations returns the recorded propagations ordered by
+// credential and controller name. If credential is not empty only the
+// propagations of the credential with that path are returned.
+func (d *Database) ListCredentialPropagations(ctx context.Context, credential string) (_ []dbmodel.CredentialPropagation, err error) {
+	const op = errors.Op("db.ListCredentialPropagations")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Order("credential, controller_name")
+	if credential != "" {
+		db = db.Where("credential = ?", credential)
+	}
+	var propagations []dbmodel.CredentialPropagation
+	if err := db.Find(&propagations).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return propagations, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestCredentialPropagations(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{ControllerName: "controller-1"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, ctl := range []string{"controller-2", "controller-1"} {
+		err = s.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+			Credential:     "aws/alice@canonical.com/cred",
+			ControllerName: ctl,
+			ControllerUUID: "00000001-0000-0000-0000-000000000001",
+			PropagatedAt:   t1,
+			JIMMVersion:    "3.1.0",
+		})
+		c.Assert(err, qt.IsNil)
+	}
+	err = s.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+		Credential:     "aws/bob@canonical.com/cred",
+		ControllerName: "controller-1",
+		ControllerUUID: "00000001-0000-0000-0000-000000000001",
+		PropagatedAt:   t1,
+		JIMMVersion:    "3.1.0",
+	})
+	c.Assert(err, qt.IsNil)
+
+	propagations, err := s.Database.ListCredentialPropagations(ctx, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(propagations, qt.HasLen, 3)
+	c.Check(propagations[0].Credential, qt.Equals, "aws/alice@canonical.com/cred")
+	c.Check(propagations[0].ControllerName, qt.Equals, "controller-1")
+	c.Check(propagations[1].ControllerName, qt.Equals, "controller-2")
+	c.Check(propagations[2].Credential, qt.Equals, "aws/bob@canonical.com/cred")
+
+	// Revoking marks every copy of the credential, confirmations are
+	// recorded for each controller.
+	t2 := t1.Add(time.Hour)
+	err = s.Database.RevokeCredentialPropagations(ctx, "aws/alice@canonical.com/cred", t2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.ConfirmCredentialRemoval(ctx, "aws/alice@canonical.com/cred", "controller-1", t2)
+	c.Assert(err, qt.IsNil)
+	// Removal of a credential that was not revoked is not recorded.
+	err = s.Database.ConfirmCredentialRemoval(ctx, "aws/bob@canonical.com/cred", "controller-1", t2)
+	c.Assert(err, qt.IsNil)
+
+	propagations, err = s.Database.ListCredentialPropagations(ctx, "aws/alice@canonical.com/cred")
+	c.Assert(err, qt.IsNil)
+	c.Assert(propagations, qt.HasLen, 2)
+	c.Check(propagations[0].RevokedAt.Time.Equal(t2), qt.IsTrue)
+	c.Check(propagations[0].RemovalConfirmedAt.Time.Equal(t2), qt.IsTrue)
+	c.Check(propagations[1].RevokedAt.Time.Equal(t2), qt.IsTrue)
+	c.Check(propagations[1].RemovalConfirmedAt.Valid, qt.IsFalse)
+	propagations, err = s.Database.ListCredentialPropagations(ctx, "aws/bob@canonical.com/cred")
+	c.Assert(err, qt.IsNil)
+	c.Assert(propagations, qt.HasLen, 1)
+	c.Check(propagations[0].RevokedAt.Valid, qt.IsFalse)
+	c.Check(propagations[0].RemovalConfirmedAt.Valid, qt.IsFalse)
+
+	// Only the unconfirmed removal needs verifying.
+	unconfirmed, err := s.Database.ListUnconfirmedCredentialRemovals(ctx, 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(unconfirmed, qt.HasLen, 1)
+	c.Check(unconfirmed[0].ControllerName, qt.Equals, "controller-2")
+	unconfirmed[0].VerificationAttempts = 2
+	unconfirmed[0].LastVerifiedAt = sql.NullTime{Time: t2, Valid: true}
+	unconfirmed[0].VerificationError = "credential still present"
+	unconfirmed[0].AlertedAt = sql.NullTime{Time: t2, Valid: true}
+	err = s.Database.UpdateCredentialRemovalVerification(ctx, &unconfirmed[0])
+	c.Assert(err, qt.IsNil)
+	unconfirmed, err = s.Database.ListUnconfirmedCredentialRemovals(ctx, 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(unconfirmed, qt.HasLen, 1)
+	c.Check(unconfirmed[0].VerificationAttempts, qt.Equals, 2)
+	c.Check(unconfirmed[0].LastVerifiedAt.Time.Equal(t2), qt.IsTrue)
+	c.Check(unconfirmed[0].VerificationError, qt.Equals, "credential still present")
+	c.Check(unconfirmed[0].AlertedAt.Time.Equal(t2), qt.IsTrue)
+
+	// Confirming the removal clears the verification error.
+	err = s.Database.ConfirmCredentialRemoval(ctx, "aws/alice@canonical.com/cred", "controller-2", t2)
+	c.Assert(err, qt.IsNil)
+	unconfirmed, err = s.Database.ListUnconfirmedCredentialRemovals(ctx, 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(unconfirmed, qt.HasLen, 0)
+	propagations, err = s.Database.ListCredentialPropagations(ctx, "aws/alice@canonical.com/cred")
+	c.Assert(err, qt.IsNil)
+	c.Check(propagations[1].VerificationError, qt.Equals, "")
+
+	// Copying the credential again clears the revocation.
+	t3 := t2.Add(time.Hour)
+	err = s.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+		Credential:     "aws/alice@canonical.com/cred",
+		ControllerName: "controller-1",
+		ControllerUUID: "00000001-0000-0000-0000-000000000001",
+		PropagatedAt:   t3,
+		JIMMVersion:    "3.2.0",
+	})
+	c.Assert(err, qt.IsNil)
+	propagations, err = s.Database.ListCredentialPropagations(ctx, "aws/alice@canonical.com/cred")
+	c.Assert(err, qt.IsNil)
+	c.Assert(propagations, qt.HasLen, 2)
+	c.Check(propagations[0].PropagatedAt.Equal(t3), qt.IsTrue)
+	c.Check(propagations[0].JIMMVersion, qt.Equals, "3.2.0")
+	c.Check(propagations[0].RevokedAt.Valid, qt.IsFalse)
+	c.Check(propagations[0].RemovalConfirmedAt.Valid, qt.IsFalse)
+	c.Check(propagations[1].VerificationAttempts, qt.Equals, 2)
+	err = s.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+		Credential:     "aws/alice@canonical.com/cred",
+		ControllerName: "controller-2",
+		ControllerUUID: "00000001-0000-0000-0000-000000000001",
+		PropagatedAt:   t3,
+		JIMMVersion:    "3.2.0",
+	})
+	c.Assert(err, qt.IsNil)
+	propagations, err = s.Database.ListCredentialPropagations(ctx, "aws/alice@canonical.com/cred")
+	c.Assert(err, qt.IsNil)
+	c.Check(propagations[1].VerificationAttempts, qt.Equals, 0)
+	c.Check(propagations[1].LastVerifiedAt.Valid, qt.IsFalse)
+	c.Check(propagations[1].AlertedAt.Valid, qt.IsFalse)
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetCredentialUpdateRetry queues the given credential update retry,
+// replacing any retry already queued for the credential and controller.
+func (d *Database) SetCredentialUpdateRetry(ctx context.Context, r *dbmodel.CredentialUpdateRetry) (err error) {
+	const op = errors.Op("db.SetCredentialUpdateRetry")
+	if r.CloudCredentialID == 0 || r.ControllerID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing cloud credential or controller ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("CloudCredential", "Controller").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cloud_credential_id"}, {Name: "controller_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "attempts", "last_error", "next_attempt"}),
+	})
+	if err := db.Create(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetCredentialUpdateRetry completes the given credential update retry,
+// which is identified by its CloudCredentialID and ControllerID. If no
+// retry is queued an error with the code CodeNotFound is returned.
+func (d *Database) GetCredentialUpdateRetry(ctx context.Context, r *dbmodel.CredentialUpdateRetry) (err error) {
+	const op = errors.Op("db.GetCredentialUpdateRetry")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("cloud_credential_id = ? AND controller_id = ?", r.CloudCredentialID, r.ControllerID)
+	if err := db.First(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteCredentialUpdateRetry removes the credential update retry queued
+// for the CloudCredentialID and ControllerID of the given retry. Removing
+// a retry that is not queued is not an error.
+func (d *Database) DeleteCredentialUpdateRetry(ctx context.Context, r *dbmodel.CredentialUpdateRetry) (err error) {
+	const op = errors.Op("db.DeleteCredentialUpdateRetry")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("cloud_credential_id = ? AND controller_id = ?", r.CloudCredentialID, r.ControllerID)
+	if err := db.Delete(&dbmodel.CredentialUpdateRetry{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListDueCredentialUpdateRetries returns at most limit credential update
+// retries that are due to be attempted at the given time, ordered by
+// the time they are due. The cloud credential and controller of each
+// retry are also loaded.
+func (d *Database) ListDueCredentialUpdateRetries(ctx context.Context, now time.Time, limit int) (_ []dbmodel.CredentialUpdateRetry, err error) {
+	const op = errors.Op("db.ListDueCredentialUpdateRetries")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var retries []dbmodel.CredentialUpdateRetry
+	db := d.DB.WithContext(ctx).Preload("CloudCredential").Preload("CloudCredential.Cloud").Preload("Controller")
+	db = db.Where("next_attempt <= ?", now).Order("next_attempt")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	if err := db.Find(&retries).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return retries, nil
+}
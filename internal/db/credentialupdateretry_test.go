@@ -0,0 +1,78 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestListDueCredentialUpdateRetriesUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+	var d db.Database
+
+	_, err := d.ListDueCredentialUpdateRetries(context.Background(), time.Now(), 0)
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestCredentialUpdateRetry(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	err := s.Database.SetCredentialUpdateRetry(ctx, &dbmodel.CredentialUpdateRetry{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	r := dbmodel.CredentialUpdateRetry{
+		CloudCredentialID: env.cred.ID,
+		ControllerID:      env.controller.ID,
+	}
+	err = s.Database.GetCredentialUpdateRetry(ctx, &r)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.SetCredentialUpdateRetry(ctx, &dbmodel.CredentialUpdateRetry{
+		CloudCredentialID: env.cred.ID,
+		ControllerID:      env.controller.ID,
+		Attempts:          1,
+		LastError:         "connection refused",
+		NextAttempt:       now.Add(time.Minute),
+	})
+	c.Assert(err, qt.IsNil)
+
+	retries, err := s.Database.ListDueCredentialUpdateRetries(ctx, now, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(retries, qt.HasLen, 0)
+
+	err = s.Database.SetCredentialUpdateRetry(ctx, &dbmodel.CredentialUpdateRetry{
+		CloudCredentialID: env.cred.ID,
+		ControllerID:      env.controller.ID,
+		Attempts:          2,
+		LastError:         "timeout",
+		NextAttempt:       now.Add(-time.Minute),
+	})
+	c.Assert(err, qt.IsNil)
+
+	retries, err = s.Database.ListDueCredentialUpdateRetries(ctx, now, 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(retries, qt.HasLen, 1)
+	c.Check(retries[0].Attempts, qt.Equals, 2)
+	c.Check(retries[0].LastError, qt.Equals, "timeout")
+	c.Check(retries[0].CloudCredential.Name, qt.Equals, env.cred.Name)
+	c.Check(retries[0].CloudCredential.Cloud.Name, qt.Equals, env.cloud.Name)
+	c.Check(retries[0].Controller.Name, qt.Equals, env.controller.Name)
+
+	err = s.Database.DeleteCredentialUpdateRetry(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetCredentialUpdateRetry(ctx, &r)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.DeleteCredentialUpdateRetry(ctx, &r)
+	c.Assert(err, qt.IsNil)
+}
@@ -14,6 +14,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
 	"github.com/canonical/jimm/v3/internal/errors"
 )
 
@@ -23,6 +24,11 @@ type Database struct {
 	// DB contains the gorm database storing the data.
 	DB *gorm.DB
 
+	// Encrypter, if configured, is used to encrypt secret data before it
+	// is written to the database. Secrets written before an Encrypter
+	// was configured are still read as plaintext.
+	Encrypter *envelope.Encrypter
+
 	// migrated holds whether the database has been successfully migrated
 	// to the current database version. The value of migrated should always
 	// be read using atomic.LoadUint32 and will contain a 0 if the
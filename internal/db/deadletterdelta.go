@@ -0,0 +1,52 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddDeadLetterDelta stores the given dead-lettered watcher delta.
+func (d *Database) AddDeadLetterDelta(ctx context.Context, delta *dbmodel.DeadLetterDelta) (err error) {
+	const op = errors.Op("db.AddDeadLetterDelta")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(delta).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListDeadLetterDeltas returns the dead-lettered watcher deltas received
+// from the controller with the given name, or from all controllers if the
+// name is empty, ordered by ID.
+func (d *Database) ListDeadLetterDeltas(ctx context.Context, controllerName string) (_ []dbmodel.DeadLetterDelta, err error) {
+	const op = errors.Op("db.ListDeadLetterDeltas")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var deltas []dbmodel.DeadLetterDelta
+	db := d.DB.WithContext(ctx)
+	if controllerName != "" {
+		db = db.Where("controller_name = ?", controllerName)
+	}
+	if err := db.Order("id").Find(&deltas).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return deltas, nil
+}
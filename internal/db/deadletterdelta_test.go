@@ -0,0 +1,58 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestDeadLetterDeltas(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddDeadLetterDelta(ctx, &dbmodel.DeadLetterDelta{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	d1 := dbmodel.DeadLetterDelta{
+		ControllerName: "controller-1",
+		ModelUUID:      "00000002-0000-0000-0000-000000000001",
+		Kind:           "model",
+		EntityID:       "00000002-0000-0000-0000-000000000001",
+		Delta:          dbmodel.JSON(`["model","change",{}]`),
+		Error:          "test error",
+		Attempts:       3,
+	}
+	err = s.Database.AddDeadLetterDelta(ctx, &d1)
+	c.Assert(err, qt.IsNil)
+	d2 := dbmodel.DeadLetterDelta{
+		ControllerName: "controller-2",
+		ModelUUID:      "00000002-0000-0000-0000-000000000002",
+		Kind:           "machine",
+		EntityID:       "0",
+		Removed:        true,
+		Error:          "test error",
+		Attempts:       3,
+	}
+	err = s.Database.AddDeadLetterDelta(ctx, &d2)
+	c.Assert(err, qt.IsNil)
+
+	deltas, err := s.Database.ListDeadLetterDeltas(ctx, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(deltas, qt.HasLen, 2)
+	c.Check(deltas[0].ID, qt.Equals, d1.ID)
+	c.Check(string(deltas[0].Delta), qt.JSONEquals, []interface{}{"model", "change", map[string]interface{}{}})
+	c.Check(deltas[1].ID, qt.Equals, d2.ID)
+	c.Check(deltas[1].Removed, qt.IsTrue)
+
+	deltas, err = s.Database.ListDeadLetterDeltas(ctx, "controller-2")
+	c.Assert(err, qt.IsNil)
+	c.Assert(deltas, qt.HasLen, 1)
+	c.Check(deltas[0].ID, qt.Equals, d2.ID)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetDeletedModel completes the given deleted model, which is identified
+// by its ModelUUID. If the model has been removed more than once the most
+// recent record is returned. If no record is retained for the model an
+// error with the code CodeNotFound is returned.
+func (d *Database) GetDeletedModel(ctx context.Context, dm *dbmodel.DeletedModel) (err error) {
+	const op = errors.Op("db.GetDeletedModel")
+	if dm.ModelUUID == "" {
+		return errors.E(op, errors.CodeNotFound, "deleted model not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("model_uuid = ?", dm.ModelUUID).Order("deleted_at DESC, id DESC")
+	if err := db.First(dm).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListDeletedModels returns the retained records of the models removed
+// from JIMM, the most recently removed first.
+func (d *Database) ListDeletedModels(ctx context.Context) (_ []dbmodel.DeletedModel, err error) {
+	const op = errors.Op("db.ListDeletedModels")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var dms []dbmodel.DeletedModel
+	if err := d.DB.WithContext(ctx).Order("deleted_at DESC, id DESC").Find(&dms).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return dms, nil
+}
+
+// DeleteDeletedModels removes every retained record of the model with
+// the given UUID.
+func (d *Database) DeleteDeletedModels(ctx context.Context, modelUUID string) (err error) {
+	const op = errors.Op("db.DeleteDeletedModels")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("model_uuid = ?", modelUUID).Delete(&dbmodel.DeletedModel{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
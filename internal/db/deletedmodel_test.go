@@ -0,0 +1,63 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestListDeletedModelsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+	var d db.Database
+
+	_, err := d.ListDeletedModels(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestDeletedModels(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	dm := dbmodel.DeletedModel{ModelUUID: env.model.UUID.String}
+	err := s.Database.GetDeletedModel(ctx, &dm)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.DeleteModel(ctx, &dbmodel.Model{ID: env.model.ID})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetDeletedModel(ctx, &dm)
+	c.Assert(err, qt.IsNil)
+	c.Check(dm.Name, qt.Equals, env.model.Name)
+	c.Check(dm.OwnerIdentityName, qt.Equals, env.model.OwnerIdentityName)
+	c.Check(dm.ControllerName, qt.Equals, env.controller.Name)
+	c.Check(dm.CloudName, qt.Equals, env.cloud.Name)
+	c.Check(dm.CloudRegionName, qt.Equals, env.cloud.Regions[0].Name)
+	c.Check(dm.CloudCredentialName, qt.Equals, env.cred.Path())
+	c.Check(dm.DeletedAt.IsZero(), qt.IsFalse)
+
+	dms, err := s.Database.ListDeletedModels(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dms, qt.HasLen, 1)
+	c.Check(dms[0].ID, qt.Equals, dm.ID)
+
+	// Removing a model that does not exist records nothing.
+	err = s.Database.DeleteModel(ctx, &dbmodel.Model{ID: env.model.ID})
+	c.Assert(err, qt.IsNil)
+	dms, err = s.Database.ListDeletedModels(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(dms, qt.HasLen, 1)
+
+	err = s.Database.DeleteDeletedModels(ctx, env.model.UUID.String)
+	c.Assert(err, qt.IsNil)
+	dms, err = s.Database.ListDeletedModels(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(dms, qt.HasLen, 0)
+}
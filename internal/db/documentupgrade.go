@@ -0,0 +1,192 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// The kinds of stored document that may be upgraded with
+// UpgradeDocuments.
+const (
+	// DocumentSecrets holds the stored secrets, see
+	// dbmodel.SecretSchemaVersion.
+	DocumentSecrets = "secrets"
+
+	// DocumentModelWatcherStates holds the persisted model watcher
+	// states, see dbmodel.ModelWatcherStateSchemaVersion.
+	DocumentModelWatcherStates = "model-watcher-states"
+)
+
+// An upgradableDocument describes how the documents of a kind stored
+// with an old schema version are found and upgraded.
+type upgradableDocument struct {
+	model   interface{}
+	version int
+	upgrade func(ctx context.Context, d *Database, tx *gorm.DB, limit int) (int64, error)
+}
+
+var upgradableDocuments = map[string]upgradableDocument{
+	DocumentSecrets: {
+		model:   &dbmodel.Secret{},
+		version: dbmodel.SecretSchemaVersion,
+		upgrade: upgradeSecrets,
+	},
+	DocumentModelWatcherStates: {
+		model:   &dbmodel.ModelWatcherState{},
+		version: dbmodel.ModelWatcherStateSchemaVersion,
+		upgrade: upgradeModelWatcherStates,
+	},
+}
+
+// UpgradableDocuments returns the kinds of document that may be upgraded
+// with UpgradeDocuments.
+func UpgradableDocuments() []string {
+	return []string{DocumentSecrets, DocumentModelWatcherStates}
+}
+
+// CountOutdatedDocuments returns the number of documents of the named
+// kind stored with an older schema version than the current one. If the
+// kind is not known an error with the code CodeBadRequest is returned.
+func (d *Database) CountOutdatedDocuments(ctx context.Context, kind string) (_ int64, err error) {
+	const op = errors.Op("db.CountOutdatedDocuments")
+
+	doc, ok := upgradableDocuments[kind]
+	if !ok {
+		return 0, errors.E(op, errors.CodeBadRequest, "unknown document kind "+kind)
+	}
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var n int64
+	if err := d.DB.WithContext(ctx).Model(doc.model).Where("schema_version < ?", doc.version).Count(&n).Error; err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return n, nil
+}
+
+// UpgradeDocuments upgrades at most limit of the documents of the named
+// kind that are stored with an older schema version to the current
+// version, so that large tables can be upgraded in batches without
+// holding long-running locks. The number of upgraded documents is
+// returned, documents superseded by one already stored in the current
+// format are removed and included in the count. If the kind is not known
+// an error with the code CodeBadRequest is returned.
+func (d *Database) UpgradeDocuments(ctx context.Context, kind string, limit int) (_ int64, err error) {
+	const op = errors.Op("db.UpgradeDocuments")
+
+	doc, ok := upgradableDocuments[kind]
+	if !ok {
+		return 0, errors.E(op, errors.CodeBadRequest, "unknown document kind "+kind)
+	}
+	if limit <= 0 {
+		return 0, errors.E(op, errors.CodeBadRequest, "limit must be positive")
+	}
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var n int64
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		n, err = doc.upgrade(ctx, d, tx, limit)
+		return err
+	})
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	return n, nil
+}
+
+// upgradeSecrets upgrades at most limit secrets stored with an older
+// schema version. Cloud credential secrets stored under a legacy
+// credential path are moved to the credential's tag, re-encrypting them
+// if necessary as the tag is bound to the encrypted data.
+func upgradeSecrets(ctx context.Context, d *Database, tx *gorm.DB, limit int) (int64, error) {
+	var secrets []dbmodel.Secret
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("schema_version < ?", dbmodel.SecretSchemaVersion).
+		Order("id").
+		Limit(limit).
+		Find(&secrets).Error
+	if err != nil {
+		return 0, dbError(err)
+	}
+	var n int64
+	for _, secret := range secrets {
+		tag := secret.UpgradedTag()
+		if tag != secret.Tag {
+			var existing int64
+			if err := tx.Model(&dbmodel.Secret{}).Where("type = ? AND tag = ?", secret.Type, tag).Count(&existing).Error; err != nil {
+				return n, dbError(err)
+			}
+			if existing > 0 {
+				// The secret has since been stored under its
+				// current tag, the legacy copy is stale.
+				if err := tx.Delete(&secret).Error; err != nil {
+					return n, dbError(err)
+				}
+				n++
+				continue
+			}
+			if envelope.IsSealed(secret.Data) {
+				if err := d.openSecret(ctx, &secret); err != nil {
+					return n, errors.E(err, fmt.Sprintf("secret %s/%s", secret.Type, secret.Tag))
+				}
+				secret.Tag = tag
+				data, err := d.Encrypter.Seal(ctx, secret.Data, secretAdditionalData(&secret))
+				if err != nil {
+					return n, errors.E(err, fmt.Sprintf("secret %s/%s", secret.Type, secret.Tag))
+				}
+				secret.Data = data
+			}
+			secret.Tag = tag
+		}
+		secret.SchemaVersion = dbmodel.SecretSchemaVersion
+		if err := tx.Model(&secret).Select("tag", "data", "schema_version").Updates(&secret).Error; err != nil {
+			return n, dbError(err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// upgradeModelWatcherStates upgrades at most limit model watcher states
+// stored with an older schema version, see
+// dbmodel.ModelWatcherState.Upgrade.
+func upgradeModelWatcherStates(_ context.Context, _ *Database, tx *gorm.DB, limit int) (int64, error) {
+	var states []dbmodel.ModelWatcherState
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("schema_version < ?", dbmodel.ModelWatcherStateSchemaVersion).
+		Order("model_id").
+		Limit(limit).
+		Find(&states).Error
+	if err != nil {
+		return 0, dbError(err)
+	}
+	for _, state := range states {
+		state.Upgrade()
+		if err := tx.Model(&state).Select("units", "offers", "relations", "agent_versions", "schema_version").Updates(&state).Error; err != nil {
+			return 0, dbError(err)
+		}
+	}
+	return int64(len(states)), nil
+}
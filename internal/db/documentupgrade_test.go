@@ -0,0 +1,132 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestUpgradeDocumentsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.UpgradeDocuments(context.Background(), db.DocumentSecrets, 10)
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpgradeSecrets(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+	s.Database.Encrypter = newTestEncrypter(c, "key-1")
+	c.Cleanup(func() { s.Database.Encrypter = nil })
+
+	// A plaintext cloud credential stored under its legacy path.
+	data, err := json.Marshal(map[string]string{"key": "plain"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DB.Create(&dbmodel.Secret{
+		Type: names.CloudCredentialTagKind,
+		Tag:  "test/bob@canonical.com/plain",
+		Data: data,
+	}).Error
+	c.Assert(err, qt.IsNil)
+
+	// An encrypted cloud credential stored under its legacy path.
+	data, err = json.Marshal(map[string]string{"key": "encrypted"})
+	c.Assert(err, qt.IsNil)
+	sealed, err := s.Database.Encrypter.Seal(ctx, data, []byte("cloudcred/test/bob@canonical.com/encrypted"))
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DB.Create(&dbmodel.Secret{
+		Type: names.CloudCredentialTagKind,
+		Tag:  "test/bob@canonical.com/encrypted",
+		Data: sealed,
+	}).Error
+	c.Assert(err, qt.IsNil)
+
+	// A legacy copy of a credential that has since been stored under
+	// its tag.
+	err = s.Database.DB.Create(&dbmodel.Secret{
+		Type: names.CloudCredentialTagKind,
+		Tag:  "test/bob@canonical.com/stale",
+		Data: dbmodel.JSON(`{"key":"old"}`),
+	}).Error
+	c.Assert(err, qt.IsNil)
+	staleTag := names.NewCloudCredentialTag("test/bob@canonical.com/stale")
+	err = s.Database.Put(ctx, staleTag, map[string]string{"key": "new"})
+	c.Assert(err, qt.IsNil)
+
+	n, err := s.Database.CountOutdatedDocuments(ctx, db.DocumentSecrets)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(3))
+
+	n, err = s.Database.UpgradeDocuments(ctx, db.DocumentSecrets, 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+	n, err = s.Database.UpgradeDocuments(ctx, db.DocumentSecrets, 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	n, err = s.Database.CountOutdatedDocuments(ctx, db.DocumentSecrets)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(0))
+
+	for name, value := range map[string]string{"plain": "plain", "encrypted": "encrypted", "stale": "new"} {
+		attr, err := s.Database.Get(ctx, names.NewCloudCredentialTag("test/bob@canonical.com/"+name))
+		c.Assert(err, qt.IsNil, qt.Commentf("credential %s", name))
+		c.Check(attr, qt.DeepEquals, map[string]string{"key": value}, qt.Commentf("credential %s", name))
+	}
+	var count int64
+	err = s.Database.DB.Model(&dbmodel.Secret{}).Count(&count).Error
+	c.Assert(err, qt.IsNil)
+	c.Check(count, qt.Equals, int64(3))
+}
+
+func (s *dbSuite) TestUpgradeModelWatcherStates(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.DB.Create(&dbmodel.ModelWatcherState{
+		ModelID:  env.model.ID,
+		Machines: dbmodel.Int64Map{"0": 2},
+		Units:    dbmodel.StringMap{"app/0": "", "app/1": "active"},
+	}).Error
+	c.Assert(err, qt.IsNil)
+
+	// The state is upgraded when it is read.
+	st := dbmodel.ModelWatcherState{ModelID: env.model.ID}
+	err = s.Database.GetModelWatcherState(ctx, &st)
+	c.Assert(err, qt.IsNil)
+	c.Check(st.Units, qt.DeepEquals, dbmodel.StringMap{"app/0": "unknown", "app/1": "active"})
+	c.Check(st.Offers, qt.DeepEquals, dbmodel.Strings{})
+	c.Check(st.AgentVersions, qt.DeepEquals, dbmodel.StringMap{})
+	c.Check(st.SchemaVersion, qt.Equals, dbmodel.ModelWatcherStateSchemaVersion)
+
+	n, err := s.Database.CountOutdatedDocuments(ctx, db.DocumentModelWatcherStates)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	n, err = s.Database.UpgradeDocuments(ctx, db.DocumentModelWatcherStates, 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	var stored dbmodel.ModelWatcherState
+	err = s.Database.DB.First(&stored, "model_id = ?", env.model.ID).Error
+	c.Assert(err, qt.IsNil)
+	c.Check(stored.Units, qt.DeepEquals, dbmodel.StringMap{"app/0": "unknown", "app/1": "active"})
+	c.Check(stored.Relations, qt.DeepEquals, dbmodel.Strings{})
+	c.Check(stored.AgentVersions, qt.DeepEquals, dbmodel.StringMap{})
+	c.Check(stored.SchemaVersion, qt.Equals, dbmodel.ModelWatcherStateSchemaVersion)
+
+	_, err = s.Database.UpgradeDocuments(ctx, "unknown", 10)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
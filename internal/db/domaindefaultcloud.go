@@ -0,0 +1,96 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetDomainDefaultCloud records the given default cloud for an identity
+// domain, replacing any default the domain already has.
+func (d *Database) SetDomainDefaultCloud(ctx context.Context, def *dbmodel.DomainDefaultCloud) (err error) {
+	const op = errors.Op("db.SetDomainDefaultCloud")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "domain"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "cloud_name", "region_name"}),
+	}).Create(def).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetDomainDefaultCloud fills in the default cloud for the domain in the
+// given def. If the domain has no default an error with a code of
+// CodeNotFound is returned.
+func (d *Database) GetDomainDefaultCloud(ctx context.Context, def *dbmodel.DomainDefaultCloud) (err error) {
+	const op = errors.Op("db.GetDomainDefaultCloud")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("domain = ?", def.Domain).First(def).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListDomainDefaultClouds returns the default clouds of all identity
+// domains ordered by domain.
+func (d *Database) ListDomainDefaultClouds(ctx context.Context) (_ []dbmodel.DomainDefaultCloud, err error) {
+	const op = errors.Op("db.ListDomainDefaultClouds")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var defs []dbmodel.DomainDefaultCloud
+	if err := d.DB.WithContext(ctx).Order("domain").Find(&defs).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return defs, nil
+}
+
+// DeleteDomainDefaultCloud removes the default cloud of the domain in the
+// given def. If the domain has no default an error with a code of
+// CodeNotFound is returned.
+func (d *Database) DeleteDomainDefaultCloud(ctx context.Context, def *dbmodel.DomainDefaultCloud) (err error) {
+	const op = errors.Op("db.DeleteDomainDefaultCloud")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	res := d.DB.WithContext(ctx).Where("domain = ?", def.Domain).Delete(&dbmodel.DomainDefaultCloud{})
+	if res.Error != nil {
+		return errors.E(op, dbError(res.Error))
+	}
+	if res.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "domain default cloud not found")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestDomainDefaultClouds(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.SetDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{Domain: "canonical.com"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for _, name := range []string{"test-cloud-1", "test-cloud-2"} {
+		err = s.Database.AddCloud(ctx, &dbmodel.Cloud{
+			Name: name,
+			Type: "test-provider",
+			Regions: []dbmodel.CloudRegion{{
+				Name: "test-region",
+			}},
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	err = s.Database.SetDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{
+		Domain:    "example.com",
+		CloudName: "test-cloud-1",
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{
+		Domain:    "canonical.com",
+		CloudName: "test-cloud-1",
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{
+		Domain:     "canonical.com",
+		CloudName:  "test-cloud-2",
+		RegionName: "test-region",
+	})
+	c.Assert(err, qt.IsNil)
+
+	def := dbmodel.DomainDefaultCloud{Domain: "canonical.com"}
+	err = s.Database.GetDomainDefaultCloud(ctx, &def)
+	c.Assert(err, qt.IsNil)
+	c.Check(def.CloudName, qt.Equals, "test-cloud-2")
+	c.Check(def.RegionName, qt.Equals, "test-region")
+
+	err = s.Database.GetDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{Domain: "ubuntu.com"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	defs, err := s.Database.ListDomainDefaultClouds(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(defs, qt.HasLen, 2)
+	c.Check(defs[0].Domain, qt.Equals, "canonical.com")
+	c.Check(defs[1].Domain, qt.Equals, "example.com")
+
+	err = s.Database.DeleteDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{Domain: "example.com"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{Domain: "example.com"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	cl := dbmodel.Cloud{Name: "test-cloud-2"}
+	err = s.Database.GetCloud(ctx, &cl)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.DeleteCloud(ctx, &cl)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetDomainDefaultCloud(ctx, &dbmodel.DomainDefaultCloud{Domain: "canonical.com"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -11,4 +11,15 @@ var (
 	OAuthKeyTag                = oauthKeyTag
 	OAuthSessionStoreSecretTag = oauthSessionStoreSecretTag
 	NewUUID                    = &newUUID
+	ModelBatchSize             = &modelBatchSize
 )
+
+// IdentityColumns returns the table and column names of every column
+// registered as referring to an identity by name.
+func IdentityColumns() [][2]string {
+	columns := make([][2]string, len(identityColumns))
+	for i, c := range identityColumns {
+		columns[i] = [2]string{c.table, c.column}
+	}
+	return columns
+}
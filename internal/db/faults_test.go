@@ -0,0 +1,35 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/faults"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+func (s *dbSuite) TestDatabaseWriteFaults(c *qt.C) {
+	jimmtest.RequireFaults(c)
+	ctx := context.Background()
+
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	jimmtest.RegisterFaultCallbacks(c, s.Database.DB)
+
+	jimmtest.InjectFault(c, faults.Fault{Point: faults.DatabaseWrite, Target: "models"})
+	jimmtest.InjectFault(c, faults.Fault{Point: faults.DatabaseWrite, Target: "groups", Count: 1})
+
+	_, err = s.Database.AddGroup(ctx, "test-group")
+	c.Check(err, qt.ErrorMatches, `injected database-write fault for groups`)
+
+	// The fault was only injected once.
+	group, err := s.Database.AddGroup(ctx, "test-group")
+	c.Assert(err, qt.IsNil)
+
+	// Reads are not affected.
+	err = s.Database.GetGroup(ctx, group)
+	c.Assert(err, qt.IsNil)
+}
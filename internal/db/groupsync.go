@@ -0,0 +1,62 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetGroupSyncStatus fills in the given group sync status. The Directory
+// must be set. If no status has been stored for the directory an error
+// with a code of CodeNotFound is returned.
+func (d *Database) GetGroupSyncStatus(ctx context.Context, status *dbmodel.GroupSyncStatus) (err error) {
+	const op = errors.Op("db.GetGroupSyncStatus")
+
+	if status.Directory == "" {
+		return errors.E(op, errors.CodeNotFound, "group sync status not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).First(status, "directory = ?", status.Directory).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// UpsertGroupSyncStatus stores the given group sync status, replacing any
+// status already stored for the directory.
+func (d *Database) UpsertGroupSyncStatus(ctx context.Context, status *dbmodel.GroupSyncStatus) (err error) {
+	const op = errors.Op("db.UpsertGroupSyncStatus")
+
+	if status.Directory == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing directory")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "directory"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "time", "created_groups", "added", "removed", "errors", "error"}),
+	})
+	if err := db.Create(status).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestGroupSyncStatus(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.UpsertGroupSyncStatus(ctx, &dbmodel.GroupSyncStatus{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	st := dbmodel.GroupSyncStatus{
+		Directory: "scim",
+	}
+	err = s.Database.GetGroupSyncStatus(ctx, &st)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	t1 := time.Now().Add(-time.Hour).UTC().Truncate(time.Millisecond)
+	err = s.Database.UpsertGroupSyncStatus(ctx, &dbmodel.GroupSyncStatus{
+		Directory: "scim",
+		Time:      t1,
+		Added:     3,
+		Error:     "directory unavailable",
+	})
+	c.Assert(err, qt.IsNil)
+	t2 := time.Now().UTC().Truncate(time.Millisecond)
+	err = s.Database.UpsertGroupSyncStatus(ctx, &dbmodel.GroupSyncStatus{
+		Directory:     "scim",
+		Time:          t2,
+		CreatedGroups: dbmodel.Strings{"engineering"},
+		Added:         2,
+		Removed:       1,
+		Errors:        dbmodel.Strings{`invalid member name "bob"`},
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetGroupSyncStatus(ctx, &st)
+	c.Assert(err, qt.IsNil)
+	c.Check(st.Time.UTC(), qt.Equals, t2)
+	c.Check(st.CreatedGroups, qt.DeepEquals, dbmodel.Strings{"engineering"})
+	c.Check(st.Added, qt.Equals, 2)
+	c.Check(st.Removed, qt.Equals, 1)
+	c.Check(st.Errors, qt.DeepEquals, dbmodel.Strings{`invalid member name "bob"`})
+	c.Check(st.Error, qt.Equals, "")
+}
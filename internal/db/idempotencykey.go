@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// ReserveIdempotencyKey records that a request with the given idempotency
+// key is being processed. If the identity has already used the key, and
+// it has not expired, the existing record is loaded into k and false is
+// returned. A record that has expired is replaced.
+func (d *Database) ReserveIdempotencyKey(ctx context.Context, k *dbmodel.IdempotencyKey) (_ bool, err error) {
+	const op = errors.Op("db.ReserveIdempotencyKey")
+	if k.IdentityName == "" || k.Key == "" {
+		return false, errors.E(op, errors.CodeBadRequest, "missing identity or key")
+	}
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var reserved bool
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("identity_name = ? AND key = ? AND expires_at <= ?", k.IdentityName, k.Key, k.CreatedAt).Delete(&dbmodel.IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(k)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			reserved = true
+			return nil
+		}
+		return tx.Where("identity_name = ? AND key = ?", k.IdentityName, k.Key).First(k).Error
+	})
+	if err != nil {
+		return false, errors.E(op, dbError(err))
+	}
+	return reserved, nil
+}
+
+// CompleteIdempotencyKey records the response to the request made with
+// the given, previously reserved, idempotency key.
+func (d *Database) CompleteIdempotencyKey(ctx context.Context, k *dbmodel.IdempotencyKey) (err error) {
+	const op = errors.Op("db.CompleteIdempotencyKey")
+	if k.ID == 0 {
+		return errors.E(op, errors.CodeNotFound, "idempotency key not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	k.Completed = true
+	err = d.DB.WithContext(ctx).Model(k).Select("completed", "status_code", "content_type", "body").Updates(k).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey removes the given idempotency key, so that a
+// request that failed may be retried with the same key.
+func (d *Database) DeleteIdempotencyKey(ctx context.Context, k *dbmodel.IdempotencyKey) (err error) {
+	const op = errors.Op("db.DeleteIdempotencyKey")
+	if k.ID == 0 {
+		return nil
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(k).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys removes the idempotency keys that expired
+// before the given time, returning the number removed.
+func (d *Database) DeleteExpiredIdempotencyKeys(ctx context.Context, before time.Time) (_ int64, err error) {
+	const op = errors.Op("db.DeleteExpiredIdempotencyKeys")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Where("expires_at <= ?", before).Delete(&dbmodel.IdempotencyKey{})
+	if result.Error != nil {
+		return 0, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
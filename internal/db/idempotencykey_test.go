@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestIdempotencyKeys(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	k1 := dbmodel.IdempotencyKey{
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Hour),
+		IdentityName: "alice@canonical.com",
+		Key:          "key-1",
+		RequestHash:  "hash-1",
+	}
+	reserved, err := s.Database.ReserveIdempotencyKey(ctx, &k1)
+	c.Assert(err, qt.IsNil)
+	c.Check(reserved, qt.IsTrue)
+	c.Check(k1.ID, qt.Not(qt.Equals), uint(0))
+
+	// The key is in use until it expires.
+	k2 := dbmodel.IdempotencyKey{
+		CreatedAt:    now.Add(time.Minute),
+		ExpiresAt:    now.Add(time.Hour + time.Minute),
+		IdentityName: "alice@canonical.com",
+		Key:          "key-1",
+		RequestHash:  "hash-2",
+	}
+	reserved, err = s.Database.ReserveIdempotencyKey(ctx, &k2)
+	c.Assert(err, qt.IsNil)
+	c.Check(reserved, qt.IsFalse)
+	c.Check(k2.ID, qt.Equals, k1.ID)
+	c.Check(k2.RequestHash, qt.Equals, "hash-1")
+	c.Check(k2.Completed, qt.IsFalse)
+
+	k1.StatusCode = 201
+	k1.ContentType = "application/json"
+	k1.Body = []byte(`{"name":"group-1"}`)
+	err = s.Database.CompleteIdempotencyKey(ctx, &k1)
+	c.Assert(err, qt.IsNil)
+
+	k3 := dbmodel.IdempotencyKey{
+		CreatedAt:    now.Add(time.Minute),
+		ExpiresAt:    now.Add(time.Hour + time.Minute),
+		IdentityName: "alice@canonical.com",
+		Key:          "key-1",
+		RequestHash:  "hash-1",
+	}
+	reserved, err = s.Database.ReserveIdempotencyKey(ctx, &k3)
+	c.Assert(err, qt.IsNil)
+	c.Check(reserved, qt.IsFalse)
+	c.Check(k3.Completed, qt.IsTrue)
+	c.Check(k3.StatusCode, qt.Equals, 201)
+	c.Check(k3.ContentType, qt.Equals, "application/json")
+	c.Check(string(k3.Body), qt.Equals, `{"name":"group-1"}`)
+
+	// Other identities may use the same key.
+	k4 := dbmodel.IdempotencyKey{
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Hour),
+		IdentityName: "bob@canonical.com",
+		Key:          "key-1",
+		RequestHash:  "hash-1",
+	}
+	reserved, err = s.Database.ReserveIdempotencyKey(ctx, &k4)
+	c.Assert(err, qt.IsNil)
+	c.Check(reserved, qt.IsTrue)
+
+	// A deleted key may be reserved again.
+	err = s.Database.DeleteIdempotencyKey(ctx, &k4)
+	c.Assert(err, qt.IsNil)
+	k4.ID = 0
+	reserved, err = s.Database.ReserveIdempotencyKey(ctx, &k4)
+	c.Assert(err, qt.IsNil)
+	c.Check(reserved, qt.IsTrue)
+
+	// An expired key is replaced.
+	k5 := dbmodel.IdempotencyKey{
+		CreatedAt:    now.Add(2 * time.Hour),
+		ExpiresAt:    now.Add(3 * time.Hour),
+		IdentityName: "alice@canonical.com",
+		Key:          "key-1",
+		RequestHash:  "hash-2",
+	}
+	reserved, err = s.Database.ReserveIdempotencyKey(ctx, &k5)
+	c.Assert(err, qt.IsNil)
+	c.Check(reserved, qt.IsTrue)
+	c.Check(k5.ID, qt.Not(qt.Equals), k1.ID)
+
+	n, err := s.Database.DeleteExpiredIdempotencyKeys(ctx, now.Add(2*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	err = s.Database.CompleteIdempotencyKey(ctx, &dbmodel.IdempotencyKey{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	_, err = s.Database.ReserveIdempotencyKey(ctx, &dbmodel.IdempotencyKey{IdentityName: "alice@canonical.com"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestReserveIdempotencyKeyUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.ReserveIdempotencyKey(context.Background(), &dbmodel.IdempotencyKey{
+		IdentityName: "alice@canonical.com",
+		Key:          "key-1",
+	})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
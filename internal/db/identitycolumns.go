@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import "github.com/canonical/jimm/v3/internal/dbmodel"
+
+// A purgeAction determines what happens to the records that refer to an
+// identity when the identity is purged.
+type purgeAction int
+
+const (
+	// purgeAnonymize keeps the records, the purged identity's name is
+	// replaced with the name of its tombstone.
+	purgeAnonymize purgeAction = iota
+
+	// purgeDelete deletes the records.
+	purgeDelete
+
+	// purgeRefuse keeps the records, an identity cannot be purged while
+	// any refer to it. If the column has a refuseWhere condition only the
+	// records meeting it prevent the purge, the others are anonymized.
+	purgeRefuse
+)
+
+// An identityColumn is a column of a table that refers to an identity
+// by name.
+type identityColumn struct {
+	table  string
+	column string
+
+	// purge is what happens to the records when the identity they refer
+	// to is purged.
+	purge purgeAction
+
+	// refusal is the format of the error returned when purging an
+	// identity is refused because of the records, it is given the
+	// number of records. It is only used with purgeRefuse.
+	refusal string
+
+	// refuseWhere is an optional SQL condition restricting the records
+	// that prevent a purge. It is only used with purgeRefuse.
+	refuseWhere string
+}
+
+// identityColumns holds every column that refers to an identity by name.
+// The columns of a new table that refers to identities must be added
+// here, RenameIdentity updates every column and PurgeIdentity applies
+// each column's purge action, so that no record refers to an identity
+// that no longer exists.
+var identityColumns = []identityColumn{
+	{table: "access_requests", column: "identity_name", purge: purgeDelete},
+	{table: "access_requests", column: "reviewer_name", purge: purgeAnonymize},
+	{table: "application_offer_connections", column: "identity_name", purge: purgeAnonymize},
+	{table: "cloud_credentials", column: "owner_identity_name", purge: purgeDelete},
+	{table: "cloud_defaults", column: "identity_name", purge: purgeDelete},
+	{table: "default_cloud_credentials", column: "identity_name", purge: purgeDelete},
+	{table: "deleted_models", column: "owner_identity_name", purge: purgeAnonymize},
+	{table: "idempotency_keys", column: "identity_name", purge: purgeDelete},
+	{table: "identity_model_defaults", column: "identity_name", purge: purgeDelete},
+	{table: "identity_quotas", column: "identity_name", purge: purgeDelete},
+	{table: "identity_sessions", column: "identity_name", purge: purgeDelete},
+	{table: "identity_tombstones", column: "purged_by", purge: purgeAnonymize},
+	{table: "model_dependencies", column: "created_by", purge: purgeAnonymize},
+	{table: "model_freezes", column: "frozen_by", purge: purgeAnonymize},
+	{table: "model_imports", column: "owner_identity_name", purge: purgeRefuse, refusal: "identity still owns %d pending model import(s)", refuseWhere: "status = '" + dbmodel.ModelImportPrepared + "'"},
+	{table: "model_imports", column: "prepared_by", purge: purgeAnonymize},
+	{table: "model_migrations", column: "initiated_by", purge: purgeAnonymize},
+	{table: "model_network_policies", column: "set_by", purge: purgeAnonymize},
+	{table: "model_tokens", column: "created_by", purge: purgeAnonymize},
+	{table: "models", column: "owner_identity_name", purge: purgeRefuse, refusal: "identity still owns %d model(s)"},
+	{table: "models", column: "origin_identity_name", purge: purgeAnonymize},
+	{table: "operations", column: "identity_name", purge: purgeAnonymize},
+	{table: "pending_model_destroys", column: "requested_by", purge: purgeAnonymize},
+	{table: "recent_cloud_credentials", column: "identity_name", purge: purgeDelete},
+	{table: "result_downloads", column: "identity_name", purge: purgeDelete},
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// An IdentityPurge holds the result of purging an identity.
+type IdentityPurge struct {
+	// CloudCredentials holds the cloud credentials of the identity that
+	// were deleted.
+	CloudCredentials []dbmodel.CloudCredential
+
+	// AuditLogEntries is the number of audit log entries attributed to
+	// the identity that were anonymized or deleted.
+	AuditLogEntries int64
+}
+
+// PurgeIdentity permanently removes the given identity along with its
+// cloud credentials, preferences and defaults. Every record referring to
+// the identity is deleted or kept as determined by identityColumns. The
+// records that are kept, such as audit log entries and the reviews of
+// access requests, refer to the given tombstone instead, which is
+// created. If deleteAuditLog is true the identity's audit log entries are
+// deleted rather than anonymized. An identity that still owns models or
+// pending model imports, or whose credentials are used by models, cannot
+// be purged and an error with a code of CodeBadRequest is returned. If the identity does not
+// exist an error with a code of CodeNotFound is returned.
+func (d *Database) PurgeIdentity(ctx context.Context, i *dbmodel.Identity, t *dbmodel.IdentityTombstone, deleteAuditLog bool) (_ *IdentityPurge, err error) {
+	const op = errors.Op("db.PurgeIdentity")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var purge IdentityPurge
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("name = ?", i.Name).First(i).Error; err != nil {
+			return err
+		}
+
+		var n int64
+		for _, c := range identityColumns {
+			if c.purge != purgeRefuse {
+				continue
+			}
+			q := tx.Table(c.table).Where(c.column+" = ?", i.Name)
+			if c.refuseWhere != "" {
+				q = q.Where(c.refuseWhere)
+			}
+			if err := q.Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				return errors.E(errors.CodeBadRequest, fmt.Sprintf(c.refusal, n))
+			}
+		}
+		if err := tx.Unscoped().Where("owner_identity_name = ?", i.Name).Find(&purge.CloudCredentials).Error; err != nil {
+			return err
+		}
+		if len(purge.CloudCredentials) > 0 {
+			ids := make([]uint, len(purge.CloudCredentials))
+			for k, cred := range purge.CloudCredentials {
+				ids[k] = cred.ID
+			}
+			if err := tx.Model(&dbmodel.Model{}).Where("cloud_credential_id IN ?", ids).Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				return errors.E(errors.CodeBadRequest, fmt.Sprintf("cloud credentials still used by %d model(s)", n))
+			}
+		}
+
+		// Credential preferences are removed along with the credentials.
+		// The records of refused columns left at this point do not
+		// prevent the purge and are anonymized.
+		for _, c := range identityColumns {
+			var err error
+			switch c.purge {
+			case purgeAnonymize, purgeRefuse:
+				err = tx.Table(c.table).Where(c.column+" = ?", i.Name).Update(c.column, t.Name).Error
+			case purgeDelete:
+				err = tx.Exec("DELETE FROM "+c.table+" WHERE "+c.column+" = ?", i.Name).Error
+			}
+			if err != nil {
+				return err
+			}
+		}
+		audit := tx.Model(&dbmodel.AuditLogEntry{}).Where("identity_tag = ?", i.Tag().String())
+		if deleteAuditLog {
+			audit = audit.Delete(&dbmodel.AuditLogEntry{})
+		} else {
+			audit = audit.Update("identity_tag", t.ResourceTag().String())
+		}
+		if audit.Error != nil {
+			return audit.Error
+		}
+		purge.AuditLogEntries = audit.RowsAffected
+
+		if err := tx.Unscoped().Delete(i).Error; err != nil {
+			return err
+		}
+		return tx.Create(t).Error
+	})
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return &purge, nil
+}
+
+// GetIdentityTombstone fills in the given tombstone, which is found by
+// name. If there is no such tombstone an error with a code of
+// CodeNotFound is returned.
+func (d *Database) GetIdentityTombstone(ctx context.Context, t *dbmodel.IdentityTombstone) (err error) {
+	const op = errors.Op("db.GetIdentityTombstone")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("name = ?", t.Name).First(t).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/state"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestPurgeIdentity(c *qt.C) {
+	ctx := context.Background()
+
+	_, err := s.Database.PurgeIdentity(ctx, &dbmodel.Identity{Name: "bob@canonical.com"}, &dbmodel.IdentityTombstone{}, false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(alice).Error, qt.IsNil)
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(bob).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region",
+		}},
+	}
+	c.Assert(s.Database.DB.Create(&cloud).Error, qt.IsNil)
+	cred := dbmodel.CloudCredential{
+		Name:     "test-cred",
+		Cloud:    cloud,
+		Owner:    *bob,
+		AuthType: "empty",
+	}
+	c.Assert(s.Database.DB.Create(&cred).Error, qt.IsNil)
+	controller := dbmodel.Controller{
+		Name:        "test-controller",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region",
+	}
+	err = s.Database.AddController(ctx, &controller)
+	c.Assert(err, qt.IsNil)
+
+	model := dbmodel.Model{
+		Name: "test-model",
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+		OwnerIdentityName: bob.Name,
+		ControllerID:      controller.ID,
+		CloudRegionID:     cloud.Regions[0].ID,
+		CloudCredentialID: cred.ID,
+		Type:              "iaas",
+		DefaultSeries:     "warty",
+		Life:              state.Alive.String(),
+	}
+	err = s.Database.AddModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.SetIdentityModelDefaults(ctx, &dbmodel.IdentityModelDefaults{
+		IdentityName: bob.Name,
+		Defaults:     map[string]interface{}{"key": "value"},
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddAccessRequest(ctx, &dbmodel.AccessRequest{
+		IdentityName: alice.Name,
+		TargetTag:    model.Tag().String(),
+		Access:       "read",
+		Status:       "approved",
+		ReviewerName: bob.Name,
+	})
+	c.Assert(err, qt.IsNil)
+	for _, tag := range []string{bob.Tag().String(), bob.Tag().String(), alice.Tag().String()} {
+		err = s.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time:        time.Now(),
+			IdentityTag: tag,
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	// Records of other identities' models that refer to bob are kept.
+	aliceCred := dbmodel.CloudCredential{
+		Name:     "test-cred",
+		Cloud:    cloud,
+		Owner:    *alice,
+		AuthType: "empty",
+	}
+	c.Assert(s.Database.DB.Create(&aliceCred).Error, qt.IsNil)
+	model2 := dbmodel.Model{
+		Name: "test-model-2",
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000002",
+			Valid:  true,
+		},
+		OwnerIdentityName: alice.Name,
+		ControllerID:      controller.ID,
+		CloudRegionID:     cloud.Regions[0].ID,
+		CloudCredentialID: aliceCred.ID,
+		Type:              "iaas",
+		DefaultSeries:     "warty",
+		Life:              state.Alive.String(),
+	}
+	err = s.Database.AddModel(ctx, &model2)
+	c.Assert(err, qt.IsNil)
+	model3 := model2
+	model3.ID = 0
+	model3.Name = "test-model-3"
+	model3.UUID.String = "00000001-0000-0000-0000-0000-000000000003"
+	err = s.Database.AddModel(ctx, &model3)
+	c.Assert(err, qt.IsNil)
+	for _, stmt := range []string{
+		"INSERT INTO model_freezes (model_id, frozen_by, reason) VALUES (@model, @name, 'test')",
+		"INSERT INTO model_network_policies (model_id, set_by) VALUES (@model, @name)",
+		"INSERT INTO model_migrations (model_id, source_controller_id, target_controller_id, migration_id, initiated_by, status) VALUES (@model, @controller, @controller, 'migration-1', @name, 'running')",
+		"INSERT INTO result_downloads (created_at, expires_at, token_hash, identity_name, name, content_type) VALUES (now(), now(), 'hash', @name, 'result', 'text/plain')",
+		"INSERT INTO pending_model_destroys (model_id, requested_by, destroy_at) VALUES (@model, @name, now())",
+		"INSERT INTO model_dependencies (model_id, depends_on_id, created_by) VALUES (@model, @model3, @name)",
+		"INSERT INTO operations (uuid, kind, identity_name, status) VALUES (\x27operation-1\x27, \x27test\x27, @name, \x27completed\x27)",
+	} {
+		err := s.Database.DB.Exec(stmt, map[string]interface{}{
+			"model":      model2.ID,
+			"model3":     model3.ID,
+			"controller": controller.ID,
+			"name":       bob.Name,
+		}).Error
+		c.Assert(err, qt.IsNil)
+	}
+
+	tombstone := dbmodel.IdentityTombstone{
+		Name:     "erased-00000000-0000-0000-0000-000000000001",
+		PurgedBy: alice.Name,
+	}
+
+	// Only pending imports of models prevent the purge.
+	completedImport := dbmodel.ModelImport{
+		ModelUUID:         "00000001-0000-0000-0000-0000-000000000004",
+		ModelName:         "imported-model",
+		SourceJIMM:        "controller-00000000-0000-0000-0000-000000000002",
+		ControllerID:      controller.ID,
+		OwnerIdentityName: bob.Name,
+		Status:            dbmodel.ModelImportCompleted,
+		PreparedBy:        bob.Name,
+	}
+	err = s.Database.UpsertModelImport(ctx, &completedImport)
+	c.Assert(err, qt.IsNil)
+	pendingImport := dbmodel.ModelImport{
+		ModelUUID:         "00000001-0000-0000-0000-0000-000000000005",
+		ModelName:         "imported-model-2",
+		SourceJIMM:        "controller-00000000-0000-0000-0000-000000000002",
+		ControllerID:      controller.ID,
+		OwnerIdentityName: bob.Name,
+		Status:            dbmodel.ModelImportPrepared,
+		PreparedBy:        alice.Name,
+	}
+	err = s.Database.UpsertModelImport(ctx, &pendingImport)
+	c.Assert(err, qt.IsNil)
+	_, err = s.Database.PurgeIdentity(ctx, &dbmodel.Identity{Name: bob.Name}, &tombstone, false)
+	c.Check(err, qt.ErrorMatches, `identity still owns 1 pending model import\(s\)`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	c.Assert(s.Database.DB.Delete(&pendingImport).Error, qt.IsNil)
+
+	_, err = s.Database.PurgeIdentity(ctx, &dbmodel.Identity{Name: bob.Name}, &tombstone, false)
+	c.Check(err, qt.ErrorMatches, `identity still owns 1 model\(s\)`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Database.DeleteModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	model.ID = 0
+	model.OwnerIdentityName = alice.Name
+	err = s.Database.AddModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	_, err = s.Database.PurgeIdentity(ctx, &dbmodel.Identity{Name: bob.Name}, &tombstone, false)
+	c.Check(err, qt.ErrorMatches, `cloud credentials still used by 1 model\(s\)`)
+
+	err = s.Database.DeleteModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	purge, err := s.Database.PurgeIdentity(ctx, &dbmodel.Identity{Name: bob.Name}, &tombstone, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(purge.AuditLogEntries, qt.Equals, int64(2))
+	c.Assert(purge.CloudCredentials, qt.HasLen, 1)
+	c.Check(purge.CloudCredentials[0].Name, qt.Equals, "test-cred")
+
+	err = s.Database.FetchIdentity(ctx, &dbmodel.Identity{Name: bob.Name})
+	c.Check(err, qt.ErrorMatches, "record not found")
+	err = s.Database.GetCloudCredential(ctx, &dbmodel.CloudCredential{
+		CloudName:         "test-cloud",
+		OwnerIdentityName: bob.Name,
+		Name:              "test-cred",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	for tag, want := range map[string]int{
+		bob.Tag().String():               0,
+		tombstone.ResourceTag().String(): 2,
+		alice.Tag().String():             1,
+	} {
+		var n int
+		err = s.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{IdentityTag: tag}, func(*dbmodel.AuditLogEntry) error {
+			n++
+			return nil
+		})
+		c.Assert(err, qt.IsNil)
+		c.Check(n, qt.Equals, want, qt.Commentf("%s", tag))
+	}
+
+	var ar dbmodel.AccessRequest
+	c.Assert(s.Database.DB.First(&ar).Error, qt.IsNil)
+	c.Check(ar.ReviewerName, qt.Equals, tombstone.Name)
+
+	// No record refers to the purged identity.
+	for _, col := range db.IdentityColumns() {
+		var n int64
+		err := s.Database.DB.Table(col[0]).Where(col[1]+" = ?", bob.Name).Count(&n).Error
+		c.Assert(err, qt.IsNil)
+		c.Check(n, qt.Equals, int64(0), qt.Commentf("%s.%s", col[0], col[1]))
+	}
+	for table, column := range map[string]string{
+		"model_freezes":          "frozen_by",
+		"model_network_policies": "set_by",
+		"model_migrations":       "initiated_by",
+		"operations":             "identity_name",
+		"model_dependencies":     "created_by",
+		"model_imports":          "prepared_by",
+		"deleted_models":         "owner_identity_name",
+		"pending_model_destroys": "requested_by",
+	} {
+		var n int64
+		err := s.Database.DB.Table(table).Where(column+" = ?", tombstone.Name).Count(&n).Error
+		c.Assert(err, qt.IsNil)
+		c.Check(n, qt.Equals, int64(1), qt.Commentf("%s.%s", table, column))
+	}
+
+	t := dbmodel.IdentityTombstone{Name: tombstone.Name}
+	err = s.Database.GetIdentityTombstone(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	c.Check(t.PurgedBy, qt.Equals, "alice@canonical.com")
+
+	_, err = s.Database.PurgeIdentity(ctx, &dbmodel.Identity{Name: bob.Name}, &dbmodel.IdentityTombstone{Name: "erased-2"}, false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
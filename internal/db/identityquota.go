@@ -0,0 +1,74 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetIdentityQuota records the given quota limits for an identity,
+// replacing any limits previously set for the identity.
+func (d *Database) SetIdentityQuota(ctx context.Context, q *dbmodel.IdentityQuota) (err error) {
+	const op = errors.Op("db.SetIdentityQuota")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "identity_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "models", "machines", "cores"}),
+	}).Create(q).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetIdentityQuota fills in the quota limits for the identity named in
+// the given q. If no limits have been set for the identity an error with
+// a code of CodeNotFound is returned.
+func (d *Database) GetIdentityQuota(ctx context.Context, q *dbmodel.IdentityQuota) (err error) {
+	const op = errors.Op("db.GetIdentityQuota")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Where("identity_name = ?", q.IdentityName).First(q).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteIdentityQuota removes the quota limits set for the identity named
+// in the given q, if there are any.
+func (d *Database) DeleteIdentityQuota(ctx context.Context, q *dbmodel.IdentityQuota) (err error) {
+	const op = errors.Op("db.DeleteIdentityQuota")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Where("identity_name = ?", q.IdentityName).Delete(&dbmodel.IdentityQuota{}).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
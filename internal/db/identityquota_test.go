@@ -0,0 +1,59 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetIdentityQuotaUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetIdentityQuota(context.Background(), &dbmodel.IdentityQuota{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestIdentityQuota(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(u).Error, qt.IsNil)
+
+	q := dbmodel.IdentityQuota{IdentityName: u.Name}
+	err = s.Database.GetIdentityQuota(ctx, &q)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	q.Models = sql.NullInt64{Int64: 5, Valid: true}
+	err = s.Database.SetIdentityQuota(ctx, &q)
+	c.Assert(err, qt.IsNil)
+
+	q.Models = sql.NullInt64{}
+	q.Cores = sql.NullInt64{Int64: 64, Valid: true}
+	err = s.Database.SetIdentityQuota(ctx, &q)
+	c.Assert(err, qt.IsNil)
+
+	q2 := dbmodel.IdentityQuota{IdentityName: u.Name}
+	err = s.Database.GetIdentityQuota(ctx, &q2)
+	c.Assert(err, qt.IsNil)
+	c.Check(q2.Models, qt.Equals, sql.NullInt64{})
+	c.Check(q2.Machines, qt.Equals, sql.NullInt64{})
+	c.Check(q2.Cores, qt.Equals, sql.NullInt64{Int64: 64, Valid: true})
+
+	err = s.Database.DeleteIdentityQuota(ctx, &q2)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetIdentityQuota(ctx, &q2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// RenameIdentity renames the given identity, which is found by name, to
+// the given name. All the records that refer to the identity, see
+// identityColumns, including its audit log entries, refer to the new name
+// afterwards. The given identity is updated to hold the renamed
+// identity. The cloud credentials owned by the identity are returned as
+// they were before the rename, so that they can be found under their old
+// tags. If the identity does not exist an error with a code of CodeNotFound is returned, if an
+// identity with the new name already exists an error with a code of
+// CodeAlreadyExists is returned.
+func (d *Database) RenameIdentity(ctx context.Context, i *dbmodel.Identity, name string) (_ []dbmodel.CloudCredential, err error) {
+	const op = errors.Op("db.RenameIdentity")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var credentials []dbmodel.CloudCredential
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("name = ?", i.Name).First(i).Error; err != nil {
+			return err
+		}
+		var n int64
+		if err := tx.Unscoped().Model(&dbmodel.Identity{}).Where("name = ?", name).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return errors.E(errors.CodeAlreadyExists, "identity "+name+" already exists")
+		}
+
+		// The records refer to the identity with foreign keys, so the
+		// renamed identity is created before they are moved to it and
+		// the old identity is only removed afterwards.
+		renamed := *i
+		renamed.ID = 0
+		renamed.Name = name
+		if i.DisplayName == strings.Split(i.Name, "@")[0] {
+			renamed.DisplayName = strings.Split(name, "@")[0]
+		}
+		if err := tx.Where("owner_identity_name = ?", i.Name).Find(&credentials).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&renamed).Error; err != nil {
+			return err
+		}
+		for _, c := range identityColumns {
+			if err := tx.Table(c.table).Where(c.column+" = ?", i.Name).Update(c.column, name).Error; err != nil {
+				return err
+			}
+		}
+		err := tx.Model(&dbmodel.AuditLogEntry{}).Where("identity_tag = ?", i.Tag().String()).Update("identity_tag", renamed.Tag().String()).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(i).Error; err != nil {
+			return err
+		}
+		*i = renamed
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return credentials, nil
+}
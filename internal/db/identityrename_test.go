@@ -0,0 +1,177 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/state"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestRenameIdentity(c *qt.C) {
+	ctx := context.Background()
+
+	_, err := s.Database.RenameIdentity(ctx, &dbmodel.Identity{Name: "bob@external"}, "bob@canonical.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(alice).Error, qt.IsNil)
+	bob, err := dbmodel.NewIdentity("bob@external")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(bob).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region",
+		}},
+	}
+	c.Assert(s.Database.DB.Create(&cloud).Error, qt.IsNil)
+	cred := dbmodel.CloudCredential{
+		Name:     "test-cred",
+		Cloud:    cloud,
+		Owner:    *bob,
+		AuthType: "empty",
+	}
+	c.Assert(s.Database.DB.Create(&cred).Error, qt.IsNil)
+	controller := dbmodel.Controller{
+		Name:        "test-controller",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region",
+	}
+	err = s.Database.AddController(ctx, &controller)
+	c.Assert(err, qt.IsNil)
+
+	model := dbmodel.Model{
+		Name: "test-model",
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+		OwnerIdentityName: bob.Name,
+		ControllerID:      controller.ID,
+		CloudRegionID:     cloud.Regions[0].ID,
+		CloudCredentialID: cred.ID,
+		Type:              "iaas",
+		DefaultSeries:     "warty",
+		Life:              state.Alive.String(),
+	}
+	err = s.Database.AddModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetIdentityModelDefaults(ctx, &dbmodel.IdentityModelDefaults{
+		IdentityName: bob.Name,
+		Defaults:     map[string]interface{}{"key": "value"},
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddAccessRequest(ctx, &dbmodel.AccessRequest{
+		IdentityName: alice.Name,
+		TargetTag:    model.Tag().String(),
+		Access:       "read",
+		Status:       "approved",
+		ReviewerName: bob.Name,
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+		Time:        time.Now(),
+		IdentityTag: bob.Tag().String(),
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.Database.RenameIdentity(ctx, &dbmodel.Identity{Name: "charlie@external"}, "charlie@canonical.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	_, err = s.Database.RenameIdentity(ctx, &dbmodel.Identity{Name: bob.Name}, alice.Name)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	renamed := dbmodel.Identity{Name: bob.Name}
+	credentials, err := s.Database.RenameIdentity(ctx, &renamed, "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(credentials, qt.HasLen, 1)
+	c.Check(credentials[0].OwnerIdentityName, qt.Equals, bob.Name)
+	c.Check(credentials[0].Name, qt.Equals, "test-cred")
+	c.Check(renamed.Name, qt.Equals, "bob@canonical.com")
+	c.Check(renamed.DisplayName, qt.Equals, "bob")
+
+	err = s.Database.FetchIdentity(ctx, &dbmodel.Identity{Name: bob.Name})
+	c.Check(err, qt.ErrorMatches, "record not found")
+	err = s.Database.FetchIdentity(ctx, &dbmodel.Identity{Name: "bob@canonical.com"})
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{UUID: model.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.OwnerIdentityName, qt.Equals, "bob@canonical.com")
+
+	err = s.Database.GetCloudCredential(ctx, &dbmodel.CloudCredential{
+		CloudName:         "test-cloud",
+		OwnerIdentityName: "bob@canonical.com",
+		Name:              "test-cred",
+	})
+	c.Check(err, qt.IsNil)
+
+	defaults := dbmodel.IdentityModelDefaults{IdentityName: "bob@canonical.com"}
+	err = s.Database.IdentityModelDefaults(ctx, &defaults)
+	c.Assert(err, qt.IsNil)
+	c.Check(defaults.Defaults, qt.DeepEquals, dbmodel.Map{"key": "value"})
+
+	var reviewer string
+	err = s.Database.DB.Table("access_requests").Select("reviewer_name").Scan(&reviewer).Error
+	c.Assert(err, qt.IsNil)
+	c.Check(reviewer, qt.Equals, "bob@canonical.com")
+
+	var n int
+	err = s.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{IdentityTag: renamed.Tag().String()}, func(*dbmodel.AuditLogEntry) error {
+		n++
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+}
+
+func (s *dbSuite) TestIdentityColumnsCoverSchema(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	// These columns hold the names of Juju users, not JIMM identities.
+	notIdentities := map[[2]string]bool{
+		{"controllers", "admin_identity_name"}:    true,
+		{"model_branches", "branch_created_by"}:   true,
+		{"model_branches", "branch_completed_by"}: true,
+	}
+
+	var schemaColumns []struct {
+		TableName  string
+		ColumnName string
+	}
+	err = s.Database.DB.Raw(`SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema()
+		AND (column_name LIKE '%identity_name' OR column_name LIKE '%\_by' OR column_name = 'reviewer_name')`).Scan(&schemaColumns).Error
+	c.Assert(err, qt.IsNil)
+	c.Assert(schemaColumns, qt.Not(qt.HasLen), 0)
+
+	registered := make(map[[2]string]bool)
+	for _, col := range db.IdentityColumns() {
+		registered[col] = true
+	}
+	for _, col := range schemaColumns {
+		key := [2]string{col.TableName, col.ColumnName}
+		if notIdentities[key] {
+			continue
+		}
+		c.Check(registered[key], qt.IsTrue, qt.Commentf("%s.%s is not a registered identity column", col.TableName, col.ColumnName))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddIdentitySession stores the given identity session. The ID of the
+// session is set to the ID of the stored record.
+func (d *Database) AddIdentitySession(ctx context.Context, s *dbmodel.IdentitySession) (err error) {
+	const op = errors.Op("db.AddIdentitySession")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(s).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// UpdateIdentitySessionActivity records the LastActivity of the given
+// session on both the session and the identity that opened it. The
+// identity's last activity is never moved backwards.
+func (d *Database) UpdateIdentitySessionActivity(ctx context.Context, s *dbmodel.IdentitySession) (err error) {
+	const op = errors.Op("db.UpdateIdentitySessionActivity")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&dbmodel.IdentitySession{}).
+			Where("id = ?", s.ID).
+			Update("last_activity", s.LastActivity).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&dbmodel.Identity{}).
+			Where("name = ?", s.IdentityName).
+			Where("last_activity IS NULL OR last_activity < ?", s.LastActivity).
+			UpdateColumn("last_activity", s.LastActivity).Error
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// RemoveIdentitySession removes the given identity session, if it is
+// still stored.
+func (d *Database) RemoveIdentitySession(ctx context.Context, s *dbmodel.IdentitySession) (err error) {
+	const op = errors.Op("db.RemoveIdentitySession")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("id = ?", s.ID).Delete(&dbmodel.IdentitySession{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListIdentitySessions returns the sessions open for the named identity,
+// oldest first.
+func (d *Database) ListIdentitySessions(ctx context.Context, identityName string) (_ []dbmodel.IdentitySession, err error) {
+	const op = errors.Op("db.ListIdentitySessions")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var sessions []dbmodel.IdentitySession
+	err = d.DB.WithContext(ctx).
+		Where("identity_name = ?", identityName).
+		Order("created_at, id").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return sessions, nil
+}
+
+// CountIdentitySessions returns the number of sessions open for the
+// named identity.
+func (d *Database) CountIdentitySessions(ctx context.Context, identityName string) (_ int, err error) {
+	const op = errors.Op("db.CountIdentitySessions")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var count int64
+	err = d.DB.WithContext(ctx).
+		Model(&dbmodel.IdentitySession{}).
+		Where("identity_name = ?", identityName).
+		Count(&count).Error
+	if err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return int(count), nil
+}
+
+// DeleteIdleIdentitySessions removes the sessions with no activity since
+// the given time, returning the number removed.
+func (d *Database) DeleteIdleIdentitySessions(ctx context.Context, before time.Time) (_ int64, err error) {
+	const op = errors.Op("db.DeleteIdleIdentitySessions")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Where("last_activity < ?", before).Delete(&dbmodel.IdentitySession{})
+	if result.Error != nil {
+		return 0, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAddIdentitySessionUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.AddIdentitySession(context.Background(), &dbmodel.IdentitySession{})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestIdentitySessions(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(u).Error, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	s1 := dbmodel.IdentitySession{
+		IdentityName:  u.Name,
+		LastActivity:  now.Add(-2 * time.Hour),
+		RemoteAddress: "10.0.0.1:4321",
+	}
+	err = s.Database.AddIdentitySession(ctx, &s1)
+	c.Assert(err, qt.IsNil)
+	c.Check(s1.ID, qt.Not(qt.Equals), uint(0))
+
+	s2 := dbmodel.IdentitySession{
+		IdentityName: u.Name,
+		LastActivity: now.Add(-time.Hour),
+	}
+	err = s.Database.AddIdentitySession(ctx, &s2)
+	c.Assert(err, qt.IsNil)
+
+	n, err := s.Database.CountIdentitySessions(ctx, u.Name)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 2)
+
+	s2.LastActivity = now
+	err = s.Database.UpdateIdentitySessionActivity(ctx, &s2)
+	c.Assert(err, qt.IsNil)
+
+	// Older activity in another session doesn't move the identity's
+	// last activity backwards.
+	s1.LastActivity = now.Add(-time.Minute)
+	err = s.Database.UpdateIdentitySessionActivity(ctx, &s1)
+	c.Assert(err, qt.IsNil)
+
+	u2 := dbmodel.Identity{Name: u.Name}
+	err = s.Database.GetIdentity(ctx, &u2)
+	c.Assert(err, qt.IsNil)
+	c.Check(u2.LastActivity.Valid, qt.IsTrue)
+	c.Check(u2.LastActivity.Time.Equal(now), qt.IsTrue)
+
+	sessions, err := s.Database.ListIdentitySessions(ctx, u.Name)
+	c.Assert(err, qt.IsNil)
+	c.Assert(sessions, qt.HasLen, 2)
+	c.Check(sessions[0].ID, qt.Equals, s1.ID)
+	c.Check(sessions[0].RemoteAddress, qt.Equals, "10.0.0.1:4321")
+	c.Check(sessions[0].LastActivity.Equal(now.Add(-time.Minute)), qt.IsTrue)
+	c.Check(sessions[1].ID, qt.Equals, s2.ID)
+
+	removed, err := s.Database.DeleteIdleIdentitySessions(ctx, now.Add(-30*time.Second))
+	c.Assert(err, qt.IsNil)
+	c.Check(removed, qt.Equals, int64(1))
+
+	err = s.Database.RemoveIdentitySession(ctx, &s2)
+	c.Assert(err, qt.IsNil)
+
+	n, err = s.Database.CountIdentitySessions(ctx, u.Name)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetIdentityTOTPSecret stores the given TOTP secret for the given
+// identity, replacing any previous secret and forgetting the last time
+// step accepted from it. The secret is encrypted if an Encrypter is
+// configured. The identity's TOTPSecret is set to the stored value.
+func (d *Database) SetIdentityTOTPSecret(ctx context.Context, i *dbmodel.Identity, secret string) (err error) {
+	const op = errors.Op("db.SetIdentityTOTPSecret")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if i.Name == "" {
+		return errors.E(op, errors.CodeNotFound, `invalid identity name ""`)
+	}
+	stored := secret
+	if d.Encrypter != nil && secret != "" {
+		sealed, err := d.Encrypter.Seal(ctx, []byte(secret), totpAdditionalData(i))
+		if err != nil {
+			return errors.E(op, err, "failed to encrypt TOTP secret")
+		}
+		stored = string(sealed)
+	}
+	result := d.DB.WithContext(ctx).Model(&dbmodel.Identity{}).
+		Where("name = ?", i.Name).
+		Updates(map[string]interface{}{
+			"totp_secret":    stored,
+			"totp_last_step": 0,
+		})
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "identity not found")
+	}
+	i.TOTPSecret = stored
+	i.TOTPLastStep = 0
+	return nil
+}
+
+// OpenIdentityTOTPSecret returns the TOTP secret of the given identity,
+// decrypting it if it is encrypted. Secrets stored before an Encrypter
+// was configured are returned as they are.
+func (d *Database) OpenIdentityTOTPSecret(ctx context.Context, i *dbmodel.Identity) (string, error) {
+	const op = errors.Op("db.OpenIdentityTOTPSecret")
+
+	if !envelope.IsSealed([]byte(i.TOTPSecret)) {
+		return i.TOTPSecret, nil
+	}
+	if d.Encrypter == nil {
+		return "", errors.E(op, errors.CodeServerConfiguration, "TOTP secret is encrypted but no encryption key is configured")
+	}
+	secret, err := d.Encrypter.Open(ctx, []byte(i.TOTPSecret), totpAdditionalData(i))
+	if err != nil {
+		return "", errors.E(op, err, "failed to decrypt TOTP secret")
+	}
+	return string(secret), nil
+}
+
+// UseIdentityTOTPStep records that a TOTP code for the given time step
+// has been accepted from the given identity's authenticator. If a code
+// for this or a later step has already been accepted nothing is
+// recorded and false is returned, so that each code can be used once
+// even when several JIMM replicas check codes at the same time.
+func (d *Database) UseIdentityTOTPStep(ctx context.Context, i *dbmodel.Identity, step int64) (_ bool, err error) {
+	const op = errors.Op("db.UseIdentityTOTPStep")
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Model(&dbmodel.Identity{}).
+		Where("name = ?", i.Name).
+		Where("totp_last_step < ?", step).
+		UpdateColumn("totp_last_step", step)
+	if result.Error != nil {
+		return false, errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	i.TOTPLastStep = step
+	return true, nil
+}
+
+// totpAdditionalData returns the additional data authenticated when
+// encrypting a TOTP secret, this binds the encrypted secret to the
+// identity.
+func totpAdditionalData(i *dbmodel.Identity) []byte {
+	return []byte("identity/" + i.Name + "/totp")
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestSetIdentityTOTPSecretUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.SetIdentityTOTPSecret(context.Background(), &dbmodel.Identity{Name: "bob@canonical.com"}, "SECRET")
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestIdentityTOTP(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	s.Database.Encrypter = newTestEncrypter(c, "key-1")
+	c.Cleanup(func() { s.Database.Encrypter = nil })
+
+	u, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(u).Error, qt.IsNil)
+
+	err = s.Database.SetIdentityTOTPSecret(ctx, &dbmodel.Identity{Name: "alice@canonical.com"}, "SECRET")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.SetIdentityTOTPSecret(ctx, u, "SECRET")
+	c.Assert(err, qt.IsNil)
+
+	u2 := dbmodel.Identity{Name: u.Name}
+	err = s.Database.GetIdentity(ctx, &u2)
+	c.Assert(err, qt.IsNil)
+	c.Check(envelope.IsSealed([]byte(u2.TOTPSecret)), qt.IsTrue)
+	secret, err := s.Database.OpenIdentityTOTPSecret(ctx, &u2)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret, qt.Equals, "SECRET")
+
+	// The secret is bound to the identity.
+	_, err = s.Database.OpenIdentityTOTPSecret(ctx, &dbmodel.Identity{Name: "alice@canonical.com", TOTPSecret: u2.TOTPSecret})
+	c.Check(err, qt.Not(qt.IsNil))
+
+	ok, err := s.Database.UseIdentityTOTPStep(ctx, &u2, 100)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+	ok, err = s.Database.UseIdentityTOTPStep(ctx, &u2, 100)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsFalse)
+	ok, err = s.Database.UseIdentityTOTPStep(ctx, &u2, 99)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsFalse)
+	ok, err = s.Database.UseIdentityTOTPStep(ctx, &u2, 101)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+
+	// Replacing the secret forgets the steps used.
+	err = s.Database.SetIdentityTOTPSecret(ctx, &u2, "SECRET2")
+	c.Assert(err, qt.IsNil)
+	ok, err = s.Database.UseIdentityTOTPStep(ctx, &u2, 50)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+
+	// Secrets stored without an encryption key are read as they are.
+	s.Database.Encrypter = nil
+	err = s.Database.SetIdentityTOTPSecret(ctx, &u2, "PLAIN")
+	c.Assert(err, qt.IsNil)
+	secret, err = s.Database.OpenIdentityTOTPSecret(ctx, &u2)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret, qt.Equals, "PLAIN")
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// An Index describes a database index required by one of JIMM's
+// frequently run queries.
+type Index struct {
+	// Name is the name the index is created with if it is missing.
+	Name string
+
+	// Table is the table the index is on.
+	Table string
+
+	// Columns holds the indexed columns. Any valid index whose leading
+	// columns are these columns, in any order, satisfies the
+	// requirement.
+	Columns []string
+
+	// Query describes the queries the index is required by.
+	Query string
+}
+
+// String returns the definition of the index.
+func (i Index) String() string {
+	return fmt.Sprintf("%s ON %s (%s)", i.Name, i.Table, strings.Join(i.Columns, ", "))
+}
+
+// requiredIndexes holds the indexes used by JIMM's frequently run
+// queries. Most are created by the unique constraints in the schema,
+// they are listed so that their absence is noticed.
+var requiredIndexes = []Index{{
+	Name:    "idx_models_uuid",
+	Table:   "models",
+	Columns: []string{"uuid"},
+	Query:   "models by UUID",
+}, {
+	Name:    "idx_models_owner_identity_name",
+	Table:   "models",
+	Columns: []string{"owner_identity_name"},
+	Query:   "models by owner",
+}, {
+	Name:    "idx_models_controller_id",
+	Table:   "models",
+	Columns: []string{"controller_id"},
+	Query:   "models by controller",
+}, {
+	Name:    "idx_machines_model_id",
+	Table:   "machines",
+	Columns: []string{"model_id"},
+	Query:   "machines by model",
+}, {
+	Name:    "idx_cloud_credentials_owner_identity_name_cloud_name",
+	Table:   "cloud_credentials",
+	Columns: []string{"owner_identity_name", "cloud_name"},
+	Query:   "cloud credentials by user and cloud",
+}}
+
+// RequiredIndexes returns the indexes required by JIMM's frequently run
+// queries.
+func RequiredIndexes() []Index {
+	return append([]Index(nil), requiredIndexes...)
+}
+
+// indexesQuery lists the indexes on a table, with whether they are
+// valid and their columns in order. Indexes left invalid by a failed
+// concurrent build are not used by the query planner.
+const indexesQuery = `
+SELECT ic.relname AS name, i.indisvalid AS valid,
+	array_to_string(ARRAY(
+		SELECT a.attname FROM unnest(i.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+		ORDER BY k.ord
+	), ',') AS columns
+FROM pg_index i
+JOIN pg_class t ON t.oid = i.indrelid
+JOIN pg_class ic ON ic.oid = i.indexrelid
+WHERE t.relname = ? AND pg_table_is_visible(t.oid)`
+
+type tableIndex struct {
+	Name    string
+	Valid   bool
+	Columns string
+}
+
+// covers reports whether the index can be used for queries on all the
+// given columns.
+func (ti tableIndex) covers(columns []string) bool {
+	if !ti.Valid {
+		return false
+	}
+	indexed := strings.Split(ti.Columns, ",")
+	if len(indexed) < len(columns) {
+		return false
+	}
+	leading := make(map[string]bool, len(columns))
+	for _, c := range indexed[:len(columns)] {
+		leading[c] = true
+	}
+	for _, c := range columns {
+		if !leading[c] {
+			return false
+		}
+	}
+	return true
+}
+
+// MissingIndexes returns the required indexes, see RequiredIndexes, that
+// are not satisfied by a valid index in the database.
+func (d *Database) MissingIndexes(ctx context.Context) (_ []Index, err error) {
+	const op = errors.Op("db.MissingIndexes")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	tables := make(map[string][]tableIndex)
+	var missing []Index
+	for _, idx := range requiredIndexes {
+		indexes, ok := tables[idx.Table]
+		if !ok {
+			if err := db.Raw(indexesQuery, idx.Table).Scan(&indexes).Error; err != nil {
+				return nil, errors.E(op, dbError(err))
+			}
+			tables[idx.Table] = indexes
+		}
+		covered := false
+		for _, ti := range indexes {
+			if ti.covers(idx.Columns) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, idx)
+		}
+	}
+	return missing, nil
+}
+
+// CreateIndex builds the given index in the background, without locking
+// the table against writes. Any invalid index with the same name, left
+// by a previous build that failed, is dropped first. The build cannot be
+// run in a transaction.
+func (d *Database) CreateIndex(ctx context.Context, idx Index) (err error) {
+	const op = errors.Op("db.CreateIndex")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	var invalid int64
+	err = db.Raw(`SELECT COUNT(*) FROM pg_index i JOIN pg_class ic ON ic.oid = i.indexrelid WHERE ic.relname = ? AND NOT i.indisvalid AND pg_table_is_visible(ic.oid)`, idx.Name).Scan(&invalid).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	if invalid > 0 {
+		if err := db.Exec(fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %q", idx.Name)).Error; err != nil {
+			return errors.E(op, dbError(err))
+		}
+	}
+
+	columns := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		columns[i] = fmt.Sprintf("%q", c)
+	}
+	stmt := fmt.Sprintf("CREATE INDEX CONCURRENTLY IF NOT EXISTS %q ON %q (%s)", idx.Name, idx.Table, strings.Join(columns, ", "))
+	if err := db.Exec(stmt).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
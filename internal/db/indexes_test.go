@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestMissingIndexesUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.MissingIndexes(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestMissingIndexes(c *qt.C) {
+	ctx := context.Background()
+
+	_, err := s.Database.MissingIndexes(ctx)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// Most required indexes are created by unique constraints in the
+	// schema.
+	missing, err := s.Database.MissingIndexes(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(missing, qt.HasLen, 1)
+	c.Check(missing[0].Name, qt.Equals, "idx_models_controller_id")
+
+	err = s.Database.CreateIndex(ctx, missing[0])
+	c.Assert(err, qt.IsNil)
+	missing, err = s.Database.MissingIndexes(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(missing, qt.HasLen, 0)
+
+	// Creating an existing index is not an error.
+	err = s.Database.CreateIndex(ctx, db.RequiredIndexes()[2])
+	c.Assert(err, qt.IsNil)
+
+	// An index that is dropped is reported missing.
+	err = s.Database.DB.Exec(`DROP INDEX idx_models_controller_id`).Error
+	c.Assert(err, qt.IsNil)
+	missing, err = s.Database.MissingIndexes(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(missing, qt.HasLen, 1)
+	c.Check(missing[0].Table, qt.Equals, "models")
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AcquireLease attempts to claim the lease with the given name for the
+// given holder until the given expiry time. The lease is claimed if it
+// does not yet exist, is already held by the same holder, or has expired
+// at the given time now. AcquireLease reports whether the lease is held
+// by the holder after the call.
+func (d *Database) AcquireLease(ctx context.Context, lease *dbmodel.Lease, now time.Time) (_ bool, err error) {
+	const op = errors.Op("db.AcquireLease")
+
+	if lease.Name == "" || lease.Holder == "" {
+		return false, errors.E(op, errors.CodeBadRequest, "missing lease name or holder")
+	}
+	if err := d.ready(); err != nil {
+		return false, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	// Only take over an existing lease when it is already ours or has
+	// expired. If neither is the case no rows are affected.
+	result := d.DB.WithContext(ctx).Exec(`
+		INSERT INTO leases (name, created_at, updated_at, holder, expires)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			updated_at = excluded.updated_at,
+			holder = excluded.holder,
+			expires = excluded.expires
+		WHERE leases.holder = excluded.holder OR leases.expires < ?`,
+		lease.Name, now, now, lease.Holder, lease.Expires, now,
+	)
+	if result.Error != nil {
+		return false, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseLease releases the given lease if it is held by the lease's
+// holder. Releasing a lease that is not held by the holder is not an
+// error.
+func (d *Database) ReleaseLease(ctx context.Context, lease *dbmodel.Lease) (err error) {
+	const op = errors.Op("db.ReleaseLease")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("name = ? AND holder = ?", lease.Name, lease.Holder)
+	if err := db.Delete(&dbmodel.Lease{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListLeases returns all leases, including any that have expired, ordered
+// by name.
+func (d *Database) ListLeases(ctx context.Context) (_ []dbmodel.Lease, err error) {
+	const op = errors.Op("db.ListLeases")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var leases []dbmodel.Lease
+	if err := d.DB.WithContext(ctx).Order("name").Find(&leases).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return leases, nil
+}
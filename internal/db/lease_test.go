@@ -0,0 +1,102 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAcquireLeaseUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.AcquireLease(context.Background(), &dbmodel.Lease{Name: "worker", Holder: "replica-1"}, time.Now())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestAcquireAndReleaseLease(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Round(time.Millisecond)
+
+	_, err = s.Database.AcquireLease(ctx, &dbmodel.Lease{Name: "worker"}, now)
+	c.Check(err, qt.ErrorMatches, `missing lease name or holder`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// The first holder acquires the lease.
+	acquired, err := s.Database.AcquireLease(ctx, &dbmodel.Lease{
+		Name:    "worker",
+		Holder:  "replica-1",
+		Expires: now.Add(time.Minute),
+	}, now)
+	c.Assert(err, qt.IsNil)
+	c.Check(acquired, qt.IsTrue)
+
+	// Another holder cannot acquire an unexpired lease.
+	acquired, err = s.Database.AcquireLease(ctx, &dbmodel.Lease{
+		Name:    "worker",
+		Holder:  "replica-2",
+		Expires: now.Add(time.Minute),
+	}, now)
+	c.Assert(err, qt.IsNil)
+	c.Check(acquired, qt.IsFalse)
+
+	// The holder can renew its lease.
+	acquired, err = s.Database.AcquireLease(ctx, &dbmodel.Lease{
+		Name:    "worker",
+		Holder:  "replica-1",
+		Expires: now.Add(2 * time.Minute),
+	}, now.Add(30*time.Second))
+	c.Assert(err, qt.IsNil)
+	c.Check(acquired, qt.IsTrue)
+
+	leases, err := s.Database.ListLeases(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(leases, qt.HasLen, 1)
+	c.Check(leases[0].Name, qt.Equals, "worker")
+	c.Check(leases[0].Holder, qt.Equals, "replica-1")
+	c.Check(leases[0].Expires.Equal(now.Add(2*time.Minute)), qt.IsTrue)
+
+	// Another holder takes over once the lease expires.
+	acquired, err = s.Database.AcquireLease(ctx, &dbmodel.Lease{
+		Name:    "worker",
+		Holder:  "replica-2",
+		Expires: now.Add(4 * time.Minute),
+	}, now.Add(3*time.Minute))
+	c.Assert(err, qt.IsNil)
+	c.Check(acquired, qt.IsTrue)
+
+	// Releasing a lease held by someone else has no effect.
+	err = s.Database.ReleaseLease(ctx, &dbmodel.Lease{Name: "worker", Holder: "replica-1"})
+	c.Assert(err, qt.IsNil)
+	leases, err = s.Database.ListLeases(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(leases, qt.HasLen, 1)
+	c.Check(leases[0].Holder, qt.Equals, "replica-2")
+
+	// Once released the lease is immediately available.
+	err = s.Database.ReleaseLease(ctx, &dbmodel.Lease{Name: "worker", Holder: "replica-2"})
+	c.Assert(err, qt.IsNil)
+	leases, err = s.Database.ListLeases(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(leases, qt.HasLen, 0)
+
+	acquired, err = s.Database.AcquireLease(ctx, &dbmodel.Lease{
+		Name:    "worker",
+		Holder:  "replica-1",
+		Expires: now.Add(5 * time.Minute),
+	}, now.Add(4*time.Minute))
+	c.Assert(err, qt.IsNil)
+	c.Check(acquired, qt.IsTrue)
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// UpsertMachine stores the given machine, replacing any existing machine
+// with the same ID in the same model.
+func (d *Database) UpsertMachine(ctx context.Context, machine *dbmodel.Machine) (err error) {
+	const op = errors.Op("db.UpsertMachine")
+
+	if machine.ModelID == 0 || machine.MachineID == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing model or machine ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}, {Name: "machine_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "instance_id", "hostname", "life", "availability_zone", "addresses", "subnets"}),
+	})
+	if err := db.Create(machine).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteMachine removes the machine with the given ID from the given
+// model. Deleting a machine that does not exist is not an error.
+func (d *Database) DeleteMachine(ctx context.Context, machine *dbmodel.Machine) (err error) {
+	const op = errors.Op("db.DeleteMachine")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("model_id = ? AND machine_id = ?", machine.ModelID, machine.MachineID)
+	if err := db.Delete(&dbmodel.Machine{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelMachines returns the machines in the model with the given ID,
+// ordered by machine ID.
+func (d *Database) GetModelMachines(ctx context.Context, modelID uint) (_ []dbmodel.Machine, err error) {
+	const op = errors.Op("db.GetModelMachines")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var machines []dbmodel.Machine
+	if err := d.DB.WithContext(ctx).Where("model_id = ?", modelID).Order("machine_id").Find(&machines).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return machines, nil
+}
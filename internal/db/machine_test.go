@@ -0,0 +1,91 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestUpsertMachineUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.UpsertMachine(context.Background(), &dbmodel.Machine{ModelID: 1, MachineID: "0"})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpsertAndDeleteMachine(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.UpsertMachine(ctx, &dbmodel.Machine{ModelID: env.model.ID})
+	c.Check(err, qt.ErrorMatches, `missing model or machine ID`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	for _, m := range []dbmodel.Machine{{
+		ModelID:   env.model.ID,
+		MachineID: "1",
+		Life:      "alive",
+	}, {
+		ModelID:   env.model.ID,
+		MachineID: "0",
+		Life:      "alive",
+	}, {
+		ModelID:          env.model.ID,
+		MachineID:        "0",
+		InstanceID:       "i-0",
+		Life:             "alive",
+		AvailabilityZone: "zone-1",
+		Addresses: dbmodel.Addresses{{
+			Value: "10.0.0.4",
+			CIDR:  "10.0.0.0/24",
+			Type:  "ipv4",
+			Scope: "local-cloud",
+		}},
+		Subnets: dbmodel.Strings{"10.0.0.0/24"},
+	}} {
+		err := s.Database.UpsertMachine(ctx, &m)
+		c.Assert(err, qt.IsNil)
+	}
+
+	machines, err := s.Database.GetModelMachines(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(machines, qt.HasLen, 2)
+	c.Check(machines[0].MachineID, qt.Equals, "0")
+	c.Check(machines[0].InstanceID, qt.Equals, "i-0")
+	c.Check(machines[0].AvailabilityZone, qt.Equals, "zone-1")
+	c.Check(machines[0].Addresses, qt.DeepEquals, dbmodel.Addresses{{
+		Value: "10.0.0.4",
+		CIDR:  "10.0.0.0/24",
+		Type:  "ipv4",
+		Scope: "local-cloud",
+	}})
+	c.Check(machines[0].Subnets, qt.DeepEquals, dbmodel.Strings{"10.0.0.0/24"})
+	c.Check(machines[1].MachineID, qt.Equals, "1")
+
+	err = s.Database.DeleteMachine(ctx, &dbmodel.Machine{ModelID: env.model.ID, MachineID: "0"})
+	c.Assert(err, qt.IsNil)
+	// Deleting a machine that does not exist is not an error.
+	err = s.Database.DeleteMachine(ctx, &dbmodel.Machine{ModelID: env.model.ID, MachineID: "0"})
+	c.Assert(err, qt.IsNil)
+
+	machines, err = s.Database.GetModelMachines(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(machines, qt.HasLen, 1)
+	c.Check(machines[0].MachineID, qt.Equals, "1")
+
+	// Machines are removed with their model.
+	err = s.Database.DeleteModel(ctx, &env.model)
+	c.Assert(err, qt.IsNil)
+	machines, err = s.Database.GetModelMachines(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(machines, qt.HasLen, 0)
+}
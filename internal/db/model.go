@@ -4,6 +4,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -113,7 +114,9 @@ func (d *Database) UpdateModel(ctx context.Context, model *dbmodel.Model) (err e
 	return nil
 }
 
-// DeleteModel removes the model information from the database.
+// DeleteModel removes the model information from the database. A
+// record of the removed model is retained, see DeletedModel, so that the
+// model can be inspected and restored until the record is pruned.
 func (d *Database) DeleteModel(ctx context.Context, model *dbmodel.Model) (err error) {
 	const op = errors.Op("db.DeleteModel")
 	if err := d.ready(); err != nil {
@@ -124,16 +127,37 @@ func (d *Database) DeleteModel(ctx context.Context, model *dbmodel.Model) (err e
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
 
-	db := d.DB.WithContext(ctx)
-	if err := db.Delete(model, model.ID).Error; err != nil {
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m dbmodel.Model
+		err := tx.Preload("Controller").Preload("CloudRegion").Preload("CloudCredential").First(&m, model.ID).Error
+		switch {
+		case err == nil:
+			if err := tx.Create(dbmodel.NewDeletedModel(&m, time.Now().UTC())).Error; err != nil {
+				return err
+			}
+		case err != gorm.ErrRecordNotFound:
+			return err
+		}
+		return tx.Delete(model, model.ID).Error
+	})
+	if err != nil {
 		return errors.E(op, dbError(err))
 	}
 	return nil
 }
 
+// modelBatchSize is the number of models loaded from the database at a
+// time by ForEachModel.
+var modelBatchSize = 500
+
 // ForEachModel iterates through every model calling the given function
 // for each one. If the given function returns an error the iteration
 // will stop immediately and the error will be returned unmodified.
+// Models are loaded, along with their associations, in batches so that
+// iterating over all models takes a small number of queries regardless
+// of the number of models. Each batch starts after the highest ID of the
+// previous one, so that models are neither skipped nor repeated when
+// models are added or removed during the iteration, including by f.
 func (d *Database) ForEachModel(ctx context.Context, f func(m *dbmodel.Model) error) (err error) {
 	const op = errors.Op("db.ForEachModel")
 
@@ -145,33 +169,24 @@ func (d *Database) ForEachModel(ctx context.Context, f func(m *dbmodel.Model) er
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
 
-	db := d.DB.WithContext(ctx)
-	db = preloadModel("", db)
-	rows, err := db.Model(&dbmodel.Model{}).Rows()
-	if err != nil {
-		return errors.E(op, err)
-	}
-	defer rows.Close()
-	for rows.Next() {
-		var m dbmodel.Model
-		if err := db.ScanRows(rows, &m); err != nil {
-			return errors.E(op, err)
+	var lastID uint
+	for {
+		var models []dbmodel.Model
+		db := preloadModel("", d.DB.WithContext(ctx))
+		db = db.Where("models.id > ?", lastID).Order("models.id").Limit(modelBatchSize)
+		if err := db.Find(&models).Error; err != nil {
+			return errors.E(op, dbError(err))
 		}
-		// ScanRows does not use the preloads added on L141, therefore
-		// we need to fetch each model to load the associated
-		// fields otherwise the only populated fields will be association
-		// IDs.
-		if err := d.GetModel(ctx, &m); err != nil {
-			return errors.E(op, err)
+		for i := range models {
+			if err := f(&models[i]); err != nil {
+				return err
+			}
 		}
-		if err := f(&m); err != nil {
-			return err
+		if len(models) < modelBatchSize {
+			return nil
 		}
+		lastID = models[len(models)-1].ID
 	}
-	if err := rows.Err(); err != nil {
-		return errors.E(op, dbError(err))
-	}
-	return nil
 }
 
 // GetModelsByUUID retrieves a list of models where the model UUIDs are in
@@ -201,6 +216,27 @@ func (d *Database) GetModelsByUUID(ctx context.Context, modelUUIDs []string) (_
 	return models, nil
 }
 
+// GetModelsByName returns all models with the given name, whatever their
+// owner, ordered by owner.
+func (d *Database) GetModelsByName(ctx context.Context, name string) (_ []dbmodel.Model, err error) {
+	const op = errors.Op("db.GetModelsByName")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var models []dbmodel.Model
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("name = ?", name).Order("owner_identity_name").Find(&models).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return models, nil
+}
+
 func preloadModel(prefix string, db *gorm.DB) *gorm.DB {
 	if len(prefix) > 0 && prefix[len(prefix)-1] != '.' {
 		prefix += "."
@@ -248,3 +284,39 @@ func (d *Database) CountModelsByController(ctx context.Context, ctl dbmodel.Cont
 	}
 	return int(count), nil
 }
+
+// ModelUsage holds the resources used by the models owned by an
+// identity.
+type ModelUsage struct {
+	// Models is the number of models.
+	Models int64
+
+	// Machines is the total number of machines in the models.
+	Machines int64
+
+	// Cores is the total number of cores in the models.
+	Cores int64
+}
+
+// ModelUsageByOwner returns the resources used by the models owned by
+// the identity with the given name.
+func (d *Database) ModelUsageByOwner(ctx context.Context, ownerName string) (_ ModelUsage, err error) {
+	const op = errors.Op("db.ModelUsageByOwner")
+
+	if err := d.ready(); err != nil {
+		return ModelUsage{}, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var usage ModelUsage
+	db := d.DB.WithContext(ctx).Model(&dbmodel.Model{})
+	db = db.Select("COUNT(*) AS models, COALESCE(SUM(machines), 0) AS machines, COALESCE(SUM(cores), 0) AS cores")
+	db = db.Where("owner_identity_name = ?", ownerName)
+	if err := db.Scan(&usage).Error; err != nil {
+		return ModelUsage{}, errors.E(op, dbError(err))
+	}
+	return usage, nil
+}
@@ -554,6 +554,33 @@ func (s *dbSuite) TestForEachModel(c *qt.C) {
 	})
 }
 
+func (s *dbSuite) TestForEachModelBatches(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testForEachModelEnv)
+	env.PopulateDB(c, *s.Database)
+
+	batchSize := *db.ModelBatchSize
+	*db.ModelBatchSize = 2
+	c.Cleanup(func() { *db.ModelBatchSize = batchSize })
+
+	// Removing models as they are visited does not cause the models in
+	// later batches to be skipped.
+	var models []string
+	err = s.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		models = append(models, m.UUID.String)
+		return s.Database.DeleteModel(ctx, m)
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.DeepEquals, []string{
+		"00000002-0000-0000-0000-000000000001",
+		"00000002-0000-0000-0000-000000000002",
+		"00000002-0000-0000-0000-000000000003",
+	})
+}
+
 const testGetModelsByUUIDEnv = `clouds:
 - name: test
   type: test
@@ -639,6 +666,25 @@ func (s *dbSuite) TestGetModelsByUUID(c *qt.C) {
 	c.Check(models[2].Controller.Name, qt.Not(qt.Equals), "")
 }
 
+func (s *dbSuite) TestGetModelsByName(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testGetModelsByUUIDEnv)
+	env.PopulateDB(c, *s.Database)
+
+	models, err := s.Database.GetModelsByName(ctx, "test-2")
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 1)
+	c.Check(models[0].UUID.String, qt.Equals, "00000002-0000-0000-0000-000000000002")
+	c.Check(models[0].OwnerIdentityName, qt.Equals, "bob@canonical.com")
+
+	models, err = s.Database.GetModelsByName(ctx, "no-such-model")
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+}
+
 func (s *dbSuite) TestGetModelsByController(c *qt.C) {
 	err := s.Database.Migrate(context.Background(), true)
 	c.Assert(err, qt.Equals, nil)
@@ -788,3 +834,25 @@ func (s *dbSuite) TestCountModelsByController(c *qt.C) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(count, qt.Equals, 3)
 }
+
+func (s *dbSuite) TestModelUsageByOwner(c *qt.C) {
+	err := s.Database.Migrate(context.Background(), true)
+	c.Assert(err, qt.Equals, nil)
+
+	env := jimmtest.ParseEnvironment(c, testCountModelsByControllerEnv)
+	env.PopulateDB(c, *s.Database)
+
+	m := env.Models[1].DBObject(c, *s.Database)
+	m.Machines = 3
+	m.Cores = 6
+	err = s.Database.UpdateModel(context.Background(), &m)
+	c.Assert(err, qt.IsNil)
+
+	usage, err := s.Database.ModelUsageByOwner(context.Background(), "bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.Equals, db.ModelUsage{Models: 2, Machines: 3, Cores: 6})
+
+	usage, err = s.Database.ModelUsageByOwner(context.Background(), "charlie@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(usage, qt.Equals, db.ModelUsage{})
+}
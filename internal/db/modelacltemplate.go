@@ -0,0 +1,92 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddModelACLTemplate stores the given model ACL template. If a template
+// for the same owner and grant groups already exists an error with the
+// code CodeAlreadyExists is returned.
+func (d *Database) AddModelACLTemplate(ctx context.Context, t *dbmodel.ModelACLTemplate) (err error) {
+	const op = errors.Op("db.AddModelACLTemplate")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("OwnerGroup", "GrantGroup").Create(t).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelACLTemplate completes the given model ACL template, which is
+// identified by its ID. If the template cannot be found an error with the
+// code CodeNotFound is returned.
+func (d *Database) GetModelACLTemplate(ctx context.Context, t *dbmodel.ModelACLTemplate) (err error) {
+	const op = errors.Op("db.GetModelACLTemplate")
+	if t.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("OwnerGroup").Preload("GrantGroup")
+	if err := db.First(t, t.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListModelACLTemplates returns all model ACL templates ordered by ID.
+func (d *Database) ListModelACLTemplates(ctx context.Context) (_ []dbmodel.ModelACLTemplate, err error) {
+	const op = errors.Op("db.ListModelACLTemplates")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var templates []dbmodel.ModelACLTemplate
+	db := d.DB.WithContext(ctx).Preload("OwnerGroup").Preload("GrantGroup")
+	if err := db.Order("id").Find(&templates).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return templates, nil
+}
+
+// DeleteModelACLTemplate removes the given model ACL template.
+func (d *Database) DeleteModelACLTemplate(ctx context.Context, t *dbmodel.ModelACLTemplate) (err error) {
+	const op = errors.Op("db.DeleteModelACLTemplate")
+	if t.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(t).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelACLTemplates(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddModelACLTemplate(ctx, &dbmodel.ModelACLTemplate{Access: "read"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	payments, err := s.Database.AddGroup(ctx, "payments")
+	c.Assert(err, qt.IsNil)
+	paymentsAdmins, err := s.Database.AddGroup(ctx, "payments-admins")
+	c.Assert(err, qt.IsNil)
+
+	t1 := dbmodel.ModelACLTemplate{
+		OwnerGroupID: payments.ID,
+		GrantGroupID: payments.ID,
+		Access:       "read",
+	}
+	err = s.Database.AddModelACLTemplate(ctx, &t1)
+	c.Assert(err, qt.IsNil)
+	t2 := dbmodel.ModelACLTemplate{
+		OwnerGroupID: payments.ID,
+		GrantGroupID: paymentsAdmins.ID,
+		Access:       "admin",
+	}
+	err = s.Database.AddModelACLTemplate(ctx, &t2)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddModelACLTemplate(ctx, &dbmodel.ModelACLTemplate{
+		OwnerGroupID: payments.ID,
+		GrantGroupID: payments.ID,
+		Access:       "write",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	t := dbmodel.ModelACLTemplate{ID: t2.ID}
+	err = s.Database.GetModelACLTemplate(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	c.Check(t.OwnerGroup.Name, qt.Equals, "payments")
+	c.Check(t.GrantGroup.Name, qt.Equals, "payments-admins")
+	c.Check(t.Access, qt.Equals, "admin")
+
+	templates, err := s.Database.ListModelACLTemplates(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(templates, qt.HasLen, 2)
+	c.Check(templates[0].ID, qt.Equals, t1.ID)
+	c.Check(templates[0].GrantGroup.Name, qt.Equals, "payments")
+	c.Check(templates[1].ID, qt.Equals, t2.ID)
+
+	err = s.Database.DeleteModelACLTemplate(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetModelACLTemplate(ctx, &dbmodel.ModelACLTemplate{ID: t2.ID})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.GetModelACLTemplate(ctx, &dbmodel.ModelACLTemplate{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.DeleteModelACLTemplate(ctx, &dbmodel.ModelACLTemplate{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetModelLastConnection records that a user connected to the given
+// model, which must have its ID set, at the given time. Only the
+// connection time is updated.
+func (d *Database) SetModelLastConnection(ctx context.Context, m *dbmodel.Model, t time.Time) (err error) {
+	const op = errors.Op("db.SetModelLastConnection")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Model(&dbmodel.Model{}).Where("id = ?", m.ID).UpdateColumn("last_connection_at", t)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model not found")
+	}
+	m.LastConnectionAt.Time = t
+	m.LastConnectionAt.Valid = true
+	return nil
+}
+
+// ListIdleModels returns the alive models, other than controller
+// models, that have not been active since the given time. A model is
+// active when it is added, when its units change and when a user
+// connects to it. The models are ordered by ID.
+func (d *Database) ListIdleModels(ctx context.Context, since time.Time) (_ []dbmodel.Model, err error) {
+	const op = errors.Op("db.ListIdleModels")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := preloadModel("", d.DB.WithContext(ctx))
+	var models []dbmodel.Model
+	db = db.Where("life = ? AND NOT is_controller AND created_at < ?", "alive", since)
+	db = db.Where("(last_unit_change_at IS NULL OR last_unit_change_at < ?)", since)
+	db = db.Where("(last_connection_at IS NULL OR last_connection_at < ?)", since)
+	if err := db.Order("id").Find(&models).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return models, nil
+}
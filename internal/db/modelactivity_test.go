@@ -0,0 +1,73 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelActivity(c *qt.C) {
+	ctx := context.Background()
+
+	_, err := s.Database.ListIdleModels(ctx, time.Now())
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env := initTestEnvironment(c, s.Database)
+
+	// A model that has never been active since it was added is idle.
+	now := time.Now()
+	models, err := s.Database.ListIdleModels(ctx, now.Add(time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 1)
+	c.Check(models[0].UUID, qt.DeepEquals, env.model.UUID)
+	c.Check(models[0].Controller.Name, qt.Equals, env.controller.Name)
+	c.Check(models[0].Owner.Name, qt.Equals, env.u.Name)
+
+	// Models added after the given time are not idle.
+	models, err = s.Database.ListIdleModels(ctx, now.Add(-time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+
+	// Connecting to the model makes it active.
+	err = s.Database.SetModelLastConnection(ctx, &env.model, now.Add(2*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(env.model.LastConnectionAt.Valid, qt.IsTrue)
+	models, err = s.Database.ListIdleModels(ctx, now.Add(time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+	models, err = s.Database.ListIdleModels(ctx, now.Add(3*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 1)
+
+	// So does a change to its units.
+	m := dbmodel.Model{UUID: env.model.UUID}
+	err = s.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.LastConnectionAt.Time.Equal(now.Add(2*time.Hour).Truncate(time.Microsecond)), qt.IsTrue)
+	m.LastUnitChangeAt = sql.NullTime{Time: now.Add(4 * time.Hour), Valid: true}
+	err = s.Database.UpdateModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	models, err = s.Database.ListIdleModels(ctx, now.Add(3*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+
+	// Dying models are not idle.
+	m.Life = "dying"
+	err = s.Database.UpdateModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	models, err = s.Database.ListIdleModels(ctx, now.Add(5*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+
+	err = s.Database.SetModelLastConnection(ctx, &dbmodel.Model{ID: 9999}, now)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// UpsertModelBranch stores the given model branch, replacing any existing
+// branch with the same branch ID in the same model.
+func (d *Database) UpsertModelBranch(ctx context.Context, branch *dbmodel.ModelBranch) (err error) {
+	const op = errors.Op("db.UpsertModelBranch")
+
+	if branch.ModelID == 0 || branch.BranchID == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing model or branch ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "model_id"}, {Name: "branch_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at",
+			"name",
+			"generation_id",
+			"assigned_units",
+			"config_changes",
+			"branch_created",
+			"branch_created_by",
+			"branch_completed",
+			"branch_completed_by",
+		}),
+	})
+	if err := db.Create(branch).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteModelBranch removes the branch with the given branch ID from the
+// given model. Deleting a branch that does not exist is not an error.
+func (d *Database) DeleteModelBranch(ctx context.Context, branch *dbmodel.ModelBranch) (err error) {
+	const op = errors.Op("db.DeleteModelBranch")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("model_id = ? AND branch_id = ?", branch.ModelID, branch.BranchID)
+	if err := db.Delete(&dbmodel.ModelBranch{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelBranches returns the branches of the model with the given ID,
+// ordered by name.
+func (d *Database) GetModelBranches(ctx context.Context, modelID uint) (_ []dbmodel.ModelBranch, err error) {
+	const op = errors.Op("db.GetModelBranches")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var branches []dbmodel.ModelBranch
+	if err := d.DB.WithContext(ctx).Where("model_id = ?", modelID).Order("name").Find(&branches).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return branches, nil
+}
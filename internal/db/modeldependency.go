@@ -0,0 +1,107 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddModelDependency stores the given model dependency. Adding a
+// dependency that is already stored replaces its creator and
+// description.
+func (d *Database) AddModelDependency(ctx context.Context, dep *dbmodel.ModelDependency) (err error) {
+	const op = errors.Op("db.AddModelDependency")
+	if dep.ModelID == 0 || dep.DependsOnID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model", "DependsOn").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}, {Name: "depends_on_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"created_by", "description"}),
+	})
+	if err := db.Create(dep).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// RemoveModelDependency removes the dependency of the model with the
+// ModelID of the given dependency on the model with its DependsOnID. If
+// there is no such dependency an error with the code CodeNotFound is
+// returned.
+func (d *Database) RemoveModelDependency(ctx context.Context, dep *dbmodel.ModelDependency) (err error) {
+	const op = errors.Op("db.RemoveModelDependency")
+	if dep.ModelID == 0 || dep.DependsOnID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Delete(&dbmodel.ModelDependency{}, "model_id = ? AND depends_on_id = ?", dep.ModelID, dep.DependsOnID)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return errors.E(op, errors.CodeNotFound, "model dependency not found")
+	}
+	return nil
+}
+
+// ListModelDependencies returns the dependencies declared by the model
+// with the given ID, ordered by the ID of the model depended on.
+func (d *Database) ListModelDependencies(ctx context.Context, modelID uint) (_ []dbmodel.ModelDependency, err error) {
+	const op = errors.Op("db.ListModelDependencies")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var deps []dbmodel.ModelDependency
+	db := d.DB.WithContext(ctx).Preload("Model").Preload("DependsOn").Where("model_id = ?", modelID).Order("depends_on_id")
+	if err := db.Find(&deps).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return deps, nil
+}
+
+// ListModelDependents returns the dependencies other models have
+// declared on the model with the given ID, ordered by the ID of the
+// dependent model.
+func (d *Database) ListModelDependents(ctx context.Context, modelID uint) (_ []dbmodel.ModelDependency, err error) {
+	const op = errors.Op("db.ListModelDependents")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var deps []dbmodel.ModelDependency
+	db := d.DB.WithContext(ctx).Preload("Model").Preload("DependsOn").Where("depends_on_id = ?", modelID).Order("model_id")
+	if err := db.Find(&deps).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return deps, nil
+}
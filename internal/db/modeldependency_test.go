@@ -0,0 +1,69 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelDependencies(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	database := env.model
+	app := env.model
+	app.ID = 0
+	app.Name = "app-model"
+	app.UUID = sql.NullString{String: "00000001-0000-0000-0000-0000-000000000002", Valid: true}
+	err := s.Database.AddModel(ctx, &app)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddModelDependency(ctx, &dbmodel.ModelDependency{ModelID: app.ID})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Database.AddModelDependency(ctx, &dbmodel.ModelDependency{
+		ModelID:     app.ID,
+		DependsOnID: database.ID,
+		CreatedBy:   "bob@canonical.com",
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddModelDependency(ctx, &dbmodel.ModelDependency{
+		ModelID:     app.ID,
+		DependsOnID: database.ID,
+		CreatedBy:   "alice@canonical.com",
+		Description: "consumes the postgresql offer",
+	})
+	c.Assert(err, qt.IsNil)
+
+	deps, err := s.Database.ListModelDependencies(ctx, app.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deps, qt.HasLen, 1)
+	c.Check(deps[0].CreatedBy, qt.Equals, "alice@canonical.com")
+	c.Check(deps[0].Description, qt.Equals, "consumes the postgresql offer")
+	c.Check(deps[0].Model.Name, qt.Equals, "app-model")
+	c.Check(deps[0].DependsOn.Name, qt.Equals, "test-model")
+
+	dependents, err := s.Database.ListModelDependents(ctx, database.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(dependents, qt.HasLen, 1)
+	c.Check(dependents[0].ID, qt.Equals, deps[0].ID)
+	dependents, err = s.Database.ListModelDependents(ctx, app.ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(dependents, qt.HasLen, 0)
+
+	err = s.Database.RemoveModelDependency(ctx, &dbmodel.ModelDependency{ModelID: database.ID, DependsOnID: app.ID})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Dependencies are removed along with their models.
+	err = s.Database.DeleteModel(ctx, &app)
+	c.Assert(err, qt.IsNil)
+	dependents, err = s.Database.ListModelDependents(ctx, database.ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(dependents, qt.HasLen, 0)
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetModelFreeze stores the given model freeze, replacing any freeze
+// already stored for the model.
+func (d *Database) SetModelFreeze(ctx context.Context, f *dbmodel.ModelFreeze) (err error) {
+	const op = errors.Op("db.SetModelFreeze")
+	if f.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"created_at", "frozen_by", "reason", "until"}),
+	})
+	if err := db.Create(f).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelFreeze completes the given model freeze, which is identified by
+// its ModelID. If the model is not frozen an error with the code
+// CodeNotFound is returned.
+func (d *Database) GetModelFreeze(ctx context.Context, f *dbmodel.ModelFreeze) (err error) {
+	const op = errors.Op("db.GetModelFreeze")
+	if f.ModelID == 0 {
+		return errors.E(op, errors.CodeNotFound, "model freeze not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Preload("Model").First(f, "model_id = ?", f.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteModelFreeze removes the freeze on the model with the ModelID of
+// the given freeze. Removing a freeze from a model that is not frozen is
+// not an error.
+func (d *Database) DeleteModelFreeze(ctx context.Context, f *dbmodel.ModelFreeze) (err error) {
+	const op = errors.Op("db.DeleteModelFreeze")
+	if f.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.ModelFreeze{}, "model_id = ?", f.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
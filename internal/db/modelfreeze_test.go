@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelFreeze(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.SetModelFreeze(ctx, &dbmodel.ModelFreeze{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	f := dbmodel.ModelFreeze{
+		ModelID: env.model.ID,
+	}
+	err = s.Database.GetModelFreeze(ctx, &f)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.SetModelFreeze(ctx, &dbmodel.ModelFreeze{
+		ModelID:  env.model.ID,
+		FrozenBy: "alice@canonical.com",
+		Reason:   "incident",
+	})
+	c.Assert(err, qt.IsNil)
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = s.Database.SetModelFreeze(ctx, &dbmodel.ModelFreeze{
+		ModelID:  env.model.ID,
+		FrozenBy: "bob@canonical.com",
+		Reason:   "change window",
+		Until:    sql.NullTime{Time: until, Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetModelFreeze(ctx, &f)
+	c.Assert(err, qt.IsNil)
+	c.Check(f.FrozenBy, qt.Equals, "bob@canonical.com")
+	c.Check(f.Reason, qt.Equals, "change window")
+	c.Check(f.Until.Time.Equal(until), qt.IsTrue)
+	c.Check(f.Model.UUID, qt.DeepEquals, env.model.UUID)
+
+	err = s.Database.DeleteModelFreeze(ctx, &f)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetModelFreeze(ctx, &f)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.DeleteModelFreeze(ctx, &f)
+	c.Assert(err, qt.IsNil)
+}
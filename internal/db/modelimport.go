@@ -0,0 +1,93 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// UpsertModelImport records the given model import, replacing any import
+// already recorded for the same model.
+func (d *Database) UpsertModelImport(ctx context.Context, mi *dbmodel.ModelImport) (err error) {
+	const op = errors.Op("db.UpsertModelImport")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Controller").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "model_uuid"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"updated_at",
+			"model_name",
+			"source_jimm",
+			"controller_id",
+			"owner_identity_name",
+			"user_access",
+			"group_access",
+			"status",
+			"prepared_by",
+			"completed_at",
+		}),
+	})
+	if err := db.Create(mi).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelImport fills in the given model import, which is found by its
+// ID if that is set, otherwise by its model UUID. If there is no such
+// import an error with a code of CodeNotFound is returned.
+func (d *Database) GetModelImport(ctx context.Context, mi *dbmodel.ModelImport) (err error) {
+	const op = errors.Op("db.GetModelImport")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("Controller")
+	if mi.ID != 0 {
+		db = db.Where("id = ?", mi.ID)
+	} else {
+		db = db.Where("model_uuid = ?", mi.ModelUUID)
+	}
+	if err := db.First(mi).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// UpdateModelImport updates the status and completion time of the given
+// model import.
+func (d *Database) UpdateModelImport(ctx context.Context, mi *dbmodel.ModelImport) (err error) {
+	const op = errors.Op("db.UpdateModelImport")
+	if mi.ID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model import ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(mi).Select("updated_at", "status", "completed_at")
+	if err := db.Updates(mi).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelImports(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	mi := dbmodel.ModelImport{ModelUUID: "00000002-0000-0000-0000-000000000009"}
+	err := s.Database.GetModelImport(ctx, &mi)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	mi = dbmodel.ModelImport{
+		ModelUUID:         "00000002-0000-0000-0000-000000000009",
+		ModelName:         "imported",
+		SourceJIMM:        "controller-00000000-0000-0000-0000-000000000001",
+		ControllerID:      env.controller.ID,
+		OwnerIdentityName: "bob@canonical.com",
+		UserAccess:        dbmodel.StringMap{"alice@canonical.com": "read"},
+		Status:            dbmodel.ModelImportPrepared,
+		PreparedBy:        "alice@canonical.com",
+	}
+	err = s.Database.UpsertModelImport(ctx, &mi)
+	c.Assert(err, qt.IsNil)
+	id := mi.ID
+
+	// Preparing the import again replaces it.
+	mi.ID = 0
+	mi.UserAccess = dbmodel.StringMap{"alice@canonical.com": "write"}
+	err = s.Database.UpsertModelImport(ctx, &mi)
+	c.Assert(err, qt.IsNil)
+
+	mi = dbmodel.ModelImport{ID: id}
+	err = s.Database.GetModelImport(ctx, &mi)
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ModelUUID, qt.Equals, "00000002-0000-0000-0000-000000000009")
+	c.Check(mi.Controller.Name, qt.Equals, env.controller.Name)
+	c.Check(mi.UserAccess, qt.DeepEquals, dbmodel.StringMap{"alice@canonical.com": "write"})
+
+	mi.Status = dbmodel.ModelImportCompleted
+	mi.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	err = s.Database.UpdateModelImport(ctx, &mi)
+	c.Assert(err, qt.IsNil)
+
+	mi = dbmodel.ModelImport{ModelUUID: "00000002-0000-0000-0000-000000000009"}
+	err = s.Database.GetModelImport(ctx, &mi)
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ID, qt.Equals, id)
+	c.Check(mi.Status, qt.Equals, dbmodel.ModelImportCompleted)
+	c.Check(mi.CompletedAt.Valid, qt.IsTrue)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddModelMigration records the given model migration. If a migration of
+// the model is already being monitored an error with the code
+// CodeAlreadyExists is returned.
+func (d *Database) AddModelMigration(ctx context.Context, m *dbmodel.ModelMigration) (err error) {
+	const op = errors.Op("db.AddModelMigration")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model", "SourceController", "TargetController")
+	if err := db.Create(m).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// UpdateModelMigration updates the status, phase, error and end time of
+// the given model migration.
+func (d *Database) UpdateModelMigration(ctx context.Context, m *dbmodel.ModelMigration) (err error) {
+	const op = errors.Op("db.UpdateModelMigration")
+	if m.ID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model migration ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(m).Select("updated_at", "status", "phase", "error", "ended_at")
+	if err := db.Updates(m).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListModelMigrations returns the recorded model migrations, most recent
+// first. If modelID is not zero only the migrations of that model are
+// returned, if activeOnly is true only the migrations that are still
+// being monitored are returned.
+func (d *Database) ListModelMigrations(ctx context.Context, modelID uint, activeOnly bool) (_ []dbmodel.ModelMigration, err error) {
+	const op = errors.Op("db.ListModelMigrations")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("Model").Preload("Model.CloudCredential").Preload("SourceController").Preload("TargetController")
+	if modelID != 0 {
+		db = db.Where("model_id = ?", modelID)
+	}
+	if activeOnly {
+		db = db.Where("ended_at IS NULL")
+	}
+	var migrations []dbmodel.ModelMigration
+	if err := db.Order("id DESC").Find(&migrations).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return migrations, nil
+}
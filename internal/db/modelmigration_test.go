@@ -0,0 +1,88 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelMigrations(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	target := dbmodel.Controller{
+		Name:        "target-controller",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000002",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region",
+	}
+	c.Assert(s.Database.DB.Create(&target).Error, qt.IsNil)
+
+	m := dbmodel.ModelMigration{
+		ModelID:            env.model.ID,
+		SourceControllerID: env.controller.ID,
+		TargetControllerID: target.ID,
+		MigrationID:        "migration-1",
+		InitiatedBy:        "alice@canonical.com",
+		Status:             dbmodel.MigrationRunning,
+	}
+	err := s.Database.AddModelMigration(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	// Only one migration of a model may be monitored at a time.
+	err = s.Database.AddModelMigration(ctx, &dbmodel.ModelMigration{
+		ModelID:            env.model.ID,
+		SourceControllerID: env.controller.ID,
+		TargetControllerID: target.ID,
+		MigrationID:        "migration-2",
+		InitiatedBy:        "alice@canonical.com",
+		Status:             dbmodel.MigrationRunning,
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	migrations, err := s.Database.ListModelMigrations(ctx, 0, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].MigrationID, qt.Equals, "migration-1")
+	c.Check(migrations[0].Model.UUID, qt.DeepEquals, env.model.UUID)
+	c.Check(migrations[0].SourceController.Name, qt.Equals, "test-controller")
+	c.Check(migrations[0].TargetController.Name, qt.Equals, "target-controller")
+
+	m.Status = dbmodel.MigrationAborted
+	m.Phase = "aborted, removing model from target controller"
+	m.Error = "migration aborted"
+	m.EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	err = s.Database.UpdateModelMigration(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	migrations, err = s.Database.ListModelMigrations(ctx, 0, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(migrations, qt.HasLen, 0)
+	migrations, err = s.Database.ListModelMigrations(ctx, env.model.ID, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].Status, qt.Equals, dbmodel.MigrationAborted)
+	c.Check(migrations[0].Phase, qt.Equals, "aborted, removing model from target controller")
+	c.Check(migrations[0].EndedAt.Valid, qt.IsTrue)
+
+	// Once the migration has ended the model may be migrated again.
+	err = s.Database.AddModelMigration(ctx, &dbmodel.ModelMigration{
+		ModelID:            env.model.ID,
+		SourceControllerID: env.controller.ID,
+		TargetControllerID: target.ID,
+		MigrationID:        "migration-2",
+		InitiatedBy:        "alice@canonical.com",
+		Status:             dbmodel.MigrationRunning,
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.UpdateModelMigration(ctx, &dbmodel.ModelMigration{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetModelNetworkPolicy stores the given model network policy, replacing
+// any policy already stored for the model.
+func (d *Database) SetModelNetworkPolicy(ctx context.Context, p *dbmodel.ModelNetworkPolicy) (err error) {
+	const op = errors.Op("db.SetModelNetworkPolicy")
+	if p.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "set_by", "allowed_egress", "enforced", "enforcement_error"}),
+	})
+	if err := db.Create(p).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelNetworkPolicy completes the given model network policy, which
+// is identified by its ModelID. If the model has no policy an error with
+// the code CodeNotFound is returned.
+func (d *Database) GetModelNetworkPolicy(ctx context.Context, p *dbmodel.ModelNetworkPolicy) (err error) {
+	const op = errors.Op("db.GetModelNetworkPolicy")
+	if p.ModelID == 0 {
+		return errors.E(op, errors.CodeNotFound, "model network policy not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Preload("Model").First(p, "model_id = ?", p.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListModelNetworkPolicies returns the model network policies, ordered by
+// model ID. If unenforcedOnly is true only the policies that are not
+// enforced are returned.
+func (d *Database) ListModelNetworkPolicies(ctx context.Context, unenforcedOnly bool) (_ []dbmodel.ModelNetworkPolicy, err error) {
+	const op = errors.Op("db.ListModelNetworkPolicies")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("Model").Order("model_id")
+	if unenforcedOnly {
+		db = db.Where("NOT enforced")
+	}
+	var policies []dbmodel.ModelNetworkPolicy
+	if err := db.Find(&policies).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return policies, nil
+}
+
+// DeleteModelNetworkPolicy removes the network policy of the model with
+// the ModelID of the given policy. Removing the policy of a model that
+// has none is not an error.
+func (d *Database) DeleteModelNetworkPolicy(ctx context.Context, p *dbmodel.ModelNetworkPolicy) (err error) {
+	const op = errors.Op("db.DeleteModelNetworkPolicy")
+	if p.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.ModelNetworkPolicy{}, "model_id = ?", p.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelNetworkPolicy(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.SetModelNetworkPolicy(ctx, &dbmodel.ModelNetworkPolicy{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	p := dbmodel.ModelNetworkPolicy{
+		ModelID: env.model.ID,
+	}
+	err = s.Database.GetModelNetworkPolicy(ctx, &p)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.SetModelNetworkPolicy(ctx, &dbmodel.ModelNetworkPolicy{
+		ModelID:       env.model.ID,
+		SetBy:         "alice@canonical.com",
+		AllowedEgress: dbmodel.Strings{"10.0.0.0/8"},
+		Enforced:      true,
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetModelNetworkPolicy(ctx, &dbmodel.ModelNetworkPolicy{
+		ModelID:          env.model.ID,
+		SetBy:            "bob@canonical.com",
+		AllowedEgress:    dbmodel.Strings{"10.0.0.0/8", "192.168.0.0/16"},
+		EnforcementError: "connection refused",
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetModelNetworkPolicy(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	c.Check(p.SetBy, qt.Equals, "bob@canonical.com")
+	c.Check(p.AllowedEgress, qt.DeepEquals, dbmodel.Strings{"10.0.0.0/8", "192.168.0.0/16"})
+	c.Check(p.Enforced, qt.IsFalse)
+	c.Check(p.EnforcementError, qt.Equals, "connection refused")
+	c.Check(p.Model.UUID, qt.DeepEquals, env.model.UUID)
+
+	policies, err := s.Database.ListModelNetworkPolicies(ctx, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(policies, qt.HasLen, 1)
+	c.Check(policies[0].ModelID, qt.Equals, env.model.ID)
+
+	p.Enforced = true
+	p.EnforcementError = ""
+	err = s.Database.SetModelNetworkPolicy(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	policies, err = s.Database.ListModelNetworkPolicies(ctx, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(policies, qt.HasLen, 0)
+	policies, err = s.Database.ListModelNetworkPolicies(ctx, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(policies, qt.HasLen, 1)
+
+	err = s.Database.DeleteModelNetworkPolicy(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetModelNetworkPolicy(ctx, &p)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.DeleteModelNetworkPolicy(ctx, &p)
+	c.Assert(err, qt.IsNil)
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// preloadModelPool preloads the associations of a model pool.
+func preloadModelPool(db *gorm.DB) *gorm.DB {
+	return db.Preload("CloudRegion").
+		Preload("CloudRegion.Cloud").
+		Preload("CloudCredential").
+		Preload("Models", func(db *gorm.DB) *gorm.DB {
+			return db.Order("pooled_models.id")
+		}).
+		Preload("Models.Controller")
+}
+
+// AddModelPool stores the given model pool. If a pool with the same name
+// already exists an error with the code CodeAlreadyExists is returned.
+func (d *Database) AddModelPool(ctx context.Context, p *dbmodel.ModelPool) (err error) {
+	const op = errors.Op("db.AddModelPool")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("CloudRegion", "CloudCredential", "Models").Create(p).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelPool completes the given model pool, which is identified by
+// its Name, including the models ready in the pool. If the pool does not
+// exist an error with the code CodeNotFound is returned.
+func (d *Database) GetModelPool(ctx context.Context, p *dbmodel.ModelPool) (err error) {
+	const op = errors.Op("db.GetModelPool")
+	if p.Name == "" {
+		return errors.E(op, errors.CodeNotFound, "model pool not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := preloadModelPool(d.DB.WithContext(ctx)).First(p, "name = ?", p.Name).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListModelPools returns the model pools, including the models ready in
+// each pool, ordered by name.
+func (d *Database) ListModelPools(ctx context.Context) (_ []dbmodel.ModelPool, err error) {
+	const op = errors.Op("db.ListModelPools")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var pools []dbmodel.ModelPool
+	if err := preloadModelPool(d.DB.WithContext(ctx)).Order("name").Find(&pools).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return pools, nil
+}
+
+// DeleteModelPool removes the given model pool, and the records of the
+// models ready in it.
+func (d *Database) DeleteModelPool(ctx context.Context, p *dbmodel.ModelPool) (err error) {
+	const op = errors.Op("db.DeleteModelPool")
+	if p.ID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model pool ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.ModelPool{}, p.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// AddPooledModel records the given model as ready in its pool.
+func (d *Database) AddPooledModel(ctx context.Context, m *dbmodel.PooledModel) (err error) {
+	const op = errors.Op("db.AddPooledModel")
+	if m.ModelPoolID == 0 || m.ControllerID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model pool or controller ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("Controller").Create(m).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ClaimPooledModel removes the oldest model ready in the given pool from
+// the pool and returns it. Models being claimed concurrently are skipped,
+// so that each model is claimed at most once. If the pool has no ready
+// models an error with the code CodeNotFound is returned.
+func (d *Database) ClaimPooledModel(ctx context.Context, p *dbmodel.ModelPool) (_ *dbmodel.PooledModel, err error) {
+	const op = errors.Op("db.ClaimPooledModel")
+	if p.ID == 0 {
+		return nil, errors.E(op, errors.CodeBadRequest, "missing model pool ID")
+	}
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var m dbmodel.PooledModel
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("model_pool_id = ?", p.ID).
+			Order("id").
+			First(&m).Error
+		if err != nil {
+			return dbError(err)
+		}
+		if err := tx.Delete(&dbmodel.PooledModel{}, m.ID).Error; err != nil {
+			return dbError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if err := d.DB.WithContext(ctx).First(&m.Controller, m.ControllerID).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return &m, nil
+}
+
+// DeletePooledModel removes the record of the given pooled model.
+func (d *Database) DeletePooledModel(ctx context.Context, m *dbmodel.PooledModel) (err error) {
+	const op = errors.Op("db.DeletePooledModel")
+	if m.ID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing pooled model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.PooledModel{}, m.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelPool(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	p := dbmodel.ModelPool{
+		Name:              "ci",
+		CloudRegionID:     env.cloud.Regions[0].ID,
+		CloudCredentialID: env.cred.ID,
+		Config:            dbmodel.Map{"default-series": "jammy"},
+		Size:              2,
+	}
+	err := s.Database.AddModelPool(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddModelPool(ctx, &dbmodel.ModelPool{
+		Name:              "ci",
+		CloudRegionID:     env.cloud.Regions[0].ID,
+		CloudCredentialID: env.cred.ID,
+		Size:              1,
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	err = s.Database.GetModelPool(ctx, &dbmodel.ModelPool{Name: "no-such-pool"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	_, err = s.Database.ClaimPooledModel(ctx, &p)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.AddPooledModel(ctx, &dbmodel.PooledModel{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	for _, uuid := range []string{"00000003-0000-0000-0000-000000000001", "00000003-0000-0000-0000-000000000002"} {
+		err = s.Database.AddPooledModel(ctx, &dbmodel.PooledModel{
+			ModelPoolID:  p.ID,
+			ControllerID: env.controller.ID,
+			Name:         "ci-" + uuid[:8],
+			UUID:         uuid,
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	p2 := dbmodel.ModelPool{
+		Name: "ci",
+	}
+	err = s.Database.GetModelPool(ctx, &p2)
+	c.Assert(err, qt.IsNil)
+	c.Check(p2.CloudRegion.Name, qt.Equals, env.cloud.Regions[0].Name)
+	c.Check(p2.CloudRegion.Cloud.Name, qt.Equals, env.cloud.Name)
+	c.Check(p2.CloudCredential.Name, qt.Equals, env.cred.Name)
+	c.Check(p2.Config, qt.DeepEquals, dbmodel.Map{"default-series": "jammy"})
+	c.Assert(p2.Models, qt.HasLen, 2)
+	c.Check(p2.Models[0].Controller.Name, qt.Equals, env.controller.Name)
+
+	// Models are claimed oldest first, and only once.
+	m, err := s.Database.ClaimPooledModel(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.UUID, qt.Equals, "00000003-0000-0000-0000-000000000001")
+	c.Check(m.Controller.Name, qt.Equals, env.controller.Name)
+
+	pools, err := s.Database.ListModelPools(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pools, qt.HasLen, 1)
+	c.Assert(pools[0].Models, qt.HasLen, 1)
+	c.Check(pools[0].Models[0].UUID, qt.Equals, "00000003-0000-0000-0000-000000000002")
+
+	err = s.Database.DeletePooledModel(ctx, &pools[0].Models[0])
+	c.Assert(err, qt.IsNil)
+	_, err = s.Database.ClaimPooledModel(ctx, &p)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.DeleteModelPool(ctx, &p)
+	c.Assert(err, qt.IsNil)
+	pools, err = s.Database.ListModelPools(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(pools, qt.HasLen, 0)
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// RecordModelResourceSnapshots records an hourly snapshot of the
+// machine, core and unit counts of every model for the hour containing
+// t. Recording a snapshot more than once in an hour replaces the
+// earlier snapshot for that hour.
+func (d *Database) RecordModelResourceSnapshots(ctx context.Context, t time.Time) (err error) {
+	const op = errors.Op("db.RecordModelResourceSnapshots")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	err = d.DB.WithContext(ctx).Exec(`
+		INSERT INTO model_resource_snapshots (model_id, time, resolution, machines, cores, units)
+		SELECT id, ?, ?, machines, cores, units FROM models
+		ON CONFLICT (model_id, resolution, time) DO UPDATE SET
+			machines = excluded.machines,
+			cores = excluded.cores,
+			units = excluded.units`,
+		t.UTC().Truncate(time.Hour), dbmodel.SnapshotResolutionHour,
+	).Error
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DownsampleModelResourceSnapshots replaces the hourly model resource
+// snapshots taken before the given time with daily snapshots holding
+// the maximum of the hourly values recorded on each day (in UTC). The
+// number of hourly snapshots removed is returned. The before time
+// should be the start of a day so that no day is left with both hourly
+// and daily snapshots.
+func (d *Database) DownsampleModelResourceSnapshots(ctx context.Context, before time.Time) (_ int64, err error) {
+	const op = errors.Op("db.DownsampleModelResourceSnapshots")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var n int64
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO model_resource_snapshots (model_id, time, resolution, machines, cores, units)
+			SELECT model_id, date_trunc('day', time, 'UTC'), ?, MAX(machines), MAX(cores), MAX(units)
+			FROM model_resource_snapshots
+			WHERE resolution = ? AND time < ?
+			GROUP BY model_id, date_trunc('day', time, 'UTC')
+			ON CONFLICT (model_id, resolution, time) DO UPDATE SET
+				machines = GREATEST(model_resource_snapshots.machines, excluded.machines),
+				cores = GREATEST(model_resource_snapshots.cores, excluded.cores),
+				units = GREATEST(model_resource_snapshots.units, excluded.units)`,
+			dbmodel.SnapshotResolutionDay, dbmodel.SnapshotResolutionHour, before,
+		).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Where("resolution = ? AND time < ?", dbmodel.SnapshotResolutionHour, before).Delete(&dbmodel.ModelResourceSnapshot{})
+		if result.Error != nil {
+			return result.Error
+		}
+		n = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, errors.E(op, dbError(err))
+	}
+	return n, nil
+}
+
+// GetModelResourceSnapshots returns the resource snapshots, at all
+// resolutions, of the model with the given ID that were taken in the
+// given time range, ordered by time. If start or end are zero the range
+// is not bounded at that end.
+func (d *Database) GetModelResourceSnapshots(ctx context.Context, modelID uint, start, end time.Time) (_ []dbmodel.ModelResourceSnapshot, err error) {
+	const op = errors.Op("db.GetModelResourceSnapshots")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("model_id = ?", modelID)
+	if !start.IsZero() {
+		db = db.Where("time >= ?", start)
+	}
+	if !end.IsZero() {
+		db = db.Where("time <= ?", end)
+	}
+	var snapshots []dbmodel.ModelResourceSnapshot
+	if err := db.Order("time").Order("id").Find(&snapshots).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelResourceSnapshots(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	record := func(t time.Time, machines, cores, units int64) {
+		env.model.Machines = machines
+		env.model.Cores = cores
+		env.model.Units = units
+		c.Assert(s.Database.DB.Save(&env.model).Error, qt.IsNil)
+		err := s.Database.RecordModelResourceSnapshots(ctx, t)
+		c.Assert(err, qt.IsNil)
+	}
+	record(day.Add(time.Hour), 1, 2, 3)
+	// A second snapshot in the same hour replaces the first.
+	record(day.Add(time.Hour+30*time.Minute), 2, 4, 3)
+	record(day.Add(2*time.Hour), 1, 8, 1)
+	record(day.Add(25*time.Hour), 3, 6, 9)
+
+	snapshots, err := s.Database.GetModelResourceSnapshots(ctx, env.model.ID, time.Time{}, time.Time{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(snapshots, qt.HasLen, 3)
+	c.Check(snapshots[0].Time.Equal(day.Add(time.Hour)), qt.IsTrue)
+	c.Check(snapshots[0].Resolution, qt.Equals, dbmodel.SnapshotResolutionHour)
+	c.Check(snapshots[0].Machines, qt.Equals, int64(2))
+	c.Check(snapshots[0].Cores, qt.Equals, int64(4))
+	c.Check(snapshots[0].Units, qt.Equals, int64(3))
+
+	snapshots, err = s.Database.GetModelResourceSnapshots(ctx, env.model.ID, day.Add(2*time.Hour), day.Add(24*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Assert(snapshots, qt.HasLen, 1)
+	c.Check(snapshots[0].Time.Equal(day.Add(2*time.Hour)), qt.IsTrue)
+
+	n, err := s.Database.DownsampleModelResourceSnapshots(ctx, day.Add(24*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+
+	snapshots, err = s.Database.GetModelResourceSnapshots(ctx, env.model.ID, time.Time{}, time.Time{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(snapshots, qt.HasLen, 2)
+	c.Check(snapshots[0].Time.Equal(day), qt.IsTrue)
+	c.Check(snapshots[0].Resolution, qt.Equals, dbmodel.SnapshotResolutionDay)
+	c.Check(snapshots[0].Machines, qt.Equals, int64(2))
+	c.Check(snapshots[0].Cores, qt.Equals, int64(8))
+	c.Check(snapshots[0].Units, qt.Equals, int64(3))
+	c.Check(snapshots[1].Resolution, qt.Equals, dbmodel.SnapshotResolutionHour)
+
+	// Downsampling again does not change the daily snapshot.
+	n, err = s.Database.DownsampleModelResourceSnapshots(ctx, day.Add(24*time.Hour))
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(0))
+	snapshots, err = s.Database.GetModelResourceSnapshots(ctx, env.model.ID, time.Time{}, time.Time{})
+	c.Assert(err, qt.IsNil)
+	c.Check(snapshots, qt.HasLen, 2)
+	c.Check(snapshots[0].Cores, qt.Equals, int64(8))
+
+	// Snapshots are removed with their model.
+	c.Assert(s.Database.DeleteModel(ctx, &env.model), qt.IsNil)
+	snapshots, err = s.Database.GetModelResourceSnapshots(ctx, env.model.ID, time.Time{}, time.Time{})
+	c.Assert(err, qt.IsNil)
+	c.Check(snapshots, qt.HasLen, 0)
+}
+
+func TestModelResourceSnapshotsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var d db.Database
+	err := d.RecordModelResourceSnapshots(ctx, time.Now())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+	_, err = d.DownsampleModelResourceSnapshots(ctx, time.Now())
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+	_, err = d.GetModelResourceSnapshots(ctx, 1, time.Time{}, time.Time{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
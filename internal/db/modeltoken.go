@@ -0,0 +1,90 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddModelToken stores the given model token.
+func (d *Database) AddModelToken(ctx context.Context, t *dbmodel.ModelToken) (err error) {
+	const op = errors.Op("db.AddModelToken")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("Model").Create(t).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelToken completes the given model token, which is identified by
+// its ID. If the token cannot be found an error with the code
+// CodeNotFound is returned.
+func (d *Database) GetModelToken(ctx context.Context, t *dbmodel.ModelToken) (err error) {
+	const op = errors.Op("db.GetModelToken")
+	if t.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Preload("Model").First(t, t.ID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListModelTokens returns the tokens granting access to the model with
+// the given ID, ordered by ID.
+func (d *Database) ListModelTokens(ctx context.Context, modelID uint) (_ []dbmodel.ModelToken, err error) {
+	const op = errors.Op("db.ListModelTokens")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var tokens []dbmodel.ModelToken
+	db := d.DB.WithContext(ctx).Preload("Model")
+	if err := db.Where("model_id = ?", modelID).Order("id").Find(&tokens).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return tokens, nil
+}
+
+// DeleteModelToken removes the given model token.
+func (d *Database) DeleteModelToken(ctx context.Context, t *dbmodel.ModelToken) (err error) {
+	const op = errors.Op("db.DeleteModelToken")
+	if t.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(t).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
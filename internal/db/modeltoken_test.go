@@ -0,0 +1,66 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelTokens(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	t1 := dbmodel.ModelToken{
+		ModelID:     env.model.ID,
+		Access:      "write",
+		Description: "staging deployments",
+		CreatedBy:   "bob@canonical.com",
+		SecretHash:  []byte("hash-1"),
+	}
+	err := s.Database.AddModelToken(ctx, &t1)
+	c.Assert(err, qt.IsNil)
+	t2 := dbmodel.ModelToken{
+		ModelID:    env.model.ID,
+		Access:     "read",
+		CreatedBy:  "bob@canonical.com",
+		SecretHash: []byte("hash-2"),
+	}
+	err = s.Database.AddModelToken(ctx, &t2)
+	c.Assert(err, qt.IsNil)
+
+	t := dbmodel.ModelToken{ID: t1.ID}
+	err = s.Database.GetModelToken(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	c.Check(t.Model.UUID, qt.Equals, env.model.UUID)
+	c.Check(t.Access, qt.Equals, "write")
+	c.Check(t.Description, qt.Equals, "staging deployments")
+	c.Check(t.SecretHash, qt.DeepEquals, []byte("hash-1"))
+
+	tokens, err := s.Database.ListModelTokens(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tokens, qt.HasLen, 2)
+	c.Check(tokens[0].ID, qt.Equals, t1.ID)
+	c.Check(tokens[1].ID, qt.Equals, t2.ID)
+
+	err = s.Database.DeleteModelToken(ctx, &t)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetModelToken(ctx, &dbmodel.ModelToken{ID: t1.ID})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Tokens are removed along with their model.
+	err = s.Database.DeleteModel(ctx, &env.model)
+	c.Assert(err, qt.IsNil)
+	tokens, err = s.Database.ListModelTokens(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(tokens, qt.HasLen, 0)
+
+	err = s.Database.GetModelToken(ctx, &dbmodel.ModelToken{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.DeleteModelToken(ctx, &dbmodel.ModelToken{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
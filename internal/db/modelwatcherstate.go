@@ -0,0 +1,66 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetModelWatcherState fills in the given watcher state. The ModelID must
+// be set. If no state has been stored for the model an error with a code
+// of CodeNotFound is returned.
+func (d *Database) GetModelWatcherState(ctx context.Context, state *dbmodel.ModelWatcherState) (err error) {
+	const op = errors.Op("db.GetModelWatcherState")
+
+	if state.ModelID == 0 {
+		return errors.E(op, errors.CodeNotFound, "model watcher state not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).First(state, "model_id = ?", state.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	// States stored in an older format are upgraded as they are read,
+	// the stored state is upgraded when it is next written.
+	state.Upgrade()
+	return nil
+}
+
+// UpsertModelWatcherState stores the given watcher state, replacing any
+// state already stored for the model.
+func (d *Database) UpsertModelWatcherState(ctx context.Context, state *dbmodel.ModelWatcherState) (err error) {
+	const op = errors.Op("db.UpsertModelWatcherState")
+
+	if state.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	state.SchemaVersion = dbmodel.ModelWatcherStateSchemaVersion
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "machines", "units", "offers", "relations", "agent_versions", "schema_version"}),
+	})
+	if err := db.Create(state).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestModelWatcherState(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	st := dbmodel.ModelWatcherState{
+		ModelID: env.model.ID,
+	}
+	err = s.Database.GetModelWatcherState(ctx, &st)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+		ModelID:  env.model.ID,
+		Machines: dbmodel.Int64Map{"0": 2},
+		Units:    dbmodel.StringMap{"app/0": "active"},
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+		ModelID:   env.model.ID,
+		Machines:  dbmodel.Int64Map{"0": 2, "1": 4},
+		Units:     dbmodel.StringMap{"app/0": "active", "app/1": "blocked"},
+		Offers:    dbmodel.Strings{"offer-1"},
+		Relations: dbmodel.Strings{"app:db db:db"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetModelWatcherState(ctx, &st)
+	c.Assert(err, qt.IsNil)
+	c.Check(st.Machines, qt.DeepEquals, dbmodel.Int64Map{"0": 2, "1": 4})
+	c.Check(st.Units, qt.DeepEquals, dbmodel.StringMap{"app/0": "active", "app/1": "blocked"})
+	c.Check(st.Offers, qt.DeepEquals, dbmodel.Strings{"offer-1"})
+	c.Check(st.Relations, qt.DeepEquals, dbmodel.Strings{"app:db db:db"})
+
+	// The state is removed along with the model.
+	err = s.Database.DeleteModel(ctx, &env.model)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetModelWatcherState(ctx, &st)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddNamespaceReservation stores the given namespace reservation. If a
+// reservation with the same prefix already exists an error with the code
+// CodeAlreadyExists is returned.
+func (d *Database) AddNamespaceReservation(ctx context.Context, r *dbmodel.NamespaceReservation) (err error) {
+	const op = errors.Op("db.AddNamespaceReservation")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("Group").Create(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetNamespaceReservation completes the given namespace reservation,
+// which is identified by its prefix. If the reservation cannot be found
+// an error with the code CodeNotFound is returned.
+func (d *Database) GetNamespaceReservation(ctx context.Context, r *dbmodel.NamespaceReservation) (err error) {
+	const op = errors.Op("db.GetNamespaceReservation")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("Group").Where("prefix = ?", r.Prefix)
+	if err := db.First(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListNamespaceReservations returns all namespace reservations ordered by
+// prefix.
+func (d *Database) ListNamespaceReservations(ctx context.Context) (_ []dbmodel.NamespaceReservation, err error) {
+	const op = errors.Op("db.ListNamespaceReservations")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var reservations []dbmodel.NamespaceReservation
+	if err := d.DB.WithContext(ctx).Preload("Group").Order("prefix").Find(&reservations).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return reservations, nil
+}
+
+// UpdateNamespaceReservation updates the group owning the given namespace
+// reservation.
+func (d *Database) UpdateNamespaceReservation(ctx context.Context, r *dbmodel.NamespaceReservation) (err error) {
+	const op = errors.Op("db.UpdateNamespaceReservation")
+	if r.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("Group").Save(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteNamespaceReservation removes the given namespace reservation.
+func (d *Database) DeleteNamespaceReservation(ctx context.Context, r *dbmodel.NamespaceReservation) (err error) {
+	const op = errors.Op("db.DeleteNamespaceReservation")
+	if r.ID == 0 {
+		return errors.E(op, errors.CodeNotFound)
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(r).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestNamespaceReservations(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.AddNamespaceReservation(ctx, &dbmodel.NamespaceReservation{Prefix: "payments-"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	payments, err := s.Database.AddGroup(ctx, "payments")
+	c.Assert(err, qt.IsNil)
+	billing, err := s.Database.AddGroup(ctx, "billing")
+	c.Assert(err, qt.IsNil)
+
+	r1 := dbmodel.NamespaceReservation{
+		Prefix:  "payments-",
+		GroupID: payments.ID,
+	}
+	err = s.Database.AddNamespaceReservation(ctx, &r1)
+	c.Assert(err, qt.IsNil)
+	r2 := dbmodel.NamespaceReservation{
+		Prefix:  "billing-",
+		GroupID: billing.ID,
+	}
+	err = s.Database.AddNamespaceReservation(ctx, &r2)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddNamespaceReservation(ctx, &dbmodel.NamespaceReservation{
+		Prefix:  "payments-",
+		GroupID: billing.ID,
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	r := dbmodel.NamespaceReservation{Prefix: "payments-"}
+	err = s.Database.GetNamespaceReservation(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	c.Check(r.ID, qt.Equals, r1.ID)
+	c.Check(r.Group.Name, qt.Equals, "payments")
+
+	err = s.Database.GetNamespaceReservation(ctx, &dbmodel.NamespaceReservation{Prefix: "unknown-"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	reservations, err := s.Database.ListNamespaceReservations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reservations, qt.HasLen, 2)
+	c.Check(reservations[0].Prefix, qt.Equals, "billing-")
+	c.Check(reservations[0].Group.Name, qt.Equals, "billing")
+	c.Check(reservations[1].Prefix, qt.Equals, "payments-")
+	c.Check(reservations[1].Group.Name, qt.Equals, "payments")
+
+	r.GroupID = billing.ID
+	err = s.Database.UpdateNamespaceReservation(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	r = dbmodel.NamespaceReservation{Prefix: "payments-"}
+	err = s.Database.GetNamespaceReservation(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	c.Check(r.Group.Name, qt.Equals, "billing")
+
+	err = s.Database.DeleteNamespaceReservation(ctx, &r)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetNamespaceReservation(ctx, &dbmodel.NamespaceReservation{Prefix: "payments-"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.DeleteNamespaceReservation(ctx, &dbmodel.NamespaceReservation{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// Notify sends a notification with the given payload on the given
+// channel to every database session listening on it, including those
+// of other JIMM replicas. The notification is delivered when the
+// current transaction, if any, commits.
+func (d *Database) Notify(ctx context.Context, channel, payload string) (err error) {
+	const op = errors.Op("db.Notify")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", channel, payload).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// A Listener receives the notifications sent on a database channel. A
+// Listener holds a dedicated database connection which must be released
+// by calling Close.
+type Listener struct {
+	conn *sql.Conn
+}
+
+// Listen starts listening for notifications on the given channel. Only
+// notifications sent after Listen returns are received.
+func (d *Database) Listen(ctx context.Context, channel string) (_ *Listener, err error) {
+	const op = errors.Op("db.Listen")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	if _, err := conn.ExecContext(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Close()
+		return nil, errors.E(op, dbError(err))
+	}
+	return &Listener{conn: conn}, nil
+}
+
+// Next waits for the next notification on the listener's channel and
+// returns its payload. Next returns an error if the context is canceled
+// or the connection to the database is lost, in which case notifications
+// may have been missed and the Listener should be closed.
+func (l *Listener) Next(ctx context.Context) (string, error) {
+	const op = errors.Op("db.Listener.Next")
+
+	var payload string
+	err := l.conn.Raw(func(driverConn interface{}) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.E(errors.CodeServerConfiguration, "database does not support notifications")
+		}
+		n, err := c.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		payload = n.Payload
+		return nil
+	})
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	return payload, nil
+}
+
+// Close stops listening and returns the listener's connection to the
+// database pool.
+func (l *Listener) Close() error {
+	// Closing the connection while listening would return it to the
+	// pool still subscribed, so unsubscribe first. If that fails the
+	// connection is discarded.
+	if _, err := l.conn.ExecContext(context.Background(), "UNLISTEN *"); err != nil {
+		_ = l.conn.Raw(func(interface{}) error {
+			return driver.ErrBadConn
+		})
+	}
+	return l.conn.Close()
+}
@@ -0,0 +1,51 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestNotifyListen(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.Notify(ctx, "test-channel", "hello")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+	_, err = s.Database.Listen(ctx, "test-channel")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	l, err := s.Database.Listen(ctx, "test-channel")
+	c.Assert(err, qt.IsNil)
+	defer l.Close()
+
+	// Notifications on other channels are not received.
+	err = s.Database.Notify(ctx, "other-channel", "ignored")
+	c.Assert(err, qt.IsNil)
+	err = s.Database.Notify(ctx, "test-channel", "hello")
+	c.Assert(err, qt.IsNil)
+	err = s.Database.Notify(ctx, "test-channel", "world")
+	c.Assert(err, qt.IsNil)
+
+	nctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	payload, err := l.Next(nctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(payload, qt.Equals, "hello")
+	payload, err = l.Next(nctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(payload, qt.Equals, "world")
+
+	// Next returns when the context is canceled.
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = l.Next(cctx)
+	c.Check(err, qt.ErrorMatches, `.*context canceled`)
+}
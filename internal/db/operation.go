@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddOperation stores the given operation.
+func (d *Database) AddOperation(ctx context.Context, o *dbmodel.Operation) (err error) {
+	const op = errors.Op("db.AddOperation")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Omit("Steps").Create(o).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetOperation fills in the given operation, which is found by its UUID,
+// along with its steps oldest first. If there is no such operation an
+// error with a code of CodeNotFound is returned.
+func (d *Database) GetOperation(ctx context.Context, o *dbmodel.Operation) (err error) {
+	const op = errors.Op("db.GetOperation")
+	if o.UUID == "" {
+		return errors.E(op, errors.CodeNotFound, "operation not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	})
+	if err := db.Where("uuid = ?", o.UUID).First(o).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, errors.CodeNotFound, "operation not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UpdateOperation updates the status, progress, error, result and end
+// time of the given operation.
+func (d *Database) UpdateOperation(ctx context.Context, o *dbmodel.Operation) (err error) {
+	const op = errors.Op("db.UpdateOperation")
+	if o.ID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing operation ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(o).Select("updated_at", "status", "progress", "error", "result", "ended_at")
+	if err := db.Updates(o).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// AddOperationStep appends the given step to the step log of its
+// operation.
+func (d *Database) AddOperationStep(ctx context.Context, s *dbmodel.OperationStep) (err error) {
+	const op = errors.Op("db.AddOperationStep")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(s).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListOperations returns the operations started by the named identity,
+// or by every identity if the name is empty, most recently started
+// first. The steps of the operations are not loaded.
+func (d *Database) ListOperations(ctx context.Context, identityName string) (_ []dbmodel.Operation, err error) {
+	const op = errors.Op("db.ListOperations")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if identityName != "" {
+		db = db.Where("identity_name = ?", identityName)
+	}
+	var ops []dbmodel.Operation
+	if err := db.Order("created_at DESC, id DESC").Find(&ops).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return ops, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestOperations(c *qt.C) {
+	ctx := context.Background()
+
+	o := dbmodel.Operation{UUID: "00000009-0000-0000-0000-000000000001"}
+	err := s.Database.GetOperation(ctx, &o)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetOperation(ctx, &o)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	o = dbmodel.Operation{
+		UUID:         "00000009-0000-0000-0000-000000000001",
+		Kind:         "test",
+		IdentityName: "alice@canonical.com",
+		Status:       dbmodel.OperationRunning,
+	}
+	err = s.Database.AddOperation(ctx, &o)
+	c.Assert(err, qt.IsNil)
+	o2 := dbmodel.Operation{
+		UUID:         "00000009-0000-0000-0000-000000000002",
+		Kind:         "test",
+		IdentityName: "bob@canonical.com",
+		Status:       dbmodel.OperationRunning,
+	}
+	err = s.Database.AddOperation(ctx, &o2)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddOperationStep(ctx, &dbmodel.OperationStep{OperationID: o.ID, Message: "step 1"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.AddOperationStep(ctx, &dbmodel.OperationStep{OperationID: o.ID, Message: "step 2"})
+	c.Assert(err, qt.IsNil)
+
+	o.Status = dbmodel.OperationSucceeded
+	o.Progress = 100
+	o.Result = dbmodel.JSON(`{"a":1}`)
+	o.EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	err = s.Database.UpdateOperation(ctx, &o)
+	c.Assert(err, qt.IsNil)
+
+	got := dbmodel.Operation{UUID: o.UUID}
+	err = s.Database.GetOperation(ctx, &got)
+	c.Assert(err, qt.IsNil)
+	c.Check(got.Status, qt.Equals, dbmodel.OperationSucceeded)
+	c.Check(got.Progress, qt.Equals, 100)
+	c.Check(got.EndedAt.Valid, qt.IsTrue)
+	c.Assert(got.Steps, qt.HasLen, 2)
+	c.Check(got.Steps[0].Message, qt.Equals, "step 1")
+	c.Check(got.Steps[1].Message, qt.Equals, "step 2")
+	c.Check(got.ToAPIOperation().Result, qt.DeepEquals, map[string]interface{}{"a": float64(1)})
+
+	ops, err := s.Database.ListOperations(ctx, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(ops, qt.HasLen, 1)
+	c.Check(ops[0].UUID, qt.Equals, o.UUID)
+
+	ops, err = s.Database.ListOperations(ctx, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(ops, qt.HasLen, 2)
+
+	// Only finished operations are pruned, along with their steps.
+	n, err := s.Database.PruneDataset(ctx, db.DatasetOperations, time.Now().Add(time.Minute), 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+	got = dbmodel.Operation{UUID: o.UUID}
+	err = s.Database.GetOperation(ctx, &got)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	got = dbmodel.Operation{UUID: o2.UUID}
+	err = s.Database.GetOperation(ctx, &got)
+	c.Check(err, qt.IsNil)
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddPendingModelDestroy stores the given pending model destroy. If a
+// destroy is already pending for the model the existing destroy is kept,
+// so that repeating a destroy request does not extend its window.
+func (d *Database) AddPendingModelDestroy(ctx context.Context, pd *dbmodel.PendingModelDestroy) (err error) {
+	const op = errors.Op("db.AddPendingModelDestroy")
+	if pd.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}},
+		DoNothing: true,
+	})
+	if err := db.Create(pd).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetPendingModelDestroy completes the given pending model destroy, which
+// is identified by its ModelID. If no destroy is pending for the model an
+// error with the code CodeNotFound is returned.
+func (d *Database) GetPendingModelDestroy(ctx context.Context, pd *dbmodel.PendingModelDestroy) (err error) {
+	const op = errors.Op("db.GetPendingModelDestroy")
+	if pd.ModelID == 0 {
+		return errors.E(op, errors.CodeNotFound, "pending model destroy not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Preload("Model").First(pd, "model_id = ?", pd.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListPendingModelDestroys returns the pending model destroys due at or
+// before the given time, the earliest first. If due is zero all pending
+// destroys are returned.
+func (d *Database) ListPendingModelDestroys(ctx context.Context, due time.Time) (_ []dbmodel.PendingModelDestroy, err error) {
+	const op = errors.Op("db.ListPendingModelDestroys")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Preload("Model").Preload("Model.Controller")
+	if !due.IsZero() {
+		db = db.Where("destroy_at <= ?", due)
+	}
+	var destroys []dbmodel.PendingModelDestroy
+	if err := db.Order("destroy_at, model_id").Find(&destroys).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return destroys, nil
+}
+
+// DeletePendingModelDestroy removes the pending destroy of the model with
+// the ModelID of the given pending destroy. Removing a destroy that is not
+// pending is not an error.
+func (d *Database) DeletePendingModelDestroy(ctx context.Context, pd *dbmodel.PendingModelDestroy) (err error) {
+	const op = errors.Op("db.DeletePendingModelDestroy")
+	if pd.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.PendingModelDestroy{}, "model_id = ?", pd.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestPendingModelDestroy(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.AddPendingModelDestroy(ctx, &dbmodel.PendingModelDestroy{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	pd := dbmodel.PendingModelDestroy{
+		ModelID: env.model.ID,
+	}
+	err = s.Database.GetPendingModelDestroy(ctx, &pd)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	destroyAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = s.Database.AddPendingModelDestroy(ctx, &dbmodel.PendingModelDestroy{
+		ModelID:        env.model.ID,
+		RequestedBy:    "alice@canonical.com",
+		DestroyAt:      destroyAt,
+		DestroyStorage: sql.NullBool{Bool: true, Valid: true},
+		MaxWait:        sql.NullInt64{Int64: int64(time.Minute), Valid: true},
+	})
+	c.Assert(err, qt.IsNil)
+
+	// A repeated request does not replace the pending destroy.
+	err = s.Database.AddPendingModelDestroy(ctx, &dbmodel.PendingModelDestroy{
+		ModelID:     env.model.ID,
+		RequestedBy: "bob@canonical.com",
+		DestroyAt:   destroyAt.Add(time.Hour),
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.GetPendingModelDestroy(ctx, &pd)
+	c.Assert(err, qt.IsNil)
+	c.Check(pd.RequestedBy, qt.Equals, "alice@canonical.com")
+	c.Check(pd.DestroyAt.Equal(destroyAt), qt.IsTrue)
+	c.Check(pd.DestroyStorage, qt.DeepEquals, sql.NullBool{Bool: true, Valid: true})
+	c.Check(pd.Force.Valid, qt.IsFalse)
+	c.Check(pd.MaxWait, qt.DeepEquals, sql.NullInt64{Int64: int64(time.Minute), Valid: true})
+	c.Check(pd.Model.UUID, qt.DeepEquals, env.model.UUID)
+
+	destroys, err := s.Database.ListPendingModelDestroys(ctx, destroyAt.Add(-time.Second))
+	c.Assert(err, qt.IsNil)
+	c.Check(destroys, qt.HasLen, 0)
+	destroys, err = s.Database.ListPendingModelDestroys(ctx, destroyAt)
+	c.Assert(err, qt.IsNil)
+	c.Assert(destroys, qt.HasLen, 1)
+	c.Check(destroys[0].Model.Controller.Name, qt.Equals, env.controller.Name)
+	destroys, err = s.Database.ListPendingModelDestroys(ctx, time.Time{})
+	c.Assert(err, qt.IsNil)
+	c.Check(destroys, qt.HasLen, 1)
+
+	err = s.Database.DeletePendingModelDestroy(ctx, &pd)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetPendingModelDestroy(ctx, &pd)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = s.Database.DeletePendingModelDestroy(ctx, &pd)
+	c.Assert(err, qt.IsNil)
+}
@@ -0,0 +1,153 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetPublicModelListing stores the given public model listing, replacing
+// any listing already stored for the model.
+func (d *Database) SetPublicModelListing(ctx context.Context, l *dbmodel.PublicModelListing) (err error) {
+	const op = errors.Op("db.SetPublicModelListing")
+	if l.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("Model").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "purpose", "owner_group"}),
+	})
+	if err := db.Create(l).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeletePublicModelListing removes the model with the ModelID of the
+// given listing from the public catalog. Removing a model that is not
+// listed is not an error.
+func (d *Database) DeletePublicModelListing(ctx context.Context, l *dbmodel.PublicModelListing) (err error) {
+	const op = errors.Op("db.DeletePublicModelListing")
+	if l.ModelID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing model ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.PublicModelListing{}, "model_id = ?", l.ModelID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListPublicModelListings returns every public model listing, with the
+// listed model, ordered by model name.
+func (d *Database) ListPublicModelListings(ctx context.Context) (_ []dbmodel.PublicModelListing, err error) {
+	const op = errors.Op("db.ListPublicModelListings")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var listings []dbmodel.PublicModelListing
+	db := d.DB.WithContext(ctx).Preload("Model").
+		Joins("JOIN models ON models.id = public_model_listings.model_id").
+		Order("models.name, models.id")
+	if err := db.Find(&listings).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return listings, nil
+}
+
+// SetPublicOfferListing stores the given public offer listing, replacing
+// any listing already stored for the application offer.
+func (d *Database) SetPublicOfferListing(ctx context.Context, l *dbmodel.PublicOfferListing) (err error) {
+	const op = errors.Op("db.SetPublicOfferListing")
+	if l.ApplicationOfferID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing application offer ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Omit("ApplicationOffer").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "application_offer_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "purpose", "owner_group"}),
+	})
+	if err := db.Create(l).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeletePublicOfferListing removes the application offer with the
+// ApplicationOfferID of the given listing from the public catalog.
+// Removing an offer that is not listed is not an error.
+func (d *Database) DeletePublicOfferListing(ctx context.Context, l *dbmodel.PublicOfferListing) (err error) {
+	const op = errors.Op("db.DeletePublicOfferListing")
+	if l.ApplicationOfferID == 0 {
+		return errors.E(op, errors.CodeBadRequest, "missing application offer ID")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Delete(&dbmodel.PublicOfferListing{}, "application_offer_id = ?", l.ApplicationOfferID).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListPublicOfferListings returns every public offer listing, with the
+// listed application offer and its endpoints, ordered by offer name.
+func (d *Database) ListPublicOfferListings(ctx context.Context) (_ []dbmodel.PublicOfferListing, err error) {
+	const op = errors.Op("db.ListPublicOfferListings")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var listings []dbmodel.PublicOfferListing
+	db := d.DB.WithContext(ctx).
+		Preload("ApplicationOffer").
+		Preload("ApplicationOffer.Endpoints").
+		Joins("JOIN application_offers ON application_offers.id = public_offer_listings.application_offer_id").
+		Order("application_offers.name, application_offers.id")
+	if err := db.Find(&listings).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return listings, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestPublicModelListing(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.SetPublicModelListing(ctx, &dbmodel.PublicModelListing{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	listings, err := s.Database.ListPublicModelListings(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(listings, qt.HasLen, 0)
+
+	err = s.Database.SetPublicModelListing(ctx, &dbmodel.PublicModelListing{
+		ModelID:    env.model.ID,
+		Purpose:    "testing",
+		OwnerGroup: "test-group",
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetPublicModelListing(ctx, &dbmodel.PublicModelListing{
+		ModelID:    env.model.ID,
+		Purpose:    "production database",
+		OwnerGroup: "dba",
+	})
+	c.Assert(err, qt.IsNil)
+
+	listings, err = s.Database.ListPublicModelListings(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(listings, qt.HasLen, 1)
+	c.Check(listings[0].Purpose, qt.Equals, "production database")
+	c.Check(listings[0].OwnerGroup, qt.Equals, "dba")
+	c.Check(listings[0].Model.Name, qt.Equals, env.model.Name)
+
+	l := dbmodel.PublicModelListing{ModelID: env.model.ID}
+	err = s.Database.DeletePublicModelListing(ctx, &l)
+	c.Assert(err, qt.IsNil)
+	listings, err = s.Database.ListPublicModelListings(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(listings, qt.HasLen, 0)
+	err = s.Database.DeletePublicModelListing(ctx, &l)
+	c.Assert(err, qt.IsNil)
+}
+
+func (s *dbSuite) TestPublicOfferListing(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	offer := dbmodel.ApplicationOffer{
+		UUID:            "00000000-0000-0000-0000-000000000001",
+		Name:            "offer1",
+		ModelID:         env.model.ID,
+		ApplicationName: "app-1",
+		Endpoints: []dbmodel.ApplicationOfferRemoteEndpoint{{
+			Name:      "db",
+			Role:      "provider",
+			Interface: "postgresql",
+		}},
+	}
+	err := s.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.SetPublicOfferListing(ctx, &dbmodel.PublicOfferListing{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = s.Database.SetPublicOfferListing(ctx, &dbmodel.PublicOfferListing{
+		ApplicationOfferID: offer.ID,
+		Purpose:            "shared database",
+		OwnerGroup:         "dba",
+	})
+	c.Assert(err, qt.IsNil)
+
+	listings, err := s.Database.ListPublicOfferListings(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(listings, qt.HasLen, 1)
+	c.Check(listings[0].Purpose, qt.Equals, "shared database")
+	c.Check(listings[0].OwnerGroup, qt.Equals, "dba")
+	c.Check(listings[0].ApplicationOffer.Name, qt.Equals, "offer1")
+	c.Assert(listings[0].ApplicationOffer.Endpoints, qt.HasLen, 1)
+	c.Check(listings[0].ApplicationOffer.Endpoints[0].Interface, qt.Equals, "postgresql")
+
+	err = s.Database.DeleteApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	listings, err = s.Database.ListPublicOfferListings(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(listings, qt.HasLen, 0)
+}
+
+func TestListPublicModelListingsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.ListPublicModelListings(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
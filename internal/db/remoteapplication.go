@@ -0,0 +1,80 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// UpsertRemoteApplication stores the given remote application, replacing
+// any existing remote application with the same name in the same model.
+func (d *Database) UpsertRemoteApplication(ctx context.Context, app *dbmodel.RemoteApplication) (err error) {
+	const op = errors.Op("db.UpsertRemoteApplication")
+
+	if app.ModelID == 0 || app.Name == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing model or remote application name")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "model_id"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "offer_url", "life", "status"}),
+	})
+	if err := db.Create(app).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// DeleteRemoteApplication removes the remote application with the given
+// name from the given model. Deleting a remote application that does not
+// exist is not an error.
+func (d *Database) DeleteRemoteApplication(ctx context.Context, app *dbmodel.RemoteApplication) (err error) {
+	const op = errors.Op("db.DeleteRemoteApplication")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("model_id = ? AND name = ?", app.ModelID, app.Name)
+	if err := db.Delete(&dbmodel.RemoteApplication{}).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetModelRemoteApplications returns the remote applications consumed by
+// the model with the given ID, ordered by name.
+func (d *Database) GetModelRemoteApplications(ctx context.Context, modelID uint) (_ []dbmodel.RemoteApplication, err error) {
+	const op = errors.Op("db.GetModelRemoteApplications")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var apps []dbmodel.RemoteApplication
+	if err := d.DB.WithContext(ctx).Where("model_id = ?", modelID).Order("name").Find(&apps).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return apps, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestUpsertRemoteApplicationUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	err := d.UpsertRemoteApplication(context.Background(), &dbmodel.RemoteApplication{ModelID: 1, Name: "app-1"})
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestUpsertAndDeleteRemoteApplication(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.UpsertRemoteApplication(ctx, &dbmodel.RemoteApplication{Name: "app-1"})
+	c.Check(err, qt.ErrorMatches, `missing model or remote application name`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	for _, app := range []dbmodel.RemoteApplication{{
+		ModelID:  env.model.ID,
+		Name:     "app-2",
+		OfferURL: "test-controller:bob@canonical.com/model-2.offer-2",
+		Life:     "alive",
+		Status:   "active",
+	}, {
+		ModelID:  env.model.ID,
+		Name:     "app-1",
+		OfferURL: "test-controller:bob@canonical.com/model-2.offer-1",
+		Life:     "alive",
+		Status:   "active",
+	}, {
+		ModelID:  env.model.ID,
+		Name:     "app-1",
+		OfferURL: "test-controller:bob@canonical.com/model-2.offer-1",
+		Life:     "dying",
+		Status:   "terminated",
+	}} {
+		err := s.Database.UpsertRemoteApplication(ctx, &app)
+		c.Assert(err, qt.IsNil)
+	}
+
+	apps, err := s.Database.GetModelRemoteApplications(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(apps, qt.HasLen, 2)
+	c.Check(apps[0].Name, qt.Equals, "app-1")
+	c.Check(apps[0].Life, qt.Equals, "dying")
+	c.Check(apps[0].Status, qt.Equals, "terminated")
+	c.Check(apps[1].Name, qt.Equals, "app-2")
+
+	err = s.Database.DeleteRemoteApplication(ctx, &dbmodel.RemoteApplication{ModelID: env.model.ID, Name: "app-1"})
+	c.Assert(err, qt.IsNil)
+	// Deleting a remote application that does not exist is not an error.
+	err = s.Database.DeleteRemoteApplication(ctx, &dbmodel.RemoteApplication{ModelID: env.model.ID, Name: "app-1"})
+	c.Assert(err, qt.IsNil)
+
+	apps, err = s.Database.GetModelRemoteApplications(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(apps, qt.HasLen, 1)
+	c.Check(apps[0].Name, qt.Equals, "app-2")
+
+	// Remote applications are removed with their model.
+	err = s.Database.DeleteModel(ctx, &env.model)
+	c.Assert(err, qt.IsNil)
+	apps, err = s.Database.GetModelRemoteApplications(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(apps, qt.HasLen, 0)
+}
+
+func (s *dbSuite) TestUpsertAndDeleteModelBranch(c *qt.C) {
+	ctx := context.Background()
+	env := initTestEnvironment(c, s.Database)
+
+	err := s.Database.UpsertModelBranch(ctx, &dbmodel.ModelBranch{ModelID: env.model.ID})
+	c.Check(err, qt.ErrorMatches, `missing model or branch ID`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	branch := dbmodel.ModelBranch{
+		ModelID:         env.model.ID,
+		BranchID:        "branch-id-1",
+		Name:            "branch-1",
+		AssignedUnits:   dbmodel.Strings{"app-1/0"},
+		BranchCreatedBy: "bob@canonical.com",
+	}
+	err = s.Database.UpsertModelBranch(ctx, &branch)
+	c.Assert(err, qt.IsNil)
+
+	branch.AssignedUnits = dbmodel.Strings{"app-1/0", "app-1/1"}
+	branch.ConfigChanges = dbmodel.Strings{"app-1:key"}
+	err = s.Database.UpsertModelBranch(ctx, &branch)
+	c.Assert(err, qt.IsNil)
+
+	branches, err := s.Database.GetModelBranches(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Assert(branches, qt.HasLen, 1)
+	c.Check(branches[0].Name, qt.Equals, "branch-1")
+	c.Check(branches[0].AssignedUnits, qt.DeepEquals, dbmodel.Strings{"app-1/0", "app-1/1"})
+	c.Check(branches[0].ConfigChanges, qt.DeepEquals, dbmodel.Strings{"app-1:key"})
+
+	err = s.Database.DeleteModelBranch(ctx, &dbmodel.ModelBranch{ModelID: env.model.ID, BranchID: "branch-id-1"})
+	c.Assert(err, qt.IsNil)
+	branches, err = s.Database.GetModelBranches(ctx, env.model.ID)
+	c.Assert(err, qt.IsNil)
+	c.Check(branches, qt.HasLen, 0)
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// AddResultDownload stores the given result so that it may be downloaded
+// out of band.
+func (d *Database) AddResultDownload(ctx context.Context, rd *dbmodel.ResultDownload) (err error) {
+	const op = errors.Op("db.AddResultDownload")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(rd).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// GetResultDownload loads the result with the token hash in the given
+// ResultDownload. If there is no such result an error with the code
+// CodeNotFound is returned.
+func (d *Database) GetResultDownload(ctx context.Context, rd *dbmodel.ResultDownload) (err error) {
+	const op = errors.Op("db.GetResultDownload")
+	if rd.TokenHash == "" {
+		return errors.E(op, errors.CodeNotFound, "result download not found")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("token_hash = ?", rd.TokenHash).First(rd).Error; err != nil {
+		err = dbError(err)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, errors.CodeNotFound, "result download not found")
+		}
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListExpiredResultDownloads returns the results that expired before the
+// given time and are kept in the object store. The bodies of the results
+// are not loaded.
+func (d *Database) ListExpiredResultDownloads(ctx context.Context, before time.Time) (_ []dbmodel.ResultDownload, err error) {
+	const op = errors.Op("db.ListExpiredResultDownloads")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var rds []dbmodel.ResultDownload
+	db := d.DB.WithContext(ctx).Omit("body").Where("expires_at <= ? AND object_key <> ''", before).Order("id")
+	if err := db.Find(&rds).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return rds, nil
+}
+
+// DeleteExpiredResultDownloads removes the results that expired before
+// the given time, returning the number removed.
+func (d *Database) DeleteExpiredResultDownloads(ctx context.Context, before time.Time) (_ int64, err error) {
+	const op = errors.Op("db.DeleteExpiredResultDownloads")
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Where("expires_at <= ?", before).Delete(&dbmodel.ResultDownload{})
+	if result.Error != nil {
+		return 0, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
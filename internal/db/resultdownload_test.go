@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestResultDownloads(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	rd1 := dbmodel.ResultDownload{
+		ExpiresAt:    now.Add(time.Hour),
+		TokenHash:    "hash-1",
+		IdentityName: "alice@canonical.com",
+		Name:         "model-1.yaml",
+		ContentType:  "application/yaml",
+		Body:         []byte("applications: {}\n"),
+	}
+	err = s.Database.AddResultDownload(ctx, &rd1)
+	c.Assert(err, qt.IsNil)
+	rd2 := dbmodel.ResultDownload{
+		ExpiresAt:    now.Add(-time.Minute),
+		TokenHash:    "hash-2",
+		IdentityName: "alice@canonical.com",
+		Name:         "model-2.yaml",
+		ContentType:  "application/yaml",
+		ObjectKey:    "results/hash-2",
+	}
+	err = s.Database.AddResultDownload(ctx, &rd2)
+	c.Assert(err, qt.IsNil)
+
+	rd := dbmodel.ResultDownload{TokenHash: "hash-1"}
+	err = s.Database.GetResultDownload(ctx, &rd)
+	c.Assert(err, qt.IsNil)
+	c.Check(rd.Name, qt.Equals, "model-1.yaml")
+	c.Check(string(rd.Body), qt.Equals, "applications: {}\n")
+	c.Check(rd.ExpiresAt.Equal(rd1.ExpiresAt), qt.IsTrue)
+
+	rds, err := s.Database.ListExpiredResultDownloads(ctx, now)
+	c.Assert(err, qt.IsNil)
+	c.Assert(rds, qt.HasLen, 1)
+	c.Check(rds[0].TokenHash, qt.Equals, "hash-2")
+	c.Check(rds[0].ObjectKey, qt.Equals, "results/hash-2")
+
+	n, err := s.Database.DeleteExpiredResultDownloads(ctx, now)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	rd = dbmodel.ResultDownload{TokenHash: "hash-2"}
+	err = s.Database.GetResultDownload(ctx, &rd)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	rd = dbmodel.ResultDownload{}
+	err = s.Database.GetResultDownload(ctx, &rd)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
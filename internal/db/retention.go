@@ -0,0 +1,172 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// The historical datasets that may be pruned with PruneDataset.
+const (
+	// DatasetAuditLog holds the audit log entries, pruned by the time
+	// of the entry.
+	DatasetAuditLog = "audit-log"
+
+	// DatasetDeadLetterDeltas holds the dead-lettered watcher deltas,
+	// pruned by the time the delta was dead-lettered.
+	DatasetDeadLetterDeltas = "dead-letter-deltas"
+
+	// DatasetAccessRequests holds the access requests that have been
+	// approved or denied, pruned by the time of the review. Pending
+	// requests are never pruned.
+	DatasetAccessRequests = "access-requests"
+
+	// DatasetModelResourceSnapshots holds the model resource snapshots,
+	// pruned by the time of the snapshot.
+	DatasetModelResourceSnapshots = "model-resource-snapshots"
+
+	// DatasetDeletedModels holds the records of models removed from
+	// JIMM, pruned by the time the model was removed. A model can no
+	// longer be restored once its record is pruned.
+	DatasetDeletedModels = "deleted-models"
+
+	// DatasetWebhookDeliveries holds the records of signed webhook
+	// deliveries, pruned by the time the delivery was signed.
+	DatasetWebhookDeliveries = "webhook-deliveries"
+
+	// DatasetOperations holds the long-running operations that have
+	// finished, pruned by the time the operation finished. Running
+	// operations are never pruned.
+	DatasetOperations = "operations"
+)
+
+// A prunableDataset describes how the rows of a dataset older than a
+// given time are found.
+type prunableDataset struct {
+	model     interface{}
+	condition string
+}
+
+var prunableDatasets = map[string]prunableDataset{
+	DatasetAuditLog: {
+		model:     &dbmodel.AuditLogEntry{},
+		condition: "time < ?",
+	},
+	DatasetDeadLetterDeltas: {
+		model:     &dbmodel.DeadLetterDelta{},
+		condition: "created_at < ?",
+	},
+	DatasetAccessRequests: {
+		model:     &dbmodel.AccessRequest{},
+		condition: "reviewed_at < ?",
+	},
+	DatasetModelResourceSnapshots: {
+		model:     &dbmodel.ModelResourceSnapshot{},
+		condition: "time < ?",
+	},
+	DatasetDeletedModels: {
+		model:     &dbmodel.DeletedModel{},
+		condition: "deleted_at < ?",
+	},
+	DatasetWebhookDeliveries: {
+		model:     &dbmodel.WebhookDelivery{},
+		condition: "created_at < ?",
+	},
+	DatasetOperations: {
+		model:     &dbmodel.Operation{},
+		condition: "ended_at < ?",
+	},
+}
+
+// PrunableDatasets returns the names of the datasets that may be pruned
+// with PruneDataset.
+func PrunableDatasets() []string {
+	return []string{DatasetAuditLog, DatasetDeadLetterDeltas, DatasetAccessRequests, DatasetModelResourceSnapshots, DatasetDeletedModels, DatasetWebhookDeliveries, DatasetOperations}
+}
+
+// PruneDataset deletes at most limit of the rows in the named dataset
+// that are older than before, oldest first, so that large datasets can
+// be pruned in batches without holding long-running locks. If limit is
+// not positive all matching rows are deleted. The number of deleted rows
+// is returned. If the dataset is not known an error with the code
+// CodeBadRequest is returned.
+func (d *Database) PruneDataset(ctx context.Context, dataset string, before time.Time, limit int) (_ int64, err error) {
+	const op = errors.Op("db.PruneDataset")
+
+	ds, ok := prunableDatasets[dataset]
+	if !ok {
+		return 0, errors.E(op, errors.CodeBadRequest, "unknown dataset "+dataset)
+	}
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Unscoped()
+	if limit > 0 {
+		batch := d.DB.WithContext(ctx).Model(ds.model).Select("id").Where(ds.condition, before).Order("id").Limit(limit)
+		db = db.Where("id IN (?)", batch)
+	} else {
+		db = db.Where(ds.condition, before)
+	}
+	tx := db.Delete(ds.model)
+	if tx.Error != nil {
+		return 0, errors.E(op, dbError(tx.Error))
+	}
+	return tx.RowsAffected, nil
+}
+
+// ListPrunableAuditLogEntries returns the batch of at most limit audit
+// log entries that PruneDataset would delete from the audit log dataset,
+// the entries older than before ordered oldest first. This allows the
+// entries to be exported before they are pruned.
+func (d *Database) ListPrunableAuditLogEntries(ctx context.Context, before time.Time, limit int) (_ []dbmodel.AuditLogEntry, err error) {
+	const op = errors.Op("db.ListPrunableAuditLogEntries")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where(prunableDatasets[DatasetAuditLog].condition, before).Order("id")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	var entries []dbmodel.AuditLogEntry
+	if err := db.Find(&entries).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return entries, nil
+}
+
+// DeleteAuditLogEntries deletes the audit log entries with the given IDs,
+// returning the number deleted.
+func (d *Database) DeleteAuditLogEntries(ctx context.Context, ids []uint) (_ int64, err error) {
+	const op = errors.Op("db.DeleteAuditLogEntries")
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Where("id IN ?", ids).Delete(&dbmodel.AuditLogEntry{})
+	if result.Error != nil {
+		return 0, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
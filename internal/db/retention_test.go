@@ -0,0 +1,119 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestPruneDataset(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := s.Database.PruneDataset(ctx, db.DatasetAuditLog, now, 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.Database.PruneDataset(ctx, "status-history", now, 0)
+	c.Check(err, qt.ErrorMatches, `unknown dataset status-history`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	for i := 1; i <= 5; i++ {
+		err := s.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time: now.AddDate(0, 0, -i),
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	// Entries older than 2 days are pruned in batches, oldest first.
+	before := now.AddDate(0, 0, -2).Add(time.Minute)
+	n, err := s.Database.PruneDataset(ctx, db.DatasetAuditLog, before, 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+	var logs []dbmodel.AuditLogEntry
+	c.Assert(s.Database.DB.Order("time").Find(&logs).Error, qt.IsNil)
+	c.Assert(logs, qt.HasLen, 3)
+	c.Check(logs[0].Time.Before(now.AddDate(0, 0, -3).Add(time.Minute)), qt.IsTrue)
+
+	n, err = s.Database.PruneDataset(ctx, db.DatasetAuditLog, before, 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+	n, err = s.Database.PruneDataset(ctx, db.DatasetAuditLog, before, 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(0))
+
+	// Without a limit every matching row is pruned.
+	n, err = s.Database.PruneDataset(ctx, db.DatasetAuditLog, now, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	// Pending access requests are never pruned.
+	identity, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.GetIdentity(ctx, identity), qt.IsNil)
+	pending := dbmodel.AccessRequest{
+		IdentityName: "alice@canonical.com",
+		TargetTag:    "model-00000002-0000-0000-0000-000000000001",
+		Access:       "read",
+		Status:       dbmodel.AccessRequestPending,
+	}
+	c.Assert(s.Database.DB.Create(&pending).Error, qt.IsNil)
+	denied := pending
+	denied.ID = 0
+	denied.Status = dbmodel.AccessRequestDenied
+	denied.ReviewedAt.Time = now.AddDate(0, 0, -1)
+	denied.ReviewedAt.Valid = true
+	c.Assert(s.Database.DB.Create(&denied).Error, qt.IsNil)
+	n, err = s.Database.PruneDataset(ctx, db.DatasetAccessRequests, now, 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+	var requests []dbmodel.AccessRequest
+	c.Assert(s.Database.DB.Find(&requests).Error, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 1)
+	c.Check(requests[0].ID, qt.Equals, pending.ID)
+}
+
+func (s *dbSuite) TestListAndDeletePrunableAuditLogEntries(c *qt.C) {
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := s.Database.ListPrunableAuditLogEntries(ctx, now, 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for i := 1; i <= 3; i++ {
+		err := s.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time:         now.AddDate(0, 0, -i),
+			FacadeMethod: "Method",
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	entries, err := s.Database.ListPrunableAuditLogEntries(ctx, now.AddDate(0, 0, -1).Add(-time.Minute), 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 1)
+
+	entries, err = s.Database.ListPrunableAuditLogEntries(ctx, now.AddDate(0, 0, -1).Add(-time.Minute), 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 2)
+	c.Check(entries[0].ID < entries[1].ID, qt.IsTrue)
+
+	n, err := s.Database.DeleteAuditLogEntries(ctx, []uint{entries[0].ID, entries[1].ID})
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+
+	entries, err = s.Database.ListPrunableAuditLogEntries(ctx, now, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(entries, qt.HasLen, 1)
+}
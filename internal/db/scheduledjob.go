@@ -0,0 +1,179 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// SetScheduledJobEnabled records whether the scheduled job with the given
+// name is enabled.
+func (d *Database) SetScheduledJobEnabled(ctx context.Context, name string, enabled bool) (err error) {
+	const op = errors.Op("db.SetScheduledJobEnabled")
+
+	if name == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing job name")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	// The enabled column defaults to true, so the settings are written
+	// explicitly rather than relying on gorm to write a false value.
+	now := time.Now()
+	result := d.DB.WithContext(ctx).Exec(`
+		INSERT INTO scheduled_jobs (name, created_at, updated_at, enabled)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			updated_at = excluded.updated_at,
+			enabled = excluded.enabled`,
+		name, now, now, enabled,
+	)
+	if result.Error != nil {
+		return errors.E(op, dbError(result.Error))
+	}
+	return nil
+}
+
+// ListScheduledJobs returns the settings of all scheduled jobs that have
+// any, ordered by name.
+func (d *Database) ListScheduledJobs(ctx context.Context) (_ []dbmodel.ScheduledJob, err error) {
+	const op = errors.Op("db.ListScheduledJobs")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var jobs []dbmodel.ScheduledJob
+	if err := d.DB.WithContext(ctx).Order("name").Find(&jobs).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return jobs, nil
+}
+
+// GetScheduledJob fills in the settings of the given scheduled job,
+// which must have its name set. If the job has no settings an error with
+// a code of CodeNotFound is returned.
+func (d *Database) GetScheduledJob(ctx context.Context, job *dbmodel.ScheduledJob) (err error) {
+	const op = errors.Op("db.GetScheduledJob")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Where("name = ?", job.Name).First(job).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// AddScheduledJobRun records the start of the given run of a scheduled
+// job.
+func (d *Database) AddScheduledJobRun(ctx context.Context, run *dbmodel.ScheduledJobRun) (err error) {
+	const op = errors.Op("db.AddScheduledJobRun")
+
+	if run.JobName == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing job name")
+	}
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(run).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// FinishScheduledJobRun records the finish time and error of the given
+// run of a scheduled job.
+func (d *Database) FinishScheduledJobRun(ctx context.Context, run *dbmodel.ScheduledJobRun) (err error) {
+	const op = errors.Op("db.FinishScheduledJobRun")
+
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Model(run).Select("updated_at", "finished_at", "error")
+	if err := db.Updates(run).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListScheduledJobRuns returns, at most, the given number of the most
+// recent runs of the scheduled job with the given name, the most recent
+// first.
+func (d *Database) ListScheduledJobRuns(ctx context.Context, name string, limit int) (_ []dbmodel.ScheduledJobRun, err error) {
+	const op = errors.Op("db.ListScheduledJobRuns")
+
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("job_name = ?", name).Order("started_at DESC, id DESC").Limit(limit)
+	var runs []dbmodel.ScheduledJobRun
+	if err := db.Find(&runs).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return runs, nil
+}
+
+// PruneScheduledJobRuns removes all but the given number of the most
+// recent runs of the scheduled job with the given name. It returns the
+// number of runs removed.
+func (d *Database) PruneScheduledJobRuns(ctx context.Context, name string, keep int) (_ int64, err error) {
+	const op = errors.Op("db.PruneScheduledJobRuns")
+
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	result := d.DB.WithContext(ctx).Exec(`
+		DELETE FROM scheduled_job_runs
+		WHERE job_name = ? AND id NOT IN (
+			SELECT id FROM scheduled_job_runs
+			WHERE job_name = ?
+			ORDER BY started_at DESC, id DESC
+			LIMIT ?
+		)`,
+		name, name, keep,
+	)
+	if result.Error != nil {
+		return 0, errors.E(op, dbError(result.Error))
+	}
+	return result.RowsAffected, nil
+}
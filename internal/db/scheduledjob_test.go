@@ -0,0 +1,103 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestScheduledJobs(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.SetScheduledJobEnabled(ctx, "job-1", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUpgradeInProgress)
+
+	err = s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.SetScheduledJobEnabled(ctx, "", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// Jobs have no settings until they are set.
+	job := dbmodel.ScheduledJob{Name: "job-1"}
+	err = s.Database.GetScheduledJob(ctx, &job)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = s.Database.SetScheduledJobEnabled(ctx, "job-1", false)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.SetScheduledJobEnabled(ctx, "job-2", true)
+	c.Assert(err, qt.IsNil)
+	err = s.Database.GetScheduledJob(ctx, &job)
+	c.Assert(err, qt.IsNil)
+	c.Check(job.Enabled, qt.IsFalse)
+
+	err = s.Database.SetScheduledJobEnabled(ctx, "job-1", true)
+	c.Assert(err, qt.IsNil)
+	jobs, err := s.Database.ListScheduledJobs(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(jobs, qt.HasLen, 2)
+	c.Check(jobs[0].Name, qt.Equals, "job-1")
+	c.Check(jobs[0].Enabled, qt.IsTrue)
+	c.Check(jobs[1].Name, qt.Equals, "job-2")
+	c.Check(jobs[1].Enabled, qt.IsTrue)
+}
+
+func (s *dbSuite) TestScheduledJobRuns(c *qt.C) {
+	ctx := context.Background()
+
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = s.Database.AddScheduledJobRun(ctx, &dbmodel.ScheduledJobRun{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		run := dbmodel.ScheduledJobRun{
+			JobName:   "job-1",
+			Holder:    "replica-1",
+			StartedAt: t0.Add(time.Duration(i) * time.Hour),
+		}
+		err := s.Database.AddScheduledJobRun(ctx, &run)
+		c.Assert(err, qt.IsNil)
+		if i < 4 {
+			run.FinishedAt = sql.NullTime{Time: run.StartedAt.Add(time.Minute), Valid: true}
+			if i == 3 {
+				run.Error = "failed"
+			}
+			err = s.Database.FinishScheduledJobRun(ctx, &run)
+			c.Assert(err, qt.IsNil)
+		}
+	}
+	err = s.Database.AddScheduledJobRun(ctx, &dbmodel.ScheduledJobRun{JobName: "job-2", StartedAt: t0})
+	c.Assert(err, qt.IsNil)
+
+	runs, err := s.Database.ListScheduledJobRuns(ctx, "job-1", 2)
+	c.Assert(err, qt.IsNil)
+	c.Assert(runs, qt.HasLen, 2)
+	c.Check(runs[0].StartedAt.Equal(t0.Add(4*time.Hour)), qt.IsTrue)
+	c.Check(runs[0].FinishedAt.Valid, qt.IsFalse)
+	c.Check(runs[1].StartedAt.Equal(t0.Add(3*time.Hour)), qt.IsTrue)
+	c.Check(runs[1].FinishedAt.Time.Equal(t0.Add(3*time.Hour+time.Minute)), qt.IsTrue)
+	c.Check(runs[1].Error, qt.Equals, "failed")
+
+	n, err := s.Database.PruneScheduledJobRuns(ctx, "job-1", 3)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(2))
+	runs, err = s.Database.ListScheduledJobRuns(ctx, "job-1", 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(runs, qt.HasLen, 3)
+	c.Check(runs[2].StartedAt.Equal(t0.Add(2*time.Hour)), qt.IsTrue)
+
+	// The runs of other jobs are not pruned.
+	runs, err = s.Database.ListScheduledJobRuns(ctx, "job-2", 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(runs, qt.HasLen, 1)
+}
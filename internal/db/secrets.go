@@ -5,15 +5,18 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/servermon"
 )
@@ -46,14 +49,25 @@ func (d *Database) UpsertSecret(ctx context.Context, secret *dbmodel.Secret) (er
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
 
+	stored := *secret
+	stored.SchemaVersion = dbmodel.SecretSchemaVersion
+	if d.Encrypter != nil && len(secret.Data) > 0 {
+		stored.Data, err = d.Encrypter.Seal(ctx, secret.Data, secretAdditionalData(secret))
+		if err != nil {
+			return errors.E(op, err, "failed to encrypt secret data")
+		}
+	}
+
 	// On conflict perform an upset to make the operation resemble a Put.
 	db := d.DB.WithContext(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "type"}, {Name: "tag"}},
-		DoUpdates: clause.AssignmentColumns([]string{"time", "data"}),
+		DoUpdates: clause.AssignmentColumns([]string{"time", "data", "schema_version"}),
 	})
-	if err := db.Create(secret).Error; err != nil {
+	if err := db.Create(&stored).Error; err != nil {
 		return errors.E(op, dbError(err))
 	}
+	secret.ID = stored.ID
+	secret.SchemaVersion = stored.SchemaVersion
 	return nil
 }
 
@@ -84,9 +98,150 @@ func (d *Database) GetSecret(ctx context.Context, secret *dbmodel.Secret) (err e
 		}
 		return errors.E(op, dbError(err))
 	}
+	if err := d.openSecret(ctx, secret); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// openSecret decrypts the data of the given secret in place, if it is
+// encrypted.
+func (d *Database) openSecret(ctx context.Context, secret *dbmodel.Secret) error {
+	if !envelope.IsSealed(secret.Data) {
+		return nil
+	}
+	if d.Encrypter == nil {
+		return errors.E(errors.CodeServerConfiguration, "secret is encrypted but no encryption key is configured")
+	}
+	data, err := d.Encrypter.Open(ctx, secret.Data, secretAdditionalData(secret))
+	if err != nil {
+		return errors.E(err, "failed to decrypt secret data")
+	}
+	secret.Data = data
 	return nil
 }
 
+// secretAdditionalData returns the additional data authenticated when
+// encrypting a secret, this binds the encrypted data to the secret's type
+// and tag.
+func secretAdditionalData(secret *dbmodel.Secret) []byte {
+	return []byte(secret.Type + "/" + secret.Tag)
+}
+
+// RewrapSecrets brings every stored secret up to date with the
+// configured Encrypter's primary key. This covers the secrets table, the
+// identities' TOTP secrets and the webhook signing secrets. Plaintext
+// secrets are encrypted and secrets whose data key was wrapped with a
+// previous master key have their data key re-wrapped. RewrapSecrets
+// returns the number of records updated. Once it has completed previous
+// master keys are no longer required.
+func (d *Database) RewrapSecrets(ctx context.Context) (_ int, err error) {
+	const op = errors.Op("db.RewrapSecrets")
+
+	if err := d.ready(); err != nil {
+		return 0, errors.E(op, err)
+	}
+	if d.Encrypter == nil {
+		return 0, errors.E(op, errors.CodeServerConfiguration, "no encryption key configured")
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	var n int
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var secrets []dbmodel.Secret
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&secrets).Error; err != nil {
+			return dbError(err)
+		}
+		for _, secret := range secrets {
+			if string(secret.Data) == "null" {
+				continue
+			}
+			data, changed, err := d.rewrap(ctx, secret.Data, secretAdditionalData(&secret))
+			if err != nil {
+				return errors.E(err, fmt.Sprintf("secret %s/%s", secret.Type, secret.Tag))
+			}
+			if !changed {
+				continue
+			}
+			if err := tx.Model(&secret).Update("data", dbmodel.JSON(data)).Error; err != nil {
+				return dbError(err)
+			}
+			n++
+		}
+
+		var identities []dbmodel.Identity
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("totp_secret <> ''").Order("id").Find(&identities).Error; err != nil {
+			return dbError(err)
+		}
+		for _, i := range identities {
+			data, changed, err := d.rewrap(ctx, []byte(i.TOTPSecret), totpAdditionalData(&i))
+			if err != nil {
+				return errors.E(err, fmt.Sprintf("TOTP secret of %s", i.Name))
+			}
+			if !changed {
+				continue
+			}
+			if err := tx.Model(&i).UpdateColumn("totp_secret", string(data)).Error; err != nil {
+				return dbError(err)
+			}
+			n++
+		}
+
+		var webhookSecrets []dbmodel.WebhookSecret
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&webhookSecrets).Error; err != nil {
+			return dbError(err)
+		}
+		for _, ws := range webhookSecrets {
+			ad := webhookSecretAdditionalData(ws.Webhook)
+			secret, changed, err := d.rewrap(ctx, ws.Secret, ad)
+			if err != nil {
+				return errors.E(err, fmt.Sprintf("webhook %s secret", ws.Webhook))
+			}
+			previous, previousChanged, err := d.rewrap(ctx, ws.PreviousSecret, ad)
+			if err != nil {
+				return errors.E(err, fmt.Sprintf("webhook %s previous secret", ws.Webhook))
+			}
+			if !changed && !previousChanged {
+				continue
+			}
+			if err := tx.Model(&ws).UpdateColumns(map[string]interface{}{
+				"secret":          secret,
+				"previous_secret": previous,
+			}).Error; err != nil {
+				return dbError(err)
+			}
+			n++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	return n, nil
+}
+
+// rewrap brings the given secret up to date with the Encrypter's primary
+// key, encrypting it with the given additional data if it is not already
+// encrypted. The secret is returned unchanged if it is empty or already
+// wrapped with the primary key, in which case false is returned.
+func (d *Database) rewrap(ctx context.Context, data, ad []byte) ([]byte, bool, error) {
+	switch {
+	case len(data) == 0:
+		return data, false, nil
+	case envelope.IsSealed(data):
+		return d.Encrypter.Rewrap(ctx, data)
+	default:
+		sealed, err := d.Encrypter.Seal(ctx, data, ad)
+		if err != nil {
+			return nil, false, err
+		}
+		return sealed, true, nil
+	}
+}
+
 // Delete secret deletes the secret with the specified type and tag.
 func (d *Database) DeleteSecret(ctx context.Context, secret *dbmodel.Secret) (err error) {
 	const op = errors.Op("db.DeleteSecret")
@@ -3,7 +3,11 @@
 package db_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
 	"time"
 
 	qt "github.com/frankban/quicktest"
@@ -14,6 +18,8 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
 )
 
 var testTime = time.Date(2013, 7, 26, 0, 0, 0, 0, time.UTC)
@@ -279,3 +285,131 @@ func (s *dbSuite) TestCleanupJWKS(c *qt.C) {
 	c.Assert(s.Database.DB.Model(&dbmodel.Secret{}).Count(&count).Error, qt.IsNil)
 	c.Assert(count, qt.Equals, int64(0))
 }
+
+func newTestEncrypter(c *qt.C, ids ...string) *envelope.Encrypter {
+	keys := make(map[string][]byte)
+	for _, id := range ids {
+		key := sha256.Sum256([]byte(id))
+		keys[id] = key[:]
+	}
+	kr, err := envelope.NewKeyring(ids, keys)
+	c.Assert(err, qt.IsNil)
+	return &envelope.Encrypter{Keys: kr}
+}
+
+func (s *dbSuite) TestEncryptedSecrets(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	// A secret written before encryption was configured.
+	tag := names.NewCloudCredentialTag("test/bob@canonical.com/plain")
+	err = s.Database.Put(ctx, tag, map[string]string{"key": "plain"})
+	c.Assert(err, qt.IsNil)
+
+	s.Database.Encrypter = newTestEncrypter(c, "key-1")
+	c.Cleanup(func() { s.Database.Encrypter = nil })
+
+	encTag := names.NewCloudCredentialTag("test/bob@canonical.com/encrypted")
+	err = s.Database.Put(ctx, encTag, map[string]string{"key": "encrypted"})
+	c.Assert(err, qt.IsNil)
+	err = s.Database.PutControllerCredentials(ctx, "controller-1", "admin", "hunter2")
+	c.Assert(err, qt.IsNil)
+
+	// The data is not stored in plaintext.
+	var secret dbmodel.Secret
+	err = s.Database.DB.Where("tag = ?", encTag.String()).First(&secret).Error
+	c.Assert(err, qt.IsNil)
+	c.Check(envelope.IsSealed(secret.Data), qt.IsTrue)
+	c.Check(bytes.Contains(secret.Data, []byte("encrypted")), qt.IsFalse)
+
+	// Encrypted and plaintext secrets are both read transparently.
+	attr, err := s.Database.Get(ctx, encTag)
+	c.Assert(err, qt.IsNil)
+	c.Check(attr, qt.DeepEquals, map[string]string{"key": "encrypted"})
+	attr, err = s.Database.Get(ctx, tag)
+	c.Assert(err, qt.IsNil)
+	c.Check(attr, qt.DeepEquals, map[string]string{"key": "plain"})
+	username, password, err := s.Database.GetControllerCredentials(ctx, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(username, qt.Equals, "admin")
+	c.Check(password, qt.Equals, "hunter2")
+
+	// Encrypted data cannot be moved to a different secret.
+	err = s.Database.DB.Model(&dbmodel.Secret{}).Where("tag = ?", tag.String()).Update("data", secret.Data).Error
+	c.Assert(err, qt.IsNil)
+	_, err = s.Database.Get(ctx, tag)
+	c.Check(err, qt.ErrorMatches, `failed to decrypt secret data`)
+
+	// Without the key encrypted secrets cannot be read.
+	s.Database.Encrypter = nil
+	_, err = s.Database.Get(ctx, encTag)
+	c.Check(err, qt.ErrorMatches, `secret is encrypted but no encryption key is configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestRewrapSecrets(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, true)
+	c.Assert(err, qt.IsNil)
+
+	_, err = s.Database.RewrapSecrets(ctx)
+	c.Check(err, qt.ErrorMatches, `no encryption key configured`)
+
+	err = s.Database.PutControllerCredentials(ctx, "controller-1", "admin", "pw1")
+	c.Assert(err, qt.IsNil)
+	identity, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Database.DB.Create(identity).Error, qt.IsNil)
+	err = s.Database.SetIdentityTOTPSecret(ctx, identity, "TOTP")
+	c.Assert(err, qt.IsNil)
+	for _, secret := range []string{"webhook-1", "webhook-2"} {
+		err = s.Database.RotateWebhookSecret(ctx, &dbmodel.WebhookSecret{Webhook: "ops", Secret: []byte(secret)})
+		c.Assert(err, qt.IsNil)
+	}
+	c.Cleanup(func() { s.Database.Encrypter = nil })
+
+	// Plaintext secrets are encrypted.
+	s.Database.Encrypter = newTestEncrypter(c, "key-1")
+	err = s.Database.PutControllerCredentials(ctx, "controller-2", "admin", "pw2")
+	c.Assert(err, qt.IsNil)
+	n, err := s.Database.RewrapSecrets(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 3)
+
+	// Rotate the master key.
+	s.Database.Encrypter = newTestEncrypter(c, "key-2", "key-1")
+	n, err = s.Database.RewrapSecrets(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 4)
+	n, err = s.Database.RewrapSecrets(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+
+	// The previous key is no longer needed.
+	s.Database.Encrypter = newTestEncrypter(c, "key-2")
+	for i, name := range []string{"controller-1", "controller-2"} {
+		_, password, err := s.Database.GetControllerCredentials(ctx, name)
+		c.Assert(err, qt.IsNil)
+		c.Check(password, qt.Equals, fmt.Sprintf("pw%d", i+1))
+	}
+	err = s.Database.GetIdentity(ctx, identity)
+	c.Assert(err, qt.IsNil)
+	totp, err := s.Database.OpenIdentityTOTPSecret(ctx, identity)
+	c.Assert(err, qt.IsNil)
+	c.Check(totp, qt.Equals, "TOTP")
+	ws := dbmodel.WebhookSecret{Webhook: "ops"}
+	err = s.Database.GetWebhookSecret(ctx, &ws)
+	c.Assert(err, qt.IsNil)
+	c.Check(ws.Secret, qt.DeepEquals, []byte("webhook-2"))
+	c.Check(ws.PreviousSecret, qt.DeepEquals, []byte("webhook-1"))
+}
+
+func TestRewrapSecretsUnconfiguredDatabase(t *testing.T) {
+	c := qt.New(t)
+
+	var d db.Database
+	_, err := d.RewrapSecrets(context.Background())
+	c.Check(err, qt.ErrorMatches, `database not configured`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Canonical.
+
+package db
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// GetWebhookSecret fills in the given webhook secret, which is found by
+// its webhook name. Encrypted secrets are decrypted. If the webhook has no
+// secret an error with a code of CodeNotFound is returned.
+func (d *Database) GetWebhookSecret(ctx context.Context, secret *dbmodel.WebhookSecret) (err error) {
+	const op = errors.Op("db.GetWebhookSecret")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx)
+	if err := db.Where("webhook = ?", secret.Webhook).First(secret).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	if secret.Secret, err = d.openWebhookSecret(ctx, secret.Webhook, secret.Secret); err != nil {
+		return errors.E(op, err)
+	}
+	if secret.PreviousSecret, err = d.openWebhookSecret(ctx, secret.Webhook, secret.PreviousSecret); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RotateWebhookSecret replaces the secret of the webhook named in the
+// given webhook secret with its Secret, keeping the replaced secret as
+// the previous secret. The secret is encrypted if an Encrypter is
+// configured. If the webhook has no secret one is created. The given
+// webhook secret is updated to hold the stored secret, unencrypted.
+func (d *Database) RotateWebhookSecret(ctx context.Context, secret *dbmodel.WebhookSecret) (err error) {
+	const op = errors.Op("db.RotateWebhookSecret")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	stored := secret.Secret
+	if d.Encrypter != nil && len(secret.Secret) > 0 {
+		stored, err = d.Encrypter.Seal(ctx, secret.Secret, webhookSecretAdditionalData(secret.Webhook))
+		if err != nil {
+			return errors.E(op, err, "failed to encrypt webhook secret")
+		}
+	}
+	err = d.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current dbmodel.WebhookSecret
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("webhook = ?", secret.Webhook).First(&current).Error
+		switch {
+		case err == gorm.ErrRecordNotFound:
+			current = dbmodel.WebhookSecret{Webhook: secret.Webhook}
+		case err != nil:
+			return err
+		default:
+			current.PreviousSecret = current.Secret
+		}
+		current.Secret = stored
+		current.RotatedAt = time.Now()
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+		plaintext := secret.Secret
+		*secret = current
+		secret.Secret = plaintext
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, dbError(err))
+	}
+	if secret.PreviousSecret, err = d.openWebhookSecret(ctx, secret.Webhook, secret.PreviousSecret); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// openWebhookSecret returns the given secret of the named webhook,
+// decrypting it if it is encrypted. Secrets stored before an Encrypter
+// was configured are returned as they are.
+func (d *Database) openWebhookSecret(ctx context.Context, webhook string, secret []byte) ([]byte, error) {
+	if !envelope.IsSealed(secret) {
+		return secret, nil
+	}
+	if d.Encrypter == nil {
+		return nil, errors.E(errors.CodeServerConfiguration, "webhook secret is encrypted but no encryption key is configured")
+	}
+	plaintext, err := d.Encrypter.Open(ctx, secret, webhookSecretAdditionalData(webhook))
+	if err != nil {
+		return nil, errors.E(err, "failed to decrypt webhook secret")
+	}
+	return plaintext, nil
+}
+
+// webhookSecretAdditionalData returns the additional data authenticated
+// when encrypting a webhook secret, this binds the encrypted secret to
+// the webhook.
+func webhookSecretAdditionalData(webhook string) []byte {
+	return []byte("webhook/" + webhook + "/secret")
+}
+
+// AddWebhookDelivery records the given webhook delivery. If a delivery
+// with the same nonce has already been recorded an error with a code of
+// CodeAlreadyExists is returned.
+func (d *Database) AddWebhookDelivery(ctx context.Context, delivery *dbmodel.WebhookDelivery) (err error) {
+	const op = errors.Op("db.AddWebhookDelivery")
+	if err := d.ready(); err != nil {
+		return errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	if err := d.DB.WithContext(ctx).Create(delivery).Error; err != nil {
+		return errors.E(op, dbError(err))
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns at most limit of the recorded deliveries
+// to the named webhook, newest first. If limit is not positive all the
+// recorded deliveries are returned.
+func (d *Database) ListWebhookDeliveries(ctx context.Context, webhook string, limit int) (_ []dbmodel.WebhookDelivery, err error) {
+	const op = errors.Op("db.ListWebhookDeliveries")
+	if err := d.ready(); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	durationObserver := servermon.DurationObserver(servermon.DBQueryDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.DBQueryErrorCount, &err, string(op))
+
+	db := d.DB.WithContext(ctx).Where("webhook = ?", webhook).Order("created_at DESC, id DESC")
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	var deliveries []dbmodel.WebhookDelivery
+	if err := db.Find(&deliveries).Error; err != nil {
+		return nil, errors.E(op, dbError(err))
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Canonical.
+
+package db_test
+
+import (
+	"context"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func (s *dbSuite) TestRotateWebhookSecret(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	secret := dbmodel.WebhookSecret{Webhook: "ops"}
+	err = s.Database.GetWebhookSecret(ctx, &secret)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	secret = dbmodel.WebhookSecret{Webhook: "ops", Secret: []byte("secret-1")}
+	err = s.Database.RotateWebhookSecret(ctx, &secret)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret.ID, qt.Not(qt.Equals), uint(0))
+	c.Check(secret.PreviousSecret, qt.IsNil)
+
+	secret = dbmodel.WebhookSecret{Webhook: "ops", Secret: []byte("secret-2")}
+	err = s.Database.RotateWebhookSecret(ctx, &secret)
+	c.Assert(err, qt.IsNil)
+
+	secret = dbmodel.WebhookSecret{Webhook: "ops"}
+	err = s.Database.GetWebhookSecret(ctx, &secret)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret.Secret, qt.DeepEquals, []byte("secret-2"))
+	c.Check(secret.PreviousSecret, qt.DeepEquals, []byte("secret-1"))
+	c.Check(secret.RotatedAt.IsZero(), qt.IsFalse)
+}
+
+func (s *dbSuite) TestRotateWebhookSecretEncrypted(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	s.Database.Encrypter = newTestEncrypter(c, "key-1")
+	c.Cleanup(func() { s.Database.Encrypter = nil })
+
+	for _, secret := range []string{"secret-1", "secret-2"} {
+		ws := dbmodel.WebhookSecret{Webhook: "ops", Secret: []byte(secret)}
+		err = s.Database.RotateWebhookSecret(ctx, &ws)
+		c.Assert(err, qt.IsNil)
+		c.Check(ws.Secret, qt.DeepEquals, []byte(secret))
+	}
+
+	var stored dbmodel.WebhookSecret
+	err = s.Database.DB.Where("webhook = ?", "ops").First(&stored).Error
+	c.Assert(err, qt.IsNil)
+	c.Check(envelope.IsSealed(stored.Secret), qt.IsTrue)
+	c.Check(envelope.IsSealed(stored.PreviousSecret), qt.IsTrue)
+
+	secret := dbmodel.WebhookSecret{Webhook: "ops"}
+	err = s.Database.GetWebhookSecret(ctx, &secret)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret.Secret, qt.DeepEquals, []byte("secret-2"))
+	c.Check(secret.PreviousSecret, qt.DeepEquals, []byte("secret-1"))
+
+	// The encrypted secret cannot be read without the key.
+	s.Database.Encrypter = nil
+	err = s.Database.GetWebhookSecret(ctx, &dbmodel.WebhookSecret{Webhook: "ops"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
+
+func (s *dbSuite) TestWebhookDeliveries(c *qt.C) {
+	ctx := context.Background()
+	err := s.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for i, nonce := range []string{"n1", "n2", "n3"} {
+		err := s.Database.AddWebhookDelivery(ctx, &dbmodel.WebhookDelivery{
+			Webhook:   "ops",
+			Nonce:     nonce,
+			Timestamp: int64(i),
+			Signature: "v1=" + nonce,
+		})
+		c.Assert(err, qt.IsNil)
+	}
+	err = s.Database.AddWebhookDelivery(ctx, &dbmodel.WebhookDelivery{Webhook: "ops", Nonce: "n1", Signature: "v1=n1"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	deliveries, err := s.Database.ListWebhookDeliveries(ctx, "ops", 2)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deliveries, qt.HasLen, 2)
+	c.Check(deliveries[0].Nonce, qt.Equals, "n3")
+	c.Check(deliveries[1].Nonce, qt.Equals, "n2")
+
+	deliveries, err = s.Database.ListWebhookDeliveries(ctx, "other", 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(deliveries, qt.HasLen, 0)
+
+	n, err := s.Database.PruneDataset(ctx, db.DatasetWebhookDeliveries, time.Now().Add(time.Minute), 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(3))
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The states an access request may be in.
+const (
+	AccessRequestPending  = "pending"
+	AccessRequestApproved = "approved"
+	AccessRequestDenied   = "denied"
+)
+
+// An AccessRequest is a request made by a user for access to a model or
+// cloud, which is granted when the request is approved by one of the
+// administrators of the model or cloud.
+type AccessRequest struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// IdentityName is the name of the identity requesting access.
+	IdentityName string `gorm:"not null"`
+
+	// TargetTag is the tag of the model or cloud access is requested
+	// for.
+	TargetTag string `gorm:"not null"`
+
+	// Access is the requested access level.
+	Access string `gorm:"not null"`
+
+	// Reason is the reason given by the user for requesting access.
+	Reason string
+
+	// Status is the state of the request.
+	Status string `gorm:"not null"`
+
+	// ReviewerName is the name of the identity that approved or denied
+	// the request.
+	ReviewerName string
+
+	// ReviewComment is the comment given when the request was approved
+	// or denied.
+	ReviewComment string
+
+	// ReviewedAt is the time the request was approved or denied.
+	ReviewedAt sql.NullTime
+}
+
+// ToAPIAccessRequest converts an access request to the JIMM API
+// representation.
+func (r AccessRequest) ToAPIAccessRequest() apiparams.AccessRequest {
+	ar := apiparams.AccessRequest{
+		ID:            r.ID,
+		User:          r.IdentityName,
+		Target:        r.TargetTag,
+		Access:        r.Access,
+		Reason:        r.Reason,
+		Status:        r.Status,
+		Reviewer:      r.ReviewerName,
+		ReviewComment: r.ReviewComment,
+		CreatedAt:     r.CreatedAt,
+	}
+	if r.ReviewedAt.Valid {
+		ar.ReviewedAt = &r.ReviewedAt.Time
+	}
+	return ar
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/juju/charm/v12"
+)
+
+// An ApplicationCharm records the charm used by an application in a
+// model, as reported by the model's controller.
+type ApplicationCharm struct {
+	// ModelID is the ID of the model containing the application.
+	ModelID uint `gorm:"primaryKey;autoIncrement:false"`
+	Model   Model
+
+	// ApplicationName is the name of the application.
+	ApplicationName string `gorm:"primaryKey"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// CharmURL is the URL of the charm used by the application.
+	CharmURL string
+
+	// Exposed records whether the application is exposed.
+	Exposed bool
+}
+
+// Charm returns the parsed charm URL of the application. If the charm
+// URL cannot be parsed nil is returned.
+func (a ApplicationCharm) Charm() *charm.URL {
+	u, err := charm.ParseURL(a.CharmURL)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// An ApplicationEndpoint records an endpoint of an application in a
+// model, as seen in the relations reported by the model's controller.
+type ApplicationEndpoint struct {
+	// ModelID is the ID of the model containing the application.
+	ModelID uint `gorm:"primaryKey;autoIncrement:false"`
+	Model   Model
+
+	// ApplicationName is the name of the application.
+	ApplicationName string `gorm:"primaryKey"`
+
+	// Name is the name of the endpoint.
+	Name string `gorm:"primaryKey"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Role is the role of the endpoint, "provider", "requirer" or
+	// "peer".
+	Role string
+
+	// Interface is the interface of the endpoint, for example
+	// "postgresql_client".
+	Interface string
+}
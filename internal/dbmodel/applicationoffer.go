@@ -49,6 +49,19 @@ type ApplicationOffer struct {
 
 	// CharmURL is the URL of the charm deployed to the application.
 	CharmURL string `gorm:"column:charm_url"`
+
+	// TotalConnectedCount is the number of relations to the offer, as
+	// last reported by the offering model's controller.
+	TotalConnectedCount int
+
+	// ActiveConnectedCount is the number of active relations to the
+	// offer, as last reported by the offering model's controller.
+	ActiveConnectedCount int
+
+	// ConsumerLimit is the maximum number of models that may consume
+	// the offer, set by an offer administrator. If this is zero the
+	// number of consumers is only limited by JIMM's policy.
+	ConsumerLimit int
 }
 
 // Tag returns a names.Tag for the application-offer.
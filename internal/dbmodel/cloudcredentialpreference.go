@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A DefaultCloudCredential records the cloud credential an identity has
+// chosen to use by default when creating models on a cloud.
+type DefaultCloudCredential struct {
+	// IdentityName is the name of the identity the default belongs
+	// to.
+	IdentityName string `gorm:"primaryKey"`
+
+	// CloudName is the name of the cloud the default applies to.
+	CloudName string `gorm:"primaryKey"`
+
+	UpdatedAt time.Time
+
+	// CloudCredential is the default credential.
+	CloudCredentialID uint
+	CloudCredential   CloudCredential
+}
+
+// A RecentCloudCredential records the cloud credential an identity last
+// used to create a model in a cloud region.
+type RecentCloudCredential struct {
+	// IdentityName is the name of the identity that created the
+	// model.
+	IdentityName string `gorm:"primaryKey"`
+
+	// CloudName is the name of the cloud hosting the model.
+	CloudName string `gorm:"primaryKey"`
+
+	// RegionName is the name of the cloud region hosting the model.
+	RegionName string `gorm:"primaryKey"`
+
+	UpdatedAt time.Time
+
+	// CloudCredential is the credential last used.
+	CloudCredentialID uint
+	CloudCredential   CloudCredential
+}
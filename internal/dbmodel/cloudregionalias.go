@@ -0,0 +1,22 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A CloudRegionAlias records the old name of a cloud region that has been
+// remapped, for example because the cloud provider renamed the region.
+// Requests using the old name are handled as if they used the new name.
+type CloudRegionAlias struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// CloudName is the name of the cloud the region belongs to.
+	CloudName string
+
+	// Alias is the old name of the region.
+	Alias string
+
+	// RegionName is the current name of the region.
+	RegionName string
+}
@@ -5,6 +5,7 @@ package dbmodel
 import (
 	"database/sql"
 	"net"
+	"net/url"
 	"strconv"
 	"time"
 
@@ -54,6 +55,51 @@ type Controller struct {
 	// Useful for local dev to avoid TLS issues.
 	TLSHostname string `gorm:"column:tls_hostname"`
 
+	// TLSSystemCAFallback determines whether certificates signed by the
+	// system CAs are accepted from the controller in addition to those
+	// signed by CACertificate. If this is false and CACertificate is set
+	// the controller's certificate must be signed by CACertificate.
+	TLSSystemCAFallback bool `gorm:"column:tls_system_ca_fallback"`
+
+	// TLSMinVersion is the minimum TLS version, either "1.2" or "1.3",
+	// accepted when connecting to the controller. If this is empty
+	// TLS 1.2 is the minimum.
+	TLSMinVersion string `gorm:"column:tls_min_version"`
+
+	// CertificateExpiry records the expiry time of the certificate
+	// presented by the controller the last time it was dialed by the
+	// watcher.
+	CertificateExpiry sql.NullTime
+
+	// ProxyURL is the URL of a SOCKS5 or HTTP CONNECT proxy through
+	// which connections to the controller are made. If this is empty
+	// the controller is dialed directly.
+	ProxyURL string `gorm:"column:proxy_url"`
+
+	// SSHJumpHost is the address, in the form user@host[:port], of an
+	// SSH host through which connections to the controller are
+	// tunnelled. If this is empty no jump host is used.
+	SSHJumpHost string `gorm:"column:ssh_jump_host"`
+
+	// SSHJumpHostKey is the public key, in authorized_keys format,
+	// expected from the SSH jump host.
+	SSHJumpHostKey string `gorm:"column:ssh_jump_host_key"`
+
+	// DialTimeout is the maximum time allowed to establish a connection
+	// to the controller. If this is zero no timeout is applied beyond
+	// that of the operating system.
+	DialTimeout time.Duration
+
+	// RPCTimeout is the maximum time JIMM waits for a response to the
+	// login and keepalive requests it sends to the controller. If this
+	// is zero a default timeout is used.
+	RPCTimeout time.Duration `gorm:"column:rpc_timeout"`
+
+	// KeepaliveInterval is the interval at which JIMM checks that its
+	// connections to the controller are still alive. If this is zero a
+	// default interval is used.
+	KeepaliveInterval time.Duration
+
 	// CloudName is the name of the cloud which is hosting this
 	// controller.
 	CloudName string
@@ -66,18 +112,50 @@ type Controller struct {
 	// therefore no new models or clouds will be added to the controller.
 	Deprecated bool `gorm:"not null;default:FALSE"`
 
+	// Tiers holds the tiers, such as "production" or "gpu", the
+	// controller belongs to. Models requesting a tier are only placed on
+	// controllers in that tier.
+	Tiers Strings
+
 	// AgentVersion holds the string representation of the controller's
 	// agent version.
 	AgentVersion string
 
 	// Addresses holds the known addresses on which the controller is
-	// listening.
+	// listening. These are the addresses JIMM dials.
 	Addresses HostPorts
 
+	// ClientAddresses holds the addresses given to clients that connect
+	// to the controller directly, for example when redirected to the
+	// controller, if they differ from Addresses. This allows JIMM to
+	// reach the controller over a private network while clients are
+	// given public endpoints. If this is empty clients are given
+	// Addresses.
+	ClientAddresses HostPorts
+
 	// UnavailableSince records the time that this controller became
 	// unavailable, if it has.
 	UnavailableSince sql.NullTime
 
+	// UnavailableReason categorises why the controller is unavailable,
+	// if it is.
+	UnavailableReason UnavailableReason
+
+	// UnavailableError holds the error JIMM encountered connecting to
+	// the controller when it was last found to be unavailable.
+	UnavailableError string
+
+	// MaintenanceSince records the time that this controller entered
+	// maintenance, if it has. Connections proxied to a controller in
+	// maintenance are drained and no new connections are made.
+	MaintenanceSince sql.NullTime
+
+	// ServiceUserPending records that JIMM failed to provision its
+	// service user on the controller when the controller was added, so
+	// JIMM still connects with the credentials the controller was added
+	// with until the service user is provisioned.
+	ServiceUserPending bool `gorm:"not null;default:FALSE"`
+
 	// CloudRegions is the set of cloud-regions that are available on this
 	// controller.
 	CloudRegions []CloudRegionControllerPriority
@@ -88,6 +166,49 @@ type Controller struct {
 	// TODO(mhilton) Save controller statistics?
 }
 
+// An UnavailableReason categorises why a controller is unavailable.
+type UnavailableReason string
+
+const (
+	// UnavailableDialTimeout means JIMM timed out connecting to the
+	// controller, usually a network problem.
+	UnavailableDialTimeout UnavailableReason = "dial-timeout"
+
+	// UnavailableAuthFailure means the controller rejected JIMM's
+	// credentials.
+	UnavailableAuthFailure UnavailableReason = "auth-failure"
+
+	// UnavailableTLSError means the TLS handshake with the controller
+	// failed, for example because its certificate could not be verified.
+	UnavailableTLSError UnavailableReason = "tls-error"
+
+	// UnavailableAPIIncompatible means the controller does not support
+	// the API JIMM requires.
+	UnavailableAPIIncompatible UnavailableReason = "api-incompatible"
+
+	// UnavailableUnknown means the failure could not be categorised.
+	UnavailableUnknown UnavailableReason = "unknown"
+)
+
+// SetUnavailableAt records that the controller has been unavailable
+// since the given time because of the given error, categorised as
+// reason.
+func (c *Controller) SetUnavailableAt(t sql.NullTime, reason UnavailableReason, err error) {
+	c.UnavailableSince = t
+	c.UnavailableReason = reason
+	c.UnavailableError = ""
+	if err != nil {
+		c.UnavailableError = err.Error()
+	}
+}
+
+// SetAvailable records that the controller is available.
+func (c *Controller) SetAvailable() {
+	c.UnavailableSince = sql.NullTime{}
+	c.UnavailableReason = ""
+	c.UnavailableError = ""
+}
+
 // Tag returns a names.Tag for this controller.
 func (c Controller) Tag() names.Tag {
 	return c.ResourceTag()
@@ -119,17 +240,47 @@ func (c Controller) ToAPIControllerInfo() apiparams.ControllerInfo {
 			ci.APIAddresses = append(ci.APIAddresses, net.JoinHostPort(hp.Value, strconv.Itoa(hp.Port)))
 		}
 	}
+	for _, hps := range c.ClientAddresses {
+		for _, hp := range hps {
+			ci.ClientAddresses = append(ci.ClientAddresses, net.JoinHostPort(hp.Value, strconv.Itoa(hp.Port)))
+		}
+	}
 	ci.CACertificate = c.CACertificate
+	ci.ProxyURL = redactProxyURL(c.ProxyURL)
+	ci.SSHJumpHost = c.SSHJumpHost
+	if c.DialTimeout > 0 {
+		ci.DialTimeout = c.DialTimeout.String()
+	}
+	if c.RPCTimeout > 0 {
+		ci.RPCTimeout = c.RPCTimeout.String()
+	}
+	if c.KeepaliveInterval > 0 {
+		ci.KeepaliveInterval = c.KeepaliveInterval.String()
+	}
+	ci.Tiers = c.Tiers
+	ci.TLSMinVersion = c.TLSMinVersion
+	ci.TLSSystemCAFallback = c.TLSSystemCAFallback
+	if c.CertificateExpiry.Valid {
+		ci.CertificateExpiry = &c.CertificateExpiry.Time
+	}
 	ci.CloudTag = names.NewCloudTag(c.CloudName).String()
 	ci.CloudRegion = c.CloudRegion
 	ci.Username = c.AdminIdentityName
 	ci.AgentVersion = c.AgentVersion
+	ci.ServiceUserPending = c.ServiceUserPending
 	switch {
 	case c.UnavailableSince.Valid:
 		ci.Status = jujuparams.EntityStatus{
 			Status: "unavailable",
+			Info:   c.UnavailableError,
 			Since:  &c.UnavailableSince.Time,
 		}
+		ci.UnavailableReason = string(c.UnavailableReason)
+	case c.MaintenanceSince.Valid:
+		ci.Status = jujuparams.EntityStatus{
+			Status: "maintenance",
+			Since:  &c.MaintenanceSince.Time,
+		}
 	case c.Deprecated:
 		ci.Status = jujuparams.EntityStatus{
 			Status: "deprecated",
@@ -143,7 +294,8 @@ func (c Controller) ToAPIControllerInfo() apiparams.ControllerInfo {
 }
 
 // ToJujuRedirectInfoResult converts a controller entry to a juju
-// RedirectInfoResult value.
+// RedirectInfoResult value. Clients are given the controller's
+// ClientAddresses if any are set, otherwise its Addresses.
 func (c Controller) ToJujuRedirectInfoResult() jujuparams.RedirectInfoResult {
 	var servers [][]jujuparams.HostPort
 	host, port, err := net.SplitHostPort(c.PublicAddress)
@@ -160,13 +312,23 @@ func (c Controller) ToJujuRedirectInfoResult() jujuparams.RedirectInfoResult {
 			}})
 		}
 	}
-	servers = append(servers, [][]jujuparams.HostPort(c.Addresses)...)
+	servers = append(servers, [][]jujuparams.HostPort(c.ClientHostPorts())...)
 	return jujuparams.RedirectInfoResult{
 		Servers: servers,
 		CACert:  c.CACertificate,
 	}
 }
 
+// ClientHostPorts returns the addresses on which clients may connect to
+// the controller directly. This is the ClientAddresses, if any are set,
+// otherwise the Addresses.
+func (c Controller) ClientHostPorts() HostPorts {
+	if len(c.ClientAddresses) > 0 {
+		return c.ClientAddresses
+	}
+	return c.Addresses
+}
+
 const (
 	// CloudRegionControllerPriorityDeployed is the priority given to the
 	// controller when deploying to a cloud region to which the controller
@@ -207,3 +369,18 @@ type ControllerConfig struct {
 	// Config stores the controller configuration
 	Config Map
 }
+
+// redactProxyURL removes any credentials from the given proxy URL so
+// that they are not returned to clients. A URL that cannot be parsed is
+// not returned at all.
+func redactProxyURL(s string) string {
+	if s == "" {
+		return ""
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// A ControllerBootstrapProfile is a versioned record of the configuration
+// of a controller and the definition of the cloud it was bootstrapped
+// on. If a controller is lost an equivalent controller may be
+// re-bootstrapped from its latest profile. A new version is recorded
+// only when the captured configuration changes.
+type ControllerBootstrapProfile struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// ControllerName is the name of the controller.
+	ControllerName string
+
+	// Version is the version of the profile, starting at 1 and
+	// incremented for each change.
+	Version int
+
+	// AgentVersion is the version of juju the controller was running.
+	AgentVersion string
+
+	// CloudName and CloudRegion are the cloud and region hosting the
+	// controller.
+	CloudName   string
+	CloudRegion string
+
+	// ControllerConfig holds the controller's configuration, as reported
+	// by the controller.
+	ControllerConfig Map
+
+	// Cloud holds the definition of the cloud hosting the controller,
+	// encoded as JSON.
+	Cloud JSON
+
+	// Hash is the hash of the captured configuration, used to detect
+	// changes.
+	Hash string
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ControllerModelCredential records the cloud credential used by a
+// controller's own controller model, usually the credential the
+// controller was bootstrapped with. If this credential expires the
+// controller can no longer manage cloud resources, so JIMM tracks it and
+// warns before it expires.
+type ControllerModelCredential struct {
+	// ControllerName is the name of the controller.
+	ControllerName string `gorm:"primaryKey"`
+	UpdatedAt      time.Time
+
+	// CredentialTag is the tag of the cloud credential used by the
+	// controller model, as last reported by the controller.
+	CredentialTag string
+
+	// Valid records whether the controller considers the credential to
+	// be valid, if known.
+	Valid sql.NullBool
+
+	// CheckedAt is the time the credential was last read from the
+	// controller.
+	CheckedAt sql.NullTime
+
+	// ExpiresAt is the time the credential expires, as recorded by a
+	// JIMM administrator.
+	ExpiresAt sql.NullTime
+
+	// RotatedAt is the time the credential was last rotated through
+	// JIMM.
+	RotatedAt sql.NullTime
+}
+
+// ToAPIControllerModelCredential converts a controller model credential
+// to its API representation.
+func (c ControllerModelCredential) ToAPIControllerModelCredential() apiparams.ControllerModelCredential {
+	cred := apiparams.ControllerModelCredential{
+		Controller: c.ControllerName,
+		Credential: c.CredentialTag,
+	}
+	if c.Valid.Valid {
+		valid := c.Valid.Bool
+		cred.Valid = &valid
+	}
+	if c.CheckedAt.Valid {
+		t := c.CheckedAt.Time
+		cred.CheckedAt = &t
+	}
+	if c.ExpiresAt.Valid {
+		t := c.ExpiresAt.Time
+		cred.ExpiresAt = &t
+	}
+	if c.RotatedAt.Valid {
+		t := c.RotatedAt.Time
+		cred.RotatedAt = &t
+	}
+	return cred
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A ControllerMonitor records the JIMM replica monitoring a controller.
+// The replica's watcher claims the controller with a nonce unique to the
+// watcher and checks the nonce is unchanged before applying updates, so
+// that should two replicas both believe they are responsible for the
+// controller only one of them writes to the database.
+type ControllerMonitor struct {
+	// ControllerName is the name of the monitored controller.
+	ControllerName string `gorm:"primaryKey"`
+
+	// Holder identifies the JIMM replica monitoring the controller.
+	Holder string
+
+	// Nonce is the ownership nonce of the watcher monitoring the
+	// controller.
+	Nonce string
+
+	// ClaimedAt is the time the watcher claimed the controller.
+	ClaimedAt time.Time
+
+	// Heartbeat is the time the watcher last confirmed that it is
+	// monitoring the controller. Heartbeats use the database clock so
+	// that they are unaffected by clock skew between replicas.
+	Heartbeat time.Time
+}
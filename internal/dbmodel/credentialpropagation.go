@@ -0,0 +1,94 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A CredentialPropagation records that a cloud credential has been copied
+// to a controller, so that the distribution of credentials can be
+// audited. The credential and controller are recorded by name so that
+// the record outlives both.
+type CredentialPropagation struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Credential is the path of the cloud credential, in the form
+	// cloud/owner/name.
+	Credential string
+
+	// ControllerName is the name of the controller the credential was
+	// copied to.
+	ControllerName string
+
+	// ControllerUUID is the UUID of the controller the credential was
+	// copied to.
+	ControllerUUID string
+
+	// PropagatedAt is the time the credential was last copied to the
+	// controller.
+	PropagatedAt time.Time
+
+	// JIMMVersion is the version of JIMM that last copied the
+	// credential to the controller.
+	JIMMVersion string
+
+	// RevokedAt is the time the credential was revoked, if it has been.
+	RevokedAt sql.NullTime
+
+	// RemovalConfirmedAt is the time the controller confirmed that its
+	// copy of the revoked credential was removed, if it has.
+	RemovalConfirmedAt sql.NullTime
+
+	// VerificationAttempts is the number of times the removal of the
+	// revoked credential from the controller has been verified without
+	// being confirmed.
+	VerificationAttempts int
+
+	// LastVerifiedAt is the time the removal of the revoked credential
+	// was last verified, if it has been.
+	LastVerifiedAt sql.NullTime
+
+	// VerificationError holds the reason the last verification did not
+	// confirm the removal of the revoked credential.
+	VerificationError string
+
+	// AlertedAt is the time an alert was sent because the removal of
+	// the revoked credential was not confirmed in time, if one has been.
+	AlertedAt sql.NullTime
+}
+
+// ToAPICredentialPropagation converts a credential propagation to its API
+// representation.
+func (p CredentialPropagation) ToAPICredentialPropagation() apiparams.CredentialPropagation {
+	cp := apiparams.CredentialPropagation{
+		CredentialTag:  names.NewCloudCredentialTag(p.Credential).String(),
+		Controller:     p.ControllerName,
+		ControllerUUID: p.ControllerUUID,
+		PropagatedAt:   p.PropagatedAt,
+		JIMMVersion:    p.JIMMVersion,
+
+		VerificationAttempts: p.VerificationAttempts,
+		VerificationError:    p.VerificationError,
+	}
+	if p.RevokedAt.Valid {
+		t := p.RevokedAt.Time
+		cp.RevokedAt = &t
+	}
+	if p.RemovalConfirmedAt.Valid {
+		t := p.RemovalConfirmedAt.Time
+		cp.RemovalConfirmedAt = &t
+	}
+	if p.LastVerifiedAt.Valid {
+		t := p.LastVerifiedAt.Time
+		cp.LastVerifiedAt = &t
+	}
+	return cp
+}
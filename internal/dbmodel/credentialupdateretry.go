@@ -0,0 +1,33 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// A CredentialUpdateRetry queues the update of a cloud credential on a
+// controller that failed, so that it can be retried.
+type CredentialUpdateRetry struct {
+	// CloudCredentialID is the ID of the cloud credential to update.
+	CloudCredentialID uint `gorm:"primaryKey;autoIncrement:false"`
+	CloudCredential   CloudCredential
+
+	// ControllerID is the ID of the controller to update the credential
+	// on.
+	ControllerID uint `gorm:"primaryKey;autoIncrement:false"`
+	Controller   Controller
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Attempts is the number of times the update has failed.
+	Attempts int
+
+	// LastError is the error from the most recent failed update.
+	LastError string
+
+	// NextAttempt is the time at which the update should next be
+	// attempted.
+	NextAttempt time.Time
+}
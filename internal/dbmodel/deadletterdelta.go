@@ -0,0 +1,43 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// A DeadLetterDelta is a watcher delta that JIMM repeatedly failed to
+// apply to its database. Dead-lettered deltas are recorded so that they
+// can be investigated, and the watcher continues with the following
+// deltas.
+type DeadLetterDelta struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// ControllerName is the name of the controller the delta was received
+	// from.
+	ControllerName string
+
+	// ModelUUID is the UUID of the model the delta applies to.
+	ModelUUID string
+
+	// Kind is the kind of entity the delta describes, for example
+	// "machine" or "unit".
+	Kind string
+
+	// EntityID is the ID of the entity within the model.
+	EntityID string
+
+	// Removed is true if the delta describes the removal of the entity.
+	Removed bool
+
+	// Delta holds the JSON encoding of the delta.
+	Delta JSON
+
+	// Error holds the error returned by the last attempt to apply the
+	// delta.
+	Error string
+
+	// Attempts is the number of times JIMM attempted to apply the delta.
+	Attempts int
+}
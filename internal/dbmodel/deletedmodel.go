@@ -0,0 +1,99 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A DeletedModel records a model that has been removed from JIMM. The
+// record holds enough of the model's metadata for the model to be
+// inspected, and restored, until the record is pruned.
+type DeletedModel struct {
+	ID uint `gorm:"primarykey"`
+
+	// DeletedAt is the time the model was removed.
+	DeletedAt time.Time
+
+	// ModelUUID is the UUID of the removed model.
+	ModelUUID string
+
+	// Name is the name of the removed model.
+	Name string
+
+	// OwnerIdentityName is the name of the identity that owned the
+	// model.
+	OwnerIdentityName string
+
+	// ControllerName is the name of the controller that hosted the
+	// model.
+	ControllerName string
+
+	// CloudName and CloudRegionName identify the cloud region that
+	// hosted the model.
+	CloudName       string
+	CloudRegionName string
+
+	// CloudCredentialName is the name of the cloud credential used by
+	// the model, in the form <cloud>/<owner>/<name>.
+	CloudCredentialName string
+
+	// Type is the type of the model.
+	Type string
+
+	// Life is the life status of the model when it was removed.
+	Life string
+
+	// CostCenter is the cost center the model was attributed to.
+	CostCenter string
+
+	// OriginKind is the kind of origin of the model, see ModelOrigin.
+	OriginKind string
+
+	// ModelCreatedAt is the time the model was added to JIMM.
+	ModelCreatedAt time.Time
+}
+
+// NewDeletedModel returns a DeletedModel recording the given model, which
+// must have its Controller, CloudRegion and CloudCredential loaded, as
+// having been removed at the given time.
+func NewDeletedModel(m *Model, deletedAt time.Time) *DeletedModel {
+	return &DeletedModel{
+		DeletedAt:           deletedAt,
+		ModelUUID:           m.UUID.String,
+		Name:                m.Name,
+		OwnerIdentityName:   m.OwnerIdentityName,
+		ControllerName:      m.Controller.Name,
+		CloudName:           m.CloudRegion.CloudName,
+		CloudRegionName:     m.CloudRegion.Name,
+		CloudCredentialName: m.CloudCredential.Path(),
+		Type:                m.Type,
+		Life:                m.Life,
+		CostCenter:          m.CostCenter,
+		OriginKind:          m.Origin.Kind,
+		ModelCreatedAt:      m.CreatedAt,
+	}
+}
+
+// ToAPIDeletedModel converts a deleted model to its API representation.
+func (d DeletedModel) ToAPIDeletedModel() apiparams.DeletedModel {
+	return apiparams.DeletedModel{
+		ModelTag:        names.NewModelTag(d.ModelUUID).String(),
+		Name:            d.Name,
+		Owner:           d.OwnerIdentityName,
+		Controller:      d.ControllerName,
+		Cloud:           d.CloudName,
+		CloudRegion:     d.CloudRegionName,
+		CloudCredential: d.CloudCredentialName,
+		Type:            d.Type,
+		Life:            d.Life,
+		CostCenter:      d.CostCenter,
+		Origin:          d.OriginKind,
+		CreatedAt:       d.ModelCreatedAt,
+		DeletedAt:       d.DeletedAt,
+	}
+}
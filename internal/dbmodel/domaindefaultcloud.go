@@ -0,0 +1,51 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"strings"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A DomainDefaultCloud holds the cloud, and optionally the region, used
+// for the models of identities in an identity domain when a model is
+// created without specifying a cloud or region.
+type DomainDefaultCloud struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Domain is the identity domain, the part of identity names
+	// following the "@", for example "canonical.com".
+	Domain string `gorm:"uniqueIndex"`
+
+	// CloudName is the name of the default cloud.
+	CloudName string
+
+	// RegionName is the name of the default region in the cloud. If
+	// this is empty the region is chosen as if there were no default.
+	RegionName string
+}
+
+// IdentityDomain returns the domain of the named identity, the part of
+// the name following the last "@". If the name has no domain an empty
+// string is returned.
+func IdentityDomain(name string) string {
+	i := strings.LastIndex(name, "@")
+	if i < 0 {
+		return ""
+	}
+	return name[i+1:]
+}
+
+// ToAPIDomainDefaultCloud converts a domain default cloud to the JIMM API
+// representation.
+func (d DomainDefaultCloud) ToAPIDomainDefaultCloud() apiparams.DomainDefaultCloud {
+	return apiparams.DomainDefaultCloud{
+		Domain: d.Domain,
+		Cloud:  d.CloudName,
+		Region: d.RegionName,
+	}
+}
@@ -21,6 +21,11 @@ type GroupEntry struct {
 
 	// UUID holds the uuid of the group.
 	UUID string `gotm:"index;column:uuid"`
+
+	// CostCenter is the cost center the models owned by members of the
+	// group are attributed to, if neither the model nor its owner has a
+	// cost center.
+	CostCenter string `gorm:"not null;default:''"`
 }
 
 // ToAPIGroup converts a group entry to a JIMM API
@@ -31,6 +36,7 @@ func (g GroupEntry) ToAPIGroupEntry() apiparams.Group {
 	group.Name = g.Name
 	group.CreatedAt = g.CreatedAt.Format(time.RFC3339)
 	group.UpdatedAt = g.UpdatedAt.Format(time.RFC3339)
+	group.CostCenter = g.CostCenter
 	return group
 }
 
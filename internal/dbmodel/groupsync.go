@@ -0,0 +1,36 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// A GroupSyncStatus records the outcome of the most recent
+// synchronisation of group memberships from an external directory.
+type GroupSyncStatus struct {
+	// Directory is the name of the directory groups were synchronised
+	// from.
+	Directory string `gorm:"primaryKey"`
+	UpdatedAt time.Time
+
+	// Time is the time the synchronisation started.
+	Time time.Time
+
+	// CreatedGroups holds the names of the groups created by the
+	// synchronisation.
+	CreatedGroups Strings
+
+	// Added and Removed are the number of group memberships added and
+	// removed by the synchronisation.
+	Added   int
+	Removed int
+
+	// Errors holds the problems encountered synchronising individual
+	// groups and members, these do not stop the rest of the
+	// synchronisation.
+	Errors Strings
+
+	// Error holds the error that stopped the synchronisation, if any.
+	Error string
+}
@@ -0,0 +1,45 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// An IdempotencyKey records a request made with a client-supplied
+// idempotency key, and the response to it, so that a retried request is
+// answered with the original response instead of being applied again.
+type IdempotencyKey struct {
+	ID uint `gorm:"primaryKey"`
+
+	// CreatedAt is the time the request was first received.
+	CreatedAt time.Time
+
+	// ExpiresAt is the time after which the key may be reused.
+	ExpiresAt time.Time
+
+	// IdentityName is the name of the identity that made the request,
+	// keys are unique for each identity.
+	IdentityName string
+
+	// Key is the client-supplied idempotency key.
+	Key string
+
+	// RequestHash is a hash of the request, a key may only be reused
+	// for identical requests.
+	RequestHash string
+
+	// Completed is true once the response to the request has been
+	// recorded. A key that is not completed belongs to a request that
+	// is still being processed.
+	Completed bool
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// ContentType is the content type of the response.
+	ContentType string
+
+	// Body is the body of the response.
+	Body []byte
+}
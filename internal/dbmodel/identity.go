@@ -21,13 +21,14 @@ var (
 	IdentityCreationError = errors.New("identity name cannot be empty")
 )
 
-// NewIdentity returns an Identity with the Name and DisplayName fields set.
+// NewIdentity returns an Identity with the Name and DisplayName fields
+// set. The name is normalized, see SetIdentityNormalizer.
 func NewIdentity(name string) (*Identity, error) {
 	if name == "" {
 		return nil, IdentityCreationError
 	}
 	i := &Identity{
-		Name: name,
+		Name: NormalizeIdentityName(name),
 	}
 	i.santiseIdentityId()
 	i.setDisplayName()
@@ -52,6 +53,11 @@ type Identity struct {
 	// authenticated at least once.
 	LastLogin sql.NullTime
 
+	// LastActivity is the time the identity last made an API request to
+	// the JIMM server. LastActivity will only be a valid time if the
+	// identity has made a request since activity has been recorded.
+	LastActivity sql.NullTime
+
 	// Disabled records whether the identity has been disabled or not, disabled
 	// identities are not allowed to authenticate.
 	Disabled bool `gorm:"not null;default:FALSE"`
@@ -81,6 +87,22 @@ type Identity struct {
 
 	// AccessTokenType is the type for the token, typically bearer.
 	AccessTokenType string
+
+	// TOTPSecret is the base32 encoded secret of the identity's TOTP
+	// authenticator, used to confirm the identity before sensitive
+	// operations. The secret is encrypted if the database has an
+	// encryption key configured, see db.Database.OpenIdentityTOTPSecret.
+	// This is empty if the identity has not enrolled an authenticator.
+	TOTPSecret string `gorm:"column:totp_secret;not null;default:''"`
+
+	// TOTPLastStep is the last TOTP time step accepted from the
+	// identity's authenticator. Codes for this or earlier steps are
+	// rejected so that a code cannot be replayed.
+	TOTPLastStep int64 `gorm:"column:totp_last_step;not null;default:0"`
+
+	// CostCenter is the cost center the models owned by the identity
+	// are attributed to, if the model has no cost center of its own.
+	CostCenter string `gorm:"not null;default:''"`
 }
 
 // Tag returns a names.Tag for the identity.
@@ -96,9 +118,10 @@ func (i Identity) ResourceTag() names.UserTag {
 	return names.NewUserTag(i.Name)
 }
 
-// SetTag sets the identity name of the identity to the value from the given tag.
+// SetTag sets the identity name of the identity to the normalized value
+// from the given tag.
 func (i *Identity) SetTag(t names.UserTag) {
-	i.Name = t.Id()
+	i.Name = NormalizeIdentityName(t.Id())
 }
 
 // ToJujuUserInfo converts an Identity into a juju UserInfo value.
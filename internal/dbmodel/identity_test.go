@@ -197,3 +197,49 @@ func TestNewIdentity(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentityNormalizer(t *testing.T) {
+	c := qt.New(t)
+
+	n := dbmodel.IdentityNormalizer{
+		CaseFold: true,
+		DomainAliases: map[string]string{
+			"external":    "Canonical.com",
+			"ubuntu.com":  "canonical.com",
+			"example.org": "example.com",
+		},
+	}
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{"Bob@Canonical.com", "bob@canonical.com"},
+		{"bob@external", "bob@canonical.com"},
+		{"Bob@Ubuntu.COM", "bob@canonical.com"},
+		{"alice@example.org", "alice@example.com"},
+		{"alice@other.org", "alice@other.org"},
+		{"Admin", "admin"},
+		{"FCA1F605-736E-4D1F-BCD2-AECC726923BE@serviceaccount", "fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount"},
+	}
+	for _, test := range tests {
+		c.Check(n.Normalize(test.name), qt.Equals, test.expect, qt.Commentf("%s", test.name))
+	}
+
+	n.CaseFold = false
+	c.Check(n.Normalize("Bob@External"), qt.Equals, "Bob@Canonical.com")
+
+	dbmodel.SetIdentityNormalizer(&dbmodel.IdentityNormalizer{
+		CaseFold:      true,
+		DomainAliases: map[string]string{"external": "canonical.com"},
+	})
+	c.Cleanup(func() { dbmodel.SetIdentityNormalizer(nil) })
+
+	i, err := dbmodel.NewIdentity("Bob@external")
+	c.Assert(err, qt.IsNil)
+	c.Check(i.Name, qt.Equals, "bob@canonical.com")
+	c.Check(i.DisplayName, qt.Equals, "bob")
+
+	var i2 dbmodel.Identity
+	i2.SetTag(names.NewUserTag("Alice@external"))
+	c.Check(i2.Name, qt.Equals, "alice@canonical.com")
+}
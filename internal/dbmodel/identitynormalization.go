@@ -0,0 +1,65 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// An IdentityNormalizer normalizes the names of incoming identities so
+// that an identity is known by the same name however it is presented,
+// for example after an organisation moves to a new identity provider.
+type IdentityNormalizer struct {
+	// CaseFold, if set, folds identity names to lower case.
+	CaseFold bool
+
+	// DomainAliases maps the domains of identity names to the domain
+	// they are an alias of. Domains are matched without regard to case.
+	// Legacy external users are mapped to a new domain with an alias
+	// for the "external" domain.
+	DomainAliases map[string]string
+}
+
+// Normalize returns the normalized form of the given identity name. Names
+// without a domain, such as local users, only have their case folded.
+func (n IdentityNormalizer) Normalize(name string) string {
+	if n.CaseFold {
+		name = strings.ToLower(name)
+	}
+	idx := strings.LastIndex(name, "@")
+	if idx < 0 {
+		return name
+	}
+	domain := name[idx+1:]
+	for alias, target := range n.DomainAliases {
+		if strings.EqualFold(domain, alias) {
+			if n.CaseFold {
+				target = strings.ToLower(target)
+			}
+			return name[:idx+1] + target
+		}
+	}
+	return name
+}
+
+// identityNormalizer holds the normalizer applied to all identity names,
+// nil if names are not normalized.
+var identityNormalizer atomic.Pointer[IdentityNormalizer]
+
+// SetIdentityNormalizer sets the normalizer applied to the names of all
+// identities created with NewIdentity or SetTag. A nil normalizer leaves
+// the names unchanged.
+func SetIdentityNormalizer(n *IdentityNormalizer) {
+	identityNormalizer.Store(n)
+}
+
+// NormalizeIdentityName returns the given identity name normalized with
+// the normalizer set with SetIdentityNormalizer.
+func NormalizeIdentityName(name string) string {
+	n := identityNormalizer.Load()
+	if n == nil {
+		return name
+	}
+	return n.Normalize(name)
+}
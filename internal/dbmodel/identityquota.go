@@ -0,0 +1,29 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+)
+
+// An IdentityQuota holds the quota limits that override the default
+// limits for the models owned by an identity. A limit that is not valid
+// uses the default, a limit of zero means the resource is not limited.
+type IdentityQuota struct {
+	// IdentityName is the name of the identity the limits apply to.
+	IdentityName string `gorm:"primaryKey"`
+
+	UpdatedAt time.Time
+
+	// Models is the maximum number of models the identity may own.
+	Models sql.NullInt64
+
+	// Machines is the maximum total number of machines in the models
+	// the identity owns.
+	Machines sql.NullInt64
+
+	// Cores is the maximum total number of cores in the models the
+	// identity owns.
+	Cores sql.NullInt64
+}
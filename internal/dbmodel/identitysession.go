@@ -0,0 +1,28 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// An IdentitySession records an API session an identity has open on a
+// JIMM server. A session is added when the identity logs in and removed
+// when the connection is closed. Sessions left behind by a server that
+// stopped without closing its connections are removed once they have
+// been idle for too long.
+type IdentitySession struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// IdentityName is the name of the identity that opened the session.
+	IdentityName string `gorm:"not null"`
+
+	// LastActivity is the time the identity last made an API request in
+	// the session.
+	LastActivity time.Time `gorm:"not null"`
+
+	// RemoteAddress is the address the session's connection was made
+	// from.
+	RemoteAddress string `gorm:"not null;default:''"`
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+)
+
+// An IdentityTombstone records an identity that has been purged from
+// JIMM. The records that must be kept after the identity's personal data
+// is removed, such as audit log entries, refer to the tombstone's
+// anonymous name instead of the identity's name.
+type IdentityTombstone struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// Name is the anonymous name that replaces the purged identity's
+	// name.
+	Name string `gorm:"uniqueIndex"`
+
+	// PurgedBy is the name of the identity that purged the identity.
+	PurgedBy string
+}
+
+// ResourceTag returns the user tag used in place of the purged
+// identity's tag.
+func (t IdentityTombstone) ResourceTag() names.UserTag {
+	return names.NewUserTag(t.Name)
+}
@@ -0,0 +1,21 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A Lease is a time limited claim, held by a single JIMM replica, on a
+// named piece of work such as a background worker.
+type Lease struct {
+	// Name is the name of the lease.
+	Name string `gorm:"primaryKey"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Holder identifies the JIMM replica holding the lease.
+	Holder string
+
+	// Expires is the time at which the lease expires unless renewed.
+	Expires time.Time
+}
@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"sort"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+)
+
+// A Machine is a machine in a model. Only the details needed to audit
+// the placement and networking of the model are recorded.
+type Machine struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ModelID is the ID of the model the machine is in.
+	ModelID uint
+
+	// MachineID is the ID of the machine in the model.
+	MachineID string
+
+	// InstanceID is the ID of the machine's instance in the cloud.
+	InstanceID string
+
+	// Hostname is the hostname of the machine.
+	Hostname string
+
+	// Life holds the life status of the machine.
+	Life string
+
+	// AvailabilityZone is the availability zone the machine's instance
+	// is in, if the cloud supports availability zones.
+	AvailabilityZone string
+
+	// Addresses holds the network addresses of the machine.
+	Addresses Addresses
+
+	// Subnets holds the CIDRs of the subnets the machine's addresses
+	// are in.
+	Subnets Strings
+}
+
+// FromJujuMachineInfo updates the machine from the given MachineInfo.
+func (m *Machine) FromJujuMachineInfo(info jujuparams.MachineInfo) {
+	m.MachineID = info.Id
+	m.InstanceID = info.InstanceId
+	m.Hostname = info.Hostname
+	m.Life = string(info.Life)
+	m.AvailabilityZone = ""
+	if info.HardwareCharacteristics != nil && info.HardwareCharacteristics.AvailabilityZone != nil {
+		m.AvailabilityZone = *info.HardwareCharacteristics.AvailabilityZone
+	}
+	m.Addresses = Addresses(info.Addresses)
+
+	subnets := make(map[string]bool)
+	m.Subnets = Strings{}
+	for _, addr := range info.Addresses {
+		if addr.CIDR != "" && !subnets[addr.CIDR] {
+			subnets[addr.CIDR] = true
+			m.Subnets = append(m.Subnets, addr.CIDR)
+		}
+	}
+	sort.Strings(m.Subnets)
+}
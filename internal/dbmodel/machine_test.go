@@ -0,0 +1,56 @@
+// Copyright 2024 Canonical.
+
+package dbmodel_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/core/instance"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+)
+
+func TestMachineFromJujuMachineInfo(t *testing.T) {
+	c := qt.New(t)
+
+	zone := "zone-1"
+	addresses := []jujuparams.Address{{
+		Value: "10.0.1.4",
+		CIDR:  "10.0.1.0/24",
+	}, {
+		Value: "10.0.0.4",
+		CIDR:  "10.0.0.0/24",
+	}, {
+		Value: "10.0.0.5",
+		CIDR:  "10.0.0.0/24",
+	}, {
+		Value: "203.0.113.4",
+	}}
+	var m dbmodel.Machine
+	m.FromJujuMachineInfo(jujuparams.MachineInfo{
+		Id:         "0",
+		InstanceId: "i-0",
+		Hostname:   "juju-0",
+		Life:       "alive",
+		HardwareCharacteristics: &instance.HardwareCharacteristics{
+			AvailabilityZone: &zone,
+		},
+		Addresses: addresses,
+	})
+	c.Check(m, qt.DeepEquals, dbmodel.Machine{
+		MachineID:        "0",
+		InstanceID:       "i-0",
+		Hostname:         "juju-0",
+		Life:             "alive",
+		AvailabilityZone: "zone-1",
+		Addresses:        dbmodel.Addresses(addresses),
+		Subnets:          dbmodel.Strings{"10.0.0.0/24", "10.0.1.0/24"},
+	})
+
+	// A machine without hardware characteristics has no zone.
+	m.FromJujuMachineInfo(jujuparams.MachineInfo{Id: "0"})
+	c.Check(m.AvailabilityZone, qt.Equals, "")
+	c.Check(m.Subnets, qt.DeepEquals, dbmodel.Strings{})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/version/v2"
 
 	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // A Model is a juju model.
@@ -77,6 +78,52 @@ type Model struct {
 	// Units contains the count of machines in the model.
 	Units int64
 
+	// Containers contains the count of machines in the model that are
+	// containers, these are included in Machines.
+	Containers int64
+
+	// Relations contains the count of relations in the model.
+	Relations int64
+
+	// OfferCount contains the count of application offers made from
+	// the model, as reported by the controller.
+	OfferCount int64
+
+	// CostCenter is the cost center the resources used by the model are
+	// attributed to. If this is empty the cost center of the model's
+	// owner is used.
+	CostCenter string `gorm:"not null;default:''"`
+
+	// WorkloadStatus holds the most severe workload status of the units
+	// in the model. It is empty if the model has no units.
+	WorkloadStatus string
+
+	// UnhealthyUnits contains the count of units in the model with a
+	// workload status of error or blocked.
+	UnhealthyUnits int64
+
+	// ActiveUnits, BlockedUnits and ErrorUnits contain the counts of
+	// units in the model with a workload status of active, blocked and
+	// error respectively.
+	ActiveUnits  int64
+	BlockedUnits int64
+	ErrorUnits   int64
+
+	// AgentVersions holds the number of machine and unit agents in the
+	// model reporting each agent version, as seen by the watcher.
+	AgentVersions Int64Map
+
+	// Origin records how the model came to be managed by JIMM.
+	Origin ModelOrigin `gorm:"embedded;embeddedPrefix:origin_"`
+
+	// LastUnitChangeAt is the time the units of the model, or their
+	// workload status, last changed as seen by the watcher.
+	LastUnitChangeAt sql.NullTime
+
+	// LastConnectionAt is the time a user last connected to the model
+	// through JIMM.
+	LastConnectionAt sql.NullTime
+
 	// Offers are the ApplicationOffers attached to the model.
 	Offers []ApplicationOffer
 }
@@ -97,6 +144,19 @@ func (m Model) ResourceTag() names.ModelTag {
 	return names.ModelTag{}
 }
 
+// LastActivity returns the most recent time the model was known to be
+// in use: the last change to its units, the last connection to it or,
+// if neither has been seen, the time it was added.
+func (m Model) LastActivity() time.Time {
+	last := m.CreatedAt
+	for _, t := range []sql.NullTime{m.LastUnitChangeAt, m.LastConnectionAt} {
+		if t.Valid && t.Time.After(last) {
+			last = t.Time
+		}
+	}
+	return last
+}
+
 // SetTag sets the UUID of the model to the given tag.
 func (m *Model) SetTag(t names.ModelTag) {
 	m.UUID.String = t.Id()
@@ -205,6 +265,18 @@ func (m Model) ToJujuModelSummary() jujuparams.ModelSummary {
 		Entity: jujuparams.Units,
 		Count:  m.Units,
 	}}
+	for _, c := range []jujuparams.ModelEntityCount{
+		{Entity: apiparams.Containers, Count: m.Containers},
+		{Entity: apiparams.Offers, Count: m.OfferCount},
+		{Entity: apiparams.Relations, Count: m.Relations},
+		{Entity: apiparams.ActiveUnits, Count: m.ActiveUnits},
+		{Entity: apiparams.BlockedUnits, Count: m.BlockedUnits},
+		{Entity: apiparams.ErrorUnits, Count: m.ErrorUnits},
+	} {
+		if c.Count > 0 {
+			ms.Counts = append(ms.Counts, c)
+		}
+	}
 
 	// JIMM doesn't store information about Migrations so this is omitted.
 	ms.SLA = new(jujuparams.ModelSLAInfo)
@@ -219,6 +291,39 @@ func (m Model) ToJujuModelSummary() jujuparams.ModelSummary {
 	return ms
 }
 
+// Kinds of model origin.
+const (
+	// ModelOriginCreated is the origin of models created through JIMM.
+	ModelOriginCreated = "created"
+
+	// ModelOriginAdopted is the origin of models that already existed
+	// on a controller and were imported into JIMM.
+	ModelOriginAdopted = "adopted"
+
+	// ModelOriginMigrated is the origin of models that were migrated to
+	// a JIMM controller from a controller outside JIMM and then
+	// imported.
+	ModelOriginMigrated = "migrated"
+
+	// ModelOriginRestored is the origin of models that were restored
+	// from a backup and then imported.
+	ModelOriginRestored = "restored"
+)
+
+// A ModelOrigin records how a model came to be managed by JIMM. Models
+// added before origins were recorded have an empty Kind.
+type ModelOrigin struct {
+	// Kind is the kind of origin, one of the ModelOrigin constants.
+	Kind string `gorm:"not null;default:''"`
+
+	// IdentityName is the name of the identity that created or
+	// imported the model.
+	IdentityName string `gorm:"not null;default:''"`
+
+	// Time is the time the model was created or imported.
+	Time sql.NullTime
+}
+
 // An SLA contains the details of the SLA associated with the model.
 type SLA struct {
 	// Level contains the SLA level.
@@ -274,9 +274,13 @@ func TestToJujuModelSummary(t *testing.T) {
 		SLA: dbmodel.SLA{
 			Level: "unsupported",
 		},
-		Machines: 1,
-		Cores:    2,
-		Units:    3,
+		Machines:     1,
+		Cores:        2,
+		Units:        3,
+		Containers:   1,
+		Relations:    4,
+		ActiveUnits:  2,
+		BlockedUnits: 1,
 	}
 	m.CloudRegion.Cloud = cl
 
@@ -307,6 +311,20 @@ func TestToJujuModelSummary(t *testing.T) {
 		}, {
 			Entity: "units",
 			Count:  3,
+		}, {
+			// Counts of the entities JIMM adds are only
+			// included if they are non-zero.
+			Entity: "containers",
+			Count:  1,
+		}, {
+			Entity: "relations",
+			Count:  4,
+		}, {
+			Entity: "active-units",
+			Count:  2,
+		}, {
+			Entity: "blocked-units",
+			Count:  1,
 		}},
 		SLA: &jujuparams.ModelSLAInfo{
 			Level: "unsupported",
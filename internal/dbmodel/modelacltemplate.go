@@ -0,0 +1,46 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelACLTemplate grants a group access to every new model owned by a
+// member of another group.
+type ModelACLTemplate struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// OwnerGroupID is the ID of the group whose members' new models
+	// the template applies to.
+	OwnerGroupID uint
+
+	// OwnerGroup is the group whose members' new models the template
+	// applies to.
+	OwnerGroup GroupEntry `gorm:"foreignKey:OwnerGroupID"`
+
+	// GrantGroupID is the ID of the group granted access to the models.
+	GrantGroupID uint
+
+	// GrantGroup is the group granted access to the models.
+	GrantGroup GroupEntry `gorm:"foreignKey:GrantGroupID"`
+
+	// Access is the model access granted to the group.
+	Access string
+}
+
+// ToAPIModelACLTemplate converts a model ACL template to the JIMM API
+// representation.
+func (t ModelACLTemplate) ToAPIModelACLTemplate() apiparams.ModelACLTemplate {
+	return apiparams.ModelACLTemplate{
+		ID:         t.ID,
+		OwnerGroup: t.OwnerGroup.Name,
+		GrantGroup: t.GrantGroup.Name,
+		Access:     t.Access,
+		CreatedAt:  t.CreatedAt,
+	}
+}
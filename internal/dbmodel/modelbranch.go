@@ -0,0 +1,92 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+)
+
+// A ModelBranch is an in-progress generation of changes to a model's
+// application configuration, which is applied only to the units assigned
+// to the branch until it is committed.
+type ModelBranch struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ModelID is the ID of the model the branch is in.
+	ModelID uint
+
+	// BranchID is the ID of the branch in the model.
+	BranchID string
+
+	// Name is the name of the branch.
+	Name string
+
+	// GenerationID is the ID of the generation the branch is committed
+	// as, it is zero until the branch is committed.
+	GenerationID int
+
+	// AssignedUnits holds the names of the units tracking the branch.
+	AssignedUnits Strings
+
+	// ConfigChanges holds the configuration keys changed by the branch,
+	// in the form <application>:<key>. The values are not recorded as
+	// they may be sensitive.
+	ConfigChanges Strings
+
+	// BranchCreated is the time the branch was created.
+	BranchCreated sql.NullTime
+
+	// BranchCreatedBy is the name of the user that created the branch.
+	BranchCreatedBy string
+
+	// BranchCompleted is the time the branch was committed or aborted.
+	BranchCompleted sql.NullTime
+
+	// BranchCompletedBy is the name of the user that committed or
+	// aborted the branch.
+	BranchCompletedBy string
+}
+
+// FromJujuBranchInfo updates the model branch from the given BranchInfo.
+func (b *ModelBranch) FromJujuBranchInfo(info jujuparams.BranchInfo) {
+	b.BranchID = info.Id
+	b.Name = info.Name
+	b.GenerationID = info.GenerationId
+
+	b.AssignedUnits = Strings{}
+	for _, units := range info.AssignedUnits {
+		b.AssignedUnits = append(b.AssignedUnits, units...)
+	}
+	sort.Strings(b.AssignedUnits)
+
+	b.ConfigChanges = Strings{}
+	for app, changes := range info.Config {
+		for _, change := range changes {
+			b.ConfigChanges = append(b.ConfigChanges, app+":"+change.Key)
+		}
+	}
+	sort.Strings(b.ConfigChanges)
+
+	b.BranchCreated = unixTime(info.Created)
+	b.BranchCreatedBy = info.CreatedBy
+	b.BranchCompleted = unixTime(info.Completed)
+	b.BranchCompletedBy = info.CompletedBy
+}
+
+// unixTime converts the given unix time, in seconds, to a sql.NullTime.
+// A time of zero is treated as unset.
+func unixTime(t int64) sql.NullTime {
+	if t == 0 {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{
+		Time:  time.Unix(t, 0).UTC(),
+		Valid: true,
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelDependency records that a model depends on another model, for
+// example an application model that consumes an offer from a shared
+// database model. A model that other models depend on may only be
+// destroyed with force.
+type ModelDependency struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// ModelID is the ID of the dependent model.
+	ModelID uint
+	Model   Model
+
+	// DependsOnID is the ID of the model depended on.
+	DependsOnID uint
+	DependsOn   Model
+
+	// CreatedBy is the name of the user that declared the dependency.
+	CreatedBy string
+
+	// Description describes why the model depends on the other model.
+	Description string
+}
+
+// ToAPIModelDependency converts a model dependency to its API
+// representation.
+func (d ModelDependency) ToAPIModelDependency() apiparams.ModelDependency {
+	return apiparams.ModelDependency{
+		ModelTag:           names.NewModelTag(d.Model.UUID.String).String(),
+		ModelName:          d.Model.OwnerIdentityName + "/" + d.Model.Name,
+		DependsOnModelTag:  names.NewModelTag(d.DependsOn.UUID.String).String(),
+		DependsOnModelName: d.DependsOn.OwnerIdentityName + "/" + d.DependsOn.Name,
+		Description:        d.Description,
+		CreatedBy:          d.CreatedBy,
+		CreatedAt:          d.CreatedAt,
+	}
+}
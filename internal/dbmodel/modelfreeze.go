@@ -0,0 +1,52 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelFreeze records that a model is frozen. Mutating calls made to a
+// frozen model are rejected, typically during incident response or
+// compliance lockdowns.
+type ModelFreeze struct {
+	// ModelID is the ID of the frozen model.
+	ModelID   uint `gorm:"primaryKey"`
+	Model     Model
+	CreatedAt time.Time
+
+	// FrozenBy is the name of the user that froze the model.
+	FrozenBy string
+
+	// Reason describes why the model is frozen.
+	Reason string
+
+	// Until is the time the freeze expires. If this is not valid the
+	// model is frozen until it is unfrozen.
+	Until sql.NullTime
+}
+
+// Active returns whether the freeze is in effect at the given time.
+func (f ModelFreeze) Active(now time.Time) bool {
+	return !f.Until.Valid || now.Before(f.Until.Time)
+}
+
+// ToAPIModelFreeze converts a model freeze to its API representation.
+func (f ModelFreeze) ToAPIModelFreeze() apiparams.ModelFreeze {
+	mf := apiparams.ModelFreeze{
+		ModelTag:  names.NewModelTag(f.Model.UUID.String).String(),
+		FrozenBy:  f.FrozenBy,
+		Reason:    f.Reason,
+		CreatedAt: f.CreatedAt,
+	}
+	if f.Until.Valid {
+		until := f.Until.Time
+		mf.Until = &until
+	}
+	return mf
+}
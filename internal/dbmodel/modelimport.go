@@ -0,0 +1,91 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The statuses of a model import.
+const (
+	// ModelImportPrepared is the status of an import that is waiting
+	// for the model to be migrated to the target controller.
+	ModelImportPrepared = "prepared"
+
+	// ModelImportCompleted is the status of an import whose model has
+	// been added to JIMM.
+	ModelImportCompleted = "completed"
+)
+
+// A ModelImport records the import of a model exported from another JIMM
+// deployment. The import is prepared before the source JIMM migrates the
+// model to the target controller, and completed once the model is on the
+// controller.
+type ModelImport struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ModelUUID is the UUID of the imported model.
+	ModelUUID string
+
+	// ModelName is the name of the imported model.
+	ModelName string
+
+	// SourceJIMM is the tag of the JIMM the model is exported from.
+	SourceJIMM string `gorm:"column:source_jimm"`
+
+	// ControllerID is the ID of the controller the model is migrated
+	// to.
+	ControllerID uint
+	Controller   Controller
+
+	// OwnerIdentityName is the name of the model's owner in this JIMM.
+	OwnerIdentityName string
+
+	// UserAccess holds the access, "read", "write" or "admin", each
+	// user will hold on the model keyed by user name.
+	UserAccess StringMap
+
+	// GroupAccess holds the access each group will hold on the model
+	// keyed by group name.
+	GroupAccess StringMap
+
+	// Status is the status of the import, one of the ModelImport*
+	// constants.
+	Status string
+
+	// PreparedBy is the name of the user that prepared the import.
+	PreparedBy string
+
+	// CompletedAt is the time the import was completed.
+	CompletedAt sql.NullTime
+}
+
+// ToAPIModelImport converts a model import to its API representation.
+func (m ModelImport) ToAPIModelImport() apiparams.ModelImport {
+	mi := apiparams.ModelImport{
+		ID:         m.ID,
+		ModelTag:   names.NewModelTag(m.ModelUUID).String(),
+		SourceJIMM: m.SourceJIMM,
+		Controller: m.Controller.Name,
+		Owner:      m.OwnerIdentityName,
+		Access: apiparams.ModelAccessSpec{
+			Model:  m.OwnerIdentityName + "/" + m.ModelName,
+			Users:  m.UserAccess,
+			Groups: m.GroupAccess,
+		},
+		Status:    m.Status,
+		CreatedAt: m.CreatedAt,
+	}
+	if m.CompletedAt.Valid {
+		completed := m.CompletedAt.Time
+		mi.CompletedAt = &completed
+	}
+	return mi
+}
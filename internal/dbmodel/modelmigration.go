@@ -0,0 +1,98 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The statuses of a model migration tracked by JIMM.
+const (
+	// MigrationRunning is the status of a migration that has been
+	// initiated and not yet finished.
+	MigrationRunning = "running"
+
+	// MigrationCompleted is the status of a migration that moved the
+	// model to the target controller.
+	MigrationCompleted = "completed"
+
+	// MigrationAborted is the status of a migration that finished
+	// without moving the model.
+	MigrationAborted = "aborted"
+
+	// MigrationAbandoned is the status of a migration JIMM stopped
+	// tracking because its outcome could not be determined in time.
+	MigrationAbandoned = "abandoned"
+)
+
+// A ModelMigration records a migration of a model between two of JIMM's
+// controllers, initiated by JIMM. Migrations are recorded so that they
+// continue to be monitored if the JIMM replica that initiated them
+// stops.
+type ModelMigration struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ModelID is the ID of the migrating model.
+	ModelID uint
+	Model   Model
+
+	// SourceControllerID is the ID of the controller the model is
+	// migrating from.
+	SourceControllerID uint
+	SourceController   Controller
+
+	// TargetControllerID is the ID of the controller the model is
+	// migrating to.
+	TargetControllerID uint
+	TargetController   Controller
+
+	// MigrationID is the ID of the migration on the source controller.
+	MigrationID string
+
+	// InitiatedBy is the name of the user that initiated the migration.
+	InitiatedBy string
+
+	// Status is the status of the migration as tracked by JIMM, one of
+	// the Migration* constants.
+	Status string
+
+	// Phase is the most recent migration status reported by the source
+	// controller.
+	Phase string
+
+	// Error describes why the migration was aborted or abandoned.
+	Error string
+
+	// EndedAt is the time JIMM found the migration had finished. While
+	// this is not valid the migration is monitored.
+	EndedAt sql.NullTime
+}
+
+// ToAPIModelMigration converts a model migration to its API
+// representation.
+func (m ModelMigration) ToAPIModelMigration() apiparams.ModelMigration {
+	mm := apiparams.ModelMigration{
+		ID:               m.ID,
+		ModelTag:         names.NewModelTag(m.Model.UUID.String).String(),
+		MigrationID:      m.MigrationID,
+		SourceController: m.SourceController.Name,
+		TargetController: m.TargetController.Name,
+		InitiatedBy:      m.InitiatedBy,
+		Status:           m.Status,
+		Phase:            m.Phase,
+		Error:            m.Error,
+		Started:          m.CreatedAt,
+	}
+	if m.EndedAt.Valid {
+		ended := m.EndedAt.Time
+		mm.Ended = &ended
+	}
+	return mm
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelNetworkPolicy holds the network policy an administrator has
+// attached to a model, and whether the policy has been enforced on the
+// controller hosting the model.
+type ModelNetworkPolicy struct {
+	// ModelID is the ID of the model the policy is attached to.
+	ModelID   uint `gorm:"primaryKey"`
+	Model     Model
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// SetBy is the name of the user that last set the policy.
+	SetBy string
+
+	// AllowedEgress holds the CIDRs of the destinations traffic from
+	// the model is allowed to reach.
+	AllowedEgress Strings
+
+	// Enforced records whether the policy has been applied to the
+	// controller hosting the model.
+	Enforced bool
+
+	// EnforcementError describes why the policy is not enforced, if it
+	// is not.
+	EnforcementError string
+}
+
+// ToAPIModelNetworkPolicy converts a model network policy to its API
+// representation.
+func (p ModelNetworkPolicy) ToAPIModelNetworkPolicy() apiparams.ModelNetworkPolicy {
+	return apiparams.ModelNetworkPolicy{
+		ModelTag:         names.NewModelTag(p.Model.UUID.String).String(),
+		AllowedEgress:    []string(p.AllowedEgress),
+		SetBy:            p.SetBy,
+		UpdatedAt:        p.UpdatedAt,
+		Enforced:         p.Enforced,
+		EnforcementError: p.EnforcementError,
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ModelPool is a pool of pre-provisioned, empty models kept ready in a
+// cloud region so that a model can be created by claiming one of them
+// rather than waiting for the controller to create it.
+type ModelPool struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Name is the name of the pool.
+	Name string
+
+	// CloudRegion is the cloud region hosting the pooled models.
+	CloudRegionID uint
+	CloudRegion   CloudRegion
+
+	// CloudCredential is the credential the pooled models are created
+	// with. Claimed models are switched to a credential of the identity
+	// claiming them.
+	CloudCredentialID uint
+	CloudCredential   CloudCredential
+
+	// Config holds the template model configuration the pooled models
+	// are created with.
+	Config Map
+
+	// Size is the number of models to keep ready in the pool.
+	Size int
+
+	// Models holds the models ready in the pool.
+	Models []PooledModel
+}
+
+// ToAPIModelPool converts a model pool to its API representation.
+func (p ModelPool) ToAPIModelPool() apiparams.ModelPool {
+	return apiparams.ModelPool{
+		Name:               p.Name,
+		CloudTag:           p.CloudRegion.Cloud.ResourceTag().String(),
+		CloudRegion:        p.CloudRegion.Name,
+		CloudCredentialTag: p.CloudCredential.ResourceTag().String(),
+		Config:             p.Config,
+		Size:               p.Size,
+		Ready:              len(p.Models),
+		CreatedAt:          p.CreatedAt,
+	}
+}
+
+// A PooledModel is a model created on a controller that is ready to be
+// claimed from its pool. Pooled models are not known to JIMM as models
+// until they are claimed.
+type PooledModel struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// ModelPoolID is the ID of the pool holding the model.
+	ModelPoolID uint
+
+	// Controller is the controller hosting the model.
+	ControllerID uint
+	Controller   Controller
+
+	// Name is the name of the model on the controller.
+	Name string
+
+	// UUID is the UUID of the model.
+	UUID string
+}
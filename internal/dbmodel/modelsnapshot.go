@@ -0,0 +1,44 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// Resolutions at which model resource snapshots are stored.
+const (
+	// SnapshotResolutionHour is the resolution of recent snapshots,
+	// which are recorded for each hour.
+	SnapshotResolutionHour = "hour"
+
+	// SnapshotResolutionDay is the resolution of older snapshots, which
+	// hold the maximum of the hourly snapshots recorded during each day.
+	SnapshotResolutionDay = "day"
+)
+
+// A ModelResourceSnapshot records the resources used by a model at a
+// point in time, so that the history of a model's resource usage can be
+// graphed.
+type ModelResourceSnapshot struct {
+	ID uint `gorm:"primaryKey"`
+
+	// ModelID is the ID of the model the snapshot was taken of.
+	ModelID uint
+
+	// Time is the start of the period the snapshot covers.
+	Time time.Time
+
+	// Resolution is the length of the period the snapshot covers, see
+	// the SnapshotResolution* constants.
+	Resolution string
+
+	// Machines contains the count of machines in the model.
+	Machines int64
+
+	// Cores contains the count of cores in the model.
+	Cores int64
+
+	// Units contains the count of units in the model.
+	Units int64
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelTokenDomain is the domain of the identities that model tokens
+// authenticate as.
+const ModelTokenDomain = "modeltoken"
+
+// IsModelTokenIdentity returns whether the identity with the given name is
+// one that a model token authenticates as.
+func IsModelTokenIdentity(name string) bool {
+	return strings.HasSuffix(name, "@"+ModelTokenDomain)
+}
+
+// A ModelToken is a long-lived token that grants access to a single model,
+// intended for use by automated systems such as CI pipelines.
+type ModelToken struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ModelID is the ID of the model the token grants access to.
+	ModelID uint
+
+	// Model is the model the token grants access to.
+	Model Model
+
+	// Access is the model access granted by the token, one of "read",
+	// "write" or "admin".
+	Access string
+
+	// Description describes the purpose of the token.
+	Description string
+
+	// CreatedBy is the name of the identity that created the token.
+	CreatedBy string
+
+	// SecretHash holds the SHA-256 hash of the token's secret. The
+	// secret itself is only returned when the token is created.
+	SecretHash []byte
+}
+
+// IdentityName returns the name of the identity the token authenticates
+// as. Each token has its own identity so that the access it grants is
+// limited to that granted to the token.
+func (t ModelToken) IdentityName() string {
+	return fmt.Sprintf("model-token-%d@%s", t.ID, ModelTokenDomain)
+}
+
+// ToAPIModelToken converts a model token to the JIMM API representation.
+// The model must have been fetched.
+func (t ModelToken) ToAPIModelToken() apiparams.ModelToken {
+	return apiparams.ModelToken{
+		ID:          t.ID,
+		ModelTag:    names.NewModelTag(t.Model.UUID.String).String(),
+		Identity:    t.IdentityName(),
+		Access:      t.Access,
+		Description: t.Description,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+	}
+}
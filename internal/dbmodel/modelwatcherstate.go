@@ -0,0 +1,80 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/juju/juju/core/status"
+)
+
+// ModelWatcherStateSchemaVersion is the current schema version of stored
+// model watcher states. States stored with an earlier version are
+// upgraded when they are read and by db.UpgradeDocuments. The versions
+// are:
+//
+//	0: offers and relations may be missing and units may be stored
+//	   without a workload status.
+//	1: offers and relations are always recorded and every unit has a
+//	   workload status.
+//	2: agent versions are always recorded.
+const ModelWatcherStateSchemaVersion = 2
+
+// A ModelWatcherState holds the machines, units, offers and relations
+// the watcher has seen in a model. The model's entity counts are derived
+// from this state. It is persisted so that a restarted watcher, possibly in
+// another JIMM instance, continues from the last known state rather than
+// recounting from zero.
+type ModelWatcherState struct {
+	// ModelID is the ID of the model the state belongs to.
+	ModelID   uint `gorm:"primaryKey"`
+	UpdatedAt time.Time
+
+	// Machines maps the ID of each machine in the model to the number of
+	// cores it reported.
+	Machines Int64Map
+
+	// Units maps the ID of each unit in the model to its workload
+	// status.
+	Units StringMap
+
+	// Offers holds the names of the application offers made from the
+	// model.
+	Offers Strings
+
+	// Relations holds the keys of the relations in the model.
+	Relations Strings
+
+	// AgentVersions maps each machine and unit in the model, keyed by
+	// kind and ID, for example "machine-0" or "unit-app/0", to the
+	// version of the agent it last reported.
+	AgentVersions StringMap
+
+	// SchemaVersion is the version of the format the state is stored in,
+	// see ModelWatcherStateSchemaVersion.
+	SchemaVersion int
+}
+
+// Upgrade converts a state stored with an earlier schema version to the
+// current version. It reports whether the state was changed.
+func (s *ModelWatcherState) Upgrade() bool {
+	if s.SchemaVersion >= ModelWatcherStateSchemaVersion {
+		return false
+	}
+	if s.Offers == nil {
+		s.Offers = Strings{}
+	}
+	if s.Relations == nil {
+		s.Relations = Strings{}
+	}
+	if s.AgentVersions == nil {
+		s.AgentVersions = StringMap{}
+	}
+	for id, st := range s.Units {
+		if st == "" {
+			s.Units[id] = string(status.Unknown)
+		}
+	}
+	s.SchemaVersion = ModelWatcherStateSchemaVersion
+	return true
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"strings"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A NamespaceReservation reserves the model names starting with a prefix
+// for the members of a group.
+type NamespaceReservation struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Prefix is the reserved model name prefix.
+	Prefix string `gorm:"uniqueIndex"`
+
+	// GroupID is the ID of the group owning the reservation.
+	GroupID uint
+
+	// Group is the group owning the reservation.
+	Group GroupEntry
+}
+
+// Matches reports whether the given model name falls within the
+// reservation.
+func (r NamespaceReservation) Matches(modelName string) bool {
+	return strings.HasPrefix(modelName, r.Prefix)
+}
+
+// ToAPINamespaceReservation converts a namespace reservation to the JIMM
+// API representation.
+func (r NamespaceReservation) ToAPINamespaceReservation() apiparams.NamespaceReservation {
+	return apiparams.NamespaceReservation{
+		Prefix:    r.Prefix,
+		Group:     r.Group.Name,
+		CreatedAt: r.CreatedAt,
+	}
+}
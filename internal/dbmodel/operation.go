@@ -0,0 +1,103 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// The statuses of an operation.
+const (
+	// OperationRunning is the status of an operation that has not yet
+	// finished.
+	OperationRunning = "running"
+
+	// OperationSucceeded is the status of an operation that finished
+	// successfully.
+	OperationSucceeded = "succeeded"
+
+	// OperationFailed is the status of an operation that finished with
+	// an error.
+	OperationFailed = "failed"
+)
+
+// An Operation records the progress of a long-running operation, so that
+// it may be followed by clients of any JIMM replica.
+type Operation struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// UUID is the ID of the operation given to clients.
+	UUID string
+
+	// Kind is the kind of the operation.
+	Kind string
+
+	// IdentityName is the name of the user that started the operation.
+	IdentityName string
+
+	// Status is the status of the operation, one of the Operation*
+	// constants.
+	Status string
+
+	// Progress is the percentage of the operation that has been done.
+	Progress int
+
+	// Error holds the reason the operation failed.
+	Error string
+
+	// Result holds the JSON encoded result of the operation once it has
+	// succeeded.
+	Result JSON
+
+	// EndedAt is the time the operation finished.
+	EndedAt sql.NullTime
+
+	// Steps holds the steps of the operation completed so far.
+	Steps []OperationStep
+}
+
+// An OperationStep is an entry in the step log of an operation.
+type OperationStep struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// OperationID is the ID of the operation the step is part of.
+	OperationID uint
+
+	// Message describes the step.
+	Message string
+}
+
+// ToAPIOperation converts an operation to its API representation.
+func (o Operation) ToAPIOperation() apiparams.Operation {
+	op := apiparams.Operation{
+		ID:        o.UUID,
+		Kind:      o.Kind,
+		StartedBy: o.IdentityName,
+		Status:    o.Status,
+		Progress:  o.Progress,
+		Error:     o.Error,
+		Started:   o.CreatedAt,
+	}
+	for _, s := range o.Steps {
+		op.Steps = append(op.Steps, apiparams.OperationStep{
+			Time:    s.CreatedAt,
+			Message: s.Message,
+		})
+	}
+	if len(o.Result) > 0 {
+		// A result that is not an object is not reported.
+		_ = json.Unmarshal(o.Result, &op.Result)
+	}
+	if o.EndedAt.Valid {
+		ended := o.EndedAt.Time
+		op.Ended = &ended
+	}
+	return op
+}
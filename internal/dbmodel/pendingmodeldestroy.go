@@ -0,0 +1,49 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A PendingModelDestroy records that a model has been requested to be
+// destroyed. The model is destroyed once DestroyAt has passed, until then
+// the destroy may be canceled.
+type PendingModelDestroy struct {
+	// ModelID is the ID of the model to destroy.
+	ModelID   uint `gorm:"primaryKey"`
+	Model     Model
+	CreatedAt time.Time
+
+	// RequestedBy is the name of the user that requested the destroy.
+	RequestedBy string
+
+	// DestroyAt is the time after which the model is destroyed.
+	DestroyAt time.Time
+
+	// DestroyStorage, Force, MaxWait and Timeout hold the options the
+	// destroy was requested with, MaxWait and Timeout are held in
+	// nanoseconds.
+	DestroyStorage sql.NullBool
+	Force          sql.NullBool
+	MaxWait        sql.NullInt64
+	Timeout        sql.NullInt64
+}
+
+// ToAPIPendingModelDestroy converts a pending model destroy to its API
+// representation.
+func (d PendingModelDestroy) ToAPIPendingModelDestroy() apiparams.PendingModelDestroy {
+	return apiparams.PendingModelDestroy{
+		ModelTag:    names.NewModelTag(d.Model.UUID.String).String(),
+		Name:        d.Model.Name,
+		Owner:       d.Model.OwnerIdentityName,
+		RequestedBy: d.RequestedBy,
+		RequestedAt: d.CreatedAt,
+		DestroyAt:   d.DestroyAt,
+	}
+}
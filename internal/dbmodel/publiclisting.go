@@ -0,0 +1,38 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import "time"
+
+// A PublicModelListing records that a model has been opted in to the
+// public catalog, which describes the model to unauthenticated clients.
+type PublicModelListing struct {
+	// ModelID is the ID of the listed model.
+	ModelID   uint `gorm:"primaryKey"`
+	Model     Model
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Purpose describes what the model is for.
+	Purpose string
+
+	// OwnerGroup is the name of the group responsible for the model.
+	OwnerGroup string
+}
+
+// A PublicOfferListing records that an application offer has been opted
+// in to the public catalog, which describes the offer to unauthenticated
+// clients.
+type PublicOfferListing struct {
+	// ApplicationOfferID is the ID of the listed application offer.
+	ApplicationOfferID uint `gorm:"primaryKey"`
+	ApplicationOffer   ApplicationOffer
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+
+	// Purpose describes what the offer is for.
+	Purpose string
+
+	// OwnerGroup is the name of the group responsible for the offer.
+	OwnerGroup string
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+)
+
+// A RemoteApplication is an application, offered by another model, that
+// is consumed by a model.
+type RemoteApplication struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// ModelID is the ID of the model consuming the application.
+	ModelID uint
+
+	// Name is the name of the remote application in the consuming model.
+	Name string
+
+	// OfferURL is the URL of the offer being consumed.
+	OfferURL string
+
+	// Life holds the life status of the remote application.
+	Life string
+
+	// Status holds the status of the remote application.
+	Status string
+}
+
+// FromJujuRemoteApplicationUpdate updates the remote application from the
+// given RemoteApplicationUpdate.
+func (a *RemoteApplication) FromJujuRemoteApplicationUpdate(info jujuparams.RemoteApplicationUpdate) {
+	a.Name = info.Name
+	a.OfferURL = info.OfferURL
+	a.Life = string(info.Life)
+	a.Status = string(info.Status.Current)
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+)
+
+// A ResultDownload holds the result of a call that was too large to
+// return inline in the RPC response. The result is downloaded out of
+// band using an unguessable token until it expires.
+type ResultDownload struct {
+	ID uint `gorm:"primaryKey"`
+
+	// CreatedAt is the time the result was stored.
+	CreatedAt time.Time
+
+	// ExpiresAt is the time after which the result may no longer be
+	// downloaded.
+	ExpiresAt time.Time
+
+	// TokenHash is the hash of the token identifying the download, the
+	// token itself is not stored.
+	TokenHash string
+
+	// IdentityName is the name of the identity that made the call.
+	IdentityName string
+
+	// Name is the file name the result is downloaded as.
+	Name string
+
+	// ContentType is the content type of the result.
+	ContentType string
+
+	// Body is the result. Body is empty if the result is kept in the
+	// object store.
+	Body []byte
+
+	// ObjectKey is the key of the object holding the result in the
+	// object store, if any.
+	ObjectKey string
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"database/sql"
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A ScheduledJob holds the settings of a job run by the scheduler. Jobs
+// without settings are enabled.
+type ScheduledJob struct {
+	// Name is the name of the job.
+	Name string `gorm:"primaryKey"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Enabled is whether the job is run.
+	Enabled bool
+}
+
+// A ScheduledJobRun records a single run of a scheduled job.
+type ScheduledJobRun struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// JobName is the name of the job.
+	JobName string
+
+	// Holder identifies the JIMM replica that ran the job.
+	Holder string
+
+	// StartedAt is the time the run started.
+	StartedAt time.Time
+
+	// FinishedAt is the time the run finished, if it has.
+	FinishedAt sql.NullTime
+
+	// Error holds the error the run failed with, if it failed.
+	Error string
+}
+
+// ToAPIScheduledJobRun converts a scheduled job run to its API
+// representation.
+func (r ScheduledJobRun) ToAPIScheduledJobRun() apiparams.ScheduledJobRun {
+	run := apiparams.ScheduledJobRun{
+		Job:       r.JobName,
+		Holder:    r.Holder,
+		StartedAt: r.StartedAt,
+		Error:     r.Error,
+	}
+	if r.FinishedAt.Valid {
+		t := r.FinishedAt.Time
+		run.FinishedAt = &t
+	}
+	return run
+}
@@ -1,7 +1,20 @@
 // Copyright 2024 Canonical.
 package dbmodel
 
-import "time"
+import (
+	"time"
+
+	"github.com/juju/names/v5"
+)
+
+// SecretSchemaVersion is the current schema version of stored secrets.
+// Secrets stored with an earlier version are upgraded by
+// db.UpgradeDocuments. The versions are:
+//
+//	0: cloud credential secrets may be stored using the legacy
+//	   credential path, "cloud/owner/name", as their tag.
+//	1: cloud credential secrets are stored using the credential's tag.
+const SecretSchemaVersion = 1
 
 // A Secret is a generic secret.
 type Secret struct {
@@ -19,10 +32,23 @@ type Secret struct {
 
 	// Contains the secret data.
 	Data JSON
+
+	// SchemaVersion is the version of the format the secret is stored
+	// in, see SecretSchemaVersion.
+	SchemaVersion int
 }
 
 // newSecret creates a secret object with the time set to the current time
 // and the type and tag fields set from the tag object
 func NewSecret(secretType string, secretTag string, data []byte) Secret {
-	return Secret{Time: time.Now(), Type: secretType, Tag: secretTag, Data: data}
+	return Secret{Time: time.Now(), Type: secretType, Tag: secretTag, Data: data, SchemaVersion: SecretSchemaVersion}
+}
+
+// UpgradedTag returns the tag the secret is stored with in the current
+// schema version.
+func (s Secret) UpgradedTag() string {
+	if s.Type == names.CloudCredentialTagKind && names.IsValidCloudCredential(s.Tag) {
+		return names.NewCloudCredentialTag(s.Tag).String()
+	}
+	return s.Tag
 }
@@ -0,0 +1,7 @@
+-- 1_12.sql is a migration that adds outbound dial configuration to the
+-- controller table.
+ALTER TABLE controllers ADD COLUMN proxy_url TEXT;
+ALTER TABLE controllers ADD COLUMN ssh_jump_host TEXT;
+ALTER TABLE controllers ADD COLUMN ssh_jump_host_key TEXT;
+
+UPDATE versions SET major=1, minor=12 WHERE component='jimmdb';
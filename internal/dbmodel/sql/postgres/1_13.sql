@@ -0,0 +1,11 @@
+-- 1_13.sql is a migration that adds the leases table used to elect the
+-- JIMM replica that runs each background worker.
+CREATE TABLE IF NOT EXISTS leases (
+	name TEXT NOT NULL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	holder TEXT NOT NULL,
+	expires TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+UPDATE versions SET major=1, minor=13 WHERE component='jimmdb';
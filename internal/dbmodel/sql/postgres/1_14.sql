@@ -0,0 +1,36 @@
+-- 1_14.sql is a migration that records the remote applications and
+-- branches of each model, and the number of connections to each
+-- application offer, as reported by the controller watchers.
+ALTER TABLE application_offers ADD COLUMN IF NOT EXISTS total_connected_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE application_offers ADD COLUMN IF NOT EXISTS active_connected_count INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS remote_applications (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	offer_url TEXT NOT NULL,
+	life TEXT NOT NULL,
+	status TEXT NOT NULL,
+	UNIQUE (model_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS model_branches (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	branch_id TEXT NOT NULL,
+	name TEXT NOT NULL,
+	generation_id INTEGER NOT NULL,
+	assigned_units BYTEA,
+	config_changes BYTEA,
+	branch_created TIMESTAMP WITH TIME ZONE,
+	branch_created_by TEXT NOT NULL,
+	branch_completed TIMESTAMP WITH TIME ZONE,
+	branch_completed_by TEXT NOT NULL,
+	UNIQUE (model_id, branch_id)
+);
+
+UPDATE versions SET major=1, minor=14 WHERE component='jimmdb';
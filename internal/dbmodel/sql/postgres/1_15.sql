@@ -0,0 +1,19 @@
+-- 1_15.sql is a migration that records the placement and network
+-- details of the machines in each model, as reported by the controller
+-- watchers.
+CREATE TABLE IF NOT EXISTS machines (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	machine_id TEXT NOT NULL,
+	instance_id TEXT NOT NULL,
+	hostname TEXT NOT NULL,
+	life TEXT NOT NULL,
+	availability_zone TEXT NOT NULL,
+	addresses BYTEA,
+	subnets BYTEA,
+	UNIQUE (model_id, machine_id)
+);
+
+UPDATE versions SET major=1, minor=15 WHERE component='jimmdb';
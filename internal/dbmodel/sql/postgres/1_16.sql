@@ -0,0 +1,6 @@
+-- 1_16.sql is a migration that records a summary of the workload status
+-- of the units in each model, as reported by the controller watchers.
+ALTER TABLE models ADD COLUMN workload_status TEXT NOT NULL DEFAULT '';
+ALTER TABLE models ADD COLUMN unhealthy_units BIGINT NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=16 WHERE component='jimmdb';
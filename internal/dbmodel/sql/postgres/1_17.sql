@@ -0,0 +1,7 @@
+-- 1_17.sql is a migration that adds TLS verification configuration and
+-- the last seen certificate expiry to the controller table.
+ALTER TABLE controllers ADD COLUMN tls_system_ca_fallback BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE controllers ADD COLUMN tls_min_version TEXT NOT NULL DEFAULT '';
+ALTER TABLE controllers ADD COLUMN certificate_expiry TIMESTAMP WITH TIME ZONE;
+
+UPDATE versions SET major=1, minor=17 WHERE component='jimmdb';
@@ -0,0 +1,11 @@
+-- 1_18.sql is a migration that adds the namespace_reservations table used
+-- to reserve model name prefixes for the members of a group.
+CREATE TABLE IF NOT EXISTS namespace_reservations (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	prefix TEXT NOT NULL UNIQUE,
+	group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE
+);
+
+UPDATE versions SET major=1, minor=18 WHERE component='jimmdb';
@@ -0,0 +1,8 @@
+-- 1_19.sql is a migration that adds per-controller dial, RPC and
+-- keepalive settings to the controller table. Durations are stored in
+-- nanoseconds, zero means the default is used.
+ALTER TABLE controllers ADD COLUMN dial_timeout BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE controllers ADD COLUMN rpc_timeout BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE controllers ADD COLUMN keepalive_interval BIGINT NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=19 WHERE component='jimmdb';
@@ -0,0 +1,18 @@
+-- 1_20.sql is a migration that adds the access_requests table used to
+-- record the requests users make for access to models and clouds.
+CREATE TABLE IF NOT EXISTS access_requests (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	identity_name TEXT NOT NULL REFERENCES identities (name) ON DELETE CASCADE,
+	target_tag TEXT NOT NULL,
+	access TEXT NOT NULL,
+	reason TEXT,
+	status TEXT NOT NULL,
+	reviewer_name TEXT,
+	review_comment TEXT,
+	reviewed_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_access_requests_target_tag ON access_requests (target_tag);
+
+UPDATE versions SET major=1, minor=20 WHERE component='jimmdb';
@@ -0,0 +1,13 @@
+-- 1_21.sql is a migration that adds the model_acl_templates table used to
+-- grant groups access to the new models owned by the members of a group.
+CREATE TABLE IF NOT EXISTS model_acl_templates (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	owner_group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+	grant_group_id BIGINT NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+	access TEXT NOT NULL,
+	UNIQUE (owner_group_id, grant_group_id)
+);
+
+UPDATE versions SET major=1, minor=21 WHERE component='jimmdb';
@@ -0,0 +1,17 @@
+-- 1_22.sql is a migration that adds the dead_letter_deltas table used to
+-- record watcher deltas that could not be applied to the database.
+CREATE TABLE IF NOT EXISTS dead_letter_deltas (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	controller_name TEXT NOT NULL,
+	model_uuid TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	entity_id TEXT NOT NULL,
+	removed BOOLEAN NOT NULL DEFAULT FALSE,
+	delta JSONB,
+	error TEXT NOT NULL,
+	attempts INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_dead_letter_deltas_controller_name ON dead_letter_deltas (controller_name);
+
+UPDATE versions SET major=1, minor=22 WHERE component='jimmdb';
@@ -0,0 +1,15 @@
+-- 1_23.sql is a migration that adds the model_tokens table used to
+-- store long-lived tokens granting access to a single model.
+CREATE TABLE IF NOT EXISTS model_tokens (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	access TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	created_by TEXT NOT NULL,
+	secret_hash BYTEA NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_model_tokens_model_id ON model_tokens (model_id);
+
+UPDATE versions SET major=1, minor=23 WHERE component='jimmdb';
@@ -0,0 +1,5 @@
+-- 1_24.sql is a migration that adds the tiers a controller belongs to,
+-- used to place models on appropriately classed controllers.
+ALTER TABLE controllers ADD COLUMN tiers BYTEA;
+
+UPDATE versions SET major=1, minor=24 WHERE component='jimmdb';
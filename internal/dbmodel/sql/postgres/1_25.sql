@@ -0,0 +1,10 @@
+-- 1_25.sql is a migration that adds the model_watcher_states table used
+-- to persist the machines and units the watcher has seen in each model.
+CREATE TABLE IF NOT EXISTS model_watcher_states (
+	model_id BIGINT PRIMARY KEY REFERENCES models (id) ON DELETE CASCADE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	machines BYTEA,
+	units BYTEA
+);
+
+UPDATE versions SET major=1, minor=25 WHERE component='jimmdb';
@@ -0,0 +1,11 @@
+-- 1_26.sql is a migration that adds the model_freezes table recording
+-- the models in which mutating calls are rejected.
+CREATE TABLE IF NOT EXISTS model_freezes (
+	model_id BIGINT PRIMARY KEY REFERENCES models (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE,
+	frozen_by TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	until TIMESTAMP WITH TIME ZONE
+);
+
+UPDATE versions SET major=1, minor=26 WHERE component='jimmdb';
@@ -0,0 +1,5 @@
+-- 1_27.sql is a migration that adds the TOTP secret used to confirm an
+-- identity before sensitive operations.
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS totp_secret TEXT NOT NULL DEFAULT '';
+
+UPDATE versions SET major=1, minor=27 WHERE component='jimmdb';
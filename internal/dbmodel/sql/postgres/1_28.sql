@@ -0,0 +1,15 @@
+-- 1_28.sql is a migration that adds the group_sync_statuses table used
+-- to report the outcome of synchronising group memberships from an
+-- external directory.
+CREATE TABLE IF NOT EXISTS group_sync_statuses (
+	directory TEXT PRIMARY KEY,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	time TIMESTAMP WITH TIME ZONE,
+	created_groups BYTEA,
+	added INTEGER NOT NULL DEFAULT 0,
+	removed INTEGER NOT NULL DEFAULT 0,
+	errors BYTEA,
+	error TEXT NOT NULL DEFAULT ''
+);
+
+UPDATE versions SET major=1, minor=28 WHERE component='jimmdb';
@@ -0,0 +1,10 @@
+-- 1_29.sql is a migration that adds the counts of containers, relations
+-- and application offers in each model, along with the relations and
+-- offers the watcher has seen from which they are derived.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS containers BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS relations BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS offer_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE model_watcher_states ADD COLUMN IF NOT EXISTS offers BYTEA;
+ALTER TABLE model_watcher_states ADD COLUMN IF NOT EXISTS relations BYTEA;
+
+UPDATE versions SET major=1, minor=29 WHERE component='jimmdb';
@@ -0,0 +1,14 @@
+-- 1_30.sql is a migration that adds the controller_model_credentials
+-- table used to track the cloud credential used by each controller's
+-- controller model.
+CREATE TABLE IF NOT EXISTS controller_model_credentials (
+	controller_name TEXT PRIMARY KEY REFERENCES controllers (name) ON DELETE CASCADE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	credential_tag TEXT NOT NULL DEFAULT '',
+	valid BOOLEAN,
+	checked_at TIMESTAMP WITH TIME ZONE,
+	expires_at TIMESTAMP WITH TIME ZONE,
+	rotated_at TIMESTAMP WITH TIME ZONE
+);
+
+UPDATE versions SET major=1, minor=30 WHERE component='jimmdb';
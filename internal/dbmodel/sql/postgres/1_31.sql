@@ -0,0 +1,8 @@
+-- 1_31.sql is a migration that adds the cost center codes that may be
+-- attached to identities, groups and models to attribute the resources
+-- used by models in usage reports.
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS cost_center TEXT NOT NULL DEFAULT '';
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS cost_center TEXT NOT NULL DEFAULT '';
+ALTER TABLE models ADD COLUMN IF NOT EXISTS cost_center TEXT NOT NULL DEFAULT '';
+
+UPDATE versions SET major=1, minor=31 WHERE component='jimmdb';
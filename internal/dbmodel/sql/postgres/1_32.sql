@@ -0,0 +1,15 @@
+-- 1_32.sql is a migration that adds the model_resource_snapshots table
+-- used to record the history of the resources used by each model.
+CREATE TABLE IF NOT EXISTS model_resource_snapshots (
+	id BIGSERIAL PRIMARY KEY,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	time TIMESTAMP WITH TIME ZONE NOT NULL,
+	resolution TEXT NOT NULL,
+	machines BIGINT NOT NULL DEFAULT 0,
+	cores BIGINT NOT NULL DEFAULT 0,
+	units BIGINT NOT NULL DEFAULT 0,
+	UNIQUE (model_id, resolution, time)
+);
+CREATE INDEX IF NOT EXISTS idx_model_resource_snapshots_time ON model_resource_snapshots (time);
+
+UPDATE versions SET major=1, minor=32 WHERE component='jimmdb';
@@ -0,0 +1,6 @@
+-- 1_33.sql is a migration that adds the addresses given to clients of a
+-- controller, where they differ from the addresses JIMM dials, to the
+-- controller table.
+ALTER TABLE controllers ADD COLUMN client_addresses BYTEA;
+
+UPDATE versions SET major=1, minor=33 WHERE component='jimmdb';
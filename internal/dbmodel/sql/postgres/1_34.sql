@@ -0,0 +1,19 @@
+-- 1_34.sql is a migration that adds a table recording the responses to
+-- requests made with idempotency keys, so that retried requests are not
+-- applied twice.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	identity_name TEXT NOT NULL,
+	key TEXT NOT NULL,
+	request_hash TEXT NOT NULL,
+	completed BOOLEAN NOT NULL DEFAULT FALSE,
+	status_code INTEGER NOT NULL DEFAULT 0,
+	content_type TEXT NOT NULL DEFAULT '',
+	body BYTEA,
+	UNIQUE (identity_name, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+UPDATE versions SET major=1, minor=34 WHERE component='jimmdb';
@@ -0,0 +1,21 @@
+-- 1_35.sql is a migration that adds the tables recording the cloud
+-- credential each identity prefers to use for a cloud, either set
+-- explicitly as the default or remembered from the last model created.
+CREATE TABLE IF NOT EXISTS default_cloud_credentials (
+	identity_name TEXT NOT NULL REFERENCES identities (name) ON DELETE CASCADE,
+	cloud_name TEXT NOT NULL REFERENCES clouds (name) ON DELETE CASCADE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	cloud_credential_id BIGINT NOT NULL REFERENCES cloud_credentials (id) ON DELETE CASCADE,
+	PRIMARY KEY (identity_name, cloud_name)
+);
+
+CREATE TABLE IF NOT EXISTS recent_cloud_credentials (
+	identity_name TEXT NOT NULL REFERENCES identities (name) ON DELETE CASCADE,
+	cloud_name TEXT NOT NULL REFERENCES clouds (name) ON DELETE CASCADE,
+	region_name TEXT NOT NULL,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	cloud_credential_id BIGINT NOT NULL REFERENCES cloud_credentials (id) ON DELETE CASCADE,
+	PRIMARY KEY (identity_name, cloud_name, region_name)
+);
+
+UPDATE versions SET major=1, minor=35 WHERE component='jimmdb';
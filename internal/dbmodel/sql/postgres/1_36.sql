@@ -0,0 +1,11 @@
+-- 1_36.sql is a migration that adds a table holding quota limits that
+-- override the default limits for an identity's models.
+CREATE TABLE IF NOT EXISTS identity_quotas (
+	identity_name TEXT NOT NULL PRIMARY KEY REFERENCES identities (name) ON DELETE CASCADE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	models BIGINT,
+	machines BIGINT,
+	cores BIGINT
+);
+
+UPDATE versions SET major=1, minor=36 WHERE component='jimmdb';
@@ -0,0 +1,19 @@
+-- 1_37.sql is a migration that adds the tables recording the models and
+-- application offers listed in the unauthenticated public catalog.
+CREATE TABLE IF NOT EXISTS public_model_listings (
+	model_id BIGINT PRIMARY KEY REFERENCES models (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	purpose TEXT NOT NULL,
+	owner_group TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public_offer_listings (
+	application_offer_id BIGINT PRIMARY KEY REFERENCES application_offers (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	purpose TEXT NOT NULL,
+	owner_group TEXT NOT NULL
+);
+
+UPDATE versions SET major=1, minor=37 WHERE component='jimmdb';
@@ -0,0 +1,7 @@
+-- 1_38.sql is a migration that records the schema version of stored
+-- secrets and model watcher states so that those stored in an older
+-- format can be upgraded.
+ALTER TABLE secrets ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE model_watcher_states ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=38 WHERE component='jimmdb';
@@ -0,0 +1,12 @@
+-- 1_39.sql is a migration that adds the application_charms table
+-- recording the charm used by each application in a model.
+CREATE TABLE IF NOT EXISTS application_charms (
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	application_name TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	charm_url TEXT NOT NULL,
+	PRIMARY KEY (model_id, application_name)
+);
+
+UPDATE versions SET major=1, minor=39 WHERE component='jimmdb';
@@ -0,0 +1,15 @@
+-- 1_40.sql is a migration that adds the credential_update_retries table
+-- queuing the cloud credential updates that failed on a controller so
+-- that they can be retried.
+CREATE TABLE IF NOT EXISTS credential_update_retries (
+	cloud_credential_id BIGINT NOT NULL REFERENCES cloud_credentials (id) ON DELETE CASCADE,
+	controller_id INTEGER NOT NULL REFERENCES controllers (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	next_attempt TIMESTAMP WITH TIME ZONE NOT NULL,
+	PRIMARY KEY (cloud_credential_id, controller_id)
+);
+
+UPDATE versions SET major=1, minor=40 WHERE component='jimmdb';
@@ -0,0 +1,8 @@
+-- 1_41.sql is a migration that records how each model came to be managed
+-- by JIMM.
+
+ALTER TABLE models ADD COLUMN origin_kind TEXT NOT NULL DEFAULT '';
+ALTER TABLE models ADD COLUMN origin_identity_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE models ADD COLUMN origin_time TIMESTAMP WITH TIME ZONE;
+
+UPDATE versions SET major=1, minor=41 WHERE component='jimmdb';
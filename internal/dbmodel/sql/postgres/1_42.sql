@@ -0,0 +1,13 @@
+-- 1_42.sql is a migration that adds the domain_default_clouds table used
+-- to choose the cloud and region for the models of identities in an
+-- identity domain.
+CREATE TABLE IF NOT EXISTS domain_default_clouds (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	domain TEXT NOT NULL UNIQUE,
+	cloud_name TEXT NOT NULL REFERENCES clouds (name) ON DELETE CASCADE,
+	region_name TEXT NOT NULL DEFAULT ''
+);
+
+UPDATE versions SET major=1, minor=42 WHERE component='jimmdb';
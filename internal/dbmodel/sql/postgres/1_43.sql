@@ -0,0 +1,8 @@
+-- 1_43.sql is a migration that records the number of units in each model
+-- with an active, blocked or error workload status, as reported by the
+-- controller watchers.
+ALTER TABLE models ADD COLUMN active_units BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN blocked_units BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE models ADD COLUMN error_units BIGINT NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=43 WHERE component='jimmdb';
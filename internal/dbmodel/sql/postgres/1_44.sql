@@ -0,0 +1,10 @@
+-- 1_44.sql is a migration that adds the identity_tombstones table
+-- recording the anonymous names that replace purged identities.
+CREATE TABLE IF NOT EXISTS identity_tombstones (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	purged_by TEXT NOT NULL
+);
+
+UPDATE versions SET major=1, minor=44 WHERE component='jimmdb';
@@ -0,0 +1,5 @@
+-- 1_45.sql is a migration that adds the maintenance_since column to the
+-- controllers table, recording when a controller entered maintenance.
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS maintenance_since TIMESTAMP WITH TIME ZONE;
+
+UPDATE versions SET major=1, minor=45 WHERE component='jimmdb';
@@ -0,0 +1,16 @@
+-- 1_46.sql is a migration that adds the result_downloads table holding
+-- the results too large to return inline, until they are downloaded or
+-- expire.
+CREATE TABLE IF NOT EXISTS result_downloads (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	token_hash TEXT NOT NULL UNIQUE,
+	identity_name TEXT NOT NULL,
+	name TEXT NOT NULL,
+	content_type TEXT NOT NULL,
+	body BYTEA
+);
+CREATE INDEX IF NOT EXISTS idx_result_downloads_expires_at ON result_downloads (expires_at);
+
+UPDATE versions SET major=1, minor=46 WHERE component='jimmdb';
@@ -0,0 +1,6 @@
+-- 1_47.sql is a migration that adds the consumer_limit column to the
+-- application_offers table, limiting how many models may consume an
+-- offer.
+ALTER TABLE application_offers ADD COLUMN IF NOT EXISTS consumer_limit INTEGER NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=47 WHERE component='jimmdb';
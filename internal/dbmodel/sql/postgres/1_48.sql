@@ -0,0 +1,18 @@
+-- 1_48.sql is a migration that adds the controller_bootstrap_profiles
+-- table, holding versioned records of the configuration each controller
+-- was bootstrapped with so that it may be rebuilt.
+CREATE TABLE IF NOT EXISTS controller_bootstrap_profiles (
+	id SERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	controller_name TEXT NOT NULL REFERENCES controllers (name) ON DELETE CASCADE,
+	version INTEGER NOT NULL,
+	agent_version TEXT NOT NULL DEFAULT '',
+	cloud_name TEXT NOT NULL DEFAULT '',
+	cloud_region TEXT NOT NULL DEFAULT '',
+	controller_config BYTEA,
+	cloud JSONB,
+	hash TEXT NOT NULL,
+	UNIQUE (controller_name, version)
+);
+
+UPDATE versions SET major=1, minor=48 WHERE component='jimmdb';
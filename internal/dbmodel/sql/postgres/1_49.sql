@@ -0,0 +1,17 @@
+-- 1_49.sql is a migration that records the last API activity of each
+-- identity and adds the identity_sessions table, holding the API
+-- sessions currently open for each identity.
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS last_activity TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS identity_sessions (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	identity_name TEXT NOT NULL REFERENCES identities (name) ON DELETE CASCADE,
+	last_activity TIMESTAMP WITH TIME ZONE NOT NULL,
+	remote_address TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_sessions_identity_name ON identity_sessions (identity_name);
+CREATE INDEX IF NOT EXISTS idx_identity_sessions_last_activity ON identity_sessions (last_activity);
+
+UPDATE versions SET major=1, minor=49 WHERE component='jimmdb';
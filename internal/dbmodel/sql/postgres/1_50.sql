@@ -0,0 +1,12 @@
+-- 1_50.sql is a migration that adds the controller_monitors table,
+-- recording which JIMM replica is monitoring each controller so that two
+-- replicas never apply updates from the same controller.
+CREATE TABLE IF NOT EXISTS controller_monitors (
+	controller_name TEXT PRIMARY KEY REFERENCES controllers (name) ON DELETE CASCADE,
+	holder TEXT NOT NULL,
+	nonce TEXT NOT NULL,
+	claimed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	heartbeat TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+UPDATE versions SET major=1, minor=50 WHERE component='jimmdb';
@@ -0,0 +1,6 @@
+-- 1_51.sql is a migration that adds the object_key column to
+-- result_downloads, naming the object holding the body of results kept in
+-- the object store rather than the database.
+ALTER TABLE result_downloads ADD COLUMN IF NOT EXISTS object_key TEXT NOT NULL DEFAULT '';
+
+UPDATE versions SET major=1, minor=51 WHERE component='jimmdb';
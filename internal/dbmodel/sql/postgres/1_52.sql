@@ -0,0 +1,14 @@
+-- 1_52.sql is a migration that adds the model_network_policies table
+-- holding the network egress policy attached to each model and whether
+-- it has been enforced on the model's controller.
+CREATE TABLE IF NOT EXISTS model_network_policies (
+	model_id BIGINT PRIMARY KEY REFERENCES models (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	set_by TEXT NOT NULL,
+	allowed_egress BYTEA,
+	enforced BOOLEAN NOT NULL DEFAULT FALSE,
+	enforcement_error TEXT NOT NULL DEFAULT ''
+);
+
+UPDATE versions SET major=1, minor=52 WHERE component='jimmdb';
@@ -0,0 +1,20 @@
+-- 1_53.sql is a migration that adds the model_migrations table recording
+-- the model migrations JIMM has initiated between its controllers, so
+-- that any JIMM replica can continue monitoring a migration.
+CREATE TABLE IF NOT EXISTS model_migrations (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	source_controller_id BIGINT NOT NULL REFERENCES controllers (id) ON DELETE CASCADE,
+	target_controller_id BIGINT NOT NULL REFERENCES controllers (id) ON DELETE CASCADE,
+	migration_id TEXT NOT NULL,
+	initiated_by TEXT NOT NULL,
+	status TEXT NOT NULL,
+	phase TEXT NOT NULL DEFAULT '',
+	error TEXT NOT NULL DEFAULT '',
+	ended_at TIMESTAMP WITH TIME ZONE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_model_migrations_active ON model_migrations (model_id) WHERE ended_at IS NULL;
+
+UPDATE versions SET major=1, minor=53 WHERE component='jimmdb';
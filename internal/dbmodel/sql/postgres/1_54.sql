@@ -0,0 +1,19 @@
+-- 1_54.sql is a migration that adds the credential_propagations table
+-- recording the controllers each cloud credential has been copied to
+-- and whether the copies have been removed after the credential was
+-- revoked.
+CREATE TABLE IF NOT EXISTS credential_propagations (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	credential TEXT NOT NULL,
+	controller_name TEXT NOT NULL,
+	controller_uuid TEXT NOT NULL,
+	propagated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	jimm_version TEXT NOT NULL,
+	revoked_at TIMESTAMP WITH TIME ZONE,
+	removal_confirmed_at TIMESTAMP WITH TIME ZONE,
+	UNIQUE (credential, controller_name)
+);
+
+UPDATE versions SET major=1, minor=54 WHERE component='jimmdb';
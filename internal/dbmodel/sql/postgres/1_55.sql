@@ -0,0 +1,26 @@
+-- 1_55.sql is a migration that adds the model_pools table, holding the
+-- pools of pre-provisioned models kept ready for each cloud region and
+-- template, and the pooled_models table holding the models ready to be
+-- claimed from each pool.
+CREATE TABLE IF NOT EXISTS model_pools (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	name TEXT NOT NULL UNIQUE,
+	cloud_region_id INTEGER NOT NULL REFERENCES cloud_regions (id) ON DELETE CASCADE,
+	cloud_credential_id INTEGER NOT NULL REFERENCES cloud_credentials (id) ON DELETE CASCADE,
+	config BYTEA,
+	size INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS pooled_models (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	model_pool_id BIGINT NOT NULL REFERENCES model_pools (id) ON DELETE CASCADE,
+	controller_id INTEGER NOT NULL REFERENCES controllers (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	uuid TEXT NOT NULL UNIQUE
+);
+CREATE INDEX IF NOT EXISTS idx_pooled_models_model_pool_id ON pooled_models (model_pool_id);
+
+UPDATE versions SET major=1, minor=55 WHERE component='jimmdb';
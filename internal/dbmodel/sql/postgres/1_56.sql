@@ -0,0 +1,6 @@
+-- 1_56.sql is a migration that records why a controller is unavailable,
+-- as a reason category and the error JIMM encountered connecting to it.
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS unavailable_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS unavailable_error TEXT NOT NULL DEFAULT '';
+
+UPDATE versions SET major=1, minor=56 WHERE component='jimmdb';
@@ -0,0 +1,6 @@
+-- 1_57.sql is a migration that records when the units of a model last
+-- changed and when a user last connected to it, used to find idle models.
+ALTER TABLE models ADD COLUMN IF NOT EXISTS last_unit_change_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS last_connection_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE versions SET major=1, minor=57 WHERE component='jimmdb';
@@ -0,0 +1,16 @@
+-- 1_58.sql is a migration that adds the pending_model_destroys table
+-- recording the models waiting to be destroyed once their destroy
+-- confirmation window has passed.
+CREATE TABLE IF NOT EXISTS pending_model_destroys (
+	model_id BIGINT PRIMARY KEY REFERENCES models (id) ON DELETE CASCADE,
+	created_at TIMESTAMP WITH TIME ZONE,
+	requested_by TEXT NOT NULL,
+	destroy_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	destroy_storage BOOLEAN,
+	force BOOLEAN,
+	max_wait BIGINT,
+	timeout BIGINT
+);
+CREATE INDEX IF NOT EXISTS pending_model_destroys_destroy_at ON pending_model_destroys (destroy_at);
+
+UPDATE versions SET major=1, minor=58 WHERE component='jimmdb';
@@ -0,0 +1,18 @@
+-- 1_59.sql is a migration that records whether applications are exposed
+-- and the endpoints, with their interfaces, of the applications seen in
+-- relations, used to search for applications across all models.
+ALTER TABLE application_charms ADD COLUMN IF NOT EXISTS exposed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS application_endpoints (
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	application_name TEXT NOT NULL,
+	name TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	role TEXT NOT NULL,
+	interface TEXT NOT NULL,
+	PRIMARY KEY (model_id, application_name, name)
+);
+CREATE INDEX IF NOT EXISTS application_endpoints_interface ON application_endpoints (interface);
+
+UPDATE versions SET major=1, minor=59 WHERE component='jimmdb';
@@ -0,0 +1,23 @@
+-- 1_60.sql is a migration that adds a table recording the models that
+-- have been removed from JIMM, so that recently removed models can be
+-- inspected and restored.
+CREATE TABLE IF NOT EXISTS deleted_models (
+	id BIGSERIAL PRIMARY KEY,
+	deleted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	model_uuid TEXT NOT NULL,
+	name TEXT NOT NULL,
+	owner_identity_name TEXT NOT NULL,
+	controller_name TEXT NOT NULL,
+	cloud_name TEXT NOT NULL,
+	cloud_region_name TEXT NOT NULL,
+	cloud_credential_name TEXT NOT NULL,
+	type TEXT NOT NULL,
+	life TEXT NOT NULL,
+	cost_center TEXT NOT NULL DEFAULT '',
+	origin_kind TEXT NOT NULL DEFAULT '',
+	model_created_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS deleted_models_model_uuid ON deleted_models (model_uuid);
+CREATE INDEX IF NOT EXISTS deleted_models_deleted_at ON deleted_models (deleted_at);
+
+UPDATE versions SET major=1, minor=60 WHERE component='jimmdb';
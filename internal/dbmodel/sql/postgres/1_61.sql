@@ -0,0 +1,7 @@
+-- 1_61.sql is a migration that records the agent versions reported by
+-- the machines and units in each model, so that models running agents
+-- older than their controller can be found.
+ALTER TABLE model_watcher_states ADD COLUMN IF NOT EXISTS agent_versions BYTEA;
+ALTER TABLE models ADD COLUMN IF NOT EXISTS agent_versions BYTEA;
+
+UPDATE versions SET major=1, minor=61 WHERE component='jimmdb';
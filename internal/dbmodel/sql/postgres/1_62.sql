@@ -0,0 +1,10 @@
+-- 1_62.sql is a migration that records the verification that the
+-- controllers a revoked cloud credential was copied to have removed
+-- their copies, and whether an alert has been sent for copies that were
+-- not removed in time.
+ALTER TABLE credential_propagations ADD COLUMN IF NOT EXISTS verification_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE credential_propagations ADD COLUMN IF NOT EXISTS last_verified_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE credential_propagations ADD COLUMN IF NOT EXISTS verification_error TEXT NOT NULL DEFAULT '';
+ALTER TABLE credential_propagations ADD COLUMN IF NOT EXISTS alerted_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE versions SET major=1, minor=62 WHERE component='jimmdb';
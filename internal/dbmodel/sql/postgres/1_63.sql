@@ -0,0 +1,22 @@
+-- 1_63.sql is a migration that adds the settings and run history of the
+-- jobs run by the scheduler.
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+	name TEXT NOT NULL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	enabled BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	job_name TEXT NOT NULL,
+	holder TEXT NOT NULL DEFAULT '',
+	started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+	finished_at TIMESTAMP WITH TIME ZONE,
+	error TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job_name_started_at ON scheduled_job_runs (job_name, started_at);
+
+UPDATE versions SET major=1, minor=63 WHERE component='jimmdb';
@@ -0,0 +1,15 @@
+-- 1_64.sql is a migration that adds the model_dependencies table
+-- recording the models that other models have declared they depend on.
+CREATE TABLE IF NOT EXISTS model_dependencies (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	model_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	depends_on_id BIGINT NOT NULL REFERENCES models (id) ON DELETE CASCADE,
+	created_by TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	UNIQUE (model_id, depends_on_id),
+	CHECK (model_id <> depends_on_id)
+);
+CREATE INDEX IF NOT EXISTS model_dependencies_depends_on_id ON model_dependencies (depends_on_id);
+
+UPDATE versions SET major=1, minor=64 WHERE component='jimmdb';
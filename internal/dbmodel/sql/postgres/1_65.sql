@@ -0,0 +1,12 @@
+-- 1_65.sql is a migration that adds the cloud_region_aliases table
+-- recording the old names of cloud regions that have been remapped.
+CREATE TABLE IF NOT EXISTS cloud_region_aliases (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	cloud_name TEXT NOT NULL REFERENCES clouds (name) ON DELETE CASCADE,
+	alias TEXT NOT NULL,
+	region_name TEXT NOT NULL,
+	UNIQUE (cloud_name, alias)
+);
+
+UPDATE versions SET major=1, minor=65 WHERE component='jimmdb';
@@ -0,0 +1,24 @@
+-- 1_66.sql is a migration that adds the webhook_secrets table holding
+-- the secrets webhook deliveries are signed with, and the
+-- webhook_deliveries table recording the signed deliveries.
+CREATE TABLE IF NOT EXISTS webhook_secrets (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	webhook TEXT NOT NULL UNIQUE,
+	secret BYTEA NOT NULL,
+	previous_secret BYTEA,
+	rotated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	webhook TEXT NOT NULL,
+	nonce TEXT NOT NULL UNIQUE,
+	timestamp BIGINT NOT NULL,
+	signature TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_created_at ON webhook_deliveries (webhook, created_at);
+
+UPDATE versions SET major=1, minor=66 WHERE component='jimmdb';
@@ -0,0 +1,19 @@
+-- 1_67.sql is a migration that adds the model_imports table recording
+-- the imports of models exported from other JIMM deployments.
+CREATE TABLE IF NOT EXISTS model_imports (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	model_uuid TEXT NOT NULL UNIQUE,
+	model_name TEXT NOT NULL,
+	source_jimm TEXT NOT NULL,
+	controller_id BIGINT NOT NULL REFERENCES controllers (id) ON DELETE CASCADE,
+	owner_identity_name TEXT NOT NULL,
+	user_access BYTEA,
+	group_access BYTEA,
+	status TEXT NOT NULL,
+	prepared_by TEXT NOT NULL,
+	completed_at TIMESTAMP WITH TIME ZONE
+);
+
+UPDATE versions SET major=1, minor=67 WHERE component='jimmdb';
@@ -0,0 +1,27 @@
+-- 1_68.sql is a migration that adds the operations table recording the
+-- progress of long-running operations, and the log of their steps.
+CREATE TABLE IF NOT EXISTS operations (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	updated_at TIMESTAMP WITH TIME ZONE,
+	uuid TEXT NOT NULL UNIQUE,
+	kind TEXT NOT NULL,
+	identity_name TEXT NOT NULL,
+	status TEXT NOT NULL,
+	progress INTEGER NOT NULL DEFAULT 0,
+	error TEXT NOT NULL DEFAULT '',
+	result JSONB,
+	ended_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS operations_identity_name ON operations (identity_name);
+CREATE INDEX IF NOT EXISTS operations_ended_at ON operations (ended_at);
+
+CREATE TABLE IF NOT EXISTS operation_steps (
+	id BIGSERIAL PRIMARY KEY,
+	created_at TIMESTAMP WITH TIME ZONE,
+	operation_id BIGINT NOT NULL REFERENCES operations (id) ON DELETE CASCADE,
+	message TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS operation_steps_operation_id ON operation_steps (operation_id);
+
+UPDATE versions SET major=1, minor=68 WHERE component='jimmdb';
@@ -0,0 +1,5 @@
+-- 1_69.sql is a migration that records the last TOTP time step accepted
+-- for each identity so that TOTP codes cannot be replayed.
+ALTER TABLE identities ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+UPDATE versions SET major=1, minor=69 WHERE component='jimmdb';
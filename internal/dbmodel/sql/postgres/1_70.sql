@@ -0,0 +1,5 @@
+-- 1_70.sql is a migration that records the controllers on which JIMM
+-- failed to provision its service user.
+ALTER TABLE controllers ADD COLUMN IF NOT EXISTS service_user_pending BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE versions SET major=1, minor=70 WHERE component='jimmdb';
@@ -136,6 +136,42 @@ func (m *StringMap) Scan(src interface{}) error {
 	return json.Unmarshal(buf, m)
 }
 
+// An Int64Map is a data type that flattens a map of string to int64 into
+// a single column. The map is encoded as a JSON object and stored in a
+// BLOB data type.
+type Int64Map map[string]int64
+
+// GormDataType implements schema.GormDataTypeInterface.
+func (m Int64Map) GormDataType() string {
+	return "bytes"
+}
+
+// Value implements driver.Valuer.
+func (m Int64Map) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner.
+func (m *Int64Map) Scan(src interface{}) error {
+	if src == nil {
+		*m = nil
+		return nil
+	}
+	var buf []byte
+	switch v := src.(type) {
+	case []byte:
+		buf = v
+	case string:
+		buf = []byte(v)
+	default:
+		return fmt.Errorf("cannot unmarshal %T as Int64Map", src)
+	}
+	return json.Unmarshal(buf, m)
+}
+
 // A Map stores a generic map in a database column. The map is encoded as
 // JSON and stored in a BLOB element.
 type Map map[string]interface{}
@@ -211,6 +247,42 @@ func (hp *HostPorts) Scan(src interface{}) error {
 	return json.Unmarshal(buf, hp)
 }
 
+// Addresses is data type that stores a set of jujuparams.Address in a
+// single column. The addresses are encoded as JSON and stored in a BLOB
+// value.
+type Addresses []jujuparams.Address
+
+// GormDataType implements schema.GormDataTypeInterface.
+func (Addresses) GormDataType() string {
+	return "bytes"
+}
+
+// Value implements driver.Valuer.
+func (a Addresses) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements sql.Scanner.
+func (a *Addresses) Scan(src interface{}) error {
+	if src == nil {
+		*a = nil
+		return nil
+	}
+	var buf []byte
+	switch v := src.(type) {
+	case []byte:
+		buf = v
+	case string:
+		buf = []byte(v)
+	default:
+		return fmt.Errorf("cannot unmarshal %T as Addresses", src)
+	}
+	return json.Unmarshal(buf, a)
+}
+
 // SetNullString sets ns to a valid string with the value of *s if s is not
 // nil, otherwise ns is set to be invalid.
 func SetNullString(ns *sql.NullString, s *string) {
@@ -101,6 +101,32 @@ func TestStringMapScan(t *testing.T) {
 	c.Check(err, qt.ErrorMatches, `cannot unmarshal int as StringMap`)
 }
 
+func TestInt64MapValue(t *testing.T) {
+	c := qt.New(t)
+
+	var m dbmodel.Int64Map
+	c.Check(m.GormDataType(), qt.Equals, "bytes")
+	v, err := m.Value()
+	c.Assert(err, qt.IsNil)
+	c.Check(v, qt.Equals, nil)
+
+	m = dbmodel.Int64Map{"0": 4, "1": 0}
+	v, err = m.Value()
+	c.Assert(err, qt.IsNil)
+
+	var m2 dbmodel.Int64Map
+	err = m2.Scan(v)
+	c.Assert(err, qt.IsNil)
+	c.Check(m2, qt.DeepEquals, m)
+
+	err = m2.Scan(nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(m2, qt.IsNil)
+
+	err = m2.Scan(0)
+	c.Check(err, qt.ErrorMatches, `cannot unmarshal int as Int64Map`)
+}
+
 func TestMapGormDataType(t *testing.T) {
 	c := qt.New(t)
 
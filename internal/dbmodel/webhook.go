@@ -0,0 +1,64 @@
+// Copyright 2024 Canonical.
+
+package dbmodel
+
+import (
+	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A WebhookSecret holds the secret the deliveries to a webhook are
+// signed with.
+type WebhookSecret struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Webhook is the name of the webhook.
+	Webhook string
+
+	// Secret is the current signing secret. It is encrypted when an
+	// encryption key is configured, see db.Database.Encrypter.
+	Secret []byte
+
+	// PreviousSecret is the signing secret replaced by the last
+	// rotation. Deliveries are also signed with this secret for a grace
+	// period after the rotation, so that receivers can be updated. It
+	// is encrypted in the same way as Secret.
+	PreviousSecret []byte
+
+	// RotatedAt is the time the secret was last rotated.
+	RotatedAt time.Time
+}
+
+// A WebhookDelivery records a signed delivery to a webhook.
+type WebhookDelivery struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+
+	// Webhook is the name of the webhook.
+	Webhook string
+
+	// Nonce is the value unique to the delivery.
+	Nonce string
+
+	// Timestamp is the time the delivery was signed, in seconds since
+	// the Unix epoch.
+	Timestamp int64
+
+	// Signature is the value of the signature header of the delivery.
+	Signature string
+}
+
+// ToAPIWebhookDelivery converts a webhook delivery to its API
+// representation.
+func (d WebhookDelivery) ToAPIWebhookDelivery() apiparams.WebhookDelivery {
+	return apiparams.WebhookDelivery{
+		Webhook:   d.Webhook,
+		Nonce:     d.Nonce,
+		Timestamp: d.Timestamp,
+		Signature: d.Signature,
+		CreatedAt: d.CreatedAt,
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
 var defaultDischargeExpiry = 15 * time.Minute
@@ -98,7 +99,19 @@ func GetDischargerMux(macaroonDischarger *MacaroonDischarger, rootPath string) *
 // a declared caveat declaring offer uuid:
 //
 //	declared offer-uuid <offer uuid>
-func (md *MacaroonDischarger) CheckThirdPartyCaveat(ctx context.Context, req *http.Request, cavInfo *bakery.ThirdPartyCaveatInfo, _ *httpbakery.DischargeToken) ([]checkers.Caveat, error) {
+func (md *MacaroonDischarger) CheckThirdPartyCaveat(ctx context.Context, req *http.Request, cavInfo *bakery.ThirdPartyCaveatInfo, _ *httpbakery.DischargeToken) (_ []checkers.Caveat, err error) {
+	start := time.Now()
+	defer func() {
+		servermon.MacaroonDischargeDurationHistogram.Observe(time.Since(start).Seconds())
+		result := "granted"
+		if err == httpbakery.ErrPermissionDenied {
+			result = "denied"
+		} else if err != nil {
+			result = "error"
+		}
+		servermon.MacaroonDischargeCount.WithLabelValues(result).Inc()
+	}()
+
 	caveatTokens := strings.Split(string(cavInfo.Condition), " ")
 	if len(caveatTokens) != 3 {
 		zapctx.Error(ctx, "caveat token length incorrect", zap.Int("length", len(caveatTokens)))
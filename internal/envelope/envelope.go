@@ -0,0 +1,189 @@
+// Copyright 2024 Canonical.
+
+// Package envelope provides envelope encryption of secret material stored
+// in the JIMM database. Each value is encrypted with its own random data
+// key, which is in turn encrypted ("wrapped") by a master key held
+// outside the database. The master key can be rotated by re-wrapping the
+// data keys, without re-encrypting the values themselves.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// dataKeySize is the size, in bytes, of the AES-256 data keys used to
+// encrypt values.
+const dataKeySize = 32
+
+// version is the current envelope format version.
+const version = 1
+
+// envelopeKey is the single key of the JSON object that holds a sealed
+// value, it is used to distinguish sealed values from plaintext ones.
+const envelopeKey = "jimm-envelope"
+
+// A KeyWrapper wraps and unwraps data keys using a master key. A
+// KeyWrapper may be backed by a local key file, see Keyring, or by an
+// external key management service.
+type KeyWrapper interface {
+	// PrimaryKeyID returns the ID of the master key that WrapKey
+	// currently uses.
+	PrimaryKeyID() string
+
+	// WrapKey encrypts the given data key with the primary master key,
+	// returning the ID of the master key used and the wrapped key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+
+	// UnwrapKey decrypts a data key wrapped by the master key with the
+	// given ID.
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// An Encrypter seals and opens values using envelope encryption.
+type Encrypter struct {
+	// Keys holds the master keys used to wrap data keys.
+	Keys KeyWrapper
+}
+
+// envelope is the stored form of a sealed value.
+type envelope struct {
+	Version    int    `json:"version"`
+	KeyID      string `json:"key-id"`
+	DataKey    []byte `json:"data-key"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// Seal encrypts the given plaintext with a new data key. The additional
+// data is authenticated but not stored, the same additional data must be
+// given to Open. The returned value is a JSON document.
+func (e *Encrypter) Seal(ctx context.Context, plaintext, additionalData []byte) ([]byte, error) {
+	const op = errors.Op("envelope.Seal")
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.E(op, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.E(op, err)
+	}
+	keyID, wrapped, err := e.Keys.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	env := envelope{
+		Version:    version,
+		KeyID:      keyID,
+		DataKey:    wrapped,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, additionalData),
+	}
+	buf, err := json.Marshal(map[string]envelope{envelopeKey: env})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return buf, nil
+}
+
+// Open decrypts a value sealed by Seal with the same additional data.
+func (e *Encrypter) Open(ctx context.Context, sealed, additionalData []byte) ([]byte, error) {
+	const op = errors.Op("envelope.Open")
+
+	env, err := parse(sealed)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	dataKey, err := e.Keys.UnwrapKey(ctx, env.KeyID, env.DataKey)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, errors.E(op, "invalid envelope nonce")
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.E(op, "cannot decrypt value", err)
+	}
+	return plaintext, nil
+}
+
+// Rewrap re-wraps the data key of a sealed value with the current
+// primary master key. The encrypted value itself is unchanged. If the
+// data key is already wrapped with the primary key the value is returned
+// unchanged and rewrapped is false.
+func (e *Encrypter) Rewrap(ctx context.Context, sealed []byte) (_ []byte, rewrapped bool, err error) {
+	const op = errors.Op("envelope.Rewrap")
+
+	env, err := parse(sealed)
+	if err != nil {
+		return nil, false, errors.E(op, err)
+	}
+	if env.KeyID == e.Keys.PrimaryKeyID() {
+		return sealed, false, nil
+	}
+	dataKey, err := e.Keys.UnwrapKey(ctx, env.KeyID, env.DataKey)
+	if err != nil {
+		return nil, false, errors.E(op, err)
+	}
+	env.KeyID, env.DataKey, err = e.Keys.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, false, errors.E(op, err)
+	}
+	buf, err := json.Marshal(map[string]envelope{envelopeKey: *env})
+	if err != nil {
+		return nil, false, errors.E(op, err)
+	}
+	return buf, true, nil
+}
+
+// IsSealed reports whether the given value was produced by Seal.
+func IsSealed(data []byte) bool {
+	if !bytes.Contains(data, []byte(envelopeKey)) {
+		return false
+	}
+	_, err := parse(data)
+	return err == nil
+}
+
+func parse(data []byte) (*envelope, error) {
+	var v map[string]*envelope
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.E("invalid envelope", err)
+	}
+	env := v[envelopeKey]
+	if len(v) != 1 || env == nil {
+		return nil, errors.E("invalid envelope")
+	}
+	if env.Version != version {
+		return nil, errors.E("unsupported envelope version")
+	}
+	return env, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, errors.E("invalid data key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
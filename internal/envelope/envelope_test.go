@@ -0,0 +1,140 @@
+// Copyright 2024 Canonical.
+
+package envelope_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/envelope"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func newKeyring(c *qt.C, ids ...string) *envelope.Keyring {
+	keys := make(map[string][]byte)
+	for _, id := range ids {
+		key := sha256.Sum256([]byte(id))
+		keys[id] = key[:]
+	}
+	kr, err := envelope.NewKeyring(ids, keys)
+	c.Assert(err, qt.IsNil)
+	return kr
+}
+
+func TestSealOpen(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	e := envelope.Encrypter{Keys: newKeyring(c, "key-1")}
+	sealed, err := e.Seal(ctx, []byte(`{"password":"secret"}`), []byte("cloudcred/test"))
+	c.Assert(err, qt.IsNil)
+	c.Check(envelope.IsSealed(sealed), qt.IsTrue)
+	c.Check(bytes.Contains(sealed, []byte("secret")), qt.IsFalse)
+
+	plaintext, err := e.Open(ctx, sealed, []byte("cloudcred/test"))
+	c.Assert(err, qt.IsNil)
+	c.Check(string(plaintext), qt.Equals, `{"password":"secret"}`)
+
+	// A value cannot be opened in the context of a different record.
+	_, err = e.Open(ctx, sealed, []byte("cloudcred/other"))
+	c.Check(err, qt.ErrorMatches, `cannot decrypt value.*`)
+
+	// Each value has its own data key.
+	sealed2, err := e.Seal(ctx, []byte(`{"password":"secret"}`), []byte("cloudcred/test"))
+	c.Assert(err, qt.IsNil)
+	c.Check(sealed2, qt.Not(qt.DeepEquals), sealed)
+}
+
+func TestIsSealed(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(envelope.IsSealed(nil), qt.IsFalse)
+	c.Check(envelope.IsSealed([]byte(`{"username":"bob","password":"pw"}`)), qt.IsFalse)
+	c.Check(envelope.IsSealed([]byte(`{"jimm-envelope":"x"}`)), qt.IsFalse)
+	c.Check(envelope.IsSealed([]byte(`"jimm-envelope"`)), qt.IsFalse)
+	c.Check(envelope.IsSealed([]byte(`{"jimm-envelope":{"version":1}}`)), qt.IsTrue)
+}
+
+func TestRewrap(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	old := envelope.Encrypter{Keys: newKeyring(c, "key-1")}
+	sealed, err := old.Seal(ctx, []byte("value"), nil)
+	c.Assert(err, qt.IsNil)
+
+	// The value is already wrapped with the primary key.
+	out, rewrapped, err := old.Rewrap(ctx, sealed)
+	c.Assert(err, qt.IsNil)
+	c.Check(rewrapped, qt.IsFalse)
+	c.Check(out, qt.DeepEquals, sealed)
+
+	// Rotate the master key, the old key is still available for
+	// unwrapping.
+	rotated := envelope.Encrypter{Keys: newKeyring(c, "key-2", "key-1")}
+	plaintext, err := rotated.Open(ctx, sealed, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(plaintext), qt.Equals, "value")
+
+	out, rewrapped, err = rotated.Rewrap(ctx, sealed)
+	c.Assert(err, qt.IsNil)
+	c.Check(rewrapped, qt.IsTrue)
+
+	// Once re-wrapped the old key is no longer needed.
+	current := envelope.Encrypter{Keys: newKeyring(c, "key-2")}
+	plaintext, err = current.Open(ctx, out, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(plaintext), qt.Equals, "value")
+
+	_, err = current.Open(ctx, sealed, nil)
+	c.Check(err, qt.ErrorMatches, `master key "key-1" not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestNewKeyringErrors(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := envelope.NewKeyring(nil, nil)
+	c.Check(err, qt.ErrorMatches, `no master keys`)
+
+	_, err = envelope.NewKeyring([]string{"key-1"}, map[string][]byte{"key-1": []byte("short")})
+	c.Check(err, qt.ErrorMatches, `master key "key-1" must be 32 bytes`)
+
+	_, err = envelope.NewKeyring([]string{"key-1", "key-1"}, map[string][]byte{"key-1": make([]byte, 32)})
+	c.Check(err, qt.ErrorMatches, `duplicate master key "key-1"`)
+}
+
+func TestLoadKeyFile(t *testing.T) {
+	c := qt.New(t)
+
+	key1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	key2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	path := filepath.Join(c.TempDir(), "keys")
+	err := os.WriteFile(path, []byte("# rotated 2024-06-01\nkey-2 "+key2+"\n\nkey-1\t"+key1+"\n"), 0600)
+	c.Assert(err, qt.IsNil)
+
+	kr, err := envelope.LoadKeyFile(path)
+	c.Assert(err, qt.IsNil)
+	c.Check(kr.PrimaryKeyID(), qt.Equals, "key-2")
+
+	err = os.WriteFile(path, []byte("key-1\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	_, err = envelope.LoadKeyFile(path)
+	c.Check(err, qt.ErrorMatches, `.*keys:1: expected key ID and key`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+
+	err = os.WriteFile(path, []byte("key-1 "+key1+"\nkey-1 "+key2+"\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	_, err = envelope.LoadKeyFile(path)
+	c.Check(err, qt.ErrorMatches, `.*keys:2: duplicate key "key-1"`)
+
+	_, err = envelope.LoadKeyFile(filepath.Join(c.TempDir(), "missing"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeServerConfiguration)
+}
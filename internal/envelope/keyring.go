@@ -0,0 +1,146 @@
+// Copyright 2024 Canonical.
+
+package envelope
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A Keyring is a KeyWrapper that holds its master keys in memory. The
+// first key is the primary key, used to wrap new data keys, the
+// remaining keys are only used to unwrap data keys wrapped before the
+// primary key was rotated.
+type Keyring struct {
+	ids  []string
+	keys map[string][]byte
+}
+
+// NewKeyring creates a new Keyring holding the given master keys, which
+// must be 32 byte AES-256 keys. The first key in ids is the primary key.
+func NewKeyring(ids []string, keys map[string][]byte) (*Keyring, error) {
+	const op = errors.Op("envelope.NewKeyring")
+
+	if len(ids) == 0 {
+		return nil, errors.E(op, errors.CodeServerConfiguration, "no master keys")
+	}
+	kr := Keyring{
+		keys: make(map[string][]byte, len(ids)),
+	}
+	for _, id := range ids {
+		if id == "" {
+			return nil, errors.E(op, errors.CodeServerConfiguration, "empty master key id")
+		}
+		if _, ok := kr.keys[id]; ok {
+			return nil, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("duplicate master key %q", id))
+		}
+		if len(keys[id]) != dataKeySize {
+			return nil, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("master key %q must be %d bytes", id, dataKeySize))
+		}
+		kr.ids = append(kr.ids, id)
+		kr.keys[id] = keys[id]
+	}
+	return &kr, nil
+}
+
+// LoadKeyFile reads a Keyring from the given file. Each non-empty line of
+// the file that does not start with "#" holds a key ID and a base64
+// encoded 32 byte key separated by whitespace. The first key in the file
+// is the primary key. To rotate the master key add a new key to the
+// start of the file, keeping the old keys until all data keys have been
+// re-wrapped by db.Database.RewrapSecrets, which JIMM's leader runs when
+// it starts.
+func LoadKeyFile(path string) (*Keyring, error) {
+	const op = errors.Op("envelope.LoadKeyFile")
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeServerConfiguration, err)
+	}
+	var ids []string
+	keys := make(map[string][]byte)
+	sc := bufio.NewScanner(bytes.NewReader(buf))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("%s:%d: expected key ID and key", path, n))
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil {
+			return nil, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("%s:%d: invalid key", path, n))
+		}
+		if _, ok := keys[fields[0]]; ok {
+			return nil, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("%s:%d: duplicate key %q", path, n, fields[0]))
+		}
+		ids = append(ids, fields[0])
+		keys[fields[0]] = key
+	}
+	if err := sc.Err(); err != nil {
+		return nil, errors.E(op, errors.CodeServerConfiguration, err)
+	}
+	kr, err := NewKeyring(ids, keys)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return kr, nil
+}
+
+// PrimaryKeyID implements KeyWrapper.
+func (kr *Keyring) PrimaryKeyID() string {
+	return kr.ids[0]
+}
+
+// WrapKey implements KeyWrapper, the data key is encrypted with the
+// primary master key using AES-GCM.
+func (kr *Keyring) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	const op = errors.Op("envelope.WrapKey")
+
+	id := kr.PrimaryKeyID()
+	aead, err := newAEAD(kr.keys[id])
+	if err != nil {
+		return "", nil, errors.E(op, err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, errors.E(op, err)
+	}
+	// The key ID is authenticated so that a wrapped key cannot be
+	// attributed to a different master key.
+	return id, aead.Seal(nonce, nonce, dataKey, []byte(id)), nil
+}
+
+// UnwrapKey implements KeyWrapper.
+func (kr *Keyring) UnwrapKey(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	const op = errors.Op("envelope.UnwrapKey")
+
+	key, ok := kr.keys[keyID]
+	if !ok {
+		return nil, errors.E(op, errors.CodeNotFound, fmt.Sprintf("master key %q not found", keyID))
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.E(op, "invalid wrapped key")
+	}
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, errors.E(op, "cannot unwrap data key", err)
+	}
+	return dataKey, nil
+}
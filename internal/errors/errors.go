@@ -109,17 +109,25 @@ const (
 	CodeAlreadyExists                Code = jujuparams.CodeAlreadyExists
 	CodeBadRequest                   Code = jujuparams.CodeBadRequest
 	CodeCloudRegionRequired          Code = jujuparams.CodeCloudRegionRequired
+	CodeConfirmationRequired         Code = apiparams.CodeConfirmationRequired
 	CodeConnectionFailed             Code = "connection failed"
 	CodeDatabaseLocked               Code = "database locked"
 	CodeForbidden                    Code = jujuparams.CodeForbidden
 	CodeIncompatibleClouds           Code = jujuparams.CodeIncompatibleClouds
+	CodeModelCreationCancelled       Code = apiparams.CodeModelCreationCancelled
+	CodeModelFrozen                  Code = apiparams.CodeModelFrozen
+	CodeModelHasDependents           Code = apiparams.CodeModelHasDependents
 	CodeModelNotFound                Code = jujuparams.CodeModelNotFound
+	CodeMonitorConflict              Code = "monitor conflict"
 	CodeNotFound                     Code = jujuparams.CodeNotFound
 	CodeNotImplemented               Code = jujuparams.CodeNotImplemented
 	CodeNotSupported                 Code = jujuparams.CodeNotSupported
+	CodeQuotaLimitExceeded           Code = jujuparams.CodeQuotaLimitExceeded
 	CodeRedirect                     Code = jujuparams.CodeRedirect
+	CodeResultTooLarge               Code = apiparams.CodeResultTooLarge
 	CodeServerConfiguration          Code = "server configuration"
 	CodeStillAlive                   Code = apiparams.CodeStillAlive
+	CodeTryAgain                     Code = jujuparams.CodeTryAgain
 	CodeUnauthorized                 Code = jujuparams.CodeUnauthorized
 	CodeSessionTokenInvalid          Code = jujuparams.CodeSessionTokenInvalid
 	CodeUpgradeInProgress            Code = jujuparams.CodeUpgradeInProgress
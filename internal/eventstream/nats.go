@@ -0,0 +1,272 @@
+// Copyright 2024 Canonical.
+
+// Package eventstream publishes messages to external event streams, for
+// consumers that build their own analytics on JIMM's activity.
+package eventstream
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// natsDialTimeout is the maximum time allowed to connect to a NATS server
+// when the context has no deadline.
+const natsDialTimeout = 30 * time.Second
+
+// A NATS publishes messages to a NATS server using the NATS client
+// protocol. The connection is made when the first message is published
+// and is made again after it fails.
+type NATS struct {
+	address   string
+	scheme    string
+	user      *url.Userinfo
+	tlsConfig *tls.Config
+
+	mu   sync.Mutex
+	conn *natsConn
+}
+
+// natsConn is a connection to a NATS server.
+type natsConn struct {
+	conn net.Conn
+	w    *bufio.Writer
+
+	// done is closed when the connection fails.
+	done chan struct{}
+}
+
+// NewNATS returns a NATS publishing to the server at the given URL, for
+// example "nats://nats.example.com:4222". A "tls" scheme requires TLS,
+// which is also used when the server requires it. The user information
+// in the URL authenticates with the server, either as a token or as a
+// user name and password. The given TLS configuration is used for TLS
+// connections, if it is nil the default configuration is used.
+func NewNATS(natsURL string, tlsConfig *tls.Config) (*NATS, error) {
+	const op = errors.Op("eventstream.NewNATS")
+
+	u, err := url.Parse(natsURL)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeBadRequest, err)
+	}
+	switch u.Scheme {
+	case "nats", "tls":
+	default:
+		return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("unsupported NATS URL scheme %q", u.Scheme))
+	}
+	if u.Hostname() == "" {
+		return nil, errors.E(op, errors.CodeBadRequest, "NATS URL has no host")
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	tlsConfig = tlsConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	return &NATS{
+		address:   address,
+		scheme:    u.Scheme,
+		user:      u.User,
+		tlsConfig: tlsConfig,
+	}, nil
+}
+
+// Publish publishes the given data to the given subject. If the
+// connection to the server has failed it is made again first.
+func (n *NATS) Publish(ctx context.Context, subject string, data []byte) error {
+	const op = errors.Op("eventstream.Publish")
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn != nil {
+		select {
+		case <-n.conn.done:
+			n.conn = nil
+		default:
+		}
+	}
+	if n.conn == nil {
+		conn, err := n.dial(ctx)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		n.conn = conn
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := n.conn.conn.SetWriteDeadline(deadline); err != nil {
+		return errors.E(op, err)
+	}
+	fmt.Fprintf(n.conn.w, "PUB %s %d\r\n", subject, len(data))
+	n.conn.w.Write(data)
+	n.conn.w.WriteString("\r\n")
+	if err := n.conn.w.Flush(); err != nil {
+		n.conn.conn.Close()
+		n.conn = nil
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// Close closes the connection to the server, if there is one.
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.conn.Close()
+	n.conn = nil
+	return err
+}
+
+// dial connects to the server and completes the protocol handshake. It
+// must be called with n.mu held.
+func (n *NATS) dial(ctx context.Context) (*natsConn, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, natsDialTimeout)
+		defer cancel()
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", n.address)
+	if err != nil {
+		return nil, err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return nil, errors.E(fmt.Sprintf("unexpected NATS greeting %q", strings.TrimSpace(line)))
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	if err := json.Unmarshal([]byte(line[len("INFO "):]), &info); err != nil {
+		conn.Close()
+		return nil, errors.E(err, "cannot parse NATS server info")
+	}
+	if info.TLSRequired || n.scheme == "tls" {
+		tconn := tls.Client(conn, n.tlsConfig)
+		if err := tconn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tconn
+		r = bufio.NewReader(conn)
+	}
+
+	connect := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "jimm",
+		"lang":     "go",
+		"protocol": 0,
+	}
+	if n.user != nil {
+		if password, ok := n.user.Password(); ok {
+			connect["user"] = n.user.Username()
+			connect["pass"] = password
+		} else {
+			connect["auth_token"] = n.user.Username()
+		}
+	}
+	buf, err := json.Marshal(connect)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "CONNECT %s\r\nPING\r\n", buf)
+	if err := w.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	// The server replies to the PING once it has accepted the
+	// connection, or reports an error.
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			conn.Close()
+			return nil, errors.E("NATS server error: " + strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	nc := &natsConn{
+		conn: conn,
+		w:    w,
+		done: make(chan struct{}),
+	}
+	go n.read(nc, r)
+	return nc, nil
+}
+
+// read reads the messages the server sends on the given connection until
+// it fails. The server's pings are answered, so that the server keeps
+// the connection open.
+func (n *NATS) read(nc *natsConn, r *bufio.Reader) {
+	defer close(nc.done)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PING":
+			n.mu.Lock()
+			nc.w.WriteString("PONG\r\n")
+			err := nc.w.Flush()
+			n.mu.Unlock()
+			if err != nil {
+				nc.conn.Close()
+				return
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			// The server closes the connection after most errors,
+			// such as an invalid subject, so it is made again.
+			zapctx.Warn(context.Background(), "NATS server error", zap.String("error", strings.TrimSpace(strings.TrimPrefix(line, "-ERR"))))
+			nc.conn.Close()
+			return
+		}
+	}
+}
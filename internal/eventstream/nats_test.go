@@ -0,0 +1,163 @@
+// Copyright 2024 Canonical.
+
+package eventstream_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/eventstream"
+)
+
+// fakeNATS is a NATS server that records the messages published to it.
+type fakeNATS struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	connects []string
+	messages []string
+	conns    []net.Conn
+}
+
+func newFakeNATS(c *qt.C) *fakeNATS {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, qt.IsNil)
+	s := &fakeNATS{ln: ln}
+	c.Cleanup(func() {
+		ln.Close()
+		s.closeConns()
+	})
+	go s.serve()
+	return s
+}
+
+func (s *fakeNATS) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeNATS) handle(conn net.Conn) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			s.mu.Lock()
+			s.connects = append(s.connects, strings.TrimPrefix(line, "CONNECT "))
+			s.mu.Unlock()
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case strings.HasPrefix(line, "PUB "):
+			fields := strings.Fields(line)
+			n, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return
+			}
+			buf := make([]byte, n+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, fields[1]+" "+string(buf[:n]))
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *fakeNATS) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *fakeNATS) published() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func TestNATSPublish(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srv := newFakeNATS(c)
+	n, err := eventstream.NewNATS("nats://s3cr3t@"+srv.ln.Addr().String(), nil)
+	c.Assert(err, qt.IsNil)
+	defer n.Close()
+
+	err = n.Publish(ctx, "jimm.deltas.controller-1", []byte(`{"kind":"unit"}`))
+	c.Assert(err, qt.IsNil)
+	err = n.Publish(ctx, "jimm.deltas.controller-2", []byte(`{"kind":"machine"}`))
+	c.Assert(err, qt.IsNil)
+
+	expect := []string{
+		`jimm.deltas.controller-1 {"kind":"unit"}`,
+		`jimm.deltas.controller-2 {"kind":"machine"}`,
+	}
+	waitFor(c, func() bool { return len(srv.published()) == 2 })
+	c.Check(srv.published(), qt.DeepEquals, expect)
+	srv.mu.Lock()
+	c.Assert(srv.connects, qt.HasLen, 1)
+	c.Check(srv.connects[0], qt.Contains, `"auth_token":"s3cr3t"`)
+	srv.mu.Unlock()
+
+	// A failed connection is made again.
+	srv.closeConns()
+	waitFor(c, func() bool {
+		err := n.Publish(ctx, "jimm.deltas.controller-1", []byte(`{"kind":"model"}`))
+		return err == nil && len(srv.published()) > 2
+	})
+	c.Check(srv.published()[2], qt.Equals, `jimm.deltas.controller-1 {"kind":"model"}`)
+	srv.mu.Lock()
+	c.Check(len(srv.connects) > 1, qt.IsTrue)
+	srv.mu.Unlock()
+}
+
+func TestNewNATSInvalidURL(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := eventstream.NewNATS("http://nats.example.com", nil)
+	c.Check(err, qt.ErrorMatches, `unsupported NATS URL scheme "http"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	_, err = eventstream.NewNATS("nats://", nil)
+	c.Check(err, qt.ErrorMatches, `NATS URL has no host`)
+}
+
+func waitFor(c *qt.C, f func() bool) {
+	for i := 0; i < 100; i++ {
+		if f() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	c.Fatal("timed out waiting for condition")
+}
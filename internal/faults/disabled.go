@@ -0,0 +1,50 @@
+// Copyright 2024 Canonical.
+
+//go:build !faults
+
+package faults
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// Enabled reports whether JIMM was built with fault injection.
+const Enabled = false
+
+// Inject injects the given fault. JIMM was built without fault
+// injection, so this always returns an error with the code
+// CodeNotSupported.
+func Inject(f Fault) error {
+	return errors.E(errors.CodeNotSupported, "fault injection is not enabled")
+}
+
+// Clear removes all injected faults.
+func Clear() {}
+
+// List returns the injected faults.
+func List() []Fault {
+	return nil
+}
+
+// Check returns the error caused by a fault injected at the given point
+// for the given target, if any.
+func Check(p Point, target string) error {
+	return nil
+}
+
+// Delay waits for the delay caused by a fault injected at the given
+// point for the given target, if any. If the context is canceled while
+// waiting the context's error is returned.
+func Delay(ctx context.Context, p Point, target string) error {
+	return nil
+}
+
+// RegisterDatabaseCallbacks registers the callbacks that inject
+// DatabaseWrite faults with the given database.
+func RegisterDatabaseCallbacks(db *gorm.DB) error {
+	return nil
+}
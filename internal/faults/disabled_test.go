@@ -0,0 +1,26 @@
+// Copyright 2024 Canonical.
+
+//go:build !faults
+
+package faults_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+)
+
+func TestDisabled(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(faults.Enabled, qt.IsFalse)
+	err := faults.Inject(faults.Fault{Point: faults.ControllerConnection})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotSupported)
+	c.Check(faults.List(), qt.HasLen, 0)
+	c.Check(faults.Check(faults.ControllerConnection, "controller-1"), qt.IsNil)
+	c.Check(faults.Delay(context.Background(), faults.WatcherDelta, "controller-1"), qt.IsNil)
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Canonical.
+
+//go:build faults
+
+package faults
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// Enabled reports whether JIMM was built with fault injection.
+const Enabled = true
+
+var registry struct {
+	mu     sync.Mutex
+	faults []Fault
+}
+
+// Inject injects the given fault. Faults are matched in the order they
+// were injected.
+func Inject(f Fault) error {
+	if err := f.Validate(); err != nil {
+		return err
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.faults = append(registry.faults, f)
+	return nil
+}
+
+// Clear removes all injected faults.
+func Clear() {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.faults = nil
+}
+
+// List returns the injected faults.
+func List() []Fault {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return append([]Fault(nil), registry.faults...)
+}
+
+// take returns the first fault matching the given point and target,
+// removing it if it has been injected as many times as requested.
+func take(p Point, target string) (Fault, bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for i, f := range registry.faults {
+		if !f.matches(p, target) {
+			continue
+		}
+		if f.Count > 0 {
+			registry.faults[i].Count--
+			if registry.faults[i].Count == 0 {
+				registry.faults = append(registry.faults[:i], registry.faults[i+1:]...)
+			}
+		}
+		return f, true
+	}
+	return Fault{}, false
+}
+
+// Check returns the error caused by a fault injected at the given point
+// for the given target, if any.
+func Check(p Point, target string) error {
+	f, ok := take(p, target)
+	if !ok {
+		return nil
+	}
+	return injectedError(f, target)
+}
+
+// Delay waits for the delay caused by a fault injected at the given
+// point for the given target, if any. If the context is canceled while
+// waiting the context's error is returned.
+func Delay(ctx context.Context, p Point, target string) error {
+	f, ok := take(p, target)
+	if !ok {
+		return nil
+	}
+	t := time.NewTimer(f.Delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegisterDatabaseCallbacks registers the callbacks that inject
+// DatabaseWrite faults with the given database.
+func RegisterDatabaseCallbacks(db *gorm.DB) error {
+	check := func(tx *gorm.DB) {
+		if err := Check(DatabaseWrite, tx.Statement.Table); err != nil {
+			tx.AddError(err)
+		}
+	}
+	cb := db.Callback()
+	if err := cb.Create().Before("gorm:create").Register("faults:create", check); err != nil {
+		return errors.E(err)
+	}
+	if err := cb.Update().Before("gorm:update").Register("faults:update", check); err != nil {
+		return errors.E(err)
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("faults:delete", check); err != nil {
+		return errors.E(err)
+	}
+	if err := cb.Raw().Before("gorm:raw").Register("faults:raw", check); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Canonical.
+
+//go:build faults
+
+package faults_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+)
+
+func TestInjectAndCheck(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(faults.Clear)
+
+	err := faults.Inject(faults.Fault{Point: faults.ControllerConnection, Target: "controller-1"})
+	c.Assert(err, qt.IsNil)
+	err = faults.Inject(faults.Fault{Point: faults.DatabaseWrite, Count: 2})
+	c.Assert(err, qt.IsNil)
+	c.Check(faults.List(), qt.HasLen, 2)
+
+	err = faults.Check(faults.ControllerConnection, "controller-1")
+	c.Check(err, qt.ErrorMatches, `injected controller-connection fault for controller-1`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConnectionFailed)
+	c.Check(faults.Check(faults.ControllerConnection, "controller-2"), qt.IsNil)
+
+	// Counted faults are removed once they have been injected.
+	c.Check(faults.Check(faults.DatabaseWrite, "models"), qt.ErrorMatches, `injected database-write fault for models`)
+	c.Check(faults.Check(faults.DatabaseWrite, ""), qt.ErrorMatches, `injected database-write fault`)
+	c.Check(faults.Check(faults.DatabaseWrite, "models"), qt.IsNil)
+	c.Check(faults.List(), qt.HasLen, 1)
+
+	faults.Clear()
+	c.Check(faults.List(), qt.HasLen, 0)
+	c.Check(faults.Check(faults.ControllerConnection, "controller-1"), qt.IsNil)
+
+	err = faults.Inject(faults.Fault{Point: "disk-full"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestDelay(t *testing.T) {
+	c := qt.New(t)
+	c.Cleanup(faults.Clear)
+
+	err := faults.Inject(faults.Fault{Point: faults.WatcherDelta, Target: "controller-1", Delay: 50 * time.Millisecond, Count: 1})
+	c.Assert(err, qt.IsNil)
+
+	start := time.Now()
+	err = faults.Delay(context.Background(), faults.WatcherDelta, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(time.Since(start) >= 50*time.Millisecond, qt.IsTrue)
+
+	// The fault has been removed.
+	start = time.Now()
+	err = faults.Delay(context.Background(), faults.WatcherDelta, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(time.Since(start) < 50*time.Millisecond, qt.IsTrue)
+
+	err = faults.Inject(faults.Fault{Point: faults.WatcherDelta, Delay: time.Hour})
+	c.Assert(err, qt.IsNil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = faults.Delay(ctx, faults.WatcherDelta, "controller-1")
+	c.Check(err, qt.Equals, context.Canceled)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Canonical.
+
+// Package faults provides fault injection used to test how JIMM behaves
+// when controllers or the database fail. Faults can only be injected
+// when JIMM is built with the "faults" build tag, in other builds the
+// injection points do nothing and requests to inject faults fail. This
+// package must never be built with the tag for production deployments.
+package faults
+
+import (
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A Point identifies a place in JIMM at which faults may be injected.
+type Point string
+
+// The points at which faults may be injected.
+const (
+	// ControllerConnection faults cause connections to controllers to
+	// fail as if the network had dropped them. Both new dials and calls
+	// on existing connections fail, and failed connections are marked
+	// broken so that they are not reused. The target is the name of the
+	// controller.
+	ControllerConnection Point = "controller-connection"
+
+	// WatcherDelta faults delay the processing of each batch of deltas
+	// received from a controller's watcher by the fault's Delay. The
+	// target is the name of the controller.
+	WatcherDelta Point = "watcher-delta"
+
+	// DatabaseWrite faults cause database writes to fail. The target is
+	// the name of the table written to, statements not associated with
+	// a table only match faults without a target.
+	DatabaseWrite Point = "database-write"
+)
+
+// points holds the error code returned by a fault injected at each
+// point, matching the code a genuine failure would have.
+var points = map[Point]errors.Code{
+	ControllerConnection: errors.CodeConnectionFailed,
+	WatcherDelta:         "",
+	DatabaseWrite:        "",
+}
+
+// A Fault describes a fault to inject.
+type Fault struct {
+	// Point is the point at which the fault is injected.
+	Point Point
+
+	// Target restricts the fault to a single controller or table, see
+	// the description of each Point. If this is empty the fault applies
+	// to all targets.
+	Target string
+
+	// Delay is the time for which the operation is delayed. This is
+	// only used by WatcherDelta faults.
+	Delay time.Duration
+
+	// Count is the number of times the fault is injected before it is
+	// removed. If this is zero the fault is injected until cleared.
+	Count int
+}
+
+// Validate checks that the fault is one that can be injected.
+func (f Fault) Validate() error {
+	if _, ok := points[f.Point]; !ok {
+		return errors.E(errors.CodeBadRequest, "unknown fault injection point "+string(f.Point))
+	}
+	if f.Count < 0 {
+		return errors.E(errors.CodeBadRequest, "invalid fault count")
+	}
+	if f.Point == WatcherDelta && f.Delay <= 0 {
+		return errors.E(errors.CodeBadRequest, "watcher-delta faults require a delay")
+	}
+	return nil
+}
+
+// matches returns whether the fault applies to the given point and
+// target.
+func (f Fault) matches(p Point, target string) bool {
+	return f.Point == p && (f.Target == "" || f.Target == target)
+}
+
+// injectedError returns the error returned by an operation failed by
+// the given fault.
+func injectedError(f Fault, target string) error {
+	msg := "injected " + string(f.Point) + " fault"
+	if target != "" {
+		msg += " for " + target
+	}
+	if code := points[f.Point]; code != "" {
+		return errors.E(code, msg)
+	}
+	return errors.E(msg)
+}
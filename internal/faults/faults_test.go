@@ -0,0 +1,55 @@
+// Copyright 2024 Canonical.
+
+package faults_test
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+)
+
+func TestValidate(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		about       string
+		fault       faults.Fault
+		expectError string
+	}{{
+		about: "controller connection",
+		fault: faults.Fault{Point: faults.ControllerConnection, Target: "controller-1"},
+	}, {
+		about: "watcher delta",
+		fault: faults.Fault{Point: faults.WatcherDelta, Delay: time.Second, Count: 2},
+	}, {
+		about: "database write",
+		fault: faults.Fault{Point: faults.DatabaseWrite, Target: "models"},
+	}, {
+		about:       "unknown point",
+		fault:       faults.Fault{Point: "disk-full"},
+		expectError: `unknown fault injection point disk-full`,
+	}, {
+		about:       "negative count",
+		fault:       faults.Fault{Point: faults.DatabaseWrite, Count: -1},
+		expectError: `invalid fault count`,
+	}, {
+		about:       "watcher delta without delay",
+		fault:       faults.Fault{Point: faults.WatcherDelta},
+		expectError: `watcher-delta faults require a delay`,
+	}}
+	for _, test := range tests {
+		c.Run(test.about, func(c *qt.C) {
+			err := test.fault.Validate()
+			if test.expectError == "" {
+				c.Check(err, qt.IsNil)
+				return
+			}
+			c.Check(err, qt.ErrorMatches, test.expectError)
+			c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Canonical.
+
+// Package groupsync reads group memberships from an external directory
+// and maps the directory's groups onto JIMM groups, so that JIMM's group
+// store can be kept in step with the organisation's directory.
+package groupsync
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A Directory is an external source of group memberships.
+type Directory interface {
+	// Groups returns the members of every group in the directory, keyed
+	// by the name of the group. Members are identified by the name
+	// (usually the email address) they authenticate to JIMM with.
+	Groups(ctx context.Context) (map[string][]string, error)
+
+	// UserActive returns whether the named member, as returned by
+	// Groups, is an active user of the directory. A user the directory
+	// does not have, or has deactivated, is not active.
+	UserActive(ctx context.Context, name string) (bool, error)
+}
+
+// A Mapping is a rule mapping groups in the directory onto JIMM groups.
+type Mapping struct {
+	// Source is the name of the directory group. If Source ends with
+	// "*" it matches every directory group with the preceding prefix.
+	Source string `json:"source"`
+
+	// Target is the name of the JIMM group the directory group maps
+	// to. Any "*" in Target is replaced by the part of the directory
+	// group name matched by the "*" in Source. If Target is empty the
+	// JIMM group has the same name as the directory group.
+	Target string `json:"target,omitempty"`
+}
+
+// Validate checks the mapping is well formed.
+func (m Mapping) Validate() error {
+	if m.Source == "" {
+		return errors.E(errors.CodeBadRequest, "group mapping source not specified")
+	}
+	if strings.Contains(strings.TrimSuffix(m.Source, "*"), "*") {
+		return errors.E(errors.CodeBadRequest, "group mapping source "+m.Source+" may only have a trailing *")
+	}
+	if !strings.HasSuffix(m.Source, "*") && strings.Contains(m.Target, "*") {
+		return errors.E(errors.CodeBadRequest, "group mapping target "+m.Target+" has a * but source "+m.Source+" does not")
+	}
+	return nil
+}
+
+// match returns the JIMM group the named directory group maps to, and
+// whether the mapping applies to the group at all.
+func (m Mapping) match(group string) (string, bool) {
+	if prefix, ok := strings.CutSuffix(m.Source, "*"); ok {
+		if !strings.HasPrefix(group, prefix) {
+			return "", false
+		}
+		if m.Target == "" {
+			return group, true
+		}
+		return strings.ReplaceAll(m.Target, "*", strings.TrimPrefix(group, prefix)), true
+	}
+	if group != m.Source {
+		return "", false
+	}
+	if m.Target == "" {
+		return group, true
+	}
+	return m.Target, true
+}
+
+// Apply maps the membership of the directory groups onto JIMM groups
+// using the given mappings, returning the members of each JIMM group
+// that is managed by the mappings, keyed by group name. A JIMM group
+// mapped from several directory groups has the members of all of them.
+// JIMM groups named by a mapping without a wildcard are always managed,
+// so that their members are removed if the directory group is removed.
+// Directory groups no mapping applies to are ignored.
+func Apply(mappings []Mapping, groups map[string][]string) map[string][]string {
+	members := make(map[string]map[string]bool)
+	for _, m := range mappings {
+		if !strings.HasSuffix(m.Source, "*") {
+			target, _ := m.match(m.Source)
+			if members[target] == nil {
+				members[target] = make(map[string]bool)
+			}
+		}
+	}
+	for group, groupMembers := range groups {
+		for _, m := range mappings {
+			target, ok := m.match(group)
+			if !ok {
+				continue
+			}
+			if members[target] == nil {
+				members[target] = make(map[string]bool)
+			}
+			for _, member := range groupMembers {
+				members[target][member] = true
+			}
+		}
+	}
+	result := make(map[string][]string, len(members))
+	for group, set := range members {
+		list := make([]string, 0, len(set))
+		for member := range set {
+			list = append(list, member)
+		}
+		sort.Strings(list)
+		result[group] = list
+	}
+	return result
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Canonical.
+
+package groupsync_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/groupsync"
+)
+
+func TestMappingValidate(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(groupsync.Mapping{Source: "eng", Target: "engineering"}.Validate(), qt.IsNil)
+	c.Check(groupsync.Mapping{Source: "team-*", Target: "juju-*"}.Validate(), qt.IsNil)
+	c.Check(groupsync.Mapping{Source: "*"}.Validate(), qt.IsNil)
+	c.Check(groupsync.Mapping{Target: "engineering"}.Validate(), qt.ErrorMatches, `group mapping source not specified`)
+	c.Check(groupsync.Mapping{Source: "a*b*"}.Validate(), qt.ErrorMatches, `group mapping source a\*b\* may only have a trailing \*`)
+	c.Check(groupsync.Mapping{Source: "eng", Target: "juju-*"}.Validate(), qt.ErrorMatches, `group mapping target juju-\* has a \* but source eng does not`)
+}
+
+func TestApply(t *testing.T) {
+	c := qt.New(t)
+
+	mappings := []groupsync.Mapping{
+		{Source: "Engineering", Target: "engineering"},
+		{Source: "Ops", Target: "engineering"},
+		{Source: "team-*", Target: "juju-*"},
+		{Source: "Removed", Target: "removed"},
+	}
+	groups := map[string][]string{
+		"Engineering": {"bob@canonical.com", "alice@canonical.com"},
+		"Ops":         {"carol@canonical.com", "alice@canonical.com"},
+		"team-db":     {"dave@canonical.com"},
+		"Marketing":   {"eve@canonical.com"},
+	}
+	c.Check(groupsync.Apply(mappings, groups), qt.DeepEquals, map[string][]string{
+		"engineering": {"alice@canonical.com", "bob@canonical.com", "carol@canonical.com"},
+		"juju-db":     {"dave@canonical.com"},
+		"removed":     {},
+	})
+}
+
+func TestSCIMDirectory(t *testing.T) {
+	c := qt.New(t)
+
+	groups := []map[string]interface{}{{
+		"displayName": "Engineering",
+		"members": []map[string]string{
+			{"value": "1", "display": "alice@canonical.com"},
+			{"value": "2", "display": "bob@canonical.com"},
+		},
+	}, {
+		"displayName": "Empty",
+	}, {
+		"displayName": "Ops",
+		"members": []map[string]string{
+			{"value": "3", "display": "carol@canonical.com"},
+		},
+	}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/scim/v2/Groups" {
+			http.NotFound(w, req)
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Serve at most two groups a page to exercise pagination.
+		start, _ := strconv.Atoi(req.URL.Query().Get("startIndex"))
+		end := start + 1
+		if end > len(groups) {
+			end = len(groups)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"totalResults": len(groups),
+			"startIndex":   start,
+			"Resources":    groups[start-1 : end],
+		})
+	}))
+	defer srv.Close()
+
+	d := groupsync.SCIMDirectory{
+		URL:   srv.URL + "/scim/v2/",
+		Token: "secret",
+	}
+	result, err := d.Groups(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Check(result, qt.DeepEquals, map[string][]string{
+		"Engineering": {"alice@canonical.com", "bob@canonical.com"},
+		"Empty":       nil,
+		"Ops":         {"carol@canonical.com"},
+	})
+
+	d.MemberAttribute = "value"
+	result, err = d.Groups(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Check(result["Engineering"], qt.DeepEquals, []string{"1", "2"})
+
+	d.Token = "wrong"
+	_, err = d.Groups(context.Background())
+	c.Check(err, qt.ErrorMatches, `SCIM service returned status 401 Unauthorized`)
+}
+
+func TestSCIMDirectoryUserActive(t *testing.T) {
+	c := qt.New(t)
+
+	users := []map[string]interface{}{
+		{"id": "1", "userName": "alice@canonical.com", "active": true},
+		{"id": "2", "userName": "bob@canonical.com", "active": false},
+		{"id": "3", "userName": "carol@canonical.com"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Path == "/scim/v2/Users" {
+			var resources []map[string]interface{}
+			for _, u := range users {
+				if req.URL.Query().Get("filter") == `userName eq "`+u["userName"].(string)+`"` {
+					resources = append(resources, u)
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"totalResults": len(resources),
+				"Resources":    resources,
+			})
+			return
+		}
+		for _, u := range users {
+			if req.URL.Path == "/scim/v2/Users/"+u["id"].(string) {
+				json.NewEncoder(w).Encode(u)
+				return
+			}
+		}
+		http.NotFound(w, req)
+	}))
+	defer srv.Close()
+
+	d := groupsync.SCIMDirectory{
+		URL:   srv.URL + "/scim/v2/",
+		Token: "secret",
+	}
+	for _, test := range []struct {
+		name   string
+		active bool
+	}{
+		{"alice@canonical.com", true},
+		{"bob@canonical.com", false},
+		{"carol@canonical.com", true},
+		{"dave@canonical.com", false},
+	} {
+		ok, err := d.UserActive(context.Background(), test.name)
+		c.Assert(err, qt.IsNil)
+		c.Check(ok, qt.Equals, test.active, qt.Commentf("%s", test.name))
+	}
+
+	d.MemberAttribute = "value"
+	for _, test := range []struct {
+		name   string
+		active bool
+	}{
+		{"1", true},
+		{"2", false},
+		{"3", true},
+		{"4", false},
+	} {
+		ok, err := d.UserActive(context.Background(), test.name)
+		c.Assert(err, qt.IsNil)
+		c.Check(ok, qt.Equals, test.active, qt.Commentf("%s", test.name))
+	}
+
+	d.Token = "wrong"
+	_, err := d.UserActive(context.Background(), "1")
+	c.Check(err, qt.ErrorMatches, `SCIM service returned status 401 Unauthorized`)
+}
@@ -0,0 +1,189 @@
+// Copyright 2024 Canonical.
+
+package groupsync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// scimPageSize is the number of groups requested from a SCIM server in
+// each page.
+const scimPageSize = 100
+
+// A SCIMDirectory is a Directory that reads groups from a SCIM 2.0
+// service provider (RFC 7644).
+type SCIMDirectory struct {
+	// URL is the base URL of the SCIM service, groups are read from
+	// the Groups endpoint below it.
+	URL string
+
+	// Token is the bearer token used to authenticate with the SCIM
+	// service. If Token is empty no authentication is performed.
+	Token string
+
+	// MemberAttribute is the attribute of a group member that holds
+	// the name the member authenticates to JIMM with. This is either
+	// "display" or "value". If this is empty "display" is used.
+	MemberAttribute string
+
+	// Client is the HTTP client used to query the SCIM service. If
+	// this is nil http.DefaultClient is used.
+	Client *http.Client
+}
+
+// scimListResponse is a page of a SCIM list response.
+type scimListResponse struct {
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	Resources    []scimGroup `json:"Resources"`
+}
+
+// scimUserListResponse is a SCIM list response holding users.
+type scimUserListResponse struct {
+	TotalResults int        `json:"totalResults"`
+	Resources    []scimUser `json:"Resources"`
+}
+
+// scimUser is a SCIM user resource. A user without an active attribute
+// is active.
+type scimUser struct {
+	Active *bool `json:"active"`
+}
+
+// scimGroup is a SCIM group resource.
+type scimGroup struct {
+	DisplayName string `json:"displayName"`
+	Members     []struct {
+		Value   string `json:"value"`
+		Display string `json:"display"`
+	} `json:"members"`
+}
+
+// Groups implements Directory.
+func (d *SCIMDirectory) Groups(ctx context.Context) (map[string][]string, error) {
+	const op = errors.Op("groupsync.SCIMDirectory.Groups")
+
+	groups := make(map[string][]string)
+	startIndex := 1
+	for {
+		page, err := d.getPage(ctx, startIndex)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		for _, g := range page.Resources {
+			if g.DisplayName == "" {
+				continue
+			}
+			members := groups[g.DisplayName]
+			for _, m := range g.Members {
+				name := m.Display
+				if d.MemberAttribute == "value" {
+					name = m.Value
+				}
+				if name != "" {
+					members = append(members, name)
+				}
+			}
+			groups[g.DisplayName] = members
+		}
+		startIndex += len(page.Resources)
+		if len(page.Resources) == 0 || startIndex > page.TotalResults {
+			return groups, nil
+		}
+	}
+}
+
+// UserActive implements Directory. If members are identified by their
+// "value" attribute the user with that ID is looked up, otherwise the
+// user with that userName is.
+func (d *SCIMDirectory) UserActive(ctx context.Context, name string) (bool, error) {
+	const op = errors.Op("groupsync.SCIMDirectory.UserActive")
+
+	var user scimUser
+	if d.MemberAttribute == "value" {
+		found, err := d.get(ctx, "/Users/"+url.PathEscape(name), nil, &user)
+		if err != nil {
+			return false, errors.E(op, err)
+		}
+		if !found {
+			return false, nil
+		}
+	} else {
+		q := url.Values{
+			"filter": {`userName eq "` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(name) + `"`},
+		}
+		var list scimUserListResponse
+		found, err := d.get(ctx, "/Users", q, &list)
+		if err != nil {
+			return false, errors.E(op, err)
+		}
+		if !found {
+			return false, errors.E(op, "SCIM service returned status 404 Not Found")
+		}
+		if len(list.Resources) == 0 {
+			return false, nil
+		}
+		user = list.Resources[0]
+	}
+	return user.Active == nil || *user.Active, nil
+}
+
+func (d *SCIMDirectory) getPage(ctx context.Context, startIndex int) (*scimListResponse, error) {
+	q := url.Values{
+		"startIndex": {strconv.Itoa(startIndex)},
+		"count":      {strconv.Itoa(scimPageSize)},
+	}
+	var page scimListResponse
+	found, err := d.get(ctx, "/Groups", q, &page)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.E("SCIM service returned status 404 Not Found")
+	}
+	return &page, nil
+}
+
+// get reads the resource at the given path below the SCIM service's URL
+// into v. If the service does not have the resource get returns false.
+func (d *SCIMDirectory) get(ctx context.Context, path string, q url.Values, v any) (bool, error) {
+	u := strings.TrimSuffix(d.URL, "/") + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/scim+json")
+	if d.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.Token)
+	}
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.E(fmt.Sprintf("SCIM service returned status %s", resp.Status))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, errors.E(fmt.Sprintf("cannot decode SCIM response: %s", err))
+	}
+	return true, nil
+}
@@ -204,6 +204,14 @@ func (auth *JWTGenerator) GetUser() names.UserTag {
 	return names.UserTag{}
 }
 
+// ModelAccess returns the logged in user's access level to the model,
+// or an empty string if the user has not logged in.
+func (auth *JWTGenerator) ModelAccess() string {
+	auth.mu.Lock()
+	defer auth.mu.Unlock()
+	return auth.accessMapCache[auth.mt.String()]
+}
+
 // MakeLoginToken authorizes the user based on the provided login requests and returns
 // a JWT containing claims about user's access to the controller, model (if applicable)
 // and all clouds that the controller knows about.
@@ -442,14 +450,11 @@ func (j *JIMM) ToJAASTag(ctx context.Context, tag *ofganames.Tag, resolveUUIDs b
 		}
 		return tagToString(names.ApplicationOfferTagKind, ao.URL), nil
 	case jimmnames.GroupTagKind:
-		group := dbmodel.GroupEntry{
-			UUID: tag.ID,
-		}
-		err := j.Database.GetGroup(ctx, &group)
+		name, err := j.getGroupName(ctx, tag.ID)
 		if err != nil {
-			return "", errors.E(err, fmt.Sprintf("failed to fetch group information: %s", group.UUID))
+			return "", errors.E(err, fmt.Sprintf("failed to fetch group information: %s", tag.ID))
 		}
-		return tagToString(jimmnames.GroupTagKind, group.Name), nil
+		return tagToString(jimmnames.GroupTagKind, name), nil
 	case names.CloudTagKind:
 		cloud := dbmodel.Cloud{
 			Name: tag.ID,
@@ -623,12 +628,21 @@ func (t *tagResolver) serviceAccountTag(ctx context.Context) (*ofga.Entity, erro
 		"Resolving JIMM tags to Juju tags for tag kind: serviceaccount",
 		zap.String("serviceaccount-name", t.trailer),
 	)
-	if !jimmnames.IsValidServiceAccountId(t.trailer) {
+	// Service account IDs may be given without the @serviceaccount
+	// domain, in which case the domain is assumed. A bare client ID is
+	// commonly a UUID, in which case it will have been parsed as the
+	// resource UUID.
+	id := t.trailer
+	if id == "" {
+		id = t.resourceUUID
+	}
+	clientID, err := jimmnames.EnsureValidServiceAccountId(id)
+	if err != nil {
 		// TODO(ale8k): Return custom error for validation check at JujuAPI
 		return nil, errors.E("invalid service account id")
 	}
 
-	return ofganames.ConvertTagWithRelation(jimmnames.NewServiceAccountTag(t.trailer), t.relation), nil
+	return ofganames.ConvertTagWithRelation(jimmnames.NewServiceAccountTag(clientID), t.relation), nil
 }
 
 // resolveTag resolves JIMM tag [of any kind available] (i.e., controller-mycontroller:alex@canonical.com/mymodel.myoffer)
@@ -743,6 +757,7 @@ func (j *JIMM) GetGroupByName(ctx context.Context, user *openfga.User, name stri
 // RenameGroup renames a group in JIMM's DB.
 func (j *JIMM) RenameGroup(ctx context.Context, user *openfga.User, oldName, newName string) error {
 	const op = errors.Op("jimm.RenameGroup")
+	defer j.Cache.InvalidateGroups()
 
 	if !user.JimmAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
@@ -766,6 +781,8 @@ func (j *JIMM) RenameGroup(ctx context.Context, user *openfga.User, oldName, new
 // RemoveGroup removes a group within JIMMs DB for reference by OpenFGA.
 func (j *JIMM) RemoveGroup(ctx context.Context, user *openfga.User, name string) error {
 	const op = errors.Op("jimm.RemoveGroup")
+	defer j.Cache.InvalidateAllModelAccess()
+	defer j.Cache.InvalidateGroups()
 
 	if !user.JimmAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
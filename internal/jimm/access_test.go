@@ -549,6 +549,14 @@ func TestResolveTags(t *testing.T) {
 		desc:     "map cloud",
 		input:    "cloud-" + cloud.Name + "#administrator",
 		expected: ofganames.ConvertTagWithRelation(names.NewCloudTag(cloud.Name), ofganames.AdministratorRelation),
+	}, {
+		desc:     "map service account",
+		input:    "serviceaccount-fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount",
+		expected: ofganames.ConvertTag(jimmnames.NewServiceAccountTag("fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount")),
+	}, {
+		desc:     "map service account without domain",
+		input:    "serviceaccount-fca1f605-736e-4d1f-bcd2-aecc726923be#administrator",
+		expected: ofganames.ConvertTagWithRelation(jimmnames.NewServiceAccountTag("fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount"), ofganames.AdministratorRelation),
 	}}
 
 	for _, tC := range testCases {
@@ -0,0 +1,270 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// An AccessRequestNotifier is notified whenever an access request is
+// made, approved or denied.
+type AccessRequestNotifier interface {
+	NotifyAccessRequest(ctx context.Context, event apiparams.AccessRequestEvent) error
+}
+
+// RequestAccess records a request by the given user for the given access
+// level on the model or cloud with the given tag, and notifies the
+// administrators of the model or cloud. If the user already has the
+// requested access, or already has a pending request for the target, an
+// error with the code CodeAlreadyExists is returned.
+func (j *JIMM) RequestAccess(ctx context.Context, user *openfga.User, target, access, reason string) (apiparams.AccessRequest, error) {
+	const op = errors.Op("jimm.RequestAccess")
+
+	tag, relation, err := j.accessRequestTarget(ctx, target, access)
+	if err != nil {
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+	hasAccess, err := openfga.CheckRelation(ctx, user, tag, relation)
+	if err != nil {
+		return apiparams.AccessRequest{}, errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	if hasAccess {
+		return apiparams.AccessRequest{}, errors.E(op, errors.CodeAlreadyExists, fmt.Sprintf("user already has %s access to %s", access, tag))
+	}
+	pending, err := j.Database.ListAccessRequests(ctx, db.AccessRequestFilter{
+		IdentityName: user.Name,
+		TargetTag:    tag.String(),
+		Status:       dbmodel.AccessRequestPending,
+	})
+	if err != nil {
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+	if len(pending) > 0 {
+		return apiparams.AccessRequest{}, errors.E(op, errors.CodeAlreadyExists, fmt.Sprintf("access request %d for %s is already pending", pending[0].ID, tag))
+	}
+
+	r := dbmodel.AccessRequest{
+		IdentityName: user.Name,
+		TargetTag:    tag.String(),
+		Access:       access,
+		Reason:       reason,
+		Status:       dbmodel.AccessRequestPending,
+	}
+	if err := j.Database.AddAccessRequest(ctx, &r); err != nil {
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+
+	event := apiparams.AccessRequestEvent{
+		Type:    apiparams.AccessRequestCreated,
+		Request: r.ToAPIAccessRequest(),
+	}
+	admins, err := openfga.ListUsersWithAccess(ctx, j.OpenFGAClient, tag, ofganames.AdministratorRelation)
+	if err != nil {
+		zapctx.Error(ctx, "failed to list access request reviewers", zaputil.Error(err), zap.String("target", r.TargetTag))
+	}
+	for _, admin := range admins {
+		event.Reviewers = append(event.Reviewers, admin.Name)
+	}
+	j.notifyAccessRequest(ctx, event)
+	return event.Request, nil
+}
+
+// ListAccessRequests returns the access requests made by the given user
+// and the access requests the given user may review, optionally limited to
+// the requests in the given state. JIMM administrators may see all access
+// requests.
+func (j *JIMM) ListAccessRequests(ctx context.Context, user *openfga.User, status string) ([]apiparams.AccessRequest, error) {
+	const op = errors.Op("jimm.ListAccessRequests")
+
+	requests, err := j.Database.ListAccessRequests(ctx, db.AccessRequestFilter{Status: status})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	reviewable := make(map[string]bool)
+	result := []apiparams.AccessRequest{}
+	for _, r := range requests {
+		if r.IdentityName != user.Name && !user.JimmAdmin {
+			ok, seen := reviewable[r.TargetTag]
+			if !seen {
+				ok, err = j.canReviewAccessRequest(ctx, user, r)
+				if err != nil {
+					return nil, errors.E(op, err)
+				}
+				reviewable[r.TargetTag] = ok
+			}
+			if !ok {
+				continue
+			}
+		}
+		result = append(result, r.ToAPIAccessRequest())
+	}
+	return result, nil
+}
+
+// ApproveAccessRequest approves the pending access request with the given
+// ID and grants the requested access. Only the administrators of the model
+// or cloud access is requested for may approve a request.
+func (j *JIMM) ApproveAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) error {
+	const op = errors.Op("jimm.ApproveAccessRequest")
+
+	r, err := j.getPendingAccessRequest(ctx, user, id)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	tag, err := names.ParseTag(r.TargetTag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	switch tag := tag.(type) {
+	case names.ModelTag:
+		err = j.GrantModelAccess(ctx, user, tag, names.NewUserTag(r.IdentityName), jujuparams.UserAccessPermission(r.Access))
+	case names.CloudTag:
+		err = j.GrantCloudAccess(ctx, user, tag, names.NewUserTag(r.IdentityName), r.Access)
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.reviewAccessRequest(ctx, user, r, dbmodel.AccessRequestApproved, comment); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// DenyAccessRequest denies the pending access request with the given ID.
+// Only the administrators of the model or cloud access is requested for
+// may deny a request.
+func (j *JIMM) DenyAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) error {
+	const op = errors.Op("jimm.DenyAccessRequest")
+
+	r, err := j.getPendingAccessRequest(ctx, user, id)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.reviewAccessRequest(ctx, user, r, dbmodel.AccessRequestDenied, comment); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// accessRequestTarget parses the target of an access request, checking
+// that it refers to an existing model or cloud and that the access level
+// is valid for it. The relation corresponding to the access level is
+// returned.
+func (j *JIMM) accessRequestTarget(ctx context.Context, target, access string) (names.Tag, openfga.Relation, error) {
+	tag, err := names.ParseTag(target)
+	if err != nil {
+		return nil, ofganames.NoRelation, errors.E(errors.CodeBadRequest, err)
+	}
+	var relation openfga.Relation
+	switch tag := tag.(type) {
+	case names.ModelTag:
+		relation, err = ToModelRelation(access)
+		if err != nil {
+			return nil, ofganames.NoRelation, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid model access %q", access))
+		}
+		var m dbmodel.Model
+		m.SetTag(tag)
+		if err := j.Database.GetModel(ctx, &m); err != nil {
+			return nil, ofganames.NoRelation, err
+		}
+	case names.CloudTag:
+		relation, err = ToCloudRelation(access)
+		if err != nil {
+			return nil, ofganames.NoRelation, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid cloud access %q", access))
+		}
+		var c dbmodel.Cloud
+		c.SetTag(tag)
+		if err := j.Database.GetCloud(ctx, &c); err != nil {
+			return nil, ofganames.NoRelation, err
+		}
+	default:
+		return nil, ofganames.NoRelation, errors.E(errors.CodeBadRequest, "access can only be requested to models and clouds")
+	}
+	return tag, relation, nil
+}
+
+// canReviewAccessRequest reports whether the given user may approve or
+// deny the given access request.
+func (j *JIMM) canReviewAccessRequest(ctx context.Context, user *openfga.User, r dbmodel.AccessRequest) (bool, error) {
+	if user.JimmAdmin {
+		return true, nil
+	}
+	tag, err := names.ParseTag(r.TargetTag)
+	if err != nil {
+		return false, err
+	}
+	isAdmin, err := openfga.CheckRelation(ctx, user, tag, ofganames.AdministratorRelation)
+	if err != nil {
+		return false, errors.E(errors.CodeOpenFGARequestFailed, err)
+	}
+	return isAdmin, nil
+}
+
+// getPendingAccessRequest returns the pending access request with the
+// given ID, checking that the given user may review it.
+func (j *JIMM) getPendingAccessRequest(ctx context.Context, user *openfga.User, id uint) (dbmodel.AccessRequest, error) {
+	r := dbmodel.AccessRequest{ID: id}
+	if err := j.Database.GetAccessRequest(ctx, &r); err != nil {
+		return r, err
+	}
+	ok, err := j.canReviewAccessRequest(ctx, user, r)
+	if err != nil {
+		return r, err
+	}
+	if !ok {
+		return r, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	if r.Status != dbmodel.AccessRequestPending {
+		return r, errors.E(errors.CodeBadRequest, fmt.Sprintf("access request %d has already been %s", r.ID, r.Status))
+	}
+	return r, nil
+}
+
+// reviewAccessRequest records the decision made on the given access
+// request and notifies the configured AccessRequestNotifier.
+func (j *JIMM) reviewAccessRequest(ctx context.Context, user *openfga.User, r dbmodel.AccessRequest, status, comment string) error {
+	r.Status = status
+	r.ReviewerName = user.Name
+	r.ReviewComment = comment
+	r.ReviewedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := j.Database.UpdateAccessRequest(ctx, &r); err != nil {
+		return err
+	}
+	event := apiparams.AccessRequestEvent{
+		Type:    apiparams.AccessRequestApproved,
+		Request: r.ToAPIAccessRequest(),
+	}
+	if status == dbmodel.AccessRequestDenied {
+		event.Type = apiparams.AccessRequestDenied
+	}
+	j.notifyAccessRequest(ctx, event)
+	return nil
+}
+
+// notifyAccessRequest sends the given event to the configured
+// AccessRequestNotifier, if any. Failures are logged but otherwise
+// ignored, the request is stored regardless.
+func (j *JIMM) notifyAccessRequest(ctx context.Context, event apiparams.AccessRequestEvent) {
+	if j.AccessRequestNotifier == nil {
+		return
+	}
+	if err := j.AccessRequestNotifier.NotifyAccessRequest(ctx, event); err != nil {
+		zapctx.Error(ctx, "failed to send access request notification", zaputil.Error(err), zap.Uint("id", event.Request.ID), zap.String("type", event.Type))
+	}
+}
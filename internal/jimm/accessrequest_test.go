@@ -0,0 +1,201 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const accessRequestTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+- username: eve@canonical.com
+  controller-access: login
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: read
+`
+
+type accessRequestRecorder struct {
+	events []apiparams.AccessRequestEvent
+}
+
+func (r *accessRequestRecorder) NotifyAccessRequest(_ context.Context, event apiparams.AccessRequestEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestAccessRequests(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var notifier accessRequestRecorder
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{},
+		},
+		AccessRequestNotifier: &notifier,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, accessRequestTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	eveIdentity := env.User("eve@canonical.com").DBObject(c, j.Database)
+	eve := openfga.NewUser(&eveIdentity, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	_, err = j.RequestAccess(ctx, bob, mt.String(), "read", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+	_, err = j.RequestAccess(ctx, bob, mt.String(), "add-model", "")
+	c.Check(err, qt.ErrorMatches, `invalid model access "add-model"`)
+	_, err = j.RequestAccess(ctx, bob, "controller-00000001-0000-0000-0000-000000000001", "admin", "")
+	c.Check(err, qt.ErrorMatches, `access can only be requested to models and clouds`)
+	_, err = j.RequestAccess(ctx, bob, names.NewModelTag("00000002-0000-0000-0000-000000000002").String(), "read", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	ar, err := j.RequestAccess(ctx, bob, mt.String(), "write", "deploying the new release")
+	c.Assert(err, qt.IsNil)
+	c.Check(ar.User, qt.Equals, "bob@canonical.com")
+	c.Check(ar.Status, qt.Equals, dbmodel.AccessRequestPending)
+	c.Assert(notifier.events, qt.HasLen, 1)
+	c.Check(notifier.events[0].Type, qt.Equals, apiparams.AccessRequestCreated)
+	c.Check(notifier.events[0].Request.ID, qt.Equals, ar.ID)
+	c.Check(notifier.events[0].Reviewers, qt.Contains, "alice@canonical.com")
+
+	_, err = j.RequestAccess(ctx, bob, mt.String(), "admin", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	// The requester and the model administrators can see the request,
+	// other users cannot.
+	requests, err := j.ListAccessRequests(ctx, bob, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(requests, qt.HasLen, 1)
+	requests, err = j.ListAccessRequests(ctx, alice, dbmodel.AccessRequestPending)
+	c.Assert(err, qt.IsNil)
+	c.Check(requests, qt.HasLen, 1)
+	requests, err = j.ListAccessRequests(ctx, eve, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(requests, qt.HasLen, 0)
+
+	err = j.ApproveAccessRequest(ctx, eve, ar.ID, "")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	err = j.ApproveAccessRequest(ctx, bob, ar.ID, "")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	err = j.ApproveAccessRequest(ctx, alice, ar.ID, "approved for the release")
+	c.Assert(err, qt.IsNil)
+	access, err := j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "write")
+	c.Assert(notifier.events, qt.HasLen, 2)
+	c.Check(notifier.events[1].Type, qt.Equals, apiparams.AccessRequestApproved)
+	c.Check(notifier.events[1].Request.Reviewer, qt.Equals, "alice@canonical.com")
+	c.Check(notifier.events[1].Request.ReviewComment, qt.Equals, "approved for the release")
+
+	err = j.DenyAccessRequest(ctx, alice, ar.ID, "")
+	c.Check(err, qt.ErrorMatches, `access request [0-9]+ has already been approved`)
+
+	ar, err = j.RequestAccess(ctx, eve, mt.String(), "read", "")
+	c.Assert(err, qt.IsNil)
+	err = j.DenyAccessRequest(ctx, alice, ar.ID, "")
+	c.Assert(err, qt.IsNil)
+	requests, err = j.ListAccessRequests(ctx, eve, dbmodel.AccessRequestDenied)
+	c.Assert(err, qt.IsNil)
+	c.Assert(requests, qt.HasLen, 1)
+	c.Check(requests[0].Reviewer, qt.Equals, "alice@canonical.com")
+	c.Check(requests[0].ReviewedAt, qt.IsNotNil)
+	c.Check(notifier.events[len(notifier.events)-1].Type, qt.Equals, apiparams.AccessRequestDenied)
+
+	err = j.ApproveAccessRequest(ctx, alice, ar.ID+1, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	c := qt.New(t)
+
+	var event apiparams.AccessRequestEvent
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, qt.Equals, http.MethodPost)
+		c.Check(req.Header.Get("Content-Type"), qt.Equals, "application/json")
+		c.Check(json.NewDecoder(req.Body).Decode(&event), qt.IsNil)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	n := jimm.WebhookNotifier{URL: srv.URL}
+	err := n.NotifyAccessRequest(context.Background(), apiparams.AccessRequestEvent{
+		Type: apiparams.AccessRequestCreated,
+		Request: apiparams.AccessRequest{
+			ID:     1,
+			User:   "bob@canonical.com",
+			Target: "cloud-test-cloud",
+			Access: "add-model",
+		},
+		Reviewers: []string{"alice@canonical.com"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(event.Request.Target, qt.Equals, "cloud-test-cloud")
+	c.Check(event.Reviewers, qt.DeepEquals, []string{"alice@canonical.com"})
+
+	status = http.StatusInternalServerError
+	err = n.NotifyAccessRequest(context.Background(), apiparams.AccessRequestEvent{})
+	c.Check(err, qt.ErrorMatches, `webhook returned status 500 Internal Server Error`)
+}
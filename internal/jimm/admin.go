@@ -5,6 +5,7 @@ package jimm
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"golang.org/x/oauth2"
 
@@ -78,8 +79,17 @@ func (j *JIMM) LoginClientCredentials(ctx context.Context, clientID string, clie
 }
 
 // LoginWithSessionToken verifies a user's session token before the user is logged in.
+// Model tokens, created with CreateModelToken, are also accepted in place of
+// a session token.
 func (j *JIMM) LoginWithSessionToken(ctx context.Context, sessionToken string) (*openfga.User, error) {
 	const op = errors.Op("jimm.LoginWithSessionToken")
+	if strings.HasPrefix(sessionToken, ModelTokenPrefix) {
+		user, err := j.loginWithModelToken(ctx, sessionToken)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		return user, nil
+	}
 	jwtToken, err := j.OAuthAuthenticator.VerifySessionToken(sessionToken)
 	if err != nil {
 		return nil, errors.E(op, err)
@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/juju/version"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// AgentVersionReport reports the agent versions running in every model,
+// as last seen by the watcher, and the totals for each cost center.
+// Models are attributed to cost centers as in UsageReport. A model is
+// outdated if the model, or any of its machine or unit agents, runs an
+// older version than its controller. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) AgentVersionReport(ctx context.Context, user *openfga.User) (apiparams.AgentVersionReport, error) {
+	const op = errors.Op("jimm.AgentVersionReport")
+
+	if !user.JimmAdmin {
+		return apiparams.AgentVersionReport{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	groupCostCenters, err := j.groupCostCenters(ctx)
+	if err != nil {
+		return apiparams.AgentVersionReport{}, errors.E(op, err)
+	}
+
+	report := apiparams.AgentVersionReport{
+		Time:        time.Now().UTC(),
+		Models:      []apiparams.ModelAgentVersions{},
+		CostCenters: []apiparams.CostCenterAgentVersions{},
+	}
+	totals := make(map[string]*apiparams.CostCenterAgentVersions)
+	err = j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		mv := modelAgentVersions(ctx, m)
+		mv.CostCenter, _ = modelCostCenter(m, groupCostCenters)
+		report.Models = append(report.Models, mv)
+
+		total := totals[mv.CostCenter]
+		if total == nil {
+			total = &apiparams.CostCenterAgentVersions{
+				CostCenter:    mv.CostCenter,
+				AgentVersions: make(map[string]int64),
+			}
+			totals[mv.CostCenter] = total
+		}
+		total.Models++
+		if mv.Outdated {
+			total.OutdatedModels++
+		}
+		for v, n := range mv.AgentVersions {
+			total.AgentVersions[v] += n
+		}
+		total.OutdatedAgents += mv.OutdatedAgents
+		return nil
+	})
+	if err != nil {
+		return apiparams.AgentVersionReport{}, errors.E(op, err)
+	}
+
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].Owner != report.Models[j].Owner {
+			return report.Models[i].Owner < report.Models[j].Owner
+		}
+		return report.Models[i].Name < report.Models[j].Name
+	})
+	for _, total := range totals {
+		report.CostCenters = append(report.CostCenters, *total)
+	}
+	sort.Slice(report.CostCenters, func(i, j int) bool {
+		return report.CostCenters[i].CostCenter < report.CostCenters[j].CostCenter
+	})
+	return report, nil
+}
+
+// modelAgentVersions returns the agent versions running in the given
+// model compared with the version of its controller. Versions that
+// cannot be parsed are reported but never considered outdated.
+func modelAgentVersions(ctx context.Context, m *dbmodel.Model) apiparams.ModelAgentVersions {
+	mv := apiparams.ModelAgentVersions{
+		ModelTag:          m.ResourceTag().String(),
+		Name:              m.Name,
+		Owner:             m.OwnerIdentityName,
+		Controller:        m.Controller.Name,
+		ControllerVersion: m.Controller.AgentVersion,
+		ModelVersion:      m.Status.Version,
+	}
+	if len(m.AgentVersions) > 0 {
+		mv.AgentVersions = make(map[string]int64, len(m.AgentVersions))
+		for v, n := range m.AgentVersions {
+			mv.AgentVersions[v] = n
+		}
+	}
+	if m.Controller.AgentVersion == "" {
+		return mv
+	}
+	cv, err := version.Parse(m.Controller.AgentVersion)
+	if err != nil {
+		zapctx.Warn(ctx, "cannot parse controller agent version", zap.String("controller", m.Controller.Name), zap.String("version", m.Controller.AgentVersion))
+		return mv
+	}
+	older := func(s string) bool {
+		v, err := version.Parse(s)
+		return err == nil && v.Compare(cv) < 0
+	}
+	for v, n := range m.AgentVersions {
+		if older(v) {
+			mv.OutdatedAgents += n
+		}
+	}
+	mv.Outdated = mv.OutdatedAgents > 0 || older(m.Status.Version)
+	return mv
+}
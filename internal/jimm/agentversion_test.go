@@ -0,0 +1,164 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const agentVersionTestEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+- name: test-cred
+  cloud: test
+  owner: charlie@canonical.com
+  type: empty
+controllers:
+- name: test
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+  agent-version: 3.5.1
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  agent-version: 3.5.1
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  agent-version: 3.5.1
+- name: model-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  owner: charlie@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  agent-version: 3.4.0
+users:
+- username: bob@canonical.com
+  controller-access: superuser
+`
+
+func TestAgentVersionReport(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, agentVersionTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	bob.JimmAdmin = true
+
+	agentVersions := map[string]dbmodel.Int64Map{
+		"model-1": {"3.5.1": 3},
+		"model-2": {"3.5.1": 1, "3.4.0": 2, "unknown": 1},
+		"model-3": {"3.4.0": 1},
+	}
+	for _, m := range env.Models {
+		var dbm dbmodel.Model
+		dbm.SetTag(names.NewModelTag(m.UUID))
+		err := j.Database.GetModel(ctx, &dbm)
+		c.Assert(err, qt.IsNil)
+		dbm.AgentVersions = agentVersions[dbm.Name]
+		err = j.Database.UpdateModel(ctx, &dbm)
+		c.Assert(err, qt.IsNil)
+	}
+	err = j.SetCostCenter(ctx, bob, "user-alice@canonical.com", "CC-alice")
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.AgentVersionReport(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	report, err := j.AgentVersionReport(ctx, bob)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Time.IsZero(), qt.IsFalse)
+	c.Check(report.Models, qt.DeepEquals, []apiparams.ModelAgentVersions{{
+		ModelTag:          "model-00000002-0000-0000-0000-000000000001",
+		Name:              "model-1",
+		Owner:             "alice@canonical.com",
+		Controller:        "test",
+		ControllerVersion: "3.5.1",
+		ModelVersion:      "3.5.1",
+		AgentVersions:     map[string]int64{"3.5.1": 3},
+		CostCenter:        "CC-alice",
+	}, {
+		ModelTag:          "model-00000002-0000-0000-0000-000000000002",
+		Name:              "model-2",
+		Owner:             "alice@canonical.com",
+		Controller:        "test",
+		ControllerVersion: "3.5.1",
+		ModelVersion:      "3.5.1",
+		AgentVersions:     map[string]int64{"3.5.1": 1, "3.4.0": 2, "unknown": 1},
+		OutdatedAgents:    2,
+		Outdated:          true,
+		CostCenter:        "CC-alice",
+	}, {
+		ModelTag:          "model-00000002-0000-0000-0000-000000000003",
+		Name:              "model-3",
+		Owner:             "charlie@canonical.com",
+		Controller:        "test",
+		ControllerVersion: "3.5.1",
+		ModelVersion:      "3.4.0",
+		AgentVersions:     map[string]int64{"3.4.0": 1},
+		OutdatedAgents:    1,
+		Outdated:          true,
+	}})
+	c.Check(report.CostCenters, qt.DeepEquals, []apiparams.CostCenterAgentVersions{{
+		CostCenter:     "",
+		Models:         1,
+		OutdatedModels: 1,
+		AgentVersions:  map[string]int64{"3.4.0": 1},
+		OutdatedAgents: 1,
+	}, {
+		CostCenter:     "CC-alice",
+		Models:         2,
+		OutdatedModels: 1,
+		AgentVersions:  map[string]int64{"3.5.1": 4, "3.4.0": 2, "unknown": 1},
+		OutdatedAgents: 2,
+	}})
+}
@@ -178,7 +178,8 @@ func (j *JIMM) Offer(ctx context.Context, user *openfga.User, offer AddApplicati
 
 // GetApplicationOfferConsumeDetails consume the application offer
 // specified by details.ApplicationOfferDetails.OfferURL and completes
-// the rest of the details.
+// the rest of the details. An error with the code CodeQuotaLimitExceeded
+// is returned if the offer has reached its consumer limit.
 func (j *JIMM) GetApplicationOfferConsumeDetails(ctx context.Context, user *openfga.User, details *jujuparams.ConsumeOfferDetails, v bakery.Version) error {
 	const op = errors.Op("jimm.GetApplicationOfferConsumeDetails")
 
@@ -207,6 +208,9 @@ func (j *JIMM) GetApplicationOfferConsumeDetails(ctx context.Context, user *open
 		//   - think about the returned error code
 		return errors.E(op, errors.CodeNotFound)
 	}
+	if err := j.checkOfferConsumerLimit(&offer); err != nil {
+		return errors.E(op, err)
+	}
 
 	api, err := j.dial(
 		ctx,
@@ -240,9 +244,15 @@ func (j *JIMM) GetApplicationOfferConsumeDetails(ctx context.Context, user *open
 		ControllerTag: offer.Model.Controller.Tag().String(),
 		Alias:         offer.Model.Controller.Name,
 	}
-	if offer.Model.Controller.PublicAddress != "" {
+	switch {
+	case offer.Model.Controller.PublicAddress != "":
 		details.ControllerInfo.Addrs = []string{offer.Model.Controller.PublicAddress}
-	} else {
+	case len(offer.Model.Controller.ClientAddresses) > 0:
+		// The controller reports the addresses JIMM dials, which
+		// clients may not be able to reach.
+		details.ControllerInfo.Addrs = offer.Model.Controller.ToAPIControllerInfo().ClientAddresses
+		details.ControllerInfo.CACert = ci.CACert
+	default:
 		details.ControllerInfo.Addrs = ci.Addrs
 		details.ControllerInfo.CACert = ci.CACert
 	}
@@ -0,0 +1,155 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// SearchApplications searches all models for applications with an
+// endpoint matching the given request. Endpoints are found from the
+// relations reported by the controllers' watchers and from the
+// application offers known to JIMM. Only applications in models the user
+// can read, or offered through offers the user can read, are returned.
+// JIMM administrators can see the applications in every model.
+func (j *JIMM) SearchApplications(ctx context.Context, user *openfga.User, req apiparams.SearchApplicationsRequest) ([]apiparams.ApplicationSearchResult, error) {
+	const op = errors.Op("jimm.SearchApplications")
+
+	if req.Interface == "" && req.Endpoint == "" {
+		return nil, errors.E(op, errors.CodeBadRequest, "interface or endpoint must be specified")
+	}
+
+	var filters []db.ApplicationEndpointFilter
+	if req.Interface != "" {
+		filters = append(filters, db.ApplicationEndpointFilterByInterface(req.Interface))
+	}
+	if req.Endpoint != "" {
+		filters = append(filters, db.ApplicationEndpointFilterByName(req.Endpoint))
+	}
+	if req.Role != "" {
+		filters = append(filters, db.ApplicationEndpointFilterByRole(req.Role))
+	}
+	search := true
+	if !user.JimmAdmin {
+		modelUUIDs, err := user.ListModels(ctx, ofganames.ReaderRelation)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		search = len(modelUUIDs) > 0
+		filters = append(filters, db.ApplicationEndpointFilterByModelUUID(modelUUIDs))
+	}
+	var eps []dbmodel.ApplicationEndpoint
+	if search {
+		var err error
+		eps, err = j.Database.FindApplicationEndpoints(ctx, filters...)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
+
+	type resultKey struct {
+		modelID     uint
+		application string
+		endpoint    string
+	}
+	results := make(map[resultKey]*apiparams.ApplicationSearchResult)
+	modelIDs := make(map[uint]bool)
+	result := func(m *dbmodel.Model, application, endpoint, role, iface string) *apiparams.ApplicationSearchResult {
+		k := resultKey{m.ID, application, endpoint}
+		if r, ok := results[k]; ok {
+			return r
+		}
+		modelIDs[m.ID] = true
+		r := &apiparams.ApplicationSearchResult{
+			Controller:  m.Controller.Name,
+			ModelUUID:   m.UUID.String,
+			ModelName:   m.Name,
+			Owner:       m.OwnerIdentityName,
+			Application: application,
+			Endpoint:    endpoint,
+			Role:        role,
+			Interface:   iface,
+		}
+		results[k] = r
+		return r
+	}
+	for i := range eps {
+		ep := &eps[i]
+		result(&ep.Model, ep.ApplicationName, ep.Name, ep.Role, ep.Interface)
+	}
+
+	offerUUIDs, err := user.ListApplicationOffers(ctx, ofganames.ReaderRelation)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if len(offerUUIDs) > 0 {
+		offerFilters := []db.ApplicationOfferFilter{db.ApplicationOfferFilterByUUID(offerUUIDs)}
+		if req.Interface != "" {
+			offerFilters = append(offerFilters, db.ApplicationOfferFilterByInterface(req.Interface))
+		}
+		offers, err := j.Database.SearchApplicationOffers(ctx, 0, offerFilters...)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		for i := range offers {
+			offer := &offers[i]
+			for _, ep := range offer.Endpoints {
+				if (req.Interface != "" && ep.Interface != req.Interface) ||
+					(req.Endpoint != "" && ep.Name != req.Endpoint) ||
+					(req.Role != "" && ep.Role != req.Role) {
+					continue
+				}
+				r := result(&offer.Model, offer.ApplicationName, ep.Name, ep.Role, ep.Interface)
+				r.OfferURLs = append(r.OfferURLs, offer.URL)
+			}
+		}
+	}
+
+	ids := make([]uint, 0, len(modelIDs))
+	for id := range modelIDs {
+		ids = append(ids, id)
+	}
+	charms, err := j.Database.ListModelApplicationCharms(ctx, ids)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	charmsByApplication := make(map[resultKey]*dbmodel.ApplicationCharm, len(charms))
+	for i := range charms {
+		charmsByApplication[resultKey{modelID: charms[i].ModelID, application: charms[i].ApplicationName}] = &charms[i]
+	}
+
+	matches := make([]apiparams.ApplicationSearchResult, 0, len(results))
+	for k, r := range results {
+		if ac, ok := charmsByApplication[resultKey{modelID: k.modelID, application: k.application}]; ok {
+			r.CharmURL = ac.CharmURL
+			r.Exposed = ac.Exposed
+		}
+		if req.ExposedOnly && !r.Exposed && len(r.OfferURLs) == 0 {
+			continue
+		}
+		sort.Strings(r.OfferURLs)
+		matches = append(matches, *r)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Controller != b.Controller {
+			return a.Controller < b.Controller
+		}
+		if a.ModelUUID != b.ModelUUID {
+			return a.ModelUUID < b.ModelUUID
+		}
+		if a.Application != b.Application {
+			return a.Application < b.Application
+		}
+		return a.Endpoint < b.Endpoint
+	})
+	return matches, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestSearchApplications(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	m := dbmodel.Model{}
+	m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	err = j.Database.SetApplicationEndpoints(ctx, []dbmodel.ApplicationEndpoint{{
+		ModelID:         m.ID,
+		ApplicationName: "postgresql",
+		Name:            "database",
+		Role:            "provider",
+		Interface:       "postgresql_client",
+	}, {
+		ModelID:         m.ID,
+		ApplicationName: "app",
+		Name:            "db",
+		Role:            "requirer",
+		Interface:       "postgresql_client",
+	}})
+	c.Assert(err, qt.IsNil)
+	err = j.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         m.ID,
+		ApplicationName: "postgresql",
+		CharmURL:        "ch:amd64/jammy/postgresql-363",
+		Exposed:         true,
+	})
+	c.Assert(err, qt.IsNil)
+
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	eve := openfga.NewUser(&dbmodel.Identity{Name: "eve@canonical.com"}, client)
+	admin := openfga.NewUser(&dbmodel.Identity{Name: "admin@canonical.com"}, client)
+	admin.JimmAdmin = true
+
+	_, err = j.SearchApplications(ctx, charlie, apiparams.SearchApplicationsRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	results, err := j.SearchApplications(ctx, charlie, apiparams.SearchApplicationsRequest{Interface: "postgresql_client"})
+	c.Assert(err, qt.IsNil)
+	c.Check(results, qt.DeepEquals, []apiparams.ApplicationSearchResult{{
+		Controller:  "controller-1",
+		ModelUUID:   "00000002-0000-0000-0000-000000000001",
+		ModelName:   "model-1",
+		Owner:       "alice@canonical.com",
+		Application: "app",
+		Endpoint:    "db",
+		Role:        "requirer",
+		Interface:   "postgresql_client",
+	}, {
+		Controller:  "controller-1",
+		ModelUUID:   "00000002-0000-0000-0000-000000000001",
+		ModelName:   "model-1",
+		Owner:       "alice@canonical.com",
+		Application: "postgresql",
+		CharmURL:    "ch:amd64/jammy/postgresql-363",
+		Exposed:     true,
+		Endpoint:    "database",
+		Role:        "provider",
+		Interface:   "postgresql_client",
+	}})
+
+	results, err = j.SearchApplications(ctx, charlie, apiparams.SearchApplicationsRequest{
+		Interface:   "postgresql_client",
+		Role:        "provider",
+		ExposedOnly: true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.HasLen, 1)
+	c.Check(results[0].Application, qt.Equals, "postgresql")
+
+	results, err = j.SearchApplications(ctx, charlie, apiparams.SearchApplicationsRequest{Endpoint: "db", ExposedOnly: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(results, qt.HasLen, 0)
+
+	results, err = j.SearchApplications(ctx, eve, apiparams.SearchApplicationsRequest{Interface: "postgresql_client"})
+	c.Assert(err, qt.IsNil)
+	c.Check(results, qt.HasLen, 0)
+
+	results, err = j.SearchApplications(ctx, admin, apiparams.SearchApplicationsRequest{Endpoint: "db"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(results, qt.HasLen, 1)
+	c.Check(results[0].Application, qt.Equals, "app")
+}
@@ -123,9 +123,33 @@ func (r recorder) HandleRequest(header *rpc.Header, body interface{}) error {
 func (o recorder) HandleReply(r rpc.Request, header *rpc.Header, body interface{}) error {
 	d := time.Since(o.start)
 	servermon.WebsocketRequestDuration.WithLabelValues(r.Type, r.Action).Observe(float64(d) / float64(time.Second))
+	if authorizationDenied(header, body) {
+		servermon.AuthorizationDenialCount.WithLabelValues(r.Type, r.Action).Inc()
+	}
 	return o.logger.LogResponse(r, header, body)
 }
 
+// authorizationDenied reports whether the reply with the given header and
+// body shows that the request, or any part of a bulk request, was denied
+// because the user was not authorized.
+func authorizationDenied(header *rpc.Header, body interface{}) bool {
+	if isAuthorizationDenial(header.ErrorCode) {
+		return true
+	}
+	if results, ok := body.(params.ErrorResults); ok {
+		for _, r := range results.Results {
+			if r.Error != nil && isAuthorizationDenial(r.Error.Code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isAuthorizationDenial(code string) bool {
+	return code == params.CodeUnauthorized || code == params.CodeForbidden
+}
+
 // AuditLogCleanupService is a service capable of cleaning up audit logs
 // on a defined retention period. The retention period is in DAYS.
 type auditLogCleanupService struct {
@@ -0,0 +1,137 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// captureBootstrapProfile reads the configuration of the given controller
+// and the definition of the cloud hosting it and records them as a new
+// version of the controller's bootstrap profile, unless they are
+// unchanged since the last version.
+func (j *JIMM) captureBootstrapProfile(ctx context.Context, api API, ctl *dbmodel.Controller) error {
+	config, err := api.ControllerConfig(ctx)
+	if err != nil {
+		return err
+	}
+	var cloud jujuparams.Cloud
+	if err := api.Cloud(ctx, names.NewCloudTag(ctl.CloudName), &cloud); err != nil {
+		return err
+	}
+	cloudJSON, err := json.Marshal(cloud)
+	if err != nil {
+		return err
+	}
+	p := dbmodel.ControllerBootstrapProfile{
+		ControllerName:   ctl.Name,
+		AgentVersion:     ctl.AgentVersion,
+		CloudName:        ctl.CloudName,
+		CloudRegion:      ctl.CloudRegion,
+		ControllerConfig: config,
+		Cloud:            dbmodel.JSON(cloudJSON),
+	}
+	// Maps are marshaled with sorted keys, so equal profiles have
+	// equal hashes.
+	buf, err := json.Marshal(struct {
+		AgentVersion     string
+		CloudRegion      string
+		ControllerConfig map[string]interface{}
+		Cloud            json.RawMessage
+	}{p.AgentVersion, p.CloudRegion, config, cloudJSON})
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(buf)
+	p.Hash = hex.EncodeToString(hash[:])
+
+	latest := dbmodel.ControllerBootstrapProfile{ControllerName: ctl.Name}
+	err = j.Database.GetControllerBootstrapProfile(ctx, &latest)
+	switch {
+	case err == nil && latest.Hash == p.Hash:
+		return nil
+	case err != nil && errors.ErrorCode(err) != errors.CodeNotFound:
+		return err
+	}
+	if err := j.Database.AddControllerBootstrapProfile(ctx, &p); err != nil {
+		return err
+	}
+	zapctx.Info(ctx, "captured controller bootstrap profile", zap.String("controller", ctl.Name), zap.Int("version", p.Version))
+	return nil
+}
+
+// CaptureControllerBootstrapProfiles captures the bootstrap profile of
+// every controller not in maintenance, recording a new version of each
+// profile that has changed. Controllers that cannot be reached are
+// skipped.
+func (j *JIMM) CaptureControllerBootstrapProfiles(ctx context.Context) error {
+	const op = errors.Op("jimm.CaptureControllerBootstrapProfiles")
+
+	controllers, err := j.selectControllers(ctx, "")
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for i := range controllers {
+		ctl := &controllers[i]
+		if ctl.MaintenanceSince.Valid {
+			continue
+		}
+		if err := j.captureControllerBootstrapProfile(ctx, ctl); err != nil {
+			zapctx.Warn(ctx, "cannot capture controller bootstrap profile", zap.String("controller", ctl.Name), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (j *JIMM) captureControllerBootstrapProfile(ctx context.Context, ctl *dbmodel.Controller) error {
+	api, err := j.dialController(ctx, ctl)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return j.captureBootstrapProfile(ctx, api, ctl)
+}
+
+// GetControllerBootstrapProfile returns the given version of the named
+// controller's bootstrap profile, or the latest version if the version
+// is zero. Only JIMM administrators may get bootstrap profiles.
+func (j *JIMM) GetControllerBootstrapProfile(ctx context.Context, user *openfga.User, controllerName string, version int) (apiparams.ControllerBootstrapProfile, error) {
+	const op = errors.Op("jimm.GetControllerBootstrapProfile")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.ControllerBootstrapProfile{}, errors.E(op, err)
+	}
+	p := dbmodel.ControllerBootstrapProfile{
+		ControllerName: controllerName,
+		Version:        version,
+	}
+	if err := j.Database.GetControllerBootstrapProfile(ctx, &p); err != nil {
+		return apiparams.ControllerBootstrapProfile{}, errors.E(op, err)
+	}
+	profile := apiparams.ControllerBootstrapProfile{
+		Controller:       p.ControllerName,
+		Version:          p.Version,
+		CapturedAt:       p.CreatedAt,
+		AgentVersion:     p.AgentVersion,
+		CloudName:        p.CloudName,
+		CloudRegion:      p.CloudRegion,
+		ControllerConfig: p.ControllerConfig,
+	}
+	if err := json.Unmarshal([]byte(p.Cloud), &profile.Cloud); err != nil {
+		return apiparams.ControllerBootstrapProfile{}, errors.E(op, err)
+	}
+	return profile, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestControllerBootstrapProfile(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	cloud := jujuparams.Cloud{
+		Type:      "ec2",
+		AuthTypes: []string{"userpass"},
+		Endpoint:  "https://example.com",
+		Regions: []jujuparams.CloudRegion{{
+			Name: "eu-west-1",
+		}},
+	}
+	config := map[string]interface{}{
+		"api-port":        float64(17070),
+		"controller-uuid": jimmtest.DefaultControllerUUID,
+	}
+	api := &jimmtest.API{
+		Clouds_: func(context.Context) (map[names.CloudTag]jujuparams.Cloud, error) {
+			return map[names.CloudTag]jujuparams.Cloud{names.NewCloudTag("aws"): cloud}, nil
+		},
+		Cloud_: func(_ context.Context, tag names.CloudTag, ci *jujuparams.Cloud) error {
+			c.Check(tag.Id(), qt.Equals, "aws")
+			*ci = cloud
+			return nil
+		},
+		ControllerConfig_: func(context.Context) (map[string]interface{}, error) {
+			return config, nil
+		},
+		ControllerModelSummary_: func(_ context.Context, ms *jujuparams.ModelSummary) error {
+			ms.Name = "controller"
+			ms.UUID = "5fddf0ed-83d5-47e8-ae7b-a4b27fc04a9f"
+			ms.ControllerUUID = jimmtest.DefaultControllerUUID
+			ms.IsController = true
+			ms.CloudTag = "cloud-aws"
+			ms.CloudRegion = "eu-west-1"
+			return nil
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+
+	ctl := dbmodel.Controller{
+		Name:              "controller-1",
+		AdminIdentityName: "admin",
+		AdminPassword:     "5ecret",
+		PublicAddress:     "example.com:443",
+	}
+	err = j.AddController(ctx, admin, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.GetControllerBootstrapProfile(ctx, openfga.NewUser(bob, client), "controller-1", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// The profile is captured when the controller is added.
+	profile, err := j.GetControllerBootstrapProfile(ctx, admin, "controller-1", 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(profile.Controller, qt.Equals, "controller-1")
+	c.Check(profile.Version, qt.Equals, 1)
+	c.Check(profile.CloudName, qt.Equals, "aws")
+	c.Check(profile.CloudRegion, qt.Equals, "eu-west-1")
+	c.Check(profile.ControllerConfig, qt.DeepEquals, config)
+	c.Check(profile.Cloud, qt.DeepEquals, cloud)
+
+	// Unchanged profiles are not recorded again.
+	err = j.CaptureControllerBootstrapProfiles(ctx)
+	c.Assert(err, qt.IsNil)
+	profile, err = j.GetControllerBootstrapProfile(ctx, admin, "controller-1", 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(profile.Version, qt.Equals, 1)
+
+	config = map[string]interface{}{
+		"api-port":        float64(17071),
+		"controller-uuid": jimmtest.DefaultControllerUUID,
+	}
+	err = j.CaptureControllerBootstrapProfiles(ctx)
+	c.Assert(err, qt.IsNil)
+	profile, err = j.GetControllerBootstrapProfile(ctx, admin, "controller-1", 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(profile.Version, qt.Equals, 2)
+	c.Check(profile.ControllerConfig["api-port"], qt.Equals, float64(17071))
+
+	profile, err = j.GetControllerBootstrapProfile(ctx, admin, "controller-1", 1)
+	c.Assert(err, qt.IsNil)
+	c.Check(profile.ControllerConfig["api-port"], qt.Equals, float64(17070))
+
+	_, err = j.GetControllerBootstrapProfile(ctx, admin, "controller-2", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/names/v5"
+	"gopkg.in/yaml.v3"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// BundleExportOptions holds the options used when exporting a model as a
+// bundle.
+type BundleExportOptions struct {
+	// IncludeCharmDefaults includes the default values of charm
+	// configuration settings in the exported bundle.
+	IncludeCharmDefaults bool
+
+	// StripCloudConstraints removes constraints that only have meaning
+	// on the cloud hosting the model, such as instance types and
+	// availability zones, from the exported bundle.
+	StripCloudConstraints bool
+}
+
+// ExportModelBundle asks the controller hosting the model with the given
+// tag to export the model as a bundle, which includes the applications,
+// relations, machine placement and offers in the model. The bundle is
+// returned in YAML format. Only users with read access to the model may
+// export its bundle.
+func (j *JIMM) ExportModelBundle(ctx context.Context, user *openfga.User, mt names.ModelTag, opts BundleExportOptions) (string, error) {
+	const op = errors.Op("jimm.ExportModelBundle")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return "", errors.E(op, err)
+	}
+	if ok, err := user.IsModelReader(ctx, mt); !ok || err != nil {
+		return "", errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	api, err := j.dial(ctx, &m.Controller, mt)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	defer api.Close()
+
+	bundle, err := api.ExportBundle(ctx, opts.IncludeCharmDefaults)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	if opts.StripCloudConstraints {
+		bundle, err = stripCloudConstraints(bundle)
+		if err != nil {
+			return "", errors.E(op, err)
+		}
+	}
+	return bundle, nil
+}
+
+// stripCloudConstraints removes the cloud-specific constraints from the
+// applications and machines in every document of the given bundle.
+func stripCloudConstraints(bundle string) (string, error) {
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewBufferString(bundle))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", errors.E(err, "cannot parse bundle")
+		}
+		if len(doc.Content) > 0 {
+			for _, key := range []string{"applications", "machines"} {
+				if err := stripEntityConstraints(mappingValue(doc.Content[0], key)); err != nil {
+					return "", err
+				}
+			}
+		}
+		docs = append(docs, &doc)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, doc := range docs {
+		if err := enc.Encode(doc); err != nil {
+			return "", errors.E(err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return "", errors.E(err)
+	}
+	return buf.String(), nil
+}
+
+// stripEntityConstraints removes the cloud-specific constraints from each
+// entity in the given mapping of entity names to entity definitions.
+// Entities that are left without any constraints have their constraints
+// key removed.
+func stripEntityConstraints(entities *yaml.Node) error {
+	if entities == nil || entities.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 1; i < len(entities.Content); i += 2 {
+		entity := entities.Content[i]
+		if entity.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j < len(entity.Content); j += 2 {
+			if entity.Content[j].Value != "constraints" {
+				continue
+			}
+			cons, err := constraints.Parse(entity.Content[j+1].Value)
+			if err != nil {
+				return errors.E(err, "cannot parse constraints")
+			}
+			cons.InstanceType = nil
+			cons.InstanceRole = nil
+			cons.Zones = nil
+			cons.RootDiskSource = nil
+			cons.VirtType = nil
+			cons.ImageID = nil
+			cons.AllocatePublicIP = nil
+			cons.Tags = nil
+			if s := cons.String(); s != "" {
+				entity.Content[j+1].Value = s
+			} else {
+				entity.Content = append(entity.Content[:j], entity.Content[j+2:]...)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the value of the given key in the given mapping
+// node, or nil if the node is not a mapping or does not contain the key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
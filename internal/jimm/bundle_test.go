@@ -0,0 +1,179 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const exportModelBundleTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+- username: eve@canonical.com
+  controller-access: login
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: read
+`
+
+const exportedBundle = `default-base: ubuntu@22.04/stable
+applications:
+  postgresql:
+    charm: postgresql
+    channel: 14/stable
+    num_units: 1
+    to:
+    - "0"
+    constraints: arch=amd64 cores=2 instance-type=m5.large zones=us-east-1a
+  ui:
+    charm: ui
+    num_units: 1
+    to:
+    - "1"
+    constraints: instance-type=t3.micro
+machines:
+  "0":
+    constraints: arch=amd64 mem=4096M root-disk-source=gp3
+  "1": {}
+relations:
+- - ui:db
+  - postgresql:db
+--- # overlay.yaml
+applications:
+  postgresql:
+    offers:
+      db:
+        endpoints:
+        - db
+`
+
+const strippedBundle = `default-base: ubuntu@22.04/stable
+applications:
+  postgresql:
+    charm: postgresql
+    channel: 14/stable
+    num_units: 1
+    to:
+      - "0"
+    constraints: arch=amd64 cores=2
+  ui:
+    charm: ui
+    num_units: 1
+    to:
+      - "1"
+machines:
+  "0":
+    constraints: arch=amd64 mem=4096M
+  "1": {}
+relations:
+  - - ui:db
+    - postgresql:db
+---
+# overlay.yaml
+applications:
+  postgresql:
+    offers:
+      db:
+        endpoints:
+          - db
+`
+
+func TestExportModelBundle(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var includeCharmDefaults bool
+	var exportErr error
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				ExportBundle_: func(_ context.Context, defaults bool) (string, error) {
+					includeCharmDefaults = defaults
+					return exportedBundle, exportErr
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, exportModelBundleTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	eveIdentity := env.User("eve@canonical.com").DBObject(c, j.Database)
+	eve := openfga.NewUser(&eveIdentity, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	bundle, err := j.ExportModelBundle(ctx, bob, mt, jimm.BundleExportOptions{})
+	c.Assert(err, qt.IsNil)
+	c.Check(bundle, qt.Equals, exportedBundle)
+	c.Check(includeCharmDefaults, qt.IsFalse)
+
+	bundle, err = j.ExportModelBundle(ctx, bob, mt, jimm.BundleExportOptions{
+		IncludeCharmDefaults:  true,
+		StripCloudConstraints: true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(bundle, qt.Equals, strippedBundle)
+	c.Check(includeCharmDefaults, qt.IsTrue)
+
+	_, err = j.ExportModelBundle(ctx, eve, mt, jimm.BundleExportOptions{})
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+
+	_, err = j.ExportModelBundle(ctx, bob, names.NewModelTag("00000002-0000-0000-0000-000000000002"), jimm.BundleExportOptions{})
+	c.Check(err, qt.ErrorMatches, "model not found")
+
+	exportErr = errors.New("export failed")
+	_, err = j.ExportModelBundle(ctx, bob, mt, jimm.BundleExportOptions{})
+	c.Check(err, qt.ErrorMatches, "export failed")
+}
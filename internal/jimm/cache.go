@@ -4,8 +4,11 @@ package jimm
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
@@ -13,14 +16,23 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
 )
 
+// dialFailureTTL is the time for which a failure to connect to a
+// controller is remembered. Dials made in this time fail immediately
+// rather than each waiting for its own connection attempt to fail. A
+// random jitter of up to half the TTL is added so that retries against a
+// broken controller are spread out.
+var dialFailureTTL = 5 * time.Second
+
 // CacheDialer wraps the given Dialer in a cache that will share controller
 // connections between a number of operations.
 func CacheDialer(d Dialer) Dialer {
 	return &cacheDialer{
-		dialer: d,
-		conns:  make(map[string]cachedAPI),
+		dialer:   d,
+		conns:    make(map[string]cachedAPI),
+		failures: make(map[string]dialFailure),
 	}
 }
 
@@ -32,16 +44,34 @@ type cacheDialer struct {
 	// not in the cache.
 	dialer Dialer
 
-	sfg   singleflight.Group
-	mu    sync.Mutex
-	conns map[string]cachedAPI
+	sfg      singleflight.Group
+	mu       sync.Mutex
+	conns    map[string]cachedAPI
+	failures map[string]dialFailure
+}
+
+// A dialFailure records a failure to connect to a controller.
+type dialFailure struct {
+	// since is the time of the first of the consecutive failures.
+	since time.Time
+
+	// until is the time until which dials fail without a new attempt
+	// to connect.
+	until time.Time
+
+	err error
 }
 
 // Dial implements Dialer.Dial.
 func (d *cacheDialer) Dial(ctx context.Context, ctl *dbmodel.Controller, mt names.ModelTag, requiredPermissions map[string]string) (API, error) {
+	if err := d.checkFailure(ctl); err != nil {
+		return nil, err
+	}
 	if mt.Id() != "" {
 		// connections to models are rare, so we don't cache them.
-		return d.dialer.Dial(ctx, ctl, mt, requiredPermissions)
+		api, err := d.dialer.Dial(ctx, ctl, mt, requiredPermissions)
+		d.recordResult(ctl, err)
+		return api, err
 	}
 	rc := d.sfg.DoChan(ctl.Name, func() (interface{}, error) {
 		return d.dial(ctx, ctl, requiredPermissions)
@@ -60,6 +90,15 @@ func (d *cacheDialer) Dial(ctx context.Context, ctl *dbmodel.Controller, mt name
 func (d *cacheDialer) dial(ctx context.Context, ctl *dbmodel.Controller, requiredPermissions map[string]string) (interface{}, error) {
 	d.mu.Lock()
 	capi, ok := d.conns[ctl.Name]
+	if ok && capi.IsBroken() {
+		// The connection is known to have failed, for example it
+		// was recycled because a call on it got stuck, so don't wait
+		// for a ping that may never return.
+		zapctx.Warn(ctx, "cached connection broken")
+		delete(d.conns, ctl.Name)
+		capi.Close()
+		ok = false
+	}
 	if ok {
 		if err := capi.Ping(ctx); err == nil {
 			d.mu.Unlock()
@@ -74,6 +113,7 @@ func (d *cacheDialer) dial(ctx context.Context, ctl *dbmodel.Controller, require
 
 	// We don't have a working connection to the controller, so dial one.
 	api, err := d.dialer.Dial(ctx, ctl, names.ModelTag{}, requiredPermissions)
+	d.recordResult(ctl, err)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +129,42 @@ func (d *cacheDialer) dial(ctx context.Context, ctl *dbmodel.Controller, require
 	return capi, nil
 }
 
+// checkFailure returns an error if a recent attempt to connect to the
+// given controller failed.
+func (d *cacheDialer) checkFailure(ctl *dbmodel.Controller) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, ok := d.failures[ctl.Name]
+	if !ok || !time.Now().Before(f.until) {
+		return nil
+	}
+	return errors.E(errors.CodeConnectionFailed, fmt.Sprintf("controller %s unavailable since %s: %s", ctl.Name, f.since.UTC().Format(time.RFC3339), f.err), f.err)
+}
+
+// recordResult records the result of an attempt to connect to the given
+// controller. Only failures to connect are remembered, other errors, such
+// as a model not being found, are returned to the caller as normal.
+func (d *cacheDialer) recordResult(ctl *dbmodel.Controller, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err == nil {
+		delete(d.failures, ctl.Name)
+		return
+	}
+	if errors.ErrorCode(err) != errors.CodeConnectionFailed {
+		return
+	}
+	now := time.Now()
+	f, ok := d.failures[ctl.Name]
+	if !ok {
+		f.since = now
+	}
+	//nolint:gosec // The jitter does not need a secure random source.
+	f.until = now.Add(dialFailureTTL + time.Duration(rand.Int63n(int64(dialFailureTTL)/2+1)))
+	f.err = err
+	d.failures[ctl.Name] = f
+}
+
 // Close implements io.Closer.
 func (d *cacheDialer) Close() error {
 	d.mu.Lock()
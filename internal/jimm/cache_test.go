@@ -8,6 +8,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/juju/names/v5"
@@ -42,6 +43,46 @@ func TestCacheDialerDialError(t *testing.T) {
 	c.Check(api.SupportsCheckCredentialModels(), qt.Equals, true)
 }
 
+func TestCacheDialerRemembersConnectionFailures(t *testing.T) {
+	c := qt.New(t)
+	c.Patch(jimm.DialFailureTTL, time.Hour)
+
+	testDialer := &jimmtest.Dialer{
+		Err: errors.E(errors.CodeConnectionFailed, "connection refused"),
+	}
+	cd := &countingDialer{dialer: testDialer}
+	dialer := jimm.CacheDialer(cd)
+	ctl := dbmodel.Controller{
+		Name: "test-controller",
+	}
+	_, err := dialer.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.ErrorMatches, `connection refused`)
+	c.Check(cd.count, qt.Equals, int64(1))
+
+	// Further dials, including to models on the controller, fail without
+	// attempting to connect.
+	testDialer.Err = nil
+	testDialer.API = &jimmtest.API{}
+	_, err = dialer.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.ErrorMatches, `controller test-controller unavailable since .*: connection refused`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConnectionFailed)
+	_, err = dialer.Dial(context.Background(), &ctl, names.NewModelTag("00000002-0000-0000-0000-000000000001"), nil)
+	c.Check(err, qt.ErrorMatches, `controller test-controller unavailable since .*: connection refused`)
+	c.Check(cd.count, qt.Equals, int64(1))
+
+	// Once the failure expires the controller is dialed again.
+	c.Patch(jimm.DialFailureTTL, time.Duration(0))
+	dialer = jimm.CacheDialer(cd)
+	testDialer.Err = errors.E(errors.CodeConnectionFailed, "connection refused")
+	_, err = dialer.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Check(err, qt.ErrorMatches, `connection refused`)
+	testDialer.Err = nil
+	api, err := dialer.Dial(context.Background(), &ctl, names.ModelTag{}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(api.Close(), qt.IsNil)
+	c.Check(cd.count, qt.Equals, int64(3))
+}
+
 func TestCacheDialerDialModel(t *testing.T) {
 	c := qt.New(t)
 
@@ -0,0 +1,180 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// invalidationQueueSize is the number of invalidations that can be
+// waiting to be broadcast before further invalidations are dropped.
+const invalidationQueueSize = 1024
+
+// DefaultInvalidationChannel is the database notification channel used
+// by a DatabaseInvalidationBus if no other channel is configured.
+const DefaultInvalidationChannel = "jimm_cache_invalidation"
+
+// A CacheInvalidationKind identifies the cached values removed by a
+// CacheInvalidation.
+type CacheInvalidationKind string
+
+const (
+	InvalidateClouds         CacheInvalidationKind = "clouds"
+	InvalidateControllers    CacheInvalidationKind = "controllers"
+	InvalidateModelAccess    CacheInvalidationKind = "model-access"
+	InvalidateAllModelAccess CacheInvalidationKind = "all-model-access"
+	InvalidateGroups         CacheInvalidationKind = "groups"
+)
+
+// A CacheInvalidation is broadcast by a JIMM replica when it changes
+// state that other replicas might hold in their ResponseCache.
+type CacheInvalidation struct {
+	// Source identifies the cache that made the invalidation.
+	Source string `json:"source"`
+
+	// Kind determines which cached values are invalidated.
+	Kind CacheInvalidationKind `json:"kind"`
+
+	// Key holds the UUID of the model for InvalidateModelAccess
+	// invalidations.
+	Key string `json:"key,omitempty"`
+
+	// Time is the time at which the invalidation was made.
+	Time time.Time `json:"time"`
+}
+
+// An InvalidationBus carries messages between the JIMM replicas sharing
+// a database.
+type InvalidationBus interface {
+	// Publish sends the given message to all current subscribers,
+	// including any subscribers in the same replica.
+	Publish(ctx context.Context, msg string) error
+
+	// Subscribe starts receiving the messages published on the bus.
+	Subscribe(ctx context.Context) (InvalidationSubscription, error)
+}
+
+// An InvalidationSubscription receives the messages published on an
+// InvalidationBus.
+type InvalidationSubscription interface {
+	// Next waits for the next message on the bus. An error is returned
+	// if the context is canceled or messages may have been lost.
+	Next(ctx context.Context) (string, error)
+
+	// Close ends the subscription.
+	Close() error
+}
+
+// A DatabaseInvalidationBus is an InvalidationBus that uses database
+// notifications to send messages.
+type DatabaseInvalidationBus struct {
+	// Database is the database used to send the notifications.
+	Database *db.Database
+
+	// Channel is the notification channel to use. If this is empty
+	// DefaultInvalidationChannel is used.
+	Channel string
+}
+
+// Publish implements InvalidationBus.
+func (b DatabaseInvalidationBus) Publish(ctx context.Context, msg string) error {
+	return b.Database.Notify(ctx, b.channel(), msg)
+}
+
+// Subscribe implements InvalidationBus.
+func (b DatabaseInvalidationBus) Subscribe(ctx context.Context) (InvalidationSubscription, error) {
+	l, err := b.Database.Listen(ctx, b.channel())
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (b DatabaseInvalidationBus) channel() string {
+	if b.Channel == "" {
+		return DefaultInvalidationChannel
+	}
+	return b.Channel
+}
+
+// RunInvalidationBus shares invalidations with the caches of the other
+// JIMM replicas using the given bus. Invalidations made to this cache
+// are broadcast and invalidations received from other caches are
+// applied. RunInvalidationBus runs until the context is canceled or the
+// subscription to the bus fails, any invalidations made while it is not
+// running are only applied locally.
+func (c *ResponseCache) RunInvalidationBus(ctx context.Context, bus InvalidationBus) error {
+	const op = errors.Op("jimm.RunInvalidationBus")
+	if c == nil {
+		return nil
+	}
+
+	sub, err := bus.Subscribe(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer sub.Close()
+
+	// Invalidations made by other replicas while this cache was not
+	// subscribed have been missed.
+	c.purge()
+	c.broadcasting.Store(true)
+	defer c.broadcasting.Store(false)
+
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := sub.Next(ctx)
+			if err != nil {
+				errc <- err
+				return
+			}
+			c.receive(ctx, msg)
+		}
+	}()
+
+	for {
+		select {
+		case inv := <-c.invalidations:
+			buf, err := json.Marshal(inv)
+			if err != nil {
+				return errors.E(op, err)
+			}
+			if err := bus.Publish(ctx, string(buf)); err != nil {
+				zapctx.Warn(ctx, "cannot broadcast cache invalidation", zap.String("kind", string(inv.Kind)), zap.Error(err))
+				servermon.CacheInvalidationsSentCount.WithLabelValues("failed").Inc()
+				continue
+			}
+			servermon.CacheInvalidationsSentCount.WithLabelValues("sent").Inc()
+		case err := <-errc:
+			// Invalidations may have been lost along with the
+			// subscription.
+			c.purge()
+			return errors.E(op, err)
+		}
+	}
+}
+
+// receive applies an invalidation received from the bus.
+func (c *ResponseCache) receive(ctx context.Context, msg string) {
+	var inv CacheInvalidation
+	if err := json.Unmarshal([]byte(msg), &inv); err != nil {
+		zapctx.Warn(ctx, "invalid cache invalidation", zap.Error(err))
+		c.purge()
+		return
+	}
+	if inv.Source == c.source {
+		return
+	}
+	c.apply(inv)
+	servermon.CacheInvalidationLagHistogram.WithLabelValues(string(inv.Kind)).Observe(time.Since(inv.Time).Seconds())
+}
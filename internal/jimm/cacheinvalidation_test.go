@@ -0,0 +1,119 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestRunInvalidationBus(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	database := db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	err = database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// Two replicas share the database, each with its own cache.
+	newReplica := func() *jimm.JIMM {
+		return &jimm.JIMM{
+			UUID:          uuid.NewString(),
+			OpenFGAClient: client,
+			Database:      database,
+			Dialer: &jimmtest.Dialer{
+				API: &jimmtest.API{},
+			},
+			Cache: jimm.NewResponseCache(jimm.ResponseCacheParams{
+				CloudTTL:      time.Hour,
+				ControllerTTL: time.Hour,
+			}),
+		}
+	}
+	j1 := newReplica()
+	j2 := newReplica()
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j1.ResourceTag(), j1.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j1.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	errc := make(chan error, 2)
+	for _, j := range []*jimm.JIMM{j1, j2} {
+		go func() {
+			errc <- j.Cache.RunInvalidationBus(ctx, jimm.DatabaseInvalidationBus{Database: &database})
+		}()
+	}
+	waitFor := func(f func() bool) {
+		c.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for !f() {
+			if time.Now().After(deadline) {
+				c.Fatalf("timed out")
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor(func() bool {
+		return jimm.CacheBroadcasting(j1.Cache) && jimm.CacheBroadcasting(j2.Cache)
+	})
+
+	cloudNames := func(j *jimm.JIMM) []string {
+		var cloudNames []string
+		err := j.ForEachCloud(ctx, diane, func(cl *dbmodel.Cloud) error {
+			cloudNames = append(cloudNames, cl.Name)
+			return nil
+		})
+		c.Assert(err, qt.IsNil)
+		return cloudNames
+	}
+
+	// Both replicas cache the clouds.
+	c.Check(cloudNames(j1), qt.DeepEquals, []string{"test-cloud"})
+	c.Check(cloudNames(j2), qt.DeepEquals, []string{"test-cloud"})
+	err = database.AddCloud(ctx, &dbmodel.Cloud{Name: "test-cloud-2", Type: "test-provider"})
+	c.Assert(err, qt.IsNil)
+	c.Check(cloudNames(j2), qt.DeepEquals, []string{"test-cloud"})
+
+	// Invalidating the clouds in one replica invalidates them in the
+	// other.
+	j1.Cache.InvalidateClouds()
+	c.Check(cloudNames(j1), qt.DeepEquals, []string{"test-cloud", "test-cloud-2"})
+	waitFor(func() bool {
+		return len(cloudNames(j2)) == 2
+	})
+
+	// Once the bus stops invalidations are only applied locally.
+	cancel()
+	c.Check(<-errc, qt.ErrorMatches, `.*context canceled`)
+	c.Check(<-errc, qt.ErrorMatches, `.*context canceled`)
+	c.Check(jimm.CacheBroadcasting(j1.Cache), qt.IsFalse)
+	c.Check(jimm.CacheBroadcasting(j2.Cache), qt.IsFalse)
+}
+
+func TestRunInvalidationBusNilCache(t *testing.T) {
+	c := qt.New(t)
+
+	var cache *jimm.ResponseCache
+	err := cache.RunInvalidationBus(context.Background(), nil)
+	c.Check(err, qt.IsNil)
+	cache.InvalidateClouds()
+}
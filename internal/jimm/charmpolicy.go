@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/juju/charm/v12"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/charmhub"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// Charmhub provides information about the charms published in Charmhub.
+type Charmhub interface {
+	// CharmInfo returns the information published about the named
+	// charm.
+	CharmInfo(ctx context.Context, name string) (*charmhub.CharmInfo, error)
+}
+
+// CharmPolicy holds the policy applied to the charms deployed through
+// JIMM.
+type CharmPolicy struct {
+	// BlockUnverifiedPublishers rejects requests to deploy, or refresh
+	// to, Charmhub charms whose publisher has not been verified by
+	// Charmhub.
+	BlockUnverifiedPublishers bool
+
+	// AllowedPublishers holds the Charmhub usernames of publishers
+	// whose charms may be deployed whether or not they are verified.
+	AllowedPublishers []string
+}
+
+// CheckCharmPolicy returns an error with the code CodeForbidden if the
+// given facade method would deploy, or refresh an application to, a
+// charm that is not allowed by the charm policy. Only charms from
+// Charmhub are checked.
+func (j *JIMM) CheckCharmPolicy(ctx context.Context, facade, method string, params json.RawMessage) error {
+	const op = errors.Op("jimm.CheckCharmPolicy")
+
+	policy := j.charmPolicy()
+	if j.Charmhub == nil || !policy.BlockUnverifiedPublishers {
+		return nil
+	}
+	urls, err := requestedCharmURLs(facade, method, params)
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, err)
+	}
+	for _, s := range urls {
+		u, err := charm.ParseURL(s)
+		if err != nil {
+			return errors.E(op, errors.CodeBadRequest, err)
+		}
+		if !charm.CharmHub.Matches(u.Schema) {
+			continue
+		}
+		info, err := j.Charmhub.CharmInfo(ctx, u.Name)
+		if err != nil {
+			return errors.E(op, err, fmt.Sprintf("cannot check charm %q: %s", u.Name, err))
+		}
+		if info.Publisher.Verified() || slices.Contains(policy.AllowedPublishers, info.Publisher.Username) {
+			continue
+		}
+		return errors.E(op, errors.CodeForbidden, fmt.Sprintf("%s.%s not allowed, charm %q is published by unverified publisher %q", facade, method, u.Name, info.Publisher.Username))
+	}
+	return nil
+}
+
+// requestedCharmURLs returns the charms that the given facade method
+// would add to a model.
+func requestedCharmURLs(facade, method string, params json.RawMessage) ([]string, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	var urls []string
+	switch facade + "." + method {
+	case "Application.Deploy":
+		var args jujuparams.ApplicationsDeploy
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		for _, a := range args.Applications {
+			urls = append(urls, a.CharmURL)
+		}
+	case "Application.DeployFromRepository":
+		var args jujuparams.DeployFromRepositoryArgs
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		for _, a := range args.Args {
+			urls = append(urls, a.CharmName)
+		}
+	case "Application.SetCharm":
+		var args jujuparams.ApplicationSetCharm
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		urls = append(urls, args.CharmURL)
+	case "Charms.AddCharm":
+		var args jujuparams.AddCharmWithOrigin
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		urls = append(urls, args.URL)
+	}
+	return urls, nil
+}
+
+// ListOutdatedCharms returns the applications using a Charmhub charm with
+// an older revision than the latest released to Charmhub. Only JIMM
+// administrators may list outdated charms.
+func (j *JIMM) ListOutdatedCharms(ctx context.Context, user *openfga.User) ([]apiparams.OutdatedCharm, error) {
+	const op = errors.Op("jimm.ListOutdatedCharms")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if j.Charmhub == nil {
+		return nil, errors.E(op, errors.CodeNotSupported, "charmhub not configured")
+	}
+	acs, err := j.Database.ListApplicationCharms(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	infos := make(map[string]*charmhub.CharmInfo)
+	outdated := []apiparams.OutdatedCharm{}
+	for _, ac := range acs {
+		u := ac.Charm()
+		if u == nil || !charm.CharmHub.Matches(u.Schema) {
+			continue
+		}
+		info, ok := infos[u.Name]
+		if !ok {
+			info, err = j.Charmhub.CharmInfo(ctx, u.Name)
+			if err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+				return nil, errors.E(op, err)
+			}
+			if err != nil {
+				zapctx.Warn(ctx, "charm not found in charmhub", zap.String("charm", u.Name))
+			}
+			infos[u.Name] = info
+		}
+		if info == nil || u.Revision >= info.LatestRevision {
+			continue
+		}
+		outdated = append(outdated, apiparams.OutdatedCharm{
+			Controller:     ac.Model.Controller.Name,
+			ModelUUID:      ac.Model.UUID.String,
+			Model:          ac.Model.Name,
+			Application:    ac.ApplicationName,
+			Charm:          u.Name,
+			Revision:       u.Revision,
+			LatestRevision: info.LatestRevision,
+		})
+	}
+	return outdated, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/charmhub"
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// testCharmhub is a jimm.Charmhub holding the information about a fixed
+// set of charms.
+type testCharmhub map[string]*charmhub.CharmInfo
+
+func (ch testCharmhub) CharmInfo(_ context.Context, name string) (*charmhub.CharmInfo, error) {
+	if info, ok := ch[name]; ok {
+		return info, nil
+	}
+	return nil, errors.E(errors.CodeNotFound, "charm not found")
+}
+
+var testCharms = testCharmhub{
+	"postgresql": {
+		Name:           "postgresql",
+		Publisher:      charmhub.Publisher{Username: "data-platform", Validation: "verified"},
+		LatestRevision: 363,
+	},
+	"homebrew": {
+		Name:           "homebrew",
+		Publisher:      charmhub.Publisher{Username: "someone", Validation: "unproven"},
+		LatestRevision: 5,
+	},
+	"trusted": {
+		Name:           "trusted",
+		Publisher:      charmhub.Publisher{Username: "partner", Validation: "unproven"},
+		LatestRevision: 1,
+	},
+}
+
+func TestCheckCharmPolicy(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := &jimm.JIMM{
+		Charmhub: testCharms,
+		CharmPolicy: jimm.CharmPolicy{
+			BlockUnverifiedPublishers: true,
+			AllowedPublishers:         []string{"partner"},
+		},
+	}
+
+	tests := []struct {
+		about       string
+		facade      string
+		method      string
+		params      string
+		expectError string
+	}{{
+		about:  "verified publisher",
+		facade: "Application",
+		method: "Deploy",
+		params: `{"applications":[{"charm-url":"ch:amd64/jammy/postgresql-363"}]}`,
+	}, {
+		about:       "unverified publisher",
+		facade:      "Application",
+		method:      "Deploy",
+		params:      `{"applications":[{"charm-url":"ch:amd64/jammy/postgresql-363"},{"charm-url":"ch:amd64/jammy/homebrew-5"}]}`,
+		expectError: `Application.Deploy not allowed, charm "homebrew" is published by unverified publisher "someone"`,
+	}, {
+		about:  "allowed publisher",
+		facade: "Charms",
+		method: "AddCharm",
+		params: `{"url":"ch:amd64/jammy/trusted-1"}`,
+	}, {
+		about:       "refresh to unverified publisher",
+		facade:      "Application",
+		method:      "SetCharm",
+		params:      `{"application":"app","charm-url":"ch:amd64/jammy/homebrew-5"}`,
+		expectError: `Application.SetCharm not allowed, charm "homebrew" is published by unverified publisher "someone"`,
+	}, {
+		about:       "deploy from repository",
+		facade:      "Application",
+		method:      "DeployFromRepository",
+		params:      `{"Args":[{"CharmName":"homebrew"}]}`,
+		expectError: `Application.DeployFromRepository not allowed, charm "homebrew" is published by unverified publisher "someone"`,
+	}, {
+		about:  "local charm",
+		facade: "Application",
+		method: "Deploy",
+		params: `{"applications":[{"charm-url":"local:jammy/homebrew-0"}]}`,
+	}, {
+		about:       "unknown charm",
+		facade:      "Charms",
+		method:      "AddCharm",
+		params:      `{"url":"ch:amd64/jammy/unknown-1"}`,
+		expectError: `cannot check charm "unknown": charm not found`,
+	}, {
+		about:  "other method",
+		facade: "Application",
+		method: "SetConfigs",
+		params: `{"Args":[]}`,
+	}}
+	for _, test := range tests {
+		c.Run(test.about, func(c *qt.C) {
+			err := j.CheckCharmPolicy(ctx, test.facade, test.method, json.RawMessage(test.params))
+			if test.expectError == "" {
+				c.Check(err, qt.IsNil)
+				return
+			}
+			c.Check(err, qt.ErrorMatches, test.expectError)
+		})
+	}
+
+	// Without a policy charms are not checked.
+	j.CharmPolicy = jimm.CharmPolicy{}
+	err := j.CheckCharmPolicy(ctx, "Application", "Deploy", json.RawMessage(`{"applications":[{"charm-url":"ch:amd64/jammy/homebrew-5"}]}`))
+	c.Check(err, qt.IsNil)
+}
+
+func TestListOutdatedCharms(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelTokenTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+
+	_, err = j.ListOutdatedCharms(ctx, admin)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotSupported)
+
+	j.Charmhub = testCharms
+	_, err = j.ListOutdatedCharms(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	m := dbmodel.Model{}
+	m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	for app, url := range map[string]string{
+		"db":      "ch:amd64/jammy/postgresql-345",
+		"db2":     "ch:amd64/jammy/postgresql-363",
+		"local":   "local:jammy/postgresql-0",
+		"unknown": "ch:amd64/jammy/unknown-1",
+	} {
+		err := j.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+			ModelID:         m.ID,
+			ApplicationName: app,
+			CharmURL:        url,
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	charms, err := j.ListOutdatedCharms(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(charms, qt.DeepEquals, []apiparams.OutdatedCharm{{
+		Controller:     "controller-1",
+		ModelUUID:      "00000002-0000-0000-0000-000000000001",
+		Model:          "model-1",
+		Application:    "db",
+		Charm:          "postgresql",
+		Revision:       345,
+		LatestRevision: 363,
+	}})
+}
@@ -38,7 +38,7 @@ func (j *JIMM) GetCloud(ctx context.Context, user *openfga.User, tag names.Cloud
 	var cl dbmodel.Cloud
 	cl.SetTag(tag)
 
-	if err := j.Database.GetCloud(ctx, &cl); err != nil {
+	if err := j.getCloud(ctx, &cl); err != nil {
 		return cl, errors.E(op, err)
 	}
 
@@ -68,7 +68,7 @@ func (j *JIMM) GetCloud(ctx context.Context, user *openfga.User, tag names.Cloud
 func (j *JIMM) ForEachUserCloud(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error {
 	const op = errors.Op("jimm.ForEachUserCloud")
 
-	clouds, err := j.Database.GetClouds(ctx)
+	clouds, err := j.getClouds(ctx)
 	if err != nil {
 		return errors.E(op, err, "cannot load clouds")
 	}
@@ -99,7 +99,7 @@ func (j *JIMM) ForEachCloud(ctx context.Context, user *openfga.User, f func(*dbm
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
-	clds, err := j.Database.GetClouds(ctx)
+	clds, err := j.getClouds(ctx)
 	if err != nil {
 		return errors.E(op, "cannot load clouds", err)
 	}
@@ -146,6 +146,7 @@ var DefaultReservedCloudNames = []string{
 // creating the cloud then that error code will be preserved.
 func (j *JIMM) AddCloudToController(ctx context.Context, user *openfga.User, controllerName string, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error {
 	const op = errors.Op("jimm.AddCloudToController")
+	defer j.Cache.InvalidateClouds()
 
 	controller, err := j.getControllerByName(ctx, controllerName)
 	if err != nil {
@@ -189,6 +190,7 @@ func (j *JIMM) AddCloudToController(ctx context.Context, user *openfga.User, con
 // will be preserved.
 func (j *JIMM) AddHostedCloud(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error {
 	const op = errors.Op("jimm.AddHostedCloud")
+	defer j.Cache.InvalidateClouds()
 
 	// NOTE (alesstimec) The default JIMM access right for every user is
 	// "login". Previously the code checked:
@@ -528,6 +530,7 @@ func (j *JIMM) RevokeCloudAccess(ctx context.Context, user *openfga.User, ct nam
 // returns an error the error code is not masked.
 func (j *JIMM) RemoveCloud(ctx context.Context, user *openfga.User, ct names.CloudTag) error {
 	const op = errors.Op("jimm.RemoveCloud")
+	defer j.Cache.InvalidateClouds()
 
 	err := j.doCloudAdmin(ctx, user, ct, func(c *dbmodel.Cloud, api API) error {
 		// Note: JIMM doesn't attempt to determine if the cloud is
@@ -560,6 +563,7 @@ func (j *JIMM) RemoveCloud(ctx context.Context, user *openfga.User, ct names.Clo
 // an error with the code CodeNotFound is returned.
 func (j *JIMM) UpdateCloud(ctx context.Context, user *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error {
 	const op = errors.Op("jimm.UpdateCloud")
+	defer j.Cache.InvalidateClouds()
 
 	var c dbmodel.Cloud
 	c.SetTag(ct)
@@ -633,6 +637,7 @@ func (j *JIMM) UpdateCloud(ctx context.Context, user *openfga.User, ct names.Clo
 // If the RemoveClouds API call returns an error the error code is not masked.
 func (j *JIMM) RemoveCloudFromController(ctx context.Context, user *openfga.User, controllerName string, ct names.CloudTag) error {
 	const op = errors.Op("jimm.RemoveCloudFromController")
+	defer j.Cache.InvalidateClouds()
 
 	var cloud dbmodel.Cloud
 	cloud.SetTag(ct)
@@ -7,7 +7,9 @@ import (
 	"database/sql"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
@@ -17,7 +19,11 @@ import (
 	"github.com/canonical/jimm/v3/internal/cloudcred"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
 // GetCloudCredential retrieves the given credential from the database. The
@@ -26,26 +32,156 @@ import (
 // identified by the given tag cannot be found then an errror with a code
 // of CodeNotFound will be returned. If the given user is not a controller
 // superuser or the owner of the credentials then an error with a code of
-// CodeUnauthorized will be returned.
+// CodeUnauthorized will be returned. If a fresh read is requested in the
+// context, see ContextWithReadConsistency, the validity of the credential
+// is read from a controller hosting a model that uses it.
 func (j *JIMM) GetCloudCredential(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag) (*dbmodel.CloudCredential, error) {
 	const op = errors.Op("jimm.GetCloudCredential")
 
 	if !user.JimmAdmin && user.Name != tag.Owner().Id() {
 		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
+	consistency, err := readConsistency(ctx, apiparams.ReadConsistencyCached)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
 
 	var credential dbmodel.CloudCredential
 	credential.SetTag(tag)
 
-	err := j.Database.GetCloudCredential(ctx, &credential)
+	err = j.Database.GetCloudCredential(ctx, &credential)
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
 	credential.Attributes = nil
 
+	if consistency == apiparams.ReadConsistencyFresh {
+		if err := j.refreshCloudCredentialValidity(ctx, &credential); err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
 	return &credential, nil
 }
 
+// GrantCloudCredentialAccess allows the given entity to create models
+// using the cloud credential. The entity is either a user tag or a group
+// tag of the form group-<name>, in which case all members of the group
+// are allowed. Entities granted access cannot read the credential's
+// attributes. Only the owner of the credential, or a JIMM administrator,
+// may grant access. If the credential or group cannot be found an error
+// with a code of CodeNotFound is returned.
+func (j *JIMM) GrantCloudCredentialAccess(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error {
+	const op = errors.Op("jimm.GrantCloudCredentialAccess")
+
+	grantee, err := j.cloudCredentialGrantee(ctx, user, tag, entity)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.OpenFGAClient.SetCloudCredentialAccess(ctx, tag, grantee); err != nil {
+		return errors.E(op, err, "failed to set cloud credential access")
+	}
+	return nil
+}
+
+// RevokeCloudCredentialAccess removes the given entity's ability to
+// create models using the cloud credential, see
+// GrantCloudCredentialAccess. Models already created using the credential
+// are unaffected.
+func (j *JIMM) RevokeCloudCredentialAccess(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error {
+	const op = errors.Op("jimm.RevokeCloudCredentialAccess")
+
+	grantee, err := j.cloudCredentialGrantee(ctx, user, tag, entity)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.OpenFGAClient.UnsetCloudCredentialAccess(ctx, tag, grantee); err != nil {
+		return errors.E(op, err, "failed to unset cloud credential access")
+	}
+	return nil
+}
+
+// cloudCredentialGrantee checks that the user may share the credential
+// and returns the OpenFGA tag of the given user or group entity.
+func (j *JIMM) cloudCredentialGrantee(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) (*openfga.Tag, error) {
+	if !user.JimmAdmin && user.Name != tag.Owner().Id() {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var credential dbmodel.CloudCredential
+	credential.SetTag(tag)
+	if err := j.Database.GetCloudCredential(ctx, &credential); err != nil {
+		return nil, err
+	}
+
+	if groupName, ok := strings.CutPrefix(entity, jimmnames.GroupTagKind+"-"); ok {
+		group, err := j.getModelAccessGroup(ctx, groupName)
+		if err != nil {
+			return nil, err
+		}
+		return ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation), nil
+	}
+	ut, err := names.ParseUserTag(entity)
+	if err != nil {
+		return nil, errors.E(errors.CodeBadRequest, err)
+	}
+	return ofganames.ConvertTag(ut), nil
+}
+
+// checkCloudCredentialUse checks that a model owned by owner may be
+// created, by the given user, using the given cloud credential. This is
+// the case if the owner owns the credential or has been granted access
+// to it, or if the user is a JIMM administrator.
+func (j *JIMM) checkCloudCredentialUse(ctx context.Context, user *openfga.User, owner *dbmodel.Identity, tag names.CloudCredentialTag) error {
+	if user.JimmAdmin || owner.Name == tag.Owner().Id() {
+		return nil
+	}
+	allowed, err := openfga.NewUser(owner, j.OpenFGAClient).IsAllowedAddModelWithCredential(ctx, tag)
+	if err != nil {
+		return errors.E("permission check failed", err)
+	}
+	if !allowed {
+		return errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	return nil
+}
+
+// SetDefaultCloudCredential sets the credential used for the user's
+// models on the given cloud when a model is created without specifying a
+// credential and the user has more than one credential for the cloud.
+// The credential must belong to the user. If credentialTag is the zero
+// value the user's default for the cloud is removed.
+func (j *JIMM) SetDefaultCloudCredential(ctx context.Context, user *openfga.User, cloudTag names.CloudTag, credentialTag names.CloudCredentialTag) error {
+	const op = errors.Op("jimm.SetDefaultCloudCredential")
+
+	def := dbmodel.DefaultCloudCredential{
+		IdentityName: user.Name,
+		CloudName:    cloudTag.Id(),
+	}
+	if credentialTag == (names.CloudCredentialTag{}) {
+		if err := j.Database.DeleteDefaultCloudCredential(ctx, &def); err != nil {
+			return errors.E(op, err)
+		}
+		return nil
+	}
+	if credentialTag.Cloud().Id() != cloudTag.Id() {
+		return errors.E(op, errors.CodeBadRequest, "cloud credential cloud mismatch")
+	}
+	if credentialTag.Owner().Id() != user.Name {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var credential dbmodel.CloudCredential
+	credential.SetTag(credentialTag)
+	if err := j.Database.GetCloudCredential(ctx, &credential); err != nil {
+		return errors.E(op, err)
+	}
+	def.CloudCredentialID = credential.ID
+	if err := j.Database.SetDefaultCloudCredential(ctx, &def); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
 // RevokeCloudCredential checks that the credential with the given path
 // can be revoked  and revokes the credential.
 func (j *JIMM) RevokeCloudCredential(ctx context.Context, user *dbmodel.Identity, tag names.CloudCredentialTag, force bool) error {
@@ -81,12 +217,34 @@ func (j *JIMM) RevokeCloudCredential(ctx context.Context, user *dbmodel.Identity
 		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("cloud credential still used by %d model(s)", len(models)))
 	}
 
-	cloud := dbmodel.Cloud{
-		Name: credential.CloudName,
+	if err := j.revokeCredentialOnControllers(ctx, credential.CloudName, tag); err != nil {
+		return errors.E(op, err)
 	}
-	if err = j.Database.GetCloud(ctx, &cloud); err != nil {
+
+	err = j.Database.DeleteCloudCredential(ctx, &credential)
+	if err != nil {
+		return errors.E(op, err, "failed to revoke credential in local database")
+	}
+
+	// Remove access for anyone the credential was shared with.
+	if err := j.OpenFGAClient.RemoveCloudCredential(ctx, tag); err != nil {
 		return errors.E(op, err)
 	}
+	return nil
+}
+
+// revokeCredentialOnControllers revokes the given credential on every
+// controller hosting a region of the named cloud. Controllers that do
+// not have the credential are ignored. The revocation, and each
+// controller's confirmation that its copy was removed, are recorded
+// against the credential's propagations.
+func (j *JIMM) revokeCredentialOnControllers(ctx context.Context, cloudName string, tag names.CloudCredentialTag) error {
+	cloud := dbmodel.Cloud{
+		Name: cloudName,
+	}
+	if err := j.getCloud(ctx, &cloud); err != nil {
+		return err
+	}
 
 	var controllers []dbmodel.Controller
 	seen := make(map[uint]bool)
@@ -100,23 +258,22 @@ func (j *JIMM) RevokeCloudCredential(ctx context.Context, user *dbmodel.Identity
 		}
 	}
 
-	err = j.forEachController(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
+	if err := j.Database.RevokeCredentialPropagations(ctx, tag.Id(), time.Now().UTC()); err != nil {
+		zapctx.Error(ctx, "cannot record credential revocation", zap.String("credential", tag.Id()), zap.Error(err))
+	}
+	return j.forEachController(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
 		err := api.RevokeCredential(ctx, tag)
 		if errors.ErrorCode(err) == errors.CodeNotFound {
 			err = nil
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		if err := j.Database.ConfirmCredentialRemoval(ctx, tag.Id(), ctl.Name, time.Now().UTC()); err != nil {
+			zapctx.Error(ctx, "cannot record credential removal", zap.String("credential", tag.Id()), zap.String("controller", ctl.Name), zap.Error(err))
+		}
+		return nil
 	})
-
-	if err != nil {
-		return errors.E(op, err)
-	}
-
-	err = j.Database.DeleteCloudCredential(ctx, &credential)
-	if err != nil {
-		return errors.E(op, err, "failed to revoke credential in local database")
-	}
-	return nil
 }
 
 // UpdateCloudCredentialArgs holds arguments for the cloud credential update
@@ -128,8 +285,9 @@ type UpdateCloudCredentialArgs struct {
 }
 
 // UpdateCloudCredential checks that the credential can be updated
-// and updates it in the local database and all controllers
-// to which it is deployed.
+// and updates it in the local database and the controllers to which it
+// is deployed. Which controllers are updated depends on the cloud's
+// CredentialPropagationPolicy.
 func (j *JIMM) UpdateCloudCredential(ctx context.Context, user *openfga.User, args UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error) {
 	const op = errors.Op("jimm.UpdateCloudCredential")
 
@@ -158,7 +316,7 @@ func (j *JIMM) UpdateCloudCredential(ctx context.Context, user *openfga.User, ar
 	// Confirm the cloud exists.
 	var cloud dbmodel.Cloud
 	cloud.SetTag(names.NewCloudTag(credential.CloudName))
-	if err = j.Database.GetCloud(ctx, &cloud); err != nil {
+	if err = j.getCloud(ctx, &cloud); err != nil {
 		return result, errors.E(op, err)
 	}
 
@@ -180,15 +338,15 @@ func (j *JIMM) UpdateCloudCredential(ctx context.Context, user *openfga.User, ar
 	credential.Attributes = args.Credential.Attributes
 
 	if !args.SkipCheck {
-		err := j.forEachController(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
+		failures := j.credentialFanOut(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
 			models, err := j.updateControllerCloudCredential(ctx, &credential, api.CheckCredentialModels)
 			resultMu.Lock()
 			defer resultMu.Unlock()
 			result = append(result, models...)
 			return err
 		})
-		if err != nil {
-			return result, errors.E(op, err)
+		if len(failures) > 0 {
+			return result, errors.E(op, fmt.Sprintf("cannot check credential: %s", failures))
 		}
 	}
 	var modelsErr bool
@@ -208,11 +366,20 @@ func (j *JIMM) UpdateCloudCredential(ctx context.Context, user *openfga.User, ar
 		return result, errors.E(op, err)
 	}
 
-	err = j.forEachController(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
-		models, err := j.updateControllerCloudCredential(ctx, &credential, api.UpdateCredential)
+	if j.credentialPropagationPolicy(cloud.Name) == CredentialPropagationEager {
+		controllers, err = j.propagatedControllers(ctx, &credential, controllers)
 		if err != nil {
+			return result, errors.E(op, err)
+		}
+	}
+	failures := j.credentialFanOut(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
+		models, err := j.propagateCloudCredential(ctx, &credential, ctl, api)
+		j.recordCredentialUpdate(ctx, &credential, ctl, err)
+		if err != nil {
+			j.queueCredentialUpdateRetry(ctx, &credential, ctl, err)
 			return err
 		}
+		j.clearCredentialUpdateRetry(ctx, &credential, ctl)
 		if args.SkipCheck {
 			resultMu.Lock()
 			defer resultMu.Unlock()
@@ -220,8 +387,8 @@ func (j *JIMM) UpdateCloudCredential(ctx context.Context, user *openfga.User, ar
 		}
 		return nil
 	})
-	if err != nil {
-		return result, errors.E(op, err)
+	if len(failures) > 0 {
+		return result, errors.E(op, fmt.Sprintf("credential stored but not updated on every controller, failed updates will be retried: %s", failures))
 	}
 	return result, nil
 }
@@ -285,12 +452,58 @@ func (j *JIMM) updateControllerCloudCredential(
 	return models, nil
 }
 
+// credentialFailureThreshold is the number of consecutive failures to
+// update a credential on a controller after which a notification is sent.
+const credentialFailureThreshold = 3
+
+// credentialFailures counts consecutive failures to update credentials on
+// controllers.
+type credentialFailures struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// record records the result of updating the credential on the controller
+// identified by the given key, returning the number of consecutive
+// failures.
+func (f *credentialFailures) record(key string, failed bool) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !failed {
+		delete(f.counts, key)
+		return 0
+	}
+	if f.counts == nil {
+		f.counts = make(map[string]int)
+	}
+	f.counts[key]++
+	return f.counts[key]
+}
+
+// recordCredentialUpdate records the result of updating the given
+// credential on the given controller. A notification is sent when the
+// update has failed credentialFailureThreshold times in a row.
+func (j *JIMM) recordCredentialUpdate(ctx context.Context, cred *dbmodel.CloudCredential, ctl *dbmodel.Controller, err error) {
+	n := j.credentialFailures.record(cred.Tag().String()+"/"+ctl.Name, err != nil)
+	if n != credentialFailureThreshold {
+		return
+	}
+	j.Notifier.Notify(ctx, notify.Event{
+		Kind:       notify.CredentialUpdateFailed,
+		Controller: ctl.Name,
+		Credential: cred.Tag().String(),
+		Message:    fmt.Sprintf("credential %s failed to update on controller %s %d times: %s", cred.Tag().Id(), ctl.Name, n, err),
+	})
+}
+
 // ForEachUserCloudCredential iterates through every credential owned by
 // the given user and for the given cloud (if specified). The given
 // function is called for each credential found. The credential used when
 // calling the function will not contain any attributes,
 // GetCloudCredentialAttributes should be used to retrive the credential
-// attributes if needed. The given function should not update the database.
+// attributes if needed. If a fresh read is requested in the context the
+// validity of each credential is read from a controller hosting a model
+// that uses it. The given function should not update the database.
 func (j *JIMM) ForEachUserCloudCredential(ctx context.Context, u *dbmodel.Identity, ct names.CloudTag, f func(cred *dbmodel.CloudCredential) error) error {
 	const op = errors.Op("jimm.ForEachUserCloudCredential")
 
@@ -299,10 +512,20 @@ func (j *JIMM) ForEachUserCloudCredential(ctx context.Context, u *dbmodel.Identi
 		cloud = ct.Id()
 	}
 
+	consistency, err := readConsistency(ctx, apiparams.ReadConsistencyCached)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
 	errStop := errors.E("stop")
 	var iterErr error
-	err := j.Database.ForEachCloudCredential(ctx, u.Name, cloud, func(cred *dbmodel.CloudCredential) error {
+	err = j.Database.ForEachCloudCredential(ctx, u.Name, cloud, func(cred *dbmodel.CloudCredential) error {
 		cred.Attributes = nil
+		if consistency == apiparams.ReadConsistencyFresh {
+			if err := j.refreshCloudCredentialValidity(ctx, cred); err != nil {
+				return err
+			}
+		}
 		iterErr = f(cred)
 		if iterErr != nil {
 			return errStop
@@ -241,7 +241,7 @@ func TestUpdateCloudCredential(t *testing.T) {
 					AuthType: "test-auth-type",
 				},
 			}
-			return u, arg, dbmodel.CloudCredential{}, "test error"
+			return u, arg, dbmodel.CloudCredential{}, "credential stored but not updated on every controller, failed updates will be retried: controller test-controller-2: test error"
 		},
 	}, {
 		about:                  "check credential error returned by controller",
@@ -327,7 +327,7 @@ func TestUpdateCloudCredential(t *testing.T) {
 					AuthType: "test-auth-type",
 				},
 			}
-			return u, arg, dbmodel.CloudCredential{}, "test error"
+			return u, arg, dbmodel.CloudCredential{}, "cannot check credential: controller test-controller-2: test error"
 		},
 	}, {
 		about:     "user is controller superuser",
@@ -1802,3 +1802,77 @@ func (s testCloudCredentialAttributeStore) GetOAuthSecret(ctx context.Context) (
 func (s testCloudCredentialAttributeStore) PutOAuthSecret(ctx context.Context, raw []byte) error {
 	return errors.E(errors.CodeNotImplemented)
 }
+
+func TestCloudCredentialAccess(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, grantModelAccessTestEnv)
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	group, err := j.Database.AddGroup(ctx, "test-group")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("dave@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	tag := names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1")
+	canUse := func(username string) bool {
+		allowed, err := openfga.NewUser(&dbmodel.Identity{Name: username}, client).IsAllowedAddModelWithCredential(ctx, tag)
+		c.Assert(err, qt.IsNil)
+		return allowed
+	}
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+
+	err = j.GrantCloudCredentialAccess(ctx, charlie, tag, "user-bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.GrantCloudCredentialAccess(ctx, alice, names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-2"), "user-bob@canonical.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.GrantCloudCredentialAccess(ctx, alice, tag, "group-no-such-group")
+	c.Check(err, qt.ErrorMatches, "group no-such-group not found")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.GrantCloudCredentialAccess(ctx, alice, tag, "bob")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.GrantCloudCredentialAccess(ctx, alice, tag, "user-bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(canUse("bob@canonical.com"), qt.IsTrue)
+	c.Check(canUse("charlie@canonical.com"), qt.IsFalse)
+
+	// Granting the same access again is not an error.
+	err = j.GrantCloudCredentialAccess(ctx, alice, tag, "user-bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+
+	err = j.GrantCloudCredentialAccess(ctx, alice, tag, "group-test-group")
+	c.Assert(err, qt.IsNil)
+	c.Check(canUse("dave@canonical.com"), qt.IsTrue)
+
+	err = j.RevokeCloudCredentialAccess(ctx, alice, tag, "user-bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(canUse("bob@canonical.com"), qt.IsFalse)
+	c.Check(canUse("dave@canonical.com"), qt.IsTrue)
+}
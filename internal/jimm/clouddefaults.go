@@ -39,7 +39,7 @@ func (j *JIMM) SetModelDefaults(ctx context.Context, user *dbmodel.Identity, clo
 	cloud := dbmodel.Cloud{
 		Name: cloudTag.Id(),
 	}
-	err := j.Database.GetCloud(ctx, &cloud)
+	err := j.getCloud(ctx, &cloud)
 	if err != nil {
 		return errors.E(op, err)
 	}
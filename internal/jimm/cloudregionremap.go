@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// RemapCloudRegion remaps a region of a cloud in JIMM's records, for
+// example after the cloud provider renamed or split the region. Every
+// controller serving the region must already know the new region. If no
+// models are given the whole region is remapped and its old name is kept
+// as an alias, so that requests using the old name keep working.
+// Otherwise only the given models are moved to the new region. Only JIMM
+// administrators can remap cloud regions.
+func (j *JIMM) RemapCloudRegion(ctx context.Context, user *openfga.User, req apiparams.RemapCloudRegionRequest) (apiparams.RemapCloudRegionResponse, error) {
+	const op = errors.Op("jimm.RemapCloudRegion")
+
+	if !user.JimmAdmin {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if req.NewRegion == "" || req.NewRegion == req.Region {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, errors.CodeBadRequest, "a different region to remap to must be specified")
+	}
+	for _, uuid := range req.Models {
+		if !names.IsValidModel(uuid) {
+			return apiparams.RemapCloudRegionResponse{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model UUID %q", uuid))
+		}
+	}
+
+	cloud := dbmodel.Cloud{Name: req.Cloud}
+	if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, err)
+	}
+	old := cloud.Region(req.Region)
+	if old.ID == 0 {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, errors.CodeNotFound, fmt.Sprintf("cloud region %s/%s not found", req.Cloud, req.Region))
+	}
+	if len(old.Controllers) == 0 {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("no controllers serve cloud region %s/%s", req.Cloud, req.Region))
+	}
+	controllers := make([]dbmodel.Controller, len(old.Controllers))
+	for i, p := range old.Controllers {
+		controllers[i] = p.Controller
+	}
+
+	// The new region is validated against the controllers, the
+	// records of which must be consistent with JIMM's.
+	var mu sync.Mutex
+	var region jujuparams.CloudRegion
+	err := j.forEachController(ctx, controllers, func(ctl *dbmodel.Controller, api API) error {
+		var cl jujuparams.Cloud
+		if err := api.Cloud(ctx, cloud.ResourceTag(), &cl); err != nil {
+			return err
+		}
+		for _, r := range cl.Regions {
+			if r.Name == req.NewRegion {
+				mu.Lock()
+				defer mu.Unlock()
+				region = r
+				return nil
+			}
+		}
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("controller %s does not have cloud region %s/%s", ctl.Name, req.Cloud, req.NewRegion))
+	})
+	if err != nil {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, err)
+	}
+
+	var to dbmodel.CloudRegion
+	to.FromJujuCloudRegion(region)
+	remapped, err := j.Database.RemapCloudRegion(ctx, cloud.Name, req.Region, &to, req.Models)
+	if err != nil {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, err)
+	}
+	// The cached controllers record the regions hosting them.
+	j.Cache.InvalidateControllers()
+	zapctx.Info(ctx, "cloud region remapped", zap.String("cloud", cloud.Name), zap.String("region", req.Region), zap.String("new-region", to.Name), zap.Int("models", len(remapped)))
+
+	resp := apiparams.RemapCloudRegionResponse{
+		Region: to.Name,
+		Models: remapped,
+	}
+	if len(req.Models) == 0 {
+		resp.Alias = req.Region
+	}
+	return resp, nil
+}
+
+// cloudRegionName returns the current name of the region of the given
+// cloud known by the given name. The old names of regions that have been
+// remapped are aliases of their new regions, so that automation using
+// the old names keeps working. Names that are not aliases are returned
+// unchanged.
+func (j *JIMM) cloudRegionName(ctx context.Context, cloud *dbmodel.Cloud, name string) (string, error) {
+	if name == "" || cloud.Region(name).Name != "" {
+		return name, nil
+	}
+	alias := dbmodel.CloudRegionAlias{
+		CloudName: cloud.Name,
+		Alias:     name,
+	}
+	err := j.Database.GetCloudRegionAlias(ctx, &alias)
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return name, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return alias.RegionName, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const remapCloudRegionTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-cloud-region
+    priority: 1
+models:
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  default-series: warty
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  users:
+  - user: alice@canonical.com
+    access: admin
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+`
+
+func TestRemapCloudRegion(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				Cloud_: func(_ context.Context, tag names.CloudTag, cl *jujuparams.Cloud) error {
+					if tag.Id() != "test-cloud" {
+						return errors.E(errors.CodeNotFound, "cloud not found")
+					}
+					cl.Regions = []jujuparams.CloudRegion{{
+						Name:     "test-cloud-region-2",
+						Endpoint: "https://region-2.example.com",
+					}}
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, remapCloudRegionTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&alice, client)
+
+	req := apiparams.RemapCloudRegionRequest{
+		Cloud:     "test-cloud",
+		Region:    "test-cloud-region",
+		NewRegion: "test-cloud-region-2",
+	}
+	_, err = j.RemapCloudRegion(ctx, user, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	user.JimmAdmin = true
+	_, err = j.RemapCloudRegion(ctx, user, apiparams.RemapCloudRegionRequest{
+		Cloud:     "test-cloud",
+		Region:    "test-cloud-region",
+		NewRegion: "test-cloud-region-3",
+	})
+	c.Check(err, qt.ErrorMatches, `controller controller-1 does not have cloud region test-cloud/test-cloud-region-3`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	resp, err := j.RemapCloudRegion(ctx, user, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.RemapCloudRegionResponse{
+		Region: "test-cloud-region-2",
+		Models: []string{"00000002-0000-0000-0000-000000000001"},
+		Alias:  "test-cloud-region",
+	})
+
+	m := dbmodel.Model{UUID: env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database).UUID}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.CloudRegion.Name, qt.Equals, "test-cloud-region-2")
+	c.Check(m.CloudRegion.Endpoint, qt.Equals, "https://region-2.example.com")
+
+	// The old name of the region still selects the region.
+	cloud := dbmodel.Cloud{Name: "test-cloud"}
+	err = j.Database.GetCloud(ctx, &cloud)
+	c.Assert(err, qt.IsNil)
+	region, err := jimm.CloudRegionName(j, ctx, &cloud, "test-cloud-region")
+	c.Assert(err, qt.IsNil)
+	c.Check(region, qt.Equals, "test-cloud-region-2")
+	region, err = jimm.CloudRegionName(j, ctx, &cloud, "unknown-region")
+	c.Assert(err, qt.IsNil)
+	c.Check(region, qt.Equals, "unknown-region")
+
+	_, err = j.RemapCloudRegion(ctx, user, req)
+	c.Check(err, qt.ErrorMatches, `cloud region test-cloud/test-cloud-region not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
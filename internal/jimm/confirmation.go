@@ -0,0 +1,103 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/auth"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// totpIssuer is the issuer named in the otpauth URIs of enrolled TOTP
+// authenticators.
+const totpIssuer = "JIMM"
+
+// EnrolTOTP generates a new TOTP secret for the given user, with which
+// the user may confirm their identity before sensitive operations. The
+// secret is stored encrypted if the database has an encryption key. If
+// the user already has an authenticator enrolled and replace is false an
+// error with the code CodeAlreadyExists is returned.
+func (j *JIMM) EnrolTOTP(ctx context.Context, user *openfga.User, replace bool) (apiparams.EnrolTOTPResponse, error) {
+	const op = errors.Op("jimm.EnrolTOTP")
+
+	i := dbmodel.Identity{Name: user.Name}
+	if err := j.Database.GetIdentity(ctx, &i); err != nil {
+		return apiparams.EnrolTOTPResponse{}, errors.E(op, err)
+	}
+	if i.TOTPSecret != "" && !replace {
+		return apiparams.EnrolTOTPResponse{}, errors.E(op, errors.CodeAlreadyExists, "TOTP authenticator already enrolled")
+	}
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return apiparams.EnrolTOTPResponse{}, errors.E(op, err)
+	}
+	if err := j.Database.SetIdentityTOTPSecret(ctx, &i, secret); err != nil {
+		return apiparams.EnrolTOTPResponse{}, errors.E(op, err)
+	}
+	return apiparams.EnrolTOTPResponse{
+		Secret: secret,
+		URI:    auth.TOTPURI(totpIssuer, user.Name, secret),
+	}, nil
+}
+
+// ConfirmIdentity confirms the identity of the given user with either a
+// code from their enrolled TOTP authenticator or a session token issued
+// to them. Each TOTP code is accepted once, a code for a time step no
+// later than that of a code already accepted is rejected. It returns the
+// time the user last authenticated, which is now for a TOTP code and the
+// time the session token was issued otherwise.
+func (j *JIMM) ConfirmIdentity(ctx context.Context, user *openfga.User, req apiparams.ConfirmIdentityRequest) (time.Time, error) {
+	const op = errors.Op("jimm.ConfirmIdentity")
+
+	switch {
+	case req.TOTPCode != "" && req.SessionToken != "":
+		return time.Time{}, errors.E(op, errors.CodeBadRequest, "only one of TOTP code and session token may be specified")
+	case req.TOTPCode != "":
+		i := dbmodel.Identity{Name: user.Name}
+		if err := j.Database.GetIdentity(ctx, &i); err != nil {
+			return time.Time{}, errors.E(op, err)
+		}
+		if i.TOTPSecret == "" {
+			return time.Time{}, errors.E(op, errors.CodeBadRequest, "no TOTP authenticator enrolled")
+		}
+		secret, err := j.Database.OpenIdentityTOTPSecret(ctx, &i)
+		if err != nil {
+			return time.Time{}, errors.E(op, err)
+		}
+		now := time.Now()
+		step, ok := auth.MatchTOTP(secret, req.TOTPCode, now)
+		if !ok {
+			return time.Time{}, errors.E(op, errors.CodeUnauthorized, "invalid TOTP code")
+		}
+		ok, err = j.Database.UseIdentityTOTPStep(ctx, &i, step)
+		if err != nil {
+			return time.Time{}, errors.E(op, err)
+		}
+		if !ok {
+			return time.Time{}, errors.E(op, errors.CodeUnauthorized, "TOTP code already used")
+		}
+		return now, nil
+	case req.SessionToken != "":
+		if j.OAuthAuthenticator == nil {
+			return time.Time{}, errors.E(op, errors.CodeNotSupported, "session tokens not supported")
+		}
+		token, err := j.OAuthAuthenticator.VerifySessionToken(req.SessionToken)
+		if err != nil {
+			return time.Time{}, errors.E(op, errors.CodeUnauthorized, err)
+		}
+		if token.Subject() != user.Name {
+			return time.Time{}, errors.E(op, errors.CodeUnauthorized, "session token issued to a different user")
+		}
+		if token.IssuedAt().IsZero() {
+			return time.Time{}, errors.E(op, errors.CodeUnauthorized, "session token has no issue time")
+		}
+		return token.IssuedAt(), nil
+	default:
+		return time.Time{}, errors.E(op, errors.CodeBadRequest, "TOTP code or session token must be specified")
+	}
+}
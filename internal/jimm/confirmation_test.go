@@ -0,0 +1,93 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/base32"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/auth"
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestConfirmIdentity(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	mockAuthenticator := jimmtest.NewMockOAuthAuthenticator(c, nil)
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OAuthAuthenticator: &mockAuthenticator,
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	identity, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.GetIdentity(ctx, identity), qt.IsNil)
+	alice := openfga.NewUser(identity, nil)
+
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{TOTPCode: "123456"})
+	c.Check(err, qt.ErrorMatches, `no TOTP authenticator enrolled`)
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	enrolment, err := j.EnrolTOTP(ctx, alice, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(enrolment.URI, qt.Matches, `otpauth://totp/JIMM:alice@canonical.com\?.*secret=`+enrolment.Secret+`.*`)
+	_, err = j.EnrolTOTP(ctx, alice, false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(enrolment.Secret)
+	c.Assert(err, qt.IsNil)
+	now := time.Now()
+	code := auth.TOTPCode(key, uint64(now.Unix()/30))
+	authTime, err := j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{TOTPCode: code})
+	c.Assert(err, qt.IsNil)
+	c.Check(authTime.Before(now), qt.IsFalse)
+
+	// A code cannot be used twice, nor can a code for an earlier step.
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{TOTPCode: code})
+	c.Check(err, qt.ErrorMatches, `TOTP code already used`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	earlier := auth.TOTPCode(key, uint64(now.Unix()/30)-1)
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{TOTPCode: earlier})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	wrong := auth.TOTPCode(key, uint64(now.Unix()/30)+10)
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{TOTPCode: wrong})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// Replacing the authenticator invalidates codes from the old one.
+	enrolment2, err := j.EnrolTOTP(ctx, alice, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(enrolment2.Secret, qt.Not(qt.Equals), enrolment.Secret)
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{TOTPCode: code})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	token, err := mockAuthenticator.MintSessionToken("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	authTime, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{SessionToken: token})
+	c.Assert(err, qt.IsNil)
+	c.Check(time.Since(authTime) < time.Minute, qt.IsTrue)
+
+	token, err = mockAuthenticator.MintSessionToken("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.ConfirmIdentity(ctx, alice, apiparams.ConfirmIdentityRequest{SessionToken: token})
+	c.Check(err, qt.ErrorMatches, `session token issued to a different user`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
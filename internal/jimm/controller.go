@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/controller/controller"
@@ -203,77 +204,142 @@ func (act *addControllerTransactor) Run(ctx context.Context) error {
 }
 
 // addControllerTx stores the clouds, regions, cloud region priorities and the controller itself in the database determined
-// from the incoming Juju API.Clouds() call.
-func addControllerTx(ctx context.Context, j *JIMM, jujuClouds []dbmodel.Cloud, ctl *dbmodel.Controller) error {
+// from the incoming Juju API.Clouds() call. The controller's credentials are stored last, so that they are
+// only stored by the registration that succeeds in adding the controller.
+func addControllerTx(ctx context.Context, j *JIMM, jujuClouds []dbmodel.Cloud, ctl *dbmodel.Controller, adminIdentityName, adminPassword string) error {
 	return j.Database.Transaction(func(tx *db.Database) error {
-		return newAddControllerTransactor(j, jujuClouds, ctl, tx).Run(ctx)
+		if ctl.UUID != "" && !j.AllowDuplicateControllerUUIDs {
+			// Serialise registrations of the same controller so that
+			// the check below sees any that committed first.
+			if err := tx.LockControllerUUID(ctx, ctl.UUID); err != nil {
+				return err
+			}
+		}
+		if err := j.checkControllerConflict(ctx, tx, ctl); err != nil {
+			return err
+		}
+		if err := newAddControllerTransactor(j, jujuClouds, ctl, tx).Run(ctx); err != nil {
+			return err
+		}
+		// TODO(ale8k): This shouldn't be necessary to check, but tests need updating
+		// to set insecure credential store explicitly.
+		if j.CredentialStore != nil {
+			err := j.CredentialStore.PutControllerCredentials(ctx, ctl.Name, adminIdentityName, adminPassword)
+			if err != nil {
+				return errors.E(err, "failed to store controller credentials")
+			}
+		}
+		return nil
 	})
 }
 
+// checkControllerConflict checks that the given controller does not
+// conflict with a controller already registered in the given database.
+// An error with a code of CodeAlreadyExists is returned if there is a
+// controller with the same name, or if the same controller, identified
+// by its UUID, is registered under a different name.
+func (j *JIMM) checkControllerConflict(ctx context.Context, d *db.Database, ctl *dbmodel.Controller) error {
+	existing := dbmodel.Controller{Name: ctl.Name}
+	err := d.GetController(ctx, &existing)
+	if err == nil {
+		return errors.E(errors.CodeAlreadyExists, fmt.Sprintf("controller %q already exists", ctl.Name))
+	}
+	if errors.ErrorCode(err) != errors.CodeNotFound {
+		return err
+	}
+	if ctl.UUID == "" || j.AllowDuplicateControllerUUIDs {
+		return nil
+	}
+	existing = dbmodel.Controller{UUID: ctl.UUID}
+	err = d.GetController(ctx, &existing)
+	if err == nil {
+		return errors.E(errors.CodeAlreadyExists, fmt.Sprintf("controller %s is already registered as %q", ctl.UUID, existing.Name))
+	}
+	if errors.ErrorCode(err) != errors.CodeNotFound {
+		return err
+	}
+	return nil
+}
+
 // AddController adds the specified controller to JIMM. Only
 // controller-admin level users may add new controllers. If the user adding
 // the controller is not authorized then an error with a code of
 // CodeUnauthorized will be returned. If there already exists a controller
-// with the same name as the controller being added then an error with a
-// code of CodeAlreadyExists will be returned. If the controller cannot be
-// contacted then an error with a code of CodeConnectionFailed will be
-// returned.
+// with the same name as the controller being added, or the same
+// controller (identified by its UUID) is already registered under
+// another name, then an error with a code of CodeAlreadyExists will be
+// returned. Concurrent attempts to add the same controller are
+// serialised, only one of them succeeds and only its credentials are
+// stored. If the controller cannot be contacted then an error with a code
+// of CodeConnectionFailed will be returned. If ControllerServiceUsers is
+// set the given credentials are only used to provision a JIMM service
+// user on the controller, which is used for all subsequent connections.
+// If the service user cannot be provisioned the controller is still
+// added, with ServiceUserPending set.
 func (j *JIMM) AddController(ctx context.Context, user *openfga.User, ctl *dbmodel.Controller) error {
 	const op = errors.Op("jimm.AddController")
+	defer j.Cache.InvalidateControllers()
 
 	if err := j.checkJimmAdmin(user); err != nil {
 		return err
 	}
 
+	// Fail early if the controller is already registered, the check is
+	// repeated when the controller is stored to catch concurrent
+	// registrations.
+	if err := j.checkControllerConflict(ctx, &j.Database, ctl); err != nil {
+		return errors.E(op, err)
+	}
+
 	api, err := j.dialController(ctx, ctl)
 	if err != nil {
 		return errors.E(op, "failed to dial the controller", err)
 	}
 	defer api.Close()
 
-	modelSummary, err := getControllerModelSummary(ctx, api)
+	modelSummary, dbClouds, err := inspectController(ctx, api, ctl)
 	if err != nil {
-		return errors.E(op, err, "failed to get model summary")
-	}
-
-	cloudName, err := getCloudNameFromModelSummary(modelSummary)
-	if err != nil {
-		return errors.E(op, err, "failed to parse the cloud tag")
-	}
-
-	ctl.CloudName = cloudName
-	ctl.CloudRegion = modelSummary.CloudRegion
-	// TODO(mhilton) add the controller model?
-
-	clouds, err := api.Clouds(ctx)
-	if err != nil {
-		return errors.E(op, err, "failed to fetch controller clouds")
-	}
-
-	dbClouds := convertJujuCloudsToDbClouds(clouds)
-
-	// TODO(ale8k): This shouldn't be necessary to check, but tests need updating
-	// to set insecure credential store explicitly.
-	if j.CredentialStore != nil {
-		err := j.CredentialStore.PutControllerCredentials(ctx, ctl.Name, ctl.AdminIdentityName, ctl.AdminPassword)
-		if err != nil {
-			return errors.E(op, err, "failed to store controller credentials")
-		}
+		return errors.E(op, err)
 	}
 
 	// Credential store will always be set either to vault or explicitly insecure,
 	// no need to be persist in db.
+	adminIdentityName, adminPassword := ctl.AdminIdentityName, ctl.AdminPassword
 	ctl.AdminIdentityName = ""
 	ctl.AdminPassword = ""
 
-	if err := addControllerTx(ctx, j, dbClouds, ctl); err != nil {
+	if err := addControllerTx(ctx, j, dbClouds, ctl, adminIdentityName, adminPassword); err != nil {
 		zapctx.Error(ctx, "failed to add controller", zaputil.Error(err))
 		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			// A concurrent registration committed first, report
+			// the precise conflict if it can be determined.
+			if cerr := j.checkControllerConflict(ctx, &j.Database, ctl); cerr != nil {
+				return errors.E(op, cerr)
+			}
 			return errors.E(op, err, fmt.Sprintf("controller %q already exists", ctl.Name))
 		}
 
 		return errors.E(op, err)
 	}
+	if j.ControllerServiceUsers && j.CredentialStore != nil {
+		// The controller is only known to be registered once it has
+		// been stored, so that concurrent registrations cannot reset
+		// each other's service user password. If the service user
+		// cannot be provisioned the admin credentials remain in use,
+		// and the controller is marked as pending, until
+		// ProvisionControllerServiceUser succeeds.
+		if _, err := j.provisionControllerServiceUser(ctx, api, ctl); err != nil {
+			zapctx.Error(ctx, "failed to provision controller service user", zap.String("controller", ctl.Name), zap.Error(err))
+			ctl.ServiceUserPending = true
+			if err := j.Database.UpdateController(ctx, ctl); err != nil {
+				return errors.E(op, err, "failed to record pending controller service user")
+			}
+		}
+	}
+	j.recordControllerModelCredential(ctx, ctl, modelSummary)
+	if err := j.captureBootstrapProfile(ctx, api, ctl); err != nil {
+		zapctx.Error(ctx, "failed to capture controller bootstrap profile", zap.String("controller", ctl.Name), zap.Error(err))
+	}
 
 	for _, cloud := range dbClouds {
 		// If this cloud is the one used by the controller model then
@@ -311,6 +377,90 @@ func (j *JIMM) AddController(ctx context.Context, user *openfga.User, ctl *dbmod
 	return nil
 }
 
+// errDryRun is returned to roll back the transaction in which a dry run
+// of adding a controller is performed.
+var errDryRun = errors.E("dry run")
+
+// DryRunAddController performs the validation and capability detection
+// of AddController for the specified controller without storing
+// anything: the controller is dialed with the given credentials, its
+// cloud and regions are read, it is checked to support the facade
+// versions JIMM requires, and storing it, along with its clouds, is
+// attempted in a transaction that is then rolled back. On success the
+// controller is updated as AddController would store it. The errors
+// returned are those AddController would return, along with an error
+// with a code of CodeNotSupported if the controller does not support
+// the facade versions JIMM requires.
+func (j *JIMM) DryRunAddController(ctx context.Context, user *openfga.User, ctl *dbmodel.Controller) error {
+	const op = errors.Op("jimm.DryRunAddController")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return err
+	}
+	if err := j.checkControllerConflict(ctx, &j.Database, ctl); err != nil {
+		return errors.E(op, err)
+	}
+
+	api, err := j.dialController(ctx, ctl)
+	if err != nil {
+		return errors.E(op, "failed to dial the controller", err)
+	}
+	defer api.Close()
+
+	if err := checkRequiredFacades(api); err != nil {
+		return errors.E(op, errors.CodeNotSupported, err)
+	}
+	_, dbClouds, err := inspectController(ctx, api, ctl)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	ctl.AdminIdentityName = ""
+	ctl.AdminPassword = ""
+
+	err = j.Database.Transaction(func(tx *db.Database) error {
+		if err := j.checkControllerConflict(ctx, tx, ctl); err != nil {
+			return err
+		}
+		if err := newAddControllerTransactor(j, dbClouds, ctl, tx).Run(ctx); err != nil {
+			return err
+		}
+		return errDryRun
+	})
+	if err != errDryRun {
+		return errors.E(op, err)
+	}
+	// Nothing was stored, so the controller has no database identity.
+	ctl.ID = 0
+	ctl.CreatedAt = time.Time{}
+	ctl.UpdatedAt = time.Time{}
+	return nil
+}
+
+// inspectController reads the cloud and region of the controller model,
+// and the clouds known to the controller, from the given controller API.
+// The cloud and region are set on the given controller.
+func inspectController(ctx context.Context, api API, ctl *dbmodel.Controller) (jujuparams.ModelSummary, []dbmodel.Cloud, error) {
+	modelSummary, err := getControllerModelSummary(ctx, api)
+	if err != nil {
+		return modelSummary, nil, errors.E(err, "failed to get model summary")
+	}
+
+	cloudName, err := getCloudNameFromModelSummary(modelSummary)
+	if err != nil {
+		return modelSummary, nil, errors.E(err, "failed to parse the cloud tag")
+	}
+
+	ctl.CloudName = cloudName
+	ctl.CloudRegion = modelSummary.CloudRegion
+	// TODO(mhilton) add the controller model?
+
+	clouds, err := api.Clouds(ctx)
+	if err != nil {
+		return modelSummary, nil, errors.E(err, "failed to fetch controller clouds")
+	}
+	return modelSummary, convertJujuCloudsToDbClouds(clouds), nil
+}
+
 // EarliestControllerVersion returns the earliest agent version
 // that any of the available public controllers is known to be running.
 // If there are no available controllers or none of their versions are
@@ -394,13 +544,26 @@ func (j *JIMM) GetUserControllerAccess(ctx context.Context, user *openfga.User,
 }
 
 // ImportModel imports model with the specified UUID from the controller.
-func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner string) error {
+// The origin records how the model came to be on the controller, it must
+// be one of dbmodel.ModelOriginAdopted, dbmodel.ModelOriginMigrated or
+// dbmodel.ModelOriginRestored. An empty origin is taken to be
+// dbmodel.ModelOriginAdopted.
+func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner, origin string) error {
 	const op = errors.Op("jimm.ImportModel")
+	defer j.Cache.InvalidateModelAccess(modelTag)
 
 	if err := j.checkJimmAdmin(user); err != nil {
 		return err
 	}
 
+	switch origin {
+	case "":
+		origin = dbmodel.ModelOriginAdopted
+	case dbmodel.ModelOriginAdopted, dbmodel.ModelOriginMigrated, dbmodel.ModelOriginRestored:
+	default:
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model origin %q", origin))
+	}
+
 	controller, err := j.getControllerByName(ctx, controllerName)
 	if err != nil {
 		return errors.E(op, err)
@@ -427,6 +590,11 @@ func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerNa
 	}
 	model.ControllerID = controller.ID
 	model.Controller = *controller
+	model.Origin = dbmodel.ModelOrigin{
+		Kind:         origin,
+		IdentityName: user.Name,
+		Time:         sql.NullTime{Time: time.Now(), Valid: true},
+	}
 
 	var ownerTag names.UserTag
 	if newOwner != "" {
@@ -491,7 +659,7 @@ func (j *JIMM) ImportModel(ctx context.Context, user *openfga.User, controllerNa
 	cloud := dbmodel.Cloud{
 		Name: cloudCredential.CloudName,
 	}
-	err = j.Database.GetCloud(ctx, &cloud)
+	err = j.getCloud(ctx, &cloud)
 	if err != nil {
 		zapctx.Error(ctx, "failed to get cloud", zap.String("cloud", cloud.Name))
 		return errors.E(op, err)
@@ -542,11 +710,7 @@ func (j *JIMM) handleModelDeltas(ctx context.Context, controller *dbmodel.Contro
 
 	modelIDf := func(uuid string) *modelState {
 		if uuid == model.UUID.String {
-			return &modelState{
-				id:       model.ID,
-				machines: make(map[string]int64),
-				units:    make(map[string]bool),
-			}
+			return newModelState(model.ID)
 		}
 		return nil
 	}
@@ -611,7 +775,8 @@ func (j *JIMM) GetControllerConfig(ctx context.Context, u *dbmodel.Identity) (*d
 }
 
 // UpdateMigratedModel asserts that the model has been migrated to the
-// specified controller and updates the internal model representation.
+// specified controller, rebinds the model to its cloud credential on that
+// controller and updates the internal model representation.
 func (j *JIMM) UpdateMigratedModel(ctx context.Context, user *openfga.User, modelTag names.ModelTag, targetControllerName string) error {
 	const op = errors.Op("jimm.UpdateMigratedModel")
 
@@ -658,6 +823,11 @@ func (j *JIMM) UpdateMigratedModel(ctx context.Context, user *openfga.User, mode
 		return errors.E(op, err)
 	}
 
+	err = j.rebindModelCredential(ctx, &targetController, api, modelTag, &model)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
 	model.Controller = targetController
 	model.ControllerID = targetController.ID
 	err = j.Database.UpdateModel(ctx, &model)
@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -174,6 +175,7 @@ func TestAddController(t *testing.T) {
 
 	ctl3 := dbmodel.Controller{
 		Name:              "test-controller-2",
+		UUID:              "00000000-0000-0000-0000-0000000000002",
 		AdminIdentityName: "admin",
 		AdminPassword:     "5ecret",
 		PublicAddress:     "example.com:443",
@@ -349,6 +351,7 @@ func TestAddControllerWithVault(t *testing.T) {
 
 	ctl3 := dbmodel.Controller{
 		Name:              "test-controller-2",
+		UUID:              "00000000-0000-0000-0000-0000000000002",
 		AdminIdentityName: "admin",
 		AdminPassword:     "5ecretToo",
 		PublicAddress:     "example.com:443",
@@ -399,6 +402,119 @@ controllers:
   agent-version: 2.1.0
 `
 
+func TestAddControllerConflicts(t *testing.T) {
+	c := qt.New(t)
+
+	api := &jimmtest.API{
+		Clouds_: func(context.Context) (map[names.CloudTag]jujuparams.Cloud, error) {
+			return map[names.CloudTag]jujuparams.Cloud{
+				names.NewCloudTag("aws"): {
+					Type:      "ec2",
+					AuthTypes: []string{"userpass"},
+					Regions: []jujuparams.CloudRegion{{
+						Name: "eu-west-1",
+					}},
+				},
+			}, nil
+		},
+		ControllerModelSummary_: func(_ context.Context, ms *jujuparams.ModelSummary) error {
+			ms.Name = "controller"
+			ms.UUID = "5fddf0ed-83d5-47e8-ae7b-a4b27fc04a9f"
+			ms.CloudTag = "cloud-aws"
+			ms.CloudRegion = "eu-west-1"
+			return nil
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+		CredentialStore: store,
+		OpenFGAClient:   client,
+	}
+
+	ctx := context.Background()
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	alice := openfga.NewUser(u, client)
+	alice.JimmAdmin = true
+
+	newController := func(name, uuid, password string) *dbmodel.Controller {
+		return &dbmodel.Controller{
+			Name:              name,
+			UUID:              uuid,
+			AdminIdentityName: "admin",
+			AdminPassword:     password,
+			PublicAddress:     "example.com:443",
+		}
+	}
+
+	err = j.AddController(ctx, alice, newController("controller-1", "00000000-0000-0000-0000-0000000000001", "5ecret"))
+	c.Assert(err, qt.IsNil)
+
+	// The same name for a different controller.
+	err = j.AddController(ctx, alice, newController("controller-1", "00000000-0000-0000-0000-0000000000002", "wrong"))
+	c.Check(err, qt.ErrorMatches, `controller "controller-1" already exists`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	// The same controller under a different name.
+	err = j.AddController(ctx, alice, newController("controller-2", "00000000-0000-0000-0000-0000000000001", "wrong"))
+	c.Check(err, qt.ErrorMatches, `controller 00000000-0000-0000-0000-0000000000001 is already registered as "controller-1"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	// The failed attempts did not replace the stored credentials.
+	username, password, err := store.GetControllerCredentials(ctx, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(username, qt.Equals, "admin")
+	c.Check(password, qt.Equals, "5ecret")
+	_, _, err = store.GetControllerCredentials(ctx, "controller-2")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Only one of several concurrent registrations of the same
+	// controller succeeds.
+	const n = 5
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("racing-%d", i)
+			errs[i] = j.AddController(ctx, alice, newController(name, "00000000-0000-0000-0000-0000000000003", name))
+		}(i)
+	}
+	wg.Wait()
+	var added []string
+	for i, err := range errs {
+		if err == nil {
+			added = append(added, fmt.Sprintf("racing-%d", i))
+			continue
+		}
+		c.Check(err, qt.ErrorMatches, `controller 00000000-0000-0000-0000-0000000000003 is already registered as "racing-[0-9]"`)
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+	}
+	c.Assert(added, qt.HasLen, 1)
+	ctl := dbmodel.Controller{UUID: "00000000-0000-0000-0000-0000000000003"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.Name, qt.Equals, added[0])
+	_, password, err = store.GetControllerCredentials(ctx, added[0])
+	c.Assert(err, qt.IsNil)
+	c.Check(password, qt.Equals, added[0])
+}
+
 func TestEarliestControllerVersion(t *testing.T) {
 	c := qt.New(t)
 
@@ -937,7 +1053,7 @@ func TestImportModel(t *testing.T) {
 			user := openfga.NewUser(&dbUser, client)
 			user.JimmAdmin = test.jimmAdmin
 
-			err = j.ImportModel(ctx, user, test.controllerName, names.NewModelTag(test.modelUUID), test.newOwner)
+			err = j.ImportModel(ctx, user, test.controllerName, names.NewModelTag(test.modelUUID), test.newOwner, "")
 			if test.expectedError == "" {
 				c.Assert(err, qt.IsNil)
 
@@ -947,6 +1063,9 @@ func TestImportModel(t *testing.T) {
 				err = j.Database.GetModel(ctx, &m1)
 				c.Assert(err, qt.IsNil)
 				c.Assert(m1, jimmtest.DBObjectEquals, test.expectedModel)
+				c.Check(m1.Origin.Kind, qt.Equals, dbmodel.ModelOriginAdopted)
+				c.Check(m1.Origin.IdentityName, qt.Equals, dbUser.Name)
+				c.Check(m1.Origin.Time.Valid, qt.IsTrue)
 				c.Assert(user.GetModelAccess(ctx, names.NewModelTag(test.modelUUID)), qt.Equals, ofganames.AdministratorRelation)
 				controllerPermissionCheck := ofga.Tuple{
 					Object:   ofganames.ConvertTag(names.NewControllerTag(test.expectedModel.Controller.UUID)),
@@ -1203,13 +1322,14 @@ func TestUpdateMigratedModel(t *testing.T) {
 	c := qt.New(t)
 
 	tests := []struct {
-		about            string
-		user             string
-		modelInfo        func(context.Context, *jujuparams.ModelInfo) error
-		model            names.ModelTag
-		targetController string
-		jimmAdmin        bool
-		expectedError    string
+		about                 string
+		user                  string
+		modelInfo             func(context.Context, *jujuparams.ModelInfo) error
+		changeModelCredential func(context.Context, names.ModelTag, names.CloudCredentialTag) error
+		model                 names.ModelTag
+		targetController      string
+		jimmAdmin             bool
+		expectedError         string
 	}{{
 		about:         "add-model user not allowed to update migrated model",
 		user:          "bob@canonical.com",
@@ -1237,6 +1357,19 @@ func TestUpdateMigratedModel(t *testing.T) {
 		},
 		expectedError: "an error",
 		jimmAdmin:     true,
+	}, {
+		about:            "credential rebind fails",
+		user:             "alice@canonical.com",
+		model:            names.NewModelTag("00000002-0000-0000-0000-000000000002"),
+		targetController: "controller-2",
+		modelInfo: func(context.Context, *jujuparams.ModelInfo) error {
+			return nil
+		},
+		changeModelCredential: func(context.Context, names.ModelTag, names.CloudCredentialTag) error {
+			return errors.E("credential not valid")
+		},
+		expectedError: "credential not valid",
+		jimmAdmin:     true,
 	}, {
 		about:            "all ok",
 		user:             "alice@canonical.com",
@@ -1245,6 +1378,12 @@ func TestUpdateMigratedModel(t *testing.T) {
 		modelInfo: func(context.Context, *jujuparams.ModelInfo) error {
 			return nil
 		},
+		changeModelCredential: func(_ context.Context, _ names.ModelTag, ct names.CloudCredentialTag) error {
+			if ct.String() != "cloudcred-test-cloud_alice@canonical.com_test-credential" {
+				return errors.E("unexpected credential " + ct.String())
+			}
+			return nil
+		},
 		jimmAdmin: true,
 	}}
 
@@ -1257,7 +1396,11 @@ func TestUpdateMigratedModel(t *testing.T) {
 				},
 				Dialer: &jimmtest.Dialer{
 					API: &jimmtest.API{
-						ModelInfo_: test.modelInfo,
+						ModelInfo_:             test.modelInfo,
+						ChangeModelCredential_: test.changeModelCredential,
+						UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+							return nil, nil
+						},
 					},
 				},
 			}
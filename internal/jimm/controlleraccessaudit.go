@@ -0,0 +1,195 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// unknownLocalUserReason is reported for local controller users
+	// that were not created by JIMM.
+	unknownLocalUserReason = "local user unknown to JIMM"
+
+	// directModelAccessReason is reported for model access that was
+	// granted on the controller rather than through JIMM.
+	directModelAccessReason = "model access granted directly on the controller"
+)
+
+// AuditControllerAccess reports the access held directly on the
+// controllers that is unknown to JIMM, see AuditAllControllerAccess for
+// details. If controllerName is not empty only that controller is
+// audited. Only JIMM administrators can perform this operation.
+func (j *JIMM) AuditControllerAccess(ctx context.Context, user *openfga.User, controllerName string) (apiparams.AuditControllerAccessResponse, error) {
+	const op = errors.Op("jimm.AuditControllerAccess")
+
+	if !user.JimmAdmin {
+		return apiparams.AuditControllerAccessResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	resp, err := j.AuditAllControllerAccess(ctx, controllerName, DefaultModelAccessResyncInterval)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// AuditAllControllerAccess queries every controller for its local users
+// and for the users with access to the models it hosts, reporting any
+// that JIMM does not know about. The only local user JIMM expects on a
+// controller is its own, and the only model access it expects is that of
+// the model owner and of JIMM's own user. Unlike ResyncAllModelAccess
+// nothing is changed on the controllers. At most one model is checked per
+// interval. If controllerName is not empty only that controller is
+// audited.
+func (j *JIMM) AuditAllControllerAccess(ctx context.Context, controllerName string, interval time.Duration) (apiparams.AuditControllerAccessResponse, error) {
+	const op = errors.Op("jimm.AuditAllControllerAccess")
+
+	var resp apiparams.AuditControllerAccessResponse
+	controllers, err := j.selectControllers(ctx, controllerName)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+
+	var limit <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+	checked := 0
+	for i := range controllers {
+		ctl := &controllers[i]
+		models, err := j.liveControllerModels(ctx, ctl)
+		if err != nil {
+			return resp, errors.E(op, err)
+		}
+
+		jimmUser, err := j.controllerUsername(ctx, ctl)
+		if err != nil {
+			resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+				Controller: ctl.Name,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		api, err := j.dialController(ctx, ctl)
+		if err != nil {
+			resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+				Controller: ctl.Name,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		users, err := api.UserInfo(ctx)
+		if err != nil {
+			zapctx.Error(ctx, "failed to audit controller users", zap.String("controller", ctl.Name), zaputil.Error(err))
+			resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+				Controller: ctl.Name,
+				Error:      err.Error(),
+			})
+		} else {
+			resp.ControllersChecked++
+			resp.Findings = append(resp.Findings, auditControllerUsers(ctl, jimmUser, users)...)
+		}
+		for k := range models {
+			if limit != nil && checked > 0 {
+				select {
+				case <-limit:
+				case <-ctx.Done():
+					api.Close()
+					return resp, errors.E(op, ctx.Err())
+				}
+			}
+			checked++
+			resp.ModelsChecked++
+			findings, err := auditModelAccess(ctx, api, ctl, jimmUser, &models[k])
+			resp.Findings = append(resp.Findings, findings...)
+			if err != nil {
+				zapctx.Error(ctx, "failed to audit model access", zap.String("model", models[k].UUID.String), zaputil.Error(err))
+				resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+					Controller: ctl.Name,
+					ModelTag:   models[k].ResourceTag().String(),
+					Error:      err.Error(),
+				})
+			}
+		}
+		api.Close()
+	}
+	for _, f := range resp.Findings {
+		zapctx.Warn(ctx, "access unknown to JIMM found on controller",
+			zap.String("controller", f.Controller),
+			zap.String("model", f.ModelTag),
+			zap.String("user", f.User),
+			zap.String("access", f.Access),
+			zap.String("reason", f.Reason),
+		)
+	}
+	return resp, nil
+}
+
+// auditControllerUsers returns a finding for every enabled local user on
+// the controller other than jimmUser, JIMM's own user.
+func auditControllerUsers(ctl *dbmodel.Controller, jimmUser string, users []jujuparams.UserInfo) []apiparams.ControllerAccessFinding {
+	jimmID := names.NewUserTag(jimmUser).Id()
+	var findings []apiparams.ControllerAccessFinding
+	for _, u := range users {
+		if u.Disabled || !names.IsValidUser(u.Username) {
+			continue
+		}
+		ut := names.NewUserTag(u.Username)
+		if !ut.IsLocal() || ut.Id() == jimmID {
+			continue
+		}
+		findings = append(findings, apiparams.ControllerAccessFinding{
+			Controller: ctl.Name,
+			User:       ut.Id(),
+			Access:     u.Access,
+			Reason:     unknownLocalUserReason,
+		})
+	}
+	return findings
+}
+
+// auditModelAccess returns a finding for every user with access to the
+// given model on the controller other than the model owner and jimmUser,
+// JIMM's own user.
+func auditModelAccess(ctx context.Context, api API, ctl *dbmodel.Controller, jimmUser string, m *dbmodel.Model) ([]apiparams.ControllerAccessFinding, error) {
+	mt := m.ResourceTag()
+	mi := jujuparams.ModelInfo{UUID: mt.Id()}
+	if err := api.ModelInfo(ctx, &mi); err != nil {
+		return nil, err
+	}
+
+	jimmID := names.NewUserTag(jimmUser).Id()
+	owner := names.NewUserTag(m.OwnerIdentityName).Id()
+	var findings []apiparams.ControllerAccessFinding
+	for _, u := range mi.Users {
+		if !names.IsValidUser(u.UserName) {
+			continue
+		}
+		ut := names.NewUserTag(u.UserName)
+		if ut.Id() == jimmID || ut.Id() == owner {
+			continue
+		}
+		findings = append(findings, apiparams.ControllerAccessFinding{
+			Controller: ctl.Name,
+			ModelTag:   mt.String(),
+			User:       ut.Id(),
+			Access:     string(u.Access),
+			Reason:     directModelAccessReason,
+		})
+	}
+	return findings, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestAuditControllerAccess(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	model1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	model2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			UserInfo_: func(context.Context) ([]jujuparams.UserInfo, error) {
+				return []jujuparams.UserInfo{{
+					Username: "admin",
+					Access:   "superuser",
+				}, {
+					Username: "mallory",
+					Access:   "login",
+				}, {
+					Username: "trent",
+					Access:   "login",
+					Disabled: true,
+				}}, nil
+			},
+			ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+				switch mi.UUID {
+				case model1.Id():
+					mi.Users = []jujuparams.ModelUserInfo{{
+						UserName: "admin",
+						Access:   jujuparams.ModelAdminAccess,
+					}, {
+						UserName: "alice@canonical.com",
+						Access:   jujuparams.ModelAdminAccess,
+					}, {
+						UserName: "mallory",
+						Access:   jujuparams.ModelWriteAccess,
+					}}
+				case model2.Id():
+					mi.Users = []jujuparams.ModelUserInfo{{
+						UserName: "admin",
+						Access:   jujuparams.ModelAdminAccess,
+					}, {
+						UserName: "alice@canonical.com",
+						Access:   jujuparams.ModelAdminAccess,
+					}}
+				default:
+					return errors.E("unexpected model " + mi.UUID)
+				}
+				return nil
+			},
+			RevokeModelAccess_: func(context.Context, names.ModelTag, names.UserTag, jujuparams.UserAccessPermission) error {
+				c.Error("unexpected call to RevokeModelAccess")
+				return nil
+			},
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		CredentialStore: store,
+		Dialer:          dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// Save the controller as AddController does, with its admin
+	// credentials only in the credential store.
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	ctl.AdminIdentityName = ""
+	ctl.AdminPassword = ""
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	err = store.PutControllerCredentials(ctx, "controller-1", "admin", "secret")
+	c.Assert(err, qt.IsNil)
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	_, err = j.AuditControllerAccess(ctx, bob, "")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	alice.JimmAdmin = true
+
+	_, err = j.AuditControllerAccess(ctx, alice, "no-such-controller")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	resp, err := j.AuditControllerAccess(ctx, alice, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.AuditControllerAccessResponse{
+		ControllersChecked: 1,
+		ModelsChecked:      2,
+		Findings: []apiparams.ControllerAccessFinding{{
+			Controller: "controller-1",
+			User:       "mallory",
+			Access:     "login",
+			Reason:     "local user unknown to JIMM",
+		}, {
+			Controller: "controller-1",
+			ModelTag:   model1.String(),
+			User:       "mallory",
+			Access:     "write",
+			Reason:     "model access granted directly on the controller",
+		}},
+	})
+	c.Check(dialer.IsClosed(), qt.IsTrue)
+}
+
+func TestAuditAllControllerAccessControllerError(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("test error"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	resp, err := j.AuditAllControllerAccess(ctx, "controller-1", 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.AuditControllerAccessResponse{
+		Errors: []apiparams.ModelAccessResyncError{{
+			Controller: "controller-1",
+			Error:      "test error",
+		}},
+	})
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/utils"
+)
+
+// controllerCallAllowlist holds the controller facade methods that may be
+// called with ControllerCall, keyed by facade name. Only routine
+// controller administration is allowed, anything that could be used to
+// gain access to the controller, such as managing users, must be done
+// with the controller's own credentials.
+var controllerCallAllowlist = map[string]map[string]bool{
+	"Controller": {
+		"ConfigSet":           true,
+		"ControllerConfig":    true,
+		"ControllerVersion":   true,
+		"IdentityProviderURL": true,
+		"MongoVersion":        true,
+	},
+}
+
+// controllerConfigSetAllowlist holds the controller configuration keys
+// that may be set with Controller.ConfigSet through ControllerCall. The
+// keys that determine who may log in to the controller, such as
+// identity-url and login-token-refresh-url, and those that control the
+// controller's audit log are not included, they must be set with the
+// controller's own credentials.
+var controllerConfigSetAllowlist = map[string]bool{
+	"agent-logfile-max-backups": true,
+	"agent-logfile-max-size":    true,
+	"agent-ratelimit-max":       true,
+	"agent-ratelimit-rate":      true,
+	"api-port-open-delay":       true,
+	"max-agent-state-size":      true,
+	"max-charm-state-size":      true,
+	"max-debug-log-duration":    true,
+	"max-prune-txn-batch-size":  true,
+	"max-prune-txn-passes":      true,
+	"migration-agent-wait-time": true,
+	"model-logfile-max-backups": true,
+	"model-logfile-max-size":    true,
+	"model-logs-size":           true,
+	"prune-txn-query-count":     true,
+	"prune-txn-sleep-time":      true,
+}
+
+// checkControllerCallArgs checks that the arguments of a call allowed by
+// controllerCallAllowlist are acceptable. The configuration set with
+// Controller.ConfigSet may only contain the keys in
+// controllerConfigSetAllowlist.
+func checkControllerCallArgs(facade, method string, args json.RawMessage) error {
+	if facade != "Controller" || method != "ConfigSet" {
+		return nil
+	}
+	var cs jujuparams.ControllerConfigSet
+	if err := json.Unmarshal(args, &cs); err != nil {
+		return errors.E(errors.CodeBadRequest, err, "invalid controller config")
+	}
+	var denied []string
+	for k := range cs.Config {
+		if !controllerConfigSetAllowlist[k] {
+			denied = append(denied, k)
+		}
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("controller config %s may not be set", strings.Join(denied, ", ")))
+	}
+	return nil
+}
+
+// ControllerCall calls the given method of a facade on the named
+// controller on behalf of a JIMM administrator, so that routine
+// controller administration does not require the controller's
+// credentials. Only the facade methods in the allowlist may be called,
+// and Controller.ConfigSet may only set the configuration keys in its
+// allowlist. The arguments are passed to the controller unchanged and the
+// controller's response is returned. The call, and its outcome, are
+// recorded in the audit log.
+func (j *JIMM) ControllerCall(ctx context.Context, user *openfga.User, controllerName, facade string, version int, method string, args json.RawMessage) (json.RawMessage, error) {
+	const op = errors.Op("jimm.ControllerCall")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if !controllerCallAllowlist[facade][method] {
+		return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("%s.%s may not be called on a controller", facade, method))
+	}
+	if err := checkControllerCallArgs(facade, method, args); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	ctl := dbmodel.Controller{
+		Name: controllerName,
+	}
+	if err := j.Database.GetController(ctx, &ctl); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Now().UTC().Round(time.Millisecond),
+		ConversationId: utils.NewConversationID(),
+		FacadeName:     facade,
+		FacadeMethod:   method,
+		FacadeVersion:  version,
+		ObjectId:       names.NewControllerTag(ctl.UUID).String(),
+		IdentityTag:    user.Tag().String(),
+		Params:         dbmodel.JSON(args),
+	}
+	j.AddAuditLogEntry(&ale)
+
+	resp, err := j.controllerCall(ctx, &ctl, facade, version, method, args)
+
+	ale.ID = 0
+	ale.Time = time.Now().UTC().Round(time.Millisecond)
+	ale.Params = nil
+	ale.IsResponse = true
+	ale.Errors = controllerCallErrors(ctx, err)
+	j.AddAuditLogEntry(&ale)
+
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// controllerCall makes the given call on the controller.
+func (j *JIMM) controllerCall(ctx context.Context, ctl *dbmodel.Controller, facade string, version int, method string, args json.RawMessage) (json.RawMessage, error) {
+	api, err := j.dial(ctx, ctl, names.ModelTag{})
+	if err != nil {
+		return nil, err
+	}
+	defer api.Close()
+
+	var callArgs interface{}
+	if len(args) > 0 {
+		callArgs = args
+	}
+	var resp json.RawMessage
+	if err := api.ControllerCall(ctx, facade, version, method, callArgs, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// controllerCallErrors returns the errors recorded in the audit log for a
+// controller call that finished with the given error.
+func controllerCallErrors(ctx context.Context, err error) dbmodel.JSON {
+	results := jujuparams.ErrorResults{Results: []jujuparams.ErrorResult{}}
+	if err != nil {
+		results.Results = append(results.Results, jujuparams.ErrorResult{Error: &jujuparams.Error{
+			Message: err.Error(),
+			Code:    string(errors.ErrorCode(err)),
+		}})
+	}
+	buf, merr := json.Marshal(results)
+	if merr != nil {
+		zapctx.Error(ctx, "cannot marshal controller call errors", zap.Error(merr))
+		return nil
+	}
+	return dbmodel.JSON(buf)
+}
+
+// RecordRecycledConnection records in the audit log that a connection to
+// a controller was recycled because the given call made on it did not
+// complete in time. The entry is attributed to JIMM itself, the error
+// describes how long the call waited and how many calls were failed.
+func (j *JIMM) RecordRecycledConnection(controllerUUID, modelUUID, facade, method string, elapsed time.Duration, inflight int) {
+	ctx := context.Background()
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Now().UTC().Round(time.Millisecond),
+		ConversationId: utils.NewConversationID(),
+		Model:          modelUUID,
+		FacadeName:     facade,
+		FacadeMethod:   method,
+		ObjectId:       names.NewControllerTag(controllerUUID).String(),
+		IdentityTag:    j.ResourceTag().String(),
+		IsResponse:     true,
+	}
+	err := errors.E(errors.CodeConnectionFailed, fmt.Sprintf("connection recycled after call waited %s, %d outstanding calls failed", elapsed.Round(time.Millisecond), inflight))
+	ale.Errors = controllerCallErrors(ctx, err)
+	j.AddAuditLogEntry(&ale)
+}
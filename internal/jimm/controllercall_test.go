@@ -0,0 +1,126 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const controllerCallTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+`
+
+func TestControllerCall(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				ControllerCall_: func(_ context.Context, facade string, version int, method string, args, resp interface{}) error {
+					c.Check(facade, qt.Equals, "Controller")
+					c.Check(version, qt.Equals, 11)
+					switch method {
+					case "ControllerConfig":
+						c.Check(args, qt.IsNil)
+						*resp.(*json.RawMessage) = json.RawMessage(`{"config":{"audit-log-max-backups":10}}`)
+						return nil
+					case "ConfigSet":
+						c.Check(args, qt.DeepEquals, json.RawMessage(`{"config":{"max-debug-log-duration":"24h"}}`))
+						return errors.E(errors.CodeBadRequest, "invalid config")
+					}
+					c.Errorf("unexpected method %s", method)
+					return errors.E("unexpected call")
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, controllerCallTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.ControllerCall(ctx, openfga.NewUser(bob, client), "controller-1", "Controller", 11, "ControllerConfig", nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+
+	_, err = j.ControllerCall(ctx, admin, "controller-1", "UserManager", 3, "AddUser", nil)
+	c.Check(err, qt.ErrorMatches, `UserManager.AddUser may not be called on a controller`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	_, err = j.ControllerCall(ctx, admin, "controller-2", "Controller", 11, "ControllerConfig", nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	resp, err := j.ControllerCall(ctx, admin, "controller-1", "Controller", 11, "ControllerConfig", nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(resp), qt.Equals, `{"config":{"audit-log-max-backups":10}}`)
+
+	_, err = j.ControllerCall(ctx, admin, "controller-1", "Controller", 11, "ConfigSet", json.RawMessage(`{"config":{"identity-url":"https://example.com","login-token-refresh-url":"https://example.com","max-debug-log-duration":"24h"}}`))
+	c.Check(err, qt.ErrorMatches, `controller config identity-url, login-token-refresh-url may not be set`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// The controller's audit log cannot be changed.
+	_, err = j.ControllerCall(ctx, admin, "controller-1", "Controller", 11, "ConfigSet", json.RawMessage(`{"config":{"audit-log-max-backups":0,"audit-log-max-size":"1M"}}`))
+	c.Check(err, qt.ErrorMatches, `controller config audit-log-max-backups, audit-log-max-size may not be set`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	_, err = j.ControllerCall(ctx, admin, "controller-1", "Controller", 11, "ConfigSet", json.RawMessage(`{"config":{"max-debug-log-duration":"24h"}}`))
+	c.Check(err, qt.ErrorMatches, `invalid config`)
+
+	// Both calls are recorded in the audit log, the rejected calls are
+	// not.
+	var entries []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 4)
+	for _, ale := range entries {
+		c.Check(ale.FacadeName, qt.Equals, "Controller")
+		c.Check(ale.FacadeVersion, qt.Equals, 11)
+		c.Check(ale.ObjectId, qt.Equals, "controller-00000001-0000-0000-0000-000000000001")
+		c.Check(ale.IdentityTag, qt.Equals, "user-alice@canonical.com")
+	}
+	c.Check(entries[0].FacadeMethod, qt.Equals, "ControllerConfig")
+	c.Check(entries[1].IsResponse, qt.IsTrue)
+	c.Check(string(entries[1].Errors), qt.Equals, `{"results":[]}`)
+	c.Check(entries[2].FacadeMethod, qt.Equals, "ConfigSet")
+	c.Check(string(entries[2].Params), qt.Equals, `{"config":{"max-debug-log-duration":"24h"}}`)
+	c.Check(string(entries[3].Errors), qt.Matches, `.*invalid config.*`)
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func (j *JIMM) controllerCertificateExpiryWarning() time.Duration {
+	if j.ControllerCertificateExpiryWarning > 0 {
+		return j.ControllerCertificateExpiryWarning
+	}
+	return CertificateExpiryWarningPeriod
+}
+
+// certificateExpiring reports whether the certificate last presented by
+// the given controller expires within the certificate expiry warning
+// period of the given time.
+func (j *JIMM) certificateExpiring(ctl *dbmodel.Controller, now time.Time) bool {
+	return ctl.CertificateExpiry.Valid && ctl.CertificateExpiry.Time.Before(now.Add(j.controllerCertificateExpiryWarning()))
+}
+
+// daysToExpiry returns the number of whole days from now until the given
+// expiry time, rounded down.
+func daysToExpiry(expiry, now time.Time) int {
+	return int(math.Floor(expiry.Sub(now).Hours() / 24))
+}
+
+// ListControllerCertificates returns the expiry of the certificates
+// presented by every controller when the controller was last dialled,
+// ordered with the soonest expiry first. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) ListControllerCertificates(ctx context.Context, user *openfga.User) (apiparams.ListControllerCertificatesResponse, error) {
+	const op = errors.Op("jimm.ListControllerCertificates")
+
+	if !user.JimmAdmin {
+		return apiparams.ListControllerCertificatesResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	now := time.Now()
+	resp := apiparams.ListControllerCertificatesResponse{
+		Certificates: []apiparams.ControllerCertificate{},
+	}
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		cert := apiparams.ControllerCertificate{
+			Controller: ctl.Name,
+		}
+		if ctl.CertificateExpiry.Valid {
+			expiry := ctl.CertificateExpiry.Time.UTC()
+			days := daysToExpiry(expiry, now)
+			cert.Expiry = &expiry
+			cert.DaysToExpiry = &days
+			cert.Expiring = j.certificateExpiring(ctl, now)
+		}
+		resp.Certificates = append(resp.Certificates, cert)
+		return nil
+	})
+	if err != nil {
+		return apiparams.ListControllerCertificatesResponse{}, errors.E(op, err)
+	}
+	sort.SliceStable(resp.Certificates, func(i, k int) bool {
+		ci, ck := resp.Certificates[i], resp.Certificates[k]
+		switch {
+		case ci.Expiry == nil:
+			return false
+		case ck.Expiry == nil:
+			return true
+		default:
+			return ci.Expiry.Before(*ck.Expiry)
+		}
+	})
+	return resp, nil
+}
+
+// CheckControllerCertificates sends a notification for each controller
+// whose certificate, as recorded when the controller was last dialled,
+// expires within the certificate expiry warning period or has already
+// expired.
+func (j *JIMM) CheckControllerCertificates(ctx context.Context) error {
+	const op = errors.Op("jimm.CheckControllerCertificates")
+
+	now := time.Now()
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if !j.certificateExpiring(ctl, now) {
+			return nil
+		}
+		expiry := ctl.CertificateExpiry.Time.UTC()
+		msg := fmt.Sprintf("certificate of controller %s expires at %s, in %d days", ctl.Name, expiry.Format(time.RFC3339), daysToExpiry(expiry, now))
+		if expiry.Before(now) {
+			msg = fmt.Sprintf("certificate of controller %s expired at %s", ctl.Name, expiry.Format(time.RFC3339))
+		}
+		j.Notifier.Notify(ctx, notify.Event{
+			Kind:       notify.ControllerCertificateExpiring,
+			Controller: ctl.Name,
+			Message:    msg,
+		})
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestControllerCertificates(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	ch := new(recordingChannel)
+	notifier, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	notifier.AddChannel(ch, 0)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Notifier:                           notifier,
+		ControllerCertificateExpiryWarning: 14 * 24 * time.Hour,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, controllerModelCredentialsTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// controller-1 expires in 90 days, controller-2 in 3 days and the
+	// expiry of controller-3 is not known.
+	now := time.Now().UTC().Truncate(time.Second)
+	setExpiry := func(name string, expiry time.Time) {
+		ctl := dbmodel.Controller{Name: name}
+		err := j.Database.GetController(ctx, &ctl)
+		c.Assert(err, qt.IsNil)
+		ctl.CertificateExpiry = sql.NullTime{Time: expiry, Valid: true}
+		err = j.Database.UpdateController(ctx, &ctl)
+		c.Assert(err, qt.IsNil)
+	}
+	setExpiry("controller-1", now.Add(90*24*time.Hour+time.Hour))
+	setExpiry("controller-2", now.Add(3*24*time.Hour+time.Hour))
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.ListControllerCertificates(ctx, openfga.NewUser(bob, client))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	aliceUser := openfga.NewUser(alice, client)
+	aliceUser.JimmAdmin = true
+
+	resp, err := j.ListControllerCertificates(ctx, aliceUser)
+	c.Assert(err, qt.IsNil)
+	c.Assert(resp.Certificates, qt.HasLen, 3)
+	c.Check(resp.Certificates[0].Controller, qt.Equals, "controller-2")
+	c.Check(*resp.Certificates[0].DaysToExpiry, qt.Equals, 3)
+	c.Check(resp.Certificates[0].Expiring, qt.IsTrue)
+	c.Check(resp.Certificates[1].Controller, qt.Equals, "controller-1")
+	c.Check(*resp.Certificates[1].DaysToExpiry, qt.Equals, 90)
+	c.Check(resp.Certificates[1].Expiring, qt.IsFalse)
+	c.Check(resp.Certificates[2].Controller, qt.Equals, "controller-3")
+	c.Check(resp.Certificates[2].Expiry, qt.IsNil)
+	c.Check(resp.Certificates[2].DaysToExpiry, qt.IsNil)
+
+	// Only the expiring certificate is notified.
+	err = j.CheckControllerCertificates(ctx)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	c.Assert(ch.events, qt.HasLen, 1)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.ControllerCertificateExpiring)
+	c.Check(ch.events[0].Controller, qt.Equals, "controller-2")
+	c.Check(ch.events[0].Message, qt.Matches, `certificate of controller controller-2 expires at .*, in 3 days`)
+
+	// Expired certificates are reported as such.
+	ch.events = nil
+	setExpiry("controller-1", now.Add(-time.Hour))
+	err = j.CheckControllerCertificates(ctx)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	sort.Slice(ch.events, func(i, j int) bool {
+		return ch.events[i].Controller < ch.events[j].Controller
+	})
+	c.Assert(ch.events, qt.HasLen, 2)
+	c.Check(ch.events[0].Controller, qt.Equals, "controller-1")
+	c.Check(ch.events[0].Message, qt.Matches, `certificate of controller controller-1 expired at .*`)
+}
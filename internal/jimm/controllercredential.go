@@ -0,0 +1,232 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// DefaultControllerCredentialExpiryWarning is the period before a
+// controller model credential expires in which it is reported as
+// expiring, if no period is configured.
+const DefaultControllerCredentialExpiryWarning = 7 * 24 * time.Hour
+
+// controllerCredentialCheckTimeout is the time allowed to read the
+// controller model credential from a single controller.
+const controllerCredentialCheckTimeout = 30 * time.Second
+
+func (j *JIMM) controllerCredentialExpiryWarning() time.Duration {
+	if j.ControllerCredentialExpiryWarning > 0 {
+		return j.ControllerCredentialExpiryWarning
+	}
+	return DefaultControllerCredentialExpiryWarning
+}
+
+// expiring reports whether the given credential expires within the
+// expiry warning period of the given time.
+func (j *JIMM) expiring(cred *dbmodel.ControllerModelCredential, now time.Time) bool {
+	return cred.ExpiresAt.Valid && cred.ExpiresAt.Time.Before(now.Add(j.controllerCredentialExpiryWarning()))
+}
+
+// ListControllerModelCredentials returns the cloud credential used by the
+// controller model of every controller, as last checked by JIMM. Only
+// JIMM administrators can perform this operation.
+func (j *JIMM) ListControllerModelCredentials(ctx context.Context, user *openfga.User) (apiparams.ListControllerModelCredentialsResponse, error) {
+	const op = errors.Op("jimm.ListControllerModelCredentials")
+
+	if !user.JimmAdmin {
+		return apiparams.ListControllerModelCredentialsResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	now := time.Now()
+	resp := apiparams.ListControllerModelCredentialsResponse{
+		Credentials: []apiparams.ControllerModelCredential{},
+	}
+	err := j.Database.ForEachControllerModelCredential(ctx, func(cred *dbmodel.ControllerModelCredential) error {
+		c := cred.ToAPIControllerModelCredential()
+		c.Expiring = j.expiring(cred, now)
+		resp.Credentials = append(resp.Credentials, c)
+		return nil
+	})
+	if err != nil {
+		return apiparams.ListControllerModelCredentialsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// RotateControllerModelCredential replaces the content of the cloud
+// credential used by the controller model of the given controller, and
+// records the time the new credential expires. If no credential content
+// is specified only the recorded expiry is changed. The validity of the
+// credential is checked on the controller after it has been updated.
+// Only JIMM administrators can perform this operation.
+func (j *JIMM) RotateControllerModelCredential(ctx context.Context, user *openfga.User, req apiparams.RotateControllerModelCredentialRequest) (apiparams.ControllerModelCredential, error) {
+	const op = errors.Op("jimm.RotateControllerModelCredential")
+
+	if !user.JimmAdmin {
+		return apiparams.ControllerModelCredential{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if req.Controller == "" {
+		return apiparams.ControllerModelCredential{}, errors.E(op, errors.CodeBadRequest, "controller not specified")
+	}
+
+	ctl := dbmodel.Controller{Name: req.Controller}
+	if err := j.Database.GetController(ctx, &ctl); err != nil {
+		return apiparams.ControllerModelCredential{}, errors.E(op, err)
+	}
+	cred := dbmodel.ControllerModelCredential{ControllerName: ctl.Name}
+	if err := j.Database.GetControllerModelCredential(ctx, &cred); err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+		return apiparams.ControllerModelCredential{}, errors.E(op, err)
+	}
+
+	api, err := j.dial(ctx, &ctl, names.ModelTag{})
+	if err != nil {
+		return apiparams.ControllerModelCredential{}, errors.E(op, err)
+	}
+	defer api.Close()
+
+	if req.Credential != nil {
+		ms, err := getControllerModelSummary(ctx, api)
+		if err != nil {
+			return apiparams.ControllerModelCredential{}, errors.E(op, err, "failed to get model summary")
+		}
+		if ms.CloudCredentialTag == "" {
+			return apiparams.ControllerModelCredential{}, errors.E(op, errors.CodeNotFound, fmt.Sprintf("controller %s does not report a controller model credential", ctl.Name))
+		}
+		if _, err := api.UpdateCredential(ctx, jujuparams.TaggedCredential{
+			Tag:        ms.CloudCredentialTag,
+			Credential: *req.Credential,
+		}); err != nil {
+			return apiparams.ControllerModelCredential{}, errors.E(op, err, "failed to update credential")
+		}
+		cred.RotatedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+	cred.ExpiresAt = sql.NullTime{}
+	if req.ExpiresAt != nil {
+		cred.ExpiresAt = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+	if err := readControllerModelCredential(ctx, api, &cred); err != nil {
+		return apiparams.ControllerModelCredential{}, errors.E(op, err)
+	}
+	if err := j.Database.UpsertControllerModelCredential(ctx, &cred); err != nil {
+		return apiparams.ControllerModelCredential{}, errors.E(op, err)
+	}
+
+	resp := cred.ToAPIControllerModelCredential()
+	resp.Expiring = j.expiring(&cred, time.Now())
+	return resp, nil
+}
+
+// CheckControllerModelCredentials reads the cloud credential used by the
+// controller model of every controller, and whether the controller
+// considers it valid, and records them. A notification is sent for each
+// credential that is invalid or that expires within the expiry warning
+// period. Controllers that cannot be checked are logged and skipped.
+func (j *JIMM) CheckControllerModelCredentials(ctx context.Context) error {
+	const op = errors.Op("jimm.CheckControllerModelCredentials")
+
+	controllers, err := j.selectControllers(ctx, "")
+	if err != nil {
+		return errors.E(op, err)
+	}
+	now := time.Now()
+	for i := range controllers {
+		ctl := &controllers[i]
+		cred := dbmodel.ControllerModelCredential{ControllerName: ctl.Name}
+		if err := j.Database.GetControllerModelCredential(ctx, &cred); err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+			return errors.E(op, err)
+		}
+		if err := j.checkControllerModelCredential(ctx, ctl, &cred); err != nil {
+			zapctx.Warn(ctx, "cannot check controller model credential", zap.String("controller", ctl.Name), zap.Error(err))
+			continue
+		}
+		if err := j.Database.UpsertControllerModelCredential(ctx, &cred); err != nil {
+			return errors.E(op, err)
+		}
+		if cred.Valid.Valid && !cred.Valid.Bool {
+			j.Notifier.Notify(ctx, notify.Event{
+				Kind:       notify.ControllerCredentialInvalid,
+				Controller: ctl.Name,
+				Credential: cred.CredentialTag,
+				Message:    fmt.Sprintf("controller model credential on controller %s is invalid", ctl.Name),
+			})
+		}
+		if j.expiring(&cred, now) {
+			j.Notifier.Notify(ctx, notify.Event{
+				Kind:       notify.ControllerCredentialExpiring,
+				Controller: ctl.Name,
+				Credential: cred.CredentialTag,
+				Message:    fmt.Sprintf("controller model credential on controller %s expires at %s", ctl.Name, cred.ExpiresAt.Time.UTC().Format(time.RFC3339)),
+			})
+		}
+	}
+	return nil
+}
+
+func (j *JIMM) checkControllerModelCredential(ctx context.Context, ctl *dbmodel.Controller, cred *dbmodel.ControllerModelCredential) error {
+	ctx, cancel := context.WithTimeout(ctx, controllerCredentialCheckTimeout)
+	defer cancel()
+
+	api, err := j.dial(ctx, ctl, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return readControllerModelCredential(ctx, api, cred)
+}
+
+// readControllerModelCredential updates the given credential with the
+// tag and validity of the controller model credential reported by the
+// controller.
+func readControllerModelCredential(ctx context.Context, api API, cred *dbmodel.ControllerModelCredential) error {
+	ms, err := getControllerModelSummary(ctx, api)
+	if err != nil {
+		return err
+	}
+	if ms.CloudCredentialTag != cred.CredentialTag {
+		// The credential has changed, whatever was known about
+		// the previous one no longer applies.
+		cred.Valid = sql.NullBool{}
+	}
+	cred.CredentialTag = ms.CloudCredentialTag
+	mi := jujuparams.ModelInfo{UUID: ms.UUID}
+	if err := api.ModelInfo(ctx, &mi); err != nil {
+		return err
+	}
+	if mi.CloudCredentialValidity != nil {
+		cred.Valid = sql.NullBool{Bool: *mi.CloudCredentialValidity, Valid: true}
+	}
+	cred.CheckedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	return nil
+}
+
+// recordControllerModelCredential records the controller model
+// credential reported in the given controller model summary, when the
+// controller is added. Failures are logged.
+func (j *JIMM) recordControllerModelCredential(ctx context.Context, ctl *dbmodel.Controller, ms jujuparams.ModelSummary) {
+	if ms.CloudCredentialTag == "" {
+		return
+	}
+	cred := dbmodel.ControllerModelCredential{
+		ControllerName: ctl.Name,
+		CredentialTag:  ms.CloudCredentialTag,
+		CheckedAt:      sql.NullTime{Time: time.Now().UTC(), Valid: true},
+	}
+	if err := j.Database.UpsertControllerModelCredential(ctx, &cred); err != nil {
+		zapctx.Error(ctx, "failed to record controller model credential", zap.String("controller", ctl.Name), zap.Error(err))
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const controllerModelCredentialsTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test-cloud
+  region: test-cloud-region
+`
+
+// recordingChannel is a notify.Channel that records the events sent to it.
+type recordingChannel struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (c *recordingChannel) Send(_ context.Context, e notify.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, e)
+	return nil
+}
+
+func controllerModelAPI(credTag string, valid *bool) *jimmtest.API {
+	return &jimmtest.API{
+		ControllerModelSummary_: func(_ context.Context, ms *jujuparams.ModelSummary) error {
+			ms.UUID = "00000002-0000-0000-0000-000000000001"
+			ms.CloudCredentialTag = credTag
+			return nil
+		},
+		ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+			if mi.UUID != "00000002-0000-0000-0000-000000000001" {
+				return errors.E(errors.CodeNotFound, "model not found")
+			}
+			mi.CloudCredentialValidity = valid
+			return nil
+		},
+	}
+}
+
+func TestControllerModelCredentials(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	valid, invalid := true, false
+	var updated []jujuparams.TaggedCredential
+	api1 := controllerModelAPI("cloudcred-test-cloud_admin_default", &valid)
+	api1.UpdateCredential_ = func(_ context.Context, cred jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+		updated = append(updated, cred)
+		return nil, nil
+	}
+
+	ch := new(recordingChannel)
+	notifier, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	notifier.AddChannel(ch, 0)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: jimmtest.DialerMap{
+			"controller-1": &jimmtest.Dialer{API: api1},
+			"controller-2": &jimmtest.Dialer{API: controllerModelAPI("cloudcred-test-cloud_admin_bootstrap", &invalid)},
+			"controller-3": &jimmtest.Dialer{Err: errors.E("connection refused")},
+		},
+		Notifier:                          notifier,
+		ControllerCredentialExpiryWarning: 24 * time.Hour,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, controllerModelCredentialsTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	bobUser := openfga.NewUser(bob, client)
+	_, err = j.ListControllerModelCredentials(ctx, bobUser)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.RotateControllerModelCredential(ctx, bobUser, apiparams.RotateControllerModelCredentialRequest{Controller: "controller-1"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	aliceUser := openfga.NewUser(alice, client)
+	aliceUser.JimmAdmin = true
+
+	resp, err := j.ListControllerModelCredentials(ctx, aliceUser)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Credentials, qt.HasLen, 0)
+
+	// Checking records the credential of every reachable controller
+	// and notifies about the invalid one.
+	err = j.CheckControllerModelCredentials(ctx)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	c.Assert(ch.events, qt.HasLen, 1)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.ControllerCredentialInvalid)
+	c.Check(ch.events[0].Controller, qt.Equals, "controller-2")
+	c.Check(ch.events[0].Credential, qt.Equals, "cloudcred-test-cloud_admin_bootstrap")
+
+	resp, err = j.ListControllerModelCredentials(ctx, aliceUser)
+	c.Assert(err, qt.IsNil)
+	c.Assert(resp.Credentials, qt.HasLen, 2)
+	c.Check(resp.Credentials[0].Controller, qt.Equals, "controller-1")
+	c.Check(resp.Credentials[0].Credential, qt.Equals, "cloudcred-test-cloud_admin_default")
+	c.Check(resp.Credentials[0].Valid, qt.DeepEquals, &valid)
+	c.Check(resp.Credentials[0].CheckedAt, qt.Not(qt.IsNil))
+	c.Check(resp.Credentials[1].Controller, qt.Equals, "controller-2")
+	c.Check(resp.Credentials[1].Valid, qt.DeepEquals, &invalid)
+
+	_, err = j.RotateControllerModelCredential(ctx, aliceUser, apiparams.RotateControllerModelCredentialRequest{Controller: "no-such-controller"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	_, err = j.RotateControllerModelCredential(ctx, aliceUser, apiparams.RotateControllerModelCredentialRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// Rotating updates the credential on the controller and records
+	// the new expiry.
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	cred, err := j.RotateControllerModelCredential(ctx, aliceUser, apiparams.RotateControllerModelCredentialRequest{
+		Controller: "controller-1",
+		Credential: &jujuparams.CloudCredential{
+			AuthType:   "userpass",
+			Attributes: map[string]string{"username": "admin", "password": "new-password"},
+		},
+		ExpiresAt: &expires,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(updated, qt.DeepEquals, []jujuparams.TaggedCredential{{
+		Tag: "cloudcred-test-cloud_admin_default",
+		Credential: jujuparams.CloudCredential{
+			AuthType:   "userpass",
+			Attributes: map[string]string{"username": "admin", "password": "new-password"},
+		},
+	}})
+	c.Check(cred.Controller, qt.Equals, "controller-1")
+	c.Check(cred.Credential, qt.Equals, "cloudcred-test-cloud_admin_default")
+	c.Check(cred.ExpiresAt.Equal(expires), qt.IsTrue)
+	c.Check(cred.RotatedAt, qt.Not(qt.IsNil))
+	c.Check(cred.Expiring, qt.IsTrue)
+
+	// The credential now expires within the warning period.
+	ch.events = nil
+	err = j.CheckControllerModelCredentials(ctx)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	sort.Slice(ch.events, func(i, j int) bool {
+		return ch.events[i].Controller < ch.events[j].Controller
+	})
+	c.Assert(ch.events, qt.HasLen, 2)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.ControllerCredentialExpiring)
+	c.Check(ch.events[0].Controller, qt.Equals, "controller-1")
+	c.Check(ch.events[1].Kind, qt.Equals, notify.ControllerCredentialInvalid)
+
+	// Changing only the expiry does not rotate the credential.
+	later := expires.Add(30 * 24 * time.Hour)
+	cred, err = j.RotateControllerModelCredential(ctx, aliceUser, apiparams.RotateControllerModelCredentialRequest{
+		Controller: "controller-1",
+		ExpiresAt:  &later,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(updated, qt.HasLen, 1)
+	c.Check(cred.ExpiresAt.Equal(later), qt.IsTrue)
+	c.Check(cred.Expiring, qt.IsFalse)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/utils"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelControllerEndpoints returns the API endpoints and CA certificate
+// of the controller hosting the model with the given tag, so that tooling
+// for which the model proxy is unsuitable can connect to the controller
+// directly. The user must be an administrator of the model. Every
+// request, whether or not it is allowed, is recorded in the audit log.
+func (j *JIMM) ModelControllerEndpoints(ctx context.Context, user *openfga.User, mt names.ModelTag) (_ apiparams.ModelControllerEndpoints, err error) {
+	const op = errors.Op("jimm.ModelControllerEndpoints")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelControllerEndpoints{}, errors.E(op, err)
+	}
+	defer func() {
+		j.auditControllerEndpointsAccess(ctx, user, &m, err)
+	}()
+
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return apiparams.ModelControllerEndpoints{}, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return apiparams.ModelControllerEndpoints{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	ctl := &m.Controller
+	endpoints := apiparams.ModelControllerEndpoints{
+		ModelTag:       mt.String(),
+		Controller:     ctl.Name,
+		ControllerUUID: ctl.UUID,
+		Endpoints:      []string{},
+		CACertificate:  ctl.CACertificate,
+	}
+	seen := make(map[string]bool)
+	addEndpoint := func(addr string) {
+		if addr == "" || seen[addr] {
+			return
+		}
+		seen[addr] = true
+		endpoints.Endpoints = append(endpoints.Endpoints, addr)
+	}
+	addEndpoint(ctl.PublicAddress)
+	for _, hps := range ctl.ClientHostPorts() {
+		for _, hp := range hps {
+			addEndpoint(net.JoinHostPort(hp.Value, strconv.Itoa(hp.Port)))
+		}
+	}
+	return endpoints, nil
+}
+
+// auditControllerEndpointsAccess records in the audit log that the given
+// user requested the endpoints of the controller hosting the given model,
+// and the error, if any, the request failed with.
+func (j *JIMM) auditControllerEndpointsAccess(ctx context.Context, user *openfga.User, m *dbmodel.Model, err error) {
+	params, _ := json.Marshal(map[string]string{"controller": m.Controller.Name})
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Now().UTC().Round(time.Millisecond),
+		ConversationId: utils.NewConversationID(),
+		Model:          m.UUID.String,
+		FacadeName:     "JIMM",
+		FacadeMethod:   "ModelControllerEndpoints",
+		ObjectId:       m.ResourceTag().String(),
+		IdentityTag:    user.Tag().String(),
+		IsResponse:     true,
+		Params:         dbmodel.JSON(params),
+		Errors:         controllerCallErrors(ctx, err),
+	}
+	j.AddAuditLogEntry(&ale)
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestModelControllerEndpoints(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// The controllers must not be contacted.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	ctl := env.Controller("controller-1").DBObject(c, j.Database)
+	ctl.PublicAddress = "controller-1.example.com:17070"
+	ctl.CACertificate = "ca-cert"
+	ctl.Addresses = dbmodel.HostPorts{{{
+		Address: jujuparams.Address{Value: "10.0.0.1", Type: "ipv4"},
+		Port:    17070,
+	}, {
+		Address: jujuparams.Address{Value: "10.0.0.2", Type: "ipv4"},
+		Port:    17070,
+	}}}
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	endpoints, err := j.ModelControllerEndpoints(ctx, alice, m.ResourceTag())
+	c.Assert(err, qt.IsNil)
+	c.Check(endpoints, qt.DeepEquals, apiparams.ModelControllerEndpoints{
+		ModelTag:       m.ResourceTag().String(),
+		Controller:     "controller-1",
+		ControllerUUID: "00000001-0000-0000-0000-000000000001",
+		Endpoints: []string{
+			"controller-1.example.com:17070",
+			"10.0.0.1:17070",
+			"10.0.0.2:17070",
+		},
+		CACertificate: "ca-cert",
+	})
+
+	// Users with write access to the model may not see the endpoints.
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	_, err = j.ModelControllerEndpoints(ctx, openfga.NewUser(&bobIdentity, client), m.ResourceTag())
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// Both requests are recorded in the audit log.
+	var entries []dbmodel.AuditLogEntry
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: "ModelControllerEndpoints"}, func(ale *dbmodel.AuditLogEntry) error {
+		entries = append(entries, *ale)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.HasLen, 2)
+	for _, ale := range entries {
+		c.Check(ale.FacadeName, qt.Equals, "JIMM")
+		c.Check(ale.ObjectId, qt.Equals, m.ResourceTag().String())
+		c.Check(string(ale.Params), qt.Equals, `{"controller":"controller-1"}`)
+	}
+	c.Check(entries[0].IdentityTag, qt.Equals, "user-alice@canonical.com")
+	c.Check(string(entries[0].Errors), qt.Equals, `{"results":[]}`)
+	c.Check(entries[1].IdentityTag, qt.Equals, "user-bob@canonical.com")
+	c.Check(string(entries[1].Errors), qt.Matches, `.*"code":"unauthorized".*`)
+
+	_, err = j.ModelControllerEndpoints(ctx, alice, names.NewModelTag("00000002-0000-0000-0000-000000000002"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,333 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ControllerHealthParams holds the parameters used to score the health of
+// controllers. Zero values are replaced by the defaults.
+type ControllerHealthParams struct {
+	// HalfLife is the age at which an observation counts half as much
+	// as a new one. The default is 5 minutes.
+	HalfLife time.Duration
+
+	// LatencyLimit is the average request latency at which a controller
+	// loses all of the score given for latency. The default is 5
+	// seconds.
+	LatencyLimit time.Duration
+
+	// WatcherLagLimit is the average time taken to process a batch of
+	// watcher deltas at which a controller loses all of the score given
+	// for watcher lag. The default is 30 seconds.
+	WatcherLagLimit time.Duration
+
+	// DegradedScore is the score below which a healthy controller is
+	// considered degraded. The default is 0.5.
+	DegradedScore float64
+
+	// RecoveredScore is the score above which a degraded controller is
+	// considered healthy again. It should be higher than DegradedScore
+	// so that a score hovering around a threshold does not flip the
+	// controller between the two states. The default is 0.7.
+	RecoveredScore float64
+}
+
+const (
+	defaultHealthHalfLife        = 5 * time.Minute
+	defaultHealthLatencyLimit    = 5 * time.Second
+	defaultHealthWatcherLagLimit = 30 * time.Second
+	defaultHealthDegradedScore   = 0.5
+	defaultHealthRecoveredScore  = 0.7
+
+	// healthErrorWeight, healthLatencyWeight and healthLagWeight are
+	// the proportions of the score given for each of the health
+	// measures.
+	healthErrorWeight   = 0.6
+	healthLatencyWeight = 0.2
+	healthLagWeight     = 0.2
+
+	// healthPriorWeight is the weight of an implicit healthy observation
+	// included in every average. It stops a handful of failures from
+	// condemning a controller and lets the averages return to healthy as
+	// the real observations decay.
+	healthPriorWeight = 1
+)
+
+// ControllerHealthStatus holds the health of a controller.
+type ControllerHealthStatus struct {
+	// Score is the health score of the controller, between 0 (unusable)
+	// and 1 (healthy).
+	Score float64
+
+	// Degraded reports whether the controller is considered degraded.
+	// Degraded controllers are avoided when placing new models.
+	Degraded bool
+
+	// ErrorRate is the proportion of recent requests to the controller
+	// that failed.
+	ErrorRate float64
+
+	// Latency is the recent average latency of requests to the
+	// controller.
+	Latency time.Duration
+
+	// WatcherLag is the recent average time taken to process the
+	// changes reported by the controller's watcher.
+	WatcherLag time.Duration
+}
+
+// ControllerHealth keeps a rolling health score for each controller,
+// calculated from the error rate and latency of the requests JIMM makes
+// to the controller and how far the watcher lags behind the controller.
+// Older observations are given exponentially less weight, so a
+// controller recovers as it stops failing. A nil ControllerHealth is
+// valid, it records nothing and reports every controller as healthy.
+type ControllerHealth struct {
+	params ControllerHealthParams
+
+	// now is used to get the current time, it may be replaced in tests.
+	now func() time.Time
+
+	mu          sync.Mutex
+	controllers map[string]*controllerHealth
+}
+
+// NewControllerHealth creates a new ControllerHealth using the given
+// parameters.
+func NewControllerHealth(p ControllerHealthParams) *ControllerHealth {
+	if p.HalfLife <= 0 {
+		p.HalfLife = defaultHealthHalfLife
+	}
+	if p.LatencyLimit <= 0 {
+		p.LatencyLimit = defaultHealthLatencyLimit
+	}
+	if p.WatcherLagLimit <= 0 {
+		p.WatcherLagLimit = defaultHealthWatcherLagLimit
+	}
+	if p.DegradedScore <= 0 {
+		p.DegradedScore = defaultHealthDegradedScore
+	}
+	if p.RecoveredScore <= 0 {
+		p.RecoveredScore = defaultHealthRecoveredScore
+	}
+	if p.RecoveredScore < p.DegradedScore {
+		p.RecoveredScore = p.DegradedScore
+	}
+	return &ControllerHealth{
+		params:      p,
+		now:         time.Now,
+		controllers: make(map[string]*controllerHealth),
+	}
+}
+
+// ObserveRequest records the outcome of a request to the named
+// controller that took the given time.
+func (h *ControllerHealth) ObserveRequest(controller string, latency time.Duration, err error) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := h.get(controller)
+	ch.requests++
+	if err != nil {
+		ch.errors++
+	}
+	ch.latency += latency.Seconds()
+	h.update(ch)
+}
+
+// ObserveWatcherLag records the time taken to process a batch of changes
+// reported by the watcher on the named controller.
+func (h *ControllerHealth) ObserveWatcherLag(controller string, lag time.Duration) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := h.get(controller)
+	ch.batches++
+	ch.lag += lag.Seconds()
+	h.update(ch)
+}
+
+// Status returns the current health of the named controller and whether
+// anything has been observed about the controller. A controller with no
+// observations is healthy.
+func (h *ControllerHealth) Status(controller string) (ControllerHealthStatus, bool) {
+	if h == nil {
+		return ControllerHealthStatus{Score: 1}, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := h.controllers[controller]
+	if ch == nil {
+		return ControllerHealthStatus{Score: 1}, false
+	}
+	h.update(ch)
+	return h.status(ch), true
+}
+
+// Degraded reports whether the named controller is considered degraded.
+func (h *ControllerHealth) Degraded(controller string) bool {
+	st, _ := h.Status(controller)
+	return st.Degraded
+}
+
+// Forget removes the health of the named controller.
+func (h *ControllerHealth) Forget(controller string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.controllers, controller)
+}
+
+// ControllerHealth returns the health of the named controller, or nil if
+// nothing is known about the controller's health.
+func (j *JIMM) ControllerHealth(controllerName string) *apiparams.ControllerHealth {
+	st, ok := j.Health.Status(controllerName)
+	if !ok {
+		return nil
+	}
+	return &apiparams.ControllerHealth{
+		Score:        st.Score,
+		Degraded:     st.Degraded,
+		ErrorRate:    st.ErrorRate,
+		LatencyMS:    st.Latency.Milliseconds(),
+		WatcherLagMS: st.WatcherLag.Milliseconds(),
+	}
+}
+
+// sortRegionControllers moves the degraded controllers in the given slice
+// after the healthy ones, otherwise keeping their order.
+func (h *ControllerHealth) sortRegionControllers(crps []dbmodel.CloudRegionControllerPriority) {
+	if h == nil {
+		return
+	}
+	degraded := make(map[string]bool, len(crps))
+	for _, crp := range crps {
+		degraded[crp.Controller.Name] = h.Degraded(crp.Controller.Name)
+	}
+	sort.SliceStable(crps, func(i, k int) bool {
+		return !degraded[crps[i].Controller.Name] && degraded[crps[k].Controller.Name]
+	})
+}
+
+// get returns the health record for the named controller, creating it if
+// necessary. The mutex must be held.
+func (h *ControllerHealth) get(controller string) *controllerHealth {
+	ch := h.controllers[controller]
+	if ch == nil {
+		ch = &controllerHealth{updated: h.now()}
+		h.controllers[controller] = ch
+	}
+	return ch
+}
+
+// update decays the observations in the given record to the current
+// time and re-evaluates whether the controller is degraded. The mutex
+// must be held.
+func (h *ControllerHealth) update(ch *controllerHealth) {
+	now := h.now()
+	if age := now.Sub(ch.updated); age > 0 {
+		f := math.Pow(0.5, float64(age)/float64(h.params.HalfLife))
+		ch.requests *= f
+		ch.errors *= f
+		ch.latency *= f
+		ch.batches *= f
+		ch.lag *= f
+		ch.updated = now
+	}
+	score := h.status(ch).Score
+	switch {
+	case !ch.degraded && score < h.params.DegradedScore:
+		ch.degraded = true
+	case ch.degraded && score > h.params.RecoveredScore:
+		ch.degraded = false
+	}
+}
+
+// status calculates the health of the controller from the given record.
+func (h *ControllerHealth) status(ch *controllerHealth) ControllerHealthStatus {
+	var st ControllerHealthStatus
+	st.ErrorRate = ch.errors / (ch.requests + healthPriorWeight)
+	st.Latency = time.Duration(ch.latency / (ch.requests + healthPriorWeight) * float64(time.Second))
+	st.WatcherLag = time.Duration(ch.lag / (ch.batches + healthPriorWeight) * float64(time.Second))
+	st.Score = 1 -
+		healthErrorWeight*st.ErrorRate -
+		healthLatencyWeight*math.Min(float64(st.Latency)/float64(h.params.LatencyLimit), 1) -
+		healthLagWeight*math.Min(float64(st.WatcherLag)/float64(h.params.WatcherLagLimit), 1)
+	st.Degraded = ch.degraded
+	return st
+}
+
+// A controllerHealth holds the decayed observations of a controller.
+type controllerHealth struct {
+	updated time.Time
+
+	// requests and errors are the weighted counts of requests and of
+	// failed requests, latency is the weighted sum of their latencies in
+	// seconds.
+	requests float64
+	errors   float64
+	latency  float64
+
+	// batches is the weighted count of watcher batches, lag is the
+	// weighted sum of their processing times in seconds.
+	batches float64
+	lag     float64
+
+	degraded bool
+}
+
+// ControllerHealthStates returns the availability, monitoring lease and
+// health of every controller attached to JIMM, ordered by name. Only
+// JIMM administrators may see the state of the controllers.
+func (j *JIMM) ControllerHealthStates(ctx context.Context, user *openfga.User) ([]apiparams.ControllerHealthState, error) {
+	const op = errors.Op("jimm.ControllerHealthStates")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	var states []apiparams.ControllerHealthState
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		ci := ctl.ToAPIControllerInfo()
+		st := apiparams.ControllerHealthState{
+			Name:   ctl.Name,
+			Status: ci.Status.Status,
+			Health: j.ControllerHealth(ctl.Name),
+		}
+		if ctl.UnavailableSince.Valid {
+			since := ctl.UnavailableSince.Time
+			st.Since = &since
+			st.UnavailableReason = string(ctl.UnavailableReason)
+		}
+		monitor := dbmodel.ControllerMonitor{ControllerName: ctl.Name}
+		switch err := j.Database.GetControllerMonitor(ctx, &monitor); {
+		case err == nil:
+			st.Monitor = monitor.Holder
+			st.MonitorHeartbeat = &monitor.Heartbeat
+		case errors.ErrorCode(err) != errors.CodeNotFound:
+			return err
+		}
+		states = append(states, st)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return states, nil
+}
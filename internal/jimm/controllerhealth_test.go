@@ -0,0 +1,179 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestControllerHealth(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := jimm.NewControllerHealth(jimm.ControllerHealthParams{
+		HalfLife: time.Minute,
+	})
+	jimm.SetControllerHealthClock(h, func() time.Time { return now })
+
+	st, ok := h.Status("controller-1")
+	c.Check(ok, qt.IsFalse)
+	c.Check(st, qt.DeepEquals, jimm.ControllerHealthStatus{Score: 1})
+
+	// A single failure is not enough to degrade a controller.
+	h.ObserveRequest("controller-1", 10*time.Millisecond, errors.New("connection refused"))
+	c.Check(h.Degraded("controller-1"), qt.IsFalse)
+
+	// Repeated failures are.
+	for i := 0; i < 10; i++ {
+		h.ObserveRequest("controller-1", time.Second, errors.New("connection refused"))
+	}
+	st, ok = h.Status("controller-1")
+	c.Check(ok, qt.IsTrue)
+	c.Check(st.Degraded, qt.IsTrue)
+	c.Check(st.Score < 0.5, qt.IsTrue, qt.Commentf("score %v", st.Score))
+
+	// Successes raise the score, but the controller only recovers once
+	// the score is well above the degraded threshold.
+	for i := 0; i < 10; i++ {
+		h.ObserveRequest("controller-1", 10*time.Millisecond, nil)
+	}
+	st, _ = h.Status("controller-1")
+	c.Check(st.Score > 0.5, qt.IsTrue, qt.Commentf("score %v", st.Score))
+	c.Check(st.Score < 0.7, qt.IsTrue, qt.Commentf("score %v", st.Score))
+	c.Check(st.Degraded, qt.IsTrue)
+
+	// Old failures decay away.
+	now = now.Add(10 * time.Minute)
+	st, _ = h.Status("controller-1")
+	c.Check(st.Degraded, qt.IsFalse)
+	c.Check(st.Score > 0.99, qt.IsTrue, qt.Commentf("score %v", st.Score))
+
+	// Watcher lag counts against the score.
+	h.ObserveWatcherLag("controller-2", time.Minute)
+	st, _ = h.Status("controller-2")
+	c.Check(st.WatcherLag, qt.Equals, 30*time.Second)
+	c.Check(st.Score, qt.CmpEquals(cmpopts.EquateApprox(0, 1e-9)), 0.8)
+
+	h.Forget("controller-2")
+	_, ok = h.Status("controller-2")
+	c.Check(ok, qt.IsFalse)
+}
+
+func TestNilControllerHealth(t *testing.T) {
+	c := qt.New(t)
+
+	var h *jimm.ControllerHealth
+	h.ObserveRequest("controller-1", time.Second, errors.New("connection refused"))
+	h.ObserveWatcherLag("controller-1", time.Minute)
+	c.Check(h.Degraded("controller-1"), qt.IsFalse)
+
+	j := &jimm.JIMM{}
+	c.Check(j.ControllerHealth("controller-1"), qt.IsNil)
+}
+
+func TestJIMMControllerHealth(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	j := &jimm.JIMM{
+		Health: jimm.NewControllerHealth(jimm.ControllerHealthParams{}),
+	}
+	jimm.SetControllerHealthClock(j.Health, func() time.Time { return now })
+	j.Health.ObserveRequest("controller-1", 2*time.Second, nil)
+	health := j.ControllerHealth("controller-1")
+	c.Assert(health, qt.Not(qt.IsNil))
+	c.Check(health.Score, qt.CmpEquals(cmpopts.EquateApprox(0, 1e-9)), 0.96)
+	c.Check(health.Degraded, qt.IsFalse)
+	c.Check(health.ErrorRate, qt.Equals, 0.0)
+	c.Check(health.LatencyMS, qt.Equals, int64(1000))
+	c.Check(health.WatcherLagMS, qt.Equals, int64(0))
+}
+
+const controllerHealthStatesTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+`
+
+func TestControllerHealthStates(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Health: jimm.NewControllerHealth(jimm.ControllerHealthParams{}),
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, controllerHealthStatesTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	ctl := env.Controller("controller-2").DBObject(c, j.Database)
+	ctl.UnavailableSince = sql.NullTime{Time: time.Now(), Valid: true}
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	claimed, err := j.Database.ClaimControllerMonitor(ctx, &dbmodel.ControllerMonitor{
+		ControllerName: "controller-1",
+		Holder:         "replica-1",
+		Nonce:          "nonce",
+	}, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(claimed, qt.IsTrue)
+	j.Health.ObserveWatcherLag("controller-1", 2*time.Second)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.ControllerHealthStates(ctx, openfga.NewUser(bob, client))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+	states, err := j.ControllerHealthStates(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(states, qt.HasLen, 2)
+	c.Check(states[0].Name, qt.Equals, "controller-1")
+	c.Check(states[0].Status, qt.Equals, "available")
+	c.Check(states[0].Monitor, qt.Equals, "replica-1")
+	c.Check(states[0].MonitorHeartbeat, qt.Not(qt.IsNil))
+	c.Assert(states[0].Health, qt.Not(qt.IsNil))
+	c.Check(states[0].Health.WatcherLagMS, qt.Equals, int64(1000))
+	c.Check(states[1].Name, qt.Equals, "controller-2")
+	c.Check(states[1].Status, qt.Equals, "unavailable")
+	c.Check(states[1].Since, qt.Not(qt.IsNil))
+	c.Check(states[1].Monitor, qt.Equals, "")
+	c.Check(states[1].Health, qt.IsNil)
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	jujuhttp "github.com/juju/http/v2"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/rpc"
+)
+
+const (
+	// controllerMetricsPath is the path of the metrics endpoint on a
+	// controller's API server.
+	controllerMetricsPath = "/introspection/metrics"
+
+	// controllerMetricsConcurrency is the maximum number of controllers
+	// scraped at the same time by ScrapeControllerMetrics.
+	controllerMetricsConcurrency = 10
+
+	// controllerMetricsTimeout is the time allowed to scrape a single
+	// controller.
+	controllerMetricsTimeout = 10 * time.Second
+
+	// controllerLabel is the label added to the scraped metrics to
+	// identify the controller they were scraped from.
+	controllerLabel = "controller"
+
+	// controllerMetricsUpName is the name of the metric reporting
+	// whether each controller was scraped successfully.
+	controllerMetricsUpName = "jimm_controller_metrics_up"
+)
+
+// ScrapeControllerMetrics scrapes the metrics endpoint of every
+// available controller and returns the metrics whose names start with
+// one of the given prefixes, labelled with the name of the controller
+// they came from and any configured controller metric labels, see
+// MetricLabeler. Metrics with the same name from different controllers
+// are returned in the same family. A jimm_controller_metrics_up gauge is
+// included for each controller scraped, which is 1 if the scrape
+// succeeded and 0 otherwise; controllers that cannot be scraped do not
+// cause the whole operation to fail.
+func (j *JIMM) ScrapeControllerMetrics(ctx context.Context, prefixes []string) ([]*dto.MetricFamily, error) {
+	const op = errors.Op("jimm.ScrapeControllerMetrics")
+
+	var controllers []dbmodel.Controller
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if !ctl.UnavailableSince.Valid {
+			controllers = append(controllers, *ctl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	scraped := make([]map[string]*dto.MetricFamily, len(controllers))
+	eg := new(errgroup.Group)
+	eg.SetLimit(controllerMetricsConcurrency)
+	for i := range controllers {
+		i := i
+		eg.Go(func() error {
+			mfs, err := j.scrapeController(ctx, &controllers[i])
+			if err != nil {
+				zapctx.Warn(ctx, "failed to scrape controller metrics", zap.String("controller", controllers[i].Name), zap.Error(err))
+				return nil
+			}
+			scraped[i] = mfs
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	up := &dto.MetricFamily{
+		Name: stringPtr(controllerMetricsUpName),
+		Help: stringPtr("Whether the controller's metrics were scraped successfully."),
+		Type: dto.MetricType_GAUGE.Enum(),
+	}
+	families := map[string]*dto.MetricFamily{controllerMetricsUpName: up}
+	labelNames := j.MetricLabels.ControllerLabelNames()
+	for i, mfs := range scraped {
+		labelValues := j.MetricLabels.ControllerLabelValues(&controllers[i])
+		value := 0.0
+		if mfs != nil {
+			value = 1
+		}
+		upMetric := &dto.Metric{
+			Label: []*dto.LabelPair{controllerLabelPair(controllers[i].Name)},
+			Gauge: &dto.Gauge{Value: &value},
+		}
+		for k, name := range labelNames {
+			setLabel(upMetric, name, labelValues[k])
+		}
+		up.Metric = append(up.Metric, upMetric)
+		for name, mf := range mfs {
+			if !hasAnyPrefix(name, prefixes) {
+				continue
+			}
+			for _, m := range mf.Metric {
+				setLabel(m, controllerLabel, controllers[i].Name)
+				for k, name := range labelNames {
+					setLabel(m, name, labelValues[k])
+				}
+			}
+			existing, ok := families[name]
+			if !ok {
+				families[name] = mf
+				continue
+			}
+			if existing.GetType() != mf.GetType() {
+				zapctx.Warn(ctx, "controller metric type mismatch", zap.String("controller", controllers[i].Name), zap.String("metric", name))
+				continue
+			}
+			existing.Metric = append(existing.Metric, mf.Metric...)
+		}
+	}
+
+	result := make([]*dto.MetricFamily, 0, len(families))
+	for _, mf := range families {
+		result = append(result, mf)
+	}
+	sort.Slice(result, func(i, k int) bool {
+		return result[i].GetName() < result[k].GetName()
+	})
+	return result, nil
+}
+
+// scrapeController fetches and parses the metrics from a single
+// controller.
+func (j *JIMM) scrapeController(ctx context.Context, ctl *dbmodel.Controller) (map[string]*dto.MetricFamily, error) {
+	ctx, cancel := context.WithTimeout(ctx, controllerMetricsTimeout)
+	defer cancel()
+
+	user, password := ctl.AdminIdentityName, ctl.AdminPassword
+	if password == "" {
+		var err error
+		user, password, err = j.CredentialStore.GetControllerCredentials(ctx, ctl.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if user == "" || password == "" {
+		return nil, errors.E("missing controller credentials")
+	}
+	header := jujuhttp.BasicAuthHeader(names.NewUserTag(user).String(), password)
+	resp, err := rpc.ControllerGet(ctx, ctl, controllerMetricsPath, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.E(fmt.Sprintf("unexpected status %s", resp.Status))
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+// setLabel sets the given label on the given metric, replacing any
+// existing label with the same name.
+func setLabel(m *dto.Metric, name, value string) {
+	for _, lp := range m.Label {
+		if lp.GetName() == name {
+			lp.Value = stringPtr(value)
+			return
+		}
+	}
+	m.Label = append(m.Label, &dto.LabelPair{
+		Name:  stringPtr(name),
+		Value: stringPtr(value),
+	})
+}
+
+func controllerLabelPair(controller string) *dto.LabelPair {
+	return &dto.LabelPair{
+		Name:  stringPtr(controllerLabel),
+		Value: stringPtr(controller),
+	}
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+const testControllerMetrics = `# HELP juju_mgo_txn_ops_total Total number of mgo txn operations.
+# TYPE juju_mgo_txn_ops_total counter
+juju_mgo_txn_ops_total{failed="false"} 42
+# HELP juju_apiserver_connections Number of active API connections.
+# TYPE juju_apiserver_connections gauge
+juju_apiserver_connections{endpoint="api"} 7
+# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 100
+`
+
+func TestScrapeControllerMetrics(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user-admin" || password != "5ecret" || r.URL.Path != "/introspection/metrics" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, testControllerMetrics)
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	c.Assert(err, qt.IsNil)
+	caCert := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}))
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		CredentialStore: jimmtest.NewInMemoryCredentialStore(),
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for _, ctl := range []dbmodel.Controller{{
+		Name:              "controller-1",
+		UUID:              "00000001-0000-0000-0000-000000000001",
+		AdminIdentityName: "admin",
+		AdminPassword:     "5ecret",
+		PublicAddress:     srvURL.Host,
+		CACertificate:     caCert,
+		CloudName:         "aws",
+		Tiers:             dbmodel.Strings{"production"},
+	}, {
+		Name:              "controller-2",
+		UUID:              "00000001-0000-0000-0000-000000000002",
+		AdminIdentityName: "admin",
+		AdminPassword:     "wrong",
+		PublicAddress:     srvURL.Host,
+		CACertificate:     caCert,
+	}, {
+		Name:              "controller-3",
+		UUID:              "00000001-0000-0000-0000-000000000003",
+		AdminIdentityName: "admin",
+		AdminPassword:     "5ecret",
+		PublicAddress:     srvURL.Host,
+		CACertificate:     caCert,
+		UnavailableSince:  sql.NullTime{Time: time.Now(), Valid: true},
+	}} {
+		ctl := ctl
+		err := j.Database.AddController(ctx, &ctl)
+		c.Assert(err, qt.IsNil)
+	}
+
+	mfs, err := j.ScrapeControllerMetrics(ctx, []string{"juju_mgo_", "juju_apiserver_connections"})
+	c.Assert(err, qt.IsNil)
+
+	got := make(map[string][]string)
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			got[mf.GetName()] = append(got[mf.GetName()], metricString(m))
+		}
+	}
+	c.Check(got, qt.DeepEquals, map[string][]string{
+		"jimm_controller_metrics_up": {
+			"controller=controller-1 1",
+			"controller=controller-2 0",
+		},
+		"juju_apiserver_connections": {
+			"endpoint=api,controller=controller-1 7",
+		},
+		"juju_mgo_txn_ops_total": {
+			"failed=false,controller=controller-1 42",
+		},
+	})
+
+	// The configured controller labels are added to every metric.
+	j.MetricLabels, err = jimm.NewMetricLabeler([]string{"tier", "cloud"}, nil, 0)
+	c.Assert(err, qt.IsNil)
+	mfs, err = j.ScrapeControllerMetrics(ctx, []string{"juju_apiserver_connections"})
+	c.Assert(err, qt.IsNil)
+	got = make(map[string][]string)
+	for _, mf := range mfs {
+		for _, m := range mf.Metric {
+			got[mf.GetName()] = append(got[mf.GetName()], metricString(m))
+		}
+	}
+	c.Check(got, qt.DeepEquals, map[string][]string{
+		"jimm_controller_metrics_up": {
+			"controller=controller-1,controller_cloud=aws,controller_tier=production 1",
+			"controller=controller-2,controller_cloud=,controller_tier= 0",
+		},
+		"juju_apiserver_connections": {
+			"endpoint=api,controller=controller-1,controller_cloud=aws,controller_tier=production 7",
+		},
+	})
+}
+
+// metricString returns a compact representation of the labels and value
+// of the given metric.
+func metricString(m *dto.Metric) string {
+	var s string
+	for i, lp := range m.Label {
+		if i > 0 {
+			s += ","
+		}
+		s += lp.GetName() + "=" + lp.GetValue()
+	}
+	var v float64
+	switch {
+	case m.Gauge != nil:
+		v = m.Gauge.GetValue()
+	case m.Counter != nil:
+		v = m.Counter.GetValue()
+	}
+	return fmt.Sprintf("%s %g", s, v)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/names/v5"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// pingControllersConcurrency is the maximum number of controllers
+	// probed at the same time by PingControllers.
+	pingControllersConcurrency = 10
+
+	// pingControllerTimeout is the time allowed to connect to and probe
+	// a single controller.
+	pingControllerTimeout = 30 * time.Second
+
+	// requiredModelManagerVersion is the version of the ModelManager
+	// facade JIMM uses on the controllers.
+	requiredModelManagerVersion = 9
+)
+
+// PingControllers probes every controller known to JIMM, a limited number
+// at a time, and reports how long each took to respond. Probing a
+// controller logs in to it, pings the connection and checks that the
+// controller supports the version of the ModelManager facade JIMM uses.
+// Controllers that cannot be probed are reported with the error
+// encountered, rather than failing the whole operation. Only JIMM
+// administrators can perform this operation.
+func (j *JIMM) PingControllers(ctx context.Context, user *openfga.User) (apiparams.PingControllersResponse, error) {
+	const op = errors.Op("jimm.PingControllers")
+
+	if !user.JimmAdmin {
+		return apiparams.PingControllersResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	controllers, err := j.selectControllers(ctx, "")
+	if err != nil {
+		return apiparams.PingControllersResponse{}, errors.E(op, err)
+	}
+
+	results := make([]apiparams.ControllerPingResult, len(controllers))
+	eg := new(errgroup.Group)
+	eg.SetLimit(pingControllersConcurrency)
+	for i := range controllers {
+		i := i
+		eg.Go(func() error {
+			results[i] = j.pingController(ctx, &controllers[i])
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Controller < results[j].Controller
+	})
+	return apiparams.PingControllersResponse{Results: results}, nil
+}
+
+// pingController probes a single controller, see PingControllers.
+func (j *JIMM) pingController(ctx context.Context, ctl *dbmodel.Controller) apiparams.ControllerPingResult {
+	ctx, cancel := context.WithTimeout(ctx, pingControllerTimeout)
+	defer cancel()
+
+	result := apiparams.ControllerPingResult{
+		Controller: ctl.Name,
+	}
+	start := time.Now()
+	err := j.probeController(ctx, ctl)
+	result.LatencyMS = time.Since(start).Milliseconds()
+	result.AgentVersion = ctl.AgentVersion
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Reachable = true
+	return result
+}
+
+func (j *JIMM) probeController(ctx context.Context, ctl *dbmodel.Controller) error {
+	api, err := j.dial(ctx, ctl, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	if err := api.Ping(ctx); err != nil {
+		return err
+	}
+	return checkRequiredFacades(api)
+}
+
+// checkRequiredFacades checks that the controller connected to with the
+// given API supports the facade versions JIMM requires.
+func checkRequiredFacades(api API) error {
+	facades := api.SupportedFacadeVersions()
+	if facades == nil {
+		// The supported facades were not reported by the login.
+		return nil
+	}
+	for _, v := range facades["ModelManager"] {
+		if v == requiredModelManagerVersion {
+			return nil
+		}
+	}
+	return errors.E(fmt.Sprintf("controller does not support ModelManager version %d (supported versions %v)", requiredModelManagerVersion, facades["ModelManager"]))
+}
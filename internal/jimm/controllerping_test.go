@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const pingControllersTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+controllers:
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test-cloud
+  region: test-cloud-region
+`
+
+func TestPingControllers(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: jimmtest.DialerMap{
+			"controller-1": &jimmtest.Dialer{
+				AgentVersion: "3.5.0",
+				API: &jimmtest.API{
+					SupportedFacadeVersions_: map[string][]int{"ModelManager": {8, 9}},
+				},
+			},
+			"controller-2": &jimmtest.Dialer{
+				AgentVersion: "2.9.0",
+				API: &jimmtest.API{
+					SupportedFacadeVersions_: map[string][]int{"ModelManager": {8}},
+				},
+			},
+			"controller-3": &jimmtest.Dialer{
+				Err: errors.E("connection refused"),
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, pingControllersTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.PingControllers(ctx, openfga.NewUser(bob, client))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+	resp, err := j.PingControllers(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	for i := range resp.Results {
+		resp.Results[i].LatencyMS = 0
+	}
+	c.Check(resp, qt.DeepEquals, apiparams.PingControllersResponse{
+		Results: []apiparams.ControllerPingResult{{
+			Controller:   "controller-1",
+			Reachable:    true,
+			AgentVersion: "3.5.0",
+		}, {
+			Controller:   "controller-2",
+			AgentVersion: "2.9.0",
+			Error:        "controller does not support ModelManager version 9 (supported versions [8])",
+		}, {
+			Controller: "controller-3",
+			Error:      "connection refused",
+		}},
+	})
+}
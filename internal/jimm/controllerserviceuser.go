@@ -0,0 +1,113 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// controllerServiceUserAccess is the controller access granted to the
+// service user JIMM provisions on a controller. This is not least
+// privilege, and least privilege is not achievable with Juju's access
+// levels: Juju has no controller access level between add-model and
+// superuser, and JIMM needs to watch every model and manage the clouds
+// and credentials of the controller, which only a superuser may do. The
+// service user has the same access as the controller's admin account,
+// what it provides is an identity used by JIMM alone, with a password
+// only JIMM knows, that can be rotated or removed without affecting the
+// controller's operators.
+const controllerServiceUserAccess = "superuser"
+
+// controllerServiceUserName returns the name of the service user this
+// JIMM provisions on the controllers it manages.
+func (j *JIMM) controllerServiceUserName() string {
+	return "jimm-" + j.UUID
+}
+
+// ProvisionControllerServiceUser provisions the JIMM service user on the
+// given controller, using the credentials JIMM currently holds for it,
+// and stores the service user's credentials in place of them. All
+// subsequent connections JIMM makes to the controller use the service
+// user. This is the upgrade path for controllers added before JIMM
+// provisioned service users, it may also be used to rotate the service
+// user's password, and clears the controller's ServiceUserPending flag.
+// Only JIMM administrators can provision service users.
+func (j *JIMM) ProvisionControllerServiceUser(ctx context.Context, user *openfga.User, controllerName string) (apiparams.ProvisionControllerServiceUserResponse, error) {
+	const op = errors.Op("jimm.ProvisionControllerServiceUser")
+	defer j.Cache.InvalidateControllers()
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(op, err)
+	}
+	if j.CredentialStore == nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(op, errors.CodeNotSupported, "no credential store configured")
+	}
+
+	ctl := dbmodel.Controller{Name: controllerName}
+	if err := j.Database.GetController(ctx, &ctl); err != nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(op, err)
+	}
+	api, err := j.dialController(ctx, &ctl)
+	if err != nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(op, "failed to dial the controller", err)
+	}
+	defer api.Close()
+
+	username, err := j.provisionControllerServiceUser(ctx, api, &ctl)
+	if err != nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(op, err)
+	}
+	return apiparams.ProvisionControllerServiceUserResponse{
+		Controller: ctl.Name,
+		Username:   username,
+	}, nil
+}
+
+// provisionControllerServiceUser creates the JIMM service user on the
+// controller connected to by the given API, or resets its password if it
+// already exists, grants it the access JIMM needs and stores its
+// credentials for the controller. If the controller is marked as waiting
+// for its service user the mark is cleared. The name of the service user
+// is returned.
+func (j *JIMM) provisionControllerServiceUser(ctx context.Context, api API, ctl *dbmodel.Controller) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	username := j.controllerServiceUserName()
+	password := base64.RawURLEncoding.EncodeToString(buf)
+
+	err := api.AddUser(ctx, username, password)
+	if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+		err = api.SetUserPassword(ctx, username, password)
+	}
+	if err != nil {
+		return "", errors.E(err, "failed to create service user")
+	}
+	err = api.GrantControllerAccess(ctx, names.NewUserTag(username), controllerServiceUserAccess)
+	if err != nil && errors.ErrorCode(err) != errors.CodeAlreadyExists {
+		return "", errors.E(err, "failed to grant service user access")
+	}
+	if err := j.CredentialStore.PutControllerCredentials(ctx, ctl.Name, username, password); err != nil {
+		return "", errors.E(err, "failed to store controller credentials")
+	}
+	if ctl.ServiceUserPending {
+		ctl.ServiceUserPending = false
+		if err := j.Database.UpdateController(ctx, ctl); err != nil {
+			return "", errors.E(err, "failed to clear pending controller service user")
+		}
+	}
+	zapctx.Info(ctx, "provisioned controller service user", zap.String("controller", ctl.Name), zap.String("user", username))
+	return username, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const controllerServiceUserTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+`
+
+// serviceUserAPI is a jimmtest.API that records the local users created
+// on a controller, and the controller access granted to them.
+type serviceUserAPI struct {
+	mu        sync.Mutex
+	passwords map[string]string
+	access    map[string]string
+	grantErr  error
+}
+
+func (s *serviceUserAPI) api() *jimmtest.API {
+	return &jimmtest.API{
+		AddUser_: func(_ context.Context, username, password string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := s.passwords[username]; ok {
+				return errors.E(errors.CodeAlreadyExists, "user already exists")
+			}
+			s.passwords[username] = password
+			return nil
+		},
+		SetUserPassword_: func(_ context.Context, username, password string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if _, ok := s.passwords[username]; !ok {
+				return errors.E(errors.CodeNotFound, "user not found")
+			}
+			s.passwords[username] = password
+			return nil
+		},
+		GrantControllerAccess_: func(_ context.Context, user names.UserTag, access string) error {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.grantErr != nil {
+				return s.grantErr
+			}
+			s.access[user.Id()] = access
+			return nil
+		},
+		ControllerModelSummary_: func(_ context.Context, ms *jujuparams.ModelSummary) error {
+			ms.Name = "controller"
+			ms.UUID = "00000002-0000-0000-0000-000000000001"
+			ms.ControllerUUID = "00000001-0000-0000-0000-000000000002"
+			ms.IsController = true
+			ms.CloudTag = "cloud-test-cloud"
+			ms.CloudRegion = "test-cloud-region"
+			return nil
+		},
+		Clouds_: func(context.Context) (map[names.CloudTag]jujuparams.Cloud, error) {
+			return map[names.CloudTag]jujuparams.Cloud{
+				names.NewCloudTag("test-cloud"): {
+					Type:      "test-provider",
+					AuthTypes: []string{"empty"},
+					Regions: []jujuparams.CloudRegion{{
+						Name: "test-cloud-region",
+					}},
+				},
+			}, nil
+		},
+	}
+}
+
+func TestControllerServiceUser(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	su := &serviceUserAPI{
+		passwords: make(map[string]string),
+		access:    make(map[string]string),
+	}
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: su.api(),
+		},
+		CredentialStore:        store,
+		ControllerServiceUsers: true,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, controllerServiceUserTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+	err = store.PutControllerCredentials(ctx, "controller-1", "admin", "password")
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	alice.JimmAdmin = true
+	bobIdentity, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	bob := openfga.NewUser(bobIdentity, client)
+
+	serviceUser := "jimm-" + j.UUID
+
+	_, err = j.ProvisionControllerServiceUser(ctx, bob, "controller-1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.ProvisionControllerServiceUser(ctx, alice, "no-such-controller")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// An existing registration is upgraded to use the service user.
+	resp, err := j.ProvisionControllerServiceUser(ctx, alice, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Controller, qt.Equals, "controller-1")
+	c.Check(resp.Username, qt.Equals, serviceUser)
+	c.Check(su.access[serviceUser], qt.Equals, "superuser")
+	u, p, err := store.GetControllerCredentials(ctx, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(u, qt.Equals, serviceUser)
+	c.Check(p, qt.Equals, su.passwords[serviceUser])
+	c.Check(p, qt.Not(qt.Equals), "password")
+
+	// Provisioning again resets the password of the existing user.
+	_, err = j.ProvisionControllerServiceUser(ctx, alice, "controller-1")
+	c.Assert(err, qt.IsNil)
+	u, p2, err := store.GetControllerCredentials(ctx, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(u, qt.Equals, serviceUser)
+	c.Check(p2, qt.Equals, su.passwords[serviceUser])
+	c.Check(p2, qt.Not(qt.Equals), p)
+
+	// A new controller is added with the service user provisioned.
+	delete(su.passwords, serviceUser)
+	ctl := dbmodel.Controller{
+		Name:              "controller-2",
+		UUID:              "00000001-0000-0000-0000-000000000002",
+		AdminIdentityName: "admin",
+		AdminPassword:     "password",
+		PublicAddress:     "example.com:443",
+	}
+	err = j.AddController(ctx, alice, &ctl)
+	c.Assert(err, qt.IsNil)
+	u, p, err = store.GetControllerCredentials(ctx, "controller-2")
+	c.Assert(err, qt.IsNil)
+	c.Check(u, qt.Equals, serviceUser)
+	c.Check(p, qt.Equals, su.passwords[serviceUser])
+	c.Check(ctl.ServiceUserPending, qt.IsFalse)
+
+	// A controller on which the service user cannot be provisioned is
+	// added using the given credentials and marked as pending.
+	j.AllowDuplicateControllerUUIDs = true
+	su.grantErr = errors.E("permission denied")
+	ctl = dbmodel.Controller{
+		Name:              "controller-3",
+		UUID:              "00000001-0000-0000-0000-000000000002",
+		AdminIdentityName: "admin",
+		AdminPassword:     "password",
+		PublicAddress:     "example.com:443",
+	}
+	err = j.AddController(ctx, alice, &ctl)
+	c.Assert(err, qt.IsNil)
+	u, p, err = store.GetControllerCredentials(ctx, "controller-3")
+	c.Assert(err, qt.IsNil)
+	c.Check(u, qt.Equals, "admin")
+	c.Check(p, qt.Equals, "password")
+	ctl = dbmodel.Controller{Name: "controller-3"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.ServiceUserPending, qt.IsTrue)
+	c.Check(ctl.ToAPIControllerInfo().ServiceUserPending, qt.IsTrue)
+
+	su.grantErr = nil
+	_, err = j.ProvisionControllerServiceUser(ctx, alice, "controller-3")
+	c.Assert(err, qt.IsNil)
+	ctl = dbmodel.Controller{Name: "controller-3"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.ServiceUserPending, qt.IsFalse)
+	u, _, err = store.GetControllerCredentials(ctx, "controller-3")
+	c.Assert(err, qt.IsNil)
+	c.Check(u, qt.Equals, serviceUser)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"net"
+	"strings"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+)
+
+// controllerUnavailableReason categorises the given error, encountered
+// connecting to a controller, as the reason the controller is
+// unavailable.
+func controllerUnavailableReason(err error) dbmodel.UnavailableReason {
+	if err == nil {
+		return ""
+	}
+	var (
+		verificationErr *tls.CertificateVerificationError
+		recordHeaderErr tls.RecordHeaderError
+		authorityErr    x509.UnknownAuthorityError
+		hostnameErr     x509.HostnameError
+		invalidErr      x509.CertificateInvalidError
+		netErr          net.Error
+	)
+	switch {
+	case stderrors.As(err, &verificationErr),
+		stderrors.As(err, &recordHeaderErr),
+		stderrors.As(err, &authorityErr),
+		stderrors.As(err, &hostnameErr),
+		stderrors.As(err, &invalidErr):
+		return dbmodel.UnavailableTLSError
+	case stderrors.Is(err, context.DeadlineExceeded),
+		stderrors.As(err, &netErr) && netErr.Timeout():
+		return dbmodel.UnavailableDialTimeout
+	}
+	for e := err; e != nil; e = stderrors.Unwrap(e) {
+		switch jujuparams.ErrCode(e) {
+		case jujuparams.CodeUnauthorized, jujuparams.CodeNoCreds:
+			return dbmodel.UnavailableAuthFailure
+		case jujuparams.CodeNotSupported, jujuparams.CodeNotImplemented:
+			return dbmodel.UnavailableAPIIncompatible
+		}
+	}
+
+	// Errors returned by the controller lose their type, so fall back
+	// to their messages.
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "x509:"), strings.Contains(msg, "tls:"):
+		return dbmodel.UnavailableTLSError
+	case strings.Contains(msg, "timeout"), strings.Contains(msg, "deadline exceeded"):
+		return dbmodel.UnavailableDialTimeout
+	case strings.Contains(msg, "authentication failed"),
+		strings.Contains(msg, "invalid entity name or password"),
+		strings.Contains(msg, "permission denied"):
+		return dbmodel.UnavailableAuthFailure
+	case strings.Contains(msg, "not supported"), strings.Contains(msg, "incompatible"):
+		return dbmodel.UnavailableAPIIncompatible
+	}
+	return dbmodel.UnavailableUnknown
+}
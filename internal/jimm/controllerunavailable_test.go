@@ -0,0 +1,54 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+func TestControllerUnavailableReason(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		err          error
+		expectReason dbmodel.UnavailableReason
+	}{{
+		err:          errors.E(errors.CodeConnectionFailed, fmt.Errorf("dial: %w", context.DeadlineExceeded)),
+		expectReason: dbmodel.UnavailableDialTimeout,
+	}, {
+		err:          errors.E("dial tcp 10.0.0.1:17070: i/o timeout"),
+		expectReason: dbmodel.UnavailableDialTimeout,
+	}, {
+		err:          errors.E(errors.CodeConnectionFailed, fmt.Errorf("dial: %w", x509.UnknownAuthorityError{})),
+		expectReason: dbmodel.UnavailableTLSError,
+	}, {
+		err:          errors.E("x509: certificate has expired or is not yet valid"),
+		expectReason: dbmodel.UnavailableTLSError,
+	}, {
+		err:          errors.E(errors.CodeConnectionFailed, "authentication failed", &jujuparams.Error{Code: jujuparams.CodeUnauthorized, Message: "invalid token"}),
+		expectReason: dbmodel.UnavailableAuthFailure,
+	}, {
+		err:          errors.E(errors.CodeConnectionFailed, "authentication failed"),
+		expectReason: dbmodel.UnavailableAuthFailure,
+	}, {
+		err:          errors.E(&jujuparams.Error{Code: jujuparams.CodeNotSupported, Message: "facade not supported"}),
+		expectReason: dbmodel.UnavailableAPIIncompatible,
+	}, {
+		err:          errors.E("connection refused"),
+		expectReason: dbmodel.UnavailableUnknown,
+	}}
+	for _, test := range tests {
+		c.Check(jimm.ControllerUnavailableReason(test.err), qt.Equals, test.expectReason, qt.Commentf("%v", test.err))
+	}
+	c.Check(jimm.ControllerUnavailableReason(nil), qt.Equals, dbmodel.UnavailableReason(""))
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+// maxCostCenterLength is the maximum length of a cost center.
+const maxCostCenterLength = 100
+
+// Sources of the cost center a model is attributed to in usage reports.
+const (
+	costCenterSourceModel = "model"
+	costCenterSourceUser  = "user"
+	costCenterSourceGroup = "group"
+)
+
+// SetCostCenter attaches the given cost center to the user, group or
+// model identified by the given tag. An empty cost center removes any
+// cost center attached to the entity. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) SetCostCenter(ctx context.Context, user *openfga.User, entity, costCenter string) error {
+	const op = errors.Op("jimm.SetCostCenter")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	costCenter = strings.TrimSpace(costCenter)
+	if len(costCenter) > maxCostCenterLength {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("cost center longer than %d characters", maxCostCenterLength))
+	}
+	tag, err := j.parseAndValidateTag(ctx, entity)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if tag.Relation != "" {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid entity %q", entity))
+	}
+
+	switch tag.Kind {
+	case names.UserTagKind:
+		identity, err := dbmodel.NewIdentity(tag.ID)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		if err := j.Database.FetchIdentity(ctx, identity); err != nil {
+			return errors.E(op, err)
+		}
+		identity.CostCenter = costCenter
+		if err := j.Database.UpdateIdentity(ctx, identity); err != nil {
+			return errors.E(op, err)
+		}
+	case jimmnames.GroupTagKind:
+		group := dbmodel.GroupEntry{UUID: tag.ID}
+		if err := j.Database.GetGroup(ctx, &group); err != nil {
+			return errors.E(op, err)
+		}
+		group.CostCenter = costCenter
+		if err := j.Database.UpdateGroup(ctx, &group); err != nil {
+			return errors.E(op, err)
+		}
+	case names.ModelTagKind:
+		model := dbmodel.Model{UUID: sql.NullString{String: tag.ID, Valid: true}}
+		if err := j.Database.GetModel(ctx, &model); err != nil {
+			return errors.E(op, err)
+		}
+		model.CostCenter = costCenter
+		if err := j.Database.UpdateModel(ctx, &model); err != nil {
+			return errors.E(op, err)
+		}
+	default:
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("cannot set cost center on %s", tag.Kind))
+	}
+	return nil
+}
+
+// UsageReport reports the resources used by every model, and the totals
+// for each cost center. Each model is attributed to its own cost center
+// if it has one, otherwise to its owner's cost center, otherwise to the
+// cost center of a group its owner is a direct member of. If the owner
+// is a member of several groups with cost centers the group with the
+// name that sorts first is used. Only JIMM administrators can perform
+// this operation.
+func (j *JIMM) UsageReport(ctx context.Context, user *openfga.User) (apiparams.UsageReport, error) {
+	const op = errors.Op("jimm.UsageReport")
+
+	if !user.JimmAdmin {
+		return apiparams.UsageReport{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	groupCostCenters, err := j.groupCostCenters(ctx)
+	if err != nil {
+		return apiparams.UsageReport{}, errors.E(op, err)
+	}
+
+	report := apiparams.UsageReport{
+		Time:        time.Now().UTC(),
+		Models:      []apiparams.ModelUsageReport{},
+		CostCenters: []apiparams.CostCenterUsage{},
+	}
+	totals := make(map[string]*apiparams.CostCenterUsage)
+	err = j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		mu := apiparams.ModelUsageReport{
+			ModelTag:   m.ResourceTag().String(),
+			Name:       m.Name,
+			Owner:      m.OwnerIdentityName,
+			Controller: m.Controller.Name,
+			Cloud:      m.CloudRegion.Cloud.Name,
+			Region:     m.CloudRegion.Name,
+			Machines:   m.Machines,
+			Cores:      m.Cores,
+			Units:      m.Units,
+		}
+		mu.CostCenter, mu.CostCenterSource = modelCostCenter(m, groupCostCenters)
+		report.Models = append(report.Models, mu)
+
+		total := totals[mu.CostCenter]
+		if total == nil {
+			total = &apiparams.CostCenterUsage{CostCenter: mu.CostCenter}
+			totals[mu.CostCenter] = total
+		}
+		total.Models++
+		total.Machines += mu.Machines
+		total.Cores += mu.Cores
+		total.Units += mu.Units
+		return nil
+	})
+	if err != nil {
+		return apiparams.UsageReport{}, errors.E(op, err)
+	}
+
+	sort.Slice(report.Models, func(i, j int) bool {
+		if report.Models[i].Owner != report.Models[j].Owner {
+			return report.Models[i].Owner < report.Models[j].Owner
+		}
+		return report.Models[i].Name < report.Models[j].Name
+	})
+	for _, total := range totals {
+		report.CostCenters = append(report.CostCenters, *total)
+	}
+	sort.Slice(report.CostCenters, func(i, j int) bool {
+		return report.CostCenters[i].CostCenter < report.CostCenters[j].CostCenter
+	})
+	return report, nil
+}
+
+// modelCostCenter returns the cost center the given model is attributed
+// to, and the source of the cost center, as described in UsageReport.
+// The groupCostCenters are those returned by groupCostCenters.
+func modelCostCenter(m *dbmodel.Model, groupCostCenters map[string]string) (costCenter, source string) {
+	switch {
+	case m.CostCenter != "":
+		return m.CostCenter, costCenterSourceModel
+	case m.Owner.CostCenter != "":
+		return m.Owner.CostCenter, costCenterSourceUser
+	case groupCostCenters[m.OwnerIdentityName] != "":
+		return groupCostCenters[m.OwnerIdentityName], costCenterSourceGroup
+	}
+	return "", ""
+}
+
+// groupCostCenters returns the cost center of each user that is a direct
+// member of a group with a cost center, keyed by user name.
+func (j *JIMM) groupCostCenters(ctx context.Context) (map[string]string, error) {
+	groups, err := j.Database.GetCostCenterGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	costCenters := make(map[string]string)
+	// The groups are ordered by name, so the first group found for a
+	// user takes precedence.
+	for _, g := range groups {
+		users, err := j.listGroupUsers(ctx, g.ResourceTag())
+		if err != nil {
+			return nil, err
+		}
+		for u := range users {
+			if _, ok := costCenters[u]; !ok {
+				costCenters[u] = g.CostCenter
+			}
+		}
+	}
+	return costCenters, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const costCenterTestEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+- name: test-cred
+  cloud: test
+  owner: charlie@canonical.com
+  type: empty
+- name: test-cred
+  cloud: test
+  owner: dave@canonical.com
+  type: empty
+controllers:
+- name: test
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  machines: 2
+  cores: 4
+  units: 3
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  machines: 1
+  cores: 8
+  units: 1
+- name: model-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  owner: charlie@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  machines: 1
+  cores: 2
+  units: 2
+- name: model-4
+  uuid: 00000002-0000-0000-0000-000000000004
+  owner: dave@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  machines: 5
+  cores: 10
+  units: 5
+users:
+- username: bob@canonical.com
+  controller-access: superuser
+`
+
+func TestCostCenters(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, costCenterTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	bob.JimmAdmin = true
+
+	// charlie is a member of two groups with cost centers, the first
+	// by name is used.
+	for _, name := range []string{"research", "engineering"} {
+		_, err := j.AddGroup(ctx, bob, name)
+		c.Assert(err, qt.IsNil)
+		err = j.SetCostCenter(ctx, bob, "group-"+name, "CC-"+name)
+		c.Assert(err, qt.IsNil)
+		ge := dbmodel.GroupEntry{Name: name}
+		err = j.Database.GetGroup(ctx, &ge)
+		c.Assert(err, qt.IsNil)
+		c.Check(ge.CostCenter, qt.Equals, "CC-"+name)
+		err = client.AddRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(ge.ResourceTag()),
+		})
+		c.Assert(err, qt.IsNil)
+	}
+	err = j.SetCostCenter(ctx, bob, "user-alice@canonical.com", " CC-alice ")
+	c.Assert(err, qt.IsNil)
+	err = j.SetCostCenter(ctx, bob, "model-alice@canonical.com/model-2", "CC-model")
+	c.Assert(err, qt.IsNil)
+
+	report, err := j.UsageReport(ctx, bob)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Time.IsZero(), qt.IsFalse)
+	c.Check(report.Models, qt.DeepEquals, []apiparams.ModelUsageReport{{
+		ModelTag:         "model-00000002-0000-0000-0000-000000000001",
+		Name:             "model-1",
+		Owner:            "alice@canonical.com",
+		Controller:       "test",
+		Cloud:            "test",
+		Region:           "test-region",
+		Machines:         2,
+		Cores:            4,
+		Units:            3,
+		CostCenter:       "CC-alice",
+		CostCenterSource: "user",
+	}, {
+		ModelTag:         "model-00000002-0000-0000-0000-000000000002",
+		Name:             "model-2",
+		Owner:            "alice@canonical.com",
+		Controller:       "test",
+		Cloud:            "test",
+		Region:           "test-region",
+		Machines:         1,
+		Cores:            8,
+		Units:            1,
+		CostCenter:       "CC-model",
+		CostCenterSource: "model",
+	}, {
+		ModelTag:         "model-00000002-0000-0000-0000-000000000003",
+		Name:             "model-3",
+		Owner:            "charlie@canonical.com",
+		Controller:       "test",
+		Cloud:            "test",
+		Region:           "test-region",
+		Machines:         1,
+		Cores:            2,
+		Units:            2,
+		CostCenter:       "CC-engineering",
+		CostCenterSource: "group",
+	}, {
+		ModelTag:   "model-00000002-0000-0000-0000-000000000004",
+		Name:       "model-4",
+		Owner:      "dave@canonical.com",
+		Controller: "test",
+		Cloud:      "test",
+		Region:     "test-region",
+		Machines:   5,
+		Cores:      10,
+		Units:      5,
+	}})
+	c.Check(report.CostCenters, qt.DeepEquals, []apiparams.CostCenterUsage{
+		{CostCenter: "", Models: 1, Machines: 5, Cores: 10, Units: 5},
+		{CostCenter: "CC-alice", Models: 1, Machines: 2, Cores: 4, Units: 3},
+		{CostCenter: "CC-engineering", Models: 1, Machines: 1, Cores: 2, Units: 2},
+		{CostCenter: "CC-model", Models: 1, Machines: 1, Cores: 8, Units: 1},
+	})
+
+	// Removing the model's cost center attributes it to its owner.
+	err = j.SetCostCenter(ctx, bob, "model-alice@canonical.com/model-2", "")
+	c.Assert(err, qt.IsNil)
+	report, err = j.UsageReport(ctx, bob)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Models[1].CostCenter, qt.Equals, "CC-alice")
+	c.Check(report.Models[1].CostCenterSource, qt.Equals, "user")
+}
+
+func TestCostCentersErrors(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, costCenterTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	bob.JimmAdmin = true
+
+	err = j.SetCostCenter(ctx, alice, "user-alice@canonical.com", "CC-1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.UsageReport(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.SetCostCenter(ctx, bob, "cloud-test", "CC-1")
+	c.Check(err, qt.ErrorMatches, `cannot set cost center on cloud`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	err = j.SetCostCenter(ctx, bob, "user-eve@canonical.com", "CC-1")
+	c.Check(err, qt.Not(qt.IsNil))
+	err = j.SetCostCenter(ctx, bob, "group-no-such-group", "CC-1")
+	c.Check(err, qt.Not(qt.IsNil))
+	longCostCenter := make([]byte, 101)
+	for i := range longCostCenter {
+		longCostCenter[i] = 'x'
+	}
+	err = j.SetCostCenter(ctx, bob, "user-alice@canonical.com", string(longCostCenter))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const (
+	// DefaultCredentialUpdateConcurrency is the number of controllers a
+	// cloud credential is updated on at once if
+	// CredentialUpdateConcurrency is not set.
+	DefaultCredentialUpdateConcurrency = 10
+
+	// credentialRetryBatchSize is the maximum number of queued
+	// credential updates retried by each call to RetryCredentialUpdates.
+	credentialRetryBatchSize = 100
+
+	// credentialRetryMaxBackoff is the longest time a failed credential
+	// update waits before it is retried.
+	credentialRetryMaxBackoff = time.Hour
+)
+
+// ControllerFailures holds the errors returned by the controllers a
+// credential operation failed on, keyed by controller name.
+type ControllerFailures map[string]error
+
+// Error implements the error interface. Every failure is reported, in
+// controller name order.
+func (f ControllerFailures) Error() string {
+	controllers := make([]string, 0, len(f))
+	for name := range f {
+		controllers = append(controllers, name)
+	}
+	sort.Strings(controllers)
+	msgs := make([]string, len(controllers))
+	for i, name := range controllers {
+		msgs[i] = "controller " + name + ": " + f[name].Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// credentialFanOut calls the given function on every one of the given
+// controllers, using at most CredentialUpdateConcurrency connections at
+// once. Unlike forEachController every controller is attempted, even
+// after a failure, and the failures are returned for each controller.
+func (j *JIMM) credentialFanOut(ctx context.Context, controllers []dbmodel.Controller, f func(*dbmodel.Controller, API) error) ControllerFailures {
+	limit := j.CredentialUpdateConcurrency
+	if limit <= 0 {
+		limit = DefaultCredentialUpdateConcurrency
+	}
+	var mu sync.Mutex
+	failures := make(ControllerFailures)
+	eg := new(errgroup.Group)
+	eg.SetLimit(limit)
+	for i := range controllers {
+		ctl := &controllers[i]
+		eg.Go(func() error {
+			err := j.callController(ctx, ctl, f)
+			if err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				failures[ctl.Name] = err
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	if len(failures) == 0 {
+		return nil
+	}
+	return failures
+}
+
+// callController connects to the given controller and calls the given
+// function with the connection.
+func (j *JIMM) callController(ctx context.Context, ctl *dbmodel.Controller, f func(*dbmodel.Controller, API) error) error {
+	api, err := j.dial(ctx, ctl, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	return f(ctl, api)
+}
+
+// queueCredentialUpdateRetry queues the update of the given credential
+// on the given controller to be retried after it failed with the given
+// error.
+func (j *JIMM) queueCredentialUpdateRetry(ctx context.Context, cred *dbmodel.CloudCredential, ctl *dbmodel.Controller, err error) {
+	r := dbmodel.CredentialUpdateRetry{
+		CloudCredentialID: cred.ID,
+		ControllerID:      ctl.ID,
+	}
+	if err := j.Database.GetCredentialUpdateRetry(ctx, &r); err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+		zapctx.Error(ctx, "cannot get credential update retry", zap.Error(err))
+	}
+	r.Attempts++
+	r.LastError = err.Error()
+	r.NextAttempt = time.Now().Add(credentialRetryBackoff(r.Attempts))
+	if err := j.Database.SetCredentialUpdateRetry(ctx, &r); err != nil {
+		zapctx.Error(ctx, "cannot queue credential update retry", zap.String("credential", cred.Tag().String()), zap.String("controller", ctl.Name), zap.Error(err))
+	}
+}
+
+// clearCredentialUpdateRetry removes any queued retry of the update of
+// the given credential on the given controller.
+func (j *JIMM) clearCredentialUpdateRetry(ctx context.Context, cred *dbmodel.CloudCredential, ctl *dbmodel.Controller) {
+	r := dbmodel.CredentialUpdateRetry{
+		CloudCredentialID: cred.ID,
+		ControllerID:      ctl.ID,
+	}
+	if err := j.Database.DeleteCredentialUpdateRetry(ctx, &r); err != nil {
+		zapctx.Error(ctx, "cannot remove credential update retry", zap.String("credential", cred.Tag().String()), zap.String("controller", ctl.Name), zap.Error(err))
+	}
+}
+
+// credentialRetryBackoff returns the time to wait before retrying a
+// credential update that has failed the given number of times. The wait
+// doubles with every attempt, starting at a minute, up to
+// credentialRetryMaxBackoff.
+func credentialRetryBackoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < credentialRetryMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > credentialRetryMaxBackoff {
+		d = credentialRetryMaxBackoff
+	}
+	return d
+}
+
+// RetryCredentialUpdates retries the queued credential updates that are
+// due. Updates that succeed are removed from the queue, those that fail
+// again are rescheduled with a longer wait.
+func (j *JIMM) RetryCredentialUpdates(ctx context.Context) error {
+	const op = errors.Op("jimm.RetryCredentialUpdates")
+
+	retries, err := j.Database.ListDueCredentialUpdateRetries(ctx, time.Now(), credentialRetryBatchSize)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for i := range retries {
+		r := &retries[i]
+		err := j.callController(ctx, &r.Controller, func(ctl *dbmodel.Controller, api API) error {
+			_, err := j.propagateCloudCredential(ctx, &r.CloudCredential, ctl, api)
+			return err
+		})
+		j.recordCredentialUpdate(ctx, &r.CloudCredential, &r.Controller, err)
+		if err == nil {
+			j.clearCredentialUpdateRetry(ctx, &r.CloudCredential, &r.Controller)
+			continue
+		}
+		zapctx.Warn(ctx, "credential update retry failed", zap.String("credential", r.CloudCredential.Tag().String()), zap.String("controller", r.Controller.Name), zap.Error(err))
+		j.queueCredentialUpdateRetry(ctx, &r.CloudCredential, &r.Controller, err)
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const credentialFanOutTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-2
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  controller: controller-3
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+`
+
+func TestUpdateCloudCredentialPartialFailure(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	updateCredential := func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+		return []jujuparams.UpdateCredentialModelResult{{
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			ModelName: "model-1",
+		}}, nil
+	}
+	controller2Err := errors.E("test error")
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: jimmtest.DialerMap{
+			"controller-1": &jimmtest.Dialer{API: &jimmtest.API{UpdateCredential_: updateCredential}},
+			"controller-2": &jimmtest.Dialer{API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, controller2Err
+				},
+			}},
+			"controller-3": &jimmtest.Dialer{Err: errors.E("connection refused")},
+		},
+		CredentialUpdateConcurrency: 2,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, credentialFanOutTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&alice, client)
+	user.JimmAdmin = true
+
+	result, err := j.UpdateCloudCredential(ctx, user, jimm.UpdateCloudCredentialArgs{
+		CredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1"),
+		Credential: jujuparams.CloudCredential{
+			AuthType:   "userpass",
+			Attributes: map[string]string{"username": "alice", "password": "5ecret"},
+		},
+		SkipCheck: true,
+	})
+	c.Check(err, qt.ErrorMatches, `credential stored but not updated on every controller, failed updates will be retried: controller controller-2: test error; controller controller-3: connection refused`)
+	c.Check(result, qt.DeepEquals, []jujuparams.UpdateCredentialModelResult{{
+		ModelUUID: "00000002-0000-0000-0000-000000000001",
+		ModelName: "model-1",
+	}})
+
+	cred := dbmodel.CloudCredential{
+		CloudName:         "test-cloud",
+		OwnerIdentityName: "alice@canonical.com",
+		Name:              "cred-1",
+	}
+	err = j.Database.GetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+	c.Check(cred.AuthType, qt.Equals, "userpass")
+
+	retries, err := j.Database.ListDueCredentialUpdateRetries(ctx, time.Now().Add(2*time.Minute), 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(retries, qt.HasLen, 2)
+	failed := make(map[string]string)
+	for _, r := range retries {
+		c.Check(r.Attempts, qt.Equals, 1)
+		failed[r.Controller.Name] = r.LastError
+	}
+	c.Check(failed, qt.DeepEquals, map[string]string{
+		"controller-2": "test error",
+		"controller-3": "connection refused",
+	})
+
+	// Nothing is retried before it is due.
+	err = j.RetryCredentialUpdates(ctx)
+	c.Assert(err, qt.IsNil)
+	retries, err = j.Database.ListDueCredentialUpdateRetries(ctx, time.Now().Add(2*time.Minute), 10)
+	c.Assert(err, qt.IsNil)
+	c.Check(retries, qt.HasLen, 2)
+
+	controller2Err = nil
+	err = j.Database.DB.Model(&dbmodel.CredentialUpdateRetry{}).Where("attempts > 0").Update("next_attempt", time.Now().Add(-time.Second)).Error
+	c.Assert(err, qt.IsNil)
+	err = j.RetryCredentialUpdates(ctx)
+	c.Assert(err, qt.IsNil)
+
+	retries, err = j.Database.ListDueCredentialUpdateRetries(ctx, time.Now().Add(time.Hour), 10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(retries, qt.HasLen, 1)
+	c.Check(retries[0].Controller.Name, qt.Equals, "controller-3")
+	c.Check(retries[0].Attempts, qt.Equals, 2)
+	c.Check(retries[0].NextAttempt.After(time.Now().Add(time.Minute)), qt.IsTrue)
+}
+
+func TestParseCredentialPropagationPolicies(t *testing.T) {
+	c := qt.New(t)
+
+	policies, err := jimm.ParseCredentialPropagationPolicies("")
+	c.Assert(err, qt.IsNil)
+	c.Check(policies, qt.HasLen, 0)
+
+	policies, err = jimm.ParseCredentialPropagationPolicies("aws=lazy  azure=eager")
+	c.Assert(err, qt.IsNil)
+	c.Check(policies, qt.DeepEquals, map[string]jimm.CredentialPropagationPolicy{
+		"aws":   jimm.CredentialPropagationLazy,
+		"azure": jimm.CredentialPropagationEager,
+	})
+
+	_, err = jimm.ParseCredentialPropagationPolicies("aws=sometimes")
+	c.Check(err, qt.ErrorMatches, `invalid credential propagation policy "aws=sometimes"`)
+
+	_, err = jimm.ParseCredentialPropagationPolicies("lazy")
+	c.Check(err, qt.ErrorMatches, `invalid credential propagation policy "lazy"`)
+}
+
+const credentialPropagationPolicyTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+`
+
+func TestUpdateCloudCredentialPropagationPolicy(t *testing.T) {
+	tests := []struct {
+		policy            jimm.CredentialPropagationPolicy
+		expectControllers []string
+	}{{
+		policy:            "",
+		expectControllers: []string{"controller-1", "controller-2"},
+	}, {
+		policy:            jimm.CredentialPropagationEager,
+		expectControllers: []string{"controller-1", "controller-2"},
+	}, {
+		policy:            jimm.CredentialPropagationLazy,
+		expectControllers: []string{"controller-1"},
+	}}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			c := qt.New(t)
+			ctx := context.Background()
+
+			client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+			c.Assert(err, qt.IsNil)
+
+			var mu sync.Mutex
+			var updated []string
+			dialer := func(name string) *jimmtest.Dialer {
+				return &jimmtest.Dialer{API: &jimmtest.API{
+					UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+						mu.Lock()
+						defer mu.Unlock()
+						updated = append(updated, name)
+						return nil, nil
+					},
+				}}
+			}
+			j := &jimm.JIMM{
+				UUID:          uuid.NewString(),
+				OpenFGAClient: client,
+				Database: db.Database{
+					DB: jimmtest.PostgresDB(c, nil),
+				},
+				Dialer: jimmtest.DialerMap{
+					"controller-1": dialer("controller-1"),
+					"controller-2": dialer("controller-2"),
+					"controller-3": dialer("controller-3"),
+				},
+			}
+			if test.policy != "" {
+				j.CredentialPropagationPolicies = map[string]jimm.CredentialPropagationPolicy{
+					"test-cloud": test.policy,
+				}
+			}
+			err = j.Database.Migrate(ctx, false)
+			c.Assert(err, qt.IsNil)
+
+			env := jimmtest.ParseEnvironment(c, credentialPropagationPolicyTestEnv)
+			env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+			// The credential was copied to controller-2 by a model that
+			// has since been destroyed, and copied to and revoked from
+			// controller-3.
+			for _, name := range []string{"controller-2", "controller-3"} {
+				err = j.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+					Credential:     "test-cloud/alice@canonical.com/cred-1",
+					ControllerName: name,
+					PropagatedAt:   time.Now().UTC(),
+				})
+				c.Assert(err, qt.IsNil)
+			}
+			err = j.Database.RevokeCredentialPropagations(ctx, "test-cloud/alice@canonical.com/cred-1", time.Now().UTC())
+			c.Assert(err, qt.IsNil)
+			err = j.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+				Credential:     "test-cloud/alice@canonical.com/cred-1",
+				ControllerName: "controller-2",
+				PropagatedAt:   time.Now().UTC(),
+			})
+			c.Assert(err, qt.IsNil)
+
+			alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+			user := openfga.NewUser(&alice, client)
+
+			_, err = j.UpdateCloudCredential(ctx, user, jimm.UpdateCloudCredentialArgs{
+				CredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1"),
+				Credential: jujuparams.CloudCredential{
+					AuthType:   "userpass",
+					Attributes: map[string]string{"username": "alice", "password": "5ecret"},
+				},
+				SkipCheck: true,
+			})
+			c.Assert(err, qt.IsNil)
+			sort.Strings(updated)
+			c.Check(updated, qt.DeepEquals, test.expectControllers)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmversion "github.com/canonical/jimm/v3/version"
+)
+
+// A CredentialPropagationPolicy determines which controllers a cloud
+// credential is copied to when it is updated.
+type CredentialPropagationPolicy string
+
+const (
+	// CredentialPropagationEager copies an updated credential to every
+	// controller that holds a copy of it, whether or not any model on
+	// the controller uses it. This is the default policy.
+	CredentialPropagationEager CredentialPropagationPolicy = "eager"
+
+	// CredentialPropagationLazy copies an updated credential only to
+	// the controllers hosting models that use it. Other controllers
+	// receive the credential when a model on them next needs it.
+	CredentialPropagationLazy CredentialPropagationPolicy = "lazy"
+)
+
+// ParseCredentialPropagationPolicies parses a space separated list of
+// per-cloud credential propagation policies of the form
+// <cloud>=<policy>, for example "aws=lazy azure=eager".
+func ParseCredentialPropagationPolicies(s string) (map[string]CredentialPropagationPolicy, error) {
+	policies := make(map[string]CredentialPropagationPolicy)
+	for _, f := range strings.Fields(s) {
+		cloud, policy, ok := strings.Cut(f, "=")
+		if !ok || cloud == "" {
+			return nil, errors.E(fmt.Sprintf("invalid credential propagation policy %q", f))
+		}
+		switch p := CredentialPropagationPolicy(policy); p {
+		case CredentialPropagationEager, CredentialPropagationLazy:
+			policies[cloud] = p
+		default:
+			return nil, errors.E(fmt.Sprintf("invalid credential propagation policy %q", f))
+		}
+	}
+	return policies, nil
+}
+
+// credentialPropagationPolicy returns the credential propagation policy
+// of the named cloud.
+func (j *JIMM) credentialPropagationPolicy(cloud string) CredentialPropagationPolicy {
+	if p, ok := j.CredentialPropagationPolicies[cloud]; ok {
+		return p
+	}
+	return CredentialPropagationEager
+}
+
+// propagatedControllers returns the given controllers, which host models
+// using the given credential, followed by every other controller the
+// credential has been copied to and not revoked from. Controllers that
+// no longer exist are ignored.
+func (j *JIMM) propagatedControllers(ctx context.Context, cred *dbmodel.CloudCredential, controllers []dbmodel.Controller) ([]dbmodel.Controller, error) {
+	propagations, err := j.Database.ListCredentialPropagations(ctx, cred.Path())
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(controllers))
+	for _, ctl := range controllers {
+		seen[ctl.Name] = true
+	}
+	for _, p := range propagations {
+		if p.RevokedAt.Valid || seen[p.ControllerName] {
+			continue
+		}
+		seen[p.ControllerName] = true
+		ctl := dbmodel.Controller{Name: p.ControllerName}
+		if err := j.Database.GetController(ctx, &ctl); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				continue
+			}
+			return nil, err
+		}
+		controllers = append(controllers, ctl)
+	}
+	return controllers, nil
+}
+
+// propagateCloudCredential copies the given cloud credential to the given
+// controller, reachable through the given API, and records the copy so
+// that the distribution of the credential can be audited.
+func (j *JIMM) propagateCloudCredential(ctx context.Context, cred *dbmodel.CloudCredential, ctl *dbmodel.Controller, api API) ([]jujuparams.UpdateCredentialModelResult, error) {
+	models, err := j.updateControllerCloudCredential(ctx, cred, api.UpdateCredential)
+	if err != nil {
+		return models, err
+	}
+	p := dbmodel.CredentialPropagation{
+		Credential:     cred.Path(),
+		ControllerName: ctl.Name,
+		ControllerUUID: ctl.UUID,
+		PropagatedAt:   time.Now().UTC(),
+		JIMMVersion:    jimmversion.VersionInfo.Version,
+	}
+	if err := j.Database.RecordCredentialPropagation(ctx, &p); err != nil {
+		// The credential is on the controller, failing to record that
+		// must not fail the operation that needed it.
+		zapctx.Error(ctx, "cannot record credential propagation", zap.String("credential", cred.Path()), zap.String("controller", ctl.Name), zaputil.Error(err))
+	}
+	return models, nil
+}
+
+// ListCredentialPropagations returns the controllers cloud credentials
+// have been copied to. If the credential tag is not zero only the
+// propagations of that credential are returned, if unconfirmed is true
+// only the propagations of revoked credentials whose removal has not
+// been confirmed are returned. JIMM administrators may list the
+// propagations of every credential, other users only those of their own
+// credentials.
+func (j *JIMM) ListCredentialPropagations(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, unconfirmed bool) ([]apiparams.CredentialPropagation, error) {
+	const op = errors.Op("jimm.ListCredentialPropagations")
+
+	path := tag.Id()
+	if !user.JimmAdmin && (path == "" || user.Tag() != tag.Owner()) {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	propagations, err := j.Database.ListCredentialPropagations(ctx, path)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.CredentialPropagation, 0, len(propagations))
+	for _, p := range propagations {
+		if unconfirmed && (!p.RevokedAt.Valid || p.RemovalConfirmedAt.Valid) {
+			continue
+		}
+		resp = append(resp, p.ToAPICredentialPropagation())
+	}
+	return resp, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// RebindModelCredentials rebinds the models using cloud credentials owned
+// by the given departing user to credentials shared with the named group,
+// so that the models keep working once the user's account is
+// deactivated. See rebindModelCredentials for how the credentials are
+// chosen. If dryRun is true the models that would be rebound are reported
+// but not changed. Only JIMM administrators can perform this operation.
+func (j *JIMM) RebindModelCredentials(ctx context.Context, user *openfga.User, departing names.UserTag, groupName string, dryRun bool) ([]apiparams.ModelCredentialRebind, error) {
+	const op = errors.Op("jimm.RebindModelCredentials")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	rebinds, err := j.rebindModelCredentials(ctx, departing.Id(), groupName, dryRun)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return rebinds, nil
+}
+
+// rebindModelCredentials rebinds every model using a cloud credential
+// owned by the named identity to a credential for the same cloud that the
+// members of the named group have been allowed to use, see
+// GrantCloudCredentialAccess. Credentials owned by the identity itself,
+// or known to be invalid, are never chosen, and of the remaining
+// credentials the first by owner and name is used. The chosen credential
+// is validated by the controller hosting each model before the model is
+// changed. A model that cannot be rebound is reported with an error and
+// does not stop the other models being rebound.
+func (j *JIMM) rebindModelCredentials(ctx context.Context, identityName, groupName string, dryRun bool) ([]apiparams.ModelCredentialRebind, error) {
+	group, err := j.getModelAccessGroup(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var creds []dbmodel.CloudCredential
+	err = j.Database.ForEachCloudCredential(ctx, identityName, "", func(cred *dbmodel.CloudCredential) error {
+		creds = append(creds, *cred)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	replacements := make(map[string]*dbmodel.CloudCredential)
+	rebinds := []apiparams.ModelCredentialRebind{}
+	for _, cred := range creds {
+		models, err := j.Database.GetModelsUsingCredential(ctx, cred.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(models) == 0 {
+			continue
+		}
+		replacement, ok := replacements[cred.CloudName]
+		if !ok {
+			replacement, err = j.groupCloudCredential(ctx, group, cred.CloudName, identityName)
+			if err != nil {
+				return nil, err
+			}
+			replacements[cred.CloudName] = replacement
+		}
+		for _, m := range models {
+			rebind := apiparams.ModelCredentialRebind{
+				ModelTag:       m.ResourceTag().String(),
+				FromCredential: cred.ResourceTag().String(),
+			}
+			switch {
+			case replacement == nil:
+				rebind.Error = fmt.Sprintf("no credential for cloud %q shared with group %q", cred.CloudName, groupName)
+			case dryRun:
+				rebind.ToCredential = replacement.ResourceTag().String()
+			default:
+				rebind.ToCredential = replacement.ResourceTag().String()
+				if err := j.changeModelCloudCredential(ctx, m.ResourceTag(), replacement); err != nil {
+					rebind.Error = err.Error()
+				}
+			}
+			rebinds = append(rebinds, rebind)
+		}
+	}
+	sort.Slice(rebinds, func(i, k int) bool {
+		return rebinds[i].ModelTag < rebinds[k].ModelTag
+	})
+	return rebinds, nil
+}
+
+// groupCloudCredential returns the first credential, by owner and name,
+// for the named cloud that the members of the given group may use to
+// create models, ignoring credentials owned by the excluded identity and
+// credentials known to be invalid. If there is no such credential nil is
+// returned.
+func (j *JIMM) groupCloudCredential(ctx context.Context, group *dbmodel.GroupEntry, cloud, exclude string) (*dbmodel.CloudCredential, error) {
+	creds, err := j.Database.GetCloudCredentialsByCloud(ctx, cloud)
+	if err != nil {
+		return nil, err
+	}
+	members := ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation)
+	for i := range creds {
+		cred := &creds[i]
+		if cred.OwnerIdentityName == exclude || (cred.Valid.Valid && !cred.Valid.Bool) {
+			continue
+		}
+		allowed, err := j.OpenFGAClient.CheckRelation(ctx, openfga.Tuple{
+			Object:   members,
+			Relation: ofganames.CanAddModelRelation,
+			Target:   ofganames.ConvertTag(cred.ResourceTag()),
+		}, false)
+		if err != nil {
+			return nil, errors.E(err, errors.CodeOpenFGARequestFailed)
+		}
+		if allowed {
+			return cred, nil
+		}
+	}
+	return nil, nil
+}
+
+// changeModelCloudCredential makes the model with the given tag use the
+// given cloud credential, on both the controller hosting the model and
+// in the database.
+func (j *JIMM) changeModelCloudCredential(ctx context.Context, mt names.ModelTag, cred *dbmodel.CloudCredential) error {
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return err
+	}
+	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	m.CloudCredential = *cred
+	m.CloudCredentialID = cred.ID
+	if err := j.rebindModelCredential(ctx, &m.Controller, api, mt, &m); err != nil {
+		return err
+	}
+	return j.Database.UpdateModel(ctx, &m)
+}
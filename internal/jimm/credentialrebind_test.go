@@ -0,0 +1,256 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/groupsync"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const credentialRebindTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+- name: other-cloud
+  type: test-provider
+  regions:
+  - name: other-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+- owner: alice@canonical.com
+  name: cred-2
+  cloud: test-cloud
+- owner: alice@canonical.com
+  name: cred-3
+  cloud: other-cloud
+- owner: charlie@canonical.com
+  name: invalid
+  cloud: test-cloud
+- owner: dave@canonical.com
+  name: shared
+  cloud: test-cloud
+- owner: eve@canonical.com
+  name: unshared
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-cloud-region
+    priority: 1
+  - cloud: other-cloud
+    region: other-cloud-region
+    priority: 1
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: other-cloud
+  region: other-cloud-region
+  cloud-credential: cred-3
+  owner: alice@canonical.com
+  life: alive
+users:
+- username: bob@canonical.com
+  controller-access: login
+`
+
+func TestRebindModelCredentials(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var changed []string
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				ChangeModelCredential_: func(_ context.Context, mt names.ModelTag, ct names.CloudCredentialTag) error {
+					changed = append(changed, mt.Id()+" "+ct.Id())
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, credentialRebindTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	group, err := j.Database.AddGroup(ctx, "platform")
+	c.Assert(err, qt.IsNil)
+	members := ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation)
+	for _, cred := range []string{
+		"test-cloud/alice@canonical.com/cred-2",
+		"test-cloud/charlie@canonical.com/invalid",
+		"test-cloud/dave@canonical.com/shared",
+	} {
+		err := client.SetCloudCredentialAccess(ctx, names.NewCloudCredentialTag(cred), members)
+		c.Assert(err, qt.IsNil)
+	}
+	err = j.Database.DB.Model(&dbmodel.CloudCredential{}).Where("name = ?", "invalid").Update("valid", false).Error
+	c.Assert(err, qt.IsNil)
+
+	alice := names.NewUserTag("alice@canonical.com")
+
+	_, err = j.RebindModelCredentials(ctx, bob, alice, "platform", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.RebindModelCredentials(ctx, admin, alice, "no-such-group", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	expectRebinds := []apiparams.ModelCredentialRebind{{
+		ModelTag:       "model-00000002-0000-0000-0000-000000000001",
+		FromCredential: "cloudcred-test-cloud_alice@canonical.com_cred-1",
+		ToCredential:   "cloudcred-test-cloud_dave@canonical.com_shared",
+	}, {
+		ModelTag:       "model-00000002-0000-0000-0000-000000000002",
+		FromCredential: "cloudcred-other-cloud_alice@canonical.com_cred-3",
+		Error:          `no credential for cloud "other-cloud" shared with group "platform"`,
+	}}
+
+	rebinds, err := j.RebindModelCredentials(ctx, admin, alice, "platform", true)
+	c.Assert(err, qt.IsNil)
+	c.Check(rebinds, qt.DeepEquals, expectRebinds)
+	c.Check(changed, qt.HasLen, 0)
+
+	rebinds, err = j.RebindModelCredentials(ctx, admin, alice, "platform", false)
+	c.Assert(err, qt.IsNil)
+	c.Check(rebinds, qt.DeepEquals, expectRebinds)
+	c.Check(changed, qt.DeepEquals, []string{
+		"00000002-0000-0000-0000-000000000001 test-cloud/dave@canonical.com/shared",
+	})
+
+	m := dbmodel.Model{UUID: env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database).UUID}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.CloudCredential.ResourceTag().Id(), qt.Equals, "test-cloud/dave@canonical.com/shared")
+
+	// Once rebound only the model without a shared credential remains.
+	rebinds, err = j.RebindModelCredentials(ctx, admin, alice, "platform", true)
+	c.Assert(err, qt.IsNil)
+	c.Check(rebinds, qt.DeepEquals, expectRebinds[1:])
+}
+
+func TestSyncGroupsRebindsDepartedUsers(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		GroupSync: jimm.GroupSyncConfig{
+			Name: "test-directory",
+			Directory: staticDirectory{
+				"Engineering": {"bob@canonical.com"},
+				"Platform":    {"eve@canonical.com"},
+			},
+			Mappings: []groupsync.Mapping{
+				{Source: "Engineering", Target: "engineering"},
+				{Source: "Platform", Target: "platform"},
+			},
+			CredentialGroup: "platform",
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, credentialRebindTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	platform, err := j.Database.AddGroup(ctx, "platform")
+	c.Assert(err, qt.IsNil)
+	err = client.SetCloudCredentialAccess(ctx, names.NewCloudCredentialTag("test-cloud/dave@canonical.com/shared"), ofganames.ConvertTagWithRelation(platform.ResourceTag(), ofganames.MemberRelation))
+	c.Assert(err, qt.IsNil)
+
+	// alice leaves engineering and every other synchronised group, eve
+	// moves from engineering to platform.
+	engineering, err := j.Database.AddGroup(ctx, "engineering")
+	c.Assert(err, qt.IsNil)
+	for _, user := range []string{"alice@canonical.com", "eve@canonical.com"} {
+		err = client.AddRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(user)),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(engineering.ResourceTag()),
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	resp, err := j.RunGroupSync(ctx, true, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Errors, qt.HasLen, 0)
+	c.Check(resp.Rebinds, qt.DeepEquals, []apiparams.ModelCredentialRebind{{
+		ModelTag:       "model-00000002-0000-0000-0000-000000000001",
+		FromCredential: "cloudcred-test-cloud_alice@canonical.com_cred-1",
+		ToCredential:   "cloudcred-test-cloud_dave@canonical.com_shared",
+	}, {
+		ModelTag:       "model-00000002-0000-0000-0000-000000000002",
+		FromCredential: "cloudcred-other-cloud_alice@canonical.com_cred-3",
+		Error:          `no credential for cloud "other-cloud" shared with group "platform"`,
+	}})
+
+	// Users who are still active in the directory keep their
+	// credentials, even if they are not in any synchronised group.
+	j.GroupSync.Directory = staticDirectory{
+		"Engineering": {"bob@canonical.com"},
+		"Platform":    {"eve@canonical.com"},
+		"Contractors": {"alice@canonical.com"},
+	}
+	resp, err = j.RunGroupSync(ctx, true, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Errors, qt.HasLen, 0)
+	c.Check(resp.Changes, qt.Contains, apiparams.GroupMembershipChange{Group: "engineering", Member: "alice@canonical.com", Action: "remove"})
+	c.Check(resp.Rebinds, qt.HasLen, 0)
+}
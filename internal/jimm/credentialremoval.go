@@ -0,0 +1,127 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+)
+
+const (
+	// DefaultCredentialRemovalDeadline is the time after a cloud
+	// credential is revoked by which its removal from every controller
+	// should be confirmed if CredentialRemovalDeadline is not set.
+	DefaultCredentialRemovalDeadline = time.Hour
+
+	// credentialRemovalBatchSize is the maximum number of unconfirmed
+	// credential removals considered by each call to
+	// VerifyCredentialRemovals.
+	credentialRemovalBatchSize = 100
+)
+
+// errCredentialPresent is recorded as the verification error of
+// controllers that still have a copy of a revoked credential.
+var errCredentialPresent = errors.E("credential still present on controller")
+
+// VerifyCredentialRemovals verifies that the controllers revoked cloud
+// credentials were copied to have removed their copies, for those
+// removals that have not been confirmed. Verifications that failed
+// before are retried with the same backoff as failed credential updates.
+// A controller that still has the credential is asked to remove it
+// again. The outcome of each verification is recorded against the
+// credential's propagation, and a notification is sent, once, for each
+// removal not confirmed by CredentialRemovalDeadline after the
+// revocation. It returns the number of removals confirmed.
+func (j *JIMM) VerifyCredentialRemovals(ctx context.Context) (int, error) {
+	const op = errors.Op("jimm.VerifyCredentialRemovals")
+
+	propagations, err := j.Database.ListUnconfirmedCredentialRemovals(ctx, credentialRemovalBatchSize)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	deadline := j.CredentialRemovalDeadline
+	if deadline <= 0 {
+		deadline = DefaultCredentialRemovalDeadline
+	}
+	now := time.Now().UTC()
+	confirmed := 0
+	for i := range propagations {
+		p := &propagations[i]
+		if p.LastVerifiedAt.Valid && now.Before(p.LastVerifiedAt.Time.Add(credentialRetryBackoff(p.VerificationAttempts))) {
+			continue
+		}
+		err := j.verifyCredentialRemoval(ctx, p)
+		if err == nil {
+			if err := j.Database.ConfirmCredentialRemoval(ctx, p.Credential, p.ControllerName, time.Now().UTC()); err != nil {
+				zapctx.Error(ctx, "cannot record credential removal", zap.String("credential", p.Credential), zap.String("controller", p.ControllerName), zap.Error(err))
+				continue
+			}
+			confirmed++
+			continue
+		}
+		zapctx.Warn(ctx, "credential removal not confirmed", zap.String("credential", p.Credential), zap.String("controller", p.ControllerName), zap.Error(err))
+		p.VerificationAttempts++
+		p.LastVerifiedAt = sql.NullTime{Time: now, Valid: true}
+		p.VerificationError = err.Error()
+		if !p.AlertedAt.Valid && now.After(p.RevokedAt.Time.Add(deadline)) {
+			j.Notifier.Notify(ctx, notify.Event{
+				Kind:       notify.CredentialRemovalUnconfirmed,
+				Controller: p.ControllerName,
+				Credential: names.NewCloudCredentialTag(p.Credential).String(),
+				Message:    fmt.Sprintf("revoked credential %s has not been confirmed removed from controller %s: %s", p.Credential, p.ControllerName, err),
+			})
+			p.AlertedAt = sql.NullTime{Time: now, Valid: true}
+		}
+		if err := j.Database.UpdateCredentialRemovalVerification(ctx, p); err != nil {
+			zapctx.Error(ctx, "cannot record credential removal verification", zap.String("credential", p.Credential), zap.String("controller", p.ControllerName), zap.Error(err))
+		}
+	}
+	return confirmed, nil
+}
+
+// verifyCredentialRemoval checks that the controller in the given
+// propagation no longer has the revoked credential, removing the
+// credential again if it does. A nil error is returned only if the
+// controller reports that it does not have the credential.
+func (j *JIMM) verifyCredentialRemoval(ctx context.Context, p *dbmodel.CredentialPropagation) error {
+	if !names.IsValidCloudCredential(p.Credential) {
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid credential %q", p.Credential))
+	}
+	tag := names.NewCloudCredentialTag(p.Credential)
+	ctl := dbmodel.Controller{Name: p.ControllerName}
+	if err := j.Database.GetController(ctx, &ctl); err != nil {
+		return err
+	}
+	return j.callController(ctx, &ctl, func(_ *dbmodel.Controller, api API) error {
+		_, err := api.Credential(ctx, tag)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// The controller still has the credential, the earlier removal
+		// must have failed.
+		if err := api.RevokeCredential(ctx, tag); err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+			return err
+		}
+		_, err = api.Credential(ctx, tag)
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return errCredentialPresent
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+)
+
+func TestVerifyCredentialRemovals(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	ch := new(recordingChannel)
+	notifier, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	notifier.AddChannel(ch, 0)
+
+	// controller-1 has removed cred-1, controller-2 still has cred-2
+	// and fails to remove it.
+	var revokes atomic.Int32
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			Credential_: func(_ context.Context, tag names.CloudCredentialTag) (jujuparams.CloudCredential, error) {
+				if tag.Name() == "cred-1" {
+					return jujuparams.CloudCredential{}, errors.E(errors.CodeNotFound, "credential not found")
+				}
+				return jujuparams.CloudCredential{AuthType: "empty"}, nil
+			},
+			RevokeCredential_: func(context.Context, names.CloudCredentialTag) error {
+				revokes.Add(1)
+				return nil
+			},
+		},
+	}
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer:                    dialer,
+		Notifier:                  notifier,
+		CredentialRemovalDeadline: time.Hour,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, controllerModelCredentialsTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	revoked := time.Now().UTC().Add(-2 * time.Hour)
+	for _, p := range []struct{ credential, controller string }{
+		{"test-cloud/alice@canonical.com/cred-1", "controller-1"},
+		{"test-cloud/alice@canonical.com/cred-2", "controller-2"},
+	} {
+		err := j.Database.RecordCredentialPropagation(ctx, &dbmodel.CredentialPropagation{
+			Credential:     p.credential,
+			ControllerName: p.controller,
+			ControllerUUID: uuid.NewString(),
+			PropagatedAt:   revoked.Add(-time.Hour),
+			JIMMVersion:    "3.1.0",
+		})
+		c.Assert(err, qt.IsNil)
+		err = j.Database.RevokeCredentialPropagations(ctx, p.credential, revoked)
+		c.Assert(err, qt.IsNil)
+	}
+
+	n, err := j.VerifyCredentialRemovals(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+	c.Check(revokes.Load(), qt.Equals, int32(1))
+
+	propagations, err := j.Database.ListCredentialPropagations(ctx, "test-cloud/alice@canonical.com/cred-1")
+	c.Assert(err, qt.IsNil)
+	c.Assert(propagations, qt.HasLen, 1)
+	c.Check(propagations[0].RemovalConfirmedAt.Valid, qt.IsTrue)
+	c.Check(propagations[0].VerificationAttempts, qt.Equals, 0)
+
+	propagations, err = j.Database.ListCredentialPropagations(ctx, "test-cloud/alice@canonical.com/cred-2")
+	c.Assert(err, qt.IsNil)
+	c.Assert(propagations, qt.HasLen, 1)
+	c.Check(propagations[0].RemovalConfirmedAt.Valid, qt.IsFalse)
+	c.Check(propagations[0].VerificationAttempts, qt.Equals, 1)
+	c.Check(propagations[0].VerificationError, qt.Equals, "credential still present on controller")
+	c.Check(propagations[0].LastVerifiedAt.Valid, qt.IsTrue)
+	c.Check(propagations[0].AlertedAt.Valid, qt.IsTrue)
+
+	// The credential remained present after the deadline.
+	notifier.Wait()
+	c.Assert(ch.events, qt.HasLen, 1)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.CredentialRemovalUnconfirmed)
+	c.Check(ch.events[0].Controller, qt.Equals, "controller-2")
+	c.Check(ch.events[0].Credential, qt.Equals, "cloudcred-test-cloud_alice@canonical.com_cred-2")
+
+	// The failed verification is not retried until its backoff has
+	// elapsed, and the alert is not repeated.
+	ch.events = nil
+	n, err = j.VerifyCredentialRemovals(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+	c.Check(revokes.Load(), qt.Equals, int32(1))
+	notifier.Wait()
+	c.Check(ch.events, qt.HasLen, 0)
+}
@@ -0,0 +1,243 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// DefaultCredentialRotationConcurrency is the number of cloud credentials
+// rotated at once if a rotation does not specify its concurrency.
+const DefaultCredentialRotationConcurrency = 5
+
+// A CredentialRotator computes the rotated attributes of cloud
+// credentials for a cloud provider, for example by issuing new keys
+// with the provider's API.
+type CredentialRotator interface {
+	// RotateCredential returns the new attributes for the given
+	// credential, which has the given attributes, on the given cloud. If
+	// the credential does not need rotating nil is returned.
+	RotateCredential(ctx context.Context, cloud *dbmodel.Cloud, cred *dbmodel.CloudCredential, attrs map[string]string) (map[string]string, error)
+}
+
+// RotateCloudCredentials rotates the cloud credentials for a cloud in a
+// single run. If the request gives attribute rotations every credential
+// with an attribute holding an old value has the value replaced,
+// otherwise the CredentialRotator configured for the cloud's provider
+// type computes the new attributes. Each rotated credential is checked
+// and updated on the controllers using it as UpdateCloudCredential
+// would. A credential that cannot be rotated is reported with an error
+// and does not stop the other credentials being rotated. If the request
+// is a dry run the credentials that would be rotated are reported but
+// not changed. If the request is asynchronous the rotation is started as
+// an operation, see GetOperation, and the operation's ID is returned
+// once the request has been validated. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) RotateCloudCredentials(ctx context.Context, user *openfga.User, req apiparams.RotateCloudCredentialsRequest) (apiparams.RotateCloudCredentialsResponse, error) {
+	const op = errors.Op("jimm.RotateCloudCredentials")
+
+	if !user.JimmAdmin {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	ct, err := names.ParseCloudTag(req.CloudTag)
+	if err != nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+	var cloud dbmodel.Cloud
+	cloud.SetTag(ct)
+	if err := j.getCloud(ctx, &cloud); err != nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, err)
+	}
+	rotate, err := j.credentialRotation(&cloud, req.Rotations)
+	if err != nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, err)
+	}
+
+	creds, err := j.Database.GetCloudCredentialsByCloud(ctx, cloud.Name)
+	if err != nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, err)
+	}
+
+	if !req.Async {
+		return j.rotateCloudCredentials(ctx, user, creds, rotate, req, nil), nil
+	}
+	o, err := j.startOperation(ctx, user, "rotate-cloud-credentials", func(ctx context.Context, r *operationReporter) (interface{}, error) {
+		return j.rotateCloudCredentials(ctx, user, creds, rotate, req, r), nil
+	})
+	if err != nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, err)
+	}
+	return apiparams.RotateCloudCredentialsResponse{
+		DryRun:    req.DryRun,
+		Results:   []apiparams.CredentialRotationResult{},
+		Operation: o.UUID,
+	}, nil
+}
+
+// rotateCloudCredentials rotates the given credentials as requested,
+// reporting the progress of the rotation to the given reporter if it is
+// not nil.
+func (j *JIMM) rotateCloudCredentials(ctx context.Context, user *openfga.User, creds []dbmodel.CloudCredential, rotate credentialRotateFunc, req apiparams.RotateCloudCredentialsRequest, r *operationReporter) apiparams.RotateCloudCredentialsResponse {
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCredentialRotationConcurrency
+	}
+	var mu sync.Mutex
+	done := 0
+	resp := apiparams.RotateCloudCredentialsResponse{
+		DryRun:  req.DryRun,
+		Results: []apiparams.CredentialRotationResult{},
+	}
+	eg := new(errgroup.Group)
+	eg.SetLimit(concurrency)
+	for i := range creds {
+		cred := &creds[i]
+		if req.AuthType != "" && cred.AuthType != req.AuthType {
+			mu.Lock()
+			resp.Unchanged++
+			done++
+			r.Progress(ctx, done, len(creds))
+			mu.Unlock()
+			continue
+		}
+		eg.Go(func() error {
+			result, rotated := j.rotateCloudCredential(ctx, user, cred, rotate, req.DryRun)
+			switch {
+			case !rotated:
+			case result.Error != "":
+				r.Step(ctx, fmt.Sprintf("failed to rotate %s: %s", result.CredentialTag, result.Error))
+			case req.DryRun:
+				r.Step(ctx, fmt.Sprintf("would rotate %s", result.CredentialTag))
+			default:
+				r.Step(ctx, fmt.Sprintf("rotated %s", result.CredentialTag))
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			r.Progress(ctx, done, len(creds))
+			if !rotated {
+				resp.Unchanged++
+				return nil
+			}
+			resp.Results = append(resp.Results, result)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	sort.Slice(resp.Results, func(i, k int) bool {
+		return resp.Results[i].CredentialTag < resp.Results[k].CredentialTag
+	})
+	return resp
+}
+
+// A credentialRotateFunc returns the new attributes of the given
+// credential, which has the given attributes, or nil if it does not need
+// rotating.
+type credentialRotateFunc func(ctx context.Context, cred *dbmodel.CloudCredential, attrs map[string]string) (map[string]string, error)
+
+// credentialRotation returns the function that rotates the credentials
+// for the given cloud using the given attribute rotations, or the
+// CredentialRotator for the cloud's provider if there are none.
+func (j *JIMM) credentialRotation(cloud *dbmodel.Cloud, rotations []apiparams.CredentialAttributeRotation) (credentialRotateFunc, error) {
+	for _, r := range rotations {
+		if r.Attribute == "" || r.Old == "" {
+			return nil, errors.E(errors.CodeBadRequest, "credential rotations must specify an attribute and an old value")
+		}
+	}
+	if len(rotations) > 0 {
+		return func(_ context.Context, _ *dbmodel.CloudCredential, attrs map[string]string) (map[string]string, error) {
+			var rotated map[string]string
+			for _, r := range rotations {
+				if v, ok := attrs[r.Attribute]; !ok || v != r.Old {
+					continue
+				}
+				if rotated == nil {
+					rotated = make(map[string]string, len(attrs))
+					for k, v := range attrs {
+						rotated[k] = v
+					}
+				}
+				rotated[r.Attribute] = r.New
+			}
+			return rotated, nil
+		}, nil
+	}
+	rotator, ok := j.CredentialRotators[cloud.Type]
+	if !ok {
+		return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("no credential rotations given and no rotation hook configured for %q clouds", cloud.Type))
+	}
+	return func(ctx context.Context, cred *dbmodel.CloudCredential, attrs map[string]string) (map[string]string, error) {
+		return rotator.RotateCredential(ctx, cloud, cred, attrs)
+	}, nil
+}
+
+// rotateCloudCredential rotates a single cloud credential using the given
+// function and returns the outcome, and whether the credential needed
+// rotating.
+func (j *JIMM) rotateCloudCredential(ctx context.Context, user *openfga.User, cred *dbmodel.CloudCredential, rotate credentialRotateFunc, dryRun bool) (apiparams.CredentialRotationResult, bool) {
+	result := apiparams.CredentialRotationResult{
+		CredentialTag: cred.ResourceTag().String(),
+		Attributes:    []string{},
+	}
+	attrs, err := j.getCloudCredentialAttributes(ctx, cred)
+	if err != nil {
+		result.Error = err.Error()
+		return result, true
+	}
+	rotated, err := rotate(ctx, cred, attrs)
+	if err != nil {
+		result.Error = err.Error()
+		return result, true
+	}
+	if rotated == nil {
+		return result, false
+	}
+	for k, v := range rotated {
+		if old, ok := attrs[k]; !ok || old != v {
+			result.Attributes = append(result.Attributes, k)
+		}
+	}
+	for k := range attrs {
+		if _, ok := rotated[k]; !ok {
+			result.Attributes = append(result.Attributes, k)
+		}
+	}
+	if len(result.Attributes) == 0 {
+		return result, false
+	}
+	sort.Strings(result.Attributes)
+	if dryRun {
+		return result, true
+	}
+
+	models, err := j.UpdateCloudCredential(ctx, user, UpdateCloudCredentialArgs{
+		CredentialTag: cred.ResourceTag(),
+		Credential: jujuparams.CloudCredential{
+			AuthType:   cred.AuthType,
+			Attributes: rotated,
+		},
+	})
+	if err != nil {
+		result.Error = err.Error()
+		return result, true
+	}
+	for _, m := range models {
+		if len(m.Errors) > 0 {
+			result.Error = fmt.Sprintf("credential not valid for model %q: %s", m.ModelName, m.Errors[0].Error)
+			break
+		}
+	}
+	return result, true
+}
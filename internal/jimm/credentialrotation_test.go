@@ -0,0 +1,191 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const credentialRotationTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+  auth-type: access-key
+  attributes:
+    access-key: old-key
+    secret-key: secret-1
+- owner: bob@canonical.com
+  name: cred-2
+  cloud: test-cloud
+  auth-type: access-key
+  attributes:
+    access-key: other-key
+    secret-key: secret-2
+- owner: charlie@canonical.com
+  name: cred-3
+  cloud: test-cloud
+  auth-type: access-key
+  attributes:
+    access-key: old-key
+    secret-key: secret-3
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+`
+
+// credentialRotatorFunc implements jimm.CredentialRotator with a
+// function.
+type credentialRotatorFunc func(map[string]string) (map[string]string, error)
+
+func (f credentialRotatorFunc) RotateCredential(_ context.Context, _ *dbmodel.Cloud, _ *dbmodel.CloudCredential, attrs map[string]string) (map[string]string, error) {
+	return f(attrs)
+}
+
+func TestRotateCloudCredentials(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var mu sync.Mutex
+	var updated []string
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				SupportsCheckCredentialModels_: true,
+				CheckCredentialModels_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				UpdateCredential_: func(_ context.Context, cred jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					updated = append(updated, cred.Tag+" "+cred.Credential.Attributes["access-key"])
+					return nil, nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, credentialRotationTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+
+	req := apiparams.RotateCloudCredentialsRequest{
+		CloudTag: names.NewCloudTag("test-cloud").String(),
+		Rotations: []apiparams.CredentialAttributeRotation{{
+			Attribute: "access-key",
+			Old:       "old-key",
+			New:       "new-key",
+		}},
+	}
+
+	_, err = j.RotateCloudCredentials(ctx, alice, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	cred1 := names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1")
+	cred3 := names.NewCloudCredentialTag("test-cloud/charlie@canonical.com/cred-3")
+	expectResults := []apiparams.CredentialRotationResult{{
+		CredentialTag: cred1.String(),
+		Attributes:    []string{"access-key"},
+	}, {
+		CredentialTag: cred3.String(),
+		Attributes:    []string{"access-key"},
+	}}
+
+	req.DryRun = true
+	resp, err := j.RotateCloudCredentials(ctx, admin, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.RotateCloudCredentialsResponse{
+		DryRun:    true,
+		Results:   expectResults,
+		Unchanged: 1,
+	})
+	c.Check(updated, qt.HasLen, 0)
+
+	req.DryRun = false
+	resp, err = j.RotateCloudCredentials(ctx, admin, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.RotateCloudCredentialsResponse{
+		Results:   expectResults,
+		Unchanged: 1,
+	})
+	// Only the credential used by a model is on a controller.
+	c.Check(updated, qt.DeepEquals, []string{cred1.String() + " new-key"})
+
+	var cred dbmodel.CloudCredential
+	cred.SetTag(cred3)
+	err = j.Database.GetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+	c.Check(cred.Attributes, qt.DeepEquals, dbmodel.StringMap{
+		"access-key": "new-key",
+		"secret-key": "secret-3",
+	})
+
+	// Without rotations the provider's hook is used.
+	req.Rotations = nil
+	_, err = j.RotateCloudCredentials(ctx, admin, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	j.CredentialRotators = map[string]jimm.CredentialRotator{
+		"test-provider": credentialRotatorFunc(func(attrs map[string]string) (map[string]string, error) {
+			if attrs["access-key"] != "other-key" {
+				return nil, nil
+			}
+			return map[string]string{"access-key": "rotated-key", "secret-key": "rotated-secret"}, nil
+		}),
+	}
+	resp, err = j.RotateCloudCredentials(ctx, admin, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.RotateCloudCredentialsResponse{
+		Results: []apiparams.CredentialRotationResult{{
+			CredentialTag: names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-2").String(),
+			Attributes:    []string{"access-key", "secret-key"},
+		}},
+		Unchanged: 2,
+	})
+}
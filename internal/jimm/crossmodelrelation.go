@@ -0,0 +1,98 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// CrossModelRelationGraph returns the graph of cross-model relations
+// recorded against the application offers known to JIMM. If the given
+// model tag is not empty only relations in which that model offers or
+// consumes an application are returned. Models consuming offers that are
+// not managed by JIMM appear in the graph with only their tag set. Only
+// JIMM administrators may view the graph.
+func (j *JIMM) CrossModelRelationGraph(ctx context.Context, user *openfga.User, modelTag names.ModelTag) (apiparams.CrossModelRelationGraph, error) {
+	const op = errors.Op("jimm.CrossModelRelationGraph")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.CrossModelRelationGraph{}, err
+	}
+
+	known := make(map[string]apiparams.CrossModelRelationGraphModel)
+	var relations []apiparams.CrossModelRelation
+	err := j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		mt := m.ResourceTag().String()
+		known[mt] = apiparams.CrossModelRelationGraphModel{
+			ModelTag:   mt,
+			Name:       m.Name,
+			Owner:      m.OwnerIdentityName,
+			Controller: m.Controller.Name,
+		}
+		for _, offer := range m.Offers {
+			for _, conn := range offer.Connections {
+				relations = append(relations, apiparams.CrossModelRelation{
+					OfferURL:          offer.URL,
+					OfferUUID:         offer.UUID,
+					OfferingModelTag:  mt,
+					ConsumingModelTag: conn.SourceModelTag,
+					RelationID:        conn.RelationID,
+					Endpoint:          conn.Endpoint,
+					Username:          conn.IdentityName,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return apiparams.CrossModelRelationGraph{}, errors.E(op, err)
+	}
+
+	graph := apiparams.CrossModelRelationGraph{
+		Models:    []apiparams.CrossModelRelationGraphModel{},
+		Relations: []apiparams.CrossModelRelation{},
+	}
+	seen := make(map[string]bool)
+	addModel := func(mt string) {
+		if seen[mt] {
+			return
+		}
+		seen[mt] = true
+		m, ok := known[mt]
+		if !ok {
+			m = apiparams.CrossModelRelationGraphModel{ModelTag: mt}
+		}
+		graph.Models = append(graph.Models, m)
+	}
+	for _, r := range relations {
+		if modelTag.Id() != "" && r.OfferingModelTag != modelTag.String() && r.ConsumingModelTag != modelTag.String() {
+			continue
+		}
+		graph.Relations = append(graph.Relations, r)
+		addModel(r.OfferingModelTag)
+		addModel(r.ConsumingModelTag)
+	}
+
+	sort.Slice(graph.Models, func(i, j int) bool {
+		return graph.Models[i].ModelTag < graph.Models[j].ModelTag
+	})
+	sort.Slice(graph.Relations, func(i, j int) bool {
+		ri, rj := graph.Relations[i], graph.Relations[j]
+		if ri.OfferURL != rj.OfferURL {
+			return ri.OfferURL < rj.OfferURL
+		}
+		if ri.ConsumingModelTag != rj.ConsumingModelTag {
+			return ri.ConsumingModelTag < rj.ConsumingModelTag
+		}
+		return ri.RelationID < rj.RelationID
+	})
+	return graph, nil
+}
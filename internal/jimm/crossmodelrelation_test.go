@@ -0,0 +1,202 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestCrossModelRelationGraph(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Round(time.Millisecond)
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, func() time.Time { return now }),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.DB.Create(u).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region-1",
+		}},
+	}
+	c.Assert(j.Database.DB.Create(&cloud).Error, qt.IsNil)
+
+	controller := dbmodel.Controller{
+		Name:        "test-controller-1",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region-1",
+	}
+	err = j.Database.AddController(ctx, &controller)
+	c.Assert(err, qt.IsNil)
+
+	cred := dbmodel.CloudCredential{
+		Name:              "test-credential-1",
+		CloudName:         cloud.Name,
+		OwnerIdentityName: u.Name,
+		AuthType:          "empty",
+	}
+	err = j.Database.SetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+
+	var models []dbmodel.Model
+	for i, name := range []string{"offering-model", "consuming-model", "unrelated-model"} {
+		m := dbmodel.Model{
+			Name: name,
+			UUID: sql.NullString{
+				String: []string{
+					"00000000-0000-0000-0000-0000-0000000000002",
+					"00000000-0000-0000-0000-0000-0000000000003",
+					"00000000-0000-0000-0000-0000-0000000000004",
+				}[i],
+				Valid: true,
+			},
+			OwnerIdentityName: u.Name,
+			ControllerID:      controller.ID,
+			CloudRegionID:     cloud.Regions[0].ID,
+			CloudCredentialID: cred.ID,
+		}
+		err = j.Database.AddModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+		models = append(models, m)
+	}
+	externalModelTag := names.NewModelTag("00000000-0000-0000-0000-0000-0000000000099").String()
+
+	offer := dbmodel.ApplicationOffer{
+		ModelID:         models[0].ID,
+		ApplicationName: "db",
+		Name:            "db",
+		UUID:            "00000000-0000-0000-0000-0000-0000000000010",
+		URL:             "test-controller-1:alice@canonical.com/offering-model.db",
+		Connections: []dbmodel.ApplicationOfferConnection{{
+			SourceModelTag: models[1].ResourceTag().String(),
+			RelationID:     1,
+			IdentityName:   "alice@canonical.com",
+			Endpoint:       "db",
+		}, {
+			SourceModelTag: externalModelTag,
+			RelationID:     2,
+			IdentityName:   "bob@canonical.com",
+			Endpoint:       "db",
+		}},
+	}
+	err = j.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+
+	offeringModel := apiparams.CrossModelRelationGraphModel{
+		ModelTag:   models[0].ResourceTag().String(),
+		Name:       "offering-model",
+		Owner:      "alice@canonical.com",
+		Controller: "test-controller-1",
+	}
+	consumingModel := apiparams.CrossModelRelationGraphModel{
+		ModelTag:   models[1].ResourceTag().String(),
+		Name:       "consuming-model",
+		Owner:      "alice@canonical.com",
+		Controller: "test-controller-1",
+	}
+	internalRelation := apiparams.CrossModelRelation{
+		OfferURL:          offer.URL,
+		OfferUUID:         offer.UUID,
+		OfferingModelTag:  models[0].ResourceTag().String(),
+		ConsumingModelTag: models[1].ResourceTag().String(),
+		RelationID:        1,
+		Endpoint:          "db",
+		Username:          "alice@canonical.com",
+	}
+	externalRelation := apiparams.CrossModelRelation{
+		OfferURL:          offer.URL,
+		OfferUUID:         offer.UUID,
+		OfferingModelTag:  models[0].ResourceTag().String(),
+		ConsumingModelTag: externalModelTag,
+		RelationID:        2,
+		Endpoint:          "db",
+		Username:          "bob@canonical.com",
+	}
+
+	admin := openfga.NewUser(u, client)
+	admin.JimmAdmin = true
+
+	tests := []struct {
+		about         string
+		user          *openfga.User
+		modelTag      names.ModelTag
+		expectedGraph apiparams.CrossModelRelationGraph
+		expectedError string
+	}{{
+		about: "full graph",
+		user:  admin,
+		expectedGraph: apiparams.CrossModelRelationGraph{
+			Models: []apiparams.CrossModelRelationGraphModel{
+				offeringModel,
+				consumingModel,
+				{ModelTag: externalModelTag},
+			},
+			Relations: []apiparams.CrossModelRelation{internalRelation, externalRelation},
+		},
+	}, {
+		about:    "graph for consuming model",
+		user:     admin,
+		modelTag: models[1].ResourceTag(),
+		expectedGraph: apiparams.CrossModelRelationGraph{
+			Models:    []apiparams.CrossModelRelationGraphModel{offeringModel, consumingModel},
+			Relations: []apiparams.CrossModelRelation{internalRelation},
+		},
+	}, {
+		about:    "graph for unrelated model",
+		user:     admin,
+		modelTag: models[2].ResourceTag(),
+		expectedGraph: apiparams.CrossModelRelationGraph{
+			Models:    []apiparams.CrossModelRelationGraphModel{},
+			Relations: []apiparams.CrossModelRelation{},
+		},
+	}, {
+		about:         "non-admin user",
+		user:          openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, client),
+		expectedError: "unauthorized",
+	}}
+
+	for _, test := range tests {
+		test := test
+		c.Run(test.about, func(c *qt.C) {
+			graph, err := j.CrossModelRelationGraph(ctx, test.user, test.modelTag)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Check(graph, qt.DeepEquals, test.expectedGraph)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ListDeletedModels returns the retained records of the models removed
+// from JIMM, the most recently removed first. JIMM administrators see
+// every removed model, other users see the removed models they owned.
+func (j *JIMM) ListDeletedModels(ctx context.Context, user *openfga.User) ([]apiparams.DeletedModel, error) {
+	const op = errors.Op("jimm.ListDeletedModels")
+
+	dms, err := j.Database.ListDeletedModels(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	models := make([]apiparams.DeletedModel, 0, len(dms))
+	for _, dm := range dms {
+		if !user.JimmAdmin && dm.OwnerIdentityName != user.Name {
+			continue
+		}
+		models = append(models, dm.ToAPIDeletedModel())
+	}
+	return models, nil
+}
+
+// GetDeletedModel returns the most recent retained record of the removed
+// model with the given tag. Only JIMM administrators and the model's
+// owner may inspect a removed model.
+func (j *JIMM) GetDeletedModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.DeletedModel, error) {
+	const op = errors.Op("jimm.GetDeletedModel")
+
+	dm, err := j.getDeletedModel(ctx, mt)
+	if err != nil {
+		return apiparams.DeletedModel{}, errors.E(op, err)
+	}
+	if !user.JimmAdmin && dm.OwnerIdentityName != user.Name {
+		return apiparams.DeletedModel{}, errors.E(op, errors.CodeNotFound, "deleted model not found")
+	}
+	return dm.ToAPIDeletedModel(), nil
+}
+
+// RestoreModel restores the removed model with the given tag by
+// importing it again from the controller that hosted it, with its
+// original owner and cost center. The model must still exist on the
+// controller. Once restored the model's retained records are removed.
+// Only JIMM administrators may restore models.
+func (j *JIMM) RestoreModel(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	const op = errors.Op("jimm.RestoreModel")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return errors.E(op, err)
+	}
+	dm, err := j.getDeletedModel(ctx, mt)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	if err == nil {
+		return errors.E(op, errors.CodeAlreadyExists, "model already exists")
+	}
+	if errors.ErrorCode(err) != errors.CodeNotFound {
+		return errors.E(op, err)
+	}
+
+	if err := j.ImportModel(ctx, user, dm.ControllerName, mt, dm.OwnerIdentityName, dbmodel.ModelOriginRestored); err != nil {
+		return errors.E(op, err)
+	}
+	if dm.CostCenter != "" {
+		m = dbmodel.Model{}
+		m.SetTag(mt)
+		if err := j.Database.GetModel(ctx, &m); err != nil {
+			return errors.E(op, err)
+		}
+		m.CostCenter = dm.CostCenter
+		if err := j.Database.UpdateModel(ctx, &m); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	if err := j.Database.DeleteDeletedModels(ctx, mt.Id()); err != nil {
+		zapctx.Error(ctx, "cannot remove deleted model records", zap.String("model", mt.Id()), zap.Error(err))
+	}
+	zapctx.Info(ctx, "model restored", zap.String("model", mt.Id()), zap.String("restored-by", user.Name))
+	return nil
+}
+
+// getDeletedModel returns the most recent retained record of the removed
+// model with the given tag.
+func (j *JIMM) getDeletedModel(ctx context.Context, mt names.ModelTag) (*dbmodel.DeletedModel, error) {
+	dm := dbmodel.DeletedModel{ModelUUID: mt.Id()}
+	if err := j.Database.GetDeletedModel(ctx, &dm); err != nil {
+		return nil, err
+	}
+	return &dm, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestDeletedModels(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	// A model that has not been removed cannot be restored.
+	err = j.RestoreModel(ctx, diane, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	err = j.Database.DeleteModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	models, err := j.ListDeletedModels(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Assert(models, qt.HasLen, 1)
+	c.Check(models[0].ModelTag, qt.Equals, mt.String())
+	c.Check(models[0].Name, qt.Equals, "model-1")
+	c.Check(models[0].Owner, qt.Equals, "alice@canonical.com")
+	c.Check(models[0].Controller, qt.Equals, "controller-1")
+	c.Check(models[0].Cloud, qt.Equals, "test-cloud")
+	c.Check(models[0].CloudRegion, qt.Equals, "test-cloud-region")
+	c.Check(models[0].CloudCredential, qt.Equals, "test-cloud/alice@canonical.com/cred-1")
+
+	models, err = j.ListDeletedModels(ctx, bob)
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 0)
+	models, err = j.ListDeletedModels(ctx, diane)
+	c.Assert(err, qt.IsNil)
+	c.Check(models, qt.HasLen, 1)
+
+	dm, err := j.GetDeletedModel(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(dm.Name, qt.Equals, "model-1")
+	_, err = j.GetDeletedModel(ctx, bob, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	_, err = j.GetDeletedModel(ctx, diane, names.NewModelTag("00000002-0000-0000-0000-000000000099"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.RestoreModel(ctx, alice, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
@@ -0,0 +1,258 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+const (
+	// DefaultDeltaExportSubject is the prefix of the subjects watcher
+	// deltas are exported to if no prefix is configured.
+	DefaultDeltaExportSubject = "jimm.deltas"
+
+	// defaultDeltaExportBufferSize is the number of deltas queued for
+	// export if no buffer size is configured.
+	defaultDeltaExportBufferSize = 10000
+
+	// deltaExportTimeout is the maximum time allowed to publish a delta.
+	deltaExportTimeout = 10 * time.Second
+)
+
+// A DeltaStream is an external event stream that watcher deltas are
+// exported to, such as a NATS server.
+type DeltaStream interface {
+	// Publish publishes the given data to the given subject.
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// A DeltaEvent is a watcher delta for a model, machine or unit as it is
+// exported. Only the fields useful for analysing the activity of the
+// fleet are included, configuration, addresses, status messages and the
+// names of identities are left out.
+type DeltaEvent struct {
+	// Controller is the name of the controller the delta came from.
+	Controller string `json:"controller"`
+
+	// ModelUUID is the UUID of the model the entity belongs to.
+	ModelUUID string `json:"model-uuid"`
+
+	// Kind is the kind of the entity, "model", "machine" or "unit".
+	Kind string `json:"kind"`
+
+	// ID is the ID of the entity in its model.
+	ID string `json:"id"`
+
+	// Time is the time the delta was received.
+	Time time.Time `json:"time"`
+
+	// Initial is set for the deltas describing the existing entities
+	// when JIMM starts watching the controller, rather than changes.
+	Initial bool `json:"initial,omitempty"`
+
+	// Removed is set if the entity has been removed, the remaining
+	// fields are not set in that case.
+	Removed bool `json:"removed,omitempty"`
+
+	// Life is the life of the entity.
+	Life string `json:"life,omitempty"`
+
+	// Status is the status of a model, the agent status of a machine
+	// or the workload status of a unit.
+	Status string `json:"status,omitempty"`
+
+	// AgentStatus is the agent status of a unit.
+	AgentStatus string `json:"agent-status,omitempty"`
+
+	// InstanceStatus is the instance status of a machine.
+	InstanceStatus string `json:"instance-status,omitempty"`
+
+	// Name is the name of a model.
+	Name string `json:"name,omitempty"`
+
+	// Type is the type of a model.
+	Type string `json:"type,omitempty"`
+
+	// Cloud and CloudRegion are the cloud and region of a model.
+	Cloud       string `json:"cloud,omitempty"`
+	CloudRegion string `json:"cloud-region,omitempty"`
+
+	// Version is the agent version of a model.
+	Version string `json:"version,omitempty"`
+
+	// Base is the base of a machine or unit.
+	Base string `json:"base,omitempty"`
+
+	// Cores is the number of CPU cores of a machine, if known.
+	Cores *uint64 `json:"cores,omitempty"`
+
+	// ContainerType is the container type of a machine.
+	ContainerType string `json:"container-type,omitempty"`
+
+	// Application is the application of a unit.
+	Application string `json:"application,omitempty"`
+
+	// Charm is the charm URL of a unit.
+	Charm string `json:"charm,omitempty"`
+
+	// Machine is the machine a unit is deployed to.
+	Machine string `json:"machine,omitempty"`
+}
+
+// newDeltaEvent returns the exported form of the given delta received
+// from the given controller. False is returned for the kinds of entity
+// that are not exported.
+func newDeltaEvent(controller string, d jujuparams.Delta, received time.Time, initial bool) (DeltaEvent, bool) {
+	eid := d.Entity.EntityId()
+	e := DeltaEvent{
+		Controller: controller,
+		ModelUUID:  eid.ModelUUID,
+		Kind:       eid.Kind,
+		ID:         eid.Id,
+		Time:       received.UTC(),
+		Initial:    initial,
+		Removed:    d.Removed,
+	}
+	switch info := d.Entity.(type) {
+	case *jujuparams.ModelUpdate:
+		if !d.Removed {
+			e.Life = string(info.Life)
+			e.Status = string(info.Status.Current)
+			e.Name = info.Name
+			e.Type = info.Type
+			e.Cloud = info.Cloud
+			e.CloudRegion = info.CloudRegion
+			e.Version = info.Version
+		}
+	case *jujuparams.MachineInfo:
+		if !d.Removed {
+			e.Life = string(info.Life)
+			e.Status = string(info.AgentStatus.Current)
+			e.InstanceStatus = string(info.InstanceStatus.Current)
+			e.Base = info.Base
+			e.ContainerType = info.ContainerType
+			if info.HardwareCharacteristics != nil {
+				e.Cores = info.HardwareCharacteristics.CpuCores
+			}
+		}
+	case *jujuparams.UnitInfo:
+		if !d.Removed {
+			e.Life = string(info.Life)
+			e.Status = string(info.WorkloadStatus.Current)
+			e.AgentStatus = string(info.AgentStatus.Current)
+			e.Base = info.Base
+			e.Application = info.Application
+			e.Charm = info.CharmURL
+			e.Machine = info.MachineId
+		}
+	default:
+		return DeltaEvent{}, false
+	}
+	return e, true
+}
+
+// A DeltaExporter exports the model, machine and unit deltas seen by the
+// watcher to an external event stream, on a subject per controller.
+// Deltas are queued and published in the background, so that a slow
+// stream cannot stall the watcher. When the queue is full further deltas
+// are dropped, and counted, until the stream catches up.
+type DeltaExporter struct {
+	// Stream is the stream the deltas are published to.
+	Stream DeltaStream
+
+	// SubjectPrefix is the prefix of the subjects deltas are published
+	// to, the subject for a controller is the prefix followed by a dot
+	// and the controller's name. If this is empty
+	// DefaultDeltaExportSubject is used.
+	SubjectPrefix string
+
+	// BufferSize is the number of deltas that may be queued for export.
+	// If this is zero a default size is used.
+	BufferSize int
+
+	once  sync.Once
+	queue chan DeltaEvent
+}
+
+func (e *DeltaExporter) init() {
+	e.once.Do(func() {
+		size := e.BufferSize
+		if size <= 0 {
+			size = defaultDeltaExportBufferSize
+		}
+		e.queue = make(chan DeltaEvent, size)
+	})
+}
+
+// subjectReplacer replaces the characters that have a special meaning in
+// subjects.
+var subjectReplacer = strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_")
+
+// subject returns the subject the deltas from the given controller are
+// published to.
+func (e *DeltaExporter) subject(controller string) string {
+	prefix := e.SubjectPrefix
+	if prefix == "" {
+		prefix = DefaultDeltaExportSubject
+	}
+	return prefix + "." + subjectReplacer.Replace(controller)
+}
+
+// Export queues the given delta, received from the given controller, for
+// export. Export never blocks, if the queue is full the delta is
+// dropped. Export does nothing if e is nil.
+func (e *DeltaExporter) Export(controller string, d jujuparams.Delta, received time.Time, initial bool) {
+	if e == nil {
+		return
+	}
+	event, ok := newDeltaEvent(controller, d, received, initial)
+	if !ok {
+		return
+	}
+	e.init()
+	select {
+	case e.queue <- event:
+	default:
+		servermon.MonitorDeltasExportedCount.WithLabelValues(controller, "dropped").Inc()
+	}
+}
+
+// Run publishes the queued deltas until the given context is canceled.
+// Deltas that cannot be published are logged and dropped.
+func (e *DeltaExporter) Run(ctx context.Context) {
+	e.init()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-e.queue:
+			e.publish(ctx, event)
+		}
+	}
+}
+
+func (e *DeltaExporter) publish(ctx context.Context, event DeltaEvent) {
+	buf, err := json.Marshal(event)
+	if err != nil {
+		zapctx.Error(ctx, "cannot marshal delta", zap.Error(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, deltaExportTimeout)
+	defer cancel()
+	if err := e.Stream.Publish(ctx, e.subject(event.Controller), buf); err != nil {
+		zapctx.Error(ctx, "cannot export delta", zap.String("controller", event.Controller), zap.Error(err))
+		servermon.MonitorDeltasExportedCount.WithLabelValues(event.Controller, "failed").Inc()
+		return
+	}
+	servermon.MonitorDeltasExportedCount.WithLabelValues(event.Controller, "sent").Inc()
+}
@@ -0,0 +1,226 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+// recordingStream is a jimm.DeltaStream that records the published
+// messages.
+type recordingStream struct {
+	mu       sync.Mutex
+	messages []streamMessage
+	block    chan struct{}
+	fail     bool
+}
+
+type streamMessage struct {
+	subject string
+	event   map[string]any
+}
+
+func (s *recordingStream) Publish(ctx context.Context, subject string, data []byte) error {
+	if s.block != nil {
+		select {
+		case <-s.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if s.fail {
+		return errors.E("publish failed")
+	}
+	var event map[string]any
+	if err := json.Unmarshal(data, &event); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, streamMessage{subject: subject, event: event})
+	return nil
+}
+
+func (s *recordingStream) published() []streamMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]streamMessage(nil), s.messages...)
+}
+
+func waitForMessages(c *qt.C, s *recordingStream, n int) []streamMessage {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if msgs := s.published(); len(msgs) >= n {
+			return msgs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Fatalf("timed out waiting for %d messages", n)
+	return nil
+}
+
+func TestDeltaExporter(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := new(recordingStream)
+	e := &jimm.DeltaExporter{
+		Stream: stream,
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	received := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	cores := uint64(4)
+	e.Export("controller-1.example", jujuparams.Delta{
+		Entity: &jujuparams.ModelUpdate{
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			Name:      "model-1",
+			Owner:     "alice@canonical.com",
+			Life:      "alive",
+			Type:      "iaas",
+			Cloud:     "test-cloud",
+			Status: jujuparams.StatusInfo{
+				Current: status.Available,
+				Message: "secret message",
+			},
+			Config: map[string]interface{}{"secret": "value"},
+		},
+	}, received, true)
+	e.Export("controller-1.example", jujuparams.Delta{
+		Entity: &jujuparams.MachineInfo{
+			ModelUUID:  "00000002-0000-0000-0000-000000000001",
+			Id:         "0",
+			InstanceId: "i-1234",
+			Hostname:   "host-0",
+			Life:       "alive",
+			AgentStatus: jujuparams.StatusInfo{
+				Current: status.Started,
+			},
+			HardwareCharacteristics: &instance.HardwareCharacteristics{
+				CpuCores: &cores,
+			},
+		},
+	}, received, false)
+	e.Export("controller-1.example", jujuparams.Delta{
+		Entity: &jujuparams.ApplicationInfo{
+			ModelUUID: "00000002-0000-0000-0000-000000000001",
+			Name:      "app-1",
+		},
+	}, received, false)
+	e.Export("controller-1.example", jujuparams.Delta{
+		Removed: true,
+		Entity: &jujuparams.UnitInfo{
+			ModelUUID:   "00000002-0000-0000-0000-000000000001",
+			Name:        "app-1/0",
+			Application: "app-1",
+		},
+	}, received, false)
+
+	msgs := waitForMessages(c, stream, 3)
+	c.Assert(msgs, qt.HasLen, 3)
+	for _, m := range msgs {
+		c.Check(m.subject, qt.Equals, "jimm.deltas.controller-1_example")
+	}
+	c.Check(msgs[0].event, qt.DeepEquals, map[string]any{
+		"controller": "controller-1.example",
+		"model-uuid": "00000002-0000-0000-0000-000000000001",
+		"kind":       "model",
+		"id":         "00000002-0000-0000-0000-000000000001",
+		"time":       "2024-05-01T12:00:00Z",
+		"initial":    true,
+		"life":       "alive",
+		"status":     "available",
+		"name":       "model-1",
+		"type":       "iaas",
+		"cloud":      "test-cloud",
+	})
+	c.Check(msgs[1].event, qt.DeepEquals, map[string]any{
+		"controller": "controller-1.example",
+		"model-uuid": "00000002-0000-0000-0000-000000000001",
+		"kind":       "machine",
+		"id":         "0",
+		"time":       "2024-05-01T12:00:00Z",
+		"life":       "alive",
+		"status":     "started",
+		"cores":      float64(4),
+	})
+	c.Check(msgs[2].event, qt.DeepEquals, map[string]any{
+		"controller": "controller-1.example",
+		"model-uuid": "00000002-0000-0000-0000-000000000001",
+		"kind":       "unit",
+		"id":         "app-1/0",
+		"time":       "2024-05-01T12:00:00Z",
+		"removed":    true,
+	})
+}
+
+func TestDeltaExporterDropsWhenFull(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &recordingStream{
+		block: make(chan struct{}),
+	}
+	e := &jimm.DeltaExporter{
+		Stream:        stream,
+		SubjectPrefix: "fleet",
+		BufferSize:    2,
+	}
+
+	// With nothing consuming the queue exporting must not block.
+	for i := 0; i < 10; i++ {
+		e.Export("controller-1", jujuparams.Delta{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+				Life:      "alive",
+			},
+		}, time.Now(), false)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Run(ctx)
+	}()
+	close(stream.block)
+
+	msgs := waitForMessages(c, stream, 2)
+	time.Sleep(50 * time.Millisecond)
+	c.Check(stream.published(), qt.HasLen, 2)
+	c.Check(msgs[0].subject, qt.Equals, "fleet.controller-1")
+	cancel()
+	<-done
+}
+
+func TestDeltaExporterNil(t *testing.T) {
+	var e *jimm.DeltaExporter
+	// Exporting to a nil exporter does nothing.
+	e.Export("controller-1", jujuparams.Delta{
+		Entity: &jujuparams.UnitInfo{Name: "app-1/0"},
+	}, time.Now(), false)
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// DefaultDocumentUpgradeBatchSize is the number of documents upgraded in
+// each batch if no batch size is given.
+const DefaultDocumentUpgradeBatchSize = 100
+
+// A DocumentUpgradeProgress reports the progress of upgrading the stored
+// documents of one kind.
+type DocumentUpgradeProgress struct {
+	// Kind is the kind of document being upgraded, see
+	// db.UpgradableDocuments.
+	Kind string
+
+	// Upgraded is the number of documents upgraded so far.
+	Upgraded int64
+
+	// Total is the number of documents that needed upgrading when the
+	// upgrade started.
+	Total int64
+}
+
+// UpgradeDocuments upgrades every stored document of every upgradable
+// kind that is stored with an older schema version, in batches of at
+// most batchSize documents. After each batch the given progress
+// function, if not nil, is called with the progress of the kind being
+// upgraded. UpgradeDocuments returns the number of documents of each kind
+// upgraded. A failure to upgrade one kind of document does not stop the
+// others being upgraded, the first error encountered is returned.
+func (j *JIMM) UpgradeDocuments(ctx context.Context, batchSize int, progress func(DocumentUpgradeProgress)) (map[string]int64, error) {
+	const op = errors.Op("jimm.UpgradeDocuments")
+
+	if batchSize <= 0 {
+		batchSize = DefaultDocumentUpgradeBatchSize
+	}
+	upgraded := make(map[string]int64)
+	var firstErr error
+	for _, kind := range db.UpgradableDocuments() {
+		n, err := j.upgradeDocuments(ctx, kind, batchSize, progress)
+		upgraded[kind] = n
+		if err != nil {
+			zapctx.Error(ctx, "failed to upgrade documents", zap.String("kind", kind), zap.Error(err))
+			if firstErr == nil {
+				firstErr = errors.E(op, err)
+			}
+		}
+	}
+	return upgraded, firstErr
+}
+
+// upgradeDocuments upgrades the documents of the given kind in batches
+// until a batch upgrades fewer documents than the batch size.
+func (j *JIMM) upgradeDocuments(ctx context.Context, kind string, batchSize int, progress func(DocumentUpgradeProgress)) (int64, error) {
+	total, err := j.Database.CountOutdatedDocuments(ctx, kind)
+	if err != nil || total == 0 {
+		return 0, err
+	}
+	p := DocumentUpgradeProgress{Kind: kind, Total: total}
+	for {
+		n, err := j.Database.UpgradeDocuments(ctx, kind, batchSize)
+		if err != nil {
+			return p.Upgraded, err
+		}
+		p.Upgraded += n
+		if progress != nil {
+			progress(p)
+		}
+		if n < int64(batchSize) {
+			return p.Upgraded, nil
+		}
+	}
+}
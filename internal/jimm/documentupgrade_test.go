@@ -0,0 +1,60 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+)
+
+func TestUpgradeDocuments(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for i := 0; i < 5; i++ {
+		err := j.Database.DB.Create(&dbmodel.Secret{
+			Type: names.CloudCredentialTagKind,
+			Tag:  fmt.Sprintf("test-cloud/alice@canonical.com/cred-%d", i),
+			Data: dbmodel.JSON(`{"key":"value"}`),
+		}).Error
+		c.Assert(err, qt.IsNil)
+	}
+
+	var progress []jimm.DocumentUpgradeProgress
+	upgraded, err := j.UpgradeDocuments(ctx, 2, func(p jimm.DocumentUpgradeProgress) {
+		progress = append(progress, p)
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(upgraded, qt.DeepEquals, map[string]int64{
+		db.DocumentSecrets:            5,
+		db.DocumentModelWatcherStates: 0,
+	})
+	c.Check(progress, qt.DeepEquals, []jimm.DocumentUpgradeProgress{
+		{Kind: db.DocumentSecrets, Upgraded: 2, Total: 5},
+		{Kind: db.DocumentSecrets, Upgraded: 4, Total: 5},
+		{Kind: db.DocumentSecrets, Upgraded: 5, Total: 5},
+	})
+
+	attr, err := j.Database.Get(ctx, names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-3"))
+	c.Assert(err, qt.IsNil)
+	c.Check(attr, qt.DeepEquals, map[string]string{"key": "value"})
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// SetDomainDefaultCloud sets the cloud, and optionally the region, used
+// for models created by identities in the given domain when no cloud is
+// specified. The domain may be given with or without a leading "@". Only
+// JIMM administrators may set domain default clouds.
+func (j *JIMM) SetDomainDefaultCloud(ctx context.Context, user *openfga.User, domain, cloud, region string) error {
+	const op = errors.Op("jimm.SetDomainDefaultCloud")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	domain = strings.TrimPrefix(domain, "@")
+	if domain == "" {
+		return errors.E(op, errors.CodeBadRequest, "domain not specified")
+	}
+
+	c := dbmodel.Cloud{Name: cloud}
+	if err := j.getCloud(ctx, &c); err != nil {
+		return errors.E(op, err)
+	}
+	if region != "" && c.Region(region).Name == "" {
+		return errors.E(op, errors.CodeNotFound, fmt.Sprintf("cloud region %s/%s not found", cloud, region))
+	}
+
+	def := dbmodel.DomainDefaultCloud{
+		Domain:     domain,
+		CloudName:  c.Name,
+		RegionName: region,
+	}
+	if err := j.Database.SetDomainDefaultCloud(ctx, &def); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveDomainDefaultCloud removes the default cloud of the given
+// domain. Only JIMM administrators may remove domain default clouds.
+func (j *JIMM) RemoveDomainDefaultCloud(ctx context.Context, user *openfga.User, domain string) error {
+	const op = errors.Op("jimm.RemoveDomainDefaultCloud")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	def := dbmodel.DomainDefaultCloud{Domain: strings.TrimPrefix(domain, "@")}
+	if err := j.Database.DeleteDomainDefaultCloud(ctx, &def); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListDomainDefaultClouds returns the default clouds of all identity
+// domains. Only JIMM administrators may list domain default clouds.
+func (j *JIMM) ListDomainDefaultClouds(ctx context.Context, user *openfga.User) ([]apiparams.DomainDefaultCloud, error) {
+	const op = errors.Op("jimm.ListDomainDefaultClouds")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	defs, err := j.Database.ListDomainDefaultClouds(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.DomainDefaultCloud, len(defs))
+	for i, def := range defs {
+		resp[i] = def.ToAPIDomainDefaultCloud()
+	}
+	return resp, nil
+}
+
+// DefaultCloud returns the default cloud, and region, for the given user
+// as configured for the user's identity domain. The user must have
+// access to the cloud. If there is no usable default an error with a
+// code of CodeNotFound is returned.
+func (j *JIMM) DefaultCloud(ctx context.Context, user *openfga.User) (dbmodel.DomainDefaultCloud, error) {
+	const op = errors.Op("jimm.DefaultCloud")
+
+	def := dbmodel.DomainDefaultCloud{Domain: dbmodel.IdentityDomain(user.Name)}
+	if def.Domain == "" {
+		return def, errors.E(op, errors.CodeNotFound, "no default cloud")
+	}
+	if err := j.Database.GetDomainDefaultCloud(ctx, &def); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return def, errors.E(op, errors.CodeNotFound, "no default cloud")
+		}
+		return def, errors.E(op, err)
+	}
+	c := dbmodel.Cloud{Name: def.CloudName}
+	if ToCloudAccessString(user.GetCloudAccess(ctx, c.ResourceTag())) == "" {
+		return def, errors.E(op, errors.CodeNotFound, "no default cloud")
+	}
+	return def, nil
+}
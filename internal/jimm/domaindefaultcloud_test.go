@@ -0,0 +1,200 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const domainDefaultCloudEnv = `
+clouds:
+- name: test-cloud-1
+  type: test-provider
+  regions:
+  - name: test-region-1
+- name: test-cloud-2
+  type: test-provider
+  regions:
+  - name: test-region-1
+  - name: test-region-2
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+- username: bob@example.com
+  controller-access: login
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud-1
+  auth-type: empty
+- name: test-credential-2
+  owner: alice@canonical.com
+  cloud: test-cloud-2
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud-1
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud-1
+    region: test-region-1
+    priority: 10
+  - cloud: test-cloud-2
+    region: test-region-1
+    priority: 10
+  - cloud: test-cloud-2
+    region: test-region-2
+    priority: 10
+`
+
+func TestDomainDefaultClouds(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, domainDefaultCloudEnv[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dbAlice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&dbAlice, client)
+	alice.JimmAdmin = true
+	dbBob := env.User("bob@example.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&dbBob, client)
+
+	err = j.SetDomainDefaultCloud(ctx, bob, "example.com", "test-cloud-1", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.SetDomainDefaultCloud(ctx, alice, "@canonical.com", "test-cloud-2", "test-region-3")
+	c.Check(err, qt.ErrorMatches, `cloud region test-cloud-2/test-region-3 not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.SetDomainDefaultCloud(ctx, alice, "@canonical.com", "test-cloud-2", "test-region-2")
+	c.Assert(err, qt.IsNil)
+	err = j.SetDomainDefaultCloud(ctx, alice, "example.com", "test-cloud-1", "")
+	c.Assert(err, qt.IsNil)
+
+	defs, err := j.ListDomainDefaultClouds(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(defs, qt.DeepEquals, []apiparams.DomainDefaultCloud{{
+		Domain: "canonical.com",
+		Cloud:  "test-cloud-2",
+		Region: "test-region-2",
+	}, {
+		Domain: "example.com",
+		Cloud:  "test-cloud-1",
+	}})
+
+	def, err := j.DefaultCloud(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(def.CloudName, qt.Equals, "test-cloud-2")
+
+	// bob cannot use the cloud, so has no default.
+	_, err = j.DefaultCloud(ctx, bob)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.RemoveDomainDefaultCloud(ctx, alice, "example.com")
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveDomainDefaultCloud(ctx, alice, "example.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestAddModelDomainDefaultCloud(t *testing.T) {
+	c := qt.New(t)
+
+	api := &jimmtest.API{
+		UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, nil
+		},
+		GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+			return nil
+		},
+		CreateModel_: createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:]),
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+	}
+	ctx := context.Background()
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, domainDefaultCloudEnv[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dbUser := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&dbUser, client)
+	user.JimmAdmin = true
+
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:     "test-model",
+		OwnerTag: user.Tag().String(),
+	})
+	c.Assert(err, qt.IsNil)
+
+	// With several clouds and no default the cloud must be specified.
+	_, err = j.AddModel(ctx, user, &args)
+	c.Check(err, qt.ErrorMatches, `no cloud specified for model; please specify one`)
+
+	err = j.SetDomainDefaultCloud(ctx, user, "canonical.com", "test-cloud-2", "test-region-2")
+	c.Assert(err, qt.IsNil)
+	_, err = j.AddModel(ctx, user, &args)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.CloudRegion.Cloud.Name, qt.Equals, "test-cloud-2")
+	c.Check(m.CloudRegion.Name, qt.Equals, "test-region-2")
+}
@@ -4,6 +4,7 @@ package jimm
 
 import (
 	"context"
+	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
@@ -16,14 +17,20 @@ import (
 
 var (
 	DetermineAccessLevelAfterGrant = determineAccessLevelAfterGrant
+	DialFailureTTL                 = &dialFailureTTL
 	PollDuration                   = pollDuration
 	CalculateNextPollDuration      = calculateNextPollDuration
 	NewControllerClient            = &newControllerClient
 	FillMigrationTarget            = fillMigrationTarget
 	InitiateMigration              = &initiateMigration
 	ResolveTag                     = resolveTag
+	ControllerUnavailableReason    = controllerUnavailableReason
 )
 
+func CacheBroadcasting(c *ResponseCache) bool {
+	return c.broadcasting.Load()
+}
+
 func WatchController(w *Watcher, ctx context.Context, ctl *dbmodel.Controller) error {
 	return w.watchController(ctx, ctl)
 }
@@ -46,6 +53,22 @@ func NewWatcherWithDeltaProcessedChannel(db db.Database, dialer Dialer, pubsub P
 	}
 }
 
+// ProcessDeltas processes the given deltas, which must all be for the
+// same model, as the watcher of the given controller would.
+func ProcessDeltas(ctx context.Context, w *Watcher, ctl *dbmodel.Controller, deltas []jujuparams.Delta, debug bool) error {
+	st := newModelState(1)
+	modelStatef := func(string) *modelState {
+		return st
+	}
+	received := time.Now()
+	for _, d := range deltas {
+		if err := w.processDelta(ctx, ctl, modelStatef, d, received, false, debug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (j *JIMM) ListApplicationOfferUsers(ctx context.Context, offer names.ApplicationOfferTag, user *dbmodel.Identity, accessLevel string) ([]jujuparams.OfferUserDetails, error) {
 	return j.listApplicationOfferUsers(ctx, offer, user, accessLevel)
 }
@@ -65,3 +88,37 @@ func (j *JIMM) UpdateUserLastLogin(ctx context.Context, identifier string) error
 func (j *JIMM) EveryoneUser() *openfga.User {
 	return j.everyoneUser()
 }
+
+func SetControllerHealthClock(h *ControllerHealth, now func() time.Time) {
+	h.now = now
+}
+
+func SetRegionLatency(p *LatencyProber, cloud, region string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.regions == nil {
+		p.regions = make(map[string]LatencyMeasurement)
+	}
+	p.regions[regionKey(cloud, region)] = LatencyMeasurement{Latency: latency}
+}
+
+func SetControllerLatency(p *LatencyProber, controller string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.controllers == nil {
+		p.controllers = make(map[string]LatencyMeasurement)
+	}
+	p.controllers[controller] = LatencyMeasurement{Latency: latency}
+}
+
+func SetLatencyProberClock(p *LatencyProber, now func() time.Time) {
+	p.now = now
+}
+
+func CloudRegionName(j *JIMM, ctx context.Context, cloud *dbmodel.Cloud, name string) (string, error) {
+	return j.cloudRegionName(ctx, cloud, name)
+}
+
+func WaitForOperations(j *JIMM) {
+	j.operations.Wait()
+}
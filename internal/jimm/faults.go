@@ -0,0 +1,61 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// InjectFault injects the given fault into this JIMM server, see
+// package faults. Only JIMM administrators may inject faults, and only
+// into servers built with fault injection enabled, otherwise an error
+// with the code CodeNotSupported is returned.
+func (j *JIMM) InjectFault(ctx context.Context, user *openfga.User, f faults.Fault) error {
+	const op = errors.Op("jimm.InjectFault")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if err := faults.Inject(f); err != nil {
+		return errors.E(op, err)
+	}
+	zapctx.Warn(ctx, "fault injected",
+		zap.String("user", user.Name),
+		zap.String("point", string(f.Point)),
+		zap.String("target", f.Target),
+		zap.Duration("delay", f.Delay),
+		zap.Int("count", f.Count),
+	)
+	return nil
+}
+
+// ClearFaults removes all the faults injected into this JIMM server.
+// Only JIMM administrators may clear faults.
+func (j *JIMM) ClearFaults(ctx context.Context, user *openfga.User) error {
+	const op = errors.Op("jimm.ClearFaults")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	faults.Clear()
+	zapctx.Info(ctx, "faults cleared", zap.String("user", user.Name))
+	return nil
+}
+
+// ListFaults returns the faults injected into this JIMM server. Only
+// JIMM administrators may list faults.
+func (j *JIMM) ListFaults(ctx context.Context, user *openfga.User) ([]faults.Fault, error) {
+	const op = errors.Op("jimm.ListFaults")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	return faults.List(), nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestFaultsUnauthorized(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := &jimm.JIMM{}
+	alice := openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil)
+
+	err := j.InjectFault(ctx, alice, faults.Fault{Point: faults.ControllerConnection})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.ClearFaults(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ListFaults(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestFaults(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := &jimm.JIMM{}
+	admin := openfga.NewUser(&dbmodel.Identity{Name: "admin@canonical.com"}, nil)
+	admin.JimmAdmin = true
+
+	f := faults.Fault{Point: faults.WatcherDelta, Target: "controller-1", Delay: time.Minute}
+	err := j.InjectFault(ctx, admin, f)
+	if !faults.Enabled {
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotSupported)
+		return
+	}
+	c.Cleanup(faults.Clear)
+	c.Assert(err, qt.IsNil)
+
+	fs, err := j.ListFaults(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(fs, qt.DeepEquals, []faults.Fault{f})
+
+	err = j.ClearFaults(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	fs, err = j.ListFaults(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(fs, qt.HasLen, 0)
+}
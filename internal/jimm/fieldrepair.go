@@ -0,0 +1,223 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// RepairDenormalizedFields recomputes the denormalized fields held on
+// models and controllers from their authoritative sources and repairs
+// those that have drifted, for example after a bug or a manual edit of
+// the database. The entity counts and workload status summaries of a
+// model are recomputed from the state persisted by its watcher, and the
+// cloud region recorded on a controller from the region it is deployed
+// in. If req.DryRun is set the repairs are reported but not made. Only
+// JIMM administrators can repair denormalized fields.
+func (j *JIMM) RepairDenormalizedFields(ctx context.Context, user *openfga.User, req apiparams.RepairDenormalizedFieldsRequest) (apiparams.RepairDenormalizedFieldsResponse, error) {
+	const op = errors.Op("jimm.RepairDenormalizedFields")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.RepairDenormalizedFieldsResponse{}, errors.E(op, err)
+	}
+
+	resp := apiparams.RepairDenormalizedFieldsResponse{
+		DryRun: req.DryRun,
+	}
+	modelRepairs, err := j.repairModelFields(ctx, req.DryRun)
+	if err != nil {
+		return apiparams.RepairDenormalizedFieldsResponse{}, errors.E(op, err)
+	}
+	resp.Repairs = append(resp.Repairs, modelRepairs...)
+	controllerRepairs, err := j.repairControllerFields(ctx, req.DryRun)
+	if err != nil {
+		return apiparams.RepairDenormalizedFieldsResponse{}, errors.E(op, err)
+	}
+	resp.Repairs = append(resp.Repairs, controllerRepairs...)
+
+	sort.Slice(resp.Repairs, func(i, j int) bool {
+		a, b := resp.Repairs[i], resp.Repairs[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Field < b.Field
+	})
+	if !req.DryRun && len(resp.Repairs) > 0 {
+		zapctx.Info(ctx, "denormalized fields repaired", zap.Int("repairs", len(resp.Repairs)))
+	}
+	return resp, nil
+}
+
+// repairModelFields repairs the denormalized fields of every model with
+// persisted watcher state. Models that have never been watched have no
+// authoritative state and are left unchanged.
+func (j *JIMM) repairModelFields(ctx context.Context, dryRun bool) ([]apiparams.DenormalizedFieldRepair, error) {
+	var ids []uint
+	err := j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		ids = append(ids, m.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var repairs []apiparams.DenormalizedFieldRepair
+	for _, id := range ids {
+		var modelRepairs []apiparams.DenormalizedFieldRepair
+		err := j.Database.Transaction(func(tx *db.Database) error {
+			m := dbmodel.Model{
+				ID: id,
+			}
+			if err := tx.GetModel(ctx, &m); err != nil {
+				return err
+			}
+			ws := dbmodel.ModelWatcherState{
+				ModelID: id,
+			}
+			if err := tx.GetModelWatcherState(ctx, &ws); err != nil {
+				if errors.ErrorCode(err) == errors.CodeNotFound {
+					return nil
+				}
+				return err
+			}
+			modelRepairs = repairModelCounts(&m, &ws)
+			if dryRun || len(modelRepairs) == 0 {
+				return nil
+			}
+			return tx.UpdateModel(ctx, &m)
+		})
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			// The model was removed since the models were listed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		repairs = append(repairs, modelRepairs...)
+	}
+	return repairs, nil
+}
+
+// repairModelCounts sets the entity counts and workload status summary
+// of the given model from the given watcher state, returning the fields
+// that changed.
+func repairModelCounts(m *dbmodel.Model, ws *dbmodel.ModelWatcherState) []apiparams.DenormalizedFieldRepair {
+	st := newModelState(m.ID)
+	st.restore(ws)
+	want := *m
+	st.updateModelCounts(&want)
+
+	name := m.OwnerIdentityName + "/" + m.Name
+	var repairs []apiparams.DenormalizedFieldRepair
+	check := func(field string, from, to interface{}) {
+		// Maps are printed with sorted keys, so equal values print
+		// equally.
+		f, t := fmt.Sprint(from), fmt.Sprint(to)
+		if f != t {
+			repairs = append(repairs, apiparams.DenormalizedFieldRepair{
+				Kind:  "model",
+				Name:  name,
+				Field: field,
+				From:  f,
+				To:    t,
+			})
+		}
+	}
+	check("cores", m.Cores, want.Cores)
+	check("machines", m.Machines, want.Machines)
+	check("containers", m.Containers, want.Containers)
+	check("units", m.Units, want.Units)
+	check("offers", m.OfferCount, want.OfferCount)
+	check("relations", m.Relations, want.Relations)
+	check("workload-status", m.WorkloadStatus, want.WorkloadStatus)
+	check("unhealthy-units", m.UnhealthyUnits, want.UnhealthyUnits)
+	check("active-units", m.ActiveUnits, want.ActiveUnits)
+	check("blocked-units", m.BlockedUnits, want.BlockedUnits)
+	check("error-units", m.ErrorUnits, want.ErrorUnits)
+	check("agent-versions", map[string]int64(m.AgentVersions), map[string]int64(want.AgentVersions))
+	*m = want
+	return repairs
+}
+
+// repairControllerFields repairs the cloud and cloud region recorded on
+// every controller deployed in exactly one cloud region.
+func (j *JIMM) repairControllerFields(ctx context.Context, dryRun bool) ([]apiparams.DenormalizedFieldRepair, error) {
+	var controllerNames []string
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		controllerNames = append(controllerNames, ctl.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var repairs []apiparams.DenormalizedFieldRepair
+	for _, name := range controllerNames {
+		ctl := dbmodel.Controller{
+			Name: name,
+		}
+		if err := j.Database.GetController(ctx, &ctl); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				continue
+			}
+			return nil, err
+		}
+		var deployed []dbmodel.CloudRegion
+		for _, crp := range ctl.CloudRegions {
+			if crp.Priority == dbmodel.CloudRegionControllerPriorityDeployed {
+				deployed = append(deployed, crp.CloudRegion)
+			}
+		}
+		if len(deployed) != 1 {
+			continue
+		}
+		region := deployed[0]
+		var ctlRepairs []apiparams.DenormalizedFieldRepair
+		if ctl.CloudName != region.CloudName {
+			ctlRepairs = append(ctlRepairs, apiparams.DenormalizedFieldRepair{
+				Kind:  "controller",
+				Name:  ctl.Name,
+				Field: "cloud",
+				From:  ctl.CloudName,
+				To:    region.CloudName,
+			})
+			ctl.CloudName = region.CloudName
+		}
+		if ctl.CloudRegion != region.Name {
+			ctlRepairs = append(ctlRepairs, apiparams.DenormalizedFieldRepair{
+				Kind:  "controller",
+				Name:  ctl.Name,
+				Field: "cloud-region",
+				From:  ctl.CloudRegion,
+				To:    region.Name,
+			})
+			ctl.CloudRegion = region.Name
+		}
+		if len(ctlRepairs) == 0 {
+			continue
+		}
+		if !dryRun {
+			if err := j.Database.UpdateController(ctx, &ctl); err != nil {
+				return nil, err
+			}
+			// The cached controllers record the regions hosting them.
+			j.Cache.InvalidateControllers()
+		}
+		repairs = append(repairs, ctlRepairs...)
+	}
+	return repairs, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const fieldRepairTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: old-region
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-cloud-region
+    priority: 10
+models:
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+- name: model-2
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+users:
+- username: diane@canonical.com
+  controller-access: superuser
+`
+
+func TestRepairDenormalizedFields(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, fieldRepairTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// model-1 has drifted from its watcher state, model-2 has never been
+	// watched.
+	m1 := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	m1.Machines = 5
+	m1.Units = 1
+	err = j.Database.UpdateModel(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+	err = j.Database.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+		ModelID:  m1.ID,
+		Machines: dbmodel.Int64Map{"0": 4, "0/lxd/0": 0},
+		Units: dbmodel.StringMap{
+			"web/0": "active",
+			"web/1": "active",
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	m2 := env.Model("alice@canonical.com", "model-2").DBObject(c, j.Database)
+	m2.Machines = 3
+	err = j.Database.UpdateModel(ctx, &m2)
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	_, err = j.RepairDenormalizedFields(ctx, openfga.NewUser(&aliceIdentity, client), apiparams.RepairDenormalizedFieldsRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	expectRepairs := []apiparams.DenormalizedFieldRepair{{
+		Kind:  "controller",
+		Name:  "controller-1",
+		Field: "cloud-region",
+		From:  "old-region",
+		To:    "test-cloud-region",
+	}, {
+		Kind:  "model",
+		Name:  "alice@canonical.com/model-1",
+		Field: "active-units",
+		From:  "0",
+		To:    "2",
+	}, {
+		Kind:  "model",
+		Name:  "alice@canonical.com/model-1",
+		Field: "containers",
+		From:  "0",
+		To:    "1",
+	}, {
+		Kind:  "model",
+		Name:  "alice@canonical.com/model-1",
+		Field: "cores",
+		From:  "0",
+		To:    "4",
+	}, {
+		Kind:  "model",
+		Name:  "alice@canonical.com/model-1",
+		Field: "machines",
+		From:  "5",
+		To:    "2",
+	}, {
+		Kind:  "model",
+		Name:  "alice@canonical.com/model-1",
+		Field: "units",
+		From:  "1",
+		To:    "2",
+	}, {
+		Kind:  "model",
+		Name:  "alice@canonical.com/model-1",
+		Field: "workload-status",
+		To:    "active",
+	}}
+
+	// A dry run reports the repairs without making them.
+	resp, err := j.RepairDenormalizedFields(ctx, diane, apiparams.RepairDenormalizedFieldsRequest{DryRun: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.RepairDenormalizedFieldsResponse{
+		DryRun:  true,
+		Repairs: expectRepairs,
+	})
+	m := dbmodel.Model{ID: m1.ID}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Machines, qt.Equals, int64(5))
+
+	resp, err = j.RepairDenormalizedFields(ctx, diane, apiparams.RepairDenormalizedFieldsRequest{})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.RepairDenormalizedFieldsResponse{
+		Repairs: expectRepairs,
+	})
+
+	m = dbmodel.Model{ID: m1.ID}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Machines, qt.Equals, int64(2))
+	c.Check(m.Containers, qt.Equals, int64(1))
+	c.Check(m.Cores, qt.Equals, int64(4))
+	c.Check(m.Units, qt.Equals, int64(2))
+	c.Check(m.WorkloadStatus, qt.Equals, "active")
+
+	m = dbmodel.Model{ID: m2.ID}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Machines, qt.Equals, int64(3))
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl.CloudRegion, qt.Equals, "test-cloud-region")
+
+	// Repairing again makes no changes.
+	resp, err = j.RepairDenormalizedFields(ctx, diane, apiparams.RepairDenormalizedFieldsRequest{})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Repairs, qt.HasLen, 0)
+}
@@ -0,0 +1,372 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/groupsync"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+// groupMembersPageSize is the number of group members read from OpenFGA
+// in each page when synchronising groups.
+const groupMembersPageSize = 100
+
+// DefaultGroupSyncMaxRemovals is the default for
+// GroupSyncConfig.MaxRemovals.
+const DefaultGroupSyncMaxRemovals = 10
+
+// GroupSyncConfig configures the synchronisation of group memberships
+// from an external directory.
+type GroupSyncConfig struct {
+	// Name is the name of the directory, it identifies the directory in
+	// the stored synchronisation status.
+	Name string
+
+	// Directory is the directory group memberships are read from. If
+	// this is nil groups are not synchronised.
+	Directory groupsync.Directory
+
+	// Mappings holds the rules mapping directory groups onto JIMM
+	// groups. Only JIMM groups that are the target of a mapping are
+	// synchronised.
+	Mappings []groupsync.Mapping
+
+	// CredentialGroup is the name of the JIMM group whose shared cloud
+	// credentials are used for the models of departed users. A user
+	// removed from a synchronised group who is no longer a member of any
+	// synchronised group, and who the directory reports is not an active
+	// user, has departed, and the models using their cloud credentials
+	// are rebound, see RebindModelCredentials. If this is empty models
+	// are not rebound.
+	CredentialGroup string
+
+	// MaxRemovals is the largest number of group memberships a
+	// synchronisation removes unless it is forced. A synchronisation
+	// that would remove more is more likely the result of a problem with
+	// the directory than of people leaving, so it is not made. If this
+	// is zero DefaultGroupSyncMaxRemovals is used, if it is negative
+	// there is no limit.
+	MaxRemovals int
+}
+
+// SyncGroups synchronises the membership of JIMM groups with the groups
+// in the external directory, see RunGroupSync. Only JIMM administrators
+// can perform this operation.
+func (j *JIMM) SyncGroups(ctx context.Context, user *openfga.User, req apiparams.SyncGroupsRequest) (apiparams.SyncGroupsResponse, error) {
+	const op = errors.Op("jimm.SyncGroups")
+
+	if !user.JimmAdmin {
+		return apiparams.SyncGroupsResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	resp, err := j.RunGroupSync(ctx, req.DryRun, req.Force)
+	if err != nil {
+		return apiparams.SyncGroupsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// RunGroupSync synchronises the membership of JIMM groups with the groups
+// in the external directory. The directory groups are mapped onto JIMM
+// groups using the configured mappings, missing groups are created, and
+// the users that are direct members of each mapped group are made to
+// match the directory. Other groups, and groups and service accounts
+// that are members of mapped groups, are left unchanged. If dryRun is
+// true the changes are reported but not made. Problems with individual
+// groups or members are reported in the response and do not stop the
+// rest of the synchronisation. The outcome of every synchronisation that
+// is not a dry run is stored, see GroupSyncStatus. If the synchronisation
+// would remove more group memberships than the configured maximum, see
+// GroupSyncConfig.MaxRemovals, no changes are made and an error with the
+// code CodeBadRequest is returned, unless force is true.
+func (j *JIMM) RunGroupSync(ctx context.Context, dryRun, force bool) (apiparams.SyncGroupsResponse, error) {
+	const op = errors.Op("jimm.RunGroupSync")
+
+	if j.GroupSync.Directory == nil {
+		return apiparams.SyncGroupsResponse{}, errors.E(op, errors.CodeNotSupported, "group synchronisation not configured")
+	}
+	resp := apiparams.SyncGroupsResponse{
+		DryRun: dryRun,
+		Time:   time.Now().UTC(),
+	}
+	err := j.syncGroups(ctx, &resp, force)
+	if !dryRun {
+		j.storeGroupSyncStatus(ctx, &resp, err)
+	}
+	if err != nil {
+		return apiparams.SyncGroupsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+func (j *JIMM) syncGroups(ctx context.Context, resp *apiparams.SyncGroupsResponse, force bool) error {
+	directoryGroups, err := j.GroupSync.Directory.Groups(ctx)
+	if err != nil {
+		return err
+	}
+	desired := groupsync.Apply(j.GroupSync.Mappings, directoryGroups)
+	groupNames := make([]string, 0, len(desired))
+	for name := range desired {
+		groupNames = append(groupNames, name)
+	}
+	sort.Strings(groupNames)
+
+	if !resp.DryRun && !force {
+		if err := j.checkGroupSyncRemovals(ctx, groupNames, desired); err != nil {
+			return err
+		}
+	}
+	if !resp.DryRun {
+		defer j.Cache.InvalidateAllModelAccess()
+		defer j.Cache.InvalidateGroups()
+	}
+	for _, name := range groupNames {
+		if err := j.syncGroup(ctx, name, desired[name], resp); err != nil {
+			return err
+		}
+	}
+	if j.GroupSync.CredentialGroup != "" {
+		j.rebindDepartedUsers(ctx, desired, resp)
+	}
+	return nil
+}
+
+// checkGroupSyncRemovals returns an error if synchronising the named
+// groups with the desired members would remove more group memberships
+// than the configured maximum.
+func (j *JIMM) checkGroupSyncRemovals(ctx context.Context, groupNames []string, desired map[string][]string) error {
+	maxRemovals := j.GroupSync.MaxRemovals
+	if maxRemovals == 0 {
+		maxRemovals = DefaultGroupSyncMaxRemovals
+	}
+	if maxRemovals < 0 {
+		return nil
+	}
+	plan := apiparams.SyncGroupsResponse{DryRun: true}
+	for _, name := range groupNames {
+		if err := j.syncGroup(ctx, name, desired[name], &plan); err != nil {
+			return err
+		}
+	}
+	var removals int
+	for _, c := range plan.Changes {
+		if c.Action == "remove" {
+			removals++
+		}
+	}
+	if removals > maxRemovals {
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("synchronisation would remove %d group memberships, more than the maximum of %d, check the directory and force the synchronisation to make the changes", removals, maxRemovals))
+	}
+	return nil
+}
+
+// rebindDepartedUsers rebinds the models of the users removed by the
+// synchronisation who are not members of any of the desired groups, and
+// who the directory reports are not active users, to credentials shared
+// with the configured credential group. A user who has only left the
+// synchronised groups keeps their credentials. Problems are reported in
+// the response.
+func (j *JIMM) rebindDepartedUsers(ctx context.Context, desired map[string][]string, resp *apiparams.SyncGroupsResponse) {
+	remaining := make(map[string]bool)
+	for _, members := range desired {
+		for _, member := range members {
+			remaining[member] = true
+		}
+	}
+	departed := make(map[string]bool)
+	for _, c := range resp.Changes {
+		if c.Action == "remove" && !remaining[c.Member] {
+			departed[c.Member] = true
+		}
+	}
+	users := make([]string, 0, len(departed))
+	for user := range departed {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	for _, user := range users {
+		active, err := j.GroupSync.Directory.UserActive(ctx, user)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("cannot check whether %q is active: %s", user, err))
+			continue
+		}
+		if active {
+			continue
+		}
+		rebinds, err := j.rebindModelCredentials(ctx, user, j.GroupSync.CredentialGroup, resp.DryRun)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("cannot rebind models of %q: %s", user, err))
+			continue
+		}
+		resp.Rebinds = append(resp.Rebinds, rebinds...)
+	}
+}
+
+// syncGroup makes the users that are direct members of the named group
+// match the given members.
+func (j *JIMM) syncGroup(ctx context.Context, name string, members []string, resp *apiparams.SyncGroupsResponse) error {
+	if !jimmnames.IsValidGroupName(name) {
+		resp.Errors = append(resp.Errors, fmt.Sprintf("invalid group name %q", name))
+		return nil
+	}
+	group := dbmodel.GroupEntry{Name: name}
+	current := make(map[string]bool)
+	err := j.Database.GetGroup(ctx, &group)
+	switch {
+	case errors.ErrorCode(err) == errors.CodeNotFound:
+		resp.CreatedGroups = append(resp.CreatedGroups, name)
+		if resp.DryRun {
+			break
+		}
+		ge, err := j.Database.AddGroup(ctx, name)
+		if err != nil {
+			return err
+		}
+		group = *ge
+	case err != nil:
+		return err
+	default:
+		current, err = j.listGroupUsers(ctx, group.ResourceTag())
+		if err != nil {
+			return err
+		}
+	}
+
+	target := ofganames.ConvertTag(group.ResourceTag())
+	var add, remove []openfga.Tuple
+	wanted := make(map[string]bool, len(members))
+	for _, member := range members {
+		if !names.IsValidUser(member) {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("invalid member name %q in group %q", member, name))
+			continue
+		}
+		wanted[member] = true
+		if current[member] {
+			continue
+		}
+		resp.Changes = append(resp.Changes, apiparams.GroupMembershipChange{Group: name, Member: member, Action: "add"})
+		add = append(add, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(member)),
+			Relation: ofganames.MemberRelation,
+			Target:   target,
+		})
+	}
+	var stale []string
+	for member := range current {
+		if !wanted[member] {
+			stale = append(stale, member)
+		}
+	}
+	sort.Strings(stale)
+	for _, member := range stale {
+		resp.Changes = append(resp.Changes, apiparams.GroupMembershipChange{Group: name, Member: member, Action: "remove"})
+		remove = append(remove, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(member)),
+			Relation: ofganames.MemberRelation,
+			Target:   target,
+		})
+	}
+	if resp.DryRun {
+		return nil
+	}
+	if len(add) > 0 {
+		if err := j.OpenFGAClient.AddRelation(ctx, add...); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := j.OpenFGAClient.RemoveRelation(ctx, remove...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listGroupUsers returns the names of the users that are direct members
+// of the given group.
+func (j *JIMM) listGroupUsers(ctx context.Context, group jimmnames.GroupTag) (map[string]bool, error) {
+	key := openfga.Tuple{
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group),
+	}
+	users := make(map[string]bool)
+	var continuationToken string
+	for {
+		tuples, ct, err := j.OpenFGAClient.ReadRelatedObjects(ctx, key, groupMembersPageSize, continuationToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tuples {
+			if t.Object.Kind == names.UserTagKind && t.Object.Relation == "" {
+				users[t.Object.ID] = true
+			}
+		}
+		if ct == "" {
+			return users, nil
+		}
+		continuationToken = ct
+	}
+}
+
+// storeGroupSyncStatus stores the outcome of a group synchronisation.
+func (j *JIMM) storeGroupSyncStatus(ctx context.Context, resp *apiparams.SyncGroupsResponse, syncErr error) {
+	status := dbmodel.GroupSyncStatus{
+		Directory:     j.GroupSync.Name,
+		Time:          resp.Time,
+		CreatedGroups: dbmodel.Strings(resp.CreatedGroups),
+		Errors:        dbmodel.Strings(resp.Errors),
+	}
+	for _, c := range resp.Changes {
+		if c.Action == "add" {
+			status.Added++
+		} else {
+			status.Removed++
+		}
+	}
+	if syncErr != nil {
+		status.Error = syncErr.Error()
+	}
+	if err := j.Database.UpsertGroupSyncStatus(ctx, &status); err != nil {
+		zapctx.Error(ctx, "failed to store group sync status", zap.Error(err))
+	}
+}
+
+// GroupSyncStatus returns the outcome of the most recent group
+// synchronisation that was not a dry run. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) GroupSyncStatus(ctx context.Context, user *openfga.User) (apiparams.GroupSyncStatusResponse, error) {
+	const op = errors.Op("jimm.GroupSyncStatus")
+
+	if !user.JimmAdmin {
+		return apiparams.GroupSyncStatusResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if j.GroupSync.Directory == nil {
+		return apiparams.GroupSyncStatusResponse{}, errors.E(op, errors.CodeNotSupported, "group synchronisation not configured")
+	}
+	status := dbmodel.GroupSyncStatus{Directory: j.GroupSync.Name}
+	if err := j.Database.GetGroupSyncStatus(ctx, &status); err != nil {
+		return apiparams.GroupSyncStatusResponse{}, errors.E(op, err)
+	}
+	return apiparams.GroupSyncStatusResponse{
+		Directory:     status.Directory,
+		Time:          status.Time.UTC(),
+		CreatedGroups: status.CreatedGroups,
+		Added:         status.Added,
+		Removed:       status.Removed,
+		Errors:        status.Errors,
+		Error:         status.Error,
+	}, nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/groupsync"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type staticDirectory map[string][]string
+
+func (d staticDirectory) Groups(context.Context) (map[string][]string, error) {
+	return d, nil
+}
+
+// UserActive reports users as active if they are a member of any group
+// in the directory.
+func (d staticDirectory) UserActive(_ context.Context, name string) (bool, error) {
+	for _, members := range d {
+		if slices.Contains(members, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestSyncGroups(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		GroupSync: jimm.GroupSyncConfig{
+			Name: "test-directory",
+			Directory: staticDirectory{
+				"Engineering": {"bob@canonical.com", "carol@canonical.com", "not a user!"},
+				"team-db":     {"dave@canonical.com"},
+				"Marketing":   {"eve@canonical.com"},
+			},
+			Mappings: []groupsync.Mapping{
+				{Source: "Engineering", Target: "engineering"},
+				{Source: "team-*", Target: "juju-*"},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// engineering already has a user that is not in the directory and
+	// a nested group, which is left alone.
+	engineering, err := j.Database.AddGroup(ctx, "engineering")
+	c.Assert(err, qt.IsNil)
+	ops, err := j.Database.AddGroup(ctx, "ops")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("alice@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(engineering.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(engineering.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.SyncGroups(ctx, openfga.NewUser(bob, client), apiparams.SyncGroupsRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+
+	_, err = j.GroupSyncStatus(ctx, admin)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	expectChanges := []apiparams.GroupMembershipChange{
+		{Group: "engineering", Member: "bob@canonical.com", Action: "add"},
+		{Group: "engineering", Member: "carol@canonical.com", Action: "add"},
+		{Group: "engineering", Member: "alice@canonical.com", Action: "remove"},
+		{Group: "juju-db", Member: "dave@canonical.com", Action: "add"},
+	}
+	expectErrors := []string{`invalid member name "not a user!" in group "engineering"`}
+
+	resp, err := j.SyncGroups(ctx, admin, apiparams.SyncGroupsRequest{DryRun: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.DryRun, qt.IsTrue)
+	c.Check(resp.CreatedGroups, qt.DeepEquals, []string{"juju-db"})
+	c.Check(resp.Changes, qt.DeepEquals, expectChanges)
+	c.Check(resp.Errors, qt.DeepEquals, expectErrors)
+
+	// A dry run changes nothing.
+	err = j.Database.GetGroup(ctx, &dbmodel.GroupEntry{Name: "juju-db"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	isMember := func(user, group string) bool {
+		ge := dbmodel.GroupEntry{Name: group}
+		err := j.Database.GetGroup(ctx, &ge)
+		c.Assert(err, qt.IsNil)
+		ok, err := client.CheckRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(user)),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(ge.ResourceTag()),
+		}, false)
+		c.Assert(err, qt.IsNil)
+		return ok
+	}
+	c.Check(isMember("alice@canonical.com", "engineering"), qt.IsTrue)
+	c.Check(isMember("bob@canonical.com", "engineering"), qt.IsFalse)
+	_, err = j.GroupSyncStatus(ctx, admin)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	resp, err = j.SyncGroups(ctx, admin, apiparams.SyncGroupsRequest{})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.DryRun, qt.IsFalse)
+	c.Check(resp.CreatedGroups, qt.DeepEquals, []string{"juju-db"})
+	c.Check(resp.Changes, qt.DeepEquals, expectChanges)
+	c.Check(resp.Errors, qt.DeepEquals, expectErrors)
+
+	c.Check(isMember("alice@canonical.com", "engineering"), qt.IsFalse)
+	c.Check(isMember("bob@canonical.com", "engineering"), qt.IsTrue)
+	c.Check(isMember("carol@canonical.com", "engineering"), qt.IsTrue)
+	c.Check(isMember("dave@canonical.com", "juju-db"), qt.IsTrue)
+	c.Check(isMember("eve@canonical.com", "engineering"), qt.IsFalse)
+	ok, err := client.CheckRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(ops.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(engineering.ResourceTag()),
+	}, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(ok, qt.IsTrue)
+
+	status, err := j.GroupSyncStatus(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(status.Time.Sub(resp.Time).Abs() < time.Millisecond, qt.IsTrue)
+	status.Time = resp.Time
+	c.Check(status, qt.DeepEquals, apiparams.GroupSyncStatusResponse{
+		Directory:     "test-directory",
+		Time:          resp.Time,
+		CreatedGroups: []string{"juju-db"},
+		Added:         3,
+		Removed:       1,
+		Errors:        expectErrors,
+	})
+
+	// A second synchronisation has nothing to do.
+	resp, err = j.SyncGroups(ctx, admin, apiparams.SyncGroupsRequest{})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.CreatedGroups, qt.HasLen, 0)
+	c.Check(resp.Changes, qt.HasLen, 0)
+}
+
+func TestSyncGroupsMaxRemovals(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		GroupSync: jimm.GroupSyncConfig{
+			Name: "test-directory",
+			// The directory has lost the members of Engineering.
+			Directory: staticDirectory{
+				"Engineering": {},
+			},
+			Mappings: []groupsync.Mapping{
+				{Source: "Engineering", Target: "engineering"},
+			},
+			MaxRemovals: 1,
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	engineering, err := j.Database.AddGroup(ctx, "engineering")
+	c.Assert(err, qt.IsNil)
+	isMember := func(user string) bool {
+		ok, err := client.CheckRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(user)),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(engineering.ResourceTag()),
+		}, false)
+		c.Assert(err, qt.IsNil)
+		return ok
+	}
+	for _, user := range []string{"bob@canonical.com", "carol@canonical.com"} {
+		err = client.AddRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(user)),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(engineering.ResourceTag()),
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+
+	// A dry run reports the removals.
+	resp, err := j.SyncGroups(ctx, admin, apiparams.SyncGroupsRequest{DryRun: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Changes, qt.HasLen, 2)
+
+	_, err = j.SyncGroups(ctx, admin, apiparams.SyncGroupsRequest{})
+	c.Check(err, qt.ErrorMatches, `synchronisation would remove 2 group memberships, more than the maximum of 1, check the directory and force the synchronisation to make the changes`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	c.Check(isMember("bob@canonical.com"), qt.IsTrue)
+	c.Check(isMember("carol@canonical.com"), qt.IsTrue)
+
+	status, err := j.GroupSyncStatus(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(status.Error, qt.Matches, `synchronisation would remove 2 group memberships.*`)
+
+	resp, err = j.SyncGroups(ctx, admin, apiparams.SyncGroupsRequest{Force: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Changes, qt.DeepEquals, []apiparams.GroupMembershipChange{
+		{Group: "engineering", Member: "bob@canonical.com", Action: "remove"},
+		{Group: "engineering", Member: "carol@canonical.com", Action: "remove"},
+	})
+	c.Check(isMember("bob@canonical.com"), qt.IsFalse)
+	c.Check(isMember("carol@canonical.com"), qt.IsFalse)
+}
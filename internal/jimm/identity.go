@@ -4,11 +4,17 @@ package jimm
 
 import (
 	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
 
 	"github.com/canonical/jimm/v3/internal/common/pagination"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // FetchIdentity fetches the user specified by the username and returns the user if it is found.
@@ -63,3 +69,70 @@ func (j *JIMM) CountIdentities(ctx context.Context, user *openfga.User) (int, er
 	}
 	return count, nil
 }
+
+// PurgeIdentity permanently removes the named identity and its personal
+// data, for example to satisfy an erasure request. The identity's cloud
+// credentials are revoked on the controllers and removed from the
+// credential store, and all of the identity's relations are removed. The
+// records JIMM must keep, such as audit log entries, are anonymized to
+// refer to a tombstone instead, unless deleteAuditLog is set in which case
+// the identity's audit log entries are deleted. Only JIMM administrators
+// may purge identities and an identity that still owns models cannot be
+// purged.
+func (j *JIMM) PurgeIdentity(ctx context.Context, user *openfga.User, name string, deleteAuditLog bool) (apiparams.PurgeIdentityResponse, error) {
+	const op = errors.Op("jimm.PurgeIdentity")
+
+	var resp apiparams.PurgeIdentityResponse
+	if !user.JimmAdmin {
+		return resp, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	identity, err := dbmodel.NewIdentity(name)
+	if err != nil {
+		return resp, errors.E(op, errors.CodeBadRequest, err)
+	}
+	if identity.Name == user.Name {
+		return resp, errors.E(op, errors.CodeBadRequest, "cannot purge own identity")
+	}
+
+	tombstone := dbmodel.IdentityTombstone{
+		Name:     "erased-" + uuid.NewString(),
+		PurgedBy: user.Name,
+	}
+	purge, err := j.Database.PurgeIdentity(ctx, identity, &tombstone, deleteAuditLog)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+
+	// The identity is gone from the database, so the remaining steps
+	// continue after a failure to remove as much as possible.
+	var cleanupErr error
+	cleanup := func(err error) {
+		if err == nil {
+			return
+		}
+		zapctx.Error(ctx, "failed to clean up purged identity", zap.String("tombstone", tombstone.Name), zap.Error(err))
+		if cleanupErr == nil {
+			cleanupErr = err
+		}
+	}
+	for _, cred := range purge.CloudCredentials {
+		tag := cred.ResourceTag()
+		cleanup(j.revokeCredentialOnControllers(ctx, cred.CloudName, tag))
+		if cred.AttributesInVault && j.CredentialStore != nil {
+			cleanup(j.CredentialStore.Put(ctx, tag, nil))
+		}
+		cleanup(j.OpenFGAClient.RemoveCloudCredential(ctx, tag))
+		resp.CloudCredentials = append(resp.CloudCredentials, tag.String())
+	}
+	cleanup(j.OpenFGAClient.RemoveUser(ctx, identity.ResourceTag()))
+	// The cache is keyed by model, so the purged identity's cached access
+	// can only be removed by removing all cached access.
+	j.Cache.InvalidateAllModelAccess()
+
+	resp.Tombstone = tombstone.Name
+	resp.AuditLogEntries = purge.AuditLogEntries
+	if cleanupErr != nil {
+		return resp, errors.E(op, fmt.Sprintf("identity purged, but cleanup failed: %v", cleanupErr))
+	}
+	return resp, nil
+}
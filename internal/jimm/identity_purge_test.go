@@ -0,0 +1,136 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+func TestPurgeIdentity(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var revoked []string
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				RevokeCredential_: func(_ context.Context, tag names.CloudCredentialTag) error {
+					revoked = append(revoked, tag.String())
+					return nil
+				},
+			},
+		},
+		CredentialStore: store,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: bob@canonical.com
+    access: add-model
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+- username: bob@canonical.com
+  controller-access: login
+cloud-credentials:
+- name: test-credential-1
+  owner: bob@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+`[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	credTag := names.NewCloudCredentialTag("test-cloud/bob@canonical.com/test-credential-1")
+	err = store.Put(ctx, credTag, map[string]string{"key": "secret"})
+	c.Assert(err, qt.IsNil)
+	cred := dbmodel.CloudCredential{}
+	cred.SetTag(credTag)
+	err = j.Database.GetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+	cred.AttributesInVault = true
+	err = j.Database.SetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+
+	err = j.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+		Time:        time.Now(),
+		IdentityTag: "user-bob@canonical.com",
+	})
+	c.Assert(err, qt.IsNil)
+
+	dbAlice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&dbAlice, client)
+	alice.JimmAdmin = true
+	dbBob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&dbBob, client)
+
+	_, err = j.PurgeIdentity(ctx, bob, "alice@canonical.com", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.PurgeIdentity(ctx, alice, "alice@canonical.com", false)
+	c.Check(err, qt.ErrorMatches, `cannot purge own identity`)
+
+	resp, err := j.PurgeIdentity(ctx, alice, "bob@canonical.com", false)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Tombstone, qt.Matches, `erased-.*`)
+	c.Check(resp.CloudCredentials, qt.DeepEquals, []string{credTag.String()})
+	c.Check(resp.AuditLogEntries, qt.Equals, int64(1))
+	c.Check(revoked, qt.DeepEquals, []string{credTag.String()})
+
+	attrs, err := store.Get(ctx, credTag)
+	c.Assert(err, qt.IsNil)
+	c.Check(attrs, qt.HasLen, 0)
+
+	_, err = j.FetchIdentity(ctx, "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, "record not found")
+
+	// A new identity with the same name has none of the purged
+	// identity's access.
+	access := bob.GetCloudAccess(ctx, names.NewCloudTag("test-cloud"))
+	c.Check(access, qt.Equals, ofganames.NoRelation)
+
+	n := 0
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{IdentityTag: "user-" + resp.Tombstone}, func(*dbmodel.AuditLogEntry) error {
+		n++
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+}
@@ -0,0 +1,102 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// NormalizeIdentities renames the existing identities whose names are not
+// normalized, see dbmodel.SetIdentityNormalizer, so that they match the
+// names of the identities as they now authenticate. The records, the
+// relations and the cloud credentials of each identity are moved to the
+// normalized name. If dryRun is true the renames are only reported. An
+// identity whose normalized name already exists is not renamed, the
+// reason is reported with the rename. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) NormalizeIdentities(ctx context.Context, user *openfga.User, dryRun bool) ([]apiparams.IdentityRename, error) {
+	const op = errors.Op("jimm.NormalizeIdentities")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	// The identities are collected before any are renamed, so that the
+	// renames do not disturb the iteration.
+	var renames []apiparams.IdentityRename
+	err := j.Database.ForEachIdentity(ctx, -1, 0, func(i *dbmodel.Identity) error {
+		normalized, err := dbmodel.NewIdentity(i.Name)
+		if err != nil {
+			return err
+		}
+		if normalized.Name != i.Name {
+			renames = append(renames, apiparams.IdentityRename{
+				From: i.Name,
+				To:   normalized.Name,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	for k, r := range renames {
+		if dryRun {
+			err := j.Database.FetchIdentity(ctx, &dbmodel.Identity{Name: r.To})
+			if err == nil {
+				renames[k].Error = "identity " + r.To + " already exists"
+			}
+			continue
+		}
+		if err := j.renameIdentity(ctx, r.From, r.To); err != nil {
+			zapctx.Error(ctx, "failed to rename identity", zap.String("from", r.From), zap.String("to", r.To), zap.Error(err))
+			renames[k].Error = err.Error()
+		}
+	}
+	return renames, nil
+}
+
+// renameIdentity renames the identity with the given name in the
+// database, in OpenFGA and in the credential store.
+func (j *JIMM) renameIdentity(ctx context.Context, from, to string) error {
+	identity := dbmodel.Identity{Name: from}
+	oldTag := identity.ResourceTag()
+	credentials, err := j.Database.RenameIdentity(ctx, &identity, to)
+	if err != nil {
+		return err
+	}
+	if err := j.OpenFGAClient.RenameUser(ctx, oldTag, identity.ResourceTag()); err != nil {
+		return err
+	}
+	for _, cred := range credentials {
+		oldCredTag := cred.ResourceTag()
+		cred.OwnerIdentityName = identity.Name
+		credTag := cred.ResourceTag()
+		if err := j.OpenFGAClient.RenameCloudCredential(ctx, oldCredTag, credTag); err != nil {
+			return err
+		}
+		if !cred.AttributesInVault || j.CredentialStore == nil {
+			continue
+		}
+		attr, err := j.CredentialStore.Get(ctx, oldCredTag)
+		if err != nil {
+			return err
+		}
+		if err := j.CredentialStore.Put(ctx, credTag, attr); err != nil {
+			return err
+		}
+		if err := j.CredentialStore.Put(ctx, oldCredTag, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
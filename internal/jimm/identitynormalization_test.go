@@ -0,0 +1,140 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestNormalizeIdentities(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		CredentialStore: store,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: Bob@external
+    access: add-model
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+- username: Bob@external
+  controller-access: login
+- username: carol@external
+  controller-access: login
+- username: carol@canonical.com
+  controller-access: login
+cloud-credentials:
+- name: test-credential-1
+  owner: Bob@external
+  cloud: test-cloud
+  auth-type: empty
+`[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	oldCredTag := names.NewCloudCredentialTag("test-cloud/Bob@external/test-credential-1")
+	err = store.Put(ctx, oldCredTag, map[string]string{"key": "secret"})
+	c.Assert(err, qt.IsNil)
+	cred := dbmodel.CloudCredential{}
+	cred.SetTag(oldCredTag)
+	err = j.Database.GetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+	cred.AttributesInVault = true
+	err = j.Database.SetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+
+	dbAlice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&dbAlice, client)
+	alice.JimmAdmin = true
+	dbCarol := env.User("carol@canonical.com").DBObject(c, j.Database)
+	carol := openfga.NewUser(&dbCarol, client)
+
+	dbmodel.SetIdentityNormalizer(&dbmodel.IdentityNormalizer{
+		CaseFold:      true,
+		DomainAliases: map[string]string{"external": "canonical.com"},
+	})
+	c.Cleanup(func() { dbmodel.SetIdentityNormalizer(nil) })
+
+	_, err = j.NormalizeIdentities(ctx, carol, true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	expectRenames := []apiparams.IdentityRename{{
+		From: "Bob@external",
+		To:   "bob@canonical.com",
+	}, {
+		From:  "carol@external",
+		To:    "carol@canonical.com",
+		Error: "identity carol@canonical.com already exists",
+	}}
+	renames, err := j.NormalizeIdentities(ctx, alice, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(renames, qt.DeepEquals, expectRenames)
+
+	// A dry run renames nothing.
+	_, err = j.FetchIdentity(ctx, "bob@canonical.com")
+	c.Check(err, qt.ErrorMatches, "record not found")
+
+	renames, err = j.NormalizeIdentities(ctx, alice, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(renames, qt.HasLen, 2)
+	c.Check(renames[0], qt.DeepEquals, expectRenames[0])
+	c.Check(renames[1].Error, qt.Matches, `.*identity carol@canonical.com already exists`)
+
+	// Incoming identities are normalized, so the old name finds the
+	// renamed identity.
+	bob, err := j.FetchIdentity(ctx, "Bob@external")
+	c.Assert(err, qt.IsNil)
+	c.Check(bob.Name, qt.Equals, "bob@canonical.com")
+	c.Check(bob.GetCloudAccess(ctx, names.NewCloudTag("test-cloud")), qt.Equals, ofganames.CanAddModelRelation)
+
+	credTag := names.NewCloudCredentialTag("test-cloud/bob@canonical.com/test-credential-1")
+	cred = dbmodel.CloudCredential{}
+	cred.SetTag(credTag)
+	err = j.Database.GetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+	attrs, err := store.Get(ctx, credTag)
+	c.Assert(err, qt.IsNil)
+	c.Check(attrs, qt.DeepEquals, map[string]string{"key": "secret"})
+	attrs, err = store.Get(ctx, oldCredTag)
+	c.Assert(err, qt.IsNil)
+	c.Check(attrs, qt.HasLen, 0)
+
+	// Once normalized, there is nothing left to rename apart from the
+	// conflicting identity.
+	renames, err = j.NormalizeIdentities(ctx, alice, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(renames, qt.DeepEquals, expectRenames[1:])
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// StartIdentitySession records a new API session for the given user,
+// made from the given remote address. The returned session should be
+// passed to EndIdentitySession when the connection is closed.
+func (j *JIMM) StartIdentitySession(ctx context.Context, user *openfga.User, remoteAddress string) (*dbmodel.IdentitySession, error) {
+	const op = errors.Op("jimm.StartIdentitySession")
+
+	s := dbmodel.IdentitySession{
+		IdentityName:  user.Name,
+		LastActivity:  j.Database.DB.Config.NowFunc(),
+		RemoteAddress: remoteAddress,
+	}
+	if err := j.Database.AddIdentitySession(ctx, &s); err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &s, nil
+}
+
+// RecordIdentitySessionActivity records that the identity made an API
+// request in the given session at the given time.
+func (j *JIMM) RecordIdentitySessionActivity(ctx context.Context, s *dbmodel.IdentitySession, t time.Time) error {
+	const op = errors.Op("jimm.RecordIdentitySessionActivity")
+
+	s.LastActivity = t
+	if err := j.Database.UpdateIdentitySessionActivity(ctx, s); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// EndIdentitySession removes the given session, recording its last
+// activity on the identity first.
+func (j *JIMM) EndIdentitySession(ctx context.Context, s *dbmodel.IdentitySession) error {
+	const op = errors.Op("jimm.EndIdentitySession")
+
+	if err := j.Database.UpdateIdentitySessionActivity(ctx, s); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.RemoveIdentitySession(ctx, s); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListIdentitySessions returns the last login and API activity of the
+// named identity along with the sessions it has open. Identities may
+// list their own sessions, only JIMM administrators may list the
+// sessions of other identities.
+func (j *JIMM) ListIdentitySessions(ctx context.Context, user *openfga.User, name string) (apiparams.ListIdentitySessionsResponse, error) {
+	const op = errors.Op("jimm.ListIdentitySessions")
+
+	var resp apiparams.ListIdentitySessionsResponse
+	identity, err := dbmodel.NewIdentity(name)
+	if err != nil {
+		return resp, errors.E(op, errors.CodeBadRequest, err)
+	}
+	if identity.Name != user.Name && !user.JimmAdmin {
+		return resp, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if err := j.Database.FetchIdentity(ctx, identity); err != nil {
+		return resp, errors.E(op, err)
+	}
+	sessions, err := j.Database.ListIdentitySessions(ctx, identity.Name)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+
+	resp.Identity = identity.ResourceTag().String()
+	if identity.LastLogin.Valid {
+		resp.LastLogin = &identity.LastLogin.Time
+	}
+	if identity.LastActivity.Valid {
+		resp.LastActivity = &identity.LastActivity.Time
+	}
+	resp.Sessions = make([]apiparams.IdentitySession, len(sessions))
+	for i, s := range sessions {
+		resp.Sessions[i] = apiparams.IdentitySession{
+			ID:            s.ID,
+			StartedAt:     s.CreatedAt,
+			LastActivity:  s.LastActivity,
+			RemoteAddress: s.RemoteAddress,
+		}
+	}
+	return resp, nil
+}
+
+// DeleteIdleIdentitySessions removes the sessions that have had no
+// activity for the given idle period. These are sessions left behind by
+// a JIMM server that stopped without closing its connections.
+func (j *JIMM) DeleteIdleIdentitySessions(ctx context.Context, idle time.Duration) (int64, error) {
+	const op = errors.Op("jimm.DeleteIdleIdentitySessions")
+
+	n, err := j.Database.DeleteIdleIdentitySessions(ctx, j.Database.DB.Config.NowFunc().Add(-idle))
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestIdentitySessions(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	clock := now
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, func() time.Time { return clock }),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.GetIdentity(ctx, aliceIdentity), qt.IsNil)
+	alice := openfga.NewUser(aliceIdentity, client)
+
+	bobIdentity, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.GetIdentity(ctx, bobIdentity), qt.IsNil)
+	bob := openfga.NewUser(bobIdentity, client)
+
+	s1, err := j.StartIdentitySession(ctx, alice, "10.0.0.1:4321")
+	c.Assert(err, qt.IsNil)
+	s2, err := j.StartIdentitySession(ctx, alice, "10.0.0.2:4321")
+	c.Assert(err, qt.IsNil)
+
+	err = j.RecordIdentitySessionActivity(ctx, s1, now.Add(time.Minute))
+	c.Assert(err, qt.IsNil)
+
+	summary, err := j.Whoami(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(summary.ActiveSessions, qt.Equals, 2)
+	c.Assert(summary.LastActivity, qt.Not(qt.IsNil))
+	c.Check(summary.LastActivity.Equal(now.Add(time.Minute)), qt.IsTrue)
+
+	resp, err := j.ListIdentitySessions(ctx, alice, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Identity, qt.Equals, "user-alice@canonical.com")
+	c.Assert(resp.Sessions, qt.HasLen, 2)
+	c.Check(resp.Sessions[0].ID, qt.Equals, s1.ID)
+	c.Check(resp.Sessions[0].RemoteAddress, qt.Equals, "10.0.0.1:4321")
+	c.Check(resp.Sessions[1].ID, qt.Equals, s2.ID)
+
+	// Only JIMM administrators may list the sessions of other identities.
+	_, err = j.ListIdentitySessions(ctx, bob, "alice@canonical.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	bob.JimmAdmin = true
+	resp, err = j.ListIdentitySessions(ctx, bob, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Sessions, qt.HasLen, 2)
+
+	s2.LastActivity = now.Add(2 * time.Minute)
+	err = j.EndIdentitySession(ctx, s2)
+	c.Assert(err, qt.IsNil)
+
+	summary, err = j.Whoami(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(summary.ActiveSessions, qt.Equals, 1)
+	c.Check(summary.LastActivity.Equal(now.Add(2*time.Minute)), qt.IsTrue)
+
+	// Sessions that have been idle for too long are removed.
+	clock = now.Add(time.Hour)
+	n, err := j.DeleteIdleIdentitySessions(ctx, 30*time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+
+	resp, err = j.ListIdentitySessions(ctx, alice, "alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Sessions, qt.HasLen, 0)
+	c.Check(resp.LastActivity.Equal(now.Add(2*time.Minute)), qt.IsTrue)
+}
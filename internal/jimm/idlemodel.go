@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// DefaultModelIdlePeriod is the time without any activity after which a
+// model is considered idle, if no period is configured.
+const DefaultModelIdlePeriod = 30 * 24 * time.Hour
+
+func (j *JIMM) modelIdlePeriod() time.Duration {
+	if j.ModelIdlePeriod > 0 {
+		return j.ModelIdlePeriod
+	}
+	return DefaultModelIdlePeriod
+}
+
+// RecordModelConnection records that a user has connected to the given
+// model. Failures are logged.
+func (j *JIMM) RecordModelConnection(ctx context.Context, m *dbmodel.Model) {
+	if err := j.Database.SetModelLastConnection(ctx, m, time.Now().UTC()); err != nil {
+		zapctx.Error(ctx, "failed to record model connection", zap.String("model", m.UUID.String), zap.Error(err))
+	}
+}
+
+// idleModels returns the models that have not been active for at least
+// the given period, the longest idle first.
+func (j *JIMM) idleModels(ctx context.Context, period time.Duration, now time.Time) ([]dbmodel.Model, error) {
+	models, err := j.Database.ListIdleModels(ctx, now.Add(-period))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(models, func(i, k int) bool {
+		return models[i].LastActivity().Before(models[k].LastActivity())
+	})
+	return models, nil
+}
+
+// ListIdleModels returns the models that have had no unit changes and no
+// user connections for at least the given number of days, the longest
+// idle first. If idleDays is zero the configured model idle period is
+// used. Only JIMM administrators can perform this operation.
+func (j *JIMM) ListIdleModels(ctx context.Context, user *openfga.User, idleDays int) ([]apiparams.IdleModel, error) {
+	const op = errors.Op("jimm.ListIdleModels")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if idleDays < 0 {
+		return nil, errors.E(op, errors.CodeBadRequest, "idle days must not be negative")
+	}
+	period := j.modelIdlePeriod()
+	if idleDays > 0 {
+		period = time.Duration(idleDays) * 24 * time.Hour
+	}
+
+	now := time.Now()
+	models, err := j.idleModels(ctx, period, now)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	idle := make([]apiparams.IdleModel, 0, len(models))
+	for _, m := range models {
+		im := apiparams.IdleModel{
+			ModelTag:   m.ResourceTag().String(),
+			Name:       m.Name,
+			Owner:      m.OwnerIdentityName,
+			Controller: m.Controller.Name,
+			IdleDays:   int(now.Sub(m.LastActivity()).Hours() / 24),
+		}
+		if m.LastUnitChangeAt.Valid {
+			t := m.LastUnitChangeAt.Time.UTC()
+			im.LastUnitChange = &t
+		}
+		if m.LastConnectionAt.Valid {
+			t := m.LastConnectionAt.Time.UTC()
+			im.LastConnection = &t
+		}
+		idle = append(idle, im)
+	}
+	return idle, nil
+}
+
+// NotifyIdleModels sends a notification naming the owner of each model
+// that has not been active for the configured model idle period. The
+// notification suggests archiving and destroying the model.
+func (j *JIMM) NotifyIdleModels(ctx context.Context) error {
+	const op = errors.Op("jimm.NotifyIdleModels")
+
+	now := time.Now()
+	models, err := j.idleModels(ctx, j.modelIdlePeriod(), now)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for _, m := range models {
+		days := int(now.Sub(m.LastActivity()).Hours() / 24)
+		j.Notifier.Notify(ctx, notify.Event{
+			Kind:       notify.ModelIdle,
+			Controller: m.Controller.Name,
+			Model:      m.ResourceTag().String(),
+			Owner:      m.OwnerIdentityName,
+			Message:    fmt.Sprintf("model %s/%s has not been used for %d days, consider archiving and destroying it with ArchiveAndDestroyModel", m.OwnerIdentityName, m.Name, days),
+		})
+	}
+	return nil
+}
+
+// ArchiveAndDestroyModel exports the model with the given tag as a
+// bundle, stores the bundle in the object store and then destroys the
+// model. The key of the stored bundle is returned. Only model
+// administrators can perform this operation, and only when an object
+// store is configured.
+func (j *JIMM) ArchiveAndDestroyModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (string, error) {
+	const op = errors.Op("jimm.ArchiveAndDestroyModel")
+
+	if j.ObjectStore == nil {
+		return "", errors.E(op, errors.CodeNotSupported, "model archives require an object store")
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return "", errors.E(op, err)
+	}
+	if j.getModelAccess(ctx, user, mt) != "admin" {
+		return "", errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	api, err := j.dial(ctx, &m.Controller, mt)
+	if err != nil {
+		return "", errors.E(op, err)
+	}
+	bundle, err := api.ExportBundle(ctx, false)
+	api.Close()
+	if err != nil {
+		return "", errors.E(op, err, "cannot export model")
+	}
+
+	key := objectstore.Key(objectstore.KindModelArchive, fmt.Sprintf("%s/%s.yaml", mt.Id(), time.Now().UTC().Format("20060102T150405Z")))
+	if err := j.ObjectStore.Put(ctx, key, "application/x-yaml", strings.NewReader(bundle), int64(len(bundle))); err != nil {
+		return "", errors.E(op, err, "cannot store model archive")
+	}
+	zapctx.Info(ctx, "model archived", zap.String("model", mt.Id()), zap.String("key", key))
+
+	if err := j.DestroyModel(ctx, user, mt, nil, nil, nil, nil); err != nil {
+		return "", errors.E(op, err, fmt.Sprintf("model archived to %s but not destroyed", key))
+	}
+	return key, nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestIdleModels(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	ch := new(recordingChannel)
+	notifier, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	notifier.AddChannel(ch, 0)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Notifier:        notifier,
+		ModelIdlePeriod: 7 * 24 * time.Hour,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// The model was created 60 days ago, its units last changed 10 days
+	// ago.
+	model := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	now := time.Now().UTC()
+	err = j.Database.DB.Model(&dbmodel.Model{}).Where("id = ?", model.ID).UpdateColumns(map[string]interface{}{
+		"created_at":          now.Add(-60 * 24 * time.Hour),
+		"last_unit_change_at": now.Add(-10*24*time.Hour - time.Hour),
+	}).Error
+	c.Assert(err, qt.IsNil)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.ListIdleModels(ctx, openfga.NewUser(bob, client), 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	admin, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	adminUser := openfga.NewUser(admin, client)
+	adminUser.JimmAdmin = true
+
+	_, err = j.ListIdleModels(ctx, adminUser, -1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	idle, err := j.ListIdleModels(ctx, adminUser, 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(idle, qt.HasLen, 1)
+	c.Check(idle[0].ModelTag, qt.Equals, names.NewModelTag(model.UUID.String).String())
+	c.Check(idle[0].Owner, qt.Equals, "alice@canonical.com")
+	c.Check(idle[0].Controller, qt.Equals, "controller-1")
+	c.Check(idle[0].IdleDays, qt.Equals, 10)
+	c.Check(idle[0].LastUnitChange, qt.Not(qt.IsNil))
+	c.Check(idle[0].LastConnection, qt.IsNil)
+
+	idle, err = j.ListIdleModels(ctx, adminUser, 14)
+	c.Assert(err, qt.IsNil)
+	c.Check(idle, qt.HasLen, 0)
+
+	err = j.NotifyIdleModels(ctx)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	c.Assert(ch.events, qt.HasLen, 1)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.ModelIdle)
+	c.Check(ch.events[0].Owner, qt.Equals, "alice@canonical.com")
+	c.Check(ch.events[0].Model, qt.Equals, names.NewModelTag(model.UUID.String).String())
+
+	// A user connection makes the model active again.
+	j.RecordModelConnection(ctx, &model)
+	idle, err = j.ListIdleModels(ctx, adminUser, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(idle, qt.HasLen, 0)
+}
+
+func TestArchiveAndDestroyModel(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	destroyed := false
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			ExportBundle_: func(context.Context, bool) (string, error) {
+				return "applications: {}\n", nil
+			},
+			DestroyModel_: func(context.Context, names.ModelTag, *bool, *bool, *time.Duration, *time.Duration) error {
+				destroyed = true
+				return nil
+			},
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+
+	_, err = j.ArchiveAndDestroyModel(ctx, alice, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotSupported)
+
+	store := &jimmtest.InMemoryObjectStore{}
+	j.ObjectStore = store
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	_, err = j.ArchiveAndDestroyModel(ctx, openfga.NewUser(&bobIdentity, client), mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	c.Check(destroyed, qt.IsFalse)
+
+	key, err := j.ArchiveAndDestroyModel(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.IsTrue)
+	c.Check(strings.HasPrefix(key, objectstore.Key(objectstore.KindModelArchive, mt.Id()+"/")), qt.IsTrue)
+
+	r, info, err := store.Get(ctx, key)
+	c.Assert(err, qt.IsNil)
+	defer r.Close()
+	body, err := io.ReadAll(r)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(body), qt.Equals, "applications: {}\n")
+	c.Check(info.ContentType, qt.Equals, "application/x-yaml")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Dying.String())
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+)
+
+// CheckDatabaseIndexes checks that the indexes required by JIMM's
+// frequently run queries, see db.RequiredIndexes, exist in the database.
+// If any are missing a warning is logged and a notification sent and,
+// if build is true, the missing indexes are built without locking their
+// tables against writes. Building an index on a large table can take a
+// long time, so this is intended to be run in the background.
+func (j *JIMM) CheckDatabaseIndexes(ctx context.Context, build bool) error {
+	const op = errors.Op("jimm.CheckDatabaseIndexes")
+
+	missing, err := j.Database.MissingIndexes(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	defs := make([]string, len(missing))
+	for i, idx := range missing {
+		defs[i] = idx.String()
+		zapctx.Warn(ctx, "required database index missing", zap.Stringer("index", idx), zap.String("query", idx.Query))
+	}
+	j.Notifier.Notify(ctx, notify.Event{
+		Kind:    notify.DatabaseIndexMissing,
+		Message: fmt.Sprintf("required database indexes missing: %s", strings.Join(defs, "; ")),
+	})
+	if !build {
+		return nil
+	}
+
+	for _, idx := range missing {
+		zapctx.Info(ctx, "building database index", zap.Stringer("index", idx))
+		if err := j.Database.CreateIndex(ctx, idx); err != nil {
+			return errors.E(op, err, fmt.Sprintf("cannot build index %s", idx.Name))
+		}
+		zapctx.Info(ctx, "built database index", zap.Stringer("index", idx))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+)
+
+func TestCheckDatabaseIndexes(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	ch := new(recordingChannel)
+	notifier, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	notifier.AddChannel(ch, 0)
+
+	j := &jimm.JIMM{
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Notifier: notifier,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// Without building, missing indexes are only reported.
+	err = j.CheckDatabaseIndexes(ctx, false)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	c.Assert(ch.events, qt.HasLen, 1)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.DatabaseIndexMissing)
+	c.Check(ch.events[0].Message, qt.Matches, `required database indexes missing: idx_models_controller_id ON models \(controller_id\)`)
+	missing, err := j.Database.MissingIndexes(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(missing, qt.HasLen, 1)
+
+	err = j.CheckDatabaseIndexes(ctx, true)
+	c.Assert(err, qt.IsNil)
+	missing, err = j.Database.MissingIndexes(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(missing, qt.HasLen, 0)
+
+	// Once all the indexes exist nothing is reported.
+	err = j.CheckDatabaseIndexes(ctx, true)
+	c.Assert(err, qt.IsNil)
+	notifier.Wait()
+	c.Check(ch.events, qt.HasLen, 2)
+}
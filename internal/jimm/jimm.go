@@ -7,9 +7,13 @@ package jimm
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
@@ -29,6 +33,8 @@ import (
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimm/credentials"
 	"github.com/canonical/jimm/v3/internal/jimmjwx"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/objectstore"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
@@ -86,6 +92,185 @@ type JIMM struct {
 	// OAuthAuthenticator is responsible for handling authentication
 	// via OAuth2.0 AND JWT access tokens to JIMM.
 	OAuthAuthenticator OAuthAuthenticator
+
+	// Cache holds the cache of frequently read data. If this is nil
+	// nothing is cached.
+	Cache *ResponseCache
+
+	// Health keeps the rolling health of the controllers, used to
+	// avoid placing models on degraded controllers. If this is nil all
+	// controllers are considered healthy.
+	Health *ControllerHealth
+
+	// Latency holds the measured latencies of the cloud regions and
+	// controllers, used to place latency sensitive models. If this is
+	// nil no latencies are measured.
+	Latency *LatencyProber
+
+	// Quotas holds the limits on the resources used by each user's
+	// models. Only the model limit is enforced, when models are added.
+	Quotas QuotaLimits
+
+	// MaxControllerModels is the maximum number of models a controller
+	// may host for migration prechecks to consider it to have capacity
+	// for another model. If this is zero there is no limit.
+	MaxControllerModels int
+
+	// AccessRequestNotifier is notified when access requests are made,
+	// approved or denied. If this is nil no notifications are sent.
+	AccessRequestNotifier AccessRequestNotifier
+
+	// Notifier is notified when cloud credentials repeatedly fail to be
+	// updated on controllers, controller model credentials are invalid
+	// or expiring, or controller certificates are expiring. If this is
+	// nil no notifications are sent.
+	Notifier *notify.Notifier
+
+	// WebhookSecretGracePeriod is the time after a webhook's signing
+	// secret is rotated during which deliveries are also signed with
+	// the previous secret. If this is zero
+	// DefaultWebhookSecretGracePeriod is used.
+	WebhookSecretGracePeriod time.Duration
+
+	// ControllerCredentialExpiryWarning is the period before a
+	// controller model credential expires in which it is reported as
+	// expiring. If this is zero DefaultControllerCredentialExpiryWarning
+	// is used.
+	ControllerCredentialExpiryWarning time.Duration
+
+	// ControllerCertificateExpiryWarning is the period before a
+	// controller's TLS certificate expires in which it is reported as
+	// expiring. If this is zero CertificateExpiryWarningPeriod is used.
+	ControllerCertificateExpiryWarning time.Duration
+
+	// ModelIdlePeriod is the time without any activity after which a
+	// model is considered idle. If this is zero DefaultModelIdlePeriod
+	// is used.
+	ModelIdlePeriod time.Duration
+
+	// ModelDestroyWindow is the time a requested model destroy is left
+	// pending, during which the model's owner or a JIMM administrator
+	// may cancel it. If this is zero models are destroyed immediately.
+	ModelDestroyWindow time.Duration
+
+	// MetricLabels, if set, determines the organisational labels
+	// attached to the metrics exported about controllers and models.
+	MetricLabels *MetricLabeler
+
+	// GroupSync configures the synchronisation of group memberships
+	// from an external directory.
+	GroupSync GroupSyncConfig
+
+	// Charmhub provides information about the charms published in
+	// Charmhub. It is used to enforce CharmPolicy and to report
+	// applications using outdated charms. If this is nil charms are not
+	// checked.
+	Charmhub Charmhub
+
+	// CharmPolicy holds the policy applied to the charms deployed
+	// through JIMM.
+	CharmPolicy CharmPolicy
+
+	// MaxOfferConsumers is the maximum number of models that may consume
+	// any application offer. Offer administrators may set a lower limit
+	// on their offers. If this is zero there is no limit.
+	MaxOfferConsumers int
+
+	// MaxInlineResultSize is the maximum size, in bytes, of the results
+	// of model dumps and bundle exports returned inline in the RPC
+	// response. Larger results are stored to be downloaded out of band,
+	// see StoreOversizedResult. If this is zero there is no limit.
+	MaxInlineResultSize int
+
+	// ResultDownloadURL is the base URL oversized results are downloaded
+	// from, normally the /downloads endpoint of this JIMM. If this is
+	// empty oversized results are rejected.
+	ResultDownloadURL string
+
+	// ResultDownloadTTL is the time for which an oversized result may be
+	// downloaded. If this is zero a default of 15 minutes is used.
+	ResultDownloadTTL time.Duration
+
+	// MigrationTimeout is the time after which a model migration between
+	// JIMM's controllers whose outcome cannot be determined is
+	// abandoned. If this is zero DefaultMigrationTimeout is used.
+	MigrationTimeout time.Duration
+
+	// ObjectStore is the store holding the artifacts JIMM produces, such
+	// as oversized results and audit log exports. If this is nil
+	// oversized results are kept in the database and audit log entries
+	// are pruned without being exported.
+	ObjectStore objectstore.Store
+
+	// TunablesFile is the path of the YAML file holding the tunables,
+	// the configuration that may be reloaded without restarting JIMM.
+	// If this is empty the tunables cannot be reloaded.
+	TunablesFile string
+
+	// tunablesMu protects the tunable settings, Quotas,
+	// MaxControllerModels and CharmPolicy, when they are reloaded.
+	tunablesMu sync.RWMutex
+
+	// tunablesBase holds the tunable settings JIMM had before the
+	// tunables were first loaded.
+	tunablesBase *tunableSettings
+
+	// CredentialUpdateConcurrency is the maximum number of controllers
+	// a cloud credential is checked or updated on at once. If this is
+	// zero DefaultCredentialUpdateConcurrency is used.
+	CredentialUpdateConcurrency int
+
+	// CredentialPropagationPolicies holds the credential propagation
+	// policy of each cloud, keyed by cloud name. Clouds without a
+	// policy use CredentialPropagationEager.
+	CredentialPropagationPolicies map[string]CredentialPropagationPolicy
+
+	// CredentialRemovalDeadline is the time after a cloud credential is
+	// revoked by which every controller it was copied to should be
+	// confirmed to have removed it, an alert is sent for controllers
+	// that have not. If this is zero DefaultCredentialRemovalDeadline is
+	// used.
+	CredentialRemovalDeadline time.Duration
+
+	// Scheduler holds the periodic jobs run in the background, it is
+	// used to list the jobs and their run history and to enable or
+	// disable them. If this is nil there are no scheduled jobs.
+	Scheduler *Scheduler
+
+	// CredentialRotators holds the hooks used to compute the rotated
+	// attributes of cloud credentials, keyed by cloud provider type,
+	// when a rotation does not give the new attribute values. See
+	// RotateCloudCredentials.
+	CredentialRotators map[string]CredentialRotator
+
+	// AllowDuplicateControllerUUIDs allows the same controller to be
+	// added more than once under different names. This is only intended
+	// for testing, where a single juju controller stands in for several.
+	AllowDuplicateControllerUUIDs bool
+
+	// ControllerServiceUsers makes JIMM provision a dedicated service
+	// user on each controller it adds, and connect to the controller as
+	// that user rather than with the credentials the controller was
+	// added with. The service user is a controller superuser, Juju's
+	// access levels do not allow JIMM to manage a controller with less.
+	// See ProvisionControllerServiceUser.
+	ControllerServiceUsers bool
+
+	// modelCreations holds the model creations in progress, so that
+	// they may be cancelled.
+	modelCreations modelCreations
+
+	// proxySessions holds the client sessions proxied to controllers,
+	// so that they may be drained.
+	proxySessions proxySessions
+
+	// credentialFailures counts the consecutive failures to update
+	// credentials on controllers.
+	credentialFailures credentialFailures
+
+	// operations tracks the long-running operations running on this
+	// JIMM replica.
+	operations sync.WaitGroup
 }
 
 // ResourceTag returns JIMM's controller tag stating its UUID.
@@ -191,7 +376,10 @@ func (j *JIMM) dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names
 		}
 	}
 
-	return j.Dialer.Dial(ctx, ctl, modelTag, permissionMap)
+	start := time.Now()
+	api, err := j.Dialer.Dial(ctx, ctl, modelTag, permissionMap)
+	j.Health.ObserveRequest(ctl.Name, time.Since(start), err)
+	return api, err
 }
 
 // A Dialer provides a connection to a controller.
@@ -199,9 +387,9 @@ type Dialer interface {
 	// Dial creates an API connection to a controller. If the given
 	// model-tag is non-zero the connection will be to that model,
 	// otherwise the connection is to the controller. After successfully
-	// dialing the controller the UUID, AgentVersion and HostPorts fields
-	// in the given controller should be updated to the values provided
-	// by the controller.
+	// dialing the controller the UUID, AgentVersion, HostPorts and
+	// CertificateExpiry fields in the given controller should be updated
+	// to the values provided by the controller.
 	Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, requiredPermissions map[string]string) (API, error)
 }
 
@@ -214,6 +402,10 @@ type API interface {
 	// AddCloud adds a new cloud.
 	AddCloud(context.Context, names.CloudTag, jujuparams.Cloud, bool) error
 
+	// AddUser adds a local user with the given name and password to the
+	// controller.
+	AddUser(ctx context.Context, username, password string) error
+
 	// AllModelWatcherNext returns the next set of deltas from an
 	// all-model watcher.
 	AllModelWatcherNext(context.Context, string) ([]jujuparams.Delta, error)
@@ -241,6 +433,13 @@ type API interface {
 	// Clouds returns the set of clouds supported by the controller.
 	Clouds(context.Context) (map[names.CloudTag]jujuparams.Cloud, error)
 
+	// ControllerCall calls the given method of a controller facade,
+	// filling in the given response.
+	ControllerCall(ctx context.Context, facade string, version int, method string, args, resp interface{}) error
+
+	// ControllerConfig returns the configuration of the controller.
+	ControllerConfig(context.Context) (map[string]interface{}, error)
+
 	// ControllerModelSummary fetches the model summary of the model on the
 	// controller that hosts the controller machines.
 	ControllerModelSummary(context.Context, *jujuparams.ModelSummary) error
@@ -248,6 +447,10 @@ type API interface {
 	// CreateModel creates a new model.
 	CreateModel(context.Context, *jujuparams.ModelCreateArgs, *jujuparams.ModelInfo) error
 
+	// Credential fetches the given credential from the controller,
+	// without its secret attributes.
+	Credential(context.Context, names.CloudCredentialTag) (jujuparams.CloudCredential, error)
+
 	// DestroyApplicationOffer destroys an application offer.
 	DestroyApplicationOffer(context.Context, string, bool) error
 
@@ -263,6 +466,10 @@ type API interface {
 	// DumpModelDB collects a database dump of a model.
 	DumpModelDB(context.Context, names.ModelTag) (map[string]interface{}, error)
 
+	// ExportBundle exports the model the connection is made to as a
+	// bundle in YAML format.
+	ExportBundle(context.Context, bool) (string, error)
+
 	// FindApplicationOffers finds application offers that match the
 	// filter.
 	FindApplicationOffers(context.Context, []jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
@@ -282,6 +489,9 @@ type API interface {
 	// GrantCloudAccess grants cloud access to a user.
 	GrantCloudAccess(context.Context, names.CloudTag, names.UserTag, string) error
 
+	// GrantControllerAccess grants controller access to a user.
+	GrantControllerAccess(context.Context, names.UserTag, string) error
+
 	// GrantJIMMModelAdmin makes the JIMM user an admin on a model.
 	GrantJIMMModelAdmin(context.Context, names.ModelTag) error
 
@@ -295,6 +505,10 @@ type API interface {
 	// filter.
 	ListApplicationOffers(context.Context, []jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 
+	// MigrationTargetPrechecks checks whether the controller can accept
+	// the migration of the described model.
+	MigrationTargetPrechecks(context.Context, jujuparams.MigrationModelInfo) error
+
 	// ModelInfo fetches a model's ModelInfo.
 	ModelInfo(context.Context, *jujuparams.ModelInfo) error
 
@@ -337,6 +551,18 @@ type API interface {
 	// RevokeModelAccess revokes model access from a user.
 	RevokeModelAccess(context.Context, names.ModelTag, names.UserTag, jujuparams.UserAccessPermission) error
 
+	// SetModelConfig sets configuration on the model the connection is
+	// made to.
+	SetModelConfig(context.Context, map[string]interface{}) error
+
+	// SetUserPassword sets the password of a local user on the
+	// controller.
+	SetUserPassword(ctx context.Context, username, password string) error
+
+	// SupportedFacadeVersions returns the versions of each facade
+	// supported by the controller.
+	SupportedFacadeVersions() map[string][]int
+
 	// SupportsCheckCredentialModels returns true if the
 	// CheckCredentialModels method can be used.
 	SupportsCheckCredentialModels() bool
@@ -348,12 +574,20 @@ type API interface {
 	// Status returns the status of the juju model.
 	Status(ctx context.Context, patterns []string) (*jujuparams.FullStatus, error)
 
+	// UnsetModelConfig resets configuration keys of the model the
+	// connection is made to to their default values.
+	UnsetModelConfig(context.Context, []string) error
+
 	// UpdateCloud updates a cloud definition.
 	UpdateCloud(context.Context, names.CloudTag, jujuparams.Cloud) error
 
 	// UpdateCredential updates a credential.
 	UpdateCredential(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error)
 
+	// UserInfo returns the local users defined on the controller,
+	// including disabled users.
+	UserInfo(context.Context) ([]jujuparams.UserInfo, error)
+
 	// ValidateModelUpgrade validates that a model can be upgraded.
 	ValidateModelUpgrade(context.Context, names.ModelTag, bool) error
 
@@ -473,11 +707,7 @@ func (j *JIMM) ListControllers(ctx context.Context, user *openfga.User) ([]dbmod
 		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
-	var controllers []dbmodel.Controller
-	err := j.Database.ForEachController(ctx, func(c *dbmodel.Controller) error {
-		controllers = append(controllers, *c)
-		return nil
-	})
+	controllers, err := j.getControllers(ctx)
 	if err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -489,6 +719,7 @@ func (j *JIMM) ListControllers(ctx context.Context, user *openfga.User) ([]dbmod
 // No new models or clouds can be added to a deprecated controller.
 func (j *JIMM) SetControllerDeprecated(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error {
 	const op = errors.Op("jimm.SetControllerDeprecated")
+	defer j.Cache.InvalidateControllers()
 
 	if !user.JimmAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
@@ -514,9 +745,103 @@ func (j *JIMM) SetControllerDeprecated(ctx context.Context, user *openfga.User,
 	return nil
 }
 
+// controllerTierRegexp matches valid controller tier names.
+var controllerTierRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// SetControllerTiers sets the tiers the named controller belongs to. Only
+// JIMM administrators may set controller tiers. Models that request a
+// tier are only placed on controllers in that tier.
+func (j *JIMM) SetControllerTiers(ctx context.Context, user *openfga.User, controllerName string, tiers []string) error {
+	const op = errors.Op("jimm.SetControllerTiers")
+	defer j.Cache.InvalidateControllers()
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	for _, tier := range tiers {
+		if !controllerTierRegexp.MatchString(tier) {
+			return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid controller tier %q", tier))
+		}
+	}
+	tiers = slices.Clone(tiers)
+	slices.Sort(tiers)
+	tiers = slices.Compact(tiers)
+
+	err := j.Database.Transaction(func(db *db.Database) error {
+		c := dbmodel.Controller{
+			Name: controllerName,
+		}
+		if err := db.GetController(ctx, &c); err != nil {
+			return err
+		}
+		c.Tiers = tiers
+		return db.UpdateController(ctx, &c)
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ControllerTimeouts holds the timeouts JIMM uses when communicating with
+// a controller. A zero value means the default is used.
+type ControllerTimeouts struct {
+	// Dial is the maximum time allowed to connect to the controller.
+	Dial time.Duration
+
+	// RPC is the maximum time JIMM waits for responses to the login and
+	// keepalive requests it sends to the controller.
+	RPC time.Duration
+
+	// KeepaliveInterval is the interval at which JIMM checks that its
+	// connections to the controller are alive.
+	KeepaliveInterval time.Duration
+}
+
+// minKeepaliveInterval is the smallest keepalive interval that may be
+// configured for a controller.
+const minKeepaliveInterval = time.Second
+
+// SetControllerTimeouts sets the timeouts JIMM uses when communicating
+// with the named controller. Only JIMM administrators may set controller
+// timeouts. The new timeouts are used for connections made after the
+// update.
+func (j *JIMM) SetControllerTimeouts(ctx context.Context, user *openfga.User, controllerName string, timeouts ControllerTimeouts) error {
+	const op = errors.Op("jimm.SetControllerTimeouts")
+	defer j.Cache.InvalidateControllers()
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if timeouts.Dial < 0 || timeouts.RPC < 0 || timeouts.KeepaliveInterval < 0 {
+		return errors.E(op, errors.CodeBadRequest, "timeouts must not be negative")
+	}
+	if timeouts.KeepaliveInterval != 0 && timeouts.KeepaliveInterval < minKeepaliveInterval {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("keepalive interval must be at least %s", minKeepaliveInterval))
+	}
+
+	err := j.Database.Transaction(func(db *db.Database) error {
+		c := dbmodel.Controller{
+			Name: controllerName,
+		}
+		if err := db.GetController(ctx, &c); err != nil {
+			return err
+		}
+		c.DialTimeout = timeouts.Dial
+		c.RPCTimeout = timeouts.RPC
+		c.KeepaliveInterval = timeouts.KeepaliveInterval
+		return db.UpdateController(ctx, &c)
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
 // RemoveController removes a controller.
 func (j *JIMM) RemoveController(ctx context.Context, user *openfga.User, controllerName string, force bool) error {
 	const op = errors.Op("jimm.RemoveController")
+	defer j.Cache.InvalidateControllers()
 
 	if !user.JimmAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
@@ -650,10 +975,30 @@ func (j *JIMM) InitiateInternalMigration(ctx context.Context, user *openfga.User
 	if err != nil {
 		return jujuparams.InitiateMigrationResult{}, errors.E(op, err)
 	}
+	target := dbmodel.Controller{
+		Name: targetController,
+	}
+	err = j.Database.GetController(ctx, &target)
+	if err != nil {
+		return jujuparams.InitiateMigrationResult{}, errors.E(op, err)
+	}
+	api, err := j.dial(ctx, &target, names.ModelTag{})
+	if err != nil {
+		return jujuparams.InitiateMigrationResult{}, errors.E(op, err)
+	}
+	defer api.Close()
+	// Make sure the target controller knows the model's credential
+	// before the migration starts.
+	rollback, err := j.mapMigrationCredential(ctx, api, &model, &target)
+	if err != nil {
+		return jujuparams.InitiateMigrationResult{}, errors.E(op, err)
+	}
 	spec := jujuparams.MigrationSpec{ModelTag: modelTag.String(), TargetInfo: migrationTarget}
 	result, err := initiateMigration(ctx, j, user, spec)
 	if err != nil {
+		rollback()
 		return result, errors.E(op, err)
 	}
+	j.trackModelMigration(ctx, user, &model, &target, result.MigrationId)
 	return result, nil
 }
@@ -849,6 +849,10 @@ controllers:
   uuid: 00000001-0000-0000-0000-000000000001
   cloud: test-cloud
   region: test-cloud-region
+- name: otherController
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
 models:
   - name: model-1
     type: iaas
@@ -872,27 +876,63 @@ func TestInitiateInternalMigration(t *testing.T) {
 	now := time.Now().UTC().Round(time.Millisecond)
 
 	tests := []struct {
-		about         string
-		user          string
-		migrateInfo   params.MigrateModelInfo
-		expectedError string
+		about            string
+		user             string
+		migrateInfo      params.MigrateModelInfo
+		migrationError   error
+		updateCredential func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error)
+		expectedError    string
+		expectedUploaded []string
+		expectedRevoked  []string
 	}{{
-		about:       "success",
-		user:        "alice@canonical.com",
-		migrateInfo: params.MigrateModelInfo{ModelTag: "model-00000002-0000-0000-0000-000000000001", TargetController: "myController"},
+		about:            "success",
+		user:             "alice@canonical.com",
+		migrateInfo:      params.MigrateModelInfo{ModelTag: "model-00000002-0000-0000-0000-000000000001", TargetController: "myController"},
+		expectedUploaded: []string{"cloudcred-test-cloud_alice@canonical.com_cred-1"},
 	}, {
 		about:         "model doesn't exist",
 		user:          "alice@canonical.com",
 		migrateInfo:   params.MigrateModelInfo{ModelTag: "model-00000002-0000-0000-0000-000000000002", TargetController: "myController"},
 		expectedError: "model not found",
+	}, {
+		about:       "credential upload fails",
+		user:        "alice@canonical.com",
+		migrateInfo: params.MigrateModelInfo{ModelTag: "model-00000002-0000-0000-0000-000000000001", TargetController: "otherController"},
+		updateCredential: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, errors.New("credential rejected")
+		},
+		expectedError: "credential rejected",
+	}, {
+		about:            "migration fails and the credential is removed",
+		user:             "alice@canonical.com",
+		migrateInfo:      params.MigrateModelInfo{ModelTag: "model-00000002-0000-0000-0000-000000000001", TargetController: "otherController"},
+		migrationError:   errors.New("migration failed"),
+		expectedError:    "migration failed",
+		expectedUploaded: []string{"cloudcred-test-cloud_alice@canonical.com_cred-1"},
+		expectedRevoked:  []string{"cloudcred-test-cloud_alice@canonical.com_cred-1"},
+	}, {
+		about:            "migration fails and the credential is in use",
+		user:             "alice@canonical.com",
+		migrateInfo:      params.MigrateModelInfo{ModelTag: "model-00000002-0000-0000-0000-000000000001", TargetController: "myController"},
+		migrationError:   errors.New("migration failed"),
+		expectedError:    "migration failed",
+		expectedUploaded: []string{"cloudcred-test-cloud_alice@canonical.com_cred-1"},
 	},
 	}
 	for _, test := range tests {
 		c.Run(test.about, func(c *qt.C) {
 
 			c.Patch(jimm.InitiateMigration, func(ctx context.Context, j *jimm.JIMM, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error) {
-				return jujuparams.InitiateMigrationResult{}, nil
+				return jujuparams.InitiateMigrationResult{}, test.migrationError
 			})
+			var uploaded, revoked []string
+			updateCredential := test.updateCredential
+			if updateCredential == nil {
+				updateCredential = func(_ context.Context, cred jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					uploaded = append(uploaded, cred.Tag)
+					return nil, nil
+				}
+			}
 			store := jimmtest.NewInMemoryCredentialStore()
 			err := store.PutControllerCredentials(context.Background(), test.migrateInfo.TargetController, "admin", "test-secret")
 			c.Assert(err, qt.IsNil)
@@ -903,6 +943,15 @@ func TestInitiateInternalMigration(t *testing.T) {
 					DB: jimmtest.PostgresDB(c, func() time.Time { return now }),
 				},
 				CredentialStore: store,
+				Dialer: &jimmtest.Dialer{
+					API: &jimmtest.API{
+						UpdateCredential_: updateCredential,
+						RevokeCredential_: func(_ context.Context, tag names.CloudCredentialTag) error {
+							revoked = append(revoked, tag.String())
+							return nil
+						},
+					},
+				},
 			}
 			err = j.Database.Migrate(ctx, false)
 			c.Assert(err, qt.IsNil)
@@ -922,6 +971,8 @@ func TestInitiateInternalMigration(t *testing.T) {
 				c.Assert(err, qt.IsNil)
 				c.Assert(res, qt.DeepEquals, jujuparams.InitiateMigrationResult{})
 			}
+			c.Check(uploaded, qt.DeepEquals, test.expectedUploaded)
+			c.Check(revoked, qt.DeepEquals, test.expectedRevoked)
 		})
 	}
 }
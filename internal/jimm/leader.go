@@ -0,0 +1,215 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// defaultLeaseDuration is the lease duration used by a LeaderElector
+// when none is configured.
+const defaultLeaseDuration = 30 * time.Second
+
+// A LeaderElector runs background workers that must only run on a single
+// JIMM replica at a time. Each registered worker has its own lease in
+// the database; the replica holding the lease runs the worker and renews
+// the lease periodically. If that replica stops, or can no longer renew
+// the lease, another replica takes over once the lease expires.
+//
+// Lease expiry is compared using the clocks of the JIMM replicas, these
+// are expected to be kept in sync. Should they drift apart two replicas
+// may run the same worker for a time, the controller watcher guards
+// against this by claiming each controller it monitors, see
+// db.Database.ClaimControllerMonitor.
+type LeaderElector struct {
+	// Database is the database holding the leases.
+	Database db.Database
+
+	// Holder identifies this replica, it must be unique amongst all
+	// replicas sharing the database.
+	Holder string
+
+	// LeaseDuration is the length of time a lease is held for without
+	// being renewed. Leases are renewed at a third of this interval. If
+	// this is zero a default of 30 seconds is used.
+	LeaseDuration time.Duration
+
+	workers []leaderWorker
+}
+
+// A leaderWorker is a background worker registered with a LeaderElector.
+type leaderWorker struct {
+	name string
+	f    func(context.Context) error
+}
+
+// Register registers a background worker with the given name. Whilst
+// this replica holds the worker's lease f is called with a context that
+// is canceled when the lease is lost. If f returns an error it is called
+// again at the next lease renewal, if it returns nil it is not called
+// again until the lease is lost and reacquired. Register must be called
+// before Run.
+func (e *LeaderElector) Register(name string, f func(context.Context) error) {
+	e.workers = append(e.workers, leaderWorker{name: name, f: f})
+}
+
+// Run contends for the leases of all registered workers, running each
+// worker for which this replica holds the lease. Run finishes when the
+// given context is canceled, at which point all running workers are
+// stopped and their leases released.
+func (e *LeaderElector) Run(ctx context.Context) error {
+	const op = errors.Op("jimm.LeaderElector.Run")
+	if e.Holder == "" {
+		return errors.E(op, errors.CodeServerConfiguration, "leader elector holder not configured")
+	}
+
+	r := newRunner()
+	for _, w := range e.workers {
+		w := w
+		r.run(w.name, func() {
+			e.runWorker(ctx, w)
+		})
+	}
+	<-ctx.Done()
+	r.wait()
+	return nil
+}
+
+// runWorker runs the election loop for a single worker until the given
+// context is canceled.
+func (e *LeaderElector) runWorker(ctx context.Context, w leaderWorker) {
+	ctx = zapctx.WithFields(ctx, zap.String("worker", w.name), zap.String("holder", e.Holder))
+
+	d := e.LeaseDuration
+	if d == 0 {
+		d = defaultLeaseDuration
+	}
+	ticker := time.NewTicker(d / 3)
+	defer ticker.Stop()
+
+	var heldUntil time.Time
+	var run *workerRun
+	defer func() {
+		if run != nil {
+			run.stop()
+		}
+		// The context is canceled, use a fresh one to release the lease
+		// so that another replica can take over immediately.
+		err := e.Database.ReleaseLease(context.Background(), &dbmodel.Lease{Name: w.name, Holder: e.Holder})
+		if err != nil {
+			zapctx.Warn(ctx, "cannot release lease", zap.Error(err))
+		}
+	}()
+
+	for {
+		now := time.Now()
+		lease := dbmodel.Lease{
+			Name:    w.name,
+			Holder:  e.Holder,
+			Expires: now.Add(d),
+		}
+		acquired, err := e.Database.AcquireLease(ctx, &lease, now)
+		switch {
+		case err != nil:
+			// The lease might still be held, keep running the worker
+			// until it would have expired.
+			zapctx.Error(ctx, "cannot acquire lease", zap.Error(err))
+		case acquired:
+			heldUntil = lease.Expires
+		default:
+			heldUntil = time.Time{}
+		}
+		leader := time.Now().Before(heldUntil)
+
+		switch {
+		case leader && run == nil:
+			zapctx.Info(ctx, "acquired leadership, starting worker")
+			run = startWorkerRun(ctx, w.f)
+		case leader && run.failed():
+			zapctx.Error(ctx, "worker failed, restarting", zap.Error(run.err))
+			run = startWorkerRun(ctx, w.f)
+		case !leader && run != nil:
+			zapctx.Warn(ctx, "lost leadership, stopping worker")
+			run.stop()
+			run = nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// A workerRun is a single run of a leader-elected worker.
+type workerRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+func startWorkerRun(ctx context.Context, f func(context.Context) error) *workerRun {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &workerRun{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		defer close(r.done)
+		r.err = f(ctx)
+	}()
+	return r
+}
+
+// failed reports whether the worker has finished with an error.
+func (r *workerRun) failed() bool {
+	select {
+	case <-r.done:
+		return r.err != nil
+	default:
+		return false
+	}
+}
+
+// stop cancels the worker and waits for it to finish.
+func (r *workerRun) stop() {
+	r.cancel()
+	<-r.done
+}
+
+// ListLeaders returns the current holder of every leader-elected
+// background worker's lease. Leases whose holder stopped without
+// releasing them are included until another replica takes them over.
+// Only JIMM administrators may list the leaders.
+func (j *JIMM) ListLeaders(ctx context.Context, user *openfga.User) ([]apiparams.LeaderInfo, error) {
+	const op = errors.Op("jimm.ListLeaders")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, err
+	}
+
+	leases, err := j.Database.ListLeases(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	leaders := make([]apiparams.LeaderInfo, len(leases))
+	for i, l := range leases {
+		leaders[i] = apiparams.LeaderInfo{
+			Worker:  l.Name,
+			Holder:  l.Holder,
+			Expires: l.Expires,
+		}
+	}
+	return leaders, nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestLeaderElectorFailover(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	database := db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	err := database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	// started receives the holder each time a worker is started.
+	started := make(chan string, 10)
+	newElector := func(holder string) *jimm.LeaderElector {
+		e := &jimm.LeaderElector{
+			Database:      database,
+			Holder:        holder,
+			LeaseDuration: 300 * time.Millisecond,
+		}
+		e.Register("test-worker", func(ctx context.Context) error {
+			started <- holder
+			<-ctx.Done()
+			return nil
+		})
+		return e
+	}
+
+	ctx1, cancel1 := context.WithCancel(ctx)
+	done1 := make(chan error)
+	go func() { done1 <- newElector("replica-1").Run(ctx1) }()
+
+	select {
+	case holder := <-started:
+		c.Assert(holder, qt.Equals, "replica-1")
+	case <-time.After(5 * time.Second):
+		c.Fatal("worker not started")
+	}
+
+	done2 := make(chan error)
+	go func() { done2 <- newElector("replica-2").Run(ctx) }()
+
+	// The second replica does not run the worker whilst the first holds
+	// the lease.
+	select {
+	case holder := <-started:
+		c.Fatalf("worker unexpectedly started on %s", holder)
+	case <-time.After(time.Second):
+	}
+
+	leases, err := database.ListLeases(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Assert(leases, qt.HasLen, 1)
+	c.Check(leases[0].Holder, qt.Equals, "replica-1")
+
+	// Stopping the first replica fails the worker over to the second.
+	cancel1()
+	c.Assert(<-done1, qt.IsNil)
+	select {
+	case holder := <-started:
+		c.Assert(holder, qt.Equals, "replica-2")
+	case <-time.After(5 * time.Second):
+		c.Fatal("worker not failed over")
+	}
+
+	cancel()
+	c.Assert(<-done2, qt.IsNil)
+	leases, err = database.ListLeases(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Check(leases, qt.HasLen, 0)
+}
+
+func TestLeaderElectorRestartsFailedWorker(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	database := db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	err := database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	runs := make(chan struct{}, 10)
+	e := jimm.LeaderElector{
+		Database:      database,
+		Holder:        "replica-1",
+		LeaseDuration: 300 * time.Millisecond,
+	}
+	e.Register("test-worker", func(ctx context.Context) error {
+		runs <- struct{}{}
+		return context.DeadlineExceeded
+	})
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			c.Fatal("worker not restarted")
+		}
+	}
+	cancel()
+	c.Assert(<-done, qt.IsNil)
+}
+
+func TestLeaderElectorNoHolder(t *testing.T) {
+	c := qt.New(t)
+
+	var e jimm.LeaderElector
+	err := e.Run(context.Background())
+	c.Check(err, qt.ErrorMatches, `leader elector holder not configured`)
+}
+
+func TestListLeaders(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Round(time.Millisecond)
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, func() time.Time { return now }),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	for _, name := range []string{"jwks-rotator", "controller-watcher"} {
+		acquired, err := j.Database.AcquireLease(ctx, &dbmodel.Lease{
+			Name:    name,
+			Holder:  "replica-1",
+			Expires: now.Add(time.Minute),
+		}, now)
+		c.Assert(err, qt.IsNil)
+		c.Assert(acquired, qt.IsTrue)
+	}
+
+	u, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(u, client)
+	admin.JimmAdmin = true
+
+	leaders, err := j.ListLeaders(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(leaders, qt.HasLen, 2)
+	c.Check(leaders[0].Worker, qt.Equals, "controller-watcher")
+	c.Check(leaders[0].Holder, qt.Equals, "replica-1")
+	c.Check(leaders[0].Expires.Equal(now.Add(time.Minute)), qt.IsTrue)
+	c.Check(leaders[1].Worker, qt.Equals, "jwks-rotator")
+
+	_, err = j.ListLeaders(ctx, openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, client))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// proxySessions tracks the client sessions this JIMM server is proxying
+// to each controller, so that they may be drained.
+type proxySessions struct {
+	mu       sync.Mutex
+	seq      uint64
+	sessions map[string]map[uint64]chan struct{}
+}
+
+// add records a session proxied to the controller with the given UUID.
+func (s *proxySessions) add(controllerUUID string) (uint64, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[string]map[uint64]chan struct{})
+	}
+	if s.sessions[controllerUUID] == nil {
+		s.sessions[controllerUUID] = make(map[uint64]chan struct{})
+	}
+	s.seq++
+	drain := make(chan struct{})
+	s.sessions[controllerUUID][s.seq] = drain
+	return s.seq, drain
+}
+
+// remove removes a session that has finished.
+func (s *proxySessions) remove(controllerUUID string, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions[controllerUUID], id)
+	if len(s.sessions[controllerUUID]) == 0 {
+		delete(s.sessions, controllerUUID)
+	}
+}
+
+// drain signals every session proxied to the controller with the given
+// UUID to drain and returns the number of sessions drained.
+func (s *proxySessions) drain(controllerUUID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.sessions[controllerUUID])
+	for _, drain := range s.sessions[controllerUUID] {
+		close(drain)
+	}
+	delete(s.sessions, controllerUUID)
+	return n
+}
+
+// RegisterProxySession records a client session proxied to the given
+// controller. The returned channel is closed when the session must be
+// drained because the controller entered maintenance. The returned
+// function must be called when the session finishes.
+func (j *JIMM) RegisterProxySession(ctl *dbmodel.Controller) (<-chan struct{}, func()) {
+	id, drain := j.proxySessions.add(ctl.UUID)
+	return drain, func() { j.proxySessions.remove(ctl.UUID, id) }
+}
+
+// ControllerMaintenanceError returns the error given to clients whose
+// requests cannot be served because the given controller is in
+// maintenance. The error has the code CodeTryAgain, clients should
+// retry once the maintenance is complete.
+func ControllerMaintenanceError(ctl *dbmodel.Controller) error {
+	return errors.E(errors.CodeTryAgain, fmt.Sprintf("controller %s is under maintenance, try again later", ctl.Name))
+}
+
+// SetControllerMaintenance puts the named controller into maintenance,
+// or takes it out of maintenance. When a controller enters maintenance
+// the sessions this JIMM server is proxying to it are drained, and the
+// number of drained sessions is returned. Other JIMM servers drain their
+// sessions when they next call DrainMaintenanceControllers. While a
+// controller is in maintenance no new sessions are proxied to it, the
+// controller is not monitored and new models are placed on other
+// controllers where possible. Only JIMM administrators may set
+// controller maintenance.
+func (j *JIMM) SetControllerMaintenance(ctx context.Context, user *openfga.User, controllerName string, maintenance bool) (int, error) {
+	const op = errors.Op("jimm.SetControllerMaintenance")
+	defer j.Cache.InvalidateControllers()
+
+	if !user.JimmAdmin {
+		return 0, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	ctl := dbmodel.Controller{
+		Name: controllerName,
+	}
+	err := j.Database.Transaction(func(tx *db.Database) error {
+		if err := tx.GetController(ctx, &ctl); err != nil {
+			return err
+		}
+		if ctl.MaintenanceSince.Valid == maintenance {
+			return nil
+		}
+		if maintenance {
+			ctl.MaintenanceSince = db.Now()
+		} else {
+			ctl.MaintenanceSince.Valid = false
+		}
+		return tx.UpdateController(ctx, &ctl)
+	})
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	if !maintenance {
+		return 0, nil
+	}
+	return j.drainController(ctx, &ctl), nil
+}
+
+// DrainMaintenanceControllers drains the sessions this JIMM server is
+// proxying to controllers in maintenance. It should be called
+// periodically on every JIMM server so that sessions are drained
+// whichever server handled the request to enter maintenance.
+func (j *JIMM) DrainMaintenanceControllers(ctx context.Context) error {
+	const op = errors.Op("jimm.DrainMaintenanceControllers")
+
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if ctl.MaintenanceSince.Valid {
+			j.drainController(ctx, ctl)
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// drainController drains the sessions proxied to the given controller
+// and returns the number drained.
+func (j *JIMM) drainController(ctx context.Context, ctl *dbmodel.Controller) int {
+	n := j.proxySessions.drain(ctl.UUID)
+	if n == 0 {
+		return 0
+	}
+	servermon.ProxySessionsDrainedCount.WithLabelValues(ctl.Name).Add(float64(n))
+	zapctx.Info(ctx, "drained proxied sessions", zap.String("controller", ctl.Name), zap.Int("sessions", n))
+	return n
+}
+
+// maintenanceLast orders the given controllers, which must already be
+// ordered by preference, so that controllers in maintenance are only
+// chosen if there is no alternative.
+func maintenanceLast(controllers []dbmodel.CloudRegionControllerPriority) {
+	sort.SliceStable(controllers, func(i, k int) bool {
+		return !controllers[i].Controller.MaintenanceSince.Valid && controllers[k].Controller.MaintenanceSince.Valid
+	})
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const maintenanceTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+`
+
+func TestSetControllerMaintenance(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, maintenanceTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	ctl1 := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl1)
+	c.Assert(err, qt.IsNil)
+	ctl2 := dbmodel.Controller{Name: "controller-2"}
+	err = j.Database.GetController(ctx, &ctl2)
+	c.Assert(err, qt.IsNil)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.SetControllerMaintenance(ctx, openfga.NewUser(bob, client), "controller-1", true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+
+	drain1, release1 := j.RegisterProxySession(&ctl1)
+	drain2, release2 := j.RegisterProxySession(&ctl1)
+	drain3, release3 := j.RegisterProxySession(&ctl2)
+	defer release3()
+
+	// A session that has finished is not drained.
+	release2()
+
+	n, err := j.SetControllerMaintenance(ctx, admin, "controller-1", true)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+	c.Check(isClosed(drain1), qt.IsTrue)
+	c.Check(isClosed(drain2), qt.IsFalse)
+	c.Check(isClosed(drain3), qt.IsFalse)
+	release1()
+
+	err = j.Database.GetController(ctx, &ctl1)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl1.MaintenanceSince.Valid, qt.IsTrue)
+	c.Check(ctl1.ToAPIControllerInfo().Status.Status, qt.Equals, "maintenance")
+
+	// Sessions started elsewhere are drained when the controllers in
+	// maintenance are next checked.
+	drain4, release4 := j.RegisterProxySession(&ctl1)
+	defer release4()
+	err = j.DrainMaintenanceControllers(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(isClosed(drain4), qt.IsTrue)
+	c.Check(isClosed(drain3), qt.IsFalse)
+
+	_, err = j.SetControllerMaintenance(ctx, admin, "controller-3", true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	n, err = j.SetControllerMaintenance(ctx, admin, "controller-1", false)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+	err = j.Database.GetController(ctx, &ctl1)
+	c.Assert(err, qt.IsNil)
+	c.Check(ctl1.MaintenanceSince.Valid, qt.IsFalse)
+
+	drain5, release5 := j.RegisterProxySession(&ctl1)
+	defer release5()
+	err = j.DrainMaintenanceControllers(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(isClosed(drain5), qt.IsFalse)
+}
+
+func isClosed(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
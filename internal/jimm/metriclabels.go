@@ -0,0 +1,195 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+const (
+	// DefaultMetricLabelMaxValues is the number of distinct values a
+	// metric label may take if no limit is configured.
+	DefaultMetricLabelMaxValues = 100
+
+	// MetricLabelOverflowValue is the value reported for a metric label
+	// once the label has taken its maximum number of distinct values.
+	MetricLabelOverflowValue = "other"
+)
+
+// controllerMetricLabels holds the supported controller metric labels
+// and the functions that determine their values.
+var controllerMetricLabels = map[string]func(*dbmodel.Controller) string{
+	"tier": func(ctl *dbmodel.Controller) string {
+		tiers := slices.Clone([]string(ctl.Tiers))
+		slices.Sort(tiers)
+		return strings.Join(tiers, ",")
+	},
+	"cloud": func(ctl *dbmodel.Controller) string {
+		return ctl.CloudName
+	},
+	"region": func(ctl *dbmodel.Controller) string {
+		return ctl.CloudRegion
+	},
+}
+
+// modelMetricLabels holds the supported model metric labels and the
+// functions that determine their values.
+var modelMetricLabels = map[string]func(*dbmodel.Model) string{
+	"cloud": func(m *dbmodel.Model) string {
+		return m.CloudRegion.Cloud.Name
+	},
+	"region": func(m *dbmodel.Model) string {
+		return m.CloudRegion.Name
+	},
+	"owner_domain": func(m *dbmodel.Model) string {
+		if _, domain, ok := strings.Cut(m.OwnerIdentityName, "@"); ok {
+			return domain
+		}
+		return ""
+	},
+}
+
+// A MetricLabeler determines the organisational labels, such as the tier
+// of a controller or the cloud of a model, attached to the metrics JIMM
+// exports about controllers and models. To protect the metrics store
+// from a cardinality explosion each label takes a limited number of
+// distinct values, once the limit is reached further values are
+// reported as MetricLabelOverflowValue.
+type MetricLabeler struct {
+	controllerLabels []string
+	modelLabels      []string
+	maxValues        int
+
+	mu     sync.Mutex
+	values map[string]map[string]bool
+}
+
+// NewMetricLabeler returns a MetricLabeler attaching the given controller
+// and model labels. The supported controller labels are "tier", "cloud"
+// and "region", the supported model labels are "cloud", "region" and
+// "owner_domain". The exported label names are prefixed with
+// "controller_" and "model_" respectively. If maxValues is zero
+// DefaultMetricLabelMaxValues is used. An error with the code
+// CodeBadRequest is returned if a label is not supported.
+func NewMetricLabeler(controllerLabels, modelLabels []string, maxValues int) (*MetricLabeler, error) {
+	const op = errors.Op("jimm.NewMetricLabeler")
+
+	for _, l := range controllerLabels {
+		if controllerMetricLabels[l] == nil {
+			return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("unsupported controller metric label %q", l))
+		}
+	}
+	for _, l := range modelLabels {
+		if modelMetricLabels[l] == nil {
+			return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("unsupported model metric label %q", l))
+		}
+	}
+	if maxValues <= 0 {
+		maxValues = DefaultMetricLabelMaxValues
+	}
+	return &MetricLabeler{
+		controllerLabels: sortedLabels(controllerLabels),
+		modelLabels:      sortedLabels(modelLabels),
+		maxValues:        maxValues,
+		values:           make(map[string]map[string]bool),
+	}, nil
+}
+
+// ControllerLabelNames returns the names of the controller labels
+// attached to metrics. If l is nil no names are returned.
+func (l *MetricLabeler) ControllerLabelNames() []string {
+	if l == nil {
+		return nil
+	}
+	return prefixLabels("controller_", l.controllerLabels)
+}
+
+// ModelLabelNames returns the names of the model labels attached to
+// metrics. If l is nil no names are returned.
+func (l *MetricLabeler) ModelLabelNames() []string {
+	if l == nil {
+		return nil
+	}
+	return prefixLabels("model_", l.modelLabels)
+}
+
+// ControllerLabelValues returns the values of the controller labels for
+// the given controller, in the order of ControllerLabelNames.
+func (l *MetricLabeler) ControllerLabelValues(ctl *dbmodel.Controller) []string {
+	if l == nil {
+		return nil
+	}
+	values := make([]string, len(l.controllerLabels))
+	for i, name := range l.controllerLabels {
+		values[i] = l.limit("controller_"+name, controllerMetricLabels[name](ctl))
+	}
+	return values
+}
+
+// ModelLabelValues returns the values of the model labels for the given
+// model, in the order of ModelLabelNames. The model's cloud region
+// should be loaded if the "cloud" or "region" labels are used.
+func (l *MetricLabeler) ModelLabelValues(m *dbmodel.Model) []string {
+	if l == nil {
+		return nil
+	}
+	values := make([]string, len(l.modelLabels))
+	for i, name := range l.modelLabels {
+		values[i] = l.limit("model_"+name, modelMetricLabels[name](m))
+	}
+	return values
+}
+
+// needsModelCloudRegion returns whether the model labels depend on the
+// model's cloud region.
+func (l *MetricLabeler) needsModelCloudRegion() bool {
+	if l == nil {
+		return false
+	}
+	return slices.Contains(l.modelLabels, "cloud") || slices.Contains(l.modelLabels, "region")
+}
+
+// limit returns the value to report for the given label, the given value
+// unless the label has already taken its maximum number of distinct
+// values.
+func (l *MetricLabeler) limit(label, value string) string {
+	if value == "" {
+		return value
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	seen := l.values[label]
+	if seen == nil {
+		seen = make(map[string]bool)
+		l.values[label] = seen
+	}
+	if seen[value] {
+		return value
+	}
+	if len(seen) >= l.maxValues {
+		return MetricLabelOverflowValue
+	}
+	seen[value] = true
+	return value
+}
+
+// sortedLabels returns the given labels sorted, with duplicates removed.
+func sortedLabels(labels []string) []string {
+	labels = slices.Clone(labels)
+	slices.Sort(labels)
+	return slices.Compact(labels)
+}
+
+func prefixLabels(prefix string, labels []string) []string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = prefix + l
+	}
+	return names
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+)
+
+func TestNewMetricLabelerUnsupportedLabel(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := jimm.NewMetricLabeler([]string{"tier", "team"}, nil, 0)
+	c.Check(err, qt.ErrorMatches, `unsupported controller metric label "team"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	_, err = jimm.NewMetricLabeler(nil, []string{"owner"}, 0)
+	c.Check(err, qt.ErrorMatches, `unsupported model metric label "owner"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestMetricLabeler(t *testing.T) {
+	c := qt.New(t)
+
+	l, err := jimm.NewMetricLabeler([]string{"tier", "cloud", "tier"}, []string{"owner_domain", "region"}, 2)
+	c.Assert(err, qt.IsNil)
+	c.Check(l.ControllerLabelNames(), qt.DeepEquals, []string{"controller_cloud", "controller_tier"})
+	c.Check(l.ModelLabelNames(), qt.DeepEquals, []string{"model_owner_domain", "model_region"})
+
+	c.Check(l.ControllerLabelValues(&dbmodel.Controller{
+		CloudName: "aws",
+		Tiers:     dbmodel.Strings{"production", "gpu"},
+	}), qt.DeepEquals, []string{"aws", "gpu,production"})
+
+	model := func(owner, region string) *dbmodel.Model {
+		return &dbmodel.Model{
+			OwnerIdentityName: owner,
+			CloudRegion: dbmodel.CloudRegion{
+				Name: region,
+			},
+		}
+	}
+	c.Check(l.ModelLabelValues(model("alice@canonical.com", "eu-west-1")), qt.DeepEquals, []string{"canonical.com", "eu-west-1"})
+	c.Check(l.ModelLabelValues(model("bob@example.com", "us-east-1")), qt.DeepEquals, []string{"example.com", "us-east-1"})
+	// Each label takes at most two distinct values, values already seen
+	// are still reported.
+	c.Check(l.ModelLabelValues(model("charlie@example.org", "eu-west-1")), qt.DeepEquals, []string{jimm.MetricLabelOverflowValue, "eu-west-1"})
+	c.Check(l.ModelLabelValues(model("diane@canonical.com", "ap-south-1")), qt.DeepEquals, []string{"canonical.com", jimm.MetricLabelOverflowValue})
+	c.Check(l.ModelLabelValues(model("service-account", "")), qt.DeepEquals, []string{"", ""})
+}
+
+func TestNilMetricLabeler(t *testing.T) {
+	c := qt.New(t)
+
+	var l *jimm.MetricLabeler
+	c.Check(l.ControllerLabelNames(), qt.IsNil)
+	c.Check(l.ModelLabelNames(), qt.IsNil)
+	c.Check(l.ControllerLabelValues(&dbmodel.Controller{CloudName: "aws"}), qt.IsNil)
+	c.Check(l.ModelLabelValues(&dbmodel.Model{}), qt.IsNil)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// mapMigrationCredential uploads the cloud credential used by the given
+// model to the target controller of a migration, so that the migrated
+// model does not reference a credential unknown to the target controller.
+// The returned function removes the uploaded credential from the target
+// controller again and should be called if the migration cannot proceed.
+// If the credential is already used by other models on the target
+// controller the returned function does nothing.
+func (j *JIMM) mapMigrationCredential(ctx context.Context, api API, m *dbmodel.Model, target *dbmodel.Controller) (func(), error) {
+	const op = errors.Op("jimm.mapMigrationCredential")
+
+	noop := func() {}
+	if m.CloudCredentialID == 0 {
+		return noop, nil
+	}
+	models, err := j.Database.GetModelsUsingCredential(ctx, m.CloudCredentialID)
+	if err != nil {
+		return noop, errors.E(op, err)
+	}
+	inUse := false
+	for _, um := range models {
+		if um.ControllerID == target.ID {
+			inUse = true
+			break
+		}
+	}
+
+	cred := m.CloudCredential
+	if _, err := j.propagateCloudCredential(ctx, &cred, target, api); err != nil {
+		return noop, errors.E(op, err)
+	}
+	if inUse {
+		return noop, nil
+	}
+	return func() {
+		if err := api.RevokeCredential(ctx, cred.ResourceTag()); err != nil {
+			zapctx.Error(ctx, "failed to remove migrated credential",
+				zap.String("controller", target.Name),
+				zap.String("credential", cred.ResourceTag().String()),
+				zaputil.Error(err),
+			)
+		}
+	}, nil
+}
+
+// rebindModelCredential makes the model with the given tag, which has
+// been migrated to the given controller reachable through the given API,
+// use the cloud credential JIMM has recorded for it.
+func (j *JIMM) rebindModelCredential(ctx context.Context, ctl *dbmodel.Controller, api API, modelTag names.ModelTag, m *dbmodel.Model) error {
+	const op = errors.Op("jimm.rebindModelCredential")
+
+	if m.CloudCredentialID == 0 {
+		return nil
+	}
+	cred := m.CloudCredential
+	if _, err := j.propagateCloudCredential(ctx, &cred, ctl, api); err != nil {
+		return errors.E(op, err)
+	}
+	if err := api.ChangeModelCredential(ctx, modelTag, cred.ResourceTag()); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/version/v2"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// MigrationPrechecks checks whether the model with the given tag can be
+// migrated to the named controller, without starting a migration. The
+// returned report holds the result of each of JIMM's checks, that the
+// target controller is available, hosts the model's cloud-region, has
+// capacity for the model and can be given the model's cloud credential,
+// followed by the result of the target controller's own migration
+// prechecks. Failed checks are recorded in the report, an error is only
+// returned if the checks cannot be run at all. The user must be an
+// administrator of the model.
+func (j *JIMM) MigrationPrechecks(ctx context.Context, user *openfga.User, mt names.ModelTag, targetController string) (apiparams.MigrationPrecheckReport, error) {
+	const op = errors.Op("jimm.MigrationPrechecks")
+
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return apiparams.MigrationPrecheckReport{}, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return apiparams.MigrationPrecheckReport{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.MigrationPrecheckReport{}, errors.E(op, err)
+	}
+	target := dbmodel.Controller{
+		Name: targetController,
+	}
+	if err := j.Database.GetController(ctx, &target); err != nil {
+		return apiparams.MigrationPrecheckReport{}, errors.E(op, err)
+	}
+
+	checks := []struct {
+		name  string
+		check func() (string, error)
+	}{{
+		name:  "target-controller",
+		check: func() (string, error) { return checkMigrationTarget(&m, &target) },
+	}, {
+		name:  "cloud-region",
+		check: func() (string, error) { return checkMigrationCloudRegion(&m, &target) },
+	}, {
+		name:  "capacity",
+		check: func() (string, error) { return j.checkMigrationCapacity(ctx, &target) },
+	}, {
+		name:  "credential",
+		check: func() (string, error) { return j.checkMigrationCredential(ctx, &m, &target) },
+	}, {
+		name:  "controller-prechecks",
+		check: func() (string, error) { return j.controllerMigrationPrechecks(ctx, &m, &target) },
+	}}
+
+	report := apiparams.MigrationPrecheckReport{
+		ModelTag:         mt.String(),
+		TargetController: target.Name,
+		Passed:           true,
+	}
+	for _, c := range checks {
+		msg, err := c.check()
+		if err != nil {
+			msg = err.Error()
+			report.Passed = false
+		}
+		report.Checks = append(report.Checks, apiparams.MigrationPrecheck{
+			Name:    c.name,
+			Passed:  err == nil,
+			Message: msg,
+		})
+	}
+	return report, nil
+}
+
+// checkMigrationTarget checks that the target controller is not the
+// model's current controller and is able to accept new models.
+func checkMigrationTarget(m *dbmodel.Model, target *dbmodel.Controller) (string, error) {
+	switch {
+	case target.ID == m.ControllerID:
+		return "", errors.E(fmt.Sprintf("model is already hosted on controller %s", target.Name))
+	case target.UnavailableSince.Valid:
+		return "", errors.E(fmt.Sprintf("controller %s has been unavailable since %s", target.Name, target.UnavailableSince.Time.UTC().Format(time.RFC3339)))
+	case target.Deprecated:
+		return "", errors.E(fmt.Sprintf("controller %s is deprecated", target.Name))
+	}
+	return fmt.Sprintf("controller %s is available", target.Name), nil
+}
+
+// checkMigrationCloudRegion checks that the target controller hosts the
+// model's cloud-region.
+func checkMigrationCloudRegion(m *dbmodel.Model, target *dbmodel.Controller) (string, error) {
+	region := m.CloudRegion.Cloud.Name + "/" + m.CloudRegion.Name
+	for _, crp := range target.CloudRegions {
+		if crp.CloudRegionID == m.CloudRegionID {
+			return fmt.Sprintf("controller %s hosts cloud-region %s", target.Name, region), nil
+		}
+	}
+	return "", errors.E(fmt.Sprintf("controller %s does not host cloud-region %s", target.Name, region))
+}
+
+// checkMigrationCapacity checks that the target controller hosts fewer
+// than the maximum number of models.
+func (j *JIMM) checkMigrationCapacity(ctx context.Context, target *dbmodel.Controller) (string, error) {
+	n, err := j.Database.CountModelsByController(ctx, *target)
+	if err != nil {
+		return "", err
+	}
+	if maxModels := j.maxControllerModels(); maxModels > 0 && n >= maxModels {
+		return "", errors.E(fmt.Sprintf("controller %s hosts %d models, the maximum is %d", target.Name, n, maxModels))
+	}
+	return fmt.Sprintf("controller %s hosts %d models", target.Name, n), nil
+}
+
+// checkMigrationCredential checks that the model's cloud credential is
+// either already known to the target controller or can be uploaded to it
+// when the migration starts.
+func (j *JIMM) checkMigrationCredential(ctx context.Context, m *dbmodel.Model, target *dbmodel.Controller) (string, error) {
+	if m.CloudCredentialID == 0 {
+		return "model has no cloud credential", nil
+	}
+	cred := m.CloudCredential
+	models, err := j.Database.GetModelsUsingCredential(ctx, m.CloudCredentialID)
+	if err != nil {
+		return "", err
+	}
+	for _, um := range models {
+		if um.ControllerID == target.ID {
+			return fmt.Sprintf("credential %s is already on controller %s", cred.Path(), target.Name), nil
+		}
+	}
+	if cred.Attributes == nil {
+		if _, err := j.getCloudCredentialAttributes(ctx, &cred); err != nil {
+			return "", errors.E(fmt.Sprintf("cannot read credential %s: %s", cred.Path(), err))
+		}
+	}
+	return fmt.Sprintf("credential %s will be uploaded to controller %s", cred.Path(), target.Name), nil
+}
+
+// controllerMigrationPrechecks runs the target controller's migration
+// prechecks for the model.
+func (j *JIMM) controllerMigrationPrechecks(ctx context.Context, m *dbmodel.Model, target *dbmodel.Controller) (string, error) {
+	source, err := j.dial(ctx, &m.Controller, names.ModelTag{})
+	if err != nil {
+		return "", errors.E(fmt.Sprintf("cannot connect to controller %s: %s", m.Controller.Name, err))
+	}
+	defer source.Close()
+	info := jujuparams.MigrationModelInfo{
+		UUID:           m.UUID.String,
+		Name:           m.Name,
+		OwnerTag:       names.NewUserTag(m.OwnerIdentityName).String(),
+		FacadeVersions: source.SupportedFacadeVersions(),
+	}
+	if v, err := version.Parse(m.Status.Version); err == nil {
+		info.AgentVersion = v
+	}
+	if v, err := version.Parse(m.Controller.AgentVersion); err == nil {
+		info.ControllerAgentVersion = v
+	}
+
+	api, err := j.dial(ctx, target, names.ModelTag{})
+	if err != nil {
+		return "", errors.E(fmt.Sprintf("cannot connect to controller %s: %s", target.Name, err))
+	}
+	defer api.Close()
+	if err := api.MigrationTargetPrechecks(ctx, info); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("controller %s accepts the model", target.Name), nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const migrationPrecheckTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-cloud-region
+    priority: 1
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-cloud-region
+    priority: 1
+- name: controller-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-2
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+users:
+- username: bob@canonical.com
+  controller-access: login
+`
+
+func TestMigrationPrechecks(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	facadeVersions := map[string][]int{"MigrationFlag": {1}}
+	var precheckErr error
+	var prechecked []jujuparams.MigrationModelInfo
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				SupportedFacadeVersions_: facadeVersions,
+				MigrationTargetPrechecks_: func(_ context.Context, info jujuparams.MigrationModelInfo) error {
+					prechecked = append(prechecked, info)
+					return precheckErr
+				},
+			},
+		},
+		MaxControllerModels: 2,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, migrationPrecheckTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	_, err = j.MigrationPrechecks(ctx, bob, mt, "controller-2")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.MigrationPrechecks(ctx, alice, mt, "controller-4")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	report, err := j.MigrationPrechecks(ctx, alice, mt, "controller-2")
+	c.Assert(err, qt.IsNil)
+	c.Check(report, qt.DeepEquals, apiparams.MigrationPrecheckReport{
+		ModelTag:         mt.String(),
+		TargetController: "controller-2",
+		Passed:           true,
+		Checks: []apiparams.MigrationPrecheck{{
+			Name:    "target-controller",
+			Passed:  true,
+			Message: "controller controller-2 is available",
+		}, {
+			Name:    "cloud-region",
+			Passed:  true,
+			Message: "controller controller-2 hosts cloud-region test-cloud/test-cloud-region",
+		}, {
+			Name:    "capacity",
+			Passed:  true,
+			Message: "controller controller-2 hosts 1 models",
+		}, {
+			Name:    "credential",
+			Passed:  true,
+			Message: "credential test-cloud/alice@canonical.com/cred-1 is already on controller controller-2",
+		}, {
+			Name:    "controller-prechecks",
+			Passed:  true,
+			Message: "controller controller-2 accepts the model",
+		}},
+	})
+	c.Assert(prechecked, qt.HasLen, 1)
+	c.Check(prechecked[0].UUID, qt.Equals, mt.Id())
+	c.Check(prechecked[0].Name, qt.Equals, "model-1")
+	c.Check(prechecked[0].OwnerTag, qt.Equals, "user-alice@canonical.com")
+	c.Check(prechecked[0].FacadeVersions, qt.DeepEquals, facadeVersions)
+
+	// The model's own controller is not a migration target.
+	report, err = j.MigrationPrechecks(ctx, alice, mt, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Passed, qt.IsFalse)
+	c.Check(report.Checks[0], qt.DeepEquals, apiparams.MigrationPrecheck{
+		Name:    "target-controller",
+		Message: "model is already hosted on controller controller-1",
+	})
+
+	// Failures are reported for each check, and do not stop the
+	// remaining checks being made.
+	precheckErr = errors.E("model with same UUID already exists")
+	j.MaxControllerModels = 1
+	report, err = j.MigrationPrechecks(ctx, alice, mt, "controller-3")
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Passed, qt.IsFalse)
+	c.Check(report.Checks, qt.DeepEquals, []apiparams.MigrationPrecheck{{
+		Name:    "target-controller",
+		Passed:  true,
+		Message: "controller controller-3 is available",
+	}, {
+		Name:    "cloud-region",
+		Message: "controller controller-3 does not host cloud-region test-cloud/test-cloud-region",
+	}, {
+		Name:    "capacity",
+		Passed:  true,
+		Message: "controller controller-3 hosts 0 models",
+	}, {
+		Name:    "credential",
+		Passed:  true,
+		Message: "credential test-cloud/alice@canonical.com/cred-1 will be uploaded to controller controller-3",
+	}, {
+		Name:    "controller-prechecks",
+		Message: "model with same UUID already exists",
+	}})
+
+	report, err = j.MigrationPrechecks(ctx, alice, mt, "controller-2")
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Checks[2], qt.DeepEquals, apiparams.MigrationPrecheck{
+		Name:    "capacity",
+		Message: "controller controller-2 hosts 1 models, the maximum is 1",
+	})
+}
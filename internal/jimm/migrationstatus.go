@@ -0,0 +1,60 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelMigrationStatus returns the status of the most recent migration of
+// the model with the given tag, as reported by the controller currently
+// hosting the model. If the model has never been migrated the returned
+// status is empty. The user must be an administrator of the model.
+func (j *JIMM) ModelMigrationStatus(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelMigrationStatus, error) {
+	const op = errors.Op("jimm.ModelMigrationStatus")
+
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, err)
+	}
+	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
+	if err != nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, err)
+	}
+	defer api.Close()
+	mi := jujuparams.ModelInfo{
+		UUID: m.UUID.String,
+	}
+	if err := api.ModelInfo(ctx, &mi); err != nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, err)
+	}
+
+	status := apiparams.ModelMigrationStatus{
+		ModelTag:   mt.String(),
+		Controller: m.Controller.Name,
+	}
+	if mi.Migration != nil {
+		status.Status = mi.Migration.Status
+		status.Start = mi.Migration.Start
+		status.End = mi.Migration.End
+		status.Active = mi.Migration.End == nil
+	}
+	return status, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestModelMigrationStatus(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var migration *jujuparams.ModelMigrationStatus
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+					c.Check(mi.UUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+					mi.Migration = migration
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, migrationPrecheckTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+
+	_, err = j.ModelMigrationStatus(ctx, bob, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	status, err := j.ModelMigrationStatus(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(status, qt.DeepEquals, apiparams.ModelMigrationStatus{
+		ModelTag:   mt.String(),
+		Controller: "controller-1",
+	})
+
+	migration = &jujuparams.ModelMigrationStatus{
+		Status: "migrating: importing model into target controller",
+		Start:  &start,
+	}
+	status, err = j.ModelMigrationStatus(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(status, qt.DeepEquals, apiparams.ModelMigrationStatus{
+		ModelTag:   mt.String(),
+		Controller: "controller-1",
+		Active:     true,
+		Status:     "migrating: importing model into target controller",
+		Start:      &start,
+	})
+}
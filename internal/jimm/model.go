@@ -7,7 +7,9 @@ import (
 	"database/sql"
 	"fmt"
 	"math/rand"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // shuffle is used to randomize the order in which possible controllers
@@ -38,6 +41,27 @@ func shuffleRegionControllers(controllers []dbmodel.CloudRegionControllerPriorit
 	})
 }
 
+// ControllerTierConfigKey is the model config attribute used to request
+// that a model is placed on a controller in the given tier. The
+// attribute may also be set in a user's model defaults. It is not passed
+// on to the controller.
+const ControllerTierConfigKey = "jimm-controller-tier"
+
+// inControllerTier returns the controllers that belong to the given tier.
+// If the tier is empty all controllers are returned.
+func inControllerTier(controllers []dbmodel.CloudRegionControllerPriority, tier string) []dbmodel.CloudRegionControllerPriority {
+	if tier == "" {
+		return controllers
+	}
+	var tierControllers []dbmodel.CloudRegionControllerPriority
+	for _, c := range controllers {
+		if slices.Contains(c.Controller.Tiers, tier) {
+			tierControllers = append(tierControllers, c)
+		}
+	}
+	return tierControllers
+}
+
 // ModelCreateArgs contains parameters used to add a new model.
 type ModelCreateArgs struct {
 	Name            string
@@ -103,13 +127,23 @@ type modelBuilder struct {
 	name          string
 	config        map[string]interface{}
 	owner         *dbmodel.Identity
+	creator       string
 	credential    *dbmodel.CloudCredential
 	controller    *dbmodel.Controller
 	cloud         *dbmodel.Cloud
 	cloudRegion   string
 	cloudRegionID uint
+	tier          string
 	model         *dbmodel.Model
 	modelInfo     *jujuparams.ModelInfo
+
+	// latencySensitive is set when the measured latencies should be
+	// used to break ties between regions and controllers.
+	latencySensitive bool
+
+	// defaultRegion is the region to use when none is specified, set
+	// when the cloud is the default cloud of the owner's domain.
+	defaultRegion string
 }
 
 // Error returns the error that occurred in the process
@@ -135,10 +169,22 @@ func (b *modelBuilder) jujuModelCreateArgs() (*jujuparams.ModelCreateArgs, error
 		return nil, errors.E("credentials not specified")
 	}
 
+	// the controller tier, latency sensitivity and model pool are only
+	// used by JIMM
+	var config map[string]interface{}
+	if b.config != nil {
+		config = make(map[string]interface{}, len(b.config))
+		for key, value := range b.config {
+			if key != ControllerTierConfigKey && key != LatencySensitiveConfigKey && key != ModelPoolConfigKey {
+				config[key] = value
+			}
+		}
+	}
+
 	return &jujuparams.ModelCreateArgs{
 		Name:               b.name,
 		OwnerTag:           b.owner.Tag().String(),
-		Config:             b.config,
+		Config:             config,
 		CloudTag:           b.cloud.Tag().String(),
 		CloudRegion:        b.cloudRegion,
 		CloudCredentialTag: b.credential.Tag().String(),
@@ -154,6 +200,16 @@ func (b *modelBuilder) WithOwner(owner *dbmodel.Identity) *modelBuilder {
 	return b
 }
 
+// WithCreator returns a builder recording the named identity as the
+// creator of the model.
+func (b *modelBuilder) WithCreator(name string) *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.creator = name
+	return b
+}
+
 // WithName returns a builder with the specified model name.
 func (b *modelBuilder) WithName(name string) *modelBuilder {
 	if b.err != nil {
@@ -174,6 +230,62 @@ func (b *modelBuilder) WithConfig(cfg map[string]interface{}) *modelBuilder {
 	return b
 }
 
+// WithControllerTier returns a builder that places the model on a
+// controller in the tier requested by the given config. If the given
+// config does not request a tier, the tier requested by the config
+// already held by the builder, if any, is used.
+func (b *modelBuilder) WithControllerTier(cfg map[string]interface{}) *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	v, ok := cfg[ControllerTierConfigKey]
+	if !ok {
+		v, ok = b.config[ControllerTierConfigKey]
+	}
+	if !ok {
+		return b
+	}
+	tier, ok := v.(string)
+	if !ok {
+		b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s value", ControllerTierConfigKey))
+		return b
+	}
+	b.tier = tier
+	return b
+}
+
+// WithLatencySensitivity returns a builder that uses the measured
+// latencies to break ties when placing the model, if the given config
+// requests it. If the given config does not mention latency sensitivity,
+// the request in the config already held by the builder, if any, is
+// used.
+func (b *modelBuilder) WithLatencySensitivity(cfg map[string]interface{}) *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	v, ok := cfg[LatencySensitiveConfigKey]
+	if !ok {
+		v, ok = b.config[LatencySensitiveConfigKey]
+	}
+	if !ok {
+		return b
+	}
+	switch v := v.(type) {
+	case bool:
+		b.latencySensitive = v
+	case string:
+		sensitive, err := strconv.ParseBool(v)
+		if err != nil {
+			b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s value", LatencySensitiveConfigKey))
+			return b
+		}
+		b.latencySensitive = sensitive
+	default:
+		b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s value", LatencySensitiveConfigKey))
+	}
+	return b
+}
+
 // WithCloud returns a builder with the specified cloud.
 func (b *modelBuilder) WithCloud(user *openfga.User, cloud names.CloudTag) *modelBuilder {
 	if b.err != nil {
@@ -190,7 +302,7 @@ func (b *modelBuilder) WithCloud(user *openfga.User, cloud names.CloudTag) *mode
 		Name: cloud.Id(),
 	}
 
-	if err := b.jimm.Database.GetCloud(b.ctx, &c); err != nil {
+	if err := b.jimm.getCloud(b.ctx, &c); err != nil {
 		b.err = err
 		return b
 	}
@@ -200,7 +312,8 @@ func (b *modelBuilder) WithCloud(user *openfga.User, cloud names.CloudTag) *mode
 }
 
 // withImplicitCloud returns a builder with the only cloud known to JIMM. Should JIMM
-// know of multiple clouds an error will be raised.
+// know of multiple clouds the default cloud of the user's domain is used,
+// if there is one, otherwise an error will be raised.
 func (b *modelBuilder) withImplicitCloud(user *openfga.User) *modelBuilder {
 	if b.err != nil {
 		return b
@@ -218,11 +331,26 @@ func (b *modelBuilder) withImplicitCloud(user *openfga.User) *modelBuilder {
 		b.err = fmt.Errorf("no available clouds")
 		return b
 	}
-	if len(clouds) != 1 {
-		b.err = fmt.Errorf("no cloud specified for model; please specify one")
+	if len(clouds) == 1 {
+		b.cloud = clouds[0]
+		return b
+	}
+	def, err := b.jimm.DefaultCloud(b.ctx, user)
+	if err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			err = fmt.Errorf("no cloud specified for model; please specify one")
+		}
+		b.err = err
 		return b
 	}
-	b.cloud = clouds[0]
+	for _, c := range clouds {
+		if c.Name == def.CloudName {
+			b.cloud = c
+			b.defaultRegion = def.RegionName
+			return b
+		}
+	}
+	b.err = fmt.Errorf("no cloud specified for model; please specify one")
 
 	return b
 }
@@ -236,15 +364,36 @@ func (b *modelBuilder) WithCloudRegion(region string) *modelBuilder {
 		b.err = errors.E("cloud not specified")
 		return b
 	}
-	// if the region is not specified, we pick the first cloud region
-	// with any associated controllers
+	// the old name of a remapped region refers to its new region
+	region, err := b.jimm.cloudRegionName(b.ctx, b.cloud, region)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	// if the region is not specified, we use the default region of the
+	// owner's domain, if there is one and it can host the model
+	if region == "" && b.defaultRegion != "" {
+		if len(inControllerTier(b.cloud.Region(b.defaultRegion).Controllers, b.tier)) > 0 {
+			region = b.defaultRegion
+		}
+	}
+	// otherwise we pick the first cloud region with any associated
+	// controllers, or for latency sensitive models the measured region
+	// with the lowest latency
 	if region == "" {
+		var candidates []string
 		for _, r := range b.cloud.Regions {
-			regionControllers := r.Controllers
+			regionControllers := inControllerTier(r.Controllers, b.tier)
 			if len(regionControllers) == 0 {
 				continue
 			}
 			region = r.Name
+			candidates = append(candidates, r.Name)
+		}
+		if b.latencySensitive {
+			if r := b.jimm.Latency.lowestLatencyRegion(b.cloud.Name, candidates); r != "" {
+				region = r
+			}
 		}
 	}
 	// loop through all cloud regions
@@ -259,8 +408,22 @@ func (b *modelBuilder) WithCloudRegion(region string) *modelBuilder {
 			b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("unsupported cloud region %s/%s", b.cloud.Name, region))
 			return b
 		}
+		// restricted to the requested tier
+		regionControllers = inControllerTier(regionControllers, b.tier)
+		if len(regionControllers) == 0 {
+			b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("no controllers in tier %q for cloud region %s/%s", b.tier, b.cloud.Name, region))
+			return b
+		}
 		// shuffle controllers
 		shuffleRegionControllers(regionControllers)
+		// preferring healthy controllers
+		b.jimm.Health.sortRegionControllers(regionControllers)
+		// and, for latency sensitive models, the closest controllers
+		if b.latencySensitive {
+			b.jimm.Latency.sortRegionControllers(b.jimm.Health, regionControllers)
+		}
+		// avoiding controllers in maintenance
+		maintenanceLast(regionControllers)
 
 		// and select the first controller in the slice
 		b.cloudRegion = region
@@ -270,6 +433,10 @@ func (b *modelBuilder) WithCloudRegion(region string) *modelBuilder {
 		break
 	}
 	// we looped through all cloud regions and could not find a match
+	if region == "" && b.tier != "" {
+		b.err = errors.E(errors.CodeBadRequest, fmt.Sprintf("no controllers in tier %q for cloud %s", b.tier, b.cloud.Name))
+		return b
+	}
 	if b.cloudRegionID == 0 {
 		b.err = errors.E("cloudregion not found", errors.CodeNotFound)
 	}
@@ -345,6 +512,11 @@ func (b *modelBuilder) CreateDatabaseModel() *modelBuilder {
 		Owner:             *b.owner,
 		CloudCredentialID: b.credential.ID,
 		CloudRegionID:     b.cloudRegionID,
+		Origin: dbmodel.ModelOrigin{
+			Kind:         dbmodel.ModelOriginCreated,
+			IdentityName: b.creator,
+			Time:         sql.NullTime{Time: time.Now(), Valid: true},
+		},
 	}
 
 	err := b.jimm.Database.AddModel(b.ctx, b.model)
@@ -421,9 +593,15 @@ func (b *modelBuilder) selectCloudRegion() error {
 	if len(regionControllers) == 0 {
 		return errors.E(fmt.Sprintf("unsupported cloud %s", b.cloud.Name))
 	}
+	regionControllers = inControllerTier(regionControllers, b.tier)
+	if len(regionControllers) == 0 {
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("no controllers in tier %q for cloud %s", b.tier, b.cloud.Name))
+	}
 
 	// shuffle controllers according to their priority
 	shuffleRegionControllers(regionControllers)
+	// avoiding controllers in maintenance
+	maintenanceLast(regionControllers)
 
 	b.cloudRegionID = regionControllers[0].CloudRegionID
 	b.controller = &regionControllers[0].Controller
@@ -442,15 +620,96 @@ func (b *modelBuilder) selectCloudCredentials() error {
 	if err != nil {
 		return errors.E(err, "failed to fetch user cloud credentials")
 	}
+	var candidates []dbmodel.CloudCredential
 	for _, credential := range credentials {
 		// skip any credentials known to be invalid.
 		if credential.Valid.Valid && !credential.Valid.Bool {
 			continue
 		}
-		b.credential = &credential
+		candidates = append(candidates, credential)
+	}
+	switch len(candidates) {
+	case 0:
+		return errors.E("valid cloud credentials not found")
+	case 1:
+		b.credential = &candidates[0]
+		return nil
+	}
+
+	// The owner has several credentials for the cloud, prefer their
+	// default credential, then the one they last used in the region.
+	def := dbmodel.DefaultCloudCredential{
+		IdentityName: b.owner.Name,
+		CloudName:    b.cloud.Name,
+	}
+	err = b.jimm.Database.GetDefaultCloudCredential(b.ctx, &def)
+	if err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+		return errors.E(err, "failed to fetch default cloud credential")
+	}
+	if b.useCandidateCredential(candidates, def.CloudCredentialID) {
 		return nil
 	}
-	return errors.E("valid cloud credentials not found")
+	recent := dbmodel.RecentCloudCredential{
+		IdentityName: b.owner.Name,
+		CloudName:    b.cloud.Name,
+		RegionName:   b.regionName(),
+	}
+	err = b.jimm.Database.GetRecentCloudCredential(b.ctx, &recent)
+	if err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+		return errors.E(err, "failed to fetch recent cloud credential")
+	}
+	if b.useCandidateCredential(candidates, recent.CloudCredentialID) {
+		return nil
+	}
+	return errors.E(errors.CodeBadRequest, fmt.Sprintf("more than one possible credential to use for cloud %s, specify a credential or set a default", b.cloud.Name))
+}
+
+// useCandidateCredential selects the credential with the given ID, if
+// it is one of the candidates, reporting whether it was selected.
+func (b *modelBuilder) useCandidateCredential(candidates []dbmodel.CloudCredential, id uint) bool {
+	if id == 0 {
+		return false
+	}
+	for i := range candidates {
+		if candidates[i].ID == id {
+			b.credential = &candidates[i]
+			return true
+		}
+	}
+	return false
+}
+
+// regionName returns the name of the selected cloud region.
+func (b *modelBuilder) regionName() string {
+	if b.cloudRegion != "" {
+		return b.cloudRegion
+	}
+	for _, r := range b.cloud.Regions {
+		if r.ID == b.cloudRegionID {
+			return r.Name
+		}
+	}
+	return ""
+}
+
+// RecordCloudCredential remembers the credential used to create the
+// model, so that it is preferred the next time the owner creates a model
+// in the same cloud region without specifying a credential. Failing to
+// record the credential does not fail the model creation.
+func (b *modelBuilder) RecordCloudCredential() *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	rc := dbmodel.RecentCloudCredential{
+		IdentityName:      b.owner.Name,
+		CloudName:         b.cloud.Name,
+		RegionName:        b.regionName(),
+		CloudCredentialID: b.credential.ID,
+	}
+	if err := b.jimm.Database.SetRecentCloudCredential(b.ctx, &rc); err != nil {
+		zapctx.Warn(b.ctx, "failed to record cloud credential", zaputil.Error(err))
+	}
+	return b
 }
 
 // CreateControllerModel uses provided information to create a new
@@ -518,6 +777,10 @@ func (b *modelBuilder) CreateControllerModel() *modelBuilder {
 		return b
 	}
 
+	// Record the model created on the controller, so that it can be
+	// destroyed should the model creation be cancelled.
+	b.modelInfo = &info
+
 	// Grant JIMM admin access to the model. Note that if this fails,
 	// the local database entry will be deleted but the model
 	// will remain on the controller and will trigger the "already exists
@@ -528,8 +791,29 @@ func (b *modelBuilder) CreateControllerModel() *modelBuilder {
 		b.err = errors.E(err)
 		return b
 	}
+	return b
+}
 
-	b.modelInfo = &info
+// AbortControllerModel destroys the model created on the controller
+// when the model creation has been cancelled for the given reason.
+func (b *modelBuilder) AbortControllerModel(reason error) *modelBuilder {
+	b.err = reason
+	if b.modelInfo == nil {
+		return b
+	}
+	// The model should be destroyed regardless of the cancellation of
+	// the request context.
+	ctx := context.Background()
+	mt := names.NewModelTag(b.modelInfo.UUID)
+	api, err := b.jimm.dialController(ctx, b.controller)
+	if err != nil {
+		zapctx.Error(ctx, "leaked model", zap.String("model", mt.Id()), zaputil.Error(err))
+		return b
+	}
+	defer api.Close()
+	if err := api.DestroyModel(ctx, mt, nil, nil, nil, nil); err != nil {
+		zapctx.Error(ctx, "leaked model", zap.String("model", mt.Id()), zaputil.Error(err))
+	}
 	return b
 }
 
@@ -541,7 +825,7 @@ func (b *modelBuilder) updateCredential(ctx context.Context, api API, cred *dbmo
 		return err
 	}
 
-	_, err = b.jimm.updateControllerCloudCredential(ctx, &cred1, api.UpdateCredential)
+	_, err = b.jimm.propagateCloudCredential(ctx, &cred1, b.controller, api)
 	return err
 }
 
@@ -569,13 +853,76 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
+	if err := j.checkModelQuota(ctx, owner.Name); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	if err := j.checkNamespaceReservation(ctx, openfga.NewUser(owner, j.OpenFGAClient), args.Name); err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	// Register the model creation so that it may be cancelled until
+	// the model has been created on the controller.
+	path := owner.Name + "/" + args.Name
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if !j.modelCreations.add(path, user.Name, cancel) {
+		return nil, errors.E(op, errors.CodeAlreadyExists, fmt.Sprintf("model %s is already being created", path))
+	}
+	defer j.modelCreations.remove(path)
+	defer func() {
+		if cause := context.Cause(ctx); err != nil && errors.ErrorCode(cause) == errors.CodeModelCreationCancelled {
+			err = errors.E(op, cause)
+		}
+	}()
+
 	builder := newModelBuilder(ctx, j)
 	builder = builder.WithOwner(owner)
+	builder = builder.WithCreator(user.Name)
 	builder = builder.WithName(args.Name)
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
 
+	// a model requested from a model pool is claimed from the pool,
+	// should the pool have no ready models the model is created in the
+	// pool's cloud region with the pool's template config
+	poolName, err := modelPoolName(args.Config)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	var poolConfig map[string]interface{}
+	if poolName != "" {
+		pool := dbmodel.ModelPool{
+			Name: poolName,
+		}
+		if err := j.Database.GetModelPool(ctx, &pool); err != nil {
+			return nil, errors.E(op, err)
+		}
+		cloudName, regionName := pool.CloudRegion.Cloud.Name, pool.CloudRegion.Name
+		requestedRegion, err := j.cloudRegionName(ctx, &pool.CloudRegion.Cloud, args.CloudRegion)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		if (args.Cloud.Id() != "" && args.Cloud.Id() != cloudName) ||
+			(requestedRegion != "" && requestedRegion != regionName) ||
+			(args.CloudCredential != (names.CloudCredentialTag{}) && args.CloudCredential.Cloud().Id() != cloudName) {
+			return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("model pool %q is in cloud region %s/%s", poolName, cloudName, regionName))
+		}
+		mi, claimed, err := j.addPooledModel(ctx, user, owner, args, &pool)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		if claimed {
+			return mi, nil
+		}
+		poolArgs := *args
+		poolArgs.Cloud = pool.CloudRegion.Cloud.ResourceTag()
+		poolArgs.CloudRegion = regionName
+		args = &poolArgs
+		poolConfig = pool.Config
+	}
+
 	// fetch user model defaults
 	userConfig, err := j.IdentityModelDefaults(ctx, user.Identity)
 	if err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
@@ -603,6 +950,10 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 		return nil, errors.E(op, err)
 	}
 
+	// the controller tier and latency sensitivity may be requested in
+	// the provided config or in the user's model defaults
+	builder = builder.WithControllerTier(args.Config)
+	builder = builder.WithLatencySensitivity(args.Config)
 	builder = builder.WithCloudRegion(args.CloudRegion)
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
@@ -634,11 +985,20 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 		builder = builder.WithConfig(cloudRegionDefaults.Defaults)
 	}
 
+	// then the template config of the model pool the model was
+	// requested from, if any
+	builder = builder.WithConfig(poolConfig)
+
 	// last but not least, use the provided config values
 	// overriding all defaults
 	builder = builder.WithConfig(args.Config)
 
 	if args.CloudCredential != (names.CloudCredentialTag{}) {
+		// The owner may use their own credentials, or any that have
+		// been shared with them.
+		if err := j.checkCloudCredentialUse(ctx, user, owner, args.CloudCredential); err != nil {
+			return nil, errors.E(op, err)
+		}
 		builder = builder.WithCloudCredential(args.CloudCredential)
 		if err := builder.Error(); err != nil {
 			return nil, errors.E(op, err)
@@ -651,6 +1011,11 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 	defer builder.Cleanup()
 
 	builder = builder.CreateControllerModel()
+	j.modelCreations.remove(path)
+	if cause := context.Cause(ctx); errors.ErrorCode(cause) == errors.CodeModelCreationCancelled {
+		builder = builder.AbortControllerModel(cause)
+		return nil, errors.E(op, builder.Error())
+	}
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
@@ -659,6 +1024,7 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 	if err := builder.Error(); err != nil {
 		return nil, errors.E(op, err)
 	}
+	builder = builder.RecordCloudCredential()
 
 	mi := builder.JujuModelInfo()
 
@@ -669,6 +1035,7 @@ func (j *JIMM) AddModel(ctx context.Context, user *openfga.User, args *ModelCrea
 	if err := j.addModelPermissions(ctx, ownerUser, modelTag, controllerTag); err != nil {
 		return nil, errors.E(op, err)
 	}
+	j.applyModelACLTemplates(ctx, ownerUser, modelTag)
 	return mi, nil
 }
 
@@ -713,13 +1080,20 @@ func (j *JIMM) addModelPermissions(ctx context.Context, owner *openfga.User, mt
 
 // ModelInfo returns the model info for the model with the given ModelTag.
 // The returned ModelInfo will be appropriate for the given user's
-// access-level on the model. If the model does not exist then the returned
-// error will have the code CodeNotFound. If the given user does not have
-// access to the model then the returned error will have the code
+// access-level on the model. The model info is read from the controller
+// hosting the model unless a cached read is requested in the context, see
+// ContextWithReadConsistency. If the model does not exist then the
+// returned error will have the code CodeNotFound. If the given user does
+// not have access to the model then the returned error will have the code
 // CodeUnauthorized.
 func (j *JIMM) ModelInfo(ctx context.Context, user *openfga.User, mt names.ModelTag) (*jujuparams.ModelInfo, error) {
 	const op = errors.Op("jimm.ModelInfo")
 
+	consistency, err := readConsistency(ctx, apiparams.ReadConsistencyFresh)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
 	var m dbmodel.Model
 	m.SetTag(mt)
 	if err := j.Database.GetModel(ctx, &m); err != nil {
@@ -730,6 +1104,14 @@ func (j *JIMM) ModelInfo(ctx context.Context, user *openfga.User, mt names.Model
 		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
+	if consistency == apiparams.ReadConsistencyCached {
+		mi, err := j.cachedModelInfo(ctx, &m)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		return j.mergeModelInfo(ctx, user, mi, m)
+	}
+
 	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
 	if err != nil {
 		return nil, errors.E(op, err)
@@ -752,6 +1134,7 @@ func (j *JIMM) mergeModelInfo(ctx context.Context, user *openfga.User, modelInfo
 	const op = errors.Op("jimm.mergeModelInfo")
 
 	jimmSummary := jimmModel.ToJujuModelSummary()
+	modelInfo.Name = jimmModel.Name
 	modelInfo.CloudCredentialTag = jimmSummary.CloudCredentialTag
 	modelInfo.ControllerUUID = jimmSummary.ControllerUUID
 	modelInfo.OwnerTag = jimmSummary.OwnerTag
@@ -813,12 +1196,38 @@ func (j *JIMM) mergeModelInfo(ctx context.Context, user *openfga.User, modelInfo
 // ModelStatus returns a jujuparams.ModelStatus for the given model. If
 // the model doesn't exist then the returned error will have the code
 // CodeNotFound, If the given user does not have admin access to the model
-// then the returned error will have the code CodeUnauthorized.
+// then the returned error will have the code CodeUnauthorized. The status
+// is read from the controller hosting the model unless a cached read is
+// requested in the context, see ContextWithReadConsistency.
 func (j *JIMM) ModelStatus(ctx context.Context, user *openfga.User, mt names.ModelTag) (*jujuparams.ModelStatus, error) {
 	const op = errors.Op("jimm.ModelStatus")
 
+	consistency, err := readConsistency(ctx, apiparams.ReadConsistencyFresh)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if consistency == apiparams.ReadConsistencyCached {
+		var m dbmodel.Model
+		m.SetTag(mt)
+		if err := j.Database.GetModel(ctx, &m); err != nil {
+			return nil, errors.E(op, err)
+		}
+		accessLevel, err := j.GetUserModelAccess(ctx, user, mt)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		if !allowedModelAccess["admin"][accessLevel] {
+			return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
+		ms, err := j.cachedModelStatus(ctx, &m)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		return ms, nil
+	}
+
 	var ms jujuparams.ModelStatus
-	err := j.doModelAdmin(ctx, user, mt, func(_ *dbmodel.Model, api API) error {
+	err = j.doModelAdmin(ctx, user, mt, func(_ *dbmodel.Model, api API) error {
 		ms.ModelTag = mt.String()
 		return api.ModelStatus(ctx, &ms)
 	})
@@ -828,6 +1237,35 @@ func (j *JIMM) ModelStatus(ctx context.Context, user *openfga.User, mt names.Mod
 	return &ms, nil
 }
 
+// ModelMachines returns the placement and network details of the machines
+// in the given model, as recorded by the controller watchers. The model's
+// controller is not contacted. If the model doesn't exist then the
+// returned error will have the code CodeNotFound. Machine details are
+// only available to users with write access to the model, if the given
+// user does not have write access the returned error will have the code
+// CodeUnauthorized.
+func (j *JIMM) ModelMachines(ctx context.Context, user *openfga.User, mt names.ModelTag) ([]dbmodel.Machine, error) {
+	const op = errors.Op("jimm.ModelMachines")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return nil, errors.E(op, err)
+	}
+	accessLevel, err := j.GetUserModelAccess(ctx, user, mt)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if !allowedModelAccess["write"][accessLevel] {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	machines, err := j.Database.GetModelMachines(ctx, m.ID)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return machines, nil
+}
+
 // ForEachUserModel calls the given function once for each model that the
 // given user has been granted explicit access to. The UserModelAccess
 // object passed to f will always include the Model_, Access, and
@@ -908,6 +1346,7 @@ func (j *JIMM) ForEachModel(ctx context.Context, user *openfga.User, f func(*dbm
 // is returned.
 func (j *JIMM) GrantModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
 	const op = errors.Op("jimm.GrantModelAccess")
+	defer j.Cache.InvalidateModelAccess(mt)
 
 	targetRelation, err := ToModelRelation(string(access))
 	if err != nil {
@@ -980,6 +1419,7 @@ func (j *JIMM) GrantModelAccess(ctx context.Context, user *openfga.User, mt name
 // then an error with the code CodeUnauthorized is returned.
 func (j *JIMM) RevokeModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
 	const op = errors.Op("jimm.RevokeModelAccess")
+	defer j.Cache.InvalidateModelAccess(mt)
 
 	targetRelation, err := ToModelRelation(string(access))
 	if err != nil {
@@ -1062,14 +1502,144 @@ func (j *JIMM) RevokeModelAccess(ctx context.Context, user *openfga.User, mt nam
 	return nil
 }
 
+// GrantModelGroupAccess grants the given access level on the given model
+// to the members of the named group. The grant is held by the group and
+// so also applies to members added to the group later. If the model is
+// not found then an error with the code CodeNotFound is returned. If the
+// group is not found then an error with the code CodeNotFound is
+// returned. If the authenticated user does not have admin access to the
+// model then an error with the code CodeUnauthorized is returned.
+func (j *JIMM) GrantModelGroupAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error {
+	const op = errors.Op("jimm.GrantModelGroupAccess")
+	defer j.Cache.InvalidateModelAccess(mt)
+
+	targetRelation, err := ToModelRelation(string(access))
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("failed to recognize given access: %q", access), err)
+	}
+
+	err = j.doModelAdmin(ctx, user, mt, func(_ *dbmodel.Model, _ API) error {
+		group, err := j.getModelAccessGroup(ctx, groupName)
+		if err != nil {
+			return err
+		}
+		if err := j.OpenFGAClient.SetGroupModelAccess(ctx, group.ResourceTag(), mt, targetRelation); err != nil {
+			return errors.E(err, op, "failed to set model access")
+		}
+		return nil
+	})
+	if err != nil {
+		zapctx.Error(
+			ctx,
+			"failed to grant model access",
+			zaputil.Error(err),
+			zap.String("targetGroup", groupName),
+			zap.String("model", string(mt.Id())),
+			zap.String("access", string(access)),
+		)
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RevokeModelGroupAccess revokes the given access level on the given
+// model from the named group. As with users, revoking an access level
+// also revokes any higher access levels the group holds. Access that
+// members of the group hold directly is unaffected. If the model or the
+// group is not found then an error with the code CodeNotFound is
+// returned. If the authenticated user does not have admin access to the
+// model then an error with the code CodeUnauthorized is returned.
+func (j *JIMM) RevokeModelGroupAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error {
+	const op = errors.Op("jimm.RevokeModelGroupAccess")
+	defer j.Cache.InvalidateModelAccess(mt)
+
+	targetRelation, err := ToModelRelation(string(access))
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("failed to recognize given access: %q", access), err)
+	}
+
+	var relationsToRevoke []openfga.Relation
+	switch targetRelation {
+	case ofganames.ReaderRelation:
+		relationsToRevoke = []openfga.Relation{
+			ofganames.ReaderRelation,
+			ofganames.WriterRelation,
+			ofganames.AdministratorRelation,
+		}
+	case ofganames.WriterRelation:
+		relationsToRevoke = []openfga.Relation{
+			ofganames.WriterRelation,
+			ofganames.AdministratorRelation,
+		}
+	case ofganames.AdministratorRelation:
+		relationsToRevoke = []openfga.Relation{
+			ofganames.AdministratorRelation,
+		}
+	}
+
+	err = j.doModelAdmin(ctx, user, mt, func(_ *dbmodel.Model, _ API) error {
+		group, err := j.getModelAccessGroup(ctx, groupName)
+		if err != nil {
+			return err
+		}
+		if err := j.OpenFGAClient.UnsetGroupModelAccess(ctx, group.ResourceTag(), mt, relationsToRevoke...); err != nil {
+			return errors.E(err, op, "failed to unset model access")
+		}
+		return nil
+	})
+	if err != nil {
+		zapctx.Error(
+			ctx,
+			"failed to revoke model access",
+			zaputil.Error(err),
+			zap.String("targetGroup", groupName),
+			zap.String("model", string(mt.Id())),
+			zap.String("access", string(access)),
+		)
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// getModelAccessGroup returns the named group that is the target of a
+// model access change.
+func (j *JIMM) getModelAccessGroup(ctx context.Context, groupName string) (*dbmodel.GroupEntry, error) {
+	group := dbmodel.GroupEntry{Name: groupName}
+	if err := j.Database.GetGroup(ctx, &group); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil, errors.E(errors.CodeNotFound, fmt.Sprintf("group %s not found", groupName))
+		}
+		return nil, err
+	}
+	return &group, nil
+}
+
 // DestroyModel starts the process of destroying the given model. If the
 // given user is not a controller superuser or a model admin an error
 // with a code of CodeUnauthorized is returned. Any error returned from
-// the juju API will not have it's code masked.
+// the juju API will not have it's code masked. If other models depend on
+// the model it is only destroyed with force, otherwise an error with the
+// code CodeModelHasDependents is returned. If a model destroy window is
+// configured the model is not destroyed immediately, instead the destroy
+// is left pending until the window has passed, see
+// ReapPendingModelDestroys.
 func (j *JIMM) DestroyModel(ctx context.Context, user *openfga.User, mt names.ModelTag, destroyStorage, force *bool, maxWait, timeout *time.Duration) error {
 	const op = errors.Op("jimm.DestroyModel")
 
+	if j.ModelDestroyWindow > 0 {
+		if err := j.scheduleModelDestroy(ctx, user, mt, destroyStorage, force, maxWait, timeout); err != nil {
+			return errors.E(op, err)
+		}
+		return nil
+	}
+
 	err := j.doModelAdmin(ctx, user, mt, func(m *dbmodel.Model, api API) error {
+		if err := j.CheckModelFrozen(ctx, m.ID, "ModelManager", "DestroyModels"); err != nil {
+			return err
+		}
+		if err := j.checkModelDependents(ctx, m, force); err != nil {
+			return err
+		}
 		if err := api.DestroyModel(ctx, mt, destroyStorage, force, maxWait, timeout); err != nil {
 			return err
 		}
@@ -1093,6 +1663,89 @@ func (j *JIMM) DestroyModel(ctx context.Context, user *openfga.User, mt names.Mo
 	return nil
 }
 
+// DestroyModelImpact determines what would be affected by destroying the
+// given model with the given destroy-storage value, without destroying
+// it. The machine, application, unit and storage details are retrieved
+// from the model's controller, offers with consumers and dependent
+// models are determined from the database. If the given user is not a controller superuser or a
+// model admin an error with the code CodeUnauthorized is returned.
+func (j *JIMM) DestroyModelImpact(ctx context.Context, user *openfga.User, mt names.ModelTag, destroyStorage *bool) (*apiparams.DestroyModelImpact, error) {
+	const op = errors.Op("jimm.DestroyModelImpact")
+
+	impact := apiparams.DestroyModelImpact{
+		ModelTag: mt.String(),
+	}
+	err := j.doModelAdmin(ctx, user, mt, func(m *dbmodel.Model, api API) error {
+		ms := jujuparams.ModelStatus{ModelTag: mt.String()}
+		if err := api.ModelStatus(ctx, &ms); err != nil {
+			return err
+		}
+		impact.MachineCount = ms.HostedMachineCount
+		impact.ApplicationCount = ms.ApplicationCount
+		impact.UnitCount = ms.UnitCount
+		for _, v := range ms.Volumes {
+			impact.Storage = append(impact.Storage, storageImpact("volume", v.Id, v.ProviderId, v.Detachable, destroyStorage))
+		}
+		for _, f := range ms.Filesystems {
+			impact.Storage = append(impact.Storage, storageImpact("filesystem", f.Id, f.ProviderId, f.Detachable, destroyStorage))
+		}
+		impact.StorageDecisionRequired = destroyStorage == nil && len(impact.Storage) > 0
+
+		for _, o := range m.Offers {
+			if len(o.Connections) == 0 {
+				continue
+			}
+			oi := apiparams.OfferImpact{
+				OfferURL:        o.URL,
+				ConnectionCount: len(o.Connections),
+			}
+			seen := make(map[string]bool)
+			for _, conn := range o.Connections {
+				if !seen[conn.SourceModelTag] {
+					seen[conn.SourceModelTag] = true
+					oi.ConsumingModelTags = append(oi.ConsumingModelTags, conn.SourceModelTag)
+				}
+			}
+			sort.Strings(oi.ConsumingModelTags)
+			impact.Offers = append(impact.Offers, oi)
+		}
+		sort.Slice(impact.Offers, func(i, j int) bool {
+			return impact.Offers[i].OfferURL < impact.Offers[j].OfferURL
+		})
+
+		dependents, err := j.dependentModels(ctx, m, false)
+		if err != nil {
+			return err
+		}
+		impact.DependentModels = modelNames(dependents)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return &impact, nil
+}
+
+// storageImpact determines what would happen to an item of storage when
+// its model is destroyed. Storage that cannot be detached is always
+// destroyed along with its machine.
+func storageImpact(kind, id, providerID string, detachable bool, destroyStorage *bool) apiparams.StorageImpact {
+	si := apiparams.StorageImpact{
+		Kind:       kind,
+		ID:         id,
+		ProviderID: providerID,
+		Detachable: detachable,
+	}
+	switch {
+	case destroyStorage == nil:
+	case *destroyStorage || !detachable:
+		si.Action = apiparams.StorageActionDestroy
+	default:
+		si.Action = apiparams.StorageActionDetach
+	}
+	return si
+}
+
 // DumpModel retrieves a database-agnostic dump of the given model from its
 // juju controller. If simplified is true a simpllified dump is requested.
 // If the given user is not a controller superuser or a model admin an
@@ -1166,8 +1819,7 @@ func (j *JIMM) doModelAdmin(ctx context.Context, user *openfga.User, mt names.Mo
 
 // GetUserModelAccess returns the access level a user has against a specific model.
 func (j *JIMM) GetUserModelAccess(ctx context.Context, user *openfga.User, model names.ModelTag) (string, error) {
-	accessLevel := user.GetModelAccess(ctx, model)
-	return ToModelAccessString(accessLevel), nil
+	return j.getModelAccess(ctx, user, model), nil
 }
 
 func (j *JIMM) doModel(ctx context.Context, user *openfga.User, mt names.ModelTag, access string, f func(*dbmodel.Model, API) error) error {
@@ -1235,7 +1887,7 @@ func (j *JIMM) ChangeModelCredential(ctx context.Context, user *openfga.User, mo
 
 	var m *dbmodel.Model
 	err = j.doModelAdmin(ctx, user, modelTag, func(model *dbmodel.Model, api API) error {
-		_, err = j.updateControllerCloudCredential(ctx, &credential, api.UpdateCredential)
+		_, err = j.propagateCloudCredential(ctx, &credential, &model.Controller, api)
 		if err != nil {
 			return errors.E(op, err)
 		}
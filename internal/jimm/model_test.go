@@ -28,6 +28,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 func TestModelCreateArgs(t *testing.T) {
@@ -255,6 +256,159 @@ users:
 			Info:   "running a test",
 		},
 	},
+}, {
+	name: "CreateModelInControllerTier",
+	env: `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: alice@canonical.com
+    access: add-model
+user-defaults:
+- user: alice@canonical.com
+  defaults:
+    key4: value4
+    jimm-controller-tier: production
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  tiers: [gpu]
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 0
+- name: controller-2
+  uuid: 00000000-0000-0000-0000-0000-0000000000002
+  cloud: test-cloud
+  region: test-region-1
+  tiers: [production]
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 2
+`[1:],
+	updateCredential: func(_ context.Context, _ jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+		return nil, nil
+	},
+	grantJIMMModelAdmin: func(_ context.Context, _ names.ModelTag) error {
+		return nil
+	},
+	createModel: assertConfig(map[string]interface{}{
+		"key4": "value4",
+	}, createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+  info: running a test
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:])),
+	username:  "alice@canonical.com",
+	jimmAdmin: true,
+	args: jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		Config:             map[string]interface{}{"jimm-controller-tier": "gpu"},
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+	},
+	expectModel: dbmodel.Model{
+		Name: "test-model",
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+		Owner: dbmodel.Identity{
+			Name: "alice@canonical.com",
+		},
+		Controller: dbmodel.Controller{
+			Name:        "controller-1",
+			UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+			CloudName:   "test-cloud",
+			CloudRegion: "test-region-1",
+			Tiers:       dbmodel.Strings{"gpu"},
+		},
+		CloudRegion: dbmodel.CloudRegion{
+			Cloud: dbmodel.Cloud{
+				Name: "test-cloud",
+				Type: "test-provider",
+			},
+			Name: "test-region-1",
+		},
+		CloudCredential: dbmodel.CloudCredential{
+			Name:     "test-credential-1",
+			AuthType: "empty",
+		},
+		Life: state.Alive.String(),
+		Status: dbmodel.Status{
+			Status: "started",
+			Info:   "running a test",
+		},
+	},
+}, {
+	name: "CreateModelInUnavailableControllerTier",
+	env: `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: alice@canonical.com
+    access: add-model
+user-defaults:
+- user: alice@canonical.com
+  defaults:
+    key4: value4
+    jimm-controller-tier: staging
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  tiers: [gpu]
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 0
+- name: controller-2
+  uuid: 00000000-0000-0000-0000-0000-0000000000002
+  cloud: test-cloud
+  region: test-region-1
+  tiers: [production]
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 2
+`[1:],
+	username:  "alice@canonical.com",
+	jimmAdmin: true,
+	args: jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+	},
+	expectError: `no controllers in tier "staging" for cloud region test-cloud/test-region-1`,
 }, {
 	name: "CreateModelWithoutCloudRegion",
 	env: `
@@ -878,6 +1032,41 @@ users:
 		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
 	},
 	expectError: "unauthorized",
+}, {
+	name: "CloudCredentialNotShared",
+	env: `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: bob@canonical.com
+    access: add-model
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 0
+`[1:],
+	username: "bob@canonical.com",
+	args: jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("bob@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+	},
+	expectError: "unauthorized",
 }, {
 	name: "CreateModelWithImplicitCloud",
 	env: `
@@ -1647,6 +1836,208 @@ func TestModelStatus(t *testing.T) {
 	}
 }
 
+func TestModelMachines(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// The controller must not be contacted.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	model := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	machine := dbmodel.Machine{
+		ModelID:          model.ID,
+		MachineID:        "0",
+		InstanceID:       "i-0",
+		Life:             "alive",
+		AvailabilityZone: "zone-1",
+		Addresses: dbmodel.Addresses{{
+			Value: "10.0.0.4",
+			CIDR:  "10.0.0.0/24",
+		}},
+		Subnets: dbmodel.Strings{"10.0.0.0/24"},
+	}
+	err = j.Database.UpsertMachine(ctx, &machine)
+	c.Assert(err, qt.IsNil)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	for _, username := range []string{"alice@canonical.com", "bob@canonical.com"} {
+		identity := env.User(username).DBObject(c, j.Database)
+		machines, err := j.ModelMachines(ctx, openfga.NewUser(&identity, client), mt)
+		c.Assert(err, qt.IsNil, qt.Commentf("user %s", username))
+		c.Assert(machines, qt.HasLen, 1)
+		c.Check(machines[0].MachineID, qt.Equals, "0")
+		c.Check(machines[0].AvailabilityZone, qt.Equals, "zone-1")
+		c.Check(machines[0].Addresses, qt.DeepEquals, machine.Addresses)
+		c.Check(machines[0].Subnets, qt.DeepEquals, machine.Subnets)
+	}
+
+	// Users with read access cannot see machine details.
+	charlie := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	_, err = j.ModelMachines(ctx, openfga.NewUser(&charlie, client), mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.ModelMachines(ctx, openfga.NewUser(&charlie, client), names.NewModelTag("00000002-0000-0000-0000-000000000002"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestDestroyModelImpact(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	destroyModelCalled := false
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			ModelStatus_: func(_ context.Context, ms *jujuparams.ModelStatus) error {
+				ms.HostedMachineCount = 2
+				ms.ApplicationCount = 3
+				ms.UnitCount = 4
+				ms.Volumes = []jujuparams.ModelVolumeInfo{{
+					Id:         "0",
+					ProviderId: "vol-0",
+					Detachable: true,
+				}, {
+					Id: "1",
+				}}
+				ms.Filesystems = []jujuparams.ModelFilesystemInfo{{
+					Id:         "0/0",
+					Detachable: true,
+				}}
+				return nil
+			},
+			DestroyModel_: func(context.Context, names.ModelTag, *bool, *bool, *time.Duration, *time.Duration) error {
+				destroyModelCalled = true
+				return nil
+			},
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	model := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	err = j.Database.AddApplicationOffer(ctx, &dbmodel.ApplicationOffer{
+		UUID:            "00000003-0000-0000-0000-000000000001",
+		URL:             "alice@canonical.com/model-1.unused",
+		Name:            "unused",
+		ModelID:         model.ID,
+		ApplicationName: "app-1",
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.Database.AddApplicationOffer(ctx, &dbmodel.ApplicationOffer{
+		UUID:            "00000003-0000-0000-0000-000000000002",
+		URL:             "alice@canonical.com/model-1.db",
+		Name:            "db",
+		ModelID:         model.ID,
+		ApplicationName: "app-2",
+		Connections: []dbmodel.ApplicationOfferConnection{{
+			SourceModelTag: names.NewModelTag("00000002-0000-0000-0000-000000000003").String(),
+			RelationID:     1,
+		}, {
+			SourceModelTag: names.NewModelTag("00000002-0000-0000-0000-000000000002").String(),
+			RelationID:     2,
+		}, {
+			SourceModelTag: names.NewModelTag("00000002-0000-0000-0000-000000000002").String(),
+			RelationID:     3,
+		}},
+	})
+	c.Assert(err, qt.IsNil)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	expectOffers := []apiparams.OfferImpact{{
+		OfferURL: "alice@canonical.com/model-1.db",
+		ConsumingModelTags: []string{
+			names.NewModelTag("00000002-0000-0000-0000-000000000002").String(),
+			names.NewModelTag("00000002-0000-0000-0000-000000000003").String(),
+		},
+		ConnectionCount: 3,
+	}}
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	destroyStorage := false
+	impact, err := j.DestroyModelImpact(ctx, alice, mt, &destroyStorage)
+	c.Assert(err, qt.IsNil)
+	c.Check(impact, qt.DeepEquals, &apiparams.DestroyModelImpact{
+		ModelTag:         mt.String(),
+		MachineCount:     2,
+		ApplicationCount: 3,
+		UnitCount:        4,
+		Storage: []apiparams.StorageImpact{{
+			Kind:       "volume",
+			ID:         "0",
+			ProviderID: "vol-0",
+			Detachable: true,
+			Action:     apiparams.StorageActionDetach,
+		}, {
+			Kind:   "volume",
+			ID:     "1",
+			Action: apiparams.StorageActionDestroy,
+		}, {
+			Kind:       "filesystem",
+			ID:         "0/0",
+			Detachable: true,
+			Action:     apiparams.StorageActionDetach,
+		}},
+		Offers: expectOffers,
+	})
+
+	destroyStorage = true
+	impact, err = j.DestroyModelImpact(ctx, alice, mt, &destroyStorage)
+	c.Assert(err, qt.IsNil)
+	for _, s := range impact.Storage {
+		c.Check(s.Action, qt.Equals, apiparams.StorageActionDestroy)
+	}
+
+	impact, err = j.DestroyModelImpact(ctx, alice, mt, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(impact.StorageDecisionRequired, qt.IsTrue)
+	for _, s := range impact.Storage {
+		c.Check(s.Action, qt.Equals, "")
+	}
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	_, err = j.DestroyModelImpact(ctx, bob, mt, nil)
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+
+	c.Check(destroyModelCalled, qt.IsFalse)
+	m := dbmodel.Model{ID: model.ID}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, model.Life)
+}
+
 const forEachModelTestEnv = `clouds:
 - name: test-cloud
   type: test-provider
@@ -3867,3 +4258,321 @@ func newVersion(s string) *version.Number {
 	n := version.MustParse(s)
 	return &n
 }
+
+func TestModelGroupAccess(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, grantModelAccessTestEnv)
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{},
+	}
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer:        dialer,
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	group, err := j.Database.AddGroup(ctx, "test-group")
+	c.Assert(err, qt.IsNil)
+	bob := names.NewUserTag("bob@canonical.com")
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	bobHasAccess := func(relation openfga.Relation) bool {
+		ok, err := client.CheckRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(bob),
+			Relation: relation,
+			Target:   ofganames.ConvertTag(mt),
+		}, false)
+		c.Assert(err, qt.IsNil)
+		return ok
+	}
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	charlie := env.User("charlie@canonical.com").DBObject(c, j.Database)
+
+	err = j.GrantModelGroupAccess(ctx, openfga.NewUser(&charlie, client), mt, "test-group", "write")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.GrantModelGroupAccess(ctx, openfga.NewUser(&alice, client), mt, "no-such-group", "write")
+	c.Check(err, qt.ErrorMatches, "group no-such-group not found")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.GrantModelGroupAccess(ctx, openfga.NewUser(&alice, client), mt, "test-group", "superuser")
+	c.Check(err, qt.ErrorMatches, `failed to recognize given access: "superuser"`)
+
+	err = j.GrantModelGroupAccess(ctx, openfga.NewUser(&alice, client), mt, "test-group", "write")
+	c.Assert(err, qt.IsNil)
+	c.Check(bobHasAccess(ofganames.WriterRelation), qt.IsTrue)
+	c.Check(bobHasAccess(ofganames.AdministratorRelation), qt.IsFalse)
+
+	// Granting the same access again is not an error.
+	err = j.GrantModelGroupAccess(ctx, openfga.NewUser(&alice, client), mt, "test-group", "write")
+	c.Assert(err, qt.IsNil)
+
+	err = j.RevokeModelGroupAccess(ctx, openfga.NewUser(&alice, client), mt, "test-group", "read")
+	c.Assert(err, qt.IsNil)
+	c.Check(bobHasAccess(ofganames.WriterRelation), qt.IsFalse)
+	c.Check(bobHasAccess(ofganames.ReaderRelation), qt.IsFalse)
+	c.Check(dialer.IsClosed(), qt.IsTrue)
+}
+
+func TestAddModelCloudCredentialPreference(t *testing.T) {
+	c := qt.New(t)
+
+	var n int
+	api := &jimmtest.API{
+		UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, nil
+		},
+		GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+			return nil
+		},
+		CreateModel_: func(ctx context.Context, args *jujuparams.ModelCreateArgs, mi *jujuparams.ModelInfo) error {
+			n++
+			err := createModel(fmt.Sprintf(`
+uuid: 00000001-0000-0000-0000-0000-00000000000%d
+status:
+  status: started
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:], n))(ctx, args, mi)
+			return err
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+	}
+	ctx := context.Background()
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+- username: bob@canonical.com
+  controller-access: login
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+- name: test-credential-2
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+`[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dbUser := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&dbUser, client)
+
+	addModel := func(c *qt.C, name, credential string) (string, error) {
+		args := jimm.ModelCreateArgs{}
+		jujuArgs := jujuparams.ModelCreateArgs{
+			Name:     name,
+			OwnerTag: user.Tag().String(),
+			CloudTag: names.NewCloudTag("test-cloud").String(),
+		}
+		if credential != "" {
+			jujuArgs.CloudCredentialTag = names.NewCloudCredentialTag("test-cloud/alice@canonical.com/" + credential).String()
+		}
+		err := args.FromJujuModelCreateArgs(&jujuArgs)
+		c.Assert(err, qt.IsNil)
+		mi, err := j.AddModel(ctx, user, &args)
+		if err != nil {
+			return "", err
+		}
+		tag, err := names.ParseCloudCredentialTag(mi.CloudCredentialTag)
+		c.Assert(err, qt.IsNil)
+		return tag.Name(), nil
+	}
+
+	// With several credentials and no preference the choice is ambiguous.
+	_, err = addModel(c, "model-1", "")
+	c.Check(err, qt.ErrorMatches, `more than one possible credential to use for cloud test-cloud, specify a credential or set a default`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// The credential last used in the region is remembered.
+	cred, err := addModel(c, "model-1", "test-credential-2")
+	c.Assert(err, qt.IsNil)
+	c.Check(cred, qt.Equals, "test-credential-2")
+	cred, err = addModel(c, "model-2", "")
+	c.Assert(err, qt.IsNil)
+	c.Check(cred, qt.Equals, "test-credential-2")
+
+	// A default credential takes precedence.
+	err = j.SetDefaultCloudCredential(ctx, user, names.NewCloudTag("test-cloud"), names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1"))
+	c.Assert(err, qt.IsNil)
+	cred, err = addModel(c, "model-3", "")
+	c.Assert(err, qt.IsNil)
+	c.Check(cred, qt.Equals, "test-credential-1")
+
+	// Removing the default falls back to the credential last used.
+	err = j.SetDefaultCloudCredential(ctx, user, names.NewCloudTag("test-cloud"), names.CloudCredentialTag{})
+	c.Assert(err, qt.IsNil)
+	cred, err = addModel(c, "model-4", "")
+	c.Assert(err, qt.IsNil)
+	c.Check(cred, qt.Equals, "test-credential-1")
+
+	err = j.SetDefaultCloudCredential(ctx, user, names.NewCloudTag("test-cloud"), names.NewCloudCredentialTag("test-cloud/alice@canonical.com/no-such-credential"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.SetDefaultCloudCredential(ctx, user, names.NewCloudTag("other-cloud"), names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1"))
+	c.Check(err, qt.ErrorMatches, `cloud credential cloud mismatch`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	err = j.SetDefaultCloudCredential(ctx, bob, names.NewCloudTag("test-cloud"), names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1"))
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestAddModelAvoidsDegradedController(t *testing.T) {
+	c := qt.New(t)
+
+	api := &jimmtest.API{
+		UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, nil
+		},
+		GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+			return nil
+		},
+		CreateModel_: createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:]),
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+		Health: jimm.NewControllerHealth(jimm.ControllerHealthParams{}),
+	}
+	ctx := context.Background()
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+- name: controller-2
+  uuid: 00000000-0000-0000-0000-0000-0000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+`[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	for i := 0; i < 10; i++ {
+		j.Health.ObserveRequest("controller-1", time.Second, errors.E("connection refused"))
+	}
+	c.Assert(j.Health.Degraded("controller-1"), qt.IsTrue)
+
+	dbUser := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&dbUser, client)
+
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:        "test-model",
+		OwnerTag:    user.Tag().String(),
+		CloudTag:    names.NewCloudTag("test-cloud").String(),
+		CloudRegion: "test-region-1",
+	})
+	c.Assert(err, qt.IsNil)
+	_, err = j.AddModel(ctx, user, &args)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Controller.Name, qt.Equals, "controller-2")
+}
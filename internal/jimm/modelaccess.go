@@ -0,0 +1,269 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// DefaultModelAccessResyncInterval is the default minimum time between
+// checking the access of successive models when re-syncing model access
+// to the controllers. This limits the load a re-sync places on the
+// controllers.
+const DefaultModelAccessResyncInterval = 100 * time.Millisecond
+
+// ResyncModelAccess re-syncs JIMM's view of model access to the
+// controllers hosting the models, see ResyncAllModelAccess for details.
+// If the request names a controller only models on that controller are
+// re-synced. The differences found are only fixed if the request asks
+// for them to be applied. The re-sync is started as an operation, see
+// GetOperation, and the operation's ID is returned. Only JIMM
+// administrators can perform this operation.
+func (j *JIMM) ResyncModelAccess(ctx context.Context, user *openfga.User, req apiparams.ResyncModelAccessRequest) (apiparams.ResyncModelAccessResponse, error) {
+	const op = errors.Op("jimm.ResyncModelAccess")
+
+	if !user.JimmAdmin {
+		return apiparams.ResyncModelAccessResponse{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	controllers, err := j.selectControllers(ctx, req.Controller)
+	if err != nil {
+		return apiparams.ResyncModelAccessResponse{}, errors.E(op, err)
+	}
+	o, err := j.startOperation(ctx, user, "resync-model-access", func(ctx context.Context, r *operationReporter) (interface{}, error) {
+		return j.resyncModelAccessControllers(ctx, controllers, DefaultModelAccessResyncInterval, req.Apply, r)
+	})
+	if err != nil {
+		return apiparams.ResyncModelAccessResponse{}, errors.E(op, err)
+	}
+	return apiparams.ResyncModelAccessResponse{
+		Applied:   req.Apply,
+		Operation: o.UUID,
+	}, nil
+}
+
+// ResyncAllModelAccess compares the access held on every controller for
+// the models it hosts with JIMM's view of that access. Users are granted
+// model access in OpenFGA, so the only access JIMM expects on the
+// controller is that of the model owner and of JIMM's own user, which
+// must be a model admin. The differences found are returned along with
+// any errors encountered. If apply is true the differences are fixed:
+// missing JIMM admin access is granted and any other access, for example
+// access granted directly on the controller, is revoked. At most one
+// model is checked per interval. If controllerName is not empty only
+// models on that controller are re-synced.
+func (j *JIMM) ResyncAllModelAccess(ctx context.Context, controllerName string, interval time.Duration, apply bool) (apiparams.ResyncModelAccessResponse, error) {
+	const op = errors.Op("jimm.ResyncAllModelAccess")
+
+	controllers, err := j.selectControllers(ctx, controllerName)
+	if err != nil {
+		return apiparams.ResyncModelAccessResponse{}, errors.E(op, err)
+	}
+	resp, err := j.resyncModelAccessControllers(ctx, controllers, interval, apply, nil)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// resyncModelAccessControllers re-syncs model access to the given
+// controllers, see ResyncAllModelAccess, reporting the progress of the
+// re-sync to the given reporter if it is not nil.
+func (j *JIMM) resyncModelAccessControllers(ctx context.Context, controllers []dbmodel.Controller, interval time.Duration, apply bool, r *operationReporter) (apiparams.ResyncModelAccessResponse, error) {
+	resp := apiparams.ResyncModelAccessResponse{Applied: apply}
+	var limit <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+	for i := range controllers {
+		ctl := &controllers[i]
+		r.Progress(ctx, i, len(controllers))
+		models, err := j.liveControllerModels(ctx, ctl)
+		if err != nil {
+			return resp, err
+		}
+		if len(models) == 0 {
+			continue
+		}
+
+		jimmUser, err := j.controllerUsername(ctx, ctl)
+		if err != nil {
+			resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+				Controller: ctl.Name,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		api, err := j.dialController(ctx, ctl)
+		if err != nil {
+			resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+				Controller: ctl.Name,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		users, err := api.UserInfo(ctx)
+		if err != nil {
+			api.Close()
+			resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+				Controller: ctl.Name,
+				Error:      err.Error(),
+			})
+			continue
+		}
+		superusers := controllerSuperusers(users)
+		for k := range models {
+			if limit != nil && (i > 0 || k > 0) {
+				select {
+				case <-limit:
+				case <-ctx.Done():
+					api.Close()
+					return resp, ctx.Err()
+				}
+			}
+			resp.ModelsChecked++
+			diffs, err := resyncModelAccess(ctx, api, ctl.Name, jimmUser, superusers, &models[k], apply)
+			resp.Differences = append(resp.Differences, diffs...)
+			if err != nil {
+				zapctx.Error(ctx, "failed to re-sync model access", zap.String("model", models[k].UUID.String), zaputil.Error(err))
+				resp.Errors = append(resp.Errors, apiparams.ModelAccessResyncError{
+					Controller: ctl.Name,
+					ModelTag:   models[k].ResourceTag().String(),
+					Error:      err.Error(),
+				})
+			}
+		}
+		api.Close()
+		r.Step(ctx, "re-synced model access to controller "+ctl.Name)
+	}
+	if len(resp.Differences) > 0 {
+		zapctx.Warn(ctx, "model access differences found", zap.Int("count", len(resp.Differences)), zap.Bool("applied", apply))
+	}
+	return resp, nil
+}
+
+// selectControllers returns the controller with the given name, or every
+// controller if controllerName is empty.
+func (j *JIMM) selectControllers(ctx context.Context, controllerName string) ([]dbmodel.Controller, error) {
+	var controllers []dbmodel.Controller
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if controllerName == "" || ctl.Name == controllerName {
+			controllers = append(controllers, *ctl)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if controllerName != "" && len(controllers) == 0 {
+		return nil, errors.E(errors.CodeNotFound, "controller not found")
+	}
+	return controllers, nil
+}
+
+// liveControllerModels returns the live models hosted on the given
+// controller.
+func (j *JIMM) liveControllerModels(ctx context.Context, ctl *dbmodel.Controller) ([]dbmodel.Model, error) {
+	var models []dbmodel.Model
+	err := j.Database.ForEachControllerModel(ctx, ctl, func(m *dbmodel.Model) error {
+		if m.Life == state.Alive.String() {
+			models = append(models, *m)
+		}
+		return nil
+	})
+	return models, err
+}
+
+// controllerSuperusers returns the IDs of the enabled users with superuser
+// access to the controller.
+func controllerSuperusers(users []jujuparams.UserInfo) map[string]bool {
+	superusers := make(map[string]bool)
+	for _, u := range users {
+		if u.Disabled || u.Access != "superuser" || !names.IsValidUser(u.Username) {
+			continue
+		}
+		superusers[names.NewUserTag(u.Username).Id()] = true
+	}
+	return superusers
+}
+
+// resyncModelAccess compares the access held on the named controller for
+// the given model with the access JIMM, connected to the controller as
+// jimmUser, expects. The model's owners, both in JIMM and on the
+// controller, and the controller's superusers keep their access. The
+// differences found are returned, and fixed if apply is true.
+func resyncModelAccess(ctx context.Context, api API, controllerName, jimmUser string, superusers map[string]bool, m *dbmodel.Model, apply bool) ([]apiparams.ModelAccessDifference, error) {
+	mt := m.ResourceTag()
+	mi := jujuparams.ModelInfo{UUID: mt.Id()}
+	if err := api.ModelInfo(ctx, &mi); err != nil {
+		return nil, err
+	}
+
+	jimmID := names.NewUserTag(jimmUser).Id()
+	owner := names.NewUserTag(m.OwnerIdentityName).Id()
+	var controllerOwner string
+	if ot, err := names.ParseUserTag(mi.OwnerTag); err == nil {
+		controllerOwner = ot.Id()
+	}
+	var diffs []apiparams.ModelAccessDifference
+	var jimmAccess jujuparams.UserAccessPermission
+	for _, u := range mi.Users {
+		if !names.IsValidUser(u.UserName) {
+			continue
+		}
+		ut := names.NewUserTag(u.UserName)
+		switch ut.Id() {
+		case jimmID:
+			jimmAccess = u.Access
+			continue
+		case owner, controllerOwner:
+			continue
+		}
+		if superusers[ut.Id()] {
+			continue
+		}
+		diffs = append(diffs, apiparams.ModelAccessDifference{
+			Controller:       controllerName,
+			ModelTag:         mt.String(),
+			User:             ut.Id(),
+			ControllerAccess: string(u.Access),
+		})
+		if !apply {
+			continue
+		}
+		// Revoking read access removes all access to the model.
+		if err := api.RevokeModelAccess(ctx, mt, ut, jujuparams.ModelReadAccess); err != nil {
+			return diffs, err
+		}
+	}
+	if jimmAccess != jujuparams.ModelAdminAccess {
+		diffs = append(diffs, apiparams.ModelAccessDifference{
+			Controller:       controllerName,
+			ModelTag:         mt.String(),
+			User:             jimmID,
+			ExpectedAccess:   string(jujuparams.ModelAdminAccess),
+			ControllerAccess: string(jimmAccess),
+		})
+		if !apply {
+			return diffs, nil
+		}
+		if err := api.GrantJIMMModelAdmin(ctx, mt); err != nil {
+			return diffs, err
+		}
+	}
+	return diffs, nil
+}
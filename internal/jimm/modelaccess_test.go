@@ -0,0 +1,437 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const resyncModelAccessTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+  admin-user: admin
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: dying
+users:
+- username: bob@canonical.com
+  controller-access: login
+`
+
+func TestResyncModelAccess(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	model1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	model2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+
+	var mu sync.Mutex
+	var revoked []string
+	var granted []string
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			UserInfo_: func(context.Context) ([]jujuparams.UserInfo, error) {
+				return []jujuparams.UserInfo{{
+					Username: "admin",
+					Access:   "superuser",
+				}}, nil
+			},
+			ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+				switch mi.UUID {
+				case model1.Id():
+					// model-1 has had access granted directly on the
+					// controller, and has lost JIMM's admin access.
+					mi.Users = []jujuparams.ModelUserInfo{{
+						UserName: "admin",
+						Access:   jujuparams.ModelReadAccess,
+					}, {
+						UserName: "alice@canonical.com",
+						Access:   jujuparams.ModelAdminAccess,
+					}, {
+						UserName: "eve@canonical.com",
+						Access:   jujuparams.ModelWriteAccess,
+					}}
+				case model2.Id():
+					mi.Users = []jujuparams.ModelUserInfo{{
+						UserName: "admin",
+						Access:   jujuparams.ModelAdminAccess,
+					}, {
+						UserName: "alice@canonical.com",
+						Access:   jujuparams.ModelAdminAccess,
+					}}
+				default:
+					return errors.E("unexpected model " + mi.UUID)
+				}
+				return nil
+			},
+			RevokeModelAccess_: func(_ context.Context, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
+				mu.Lock()
+				defer mu.Unlock()
+				revoked = append(revoked, mt.Id()+":"+ut.Id()+":"+string(access))
+				return nil
+			},
+			GrantJIMMModelAdmin_: func(_ context.Context, mt names.ModelTag) error {
+				mu.Lock()
+				defer mu.Unlock()
+				granted = append(granted, mt.Id())
+				return nil
+			},
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	expectDiffs := []apiparams.ModelAccessDifference{{
+		Controller:       "controller-1",
+		ModelTag:         model1.String(),
+		User:             "eve@canonical.com",
+		ControllerAccess: "write",
+	}, {
+		Controller:       "controller-1",
+		ModelTag:         model1.String(),
+		User:             "admin",
+		ExpectedAccess:   "admin",
+		ControllerAccess: "read",
+	}}
+
+	// By default the differences are only reported.
+	resp, err := j.ResyncAllModelAccess(ctx, "", 0, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.ResyncModelAccessResponse{
+		ModelsChecked: 2,
+		Differences:   expectDiffs,
+	})
+	c.Check(revoked, qt.HasLen, 0)
+	c.Check(granted, qt.HasLen, 0)
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	_, err = j.ResyncModelAccess(ctx, bob, apiparams.ResyncModelAccessRequest{})
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	alice.JimmAdmin = true
+
+	_, err = j.ResyncModelAccess(ctx, alice, apiparams.ResyncModelAccessRequest{Controller: "no-such-controller"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	resp, err = j.ResyncModelAccess(ctx, alice, apiparams.ResyncModelAccessRequest{Apply: true})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Applied, qt.IsTrue)
+	c.Check(resp.Operation, qt.Not(qt.Equals), "")
+	jimm.WaitForOperations(j)
+
+	o, err := j.GetOperation(ctx, alice, resp.Operation)
+	c.Assert(err, qt.IsNil)
+	c.Check(o.Kind, qt.Equals, "resync-model-access")
+	c.Check(o.Status, qt.Equals, dbmodel.OperationSucceeded)
+	c.Check(o.Result["applied"], qt.Equals, true)
+	c.Check(o.Result["models-checked"], qt.Equals, float64(2))
+	c.Check(o.Result["differences"], qt.HasLen, 2)
+	c.Check(revoked, qt.DeepEquals, []string{model1.Id() + ":eve@canonical.com:read"})
+	c.Check(granted, qt.DeepEquals, []string{model1.Id()})
+	c.Check(dialer.IsClosed(), qt.IsTrue)
+}
+
+func TestResyncModelAccessStoredControllerCredentials(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var revoked []string
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			UserInfo_: func(context.Context) ([]jujuparams.UserInfo, error) {
+				return nil, nil
+			},
+			ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+				mi.Users = []jujuparams.ModelUserInfo{{
+					UserName: "jimm-admin",
+					Access:   jujuparams.ModelAdminAccess,
+				}, {
+					UserName: "alice@canonical.com",
+					Access:   jujuparams.ModelAdminAccess,
+				}}
+				return nil
+			},
+			RevokeModelAccess_: func(_ context.Context, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
+				revoked = append(revoked, ut.Id())
+				return nil
+			},
+		},
+	}
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		CredentialStore: store,
+		Dialer:          dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// Save the controller as AddController does, with its admin
+	// credentials only in the credential store.
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	ctl.AdminIdentityName = ""
+	ctl.AdminPassword = ""
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	err = store.PutControllerCredentials(ctx, "controller-1", "jimm-admin", "secret")
+	c.Assert(err, qt.IsNil)
+
+	resp, err := j.ResyncAllModelAccess(ctx, "controller-1", 0, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.ResyncModelAccessResponse{
+		Applied:       true,
+		ModelsChecked: 2,
+	})
+	c.Check(revoked, qt.HasLen, 0)
+}
+
+func TestResyncModelAccessKeepsControllerSuperusers(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	// The models were created by the controller's bootstrap admin before
+	// JIMM connected as its own service user.
+	var revoked []string
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			UserInfo_: func(context.Context) ([]jujuparams.UserInfo, error) {
+				return []jujuparams.UserInfo{{
+					Username: "admin",
+					Access:   "superuser",
+				}, {
+					Username: "jimm-admin",
+					Access:   "superuser",
+				}, {
+					Username: "operator",
+					Access:   "superuser",
+				}, {
+					Username: "former-operator",
+					Access:   "superuser",
+					Disabled: true,
+				}, {
+					Username: "eve",
+					Access:   "login",
+				}}, nil
+			},
+			ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+				mi.OwnerTag = names.NewUserTag("admin").String()
+				mi.Users = []jujuparams.ModelUserInfo{{
+					UserName: "admin",
+					Access:   jujuparams.ModelAdminAccess,
+				}, {
+					UserName: "jimm-admin",
+					Access:   jujuparams.ModelAdminAccess,
+				}, {
+					UserName: "operator",
+					Access:   jujuparams.ModelWriteAccess,
+				}, {
+					UserName: "former-operator",
+					Access:   jujuparams.ModelWriteAccess,
+				}, {
+					UserName: "eve",
+					Access:   jujuparams.ModelReadAccess,
+				}}
+				return nil
+			},
+			RevokeModelAccess_: func(_ context.Context, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
+				revoked = append(revoked, ut.Id())
+				return nil
+			},
+		},
+	}
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	store := jimmtest.NewInMemoryCredentialStore()
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		CredentialStore: store,
+		Dialer:          dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	ctl := dbmodel.Controller{Name: "controller-1"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	ctl.AdminIdentityName = ""
+	ctl.AdminPassword = ""
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	err = store.PutControllerCredentials(ctx, "controller-1", "jimm-admin", "secret")
+	c.Assert(err, qt.IsNil)
+
+	resp, err := j.ResyncAllModelAccess(ctx, "controller-1", 0, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Errors, qt.HasLen, 0)
+	c.Check(resp.ModelsChecked, qt.Equals, 2)
+	c.Check(revoked, qt.DeepEquals, []string{"former-operator", "eve", "former-operator", "eve"})
+	for _, d := range resp.Differences {
+		c.Check(d.User, qt.Not(qt.Equals), "admin")
+		c.Check(d.User, qt.Not(qt.Equals), "operator")
+	}
+}
+
+func TestResyncAllModelAccessUserInfoError(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var revoked []string
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UserInfo_: func(context.Context) ([]jujuparams.UserInfo, error) {
+					return nil, errors.E("test error")
+				},
+				RevokeModelAccess_: func(_ context.Context, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
+					revoked = append(revoked, ut.Id())
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// Without the controller's superusers no access is revoked.
+	resp, err := j.ResyncAllModelAccess(ctx, "controller-1", 0, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.ResyncModelAccessResponse{
+		Applied: true,
+		Errors: []apiparams.ModelAccessResyncError{{
+			Controller: "controller-1",
+			Error:      "test error",
+		}},
+	})
+	c.Check(revoked, qt.HasLen, 0)
+}
+
+func TestResyncAllModelAccessControllerError(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("test error"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resyncModelAccessTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	resp, err := j.ResyncAllModelAccess(ctx, "controller-1", 0, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.ResyncModelAccessResponse{
+		Errors: []apiparams.ModelAccessResyncError{{
+			Controller: "controller-1",
+			Error:      "test error",
+		}},
+	})
+}
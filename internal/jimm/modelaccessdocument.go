@@ -0,0 +1,407 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
+)
+
+// modelAccessPageSize is the number of tuples read at a time when reading
+// the access held on a model.
+const modelAccessPageSize = 100
+
+// modelAccessRelations holds the relations giving access to a model, from
+// the least to the most access.
+var modelAccessRelations = []openfga.Relation{
+	ofganames.ReaderRelation,
+	ofganames.WriterRelation,
+	ofganames.AdministratorRelation,
+}
+
+// A modelAccessKey identifies a user or group in a model's access.
+type modelAccessKey struct {
+	kind string
+	name string
+}
+
+// A directModelAccess holds the relations an entity holds directly on a
+// model.
+type directModelAccess struct {
+	// object is the OpenFGA entity the relations are held by.
+	object *openfga.Tag
+	// relations holds the entity's relations to the model.
+	relations []openfga.Relation
+}
+
+// access returns the highest access held, "read", "write" or "admin",
+// or an empty string if no access is held.
+func (a *directModelAccess) access() string {
+	if a == nil {
+		return ""
+	}
+	highest := -1
+	for _, r := range a.relations {
+		for i, mr := range modelAccessRelations {
+			if r == mr && i > highest {
+				highest = i
+			}
+		}
+	}
+	if highest < 0 {
+		return ""
+	}
+	return ToModelAccessString(modelAccessRelations[highest])
+}
+
+// ExportModelAccess returns a document declaring the access users and
+// groups hold directly on a model, or on every model of an owner. The
+// access of each model's owner is left out. Exporting a model requires
+// administrator access to it, exporting an owner's models may only be
+// done by the owner or by JIMM administrators.
+func (j *JIMM) ExportModelAccess(ctx context.Context, user *openfga.User, req apiparams.ExportModelAccessRequest) (apiparams.ModelAccessDocument, error) {
+	const op = errors.Op("jimm.ExportModelAccess")
+
+	var models []dbmodel.Model
+	switch {
+	case req.ModelTag != "" && req.Owner == "":
+		mt, err := j.ResolveModel(ctx, user, req.ModelTag)
+		if err != nil {
+			return apiparams.ModelAccessDocument{}, errors.E(op, err)
+		}
+		var m dbmodel.Model
+		m.SetTag(mt)
+		if err := j.Database.GetModel(ctx, &m); err != nil {
+			return apiparams.ModelAccessDocument{}, errors.E(op, err)
+		}
+		if err := checkModelAccessAdmin(ctx, user, mt); err != nil {
+			return apiparams.ModelAccessDocument{}, errors.E(op, err)
+		}
+		models = append(models, m)
+	case req.Owner != "" && req.ModelTag == "":
+		if !user.JimmAdmin && user.Name != req.Owner {
+			return apiparams.ModelAccessDocument{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
+		err := j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+			if m.OwnerIdentityName == req.Owner {
+				models = append(models, *m)
+			}
+			return nil
+		})
+		if err != nil {
+			return apiparams.ModelAccessDocument{}, errors.E(op, err)
+		}
+	default:
+		return apiparams.ModelAccessDocument{}, errors.E(op, errors.CodeBadRequest, "exactly one of model-tag and owner must be specified")
+	}
+
+	doc := apiparams.ModelAccessDocument{
+		Models: make([]apiparams.ModelAccessSpec, 0, len(models)),
+	}
+	groupNames := make(map[string]string)
+	for _, m := range models {
+		access, err := j.readModelAccess(ctx, m.ResourceTag(), groupNames)
+		if err != nil {
+			return apiparams.ModelAccessDocument{}, errors.E(op, err)
+		}
+		doc.Models = append(doc.Models, modelAccessSpec(&m, access))
+	}
+	sort.Slice(doc.Models, func(i, j int) bool {
+		return doc.Models[i].Model < doc.Models[j].Model
+	})
+	return doc, nil
+}
+
+// modelAccessSpec returns the specification of the given access to the
+// given model, leaving out the access of the model's owner.
+func modelAccessSpec(m *dbmodel.Model, access map[modelAccessKey]*directModelAccess) apiparams.ModelAccessSpec {
+	spec := apiparams.ModelAccessSpec{
+		Model: m.OwnerIdentityName + "/" + m.Name,
+	}
+	for k, a := range access {
+		switch {
+		case k.kind == names.UserTagKind && k.name == m.OwnerIdentityName:
+		case k.kind == names.UserTagKind:
+			if spec.Users == nil {
+				spec.Users = make(map[string]string)
+			}
+			spec.Users[k.name] = a.access()
+		default:
+			if spec.Groups == nil {
+				spec.Groups = make(map[string]string)
+			}
+			spec.Groups[k.name] = a.access()
+		}
+	}
+	return spec
+}
+
+// A modelAccessPlan holds the changes needed to make the access to a
+// model match its specification.
+type modelAccessPlan struct {
+	model   dbmodel.Model
+	changes []apiparams.ModelAccessChange
+	add     []openfga.Tuple
+	remove  []openfga.Tuple
+}
+
+// ApplyModelAccess makes the access users and groups hold directly on
+// each model in the given document match the document. Users and groups
+// not listed for a model lose their direct access to it, the access of
+// the model's owner is left unchanged. The user must have administrator
+// access to every model in the document. The whole document is validated
+// before any change is made, and applying a document that already
+// matches makes no changes. If req.DryRun is set the changes are
+// reported but not made.
+func (j *JIMM) ApplyModelAccess(ctx context.Context, user *openfga.User, req apiparams.ApplyModelAccessRequest) (apiparams.ApplyModelAccessResponse, error) {
+	const op = errors.Op("jimm.ApplyModelAccess")
+
+	groups := make(map[string]*dbmodel.GroupEntry)
+	groupNames := make(map[string]string)
+	seen := make(map[string]bool)
+	var plans []modelAccessPlan
+	for _, spec := range req.Document.Models {
+		mt, err := j.ResolveModel(ctx, user, spec.Model)
+		if err != nil {
+			return apiparams.ApplyModelAccessResponse{}, errors.E(op, err)
+		}
+		if seen[mt.Id()] {
+			return apiparams.ApplyModelAccessResponse{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("model %q specified more than once", spec.Model))
+		}
+		seen[mt.Id()] = true
+		plan := modelAccessPlan{}
+		plan.model.SetTag(mt)
+		if err := j.Database.GetModel(ctx, &plan.model); err != nil {
+			return apiparams.ApplyModelAccessResponse{}, errors.E(op, err)
+		}
+		if err := checkModelAccessAdmin(ctx, user, mt); err != nil {
+			return apiparams.ApplyModelAccessResponse{}, errors.E(op, err)
+		}
+		desired, err := j.desiredModelAccess(ctx, &plan.model, spec, groups)
+		if err != nil {
+			return apiparams.ApplyModelAccessResponse{}, errors.E(op, err)
+		}
+		current, err := j.readModelAccess(ctx, mt, groupNames)
+		if err != nil {
+			return apiparams.ApplyModelAccessResponse{}, errors.E(op, err)
+		}
+		plan.diff(current, desired)
+		if len(plan.changes) > 0 {
+			plans = append(plans, plan)
+		}
+	}
+
+	resp := apiparams.ApplyModelAccessResponse{
+		DryRun: req.DryRun,
+	}
+	for _, plan := range plans {
+		resp.Changes = append(resp.Changes, plan.changes...)
+	}
+	sort.Slice(resp.Changes, func(i, j int) bool {
+		a, b := resp.Changes[i], resp.Changes[j]
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	if req.DryRun {
+		return resp, nil
+	}
+
+	for _, plan := range plans {
+		if len(plan.remove) > 0 {
+			if err := j.OpenFGAClient.RemoveRelation(ctx, plan.remove...); err != nil {
+				return resp, errors.E(op, err)
+			}
+		}
+		if len(plan.add) > 0 {
+			if err := j.OpenFGAClient.AddRelation(ctx, plan.add...); err != nil {
+				return resp, errors.E(op, err)
+			}
+		}
+		j.Cache.InvalidateModelAccess(plan.model.ResourceTag())
+	}
+	return resp, nil
+}
+
+// checkModelAccessAdmin checks that the given user may manage the access
+// to the given model.
+func checkModelAccessAdmin(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	if user.JimmAdmin {
+		return nil
+	}
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return errors.E(err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	return nil
+}
+
+// desiredModelAccess validates the given specification of the access to
+// the given model and returns the access it declares. Groups looked up
+// are cached in the given map, keyed by name.
+func (j *JIMM) desiredModelAccess(ctx context.Context, m *dbmodel.Model, spec apiparams.ModelAccessSpec, groups map[string]*dbmodel.GroupEntry) (map[modelAccessKey]*directModelAccess, error) {
+	desired := make(map[modelAccessKey]*directModelAccess)
+	for name, access := range spec.Users {
+		if name == m.OwnerIdentityName {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("model %q: the access of the model owner cannot be specified", spec.Model))
+		}
+		if !names.IsValidUser(name) {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("model %q: invalid user name %q", spec.Model, name))
+		}
+		relation, err := ToModelRelation(access)
+		if err != nil {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("model %q: invalid access %q for user %q", spec.Model, access, name))
+		}
+		if name != ofganames.EveryoneUser {
+			identity := dbmodel.Identity{Name: name}
+			if err := j.Database.GetIdentity(ctx, &identity); err != nil {
+				return nil, errors.E(err, fmt.Sprintf("model %q: user %q", spec.Model, name))
+			}
+		}
+		desired[modelAccessKey{kind: names.UserTagKind, name: name}] = &directModelAccess{
+			object:    ofganames.ConvertTag(names.NewUserTag(name)),
+			relations: []openfga.Relation{relation},
+		}
+	}
+	for name, access := range spec.Groups {
+		relation, err := ToModelRelation(access)
+		if err != nil {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("model %q: invalid access %q for group %q", spec.Model, access, name))
+		}
+		group, ok := groups[name]
+		if !ok {
+			group = &dbmodel.GroupEntry{Name: name}
+			if err := j.Database.GetGroup(ctx, group); err != nil {
+				return nil, errors.E(err, fmt.Sprintf("model %q: group %q", spec.Model, name))
+			}
+			groups[name] = group
+		}
+		desired[modelAccessKey{kind: jimmnames.GroupTagKind, name: name}] = &directModelAccess{
+			object:    ofganames.ConvertTagWithRelation(group.ResourceTag(), ofganames.MemberRelation),
+			relations: []openfga.Relation{relation},
+		}
+	}
+	return desired, nil
+}
+
+// readModelAccess returns the access users and groups hold directly on
+// the given model. The names of groups looked up are cached in the given
+// map, keyed by UUID.
+func (j *JIMM) readModelAccess(ctx context.Context, mt names.ModelTag, groupNames map[string]string) (map[modelAccessKey]*directModelAccess, error) {
+	key := openfga.Tuple{
+		Target: ofganames.ConvertTag(mt),
+	}
+	access := make(map[modelAccessKey]*directModelAccess)
+	var continuationToken string
+	for {
+		tuples, ct, err := j.OpenFGAClient.ReadRelatedObjects(ctx, key, modelAccessPageSize, continuationToken)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tuples {
+			if !isModelAccessRelation(t.Relation) {
+				continue
+			}
+			var k modelAccessKey
+			switch {
+			case t.Object.Kind == names.UserTagKind && t.Object.Relation == "":
+				k = modelAccessKey{kind: names.UserTagKind, name: t.Object.ID}
+			case t.Object.Kind == jimmnames.GroupTagKind && t.Object.Relation == ofganames.MemberRelation:
+				name, ok := groupNames[t.Object.ID]
+				if !ok {
+					group := dbmodel.GroupEntry{UUID: t.Object.ID}
+					if err := j.Database.GetGroup(ctx, &group); err != nil {
+						return nil, err
+					}
+					name = group.Name
+					groupNames[t.Object.ID] = name
+				}
+				k = modelAccessKey{kind: jimmnames.GroupTagKind, name: name}
+			default:
+				continue
+			}
+			a, ok := access[k]
+			if !ok {
+				// The everyone user is stored as the wildcard user,
+				// which the tuples read have been converted from.
+				object := *t.Object
+				if k.kind == names.UserTagKind && k.name == ofganames.EveryoneUser {
+					object.ID = "*"
+				}
+				a = &directModelAccess{object: &object}
+				access[k] = a
+			}
+			a.relations = append(a.relations, t.Relation)
+		}
+		if ct == "" {
+			return access, nil
+		}
+		continuationToken = ct
+	}
+}
+
+func isModelAccessRelation(r openfga.Relation) bool {
+	for _, mr := range modelAccessRelations {
+		if r == mr {
+			return true
+		}
+	}
+	return false
+}
+
+// diff adds the changes needed to turn the current access to the plan's
+// model into the desired access. The access of the model's owner is left
+// unchanged.
+func (p *modelAccessPlan) diff(current, desired map[modelAccessKey]*directModelAccess) {
+	mt := ofganames.ConvertTag(p.model.ResourceTag())
+	path := p.model.OwnerIdentityName + "/" + p.model.Name
+	check := func(k modelAccessKey) {
+		if k.kind == names.UserTagKind && k.name == p.model.OwnerIdentityName {
+			return
+		}
+		from, to := current[k].access(), desired[k].access()
+		if from == to {
+			return
+		}
+		p.changes = append(p.changes, apiparams.ModelAccessChange{
+			Model: path,
+			Kind:  k.kind,
+			Name:  k.name,
+			From:  from,
+			To:    to,
+		})
+		if c := current[k]; c != nil {
+			for _, r := range c.relations {
+				p.remove = append(p.remove, openfga.Tuple{Object: c.object, Relation: r, Target: mt})
+			}
+		}
+		if d := desired[k]; d != nil {
+			p.add = append(p.add, openfga.Tuple{Object: d.object, Relation: d.relations[0], Target: mt})
+		}
+	}
+	for k := range current {
+		check(k)
+	}
+	for k := range desired {
+		if _, ok := current[k]; !ok {
+			check(k)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestExportAndApplyModelAccess(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// The controllers must not be contacted.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+	_, err = j.AddGroup(ctx, diane, "ops")
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+
+	doc, err := j.ExportModelAccess(ctx, alice, apiparams.ExportModelAccessRequest{
+		ModelTag: "alice@canonical.com/model-1",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(doc, qt.DeepEquals, apiparams.ModelAccessDocument{
+		Models: []apiparams.ModelAccessSpec{{
+			Model: "alice@canonical.com/model-1",
+			Users: map[string]string{
+				"bob@canonical.com":     "write",
+				"charlie@canonical.com": "read",
+			},
+		}},
+	})
+
+	desired := apiparams.ModelAccessDocument{
+		Models: []apiparams.ModelAccessSpec{{
+			Model: "alice@canonical.com/model-1",
+			Users: map[string]string{
+				"bob@canonical.com": "read",
+				"everyone@external": "read",
+			},
+			Groups: map[string]string{
+				"ops": "admin",
+			},
+		}},
+	}
+	expectChanges := []apiparams.ModelAccessChange{{
+		Model: "alice@canonical.com/model-1",
+		Kind:  "group",
+		Name:  "ops",
+		To:    "admin",
+	}, {
+		Model: "alice@canonical.com/model-1",
+		Kind:  "user",
+		Name:  "bob@canonical.com",
+		From:  "write",
+		To:    "read",
+	}, {
+		Model: "alice@canonical.com/model-1",
+		Kind:  "user",
+		Name:  "charlie@canonical.com",
+		From:  "read",
+	}, {
+		Model: "alice@canonical.com/model-1",
+		Kind:  "user",
+		Name:  "everyone@external",
+		To:    "read",
+	}}
+
+	// A dry run reports the plan without changing anything.
+	resp, err := j.ApplyModelAccess(ctx, alice, apiparams.ApplyModelAccessRequest{
+		Document: desired,
+		DryRun:   true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, apiparams.ApplyModelAccessResponse{
+		DryRun:  true,
+		Changes: expectChanges,
+	})
+	unchanged, err := j.ExportModelAccess(ctx, alice, apiparams.ExportModelAccessRequest{
+		ModelTag: "alice@canonical.com/model-1",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(unchanged, qt.DeepEquals, doc)
+
+	resp, err = j.ApplyModelAccess(ctx, alice, apiparams.ApplyModelAccessRequest{
+		Document: desired,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Changes, qt.DeepEquals, expectChanges)
+
+	applied, err := j.ExportModelAccess(ctx, alice, apiparams.ExportModelAccessRequest{
+		Owner: "alice@canonical.com",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(applied, qt.DeepEquals, desired)
+
+	// Applying the document again makes no changes.
+	resp, err = j.ApplyModelAccess(ctx, alice, apiparams.ApplyModelAccessRequest{
+		Document: desired,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Changes, qt.HasLen, 0)
+
+	// bob no longer has administrator access.
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	_, err = j.ApplyModelAccess(ctx, bob, apiparams.ApplyModelAccessRequest{
+		Document: doc,
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ExportModelAccess(ctx, bob, apiparams.ExportModelAccessRequest{
+		Owner: "alice@canonical.com",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestApplyModelAccessInvalidDocument(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+
+	for _, test := range []struct {
+		spec        apiparams.ModelAccessSpec
+		expectError string
+		expectCode  errors.Code
+	}{{
+		spec: apiparams.ModelAccessSpec{
+			Model: "alice@canonical.com/model-1",
+			Users: map[string]string{"bob@canonical.com": "superuser"},
+		},
+		expectError: `model "alice@canonical.com/model-1": invalid access "superuser" for user "bob@canonical.com"`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		spec: apiparams.ModelAccessSpec{
+			Model: "alice@canonical.com/model-1",
+			Users: map[string]string{"alice@canonical.com": "read"},
+		},
+		expectError: `model "alice@canonical.com/model-1": the access of the model owner cannot be specified`,
+		expectCode:  errors.CodeBadRequest,
+	}, {
+		spec: apiparams.ModelAccessSpec{
+			Model:  "alice@canonical.com/model-1",
+			Groups: map[string]string{"no-such-group": "read"},
+		},
+		expectError: `model "alice@canonical.com/model-1": group "no-such-group".*`,
+		expectCode:  errors.CodeNotFound,
+	}, {
+		spec: apiparams.ModelAccessSpec{
+			Model: "alice@canonical.com/no-such-model",
+		},
+		expectError: `.*model not found`,
+		expectCode:  errors.CodeNotFound,
+	}} {
+		_, err := j.ApplyModelAccess(ctx, alice, apiparams.ApplyModelAccessRequest{
+			Document: apiparams.ModelAccessDocument{
+				Models: []apiparams.ModelAccessSpec{test.spec},
+			},
+		})
+		c.Check(err, qt.ErrorMatches, test.expectError)
+		c.Check(errors.ErrorCode(err), qt.Equals, test.expectCode)
+	}
+}
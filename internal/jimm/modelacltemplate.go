@@ -0,0 +1,140 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// AddModelACLTemplate adds a template granting the group named grantGroup
+// the given access to every new model owned by a member of the group
+// named ownerGroup. The groups may be the same. Only JIMM administrators
+// may add templates.
+func (j *JIMM) AddModelACLTemplate(ctx context.Context, user *openfga.User, ownerGroup, grantGroup, access string) (apiparams.ModelACLTemplate, error) {
+	const op = errors.Op("jimm.AddModelACLTemplate")
+
+	if !user.JimmAdmin {
+		return apiparams.ModelACLTemplate{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if _, err := ToModelRelation(access); err != nil {
+		return apiparams.ModelACLTemplate{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model access %q", access))
+	}
+	owner := dbmodel.GroupEntry{
+		Name: ownerGroup,
+	}
+	if err := j.Database.GetGroup(ctx, &owner); err != nil {
+		return apiparams.ModelACLTemplate{}, errors.E(op, err)
+	}
+	grant := dbmodel.GroupEntry{
+		Name: grantGroup,
+	}
+	if err := j.Database.GetGroup(ctx, &grant); err != nil {
+		return apiparams.ModelACLTemplate{}, errors.E(op, err)
+	}
+
+	t := dbmodel.ModelACLTemplate{
+		OwnerGroupID: owner.ID,
+		GrantGroupID: grant.ID,
+		Access:       access,
+	}
+	if err := j.Database.AddModelACLTemplate(ctx, &t); err != nil {
+		return apiparams.ModelACLTemplate{}, errors.E(op, err)
+	}
+	t.OwnerGroup = owner
+	t.GrantGroup = grant
+	return t.ToAPIModelACLTemplate(), nil
+}
+
+// ListModelACLTemplates returns all model ACL templates. Only JIMM
+// administrators may list templates.
+func (j *JIMM) ListModelACLTemplates(ctx context.Context, user *openfga.User) ([]apiparams.ModelACLTemplate, error) {
+	const op = errors.Op("jimm.ListModelACLTemplates")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	templates, err := j.Database.ListModelACLTemplates(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.ModelACLTemplate, len(templates))
+	for i, t := range templates {
+		result[i] = t.ToAPIModelACLTemplate()
+	}
+	return result, nil
+}
+
+// RemoveModelACLTemplate removes the model ACL template with the given ID.
+// Access already granted by the template is unaffected. Only JIMM
+// administrators may remove templates.
+func (j *JIMM) RemoveModelACLTemplate(ctx context.Context, user *openfga.User, id uint) error {
+	const op = errors.Op("jimm.RemoveModelACLTemplate")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	t := dbmodel.ModelACLTemplate{
+		ID: id,
+	}
+	if err := j.Database.GetModelACLTemplate(ctx, &t); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteModelACLTemplate(ctx, &t); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// applyModelACLTemplates grants the access described by the model ACL
+// templates that apply to the given owner to the new model with the given
+// tag. The model has already been created so failures are logged rather
+// than returned.
+func (j *JIMM) applyModelACLTemplates(ctx context.Context, owner *openfga.User, mt names.ModelTag) {
+	templates, err := j.Database.ListModelACLTemplates(ctx)
+	if err != nil {
+		zapctx.Error(ctx, "failed to list model ACL templates", zaputil.Error(err))
+		return
+	}
+	isMember := make(map[uint]bool)
+	for _, t := range templates {
+		member, ok := isMember[t.OwnerGroupID]
+		if !ok {
+			member, err = openfga.CheckRelation(ctx, owner, t.OwnerGroup.ResourceTag(), ofganames.MemberRelation)
+			if err != nil {
+				zapctx.Error(ctx, "failed to check group membership", zap.String("group", t.OwnerGroup.Name), zaputil.Error(err))
+				continue
+			}
+			isMember[t.OwnerGroupID] = member
+		}
+		if !member {
+			continue
+		}
+		relation, err := ToModelRelation(t.Access)
+		if err != nil {
+			zapctx.Error(ctx, "invalid model ACL template", zap.Uint("id", t.ID), zaputil.Error(err))
+			continue
+		}
+		if err := j.OpenFGAClient.SetGroupModelAccess(ctx, t.GrantGroup.ResourceTag(), mt, relation); err != nil {
+			zapctx.Error(
+				ctx,
+				"failed to apply model ACL template",
+				zaputil.Error(err),
+				zap.Uint("id", t.ID),
+				zap.String("targetGroup", t.GrantGroup.Name),
+				zap.String("model", mt.Id()),
+			)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+const modelACLTemplateTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: alice@canonical.com
+    access: add-model
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+- username: charlie@canonical.com
+  controller-access: login
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+`
+
+func TestModelACLTemplates(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+					return nil
+				},
+				CreateModel_: createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:]),
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelACLTemplateTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	admin := openfga.NewUser(&charlieIdentity, client)
+	admin.JimmAdmin = true
+
+	payments, err := j.Database.AddGroup(ctx, "payments")
+	c.Assert(err, qt.IsNil)
+	paymentsAdmins, err := j.Database.AddGroup(ctx, "payments-admins")
+	c.Assert(err, qt.IsNil)
+	for _, m := range []struct {
+		user  *openfga.User
+		group names.Tag
+	}{
+		{alice, payments.ResourceTag()},
+		{bob, payments.ResourceTag()},
+		{charlie, paymentsAdmins.ResourceTag()},
+	} {
+		err = client.AddRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(m.user.ResourceTag()),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertGenericTag(m.group),
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	// Only JIMM administrators may manage templates.
+	_, err = j.AddModelACLTemplate(ctx, alice, "payments", "payments", "read")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.AddModelACLTemplate(ctx, admin, "payments", "payments", "add-model")
+	c.Check(err, qt.ErrorMatches, `invalid model access "add-model"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.AddModelACLTemplate(ctx, admin, "payments", "no-such-group", "read")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	t1, err := j.AddModelACLTemplate(ctx, admin, "payments", "payments", "read")
+	c.Assert(err, qt.IsNil)
+	c.Check(t1.OwnerGroup, qt.Equals, "payments")
+	c.Check(t1.GrantGroup, qt.Equals, "payments")
+	t2, err := j.AddModelACLTemplate(ctx, admin, "payments", "payments-admins", "admin")
+	c.Assert(err, qt.IsNil)
+	_, err = j.AddModelACLTemplate(ctx, admin, "payments", "payments-admins", "write")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	_, err = j.ListModelACLTemplates(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	templates, err := j.ListModelACLTemplates(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(templates, qt.HasLen, 2)
+	c.Check(templates[0].ID, qt.Equals, t1.ID)
+	c.Check(templates[1].GrantGroup, qt.Equals, "payments-admins")
+	c.Check(templates[1].Access, qt.Equals, "admin")
+
+	// New models owned by members of payments are shared according to
+	// the templates.
+	var args jimm.ModelCreateArgs
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+	})
+	c.Assert(err, qt.IsNil)
+	mi, err := j.AddModel(ctx, alice, &args)
+	c.Assert(err, qt.IsNil)
+	mt := names.NewModelTag(mi.UUID)
+
+	access, err := j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "read")
+	access, err = j.GetUserModelAccess(ctx, charlie, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "admin")
+
+	err = j.RemoveModelACLTemplate(ctx, alice, t2.ID)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelACLTemplate(ctx, admin, t2.ID)
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveModelACLTemplate(ctx, admin, t2.ID)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	templates, err = j.ListModelACLTemplates(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(templates, qt.HasLen, 1)
+
+	// Access already granted by a removed template is unaffected.
+	access, err = j.GetUserModelAccess(ctx, charlie, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "admin")
+}
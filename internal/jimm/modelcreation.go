@@ -0,0 +1,87 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// modelCreations tracks the model creations in progress on this JIMM
+// replica so that they may be cancelled. The zero value is ready to use.
+type modelCreations struct {
+	mu        sync.Mutex
+	creations map[string]modelCreation
+}
+
+// A modelCreation is a model creation in progress.
+type modelCreation struct {
+	// userName is the name of the user creating the model.
+	userName string
+
+	cancel context.CancelCauseFunc
+}
+
+// add records a creation of the model with the given path, returning
+// false if the model is already being created.
+func (c *modelCreations) add(path, userName string, cancel context.CancelCauseFunc) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.creations[path]; ok {
+		return false
+	}
+	if c.creations == nil {
+		c.creations = make(map[string]modelCreation)
+	}
+	c.creations[path] = modelCreation{userName: userName, cancel: cancel}
+	return true
+}
+
+// remove removes the creation of the model with the given path, after
+// which it can no longer be cancelled.
+func (c *modelCreations) remove(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.creations, path)
+}
+
+// get returns the creation of the model with the given path.
+func (c *modelCreations) get(path string) (modelCreation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mc, ok := c.creations[path]
+	return mc, ok
+}
+
+// CancelModelCreation cancels the creation of the model with the given
+// <owner>/<name> path that is still in progress. The model owner, the user
+// creating the model and JIMM administrators may cancel a model creation.
+// If the controller has already created the model it is destroyed, and
+// the partially created model is removed from JIMM, releasing it from the
+// owner's quota. The cancelled CreateModel call fails with an error with
+// the code CodeModelCreationCancelled, which is recorded in the audit log.
+// Model creations can only be cancelled on the JIMM replica performing
+// them, if no creation of the model is in progress an error with the code
+// CodeNotFound is returned.
+func (j *JIMM) CancelModelCreation(ctx context.Context, user *openfga.User, path string) error {
+	const op = errors.Op("jimm.CancelModelCreation")
+
+	owner, name, ok := strings.Cut(path, "/")
+	if !ok || !strings.Contains(owner, "@") || name == "" {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model path %q, expected <owner>/<name>", path))
+	}
+	mc, ok := j.modelCreations.get(path)
+	if !ok {
+		return errors.E(op, errors.CodeNotFound, fmt.Sprintf("no creation of model %s in progress", path))
+	}
+	if user.Name != owner && user.Name != mc.userName && !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	mc.cancel(errors.E(errors.CodeModelCreationCancelled, fmt.Sprintf("model creation cancelled by %s", user.Name)))
+	return nil
+}
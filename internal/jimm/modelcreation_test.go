@@ -0,0 +1,170 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const cancelModelCreationTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  users:
+  - user: alice@canonical.com
+    access: add-model
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+`
+
+func TestCancelModelCreation(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	// ignoreCancel is set when the controller completes the model
+	// creation regardless of the request being cancelled.
+	ignoreCancel := false
+	var destroyed []string
+	api := &jimmtest.API{
+		UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, nil
+		},
+		GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+			return nil
+		},
+		CreateModel_: func(ctx context.Context, args *jujuparams.ModelCreateArgs, mi *jujuparams.ModelInfo) error {
+			started <- struct{}{}
+			if !ignoreCancel {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			<-release
+			return createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:])(ctx, args, mi)
+		},
+		DestroyModel_: func(_ context.Context, mt names.ModelTag, _, _ *bool, _, _ *time.Duration) error {
+			destroyed = append(destroyed, mt.Id())
+			return nil
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, cancelModelCreationTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	var args jimm.ModelCreateArgs
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:               "test-model",
+		OwnerTag:           names.NewUserTag("alice@canonical.com").String(),
+		CloudTag:           names.NewCloudTag("test-cloud").String(),
+		CloudRegion:        "test-region-1",
+		CloudCredentialTag: names.NewCloudCredentialTag("test-cloud/alice@canonical.com/test-credential-1").String(),
+	})
+	c.Assert(err, qt.IsNil)
+
+	err = j.CancelModelCreation(ctx, alice, "alice@canonical.com/test-model")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = j.CancelModelCreation(ctx, alice, "test-model")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	// Cancel while the controller is creating the model.
+	errc := make(chan error, 1)
+	go func() {
+		_, err := j.AddModel(ctx, alice, &args)
+		errc <- err
+	}()
+	<-started
+	err = j.CancelModelCreation(ctx, bob, "alice@canonical.com/test-model")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.CancelModelCreation(ctx, alice, "alice@canonical.com/test-model")
+	c.Assert(err, qt.IsNil)
+	err = <-errc
+	c.Check(err, qt.ErrorMatches, "model creation cancelled by alice@canonical.com")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeModelCreationCancelled)
+	c.Check(destroyed, qt.HasLen, 0)
+
+	m := dbmodel.Model{OwnerIdentityName: "alice@canonical.com", Name: "test-model"}
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Cancel after the controller has created the model.
+	ignoreCancel = true
+	go func() {
+		_, err := j.AddModel(ctx, alice, &args)
+		errc <- err
+	}()
+	<-started
+	err = j.CancelModelCreation(ctx, alice, "alice@canonical.com/test-model")
+	c.Assert(err, qt.IsNil)
+	close(release)
+	err = <-errc
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeModelCreationCancelled)
+	c.Check(destroyed, qt.DeepEquals, []string{"00000001-0000-0000-0000-0000-000000000001"})
+
+	err = j.Database.GetModel(ctx, &m)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.CancelModelCreation(ctx, alice, "alice@canonical.com/test-model")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// AddModelDependency declares that the model with the given tag depends
+// on the model with the tag dependsOn, for example because it consumes
+// an offer from that model. Declaring an existing dependency replaces
+// its description. The user must be an administrator of the dependent
+// model and have read access to the model depended on. A dependency
+// that would make a model depend on itself, directly or through other
+// models, is rejected with an error with the code CodeBadRequest.
+func (j *JIMM) AddModelDependency(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag, description string) (apiparams.ModelDependency, error) {
+	const op = errors.Op("jimm.AddModelDependency")
+
+	if mt == dependsOn {
+		return apiparams.ModelDependency{}, errors.E(op, errors.CodeBadRequest, "a model cannot depend on itself")
+	}
+	m, err := j.modelWithAccess(ctx, user, mt, "admin")
+	if err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	d, err := j.modelWithAccess(ctx, user, dependsOn, "read")
+	if err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	dependents, err := j.dependentModels(ctx, m, true)
+	if err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	for _, dm := range dependents {
+		if dm.ID == d.ID {
+			return apiparams.ModelDependency{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("%s already depends on %s", modelName(d), modelName(m)))
+		}
+	}
+
+	dep := dbmodel.ModelDependency{
+		ModelID:     m.ID,
+		DependsOnID: d.ID,
+		CreatedBy:   user.Name,
+		Description: description,
+	}
+	if err := j.Database.AddModelDependency(ctx, &dep); err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	dep.Model = *m
+	dep.DependsOn = *d
+	zapctx.Info(ctx, "model dependency added", zap.String("model", mt.Id()), zap.String("depends-on", dependsOn.Id()), zap.String("user", user.Name))
+	return dep.ToAPIModelDependency(), nil
+}
+
+// RemoveModelDependency removes the dependency of the model with the
+// given tag on the model with the tag dependsOn. The user must be an
+// administrator of the dependent model. If there is no such dependency
+// an error with the code CodeNotFound is returned.
+func (j *JIMM) RemoveModelDependency(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag) error {
+	const op = errors.Op("jimm.RemoveModelDependency")
+
+	m, err := j.modelWithAccess(ctx, user, mt, "admin")
+	if err != nil {
+		return errors.E(op, err)
+	}
+	d := dbmodel.Model{}
+	d.SetTag(dependsOn)
+	if err := j.Database.GetModel(ctx, &d); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.RemoveModelDependency(ctx, &dbmodel.ModelDependency{ModelID: m.ID, DependsOnID: d.ID}); err != nil {
+		return errors.E(op, err)
+	}
+	zapctx.Info(ctx, "model dependency removed", zap.String("model", mt.Id()), zap.String("depends-on", dependsOn.Id()), zap.String("user", user.Name))
+	return nil
+}
+
+// ModelDependencies returns the dependencies declared by the model with
+// the given tag, the dependencies other models have declared on it and
+// every model that would be affected by destroying it. The user must
+// have read access to the model.
+func (j *JIMM) ModelDependencies(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelDependencies, error) {
+	const op = errors.Op("jimm.ModelDependencies")
+
+	m, err := j.modelWithAccess(ctx, user, mt, "read")
+	if err != nil {
+		return apiparams.ModelDependencies{}, errors.E(op, err)
+	}
+	deps := apiparams.ModelDependencies{
+		ModelTag:   mt.String(),
+		DependsOn:  []apiparams.ModelDependency{},
+		Dependents: []apiparams.ModelDependency{},
+	}
+	dependsOn, err := j.Database.ListModelDependencies(ctx, m.ID)
+	if err != nil {
+		return apiparams.ModelDependencies{}, errors.E(op, err)
+	}
+	for _, d := range dependsOn {
+		deps.DependsOn = append(deps.DependsOn, d.ToAPIModelDependency())
+	}
+	dependents, err := j.Database.ListModelDependents(ctx, m.ID)
+	if err != nil {
+		return apiparams.ModelDependencies{}, errors.E(op, err)
+	}
+	for _, d := range dependents {
+		deps.Dependents = append(deps.Dependents, d.ToAPIModelDependency())
+	}
+	affected, err := j.dependentModels(ctx, m, false)
+	if err != nil {
+		return apiparams.ModelDependencies{}, errors.E(op, err)
+	}
+	deps.AffectedModels = modelNames(affected)
+	return deps, nil
+}
+
+// checkModelDependents returns an error with the code
+// CodeModelHasDependents if live models depend on the given model and
+// the destroy is not forced. Forced destroys of a model with dependents
+// are logged.
+func (j *JIMM) checkModelDependents(ctx context.Context, m *dbmodel.Model, force *bool) error {
+	dependents, err := j.dependentModels(ctx, m, false)
+	if err != nil {
+		return err
+	}
+	if len(dependents) == 0 {
+		return nil
+	}
+	dependentNames := modelNames(dependents)
+	if force != nil && *force {
+		zapctx.Warn(ctx, "destroying model with dependent models", zap.String("model", m.UUID.String), zap.Strings("dependents", dependentNames))
+		return nil
+	}
+	return errors.E(errors.CodeModelHasDependents, fmt.Sprintf("models depend on %s: %s, destroy them first or use force", modelName(m), strings.Join(dependentNames, ", ")))
+}
+
+// dependentModels returns the models that depend on the given model
+// either directly or through other models. Unless all is true models
+// that are being destroyed are not returned, although the models that
+// depend on them are.
+func (j *JIMM) dependentModels(ctx context.Context, m *dbmodel.Model, all bool) ([]dbmodel.Model, error) {
+	seen := map[uint]bool{m.ID: true}
+	queue := []uint{m.ID}
+	var models []dbmodel.Model
+	for len(queue) > 0 {
+		deps, err := j.Database.ListModelDependents(ctx, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		for _, d := range deps {
+			if seen[d.ModelID] {
+				continue
+			}
+			seen[d.ModelID] = true
+			queue = append(queue, d.ModelID)
+			if all || d.Model.Life == "" || d.Model.Life == state.Alive.String() {
+				models = append(models, d.Model)
+			}
+		}
+	}
+	return models, nil
+}
+
+// modelWithAccess returns the model with the given tag if the user has
+// at least the given access to it.
+func (j *JIMM) modelWithAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, access string) (*dbmodel.Model, error) {
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return nil, err
+	}
+	if !allowedModelAccess[access][j.getModelAccess(ctx, user, mt)] {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	return &m, nil
+}
+
+// modelName returns the name of the given model in the form
+// <owner>/<name>.
+func modelName(m *dbmodel.Model) string {
+	return m.OwnerIdentityName + "/" + m.Name
+}
+
+// modelNames returns the sorted names of the given models in the form
+// <owner>/<name>.
+func modelNames(models []dbmodel.Model) []string {
+	var ns []string
+	for i := range models {
+		ns = append(ns, modelName(&models[i]))
+	}
+	sort.Strings(ns)
+	return ns
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const modelDependencyTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: shared-db
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: read
+- name: app
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: write
+- name: frontend
+  uuid: 00000002-0000-0000-0000-000000000003
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+`
+
+func TestModelDependencies(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var destroyed int
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				DestroyModel_: func(context.Context, names.ModelTag, *bool, *bool, *time.Duration, *time.Duration) error {
+					destroyed++
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelDependencyTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	sharedDB := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	app := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+	frontend := names.NewModelTag("00000002-0000-0000-0000-000000000003")
+
+	// Only administrators of the dependent model may declare its
+	// dependencies.
+	_, err = j.AddModelDependency(ctx, bob, app, sharedDB, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.AddModelDependency(ctx, alice, sharedDB, sharedDB, "")
+	c.Check(err, qt.ErrorMatches, `a model cannot depend on itself`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	dep, err := j.AddModelDependency(ctx, alice, app, sharedDB, "consumes the postgresql offer")
+	c.Assert(err, qt.IsNil)
+	c.Check(dep.ModelTag, qt.Equals, app.String())
+	c.Check(dep.ModelName, qt.Equals, "alice@canonical.com/app")
+	c.Check(dep.DependsOnModelTag, qt.Equals, sharedDB.String())
+	c.Check(dep.DependsOnModelName, qt.Equals, "alice@canonical.com/shared-db")
+	c.Check(dep.Description, qt.Equals, "consumes the postgresql offer")
+	c.Check(dep.CreatedBy, qt.Equals, "alice@canonical.com")
+	_, err = j.AddModelDependency(ctx, alice, frontend, app, "")
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.AddModelDependency(ctx, alice, sharedDB, frontend, "")
+	c.Check(err, qt.ErrorMatches, `alice@canonical.com/frontend already depends on alice@canonical.com/shared-db`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	deps, err := j.ModelDependencies(ctx, bob, sharedDB)
+	c.Assert(err, qt.IsNil)
+	c.Check(deps.ModelTag, qt.Equals, sharedDB.String())
+	c.Check(deps.DependsOn, qt.HasLen, 0)
+	c.Assert(deps.Dependents, qt.HasLen, 1)
+	c.Check(deps.Dependents[0].ModelName, qt.Equals, "alice@canonical.com/app")
+	c.Check(deps.AffectedModels, qt.DeepEquals, []string{"alice@canonical.com/app", "alice@canonical.com/frontend"})
+	_, err = j.ModelDependencies(ctx, bob, frontend)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// A model that other models depend on is only destroyed with force.
+	err = j.DestroyModel(ctx, alice, sharedDB, nil, nil, nil, nil)
+	c.Check(err, qt.ErrorMatches, `models depend on alice@canonical.com/shared-db: alice@canonical.com/app, alice@canonical.com/frontend, destroy them first or use force`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeModelHasDependents)
+	c.Check(destroyed, qt.Equals, 0)
+
+	err = j.RemoveModelDependency(ctx, bob, app, sharedDB)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelDependency(ctx, alice, frontend, app)
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveModelDependency(ctx, alice, frontend, app)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.DestroyModel(ctx, alice, frontend, nil, nil, nil, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.Equals, 1)
+
+	force := true
+	err = j.DestroyModel(ctx, alice, sharedDB, nil, &force, nil, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.Equals, 2)
+}
@@ -0,0 +1,291 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// Moving a model to another JIMM deployment is a handshake between the
+// two JIMMs:
+//
+//  1. ExportModel, on the source JIMM, exports the model's JIMM metadata.
+//  2. PrepareModelImport, on the target JIMM, translates the export into
+//     the target's users and groups and records the pending import. The
+//     import holds the details of the target controller.
+//  3. MigrateModelExternal, on the source JIMM, migrates the model to the
+//     target controller. Once the model has left the source controller
+//     the source JIMM's watcher removes it.
+//  4. CompleteModelImport, on the target JIMM, adds the migrated model and
+//     grants the exported access to it.
+//
+// The model's cloud credential moves with the Juju migration.
+
+// ExportModel exports the JIMM metadata of the given model, which may be
+// specified by tag, UUID or <owner>/<name> path, so that the model can be
+// imported by another JIMM deployment. Exporting a model requires
+// administrator access to it.
+func (j *JIMM) ExportModel(ctx context.Context, user *openfga.User, modelRef string) (apiparams.ModelExport, error) {
+	const op = errors.Op("jimm.ExportModel")
+
+	mt, err := j.ResolveModel(ctx, user, modelRef)
+	if err != nil {
+		return apiparams.ModelExport{}, errors.E(op, err)
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelExport{}, errors.E(op, err)
+	}
+	if err := checkModelAccessAdmin(ctx, user, mt); err != nil {
+		return apiparams.ModelExport{}, errors.E(op, err)
+	}
+	access, err := j.readModelAccess(ctx, mt, make(map[string]string))
+	if err != nil {
+		return apiparams.ModelExport{}, errors.E(op, err)
+	}
+	return apiparams.ModelExport{
+		SourceJIMM:      j.ResourceTag().String(),
+		ModelTag:        mt.String(),
+		Name:            m.Name,
+		Owner:           m.OwnerIdentityName,
+		Cloud:           m.CloudRegion.CloudName,
+		CloudRegion:     m.CloudRegion.Name,
+		CloudCredential: m.CloudCredential.Name,
+		Access:          modelAccessSpec(&m, access),
+		ExportedAt:      time.Now().UTC(),
+	}, nil
+}
+
+// PrepareModelImport prepares the import of a model exported from another
+// JIMM deployment to the given controller. The names of the model's owner,
+// users and groups are translated using the given maps. The groups must
+// already exist in this JIMM and the owner must hold a credential for the
+// model's cloud. The returned import holds the details the source
+// JIMM needs to migrate the model to the controller, including the
+// controller's admin credentials. Only JIMM administrators can prepare
+// model imports.
+func (j *JIMM) PrepareModelImport(ctx context.Context, user *openfga.User, req apiparams.PrepareModelImportRequest) (apiparams.ModelImport, error) {
+	const op = errors.Op("jimm.PrepareModelImport")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	exp := req.Export
+	mt, err := names.ParseModelTag(exp.ModelTag)
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+	if exp.SourceJIMM == j.ResourceTag().String() {
+		return apiparams.ModelImport{}, errors.E(op, errors.CodeBadRequest, "cannot import a model exported from this JIMM")
+	}
+	var existing dbmodel.Model
+	existing.SetTag(mt)
+	err = j.Database.GetModel(ctx, &existing)
+	if err == nil {
+		return apiparams.ModelImport{}, errors.E(op, errors.CodeAlreadyExists, fmt.Sprintf("model %s already exists", mt.Id()))
+	}
+	if errors.ErrorCode(err) != errors.CodeNotFound {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+
+	controller, err := j.getControllerByName(ctx, req.Controller)
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	if err := j.checkImportCloudRegion(ctx, controller, exp.Cloud, exp.CloudRegion); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	if _, err := j.checkMigrationCapacity(ctx, controller); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+
+	owner := dbmodel.Identity{Name: translateName(req.Identities, exp.Owner)}
+	if err := j.Database.GetIdentity(ctx, &owner); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err, fmt.Sprintf("owner %q", owner.Name))
+	}
+	// The model is added with one of the owner's credentials for its
+	// cloud, the model keeps using the credential moved with it.
+	creds, err := j.Database.GetIdentityCloudCredentials(ctx, &owner, exp.Cloud)
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	if len(creds) == 0 {
+		return apiparams.ModelImport{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("owner %q has no cloud credential for cloud %q", owner.Name, exp.Cloud))
+	}
+
+	m := dbmodel.Model{
+		Name:              exp.Name,
+		OwnerIdentityName: owner.Name,
+	}
+	spec := apiparams.ModelAccessSpec{
+		Model: owner.Name + "/" + exp.Name,
+	}
+	for name, access := range exp.Access.Users {
+		if spec.Users == nil {
+			spec.Users = make(map[string]string)
+		}
+		spec.Users[translateName(req.Identities, name)] = access
+	}
+	for name, access := range exp.Access.Groups {
+		if spec.Groups == nil {
+			spec.Groups = make(map[string]string)
+		}
+		spec.Groups[translateName(req.Groups, name)] = access
+	}
+	if _, err := j.desiredModelAccess(ctx, &m, spec, make(map[string]*dbmodel.GroupEntry)); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+
+	targetInfo, _, err := fillMigrationTarget(j.Database, j.CredentialStore, controller.Name)
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+
+	mi := dbmodel.ModelImport{
+		ModelUUID:         mt.Id(),
+		ModelName:         exp.Name,
+		SourceJIMM:        exp.SourceJIMM,
+		ControllerID:      controller.ID,
+		Controller:        *controller,
+		OwnerIdentityName: owner.Name,
+		UserAccess:        spec.Users,
+		GroupAccess:       spec.Groups,
+		Status:            dbmodel.ModelImportPrepared,
+		PreparedBy:        user.Name,
+	}
+	if err := j.Database.UpsertModelImport(ctx, &mi); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	zapctx.Info(ctx, "model import prepared", zap.String("model", mt.Id()), zap.String("source", exp.SourceJIMM), zap.String("controller", controller.Name))
+
+	resp := mi.ToAPIModelImport()
+	resp.TargetInfo = &targetInfo
+	return resp, nil
+}
+
+// checkImportCloudRegion checks that the given controller hosts the given
+// cloud region, the name of which may be an alias of a remapped region.
+func (j *JIMM) checkImportCloudRegion(ctx context.Context, ctl *dbmodel.Controller, cloudName, regionName string) error {
+	cloud := dbmodel.Cloud{Name: cloudName}
+	if err := j.Database.GetCloud(ctx, &cloud); err != nil {
+		return err
+	}
+	regionName, err := j.cloudRegionName(ctx, &cloud, regionName)
+	if err != nil {
+		return err
+	}
+	region := cloud.Region(regionName)
+	for _, crp := range ctl.CloudRegions {
+		if region.ID != 0 && crp.CloudRegionID == region.ID {
+			return nil
+		}
+	}
+	return errors.E(errors.CodeBadRequest, fmt.Sprintf("controller %s does not host cloud-region %s/%s", ctl.Name, cloudName, regionName))
+}
+
+// translateName returns the name the given name is mapped to, or the
+// name itself if it is not mapped.
+func translateName(m map[string]string, name string) string {
+	if n, ok := m[name]; ok && n != "" {
+		return n
+	}
+	return name
+}
+
+// MigrateModelExternal migrates the given model to a controller managed
+// by another JIMM deployment, using the import prepared by that JIMM.
+// Migrating a model requires administrator access to it. Once the model
+// has left its controller it is removed from this JIMM.
+func (j *JIMM) MigrateModelExternal(ctx context.Context, user *openfga.User, req apiparams.MigrateModelExternalRequest) (apiparams.MigrateModelExternalResponse, error) {
+	const op = errors.Op("jimm.MigrateModelExternal")
+
+	mt, err := j.ResolveModel(ctx, user, req.ModelTag)
+	if err != nil {
+		return apiparams.MigrateModelExternalResponse{}, errors.E(op, err)
+	}
+	if req.Import.ModelTag != mt.String() {
+		return apiparams.MigrateModelExternalResponse{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("import is for model %s, not %s", req.Import.ModelTag, mt.String()))
+	}
+	if req.Import.TargetInfo == nil {
+		return apiparams.MigrateModelExternalResponse{}, errors.E(op, errors.CodeBadRequest, "import has no migration target")
+	}
+
+	result, err := j.InitiateMigration(ctx, user, jujuparams.MigrationSpec{
+		ModelTag:   mt.String(),
+		TargetInfo: *req.Import.TargetInfo,
+	})
+	if err != nil {
+		return apiparams.MigrateModelExternalResponse{}, errors.E(op, err)
+	}
+	zapctx.Info(ctx, "external model migration started", zap.String("model", mt.Id()), zap.String("controller", req.Import.Controller), zap.String("migration-id", result.MigrationId))
+	return apiparams.MigrateModelExternalResponse{
+		ModelTag:    mt.String(),
+		MigrationID: result.MigrationId,
+	}, nil
+}
+
+// CompleteModelImport completes the given model import once the model's
+// migration to the import's controller has finished. The model is added
+// to JIMM, owned by the import's owner, and the import's users and groups
+// are granted their access to it. Only JIMM administrators can complete
+// model imports.
+func (j *JIMM) CompleteModelImport(ctx context.Context, user *openfga.User, id uint) (apiparams.ModelImport, error) {
+	const op = errors.Op("jimm.CompleteModelImport")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	if id == 0 {
+		return apiparams.ModelImport{}, errors.E(op, errors.CodeBadRequest, "missing model import ID")
+	}
+	mi := dbmodel.ModelImport{ID: id}
+	if err := j.Database.GetModelImport(ctx, &mi); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	if mi.Status == dbmodel.ModelImportCompleted {
+		return mi.ToAPIModelImport(), nil
+	}
+
+	mt := names.NewModelTag(mi.ModelUUID)
+	// A previous attempt may have added the model but failed to grant
+	// the access to it.
+	err := j.ImportModel(ctx, user, mi.Controller.Name, mt, mi.OwnerIdentityName, dbmodel.ModelOriginMigrated)
+	if err != nil && errors.ErrorCode(err) != errors.CodeAlreadyExists {
+		return apiparams.ModelImport{}, errors.E(op, err, fmt.Sprintf("cannot import model %s from controller %s, has its migration finished?", mt.Id(), mi.Controller.Name))
+	}
+
+	_, err = j.ApplyModelAccess(ctx, user, apiparams.ApplyModelAccessRequest{
+		Document: apiparams.ModelAccessDocument{
+			Models: []apiparams.ModelAccessSpec{{
+				Model:  mt.Id(),
+				Users:  mi.UserAccess,
+				Groups: mi.GroupAccess,
+			}},
+		},
+	})
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+
+	mi.Status = dbmodel.ModelImportCompleted
+	mi.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := j.Database.UpdateModelImport(ctx, &mi); err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	zapctx.Info(ctx, "model import completed", zap.String("model", mt.Id()), zap.String("controller", mi.Controller.Name))
+	return mi.ToAPIModelImport(), nil
+}
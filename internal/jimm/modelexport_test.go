@@ -0,0 +1,200 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const modelExportTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+- owner: eve@canonical.com
+  name: cred-2
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+  admin-user: admin
+  admin-password: password
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-cloud-region
+    priority: 1
+models:
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: write
+users:
+- username: diane@canonical.com
+  controller-access: superuser
+`
+
+func TestModelExportAndImport(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// Neither exporting nor preparing an import contacts the controllers.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelExportTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+	_, err = j.AddGroup(ctx, diane, "ops")
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	_, err = j.ExportModel(ctx, bob, "alice@canonical.com/model-1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	exp, err := j.ExportModel(ctx, alice, "alice@canonical.com/model-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(exp.ExportedAt.IsZero(), qt.IsFalse)
+	c.Check(exp, qt.DeepEquals, apiparams.ModelExport{
+		SourceJIMM:      j.ResourceTag().String(),
+		ModelTag:        names.NewModelTag("00000002-0000-0000-0000-000000000001").String(),
+		Name:            "model-1",
+		Owner:           "alice@canonical.com",
+		Cloud:           "test-cloud",
+		CloudRegion:     "test-cloud-region",
+		CloudCredential: "cred-1",
+		Access: apiparams.ModelAccessSpec{
+			Model: "alice@canonical.com/model-1",
+			Users: map[string]string{
+				"bob@canonical.com": "write",
+			},
+		},
+		ExportedAt: exp.ExportedAt,
+	})
+
+	// The export is imported as if it came from another JIMM, which
+	// names its users and groups differently.
+	exp.SourceJIMM = names.NewControllerTag(uuid.NewString()).String()
+	exp.Access.Groups = map[string]string{"operators": "admin"}
+	req := apiparams.PrepareModelImportRequest{
+		Export:     exp,
+		Controller: "controller-1",
+		Identities: map[string]string{
+			"alice@canonical.com": "eve@canonical.com",
+		},
+		Groups: map[string]string{
+			"operators": "ops",
+		},
+	}
+
+	_, err = j.PrepareModelImport(ctx, alice, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// The model is still managed by this JIMM.
+	_, err = j.PrepareModelImport(ctx, diane, req)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	req.Export.ModelTag = names.NewModelTag("00000002-0000-0000-0000-000000000002").String()
+	badReq := req
+	badReq.Groups = nil
+	_, err = j.PrepareModelImport(ctx, diane, badReq)
+	c.Check(err, qt.ErrorMatches, `model "eve@canonical.com/model-1": group "operators".*`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	badReq = req
+	badReq.Identities = map[string]string{"alice@canonical.com": "frank@canonical.com"}
+	_, err = j.PrepareModelImport(ctx, diane, badReq)
+	c.Check(err, qt.ErrorMatches, `owner "frank@canonical.com" has no cloud credential for cloud "test-cloud"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	badReq = req
+	badReq.Export.CloudRegion = "no-such-region"
+	_, err = j.PrepareModelImport(ctx, diane, badReq)
+	c.Check(err, qt.ErrorMatches, `controller controller-1 does not host cloud-region test-cloud/no-such-region`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	mi, err := j.PrepareModelImport(ctx, diane, req)
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.ID, qt.Not(qt.Equals), uint(0))
+	c.Check(mi.ModelTag, qt.Equals, req.Export.ModelTag)
+	c.Check(mi.SourceJIMM, qt.Equals, exp.SourceJIMM)
+	c.Check(mi.Controller, qt.Equals, "controller-1")
+	c.Check(mi.Owner, qt.Equals, "eve@canonical.com")
+	c.Check(mi.Status, qt.Equals, dbmodel.ModelImportPrepared)
+	c.Check(mi.Access, qt.DeepEquals, apiparams.ModelAccessSpec{
+		Model:  "eve@canonical.com/model-1",
+		Users:  map[string]string{"bob@canonical.com": "write"},
+		Groups: map[string]string{"ops": "admin"},
+	})
+	c.Assert(mi.TargetInfo, qt.Not(qt.IsNil))
+	c.Check(mi.TargetInfo.ControllerTag, qt.Equals, names.NewControllerTag("00000001-0000-0000-0000-000000000001").String())
+	c.Check(mi.TargetInfo.AuthTag, qt.Equals, names.NewUserTag("admin").String())
+	c.Check(mi.TargetInfo.Password, qt.Equals, "password")
+
+	stored := dbmodel.ModelImport{ID: mi.ID}
+	err = j.Database.GetModelImport(ctx, &stored)
+	c.Assert(err, qt.IsNil)
+	c.Check(stored.PreparedBy, qt.Equals, "diane@canonical.com")
+
+	// The ticket must be for the model being migrated.
+	_, err = j.MigrateModelExternal(ctx, alice, apiparams.MigrateModelExternalRequest{
+		ModelTag: "alice@canonical.com/model-1",
+		Import:   mi,
+	})
+	c.Check(err, qt.ErrorMatches, `import is for model model-00000002-0000-0000-0000-000000000002, not model-00000002-0000-0000-0000-000000000001`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	_, err = j.CompleteModelImport(ctx, alice, mi.ID)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	// The model has not been migrated to the controller.
+	_, err = j.CompleteModelImport(ctx, diane, mi.ID)
+	c.Check(err, qt.ErrorMatches, `cannot import model 00000002-0000-0000-0000-000000000002 from controller controller-1, has its migration finished\?`)
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// FreezeModel freezes the model with the given tag. Mutating calls made to
+// a frozen model are rejected with an error with the code CodeModelFrozen,
+// calls that only read the model are allowed. If until is not zero the
+// freeze expires at that time. Freezing a frozen model replaces the
+// existing freeze. Only JIMM administrators may freeze models.
+func (j *JIMM) FreezeModel(ctx context.Context, user *openfga.User, mt names.ModelTag, until time.Time, reason string) (apiparams.ModelFreeze, error) {
+	const op = errors.Op("jimm.FreezeModel")
+
+	if !user.JimmAdmin {
+		return apiparams.ModelFreeze{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if !until.IsZero() && !until.After(time.Now()) {
+		return apiparams.ModelFreeze{}, errors.E(op, errors.CodeBadRequest, "freeze expiry must be in the future")
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelFreeze{}, errors.E(op, err)
+	}
+	f := dbmodel.ModelFreeze{
+		ModelID:  m.ID,
+		FrozenBy: user.Name,
+		Reason:   reason,
+		Until: sql.NullTime{
+			Time:  until,
+			Valid: !until.IsZero(),
+		},
+	}
+	if err := j.Database.SetModelFreeze(ctx, &f); err != nil {
+		return apiparams.ModelFreeze{}, errors.E(op, err)
+	}
+	f.Model = m
+	return f.ToAPIModelFreeze(), nil
+}
+
+// UnfreezeModel removes any freeze on the model with the given tag. Only
+// JIMM administrators may unfreeze models.
+func (j *JIMM) UnfreezeModel(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	const op = errors.Op("jimm.UnfreezeModel")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteModelFreeze(ctx, &dbmodel.ModelFreeze{ModelID: m.ID}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// CheckModelFrozen returns an error with the code CodeModelFrozen if the
+// model with the given ID is frozen and the given facade method may
+// modify the model.
+func (j *JIMM) CheckModelFrozen(ctx context.Context, modelID uint, facade, method string) error {
+	const op = errors.Op("jimm.CheckModelFrozen")
+
+	if IsReadOnlyCall(facade, method) {
+		return nil
+	}
+	f := dbmodel.ModelFreeze{
+		ModelID: modelID,
+	}
+	if err := j.Database.GetModelFreeze(ctx, &f); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil
+		}
+		return errors.E(op, err)
+	}
+	if !f.Active(time.Now()) {
+		return nil
+	}
+	msg := fmt.Sprintf("model is frozen by %s", f.FrozenBy)
+	if f.Until.Valid {
+		msg += fmt.Sprintf(" until %s", f.Until.Time.UTC().Format(time.RFC3339))
+	}
+	if f.Reason != "" {
+		msg += ": " + f.Reason
+	}
+	return errors.E(op, errors.CodeModelFrozen, fmt.Sprintf("%s.%s not allowed, %s", facade, method, msg))
+}
+
+// readOnlyMethodPrefixes holds the prefixes of facade methods that only
+// read a model.
+var readOnlyMethodPrefixes = []string{
+	"Get",
+	"List",
+	"Show",
+	"Find",
+	"Watch",
+}
+
+// readOnlyMethods holds the names of other facade methods that only read
+// a model.
+var readOnlyMethods = map[string]bool{
+	"Actions":          true,
+	"ApplicationsInfo": true,
+	"CharmConfig":      true,
+	"CharmInfo":        true,
+	"FullStatus":       true,
+	"ModelGet":         true,
+	"ModelInfo":        true,
+	"ModelUserInfo":    true,
+	"Operations":       true,
+	"Ping":             true,
+	"Status":           true,
+	"StatusHistory":    true,
+}
+
+// IsReadOnlyCall returns whether a call to the given facade method only
+// reads the model it is made on. Calls that are not known to be read only
+// are assumed to modify the model.
+func IsReadOnlyCall(facade, method string) bool {
+	// Watchers are created by read only calls, and only read
+	// subsequently.
+	if strings.HasSuffix(facade, "Watcher") || facade == "Pinger" {
+		return true
+	}
+	if readOnlyMethods[method] {
+		return true
+	}
+	for _, p := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(method, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestModelFreeze(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelTokenTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	m := dbmodel.Model{}
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	// Only JIMM administrators may freeze models, even model
+	// administrators may not.
+	_, err = j.FreezeModel(ctx, alice, mt, time.Time{}, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.FreezeModel(ctx, admin, mt, time.Now().Add(-time.Hour), "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.FreezeModel(ctx, admin, names.NewModelTag("00000002-0000-0000-0000-000000000009"), time.Time{}, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	c.Check(j.CheckModelFrozen(ctx, m.ID, "Application", "Deploy"), qt.IsNil)
+
+	f, err := j.FreezeModel(ctx, admin, mt, time.Time{}, "incident 42")
+	c.Assert(err, qt.IsNil)
+	c.Check(f.ModelTag, qt.Equals, mt.String())
+	c.Check(f.FrozenBy, qt.Equals, "admin@canonical.com")
+	c.Check(f.Reason, qt.Equals, "incident 42")
+	c.Check(f.Until, qt.IsNil)
+
+	err = j.CheckModelFrozen(ctx, m.ID, "Application", "Deploy")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeModelFrozen)
+	c.Check(err, qt.ErrorMatches, `Application.Deploy not allowed, model is frozen by admin@canonical.com: incident 42`)
+	c.Check(j.CheckModelFrozen(ctx, m.ID, "Client", "FullStatus"), qt.IsNil)
+	err = j.DestroyModel(ctx, alice, mt, nil, nil, nil, nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeModelFrozen)
+
+	// A time-boxed freeze replaces the existing freeze.
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	f, err = j.FreezeModel(ctx, admin, mt, until, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(f.Until, qt.Not(qt.IsNil))
+	c.Check(f.Until.Equal(until), qt.IsTrue)
+	err = j.CheckModelFrozen(ctx, m.ID, "ModelConfig", "ModelSet")
+	c.Check(err, qt.ErrorMatches, `ModelConfig.ModelSet not allowed, model is frozen by admin@canonical.com until .*`)
+
+	err = j.UnfreezeModel(ctx, alice, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.UnfreezeModel(ctx, admin, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(j.CheckModelFrozen(ctx, m.ID, "ModelConfig", "ModelSet"), qt.IsNil)
+}
+
+func TestIsReadOnlyCall(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		facade, method string
+		expect         bool
+	}{
+		{"Client", "FullStatus", true},
+		{"Application", "Get", true},
+		{"Application", "Deploy", false},
+		{"Application", "SetConfigs", false},
+		{"ModelConfig", "ModelGet", true},
+		{"ModelConfig", "ModelSet", false},
+		{"Client", "WatchAll", true},
+		{"AllWatcher", "Next", true},
+		{"AllWatcher", "Stop", true},
+		{"Pinger", "Ping", true},
+		{"Action", "EnqueueOperation", false},
+		{"Unknown", "Method", false},
+	}
+	for _, test := range tests {
+		c.Check(jimm.IsReadOnlyCall(test.facade, test.method), qt.Equals, test.expect, qt.Commentf("%s.%s", test.facade, test.method))
+	}
+}
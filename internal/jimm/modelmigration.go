@@ -0,0 +1,233 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/utils"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// DefaultMigrationTimeout is the time after which a model migration whose
+// outcome cannot be determined is abandoned if no MigrationTimeout is
+// configured.
+const DefaultMigrationTimeout = 24 * time.Hour
+
+// trackModelMigration records that the migration with the given ID of the
+// given model to the target controller has been initiated, so that the
+// migration is monitored by MonitorModelMigrations. The migration is
+// already running on the controller, so failing to record it is logged
+// rather than returned.
+func (j *JIMM) trackModelMigration(ctx context.Context, user *openfga.User, m *dbmodel.Model, target *dbmodel.Controller, migrationID string) {
+	mm := dbmodel.ModelMigration{
+		ModelID:            m.ID,
+		Model:              *m,
+		SourceControllerID: m.ControllerID,
+		SourceController:   m.Controller,
+		TargetControllerID: target.ID,
+		TargetController:   *target,
+		MigrationID:        migrationID,
+		InitiatedBy:        user.Name,
+		Status:             dbmodel.MigrationRunning,
+	}
+	if err := j.Database.AddModelMigration(ctx, &mm); err != nil {
+		zapctx.Error(ctx, "cannot record model migration", zap.String("model", m.UUID.String), zap.String("migration-id", migrationID), zaputil.Error(err))
+		return
+	}
+	j.auditModelMigration(user.ResourceTag(), &mm)
+}
+
+// MonitorModelMigrations checks the progress of every model migration
+// JIMM has initiated that has not yet finished. Migrations that moved
+// the model are completed by rebinding the model's credential on the
+// target controller and recording the model on that controller.
+// Migrations that finished without moving the model are recorded as
+// aborted. Migrations whose outcome cannot be determined within the
+// MigrationTimeout are abandoned and reported. Every status change is
+// recorded in the audit log. Because the migrations are recorded in the
+// database, monitoring resumes on whichever JIMM instance next calls
+// MonitorModelMigrations. The number of migrations that finished is
+// returned.
+func (j *JIMM) MonitorModelMigrations(ctx context.Context) (int, error) {
+	const op = errors.Op("jimm.MonitorModelMigrations")
+
+	migrations, err := j.Database.ListModelMigrations(ctx, 0, true)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	finished := 0
+	for i := range migrations {
+		mm := &migrations[i]
+		phase := mm.Phase
+		if err := j.checkModelMigration(ctx, mm); err != nil {
+			zapctx.Warn(ctx, "cannot check model migration", zap.String("model", mm.Model.UUID.String), zap.String("migration-id", mm.MigrationID), zaputil.Error(err))
+			timeout := j.MigrationTimeout
+			if timeout <= 0 {
+				timeout = DefaultMigrationTimeout
+			}
+			if time.Since(mm.CreatedAt) < timeout {
+				continue
+			}
+			mm.Status = dbmodel.MigrationAbandoned
+			mm.Error = fmt.Sprintf("migration outcome not determined within %s: %s", timeout, err)
+		}
+		if mm.Status == dbmodel.MigrationRunning && mm.Phase == phase {
+			continue
+		}
+		if mm.Status != dbmodel.MigrationRunning {
+			mm.EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			finished++
+		}
+		if err := j.Database.UpdateModelMigration(ctx, mm); err != nil {
+			return finished, errors.E(op, err)
+		}
+		j.auditModelMigration(j.ResourceTag(), mm)
+		if mm.Status == dbmodel.MigrationAbandoned {
+			zapctx.Error(ctx, "model migration abandoned", zap.String("model", mm.Model.UUID.String), zap.String("migration-id", mm.MigrationID), zap.String("error", mm.Error))
+			j.Notifier.Notify(ctx, notify.Event{
+				Kind:       notify.ModelMigrationAbandoned,
+				Controller: mm.SourceController.Name,
+				Message:    fmt.Sprintf("migration of model %s to %s abandoned: %s", mm.Model.UUID.String, mm.TargetController.Name, mm.Error),
+			})
+		}
+	}
+	return finished, nil
+}
+
+// checkModelMigration updates the status and phase of the given migration
+// from the source and target controllers. An error is returned if the
+// state of the migration cannot be determined.
+func (j *JIMM) checkModelMigration(ctx context.Context, mm *dbmodel.ModelMigration) error {
+	mt := mm.Model.ResourceTag()
+	api, err := j.dial(ctx, &mm.SourceController, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	mi := jujuparams.ModelInfo{
+		UUID: mt.Id(),
+	}
+	err = api.ModelInfo(ctx, &mi)
+	switch {
+	case isModelNotFound(err):
+		// The model has left the source controller.
+	case err != nil:
+		return err
+	case mi.Migration == nil:
+		return errors.E("source controller has no record of the migration")
+	case mi.Migration.End == nil:
+		mm.Phase = mi.Migration.Status
+		return nil
+	default:
+		mm.Phase = mi.Migration.Status
+	}
+
+	// The migration has finished, if the model is on the target
+	// controller it succeeded.
+	target, err := j.dial(ctx, &mm.TargetController, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+	err = target.ModelInfo(ctx, &jujuparams.ModelInfo{UUID: mt.Id()})
+	if isModelNotFound(err) {
+		if mi.Migration == nil {
+			return errors.E("model not found on the source or target controller")
+		}
+		mm.Status = dbmodel.MigrationAborted
+		mm.Error = mi.Migration.Status
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return err
+	}
+	if err := j.rebindModelCredential(ctx, &mm.TargetController, target, mt, &m); err != nil {
+		return err
+	}
+	m.Controller = mm.TargetController
+	m.ControllerID = mm.TargetControllerID
+	if err := j.Database.UpdateModel(ctx, &m); err != nil {
+		return err
+	}
+	mm.Status = dbmodel.MigrationCompleted
+	return nil
+}
+
+// isModelNotFound reports whether the given error from a controller
+// indicates that the controller does not host the model.
+func isModelNotFound(err error) bool {
+	code := errors.ErrorCode(err)
+	return code == errors.CodeNotFound || code == errors.CodeModelNotFound
+}
+
+// auditModelMigration records the current status of the given migration
+// in the audit log, attributed to the given identity.
+func (j *JIMM) auditModelMigration(identity names.Tag, mm *dbmodel.ModelMigration) {
+	params, _ := json.Marshal(mm.ToAPIModelMigration())
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Now().UTC().Round(time.Millisecond),
+		ConversationId: utils.NewConversationID(),
+		Model:          mm.Model.UUID.String,
+		FacadeName:     "JIMM",
+		FacadeMethod:   "ModelMigration",
+		ObjectId:       mm.Model.ResourceTag().String(),
+		IdentityTag:    identity.String(),
+		IsResponse:     mm.Status != dbmodel.MigrationRunning,
+		Params:         dbmodel.JSON(params),
+	}
+	if mm.Error != "" {
+		ale.Errors = controllerCallErrors(context.Background(), errors.E(mm.Error))
+	}
+	j.AddAuditLogEntry(&ale)
+}
+
+// ListModelMigrations returns the model migrations JIMM has initiated
+// between its controllers, most recent first. If the model tag is not
+// zero only the migrations of that model are returned, if activeOnly is
+// true only the migrations that have not finished are returned. Only
+// JIMM administrators may list model migrations.
+func (j *JIMM) ListModelMigrations(ctx context.Context, user *openfga.User, mt names.ModelTag, activeOnly bool) ([]apiparams.ModelMigration, error) {
+	const op = errors.Op("jimm.ListModelMigrations")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	var modelID uint
+	if mt.Id() != "" {
+		var m dbmodel.Model
+		m.SetTag(mt)
+		if err := j.Database.GetModel(ctx, &m); err != nil {
+			return nil, errors.E(op, err)
+		}
+		modelID = m.ID
+	}
+	migrations, err := j.Database.ListModelMigrations(ctx, modelID, activeOnly)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.ModelMigration, len(migrations))
+	for i, mm := range migrations {
+		resp[i] = mm.ToAPIModelMigration()
+	}
+	return resp, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const modelMigrationTestEnv = `clouds:
+- name: aws
+  type: ec2
+  regions:
+  - name: eu-west-1
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: aws
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: aws
+  region: eu-west-1
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: aws
+  region: eu-west-1
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: aws
+  region: eu-west-1
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: aws
+  region: eu-west-1
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+- name: model-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  controller: controller-1
+  cloud: aws
+  region: eu-west-1
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+`
+
+func TestMonitorModelMigrations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	ch := new(recordingChannel)
+	notifier, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	notifier.AddChannel(ch, 0)
+
+	ended := time.Now()
+	model3Phase := "EXPORT"
+	source := &jimmtest.API{
+		ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+			switch mi.UUID {
+			case "00000002-0000-0000-0000-000000000001":
+				return errors.E("model not found", errors.CodeNotFound)
+			case "00000002-0000-0000-0000-000000000002":
+				mi.Migration = &jujuparams.ModelMigrationStatus{Status: "aborted, removed from target controller", End: &ended}
+			default:
+				if model3Phase == "" {
+					return errors.E("controller unavailable")
+				}
+				mi.Migration = &jujuparams.ModelMigrationStatus{Status: model3Phase}
+			}
+			return nil
+		},
+	}
+	var rebound []string
+	target := &jimmtest.API{
+		ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+			if mi.UUID != "00000002-0000-0000-0000-000000000001" {
+				return errors.E("model not found", errors.CodeNotFound)
+			}
+			return nil
+		},
+		UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, nil
+		},
+		ChangeModelCredential_: func(_ context.Context, mt names.ModelTag, _ names.CloudCredentialTag) error {
+			rebound = append(rebound, mt.Id())
+			return nil
+		},
+	}
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: jimmtest.DialerMap{
+			"controller-1": &jimmtest.Dialer{API: source},
+			"controller-2": &jimmtest.Dialer{API: target},
+		},
+		Notifier: notifier,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelMigrationTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	ctl1 := dbmodel.Controller{Name: "controller-1"}
+	c.Assert(j.Database.GetController(ctx, &ctl1), qt.IsNil)
+	ctl2 := dbmodel.Controller{Name: "controller-2"}
+	c.Assert(j.Database.GetController(ctx, &ctl2), qt.IsNil)
+	for _, name := range []string{"model-1", "model-2", "model-3"} {
+		m := env.Model("alice@canonical.com", name).DBObject(c, j.Database)
+		err := j.Database.AddModelMigration(ctx, &dbmodel.ModelMigration{
+			ModelID:            m.ID,
+			SourceControllerID: ctl1.ID,
+			TargetControllerID: ctl2.ID,
+			MigrationID:        m.UUID.String + ":0",
+			InitiatedBy:        "alice@canonical.com",
+			Status:             dbmodel.MigrationRunning,
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	// The first migration completed and the second was aborted, the
+	// third is still running.
+	n, err := j.MonitorModelMigrations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 2)
+	c.Check(rebound, qt.DeepEquals, []string{"00000002-0000-0000-0000-000000000001"})
+
+	m1 := dbmodel.Model{}
+	m1.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+	c.Assert(j.Database.GetModel(ctx, &m1), qt.IsNil)
+	c.Check(m1.Controller.Name, qt.Equals, "controller-2")
+
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+
+	migrations, err := j.ListModelMigrations(ctx, admin, names.ModelTag{}, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 3)
+	c.Check(migrations[0].Status, qt.Equals, dbmodel.MigrationRunning)
+	c.Check(migrations[0].Phase, qt.Equals, "EXPORT")
+	c.Check(migrations[1].Status, qt.Equals, dbmodel.MigrationAborted)
+	c.Check(migrations[1].Error, qt.Equals, "aborted, removed from target controller")
+	c.Check(migrations[1].Ended, qt.Not(qt.IsNil))
+	c.Check(migrations[2].Status, qt.Equals, dbmodel.MigrationCompleted)
+
+	// A migration whose outcome cannot be determined is retried until
+	// the timeout expires, then abandoned and reported.
+	model3Phase = ""
+	n, err = j.MonitorModelMigrations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+	j.MigrationTimeout = time.Nanosecond
+	n, err = j.MonitorModelMigrations(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+	notifier.Wait()
+	c.Assert(ch.events, qt.HasLen, 1)
+	c.Check(ch.events[0].Kind, qt.Equals, notify.ModelMigrationAbandoned)
+	c.Check(ch.events[0].Controller, qt.Equals, "controller-1")
+
+	migrations, err = j.ListModelMigrations(ctx, admin, names.NewModelTag("00000002-0000-0000-0000-000000000003"), false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(migrations, qt.HasLen, 1)
+	c.Check(migrations[0].Status, qt.Equals, dbmodel.MigrationAbandoned)
+	migrations, err = j.ListModelMigrations(ctx, admin, names.ModelTag{}, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(migrations, qt.HasLen, 0)
+
+	// Every status change is recorded in the audit log.
+	var statuses []string
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{Method: "ModelMigration"}, func(ale *dbmodel.AuditLogEntry) error {
+		c.Check(ale.IdentityTag, qt.Equals, j.ResourceTag().String())
+		statuses = append(statuses, ale.ObjectId)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(statuses, qt.HasLen, 4)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	_, err = j.ListModelMigrations(ctx, alice, names.ModelTag{}, false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
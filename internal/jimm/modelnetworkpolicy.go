@@ -0,0 +1,182 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// egressConfigKey is the model configuration key the allowed egress
+// destinations of a model network policy are propagated to.
+const egressConfigKey = "egress-subnets"
+
+// egressEnforcingCloudTypes holds the types of cloud whose providers
+// manage firewalls that can enforce the allowed egress destinations of a
+// model network policy.
+var egressEnforcingCloudTypes = map[string]bool{
+	"azure":     true,
+	"ec2":       true,
+	"gce":       true,
+	"openstack": true,
+}
+
+// SetModelNetworkPolicy attaches a network policy, allowing traffic from
+// the model with the given tag to reach only the given egress CIDRs, to
+// the model. Setting the policy of a model replaces any existing policy.
+// The policy is stored even if it cannot be enforced, the returned policy
+// reports whether it was applied to the controller hosting the model and
+// why not if it was not. Only JIMM administrators may set model network
+// policies.
+func (j *JIMM) SetModelNetworkPolicy(ctx context.Context, user *openfga.User, mt names.ModelTag, allowedEgress []string) (apiparams.ModelNetworkPolicy, error) {
+	const op = errors.Op("jimm.SetModelNetworkPolicy")
+
+	if !user.JimmAdmin {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	cidrs, err := normaliseCIDRs(allowedEgress)
+	if err != nil {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+	if len(cidrs) == 0 {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, errors.CodeBadRequest, "no allowed egress destinations specified")
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, err)
+	}
+	p := dbmodel.ModelNetworkPolicy{
+		ModelID:       m.ID,
+		SetBy:         user.Name,
+		AllowedEgress: cidrs,
+	}
+	j.enforceModelNetworkPolicy(ctx, &m, &p)
+	if err := j.Database.SetModelNetworkPolicy(ctx, &p); err != nil {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, err)
+	}
+	p.Model = m
+	return p.ToAPIModelNetworkPolicy(), nil
+}
+
+// RemoveModelNetworkPolicy removes the network policy from the model with
+// the given tag, resetting the model's configuration if the policy was
+// enforced. Only JIMM administrators may remove model network policies.
+func (j *JIMM) RemoveModelNetworkPolicy(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	const op = errors.Op("jimm.RemoveModelNetworkPolicy")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return errors.E(op, err)
+	}
+	p := dbmodel.ModelNetworkPolicy{
+		ModelID: m.ID,
+	}
+	if err := j.Database.GetModelNetworkPolicy(ctx, &p); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil
+		}
+		return errors.E(op, err)
+	}
+	if p.Enforced {
+		api, err := j.dial(ctx, &m.Controller, mt)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		defer api.Close()
+		if err := api.UnsetModelConfig(ctx, []string{egressConfigKey}); err != nil {
+			return errors.E(op, err)
+		}
+	}
+	if err := j.Database.DeleteModelNetworkPolicy(ctx, &p); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelNetworkPolicies returns the model network policies. If
+// unenforcedOnly is true only the policies that could not be enforced
+// are returned, reporting the models whose policies are not in effect.
+// Only JIMM administrators may list model network policies.
+func (j *JIMM) ListModelNetworkPolicies(ctx context.Context, user *openfga.User, unenforcedOnly bool) ([]apiparams.ModelNetworkPolicy, error) {
+	const op = errors.Op("jimm.ListModelNetworkPolicies")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	policies, err := j.Database.ListModelNetworkPolicies(ctx, unenforcedOnly)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.ModelNetworkPolicy, len(policies))
+	for i, p := range policies {
+		resp[i] = p.ToAPIModelNetworkPolicy()
+	}
+	return resp, nil
+}
+
+// enforceModelNetworkPolicy applies the given policy to the model on its
+// controller, if the model's provider can enforce it, recording the
+// outcome in the policy.
+func (j *JIMM) enforceModelNetworkPolicy(ctx context.Context, m *dbmodel.Model, p *dbmodel.ModelNetworkPolicy) {
+	p.Enforced = false
+	p.EnforcementError = ""
+	cloudType := m.CloudRegion.Cloud.Type
+	if !egressEnforcingCloudTypes[cloudType] {
+		p.EnforcementError = fmt.Sprintf("%q clouds cannot enforce egress policies", cloudType)
+		return
+	}
+	api, err := j.dial(ctx, &m.Controller, m.ResourceTag())
+	if err != nil {
+		zapctx.Warn(ctx, "cannot enforce model network policy", zap.String("model", m.UUID.String), zap.Error(err))
+		p.EnforcementError = err.Error()
+		return
+	}
+	defer api.Close()
+	err = api.SetModelConfig(ctx, map[string]interface{}{
+		egressConfigKey: strings.Join(p.AllowedEgress, ","),
+	})
+	if err != nil {
+		zapctx.Warn(ctx, "cannot enforce model network policy", zap.String("model", m.UUID.String), zap.Error(err))
+		p.EnforcementError = err.Error()
+		return
+	}
+	p.Enforced = true
+}
+
+// normaliseCIDRs parses the given CIDRs, returning them in canonical form,
+// sorted and without duplicates.
+func normaliseCIDRs(cidrs []string) ([]string, error) {
+	seen := make(map[string]bool, len(cidrs))
+	normalised := make([]string, 0, len(cidrs))
+	for _, s := range cidrs {
+		_, ipnet, err := net.ParseCIDR(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress destination %q", s)
+		}
+		cidr := ipnet.String()
+		if seen[cidr] {
+			continue
+		}
+		seen[cidr] = true
+		normalised = append(normalised, cidr)
+	}
+	sort.Strings(normalised)
+	return normalised, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const modelNetworkPolicyTestEnv = `clouds:
+- name: aws
+  type: ec2
+  regions:
+  - name: eu-west-1
+- name: localhost
+  type: lxd
+  regions:
+  - name: default
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: aws
+- owner: alice@canonical.com
+  name: cred-2
+  cloud: localhost
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: aws
+  region: eu-west-1
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: aws
+  region: eu-west-1
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: localhost
+  region: default
+  cloud-credential: cred-2
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+`
+
+func TestModelNetworkPolicy(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	config := make(map[string]interface{})
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				SetModelConfig_: func(_ context.Context, cfg map[string]interface{}) error {
+					for k, v := range cfg {
+						config[k] = v
+					}
+					return nil
+				},
+				UnsetModelConfig_: func(_ context.Context, keys []string) error {
+					for _, k := range keys {
+						delete(config, k)
+					}
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelNetworkPolicyTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+
+	mt1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	mt2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+
+	// Only JIMM administrators may set network policies, even model
+	// administrators may not.
+	_, err = j.SetModelNetworkPolicy(ctx, alice, mt1, []string{"10.0.0.0/8"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.SetModelNetworkPolicy(ctx, admin, mt1, []string{"not-a-cidr"})
+	c.Check(err, qt.ErrorMatches, `invalid egress destination "not-a-cidr"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.SetModelNetworkPolicy(ctx, admin, mt1, nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	p, err := j.SetModelNetworkPolicy(ctx, admin, mt1, []string{"192.168.1.7/16", "10.0.0.0/8", "10.0.0.0/8"})
+	c.Assert(err, qt.IsNil)
+	c.Check(p.ModelTag, qt.Equals, mt1.String())
+	c.Check(p.AllowedEgress, qt.DeepEquals, []string{"10.0.0.0/8", "192.168.0.0/16"})
+	c.Check(p.SetBy, qt.Equals, "admin@canonical.com")
+	c.Check(p.Enforced, qt.IsTrue)
+	c.Check(p.EnforcementError, qt.Equals, "")
+	c.Check(config, qt.DeepEquals, map[string]interface{}{"egress-subnets": "10.0.0.0/8,192.168.0.0/16"})
+
+	// The policy of a model whose provider cannot enforce it is stored
+	// and reported.
+	p, err = j.SetModelNetworkPolicy(ctx, admin, mt2, []string{"10.0.0.0/8"})
+	c.Assert(err, qt.IsNil)
+	c.Check(p.Enforced, qt.IsFalse)
+	c.Check(p.EnforcementError, qt.Equals, `"lxd" clouds cannot enforce egress policies`)
+
+	_, err = j.ListModelNetworkPolicies(ctx, alice, false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	policies, err := j.ListModelNetworkPolicies(ctx, admin, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(policies, qt.HasLen, 2)
+	policies, err = j.ListModelNetworkPolicies(ctx, admin, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(policies, qt.HasLen, 1)
+	c.Check(policies[0].ModelTag, qt.Equals, mt2.String())
+
+	err = j.RemoveModelNetworkPolicy(ctx, alice, mt1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelNetworkPolicy(ctx, admin, mt1)
+	c.Assert(err, qt.IsNil)
+	c.Check(config, qt.HasLen, 0)
+	err = j.RemoveModelNetworkPolicy(ctx, admin, mt1)
+	c.Assert(err, qt.IsNil)
+	policies, err = j.ListModelNetworkPolicies(ctx, admin, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(policies, qt.HasLen, 1)
+}
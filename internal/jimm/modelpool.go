@@ -0,0 +1,377 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	jujupermission "github.com/juju/juju/core/permission"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelPoolConfigKey is the model config attribute used to request that
+// a model is claimed from the named model pool, rather than created on a
+// controller. Should the pool have no ready models the model is created
+// as normal, in the pool's cloud region with the pool's template config.
+// The attribute is not passed on to the controller.
+const ModelPoolConfigKey = "jimm-model-pool"
+
+// AddModelPool adds a pool keeping size empty models ready in the given
+// cloud region, created with the given credential and template config.
+// The models are created in the background by ReplenishModelPools. Only
+// JIMM administrators may add model pools.
+func (j *JIMM) AddModelPool(ctx context.Context, user *openfga.User, name string, cloud names.CloudTag, region string, credential names.CloudCredentialTag, size int, config map[string]interface{}) (apiparams.ModelPool, error) {
+	const op = errors.Op("jimm.AddModelPool")
+
+	if !user.JimmAdmin {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if !names.IsValidModelName(name) {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model pool name %q", name))
+	}
+	if size < 1 {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeBadRequest, "model pool size must be positive")
+	}
+	if _, ok := config[ModelPoolConfigKey]; ok {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("model pool config cannot contain %s", ModelPoolConfigKey))
+	}
+	if credential.Cloud().Id() != cloud.Id() {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeBadRequest, "cloud credential cloud mismatch")
+	}
+
+	c := dbmodel.Cloud{
+		Name: cloud.Id(),
+	}
+	if err := j.Database.GetCloud(ctx, &c); err != nil {
+		return apiparams.ModelPool{}, errors.E(op, err)
+	}
+	cr := c.Region(region)
+	if cr.ID == 0 {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeNotFound, fmt.Sprintf("cloud region %s/%s not found", cloud.Id(), region))
+	}
+	if len(cr.Controllers) == 0 {
+		return apiparams.ModelPool{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("unsupported cloud region %s/%s", cloud.Id(), region))
+	}
+	var cred dbmodel.CloudCredential
+	cred.SetTag(credential)
+	if err := j.Database.GetCloudCredential(ctx, &cred); err != nil {
+		return apiparams.ModelPool{}, errors.E(op, err)
+	}
+
+	p := dbmodel.ModelPool{
+		Name:              name,
+		CloudRegionID:     cr.ID,
+		CloudCredentialID: cred.ID,
+		Config:            config,
+		Size:              size,
+	}
+	if err := j.Database.AddModelPool(ctx, &p); err != nil {
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return apiparams.ModelPool{}, errors.E(op, err, fmt.Sprintf("model pool %q already exists", name))
+		}
+		return apiparams.ModelPool{}, errors.E(op, err)
+	}
+	cr.Cloud = c
+	p.CloudRegion = cr
+	p.CloudCredential = cred
+	return p.ToAPIModelPool(), nil
+}
+
+// RemoveModelPool removes the named model pool, destroying the models
+// ready in the pool. Models already claimed from the pool are not
+// affected. Only JIMM administrators may remove model pools.
+func (j *JIMM) RemoveModelPool(ctx context.Context, user *openfga.User, name string) error {
+	const op = errors.Op("jimm.RemoveModelPool")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	p := dbmodel.ModelPool{
+		Name: name,
+	}
+	if err := j.Database.GetModelPool(ctx, &p); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteModelPool(ctx, &p); err != nil {
+		return errors.E(op, err)
+	}
+	for i := range p.Models {
+		j.destroyPooledModel(ctx, &p.Models[i])
+	}
+	return nil
+}
+
+// ListModelPools returns the model pools with the number of models ready
+// in each. Only JIMM administrators may list model pools.
+func (j *JIMM) ListModelPools(ctx context.Context, user *openfga.User) ([]apiparams.ModelPool, error) {
+	const op = errors.Op("jimm.ListModelPools")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	pools, err := j.Database.ListModelPools(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.ModelPool, len(pools))
+	for i, p := range pools {
+		resp[i] = p.ToAPIModelPool()
+	}
+	return resp, nil
+}
+
+// ReplenishModelPools creates models in every model pool holding fewer
+// ready models than its size, and destroys the excess models of pools
+// holding more. A pool that cannot be replenished does not prevent the
+// other pools being replenished. The number of models created is
+// returned.
+func (j *JIMM) ReplenishModelPools(ctx context.Context) (int, error) {
+	const op = errors.Op("jimm.ReplenishModelPools")
+
+	pools, err := j.Database.ListModelPools(ctx)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	var created int
+	for i := range pools {
+		p := &pools[i]
+		for n := len(p.Models); n > p.Size; n-- {
+			m, err := j.Database.ClaimPooledModel(ctx, p)
+			if err != nil {
+				if errors.ErrorCode(err) != errors.CodeNotFound {
+					zapctx.Error(ctx, "failed to trim model pool", zap.String("pool", p.Name), zaputil.Error(err))
+				}
+				break
+			}
+			j.destroyPooledModel(ctx, m)
+		}
+		for n := len(p.Models); n < p.Size; n++ {
+			if err := j.createPooledModel(ctx, p); err != nil {
+				zapctx.Error(ctx, "failed to replenish model pool", zap.String("pool", p.Name), zaputil.Error(err))
+				break
+			}
+			created++
+		}
+	}
+	return created, nil
+}
+
+// createPooledModel creates an empty model on a controller hosting the
+// pool's cloud region and records it as ready in the pool. The model is
+// owned by the owner of the pool's credential until it is claimed.
+func (j *JIMM) createPooledModel(ctx context.Context, p *dbmodel.ModelPool) error {
+	owner, err := dbmodel.NewIdentity(p.CloudCredential.OwnerIdentityName)
+	if err != nil {
+		return err
+	}
+	if err := j.Database.GetIdentity(ctx, owner); err != nil {
+		return err
+	}
+
+	b := newModelBuilder(ctx, j)
+	b = b.WithOwner(owner)
+	b = b.WithName(fmt.Sprintf("%s-%s", p.Name, uuid.NewString()[:8]))
+	b = b.WithConfig(p.Config)
+	b = b.WithCloud(nil, p.CloudRegion.Cloud.ResourceTag())
+	b = b.WithControllerTier(p.Config)
+	b = b.WithCloudRegion(p.CloudRegion.Name)
+	if err := b.Error(); err != nil {
+		return err
+	}
+	b.credential = &p.CloudCredential
+	args, err := b.jujuModelCreateArgs()
+	if err != nil {
+		return err
+	}
+
+	api, err := j.dial(ctx, b.controller, names.ModelTag{}, permission{
+		resource: b.cloud.ResourceTag().String(),
+		relation: string(jujupermission.AddModelAccess),
+	})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	if err := b.updateCredential(ctx, api, b.credential); err != nil {
+		return err
+	}
+	var info jujuparams.ModelInfo
+	if err := api.CreateModel(ctx, args, &info); err != nil {
+		return err
+	}
+	m := dbmodel.PooledModel{
+		ModelPoolID:  p.ID,
+		ControllerID: b.controller.ID,
+		Controller:   *b.controller,
+		Name:         info.Name,
+		UUID:         info.UUID,
+	}
+	if err := api.GrantJIMMModelAdmin(ctx, names.NewModelTag(info.UUID)); err != nil {
+		j.destroyPooledModel(ctx, &m)
+		return err
+	}
+	if err := j.Database.AddPooledModel(ctx, &m); err != nil {
+		j.destroyPooledModel(ctx, &m)
+		return err
+	}
+	return nil
+}
+
+// destroyPooledModel destroys the given pooled model on its controller.
+// Failures are logged, leaking the model.
+func (j *JIMM) destroyPooledModel(ctx context.Context, m *dbmodel.PooledModel) {
+	mt := names.NewModelTag(m.UUID)
+	api, err := j.dialController(ctx, &m.Controller)
+	if err != nil {
+		zapctx.Error(ctx, "leaked model", zap.String("model", mt.Id()), zaputil.Error(err))
+		return
+	}
+	defer api.Close()
+	if err := api.DestroyModel(ctx, mt, nil, nil, nil, nil); err != nil {
+		zapctx.Error(ctx, "leaked model", zap.String("model", mt.Id()), zaputil.Error(err))
+	}
+}
+
+// modelPoolName returns the name of the model pool the given model
+// config requests the model is claimed from, if any.
+func modelPoolName(cfg map[string]interface{}) (string, error) {
+	v, ok := cfg[ModelPoolConfigKey]
+	if !ok {
+		return "", nil
+	}
+	name, ok := v.(string)
+	if !ok {
+		return "", errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid %s value", ModelPoolConfigKey))
+	}
+	return name, nil
+}
+
+// addPooledModel adds the model described by the given arguments by
+// claiming a model ready in the given pool, renaming it and transferring
+// it to the given owner and to the owner's credential. The model keeps
+// the name it was created with on the controller. If the pool has no
+// ready models, false is returned and the model should be created as
+// normal.
+func (j *JIMM) addPooledModel(ctx context.Context, user *openfga.User, owner *dbmodel.Identity, args *ModelCreateArgs, p *dbmodel.ModelPool) (_ *jujuparams.ModelInfo, _ bool, err error) {
+	b := newModelBuilder(ctx, j)
+	b = b.WithOwner(owner)
+	b = b.WithCreator(user.Name)
+	b = b.WithName(args.Name)
+	b = b.WithCloud(user, p.CloudRegion.Cloud.ResourceTag())
+	if err := b.Error(); err != nil {
+		return nil, false, err
+	}
+
+	canAddModel, err := openfga.NewUser(owner, j.OpenFGAClient).IsAllowedAddModel(ctx, b.cloud.ResourceTag())
+	if err != nil {
+		return nil, false, errors.E("permission check failed")
+	}
+	if !canAddModel {
+		return nil, false, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	if args.CloudCredential != (names.CloudCredentialTag{}) {
+		if err := j.checkCloudCredentialUse(ctx, user, owner, args.CloudCredential); err != nil {
+			return nil, false, err
+		}
+		b = b.WithCloudCredential(args.CloudCredential)
+		if err := b.Error(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	m, err := j.Database.ClaimPooledModel(ctx, p)
+	if err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	b.cloudRegion = p.CloudRegion.Name
+	b.cloudRegionID = p.CloudRegionID
+	b.controller = &m.Controller
+
+	b = b.CreateDatabaseModel()
+	if err := b.Error(); err != nil {
+		// The claimed model is untouched, return it to the pool.
+		m.ID = 0
+		if err := j.Database.AddPooledModel(context.Background(), m); err != nil {
+			j.destroyPooledModel(context.Background(), m)
+		}
+		return nil, false, err
+	}
+	defer b.Cleanup()
+
+	b = b.ClaimControllerModel(m)
+	if err := b.Error(); err != nil {
+		j.destroyPooledModel(context.Background(), m)
+		return nil, false, err
+	}
+	b = b.UpdateDatabaseModel()
+	if err := b.Error(); err != nil {
+		j.destroyPooledModel(context.Background(), m)
+		return nil, false, err
+	}
+	b = b.RecordCloudCredential()
+
+	mi := b.JujuModelInfo()
+	ownerUser := openfga.NewUser(owner, j.OpenFGAClient)
+	modelTag := names.NewModelTag(mi.UUID)
+	if err := j.addModelPermissions(ctx, ownerUser, modelTag, b.controller.ResourceTag()); err != nil {
+		return nil, false, err
+	}
+	j.applyModelACLTemplates(ctx, ownerUser, modelTag)
+	return mi, true, nil
+}
+
+// ClaimControllerModel takes over the given pooled model on its
+// controller, switching it to the builder's credential, and records the
+// model's information under the builder's name and owner.
+func (b *modelBuilder) ClaimControllerModel(m *dbmodel.PooledModel) *modelBuilder {
+	if b.err != nil {
+		return b
+	}
+	if b.model == nil {
+		b.err = errors.E("model not specified")
+		return b
+	}
+
+	api, err := b.jimm.dial(b.ctx, b.controller, names.ModelTag{})
+	if err != nil {
+		b.err = errors.E(err)
+		return b
+	}
+	defer api.Close()
+
+	if err := b.updateCredential(b.ctx, api, b.credential); err != nil {
+		b.err = errors.E(fmt.Sprintf("failed to update cloud credential: %s", err), err)
+		return b
+	}
+	mt := names.NewModelTag(m.UUID)
+	if err := api.ChangeModelCredential(b.ctx, mt, b.credential.ResourceTag()); err != nil {
+		b.err = errors.E(err)
+		return b
+	}
+	info := jujuparams.ModelInfo{
+		UUID: m.UUID,
+	}
+	if err := api.ModelInfo(b.ctx, &info); err != nil {
+		b.err = errors.E(err)
+		return b
+	}
+	info.Name = b.name
+	info.OwnerTag = b.owner.Tag().String()
+	info.CloudCredentialTag = b.credential.Tag().String()
+	b.modelInfo = &info
+	return b
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const modelPoolTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  - name: test-region-2
+  users:
+  - user: alice@canonical.com
+    access: add-model
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+  auth-type: empty
+- owner: bob@canonical.com
+  name: pool-cred
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 1
+`
+
+func TestModelPool(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	var created []jujuparams.ModelCreateArgs
+	var destroyed []string
+	var credentials []string
+	modelNames := make(map[string]string)
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				CreateModel_: func(_ context.Context, args *jujuparams.ModelCreateArgs, mi *jujuparams.ModelInfo) error {
+					created = append(created, *args)
+					mi.Name = args.Name
+					mi.UUID = fmt.Sprintf("00000002-0000-0000-0000-%012d", len(created))
+					modelNames[mi.UUID] = args.Name
+					return nil
+				},
+				GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+					return nil
+				},
+				ChangeModelCredential_: func(_ context.Context, _ names.ModelTag, ct names.CloudCredentialTag) error {
+					credentials = append(credentials, ct.Id())
+					return nil
+				},
+				ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+					mi.Name = modelNames[mi.UUID]
+					mi.Type = "iaas"
+					mi.OwnerTag = names.NewUserTag("bob@canonical.com").String()
+					mi.Life = "alive"
+					mi.Status = jujuparams.EntityStatus{
+						Status: "available",
+					}
+					return nil
+				},
+				DestroyModel_: func(_ context.Context, mt names.ModelTag, _, _ *bool, _, _ *time.Duration) error {
+					destroyed = append(destroyed, mt.Id())
+					return nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelPoolTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+
+	ct := names.NewCloudTag("test-cloud")
+	poolCred := names.NewCloudCredentialTag("test-cloud/bob@canonical.com/pool-cred")
+	config := map[string]interface{}{"key1": "value1"}
+
+	// Only JIMM administrators may manage model pools.
+	_, err = j.AddModelPool(ctx, alice, "ci", ct, "test-region-1", poolCred, 2, config)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.ListModelPools(ctx, alice)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelPool(ctx, alice, "ci")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.AddModelPool(ctx, admin, "ci", ct, "test-region-1", poolCred, 0, config)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.AddModelPool(ctx, admin, "ci", ct, "no-such-region", poolCred, 2, config)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	_, err = j.AddModelPool(ctx, admin, "ci", ct, "test-region-2", poolCred, 2, config)
+	c.Check(err, qt.ErrorMatches, `unsupported cloud region test-cloud/test-region-2`)
+
+	p, err := j.AddModelPool(ctx, admin, "ci", ct, "test-region-1", poolCred, 2, config)
+	c.Assert(err, qt.IsNil)
+	c.Check(p.CloudTag, qt.Equals, ct.String())
+	c.Check(p.CloudRegion, qt.Equals, "test-region-1")
+	c.Check(p.CloudCredentialTag, qt.Equals, poolCred.String())
+	c.Check(p.Size, qt.Equals, 2)
+	c.Check(p.Ready, qt.Equals, 0)
+	_, err = j.AddModelPool(ctx, admin, "ci", ct, "test-region-1", poolCred, 2, config)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	// The replenisher fills the pool with models created from the
+	// pool's template.
+	n, err := j.ReplenishModelPools(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 2)
+	c.Assert(created, qt.HasLen, 2)
+	c.Check(created[0].Config, qt.DeepEquals, map[string]interface{}{"key1": "value1"})
+	c.Check(created[0].OwnerTag, qt.Equals, names.NewUserTag("bob@canonical.com").String())
+	c.Check(created[0].CloudRegion, qt.Equals, "test-region-1")
+	c.Check(created[0].CloudCredentialTag, qt.Equals, poolCred.String())
+	n, err = j.ReplenishModelPools(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+
+	pools, err := j.ListModelPools(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pools, qt.HasLen, 1)
+	c.Check(pools[0].Ready, qt.Equals, 2)
+
+	// A model requested from the pool is claimed instantly, renamed and
+	// transferred to its owner and their credential.
+	_, err = j.AddModel(ctx, alice, &jimm.ModelCreateArgs{
+		Name:        "test-model",
+		Owner:       alice.ResourceTag(),
+		Config:      map[string]interface{}{jimm.ModelPoolConfigKey: "ci"},
+		CloudRegion: "test-region-2",
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.AddModel(ctx, alice, &jimm.ModelCreateArgs{
+		Name:   "test-model",
+		Owner:  alice.ResourceTag(),
+		Config: map[string]interface{}{jimm.ModelPoolConfigKey: "no-such-pool"},
+	})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	mi, err := j.AddModel(ctx, alice, &jimm.ModelCreateArgs{
+		Name:   "test-model",
+		Owner:  alice.ResourceTag(),
+		Config: map[string]interface{}{jimm.ModelPoolConfigKey: "ci"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(created, qt.HasLen, 2)
+	c.Check(mi.UUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+	c.Check(mi.Name, qt.Equals, "test-model")
+	c.Check(mi.OwnerTag, qt.Equals, alice.ResourceTag().String())
+	c.Check(credentials, qt.DeepEquals, []string{"test-cloud/alice@canonical.com/cred-1"})
+
+	m := dbmodel.Model{
+		UUID: sql.NullString{String: mi.UUID, Valid: true},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Name, qt.Equals, "test-model")
+	c.Check(m.OwnerIdentityName, qt.Equals, "alice@canonical.com")
+	c.Check(m.Controller.Name, qt.Equals, "controller-1")
+	c.Check(m.CloudCredential.Name, qt.Equals, "cred-1")
+	isAdmin, err := openfga.IsAdministrator(ctx, alice, m.ResourceTag())
+	c.Assert(err, qt.IsNil)
+	c.Check(isAdmin, qt.IsTrue)
+
+	pools, err = j.ListModelPools(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pools, qt.HasLen, 1)
+	c.Check(pools[0].Ready, qt.Equals, 1)
+	n, err = j.ReplenishModelPools(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+
+	// Removing the pool destroys its ready models, but not the claimed
+	// model.
+	err = j.RemoveModelPool(ctx, admin, "ci")
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.DeepEquals, []string{
+		"00000002-0000-0000-0000-000000000002",
+		"00000002-0000-0000-0000-000000000003",
+	})
+	pools, err = j.ListModelPools(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(pools, qt.HasLen, 0)
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// ResolveModel resolves the given model reference to a model tag. A
+// reference may be a model tag, a model UUID or a path of the form
+// <owner>/<name>. The owner in a path may be given without its domain, in
+// which case it matches the models of that name, readable by the given
+// user, whose owner has that user name in any domain. If such a path
+// matches more than one model an error with the code CodeBadRequest is
+// returned. If a path does not match any model an error with the code
+// CodeNotFound is returned. Model tags and UUIDs are not checked against
+// the database.
+func (j *JIMM) ResolveModel(ctx context.Context, user *openfga.User, ref string) (names.ModelTag, error) {
+	const op = errors.Op("jimm.ResolveModel")
+
+	if names.IsValidModel(ref) {
+		return names.NewModelTag(ref), nil
+	}
+	owner, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		mt, err := names.ParseModelTag(ref)
+		if err != nil {
+			return names.ModelTag{}, errors.E(op, errors.CodeBadRequest, err)
+		}
+		return mt, nil
+	}
+	if owner == "" || !names.IsValidModelName(name) {
+		return names.ModelTag{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model path %q, expected <owner>/<name>", ref))
+	}
+
+	if strings.Contains(owner, "@") {
+		m := dbmodel.Model{
+			OwnerIdentityName: owner,
+			Name:              name,
+		}
+		if err := j.Database.GetModel(ctx, &m); err != nil {
+			return names.ModelTag{}, errors.E(op, err)
+		}
+		return m.ResourceTag(), nil
+	}
+
+	models, err := j.Database.GetModelsByName(ctx, name)
+	if err != nil {
+		return names.ModelTag{}, errors.E(op, err)
+	}
+	var matches []dbmodel.Model
+	for _, m := range models {
+		if !strings.HasPrefix(m.OwnerIdentityName, owner+"@") {
+			continue
+		}
+		if !user.JimmAdmin {
+			ok, err := user.IsModelReader(ctx, m.ResourceTag())
+			if err != nil {
+				return names.ModelTag{}, errors.E(op, err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		matches = append(matches, m)
+	}
+	switch len(matches) {
+	case 0:
+		return names.ModelTag{}, errors.E(op, errors.CodeNotFound, "model not found")
+	case 1:
+		return matches[0].ResourceTag(), nil
+	}
+	owners := make([]string, len(matches))
+	for i, m := range matches {
+		owners[i] = m.OwnerIdentityName
+	}
+	return names.ModelTag{}, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("model path %q is ambiguous, it matches the models owned by %s", ref, strings.Join(owners, ", ")))
+}
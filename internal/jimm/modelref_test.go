@@ -0,0 +1,133 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const resolveModelTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+- owner: alice@example.com
+  name: cred-2
+  cloud: test-cloud
+users:
+- username: alice@canonical.com
+  controller-access: login
+- username: alice@example.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: read
+- name: model-1
+  type: iaas
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-2
+  owner: alice@example.com
+  life: alive
+  users:
+  - user: alice@example.com
+    access: admin
+`
+
+func TestResolveModel(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, resolveModelTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	adminIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	admin := openfga.NewUser(&adminIdentity, client)
+	admin.JimmAdmin = true
+
+	mt1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	mt2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+
+	// Model tags and UUIDs are accepted as they are.
+	mt, err := j.ResolveModel(ctx, bob, mt2.String())
+	c.Assert(err, qt.IsNil)
+	c.Check(mt, qt.Equals, mt2)
+	mt, err = j.ResolveModel(ctx, bob, mt2.Id())
+	c.Assert(err, qt.IsNil)
+	c.Check(mt, qt.Equals, mt2)
+
+	// Paths with a fully qualified owner match exactly one model.
+	mt, err = j.ResolveModel(ctx, bob, "alice@example.com/model-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(mt, qt.Equals, mt2)
+	_, err = j.ResolveModel(ctx, bob, "alice@example.com/model-2")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Paths with a domainless owner only match models the user can read.
+	mt, err = j.ResolveModel(ctx, bob, "alice/model-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(mt, qt.Equals, mt1)
+	_, err = j.ResolveModel(ctx, admin, "alice/model-1")
+	c.Check(err, qt.ErrorMatches, `model path "alice/model-1" is ambiguous, it matches the models owned by alice@canonical.com, alice@example.com`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.ResolveModel(ctx, bob, "bob/model-1")
+	c.Check(err, qt.ErrorMatches, "model not found")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	_, err = j.ResolveModel(ctx, bob, "not-a-model")
+	c.Check(err, qt.ErrorMatches, `"not-a-model" is not a valid tag`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.ResolveModel(ctx, bob, "/model-1")
+	c.Check(err, qt.ErrorMatches, `invalid model path "/model-1", expected <owner>/<name>`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// hourlySnapshotRetention is how long hourly model resource
+	// snapshots are kept before they are downsampled to daily
+	// snapshots.
+	hourlySnapshotRetention = 7 * 24 * time.Hour
+
+	// defaultResourceHistoryRange is the length of the time range
+	// returned by ModelResourceHistory if no start time is given.
+	defaultResourceHistoryRange = 7 * 24 * time.Hour
+)
+
+// RecordModelResourceSnapshots records a snapshot of the machine, core
+// and unit counts of every model for the current hour, and downsamples
+// the hourly snapshots older than a week to daily snapshots. It is
+// intended to be run periodically, at least once an hour.
+func (j *JIMM) RecordModelResourceSnapshots(ctx context.Context) error {
+	const op = errors.Op("jimm.RecordModelResourceSnapshots")
+
+	now := time.Now().UTC()
+	if err := j.Database.RecordModelResourceSnapshots(ctx, now); err != nil {
+		return errors.E(op, err)
+	}
+	before := now.Add(-hourlySnapshotRetention).Truncate(24 * time.Hour)
+	if _, err := j.Database.DownsampleModelResourceSnapshots(ctx, before); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ModelResourceHistory returns series of the machine, core and unit
+// counts of the model with the given tag recorded between start and
+// end. If end is zero the current time is used, if start is zero the
+// week before end is used. The resolution is either
+// apiparams.ResourceResolutionHour or apiparams.ResourceResolutionDay;
+// if it is empty hourly series are returned for ranges of up to a week
+// and daily series otherwise. Hourly series only contain values for the
+// last week, older snapshots are only kept at a daily resolution. Only
+// model readers and JIMM administrators may view a model's history.
+func (j *JIMM) ModelResourceHistory(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, resolution string) (apiparams.ModelResourceHistory, error) {
+	const op = errors.Op("jimm.ModelResourceHistory")
+
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end.Add(-defaultResourceHistoryRange)
+	}
+	if start.After(end) {
+		return apiparams.ModelResourceHistory{}, errors.E(op, errors.CodeBadRequest, "start time is after end time")
+	}
+	switch resolution {
+	case apiparams.ResourceResolutionAuto:
+		resolution = apiparams.ResourceResolutionHour
+		if end.Sub(start) > defaultResourceHistoryRange {
+			resolution = apiparams.ResourceResolutionDay
+		}
+	case apiparams.ResourceResolutionHour, apiparams.ResourceResolutionDay:
+	default:
+		return apiparams.ModelResourceHistory{}, errors.E(op, errors.CodeBadRequest, "invalid resolution "+resolution)
+	}
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelResourceHistory{}, errors.E(op, err)
+	}
+	if !user.JimmAdmin {
+		if ok, err := user.IsModelReader(ctx, mt); !ok || err != nil {
+			return apiparams.ModelResourceHistory{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
+	}
+
+	if resolution == apiparams.ResourceResolutionDay {
+		// Include the whole of the first day, so that its value is
+		// the maximum over the day.
+		start = start.UTC().Truncate(24 * time.Hour)
+	}
+	snapshots, err := j.Database.GetModelResourceSnapshots(ctx, m.ID, start, end)
+	if err != nil {
+		return apiparams.ModelResourceHistory{}, errors.E(op, err)
+	}
+
+	history := apiparams.ModelResourceHistory{
+		ModelTag:   mt.String(),
+		Resolution: resolution,
+		Times:      []time.Time{},
+		Machines:   []int64{},
+		Cores:      []int64{},
+		Units:      []int64{},
+	}
+	for _, s := range snapshots {
+		t := s.Time.UTC()
+		if s.Resolution == dbmodel.SnapshotResolutionDay {
+			if resolution == apiparams.ResourceResolutionHour {
+				continue
+			}
+		} else if resolution == apiparams.ResourceResolutionDay {
+			t = t.Truncate(24 * time.Hour)
+		}
+		// Snapshots are ordered by time, so any snapshot in the same
+		// period as the previous value is merged into it.
+		n := len(history.Times)
+		if n > 0 && history.Times[n-1].Equal(t) {
+			history.Machines[n-1] = max(history.Machines[n-1], s.Machines)
+			history.Cores[n-1] = max(history.Cores[n-1], s.Cores)
+			history.Units[n-1] = max(history.Units[n-1], s.Units)
+			continue
+		}
+		history.Times = append(history.Times, t)
+		history.Machines = append(history.Machines, s.Machines)
+		history.Cores = append(history.Cores, s.Cores)
+		history.Units = append(history.Units, s.Units)
+	}
+	return history, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const modelSnapshotTestEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-1
+  users:
+  - user: bob@canonical.com
+    access: read
+`
+
+func TestModelResourceHistory(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelSnapshotTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	mt := m.ResourceTag()
+
+	// Record hourly snapshots over the last two days, the model grows
+	// by a machine each hour.
+	now := time.Now().UTC().Truncate(time.Hour)
+	for i := int64(0); i < 48; i++ {
+		m.Machines = i
+		m.Cores = 2 * i
+		m.Units = 3 * i
+		c.Assert(j.Database.UpdateModel(ctx, &m), qt.IsNil)
+		err := j.Database.RecordModelResourceSnapshots(ctx, now.Add(time.Duration(i-47)*time.Hour))
+		c.Assert(err, qt.IsNil)
+	}
+
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	history, err := j.ModelResourceHistory(ctx, bob, mt, now.Add(-2*time.Hour), time.Time{}, apiparams.ResourceResolutionAuto)
+	c.Assert(err, qt.IsNil)
+	c.Check(history, qt.DeepEquals, apiparams.ModelResourceHistory{
+		ModelTag:   mt.String(),
+		Resolution: apiparams.ResourceResolutionHour,
+		Times:      []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now},
+		Machines:   []int64{45, 46, 47},
+		Cores:      []int64{90, 92, 94},
+		Units:      []int64{135, 138, 141},
+	})
+
+	history, err = j.ModelResourceHistory(ctx, bob, mt, time.Time{}, time.Time{}, apiparams.ResourceResolutionDay)
+	c.Assert(err, qt.IsNil)
+	c.Check(history.Resolution, qt.Equals, apiparams.ResourceResolutionDay)
+	c.Assert(len(history.Times) >= 2, qt.IsTrue)
+	c.Check(history.Times[len(history.Times)-1], qt.DeepEquals, now.Truncate(24*time.Hour))
+	c.Check(history.Machines[len(history.Machines)-1], qt.Equals, int64(47))
+	c.Check(history.Machines, qt.HasLen, len(history.Times))
+	c.Check(history.Cores, qt.HasLen, len(history.Times))
+	c.Check(history.Units, qt.HasLen, len(history.Times))
+
+	// Long ranges default to a daily resolution.
+	history, err = j.ModelResourceHistory(ctx, bob, mt, now.AddDate(0, -1, 0), time.Time{}, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(history.Resolution, qt.Equals, apiparams.ResourceResolutionDay)
+
+	_, err = j.ModelResourceHistory(ctx, bob, mt, time.Time{}, time.Time{}, "minute")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, err = j.ModelResourceHistory(ctx, bob, mt, now, now.Add(-time.Hour), "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	_, err = j.ModelResourceHistory(ctx, charlie, mt, time.Time{}, time.Time{}, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.ModelResourceHistory(ctx, bob, names.NewModelTag("00000002-0000-0000-0000-000000000002"), time.Time{}, time.Time{}, "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+}
+
+func TestRecordModelResourceSnapshots(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelSnapshotTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	m.Machines = 3
+	c.Assert(j.Database.UpdateModel(ctx, &m), qt.IsNil)
+
+	// An hourly snapshot older than a week is downsampled.
+	old := time.Now().UTC().AddDate(0, 0, -10).Truncate(24 * time.Hour)
+	err = j.Database.RecordModelResourceSnapshots(ctx, old.Add(5*time.Hour))
+	c.Assert(err, qt.IsNil)
+
+	err = j.RecordModelResourceSnapshots(ctx)
+	c.Assert(err, qt.IsNil)
+
+	snapshots, err := j.Database.GetModelResourceSnapshots(ctx, m.ID, time.Time{}, time.Time{})
+	c.Assert(err, qt.IsNil)
+	c.Assert(snapshots, qt.HasLen, 2)
+	c.Check(snapshots[0].Resolution, qt.Equals, dbmodel.SnapshotResolutionDay)
+	c.Check(snapshots[0].Time.Equal(old), qt.IsTrue)
+	c.Check(snapshots[0].Machines, qt.Equals, int64(3))
+	c.Check(snapshots[1].Resolution, qt.Equals, dbmodel.SnapshotResolutionHour)
+	c.Check(snapshots[1].Machines, qt.Equals, int64(3))
+}
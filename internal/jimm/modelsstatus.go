@@ -0,0 +1,181 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelsStatus returns an overview of the status of the controllers
+// known to JIMM and the models they host. The overview is built from the
+// information recorded by the controller watchers, no controller is
+// contacted. If controllerName is not empty only that controller, and
+// the models it hosts, are included. Only JIMM administrators can
+// perform this operation.
+func (j *JIMM) ModelsStatus(ctx context.Context, user *openfga.User, controllerName string) (*apiparams.ModelsStatusResponse, error) {
+	const op = errors.Op("jimm.ModelsStatus")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	controllers, err := j.getControllers(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	resp := apiparams.ModelsStatusResponse{
+		Controllers: []apiparams.ControllerStatusOverview{},
+		Models:      []apiparams.ModelStatusOverview{},
+	}
+	for i := range controllers {
+		ctl := &controllers[i]
+		if controllerName != "" && ctl.Name != controllerName {
+			continue
+		}
+		cso := apiparams.ControllerStatusOverview{
+			Name:         ctl.Name,
+			Available:    !ctl.UnavailableSince.Valid,
+			Deprecated:   ctl.Deprecated,
+			AgentVersion: ctl.AgentVersion,
+		}
+		if ctl.UnavailableSince.Valid {
+			cso.UnavailableSince = &ctl.UnavailableSince.Time
+			cso.UnavailableReason = string(ctl.UnavailableReason)
+		}
+		err := j.Database.ForEachControllerModel(ctx, ctl, func(m *dbmodel.Model) error {
+			cso.ModelCount++
+			cso.MachineCount += m.Machines
+			resp.Models = append(resp.Models, apiparams.ModelStatusOverview{
+				ModelTag:           m.ResourceTag().String(),
+				Name:               m.Name,
+				Owner:              names.NewUserTag(m.OwnerIdentityName).Id(),
+				Controller:         ctl.Name,
+				Life:               m.Life,
+				Status:             m.Status.Status,
+				MachineCount:       m.Machines,
+				ContainerCount:     m.Containers,
+				UnitCount:          m.Units,
+				OfferCount:         m.OfferCount,
+				RelationCount:      m.Relations,
+				UnhealthyUnitCount: m.UnhealthyUnits,
+				WorkloadStatus:     m.WorkloadStatus,
+				Health:             workloadHealth(m.WorkloadStatus),
+				Origin:             modelOrigin(m),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		resp.Controllers = append(resp.Controllers, cso)
+	}
+	if controllerName != "" && len(resp.Controllers) == 0 {
+		return nil, errors.E(op, errors.CodeNotFound, "controller not found")
+	}
+	return &resp, nil
+}
+
+// GetModelSummary returns the summary of the model with the given tag.
+// The summary is built from the information recorded by the controller
+// watchers, no controller is contacted. If the model does not exist the
+// returned error has the code CodeNotFound. If the user does not have
+// access to the model the returned error has the code CodeUnauthorized.
+func (j *JIMM) GetModelSummary(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelSummary, error) {
+	const op = errors.Op("jimm.GetModelSummary")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelSummary{}, errors.E(op, err)
+	}
+	access := j.getModelAccess(ctx, user, mt)
+	if access == "" {
+		return apiparams.ModelSummary{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	summary := apiparams.ModelSummary{
+		ModelTag:            mt.String(),
+		Name:                m.Name,
+		Owner:               names.NewUserTag(m.OwnerIdentityName).Id(),
+		Type:                m.Type,
+		Cloud:               m.CloudRegion.Cloud.Name,
+		CloudRegion:         m.CloudRegion.Name,
+		Controller:          m.Controller.Name,
+		ControllerAvailable: !m.Controller.UnavailableSince.Valid,
+		AgentVersion:        m.Status.Version,
+		Access:              access,
+		Life:                m.Life,
+		Status:              m.Status.Status,
+		StatusInfo:          m.Status.Info,
+		CoreCount:           m.Cores,
+		MachineCount:        m.Machines,
+		ContainerCount:      m.Containers,
+		UnitCount:           m.Units,
+		ActiveUnitCount:     m.ActiveUnits,
+		BlockedUnitCount:    m.BlockedUnits,
+		ErrorUnitCount:      m.ErrorUnits,
+		OfferCount:          m.OfferCount,
+		RelationCount:       m.Relations,
+		WorkloadStatus:      m.WorkloadStatus,
+		Health:              workloadHealth(m.WorkloadStatus),
+		Origin:              modelOrigin(&m),
+	}
+	if m.CloudCredentialID != 0 {
+		summary.CloudCredential = m.CloudCredential.ResourceTag().String()
+	}
+	if m.Status.Since.Valid {
+		summary.StatusSince = &m.Status.Since.Time
+	}
+	if m.LastUnitChangeAt.Valid {
+		summary.LastUnitChange = &m.LastUnitChangeAt.Time
+	}
+	if m.LastConnectionAt.Valid {
+		summary.LastConnection = &m.LastConnectionAt.Time
+	}
+
+	migrations, err := j.Database.ListModelMigrations(ctx, m.ID, true)
+	if err != nil {
+		return apiparams.ModelSummary{}, errors.E(op, err)
+	}
+	if len(migrations) > 0 {
+		mm := migrations[0].ToAPIModelMigration()
+		summary.Migration = &mm
+	}
+	return summary, nil
+}
+
+// modelOrigin returns the origin of the given model, or nil if the
+// origin of the model was not recorded.
+func modelOrigin(m *dbmodel.Model) *apiparams.ModelOrigin {
+	if m.Origin.Kind == "" {
+		return nil
+	}
+	return &apiparams.ModelOrigin{
+		Kind:     m.Origin.Kind,
+		Identity: m.Origin.IdentityName,
+		Time:     m.Origin.Time.Time.UTC(),
+	}
+}
+
+// workloadHealth returns the workload health of a model with the given
+// workload status summary. A model is unhealthy if any unit is in error
+// or blocked, and degraded if any unit is not yet active.
+func workloadHealth(workloadStatus string) string {
+	switch status.Status(workloadStatus) {
+	case status.Error, status.Blocked:
+		return apiparams.WorkloadUnhealthy
+	case status.Waiting, status.Maintenance, status.Unknown:
+		return apiparams.WorkloadDegraded
+	default:
+		return apiparams.WorkloadHealthy
+	}
+}
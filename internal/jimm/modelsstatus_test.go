@@ -0,0 +1,178 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestModelsStatus(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// The controllers must not be contacted.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	m.Machines = 2
+	m.Containers = 1
+	m.Units = 4
+	m.OfferCount = 1
+	m.Relations = 3
+	m.WorkloadStatus = "waiting"
+	err = j.Database.UpdateModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	unavailableSince := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctl := env.Controller("controller-1").DBObject(c, j.Database)
+	ctl.UnavailableSince = sql.NullTime{Time: unavailableSince, Valid: true}
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	resp, err := j.ModelsStatus(ctx, diane, "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(resp.Controllers, qt.HasLen, 1)
+	c.Check(resp.Controllers[0].Name, qt.Equals, "controller-1")
+	c.Check(resp.Controllers[0].Available, qt.IsFalse)
+	c.Assert(resp.Controllers[0].UnavailableSince, qt.Not(qt.IsNil))
+	c.Check(resp.Controllers[0].UnavailableSince.Equal(unavailableSince), qt.IsTrue)
+	c.Check(resp.Controllers[0].ModelCount, qt.Equals, int64(1))
+	c.Check(resp.Controllers[0].MachineCount, qt.Equals, int64(2))
+	c.Check(resp.Models, qt.DeepEquals, []apiparams.ModelStatusOverview{{
+		ModelTag:       m.ResourceTag().String(),
+		Name:           "model-1",
+		Owner:          "alice@canonical.com",
+		Controller:     "controller-1",
+		Life:           m.Life,
+		Status:         m.Status.Status,
+		MachineCount:   2,
+		ContainerCount: 1,
+		UnitCount:      4,
+		OfferCount:     1,
+		RelationCount:  3,
+		WorkloadStatus: "waiting",
+		Health:         apiparams.WorkloadDegraded,
+	}})
+
+	resp, err = j.ModelsStatus(ctx, diane, "controller-1")
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Controllers, qt.HasLen, 1)
+	c.Check(resp.Models, qt.HasLen, 1)
+
+	_, err = j.ModelsStatus(ctx, diane, "no-such-controller")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	_, err = j.ModelsStatus(ctx, openfga.NewUser(&aliceIdentity, client), "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
+
+func TestGetModelSummary(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// The controllers must not be contacted.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	m.Machines = 2
+	m.Units = 3
+	m.ActiveUnits = 2
+	m.BlockedUnits = 1
+	m.Relations = 1
+	m.WorkloadStatus = "blocked"
+	err = j.Database.UpdateModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	ctl := env.Controller("controller-1").DBObject(c, j.Database)
+	err = j.Database.AddModelMigration(ctx, &dbmodel.ModelMigration{
+		ModelID:            m.ID,
+		SourceControllerID: ctl.ID,
+		TargetControllerID: ctl.ID,
+		MigrationID:        "migration-1",
+		InitiatedBy:        "diane@canonical.com",
+		Status:             dbmodel.MigrationRunning,
+	})
+	c.Assert(err, qt.IsNil)
+
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+
+	summary, err := j.GetModelSummary(ctx, charlie, m.ResourceTag())
+	c.Assert(err, qt.IsNil)
+	c.Check(summary.ModelTag, qt.Equals, m.ResourceTag().String())
+	c.Check(summary.Name, qt.Equals, "model-1")
+	c.Check(summary.Owner, qt.Equals, "alice@canonical.com")
+	c.Check(summary.Cloud, qt.Equals, "test-cloud")
+	c.Check(summary.CloudRegion, qt.Equals, "test-cloud-region")
+	c.Check(summary.CloudCredential, qt.Equals, "cloudcred-test-cloud_alice@canonical.com_cred-1")
+	c.Check(summary.Controller, qt.Equals, "controller-1")
+	c.Check(summary.ControllerAvailable, qt.IsTrue)
+	c.Check(summary.Access, qt.Equals, "read")
+	c.Check(summary.MachineCount, qt.Equals, int64(2))
+	c.Check(summary.UnitCount, qt.Equals, int64(3))
+	c.Check(summary.ActiveUnitCount, qt.Equals, int64(2))
+	c.Check(summary.BlockedUnitCount, qt.Equals, int64(1))
+	c.Check(summary.RelationCount, qt.Equals, int64(1))
+	c.Check(summary.Health, qt.Equals, apiparams.WorkloadUnhealthy)
+	c.Assert(summary.Migration, qt.Not(qt.IsNil))
+	c.Check(summary.Migration.MigrationID, qt.Equals, "migration-1")
+	c.Check(summary.Migration.Status, qt.Equals, dbmodel.MigrationRunning)
+
+	eveIdentity, err := dbmodel.NewIdentity("eve@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.GetModelSummary(ctx, openfga.NewUser(eveIdentity, client), m.ResourceTag())
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
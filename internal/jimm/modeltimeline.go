@@ -0,0 +1,234 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// timelineAuditMethods maps the facade methods recorded in the audit log
+// that are significant to a model's history to the function that
+// extracts the model events from the request parameters.
+var timelineAuditMethods = map[string]func(json.RawMessage, string) []apiparams.ModelTimelineEvent{
+	"ChangeModelCredential": credentialChangedEvents,
+	"ModifyModelAccess":     accessChangedEvents,
+	"MigrateModel":          migrationRequestedEvents,
+	"DestroyModels":         destroyRequestedEvents,
+}
+
+// ModelTimeline returns the significant events in the history of the
+// model with the given tag, ordered from the oldest to the most recent.
+// Events are gathered from the audit log and from the model and
+// controller status recorded by the watcher. If start or end are not
+// zero only events in that time range are returned. If limit is greater
+// than zero only the most recent limit events are returned. Only model
+// administrators and JIMM administrators may view a model's timeline.
+func (j *JIMM) ModelTimeline(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, limit int) (apiparams.ModelTimeline, error) {
+	const op = errors.Op("jimm.ModelTimeline")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return apiparams.ModelTimeline{}, errors.E(op, err)
+	}
+	isModelAdmin, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return apiparams.ModelTimeline{}, errors.E(op, err)
+	}
+	if !user.JimmAdmin && !isModelAdmin {
+		return apiparams.ModelTimeline{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	events := []apiparams.ModelTimelineEvent{originEvent(&m)}
+	if m.Status.Since.Valid && !isAvailableStatus(m.Status.Status) {
+		events = append(events, apiparams.ModelTimelineEvent{
+			Time:   m.Status.Since.Time,
+			Type:   apiparams.ModelEventAvailability,
+			Detail: "model status " + m.Status.Status + statusInfo(m.Status.Info),
+		})
+	}
+	if m.Controller.UnavailableSince.Valid {
+		events = append(events, apiparams.ModelTimelineEvent{
+			Time:   m.Controller.UnavailableSince.Time,
+			Type:   apiparams.ModelEventAvailability,
+			Detail: "controller " + m.Controller.Name + " unavailable",
+		})
+	}
+
+	for method, f := range timelineAuditMethods {
+		filter := db.AuditLogFilter{
+			Start:  start,
+			End:    end,
+			Method: method,
+		}
+		err := j.Database.ForEachAuditLogEntry(ctx, filter, func(ale *dbmodel.AuditLogEntry) error {
+			if ale.IsResponse || len(ale.Params) == 0 {
+				return nil
+			}
+			for _, e := range f(json.RawMessage(ale.Params), mt.String()) {
+				e.Time = ale.Time
+				e.Actor = ale.IdentityTag
+				events = append(events, e)
+			}
+			return nil
+		})
+		if err != nil {
+			return apiparams.ModelTimeline{}, errors.E(op, err)
+		}
+	}
+
+	timeline := apiparams.ModelTimeline{
+		ModelTag: mt.String(),
+		Events:   []apiparams.ModelTimelineEvent{},
+	}
+	for _, e := range events {
+		if !start.IsZero() && e.Time.Before(start) {
+			continue
+		}
+		if !end.IsZero() && e.Time.After(end) {
+			continue
+		}
+		e.Time = e.Time.UTC()
+		timeline.Events = append(timeline.Events, e)
+	}
+	sort.SliceStable(timeline.Events, func(i, j int) bool {
+		return timeline.Events[i].Time.Before(timeline.Events[j].Time)
+	})
+	if limit > 0 && len(timeline.Events) > limit {
+		timeline.Events = timeline.Events[len(timeline.Events)-limit:]
+	}
+	return timeline, nil
+}
+
+// originEvent returns the timeline event recording how the given model
+// came to be managed by JIMM. Models added before origins were recorded
+// are reported as created by their owner.
+func originEvent(m *dbmodel.Model) apiparams.ModelTimelineEvent {
+	if m.Origin.Kind == "" {
+		return apiparams.ModelTimelineEvent{
+			Time:   m.CreatedAt,
+			Type:   apiparams.ModelEventCreated,
+			Actor:  names.NewUserTag(m.OwnerIdentityName).String(),
+			Detail: "model created on controller " + m.Controller.Name,
+		}
+	}
+	e := apiparams.ModelTimelineEvent{
+		Time: m.CreatedAt,
+		Type: apiparams.ModelEventImported,
+	}
+	if m.Origin.Time.Valid {
+		e.Time = m.Origin.Time.Time
+	}
+	if m.Origin.IdentityName != "" {
+		e.Actor = names.NewUserTag(m.Origin.IdentityName).String()
+	}
+	switch m.Origin.Kind {
+	case dbmodel.ModelOriginCreated:
+		e.Type = apiparams.ModelEventCreated
+		e.Detail = "model created on controller " + m.Controller.Name
+	case dbmodel.ModelOriginAdopted:
+		e.Detail = "model adopted from controller " + m.Controller.Name
+	case dbmodel.ModelOriginMigrated:
+		e.Detail = "model migrated in to controller " + m.Controller.Name
+	case dbmodel.ModelOriginRestored:
+		e.Detail = "model restored from backup on controller " + m.Controller.Name
+	default:
+		e.Detail = "model " + m.Origin.Kind + " on controller " + m.Controller.Name
+	}
+	return e
+}
+
+func isAvailableStatus(s string) bool {
+	return s == "" || s == "available"
+}
+
+func statusInfo(info string) string {
+	if info == "" {
+		return ""
+	}
+	return ": " + info
+}
+
+func credentialChangedEvents(params json.RawMessage, modelTag string) []apiparams.ModelTimelineEvent {
+	var req jujuparams.ChangeModelCredentialsParams
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil
+	}
+	var events []apiparams.ModelTimelineEvent
+	for _, mc := range req.Models {
+		if mc.ModelTag != modelTag {
+			continue
+		}
+		events = append(events, apiparams.ModelTimelineEvent{
+			Type:   apiparams.ModelEventCredentialChanged,
+			Detail: "credential changed to " + mc.CloudCredentialTag,
+		})
+	}
+	return events
+}
+
+func accessChangedEvents(params json.RawMessage, modelTag string) []apiparams.ModelTimelineEvent {
+	var req jujuparams.ModifyModelAccessRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil
+	}
+	var events []apiparams.ModelTimelineEvent
+	for _, change := range req.Changes {
+		if change.ModelTag != modelTag {
+			continue
+		}
+		events = append(events, apiparams.ModelTimelineEvent{
+			Type:   apiparams.ModelEventAccessChanged,
+			Detail: string(change.Action) + " " + string(change.Access) + " access for " + change.UserTag,
+		})
+	}
+	return events
+}
+
+func migrationRequestedEvents(params json.RawMessage, modelTag string) []apiparams.ModelTimelineEvent {
+	var req apiparams.MigrateModelRequest
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil
+	}
+	var events []apiparams.ModelTimelineEvent
+	for _, spec := range req.Specs {
+		if spec.ModelTag != modelTag {
+			continue
+		}
+		events = append(events, apiparams.ModelTimelineEvent{
+			Type:   apiparams.ModelEventMigrationRequested,
+			Detail: "migration to controller " + spec.TargetController + " requested",
+		})
+	}
+	return events
+}
+
+func destroyRequestedEvents(params json.RawMessage, modelTag string) []apiparams.ModelTimelineEvent {
+	var req jujuparams.DestroyModelsParams
+	if err := json.Unmarshal(params, &req); err != nil {
+		return nil
+	}
+	var events []apiparams.ModelTimelineEvent
+	for _, dm := range req.Models {
+		if dm.ModelTag != modelTag {
+			continue
+		}
+		events = append(events, apiparams.ModelTimelineEvent{
+			Type:   apiparams.ModelEventDestroyRequested,
+			Detail: "model destruction requested",
+		})
+	}
+	return events
+}
@@ -0,0 +1,317 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestModelTimeline(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Round(time.Millisecond)
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, func() time.Time { return now }),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	u, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	c.Assert(j.Database.DB.Create(u).Error, qt.IsNil)
+
+	cloud := dbmodel.Cloud{
+		Name: "test-cloud",
+		Type: "test-provider",
+		Regions: []dbmodel.CloudRegion{{
+			Name: "test-region-1",
+		}},
+	}
+	c.Assert(j.Database.DB.Create(&cloud).Error, qt.IsNil)
+
+	controller := dbmodel.Controller{
+		Name:        "test-controller-1",
+		UUID:        "00000000-0000-0000-0000-0000-0000000000001",
+		CloudName:   "test-cloud",
+		CloudRegion: "test-region-1",
+	}
+	err = j.Database.AddController(ctx, &controller)
+	c.Assert(err, qt.IsNil)
+
+	cred := dbmodel.CloudCredential{
+		Name:              "test-credential-1",
+		CloudName:         cloud.Name,
+		OwnerIdentityName: u.Name,
+		AuthType:          "empty",
+	}
+	err = j.Database.SetCloudCredential(ctx, &cred)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{
+		Name: "model-1",
+		UUID: sql.NullString{
+			String: "00000000-0000-0000-0000-0000-0000000000002",
+			Valid:  true,
+		},
+		OwnerIdentityName: u.Name,
+		ControllerID:      controller.ID,
+		CloudRegionID:     cloud.Regions[0].ID,
+		CloudCredentialID: cred.ID,
+		Status: dbmodel.Status{
+			Status: "suspended",
+			Info:   "invalid credential",
+			Since: sql.NullTime{
+				Time:  now.Add(4 * time.Minute),
+				Valid: true,
+			},
+		},
+	}
+	err = j.Database.AddModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	mt := m.ResourceTag().String()
+	otherModelTag := "model-00000000-0000-0000-0000-0000-0000000000099"
+
+	entries := []dbmodel.AuditLogEntry{{
+		Time:         now.Add(time.Minute),
+		FacadeName:   "ModelManager",
+		FacadeMethod: "ModifyModelAccess",
+		IdentityTag:  "user-alice@canonical.com",
+		Params:       []byte(`{"changes":[{"user-tag":"user-bob@canonical.com","action":"grant","access":"read","model-tag":"` + mt + `"},{"user-tag":"user-bob@canonical.com","action":"grant","access":"read","model-tag":"` + otherModelTag + `"}]}`),
+	}, {
+		Time:         now.Add(time.Minute),
+		FacadeName:   "ModelManager",
+		FacadeMethod: "ModifyModelAccess",
+		IdentityTag:  "user-alice@canonical.com",
+		IsResponse:   true,
+		Errors:       []byte(`{"results":[]}`),
+	}, {
+		Time:         now.Add(2 * time.Minute),
+		FacadeName:   "ModelManager",
+		FacadeMethod: "ChangeModelCredential",
+		IdentityTag:  "user-alice@canonical.com",
+		Params:       []byte(`{"model-credentials":[{"model-tag":"` + mt + `","credential-tag":"cloudcred-test-cloud_alice@canonical.com_cred-2"}]}`),
+	}, {
+		Time:         now.Add(3 * time.Minute),
+		FacadeName:   "JIMM",
+		FacadeMethod: "MigrateModel",
+		IdentityTag:  "user-alice@canonical.com",
+		Params:       []byte(`{"specs":[{"model-tag":"` + mt + `","target-controller":"test-controller-2"}]}`),
+	}, {
+		Time:         now.Add(5 * time.Minute),
+		FacadeName:   "ModelManager",
+		FacadeMethod: "DestroyModels",
+		IdentityTag:  "user-alice@canonical.com",
+		Params:       []byte(`{"models":[{"model-tag":"` + otherModelTag + `"}]}`),
+	}, {
+		Time:         now.Add(6 * time.Minute),
+		FacadeName:   "ModelManager",
+		FacadeMethod: "DestroyModels",
+		IdentityTag:  "user-alice@canonical.com",
+		Params:       []byte(`{"models":[{"model-tag":"` + mt + `"}]}`),
+	}}
+	for i := range entries {
+		err = j.Database.AddAuditLogEntry(ctx, &entries[i])
+		c.Assert(err, qt.IsNil)
+	}
+
+	allEvents := []apiparams.ModelTimelineEvent{{
+		Time:   now,
+		Type:   apiparams.ModelEventCreated,
+		Actor:  "user-alice@canonical.com",
+		Detail: "model created on controller test-controller-1",
+	}, {
+		Time:   now.Add(time.Minute),
+		Type:   apiparams.ModelEventAccessChanged,
+		Actor:  "user-alice@canonical.com",
+		Detail: "grant read access for user-bob@canonical.com",
+	}, {
+		Time:   now.Add(2 * time.Minute),
+		Type:   apiparams.ModelEventCredentialChanged,
+		Actor:  "user-alice@canonical.com",
+		Detail: "credential changed to cloudcred-test-cloud_alice@canonical.com_cred-2",
+	}, {
+		Time:   now.Add(3 * time.Minute),
+		Type:   apiparams.ModelEventMigrationRequested,
+		Actor:  "user-alice@canonical.com",
+		Detail: "migration to controller test-controller-2 requested",
+	}, {
+		Time:   now.Add(4 * time.Minute),
+		Type:   apiparams.ModelEventAvailability,
+		Detail: "model status suspended: invalid credential",
+	}, {
+		Time:   now.Add(6 * time.Minute),
+		Type:   apiparams.ModelEventDestroyRequested,
+		Actor:  "user-alice@canonical.com",
+		Detail: "model destruction requested",
+	}}
+
+	admin := openfga.NewUser(u, client)
+	admin.JimmAdmin = true
+
+	tests := []struct {
+		about          string
+		user           *openfga.User
+		start          time.Time
+		end            time.Time
+		limit          int
+		expectedEvents []apiparams.ModelTimelineEvent
+		expectedError  string
+	}{{
+		about:          "full timeline",
+		user:           admin,
+		expectedEvents: allEvents,
+	}, {
+		about:          "time range",
+		user:           admin,
+		start:          now.Add(time.Minute),
+		end:            now.Add(3 * time.Minute),
+		expectedEvents: allEvents[1:4],
+	}, {
+		about:          "limit",
+		user:           admin,
+		limit:          2,
+		expectedEvents: allEvents[4:],
+	}, {
+		about:         "non-admin user",
+		user:          openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, client),
+		expectedError: "unauthorized",
+	}}
+
+	for _, test := range tests {
+		test := test
+		c.Run(test.about, func(c *qt.C) {
+			timeline, err := j.ModelTimeline(ctx, test.user, m.ResourceTag(), test.start, test.end, test.limit)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Check(timeline.ModelTag, qt.Equals, mt)
+			c.Check(timeline.Events, qt.DeepEquals, test.expectedEvents)
+		})
+	}
+}
+
+func TestModelTimelineOrigin(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	now := time.Now().UTC().Round(time.Millisecond)
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, func() time.Time { return now }),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelTokenTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	admin := openfga.NewUser(&dbmodel.Identity{Name: "admin@canonical.com"}, client)
+	admin.JimmAdmin = true
+
+	var m dbmodel.Model
+	m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+
+	tests := []struct {
+		origin        dbmodel.ModelOrigin
+		expectedEvent apiparams.ModelTimelineEvent
+	}{{
+		expectedEvent: apiparams.ModelTimelineEvent{
+			Time:   now,
+			Type:   apiparams.ModelEventCreated,
+			Actor:  "user-alice@canonical.com",
+			Detail: "model created on controller controller-1",
+		},
+	}, {
+		origin: dbmodel.ModelOrigin{
+			Kind:         dbmodel.ModelOriginCreated,
+			IdentityName: "bob@canonical.com",
+			Time:         sql.NullTime{Time: now.Add(time.Minute), Valid: true},
+		},
+		expectedEvent: apiparams.ModelTimelineEvent{
+			Time:   now.Add(time.Minute),
+			Type:   apiparams.ModelEventCreated,
+			Actor:  "user-bob@canonical.com",
+			Detail: "model created on controller controller-1",
+		},
+	}, {
+		origin: dbmodel.ModelOrigin{
+			Kind:         dbmodel.ModelOriginAdopted,
+			IdentityName: "admin@canonical.com",
+			Time:         sql.NullTime{Time: now.Add(2 * time.Minute), Valid: true},
+		},
+		expectedEvent: apiparams.ModelTimelineEvent{
+			Time:   now.Add(2 * time.Minute),
+			Type:   apiparams.ModelEventImported,
+			Actor:  "user-admin@canonical.com",
+			Detail: "model adopted from controller controller-1",
+		},
+	}, {
+		origin: dbmodel.ModelOrigin{
+			Kind:         dbmodel.ModelOriginMigrated,
+			IdentityName: "admin@canonical.com",
+			Time:         sql.NullTime{Time: now.Add(3 * time.Minute), Valid: true},
+		},
+		expectedEvent: apiparams.ModelTimelineEvent{
+			Time:   now.Add(3 * time.Minute),
+			Type:   apiparams.ModelEventImported,
+			Actor:  "user-admin@canonical.com",
+			Detail: "model migrated in to controller controller-1",
+		},
+	}, {
+		origin: dbmodel.ModelOrigin{
+			Kind:         dbmodel.ModelOriginRestored,
+			IdentityName: "admin@canonical.com",
+			Time:         sql.NullTime{Time: now.Add(4 * time.Minute), Valid: true},
+		},
+		expectedEvent: apiparams.ModelTimelineEvent{
+			Time:   now.Add(4 * time.Minute),
+			Type:   apiparams.ModelEventImported,
+			Actor:  "user-admin@canonical.com",
+			Detail: "model restored from backup on controller controller-1",
+		},
+	}}
+	for _, test := range tests {
+		m.Origin = test.origin
+		err := j.Database.UpdateModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+
+		timeline, err := j.ModelTimeline(ctx, admin, m.ResourceTag(), time.Time{}, time.Time{}, 0)
+		c.Assert(err, qt.IsNil)
+		c.Assert(timeline.Events, qt.Not(qt.HasLen), 0)
+		c.Check(timeline.Events[0], qt.DeepEquals, test.expectedEvent)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	"github.com/canonical/jimm/v3/internal/servermon"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// ModelTokenPrefix is the prefix of all model tokens, it distinguishes
+// model tokens from the session tokens minted by JIMM.
+const ModelTokenPrefix = "jimm-model-token-"
+
+// CreateModelToken creates a long-lived token granting the given access to
+// the model with the given tag. The token authenticates as an identity of
+// its own, which is only granted access to the model, and is used in
+// place of a session token when logging in. Only model administrators may
+// create tokens, and a model token cannot be used to create another. The
+// token is returned along with its details and cannot be retrieved again.
+func (j *JIMM) CreateModelToken(ctx context.Context, user *openfga.User, mt names.ModelTag, access, description string) (apiparams.ModelToken, string, error) {
+	const op = errors.Op("jimm.CreateModelToken")
+
+	if dbmodel.IsModelTokenIdentity(user.Name) {
+		return apiparams.ModelToken{}, "", errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+
+	relation, err := ToModelRelation(access)
+	if err != nil {
+		return apiparams.ModelToken{}, "", errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid model access %q", access))
+	}
+	m, err := j.getModelTokenModel(ctx, user, mt)
+	if err != nil {
+		return apiparams.ModelToken{}, "", errors.E(op, err)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return apiparams.ModelToken{}, "", errors.E(op, err)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+	hash := sha256.Sum256([]byte(secret))
+
+	t := dbmodel.ModelToken{
+		ModelID:     m.ID,
+		Access:      access,
+		Description: description,
+		CreatedBy:   user.Name,
+		SecretHash:  hash[:],
+	}
+	if err := j.Database.AddModelToken(ctx, &t); err != nil {
+		return apiparams.ModelToken{}, "", errors.E(op, err)
+	}
+	t.Model = *m
+
+	identity, err := dbmodel.NewIdentity(t.IdentityName())
+	if err != nil {
+		return apiparams.ModelToken{}, "", errors.E(op, err)
+	}
+	if err := j.Database.GetIdentity(ctx, identity); err != nil {
+		return apiparams.ModelToken{}, "", errors.E(op, err)
+	}
+	if err := openfga.NewUser(identity, j.OpenFGAClient).SetModelAccess(ctx, mt, relation); err != nil {
+		if derr := j.Database.DeleteModelToken(ctx, &t); derr != nil {
+			zapctx.Error(ctx, "failed to remove model token", zaputil.Error(derr))
+		}
+		return apiparams.ModelToken{}, "", errors.E(op, err)
+	}
+	return t.ToAPIModelToken(), fmt.Sprintf("%s%d.%s", ModelTokenPrefix, t.ID, secret), nil
+}
+
+// ListModelTokens returns the tokens granting access to the model with
+// the given tag. Only model administrators may list tokens.
+func (j *JIMM) ListModelTokens(ctx context.Context, user *openfga.User, mt names.ModelTag) ([]apiparams.ModelToken, error) {
+	const op = errors.Op("jimm.ListModelTokens")
+
+	m, err := j.getModelTokenModel(ctx, user, mt)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	tokens, err := j.Database.ListModelTokens(ctx, m.ID)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.ModelToken, len(tokens))
+	for i, t := range tokens {
+		result[i] = t.ToAPIModelToken()
+	}
+	return result, nil
+}
+
+// RevokeModelToken revokes the token with the given ID granting access to
+// the model with the given tag. The token can no longer be used to log in
+// and the access granted to it is removed. Only model administrators may
+// revoke tokens.
+func (j *JIMM) RevokeModelToken(ctx context.Context, user *openfga.User, mt names.ModelTag, id uint) error {
+	const op = errors.Op("jimm.RevokeModelToken")
+
+	m, err := j.getModelTokenModel(ctx, user, mt)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	t := dbmodel.ModelToken{
+		ID: id,
+	}
+	if err := j.Database.GetModelToken(ctx, &t); err != nil {
+		return errors.E(op, err)
+	}
+	if t.ModelID != m.ID {
+		return errors.E(op, errors.CodeNotFound, "model token not found")
+	}
+
+	identity, err := dbmodel.NewIdentity(t.IdentityName())
+	if err != nil {
+		return errors.E(op, err)
+	}
+	u := openfga.NewUser(identity, j.OpenFGAClient)
+	if err := u.UnsetModelAccess(ctx, mt, ofganames.ReaderRelation, ofganames.WriterRelation, ofganames.AdministratorRelation); err != nil {
+		return errors.E(op, err)
+	}
+	j.Cache.InvalidateModelAccess(mt)
+	if err := j.Database.DeleteModelToken(ctx, &t); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// getModelTokenModel returns the model with the given tag, if the given
+// user is an administrator of the model.
+func (j *JIMM) getModelTokenModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (*dbmodel.Model, error) {
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return nil, err
+	}
+	if j.getModelAccess(ctx, user, mt) != "admin" {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	return &m, nil
+}
+
+// loginWithModelToken verifies the given model token and returns the
+// identity the token authenticates as, limited to the token's model.
+func (j *JIMM) loginWithModelToken(ctx context.Context, token string) (_ *openfga.User, err error) {
+	const op = errors.Op("jimm.loginWithModelToken")
+	defer servermon.DurationObserver(servermon.AuthenticationDurationHistogram, "ModelToken")()
+	defer func() {
+		if err != nil {
+			servermon.AuthenticationFailCount.WithLabelValues("ModelToken").Inc()
+		} else {
+			servermon.AuthenticationSuccessCount.WithLabelValues("ModelToken").Inc()
+		}
+	}()
+
+	idStr, secret, ok := strings.Cut(strings.TrimPrefix(token, ModelTokenPrefix), ".")
+	id, err := strconv.ParseUint(idStr, 10, 0)
+	if !ok || err != nil || id == 0 {
+		return nil, errors.E(op, errors.CodeUnauthorized, "invalid model token")
+	}
+	t := dbmodel.ModelToken{
+		ID: uint(id),
+	}
+	if err := j.Database.GetModelToken(ctx, &t); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return nil, errors.E(op, errors.CodeUnauthorized, "invalid model token")
+		}
+		return nil, errors.E(op, err)
+	}
+	hash := sha256.Sum256([]byte(secret))
+	if subtle.ConstantTimeCompare(hash[:], t.SecretHash) != 1 {
+		return nil, errors.E(op, errors.CodeUnauthorized, "invalid model token")
+	}
+	user, err := j.UserLogin(ctx, t.IdentityName())
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	user.ModelScope = t.Model.ResourceTag()
+	return user, nil
+}
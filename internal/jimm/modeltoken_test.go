@@ -0,0 +1,172 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+const modelTokenTestEnv = `clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-cloud-region
+cloud-credentials:
+- owner: alice@canonical.com
+  name: cred-1
+  cloud: test-cloud
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test-cloud
+  region: test-cloud-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+  - user: bob@canonical.com
+    access: write
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  controller: controller-1
+  cloud: test-cloud
+  region: test-cloud-region
+  cloud-credential: cred-1
+  owner: alice@canonical.com
+  life: alive
+  users:
+  - user: alice@canonical.com
+    access: admin
+users:
+- username: bob@canonical.com
+  controller-access: login
+`
+
+func TestModelTokens(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelTokenTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	model1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	model2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+
+	// Only model administrators may manage tokens.
+	_, _, err = j.CreateModelToken(ctx, bob, model1, "write", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, _, err = j.CreateModelToken(ctx, alice, model1, "superuser", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	_, _, err = j.CreateModelToken(ctx, alice, names.NewModelTag("00000002-0000-0000-0000-000000000009"), "write", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	mt, token, err := j.CreateModelToken(ctx, alice, model1, "write", "staging deployments")
+	c.Assert(err, qt.IsNil)
+	c.Check(mt.ModelTag, qt.Equals, model1.String())
+	c.Check(mt.Access, qt.Equals, "write")
+	c.Check(mt.Description, qt.Equals, "staging deployments")
+	c.Check(mt.CreatedBy, qt.Equals, "alice@canonical.com")
+	c.Check(token, qt.Matches, jimm.ModelTokenPrefix+`[0-9]+\..+`)
+
+	// The token logs in as its own identity, which only has access to
+	// the token's model.
+	u, err := j.LoginWithSessionToken(ctx, token)
+	c.Assert(err, qt.IsNil)
+	c.Check(u.Name, qt.Equals, mt.Identity)
+	access, err := j.GetUserModelAccess(ctx, u, model1)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "write")
+	access, err = j.GetUserModelAccess(ctx, u, model2)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "")
+
+	// Access granted to all users does not extend to the token.
+	cloud := names.NewCloudTag("test-cloud")
+	err = j.EveryoneUser().SetCloudAccess(ctx, cloud, ofganames.CanAddModelRelation)
+	c.Assert(err, qt.IsNil)
+	err = j.EveryoneUser().SetModelAccess(ctx, model2, ofganames.ReaderRelation)
+	c.Assert(err, qt.IsNil)
+	allowed, err := bob.IsAllowedAddModel(ctx, cloud)
+	c.Assert(err, qt.IsNil)
+	c.Check(allowed, qt.IsTrue)
+	allowed, err = u.IsAllowedAddModel(ctx, cloud)
+	c.Assert(err, qt.IsNil)
+	c.Check(allowed, qt.IsFalse)
+	access, err = j.GetUserModelAccess(ctx, u, model2)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "")
+	modelUUIDs, err := u.ListModels(ctx, ofganames.ReaderRelation)
+	c.Assert(err, qt.IsNil)
+	c.Check(modelUUIDs, qt.DeepEquals, []string{model1.Id()})
+
+	// A token cannot be used to create another token.
+	_, _, err = j.CreateModelToken(ctx, u, model1, "read", "")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.LoginWithSessionToken(ctx, token+"x")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.LoginWithSessionToken(ctx, jimm.ModelTokenPrefix+"not-a-token")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.ListModelTokens(ctx, bob, model1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	tokens, err := j.ListModelTokens(ctx, alice, model1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(tokens, qt.HasLen, 1)
+	c.Check(tokens[0].ID, qt.Equals, mt.ID)
+	c.Check(tokens[0].Identity, qt.Equals, mt.Identity)
+	tokens, err = j.ListModelTokens(ctx, alice, model2)
+	c.Assert(err, qt.IsNil)
+	c.Check(tokens, qt.HasLen, 0)
+
+	err = j.RevokeModelToken(ctx, bob, model1, mt.ID)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RevokeModelToken(ctx, alice, model2, mt.ID)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = j.RevokeModelToken(ctx, alice, model1, mt.ID)
+	c.Assert(err, qt.IsNil)
+
+	_, err = j.LoginWithSessionToken(ctx, token)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	access, err = j.GetUserModelAccess(ctx, u, model1)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "")
+}
@@ -3,6 +3,7 @@ package jimm
 
 import (
 	"context"
+	"time"
 
 	"github.com/juju/zaputil/zapctx"
 	"github.com/prometheus/client_golang/prometheus"
@@ -12,10 +13,20 @@ import (
 	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
+// CertificateExpiryWarningPeriod is the period before a controller's
+// certificate expires in which it is reported as expiring, if no period
+// is configured.
+const CertificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
 // UpdateMetrics updates metrics for the total numbers of controllers
 // managed by JIMM as well as how many model each controller manages.
+// It also records the expiry time of each controller's certificate and
+// logs a warning for certificates that expire within the certificate
+// expiry warning period, and records the reason each unavailable
+// controller is unavailable.
 func (j *JIMM) UpdateMetrics(ctx context.Context) {
 	controllerCount := 0
+	now := time.Now()
 	err := j.Database.ForEachController(ctx, func(c *dbmodel.Controller) error {
 		controllerCount++
 		modelGauge, err := servermon.ModelCount.GetMetricWith(prometheus.Labels{"controller": c.Name})
@@ -29,6 +40,20 @@ func (j *JIMM) UpdateMetrics(ctx context.Context) {
 			return err
 		}
 		modelGauge.Set(float64(count))
+		if c.CertificateExpiry.Valid {
+			expiry := c.CertificateExpiry.Time
+			servermon.ControllerCertificateExpiry.WithLabelValues(c.Name).Set(float64(expiry.Unix()))
+			expiring := 0.0
+			if j.certificateExpiring(c, now) {
+				zapctx.Warn(ctx, "controller certificate expires soon", zap.String("controller", c.Name), zap.Time("expiry", expiry))
+				expiring = 1
+			}
+			servermon.ControllerCertificateExpiring.WithLabelValues(c.Name).Set(expiring)
+		}
+		servermon.ControllerUnavailable.DeletePartialMatch(prometheus.Labels{"controller": c.Name})
+		if c.UnavailableSince.Valid {
+			servermon.ControllerUnavailable.WithLabelValues(c.Name, string(c.UnavailableReason)).Set(1)
+		}
 		return nil
 	})
 	if err != nil {
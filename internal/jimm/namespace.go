@@ -0,0 +1,177 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// validNamespacePrefix matches the model name prefixes that may be
+// reserved. These are the prefixes of valid model names.
+var validNamespacePrefix = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// parseNamespacePrefix validates the given model name prefix, removing
+// any trailing "*".
+func parseNamespacePrefix(prefix string) (string, error) {
+	prefix = strings.TrimSuffix(prefix, "*")
+	if !validNamespacePrefix.MatchString(prefix) {
+		return "", errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid model name prefix %q", prefix))
+	}
+	return prefix, nil
+}
+
+// AddNamespaceReservation reserves the model names starting with the given
+// prefix for the members of the group with the given name. Reservations
+// may be made by JIMM administrators and by members of the group. A
+// prefix may not overlap with an existing reservation.
+func (j *JIMM) AddNamespaceReservation(ctx context.Context, user *openfga.User, prefix, groupName string) error {
+	const op = errors.Op("jimm.AddNamespaceReservation")
+
+	prefix, err := parseNamespacePrefix(prefix)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	group := dbmodel.GroupEntry{
+		Name: groupName,
+	}
+	if err := j.Database.GetGroup(ctx, &group); err != nil {
+		return errors.E(op, err)
+	}
+	if !user.JimmAdmin {
+		isMember, err := openfga.CheckRelation(ctx, user, group.ResourceTag(), ofganames.MemberRelation)
+		if err != nil {
+			return errors.E(op, errors.CodeOpenFGARequestFailed, err)
+		}
+		if !isMember {
+			return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+		}
+	}
+
+	reservations, err := j.Database.ListNamespaceReservations(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	for _, r := range reservations {
+		if strings.HasPrefix(prefix, r.Prefix) || strings.HasPrefix(r.Prefix, prefix) {
+			return errors.E(op, errors.CodeAlreadyExists, fmt.Sprintf("prefix %q overlaps reserved prefix %q", prefix, r.Prefix))
+		}
+	}
+
+	r := dbmodel.NamespaceReservation{
+		Prefix:  prefix,
+		GroupID: group.ID,
+	}
+	if err := j.Database.AddNamespaceReservation(ctx, &r); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListNamespaceReservations returns all namespace reservations. Only JIMM
+// administrators may list reservations.
+func (j *JIMM) ListNamespaceReservations(ctx context.Context, user *openfga.User) ([]apiparams.NamespaceReservation, error) {
+	const op = errors.Op("jimm.ListNamespaceReservations")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	reservations, err := j.Database.ListNamespaceReservations(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	result := make([]apiparams.NamespaceReservation, len(reservations))
+	for i, r := range reservations {
+		result[i] = r.ToAPINamespaceReservation()
+	}
+	return result, nil
+}
+
+// TransferNamespaceReservation transfers the reservation of the given
+// prefix to the group with the given name. Only JIMM administrators may
+// transfer reservations.
+func (j *JIMM) TransferNamespaceReservation(ctx context.Context, user *openfga.User, prefix, groupName string) error {
+	const op = errors.Op("jimm.TransferNamespaceReservation")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	prefix, err := parseNamespacePrefix(prefix)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	group := dbmodel.GroupEntry{
+		Name: groupName,
+	}
+	if err := j.Database.GetGroup(ctx, &group); err != nil {
+		return errors.E(op, err)
+	}
+	r := dbmodel.NamespaceReservation{
+		Prefix: prefix,
+	}
+	if err := j.Database.GetNamespaceReservation(ctx, &r); err != nil {
+		return errors.E(op, err)
+	}
+	r.GroupID = group.ID
+	r.Group = group
+	if err := j.Database.UpdateNamespaceReservation(ctx, &r); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveNamespaceReservation removes the reservation of the given prefix.
+// Only JIMM administrators may remove reservations.
+func (j *JIMM) RemoveNamespaceReservation(ctx context.Context, user *openfga.User, prefix string) error {
+	const op = errors.Op("jimm.RemoveNamespaceReservation")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	prefix, err := parseNamespacePrefix(prefix)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	r := dbmodel.NamespaceReservation{
+		Prefix: prefix,
+	}
+	if err := j.Database.GetNamespaceReservation(ctx, &r); err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeleteNamespaceReservation(ctx, &r); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// checkNamespaceReservation returns an error with the code CodeForbidden
+// if the given model name falls within a namespace reserved for a group
+// the given owner is not a member of. Reservations belonging to a group
+// that has since been removed do not restrict model names.
+func (j *JIMM) checkNamespaceReservation(ctx context.Context, owner *openfga.User, modelName string) error {
+	reservations, err := j.Database.ListNamespaceReservations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range reservations {
+		if !r.Matches(modelName) || r.Group.UUID == "" {
+			continue
+		}
+		isMember, err := openfga.CheckRelation(ctx, owner, r.Group.ResourceTag(), ofganames.MemberRelation)
+		if err != nil {
+			return errors.E(errors.CodeOpenFGARequestFailed, err)
+		}
+		if !isMember {
+			return errors.E(errors.CodeForbidden, fmt.Sprintf("model name prefix %q is reserved for group %q", r.Prefix, r.Group.Name))
+		}
+	}
+	return nil
+}
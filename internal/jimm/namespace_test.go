@@ -0,0 +1,127 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+const namespaceReservationTestEnv = `users:
+- username: alice@canonical.com
+  controller-access: login
+- username: bob@canonical.com
+  controller-access: login
+- username: charlie@canonical.com
+  controller-access: superuser
+`
+
+func TestNamespaceReservations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, namespaceReservationTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	charlie.JimmAdmin = true
+
+	payments, err := j.Database.AddGroup(ctx, "payments")
+	c.Assert(err, qt.IsNil)
+	_, err = j.Database.AddGroup(ctx, "billing")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(payments.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	// Only members of the group may reserve a prefix for it.
+	err = j.AddNamespaceReservation(ctx, bob, "payments-*", "payments")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	err = j.AddNamespaceReservation(ctx, alice, "Payments-*", "payments")
+	c.Check(err, qt.ErrorMatches, `invalid model name prefix "Payments-"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	err = j.AddNamespaceReservation(ctx, alice, "payments-*", "no-such-group")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = j.AddNamespaceReservation(ctx, alice, "payments-*", "payments")
+	c.Assert(err, qt.IsNil)
+	err = j.AddNamespaceReservation(ctx, charlie, "payments-eu-", "billing")
+	c.Check(err, qt.ErrorMatches, `prefix "payments-eu-" overlaps reserved prefix "payments-"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+
+	// Models in the reserved namespace can only be owned by group members.
+	_, err = j.AddModel(ctx, bob, &jimm.ModelCreateArgs{
+		Name:  "payments-api",
+		Owner: names.NewUserTag("bob@canonical.com"),
+	})
+	c.Check(err, qt.ErrorMatches, `model name prefix "payments-" is reserved for group "payments"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeForbidden)
+	_, err = j.AddModel(ctx, alice, &jimm.ModelCreateArgs{
+		Name:  "payments-api",
+		Owner: names.NewUserTag("alice@canonical.com"),
+	})
+	c.Check(errors.ErrorCode(err), qt.Not(qt.Equals), errors.CodeForbidden)
+
+	_, err = j.ListNamespaceReservations(ctx, alice)
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	reservations, err := j.ListNamespaceReservations(ctx, charlie)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reservations, qt.HasLen, 1)
+	c.Check(reservations[0].Prefix, qt.Equals, "payments-")
+	c.Check(reservations[0].Group, qt.Equals, "payments")
+
+	err = j.TransferNamespaceReservation(ctx, alice, "payments-*", "billing")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	err = j.TransferNamespaceReservation(ctx, charlie, "payments-*", "billing")
+	c.Assert(err, qt.IsNil)
+	reservations, err = j.ListNamespaceReservations(ctx, charlie)
+	c.Assert(err, qt.IsNil)
+	c.Assert(reservations, qt.HasLen, 1)
+	c.Check(reservations[0].Group, qt.Equals, "billing")
+
+	_, err = j.AddModel(ctx, alice, &jimm.ModelCreateArgs{
+		Name:  "payments-api",
+		Owner: names.NewUserTag("alice@canonical.com"),
+	})
+	c.Check(err, qt.ErrorMatches, `model name prefix "payments-" is reserved for group "billing"`)
+
+	err = j.RemoveNamespaceReservation(ctx, alice, "payments-")
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	err = j.RemoveNamespaceReservation(ctx, charlie, "payments-")
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveNamespaceReservation(ctx, charlie, "payments-")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	reservations, err = j.ListNamespaceReservations(ctx, charlie)
+	c.Assert(err, qt.IsNil)
+	c.Check(reservations, qt.HasLen, 0)
+}
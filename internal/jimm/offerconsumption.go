@@ -0,0 +1,118 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// SetOfferConsumerLimit sets the maximum number of models that may
+// consume the application offer with the given URL. A limit of zero
+// removes the offer's limit, leaving only JIMM's MaxOfferConsumers
+// policy. The limit may not exceed MaxOfferConsumers. The user must be an
+// administrator of the offer.
+func (j *JIMM) SetOfferConsumerLimit(ctx context.Context, user *openfga.User, offerURL string, limit int) error {
+	const op = errors.Op("jimm.SetOfferConsumerLimit")
+
+	if limit < 0 {
+		return errors.E(op, errors.CodeBadRequest, "consumer limit cannot be negative")
+	}
+	if j.MaxOfferConsumers > 0 && limit > j.MaxOfferConsumers {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("consumer limit cannot exceed %d", j.MaxOfferConsumers))
+	}
+	offer, err := j.administeredOffer(ctx, user, offerURL)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	offer.ConsumerLimit = limit
+	if err := j.Database.UpdateApplicationOffer(ctx, offer); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// OfferConsumption returns the current consumption of the application
+// offer with the given URL against its consumer limit. The user must be
+// an administrator of the offer.
+func (j *JIMM) OfferConsumption(ctx context.Context, user *openfga.User, offerURL string) (apiparams.OfferConsumption, error) {
+	const op = errors.Op("jimm.OfferConsumption")
+
+	offer, err := j.administeredOffer(ctx, user, offerURL)
+	if err != nil {
+		return apiparams.OfferConsumption{}, errors.E(op, err)
+	}
+	consumers := offerConsumers(offer)
+	return apiparams.OfferConsumption{
+		OfferURL:        offer.URL,
+		Consumers:       len(consumers),
+		ConsumingModels: consumers,
+		Limit:           j.offerConsumerLimit(offer),
+	}, nil
+}
+
+// checkOfferConsumerLimit returns an error with the code
+// CodeQuotaLimitExceeded if the given offer may not be consumed by
+// another model.
+func (j *JIMM) checkOfferConsumerLimit(offer *dbmodel.ApplicationOffer) error {
+	limit := j.offerConsumerLimit(offer)
+	if limit == 0 {
+		return nil
+	}
+	if n := len(offerConsumers(offer)); n >= limit {
+		return errors.E(errors.CodeQuotaLimitExceeded, fmt.Sprintf("offer %s has reached its limit of %d consumers", offer.URL, limit))
+	}
+	return nil
+}
+
+// offerConsumerLimit returns the consumer limit that applies to the
+// given offer, the lower of the offer's own limit and MaxOfferConsumers.
+// Zero means there is no limit.
+func (j *JIMM) offerConsumerLimit(offer *dbmodel.ApplicationOffer) int {
+	limit := offer.ConsumerLimit
+	if j.MaxOfferConsumers > 0 && (limit == 0 || j.MaxOfferConsumers < limit) {
+		limit = j.MaxOfferConsumers
+	}
+	return limit
+}
+
+// offerConsumers returns the sorted tags of the models with connections
+// to the given offer.
+func offerConsumers(offer *dbmodel.ApplicationOffer) []string {
+	seen := make(map[string]bool)
+	var consumers []string
+	for _, conn := range offer.Connections {
+		if conn.SourceModelTag == "" || seen[conn.SourceModelTag] {
+			continue
+		}
+		seen[conn.SourceModelTag] = true
+		consumers = append(consumers, conn.SourceModelTag)
+	}
+	sort.Strings(consumers)
+	return consumers
+}
+
+// administeredOffer returns the application offer with the given URL if
+// the user is an administrator of the offer.
+func (j *JIMM) administeredOffer(ctx context.Context, user *openfga.User, offerURL string) (*dbmodel.ApplicationOffer, error) {
+	offer := dbmodel.ApplicationOffer{
+		URL: offerURL,
+	}
+	if err := j.Database.GetApplicationOffer(ctx, &offer); err != nil {
+		return nil, err
+	}
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, offer.ResourceTag())
+	if err != nil {
+		return nil, errors.E(err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	return &offer, nil
+}
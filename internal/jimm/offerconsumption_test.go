@@ -0,0 +1,119 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestOfferConsumerLimit(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		MaxOfferConsumers: 5,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, migrationPrecheckTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	offer := dbmodel.ApplicationOffer{
+		ModelID:         m.ID,
+		ApplicationName: "postgresql",
+		Name:            "db",
+		UUID:            "00000003-0000-0000-0000-000000000001",
+		URL:             "alice@canonical.com/model-1.db",
+		Connections: []dbmodel.ApplicationOfferConnection{{
+			SourceModelTag: "model-00000002-0000-0000-0000-000000000002",
+			RelationID:     1,
+		}, {
+			SourceModelTag: "model-00000002-0000-0000-0000-000000000001",
+			RelationID:     2,
+		}, {
+			SourceModelTag: "model-00000002-0000-0000-0000-000000000001",
+			RelationID:     3,
+		}},
+	}
+	err = j.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	err = alice.SetApplicationOfferAccess(ctx, offer.ResourceTag(), ofganames.AdministratorRelation)
+	c.Assert(err, qt.IsNil)
+	err = bob.SetApplicationOfferAccess(ctx, offer.ResourceTag(), ofganames.ConsumerRelation)
+	c.Assert(err, qt.IsNil)
+
+	err = j.SetOfferConsumerLimit(ctx, bob, offer.URL, 2)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	_, err = j.OfferConsumption(ctx, bob, offer.URL)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.SetOfferConsumerLimit(ctx, alice, offer.URL, -1)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	err = j.SetOfferConsumerLimit(ctx, alice, offer.URL, 6)
+	c.Check(err, qt.ErrorMatches, `consumer limit cannot exceed 5`)
+
+	// Without an offer limit the policy applies.
+	consumption, err := j.OfferConsumption(ctx, alice, offer.URL)
+	c.Assert(err, qt.IsNil)
+	c.Check(consumption, qt.DeepEquals, apiparams.OfferConsumption{
+		OfferURL:  offer.URL,
+		Consumers: 2,
+		ConsumingModels: []string{
+			"model-00000002-0000-0000-0000-000000000001",
+			"model-00000002-0000-0000-0000-000000000002",
+		},
+		Limit: 5,
+	})
+
+	err = j.SetOfferConsumerLimit(ctx, alice, offer.URL, 2)
+	c.Assert(err, qt.IsNil)
+	consumption, err = j.OfferConsumption(ctx, alice, offer.URL)
+	c.Assert(err, qt.IsNil)
+	c.Check(consumption.Consumers, qt.Equals, 2)
+	c.Check(consumption.Limit, qt.Equals, 2)
+
+	// The offer's connections are unchanged by setting the limit.
+	dbOffer := dbmodel.ApplicationOffer{URL: offer.URL}
+	err = j.Database.GetApplicationOffer(ctx, &dbOffer)
+	c.Assert(err, qt.IsNil)
+	c.Check(dbOffer.ConsumerLimit, qt.Equals, 2)
+	c.Check(dbOffer.Connections, qt.HasLen, 3)
+
+	details := jujuparams.ConsumeOfferDetails{
+		Offer: &jujuparams.ApplicationOfferDetailsV5{
+			OfferURL: offer.URL,
+		},
+	}
+	err = j.GetApplicationOfferConsumeDetails(ctx, bob, &details, bakery.LatestVersion)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaLimitExceeded)
+	c.Check(err, qt.ErrorMatches, `offer alice@canonical.com/model-1.db has reached its limit of 2 consumers`)
+}
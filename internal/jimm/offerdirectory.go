@@ -0,0 +1,84 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// FindOffers searches the directory of application offers known to
+// JIMM, returning the offers matching the given request ordered by URL.
+// Only offers the user is able to read are returned.
+func (j *JIMM) FindOffers(ctx context.Context, user *openfga.User, req apiparams.FindOffersRequest) ([]apiparams.OfferDirectoryEntry, error) {
+	const op = errors.Op("jimm.FindOffers")
+
+	offerUUIDs, err := user.ListApplicationOffers(ctx, ofganames.ReaderRelation)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if len(offerUUIDs) == 0 {
+		return []apiparams.OfferDirectoryEntry{}, nil
+	}
+	filters := []db.ApplicationOfferFilter{db.ApplicationOfferFilterByUUID(offerUUIDs)}
+	if req.Query != "" {
+		filters = append(filters, db.ApplicationOfferFilterByText(req.Query))
+	}
+	if req.Interface != "" {
+		filters = append(filters, db.ApplicationOfferFilterByInterface(req.Interface))
+	}
+	if req.Owner != "" {
+		filters = append(filters, db.ApplicationOfferFilterByOwner(req.Owner))
+	}
+	if req.Cloud != "" {
+		filters = append(filters, db.ApplicationOfferFilterByCloud(req.Cloud))
+	}
+	offers, err := j.Database.SearchApplicationOffers(ctx, req.Limit, filters...)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+
+	entries := make([]apiparams.OfferDirectoryEntry, len(offers))
+	for i := range offers {
+		offer := &offers[i]
+		access, err := j.getUserOfferAccess(ctx, user, offer)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		interfaceSet := make(map[string]bool)
+		for _, ep := range offer.Endpoints {
+			interfaceSet[ep.Interface] = true
+		}
+		var interfaces []string
+		for iface := range interfaceSet {
+			interfaces = append(interfaces, iface)
+		}
+		sort.Strings(interfaces)
+		totalConnections := offer.TotalConnectedCount
+		if totalConnections < len(offer.Connections) {
+			totalConnections = len(offer.Connections)
+		}
+		entries[i] = apiparams.OfferDirectoryEntry{
+			OfferURL:          offer.URL,
+			OfferUUID:         offer.UUID,
+			OfferName:         offer.Name,
+			ApplicationName:   offer.ApplicationName,
+			Description:       offer.ApplicationDescription,
+			Interfaces:        interfaces,
+			Owner:             offer.Model.OwnerIdentityName,
+			ModelName:         offer.Model.Name,
+			Cloud:             offer.Model.CloudRegion.CloudName,
+			CloudRegion:       offer.Model.CloudRegion.Name,
+			Access:            access,
+			ActiveConnections: offer.ActiveConnectedCount,
+			TotalConnections:  totalConnections,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// An operationFunc performs a long-running operation, reporting its
+// progress with the given reporter. The returned result is recorded as
+// the operation's result if it succeeds.
+type operationFunc func(ctx context.Context, r *operationReporter) (interface{}, error)
+
+// startOperation records a new operation of the given kind started by the
+// given user and runs it in the background. The operation is recorded in
+// the database so that its progress may be followed by clients of any
+// JIMM replica. The operation keeps running if the given context is
+// cancelled.
+func (j *JIMM) startOperation(ctx context.Context, user *openfga.User, kind string, f operationFunc) (*dbmodel.Operation, error) {
+	o := dbmodel.Operation{
+		UUID:         uuid.NewString(),
+		Kind:         kind,
+		IdentityName: user.Name,
+		Status:       dbmodel.OperationRunning,
+	}
+	if err := j.Database.AddOperation(ctx, &o); err != nil {
+		return nil, err
+	}
+	r := &operationReporter{
+		j:  j,
+		op: o,
+	}
+	ctx = context.WithoutCancel(ctx)
+	j.operations.Add(1)
+	go func() {
+		defer j.operations.Done()
+		result, err := f(ctx, r)
+		r.finish(ctx, result, err)
+	}()
+	return &o, nil
+}
+
+// An operationReporter records the progress of an operation. It is safe
+// for concurrent use, and a nil reporter records nothing so that the
+// same code can run synchronously. Failures to record progress are
+// logged, they do not stop the operation.
+type operationReporter struct {
+	j *JIMM
+
+	mu sync.Mutex
+	op dbmodel.Operation
+}
+
+// Step records the completion of a step of the operation described by
+// the given message.
+func (r *operationReporter) Step(ctx context.Context, msg string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := dbmodel.OperationStep{
+		OperationID: r.op.ID,
+		Message:     msg,
+	}
+	if err := r.j.Database.AddOperationStep(ctx, &s); err != nil {
+		zapctx.Error(ctx, "failed to record operation step", zap.String("operation", r.op.UUID), zap.Error(err))
+	}
+}
+
+// Progress records that done of the total units of work of the operation
+// have been done.
+func (r *operationReporter) Progress(ctx context.Context, done, total int) {
+	if r == nil || total <= 0 {
+		return
+	}
+	pct := done * 100 / total
+	if pct > 100 {
+		pct = 100
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pct == r.op.Progress {
+		return
+	}
+	r.op.Progress = pct
+	if err := r.j.Database.UpdateOperation(ctx, &r.op); err != nil {
+		zapctx.Error(ctx, "failed to record operation progress", zap.String("operation", r.op.UUID), zap.Error(err))
+	}
+}
+
+// finish records that the operation ended with the given result and
+// error.
+func (r *operationReporter) finish(ctx context.Context, result interface{}, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil && result != nil {
+		var buf []byte
+		buf, err = json.Marshal(result)
+		r.op.Result = dbmodel.JSON(buf)
+	}
+	if err != nil {
+		r.op.Status = dbmodel.OperationFailed
+		r.op.Error = err.Error()
+		r.op.Result = nil
+	} else {
+		r.op.Status = dbmodel.OperationSucceeded
+		r.op.Progress = 100
+	}
+	r.op.EndedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := r.j.Database.UpdateOperation(ctx, &r.op); err != nil {
+		zapctx.Error(ctx, "failed to record operation end", zap.String("operation", r.op.UUID), zap.Error(err))
+		return
+	}
+	zapctx.Info(ctx, "operation finished", zap.String("operation", r.op.UUID), zap.String("kind", r.op.Kind), zap.String("status", r.op.Status))
+}
+
+// GetOperation returns the operation with the given ID. Users can get
+// the operations they started, JIMM administrators can get any
+// operation. If there is no such operation, or the user cannot get it,
+// an error with a code of CodeNotFound is returned.
+func (j *JIMM) GetOperation(ctx context.Context, user *openfga.User, id string) (apiparams.Operation, error) {
+	const op = errors.Op("jimm.GetOperation")
+
+	o := dbmodel.Operation{UUID: id}
+	if err := j.Database.GetOperation(ctx, &o); err != nil {
+		return apiparams.Operation{}, errors.E(op, err)
+	}
+	if o.IdentityName != user.Name && !user.JimmAdmin {
+		return apiparams.Operation{}, errors.E(op, errors.CodeNotFound, "operation not found")
+	}
+	return o.ToAPIOperation(), nil
+}
+
+// LookupOperation returns the operation with the given ID. The ID of an
+// operation is unguessable and only given to the user that started it,
+// so knowing it is all that is required. If there is no such operation an
+// error with a code of CodeNotFound is returned.
+func (j *JIMM) LookupOperation(ctx context.Context, id string) (apiparams.Operation, error) {
+	const op = errors.Op("jimm.LookupOperation")
+
+	o := dbmodel.Operation{UUID: id}
+	if err := j.Database.GetOperation(ctx, &o); err != nil {
+		return apiparams.Operation{}, errors.E(op, err)
+	}
+	return o.ToAPIOperation(), nil
+}
+
+// ListOperations returns the operations started by the given user, or
+// if all is set the operations started by every user, most recently
+// started first. Only JIMM administrators can list every operation.
+func (j *JIMM) ListOperations(ctx context.Context, user *openfga.User, all bool) ([]apiparams.Operation, error) {
+	const op = errors.Op("jimm.ListOperations")
+
+	name := user.Name
+	if all {
+		if err := j.checkJimmAdmin(user); err != nil {
+			return nil, errors.E(op, err)
+		}
+		name = ""
+	}
+	ops, err := j.Database.ListOperations(ctx, name)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.Operation, len(ops))
+	for i, o := range ops {
+		resp[i] = o.ToAPIOperation()
+	}
+	return resp, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestOperations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{
+				SupportsCheckCredentialModels_: true,
+				CheckCredentialModels_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+				UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+					return nil, nil
+				},
+			},
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, credentialRotationTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	adminIdentity, err := dbmodel.NewIdentity("admin@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(adminIdentity, client)
+	admin.JimmAdmin = true
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+
+	resp, err := j.RotateCloudCredentials(ctx, admin, apiparams.RotateCloudCredentialsRequest{
+		CloudTag: names.NewCloudTag("test-cloud").String(),
+		Rotations: []apiparams.CredentialAttributeRotation{{
+			Attribute: "access-key",
+			Old:       "old-key",
+			New:       "new-key",
+		}},
+		Async: true,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Results, qt.HasLen, 0)
+	c.Assert(resp.Operation, qt.Not(qt.Equals), "")
+	jimm.WaitForOperations(j)
+
+	cred1 := names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1")
+	cred3 := names.NewCloudCredentialTag("test-cloud/charlie@canonical.com/cred-3")
+
+	o, err := j.GetOperation(ctx, admin, resp.Operation)
+	c.Assert(err, qt.IsNil)
+	c.Check(o.ID, qt.Equals, resp.Operation)
+	c.Check(o.Kind, qt.Equals, "rotate-cloud-credentials")
+	c.Check(o.StartedBy, qt.Equals, "admin@canonical.com")
+	c.Check(o.Status, qt.Equals, dbmodel.OperationSucceeded)
+	c.Check(o.Progress, qt.Equals, 100)
+	c.Check(o.Error, qt.Equals, "")
+	c.Check(o.Ended, qt.Not(qt.IsNil))
+	var steps []string
+	for _, s := range o.Steps {
+		steps = append(steps, s.Message)
+	}
+	sort.Strings(steps)
+	c.Check(steps, qt.DeepEquals, []string{
+		"rotated " + cred1.String(),
+		"rotated " + cred3.String(),
+	})
+	c.Check(o.Result["results"], qt.HasLen, 2)
+	c.Check(o.Result["unchanged"], qt.Equals, float64(1))
+
+	// Users cannot see operations started by others.
+	_, err = j.GetOperation(ctx, alice, resp.Operation)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	_, err = j.GetOperation(ctx, admin, "no-such-operation")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Knowing the ID is enough to look the operation up.
+	o2, err := j.LookupOperation(ctx, resp.Operation)
+	c.Assert(err, qt.IsNil)
+	c.Check(o2, qt.DeepEquals, o)
+
+	ops, err := j.ListOperations(ctx, admin, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ops, qt.HasLen, 1)
+	c.Check(ops[0].ID, qt.Equals, resp.Operation)
+
+	ops, err = j.ListOperations(ctx, alice, false)
+	c.Assert(err, qt.IsNil)
+	c.Check(ops, qt.HasLen, 0)
+
+	_, err = j.ListOperations(ctx, alice, true)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
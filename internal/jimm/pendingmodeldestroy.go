@@ -0,0 +1,204 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// scheduleModelDestroy records that the model with the given tag is to be
+// destroyed, with the given options, once the model destroy window has
+// passed. Only model administrators may destroy models. Requesting the
+// destroy of a model whose destroy is already pending leaves the pending
+// destroy unchanged.
+func (j *JIMM) scheduleModelDestroy(ctx context.Context, user *openfga.User, mt names.ModelTag, destroyStorage, force *bool, maxWait, timeout *time.Duration) error {
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return err
+	}
+	if !allowedModelAccess["admin"][j.getModelAccess(ctx, user, mt)] {
+		return errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	if err := j.CheckModelFrozen(ctx, m.ID, "ModelManager", "DestroyModels"); err != nil {
+		return err
+	}
+	if err := j.checkModelDependents(ctx, &m, force); err != nil {
+		return err
+	}
+
+	pd := dbmodel.PendingModelDestroy{
+		ModelID:     m.ID,
+		RequestedBy: user.Name,
+		DestroyAt:   time.Now().Add(j.ModelDestroyWindow).UTC(),
+	}
+	if destroyStorage != nil {
+		pd.DestroyStorage = sql.NullBool{Bool: *destroyStorage, Valid: true}
+	}
+	if force != nil {
+		pd.Force = sql.NullBool{Bool: *force, Valid: true}
+	}
+	if maxWait != nil {
+		pd.MaxWait = sql.NullInt64{Int64: int64(*maxWait), Valid: true}
+	}
+	if timeout != nil {
+		pd.Timeout = sql.NullInt64{Int64: int64(*timeout), Valid: true}
+	}
+	if err := j.Database.AddPendingModelDestroy(ctx, &pd); err != nil {
+		return err
+	}
+	// Report the destroy that is actually pending, which may have been
+	// requested earlier.
+	if err := j.Database.GetPendingModelDestroy(ctx, &pd); err != nil {
+		return err
+	}
+	zapctx.Info(ctx, "model destroy pending", zap.String("model", mt.Id()), zap.String("requested-by", pd.RequestedBy), zap.Time("destroy-at", pd.DestroyAt))
+	j.Notifier.Notify(ctx, notify.Event{
+		Kind:       notify.ModelDestroyPending,
+		Controller: m.Controller.Name,
+		Model:      mt.String(),
+		Owner:      m.OwnerIdentityName,
+		Message:    fmt.Sprintf("model %s/%s will be destroyed at %s as requested by %s, cancel with CancelModelDestroy to keep it", m.OwnerIdentityName, m.Name, pd.DestroyAt.UTC().Format(time.RFC3339), pd.RequestedBy),
+	})
+	return nil
+}
+
+// CancelModelDestroy cancels the pending destroy of the model with the
+// given tag. Only the model's owner and JIMM administrators may cancel a
+// destroy. If no destroy is pending for the model an error with the code
+// CodeNotFound is returned.
+func (j *JIMM) CancelModelDestroy(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	const op = errors.Op("jimm.CancelModelDestroy")
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return errors.E(op, err)
+	}
+	if !user.JimmAdmin && m.OwnerIdentityName != user.Name {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	pd := dbmodel.PendingModelDestroy{
+		ModelID: m.ID,
+	}
+	if err := j.Database.GetPendingModelDestroy(ctx, &pd); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(op, errors.CodeNotFound, "no destroy pending for model")
+		}
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeletePendingModelDestroy(ctx, &pd); err != nil {
+		return errors.E(op, err)
+	}
+	zapctx.Info(ctx, "model destroy canceled", zap.String("model", mt.Id()), zap.String("canceled-by", user.Name))
+	return nil
+}
+
+// ListPendingModelDestroys returns the models waiting to be destroyed,
+// the earliest first. JIMM administrators see every pending destroy,
+// other users see the pending destroys of the models they own.
+func (j *JIMM) ListPendingModelDestroys(ctx context.Context, user *openfga.User) ([]apiparams.PendingModelDestroy, error) {
+	const op = errors.Op("jimm.ListPendingModelDestroys")
+
+	destroys, err := j.Database.ListPendingModelDestroys(ctx, time.Time{})
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	pending := make([]apiparams.PendingModelDestroy, 0, len(destroys))
+	for _, pd := range destroys {
+		if !user.JimmAdmin && pd.Model.OwnerIdentityName != user.Name {
+			continue
+		}
+		pending = append(pending, pd.ToAPIPendingModelDestroy())
+	}
+	return pending, nil
+}
+
+// ReapPendingModelDestroys destroys the models whose destroy window has
+// passed, with the options the destroy was requested with. Models that
+// have since been frozen are left pending until they are unfrozen, as
+// are models that other models have since declared a dependency on,
+// unless the destroy is forced.
+// Failures to destroy a model are logged and the destroy is retried the
+// next time ReapPendingModelDestroys is called. The number of models
+// destroyed is returned.
+func (j *JIMM) ReapPendingModelDestroys(ctx context.Context) (int, error) {
+	const op = errors.Op("jimm.ReapPendingModelDestroys")
+
+	destroys, err := j.Database.ListPendingModelDestroys(ctx, time.Now())
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	var n int
+	for i := range destroys {
+		pd := &destroys[i]
+		ctx := zapctx.WithFields(ctx, zap.String("model", pd.Model.UUID.String))
+		if err := j.reapModel(ctx, pd); err != nil {
+			zapctx.Error(ctx, "failed to destroy model", zap.Error(err))
+			continue
+		}
+		n++
+	}
+	return n, nil
+}
+
+// reapModel destroys the model of the given pending destroy and removes
+// the pending destroy.
+func (j *JIMM) reapModel(ctx context.Context, pd *dbmodel.PendingModelDestroy) error {
+	m := &pd.Model
+	if err := j.CheckModelFrozen(ctx, m.ID, "ModelManager", "DestroyModels"); err != nil {
+		return err
+	}
+	var destroyStorage, force *bool
+	if pd.DestroyStorage.Valid {
+		destroyStorage = &pd.DestroyStorage.Bool
+	}
+	if pd.Force.Valid {
+		force = &pd.Force.Bool
+	}
+	if err := j.checkModelDependents(ctx, m, force); err != nil {
+		return err
+	}
+	var maxWait, timeout *time.Duration
+	if pd.MaxWait.Valid {
+		d := time.Duration(pd.MaxWait.Int64)
+		maxWait = &d
+	}
+	if pd.Timeout.Valid {
+		d := time.Duration(pd.Timeout.Int64)
+		timeout = &d
+	}
+
+	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+	if err := api.DestroyModel(ctx, m.ResourceTag(), destroyStorage, force, maxWait, timeout); err != nil {
+		return err
+	}
+	m.Life = state.Dying.String()
+	if err := j.Database.UpdateModel(ctx, m); err != nil {
+		// The monitor will catch up with the model's life.
+		zapctx.Error(ctx, "failed to store model change", zap.Error(err))
+	}
+	if err := j.Database.DeletePendingModelDestroy(ctx, pd); err != nil {
+		return err
+	}
+	zapctx.Info(ctx, "model destroyed", zap.String("requested-by", pd.RequestedBy))
+	return nil
+}
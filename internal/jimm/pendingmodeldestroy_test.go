@@ -0,0 +1,125 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/juju/state"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestPendingModelDestroy(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var destroyed int
+	var destroyStorage *bool
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			DestroyModel_: func(_ context.Context, _ names.ModelTag, ds *bool, _ *bool, _ *time.Duration, _ *time.Duration) error {
+				destroyed++
+				destroyStorage = ds
+				return nil
+			},
+		},
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer:             dialer,
+		ModelDestroyWindow: time.Hour,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	err = j.DestroyModel(ctx, bob, mt, nil, nil, nil, nil)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	storage := true
+	err = j.DestroyModel(ctx, alice, mt, &storage, nil, nil, nil)
+	c.Assert(err, qt.IsNil)
+	c.Check(destroyed, qt.Equals, 0)
+
+	var m dbmodel.Model
+	m.SetTag(mt)
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Alive.String())
+
+	pending, err := j.ListPendingModelDestroys(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pending, qt.HasLen, 1)
+	c.Check(pending[0].ModelTag, qt.Equals, mt.String())
+	c.Check(pending[0].RequestedBy, qt.Equals, "alice@canonical.com")
+	c.Check(pending[0].DestroyAt.After(time.Now().Add(59*time.Minute)), qt.IsTrue)
+	pending, err = j.ListPendingModelDestroys(ctx, bob)
+	c.Assert(err, qt.IsNil)
+	c.Check(pending, qt.HasLen, 0)
+	pending, err = j.ListPendingModelDestroys(ctx, diane)
+	c.Assert(err, qt.IsNil)
+	c.Check(pending, qt.HasLen, 1)
+
+	// The destroy is not due yet.
+	n, err := j.ReapPendingModelDestroys(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 0)
+	c.Check(destroyed, qt.Equals, 0)
+
+	err = j.CancelModelDestroy(ctx, bob, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.CancelModelDestroy(ctx, diane, mt)
+	c.Assert(err, qt.IsNil)
+	err = j.CancelModelDestroy(ctx, alice, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Once the window has passed the reaper destroys the model with the
+	// requested options.
+	j.ModelDestroyWindow = time.Millisecond
+	err = j.DestroyModel(ctx, alice, mt, &storage, nil, nil, nil)
+	c.Assert(err, qt.IsNil)
+	time.Sleep(10 * time.Millisecond)
+	n, err = j.ReapPendingModelDestroys(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, 1)
+	c.Check(destroyed, qt.Equals, 1)
+	c.Assert(destroyStorage, qt.IsNotNil)
+	c.Check(*destroyStorage, qt.IsTrue)
+
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Life, qt.Equals, state.Dying.String())
+	pending, err = j.ListPendingModelDestroys(ctx, diane)
+	c.Assert(err, qt.IsNil)
+	c.Check(pending, qt.HasLen, 0)
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// SetModelPublicListing lists the model with the given tag in the public
+// catalog with the given purpose and owner group, replacing any existing
+// listing. The owner group, if specified, must exist. The user must be an
+// administrator of the model.
+func (j *JIMM) SetModelPublicListing(ctx context.Context, user *openfga.User, mt names.ModelTag, purpose, ownerGroup string) error {
+	const op = errors.Op("jimm.SetModelPublicListing")
+
+	m, err := j.publicListingModel(ctx, user, mt)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.checkPublicListingGroup(ctx, ownerGroup); err != nil {
+		return errors.E(op, err)
+	}
+	l := dbmodel.PublicModelListing{
+		ModelID:    m.ID,
+		Purpose:    purpose,
+		OwnerGroup: ownerGroup,
+	}
+	if err := j.Database.SetPublicModelListing(ctx, &l); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveModelPublicListing removes the model with the given tag from the
+// public catalog. The user must be an administrator of the model.
+func (j *JIMM) RemoveModelPublicListing(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	const op = errors.Op("jimm.RemoveModelPublicListing")
+
+	m, err := j.publicListingModel(ctx, user, mt)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeletePublicModelListing(ctx, &dbmodel.PublicModelListing{ModelID: m.ID}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// SetOfferPublicListing lists the application offer with the given URL in
+// the public catalog with the given purpose and owner group, replacing
+// any existing listing. The owner group, if specified, must exist. The
+// user must be an administrator of the offer.
+func (j *JIMM) SetOfferPublicListing(ctx context.Context, user *openfga.User, offerURL, purpose, ownerGroup string) error {
+	const op = errors.Op("jimm.SetOfferPublicListing")
+
+	offer, err := j.administeredOffer(ctx, user, offerURL)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.checkPublicListingGroup(ctx, ownerGroup); err != nil {
+		return errors.E(op, err)
+	}
+	l := dbmodel.PublicOfferListing{
+		ApplicationOfferID: offer.ID,
+		Purpose:            purpose,
+		OwnerGroup:         ownerGroup,
+	}
+	if err := j.Database.SetPublicOfferListing(ctx, &l); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveOfferPublicListing removes the application offer with the given
+// URL from the public catalog. The user must be an administrator of the
+// offer.
+func (j *JIMM) RemoveOfferPublicListing(ctx context.Context, user *openfga.User, offerURL string) error {
+	const op = errors.Op("jimm.RemoveOfferPublicListing")
+
+	offer, err := j.administeredOffer(ctx, user, offerURL)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.DeletePublicOfferListing(ctx, &dbmodel.PublicOfferListing{ApplicationOfferID: offer.ID}); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// PublicCatalog returns the sanitized descriptions of the models and
+// application offers that have been listed in the public catalog. The
+// catalog is served to unauthenticated clients so it deliberately
+// includes nothing that identifies the controllers, owners or users of
+// the listed models and offers.
+func (j *JIMM) PublicCatalog(ctx context.Context) (apiparams.PublicCatalog, error) {
+	const op = errors.Op("jimm.PublicCatalog")
+
+	modelListings, err := j.Database.ListPublicModelListings(ctx)
+	if err != nil {
+		return apiparams.PublicCatalog{}, errors.E(op, err)
+	}
+	offerListings, err := j.Database.ListPublicOfferListings(ctx)
+	if err != nil {
+		return apiparams.PublicCatalog{}, errors.E(op, err)
+	}
+
+	catalog := apiparams.PublicCatalog{
+		Models: make([]apiparams.PublicModel, len(modelListings)),
+		Offers: make([]apiparams.PublicOffer, len(offerListings)),
+	}
+	for i, l := range modelListings {
+		catalog.Models[i] = apiparams.PublicModel{
+			Name:       l.Model.Name,
+			Purpose:    l.Purpose,
+			OwnerGroup: l.OwnerGroup,
+		}
+	}
+	for i, l := range offerListings {
+		interfaceSet := make(map[string]bool)
+		for _, ep := range l.ApplicationOffer.Endpoints {
+			interfaceSet[ep.Interface] = true
+		}
+		interfaces := make([]string, 0, len(interfaceSet))
+		for iface := range interfaceSet {
+			interfaces = append(interfaces, iface)
+		}
+		sort.Strings(interfaces)
+		catalog.Offers[i] = apiparams.PublicOffer{
+			Name:       l.ApplicationOffer.Name,
+			Purpose:    l.Purpose,
+			OwnerGroup: l.OwnerGroup,
+			Interfaces: interfaces,
+		}
+	}
+	return catalog, nil
+}
+
+// publicListingModel returns the model with the given tag if the user is
+// an administrator of the model.
+func (j *JIMM) publicListingModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (*dbmodel.Model, error) {
+	isAdministrator, err := openfga.IsAdministrator(ctx, user, mt)
+	if err != nil {
+		return nil, errors.E(err, errors.CodeOpenFGARequestFailed)
+	}
+	if !isAdministrator {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	var m dbmodel.Model
+	m.SetTag(mt)
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// checkPublicListingGroup returns an error if a group with the given name
+// does not exist. An empty name is allowed.
+func (j *JIMM) checkPublicListingGroup(ctx context.Context, name string) error {
+	if name == "" {
+		return nil
+	}
+	if err := j.Database.GetGroup(ctx, &dbmodel.GroupEntry{Name: name}); err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return errors.E(errors.CodeBadRequest, fmt.Sprintf("group %q not found", name))
+		}
+		return err
+	}
+	return nil
+}
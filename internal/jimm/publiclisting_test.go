@@ -0,0 +1,128 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestPublicCatalog(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, migrationPrecheckTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	_, err = j.Database.AddGroup(ctx, "data-platform")
+	c.Assert(err, qt.IsNil)
+
+	m := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	offer := dbmodel.ApplicationOffer{
+		ModelID:         m.ID,
+		ApplicationName: "postgresql",
+		Name:            "db",
+		UUID:            "00000003-0000-0000-0000-000000000001",
+		URL:             "alice@canonical.com/model-1.db",
+		Endpoints: []dbmodel.ApplicationOfferRemoteEndpoint{{
+			Name:      "db",
+			Role:      "provider",
+			Interface: "postgresql_client",
+		}, {
+			Name:      "db-admin",
+			Role:      "provider",
+			Interface: "pgsql",
+		}, {
+			Name:      "legacy-db",
+			Role:      "provider",
+			Interface: "pgsql",
+		}},
+	}
+	err = j.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, qt.IsNil)
+	err = alice.SetApplicationOfferAccess(ctx, offer.ResourceTag(), ofganames.AdministratorRelation)
+	c.Assert(err, qt.IsNil)
+
+	mt := names.NewModelTag(m.UUID.String)
+
+	err = j.SetModelPublicListing(ctx, bob, mt, "shared databases", "data-platform")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.SetOfferPublicListing(ctx, bob, offer.URL, "shared databases", "data-platform")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.SetModelPublicListing(ctx, alice, mt, "shared databases", "no-such-group")
+	c.Check(err, qt.ErrorMatches, `group "no-such-group" not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	catalog, err := j.PublicCatalog(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(catalog, qt.DeepEquals, apiparams.PublicCatalog{
+		Models: []apiparams.PublicModel{},
+		Offers: []apiparams.PublicOffer{},
+	})
+
+	err = j.SetModelPublicListing(ctx, alice, mt, "shared databases", "data-platform")
+	c.Assert(err, qt.IsNil)
+	err = j.SetOfferPublicListing(ctx, alice, offer.URL, "production PostgreSQL", "data-platform")
+	c.Assert(err, qt.IsNil)
+
+	catalog, err = j.PublicCatalog(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(catalog, qt.DeepEquals, apiparams.PublicCatalog{
+		Models: []apiparams.PublicModel{{
+			Name:       "model-1",
+			Purpose:    "shared databases",
+			OwnerGroup: "data-platform",
+		}},
+		Offers: []apiparams.PublicOffer{{
+			Name:       "db",
+			Purpose:    "production PostgreSQL",
+			OwnerGroup: "data-platform",
+			Interfaces: []string{"pgsql", "postgresql_client"},
+		}},
+	})
+
+	err = j.RemoveModelPublicListing(ctx, bob, mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.RemoveModelPublicListing(ctx, alice, mt)
+	c.Assert(err, qt.IsNil)
+	err = j.RemoveOfferPublicListing(ctx, alice, offer.URL)
+	c.Assert(err, qt.IsNil)
+
+	catalog, err = j.PublicCatalog(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(catalog, qt.DeepEquals, apiparams.PublicCatalog{
+		Models: []apiparams.PublicModel{},
+		Offers: []apiparams.PublicOffer{},
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// QuotaLimits holds the limits on the resources each user's models may
+// use. A limit of zero means the resource is not limited.
+type QuotaLimits struct {
+	// Models is the maximum number of models a user may own.
+	Models int64
+
+	// Machines is the maximum total number of machines in the models a
+	// user owns.
+	Machines int64
+
+	// Cores is the maximum total number of cores in the models a user
+	// owns.
+	Cores int64
+}
+
+// UserQuota returns the quota limits for the given user's models along
+// with their current usage. Users may view their own quota, JIMM
+// administrators may view the quota of any user.
+func (j *JIMM) UserQuota(ctx context.Context, user *openfga.User, target names.UserTag) (apiparams.UserQuota, error) {
+	const op = errors.Op("jimm.UserQuota")
+
+	if target.Id() != user.Name && !user.JimmAdmin {
+		return apiparams.UserQuota{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	limits, err := j.quotaLimits(ctx, target.Id())
+	if err != nil {
+		return apiparams.UserQuota{}, errors.E(op, err)
+	}
+	usage, err := j.Database.ModelUsageByOwner(ctx, target.Id())
+	if err != nil {
+		return apiparams.UserQuota{}, errors.E(op, err)
+	}
+	return apiparams.UserQuota{
+		UserTag: target.String(),
+		Models: apiparams.QuotaUsage{
+			Limit: limits.Models,
+			Used:  usage.Models,
+		},
+		Machines: apiparams.QuotaUsage{
+			Limit: limits.Machines,
+			Used:  usage.Machines,
+		},
+		Cores: apiparams.QuotaUsage{
+			Limit: limits.Cores,
+			Used:  usage.Cores,
+		},
+	}, nil
+}
+
+// SetUserQuota overrides the default quota limits for the given user's
+// models. Limits that are not specified in the request use the default,
+// a limit of zero means the resource is not limited. Only JIMM
+// administrators may set quotas.
+func (j *JIMM) SetUserQuota(ctx context.Context, user *openfga.User, target names.UserTag, req apiparams.SetUserQuotaRequest) error {
+	const op = errors.Op("jimm.SetUserQuota")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	identity, err := dbmodel.NewIdentity(target.Id())
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.Database.FetchIdentity(ctx, identity); err != nil {
+		return errors.E(op, err)
+	}
+	q := dbmodel.IdentityQuota{
+		IdentityName: identity.Name,
+	}
+	for _, l := range []struct {
+		name  string
+		value *int64
+		dst   *sql.NullInt64
+	}{
+		{"models", req.Models, &q.Models},
+		{"machines", req.Machines, &q.Machines},
+		{"cores", req.Cores, &q.Cores},
+	} {
+		if l.value == nil {
+			continue
+		}
+		if *l.value < 0 {
+			return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid %s quota %d", l.name, *l.value))
+		}
+		*l.dst = sql.NullInt64{Int64: *l.value, Valid: true}
+	}
+	if err := j.Database.SetIdentityQuota(ctx, &q); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveUserQuota removes the quota limits set for the given user, so
+// that their models use the default limits. Only JIMM administrators may
+// remove quotas.
+func (j *JIMM) RemoveUserQuota(ctx context.Context, user *openfga.User, target names.UserTag) error {
+	const op = errors.Op("jimm.RemoveUserQuota")
+
+	if !user.JimmAdmin {
+		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	q := dbmodel.IdentityQuota{
+		IdentityName: target.Id(),
+	}
+	if err := j.Database.DeleteIdentityQuota(ctx, &q); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// quotaLimits returns the quota limits that apply to the models owned by
+// the identity with the given name.
+func (j *JIMM) quotaLimits(ctx context.Context, name string) (QuotaLimits, error) {
+	limits := j.quotas()
+	q := dbmodel.IdentityQuota{
+		IdentityName: name,
+	}
+	err := j.Database.GetIdentityQuota(ctx, &q)
+	if err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			return limits, nil
+		}
+		return QuotaLimits{}, err
+	}
+	if q.Models.Valid {
+		limits.Models = q.Models.Int64
+	}
+	if q.Machines.Valid {
+		limits.Machines = q.Machines.Int64
+	}
+	if q.Cores.Valid {
+		limits.Cores = q.Cores.Int64
+	}
+	return limits, nil
+}
+
+// checkModelQuota returns an error with the code CodeQuotaLimitExceeded
+// if the identity with the given name may not own another model.
+func (j *JIMM) checkModelQuota(ctx context.Context, ownerName string) error {
+	limits, err := j.quotaLimits(ctx, ownerName)
+	if err != nil {
+		return err
+	}
+	if limits.Models <= 0 {
+		return nil
+	}
+	usage, err := j.Database.ModelUsageByOwner(ctx, ownerName)
+	if err != nil {
+		return err
+	}
+	if usage.Models >= limits.Models {
+		return errors.E(errors.CodeQuotaLimitExceeded, fmt.Sprintf("model quota of %d exceeded", limits.Models))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const userQuotaTestEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: test
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  machines: 2
+  cores: 4
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: test
+  machines: 1
+  cores: 8
+users:
+- username: bob@canonical.com
+  controller-access: superuser
+`
+
+func TestUserQuota(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+		Quotas: jimm.QuotaLimits{
+			Models: 2,
+			Cores:  16,
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, userQuotaTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	bob.JimmAdmin = true
+
+	expectedQuota := apiparams.UserQuota{
+		UserTag:  "user-alice@canonical.com",
+		Models:   apiparams.QuotaUsage{Limit: 2, Used: 2},
+		Machines: apiparams.QuotaUsage{Limit: 0, Used: 3},
+		Cores:    apiparams.QuotaUsage{Limit: 16, Used: 12},
+	}
+
+	quota, err := j.UserQuota(ctx, alice, names.NewUserTag("alice@canonical.com"))
+	c.Assert(err, qt.IsNil)
+	c.Check(quota, qt.DeepEquals, expectedQuota)
+
+	quota, err = j.UserQuota(ctx, bob, names.NewUserTag("alice@canonical.com"))
+	c.Assert(err, qt.IsNil)
+	c.Check(quota, qt.DeepEquals, expectedQuota)
+
+	_, err = j.UserQuota(ctx, alice, names.NewUserTag("charlie@canonical.com"))
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	_, err = j.AddModel(ctx, alice, &jimm.ModelCreateArgs{
+		Name:  "model-3",
+		Owner: names.NewUserTag("alice@canonical.com"),
+	})
+	c.Check(err, qt.ErrorMatches, "model quota of 2 exceeded")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeQuotaLimitExceeded)
+}
+
+func TestSetUserQuota(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+		Quotas: jimm.QuotaLimits{
+			Models: 2,
+			Cores:  16,
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, userQuotaTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+	bob.JimmAdmin = true
+
+	aliceTag := names.NewUserTag("alice@canonical.com")
+	models, machines := int64(5), int64(0)
+	err = j.SetUserQuota(ctx, alice, aliceTag, apiparams.SetUserQuotaRequest{Models: &models})
+	c.Check(err, qt.ErrorMatches, "unauthorized")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.SetUserQuota(ctx, bob, aliceTag, apiparams.SetUserQuotaRequest{Models: &models, Machines: &machines})
+	c.Assert(err, qt.IsNil)
+
+	quota, err := j.UserQuota(ctx, alice, aliceTag)
+	c.Assert(err, qt.IsNil)
+	c.Check(quota, qt.DeepEquals, apiparams.UserQuota{
+		UserTag:  "user-alice@canonical.com",
+		Models:   apiparams.QuotaUsage{Limit: 5, Used: 2},
+		Machines: apiparams.QuotaUsage{Limit: 0, Used: 3},
+		Cores:    apiparams.QuotaUsage{Limit: 16, Used: 12},
+	})
+
+	negative := int64(-1)
+	err = j.SetUserQuota(ctx, bob, aliceTag, apiparams.SetUserQuotaRequest{Cores: &negative})
+	c.Check(err, qt.ErrorMatches, "invalid cores quota -1")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+
+	err = j.SetUserQuota(ctx, bob, names.NewUserTag("charlie@canonical.com"), apiparams.SetUserQuotaRequest{Models: &models})
+	c.Check(err, qt.ErrorMatches, "record not found")
+
+	err = j.RemoveUserQuota(ctx, alice, aliceTag)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	err = j.RemoveUserQuota(ctx, bob, aliceTag)
+	c.Assert(err, qt.IsNil)
+	quota, err = j.UserQuota(ctx, alice, aliceTag)
+	c.Assert(err, qt.IsNil)
+	c.Check(quota.Models.Limit, qt.Equals, int64(2))
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/juju/core/life"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type readConsistencyKey struct{}
+
+// ContextWithReadConsistency returns a context requesting that the read
+// operations performed with it use the given read consistency. The
+// ModelInfo and ModelStatus methods read from the controller hosting the
+// model unless a cached read is requested, GetCloudCredential and
+// ForEachUserCloudCredential read from JIMM's store unless a fresh read
+// is requested.
+func ContextWithReadConsistency(ctx context.Context, c apiparams.ReadConsistency) context.Context {
+	return context.WithValue(ctx, readConsistencyKey{}, c)
+}
+
+// readConsistency returns the read consistency requested in the given
+// context, or def if none was requested. If the requested consistency is
+// not known an error with a code of CodeBadRequest is returned.
+func readConsistency(ctx context.Context, def apiparams.ReadConsistency) (apiparams.ReadConsistency, error) {
+	c, _ := ctx.Value(readConsistencyKey{}).(apiparams.ReadConsistency)
+	switch c {
+	case "":
+		return def, nil
+	case apiparams.ReadConsistencyCached, apiparams.ReadConsistencyFresh:
+		return c, nil
+	default:
+		return "", errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid read consistency %q", c))
+	}
+}
+
+// cachedModelInfo returns the model info for the given model as recorded
+// in JIMM's database. The controller hosting the model is not contacted.
+func (j *JIMM) cachedModelInfo(ctx context.Context, m *dbmodel.Model) (*jujuparams.ModelInfo, error) {
+	ms := m.ToJujuModelSummary()
+	mi := jujuparams.ModelInfo{
+		Name:               ms.Name,
+		Type:               ms.Type,
+		UUID:               ms.UUID,
+		ControllerUUID:     ms.ControllerUUID,
+		IsController:       ms.IsController,
+		ProviderType:       ms.ProviderType,
+		DefaultSeries:      ms.DefaultSeries,
+		CloudTag:           ms.CloudTag,
+		CloudRegion:        ms.CloudRegion,
+		CloudCredentialTag: ms.CloudCredentialTag,
+		OwnerTag:           ms.OwnerTag,
+		Life:               ms.Life,
+		Status:             ms.Status,
+		SLA:                ms.SLA,
+		AgentVersion:       ms.AgentVersion,
+	}
+	if m.CloudCredential.Valid.Valid {
+		valid := m.CloudCredential.Valid.Bool
+		mi.CloudCredentialValidity = &valid
+	}
+	machines, err := j.cachedModelMachines(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	mi.Machines = machines
+	return &mi, nil
+}
+
+// cachedModelStatus returns the status of the given model as recorded in
+// JIMM's database. The controller hosting the model is not contacted.
+// The applications in the model are those that have units.
+func (j *JIMM) cachedModelStatus(ctx context.Context, m *dbmodel.Model) (*jujuparams.ModelStatus, error) {
+	ms := jujuparams.ModelStatus{
+		ModelTag:           m.ResourceTag().String(),
+		Life:               life.Value(m.Life),
+		Type:               m.Type,
+		HostedMachineCount: int(m.Machines),
+		UnitCount:          int(m.Units),
+		OwnerTag:           names.NewUserTag(m.OwnerIdentityName).String(),
+	}
+	machines, err := j.cachedModelMachines(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	ms.Machines = machines
+
+	state := dbmodel.ModelWatcherState{ModelID: m.ID}
+	err = j.Database.GetModelWatcherState(ctx, &state)
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		// The model has not yet been seen by a watcher.
+		return &ms, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	applications := make(map[string]bool)
+	for unit := range state.Units {
+		application, _, _ := strings.Cut(unit, "/")
+		applications[application] = true
+	}
+	for application := range applications {
+		ms.Applications = append(ms.Applications, jujuparams.ModelApplicationInfo{Name: application})
+	}
+	sort.Slice(ms.Applications, func(i, j int) bool {
+		return ms.Applications[i].Name < ms.Applications[j].Name
+	})
+	ms.ApplicationCount = len(ms.Applications)
+	return &ms, nil
+}
+
+// cachedModelMachines returns the machines in the given model as
+// recorded by the controller watchers.
+func (j *JIMM) cachedModelMachines(ctx context.Context, m *dbmodel.Model) ([]jujuparams.ModelMachineInfo, error) {
+	machines, err := j.Database.GetModelMachines(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]jujuparams.ModelMachineInfo, len(machines))
+	for i, machine := range machines {
+		infos[i] = jujuparams.ModelMachineInfo{
+			Id:          machine.MachineID,
+			InstanceId:  machine.InstanceID,
+			DisplayName: machine.Hostname,
+		}
+	}
+	return infos, nil
+}
+
+// refreshCloudCredentialValidity updates the validity of the given
+// credential with the validity reported by the controller hosting a
+// model that uses the credential. Credentials that are not used by any
+// model are left unchanged.
+func (j *JIMM) refreshCloudCredentialValidity(ctx context.Context, cred *dbmodel.CloudCredential) error {
+	if len(cred.Models) == 0 {
+		return nil
+	}
+	m := dbmodel.Model{ID: cred.Models[0].ID}
+	if err := j.Database.GetModel(ctx, &m); err != nil {
+		return err
+	}
+	api, err := j.dial(ctx, &m.Controller, names.ModelTag{})
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	mi := jujuparams.ModelInfo{UUID: m.UUID.String}
+	if err := api.ModelInfo(ctx, &mi); err != nil {
+		return err
+	}
+	if mi.CloudCredentialValidity != nil {
+		cred.Valid = sql.NullBool{Bool: *mi.CloudCredentialValidity, Valid: true}
+	}
+	return nil
+}
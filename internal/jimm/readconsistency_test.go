@@ -0,0 +1,174 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestCachedModelReads(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	// Cached reads must not contact the controller.
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			Err: errors.E("unexpected dial"),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	model := env.Model("alice@canonical.com", "model-1").DBObject(c, j.Database)
+	model.Machines = 1
+	model.Units = 3
+	err = j.Database.UpdateModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	err = j.Database.UpsertMachine(ctx, &dbmodel.Machine{
+		ModelID:    model.ID,
+		MachineID:  "0",
+		InstanceID: "i-0",
+		Hostname:   "juju-0",
+		Life:       "alive",
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.Database.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+		ModelID: model.ID,
+		Units: dbmodel.StringMap{
+			"db/0":  "active",
+			"web/0": "active",
+			"web/1": "blocked",
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	bob := env.User("bob@canonical.com").DBObject(c, j.Database)
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	cachedCtx := jimm.ContextWithReadConsistency(ctx, apiparams.ReadConsistencyCached)
+
+	ms, err := j.ModelStatus(cachedCtx, openfga.NewUser(&alice, client), mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(ms.ModelTag, qt.Equals, mt.String())
+	c.Check(ms.HostedMachineCount, qt.Equals, 1)
+	c.Check(ms.UnitCount, qt.Equals, 3)
+	c.Check(ms.ApplicationCount, qt.Equals, 2)
+	c.Check(ms.Applications, qt.DeepEquals, []jujuparams.ModelApplicationInfo{{Name: "db"}, {Name: "web"}})
+	c.Check(ms.Machines, qt.DeepEquals, []jujuparams.ModelMachineInfo{{Id: "0", InstanceId: "i-0", DisplayName: "juju-0"}})
+
+	// Cached status requires the same access as fresh status.
+	_, err = j.ModelStatus(cachedCtx, openfga.NewUser(&bob, client), mt)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	mi, err := j.ModelInfo(cachedCtx, openfga.NewUser(&alice, client), mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(mi.Name, qt.Equals, "model-1")
+	c.Check(mi.UUID, qt.Equals, mt.Id())
+	c.Check(mi.ControllerUUID, qt.Equals, "00000001-0000-0000-0000-000000000001")
+	c.Check(mi.DefaultSeries, qt.Equals, "warty")
+	c.Check(mi.CloudTag, qt.Equals, names.NewCloudTag("test-cloud").String())
+	c.Check(mi.CloudRegion, qt.Equals, "test-cloud-region")
+	c.Check(mi.OwnerTag, qt.Equals, names.NewUserTag("alice@canonical.com").String())
+	c.Check(mi.Machines, qt.HasLen, 1)
+	c.Check(mi.Users, qt.HasLen, 3)
+
+	// Fresh reads, the default, contact the controller.
+	_, err = j.ModelInfo(ctx, openfga.NewUser(&alice, client), mt)
+	c.Check(err, qt.ErrorMatches, `unexpected dial`)
+	_, err = j.ModelStatus(jimm.ContextWithReadConsistency(ctx, apiparams.ReadConsistencyFresh), openfga.NewUser(&alice, client), mt)
+	c.Check(err, qt.ErrorMatches, `unexpected dial`)
+
+	_, err = j.ModelInfo(jimm.ContextWithReadConsistency(ctx, "eventual"), openfga.NewUser(&alice, client), mt)
+	c.Check(err, qt.ErrorMatches, `invalid read consistency "eventual"`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestFreshCloudCredentialReads(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	dialer := &jimmtest.Dialer{
+		API: &jimmtest.API{
+			ModelInfo_: func(_ context.Context, mi *jujuparams.ModelInfo) error {
+				if mi.UUID != "00000002-0000-0000-0000-000000000001" {
+					return errors.E("unexpected model")
+				}
+				valid := false
+				mi.CloudCredentialValidity = &valid
+				return nil
+			},
+		},
+	}
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: dialer,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	alice := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&alice, client)
+	tag := names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1")
+
+	// Cached reads, the default, use the validity in JIMM's store.
+	cred, err := j.GetCloudCredential(ctx, user, tag)
+	c.Assert(err, qt.IsNil)
+	c.Check(cred.Valid.Valid, qt.IsFalse)
+
+	freshCtx := jimm.ContextWithReadConsistency(ctx, apiparams.ReadConsistencyFresh)
+	cred, err = j.GetCloudCredential(freshCtx, user, tag)
+	c.Assert(err, qt.IsNil)
+	c.Check(cred.Valid.Valid, qt.IsTrue)
+	c.Check(cred.Valid.Bool, qt.IsFalse)
+
+	var creds []*dbmodel.CloudCredential
+	err = j.ForEachUserCloudCredential(freshCtx, &alice, names.CloudTag{}, func(cred *dbmodel.CloudCredential) error {
+		creds = append(creds, cred)
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(creds, qt.HasLen, 1)
+	c.Check(creds[0].Valid.Valid, qt.IsTrue)
+	c.Check(creds[0].Valid.Bool, qt.IsFalse)
+	c.Check(dialer.IsClosed(), qt.IsTrue)
+
+	// The stored validity is not changed by a fresh read.
+	cred, err = j.GetCloudCredential(ctx, user, tag)
+	c.Assert(err, qt.IsNil)
+	c.Check(cred.Valid.Valid, qt.IsFalse)
+}
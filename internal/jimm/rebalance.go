@@ -0,0 +1,197 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/juju/juju/core/life"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// RebalanceRecommendations compares the placement of models against the
+// load on each available controller and recommends model migrations that
+// would balance the models across the controllers. Models are moved off
+// deprecated controllers and controllers hosting more than the maximum
+// number of models first, then from the busiest controllers to the
+// quietest ones that host the model's cloud-region. Smaller models,
+// those with fewer machines, are preferred as they are quicker to
+// migrate. Unavailable controllers are ignored and degraded controllers
+// are not recommended as targets. If limit is greater than
+// zero at most limit migrations are recommended. The recommendations
+// are suitable for passing to MigrateModel, they are not checked with
+// MigrationPrechecks. Only JIMM administrators may request
+// recommendations.
+func (j *JIMM) RebalanceRecommendations(ctx context.Context, user *openfga.User, limit int) (apiparams.RebalanceReport, error) {
+	const op = errors.Op("jimm.RebalanceRecommendations")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return apiparams.RebalanceReport{}, errors.E(op, err)
+	}
+
+	var loads []*controllerLoad
+	byID := make(map[uint]*controllerLoad)
+	err := j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if ctl.UnavailableSince.Valid {
+			return nil
+		}
+		l := &controllerLoad{
+			controller: *ctl,
+			regions:    make(map[uint]bool),
+			degraded:   j.Health.Degraded(ctl.Name),
+		}
+		for _, crp := range ctl.CloudRegions {
+			l.regions[crp.CloudRegionID] = true
+		}
+		loads = append(loads, l)
+		byID[ctl.ID] = l
+		return nil
+	})
+	if err != nil {
+		return apiparams.RebalanceReport{}, errors.E(op, err)
+	}
+	err = j.Database.ForEachModel(ctx, func(m *dbmodel.Model) error {
+		l := byID[m.ControllerID]
+		if l == nil {
+			return nil
+		}
+		l.models++
+		l.machines += m.Machines
+		// Models that are dying, or already migrating, cannot be
+		// moved.
+		if !life.IsNotAlive(life.Value(m.Life)) && !m.MigrationControllerID.Valid {
+			l.candidates = append(l.candidates, *m)
+		}
+		return nil
+	})
+	if err != nil {
+		return apiparams.RebalanceReport{}, errors.E(op, err)
+	}
+
+	maxModels := j.maxControllerModels()
+	report := apiparams.RebalanceReport{
+		MaxControllerModels: maxModels,
+		Recommendations:     []apiparams.RebalanceRecommendation{},
+	}
+	for _, l := range loads {
+		report.Controllers = append(report.Controllers, apiparams.ControllerLoad{
+			Name:       l.controller.Name,
+			Deprecated: l.controller.Deprecated,
+			Models:     l.models,
+			Machines:   l.machines,
+		})
+		// Consider the smallest models first.
+		sort.SliceStable(l.candidates, func(i, k int) bool {
+			return l.candidates[i].Machines < l.candidates[k].Machines
+		})
+	}
+	for limit <= 0 || len(report.Recommendations) < limit {
+		rec, ok := nextRebalanceMove(loads, maxModels)
+		if !ok {
+			break
+		}
+		report.Recommendations = append(report.Recommendations, rec)
+	}
+	for i, l := range loads {
+		report.Controllers[i].ModelsAfter = l.models
+		report.Controllers[i].MachinesAfter = l.machines
+	}
+	return report, nil
+}
+
+// A controllerLoad holds the load on a controller while rebalance
+// recommendations are calculated.
+type controllerLoad struct {
+	controller dbmodel.Controller
+	regions    map[uint]bool
+	models     int
+	machines   int64
+	degraded   bool
+
+	// candidates holds the models that may be moved off the
+	// controller, smallest first.
+	candidates []dbmodel.Model
+}
+
+// overloaded reports whether the controller must shed models.
+func (l *controllerLoad) overloaded(maxModels int) bool {
+	return l.controller.Deprecated || (maxModels > 0 && l.models > maxModels)
+}
+
+// nextRebalanceMove finds the next recommended migration and updates the
+// given loads to account for it. maxModels is the maximum number of
+// models a controller may host, zero meaning no limit. If no migration
+// would improve the balance of the controllers false is returned.
+func nextRebalanceMove(loads []*controllerLoad, maxModels int) (apiparams.RebalanceRecommendation, bool) {
+	sources := make([]*controllerLoad, len(loads))
+	copy(sources, loads)
+	sort.SliceStable(sources, func(i, k int) bool {
+		oi, ok := sources[i].overloaded(maxModels), sources[k].overloaded(maxModels)
+		if oi != ok {
+			return oi
+		}
+		return sources[i].models > sources[k].models
+	})
+
+	for _, src := range sources {
+		overloaded := src.overloaded(maxModels)
+		for i, m := range src.candidates {
+			var target *controllerLoad
+			for _, l := range loads {
+				switch {
+				case l == src, l.controller.Deprecated, l.degraded, !l.regions[m.CloudRegionID]:
+					continue
+				case maxModels > 0 && l.models >= maxModels:
+					continue
+				case target == nil, l.models < target.models:
+					target = l
+				case l.models == target.models && l.machines < target.machines:
+					target = l
+				}
+			}
+			if target == nil {
+				continue
+			}
+			// Moving a model between controllers whose model counts
+			// differ by one would not improve the balance.
+			if !overloaded && src.models-target.models < 2 {
+				continue
+			}
+
+			rec := apiparams.RebalanceRecommendation{
+				ModelTag:         m.ResourceTag().String(),
+				Model:            m.OwnerIdentityName + "/" + m.Name,
+				SourceController: src.controller.Name,
+				TargetController: target.controller.Name,
+				Machines:         m.Machines,
+				Reason:           rebalanceReason(src, target, maxModels),
+			}
+			src.candidates = append(src.candidates[:i:i], src.candidates[i+1:]...)
+			src.models--
+			src.machines -= m.Machines
+			target.models++
+			target.machines += m.Machines
+			return rec, true
+		}
+	}
+	return apiparams.RebalanceRecommendation{}, false
+}
+
+// rebalanceReason describes why a model is recommended to be moved from
+// src to target.
+func rebalanceReason(src, target *controllerLoad, maxModels int) string {
+	switch {
+	case src.controller.Deprecated:
+		return fmt.Sprintf("controller %s is deprecated", src.controller.Name)
+	case maxModels > 0 && src.models > maxModels:
+		return fmt.Sprintf("controller %s hosts %d models, the maximum is %d", src.controller.Name, src.models, maxModels)
+	default:
+		return fmt.Sprintf("controller %s hosts %d models, controller %s hosts %d", src.controller.Name, src.models, target.controller.Name, target.models)
+	}
+}
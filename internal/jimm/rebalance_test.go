@@ -0,0 +1,205 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const rebalanceTestEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+  - name: other-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+  cloud-regions:
+  - cloud: test
+    region: test-region
+    priority: 1
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test
+  region: test-region
+  cloud-regions:
+  - cloud: test
+    region: test-region
+    priority: 1
+- name: controller-3
+  uuid: 00000001-0000-0000-0000-000000000003
+  cloud: test
+  region: test-region
+  cloud-regions:
+  - cloud: test
+    region: test-region
+    priority: 1
+- name: controller-4
+  uuid: 00000001-0000-0000-0000-000000000004
+  cloud: test
+  region: other-region
+  cloud-regions:
+  - cloud: test
+    region: other-region
+    priority: 1
+models:
+- name: model-a
+  uuid: 00000002-0000-0000-0000-00000000000a
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-1
+  machines: 3
+- name: model-b
+  uuid: 00000002-0000-0000-0000-00000000000b
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-1
+  machines: 1
+- name: model-c
+  uuid: 00000002-0000-0000-0000-00000000000c
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-1
+  machines: 2
+- name: model-d
+  uuid: 00000002-0000-0000-0000-00000000000d
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-1
+- name: model-e
+  uuid: 00000002-0000-0000-0000-00000000000e
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-2
+  machines: 1
+- name: model-f
+  uuid: 00000002-0000-0000-0000-00000000000f
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-3
+  machines: 2
+`
+
+func TestRebalanceRecommendations(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, rebalanceTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	ctl := dbmodel.Controller{Name: "controller-3"}
+	err = j.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	ctl.Deprecated = true
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	_, err = j.RebalanceRecommendations(ctx, alice, 0)
+	c.Check(err, qt.ErrorMatches, `unauthorized`)
+
+	alice.JimmAdmin = true
+	report, err := j.RebalanceRecommendations(ctx, alice, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(report, qt.DeepEquals, apiparams.RebalanceReport{
+		Controllers: []apiparams.ControllerLoad{{
+			Name:          "controller-1",
+			Models:        4,
+			Machines:      6,
+			ModelsAfter:   3,
+			MachinesAfter: 6,
+		}, {
+			Name:          "controller-2",
+			Models:        1,
+			Machines:      1,
+			ModelsAfter:   3,
+			MachinesAfter: 3,
+		}, {
+			Name:          "controller-3",
+			Deprecated:    true,
+			Models:        1,
+			Machines:      2,
+			ModelsAfter:   0,
+			MachinesAfter: 0,
+		}, {
+			Name: "controller-4",
+		}},
+		Recommendations: []apiparams.RebalanceRecommendation{{
+			ModelTag:         "model-00000002-0000-0000-0000-00000000000f",
+			Model:            "alice@canonical.com/model-f",
+			SourceController: "controller-3",
+			TargetController: "controller-2",
+			Machines:         2,
+			Reason:           "controller controller-3 is deprecated",
+		}, {
+			ModelTag:         "model-00000002-0000-0000-0000-00000000000d",
+			Model:            "alice@canonical.com/model-d",
+			SourceController: "controller-1",
+			TargetController: "controller-2",
+			Reason:           "controller controller-1 hosts 4 models, controller controller-2 hosts 2",
+		}},
+	})
+
+	report, err = j.RebalanceRecommendations(ctx, alice, 1)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.Recommendations, qt.HasLen, 1)
+	c.Check(report.Controllers[1].ModelsAfter, qt.Equals, 2)
+
+	// Controllers at capacity are not migration targets.
+	j.MaxControllerModels = 2
+	report, err = j.RebalanceRecommendations(ctx, alice, 0)
+	c.Assert(err, qt.IsNil)
+	c.Check(report.MaxControllerModels, qt.Equals, 2)
+	c.Check(report.Recommendations, qt.DeepEquals, []apiparams.RebalanceRecommendation{{
+		ModelTag:         "model-00000002-0000-0000-0000-00000000000f",
+		Model:            "alice@canonical.com/model-f",
+		SourceController: "controller-3",
+		TargetController: "controller-2",
+		Machines:         2,
+		Reason:           "controller controller-3 is deprecated",
+	}})
+}
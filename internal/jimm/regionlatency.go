@@ -0,0 +1,342 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// LatencySensitiveConfigKey is the model config attribute used to request
+// that a model is placed using the measured latencies of the cloud
+// regions and controllers. The attribute may also be set in a user's
+// model defaults. It is not passed on to the controller.
+const LatencySensitiveConfigKey = "jimm-latency-sensitive"
+
+// defaultLatencyProbeTimeout is the time allowed to connect to an
+// endpoint before the probe is considered failed.
+const defaultLatencyProbeTimeout = 5 * time.Second
+
+// A LatencyMeasurement holds the result of the latest probe of an
+// endpoint.
+type LatencyMeasurement struct {
+	// Address is the address that was probed.
+	Address string
+
+	// Latency is the time taken to connect to the address.
+	Latency time.Duration
+
+	// Time is the time the probe was made.
+	Time time.Time
+
+	// Err holds the error from the probe, if it failed.
+	Err error
+}
+
+// A LatencyProber measures the time taken to connect from JIMM to the
+// representative endpoint of each cloud region and to each controller.
+// The measurements are used to break ties when placing models that are
+// latency sensitive. A nil LatencyProber is valid, it measures nothing
+// and never reorders controllers.
+type LatencyProber struct {
+	// Timeout is the time allowed to connect to an endpoint. If this is
+	// zero a default of 5 seconds is used.
+	Timeout time.Duration
+
+	// Dial is used to connect to the endpoints. If this is nil a
+	// net.Dialer is used. It may be replaced in tests.
+	Dial func(ctx context.Context, network, address string) (net.Conn, error)
+
+	// now is used to get the current time, it may be replaced in tests.
+	now func() time.Time
+
+	mu          sync.Mutex
+	regions     map[string]LatencyMeasurement
+	controllers map[string]LatencyMeasurement
+}
+
+// NewLatencyProber creates a new LatencyProber that allows the given
+// timeout to connect to each endpoint.
+func NewLatencyProber(timeout time.Duration) *LatencyProber {
+	return &LatencyProber{Timeout: timeout}
+}
+
+// probe connects to the given address and records the time taken.
+func (p *LatencyProber) probe(ctx context.Context, address string) LatencyMeasurement {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = defaultLatencyProbeTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dial := p.Dial
+	if dial == nil {
+		var d net.Dialer
+		dial = d.DialContext
+	}
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	m := LatencyMeasurement{Address: address, Time: now()}
+	start := time.Now()
+	conn, err := dial(ctx, "tcp", address)
+	if err != nil {
+		m.Err = err
+		return m
+	}
+	m.Latency = time.Since(start)
+	conn.Close()
+	return m
+}
+
+// RegionLatency returns the latest measurement for the given cloud
+// region and whether the region has been successfully probed.
+func (p *LatencyProber) RegionLatency(cloud, region string) (LatencyMeasurement, bool) {
+	if p == nil {
+		return LatencyMeasurement{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m, ok := p.regions[regionKey(cloud, region)]
+	return m, ok && m.Err == nil
+}
+
+// ControllerLatency returns the latest measurement for the named
+// controller and whether the controller has been successfully probed.
+func (p *LatencyProber) ControllerLatency(controller string) (LatencyMeasurement, bool) {
+	if p == nil {
+		return LatencyMeasurement{}, false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m, ok := p.controllers[controller]
+	return m, ok && m.Err == nil
+}
+
+// sortRegionControllers orders controllers in the given slice, which
+// must already be ordered by health and priority, that share a priority
+// and health by their measured latency. Controllers that have not been
+// measured are placed after the measured ones.
+func (p *LatencyProber) sortRegionControllers(h *ControllerHealth, crps []dbmodel.CloudRegionControllerPriority) {
+	if p == nil {
+		return
+	}
+	type key struct {
+		latency  time.Duration
+		measured bool
+		degraded bool
+	}
+	keys := make(map[string]key, len(crps))
+	for _, crp := range crps {
+		m, ok := p.ControllerLatency(crp.Controller.Name)
+		keys[crp.Controller.Name] = key{
+			latency:  m.Latency,
+			measured: ok,
+			degraded: h.Degraded(crp.Controller.Name),
+		}
+	}
+	sort.SliceStable(crps, func(i, k int) bool {
+		ki, kk := keys[crps[i].Controller.Name], keys[crps[k].Controller.Name]
+		if ki.degraded != kk.degraded {
+			return kk.degraded
+		}
+		if crps[i].Priority != crps[k].Priority {
+			return crps[i].Priority > crps[k].Priority
+		}
+		if ki.measured != kk.measured {
+			return ki.measured
+		}
+		return ki.latency < kk.latency
+	})
+}
+
+// lowestLatencyRegion returns the name of the region, from the given
+// candidates, with the lowest measured latency. If none of the regions
+// have been measured an empty string is returned.
+func (p *LatencyProber) lowestLatencyRegion(cloud string, regions []string) string {
+	var best string
+	var bestLatency time.Duration
+	for _, r := range regions {
+		m, ok := p.RegionLatency(cloud, r)
+		if !ok {
+			continue
+		}
+		if best == "" || m.Latency < bestLatency {
+			best, bestLatency = r, m.Latency
+		}
+	}
+	return best
+}
+
+// ProbeLatencies measures the latency from JIMM to the endpoint of every
+// known cloud region and to every available controller. Regions without
+// an endpoint are not probed. Failed probes are recorded and reported
+// but do not stop the remaining endpoints being probed.
+func (j *JIMM) ProbeLatencies(ctx context.Context) error {
+	const op = errors.Op("jimm.ProbeLatencies")
+
+	p := j.Latency
+	if p == nil {
+		return nil
+	}
+
+	clouds, err := j.Database.GetClouds(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	regions := make(map[string]LatencyMeasurement)
+	for _, c := range clouds {
+		for _, r := range c.Regions {
+			endpoint := r.Endpoint
+			if endpoint == "" {
+				endpoint = c.Endpoint
+			}
+			address := endpointAddress(endpoint)
+			if address == "" {
+				continue
+			}
+			regions[regionKey(c.Name, r.Name)] = p.probe(ctx, address)
+		}
+	}
+
+	controllers := make(map[string]LatencyMeasurement)
+	err = j.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+		if ctl.UnavailableSince.Valid {
+			return nil
+		}
+		address := controllerAddress(ctl)
+		if address == "" {
+			return nil
+		}
+		controllers[ctl.Name] = p.probe(ctx, address)
+		return nil
+	})
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.regions = regions
+	p.controllers = controllers
+	return nil
+}
+
+// ListPlacementLatencies returns the latest latency measurements of the
+// cloud regions and controllers known to JIMM. Only JIMM administrators
+// may list the latencies.
+func (j *JIMM) ListPlacementLatencies(ctx context.Context, user *openfga.User) (apiparams.PlacementLatencies, error) {
+	const op = errors.Op("jimm.ListPlacementLatencies")
+
+	var resp apiparams.PlacementLatencies
+	if !user.JimmAdmin {
+		return resp, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	p := j.Latency
+	if p == nil {
+		return resp, errors.E(op, errors.CodeNotSupported, "latency probing not configured")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	resp.Regions = make([]apiparams.RegionLatency, 0, len(p.regions))
+	for k, m := range p.regions {
+		cloud, region := splitRegionKey(k)
+		resp.Regions = append(resp.Regions, apiparams.RegionLatency{
+			Cloud:       cloud,
+			Region:      region,
+			Measurement: latencyMeasurement(m),
+		})
+	}
+	sort.Slice(resp.Regions, func(i, k int) bool {
+		if resp.Regions[i].Cloud != resp.Regions[k].Cloud {
+			return resp.Regions[i].Cloud < resp.Regions[k].Cloud
+		}
+		return resp.Regions[i].Region < resp.Regions[k].Region
+	})
+	resp.Controllers = make([]apiparams.ControllerLatency, 0, len(p.controllers))
+	for name, m := range p.controllers {
+		resp.Controllers = append(resp.Controllers, apiparams.ControllerLatency{
+			Controller:  name,
+			Measurement: latencyMeasurement(m),
+		})
+	}
+	sort.Slice(resp.Controllers, func(i, k int) bool {
+		return resp.Controllers[i].Controller < resp.Controllers[k].Controller
+	})
+	return resp, nil
+}
+
+func latencyMeasurement(m LatencyMeasurement) apiparams.LatencyMeasurement {
+	lm := apiparams.LatencyMeasurement{
+		Address: m.Address,
+		Time:    m.Time,
+	}
+	if m.Err != nil {
+		lm.Error = m.Err.Error()
+	} else {
+		lm.LatencyMS = m.Latency.Milliseconds()
+	}
+	return lm
+}
+
+// regionKey returns the key used to hold the measurements of a cloud
+// region. Cloud names cannot contain a "/".
+func regionKey(cloud, region string) string {
+	return cloud + "/" + region
+}
+
+func splitRegionKey(k string) (cloud, region string) {
+	cloud, region, _ = strings.Cut(k, "/")
+	return cloud, region
+}
+
+// endpointAddress returns the host:port address to probe for the given
+// cloud endpoint. An endpoint without a port uses the default port for
+// its scheme, or 443 if there is no scheme.
+func endpointAddress(endpoint string) string {
+	if endpoint == "" {
+		return ""
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		// The endpoint may be a bare host or host:port.
+		u = &url.URL{Host: endpoint}
+	}
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "443"
+	if u.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// controllerAddress returns the address to probe for the given
+// controller, its public address if it has one, otherwise its first
+// known address.
+func controllerAddress(ctl *dbmodel.Controller) string {
+	if ctl.PublicAddress != "" {
+		return ctl.PublicAddress
+	}
+	for _, hps := range ctl.Addresses {
+		for _, hp := range hps {
+			return net.JoinHostPort(hp.Value, strconv.Itoa(hp.Port))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,288 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestProbeLatencies(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var dialed []string
+	p := jimm.NewLatencyProber(time.Second)
+	p.Dial = func(_ context.Context, network, address string) (net.Conn, error) {
+		c.Check(network, qt.Equals, "tcp")
+		dialed = append(dialed, address)
+		if address == "unreachable.example.com:443" {
+			return nil, errors.E("connection refused")
+		}
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	}
+	jimm.SetLatencyProberClock(p, func() time.Time { return now })
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Latency: p,
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	err = j.Database.AddCloud(ctx, &dbmodel.Cloud{
+		Name:     "test-cloud",
+		Type:     "openstack",
+		Endpoint: "https://cloud.example.com",
+		Regions: []dbmodel.CloudRegion{{
+			Name:     "region-1",
+			Endpoint: "https://region-1.example.com:5000/v3",
+		}, {
+			Name: "region-2",
+		}, {
+			Name:     "region-3",
+			Endpoint: "unreachable.example.com",
+		}},
+	})
+	c.Assert(err, qt.IsNil)
+	for _, ctl := range []dbmodel.Controller{{
+		Name:          "controller-1",
+		UUID:          "00000001-0000-0000-0000-000000000001",
+		PublicAddress: "controller-1.example.com:17070",
+	}, {
+		Name: "controller-2",
+		UUID: "00000001-0000-0000-0000-000000000002",
+		Addresses: dbmodel.HostPorts{{{
+			Address: jujuparams.Address{Value: "10.0.0.2", Type: "ipv4"},
+			Port:    17070,
+		}}},
+	}, {
+		Name:             "controller-3",
+		UUID:             "00000001-0000-0000-0000-000000000003",
+		PublicAddress:    "controller-3.example.com:17070",
+		UnavailableSince: sql.NullTime{Time: now, Valid: true},
+	}} {
+		ctl := ctl
+		err := j.Database.AddController(ctx, &ctl)
+		c.Assert(err, qt.IsNil)
+	}
+
+	err = j.ProbeLatencies(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(dialed, qt.ContentEquals, []string{
+		"region-1.example.com:5000",
+		"cloud.example.com:443",
+		"unreachable.example.com:443",
+		"controller-1.example.com:17070",
+		"10.0.0.2:17070",
+	})
+
+	_, ok := p.RegionLatency("test-cloud", "region-1")
+	c.Check(ok, qt.IsTrue)
+	_, ok = p.RegionLatency("test-cloud", "region-3")
+	c.Check(ok, qt.IsFalse)
+	_, ok = p.ControllerLatency("controller-2")
+	c.Check(ok, qt.IsTrue)
+	_, ok = p.ControllerLatency("controller-3")
+	c.Check(ok, qt.IsFalse)
+
+	user := openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, nil)
+	_, err = j.ListPlacementLatencies(ctx, user)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	user.JimmAdmin = true
+	resp, err := j.ListPlacementLatencies(ctx, user)
+	c.Assert(err, qt.IsNil)
+	for i := range resp.Regions {
+		resp.Regions[i].Measurement.LatencyMS = 0
+	}
+	for i := range resp.Controllers {
+		resp.Controllers[i].Measurement.LatencyMS = 0
+	}
+	c.Check(resp, qt.DeepEquals, apiparams.PlacementLatencies{
+		Regions: []apiparams.RegionLatency{{
+			Cloud:       "test-cloud",
+			Region:      "region-1",
+			Measurement: apiparams.LatencyMeasurement{Address: "region-1.example.com:5000", Time: now},
+		}, {
+			Cloud:       "test-cloud",
+			Region:      "region-2",
+			Measurement: apiparams.LatencyMeasurement{Address: "cloud.example.com:443", Time: now},
+		}, {
+			Cloud:  "test-cloud",
+			Region: "region-3",
+			Measurement: apiparams.LatencyMeasurement{
+				Address: "unreachable.example.com:443",
+				Time:    now,
+				Error:   "connection refused",
+			},
+		}},
+		Controllers: []apiparams.ControllerLatency{{
+			Controller:  "controller-1",
+			Measurement: apiparams.LatencyMeasurement{Address: "controller-1.example.com:17070", Time: now},
+		}, {
+			Controller:  "controller-2",
+			Measurement: apiparams.LatencyMeasurement{Address: "10.0.0.2:17070", Time: now},
+		}},
+	})
+}
+
+func TestListPlacementLatenciesNotConfigured(t *testing.T) {
+	c := qt.New(t)
+
+	j := &jimm.JIMM{}
+	user := openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil)
+	user.JimmAdmin = true
+	_, err := j.ListPlacementLatencies(context.Background(), user)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotSupported)
+}
+
+func TestAddModelLatencySensitive(t *testing.T) {
+	c := qt.New(t)
+
+	api := &jimmtest.API{
+		UpdateCredential_: func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error) {
+			return nil, nil
+		},
+		GrantJIMMModelAdmin_: func(context.Context, names.ModelTag) error {
+			return nil
+		},
+		CreateModel_: assertConfig(map[string]interface{}{}, createModel(`
+uuid: 00000001-0000-0000-0000-0000-000000000001
+status:
+  status: started
+life: alive
+users:
+- user: alice@canonical.com
+  access: admin
+`[1:])),
+	}
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: api,
+		},
+		Latency: jimm.NewLatencyProber(0),
+	}
+	ctx := context.Background()
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, `
+clouds:
+- name: test-cloud
+  type: test-provider
+  regions:
+  - name: test-region-1
+  - name: test-region-2
+users:
+- username: alice@canonical.com
+  controller-access: superuser
+cloud-credentials:
+- name: test-credential-1
+  owner: alice@canonical.com
+  cloud: test-cloud
+  auth-type: empty
+controllers:
+- name: controller-1
+  uuid: 00000000-0000-0000-0000-0000-0000000000001
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+- name: controller-2
+  uuid: 00000000-0000-0000-0000-0000-0000000000002
+  cloud: test-cloud
+  region: test-region-1
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-1
+    priority: 10
+- name: controller-3
+  uuid: 00000000-0000-0000-0000-0000-0000000000003
+  cloud: test-cloud
+  region: test-region-2
+  cloud-regions:
+  - cloud: test-cloud
+    region: test-region-2
+    priority: 10
+`[1:])
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// Without a region the last region with controllers would be used,
+	// the measured latencies choose the other region and the closer of
+	// its controllers.
+	jimm.SetRegionLatency(j.Latency, "test-cloud", "test-region-1", 10*time.Millisecond)
+	jimm.SetRegionLatency(j.Latency, "test-cloud", "test-region-2", 100*time.Millisecond)
+	jimm.SetControllerLatency(j.Latency, "controller-1", 50*time.Millisecond)
+	jimm.SetControllerLatency(j.Latency, "controller-2", 5*time.Millisecond)
+
+	dbUser := env.User("alice@canonical.com").DBObject(c, j.Database)
+	user := openfga.NewUser(&dbUser, client)
+
+	args := jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:     "test-model",
+		OwnerTag: user.Tag().String(),
+		CloudTag: names.NewCloudTag("test-cloud").String(),
+		Config:   map[string]interface{}{"jimm-latency-sensitive": "true"},
+	})
+	c.Assert(err, qt.IsNil)
+	_, err = j.AddModel(ctx, user, &args)
+	c.Assert(err, qt.IsNil)
+
+	m := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000001-0000-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err = j.Database.GetModel(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Controller.Name, qt.Equals, "controller-2")
+	c.Check(m.CloudRegion.Name, qt.Equals, "test-region-1")
+
+	args = jimm.ModelCreateArgs{}
+	err = args.FromJujuModelCreateArgs(&jujuparams.ModelCreateArgs{
+		Name:     "test-model-2",
+		OwnerTag: user.Tag().String(),
+		CloudTag: names.NewCloudTag("test-cloud").String(),
+		Config:   map[string]interface{}{"jimm-latency-sensitive": 1},
+	})
+	c.Assert(err, qt.IsNil)
+	_, err = j.AddModel(ctx, user, &args)
+	c.Check(err, qt.ErrorMatches, `invalid jimm-latency-sensitive value`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
@@ -20,6 +20,7 @@ import (
 // At the moment user is required be admin.
 func (j *JIMM) AddRelation(ctx context.Context, user *openfga.User, tuples []apiparams.RelationshipTuple) error {
 	const op = errors.Op("jimm.AddRelation")
+	defer j.Cache.InvalidateAllModelAccess()
 	if !user.JimmAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
@@ -38,6 +39,7 @@ func (j *JIMM) AddRelation(ctx context.Context, user *openfga.User, tuples []api
 // At the moment user is required be admin.
 func (j *JIMM) RemoveRelation(ctx context.Context, user *openfga.User, tuples []apiparams.RelationshipTuple) error {
 	const op = errors.Op("jimm.RemoveRelation")
+	defer j.Cache.InvalidateAllModelAccess()
 	if !user.JimmAdmin {
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
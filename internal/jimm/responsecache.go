@@ -0,0 +1,259 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/common/cache"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// ResponseCacheParams holds the time-to-live of the entries in each of
+// the caches held by a ResponseCache. A non-positive TTL disables the
+// corresponding cache.
+type ResponseCacheParams struct {
+	// CloudTTL is the time-to-live of cached clouds.
+	CloudTTL time.Duration
+
+	// ControllerTTL is the time-to-live of cached controller lists.
+	ControllerTTL time.Duration
+
+	// GroupTTL is the time-to-live of cached group names, used when
+	// resolving group UUIDs in OpenFGA tuples to names.
+	GroupTTL time.Duration
+
+	// ModelAccessTTL is the time-to-live of cached model access
+	// checks. Only checks finding some access are cached, so a user
+	// without access being granted access is seen immediately. Changes
+	// to model access made through JIMM invalidate the cache, this is
+	// the longest time any other change, a revocation or a change to a
+	// user's access level, might take to be seen.
+	ModelAccessTTL time.Duration
+}
+
+// A ResponseCache caches the results of frequently performed database
+// and OpenFGA reads. A nil ResponseCache is valid and caches nothing.
+type ResponseCache struct {
+	clouds      *cache.Cache[[]dbmodel.Cloud]
+	controllers *cache.Cache[[]dbmodel.Controller]
+	modelAccess *cache.Cache[string]
+	groupNames  *cache.Cache[string]
+
+	// source identifies this cache in the invalidations it broadcasts
+	// so that it can ignore its own invalidations.
+	source string
+
+	// broadcasting is set while RunInvalidationBus is running, when
+	// invalidations are queued on the invalidations channel to be sent
+	// to the other replicas.
+	broadcasting  atomic.Bool
+	invalidations chan CacheInvalidation
+}
+
+// NewResponseCache creates a new ResponseCache using the given
+// parameters.
+func NewResponseCache(p ResponseCacheParams) *ResponseCache {
+	return &ResponseCache{
+		clouds:        cache.New[[]dbmodel.Cloud]("clouds", p.CloudTTL),
+		controllers:   cache.New[[]dbmodel.Controller]("controllers", p.ControllerTTL),
+		modelAccess:   cache.New[string]("model_access", p.ModelAccessTTL),
+		groupNames:    cache.New[string]("group_names", p.GroupTTL),
+		source:        uuid.NewString(),
+		invalidations: make(chan CacheInvalidation, invalidationQueueSize),
+	}
+}
+
+// InvalidateClouds removes all cached clouds.
+func (c *ResponseCache) InvalidateClouds() {
+	c.invalidate(CacheInvalidation{Kind: InvalidateClouds})
+}
+
+// InvalidateControllers removes all cached controllers. The cached
+// clouds include the controllers hosting their regions so these are
+// removed too.
+func (c *ResponseCache) InvalidateControllers() {
+	c.invalidate(CacheInvalidation{Kind: InvalidateControllers})
+}
+
+// InvalidateModelAccess removes the cached access of all users to the
+// given model.
+func (c *ResponseCache) InvalidateModelAccess(mt names.ModelTag) {
+	c.invalidate(CacheInvalidation{Kind: InvalidateModelAccess, Key: mt.Id()})
+}
+
+// InvalidateAllModelAccess removes the cached access of all users to all
+// models. This is used when a change, such as a change in group
+// membership, could affect the access of any user to any model.
+func (c *ResponseCache) InvalidateAllModelAccess() {
+	c.invalidate(CacheInvalidation{Kind: InvalidateAllModelAccess})
+}
+
+// InvalidateGroups removes all cached group names.
+func (c *ResponseCache) InvalidateGroups() {
+	c.invalidate(CacheInvalidation{Kind: InvalidateGroups})
+}
+
+// invalidate applies the given invalidation to the cache and, if an
+// invalidation bus is running, queues it to be broadcast to the other
+// replicas.
+func (c *ResponseCache) invalidate(inv CacheInvalidation) {
+	if c == nil {
+		return
+	}
+	c.apply(inv)
+	if !c.broadcasting.Load() {
+		return
+	}
+	inv.Source = c.source
+	inv.Time = time.Now()
+	select {
+	case c.invalidations <- inv:
+	default:
+		// The bus cannot keep up, the other replicas will see
+		// the change once their cached values expire.
+		servermon.CacheInvalidationsSentCount.WithLabelValues("dropped").Inc()
+	}
+}
+
+// apply removes the values affected by the given invalidation from the
+// cache.
+func (c *ResponseCache) apply(inv CacheInvalidation) {
+	switch inv.Kind {
+	case InvalidateClouds:
+		c.clouds.Purge()
+	case InvalidateControllers:
+		c.controllers.Purge()
+		c.clouds.Purge()
+	case InvalidateModelAccess:
+		c.modelAccess.DeletePrefix(inv.Key + "/")
+	case InvalidateAllModelAccess:
+		c.modelAccess.Purge()
+	case InvalidateGroups:
+		c.groupNames.Purge()
+	default:
+		// An invalidation this replica does not understand, most
+		// likely from a newer version of JIMM. Purge everything to
+		// be safe.
+		c.purge()
+	}
+}
+
+// purge removes all values from the cache.
+func (c *ResponseCache) purge() {
+	c.clouds.Purge()
+	c.controllers.Purge()
+	c.modelAccess.Purge()
+	c.groupNames.Purge()
+}
+
+// getModelAccess returns the access level the given user has to the
+// given model, using the cache if possible. Only checks finding some
+// access are cached so that access granted to a user without access is
+// seen immediately, see ResponseCacheParams.ModelAccessTTL.
+func (j *JIMM) getModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag) string {
+	var access *cache.Cache[string]
+	if j.Cache != nil {
+		access = j.Cache.modelAccess
+	}
+	key := mt.Id() + "/" + user.Name
+	if accessLevel, ok := access.Get(key); ok {
+		return accessLevel
+	}
+	accessLevel := ToModelAccessString(user.GetModelAccess(ctx, mt))
+	if accessLevel != "" {
+		access.Set(key, accessLevel)
+	}
+	return accessLevel
+}
+
+// getClouds returns all the clouds known to JIMM, using the cache if
+// possible. The clouds returned share their regions with the cache and
+// must not be modified.
+func (j *JIMM) getClouds(ctx context.Context) ([]dbmodel.Cloud, error) {
+	var clouds *cache.Cache[[]dbmodel.Cloud]
+	if j.Cache != nil {
+		clouds = j.Cache.clouds
+	}
+	if cl, ok := clouds.Get(""); ok {
+		return slices.Clone(cl), nil
+	}
+	cl, err := j.Database.GetClouds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clouds.Set("", slices.Clone(cl))
+	return cl, nil
+}
+
+// getControllers returns all the controllers known to JIMM, using the
+// cache if possible.
+func (j *JIMM) getControllers(ctx context.Context) ([]dbmodel.Controller, error) {
+	var controllers *cache.Cache[[]dbmodel.Controller]
+	if j.Cache != nil {
+		controllers = j.Cache.controllers
+	}
+	if ctls, ok := controllers.Get(""); ok {
+		return slices.Clone(ctls), nil
+	}
+	var ctls []dbmodel.Controller
+	err := j.Database.ForEachController(ctx, func(c *dbmodel.Controller) error {
+		ctls = append(ctls, *c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	controllers.Set("", slices.Clone(ctls))
+	return ctls, nil
+}
+
+// getCloud fills in the given cloud, which must have its name set. If
+// cloud caching is enabled the cloud is taken from the cached clouds,
+// otherwise it is read from the database. This should only be used
+// where the cloud is not going to be updated.
+func (j *JIMM) getCloud(ctx context.Context, c *dbmodel.Cloud) error {
+	if j.Cache == nil || !j.Cache.clouds.Enabled() {
+		return j.Database.GetCloud(ctx, c)
+	}
+	clouds, err := j.getClouds(ctx)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(clouds, func(cl dbmodel.Cloud) bool { return cl.Name == c.Name })
+	if i < 0 {
+		return errors.E(errors.CodeNotFound, fmt.Sprintf("cloud %q not found", c.Name))
+	}
+	*c = clouds[i]
+	return nil
+}
+
+// getGroupName returns the name of the group with the given UUID, using
+// the cache if possible.
+func (j *JIMM) getGroupName(ctx context.Context, uuid string) (string, error) {
+	var groupNames *cache.Cache[string]
+	if j.Cache != nil {
+		groupNames = j.Cache.groupNames
+	}
+	if name, ok := groupNames.Get(uuid); ok {
+		return name, nil
+	}
+	group := dbmodel.GroupEntry{
+		UUID: uuid,
+	}
+	if err := j.Database.GetGroup(ctx, &group); err != nil {
+		return "", err
+	}
+	groupNames.Set(uuid, group.Name)
+	return group.Name, nil
+}
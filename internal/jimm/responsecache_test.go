@@ -0,0 +1,165 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+)
+
+func TestResponseCache(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Dialer: &jimmtest.Dialer{
+			API: &jimmtest.API{},
+		},
+		Cache: jimm.NewResponseCache(jimm.ResponseCacheParams{
+			CloudTTL:       time.Hour,
+			ControllerTTL:  time.Hour,
+			GroupTTL:       time.Hour,
+			ModelAccessTTL: time.Hour,
+		}),
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, modelStatusTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	dianeIdentity := env.User("diane@canonical.com").DBObject(c, j.Database)
+	diane := openfga.NewUser(&dianeIdentity, client)
+	diane.JimmAdmin = true
+
+	cloudNames := func() []string {
+		var cloudNames []string
+		err := j.ForEachCloud(ctx, diane, func(cl *dbmodel.Cloud) error {
+			cloudNames = append(cloudNames, cl.Name)
+			return nil
+		})
+		c.Assert(err, qt.IsNil)
+		return cloudNames
+	}
+
+	// Clouds added outside of JIMM are not seen until the cache is
+	// invalidated.
+	c.Check(cloudNames(), qt.DeepEquals, []string{"test-cloud"})
+	err = j.Database.AddCloud(ctx, &dbmodel.Cloud{Name: "test-cloud-2", Type: "test-provider"})
+	c.Assert(err, qt.IsNil)
+	c.Check(cloudNames(), qt.DeepEquals, []string{"test-cloud"})
+	j.Cache.InvalidateClouds()
+	c.Check(cloudNames(), qt.DeepEquals, []string{"test-cloud", "test-cloud-2"})
+
+	// Controller updates made through JIMM invalidate the cache.
+	controllers, err := j.ListControllers(ctx, diane)
+	c.Assert(err, qt.IsNil)
+	c.Assert(controllers, qt.HasLen, 1)
+	ctl := controllers[0]
+	ctl.AgentVersion = "3.5.0"
+	err = j.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+	controllers, err = j.ListControllers(ctx, diane)
+	c.Assert(err, qt.IsNil)
+	c.Check(controllers[0].AgentVersion, qt.Not(qt.Equals), "3.5.0")
+	err = j.SetControllerDeprecated(ctx, diane, "controller-1", true)
+	c.Assert(err, qt.IsNil)
+	controllers, err = j.ListControllers(ctx, diane)
+	c.Assert(err, qt.IsNil)
+	c.Check(controllers[0].AgentVersion, qt.Equals, "3.5.0")
+	c.Check(controllers[0].Deprecated, qt.IsTrue)
+
+	// Model access changes made through JIMM invalidate the cache.
+	mt := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	bobIdentity := env.User("bob@canonical.com").DBObject(c, j.Database)
+	bob := openfga.NewUser(&bobIdentity, client)
+
+	access, err := j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "write")
+	err = bob.UnsetModelAccess(ctx, mt, ofganames.WriterRelation)
+	c.Assert(err, qt.IsNil)
+	access, err = j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "write")
+	err = j.GrantModelAccess(ctx, alice, mt, bob.ResourceTag(), jujuparams.ModelReadAccess)
+	c.Assert(err, qt.IsNil)
+	access, err = j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "read")
+
+	// Failed access checks are not cached.
+	err = j.RevokeModelAccess(ctx, alice, mt, bob.ResourceTag(), jujuparams.ModelReadAccess)
+	c.Assert(err, qt.IsNil)
+	access, err = j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "")
+	err = bob.SetModelAccess(ctx, mt, ofganames.ReaderRelation)
+	c.Assert(err, qt.IsNil)
+	access, err = j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "read")
+
+	// Group model access changes made through JIMM invalidate the cache.
+	modelGroup, err := j.Database.AddGroup(ctx, "model-group")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob.ResourceTag()),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(modelGroup.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+	err = j.GrantModelGroupAccess(ctx, alice, mt, "model-group", jujuparams.ModelWriteAccess)
+	c.Assert(err, qt.IsNil)
+	access, err = j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "write")
+	err = j.RevokeModelGroupAccess(ctx, alice, mt, "model-group", jujuparams.ModelWriteAccess)
+	c.Assert(err, qt.IsNil)
+	access, err = j.GetUserModelAccess(ctx, bob, mt)
+	c.Assert(err, qt.IsNil)
+	c.Check(access, qt.Equals, "read")
+
+	// Group names are cached when resolving group tags, renames made
+	// through JIMM invalidate the cache.
+	group, err := j.AddGroup(ctx, diane, "group-1")
+	c.Assert(err, qt.IsNil)
+	groupTag := ofganames.ConvertTag(group.ResourceTag())
+	t, err := j.ToJAASTag(ctx, groupTag, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(t, qt.Equals, "group-group-1")
+	group.Name = "group-2"
+	err = j.Database.UpdateGroup(ctx, group)
+	c.Assert(err, qt.IsNil)
+	t, err = j.ToJAASTag(ctx, groupTag, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(t, qt.Equals, "group-group-1")
+	err = j.RenameGroup(ctx, diane, "group-2", "group-3")
+	c.Assert(err, qt.IsNil)
+	t, err = j.ToJAASTag(ctx, groupTag, true)
+	c.Assert(err, qt.IsNil)
+	c.Check(t, qt.Equals, "group-group-3")
+}
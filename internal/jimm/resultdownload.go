@@ -0,0 +1,151 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+// defaultResultDownloadTTL is the time for which an oversized result may
+// be downloaded if no ResultDownloadTTL is configured.
+const defaultResultDownloadTTL = 15 * time.Minute
+
+// StoreOversizedResult checks the size of the given result of an
+// expensive call, such as a model dump or bundle export. If the result
+// is no larger than MaxInlineResultSize an empty string is returned and
+// the result should be returned inline. Otherwise the result is stored
+// and the URL it may be downloaded from, until it expires, is returned.
+// The URL contains an unguessable token which is all that is needed to
+// download the result, so it must only be given to the user that made
+// the call. If no ResultDownloadURL is configured oversized results are
+// rejected with an error with the code CodeResultTooLarge.
+func (j *JIMM) StoreOversizedResult(ctx context.Context, user *openfga.User, name, contentType string, data []byte) (string, error) {
+	const op = errors.Op("jimm.StoreOversizedResult")
+
+	if j.MaxInlineResultSize <= 0 || len(data) <= j.MaxInlineResultSize {
+		return "", nil
+	}
+	if j.ResultDownloadURL == "" {
+		return "", errors.E(op, errors.CodeResultTooLarge, fmt.Sprintf("%s is %d bytes, larger than the %d byte limit", name, len(data), j.MaxInlineResultSize))
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.E(op, err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	ttl := j.ResultDownloadTTL
+	if ttl <= 0 {
+		ttl = defaultResultDownloadTTL
+	}
+	rd := dbmodel.ResultDownload{
+		ExpiresAt:    time.Now().Add(ttl),
+		TokenHash:    resultDownloadTokenHash(token),
+		IdentityName: user.Name,
+		Name:         name,
+		ContentType:  contentType,
+		Body:         data,
+	}
+	if j.ObjectStore != nil {
+		rd.ObjectKey = objectstore.Key(objectstore.KindResult, rd.TokenHash)
+		rd.Body = nil
+		if err := j.ObjectStore.Put(ctx, rd.ObjectKey, contentType, bytes.NewReader(data), int64(len(data))); err != nil {
+			return "", errors.E(op, err)
+		}
+	}
+	if err := j.Database.AddResultDownload(ctx, &rd); err != nil {
+		if rd.ObjectKey != "" {
+			if err := j.ObjectStore.Delete(ctx, rd.ObjectKey); err != nil {
+				zapctx.Warn(ctx, "cannot remove result from object store", zap.String("key", rd.ObjectKey), zap.Error(err))
+			}
+		}
+		return "", errors.E(op, err)
+	}
+	return strings.TrimSuffix(j.ResultDownloadURL, "/") + "/" + token, nil
+}
+
+// ResultTooLargeError returns the error given to clients of the juju
+// facades, which cannot be told of a download URL any other way, when
+// the named result was too large to return inline.
+func ResultTooLargeError(name, url string) error {
+	return errors.E(errors.CodeResultTooLarge, fmt.Sprintf("%s is too large to return inline, download it from %s", name, url))
+}
+
+// GetResultDownload returns the oversized result stored with the given
+// token. If there is no such result, or it has expired, an error with the
+// code CodeNotFound is returned.
+func (j *JIMM) GetResultDownload(ctx context.Context, token string) (*dbmodel.ResultDownload, error) {
+	const op = errors.Op("jimm.GetResultDownload")
+
+	rd := dbmodel.ResultDownload{
+		TokenHash: resultDownloadTokenHash(token),
+	}
+	if err := j.Database.GetResultDownload(ctx, &rd); err != nil {
+		return nil, errors.E(op, err)
+	}
+	if !rd.ExpiresAt.After(time.Now()) {
+		return nil, errors.E(op, errors.CodeNotFound, "result download not found")
+	}
+	if rd.ObjectKey != "" {
+		if j.ObjectStore == nil {
+			return nil, errors.E(op, errors.CodeServerConfiguration, "object store not configured")
+		}
+		r, _, err := j.ObjectStore.Get(ctx, rd.ObjectKey)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		defer r.Close()
+		rd.Body, err = io.ReadAll(r)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+	}
+	return &rd, nil
+}
+
+// DeleteExpiredResultDownloads removes the oversized results that have
+// expired, returning the number removed. Results kept in the object store
+// are removed from the store before their records are deleted.
+func (j *JIMM) DeleteExpiredResultDownloads(ctx context.Context) (int64, error) {
+	const op = errors.Op("jimm.DeleteExpiredResultDownloads")
+
+	now := time.Now()
+	if j.ObjectStore != nil {
+		rds, err := j.Database.ListExpiredResultDownloads(ctx, now)
+		if err != nil {
+			return 0, errors.E(op, err)
+		}
+		for _, rd := range rds {
+			if err := j.ObjectStore.Delete(ctx, rd.ObjectKey); err != nil {
+				return 0, errors.E(op, err)
+			}
+		}
+	}
+	n, err := j.Database.DeleteExpiredResultDownloads(ctx, now)
+	if err != nil {
+		return 0, errors.E(op, err)
+	}
+	return n, nil
+}
+
+func resultDownloadTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestStoreOversizedResult(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		MaxInlineResultSize: 10,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	user := openfga.NewUser(alice, client)
+
+	// Results within the limit are returned inline.
+	url, err := j.StoreOversizedResult(ctx, user, "dump.yaml", "application/yaml", []byte("small"))
+	c.Assert(err, qt.IsNil)
+	c.Check(url, qt.Equals, "")
+
+	// Oversized results are rejected if they cannot be downloaded.
+	_, err = j.StoreOversizedResult(ctx, user, "dump.yaml", "application/yaml", []byte("a larger result"))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeResultTooLarge)
+
+	j.ResultDownloadURL = "https://jimm.example.com/downloads/"
+	url, err = j.StoreOversizedResult(ctx, user, "dump.yaml", "application/yaml", []byte("a larger result"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(url, qt.Matches, `https://jimm.example.com/downloads/[A-Za-z0-9_-]+`)
+	token := strings.TrimPrefix(url, "https://jimm.example.com/downloads/")
+
+	rd, err := j.GetResultDownload(ctx, token)
+	c.Assert(err, qt.IsNil)
+	c.Check(rd.Name, qt.Equals, "dump.yaml")
+	c.Check(rd.ContentType, qt.Equals, "application/yaml")
+	c.Check(rd.IdentityName, qt.Equals, "alice@canonical.com")
+	c.Check(string(rd.Body), qt.Equals, "a larger result")
+	c.Check(rd.ExpiresAt.After(time.Now().Add(14*time.Minute)), qt.IsTrue)
+
+	_, err = j.GetResultDownload(ctx, "not-a-token")
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	// Expired results cannot be downloaded.
+	rd.ExpiresAt = time.Now().Add(-time.Minute)
+	err = j.Database.DB.Save(rd).Error
+	c.Assert(err, qt.IsNil)
+	_, err = j.GetResultDownload(ctx, token)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	n, err := j.DeleteExpiredResultDownloads(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+}
+
+func TestStoreOversizedResultInObjectStore(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	store := &jimmtest.InMemoryObjectStore{}
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		MaxInlineResultSize: 10,
+		ResultDownloadURL:   "https://jimm.example.com/downloads/",
+		ObjectStore:         store,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	user := openfga.NewUser(alice, client)
+
+	url, err := j.StoreOversizedResult(ctx, user, "dump.yaml", "application/yaml", []byte("a larger result"))
+	c.Assert(err, qt.IsNil)
+	token := strings.TrimPrefix(url, "https://jimm.example.com/downloads/")
+	c.Assert(store.Keys(), qt.HasLen, 1)
+	c.Check(store.Keys()[0], qt.Matches, `results/[0-9a-f]{64}`)
+
+	rd, err := j.GetResultDownload(ctx, token)
+	c.Assert(err, qt.IsNil)
+	c.Check(rd.ObjectKey, qt.Equals, store.Keys()[0])
+	c.Check(string(rd.Body), qt.Equals, "a larger result")
+
+	// The body is not kept in the database.
+	var stored dbmodel.ResultDownload
+	err = j.Database.DB.Where("token_hash = ?", rd.TokenHash).First(&stored).Error
+	c.Assert(err, qt.IsNil)
+	c.Check(stored.Body, qt.HasLen, 0)
+
+	// Expired results are removed from the object store.
+	stored.ExpiresAt = time.Now().Add(-time.Minute)
+	err = j.Database.DB.Save(&stored).Error
+	c.Assert(err, qt.IsNil)
+	n, err := j.DeleteExpiredResultDownloads(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(n, qt.Equals, int64(1))
+	c.Check(store.Keys(), qt.HasLen, 0)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// DefaultRetentionBatchSize is the number of rows deleted in each batch
+// when pruning a dataset if no batch size is configured.
+const DefaultRetentionBatchSize = 1000
+
+// RetentionPolicy configures how long the historical data JIMM
+// accumulates is kept.
+type RetentionPolicy struct {
+	// Periods holds the retention period of each dataset, keyed by the
+	// dataset name, see db.PrunableDatasets. Data older than the period
+	// is pruned. Datasets without a positive period are never pruned.
+	Periods map[string]time.Duration
+
+	// BatchSize is the maximum number of rows deleted in a single
+	// statement. If this is zero DefaultRetentionBatchSize is used.
+	BatchSize int
+
+	// BatchInterval is the time waited between batches, limiting the
+	// load pruning places on the database.
+	BatchInterval time.Duration
+}
+
+// Validate checks that the policy only names known datasets.
+func (p RetentionPolicy) Validate() error {
+	const op = errors.Op("jimm.RetentionPolicy.Validate")
+
+	known := make(map[string]bool)
+	for _, ds := range db.PrunableDatasets() {
+		known[ds] = true
+	}
+	for ds, period := range p.Periods {
+		if !known[ds] {
+			return errors.E(op, errors.CodeBadRequest, "unknown dataset "+ds)
+		}
+		if period < 0 {
+			return errors.E(op, errors.CodeBadRequest, "retention period of "+ds+" cannot be negative")
+		}
+	}
+	if p.BatchSize < 0 {
+		return errors.E(op, errors.CodeBadRequest, "retention batch size cannot be negative")
+	}
+	return nil
+}
+
+// PruneHistoricalData deletes the data older than its retention period
+// from every dataset in the given policy, in batches of at most the
+// policy's batch size. It returns the number of rows pruned from each
+// dataset. A failure to prune one dataset does not stop the others being
+// pruned, the first error encountered is returned.
+func (j *JIMM) PruneHistoricalData(ctx context.Context, policy RetentionPolicy) (map[string]int64, error) {
+	const op = errors.Op("jimm.PruneHistoricalData")
+
+	batchSize := policy.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultRetentionBatchSize
+	}
+	datasets := make([]string, 0, len(policy.Periods))
+	for ds, period := range policy.Periods {
+		if period > 0 {
+			datasets = append(datasets, ds)
+		}
+	}
+	sort.Strings(datasets)
+
+	pruned := make(map[string]int64)
+	var firstErr error
+	for _, ds := range datasets {
+		before := time.Now().Add(-policy.Periods[ds])
+		n, err := j.pruneDataset(ctx, ds, before, batchSize, policy.BatchInterval)
+		pruned[ds] = n
+		if err != nil {
+			servermon.RetentionPruneErrorCount.WithLabelValues(ds).Inc()
+			zapctx.Error(ctx, "failed to prune dataset", zap.String("dataset", ds), zap.Error(err))
+			if firstErr == nil {
+				firstErr = errors.E(op, err)
+			}
+		}
+	}
+	return pruned, firstErr
+}
+
+// pruneDataset deletes the rows of the dataset older than before in
+// batches until a batch deletes fewer rows than the batch size. If an
+// object store is configured each batch of audit log entries is exported
+// to the store before it is deleted, a batch that cannot be exported is
+// not deleted.
+func (j *JIMM) pruneDataset(ctx context.Context, dataset string, before time.Time, batchSize int, interval time.Duration) (int64, error) {
+	prune := func() (int64, error) {
+		return j.Database.PruneDataset(ctx, dataset, before, batchSize)
+	}
+	if dataset == db.DatasetAuditLog && j.ObjectStore != nil {
+		prune = func() (int64, error) {
+			return j.exportAndPruneAuditLog(ctx, before, batchSize)
+		}
+	}
+	var total int64
+	for {
+		n, err := prune()
+		if err != nil {
+			return total, err
+		}
+		total += n
+		servermon.RetentionPrunedRowsCount.WithLabelValues(dataset).Add(float64(n))
+		if n < int64(batchSize) {
+			return total, nil
+		}
+		if interval <= 0 {
+			continue
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return total, ctx.Err()
+		}
+	}
+}
+
+// exportAndPruneAuditLog exports the oldest batch of audit log entries
+// older than before to the object store as JSON lines, then deletes them.
+func (j *JIMM) exportAndPruneAuditLog(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	entries, err := j.Database.ListPrunableAuditLogEntries(ctx, before, batchSize)
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	ids := make([]uint, len(entries))
+	for i, e := range entries {
+		if err := enc.Encode(e.ToAPIAuditEvent()); err != nil {
+			return 0, err
+		}
+		ids[i] = e.ID
+	}
+	first, last := entries[0], entries[len(entries)-1]
+	name := fmt.Sprintf("%s-%d-%d.jsonl", first.Time.UTC().Format("20060102T150405Z"), first.ID, last.ID)
+	if err := j.ObjectStore.Put(ctx, objectstore.Key(objectstore.KindAuditExport, name), "application/jsonl", &buf, int64(buf.Len())); err != nil {
+		return 0, err
+	}
+	return j.Database.DeleteAuditLogEntries(ctx, ids)
+}
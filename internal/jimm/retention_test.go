@@ -0,0 +1,138 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestRetentionPolicyValidate(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(jimm.RetentionPolicy{}.Validate(), qt.IsNil)
+	c.Check(jimm.RetentionPolicy{
+		Periods: map[string]time.Duration{db.DatasetAuditLog: time.Hour},
+	}.Validate(), qt.IsNil)
+	c.Check(jimm.RetentionPolicy{
+		Periods: map[string]time.Duration{"usage-samples": time.Hour},
+	}.Validate(), qt.ErrorMatches, `unknown dataset usage-samples`)
+	c.Check(jimm.RetentionPolicy{
+		Periods: map[string]time.Duration{db.DatasetAuditLog: -time.Hour},
+	}.Validate(), qt.ErrorMatches, `retention period of audit-log cannot be negative`)
+	c.Check(jimm.RetentionPolicy{BatchSize: -1}.Validate(), qt.ErrorMatches, `retention batch size cannot be negative`)
+}
+
+func TestPruneHistoricalData(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		err := j.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time: now.Add(-time.Duration(i) * 24 * time.Hour),
+		})
+		c.Assert(err, qt.IsNil)
+		err = j.Database.AddDeadLetterDelta(ctx, &dbmodel.DeadLetterDelta{
+			ControllerName: "controller-1",
+			Kind:           "machine",
+			Error:          "test error",
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	pruned, err := j.PruneHistoricalData(ctx, jimm.RetentionPolicy{
+		Periods: map[string]time.Duration{
+			db.DatasetAuditLog:         36 * time.Hour,
+			db.DatasetDeadLetterDeltas: 0,
+		},
+		BatchSize: 1,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(pruned, qt.DeepEquals, map[string]int64{db.DatasetAuditLog: 3})
+
+	var logs []dbmodel.AuditLogEntry
+	c.Assert(j.Database.DB.Find(&logs).Error, qt.IsNil)
+	c.Check(logs, qt.HasLen, 2)
+	deltas, err := j.Database.ListDeadLetterDeltas(ctx, "")
+	c.Assert(err, qt.IsNil)
+	c.Check(deltas, qt.HasLen, 5)
+}
+
+func TestPruneHistoricalDataExportsAuditLog(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	store := &jimmtest.InMemoryObjectStore{}
+	j := &jimm.JIMM{
+		UUID: uuid.NewString(),
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		ObjectStore: store,
+	}
+	err := j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		err := j.Database.AddAuditLogEntry(ctx, &dbmodel.AuditLogEntry{
+			Time:         now.Add(-time.Duration(i) * 24 * time.Hour),
+			FacadeName:   "Client",
+			FacadeMethod: "FullStatus",
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	pruned, err := j.PruneHistoricalData(ctx, jimm.RetentionPolicy{
+		Periods: map[string]time.Duration{
+			db.DatasetAuditLog: 36 * time.Hour,
+		},
+		BatchSize: 2,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(pruned, qt.DeepEquals, map[string]int64{db.DatasetAuditLog: 3})
+
+	keys := store.Keys()
+	c.Assert(keys, qt.HasLen, 2)
+	var exported int
+	for _, key := range keys {
+		c.Check(key, qt.Matches, `audit-exports/\d{8}T\d{6}Z-\d+-\d+\.jsonl`)
+		r, info, err := store.Get(ctx, key)
+		c.Assert(err, qt.IsNil)
+		c.Check(info.ContentType, qt.Equals, "application/jsonl")
+		dec := json.NewDecoder(r)
+		for dec.More() {
+			var event apiparams.AuditEvent
+			c.Assert(dec.Decode(&event), qt.IsNil)
+			c.Check(event.FacadeMethod, qt.Equals, "FullStatus")
+			exported++
+		}
+		r.Close()
+	}
+	c.Check(exported, qt.Equals, 3)
+
+	var logs []dbmodel.AuditLogEntry
+	c.Assert(j.Database.DB.Find(&logs).Error, qt.IsNil)
+	c.Check(logs, qt.HasLen, 2)
+}
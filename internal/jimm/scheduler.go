@@ -0,0 +1,313 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	// scheduledJobRunHistory is the number of runs of each scheduled
+	// job kept in the database.
+	scheduledJobRunHistory = 100
+
+	// defaultScheduledJobHistoryLimit is the number of runs returned by
+	// ScheduledJobHistory if no limit is requested.
+	defaultScheduledJobHistoryLimit = 20
+)
+
+// ParseJobSchedules parses a semicolon separated list of scheduled job
+// schedules of the form <job>=<schedule>, for example
+// "data-retention=0 3 * * *;group-sync=@every 15m".
+func ParseJobSchedules(s string) (map[string]string, error) {
+	schedules := make(map[string]string)
+	for _, f := range strings.Split(s, ";") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		name, schedule, ok := strings.Cut(f, "=")
+		name, schedule = strings.TrimSpace(name), strings.TrimSpace(schedule)
+		if !ok || name == "" {
+			return nil, errors.E(fmt.Sprintf("invalid job schedule %q", f))
+		}
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			return nil, errors.E(fmt.Sprintf("invalid job schedule %q: %s", f, err))
+		}
+		schedules[name] = schedule
+	}
+	return schedules, nil
+}
+
+// A ScheduledJob is a job run periodically by a Scheduler.
+type ScheduledJob struct {
+	// Name is the name of the job, it must be unique amongst the jobs
+	// of a Scheduler. The job runs as a leader-elected worker with the
+	// same name.
+	Name string
+
+	// Description describes what the job does.
+	Description string
+
+	// Schedule is the schedule of the job, either a standard five
+	// field cron expression, such as "0 3 * * *", a descriptor, such as
+	// "@daily", or "@every <duration>".
+	Schedule string
+
+	// Jitter is the maximum random delay added to each scheduled run of
+	// the job, spreading the load of jobs scheduled at the same time.
+	Jitter time.Duration
+
+	// RunAtStart runs the job as soon as this replica starts running
+	// it, as well as on its schedule.
+	RunAtStart bool
+
+	// Run runs the job. An error is recorded in the job's run history,
+	// the job still runs at its next scheduled time.
+	Run func(context.Context) error
+
+	schedule cron.Schedule
+}
+
+// A Scheduler runs periodic jobs on their schedules. Each job runs on
+// whichever JIMM replica holds its lease, see LeaderElector. Jobs may be
+// disabled and re-enabled by JIMM administrators, the setting is held in
+// the database so that it applies to whichever replica runs the job. The
+// runs of every job are recorded in the database.
+type Scheduler struct {
+	// Database is the database holding the job settings and run
+	// history.
+	Database db.Database
+
+	jobs []*ScheduledJob
+}
+
+// Add adds the given job to the scheduler. An error is returned if the
+// job's schedule cannot be parsed or a job with the same name has
+// already been added. Add must be called before Register.
+func (s *Scheduler) Add(job ScheduledJob) error {
+	const op = errors.Op("jimm.Scheduler.Add")
+
+	if job.Name == "" || job.Run == nil {
+		return errors.E(op, errors.CodeBadRequest, "missing job name or function")
+	}
+	if s.job(job.Name) != nil {
+		return errors.E(op, errors.CodeAlreadyExists, fmt.Sprintf("job %q already scheduled", job.Name))
+	}
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid schedule %q for job %q: %s", job.Schedule, job.Name, err))
+	}
+	job.schedule = schedule
+	s.jobs = append(s.jobs, &job)
+	return nil
+}
+
+// job returns the job with the given name, or nil if there is no such
+// job.
+func (s *Scheduler) job(name string) *ScheduledJob {
+	if s == nil {
+		return nil
+	}
+	for _, job := range s.jobs {
+		if job.Name == name {
+			return job
+		}
+	}
+	return nil
+}
+
+// Register registers every job with the given LeaderElector, so that
+// each job runs on the replica holding its lease.
+func (s *Scheduler) Register(e *LeaderElector) {
+	for _, job := range s.jobs {
+		job := job
+		e.Register(job.Name, func(ctx context.Context) error {
+			s.runJob(ctx, job, e.Holder)
+			return nil
+		})
+	}
+}
+
+// runJob runs the given job on its schedule until the given context is
+// canceled.
+func (s *Scheduler) runJob(ctx context.Context, job *ScheduledJob, holder string) {
+	ctx = zapctx.WithFields(ctx, zap.String("job", job.Name))
+	if job.RunAtStart {
+		s.runOnce(ctx, job, holder)
+	}
+	for {
+		next := job.schedule.Next(time.Now())
+		if job.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(job.Jitter))))
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+		s.runOnce(ctx, job, holder)
+	}
+}
+
+// runOnce runs the given job, if it is enabled, recording the run in the
+// database.
+func (s *Scheduler) runOnce(ctx context.Context, job *ScheduledJob, holder string) {
+	if !s.enabled(ctx, job.Name) {
+		zapctx.Debug(ctx, "scheduled job disabled, skipping run")
+		return
+	}
+	run := dbmodel.ScheduledJobRun{
+		JobName:   job.Name,
+		Holder:    holder,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := s.Database.AddScheduledJobRun(ctx, &run); err != nil {
+		// The job still runs, only its history is lost.
+		zapctx.Error(ctx, "cannot record scheduled job run", zap.Error(err))
+	}
+	err := job.Run(ctx)
+	if err != nil {
+		zapctx.Error(ctx, "scheduled job failed", zap.Error(err))
+		run.Error = err.Error()
+	}
+	if run.ID == 0 {
+		return
+	}
+	run.FinishedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	if err := s.Database.FinishScheduledJobRun(ctx, &run); err != nil {
+		zapctx.Error(ctx, "cannot record scheduled job run", zap.Error(err))
+	}
+	if _, err := s.Database.PruneScheduledJobRuns(ctx, job.Name, scheduledJobRunHistory); err != nil {
+		zapctx.Error(ctx, "cannot prune scheduled job runs", zap.Error(err))
+	}
+}
+
+// enabled reports whether the job with the given name is enabled. Jobs
+// without settings are enabled, as are jobs whose settings cannot be
+// read.
+func (s *Scheduler) enabled(ctx context.Context, name string) bool {
+	job := dbmodel.ScheduledJob{Name: name}
+	err := s.Database.GetScheduledJob(ctx, &job)
+	switch {
+	case err == nil:
+		return job.Enabled
+	case errors.ErrorCode(err) == errors.CodeNotFound:
+		return true
+	default:
+		zapctx.Error(ctx, "cannot get scheduled job settings", zap.Error(err))
+		return true
+	}
+}
+
+// ListScheduledJobs returns the jobs run by the scheduler, whether they
+// are enabled, when they next run and their most recent run. Only JIMM
+// administrators may list the scheduled jobs.
+func (j *JIMM) ListScheduledJobs(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error) {
+	const op = errors.Op("jimm.ListScheduledJobs")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, err
+	}
+	if j.Scheduler == nil {
+		return nil, nil
+	}
+
+	settings, err := j.Database.ListScheduledJobs(ctx)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	enabled := make(map[string]bool, len(settings))
+	for _, s := range settings {
+		enabled[s.Name] = s.Enabled
+	}
+	now := time.Now().UTC()
+	jobs := make([]apiparams.ScheduledJob, len(j.Scheduler.jobs))
+	for i, job := range j.Scheduler.jobs {
+		jobs[i] = apiparams.ScheduledJob{
+			Name:        job.Name,
+			Description: job.Description,
+			Schedule:    job.Schedule,
+			Jitter:      job.Jitter,
+			Enabled:     true,
+		}
+		if e, ok := enabled[job.Name]; ok {
+			jobs[i].Enabled = e
+		}
+		if jobs[i].Enabled {
+			next := job.schedule.Next(now)
+			jobs[i].NextRun = &next
+		}
+		runs, err := j.Database.ListScheduledJobRuns(ctx, job.Name, 1)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		if len(runs) > 0 {
+			run := runs[0].ToAPIScheduledJobRun()
+			jobs[i].LastRun = &run
+		}
+	}
+	return jobs, nil
+}
+
+// ScheduledJobHistory returns, at most, the given number of the most
+// recent runs of the named scheduled job, the most recent first. If
+// limit is not positive a default limit is used. Only JIMM
+// administrators may view the run history.
+func (j *JIMM) ScheduledJobHistory(ctx context.Context, user *openfga.User, name string, limit int) ([]apiparams.ScheduledJobRun, error) {
+	const op = errors.Op("jimm.ScheduledJobHistory")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return nil, err
+	}
+	if j.Scheduler.job(name) == nil {
+		return nil, errors.E(op, errors.CodeNotFound, fmt.Sprintf("scheduled job %q not found", name))
+	}
+	if limit <= 0 {
+		limit = defaultScheduledJobHistoryLimit
+	}
+	runs, err := j.Database.ListScheduledJobRuns(ctx, name, limit)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.ScheduledJobRun, len(runs))
+	for i, r := range runs {
+		resp[i] = r.ToAPIScheduledJobRun()
+	}
+	return resp, nil
+}
+
+// SetScheduledJobEnabled enables, or disables, the named scheduled job.
+// A disabled job is skipped at its scheduled times, a run in progress is
+// not interrupted. Only JIMM administrators may enable or disable jobs.
+func (j *JIMM) SetScheduledJobEnabled(ctx context.Context, user *openfga.User, name string, enabled bool) error {
+	const op = errors.Op("jimm.SetScheduledJobEnabled")
+
+	if err := j.checkJimmAdmin(user); err != nil {
+		return err
+	}
+	if j.Scheduler.job(name) == nil {
+		return errors.E(op, errors.CodeNotFound, fmt.Sprintf("scheduled job %q not found", name))
+	}
+	if err := j.Database.SetScheduledJobEnabled(ctx, name, enabled); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestParseJobSchedules(t *testing.T) {
+	c := qt.New(t)
+
+	schedules, err := jimm.ParseJobSchedules("")
+	c.Assert(err, qt.IsNil)
+	c.Check(schedules, qt.HasLen, 0)
+
+	schedules, err = jimm.ParseJobSchedules("data-retention=0 3 * * *; group-sync=@every 15m;")
+	c.Assert(err, qt.IsNil)
+	c.Check(schedules, qt.DeepEquals, map[string]string{
+		"data-retention": "0 3 * * *",
+		"group-sync":     "@every 15m",
+	})
+
+	_, err = jimm.ParseJobSchedules("data-retention")
+	c.Check(err, qt.ErrorMatches, `invalid job schedule "data-retention"`)
+	_, err = jimm.ParseJobSchedules("data-retention=3am")
+	c.Check(err, qt.ErrorMatches, `invalid job schedule "data-retention=3am": .*`)
+}
+
+func TestSchedulerAdd(t *testing.T) {
+	c := qt.New(t)
+
+	var s jimm.Scheduler
+	run := func(context.Context) error { return nil }
+	err := s.Add(jimm.ScheduledJob{Name: "job-1", Schedule: "*/5 * * * *", Run: run})
+	c.Assert(err, qt.IsNil)
+	err = s.Add(jimm.ScheduledJob{Name: "job-2", Schedule: "@every 10m", Run: run})
+	c.Assert(err, qt.IsNil)
+
+	err = s.Add(jimm.ScheduledJob{Name: "job-1", Schedule: "@hourly", Run: run})
+	c.Check(err, qt.ErrorMatches, `job "job-1" already scheduled`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeAlreadyExists)
+	err = s.Add(jimm.ScheduledJob{Name: "job-3", Schedule: "every day", Run: run})
+	c.Check(err, qt.ErrorMatches, `invalid schedule "every day" for job "job-3": .*`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	err = s.Add(jimm.ScheduledJob{Name: "job-4", Schedule: "@hourly"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+}
+
+func TestScheduler(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	ran := make(chan string, 10)
+	j.Scheduler = &jimm.Scheduler{Database: j.Database}
+	for _, name := range []string{"job-1", "job-2"} {
+		name := name
+		err := j.Scheduler.Add(jimm.ScheduledJob{
+			Name:        name,
+			Description: "test job",
+			Schedule:    "@daily",
+			RunAtStart:  true,
+			Run: func(context.Context) error {
+				ran <- name
+				return errors.E("job failed")
+			},
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	aliceUser := openfga.NewUser(alice, client)
+	aliceUser.JimmAdmin = true
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	bobUser := openfga.NewUser(bob, client)
+
+	err = j.SetScheduledJobEnabled(ctx, bobUser, "job-2", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	err = j.SetScheduledJobEnabled(ctx, aliceUser, "job-3", false)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	err = j.SetScheduledJobEnabled(ctx, aliceUser, "job-2", false)
+	c.Assert(err, qt.IsNil)
+
+	e := &jimm.LeaderElector{
+		Database:      j.Database,
+		Holder:        "replica-1",
+		LeaseDuration: 300 * time.Millisecond,
+	}
+	j.Scheduler.Register(e)
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	// Only the enabled job runs.
+	select {
+	case name := <-ran:
+		c.Check(name, qt.Equals, "job-1")
+	case <-time.After(5 * time.Second):
+		c.Fatal("job not run")
+	}
+	select {
+	case name := <-ran:
+		c.Fatalf("job %s unexpectedly run", name)
+	case <-time.After(time.Second):
+	}
+	cancel()
+	c.Assert(<-done, qt.IsNil)
+
+	ctx = context.Background()
+	_, err = j.ListScheduledJobs(ctx, bobUser)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+	jobs, err := j.ListScheduledJobs(ctx, aliceUser)
+	c.Assert(err, qt.IsNil)
+	c.Assert(jobs, qt.HasLen, 2)
+	c.Check(jobs[0].Name, qt.Equals, "job-1")
+	c.Check(jobs[0].Description, qt.Equals, "test job")
+	c.Check(jobs[0].Schedule, qt.Equals, "@daily")
+	c.Check(jobs[0].Enabled, qt.IsTrue)
+	c.Assert(jobs[0].NextRun, qt.Not(qt.IsNil))
+	c.Check(jobs[0].NextRun.After(time.Now()), qt.IsTrue)
+	c.Assert(jobs[0].LastRun, qt.Not(qt.IsNil))
+	c.Check(jobs[0].LastRun.Holder, qt.Equals, "replica-1")
+	c.Check(jobs[0].LastRun.FinishedAt, qt.Not(qt.IsNil))
+	c.Check(jobs[0].LastRun.Error, qt.Equals, "job failed")
+	c.Check(jobs[1].Name, qt.Equals, "job-2")
+	c.Check(jobs[1].Enabled, qt.IsFalse)
+	c.Check(jobs[1].NextRun, qt.IsNil)
+	c.Check(jobs[1].LastRun, qt.IsNil)
+
+	runs, err := j.ScheduledJobHistory(ctx, aliceUser, "job-1", 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(runs, qt.HasLen, 1)
+	c.Check(runs[0].Job, qt.Equals, "job-1")
+	c.Check(runs[0].Error, qt.Equals, "job failed")
+	_, err = j.ScheduledJobHistory(ctx, aliceUser, "job-3", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+	_, err = j.ScheduledJobHistory(ctx, bobUser, "job-1", 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
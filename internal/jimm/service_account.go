@@ -11,6 +11,7 @@ import (
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
 
+	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
@@ -127,3 +128,68 @@ func (j *JIMM) GrantServiceAccountAccess(ctx context.Context, u *openfga.User, s
 	}
 	return nil
 }
+
+// AddServiceAccountToGroups makes the service account a member of each of
+// the named groups. The calling user must be a JIMM administrator as group
+// membership may confer access to resources beyond the service account
+// itself.
+func (j *JIMM) AddServiceAccountToGroups(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error {
+	const op = errors.Op("jimm.AddServiceAccountToGroups")
+
+	tuples, err := j.serviceAccountGroupTuples(ctx, u, svcAccTag, groups)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.OpenFGAClient.AddRelation(ctx, tuples...); err != nil {
+		zapctx.Error(ctx, "failed to add tuple(s)", zap.NamedError("add-relation-error", err))
+		return errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	return nil
+}
+
+// RemoveServiceAccountFromGroups removes the service account from each of
+// the named groups. The calling user must be a JIMM administrator as
+// AddServiceAccountToGroups.
+func (j *JIMM) RemoveServiceAccountFromGroups(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error {
+	const op = errors.Op("jimm.RemoveServiceAccountFromGroups")
+
+	tuples, err := j.serviceAccountGroupTuples(ctx, u, svcAccTag, groups)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := j.OpenFGAClient.RemoveRelation(ctx, tuples...); err != nil {
+		zapctx.Error(ctx, "failed to remove tuple(s)", zap.NamedError("remove-relation-error", err))
+		return errors.E(op, errors.CodeOpenFGARequestFailed, err)
+	}
+	return nil
+}
+
+// serviceAccountGroupTuples returns the member tuples relating the service
+// account identity to each of the named groups. Service accounts
+// authenticate as users with the @serviceaccount domain, so group
+// membership is recorded against the user form of the client ID.
+func (j *JIMM) serviceAccountGroupTuples(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) ([]openfga.Tuple, error) {
+	if !u.JimmAdmin {
+		return nil, errors.E(errors.CodeUnauthorized, "unauthorized")
+	}
+	if len(groups) == 0 {
+		return nil, errors.E(errors.CodeBadRequest, "no groups specified")
+	}
+	svcAccIdentity := ofganames.ConvertTag(names.NewUserTag(svcAccTag.Id()))
+	tuples := make([]openfga.Tuple, 0, len(groups))
+	for _, name := range groups {
+		group := dbmodel.GroupEntry{Name: name}
+		if err := j.Database.GetGroup(ctx, &group); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				return nil, errors.E(errors.CodeNotFound, fmt.Sprintf("group %s not found", name))
+			}
+			return nil, err
+		}
+		tuples = append(tuples, openfga.Tuple{
+			Object:   svcAccIdentity,
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(group.ResourceTag()),
+		})
+	}
+	return tuples, nil
+}
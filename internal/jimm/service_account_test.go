@@ -14,6 +14,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	"github.com/canonical/jimm/v3/internal/openfga"
@@ -277,3 +278,57 @@ func TestGrantServiceAccountAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestAddServiceAccountToGroups(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	ofgaClient, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+	pgDb := db.Database{
+		DB: jimmtest.PostgresDB(c, nil),
+	}
+	err = pgDb.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	j := &jimm.JIMM{
+		Database:      pgDb,
+		OpenFGAClient: ofgaClient,
+	}
+
+	admin, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	adminUser := openfga.NewUser(admin, ofgaClient)
+	adminUser.JimmAdmin = true
+
+	group, err := j.AddGroup(ctx, adminUser, "ci-bots")
+	c.Assert(err, qt.IsNil)
+
+	clientID := "fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount"
+	svcAccTag := jimmnames.NewServiceAccountTag(clientID)
+	tuple := openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag(clientID)),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group.ResourceTag()),
+	}
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	err = j.AddServiceAccountToGroups(ctx, openfga.NewUser(bob, ofgaClient), svcAccTag, []string{"ci-bots"})
+	c.Assert(err, qt.ErrorMatches, "unauthorized")
+
+	err = j.AddServiceAccountToGroups(ctx, adminUser, svcAccTag, []string{"no-such-group"})
+	c.Assert(err, qt.ErrorMatches, "group no-such-group not found")
+	c.Assert(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	err = j.AddServiceAccountToGroups(ctx, adminUser, svcAccTag, []string{"ci-bots"})
+	c.Assert(err, qt.IsNil)
+	ok, err := ofgaClient.CheckRelation(ctx, tuple, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsTrue)
+
+	err = j.RemoveServiceAccountFromGroups(ctx, adminUser, svcAccTag, []string{"ci-bots"})
+	c.Assert(err, qt.IsNil)
+	ok, err = ofgaClient.CheckRelation(ctx, tuple, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(ok, qt.IsFalse)
+}
@@ -0,0 +1,244 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/yaml"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	"github.com/canonical/jimm/v3/internal/utils"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// Tunables holds the configuration that may be changed while JIMM is
+// running, without a restart. Tunables are read from a YAML file, any
+// value not set in the file takes the value JIMM was started with.
+type Tunables struct {
+	// LogLevel is the minimum level of the messages logged.
+	LogLevel *string `json:"log-level,omitempty"`
+
+	// QuotaModels, QuotaMachines and QuotaCores are the default quota
+	// limits applied to each user's models.
+	QuotaModels   *int64 `json:"quota-models,omitempty"`
+	QuotaMachines *int64 `json:"quota-machines,omitempty"`
+	QuotaCores    *int64 `json:"quota-cores,omitempty"`
+
+	// MaxControllerModels is the maximum number of models a controller
+	// may host when placing and rebalancing models.
+	MaxControllerModels *int `json:"max-controller-models,omitempty"`
+
+	// BlockUnverifiedPublishers and AllowedPublishers configure the
+	// charm policy.
+	BlockUnverifiedPublishers *bool    `json:"block-unverified-publishers,omitempty"`
+	AllowedPublishers         []string `json:"allowed-publishers,omitempty"`
+}
+
+// ParseTunables parses and validates the given YAML tunables.
+func ParseTunables(data []byte) (*Tunables, error) {
+	var t Tunables
+	if err := yaml.UnmarshalStrict(data, &t); err != nil {
+		return nil, fmt.Errorf("cannot parse tunables: %w", err)
+	}
+	if t.LogLevel != nil {
+		var l zapcore.Level
+		if err := l.UnmarshalText([]byte(*t.LogLevel)); err != nil {
+			return nil, fmt.Errorf("invalid log-level: %w", err)
+		}
+	}
+	for name, v := range map[string]*int64{
+		"quota-models":   t.QuotaModels,
+		"quota-machines": t.QuotaMachines,
+		"quota-cores":    t.QuotaCores,
+	} {
+		if v != nil && *v < 0 {
+			return nil, fmt.Errorf("invalid %s: cannot be negative", name)
+		}
+	}
+	if t.MaxControllerModels != nil && *t.MaxControllerModels < 0 {
+		return nil, fmt.Errorf("invalid max-controller-models: cannot be negative")
+	}
+	for _, p := range t.AllowedPublishers {
+		if strings.TrimSpace(p) == "" {
+			return nil, fmt.Errorf("invalid allowed-publishers: empty publisher")
+		}
+	}
+	return &t, nil
+}
+
+// tunableSettings holds the values of the runtime-tunable settings.
+type tunableSettings struct {
+	logLevel            string
+	quotas              QuotaLimits
+	maxControllerModels int
+	charmPolicy         CharmPolicy
+}
+
+// apply returns the settings with the values set in the tunables
+// replacing those in s.
+func (t *Tunables) apply(s tunableSettings) tunableSettings {
+	if t.LogLevel != nil {
+		s.logLevel = *t.LogLevel
+	}
+	if t.QuotaModels != nil {
+		s.quotas.Models = *t.QuotaModels
+	}
+	if t.QuotaMachines != nil {
+		s.quotas.Machines = *t.QuotaMachines
+	}
+	if t.QuotaCores != nil {
+		s.quotas.Cores = *t.QuotaCores
+	}
+	if t.MaxControllerModels != nil {
+		s.maxControllerModels = *t.MaxControllerModels
+	}
+	if t.BlockUnverifiedPublishers != nil {
+		s.charmPolicy.BlockUnverifiedPublishers = *t.BlockUnverifiedPublishers
+	}
+	if t.AllowedPublishers != nil {
+		s.charmPolicy.AllowedPublishers = slices.Clone(t.AllowedPublishers)
+	}
+	return s
+}
+
+// diffTunableSettings returns the changes from prev to next.
+func diffTunableSettings(prev, next tunableSettings) []apiparams.TunableChange {
+	var changes []apiparams.TunableChange
+	add := func(name, o, n string) {
+		if o != n {
+			changes = append(changes, apiparams.TunableChange{Name: name, Old: o, New: n})
+		}
+	}
+	add("log-level", prev.logLevel, next.logLevel)
+	add("quota-models", strconv.FormatInt(prev.quotas.Models, 10), strconv.FormatInt(next.quotas.Models, 10))
+	add("quota-machines", strconv.FormatInt(prev.quotas.Machines, 10), strconv.FormatInt(next.quotas.Machines, 10))
+	add("quota-cores", strconv.FormatInt(prev.quotas.Cores, 10), strconv.FormatInt(next.quotas.Cores, 10))
+	add("max-controller-models", strconv.Itoa(prev.maxControllerModels), strconv.Itoa(next.maxControllerModels))
+	add("block-unverified-publishers", strconv.FormatBool(prev.charmPolicy.BlockUnverifiedPublishers), strconv.FormatBool(next.charmPolicy.BlockUnverifiedPublishers))
+	add("allowed-publishers", strings.Join(prev.charmPolicy.AllowedPublishers, ","), strings.Join(next.charmPolicy.AllowedPublishers, ","))
+	return changes
+}
+
+// tunableSettings returns the current values of the runtime-tunable
+// settings. The caller must hold tunablesMu.
+func (j *JIMM) tunableSettings() tunableSettings {
+	return tunableSettings{
+		logLevel:            zapctx.LogLevel.Level().String(),
+		quotas:              j.Quotas,
+		maxControllerModels: j.MaxControllerModels,
+		charmPolicy:         j.CharmPolicy,
+	}
+}
+
+// quotas returns the default quota limits.
+func (j *JIMM) quotas() QuotaLimits {
+	j.tunablesMu.RLock()
+	defer j.tunablesMu.RUnlock()
+	return j.Quotas
+}
+
+// maxControllerModels returns the maximum number of models a controller
+// may host.
+func (j *JIMM) maxControllerModels() int {
+	j.tunablesMu.RLock()
+	defer j.tunablesMu.RUnlock()
+	return j.MaxControllerModels
+}
+
+// charmPolicy returns the charm policy.
+func (j *JIMM) charmPolicy() CharmPolicy {
+	j.tunablesMu.RLock()
+	defer j.tunablesMu.RUnlock()
+	return j.CharmPolicy
+}
+
+// LoadTunables reads the tunables from TunablesFile and applies them,
+// returning the settings that changed. The tunables are validated before
+// any are applied, so an invalid file changes nothing. Settings not in
+// the file revert to the values JIMM had when the tunables were first
+// loaded. Any changes are recorded in the audit log as made by the
+// given entity.
+func (j *JIMM) LoadTunables(ctx context.Context, by names.Tag) ([]apiparams.TunableChange, error) {
+	const op = errors.Op("jimm.LoadTunables")
+
+	if j.TunablesFile == "" {
+		return nil, errors.E(op, errors.CodeNotSupported, "tunables file not configured")
+	}
+	data, err := os.ReadFile(j.TunablesFile)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	t, err := ParseTunables(data)
+	if err != nil {
+		return nil, errors.E(op, errors.CodeBadRequest, err)
+	}
+
+	j.tunablesMu.Lock()
+	if j.tunablesBase == nil {
+		base := j.tunableSettings()
+		j.tunablesBase = &base
+	}
+	old := j.tunableSettings()
+	s := t.apply(*j.tunablesBase)
+	if err := zapctx.LogLevel.UnmarshalText([]byte(s.logLevel)); err != nil {
+		// The level has already been validated.
+		j.tunablesMu.Unlock()
+		return nil, errors.E(op, err)
+	}
+	j.Quotas = s.quotas
+	j.MaxControllerModels = s.maxControllerModels
+	j.CharmPolicy = s.charmPolicy
+	j.tunablesMu.Unlock()
+
+	changes := diffTunableSettings(old, s)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	zapctx.Info(ctx, "reloaded tunables", zap.Any("changes", changes))
+	params, err := json.Marshal(apiparams.ReloadTunablesResponse{Changes: changes})
+	if err != nil {
+		zapctx.Error(ctx, "cannot marshal tunable changes", zap.Error(err))
+	}
+	ale := dbmodel.AuditLogEntry{
+		Time:           time.Now().UTC().Round(time.Millisecond),
+		ConversationId: utils.NewConversationID(),
+		FacadeName:     "JIMM",
+		FacadeMethod:   "ReloadTunables",
+		ObjectId:       j.ResourceTag().String(),
+		IdentityTag:    by.String(),
+		Params:         dbmodel.JSON(params),
+		IsResponse:     true,
+	}
+	j.AddAuditLogEntry(&ale)
+	return changes, nil
+}
+
+// ReloadTunables reloads the runtime-tunable configuration, returning
+// the settings that changed. Only JIMM administrators may reload the
+// tunables.
+func (j *JIMM) ReloadTunables(ctx context.Context, user *openfga.User) ([]apiparams.TunableChange, error) {
+	const op = errors.Op("jimm.ReloadTunables")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	changes, err := j.LoadTunables(ctx, user.Tag())
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+	"github.com/juju/zaputil/zapctx"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestParseTunables(t *testing.T) {
+	c := qt.New(t)
+
+	tun, err := jimm.ParseTunables([]byte("log-level: debug\nquota-models: 5\nallowed-publishers: [canonical]\n"))
+	c.Assert(err, qt.IsNil)
+	c.Check(*tun.LogLevel, qt.Equals, "debug")
+	c.Check(*tun.QuotaModels, qt.Equals, int64(5))
+	c.Check(tun.QuotaCores, qt.IsNil)
+	c.Check(tun.AllowedPublishers, qt.DeepEquals, []string{"canonical"})
+
+	for _, test := range []struct {
+		data        string
+		expectError string
+	}{{
+		data:        "log-level: loud\n",
+		expectError: `invalid log-level: .*`,
+	}, {
+		data:        "quota-machines: -1\n",
+		expectError: `invalid quota-machines: cannot be negative`,
+	}, {
+		data:        "max-controller-models: -1\n",
+		expectError: `invalid max-controller-models: cannot be negative`,
+	}, {
+		data:        "allowed-publishers: ['']\n",
+		expectError: `invalid allowed-publishers: empty publisher`,
+	}, {
+		data:        "rate-limit: 10\n",
+		expectError: `cannot parse tunables: .*`,
+	}} {
+		_, err := jimm.ParseTunables([]byte(test.data))
+		c.Check(err, qt.ErrorMatches, test.expectError)
+	}
+}
+
+func TestReloadTunables(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	level := zapctx.LogLevel.Level()
+	c.Cleanup(func() { zapctx.LogLevel.SetLevel(level) })
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	path := filepath.Join(c.TempDir(), "tunables.yaml")
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Quotas:              jimm.QuotaLimits{Models: 10},
+		MaxControllerModels: 100,
+		TunablesFile:        path,
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	bob, err := dbmodel.NewIdentity("bob@canonical.com")
+	c.Assert(err, qt.IsNil)
+	_, err = j.ReloadTunables(ctx, openfga.NewUser(bob, client))
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	alice, err := dbmodel.NewIdentity("alice@canonical.com")
+	c.Assert(err, qt.IsNil)
+	admin := openfga.NewUser(alice, client)
+	admin.JimmAdmin = true
+
+	_, err = j.ReloadTunables(ctx, admin)
+	c.Check(err, qt.ErrorMatches, `open .*: no such file or directory`)
+
+	err = os.WriteFile(path, []byte("quota-models: 20\nmax-controller-models: 50\nblock-unverified-publishers: true\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	changes, err := j.ReloadTunables(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(changes, qt.DeepEquals, []apiparams.TunableChange{
+		{Name: "quota-models", Old: "10", New: "20"},
+		{Name: "max-controller-models", Old: "100", New: "50"},
+		{Name: "block-unverified-publishers", Old: "false", New: "true"},
+	})
+	c.Check(j.Quotas, qt.DeepEquals, jimm.QuotaLimits{Models: 20})
+	c.Check(j.MaxControllerModels, qt.Equals, 50)
+	c.Check(j.CharmPolicy.BlockUnverifiedPublishers, qt.IsTrue)
+
+	// An invalid file changes nothing.
+	err = os.WriteFile(path, []byte("quota-models: 30\nmax-controller-models: -1\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	_, err = j.ReloadTunables(ctx, admin)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	c.Check(j.Quotas, qt.DeepEquals, jimm.QuotaLimits{Models: 20})
+
+	// Settings removed from the file revert to their original values.
+	err = os.WriteFile(path, []byte("quota-models: 20\n"), 0600)
+	c.Assert(err, qt.IsNil)
+	changes, err = j.ReloadTunables(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(changes, qt.DeepEquals, []apiparams.TunableChange{
+		{Name: "max-controller-models", Old: "50", New: "100"},
+		{Name: "block-unverified-publishers", Old: "true", New: "false"},
+	})
+
+	// Reloading an unchanged file changes nothing.
+	changes, err = j.ReloadTunables(ctx, admin)
+	c.Assert(err, qt.IsNil)
+	c.Check(changes, qt.HasLen, 0)
+
+	// Each reload that changed settings is recorded in the audit log.
+	var params []string
+	err = j.Database.ForEachAuditLogEntry(ctx, db.AuditLogFilter{}, func(ale *dbmodel.AuditLogEntry) error {
+		c.Check(ale.FacadeName, qt.Equals, "JIMM")
+		c.Check(ale.FacadeMethod, qt.Equals, "ReloadTunables")
+		c.Check(ale.IdentityTag, qt.Equals, "user-alice@canonical.com")
+		params = append(params, string(ale.Params))
+		return nil
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(params, qt.HasLen, 2)
+	c.Check(params, qt.Contains, `{"changes":[{"name":"max-controller-models","old":"50","new":"100"},{"name":"block-unverified-publishers","old":"true","new":"false"}]}`)
+}
@@ -78,3 +78,24 @@ func (j *JIMM) dialModel(ctx context.Context, ctl *dbmodel.Controller, mt names.
 	}
 	return api, nil
 }
+
+// controllerUsername returns the name of the user JIMM connects to the
+// given controller as. Controllers read from the database do not hold
+// their admin credentials, see AddController, so the name is read from
+// the credential store unless the controller holds one.
+func (j *JIMM) controllerUsername(ctx context.Context, ctl *dbmodel.Controller) (string, error) {
+	if ctl.AdminIdentityName != "" {
+		return ctl.AdminIdentityName, nil
+	}
+	if j.CredentialStore == nil {
+		return "", errors.E(errors.CodeServerConfiguration, "credential store not configured")
+	}
+	user, _, err := j.CredentialStore.GetControllerCredentials(ctx, ctl.Name)
+	if err != nil {
+		return "", err
+	}
+	if user == "" {
+		return "", errors.E(errors.CodeNotFound, "missing controller credentials")
+	}
+	return user, nil
+}
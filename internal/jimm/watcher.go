@@ -5,17 +5,26 @@ package jimm
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/juju/juju/core/status"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+	"github.com/canonical/jimm/v3/internal/notify"
 	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
@@ -38,10 +47,74 @@ type Watcher struct {
 	// model summaries.
 	Pubsub Publisher
 
+	// Cache is the cache of frequently read data used by JIMM. Entries
+	// are invalidated when the watcher changes the data they hold.
+	Cache *ResponseCache
+
+	// Notifier is notified when controllers become unavailable or
+	// recover. If this is nil no notifications are sent.
+	Notifier *notify.Notifier
+
+	// Health records the health of the controllers seen by the watcher.
+	// If this is nil no health is recorded.
+	Health *ControllerHealth
+
+	// PerModelMetrics enables the gauges reporting the number of
+	// entities in each model. A series is created for every model
+	// watched, so this should only be enabled when the number of models
+	// is modest. The totals for each controller are always reported.
+	PerModelMetrics bool
+
+	// Holder identifies the JIMM replica running the watcher in the
+	// claims made on the controllers it monitors. If this is empty the
+	// host name is used.
+	Holder string
+
+	// MetricLabels, if set, determines the organisational labels
+	// attached to the entity gauges. servermon.SetEntityLabels must have
+	// been called with the label names of the MetricLabeler.
+	MetricLabels *MetricLabeler
+
+	// DeltaExporter, if set, exports the model, machine and unit deltas
+	// of the models managed by JIMM to an external event stream.
+	DeltaExporter *DeltaExporter
+
+	// ModelCountReconcileInterval is the interval at which the models
+	// tracked by the watcher of each controller are reconciled with the
+	// models recorded for the controller in the database, correcting
+	// any drift caused by missed deltas. If this is zero
+	// DefaultModelCountReconcileInterval is used.
+	ModelCountReconcileInterval time.Duration
+
 	controllerUnavailableChan chan error
 	deltaProcessedChan        chan bool
+	deltaRetryDelay           time.Duration
 }
 
+const (
+	// deltaAttempts is the number of times the watcher attempts to apply
+	// a delta before it is dead-lettered.
+	deltaAttempts = 3
+
+	// defaultDeltaRetryDelay is the delay before the first retry of a
+	// delta that could not be applied. The delay doubles after each
+	// attempt.
+	defaultDeltaRetryDelay = 100 * time.Millisecond
+
+	// monitorHeartbeatInterval is the interval at which a watcher
+	// renews its claim on the controller it monitors.
+	monitorHeartbeatInterval = 10 * time.Second
+
+	// monitorStaleAfter is the time after its last heartbeat at which
+	// the claim of a watcher that has stopped may be taken over.
+	monitorStaleAfter = 30 * time.Second
+)
+
+// DefaultModelCountReconcileInterval is the interval at which the models
+// tracked by a controller's watcher are reconciled with the database, if
+// no interval is configured.
+const DefaultModelCountReconcileInterval = 10 * time.Minute
+
 // Watch starts the watcher which connects to all known controllers and
 // monitors them for updates. Watch polls the database at the given
 // interval to find any new controllers to watch. Watch blocks until either
@@ -58,10 +131,20 @@ func (w *Watcher) Watch(ctx context.Context, interval time.Duration) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	if w.DeltaExporter != nil {
+		r.run("delta-exporter", func() {
+			w.DeltaExporter.Run(ctx)
+		})
+	}
+
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 	for {
 		err := w.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+			if ctl.MaintenanceSince.Valid {
+				// Don't reconnect to controllers in maintenance.
+				return nil
+			}
 			ctx := zapctx.WithFields(ctx, zap.String("controller", ctl.Name))
 			r.run(ctl.Name, func() {
 				zapctx.Info(ctx, "starting controller watcher")
@@ -107,6 +190,10 @@ func (w *Watcher) WatchAllModelSummaries(ctx context.Context, interval time.Dura
 	defer ticker.Stop()
 	for {
 		err := w.Database.ForEachController(ctx, func(ctl *dbmodel.Controller) error {
+			if ctl.MaintenanceSince.Valid {
+				// Don't reconnect to controllers in maintenance.
+				return nil
+			}
 			ctx := zapctx.WithFields(ctx, zap.String("controller", ctl.Name))
 			r.run(ctl.Name, func() {
 				zapctx.Info(ctx, "starting model summary watcher")
@@ -141,6 +228,7 @@ func (w *Watcher) dialController(ctx context.Context, ctl *dbmodel.Controller) (
 		if uerr := w.Database.UpdateController(ctx, ctl); uerr != nil {
 			zapctx.Error(ctx, "cannot set controller available", zap.Error(uerr))
 		}
+		w.Cache.InvalidateControllers()
 		// Note (alesstimec) This channel is only available in tests.
 		if w.controllerUnavailableChan != nil {
 			select {
@@ -151,15 +239,34 @@ func (w *Watcher) dialController(ctx context.Context, ctl *dbmodel.Controller) (
 	}()
 
 	// connect to the controller
+	certificateExpiry := ctl.CertificateExpiry
+	start := time.Now()
 	api, err = w.Dialer.Dial(ctx, ctl, names.ModelTag{}, nil)
+	w.Health.ObserveRequest(ctl.Name, time.Since(start), err)
 	if err != nil {
-		ctl.UnavailableSince = db.Now()
+		reason := controllerUnavailableReason(err)
+		if !ctl.UnavailableSince.Valid {
+			w.Notifier.Notify(ctx, notify.Event{
+				Kind:       notify.ControllerUnavailable,
+				Controller: ctl.Name,
+				Message:    fmt.Sprintf("controller %s is unavailable (%s): %s", ctl.Name, reason, err),
+			})
+		}
+		ctl.SetUnavailableAt(db.Now(), reason, err)
 		updateController = true
 
 		return nil, errors.E(op, err)
 	}
+	if ctl.CertificateExpiry.Valid != certificateExpiry.Valid || !ctl.CertificateExpiry.Time.Equal(certificateExpiry.Time) {
+		updateController = true
+	}
 	if ctl.UnavailableSince.Valid {
-		ctl.UnavailableSince = sql.NullTime{}
+		w.Notifier.Notify(ctx, notify.Event{
+			Kind:       notify.ControllerAvailable,
+			Controller: ctl.Name,
+			Message:    fmt.Sprintf("controller %s is available, it was unavailable since %s", ctl.Name, ctl.UnavailableSince.Time.UTC().Format(time.RFC3339)),
+		})
+		ctl.SetAvailable()
 		updateController = true
 	}
 	return api, nil
@@ -171,12 +278,240 @@ type modelState struct {
 	id      uint
 	changed bool
 
+	// unitsChanged is set when a unit is added or removed, or its
+	// workload status changes, and is recorded as model activity.
+	unitsChanged bool
+
 	// machines maps the Id of all the machines that have been seen to
 	// the number of cores reported.
 	machines map[string]int64
 
-	// units stores the ids of all units that have been seen.
-	units map[string]bool
+	// units maps the ids of all units that have been seen to their
+	// workload status.
+	units map[string]status.Status
+
+	// offers and relations hold the ids of all the application offers
+	// and relations that have been seen.
+	offers    map[string]bool
+	relations map[string]bool
+
+	// applications holds the charm URLs of all the applications that
+	// have been seen, keyed by application name. It is used for metrics
+	// and to avoid recording unchanged charms, it is not persisted.
+	applications map[string]string
+
+	// exposed holds whether each of the applications that have been seen
+	// is exposed, keyed by application name. It is used to avoid
+	// recording unchanged applications, it is not persisted.
+	exposed map[string]bool
+
+	// agentVersions maps the keys, see entityAgentKey, of all the
+	// machines and units that have been seen to the version of the
+	// agent they reported.
+	agentVersions map[string]string
+
+	// labels holds the values of the model's metric labels, see
+	// MetricLabeler.
+	labels []string
+
+	// unseenMachines, unseenUnits, unseenOffers and unseenRelations
+	// hold the ids of the entities restored from the persisted state
+	// that have not yet been seen by this watcher.
+	unseenMachines  map[string]bool
+	unseenUnits     map[string]bool
+	unseenOffers    map[string]bool
+	unseenRelations map[string]bool
+}
+
+// newModelState returns an empty state for the model with the given ID.
+func newModelState(id uint) *modelState {
+	return &modelState{
+		id:            id,
+		machines:      make(map[string]int64),
+		units:         make(map[string]status.Status),
+		offers:        make(map[string]bool),
+		relations:     make(map[string]bool),
+		applications:  make(map[string]string),
+		exposed:       make(map[string]bool),
+		agentVersions: make(map[string]string),
+	}
+}
+
+// restore seeds the model state with the machines and units persisted by
+// a previous watcher, so that the model's counts continue from their last
+// known values rather than being recounted from zero.
+func (st *modelState) restore(ws *dbmodel.ModelWatcherState) {
+	st.unseenMachines = make(map[string]bool, len(ws.Machines))
+	for id, cores := range ws.Machines {
+		st.machines[id] = cores
+		st.unseenMachines[id] = true
+	}
+	st.unseenUnits = make(map[string]bool, len(ws.Units))
+	for id, s := range ws.Units {
+		st.units[id] = status.Status(s)
+		st.unseenUnits[id] = true
+	}
+	st.unseenOffers = make(map[string]bool, len(ws.Offers))
+	for _, id := range ws.Offers {
+		st.offers[id] = true
+		st.unseenOffers[id] = true
+	}
+	st.unseenRelations = make(map[string]bool, len(ws.Relations))
+	for _, id := range ws.Relations {
+		st.relations[id] = true
+		st.unseenRelations[id] = true
+	}
+	for tag, v := range ws.AgentVersions {
+		st.agentVersions[tag] = v
+	}
+}
+
+// pruneUnseen removes the restored entities that have not been seen by
+// this watcher.
+func (st *modelState) pruneUnseen() {
+	for id := range st.unseenMachines {
+		delete(st.machines, id)
+		delete(st.agentVersions, entityAgentKey("machine", id))
+		st.changed = true
+	}
+	for id := range st.unseenUnits {
+		delete(st.units, id)
+		delete(st.agentVersions, entityAgentKey("unit", id))
+		st.changed = true
+	}
+	for id := range st.unseenOffers {
+		delete(st.offers, id)
+		st.changed = true
+	}
+	for id := range st.unseenRelations {
+		delete(st.relations, id)
+		st.changed = true
+	}
+	st.unseenMachines = nil
+	st.unseenUnits = nil
+	st.unseenOffers = nil
+	st.unseenRelations = nil
+}
+
+// seen records that the entity with the given id, which is tracked in
+// the given set, has been seen with the given removal state.
+func (st *modelState) seen(set, unseen map[string]bool, id string, removed bool) {
+	delete(unseen, id)
+	if removed {
+		if set[id] {
+			delete(set, id)
+			st.changed = true
+		}
+		return
+	}
+	if !set[id] {
+		set[id] = true
+		st.changed = true
+	}
+}
+
+// agentVersionKey returns the key under which the agent version of the
+// entity of the given kind, "machine" or "unit", and ID is recorded.
+func entityAgentKey(kind, id string) string {
+	return kind + "-" + id
+}
+
+// setAgentVersion records the agent version reported by the machine or
+// unit with the given key.
+func (st *modelState) setAgentVersion(key, version string) {
+	if v, ok := st.agentVersions[key]; !ok || v != version {
+		st.agentVersions[key] = version
+		st.changed = true
+	}
+}
+
+// updateModelCounts sets the entity counts, and the agent version
+// distribution, of the given model from the model state.
+func (st *modelState) updateModelCounts(m *dbmodel.Model) {
+	var machines, containers, cores int64
+	for id, n := range st.machines {
+		machines++
+		if names.IsContainerMachine(id) {
+			containers++
+		}
+		cores += n
+	}
+	m.Cores = cores
+	m.Machines = machines
+	m.Containers = containers
+	m.Units = int64(len(st.units))
+	m.OfferCount = int64(len(st.offers))
+	m.Relations = int64(len(st.relations))
+	m.WorkloadStatus, m.UnhealthyUnits = summarizeWorkloadStatus(st.units)
+	m.ActiveUnits, m.BlockedUnits, m.ErrorUnits = countWorkloadStatuses(st.units)
+	m.AgentVersions = make(dbmodel.Int64Map)
+	for _, v := range st.agentVersions {
+		if v != "" {
+			m.AgentVersions[v]++
+		}
+	}
+}
+
+// watcherState returns the persistable form of the model state.
+func (st *modelState) watcherState() *dbmodel.ModelWatcherState {
+	ws := dbmodel.ModelWatcherState{
+		ModelID:       st.id,
+		Machines:      make(dbmodel.Int64Map, len(st.machines)),
+		Units:         make(dbmodel.StringMap, len(st.units)),
+		AgentVersions: make(dbmodel.StringMap, len(st.agentVersions)),
+	}
+	for id, cores := range st.machines {
+		ws.Machines[id] = cores
+	}
+	for id, s := range st.units {
+		ws.Units[id] = string(s)
+	}
+	for tag, v := range st.agentVersions {
+		ws.AgentVersions[tag] = v
+	}
+	ws.Offers = sortedKeys(st.offers)
+	ws.Relations = sortedKeys(st.relations)
+	return &ws
+}
+
+// entityCount returns the number of entities of the given kind, one of
+// entityKinds, in the model.
+func (st *modelState) entityCount(kind string) int {
+	switch kind {
+	case "applications":
+		return len(st.applications)
+	case "machines":
+		return len(st.machines)
+	case "offers":
+		return len(st.offers)
+	case "units":
+		return len(st.units)
+	}
+	return 0
+}
+
+// sortedKeys returns the keys of the given set in order.
+func sortedKeys(set map[string]bool) dbmodel.Strings {
+	keys := make(dbmodel.Strings, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// modelLabelValues returns the values of the metric labels of the given
+// model, loading the model's cloud region if the labels require it.
+func (w *Watcher) modelLabelValues(ctx context.Context, m *dbmodel.Model) []string {
+	if w.MetricLabels.needsModelCloudRegion() && m.CloudRegion.Name == "" {
+		full := dbmodel.Model{ID: m.ID}
+		if err := w.Database.GetModel(ctx, &full); err != nil {
+			zapctx.Warn(ctx, "cannot get model metric labels", zap.String("model", m.UUID.String), zap.Error(err))
+		} else {
+			m = &full
+		}
+	}
+	return w.MetricLabels.ModelLabelValues(m)
 }
 
 func (w *Watcher) checkControllerModels(ctx context.Context, ctl *dbmodel.Controller, checks ...func(*dbmodel.Model) error) (map[string]*modelState, error) {
@@ -199,11 +534,9 @@ func (w *Watcher) checkControllerModels(ctx context.Context, ctl *dbmodel.Contro
 				return errors.E(op, err)
 			}
 		}
-		modelStates[m.UUID.String] = &modelState{
-			id:       m.ID,
-			machines: make(map[string]int64),
-			units:    make(map[string]bool),
-		}
+		st := newModelState(m.ID)
+		st.labels = w.modelLabelValues(ctx, m)
+		modelStates[m.UUID.String] = st
 		return nil
 	})
 	if err != nil {
@@ -212,6 +545,75 @@ func (w *Watcher) checkControllerModels(ctx context.Context, ctl *dbmodel.Contro
 	return modelStates, nil
 }
 
+// restoreModelState seeds the given model state with the state persisted
+// for the model by a previous watcher, if there is any.
+func (w *Watcher) restoreModelState(ctx context.Context, st *modelState) {
+	ws := dbmodel.ModelWatcherState{
+		ModelID: st.id,
+	}
+	if err := w.Database.GetModelWatcherState(ctx, &ws); err != nil {
+		if errors.ErrorCode(err) != errors.CodeNotFound {
+			zapctx.Error(ctx, "cannot get model watcher state", zap.Error(err))
+		}
+		return
+	}
+	st.restore(&ws)
+}
+
+func (w *Watcher) modelCountReconcileInterval() time.Duration {
+	if w.ModelCountReconcileInterval > 0 {
+		return w.ModelCountReconcileInterval
+	}
+	return DefaultModelCountReconcileInterval
+}
+
+// reconcileModelCount reconciles the models tracked by the watcher of
+// the given controller with the models recorded for the controller in
+// the database, which is authoritative. Models whose creation was
+// missed are tracked and models whose removal was missed are dropped,
+// each correction is counted in the MonitorModelCountCorrections metric.
+// If the database cannot be read the tracked models are left unchanged.
+func (w *Watcher) reconcileModelCount(ctx context.Context, ctl *dbmodel.Controller, modelStates map[string]*modelState) {
+	stored := make(map[string]*dbmodel.Model)
+	err := w.Database.ForEachControllerModel(ctx, ctl, func(m *dbmodel.Model) error {
+		// models without a UUID are currently being initialised
+		// and are not tracked yet.
+		if m.UUID.Valid {
+			m := *m
+			stored[m.UUID.String] = &m
+		}
+		return nil
+	})
+	if err != nil {
+		zapctx.Error(ctx, "cannot reconcile model count", zap.Error(err))
+		return
+	}
+
+	var added, removed int
+	for uuid, m := range stored {
+		if _, ok := modelStates[uuid]; ok {
+			continue
+		}
+		st := newModelState(m.ID)
+		st.labels = w.modelLabelValues(ctx, m)
+		w.restoreModelState(ctx, st)
+		modelStates[uuid] = st
+		added++
+	}
+	for uuid := range modelStates {
+		if _, ok := stored[uuid]; !ok {
+			delete(modelStates, uuid)
+			removed++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return
+	}
+	servermon.MonitorModelCountCorrections.WithLabelValues(ctl.UUID, "added").Add(float64(added))
+	servermon.MonitorModelCountCorrections.WithLabelValues(ctl.UUID, "removed").Add(float64(removed))
+	zapctx.Warn(ctx, "corrected watched model count", zap.Int("added", added), zap.Int("removed", removed), zap.Int("models", len(modelStates)))
+}
+
 func (w *Watcher) deltaProcessedNotification() {
 	if w.deltaProcessedChan != nil {
 		select {
@@ -228,12 +630,24 @@ func (w *Watcher) deltaProcessedNotification() {
 func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller) error {
 	const op = errors.Op("jimm.watchController")
 
+	// claim the controller, so that no other watcher applies updates
+	// from it whilst this watcher does.
+	monitor, err := w.claimController(ctx, ctl)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer w.releaseController(ctx, monitor)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.holdController(ctx, cancel, ctl, monitor)
+
 	// connect to the controller
 	api, err := w.dialController(ctx, ctl)
 	if err != nil {
 		return errors.E(op, err)
 	}
 	defer api.Close()
+	defer deleteEntityMetrics(ctl)
 	// start the all watcher
 	id, err := api.WatchAllModels(ctx)
 	if err != nil {
@@ -259,6 +673,7 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 					if err := w.Database.DeleteModel(ctx, m); err != nil {
 						return errors.E(op, err)
 					} else {
+						w.Cache.InvalidateModelAccess(m.ResourceTag())
 						return nil
 					}
 				} else {
@@ -276,6 +691,10 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 	if err != nil {
 		return errors.E(op, err)
 	}
+	for _, st := range modelStates {
+		w.restoreModelState(ctx, st)
+	}
+	reconciled := time.Now()
 
 	modelStatef := func(uuid string) *modelState {
 		state, ok := modelStates[uuid]
@@ -292,12 +711,9 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 		err := w.Database.GetModel(ctx, &m)
 		switch {
 		case err == nil:
-			st := modelState{
-				id:       m.ID,
-				machines: make(map[string]int64),
-				units:    make(map[string]bool),
-			}
-			modelStates[uuid] = &st
+			st := newModelState(m.ID)
+			st.labels = w.MetricLabels.ModelLabelValues(&m)
+			modelStates[uuid] = st
 		case errors.ErrorCode(err) == errors.CodeNotFound:
 			modelStates[uuid] = nil
 		default:
@@ -306,21 +722,42 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 		return modelStates[uuid]
 	}
 
-	for {
+	for initial := true; ; initial = false {
 		// wait for updates from the all watcher.
 		deltas, err := api.AllModelWatcherNext(ctx, id)
 		if err != nil {
+			if ctx.Err() == nil {
+				w.Health.ObserveRequest(ctl.Name, 0, err)
+			}
+			return errors.E(op, err)
+		}
+		received := time.Now()
+		if err := faults.Delay(ctx, faults.WatcherDelta, ctl.Name); err != nil {
 			return errors.E(op, err)
 		}
 		servermon.MonitorDeltasReceivedCount.WithLabelValues(ctl.UUID).Add(float64(len(deltas)))
+		if err := w.Database.CheckControllerMonitor(ctx, monitor); err != nil {
+			if errors.ErrorCode(err) == errors.CodeMonitorConflict {
+				w.monitorConflict(ctx, ctl, monitor)
+			}
+			return errors.E(op, err)
+		}
+		debug := zapctx.Logger(ctx).Core().Enabled(zap.DebugLevel)
 		for _, d := range deltas {
-			eid := d.Entity.EntityId()
-			ctx := zapctx.WithFields(ctx, zap.String("model-uuid", eid.ModelUUID), zap.String("kind", eid.Kind), zap.String("id", eid.Id))
-			zapctx.Debug(ctx, "processing delta")
-			if err := w.handleDelta(ctx, modelStatef, d); err != nil {
+			if err := w.processDelta(ctx, ctl, modelStatef, d, received, initial, debug); err != nil {
 				return errors.E(op, err)
 			}
 		}
+		if initial {
+			// The initial deltas from the all watcher describe every
+			// entity on the controller, any restored entity not
+			// included has been removed since the state was persisted.
+			for _, v := range modelStates {
+				if v != nil {
+					v.pruneUnseen()
+				}
+			}
+		}
 		for k, v := range modelStates {
 			if v == nil {
 				// If we have cached not to process a model
@@ -329,35 +766,205 @@ func (w *Watcher) watchController(ctx context.Context, ctl *dbmodel.Controller)
 				continue
 			}
 			if v.changed {
+				// The initial deltas describe the existing units
+				// rather than changes to them.
+				unitsChanged := v.unitsChanged && !initial
 				v.changed = false
+				v.unitsChanged = false
 				// Update changed model.
 				err := w.Database.Transaction(func(tx *db.Database) error {
+					if err := tx.CheckControllerMonitor(ctx, monitor); err != nil {
+						return err
+					}
 					m := dbmodel.Model{
 						ID: v.id,
 					}
 					if err := tx.GetModel(ctx, &m); err != nil {
 						return err
 					}
-					var machines, cores int64
-					for _, n := range v.machines {
-						machines++
-						cores += n
+					v.updateModelCounts(&m)
+					if unitsChanged {
+						m.LastUnitChangeAt = sql.NullTime{Time: received.UTC(), Valid: true}
 					}
-					m.Cores = cores
-					m.Machines = machines
-					m.Units = int64(len(v.units))
 					if err := tx.UpdateModel(ctx, &m); err != nil {
 						return err
 					}
-					return nil
+					return tx.UpsertModelWatcherState(ctx, v.watcherState())
 				})
+				if errors.ErrorCode(err) == errors.CodeMonitorConflict {
+					w.monitorConflict(ctx, ctl, monitor)
+					return errors.E(op, err)
+				}
 				if err != nil {
 					zapctx.Error(ctx, "cannot get model for update", zap.Error(err))
 					continue
 				}
 			}
 		}
+		if time.Since(reconciled) >= w.modelCountReconcileInterval() {
+			w.reconcileModelCount(ctx, ctl, modelStates)
+			reconciled = time.Now()
+		}
+		w.updateEntityMetrics(ctl, modelStates)
+		if !initial {
+			// The initial deltas describe the whole controller and
+			// are expected to take a while to process.
+			w.Health.ObserveWatcherLag(ctl.Name, time.Since(received))
+		}
+	}
+}
+
+// processDelta applies a single delta received from the controller and
+// exports it. This is the hot path of the watcher, the initial deltas
+// of a large controller number in the tens of thousands, so the delta's
+// identity is only added to the logging context when debug logging is
+// enabled, otherwise it is added only when the delta fails.
+func (w *Watcher) processDelta(ctx context.Context, ctl *dbmodel.Controller, modelStatef func(string) *modelState, d jujuparams.Delta, received time.Time, initial, debug bool) error {
+	if debug {
+		ctx = deltaLogContext(ctx, d)
+		zapctx.Debug(ctx, "processing delta")
+	}
+	if err := w.applyDelta(ctx, ctl, modelStatef, d, !debug); err != nil {
+		return err
+	}
+	if w.DeltaExporter != nil && modelStatef(d.Entity.EntityId().ModelUUID) != nil {
+		w.DeltaExporter.Export(ctl.Name, d, received, initial)
+	}
+	return nil
+}
+
+// deltaLogContext returns a context that logs the identity of the
+// entity the given delta is for.
+func deltaLogContext(ctx context.Context, d jujuparams.Delta) context.Context {
+	eid := d.Entity.EntityId()
+	return zapctx.WithFields(ctx, zap.String("model-uuid", eid.ModelUUID), zap.String("kind", eid.Kind), zap.String("id", eid.Id))
+}
+
+// claimController claims the monitoring of the given controller for a
+// new watcher. If another watcher holds a current claim on the
+// controller an error with a code of CodeMonitorConflict is returned and
+// the watcher must not be started, the controller is claimed again the
+// next time the controllers are polled.
+func (w *Watcher) claimController(ctx context.Context, ctl *dbmodel.Controller) (*dbmodel.ControllerMonitor, error) {
+	const op = errors.Op("jimm.claimController")
+
+	holder := w.Holder
+	if holder == "" {
+		holder, _ = os.Hostname()
+	}
+	monitor := dbmodel.ControllerMonitor{
+		ControllerName: ctl.Name,
+		Holder:         holder,
+		Nonce:          uuid.NewString(),
+	}
+	claimed, err := w.Database.ClaimControllerMonitor(ctx, &monitor, monitorStaleAfter)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	if !claimed {
+		current := dbmodel.ControllerMonitor{ControllerName: ctl.Name}
+		if err := w.Database.GetControllerMonitor(ctx, &current); err != nil {
+			zapctx.Error(ctx, "cannot get controller monitor", zap.Error(err))
+		}
+		servermon.MonitorConflictCount.WithLabelValues(ctl.UUID).Inc()
+		zapctx.Warn(ctx, "controller is monitored by another watcher, not starting", zap.String("holder", current.Holder))
+		return nil, errors.E(op, errors.CodeMonitorConflict, fmt.Sprintf("controller %s is monitored by %s", ctl.Name, current.Holder))
+	}
+	return &monitor, nil
+}
+
+// holdController renews the watcher's claim on the given controller
+// until the given context is canceled. If the claim has been taken over
+// by another watcher the conflict is reported and cancel is called to
+// stop this watcher.
+func (w *Watcher) holdController(ctx context.Context, cancel context.CancelFunc, ctl *dbmodel.Controller, monitor *dbmodel.ControllerMonitor) {
+	ticker := time.NewTicker(monitorHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		held, err := w.Database.RenewControllerMonitor(ctx, monitor)
+		if err != nil {
+			// The claim is kept until it goes stale, keep trying.
+			zapctx.Error(ctx, "cannot renew controller monitor", zap.Error(err))
+			continue
+		}
+		if !held {
+			w.monitorConflict(ctx, ctl, monitor)
+			cancel()
+			return
+		}
+	}
+}
+
+// monitorConflict reports that the watcher holding the given monitor has
+// found its claim on the controller taken over by another watcher. Both
+// watchers could have applied updates from the controller, so an alert
+// is raised.
+func (w *Watcher) monitorConflict(ctx context.Context, ctl *dbmodel.Controller, monitor *dbmodel.ControllerMonitor) {
+	current := dbmodel.ControllerMonitor{ControllerName: ctl.Name}
+	if err := w.Database.GetControllerMonitor(ctx, &current); err != nil && errors.ErrorCode(err) != errors.CodeNotFound {
+		zapctx.Error(ctx, "cannot get controller monitor", zap.Error(err))
+	}
+	servermon.MonitorConflictCount.WithLabelValues(ctl.UUID).Inc()
+	zapctx.Error(ctx, "controller is monitored by another watcher, standing down", zap.String("holder", monitor.Holder), zap.String("other-holder", current.Holder))
+	w.Notifier.Notify(ctx, notify.Event{
+		Kind:       notify.ControllerMonitorConflict,
+		Controller: ctl.Name,
+		Message:    fmt.Sprintf("controller %s is monitored by another watcher (%s), the watcher on %s has stopped", ctl.Name, current.Holder, monitor.Holder),
+	})
+}
+
+// releaseController releases the watcher's claim on its controller so
+// that another watcher may take over immediately.
+func (w *Watcher) releaseController(ctx context.Context, monitor *dbmodel.ControllerMonitor) {
+	// The context is probably canceled, use a fresh one to release the
+	// claim.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := w.Database.ReleaseControllerMonitor(ctx, monitor); err != nil {
+		zapctx.Warn(ctx, "cannot release controller monitor", zap.Error(err))
+	}
+}
+
+// entityKinds holds the kinds of entity reported by the entity metrics.
+var entityKinds = []string{"applications", "machines", "offers", "units"}
+
+// updateEntityMetrics sets the entity gauges for the given controller
+// from the states of its models.
+func (w *Watcher) updateEntityMetrics(ctl *dbmodel.Controller, modelStates map[string]*modelState) {
+	totals := make(map[string]int, len(entityKinds))
+	controllerLabels := w.MetricLabels.ControllerLabelValues(ctl)
+	if w.PerModelMetrics {
+		// Models that have been removed would otherwise keep
+		// reporting their last counts.
+		servermon.MonitorModelEntities.DeletePartialMatch(prometheus.Labels{"controller": ctl.UUID})
+	}
+	for uuid, st := range modelStates {
+		for _, kind := range entityKinds {
+			count := st.entityCount(kind)
+			totals[kind] += count
+			if w.PerModelMetrics {
+				values := append([]string{ctl.UUID, uuid, kind}, controllerLabels...)
+				servermon.MonitorModelEntities.WithLabelValues(append(values, st.labels...)...).Set(float64(count))
+			}
+		}
 	}
+	for _, kind := range entityKinds {
+		servermon.MonitorControllerEntities.WithLabelValues(append([]string{ctl.UUID, kind}, controllerLabels...)...).Set(float64(totals[kind]))
+	}
+	servermon.MonitorControllerModels.WithLabelValues(ctl.UUID).Set(float64(len(modelStates)))
+}
+
+// deleteEntityMetrics removes the entity gauges for the given
+// controller, whose counts are no longer being maintained.
+func deleteEntityMetrics(ctl *dbmodel.Controller) {
+	servermon.MonitorControllerEntities.DeletePartialMatch(prometheus.Labels{"controller": ctl.UUID})
+	servermon.MonitorModelEntities.DeletePartialMatch(prometheus.Labels{"controller": ctl.UUID})
+	servermon.MonitorControllerModels.DeleteLabelValues(ctl.UUID)
 }
 
 // watchAllModelSummaries connects to the given controller and watches the
@@ -450,8 +1057,85 @@ func (w *Watcher) watchAllModelSummaries(ctx context.Context, ctl *dbmodel.Contr
 	}
 }
 
-func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelState, d jujuparams.Delta) error {
+// applyDelta applies the given delta received from the given controller.
+// Failures are retried with an increasing delay, unless retrying cannot
+// succeed. Deltas that still cannot be applied are dead-lettered so that
+// a single failing delta does not stop the controller's watcher. An error
+// is only returned if the context is cancelled.
+//
+// If annotate is set the delta's identity is added to the logging
+// context before any failure is logged.
+func (w *Watcher) applyDelta(ctx context.Context, ctl *dbmodel.Controller, modelStatef func(string) *modelState, d jujuparams.Delta, annotate bool) error {
 	defer w.deltaProcessedNotification()
+
+	delay := w.deltaRetryDelay
+	if delay == 0 {
+		delay = defaultDeltaRetryDelay
+	}
+	for attempt := 1; ; attempt++ {
+		err := w.handleDelta(ctx, modelStatef, d)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if annotate {
+			ctx = deltaLogContext(ctx, d)
+			annotate = false
+		}
+		if attempt >= deltaAttempts || !isRetryableDeltaError(err) {
+			w.deadLetterDelta(ctx, ctl, d, err, attempt)
+			return nil
+		}
+		zapctx.Warn(ctx, "cannot apply delta, retrying", zap.Error(err), zap.Int("attempt", attempt))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// isRetryableDeltaError returns whether applying a delta that failed with
+// the given error may succeed if it is retried.
+func isRetryableDeltaError(err error) bool {
+	switch errors.ErrorCode(err) {
+	case errors.CodeNotFound, errors.CodeBadRequest, errors.CodeAlreadyExists:
+		return false
+	}
+	return true
+}
+
+// deadLetterDelta records the given delta, which could not be applied
+// after the given number of attempts, in the database for later
+// investigation.
+func (w *Watcher) deadLetterDelta(ctx context.Context, ctl *dbmodel.Controller, d jujuparams.Delta, err error, attempts int) {
+	eid := d.Entity.EntityId()
+	zapctx.Error(ctx, "cannot apply delta, dead-lettering", zap.Error(err), zap.Int("attempts", attempts))
+	servermon.MonitorDeltasDeadLetteredCount.WithLabelValues(ctl.UUID, eid.Kind).Inc()
+
+	dl := dbmodel.DeadLetterDelta{
+		ControllerName: ctl.Name,
+		ModelUUID:      eid.ModelUUID,
+		Kind:           eid.Kind,
+		EntityID:       eid.Id,
+		Removed:        d.Removed,
+		Error:          err.Error(),
+		Attempts:       attempts,
+	}
+	if buf, merr := json.Marshal(&d); merr == nil {
+		dl.Delta = dbmodel.JSON(buf)
+	} else {
+		zapctx.Error(ctx, "cannot marshal delta", zap.Error(merr))
+	}
+	if err := w.Database.AddDeadLetterDelta(ctx, &dl); err != nil {
+		zapctx.Error(ctx, "cannot store dead-lettered delta", zap.Error(err))
+	}
+}
+
+func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelState, d jujuparams.Delta) error {
 	eid := d.Entity.EntityId()
 	state := modelIDf(eid.ModelUUID)
 	if state == nil {
@@ -460,13 +1144,36 @@ func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelS
 	switch eid.Kind {
 	case "application":
 		if d.Removed {
+			delete(state.applications, eid.Id)
+			delete(state.exposed, eid.Id)
+			w.deleteApplicationCharm(ctx, state.id, eid.Id)
 			return nil
 		}
-		return w.updateApplication(ctx, state.id, d.Entity.(*jujuparams.ApplicationInfo))
+		info := d.Entity.(*jujuparams.ApplicationInfo)
+		if charmURL, ok := state.applications[eid.Id]; !ok || charmURL != info.CharmURL || state.exposed[eid.Id] != info.Exposed {
+			if err := w.setApplicationCharm(ctx, state.id, info); err != nil {
+				zapctx.Error(ctx, "cannot record application charm", zap.String("application", info.Name), zap.Error(err))
+			} else {
+				state.applications[eid.Id] = info.CharmURL
+				state.exposed[eid.Id] = info.Exposed
+			}
+		}
+		return w.updateApplication(ctx, state.id, info)
+	case "applicationOffer":
+		state.seen(state.offers, state.unseenOffers, eid.Id, d.Removed)
+		if d.Removed {
+			return nil
+		}
+		return w.updateApplicationOffer(ctx, d.Entity.(*jujuparams.ApplicationOfferInfo))
+	case "branch":
+		return w.updateModelBranch(ctx, state.id, d)
 	case "machine":
+		delete(state.unseenMachines, eid.Id)
+		w.updateMachine(ctx, state.id, d)
 		if d.Removed {
 			state.changed = true
 			delete(state.machines, eid.Id)
+			delete(state.agentVersions, entityAgentKey(eid.Kind, eid.Id))
 			return nil
 		}
 		var cores int64
@@ -480,6 +1187,14 @@ func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelS
 			state.machines[eid.Id] = cores
 			state.changed = true
 		}
+		state.setAgentVersion(entityAgentKey(eid.Kind, eid.Id), machine.AgentStatus.Version)
+	case "relation":
+		state.seen(state.relations, state.unseenRelations, eid.Id, d.Removed)
+		if !d.Removed {
+			w.setApplicationEndpoints(ctx, state.id, d.Entity.(*jujuparams.RelationInfo))
+		}
+	case "remoteApplication":
+		return w.updateRemoteApplication(ctx, state.id, d)
 	case "model":
 		model := dbmodel.Model{
 			ID: state.id,
@@ -489,19 +1204,76 @@ func (w *Watcher) handleDelta(ctx context.Context, modelIDf func(string) *modelS
 		}
 		return w.updateModel(ctx, &model, d.Entity.(*jujuparams.ModelUpdate))
 	case "unit":
+		delete(state.unseenUnits, eid.Id)
 		if d.Removed {
 			state.changed = true
+			state.unitsChanged = true
 			delete(state.units, eid.Id)
+			delete(state.agentVersions, entityAgentKey(eid.Kind, eid.Id))
 			return nil
 		}
-		if !state.units[eid.Id] {
+		unit := d.Entity.(*jujuparams.UnitInfo)
+		if st, ok := state.units[eid.Id]; !ok || st != unit.WorkloadStatus.Current {
 			state.changed = true
-			state.units[eid.Id] = true
+			state.unitsChanged = true
+			state.units[eid.Id] = unit.WorkloadStatus.Current
 		}
+		state.setAgentVersion(entityAgentKey(eid.Kind, eid.Id), unit.AgentStatus.Version)
 	}
 	return nil
 }
 
+// workloadStatusSeverity orders the workload statuses from the least to
+// the most severe.
+var workloadStatusSeverity = []status.Status{
+	status.Terminated,
+	status.Active,
+	status.Unknown,
+	status.Maintenance,
+	status.Waiting,
+	status.Blocked,
+	status.Error,
+}
+
+// summarizeWorkloadStatus returns the most severe of the given unit
+// workload statuses along with the number of units that are in error or
+// blocked. Units with any other workload status, including those that
+// have not reported one, are treated as unknown.
+func summarizeWorkloadStatus(units map[string]status.Status) (string, int64) {
+	worst := -1
+	var unhealthy int64
+	for _, st := range units {
+		if st == status.Error || st == status.Blocked {
+			unhealthy++
+		}
+		i := slices.Index(workloadStatusSeverity, st)
+		if i < 0 {
+			i = slices.Index(workloadStatusSeverity, status.Unknown)
+		}
+		worst = max(worst, i)
+	}
+	if worst < 0 {
+		return "", unhealthy
+	}
+	return string(workloadStatusSeverity[worst]), unhealthy
+}
+
+// countWorkloadStatuses returns the number of the given units with a
+// workload status of active, blocked and error.
+func countWorkloadStatuses(units map[string]status.Status) (active, blocked, errored int64) {
+	for _, st := range units {
+		switch st {
+		case status.Active:
+			active++
+		case status.Blocked:
+			blocked++
+		case status.Error:
+			errored++
+		}
+	}
+	return active, blocked, errored
+}
+
 func (w *Watcher) deleteModel(ctx context.Context, model *dbmodel.Model) error {
 	const op = errors.Op("watcher.deleteModel")
 
@@ -520,6 +1292,7 @@ func (w *Watcher) deleteModel(ctx context.Context, model *dbmodel.Model) error {
 	if err != nil {
 		return errors.E(op, err)
 	}
+	w.Cache.InvalidateModelAccess(model.ResourceTag())
 	return nil
 }
 
@@ -541,6 +1314,137 @@ func (w *Watcher) updateModel(ctx context.Context, model *dbmodel.Model, info *j
 	return nil
 }
 
+// updateApplicationOffer records the connection counts of the given
+// offer. Offers not known to JIMM are ignored.
+func (w *Watcher) updateApplicationOffer(ctx context.Context, info *jujuparams.ApplicationOfferInfo) error {
+	err := w.Database.Transaction(func(tx *db.Database) error {
+		offer := dbmodel.ApplicationOffer{
+			UUID: info.OfferUUID,
+		}
+		if err := tx.GetApplicationOffer(ctx, &offer); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				return nil
+			}
+			return err
+		}
+		if offer.TotalConnectedCount == info.TotalConnectedCount && offer.ActiveConnectedCount == info.ActiveConnectedCount {
+			return nil
+		}
+		offer.TotalConnectedCount = info.TotalConnectedCount
+		offer.ActiveConnectedCount = info.ActiveConnectedCount
+		return tx.UpdateApplicationOffer(ctx, &offer)
+	})
+	if err != nil {
+		zapctx.Error(ctx, "error updating application offer", zap.Error(err))
+	}
+	return nil
+}
+
+// updateRemoteApplication records, or removes, the remote application
+// described by the given delta.
+func (w *Watcher) updateRemoteApplication(ctx context.Context, modelID uint, d jujuparams.Delta) error {
+	app := dbmodel.RemoteApplication{
+		ModelID: modelID,
+		Name:    d.Entity.EntityId().Id,
+	}
+	var err error
+	if d.Removed {
+		err = w.Database.DeleteRemoteApplication(ctx, &app)
+	} else {
+		app.FromJujuRemoteApplicationUpdate(*d.Entity.(*jujuparams.RemoteApplicationUpdate))
+		err = w.Database.UpsertRemoteApplication(ctx, &app)
+	}
+	if err != nil {
+		zapctx.Error(ctx, "error updating remote application", zap.Error(err))
+	}
+	return nil
+}
+
+// updateModelBranch records, or removes, the model branch described by
+// the given delta.
+func (w *Watcher) updateModelBranch(ctx context.Context, modelID uint, d jujuparams.Delta) error {
+	branch := dbmodel.ModelBranch{
+		ModelID:  modelID,
+		BranchID: d.Entity.EntityId().Id,
+	}
+	var err error
+	if d.Removed {
+		err = w.Database.DeleteModelBranch(ctx, &branch)
+	} else {
+		branch.FromJujuBranchInfo(*d.Entity.(*jujuparams.BranchInfo))
+		err = w.Database.UpsertModelBranch(ctx, &branch)
+	}
+	if err != nil {
+		zapctx.Error(ctx, "error updating model branch", zap.Error(err))
+	}
+	return nil
+}
+
+// updateMachine records, or removes, the placement and network details
+// of the machine described by the given delta.
+func (w *Watcher) updateMachine(ctx context.Context, modelID uint, d jujuparams.Delta) {
+	machine := dbmodel.Machine{
+		ModelID:   modelID,
+		MachineID: d.Entity.EntityId().Id,
+	}
+	var err error
+	if d.Removed {
+		err = w.Database.DeleteMachine(ctx, &machine)
+	} else {
+		machine.FromJujuMachineInfo(*d.Entity.(*jujuparams.MachineInfo))
+		err = w.Database.UpsertMachine(ctx, &machine)
+	}
+	if err != nil {
+		zapctx.Error(ctx, "error updating machine", zap.Error(err))
+	}
+}
+
+// setApplicationCharm records the charm used by the given application.
+func (w *Watcher) setApplicationCharm(ctx context.Context, modelID uint, info *jujuparams.ApplicationInfo) error {
+	if info.CharmURL == "" {
+		return nil
+	}
+	return w.Database.SetApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         modelID,
+		ApplicationName: info.Name,
+		CharmURL:        info.CharmURL,
+		Exposed:         info.Exposed,
+	})
+}
+
+// setApplicationEndpoints records the application endpoints taking part
+// in the given relation.
+func (w *Watcher) setApplicationEndpoints(ctx context.Context, modelID uint, info *jujuparams.RelationInfo) {
+	var eps []dbmodel.ApplicationEndpoint
+	for _, ep := range info.Endpoints {
+		if ep.ApplicationName == "" || ep.Relation.Name == "" {
+			continue
+		}
+		eps = append(eps, dbmodel.ApplicationEndpoint{
+			ModelID:         modelID,
+			ApplicationName: ep.ApplicationName,
+			Name:            ep.Relation.Name,
+			Role:            ep.Relation.Role,
+			Interface:       ep.Relation.Interface,
+		})
+	}
+	if err := w.Database.SetApplicationEndpoints(ctx, eps); err != nil {
+		zapctx.Error(ctx, "cannot record application endpoints", zap.String("relation", info.Key), zap.Error(err))
+	}
+}
+
+// deleteApplicationCharm removes the charm recorded for the named
+// application.
+func (w *Watcher) deleteApplicationCharm(ctx context.Context, modelID uint, name string) {
+	err := w.Database.DeleteApplicationCharm(ctx, &dbmodel.ApplicationCharm{
+		ModelID:         modelID,
+		ApplicationName: name,
+	})
+	if err != nil {
+		zapctx.Error(ctx, "cannot remove application charm", zap.String("application", name), zap.Error(err))
+	}
+}
+
 func (w *Watcher) updateApplication(ctx context.Context, modelID uint, info *jujuparams.ApplicationInfo) error {
 	err := w.Database.Transaction(func(tx *db.Database) error {
 		m := dbmodel.Model{
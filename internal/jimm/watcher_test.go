@@ -5,6 +5,7 @@ package jimm_test
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -13,15 +14,18 @@ import (
 	qt "github.com/frankban/quicktest"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/juju/state"
 	"github.com/juju/names/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
 const testWatcherEnv = `clouds:
@@ -98,9 +102,17 @@ var watcherTests = []struct {
 				ModelUUID:  "00000002-0000-0000-0000-000000000001",
 				Id:         "2",
 				InstanceId: "machine-2",
+				Hostname:   "juju-2",
 				HardwareCharacteristics: &instance.HardwareCharacteristics{
-					CpuCores: newUint64(2),
+					CpuCores:         newUint64(2),
+					AvailabilityZone: newString("zone-1"),
 				},
+				Addresses: []jujuparams.Address{{
+					Value: "10.0.0.2",
+					CIDR:  "10.0.0.0/24",
+					Type:  "ipv4",
+					Scope: "local-cloud",
+				}},
 			},
 		}},
 		nil,
@@ -119,6 +131,21 @@ var watcherTests = []struct {
 
 		c.Check(model.Machines, qt.Equals, int64(1))
 		c.Check(model.Cores, qt.Equals, int64(2))
+
+		machines, err := db.GetModelMachines(ctx, model.ID)
+		c.Assert(err, qt.IsNil)
+		c.Assert(machines, qt.HasLen, 1)
+		c.Check(machines[0].MachineID, qt.Equals, "2")
+		c.Check(machines[0].InstanceID, qt.Equals, "machine-2")
+		c.Check(machines[0].Hostname, qt.Equals, "juju-2")
+		c.Check(machines[0].AvailabilityZone, qt.Equals, "zone-1")
+		c.Check(machines[0].Addresses, qt.DeepEquals, dbmodel.Addresses{{
+			Value: "10.0.0.2",
+			CIDR:  "10.0.0.0/24",
+			Type:  "ipv4",
+			Scope: "local-cloud",
+		}})
+		c.Check(machines[0].Subnets, qt.DeepEquals, dbmodel.Strings{"10.0.0.0/24"})
 	},
 }, {
 	name: "UpdateMachine",
@@ -191,6 +218,180 @@ var watcherTests = []struct {
 
 		c.Check(model.Machines, qt.Equals, int64(0))
 		c.Check(model.Cores, qt.Equals, int64(0))
+
+		machines, err := db.GetModelMachines(ctx, model.ID)
+		c.Assert(err, qt.IsNil)
+		c.Check(machines, qt.HasLen, 0)
+	},
+}, {
+	name: "RestoreModelState",
+	initDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		err = db.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+			ModelID:  model.ID,
+			Machines: dbmodel.Int64Map{"0": 2, "1": 4},
+			Units:    dbmodel.StringMap{"app-1/0": "active", "app-1/1": "blocked"},
+		})
+		c.Assert(err, qt.IsNil)
+	},
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.MachineInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "0",
+				HardwareCharacteristics: &instance.HardwareCharacteristics{
+					CpuCores: newUint64(2),
+				},
+			},
+		}, {
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+				WorkloadStatus: jujuparams.StatusInfo{
+					Current: status.Active,
+				},
+			},
+		}}, {{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/2",
+				WorkloadStatus: jujuparams.StatusInfo{
+					Current: status.Blocked,
+				},
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		// Machine 1 and unit app-1/1 were not in the initial deltas
+		// so they are no longer counted.
+		c.Check(model.Machines, qt.Equals, int64(1))
+		c.Check(model.Cores, qt.Equals, int64(2))
+		c.Check(model.Units, qt.Equals, int64(2))
+		c.Check(model.WorkloadStatus, qt.Equals, "blocked")
+		c.Check(model.UnhealthyUnits, qt.Equals, int64(1))
+		c.Check(model.ActiveUnits, qt.Equals, int64(1))
+		c.Check(model.BlockedUnits, qt.Equals, int64(1))
+		c.Check(model.ErrorUnits, qt.Equals, int64(0))
+
+		ws := dbmodel.ModelWatcherState{
+			ModelID: model.ID,
+		}
+		err = db.GetModelWatcherState(ctx, &ws)
+		c.Assert(err, qt.IsNil)
+		c.Check(ws.Machines, qt.DeepEquals, dbmodel.Int64Map{"0": 2})
+		c.Check(ws.Units, qt.DeepEquals, dbmodel.StringMap{"app-1/0": "active", "app-1/2": "blocked"})
+	},
+}, {
+	name: "AgentVersions",
+	initDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		err = db.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+			ModelID:       model.ID,
+			Machines:      dbmodel.Int64Map{"0": 2, "1": 4},
+			AgentVersions: dbmodel.StringMap{"machine-0": "3.4.0", "machine-1": "3.4.0"},
+		})
+		c.Assert(err, qt.IsNil)
+	},
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.MachineInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "0",
+				AgentStatus: jujuparams.StatusInfo{
+					Current: status.Started,
+					Version: "3.5.1",
+				},
+			},
+		}, {
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/0",
+				AgentStatus: jujuparams.StatusInfo{
+					Current: status.Idle,
+					Version: "3.5.1",
+				},
+			},
+		}, {
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/1",
+				AgentStatus: jujuparams.StatusInfo{
+					Current: status.Idle,
+					Version: "3.4.0",
+				},
+			},
+		}}, {{
+			Removed: true,
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/1",
+			},
+		}, {
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "app-1/2",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		// Machine 1 was not in the initial deltas, unit app-1/1 was
+		// removed and unit app-1/2 has not reported a version.
+		c.Check(model.AgentVersions, qt.DeepEquals, dbmodel.Int64Map{"3.5.1": 2})
+
+		ws := dbmodel.ModelWatcherState{
+			ModelID: model.ID,
+		}
+		err = db.GetModelWatcherState(ctx, &ws)
+		c.Assert(err, qt.IsNil)
+		c.Check(ws.AgentVersions, qt.DeepEquals, dbmodel.StringMap{
+			"machine-0":    "3.5.1",
+			"unit-app-1/0": "3.5.1",
+			"unit-app-1/2": "",
+		})
 	},
 }, {
 	name: "UpdateApplication",
@@ -242,6 +443,9 @@ var watcherTests = []struct {
 			Entity: &jujuparams.UnitInfo{
 				ModelUUID: "00000002-0000-0000-0000-000000000001",
 				Name:      "app-1/2",
+				WorkloadStatus: jujuparams.StatusInfo{
+					Current: status.Active,
+				},
 			},
 		}},
 		nil,
@@ -259,6 +463,8 @@ var watcherTests = []struct {
 		c.Assert(err, qt.IsNil)
 
 		c.Check(model.Units, qt.Equals, int64(1))
+		c.Check(model.WorkloadStatus, qt.Equals, "active")
+		c.Check(model.UnhealthyUnits, qt.Equals, int64(0))
 	},
 }, {
 	name: "UpdateUnit",
@@ -267,12 +473,18 @@ var watcherTests = []struct {
 			Entity: &jujuparams.UnitInfo{
 				ModelUUID: "00000002-0000-0000-0000-000000000001",
 				Name:      "app-1/2",
+				WorkloadStatus: jujuparams.StatusInfo{
+					Current: status.Active,
+				},
 			},
 		}},
 		{{
 			Entity: &jujuparams.UnitInfo{
 				ModelUUID: "00000002-0000-0000-0000-000000000001",
 				Name:      "app-1/2",
+				WorkloadStatus: jujuparams.StatusInfo{
+					Current: status.Error,
+				},
 			},
 		}},
 		nil,
@@ -290,6 +502,9 @@ var watcherTests = []struct {
 		c.Assert(err, qt.IsNil)
 
 		c.Check(model.Units, qt.Equals, int64(1))
+		c.Check(model.WorkloadStatus, qt.Equals, "error")
+		c.Check(model.UnhealthyUnits, qt.Equals, int64(1))
+		c.Check(model.ErrorUnits, qt.Equals, int64(1))
 	},
 }, {
 	name: "DeleteUnit",
@@ -323,6 +538,252 @@ var watcherTests = []struct {
 
 		c.Check(model.Units, qt.Equals, int64(0))
 	},
+}, {
+	name: "CountContainersOffersRelations",
+	initDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		// The relation restored from the persisted state is not in
+		// the initial deltas so it is no longer counted.
+		err = db.UpsertModelWatcherState(ctx, &dbmodel.ModelWatcherState{
+			ModelID:   model.ID,
+			Relations: dbmodel.Strings{"app-3:db app-4:db"},
+		})
+		c.Assert(err, qt.IsNil)
+	},
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.MachineInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "0",
+			},
+		}, {
+			Entity: &jujuparams.MachineInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "0/lxd/0",
+			},
+		}, {
+			Entity: &jujuparams.RelationInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Key:       "app-1:db app-2:db",
+			},
+		}, {
+			Entity: &jujuparams.RelationInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Key:       "app-1:cache app-2:cache",
+			},
+		}, {
+			Entity: &jujuparams.ApplicationOfferInfo{
+				ModelUUID:       "00000002-0000-0000-0000-000000000001",
+				OfferName:       "offer-1",
+				OfferUUID:       "00000010-0000-0000-0000-000000000001",
+				ApplicationName: "app-1",
+			},
+		}}, {{
+			Removed: true,
+			Entity: &jujuparams.RelationInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Key:       "app-1:cache app-2:cache",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err := db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+
+		c.Check(model.Machines, qt.Equals, int64(2))
+		c.Check(model.Containers, qt.Equals, int64(1))
+		c.Check(model.Relations, qt.Equals, int64(1))
+		c.Check(model.OfferCount, qt.Equals, int64(1))
+
+		ws := dbmodel.ModelWatcherState{
+			ModelID: model.ID,
+		}
+		err = db.GetModelWatcherState(ctx, &ws)
+		c.Assert(err, qt.IsNil)
+		c.Check(ws.Relations, qt.DeepEquals, dbmodel.Strings{"app-1:db app-2:db"})
+		c.Check(ws.Offers, qt.DeepEquals, dbmodel.Strings{"offer-1"})
+	},
+}, {
+	name: "UpdateApplicationOffer",
+	initDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		var m dbmodel.Model
+		m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+		err := db.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+
+		err = db.AddApplicationOffer(ctx, &dbmodel.ApplicationOffer{
+			ModelID:         m.ID,
+			UUID:            "00000010-0000-0000-0000-000000000001",
+			Name:            "offer-1",
+			ApplicationName: "app-1",
+		})
+		c.Assert(err, qt.IsNil)
+	},
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.ApplicationOfferInfo{
+				ModelUUID:            "00000002-0000-0000-0000-000000000001",
+				OfferName:            "offer-1",
+				OfferUUID:            "00000010-0000-0000-0000-000000000001",
+				ApplicationName:      "app-1",
+				TotalConnectedCount:  3,
+				ActiveConnectedCount: 2,
+			},
+		}, {
+			// Offers not known to JIMM are ignored.
+			Entity: &jujuparams.ApplicationOfferInfo{
+				ModelUUID:           "00000002-0000-0000-0000-000000000001",
+				OfferName:           "offer-2",
+				OfferUUID:           "00000010-0000-0000-0000-000000000002",
+				ApplicationName:     "app-2",
+				TotalConnectedCount: 1,
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		offer := dbmodel.ApplicationOffer{
+			UUID: "00000010-0000-0000-0000-000000000001",
+		}
+		err := db.GetApplicationOffer(ctx, &offer)
+		c.Assert(err, qt.IsNil)
+		c.Check(offer.TotalConnectedCount, qt.Equals, 3)
+		c.Check(offer.ActiveConnectedCount, qt.Equals, 2)
+	},
+}, {
+	name: "AddRemoteApplication",
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.RemoteApplicationUpdate{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "remote-app-1",
+				OfferURL:  "controller-1:alice@canonical.com/model-2.offer-1",
+				Life:      life.Value(state.Alive.String()),
+				Status: jujuparams.StatusInfo{
+					Current: "active",
+				},
+			},
+		}, {
+			Entity: &jujuparams.RemoteApplicationUpdate{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "remote-app-2",
+				OfferURL:  "controller-1:alice@canonical.com/model-2.offer-2",
+				Life:      life.Value(state.Alive.String()),
+				Status: jujuparams.StatusInfo{
+					Current: "active",
+				},
+			},
+		}},
+		{{
+			Entity: &jujuparams.RemoteApplicationUpdate{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "remote-app-1",
+				OfferURL:  "controller-1:alice@canonical.com/model-2.offer-1",
+				Life:      life.Value(state.Dying.String()),
+				Status: jujuparams.StatusInfo{
+					Current: "terminated",
+				},
+			},
+		}, {
+			Removed: true,
+			Entity: &jujuparams.RemoteApplicationUpdate{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Name:      "remote-app-2",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		var m dbmodel.Model
+		m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+		err := db.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+
+		apps, err := db.GetModelRemoteApplications(ctx, m.ID)
+		c.Assert(err, qt.IsNil)
+		c.Assert(apps, qt.HasLen, 1)
+		c.Check(apps[0].Name, qt.Equals, "remote-app-1")
+		c.Check(apps[0].OfferURL, qt.Equals, "controller-1:alice@canonical.com/model-2.offer-1")
+		c.Check(apps[0].Life, qt.Equals, state.Dying.String())
+		c.Check(apps[0].Status, qt.Equals, "terminated")
+	},
+}, {
+	name: "AddBranch",
+	deltas: [][]jujuparams.Delta{
+		{{
+			Entity: &jujuparams.BranchInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "branch-id-1",
+				Name:      "branch-1",
+				AssignedUnits: map[string][]string{
+					"app-1": {"app-1/1", "app-1/0"},
+				},
+				Config: map[string][]jujuparams.ItemChange{
+					"app-1": {{Key: "password", NewValue: "secret"}},
+				},
+				Created:   1700000000,
+				CreatedBy: "alice@canonical.com",
+			},
+		}, {
+			Entity: &jujuparams.BranchInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "branch-id-2",
+				Name:      "branch-2",
+			},
+		}},
+		{{
+			Removed: true,
+			Entity: &jujuparams.BranchInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "branch-id-2",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		var m dbmodel.Model
+		m.SetTag(names.NewModelTag("00000002-0000-0000-0000-000000000001"))
+		err := db.GetModel(ctx, &m)
+		c.Assert(err, qt.IsNil)
+
+		branches, err := db.GetModelBranches(ctx, m.ID)
+		c.Assert(err, qt.IsNil)
+		c.Assert(branches, qt.HasLen, 1)
+		c.Check(branches[0].BranchID, qt.Equals, "branch-id-1")
+		c.Check(branches[0].Name, qt.Equals, "branch-1")
+		c.Check(branches[0].AssignedUnits, qt.DeepEquals, dbmodel.Strings{"app-1/0", "app-1/1"})
+		c.Check(branches[0].ConfigChanges, qt.DeepEquals, dbmodel.Strings{"app-1:password"})
+		c.Check(branches[0].BranchCreated.Time.Equal(time.Unix(1700000000, 0)), qt.IsTrue)
+		c.Check(branches[0].BranchCreatedBy, qt.Equals, "alice@canonical.com")
+		c.Check(branches[0].BranchCompleted.Valid, qt.IsFalse)
+	},
 }, {
 	name: "UnknownModelsIgnored",
 	deltas: [][]jujuparams.Delta{
@@ -409,6 +870,52 @@ var watcherTests = []struct {
 			},
 		})
 	},
+}, {
+	name: "DeadLetterDelta",
+	deltas: [][]jujuparams.Delta{
+		{{
+			// PostgreSQL cannot store NUL characters in text columns
+			// so this delta can never be applied.
+			Entity: &jujuparams.ModelUpdate{
+				ModelUUID:      "00000002-0000-0000-0000-000000000001",
+				Name:           "model\x00-1",
+				Owner:          "alice@canonical.com",
+				Life:           life.Value(state.Alive.String()),
+				ControllerUUID: "00000001-0000-0000-0000-000000000001",
+			},
+		}, {
+			Entity: &jujuparams.MachineInfo{
+				ModelUUID: "00000002-0000-0000-0000-000000000001",
+				Id:        "0",
+			},
+		}},
+		nil,
+	},
+	checkDB: func(c *qt.C, db db.Database) {
+		ctx := context.Background()
+
+		deltas, err := db.ListDeadLetterDeltas(ctx, "controller-1")
+		c.Assert(err, qt.IsNil)
+		c.Assert(deltas, qt.HasLen, 1)
+		c.Check(deltas[0].ModelUUID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+		c.Check(deltas[0].Kind, qt.Equals, "model")
+		c.Check(deltas[0].EntityID, qt.Equals, "00000002-0000-0000-0000-000000000001")
+		c.Check(deltas[0].Attempts, qt.Equals, 3)
+		c.Check(deltas[0].Error, qt.Not(qt.Equals), "")
+
+		// The watcher continues with the deltas following the failed
+		// delta.
+		model := dbmodel.Model{
+			UUID: sql.NullString{
+				String: "00000002-0000-0000-0000-000000000001",
+				Valid:  true,
+			},
+		}
+		err = db.GetModel(ctx, &model)
+		c.Assert(err, qt.IsNil)
+		c.Check(model.Name, qt.Equals, "model-1")
+		c.Check(model.Machines, qt.Equals, int64(1))
+	},
 }, {
 	name: "DeleteDyingModel",
 	deltas: [][]jujuparams.Delta{
@@ -624,7 +1131,7 @@ func TestWatcher(t *testing.T) {
 var modelSummaryWatcherTests = []struct {
 	name           string
 	summaries      [][]jujuparams.ModelAbstract
-	checkPublisher func(*qt.C, *testPublisher)
+	checkPublisher func(*qt.C, *jimmtest.Publisher)
 }{{
 	name: "ModelSummaries",
 	summaries: [][]jujuparams.ModelAbstract{
@@ -656,8 +1163,8 @@ var modelSummaryWatcherTests = []struct {
 		}},
 		nil,
 	},
-	checkPublisher: func(c *qt.C, publisher *testPublisher) {
-		c.Assert(publisher.messages, qt.DeepEquals, []interface{}{
+	checkPublisher: func(c *qt.C, publisher *jimmtest.Publisher) {
+		c.Assert(publisher.Messages(), qt.DeepEquals, []interface{}{
 			jujuparams.ModelAbstract{
 				UUID:   "00000002-0000-0000-0000-000000000001",
 				Status: "test status",
@@ -685,7 +1192,7 @@ func TestModelSummaryWatcher(t *testing.T) {
 			nextC := make(chan []jujuparams.ModelAbstract)
 			var stopped uint32
 
-			publisher := &testPublisher{}
+			publisher := &jimmtest.Publisher{}
 
 			w := &jimm.Watcher{
 				Pubsub: publisher,
@@ -778,7 +1285,7 @@ func TestWatcherSetsControllerUnavailable(t *testing.T) {
 		&jimmtest.Dialer{
 			Err: errors.E("test error"),
 		},
-		&testPublisher{},
+		&jimmtest.Publisher{},
 		controllerUnavailableChannel,
 	)
 
@@ -806,6 +1313,8 @@ func TestWatcherSetsControllerUnavailable(t *testing.T) {
 		err = w.Database.GetController(ctx, &ctl)
 		c.Assert(err, qt.IsNil)
 		c.Check(ctl.UnavailableSince.Valid, qt.Equals, true)
+		c.Check(ctl.UnavailableReason, qt.Equals, dbmodel.UnavailableUnknown)
+		c.Check(ctl.UnavailableError, qt.Equals, "test error")
 	}
 	cancel()
 	wg.Wait()
@@ -842,7 +1351,7 @@ func TestWatcherClearsControllerUnavailable(t *testing.T) {
 				},
 			},
 		},
-		Pubsub: &testPublisher{},
+		Pubsub: &jimmtest.Publisher{},
 	}
 
 	env := jimmtest.ParseEnvironment(c, testWatcherEnv)
@@ -856,10 +1365,10 @@ func TestWatcherClearsControllerUnavailable(t *testing.T) {
 	}
 	err = w.Database.GetController(ctx, &ctl)
 	c.Assert(err, qt.IsNil)
-	ctl.UnavailableSince = sql.NullTime{
+	ctl.SetUnavailableAt(sql.NullTime{
 		Time:  time.Now(),
 		Valid: true,
-	}
+	}, dbmodel.UnavailableDialTimeout, context.DeadlineExceeded)
 	err = w.Database.UpdateController(ctx, &ctl)
 	c.Assert(err, qt.IsNil)
 
@@ -880,6 +1389,8 @@ func TestWatcherClearsControllerUnavailable(t *testing.T) {
 	err = w.Database.GetController(context.Background(), &ctl)
 	c.Assert(err, qt.IsNil)
 	c.Assert(ctl.UnavailableSince.Valid, qt.IsFalse)
+	c.Check(ctl.UnavailableReason, qt.Equals, dbmodel.UnavailableReason(""))
+	c.Check(ctl.UnavailableError, qt.Equals, "")
 }
 
 func TestWatcherRemoveDyingModelsOnStartup(t *testing.T) {
@@ -889,7 +1400,7 @@ func TestWatcherRemoveDyingModelsOnStartup(t *testing.T) {
 	defer cancel()
 
 	w := &jimm.Watcher{
-		Pubsub: &testPublisher{},
+		Pubsub: &jimmtest.Publisher{},
 		Database: db.Database{
 			DB: jimmtest.PostgresDB(c, nil),
 		},
@@ -978,7 +1489,7 @@ func TestWatcherIgnoreDeltasForModelsFromIncorrectController(t *testing.T) {
 
 	nextC := make(chan []jujuparams.Delta)
 	w := &jimm.Watcher{
-		Pubsub: &testPublisher{},
+		Pubsub: &jimmtest.Publisher{},
 		Database: db.Database{
 			DB: jimmtest.PostgresDB(c, nil),
 		},
@@ -1082,6 +1593,191 @@ func TestWatcherIgnoreDeltasForModelsFromIncorrectController(t *testing.T) {
 	c.Check(m2, qt.DeepEquals, m1)
 }
 
+func TestWatcherEntityMetrics(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// nextC is unbuffered so that a send completes only once the
+	// watcher has finished processing the previous batch of deltas.
+	nextC := make(chan []jujuparams.Delta)
+	w := jimm.NewWatcherWithDeltaProcessedChannel(
+		db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		&jimmtest.Dialer{
+			API: &jimmtest.API{
+				AllModelWatcherNext_: func(ctx context.Context, id string) ([]jujuparams.Delta, error) {
+					select {
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					case d, ok := <-nextC:
+						if ok {
+							return d, nil
+						}
+						cancel()
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+				},
+				AllModelWatcherStop_: func(context.Context, string) error {
+					return nil
+				},
+				WatchAllModels_: func(context.Context) (string, error) {
+					return "1234", nil
+				},
+				ModelInfo_: func(context.Context, *jujuparams.ModelInfo) error {
+					return errors.E(errors.CodeNotFound)
+				},
+			},
+		},
+		nil,
+		nil,
+	)
+	w.PerModelMetrics = true
+
+	env := jimmtest.ParseEnvironment(c, testWatcherEnv)
+	err := w.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDB(c, w.Database)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := w.Watch(ctx, time.Millisecond)
+		checkIfContextCanceled(c, ctx, err)
+	}()
+
+	const modelUUID = "00000002-0000-0000-0000-000000000001"
+	nextC <- []jujuparams.Delta{{
+		Entity: &jujuparams.ApplicationInfo{
+			ModelUUID: modelUUID,
+			Name:      "app-1",
+		},
+	}, {
+		Entity: &jujuparams.MachineInfo{
+			ModelUUID: modelUUID,
+			Id:        "0",
+		},
+	}, {
+		Entity: &jujuparams.UnitInfo{
+			ModelUUID:   modelUUID,
+			Name:        "app-1/0",
+			Application: "app-1",
+		},
+	}, {
+		Entity: &jujuparams.UnitInfo{
+			ModelUUID:   modelUUID,
+			Name:        "app-1/1",
+			Application: "app-1",
+		},
+	}}
+	nextC <- []jujuparams.Delta{}
+
+	controllerUUID := "00000001-0000-0000-0000-000000000001"
+	for kind, n := range map[string]float64{"applications": 1, "machines": 1, "offers": 0, "units": 2} {
+		c.Check(testutil.ToFloat64(servermon.MonitorControllerEntities.WithLabelValues(controllerUUID, kind)), qt.Equals, n, qt.Commentf("controller %s", kind))
+		c.Check(testutil.ToFloat64(servermon.MonitorModelEntities.WithLabelValues(controllerUUID, modelUUID, kind)), qt.Equals, n, qt.Commentf("model %s", kind))
+	}
+
+	close(nextC)
+	wg.Wait()
+}
+
+func TestWatcherReconcilesModelCount(t *testing.T) {
+	c := qt.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// nextC is unbuffered so that a send completes only once the
+	// watcher has finished processing the previous batch of deltas.
+	nextC := make(chan []jujuparams.Delta)
+	w := jimm.NewWatcherWithDeltaProcessedChannel(
+		db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		&jimmtest.Dialer{
+			API: &jimmtest.API{
+				AllModelWatcherNext_: func(ctx context.Context, id string) ([]jujuparams.Delta, error) {
+					select {
+					case <-ctx.Done():
+						return nil, ctx.Err()
+					case d, ok := <-nextC:
+						if ok {
+							return d, nil
+						}
+						cancel()
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+				},
+				AllModelWatcherStop_: func(context.Context, string) error {
+					return nil
+				},
+				WatchAllModels_: func(context.Context) (string, error) {
+					return "1234", nil
+				},
+				ModelInfo_: func(context.Context, *jujuparams.ModelInfo) error {
+					return errors.E(errors.CodeNotFound)
+				},
+			},
+		},
+		nil,
+		nil,
+	)
+	w.ModelCountReconcileInterval = time.Nanosecond
+
+	env := jimmtest.ParseEnvironment(c, testWatcherEnv)
+	err := w.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDB(c, w.Database)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := w.Watch(ctx, time.Millisecond)
+		checkIfContextCanceled(c, ctx, err)
+	}()
+
+	controllerUUID := "00000001-0000-0000-0000-000000000001"
+	added := servermon.MonitorModelCountCorrections.WithLabelValues(controllerUUID, "added")
+	removed := servermon.MonitorModelCountCorrections.WithLabelValues(controllerUUID, "removed")
+	nextC <- []jujuparams.Delta{}
+	nextC <- []jujuparams.Delta{}
+	// The dying and dead models are removed on startup.
+	c.Check(testutil.ToFloat64(servermon.MonitorControllerModels.WithLabelValues(controllerUUID)), qt.Equals, float64(1))
+	addedBefore, removedBefore := testutil.ToFloat64(added), testutil.ToFloat64(removed)
+
+	// Simulate missing the deltas for a model being added and a model
+	// being removed.
+	m1 := env.Model("alice@canonical.com", "model-1").DBObject(c, w.Database)
+	m4 := m1
+	m4.ID = 0
+	m4.Name = "model-4"
+	m4.UUID = sql.NullString{String: "00000002-0000-0000-0000-000000000004", Valid: true}
+	err = w.Database.AddModel(ctx, &m4)
+	c.Assert(err, qt.IsNil)
+	m5 := m1
+	m5.ID = 0
+	m5.Name = "model-5"
+	m5.UUID = sql.NullString{String: "00000002-0000-0000-0000-000000000005", Valid: true}
+	err = w.Database.AddModel(ctx, &m5)
+	c.Assert(err, qt.IsNil)
+	err = w.Database.DeleteModel(ctx, &m1)
+	c.Assert(err, qt.IsNil)
+
+	nextC <- []jujuparams.Delta{}
+	nextC <- []jujuparams.Delta{}
+	c.Check(testutil.ToFloat64(servermon.MonitorControllerModels.WithLabelValues(controllerUUID)), qt.Equals, float64(2))
+	c.Check(testutil.ToFloat64(added)-addedBefore, qt.Equals, float64(2))
+	c.Check(testutil.ToFloat64(removed)-removedBefore, qt.Equals, float64(1))
+
+	close(nextC)
+	wg.Wait()
+}
+
 func checkIfContextCanceled(c *qt.C, ctx context.Context, err error) {
 	errorToCheck := err
 	if ctx.Err() != nil {
@@ -1094,21 +1790,134 @@ func checkIfContextCanceled(c *qt.C, ctx context.Context, err error) {
 	)
 }
 
-type testPublisher struct {
-	mu       sync.Mutex
-	messages []interface{}
+func newUint64(i uint64) *uint64 {
+	return &i
+}
+
+func newString(s string) *string {
+	return &s
 }
 
-func (p *testPublisher) Publish(model string, content interface{}) <-chan struct{} {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	p.messages = append(p.messages, content)
+func TestWatcherStandsDownOnMonitorConflict(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	w := jimm.Watcher{
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		Pubsub: &jimmtest.Publisher{},
+		Holder: "replica-1",
+	}
+
+	env := jimmtest.ParseEnvironment(c, testWatcherEnv)
+	err := w.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+	env.PopulateDB(c, w.Database)
+
+	ctl := dbmodel.Controller{
+		Name: "controller-1",
+	}
+	err = w.Database.GetController(ctx, &ctl)
+	c.Assert(err, qt.IsNil)
+
+	// A watcher does not start whilst another holds a current claim on
+	// the controller.
+	other := dbmodel.ControllerMonitor{
+		ControllerName: "controller-1",
+		Holder:         "replica-2",
+		Nonce:          "other-nonce",
+	}
+	claimed, err := w.Database.ClaimControllerMonitor(ctx, &other, time.Minute)
+	c.Assert(err, qt.IsNil)
+	c.Assert(claimed, qt.IsTrue)
+
+	w.Dialer = &jimmtest.Dialer{
+		Err: errors.E("unexpected dial"),
+	}
+	err = jimm.WatchController(&w, ctx, &ctl)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeMonitorConflict)
+
+	err = w.Database.ReleaseControllerMonitor(ctx, &other)
+	c.Assert(err, qt.IsNil)
 
-	done := make(chan struct{})
-	close(done)
-	return done
+	// A running watcher stands down when its claim is taken over,
+	// without applying the updates it received.
+	w.Dialer = &jimmtest.Dialer{
+		API: &jimmtest.API{
+			AllModelWatcherNext_: func(_ context.Context, _ string) ([]jujuparams.Delta, error) {
+				err := w.Database.DB.Exec(`UPDATE controller_monitors SET holder = 'replica-2', nonce = 'other-nonce' WHERE controller_name = 'controller-1'`).Error
+				c.Assert(err, qt.IsNil)
+				return []jujuparams.Delta{{
+					Entity: &jujuparams.ModelUpdate{
+						ModelUUID: "00000002-0000-0000-0000-000000000001",
+						Name:      "model-1",
+						Owner:     "alice@canonical.com",
+						Life:      life.Dying,
+					},
+				}}, nil
+			},
+			ModelInfo_: func(_ context.Context, info *jujuparams.ModelInfo) error {
+				return errors.E(errors.CodeNotFound)
+			},
+			WatchAllModels_: func(ctx context.Context) (string, error) {
+				return "1234", nil
+			},
+		},
+	}
+	err = jimm.WatchController(&w, ctx, &ctl)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeMonitorConflict)
+
+	m := dbmodel.ControllerMonitor{ControllerName: "controller-1"}
+	err = w.Database.GetControllerMonitor(ctx, &m)
+	c.Assert(err, qt.IsNil)
+	c.Check(m.Holder, qt.Equals, "replica-2")
+	c.Check(m.Nonce, qt.Equals, "other-nonce")
+
+	model := dbmodel.Model{
+		UUID: sql.NullString{
+			String: "00000002-0000-0000-0000-000000000001",
+			Valid:  true,
+		},
+	}
+	err = w.Database.GetModel(ctx, &model)
+	c.Assert(err, qt.IsNil)
+	c.Check(model.Life, qt.Equals, "alive")
 }
 
-func newUint64(i uint64) *uint64 {
-	return &i
+// BenchmarkProcessUnitDeltas measures processing the initial unit deltas
+// of a controller with ten thousand units, with and without debug
+// logging.
+func BenchmarkProcessUnitDeltas(b *testing.B) {
+	deltas := make([]jujuparams.Delta, 10000)
+	for i := range deltas {
+		deltas[i] = jujuparams.Delta{
+			Entity: &jujuparams.UnitInfo{
+				ModelUUID:   "00000002-0000-0000-0000-000000000001",
+				Name:        fmt.Sprintf("app-%d/%d", i/100, i%100),
+				Application: fmt.Sprintf("app-%d", i/100),
+				WorkloadStatus: jujuparams.StatusInfo{
+					Current: status.Active,
+				},
+			},
+		}
+	}
+	ctl := &dbmodel.Controller{
+		Name: "controller-1",
+		UUID: "00000001-0000-0000-0000-000000000001",
+	}
+	w := &jimm.Watcher{}
+
+	for _, debug := range []bool{false, true} {
+		b.Run(fmt.Sprintf("debug=%t", debug), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := jimm.ProcessDeltas(context.Background(), w, ctl, deltas, debug); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
 }
@@ -0,0 +1,200 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// AccessRequestWebhook is the name of the webhook access request events
+// are posted to.
+const AccessRequestWebhook = "access-requests"
+
+// DefaultWebhookSecretGracePeriod is the time after a webhook's signing
+// secret is rotated during which deliveries are also signed with the
+// previous secret, if no period is configured.
+const DefaultWebhookSecretGracePeriod = 24 * time.Hour
+
+// webhookSecretSize is the size, in bytes, of generated webhook signing
+// secrets.
+const webhookSecretSize = 32
+
+// A WebhookNotifier is an AccessRequestNotifier that POSTs each access
+// request event, encoded as JSON, to a URL.
+type WebhookNotifier struct {
+	// URL is the URL events are posted to.
+	URL string
+
+	// Client is the HTTP client used to post events. If this is nil
+	// http.DefaultClient is used.
+	Client *http.Client
+
+	// Name is the name of the webhook, used to find its signing
+	// secret.
+	Name string
+
+	// Signer signs the events posted to the webhook. If this is nil,
+	// or the webhook has no name, events are not signed.
+	Signer notify.Signer
+}
+
+// NotifyAccessRequest implements AccessRequestNotifier.
+func (n *WebhookNotifier) NotifyAccessRequest(ctx context.Context, event apiparams.AccessRequestEvent) error {
+	const op = errors.Op("jimm.NotifyAccessRequest")
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if n.Signer != nil && n.Name != "" {
+		h, err := n.Signer.SignDelivery(ctx, n.Name, body)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		for k, v := range h {
+			req.Header[k] = v
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.E(op, fmt.Sprintf("webhook returned status %s", resp.Status))
+	}
+	return nil
+}
+
+func (j *JIMM) webhookSecretGracePeriod() time.Duration {
+	if j.WebhookSecretGracePeriod > 0 {
+		return j.WebhookSecretGracePeriod
+	}
+	return DefaultWebhookSecretGracePeriod
+}
+
+// SignDelivery implements notify.Signer. Deliveries are signed with the
+// webhook's current secret and, for a grace period after the secret is
+// rotated, with its previous secret. Each delivery is signed with a new
+// nonce and recorded, so that receivers can reject replayed deliveries
+// and operators can audit what was sent. If the webhook has no secret
+// the delivery is not signed.
+func (j *JIMM) SignDelivery(ctx context.Context, webhook string, body []byte) (http.Header, error) {
+	const op = errors.Op("jimm.SignDelivery")
+
+	secret := dbmodel.WebhookSecret{Webhook: webhook}
+	err := j.Database.GetWebhookSecret(ctx, &secret)
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	secrets := [][]byte{secret.Secret}
+	now := time.Now()
+	if len(secret.PreviousSecret) > 0 && now.Before(secret.RotatedAt.Add(j.webhookSecretGracePeriod())) {
+		secrets = append(secrets, secret.PreviousSecret)
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.E(op, err)
+	}
+	h := notify.SignatureHeaders(secrets, now.Unix(), hex.EncodeToString(nonce), body)
+	delivery := dbmodel.WebhookDelivery{
+		Webhook:   webhook,
+		Nonce:     h.Get(notify.NonceHeader),
+		Timestamp: now.Unix(),
+		Signature: h.Get(notify.SignatureHeader),
+	}
+	if err := j.Database.AddWebhookDelivery(ctx, &delivery); err != nil {
+		return nil, errors.E(op, err)
+	}
+	return h, nil
+}
+
+// webhooks returns the names of the webhooks deliveries are signed for.
+func (j *JIMM) webhooks() map[string]bool {
+	webhooks := make(map[string]bool)
+	if n, ok := j.AccessRequestNotifier.(*WebhookNotifier); ok && n.Name != "" {
+		webhooks[n.Name] = true
+	}
+	for _, name := range j.Notifier.Webhooks() {
+		webhooks[name] = true
+	}
+	return webhooks
+}
+
+// RotateWebhookSecret generates a new secret for signing the deliveries
+// to the named webhook. The previous secret continues to sign deliveries
+// for a grace period, so that receivers can be updated to accept the new
+// secret. The new secret is only returned here and cannot be read back.
+// Only JIMM administrators can rotate webhook secrets.
+func (j *JIMM) RotateWebhookSecret(ctx context.Context, user *openfga.User, webhook string) (apiparams.WebhookSecret, error) {
+	const op = errors.Op("jimm.RotateWebhookSecret")
+
+	if !user.JimmAdmin {
+		return apiparams.WebhookSecret{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if !j.webhooks()[webhook] {
+		return apiparams.WebhookSecret{}, errors.E(op, errors.CodeNotFound, fmt.Sprintf("webhook %q not found", webhook))
+	}
+
+	secret := dbmodel.WebhookSecret{
+		Webhook: webhook,
+		Secret:  make([]byte, webhookSecretSize),
+	}
+	if _, err := rand.Read(secret.Secret); err != nil {
+		return apiparams.WebhookSecret{}, errors.E(op, err)
+	}
+	if err := j.Database.RotateWebhookSecret(ctx, &secret); err != nil {
+		return apiparams.WebhookSecret{}, errors.E(op, err)
+	}
+	return apiparams.WebhookSecret{
+		Webhook:   webhook,
+		Secret:    hex.EncodeToString(secret.Secret),
+		RotatedAt: secret.RotatedAt,
+	}, nil
+}
+
+// ListWebhookDeliveries returns at most limit of the signed deliveries to
+// the named webhook, newest first. Only JIMM administrators can list
+// webhook deliveries.
+func (j *JIMM) ListWebhookDeliveries(ctx context.Context, user *openfga.User, webhook string, limit int) ([]apiparams.WebhookDelivery, error) {
+	const op = errors.Op("jimm.ListWebhookDeliveries")
+
+	if !user.JimmAdmin {
+		return nil, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	deliveries, err := j.Database.ListWebhookDeliveries(ctx, webhook, limit)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	resp := make([]apiparams.WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		resp[i] = d.ToAPIWebhookDelivery()
+	}
+	return resp, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/uuid"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/notify"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestSignedWebhookDeliveries(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID:          uuid.NewString(),
+		OpenFGAClient: client,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	var verifier notify.Verifier
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		body, _ = io.ReadAll(req.Body)
+		if err := verifier.Verify(req.Header, body); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	n := &jimm.WebhookNotifier{URL: srv.URL, Name: jimm.AccessRequestWebhook, Signer: j}
+	j.AccessRequestNotifier = n
+
+	// Deliveries are not signed until the webhook has a secret.
+	err = n.NotifyAccessRequest(ctx, apiparams.AccessRequestEvent{Type: apiparams.AccessRequestCreated})
+	c.Check(err, qt.ErrorMatches, `webhook returned status 401 Unauthorized`)
+	c.Check(header.Get(notify.SignatureHeader), qt.Equals, "")
+
+	user := openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, client)
+	_, err = j.RotateWebhookSecret(ctx, user, jimm.AccessRequestWebhook)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	user.JimmAdmin = true
+	_, err = j.RotateWebhookSecret(ctx, user, "no-such-webhook")
+	c.Check(err, qt.ErrorMatches, `webhook "no-such-webhook" not found`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeNotFound)
+
+	secret1, err := j.RotateWebhookSecret(ctx, user, jimm.AccessRequestWebhook)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret1.Webhook, qt.Equals, jimm.AccessRequestWebhook)
+	key1, err := hex.DecodeString(secret1.Secret)
+	c.Assert(err, qt.IsNil)
+	verifier.Secrets = [][]byte{key1}
+
+	err = n.NotifyAccessRequest(ctx, apiparams.AccessRequestEvent{Type: apiparams.AccessRequestCreated})
+	c.Assert(err, qt.IsNil)
+
+	// A replayed delivery is rejected.
+	err = verifier.Verify(header, body)
+	c.Check(err, qt.ErrorMatches, `delivery nonce already used`)
+
+	// After a rotation deliveries are signed with both secrets, so
+	// receivers still using the previous secret accept them.
+	secret2, err := j.RotateWebhookSecret(ctx, user, jimm.AccessRequestWebhook)
+	c.Assert(err, qt.IsNil)
+	c.Check(secret2.Secret, qt.Not(qt.Equals), secret1.Secret)
+	err = n.NotifyAccessRequest(ctx, apiparams.AccessRequestEvent{Type: apiparams.AccessRequestApproved})
+	c.Assert(err, qt.IsNil)
+	key2, err := hex.DecodeString(secret2.Secret)
+	c.Assert(err, qt.IsNil)
+	err = (&notify.Verifier{Secrets: [][]byte{key2}}).Verify(header, body)
+	c.Check(err, qt.IsNil)
+
+	deliveries, err := j.ListWebhookDeliveries(ctx, user, jimm.AccessRequestWebhook, 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(deliveries, qt.HasLen, 2)
+	c.Check(deliveries[0].Nonce, qt.Equals, header.Get(notify.NonceHeader))
+	c.Check(deliveries[0].Signature, qt.Equals, header.Get(notify.SignatureHeader))
+
+	user.JimmAdmin = false
+	_, err = j.ListWebhookDeliveries(ctx, user, jimm.AccessRequestWebhook, 0)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical.
+
+package jimm
+
+import (
+	"context"
+	"sort"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// Whoami returns the given user's identity along with a summary of their
+// effective permissions: the groups they are a member of, whether they
+// are a JIMM administrator, their model quota and the number of models,
+// controllers and clouds they can access, and their recent activity. It
+// is intended to help users debug access problems.
+func (j *JIMM) Whoami(ctx context.Context, user *openfga.User) (apiparams.IdentitySummary, error) {
+	const op = errors.Op("jimm.Whoami")
+
+	quota, err := j.UserQuota(ctx, user, user.ResourceTag())
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err)
+	}
+	summary := apiparams.IdentitySummary{
+		UserTag:         user.ResourceTag().String(),
+		DisplayName:     user.DisplayName,
+		Groups:          []string{},
+		ControllerAdmin: user.JimmAdmin,
+		Quota:           quota,
+	}
+
+	identity := dbmodel.Identity{Name: user.Name}
+	if err := j.Database.FetchIdentity(ctx, &identity); err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err)
+	}
+	if identity.LastLogin.Valid {
+		summary.LastLogin = &identity.LastLogin.Time
+	}
+	if identity.LastActivity.Valid {
+		summary.LastActivity = &identity.LastActivity.Time
+	}
+	summary.ActiveSessions, err = j.Database.CountIdentitySessions(ctx, user.Name)
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err)
+	}
+
+	groupUUIDs, err := user.ListGroups(ctx)
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err, "failed to list groups")
+	}
+	for _, uuid := range groupUUIDs {
+		group := dbmodel.GroupEntry{UUID: uuid}
+		if err := j.Database.GetGroup(ctx, &group); err != nil {
+			if errors.ErrorCode(err) == errors.CodeNotFound {
+				// The group has been removed.
+				continue
+			}
+			return apiparams.IdentitySummary{}, errors.E(op, err)
+		}
+		summary.Groups = append(summary.Groups, group.Name)
+	}
+	sort.Strings(summary.Groups)
+
+	modelUUIDs, err := user.ListModels(ctx, ofganames.ReaderRelation)
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err, "failed to list models")
+	}
+	models, err := j.Database.GetModelsByUUID(ctx, modelUUIDs)
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err)
+	}
+	summary.Models = len(models)
+
+	if user.JimmAdmin {
+		controllers, err := j.getControllers(ctx)
+		if err != nil {
+			return apiparams.IdentitySummary{}, errors.E(op, err)
+		}
+		summary.Controllers = len(controllers)
+	} else {
+		controllers := make(map[uint]bool)
+		for _, m := range models {
+			controllers[m.ControllerID] = true
+		}
+		summary.Controllers = len(controllers)
+	}
+
+	err = j.ForEachUserCloud(ctx, user, func(*dbmodel.Cloud) error {
+		summary.Clouds++
+		return nil
+	})
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Canonical.
+
+package jimm_test
+
+import (
+	"context"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/jimm"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const whoamiTestEnv = `clouds:
+- name: test
+  type: test
+  regions:
+  - name: test-region
+  users:
+  - user: alice@canonical.com
+    access: add-model
+- name: other
+  type: test
+  regions:
+  - name: other-region
+cloud-credentials:
+- name: test-cred
+  cloud: test
+  owner: alice@canonical.com
+  type: empty
+- name: test-cred
+  cloud: test
+  owner: bob@canonical.com
+  type: empty
+controllers:
+- name: controller-1
+  uuid: 00000001-0000-0000-0000-000000000001
+  cloud: test
+  region: test-region
+- name: controller-2
+  uuid: 00000001-0000-0000-0000-000000000002
+  cloud: test
+  region: test-region
+models:
+- name: model-1
+  uuid: 00000002-0000-0000-0000-000000000001
+  owner: alice@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-1
+  machines: 2
+  cores: 4
+- name: model-2
+  uuid: 00000002-0000-0000-0000-000000000002
+  owner: bob@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-2
+  users:
+  - user: alice@canonical.com
+    access: read
+- name: model-3
+  uuid: 00000002-0000-0000-0000-000000000003
+  owner: bob@canonical.com
+  cloud: test
+  region: test-region
+  cloud-credential: test-cred
+  controller: controller-2
+`
+
+func TestWhoami(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	client, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+	c.Assert(err, qt.IsNil)
+
+	j := &jimm.JIMM{
+		UUID: jimmtest.ControllerUUID,
+		Database: db.Database{
+			DB: jimmtest.PostgresDB(c, nil),
+		},
+		OpenFGAClient: client,
+		Quotas:        jimm.QuotaLimits{Models: 5},
+	}
+	err = j.Database.Migrate(ctx, false)
+	c.Assert(err, qt.IsNil)
+
+	env := jimmtest.ParseEnvironment(c, whoamiTestEnv)
+	env.PopulateDBAndPermissions(c, j.ResourceTag(), j.Database, client)
+
+	// alice is a member of eng, and through it of all.
+	eng, err := j.Database.AddGroup(ctx, "eng")
+	c.Assert(err, qt.IsNil)
+	all, err := j.Database.AddGroup(ctx, "all")
+	c.Assert(err, qt.IsNil)
+	err = client.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("alice@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(eng.ResourceTag()),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTagWithRelation(eng.ResourceTag(), ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(all.ResourceTag()),
+	})
+	c.Assert(err, qt.IsNil)
+
+	aliceIdentity := env.User("alice@canonical.com").DBObject(c, j.Database)
+	alice := openfga.NewUser(&aliceIdentity, client)
+	summary, err := j.Whoami(ctx, alice)
+	c.Assert(err, qt.IsNil)
+	c.Check(summary, qt.DeepEquals, apiparams.IdentitySummary{
+		UserTag:         "user-alice@canonical.com",
+		DisplayName:     "alice",
+		Groups:          []string{"all", "eng"},
+		ControllerAdmin: false,
+		Quota: apiparams.UserQuota{
+			UserTag:  "user-alice@canonical.com",
+			Models:   apiparams.QuotaUsage{Limit: 5, Used: 1},
+			Machines: apiparams.QuotaUsage{Used: 2},
+			Cores:    apiparams.QuotaUsage{Used: 4},
+		},
+		Models:      2,
+		Controllers: 2,
+		Clouds:      1,
+	})
+
+	// JIMM administrators can access every controller.
+	charlieIdentity := env.User("charlie@canonical.com").DBObject(c, j.Database)
+	charlie := openfga.NewUser(&charlieIdentity, client)
+	charlie.JimmAdmin = true
+	summary, err = j.Whoami(ctx, charlie)
+	c.Assert(err, qt.IsNil)
+	c.Check(summary.ControllerAdmin, qt.IsTrue)
+	c.Check(summary.Groups, qt.HasLen, 0)
+	c.Check(summary.Controllers, qt.Equals, 2)
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/juju/version/v2"
+	"github.com/rs/cors"
+)
+
+// clientVersionHeader is the header in which juju clients advertise
+// their version when connecting.
+const clientVersionHeader = "X-Juju-ClientVersion"
+
+// Reasons a connection is rejected by a ConnectionPolicy, used to label
+// the rejected connections metric.
+const (
+	rejectedOrigin        = "origin"
+	rejectedClientVersion = "client-version"
+	rejectedUserAgent     = "user-agent"
+)
+
+// A ConnectionPolicy restricts the clients that may open websocket
+// connections. The zero value allows all clients.
+type ConnectionPolicy struct {
+	// AllowedOrigins holds the origins browser clients may connect
+	// from, in addition to any CORS restrictions. Origins may contain a
+	// single "*" wildcard, for example "https://*.example.com". If this
+	// is empty all origins are allowed. Clients that do not send an
+	// Origin header are not browsers and are always allowed.
+	AllowedOrigins []string
+
+	// MinClientVersion is the minimum juju client version that may
+	// connect. Clients that do not advertise a version are allowed. If
+	// this is zero all versions are allowed.
+	MinClientVersion version.Number
+
+	// BlockedUserAgents holds strings which, if found in the User-Agent
+	// header of a client ignoring case, cause the client to be
+	// rejected.
+	BlockedUserAgents []string
+
+	originsOnce sync.Once
+	origins     *cors.Cors
+}
+
+// A PolicyViolation is the error returned when a connection is rejected
+// by a ConnectionPolicy. The message is suitable for returning to the
+// client as a close reason.
+type PolicyViolation struct {
+	// Reason is the policy the connection violates.
+	Reason string
+
+	// Message describes the violation.
+	Message string
+}
+
+// Error implements the error interface.
+func (v *PolicyViolation) Error() string {
+	return v.Message
+}
+
+// Check checks the given websocket upgrade request against the policy,
+// returning a *PolicyViolation if the connection is not allowed.
+func (p *ConnectionPolicy) Check(req *http.Request) error {
+	if p == nil {
+		return nil
+	}
+	if origin := req.Header.Get("Origin"); origin != "" && len(p.AllowedOrigins) > 0 {
+		p.originsOnce.Do(func() {
+			p.origins = cors.New(cors.Options{AllowedOrigins: p.AllowedOrigins})
+		})
+		if !p.origins.OriginAllowed(req) {
+			return &PolicyViolation{
+				Reason:  rejectedOrigin,
+				Message: fmt.Sprintf("connections from origin %s are not allowed", truncate(origin, 64)),
+			}
+		}
+	}
+	if v := req.Header.Get(clientVersionHeader); v != "" && p.MinClientVersion != version.Zero {
+		// Versions that cannot be parsed are not from a known juju
+		// client, so are not restricted.
+		if cv, err := version.Parse(v); err == nil && cv.Compare(p.MinClientVersion) < 0 {
+			return &PolicyViolation{
+				Reason:  rejectedClientVersion,
+				Message: fmt.Sprintf("juju client %s is not supported, upgrade to %s or later", cv, p.MinClientVersion),
+			}
+		}
+	}
+	if ua := strings.ToLower(req.UserAgent()); ua != "" {
+		for _, blocked := range p.BlockedUserAgents {
+			if blocked != "" && strings.Contains(ua, strings.ToLower(blocked)) {
+				return &PolicyViolation{
+					Reason:  rejectedUserAgent,
+					Message: fmt.Sprintf("client %s is not supported", truncate(req.UserAgent(), 64)),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes, so that messages fit in a
+// websocket close frame.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
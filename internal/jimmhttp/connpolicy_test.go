@@ -0,0 +1,84 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/version/v2"
+
+	"github.com/canonical/jimm/v3/internal/jimmhttp"
+)
+
+func TestConnectionPolicy(t *testing.T) {
+	c := qt.New(t)
+
+	policy := &jimmhttp.ConnectionPolicy{
+		AllowedOrigins:    []string{"https://dashboard.example.com", "https://*.jaas.example.com"},
+		MinClientVersion:  version.MustParse("3.1.0"),
+		BlockedUserAgents: []string{"BadClient/1."},
+	}
+
+	tests := []struct {
+		about       string
+		headers     map[string]string
+		expectError string
+	}{{
+		about: "no headers",
+	}, {
+		about:   "allowed origin",
+		headers: map[string]string{"Origin": "https://dashboard.example.com"},
+	}, {
+		about:   "allowed wildcard origin",
+		headers: map[string]string{"Origin": "https://eu.jaas.example.com"},
+	}, {
+		about:       "disallowed origin",
+		headers:     map[string]string{"Origin": "https://evil.example.com"},
+		expectError: `connections from origin https://evil.example.com are not allowed`,
+	}, {
+		about:   "supported client",
+		headers: map[string]string{"X-Juju-ClientVersion": "3.1.2"},
+	}, {
+		about:       "outdated client",
+		headers:     map[string]string{"X-Juju-ClientVersion": "2.9.44"},
+		expectError: `juju client 2.9.44 is not supported, upgrade to 3.1.0 or later`,
+	}, {
+		about:   "unknown client version",
+		headers: map[string]string{"X-Juju-ClientVersion": "not-a-version"},
+	}, {
+		about:   "allowed user agent",
+		headers: map[string]string{"User-Agent": "GoodClient/1.0"},
+	}, {
+		about:       "blocked user agent",
+		headers:     map[string]string{"User-Agent": "Mozilla/5.0 badclient/1.2"},
+		expectError: `client Mozilla/5.0 badclient/1.2 is not supported`,
+	}}
+	for _, test := range tests {
+		c.Run(test.about, func(c *qt.C) {
+			req := httptest.NewRequest("GET", "/api", nil)
+			for k, v := range test.headers {
+				req.Header.Set(k, v)
+			}
+			err := policy.Check(req)
+			if test.expectError == "" {
+				c.Check(err, qt.IsNil)
+				return
+			}
+			c.Check(err, qt.ErrorMatches, test.expectError)
+			c.Check(err, qt.ErrorAs, new(*jimmhttp.PolicyViolation))
+		})
+	}
+}
+
+func TestConnectionPolicyZeroValue(t *testing.T) {
+	c := qt.New(t)
+
+	var policy *jimmhttp.ConnectionPolicy
+	req := httptest.NewRequest("GET", "/api", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("X-Juju-ClientVersion", "2.0.0")
+	c.Check(policy.Check(req), qt.IsNil)
+	c.Check(new(jimmhttp.ConnectionPolicy).Check(req), qt.IsNil)
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/juju/zaputil/zapctx"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+)
+
+// A ControllerMetricsScraper scrapes the metrics of the controllers
+// managed by JIMM.
+type ControllerMetricsScraper interface {
+	ScrapeControllerMetrics(ctx context.Context, prefixes []string) ([]*dto.MetricFamily, error)
+}
+
+// ControllerMetricsHandler is a handler that re-exports selected metrics
+// from every controller managed by JIMM, labelled with the controller
+// they came from, so that a single Prometheus target covers the health
+// of the whole fleet.
+type ControllerMetricsHandler struct {
+	Router   *chi.Mux
+	scraper  ControllerMetricsScraper
+	prefixes []string
+}
+
+// NewControllerMetricsHandler creates a handler exporting the controller
+// metrics whose names start with one of the given prefixes.
+func NewControllerMetricsHandler(scraper ControllerMetricsScraper, prefixes []string) *ControllerMetricsHandler {
+	return &ControllerMetricsHandler{
+		Router:   chi.NewRouter(),
+		scraper:  scraper,
+		prefixes: prefixes,
+	}
+}
+
+// Routes returns the routes for the controller metrics handler.
+func (cmh *ControllerMetricsHandler) Routes() chi.Router {
+	cmh.SetupMiddleware()
+	cmh.Router.Get("/", cmh.Metrics)
+	return cmh.Router
+}
+
+// SetupMiddleware implements JIMMHttpHandler, the controller metrics
+// handler needs no middleware.
+func (cmh *ControllerMetricsHandler) SetupMiddleware() {}
+
+// Metrics scrapes the controllers and writes their metrics in the
+// Prometheus text exposition format.
+func (cmh *ControllerMetricsHandler) Metrics(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	mfs, err := cmh.scraper.ScrapeControllerMetrics(ctx, cmh.prefixes)
+	if err != nil {
+		writeError(ctx, w, http.StatusInternalServerError, err, "failed to scrape controller metrics")
+		return
+	}
+	format := expfmt.NewFormat(expfmt.TypeTextPlain)
+	w.Header().Set("Content-Type", string(format))
+	enc := expfmt.NewEncoder(w, format)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			zapctx.Error(ctx, "failed to encode controller metrics", zap.String("metric", mf.GetName()), zap.Error(err))
+			return
+		}
+	}
+}
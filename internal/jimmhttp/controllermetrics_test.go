@@ -0,0 +1,66 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/canonical/jimm/v3/internal/jimmhttp"
+)
+
+type scraperFunc func(ctx context.Context, prefixes []string) ([]*dto.MetricFamily, error)
+
+func (f scraperFunc) ScrapeControllerMetrics(ctx context.Context, prefixes []string) ([]*dto.MetricFamily, error) {
+	return f(ctx, prefixes)
+}
+
+func TestControllerMetricsHandler(t *testing.T) {
+	c := qt.New(t)
+
+	name, help := "juju_apiserver_connections", "Number of active API connections."
+	label, controller := "controller", "controller-1"
+	value := 7.0
+	scraper := scraperFunc(func(_ context.Context, prefixes []string) ([]*dto.MetricFamily, error) {
+		c.Check(prefixes, qt.DeepEquals, []string{"juju_apiserver_"})
+		return []*dto.MetricFamily{{
+			Name: &name,
+			Help: &help,
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{
+				Label: []*dto.LabelPair{{Name: &label, Value: &controller}},
+				Gauge: &dto.Gauge{Value: &value},
+			}},
+		}}, nil
+	})
+	h := jimmhttp.NewControllerMetricsHandler(scraper, []string{"juju_apiserver_"})
+
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+	c.Check(rr.Header().Get("Content-Type"), qt.Matches, `text/plain; version=0.0.4.*`)
+	c.Check(rr.Body.String(), qt.Equals, `# HELP juju_apiserver_connections Number of active API connections.
+# TYPE juju_apiserver_connections gauge
+juju_apiserver_connections{controller="controller-1"} 7
+`)
+}
+
+func TestControllerMetricsHandlerError(t *testing.T) {
+	c := qt.New(t)
+
+	scraper := scraperFunc(func(context.Context, []string) ([]*dto.MetricFamily, error) {
+		return nil, errors.New("database unavailable")
+	})
+	h := jimmhttp.NewControllerMetricsHandler(scraper, []string{"juju_"})
+
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusInternalServerError)
+	c.Check(rr.Body.String(), qt.Equals, "Internal Server Error - database unavailable")
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// An OperationGetter gets the progress of long-running operations.
+type OperationGetter interface {
+	LookupOperation(ctx context.Context, id string) (apiparams.Operation, error)
+}
+
+// OperationHandler is a handler that serves the progress of long-running
+// operations so that clients may poll them. Operations are identified by
+// an unguessable ID which is given only to the user that started the
+// operation, so no further authentication is required.
+type OperationHandler struct {
+	Router *chi.Mux
+	getter OperationGetter
+}
+
+// NewOperationHandler creates a handler serving the operations returned
+// by the given getter.
+func NewOperationHandler(getter OperationGetter) *OperationHandler {
+	return &OperationHandler{
+		Router: chi.NewRouter(),
+		getter: getter,
+	}
+}
+
+// Routes returns the routes for the operation handler.
+func (oh *OperationHandler) Routes() chi.Router {
+	oh.SetupMiddleware()
+	oh.Router.Get("/{id}", oh.Get)
+	return oh.Router
+}
+
+// SetupMiddleware implements JIMMHttpHandler, the operation handler needs
+// no middleware.
+func (oh *OperationHandler) SetupMiddleware() {}
+
+// Get writes the operation identified by the ID in the path as JSON.
+func (oh *OperationHandler) Get(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	o, err := oh.getter.LookupOperation(ctx, chi.URLParam(req, "id"))
+	if err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		zapctx.Error(ctx, "failed to get operation", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		zapctx.Error(ctx, "failed to marshal operation", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		zapctx.Error(ctx, "failed to write operation", zap.Error(err))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimmhttp"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type operationGetterFunc func(ctx context.Context, id string) (apiparams.Operation, error)
+
+func (f operationGetterFunc) LookupOperation(ctx context.Context, id string) (apiparams.Operation, error) {
+	return f(ctx, id)
+}
+
+func TestOperationHandler(t *testing.T) {
+	c := qt.New(t)
+
+	h := jimmhttp.NewOperationHandler(operationGetterFunc(func(_ context.Context, id string) (apiparams.Operation, error) {
+		switch id {
+		case "op-1":
+			return apiparams.Operation{
+				ID:        "op-1",
+				Kind:      "rotate-cloud-credentials",
+				StartedBy: "alice@canonical.com",
+				Status:    "running",
+				Progress:  50,
+				Steps: []apiparams.OperationStep{{
+					Message: "rotated cloudcred-test-cloud_alice@canonical.com_cred-1",
+				}},
+			}, nil
+		case "op-2":
+			return apiparams.Operation{}, errors.E("database unavailable")
+		default:
+			return apiparams.Operation{}, errors.E(errors.CodeNotFound, "operation not found")
+		}
+	}))
+	r := h.Routes()
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/op-1", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+	c.Check(rr.Header().Get("Content-Type"), qt.Equals, "application/json")
+	var o apiparams.Operation
+	err := json.Unmarshal(rr.Body.Bytes(), &o)
+	c.Assert(err, qt.IsNil)
+	c.Check(o.ID, qt.Equals, "op-1")
+	c.Check(o.Status, qt.Equals, "running")
+	c.Check(o.Progress, qt.Equals, 50)
+	c.Check(o.Steps, qt.HasLen, 1)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/op-2", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusInternalServerError)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/op-3", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusNotFound)
+}
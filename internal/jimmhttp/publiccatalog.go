@@ -0,0 +1,72 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A PublicCatalogLister lists the models and application offers that
+// have been opted in to the public catalog.
+type PublicCatalogLister interface {
+	PublicCatalog(ctx context.Context) (apiparams.PublicCatalog, error)
+}
+
+// PublicCatalogHandler is a handler that serves the public catalog to
+// unauthenticated clients, allowing internal service catalogs to
+// discover the listed models and offers without a JAAS account.
+type PublicCatalogHandler struct {
+	Router *chi.Mux
+	lister PublicCatalogLister
+}
+
+// NewPublicCatalogHandler creates a handler serving the catalog returned
+// by the given lister.
+func NewPublicCatalogHandler(lister PublicCatalogLister) *PublicCatalogHandler {
+	return &PublicCatalogHandler{
+		Router: chi.NewRouter(),
+		lister: lister,
+	}
+}
+
+// Routes returns the routes for the public catalog handler.
+func (pch *PublicCatalogHandler) Routes() chi.Router {
+	pch.SetupMiddleware()
+	pch.Router.Get("/", pch.Catalog)
+	return pch.Router
+}
+
+// SetupMiddleware implements JIMMHttpHandler, the public catalog handler
+// needs no middleware.
+func (pch *PublicCatalogHandler) SetupMiddleware() {}
+
+// Catalog writes the public catalog as JSON. The details of any error are
+// logged rather than returned, as the client is not authenticated.
+func (pch *PublicCatalogHandler) Catalog(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	catalog, err := pch.lister.PublicCatalog(ctx)
+	if err != nil {
+		zapctx.Error(ctx, "failed to list public catalog", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	b, err := json.Marshal(catalog)
+	if err != nil {
+		zapctx.Error(ctx, "failed to marshal public catalog", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		zapctx.Error(ctx, "failed to write public catalog", zap.Error(err))
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/jimmhttp"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+type catalogListerFunc func(ctx context.Context) (apiparams.PublicCatalog, error)
+
+func (f catalogListerFunc) PublicCatalog(ctx context.Context) (apiparams.PublicCatalog, error) {
+	return f(ctx)
+}
+
+func TestPublicCatalogHandler(t *testing.T) {
+	c := qt.New(t)
+
+	h := jimmhttp.NewPublicCatalogHandler(catalogListerFunc(func(context.Context) (apiparams.PublicCatalog, error) {
+		return apiparams.PublicCatalog{
+			Models: []apiparams.PublicModel{{
+				Name:       "model-1",
+				Purpose:    "shared databases",
+				OwnerGroup: "data-platform",
+			}},
+			Offers: []apiparams.PublicOffer{{
+				Name:       "db",
+				Interfaces: []string{"postgresql_client"},
+			}},
+		}, nil
+	}))
+
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+	c.Check(rr.Header().Get("Content-Type"), qt.Equals, "application/json")
+	c.Check(rr.Body.String(), qt.JSONEquals, map[string]any{
+		"models": []any{map[string]any{
+			"name":        "model-1",
+			"purpose":     "shared databases",
+			"owner-group": "data-platform",
+		}},
+		"offers": []any{map[string]any{
+			"name":       "db",
+			"interfaces": []any{"postgresql_client"},
+		}},
+	})
+}
+
+func TestPublicCatalogHandlerError(t *testing.T) {
+	c := qt.New(t)
+
+	h := jimmhttp.NewPublicCatalogHandler(catalogListerFunc(func(context.Context) (apiparams.PublicCatalog, error) {
+		return apiparams.PublicCatalog{}, errors.New("database unavailable")
+	}))
+
+	rr := httptest.NewRecorder()
+	h.Routes().ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusInternalServerError)
+	c.Check(rr.Body.String(), qt.Equals, "Internal Server Error\n")
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A ResultDownloadGetter gets the oversized results stored for
+// out-of-band download.
+type ResultDownloadGetter interface {
+	GetResultDownload(ctx context.Context, token string) (*dbmodel.ResultDownload, error)
+}
+
+// ResultDownloadHandler is a handler that serves the results of
+// expensive calls that were too large to return inline. Results are
+// identified by an unguessable token which is given only to the user
+// that made the call, so no further authentication is required.
+type ResultDownloadHandler struct {
+	Router *chi.Mux
+	getter ResultDownloadGetter
+}
+
+// NewResultDownloadHandler creates a handler serving the results
+// returned by the given getter.
+func NewResultDownloadHandler(getter ResultDownloadGetter) *ResultDownloadHandler {
+	return &ResultDownloadHandler{
+		Router: chi.NewRouter(),
+		getter: getter,
+	}
+}
+
+// Routes returns the routes for the result download handler.
+func (rdh *ResultDownloadHandler) Routes() chi.Router {
+	rdh.SetupMiddleware()
+	rdh.Router.Get("/{token}", rdh.Download)
+	return rdh.Router
+}
+
+// SetupMiddleware implements JIMMHttpHandler, the result download
+// handler needs no middleware.
+func (rdh *ResultDownloadHandler) SetupMiddleware() {}
+
+// Download writes the result identified by the token in the path as an
+// attachment.
+func (rdh *ResultDownloadHandler) Download(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	rd, err := rdh.getter.GetResultDownload(ctx, chi.URLParam(req, "token"))
+	if err != nil {
+		if errors.ErrorCode(err) == errors.CodeNotFound {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+		zapctx.Error(ctx, "failed to get result download", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", rd.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(rd.Body)))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": rd.Name}))
+	if _, err := w.Write(rd.Body); err != nil {
+		zapctx.Error(ctx, "failed to write result download", zap.Error(err))
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical.
+
+package jimmhttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimmhttp"
+)
+
+type resultDownloadGetterFunc func(ctx context.Context, token string) (*dbmodel.ResultDownload, error)
+
+func (f resultDownloadGetterFunc) GetResultDownload(ctx context.Context, token string) (*dbmodel.ResultDownload, error) {
+	return f(ctx, token)
+}
+
+func TestResultDownloadHandler(t *testing.T) {
+	c := qt.New(t)
+
+	h := jimmhttp.NewResultDownloadHandler(resultDownloadGetterFunc(func(_ context.Context, token string) (*dbmodel.ResultDownload, error) {
+		switch token {
+		case "token-1":
+			return &dbmodel.ResultDownload{
+				Name:        "model-dump.yaml",
+				ContentType: "application/yaml",
+				Body:        []byte("name: model-1\n"),
+			}, nil
+		case "token-2":
+			return nil, errors.E("database unavailable")
+		default:
+			return nil, errors.E(errors.CodeNotFound, "result download not found")
+		}
+	}))
+	r := h.Routes()
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/token-1", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+	c.Check(rr.Header().Get("Content-Type"), qt.Equals, "application/yaml")
+	c.Check(rr.Header().Get("Content-Disposition"), qt.Equals, "attachment; filename=model-dump.yaml")
+	c.Check(rr.Body.String(), qt.Equals, "name: model-1\n")
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/token-2", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusInternalServerError)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/token-3", nil))
+	c.Check(rr.Code, qt.Equals, http.StatusNotFound)
+}
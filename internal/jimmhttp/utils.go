@@ -15,6 +15,24 @@ import (
 
 type contextPathKey string
 
+type contextQueryKey struct{}
+
+type contextHeaderKey struct{}
+
+// QueryFromContext returns the query parameters of the request that
+// started a websocket connection served by a WSHandler.
+func QueryFromContext(ctx context.Context) url.Values {
+	v, _ := ctx.Value(contextQueryKey{}).(url.Values)
+	return v
+}
+
+// HeaderFromContext returns the header of the request that started a
+// websocket connection served by a WSHandler.
+func HeaderFromContext(ctx context.Context) http.Header {
+	v, _ := ctx.Value(contextHeaderKey{}).(http.Header)
+	return v
+}
+
 // PathElementFromContext returns the value of the path element previously
 // extracted in a StripPathElement handler.
 func PathElementFromContext(ctx context.Context, key string) string {
@@ -22,6 +22,10 @@ type WSHandler struct {
 	// connection.
 	Upgrader websocket.Upgrader
 
+	// Policy restricts the clients that may connect. If this is nil all
+	// clients may connect.
+	Policy *ConnectionPolicy
+
 	// Server is the websocket server that will handle the websocket
 	// connection.
 	Server WSServer
@@ -38,6 +42,11 @@ func (h *WSHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if err := h.Policy.Check(req); err != nil {
+		h.reject(ctx, w, req, err)
+		return
+	}
+
 	ctx, authErr := h.Server.Authenticate(ctx, w, req)
 	if authErr != nil {
 		zapctx.Error(ctx, "authentication error", zap.Error(authErr))
@@ -50,6 +59,8 @@ func (h *WSHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 
 	ctx = context.WithValue(ctx, contextPathKey("path"), req.URL.EscapedPath())
+	ctx = context.WithValue(ctx, contextQueryKey{}, req.URL.Query())
+	ctx = context.WithValue(ctx, contextHeaderKey{}, req.Header)
 	conn, err := h.Upgrader.Upgrade(w, req, nil)
 	if err != nil {
 		// If the upgrader returns an error it will have written an
@@ -71,6 +82,38 @@ func (h *WSHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	h.Server.ServeWS(ctx, conn)
 }
 
+// reject upgrades the connection and immediately closes it with a close
+// reason describing the policy violation, so that clients can report why
+// they could not connect.
+func (h *WSHandler) reject(ctx context.Context, w http.ResponseWriter, req *http.Request, err error) {
+	var reason string
+	if v, ok := err.(*PolicyViolation); ok {
+		reason = v.Reason
+	}
+	zapctx.Warn(ctx, "websocket connection rejected by policy",
+		zap.Error(err),
+		zap.String("remote-addr", req.RemoteAddr),
+		zap.String("user-agent", req.UserAgent()),
+	)
+	servermon.RejectedWebsocketConnectionsCount.WithLabelValues(reason).Inc()
+
+	// The connection is closed before any messages are read, so the
+	// origin check can safely be skipped in order to send the reason to
+	// browser clients.
+	upgrader := h.Upgrader
+	upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	conn, uerr := upgrader.Upgrade(w, req, nil)
+	if uerr != nil {
+		zapctx.Error(ctx, "cannot upgrade websocket", zap.Error(uerr))
+		return
+	}
+	defer conn.Close()
+	data := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, err.Error())
+	if err := conn.WriteControl(websocket.CloseMessage, data, time.Now().Add(time.Second)); err != nil {
+		zapctx.Error(ctx, "cannot write close message", zap.Error(err))
+	}
+}
+
 func writeInternalServerErrorClosure(ctx context.Context, conn *websocket.Conn, err any) {
 	data := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, fmt.Sprintf("%v", err))
 	if err := conn.WriteControl(websocket.CloseMessage, data, time.Time{}); err != nil {
@@ -86,6 +86,30 @@ func TestWSHandlerPanic(t *testing.T) {
 	c.Assert(err, qt.ErrorMatches, `websocket: close 1011 \(internal server error\): test`)
 }
 
+func TestWSHandlerPolicyViolation(t *testing.T) {
+	c := qt.New(t)
+
+	hnd := &jimmhttp.WSHandler{
+		Policy: &jimmhttp.ConnectionPolicy{
+			AllowedOrigins: []string{"https://dashboard.example.com"},
+		},
+		Server: echoServer{t: c},
+	}
+
+	srv := httptest.NewServer(hnd)
+	c.Cleanup(srv.Close)
+
+	var d websocket.Dialer
+	conn, resp, err := d.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), http.Header{
+		"Origin": []string{"https://evil.example.com"},
+	})
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+
+	_, _, err = conn.ReadMessage()
+	c.Assert(err, qt.ErrorMatches, `websocket: close 1008 \(policy violation\): connections from origin https://evil.example.com are not allowed`)
+}
+
 type panicServer struct{}
 
 func (s panicServer) Authenticate(ctx context.Context, w http.ResponseWriter, req *http.Request) (context.Context, error) {
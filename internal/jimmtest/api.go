@@ -122,6 +122,7 @@ type API struct {
 	base.APICaller
 
 	AddCloud_                          func(context.Context, names.CloudTag, jujuparams.Cloud, bool) error
+	AddUser_                           func(context.Context, string, string) error
 	AllModelWatcherNext_               func(context.Context, string) ([]jujuparams.Delta, error)
 	AllModelWatcherStop_               func(context.Context, string) error
 	ChangeModelCredential_             func(context.Context, names.ModelTag, names.CloudCredentialTag) error
@@ -130,21 +131,27 @@ type API struct {
 	Cloud_                             func(context.Context, names.CloudTag, *jujuparams.Cloud) error
 	CloudInfo_                         func(context.Context, names.CloudTag, *jujuparams.CloudInfo) error
 	Clouds_                            func(context.Context) (map[names.CloudTag]jujuparams.Cloud, error)
+	ControllerCall_                    func(context.Context, string, int, string, interface{}, interface{}) error
+	ControllerConfig_                  func(context.Context) (map[string]interface{}, error)
 	ControllerModelSummary_            func(context.Context, *jujuparams.ModelSummary) error
 	CreateModel_                       func(context.Context, *jujuparams.ModelCreateArgs, *jujuparams.ModelInfo) error
+	Credential_                        func(context.Context, names.CloudCredentialTag) (jujuparams.CloudCredential, error)
 	DestroyApplicationOffer_           func(context.Context, string, bool) error
 	DestroyModel_                      func(context.Context, names.ModelTag, *bool, *bool, *time.Duration, *time.Duration) error
 	DumpModel_                         func(context.Context, names.ModelTag, bool) (string, error)
 	DumpModelDB_                       func(context.Context, names.ModelTag) (map[string]interface{}, error)
+	ExportBundle_                      func(context.Context, bool) (string, error)
 	FindApplicationOffers_             func(context.Context, []jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	GetApplicationOffer_               func(context.Context, *jujuparams.ApplicationOfferAdminDetailsV5) error
 	GetApplicationOfferConsumeDetails_ func(context.Context, names.UserTag, *jujuparams.ConsumeOfferDetails, bakery.Version) error
 	GrantApplicationOfferAccess_       func(context.Context, string, names.UserTag, jujuparams.OfferAccessPermission) error
 	GrantCloudAccess_                  func(context.Context, names.CloudTag, names.UserTag, string) error
+	GrantControllerAccess_             func(context.Context, names.UserTag, string) error
 	GrantJIMMModelAdmin_               func(context.Context, names.ModelTag) error
 	GrantModelAccess_                  func(context.Context, names.ModelTag, names.UserTag, jujuparams.UserAccessPermission) error
 	IsBroken_                          bool
 	ListApplicationOffers_             func(context.Context, []jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
+	MigrationTargetPrechecks_          func(context.Context, jujuparams.MigrationModelInfo) error
 	ModelInfo_                         func(context.Context, *jujuparams.ModelInfo) error
 	ModelStatus_                       func(context.Context, *jujuparams.ModelStatus) error
 	ModelSummaryWatcherNext_           func(context.Context, string) ([]jujuparams.ModelAbstract, error)
@@ -158,11 +165,16 @@ type API struct {
 	RevokeCloudAccess_                 func(context.Context, names.CloudTag, names.UserTag, string) error
 	RevokeCredential_                  func(context.Context, names.CloudCredentialTag) error
 	RevokeModelAccess_                 func(context.Context, names.ModelTag, names.UserTag, jujuparams.UserAccessPermission) error
+	SetModelConfig_                    func(context.Context, map[string]interface{}) error
+	SetUserPassword_                   func(context.Context, string, string) error
+	SupportedFacadeVersions_           map[string][]int
 	SupportsCheckCredentialModels_     bool
 	SupportsModelSummaryWatcher_       bool
 	Status_                            func(context.Context, []string) (*jujuparams.FullStatus, error)
+	UnsetModelConfig_                  func(context.Context, []string) error
 	UpdateCloud_                       func(context.Context, names.CloudTag, jujuparams.Cloud) error
 	UpdateCredential_                  func(context.Context, jujuparams.TaggedCredential) ([]jujuparams.UpdateCredentialModelResult, error)
+	UserInfo_                          func(context.Context) ([]jujuparams.UserInfo, error)
 	ValidateModelUpgrade_              func(context.Context, names.ModelTag, bool) error
 	WatchAll_                          func(context.Context) (string, error)
 	WatchAllModelSummaries_            func(context.Context) (string, error)
@@ -179,6 +191,13 @@ func (a *API) AddCloud(ctx context.Context, tag names.CloudTag, cld jujuparams.C
 	return a.AddCloud_(ctx, tag, cld, force)
 }
 
+func (a *API) AddUser(ctx context.Context, username, password string) error {
+	if a.AddUser_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.AddUser_(ctx, username, password)
+}
+
 func (a *API) AllModelWatcherNext(ctx context.Context, id string) ([]jujuparams.Delta, error) {
 	if a.AllModelWatcherNext_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -228,6 +247,20 @@ func (a *API) Clouds(ctx context.Context) (map[names.CloudTag]jujuparams.Cloud,
 	return a.Clouds_(ctx)
 }
 
+func (a *API) ControllerCall(ctx context.Context, facade string, version int, method string, args, resp interface{}) error {
+	if a.ControllerCall_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.ControllerCall_(ctx, facade, version, method, args, resp)
+}
+
+func (a *API) ControllerConfig(ctx context.Context) (map[string]interface{}, error) {
+	if a.ControllerConfig_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return a.ControllerConfig_(ctx)
+}
+
 func (a *API) ControllerModelSummary(ctx context.Context, ms *jujuparams.ModelSummary) error {
 	if a.ControllerModelSummary_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -242,6 +275,13 @@ func (a *API) CreateModel(ctx context.Context, args *jujuparams.ModelCreateArgs,
 	return a.CreateModel_(ctx, args, mi)
 }
 
+func (a *API) Credential(ctx context.Context, tag names.CloudCredentialTag) (jujuparams.CloudCredential, error) {
+	if a.Credential_ == nil {
+		return jujuparams.CloudCredential{}, errors.E(errors.CodeNotImplemented)
+	}
+	return a.Credential_(ctx, tag)
+}
+
 func (a *API) DestroyApplicationOffer(ctx context.Context, offerURL string, force bool) error {
 	if a.DestroyApplicationOffer_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -270,6 +310,13 @@ func (a *API) DumpModelDB(ctx context.Context, mt names.ModelTag) (map[string]in
 	return a.DumpModelDB_(ctx, mt)
 }
 
+func (a *API) ExportBundle(ctx context.Context, includeCharmDefaults bool) (string, error) {
+	if a.ExportBundle_ == nil {
+		return "", errors.E(errors.CodeNotImplemented)
+	}
+	return a.ExportBundle_(ctx, includeCharmDefaults)
+}
+
 func (a *API) FindApplicationOffers(ctx context.Context, f []jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
 	if a.FindApplicationOffers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -305,6 +352,13 @@ func (a *API) GrantCloudAccess(ctx context.Context, ct names.CloudTag, ut names.
 	return a.GrantCloudAccess_(ctx, ct, ut, access)
 }
 
+func (a *API) GrantControllerAccess(ctx context.Context, ut names.UserTag, access string) error {
+	if a.GrantControllerAccess_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.GrantControllerAccess_(ctx, ut, access)
+}
+
 func (a *API) GrantJIMMModelAdmin(ctx context.Context, tag names.ModelTag) error {
 	if a.GrantJIMMModelAdmin_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -330,6 +384,13 @@ func (a *API) ListApplicationOffers(ctx context.Context, f []jujuparams.OfferFil
 	return a.ListApplicationOffers_(ctx, f)
 }
 
+func (a *API) MigrationTargetPrechecks(ctx context.Context, info jujuparams.MigrationModelInfo) error {
+	if a.MigrationTargetPrechecks_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.MigrationTargetPrechecks_(ctx, info)
+}
+
 func (a *API) ModelInfo(ctx context.Context, mi *jujuparams.ModelInfo) error {
 	if a.ModelInfo_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -407,6 +468,24 @@ func (a *API) RevokeModelAccess(ctx context.Context, mt names.ModelTag, ut names
 	return a.RevokeModelAccess_(ctx, mt, ut, p)
 }
 
+func (a *API) SetModelConfig(ctx context.Context, config map[string]interface{}) error {
+	if a.SetModelConfig_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.SetModelConfig_(ctx, config)
+}
+
+func (a *API) SetUserPassword(ctx context.Context, username, password string) error {
+	if a.SetUserPassword_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.SetUserPassword_(ctx, username, password)
+}
+
+func (a *API) SupportedFacadeVersions() map[string][]int {
+	return a.SupportedFacadeVersions_
+}
+
 func (a *API) SupportsCheckCredentialModels() bool {
 	return a.SupportsCheckCredentialModels_
 }
@@ -422,6 +501,13 @@ func (a *API) Status(ctx context.Context, patterns []string) (*jujuparams.FullSt
 	return a.Status_(ctx, patterns)
 }
 
+func (a *API) UnsetModelConfig(ctx context.Context, keys []string) error {
+	if a.UnsetModelConfig_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return a.UnsetModelConfig_(ctx, keys)
+}
+
 func (a *API) UpdateCloud(ctx context.Context, tag names.CloudTag, cloud jujuparams.Cloud) error {
 	if a.UpdateCloud_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -436,6 +522,13 @@ func (a *API) UpdateCredential(ctx context.Context, cred jujuparams.TaggedCreden
 	return a.UpdateCredential_(ctx, cred)
 }
 
+func (a *API) UserInfo(ctx context.Context) ([]jujuparams.UserInfo, error) {
+	if a.UserInfo_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return a.UserInfo_(ctx)
+}
+
 func (a *API) ValidateModelUpgrade(ctx context.Context, tag names.ModelTag, force bool) error {
 	if a.ValidateModelUpgrade_ == nil {
 		return errors.E(errors.CodeNotImplemented)
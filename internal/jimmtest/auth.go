@@ -179,9 +179,11 @@ func (m *mockOAuthAuthenticator) VerifyClientCredentials(ctx context.Context, cl
 // while integration tests must provide the same secret used when verifying JWTs.
 func newSessionToken(c SimpleTester, username string, signatureSecret string) string {
 	email := ConvertUsernameToEmail(username)
+	now := time.Now()
 	token, err := jwt.NewBuilder().
 		Subject(email).
-		Expiration(time.Now().Add(1 * time.Hour)).
+		IssuedAt(now).
+		Expiration(now.Add(1 * time.Hour)).
 		Build()
 	if err != nil {
 		c.Fatalf("failed to generate test session token")
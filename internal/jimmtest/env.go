@@ -394,6 +394,7 @@ type Controller struct {
 	AgentVersion  string                          `json:"agent-version"`
 	AdminUser     string                          `json:"admin-user"`
 	AdminPassword string                          `json:"admin-password"`
+	Tiers         []string                        `json:"tiers"`
 
 	env *Environment
 	dbo dbmodel.Controller
@@ -410,6 +411,7 @@ func (ctl *Controller) DBObject(c Tester, db db.Database) dbmodel.Controller {
 	ctl.dbo.AdminPassword = ctl.AdminPassword
 	ctl.dbo.CloudName = ctl.Cloud
 	ctl.dbo.CloudRegion = ctl.CloudRegion
+	ctl.dbo.Tiers = ctl.Tiers
 	ctl.dbo.CloudRegions = make([]dbmodel.CloudRegionControllerPriority, len(ctl.CloudRegions))
 	for i, cr := range ctl.CloudRegions {
 		cl := ctl.env.Cloud(cr.Cloud).DBObject(c, db)
@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical.
+
+package jimmtest
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/canonical/jimm/v3/internal/faults"
+)
+
+// A FaultTester is a Tester that can skip tests.
+type FaultTester interface {
+	Tester
+	Skip(args ...interface{})
+}
+
+// RequireFaults skips the test unless the tests were built with fault
+// injection enabled, using the "faults" build tag.
+func RequireFaults(c FaultTester) {
+	if !faults.Enabled {
+		c.Skip("fault injection not enabled, build with -tags faults")
+	}
+}
+
+// InjectFault injects the given fault for the duration of the test. All
+// injected faults are cleared when the test finishes. As injected faults
+// are global, tests injecting faults must not run in parallel.
+func InjectFault(c FaultTester, f faults.Fault) {
+	RequireFaults(c)
+	if err := faults.Inject(f); err != nil {
+		c.Fatalf("cannot inject fault: %s", err)
+	}
+	c.Cleanup(faults.Clear)
+}
+
+// RegisterFaultCallbacks registers the callbacks that inject database
+// write faults with the given test database.
+func RegisterFaultCallbacks(c Tester, db *gorm.DB) {
+	if err := faults.RegisterDatabaseCallbacks(db); err != nil {
+		c.Fatalf("cannot register fault callbacks: %s", err)
+	}
+}
@@ -4,6 +4,7 @@ package jimmtest
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
@@ -15,12 +16,14 @@ import (
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	jimmcreds "github.com/canonical/jimm/v3/internal/jimm/credentials"
 	"github.com/canonical/jimm/v3/internal/jimmtest/mocks"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
@@ -37,13 +40,31 @@ type JIMM struct {
 	AddAuditLogEntry_                  func(ale *dbmodel.AuditLogEntry)
 	AddCloudToController_              func(ctx context.Context, user *openfga.User, controllerName string, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddHostedCloud_                    func(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
+	AddModelACLTemplate_               func(ctx context.Context, user *openfga.User, ownerGroup, grantGroup, access string) (apiparams.ModelACLTemplate, error)
+	AddNamespaceReservation_           func(ctx context.Context, user *openfga.User, prefix, groupName string) error
 	AddServiceAccount_                 func(ctx context.Context, u *openfga.User, clientId string) error
+	AddServiceAccountToGroups_         func(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error
+	AgentVersionReport_                func(ctx context.Context, user *openfga.User) (apiparams.AgentVersionReport, error)
+	ApplyModelAccess_                  func(ctx context.Context, user *openfga.User, req apiparams.ApplyModelAccessRequest) (apiparams.ApplyModelAccessResponse, error)
+	ApproveAccessRequest_              func(ctx context.Context, user *openfga.User, id uint, comment string) error
+	ArchiveAndDestroyModel_            func(ctx context.Context, user *openfga.User, mt names.ModelTag) (string, error)
+	AuditControllerAccess_             func(ctx context.Context, user *openfga.User, controllerName string) (apiparams.AuditControllerAccessResponse, error)
 	Authenticate_                      func(ctx context.Context, req *jujuparams.LoginRequest) (*openfga.User, error)
+	CancelModelCreation_               func(ctx context.Context, user *openfga.User, path string) error
+	CancelModelDestroy_                func(ctx context.Context, user *openfga.User, mt names.ModelTag) error
 	CheckPermission_                   func(ctx context.Context, user *openfga.User, cachedPerms map[string]string, desiredPerms map[string]interface{}) (map[string]string, error)
+	ConfirmIdentity_                   func(ctx context.Context, user *openfga.User, req apiparams.ConfirmIdentityRequest) (time.Time, error)
+	ControllerCall_                    func(ctx context.Context, user *openfga.User, controllerName, facade string, version int, method string, args json.RawMessage) (json.RawMessage, error)
 	CopyServiceAccountCredential_      func(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error)
+	DefaultCloud_                      func(ctx context.Context, user *openfga.User) (dbmodel.DomainDefaultCloud, error)
+	DenyAccessRequest_                 func(ctx context.Context, user *openfga.User, id uint, comment string) error
 	DestroyOffer_                      func(ctx context.Context, user *openfga.User, offerURL string, force bool) error
+	DryRunAddController_               func(ctx context.Context, user *openfga.User, ctl *dbmodel.Controller) error
+	ExportModelAccess_                 func(ctx context.Context, user *openfga.User, req apiparams.ExportModelAccessRequest) (apiparams.ModelAccessDocument, error)
+	ExportModelBundle_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag, opts jimm.BundleExportOptions) (string, error)
 	FindApplicationOffers_             func(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	FindAuditEvents_                   func(ctx context.Context, user *openfga.User, filter db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error)
+	FindOffers_                        func(ctx context.Context, user *openfga.User, req apiparams.FindOffersRequest) ([]apiparams.OfferDirectoryEntry, error)
 	ForEachCloud_                      func(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error
 	ForEachUserCloud_                  func(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error
 	ForEachUserCloudCredential_        func(ctx context.Context, u *dbmodel.Identity, ct names.CloudTag, f func(cred *dbmodel.CloudCredential) error) error
@@ -53,39 +74,138 @@ type JIMM struct {
 	GetCloudCredential_                func(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag) (*dbmodel.CloudCredential, error)
 	GetCloudCredentialAttributes_      func(ctx context.Context, u *openfga.User, cred *dbmodel.CloudCredential, hidden bool) (attrs map[string]string, redacted []string, err error)
 	GetCredentialStore_                func() jimmcreds.CredentialStore
+	GetDeletedModel_                   func(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.DeletedModel, error)
 	GetJimmControllerAccess_           func(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error)
 	FetchIdentity_                     func(ctx context.Context, username string) (*openfga.User, error)
 	CountIdentities_                   func(ctx context.Context, user *openfga.User) (int, error)
+	CreateModelToken_                  func(ctx context.Context, user *openfga.User, mt names.ModelTag, access, description string) (apiparams.ModelToken, string, error)
+	CrossModelRelationGraph_           func(ctx context.Context, user *openfga.User, modelTag names.ModelTag) (apiparams.CrossModelRelationGraph, error)
+	EndIdentitySession_                func(ctx context.Context, s *dbmodel.IdentitySession) error
+	EnrolTOTP_                         func(ctx context.Context, user *openfga.User, replace bool) (apiparams.EnrolTOTPResponse, error)
+	FreezeModel_                       func(ctx context.Context, user *openfga.User, mt names.ModelTag, until time.Time, reason string) (apiparams.ModelFreeze, error)
+	GetModelSummary_                   func(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelSummary, error)
+	ListDeletedModels_                 func(ctx context.Context, user *openfga.User) ([]apiparams.DeletedModel, error)
+	ModelControllerEndpoints_          func(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelControllerEndpoints, error)
+	RestoreModel_                      func(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	RotateCloudCredentials_            func(ctx context.Context, user *openfga.User, req apiparams.RotateCloudCredentialsRequest) (apiparams.RotateCloudCredentialsResponse, error)
+	SetModelNetworkPolicy_             func(ctx context.Context, user *openfga.User, mt names.ModelTag, allowedEgress []string) (apiparams.ModelNetworkPolicy, error)
+	RemoveModelNetworkPolicy_          func(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	AddModelDependency_                func(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag, description string) (apiparams.ModelDependency, error)
+	RemoveModelDependency_             func(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag) error
+	ModelDependencies_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelDependencies, error)
+	ListModelMigrations_               func(ctx context.Context, user *openfga.User, mt names.ModelTag, activeOnly bool) ([]apiparams.ModelMigration, error)
+	ListModelNetworkPolicies_          func(ctx context.Context, user *openfga.User, unenforcedOnly bool) ([]apiparams.ModelNetworkPolicy, error)
 	ListIdentities_                    func(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination) ([]openfga.User, error)
+	ListLeaders_                       func(ctx context.Context, user *openfga.User) ([]apiparams.LeaderInfo, error)
 	GetUserCloudAccess_                func(ctx context.Context, user *openfga.User, cloud names.CloudTag) (string, error)
 	GetUserControllerAccess_           func(ctx context.Context, user *openfga.User, controller names.ControllerTag) (string, error)
 	GetUserModelAccess_                func(ctx context.Context, user *openfga.User, model names.ModelTag) (string, error)
+	GroupSyncStatus_                   func(ctx context.Context, user *openfga.User) (apiparams.GroupSyncStatusResponse, error)
 	GrantAuditLogAccess_               func(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	GrantCloudAccess_                  func(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
+	GrantCloudCredentialAccess_        func(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error
 	GrantModelAccess_                  func(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
+	GrantModelGroupAccess_             func(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error
 	GrantOfferAccess_                  func(ctx context.Context, u *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) error
 	GrantServiceAccountAccess_         func(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, entities []string) error
 	InitiateMigration_                 func(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error)
 	InitiateInternalMigration_         func(ctx context.Context, user *openfga.User, modelTag names.ModelTag, targetController string) (jujuparams.InitiateMigrationResult, error)
+	ListAccessRequests_                func(ctx context.Context, user *openfga.User, status string) ([]apiparams.AccessRequest, error)
 	ListApplicationOffers_             func(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
+	ListIdleModels_                    func(ctx context.Context, user *openfga.User, idleDays int) ([]apiparams.IdleModel, error)
+	ListControllerCertificates_        func(ctx context.Context, user *openfga.User) (apiparams.ListControllerCertificatesResponse, error)
+	ListControllerModelCredentials_    func(ctx context.Context, user *openfga.User) (apiparams.ListControllerModelCredentialsResponse, error)
+	ListModelACLTemplates_             func(ctx context.Context, user *openfga.User) ([]apiparams.ModelACLTemplate, error)
+	ListIdentitySessions_              func(ctx context.Context, user *openfga.User, name string) (apiparams.ListIdentitySessionsResponse, error)
+	ListIdleModels_                    func(ctx context.Context, user *openfga.User, idleDays int) ([]apiparams.IdleModel, error)
+	ListModelTokens_                   func(ctx context.Context, user *openfga.User, mt names.ModelTag) ([]apiparams.ModelToken, error)
+	ListNamespaceReservations_         func(ctx context.Context, user *openfga.User) ([]apiparams.NamespaceReservation, error)
+	ListOutdatedCharms_                func(ctx context.Context, user *openfga.User) ([]apiparams.OutdatedCharm, error)
+	ListCredentialPropagations_        func(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, unconfirmed bool) ([]apiparams.CredentialPropagation, error)
+	ListDomainDefaultClouds_           func(ctx context.Context, user *openfga.User) ([]apiparams.DomainDefaultCloud, error)
+	ListPendingModelDestroys_          func(ctx context.Context, user *openfga.User) ([]apiparams.PendingModelDestroy, error)
+	ListPlacementLatencies_            func(ctx context.Context, user *openfga.User) (apiparams.PlacementLatencies, error)
 	ListResources_                     func(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string) ([]db.Resource, error)
+	ListScheduledJobs_                 func(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error)
+	MigrationPrechecks_                func(ctx context.Context, user *openfga.User, mt names.ModelTag, targetController string) (apiparams.MigrationPrecheckReport, error)
+	ModelsStatus_                      func(ctx context.Context, user *openfga.User, controllerName string) (*apiparams.ModelsStatusResponse, error)
+	ModelMigrationStatus_              func(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelMigrationStatus, error)
+	ModelTimeline_                     func(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, limit int) (apiparams.ModelTimeline, error)
+	ModelResourceHistory_              func(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, resolution string) (apiparams.ModelResourceHistory, error)
+	NormalizeIdentities_               func(ctx context.Context, user *openfga.User, dryRun bool) ([]apiparams.IdentityRename, error)
 	Offer_                             func(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error
+	OfferConsumption_                  func(ctx context.Context, user *openfga.User, offerURL string) (apiparams.OfferConsumption, error)
+	PingControllers_                   func(ctx context.Context, user *openfga.User) (apiparams.PingControllersResponse, error)
 	PubSubHub_                         func() *pubsub.Hub
 	PurgeLogs_                         func(ctx context.Context, user *openfga.User, before time.Time) (int64, error)
+	RecordIdentitySessionActivity_     func(ctx context.Context, s *dbmodel.IdentitySession, t time.Time) error
+	RebalanceRecommendations_          func(ctx context.Context, user *openfga.User, limit int) (apiparams.RebalanceReport, error)
+	RebindModelCredentials_            func(ctx context.Context, user *openfga.User, departing names.UserTag, groupName string, dryRun bool) ([]apiparams.ModelCredentialRebind, error)
+	ReloadTunables_                    func(ctx context.Context, user *openfga.User) ([]apiparams.TunableChange, error)
+	PurgeIdentity_                     func(ctx context.Context, user *openfga.User, name string, deleteAuditLog bool) (apiparams.PurgeIdentityResponse, error)
 	RemoveCloud_                       func(ctx context.Context, u *openfga.User, ct names.CloudTag) error
 	RemoveCloudFromController_         func(ctx context.Context, u *openfga.User, controllerName string, ct names.CloudTag) error
+	RemoveDomainDefaultCloud_          func(ctx context.Context, user *openfga.User, domain string) error
+	RemapCloudRegion_                  func(ctx context.Context, user *openfga.User, req apiparams.RemapCloudRegionRequest) (apiparams.RemapCloudRegionResponse, error)
+	RotateWebhookSecret_               func(ctx context.Context, user *openfga.User, webhook string) (apiparams.WebhookSecret, error)
+	ListWebhookDeliveries_             func(ctx context.Context, user *openfga.User, webhook string, limit int) ([]apiparams.WebhookDelivery, error)
+	ExportModel_                       func(ctx context.Context, user *openfga.User, modelRef string) (apiparams.ModelExport, error)
+	PrepareModelImport_                func(ctx context.Context, user *openfga.User, req apiparams.PrepareModelImportRequest) (apiparams.ModelImport, error)
+	MigrateModelExternal_              func(ctx context.Context, user *openfga.User, req apiparams.MigrateModelExternalRequest) (apiparams.MigrateModelExternalResponse, error)
+	CompleteModelImport_               func(ctx context.Context, user *openfga.User, id uint) (apiparams.ModelImport, error)
+	RepairDenormalizedFields_          func(ctx context.Context, user *openfga.User, req apiparams.RepairDenormalizedFieldsRequest) (apiparams.RepairDenormalizedFieldsResponse, error)
+	ProvisionControllerServiceUser_    func(ctx context.Context, user *openfga.User, controllerName string) (apiparams.ProvisionControllerServiceUserResponse, error)
+	GetOperation_                      func(ctx context.Context, user *openfga.User, id string) (apiparams.Operation, error)
+	ListOperations_                    func(ctx context.Context, user *openfga.User, all bool) ([]apiparams.Operation, error)
+	RemoveModelACLTemplate_            func(ctx context.Context, user *openfga.User, id uint) error
+	RemoveModelPublicListing_          func(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	RemoveNamespaceReservation_        func(ctx context.Context, user *openfga.User, prefix string) error
+	RemoveOfferPublicListing_          func(ctx context.Context, user *openfga.User, offerURL string) error
+	RemoveUserQuota_                   func(ctx context.Context, user *openfga.User, target names.UserTag) error
+	RemoveServiceAccountFromGroups_    func(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error
+	RequestAccess_                     func(ctx context.Context, user *openfga.User, target, access, reason string) (apiparams.AccessRequest, error)
+	ResolveModel_                      func(ctx context.Context, user *openfga.User, ref string) (names.ModelTag, error)
 	ResourceTag_                       func() names.ControllerTag
+	RotateControllerModelCredential_   func(ctx context.Context, user *openfga.User, req apiparams.RotateControllerModelCredentialRequest) (apiparams.ControllerModelCredential, error)
+	ScheduledJobHistory_               func(ctx context.Context, user *openfga.User, name string, limit int) ([]apiparams.ScheduledJobRun, error)
+	ResyncModelAccess_                 func(ctx context.Context, user *openfga.User, req apiparams.ResyncModelAccessRequest) (apiparams.ResyncModelAccessResponse, error)
 	RevokeAuditLogAccess_              func(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	RevokeCloudAccess_                 func(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
 	RevokeCloudCredential_             func(ctx context.Context, user *dbmodel.Identity, tag names.CloudCredentialTag, force bool) error
+	RevokeCloudCredentialAccess_       func(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error
 	RevokeModelAccess_                 func(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
+	RevokeModelGroupAccess_            func(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error
+	RevokeModelToken_                  func(ctx context.Context, user *openfga.User, mt names.ModelTag, id uint) error
+	UnfreezeModel_                     func(ctx context.Context, user *openfga.User, mt names.ModelTag) error
 	RevokeOfferAccess_                 func(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error)
+	SearchApplications_                func(ctx context.Context, user *openfga.User, req apiparams.SearchApplicationsRequest) ([]apiparams.ApplicationSearchResult, error)
 	SetIdentityModelDefaults_          func(ctx context.Context, user *dbmodel.Identity, configs map[string]interface{}) error
+	SetCostCenter_                     func(ctx context.Context, user *openfga.User, entity, costCenter string) error
+	SetDefaultCloudCredential_         func(ctx context.Context, user *openfga.User, cloudTag names.CloudTag, credentialTag names.CloudCredentialTag) error
+	SetDomainDefaultCloud_             func(ctx context.Context, user *openfga.User, domain, cloud, region string) error
+	SetModelPublicListing_             func(ctx context.Context, user *openfga.User, mt names.ModelTag, purpose, ownerGroup string) error
+	SetOfferConsumerLimit_             func(ctx context.Context, user *openfga.User, offerURL string, limit int) error
+	SetOfferPublicListing_             func(ctx context.Context, user *openfga.User, offerURL, purpose, ownerGroup string) error
+	SetScheduledJobEnabled_            func(ctx context.Context, user *openfga.User, name string, enabled bool) error
+	SetUserQuota_                      func(ctx context.Context, user *openfga.User, target names.UserTag, req apiparams.SetUserQuotaRequest) error
+	StartIdentitySession_              func(ctx context.Context, user *openfga.User, remoteAddress string) (*dbmodel.IdentitySession, error)
+	StoreOversizedResult_              func(ctx context.Context, user *openfga.User, name, contentType string, data []byte) (string, error)
+	SyncGroups_                        func(ctx context.Context, user *openfga.User, req apiparams.SyncGroupsRequest) (apiparams.SyncGroupsResponse, error)
 	ToJAASTag_                         func(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error)
+	TransferNamespaceReservation_      func(ctx context.Context, user *openfga.User, prefix, groupName string) error
 	UpdateApplicationOffer_            func(ctx context.Context, controller *dbmodel.Controller, offerUUID string, removed bool) error
 	UpdateCloud_                       func(ctx context.Context, u *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error
 	UpdateCloudCredential_             func(ctx context.Context, u *openfga.User, args jimm.UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error)
 	UserLogin_                         func(ctx context.Context, identityName string) (*openfga.User, error)
+	UsageReport_                       func(ctx context.Context, user *openfga.User) (apiparams.UsageReport, error)
+	UserQuota_                         func(ctx context.Context, user *openfga.User, target names.UserTag) (apiparams.UserQuota, error)
+	Whoami_                            func(ctx context.Context, user *openfga.User) (apiparams.IdentitySummary, error)
+	InjectFault_                       func(ctx context.Context, user *openfga.User, f faults.Fault) error
+	ClearFaults_                       func(ctx context.Context, user *openfga.User) error
+	ListFaults_                        func(ctx context.Context, user *openfga.User) ([]faults.Fault, error)
+	AddModelPool_                      func(ctx context.Context, user *openfga.User, name string, cloud names.CloudTag, region string, credential names.CloudCredentialTag, size int, config map[string]interface{}) (apiparams.ModelPool, error)
+	RemoveModelPool_                   func(ctx context.Context, user *openfga.User, name string) error
+	ListModelPools_                    func(ctx context.Context, user *openfga.User) ([]apiparams.ModelPool, error)
 }
 
 func (j *JIMM) AddAuditLogEntry(ale *dbmodel.AuditLogEntry) {
@@ -107,6 +227,20 @@ func (j *JIMM) AddHostedCloud(ctx context.Context, user *openfga.User, tag names
 	return j.AddHostedCloud_(ctx, user, tag, cloud, force)
 }
 
+func (j *JIMM) AddModelACLTemplate(ctx context.Context, user *openfga.User, ownerGroup, grantGroup, access string) (apiparams.ModelACLTemplate, error) {
+	if j.AddModelACLTemplate_ == nil {
+		return apiparams.ModelACLTemplate{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddModelACLTemplate_(ctx, user, ownerGroup, grantGroup, access)
+}
+
+func (j *JIMM) AddNamespaceReservation(ctx context.Context, user *openfga.User, prefix, groupName string) error {
+	if j.AddNamespaceReservation_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddNamespaceReservation_(ctx, user, prefix, groupName)
+}
+
 func (j *JIMM) AddServiceAccount(ctx context.Context, u *openfga.User, clientId string) error {
 	if j.AddServiceAccount_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -114,6 +248,60 @@ func (j *JIMM) AddServiceAccount(ctx context.Context, u *openfga.User, clientId
 	return j.AddServiceAccount_(ctx, u, clientId)
 }
 
+func (j *JIMM) AddServiceAccountToGroups(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error {
+	if j.AddServiceAccountToGroups_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddServiceAccountToGroups_(ctx, u, svcAccTag, groups)
+}
+
+func (j *JIMM) AgentVersionReport(ctx context.Context, user *openfga.User) (apiparams.AgentVersionReport, error) {
+	if j.AgentVersionReport_ == nil {
+		return apiparams.AgentVersionReport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AgentVersionReport_(ctx, user)
+}
+
+func (j *JIMM) ApplyModelAccess(ctx context.Context, user *openfga.User, req apiparams.ApplyModelAccessRequest) (apiparams.ApplyModelAccessResponse, error) {
+	if j.ApplyModelAccess_ == nil {
+		return apiparams.ApplyModelAccessResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ApplyModelAccess_(ctx, user, req)
+}
+
+func (j *JIMM) ApproveAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) error {
+	if j.ApproveAccessRequest_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.ApproveAccessRequest_(ctx, user, id, comment)
+}
+
+func (j *JIMM) ArchiveAndDestroyModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (string, error) {
+	if j.ArchiveAndDestroyModel_ == nil {
+		return "", errors.E(errors.CodeNotImplemented)
+	}
+	return j.ArchiveAndDestroyModel_(ctx, user, mt)
+}
+
+func (j *JIMM) AuditControllerAccess(ctx context.Context, user *openfga.User, controllerName string) (apiparams.AuditControllerAccessResponse, error) {
+	if j.AuditControllerAccess_ == nil {
+		return apiparams.AuditControllerAccessResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AuditControllerAccess_(ctx, user, controllerName)
+}
+func (j *JIMM) ConfirmIdentity(ctx context.Context, user *openfga.User, req apiparams.ConfirmIdentityRequest) (time.Time, error) {
+	if j.ConfirmIdentity_ == nil {
+		return time.Time{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ConfirmIdentity_(ctx, user, req)
+}
+
+func (j *JIMM) ControllerCall(ctx context.Context, user *openfga.User, controllerName, facade string, version int, method string, args json.RawMessage) (json.RawMessage, error) {
+	if j.ControllerCall_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ControllerCall_(ctx, user, controllerName, facade, version, method, args)
+}
 func (j *JIMM) CopyServiceAccountCredential(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error) {
 	if j.CopyServiceAccountCredential_ == nil {
 		return names.CloudCredentialTag{}, nil, errors.E(errors.CodeNotImplemented)
@@ -128,18 +316,58 @@ func (j *JIMM) Authenticate(ctx context.Context, req *jujuparams.LoginRequest) (
 	return j.Authenticate_(ctx, req)
 }
 
+func (j *JIMM) CancelModelCreation(ctx context.Context, user *openfga.User, path string) error {
+	if j.CancelModelCreation_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.CancelModelCreation_(ctx, user, path)
+}
+
+func (j *JIMM) CancelModelDestroy(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	if j.CancelModelDestroy_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.CancelModelDestroy_(ctx, user, mt)
+}
+
 func (j *JIMM) CheckPermission(ctx context.Context, user *openfga.User, cachedPerms map[string]string, desiredPerms map[string]interface{}) (map[string]string, error) {
 	if j.CheckPermission_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
 	}
 	return j.CheckPermission_(ctx, user, cachedPerms, desiredPerms)
 }
+func (j *JIMM) DenyAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) error {
+	if j.DenyAccessRequest_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.DenyAccessRequest_(ctx, user, id, comment)
+}
 func (j *JIMM) DestroyOffer(ctx context.Context, user *openfga.User, offerURL string, force bool) error {
 	if j.DestroyOffer_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
 	return j.DestroyOffer_(ctx, user, offerURL, force)
 }
+func (j *JIMM) DryRunAddController(ctx context.Context, user *openfga.User, ctl *dbmodel.Controller) error {
+	if j.DryRunAddController_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.DryRunAddController_(ctx, user, ctl)
+}
+
+func (j *JIMM) ExportModelAccess(ctx context.Context, user *openfga.User, req apiparams.ExportModelAccessRequest) (apiparams.ModelAccessDocument, error) {
+	if j.ExportModelAccess_ == nil {
+		return apiparams.ModelAccessDocument{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ExportModelAccess_(ctx, user, req)
+}
+
+func (j *JIMM) ExportModelBundle(ctx context.Context, user *openfga.User, mt names.ModelTag, opts jimm.BundleExportOptions) (string, error) {
+	if j.ExportModelBundle_ == nil {
+		return "", errors.E(errors.CodeNotImplemented)
+	}
+	return j.ExportModelBundle_(ctx, user, mt, opts)
+}
 func (j *JIMM) FindApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
 	if j.FindApplicationOffers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -152,6 +380,13 @@ func (j *JIMM) FindAuditEvents(ctx context.Context, user *openfga.User, filter d
 	}
 	return j.FindAuditEvents_(ctx, user, filter)
 }
+
+func (j *JIMM) FindOffers(ctx context.Context, user *openfga.User, req apiparams.FindOffersRequest) ([]apiparams.OfferDirectoryEntry, error) {
+	if j.FindOffers_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.FindOffers_(ctx, user, req)
+}
 func (j *JIMM) ForEachCloud(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error {
 	if j.ForEachCloud_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -209,12 +444,24 @@ func (j *JIMM) GetCredentialStore() jimmcreds.CredentialStore {
 	}
 	return j.GetCredentialStore_()
 }
+func (j *JIMM) GetDeletedModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.DeletedModel, error) {
+	if j.GetDeletedModel_ == nil {
+		return apiparams.DeletedModel{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetDeletedModel_(ctx, user, mt)
+}
 func (j *JIMM) GetJimmControllerAccess(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error) {
 	if j.GetJimmControllerAccess_ == nil {
 		return "", errors.E(errors.CodeNotImplemented)
 	}
 	return j.GetJimmControllerAccess_(ctx, user, tag)
 }
+func (j *JIMM) GetModelSummary(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelSummary, error) {
+	if j.GetModelSummary_ == nil {
+		return apiparams.ModelSummary{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetModelSummary_(ctx, user, mt)
+}
 func (j *JIMM) FetchIdentity(ctx context.Context, username string) (*openfga.User, error) {
 	if j.FetchIdentity_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -227,12 +474,176 @@ func (j *JIMM) CountIdentities(ctx context.Context, user *openfga.User) (int, er
 	}
 	return j.CountIdentities_(ctx, user)
 }
+func (j *JIMM) CreateModelToken(ctx context.Context, user *openfga.User, mt names.ModelTag, access, description string) (apiparams.ModelToken, string, error) {
+	if j.CreateModelToken_ == nil {
+		return apiparams.ModelToken{}, "", errors.E(errors.CodeNotImplemented)
+	}
+	return j.CreateModelToken_(ctx, user, mt, access, description)
+}
+func (j *JIMM) EnrolTOTP(ctx context.Context, user *openfga.User, replace bool) (apiparams.EnrolTOTPResponse, error) {
+	if j.EnrolTOTP_ == nil {
+		return apiparams.EnrolTOTPResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.EnrolTOTP_(ctx, user, replace)
+}
+func (j *JIMM) FreezeModel(ctx context.Context, user *openfga.User, mt names.ModelTag, until time.Time, reason string) (apiparams.ModelFreeze, error) {
+	if j.FreezeModel_ == nil {
+		return apiparams.ModelFreeze{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.FreezeModel_(ctx, user, mt, until, reason)
+}
+func (j *JIMM) ListDeletedModels(ctx context.Context, user *openfga.User) ([]apiparams.DeletedModel, error) {
+	if j.ListDeletedModels_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListDeletedModels_(ctx, user)
+}
+func (j *JIMM) RestoreModel(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	if j.RestoreModel_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RestoreModel_(ctx, user, mt)
+}
+func (j *JIMM) SetModelNetworkPolicy(ctx context.Context, user *openfga.User, mt names.ModelTag, allowedEgress []string) (apiparams.ModelNetworkPolicy, error) {
+	if j.SetModelNetworkPolicy_ == nil {
+		return apiparams.ModelNetworkPolicy{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelNetworkPolicy_(ctx, user, mt, allowedEgress)
+}
+func (j *JIMM) RemoveModelNetworkPolicy(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	if j.RemoveModelNetworkPolicy_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelNetworkPolicy_(ctx, user, mt)
+}
+func (j *JIMM) AddModelDependency(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag, description string) (apiparams.ModelDependency, error) {
+	if j.AddModelDependency_ == nil {
+		return apiparams.ModelDependency{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddModelDependency_(ctx, user, mt, dependsOn, description)
+}
+func (j *JIMM) RemoveModelDependency(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag) error {
+	if j.RemoveModelDependency_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelDependency_(ctx, user, mt, dependsOn)
+}
+func (j *JIMM) ModelDependencies(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelDependencies, error) {
+	if j.ModelDependencies_ == nil {
+		return apiparams.ModelDependencies{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelDependencies_(ctx, user, mt)
+}
+func (j *JIMM) ListModelMigrations(ctx context.Context, user *openfga.User, mt names.ModelTag, activeOnly bool) ([]apiparams.ModelMigration, error) {
+	if j.ListModelMigrations_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelMigrations_(ctx, user, mt, activeOnly)
+}
+
+func (j *JIMM) ListModelNetworkPolicies(ctx context.Context, user *openfga.User, unenforcedOnly bool) ([]apiparams.ModelNetworkPolicy, error) {
+	if j.ListModelNetworkPolicies_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelNetworkPolicies_(ctx, user, unenforcedOnly)
+}
+func (j *JIMM) CrossModelRelationGraph(ctx context.Context, user *openfga.User, modelTag names.ModelTag) (apiparams.CrossModelRelationGraph, error) {
+	if j.CrossModelRelationGraph_ == nil {
+		return apiparams.CrossModelRelationGraph{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.CrossModelRelationGraph_(ctx, user, modelTag)
+}
 func (j *JIMM) ListIdentities(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination) ([]openfga.User, error) {
 	if j.ListIdentities_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
 	}
 	return j.ListIdentities_(ctx, user, filter)
 }
+func (j *JIMM) ListLeaders(ctx context.Context, user *openfga.User) ([]apiparams.LeaderInfo, error) {
+	if j.ListLeaders_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListLeaders_(ctx, user)
+}
+func (j *JIMM) ListModelACLTemplates(ctx context.Context, user *openfga.User) ([]apiparams.ModelACLTemplate, error) {
+	if j.ListModelACLTemplates_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelACLTemplates_(ctx, user)
+}
+
+func (j *JIMM) ListModelTokens(ctx context.Context, user *openfga.User, mt names.ModelTag) ([]apiparams.ModelToken, error) {
+	if j.ListModelTokens_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelTokens_(ctx, user, mt)
+}
+func (j *JIMM) ListNamespaceReservations(ctx context.Context, user *openfga.User) ([]apiparams.NamespaceReservation, error) {
+	if j.ListNamespaceReservations_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListNamespaceReservations_(ctx, user)
+}
+func (j *JIMM) ListOutdatedCharms(ctx context.Context, user *openfga.User) ([]apiparams.OutdatedCharm, error) {
+	if j.ListOutdatedCharms_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListOutdatedCharms_(ctx, user)
+}
+func (j *JIMM) ListPendingModelDestroys(ctx context.Context, user *openfga.User) ([]apiparams.PendingModelDestroy, error) {
+	if j.ListPendingModelDestroys_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListPendingModelDestroys_(ctx, user)
+}
+func (j *JIMM) ListPlacementLatencies(ctx context.Context, user *openfga.User) (apiparams.PlacementLatencies, error) {
+	if j.ListPlacementLatencies_ == nil {
+		return apiparams.PlacementLatencies{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListPlacementLatencies_(ctx, user)
+}
+
+func (j *JIMM) DefaultCloud(ctx context.Context, user *openfga.User) (dbmodel.DomainDefaultCloud, error) {
+	if j.DefaultCloud_ == nil {
+		return dbmodel.DomainDefaultCloud{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.DefaultCloud_(ctx, user)
+}
+
+func (j *JIMM) SetDomainDefaultCloud(ctx context.Context, user *openfga.User, domain, cloud, region string) error {
+	if j.SetDomainDefaultCloud_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetDomainDefaultCloud_(ctx, user, domain, cloud, region)
+}
+
+func (j *JIMM) RemoveDomainDefaultCloud(ctx context.Context, user *openfga.User, domain string) error {
+	if j.RemoveDomainDefaultCloud_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveDomainDefaultCloud_(ctx, user, domain)
+}
+
+func (j *JIMM) ListCredentialPropagations(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, unconfirmed bool) ([]apiparams.CredentialPropagation, error) {
+	if j.ListCredentialPropagations_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListCredentialPropagations_(ctx, user, tag, unconfirmed)
+}
+
+func (j *JIMM) ListDomainDefaultClouds(ctx context.Context, user *openfga.User) ([]apiparams.DomainDefaultCloud, error) {
+	if j.ListDomainDefaultClouds_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListDomainDefaultClouds_(ctx, user)
+}
+
+func (j *JIMM) PurgeIdentity(ctx context.Context, user *openfga.User, name string, deleteAuditLog bool) (apiparams.PurgeIdentityResponse, error) {
+	if j.PurgeIdentity_ == nil {
+		return apiparams.PurgeIdentityResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.PurgeIdentity_(ctx, user, name, deleteAuditLog)
+}
 func (j *JIMM) GetUserCloudAccess(ctx context.Context, user *openfga.User, cloud names.CloudTag) (string, error) {
 	if j.GetUserCloudAccess_ == nil {
 		return "", errors.E(errors.CodeNotImplemented)
@@ -263,12 +674,24 @@ func (j *JIMM) GrantCloudAccess(ctx context.Context, user *openfga.User, ct name
 	}
 	return j.GrantCloudAccess_(ctx, user, ct, ut, access)
 }
+func (j *JIMM) GrantCloudCredentialAccess(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error {
+	if j.GrantCloudCredentialAccess_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.GrantCloudCredentialAccess_(ctx, user, tag, entity)
+}
 func (j *JIMM) GrantModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
 	if j.GrantModelAccess_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
 	return j.GrantModelAccess_(ctx, user, mt, ut, access)
 }
+func (j *JIMM) GrantModelGroupAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error {
+	if j.GrantModelGroupAccess_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.GrantModelGroupAccess_(ctx, user, mt, groupName, access)
+}
 func (j *JIMM) GrantOfferAccess(ctx context.Context, u *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) error {
 	if j.GrantOfferAccess_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -295,6 +718,12 @@ func (j *JIMM) InitiateInternalMigration(ctx context.Context, user *openfga.User
 	}
 	return j.InitiateInternalMigration_(ctx, user, modelTag, targetController)
 }
+func (j *JIMM) ListAccessRequests(ctx context.Context, user *openfga.User, status string) ([]apiparams.AccessRequest, error) {
+	if j.ListAccessRequests_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListAccessRequests_(ctx, user, status)
+}
 func (j *JIMM) ListApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error) {
 	if j.ListApplicationOffers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -307,12 +736,78 @@ func (j *JIMM) ListResources(ctx context.Context, user *openfga.User, filter pag
 	}
 	return j.ListResources_(ctx, user, filter, namePrefixFilter, typeFilter)
 }
+func (j *JIMM) ListScheduledJobs(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error) {
+	if j.ListScheduledJobs_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListScheduledJobs_(ctx, user)
+}
+func (j *JIMM) MigrationPrechecks(ctx context.Context, user *openfga.User, mt names.ModelTag, targetController string) (apiparams.MigrationPrecheckReport, error) {
+	if j.MigrationPrechecks_ == nil {
+		return apiparams.MigrationPrecheckReport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.MigrationPrechecks_(ctx, user, mt, targetController)
+}
+func (j *JIMM) ModelsStatus(ctx context.Context, user *openfga.User, controllerName string) (*apiparams.ModelsStatusResponse, error) {
+	if j.ModelsStatus_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelsStatus_(ctx, user, controllerName)
+}
+
+func (j *JIMM) ModelControllerEndpoints(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelControllerEndpoints, error) {
+	if j.ModelControllerEndpoints_ == nil {
+		return apiparams.ModelControllerEndpoints{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelControllerEndpoints_(ctx, user, mt)
+}
+
+func (j *JIMM) ModelMigrationStatus(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelMigrationStatus, error) {
+	if j.ModelMigrationStatus_ == nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelMigrationStatus_(ctx, user, mt)
+}
+
+func (j *JIMM) ModelTimeline(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, limit int) (apiparams.ModelTimeline, error) {
+	if j.ModelTimeline_ == nil {
+		return apiparams.ModelTimeline{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelTimeline_(ctx, user, mt, start, end, limit)
+}
+
+func (j *JIMM) ModelResourceHistory(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, resolution string) (apiparams.ModelResourceHistory, error) {
+	if j.ModelResourceHistory_ == nil {
+		return apiparams.ModelResourceHistory{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelResourceHistory_(ctx, user, mt, start, end, resolution)
+}
+func (j *JIMM) OfferConsumption(ctx context.Context, user *openfga.User, offerURL string) (apiparams.OfferConsumption, error) {
+	if j.OfferConsumption_ == nil {
+		return apiparams.OfferConsumption{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.OfferConsumption_(ctx, user, offerURL)
+}
+
+func (j *JIMM) NormalizeIdentities(ctx context.Context, user *openfga.User, dryRun bool) ([]apiparams.IdentityRename, error) {
+	if j.NormalizeIdentities_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.NormalizeIdentities_(ctx, user, dryRun)
+}
+
 func (j *JIMM) Offer(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error {
 	if j.Offer_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
 	return j.Offer_(ctx, user, offer)
 }
+func (j *JIMM) PingControllers(ctx context.Context, user *openfga.User) (apiparams.PingControllersResponse, error) {
+	if j.PingControllers_ == nil {
+		return apiparams.PingControllersResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.PingControllers_(ctx, user)
+}
 func (j *JIMM) PubSubHub() *pubsub.Hub {
 	if j.PubSubHub_ == nil {
 		panic("not implemented")
@@ -325,6 +820,27 @@ func (j *JIMM) PurgeLogs(ctx context.Context, user *openfga.User, before time.Ti
 	}
 	return j.PurgeLogs_(ctx, user, before)
 }
+func (j *JIMM) RebalanceRecommendations(ctx context.Context, user *openfga.User, limit int) (apiparams.RebalanceReport, error) {
+	if j.RebalanceRecommendations_ == nil {
+		return apiparams.RebalanceReport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RebalanceRecommendations_(ctx, user, limit)
+}
+
+func (j *JIMM) RebindModelCredentials(ctx context.Context, user *openfga.User, departing names.UserTag, groupName string, dryRun bool) ([]apiparams.ModelCredentialRebind, error) {
+	if j.RebindModelCredentials_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RebindModelCredentials_(ctx, user, departing, groupName, dryRun)
+}
+
+func (j *JIMM) ReloadTunables(ctx context.Context, user *openfga.User) ([]apiparams.TunableChange, error) {
+	if j.ReloadTunables_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ReloadTunables_(ctx, user)
+}
+
 func (j *JIMM) RemoveCloud(ctx context.Context, u *openfga.User, ct names.CloudTag) error {
 	if j.RemoveCloud_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -337,12 +853,43 @@ func (j *JIMM) RemoveCloudFromController(ctx context.Context, u *openfga.User, c
 	}
 	return j.RemoveCloudFromController_(ctx, u, controllerName, ct)
 }
+func (j *JIMM) RemoveUserQuota(ctx context.Context, user *openfga.User, target names.UserTag) error {
+	if j.RemoveUserQuota_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveUserQuota_(ctx, user, target)
+}
+
+func (j *JIMM) RemoveServiceAccountFromGroups(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error {
+	if j.RemoveServiceAccountFromGroups_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveServiceAccountFromGroups_(ctx, u, svcAccTag, groups)
+}
+func (j *JIMM) RequestAccess(ctx context.Context, user *openfga.User, target, access, reason string) (apiparams.AccessRequest, error) {
+	if j.RequestAccess_ == nil {
+		return apiparams.AccessRequest{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RequestAccess_(ctx, user, target, access, reason)
+}
+func (j *JIMM) ResolveModel(ctx context.Context, user *openfga.User, ref string) (names.ModelTag, error) {
+	if j.ResolveModel_ == nil {
+		return names.ModelTag{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ResolveModel_(ctx, user, ref)
+}
 func (j *JIMM) ResourceTag() names.ControllerTag {
 	if j.ResourceTag_ == nil {
 		return names.NewControllerTag(uuid.NewString())
 	}
 	return j.ResourceTag_()
 }
+func (j *JIMM) ResyncModelAccess(ctx context.Context, user *openfga.User, req apiparams.ResyncModelAccessRequest) (apiparams.ResyncModelAccessResponse, error) {
+	if j.ResyncModelAccess_ == nil {
+		return apiparams.ResyncModelAccessResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ResyncModelAccess_(ctx, user, req)
+}
 func (j *JIMM) RevokeAuditLogAccess(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error {
 	if j.RevokeAuditLogAccess_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -361,18 +908,48 @@ func (j *JIMM) RevokeCloudCredential(ctx context.Context, user *dbmodel.Identity
 	}
 	return j.RevokeCloudCredential_(ctx, user, tag, force)
 }
+func (j *JIMM) RevokeCloudCredentialAccess(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error {
+	if j.RevokeCloudCredentialAccess_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RevokeCloudCredentialAccess_(ctx, user, tag, entity)
+}
 func (j *JIMM) RevokeModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error {
 	if j.RevokeModelAccess_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
 	return j.RevokeModelAccess_(ctx, user, mt, ut, access)
 }
+func (j *JIMM) RevokeModelGroupAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error {
+	if j.RevokeModelGroupAccess_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RevokeModelGroupAccess_(ctx, user, mt, groupName, access)
+}
+func (j *JIMM) RevokeModelToken(ctx context.Context, user *openfga.User, mt names.ModelTag, id uint) error {
+	if j.RevokeModelToken_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RevokeModelToken_(ctx, user, mt, id)
+}
+func (j *JIMM) UnfreezeModel(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	if j.UnfreezeModel_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.UnfreezeModel_(ctx, user, mt)
+}
 func (j *JIMM) RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error) {
 	if j.RevokeOfferAccess_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
 	return j.RevokeOfferAccess_(ctx, user, offerURL, ut, access)
 }
+func (j *JIMM) SearchApplications(ctx context.Context, user *openfga.User, req apiparams.SearchApplicationsRequest) ([]apiparams.ApplicationSearchResult, error) {
+	if j.SearchApplications_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.SearchApplications_(ctx, user, req)
+}
 func (j *JIMM) SetIdentityModelDefaults(ctx context.Context, user *dbmodel.Identity, configs map[string]interface{}) error {
 	if j.SetIdentityModelDefaults_ == nil {
 		return errors.E(errors.CodeNotImplemented)
@@ -410,3 +987,293 @@ func (j *JIMM) UserLogin(ctx context.Context, identityName string) (*openfga.Use
 	}
 	return j.UserLogin_(ctx, identityName)
 }
+func (j *JIMM) UserQuota(ctx context.Context, user *openfga.User, target names.UserTag) (apiparams.UserQuota, error) {
+	if j.UserQuota_ == nil {
+		return apiparams.UserQuota{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.UserQuota_(ctx, user, target)
+}
+func (j *JIMM) RemapCloudRegion(ctx context.Context, user *openfga.User, req apiparams.RemapCloudRegionRequest) (apiparams.RemapCloudRegionResponse, error) {
+	if j.RemapCloudRegion_ == nil {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemapCloudRegion_(ctx, user, req)
+}
+func (j *JIMM) ExportModel(ctx context.Context, user *openfga.User, modelRef string) (apiparams.ModelExport, error) {
+	if j.ExportModel_ == nil {
+		return apiparams.ModelExport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ExportModel_(ctx, user, modelRef)
+}
+func (j *JIMM) PrepareModelImport(ctx context.Context, user *openfga.User, req apiparams.PrepareModelImportRequest) (apiparams.ModelImport, error) {
+	if j.PrepareModelImport_ == nil {
+		return apiparams.ModelImport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.PrepareModelImport_(ctx, user, req)
+}
+func (j *JIMM) MigrateModelExternal(ctx context.Context, user *openfga.User, req apiparams.MigrateModelExternalRequest) (apiparams.MigrateModelExternalResponse, error) {
+	if j.MigrateModelExternal_ == nil {
+		return apiparams.MigrateModelExternalResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.MigrateModelExternal_(ctx, user, req)
+}
+func (j *JIMM) CompleteModelImport(ctx context.Context, user *openfga.User, id uint) (apiparams.ModelImport, error) {
+	if j.CompleteModelImport_ == nil {
+		return apiparams.ModelImport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.CompleteModelImport_(ctx, user, id)
+}
+func (j *JIMM) RepairDenormalizedFields(ctx context.Context, user *openfga.User, req apiparams.RepairDenormalizedFieldsRequest) (apiparams.RepairDenormalizedFieldsResponse, error) {
+	if j.RepairDenormalizedFields_ == nil {
+		return apiparams.RepairDenormalizedFieldsResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RepairDenormalizedFields_(ctx, user, req)
+}
+func (j *JIMM) ProvisionControllerServiceUser(ctx context.Context, user *openfga.User, controllerName string) (apiparams.ProvisionControllerServiceUserResponse, error) {
+	if j.ProvisionControllerServiceUser_ == nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ProvisionControllerServiceUser_(ctx, user, controllerName)
+}
+func (j *JIMM) GetOperation(ctx context.Context, user *openfga.User, id string) (apiparams.Operation, error) {
+	if j.GetOperation_ == nil {
+		return apiparams.Operation{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetOperation_(ctx, user, id)
+}
+func (j *JIMM) ListOperations(ctx context.Context, user *openfga.User, all bool) ([]apiparams.Operation, error) {
+	if j.ListOperations_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListOperations_(ctx, user, all)
+}
+func (j *JIMM) RotateWebhookSecret(ctx context.Context, user *openfga.User, webhook string) (apiparams.WebhookSecret, error) {
+	if j.RotateWebhookSecret_ == nil {
+		return apiparams.WebhookSecret{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RotateWebhookSecret_(ctx, user, webhook)
+}
+func (j *JIMM) ListWebhookDeliveries(ctx context.Context, user *openfga.User, webhook string, limit int) ([]apiparams.WebhookDelivery, error) {
+	if j.ListWebhookDeliveries_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListWebhookDeliveries_(ctx, user, webhook, limit)
+}
+func (j *JIMM) RemoveModelACLTemplate(ctx context.Context, user *openfga.User, id uint) error {
+	if j.RemoveModelACLTemplate_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelACLTemplate_(ctx, user, id)
+}
+
+func (j *JIMM) RemoveModelPublicListing(ctx context.Context, user *openfga.User, mt names.ModelTag) error {
+	if j.RemoveModelPublicListing_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelPublicListing_(ctx, user, mt)
+}
+
+func (j *JIMM) RemoveNamespaceReservation(ctx context.Context, user *openfga.User, prefix string) error {
+	if j.RemoveNamespaceReservation_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveNamespaceReservation_(ctx, user, prefix)
+}
+
+func (j *JIMM) RemoveOfferPublicListing(ctx context.Context, user *openfga.User, offerURL string) error {
+	if j.RemoveOfferPublicListing_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveOfferPublicListing_(ctx, user, offerURL)
+}
+
+func (j *JIMM) TransferNamespaceReservation(ctx context.Context, user *openfga.User, prefix, groupName string) error {
+	if j.TransferNamespaceReservation_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.TransferNamespaceReservation_(ctx, user, prefix, groupName)
+}
+func (j *JIMM) GroupSyncStatus(ctx context.Context, user *openfga.User) (apiparams.GroupSyncStatusResponse, error) {
+	if j.GroupSyncStatus_ == nil {
+		return apiparams.GroupSyncStatusResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GroupSyncStatus_(ctx, user)
+}
+func (j *JIMM) StoreOversizedResult(ctx context.Context, user *openfga.User, name, contentType string, data []byte) (string, error) {
+	if j.StoreOversizedResult_ == nil {
+		return "", errors.E(errors.CodeNotImplemented)
+	}
+	return j.StoreOversizedResult_(ctx, user, name, contentType, data)
+}
+func (j *JIMM) SyncGroups(ctx context.Context, user *openfga.User, req apiparams.SyncGroupsRequest) (apiparams.SyncGroupsResponse, error) {
+	if j.SyncGroups_ == nil {
+		return apiparams.SyncGroupsResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.SyncGroups_(ctx, user, req)
+}
+func (j *JIMM) ListControllerCertificates(ctx context.Context, user *openfga.User) (apiparams.ListControllerCertificatesResponse, error) {
+	if j.ListControllerCertificates_ == nil {
+		return apiparams.ListControllerCertificatesResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListControllerCertificates_(ctx, user)
+}
+func (j *JIMM) ListControllerModelCredentials(ctx context.Context, user *openfga.User) (apiparams.ListControllerModelCredentialsResponse, error) {
+	if j.ListControllerModelCredentials_ == nil {
+		return apiparams.ListControllerModelCredentialsResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListControllerModelCredentials_(ctx, user)
+}
+func (j *JIMM) RotateCloudCredentials(ctx context.Context, user *openfga.User, req apiparams.RotateCloudCredentialsRequest) (apiparams.RotateCloudCredentialsResponse, error) {
+	if j.RotateCloudCredentials_ == nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RotateCloudCredentials_(ctx, user, req)
+}
+func (j *JIMM) RotateControllerModelCredential(ctx context.Context, user *openfga.User, req apiparams.RotateControllerModelCredentialRequest) (apiparams.ControllerModelCredential, error) {
+	if j.RotateControllerModelCredential_ == nil {
+		return apiparams.ControllerModelCredential{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.RotateControllerModelCredential_(ctx, user, req)
+}
+func (j *JIMM) ScheduledJobHistory(ctx context.Context, user *openfga.User, name string, limit int) ([]apiparams.ScheduledJobRun, error) {
+	if j.ScheduledJobHistory_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ScheduledJobHistory_(ctx, user, name, limit)
+}
+func (j *JIMM) SetUserQuota(ctx context.Context, user *openfga.User, target names.UserTag, req apiparams.SetUserQuotaRequest) error {
+	if j.SetUserQuota_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetUserQuota_(ctx, user, target, req)
+}
+
+func (j *JIMM) SetCostCenter(ctx context.Context, user *openfga.User, entity, costCenter string) error {
+	if j.SetCostCenter_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetCostCenter_(ctx, user, entity, costCenter)
+}
+func (j *JIMM) SetDefaultCloudCredential(ctx context.Context, user *openfga.User, cloudTag names.CloudTag, credentialTag names.CloudCredentialTag) error {
+	if j.SetDefaultCloudCredential_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetDefaultCloudCredential_(ctx, user, cloudTag, credentialTag)
+}
+
+func (j *JIMM) SetModelPublicListing(ctx context.Context, user *openfga.User, mt names.ModelTag, purpose, ownerGroup string) error {
+	if j.SetModelPublicListing_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetModelPublicListing_(ctx, user, mt, purpose, ownerGroup)
+}
+
+func (j *JIMM) SetOfferConsumerLimit(ctx context.Context, user *openfga.User, offerURL string, limit int) error {
+	if j.SetOfferConsumerLimit_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetOfferConsumerLimit_(ctx, user, offerURL, limit)
+}
+func (j *JIMM) SetOfferPublicListing(ctx context.Context, user *openfga.User, offerURL, purpose, ownerGroup string) error {
+	if j.SetOfferPublicListing_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetOfferPublicListing_(ctx, user, offerURL, purpose, ownerGroup)
+}
+
+func (j *JIMM) SetScheduledJobEnabled(ctx context.Context, user *openfga.User, name string, enabled bool) error {
+	if j.SetScheduledJobEnabled_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetScheduledJobEnabled_(ctx, user, name, enabled)
+}
+
+func (j *JIMM) UsageReport(ctx context.Context, user *openfga.User) (apiparams.UsageReport, error) {
+	if j.UsageReport_ == nil {
+		return apiparams.UsageReport{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.UsageReport_(ctx, user)
+}
+func (j *JIMM) Whoami(ctx context.Context, user *openfga.User) (apiparams.IdentitySummary, error) {
+	if j.Whoami_ == nil {
+		return apiparams.IdentitySummary{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.Whoami_(ctx, user)
+}
+
+func (j *JIMM) InjectFault(ctx context.Context, user *openfga.User, f faults.Fault) error {
+	if j.InjectFault_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.InjectFault_(ctx, user, f)
+}
+
+func (j *JIMM) ClearFaults(ctx context.Context, user *openfga.User) error {
+	if j.ClearFaults_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.ClearFaults_(ctx, user)
+}
+
+func (j *JIMM) ListFaults(ctx context.Context, user *openfga.User) ([]faults.Fault, error) {
+	if j.ListFaults_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListFaults_(ctx, user)
+}
+
+func (j *JIMM) EndIdentitySession(ctx context.Context, s *dbmodel.IdentitySession) error {
+	if j.EndIdentitySession_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.EndIdentitySession_(ctx, s)
+}
+
+func (j *JIMM) ListIdentitySessions(ctx context.Context, user *openfga.User, name string) (apiparams.ListIdentitySessionsResponse, error) {
+	if j.ListIdentitySessions_ == nil {
+		return apiparams.ListIdentitySessionsResponse{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListIdentitySessions_(ctx, user, name)
+}
+
+func (j *JIMM) ListIdleModels(ctx context.Context, user *openfga.User, idleDays int) ([]apiparams.IdleModel, error) {
+	if j.ListIdleModels_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListIdleModels_(ctx, user, idleDays)
+}
+
+func (j *JIMM) RecordIdentitySessionActivity(ctx context.Context, s *dbmodel.IdentitySession, t time.Time) error {
+	if j.RecordIdentitySessionActivity_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RecordIdentitySessionActivity_(ctx, s, t)
+}
+
+func (j *JIMM) StartIdentitySession(ctx context.Context, user *openfga.User, remoteAddress string) (*dbmodel.IdentitySession, error) {
+	if j.StartIdentitySession_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.StartIdentitySession_(ctx, user, remoteAddress)
+}
+
+func (j *JIMM) AddModelPool(ctx context.Context, user *openfga.User, name string, cloud names.CloudTag, region string, credential names.CloudCredentialTag, size int, config map[string]interface{}) (apiparams.ModelPool, error) {
+	if j.AddModelPool_ == nil {
+		return apiparams.ModelPool{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.AddModelPool_(ctx, user, name, cloud, region, credential, size, config)
+}
+
+func (j *JIMM) RemoveModelPool(ctx context.Context, user *openfga.User, name string) error {
+	if j.RemoveModelPool_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.RemoveModelPool_(ctx, user, name)
+}
+
+func (j *JIMM) ListModelPools(ctx context.Context, user *openfga.User) ([]apiparams.ModelPool, error) {
+	if j.ListModelPools_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ListModelPools_(ctx, user)
+}
@@ -9,19 +9,27 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // ControllerService is an implementation of the jujuapi.ControllerService interface.
 type ControllerService struct {
-	AddController_             func(ctx context.Context, u *openfga.User, ctl *dbmodel.Controller) error
-	ControllerInfo_            func(ctx context.Context, name string) (*dbmodel.Controller, error)
-	GetControllerConfig_       func(ctx context.Context, u *dbmodel.Identity) (*dbmodel.ControllerConfig, error)
-	EarliestControllerVersion_ func(ctx context.Context) (version.Number, error)
-	ListControllers_           func(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error)
-	RemoveController_          func(ctx context.Context, user *openfga.User, controllerName string, force bool) error
-	SetControllerConfig_       func(ctx context.Context, u *openfga.User, args jujuparams.ControllerConfigSet) error
-	SetControllerDeprecated_   func(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	AddController_                 func(ctx context.Context, u *openfga.User, ctl *dbmodel.Controller) error
+	ControllerHealth_              func(controllerName string) *apiparams.ControllerHealth
+	ControllerHealthStates_        func(ctx context.Context, user *openfga.User) ([]apiparams.ControllerHealthState, error)
+	ControllerInfo_                func(ctx context.Context, name string) (*dbmodel.Controller, error)
+	GetControllerConfig_           func(ctx context.Context, u *dbmodel.Identity) (*dbmodel.ControllerConfig, error)
+	GetControllerBootstrapProfile_ func(ctx context.Context, user *openfga.User, controllerName string, version int) (apiparams.ControllerBootstrapProfile, error)
+	EarliestControllerVersion_     func(ctx context.Context) (version.Number, error)
+	ListControllers_               func(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error)
+	RemoveController_              func(ctx context.Context, user *openfga.User, controllerName string, force bool) error
+	SetControllerConfig_           func(ctx context.Context, u *openfga.User, args jujuparams.ControllerConfigSet) error
+	SetControllerDeprecated_       func(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	SetControllerMaintenance_      func(ctx context.Context, user *openfga.User, controllerName string, maintenance bool) (int, error)
+	SetControllerTiers_            func(ctx context.Context, user *openfga.User, controllerName string, tiers []string) error
+	SetControllerTimeouts_         func(ctx context.Context, user *openfga.User, controllerName string, timeouts jimm.ControllerTimeouts) error
 }
 
 func (j *ControllerService) AddController(ctx context.Context, u *openfga.User, ctl *dbmodel.Controller) error {
@@ -31,6 +39,20 @@ func (j *ControllerService) AddController(ctx context.Context, u *openfga.User,
 	return j.AddController_(ctx, u, ctl)
 }
 
+func (j *ControllerService) ControllerHealth(controllerName string) *apiparams.ControllerHealth {
+	if j.ControllerHealth_ == nil {
+		return nil
+	}
+	return j.ControllerHealth_(controllerName)
+}
+
+func (j *ControllerService) ControllerHealthStates(ctx context.Context, user *openfga.User) ([]apiparams.ControllerHealthState, error) {
+	if j.ControllerHealthStates_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ControllerHealthStates_(ctx, user)
+}
+
 func (j *ControllerService) ControllerInfo(ctx context.Context, name string) (*dbmodel.Controller, error) {
 	if j.ControllerInfo_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -52,6 +74,13 @@ func (j *ControllerService) GetControllerConfig(ctx context.Context, u *dbmodel.
 	return j.GetControllerConfig_(ctx, u)
 }
 
+func (j *ControllerService) GetControllerBootstrapProfile(ctx context.Context, user *openfga.User, controllerName string, version int) (apiparams.ControllerBootstrapProfile, error) {
+	if j.GetControllerBootstrapProfile_ == nil {
+		return apiparams.ControllerBootstrapProfile{}, errors.E(errors.CodeNotImplemented)
+	}
+	return j.GetControllerBootstrapProfile_(ctx, user, controllerName, version)
+}
+
 func (j *ControllerService) ListControllers(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error) {
 	if j.ListControllers_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -79,3 +108,24 @@ func (j *ControllerService) SetControllerDeprecated(ctx context.Context, user *o
 	}
 	return j.SetControllerDeprecated_(ctx, user, controllerName, deprecated)
 }
+
+func (j *ControllerService) SetControllerMaintenance(ctx context.Context, user *openfga.User, controllerName string, maintenance bool) (int, error) {
+	if j.SetControllerMaintenance_ == nil {
+		return 0, errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetControllerMaintenance_(ctx, user, controllerName, maintenance)
+}
+
+func (j *ControllerService) SetControllerTiers(ctx context.Context, user *openfga.User, controllerName string, tiers []string) error {
+	if j.SetControllerTiers_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetControllerTiers_(ctx, user, controllerName, tiers)
+}
+
+func (j *ControllerService) SetControllerTimeouts(ctx context.Context, user *openfga.User, controllerName string, timeouts jimm.ControllerTimeouts) error {
+	if j.SetControllerTimeouts_ == nil {
+		return errors.E(errors.CodeNotImplemented)
+	}
+	return j.SetControllerTimeouts_(ctx, user, controllerName, timeouts)
+}
@@ -20,16 +20,18 @@ type ModelManager struct {
 	AddModel_               func(ctx context.Context, u *openfga.User, args *jimm.ModelCreateArgs) (*jujuparams.ModelInfo, error)
 	ChangeModelCredential_  func(ctx context.Context, user *openfga.User, modelTag names.ModelTag, cloudCredentialTag names.CloudCredentialTag) error
 	DestroyModel_           func(ctx context.Context, u *openfga.User, mt names.ModelTag, destroyStorage *bool, force *bool, maxWait *time.Duration, timeout *time.Duration) error
+	DestroyModelImpact_     func(ctx context.Context, u *openfga.User, mt names.ModelTag, destroyStorage *bool) (*params.DestroyModelImpact, error)
 	DumpModel_              func(ctx context.Context, u *openfga.User, mt names.ModelTag, simplified bool) (string, error)
 	DumpModelDB_            func(ctx context.Context, u *openfga.User, mt names.ModelTag) (map[string]interface{}, error)
 	ForEachModel_           func(ctx context.Context, u *openfga.User, f func(*dbmodel.Model, jujuparams.UserAccessPermission) error) error
 	ForEachUserModel_       func(ctx context.Context, u *openfga.User, f func(*dbmodel.Model, jujuparams.UserAccessPermission) error) error
 	FullModelStatus_        func(ctx context.Context, user *openfga.User, modelTag names.ModelTag, patterns []string) (*jujuparams.FullStatus, error)
 	GetModel_               func(ctx context.Context, uuid string) (dbmodel.Model, error)
-	ImportModel_            func(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner string) error
+	ImportModel_            func(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner, origin string) error
 	IdentityModelDefaults_  func(ctx context.Context, user *dbmodel.Identity) (map[string]interface{}, error)
 	ModelDefaultsForCloud_  func(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag) (jujuparams.ModelDefaultsResult, error)
 	ModelInfo_              func(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelInfo, error)
+	ModelMachines_          func(ctx context.Context, u *openfga.User, mt names.ModelTag) ([]dbmodel.Machine, error)
 	ModelStatus_            func(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelStatus, error)
 	QueryModelsJq_          func(ctx context.Context, models []string, jqQuery string) (params.CrossModelQueryResponse, error)
 	SetModelDefaults_       func(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag, region string, configs map[string]interface{}) error
@@ -60,6 +62,13 @@ func (j *ModelManager) DestroyModel(ctx context.Context, u *openfga.User, mt nam
 	return j.DestroyModel_(ctx, u, mt, destroyStorage, force, maxWait, timeout)
 }
 
+func (j *ModelManager) DestroyModelImpact(ctx context.Context, u *openfga.User, mt names.ModelTag, destroyStorage *bool) (*params.DestroyModelImpact, error) {
+	if j.DestroyModelImpact_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.DestroyModelImpact_(ctx, u, mt, destroyStorage)
+}
+
 func (j *ModelManager) DumpModel(ctx context.Context, u *openfga.User, mt names.ModelTag, simplified bool) (string, error) {
 	if j.DumpModel_ == nil {
 		return "", errors.E(errors.CodeNotImplemented)
@@ -101,11 +110,11 @@ func (j *ModelManager) GetModel(ctx context.Context, uuid string) (dbmodel.Model
 	return j.GetModel_(ctx, uuid)
 }
 
-func (j *ModelManager) ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner string) error {
+func (j *ModelManager) ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner, origin string) error {
 	if j.ImportModel_ == nil {
 		return errors.E(errors.CodeNotImplemented)
 	}
-	return j.ImportModel_(ctx, user, controllerName, modelTag, newOwner)
+	return j.ImportModel_(ctx, user, controllerName, modelTag, newOwner, origin)
 }
 
 func (j *ModelManager) ModelDefaultsForCloud(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag) (jujuparams.ModelDefaultsResult, error) {
@@ -121,6 +130,13 @@ func (j *ModelManager) ModelInfo(ctx context.Context, u *openfga.User, mt names.
 	}
 	return j.ModelInfo_(ctx, u, mt)
 }
+func (j *ModelManager) ModelMachines(ctx context.Context, u *openfga.User, mt names.ModelTag) ([]dbmodel.Machine, error) {
+	if j.ModelMachines_ == nil {
+		return nil, errors.E(errors.CodeNotImplemented)
+	}
+	return j.ModelMachines_(ctx, u, mt)
+}
+
 func (j *ModelManager) ModelStatus(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelStatus, error) {
 	if j.ModelStatus_ == nil {
 		return nil, errors.E(errors.CodeNotImplemented)
@@ -0,0 +1,82 @@
+// Copyright 2024 Canonical.
+package jimmtest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/objectstore"
+)
+
+type object struct {
+	info objectstore.ObjectInfo
+	body []byte
+}
+
+// InMemoryObjectStore implements objectstore.Store, keeping the objects
+// in memory, for use in tests in place of an S3-compatible object store.
+type InMemoryObjectStore struct {
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+// Put implements objectstore.Store.
+func (s *InMemoryObjectStore) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error {
+	buf, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) != size {
+		return errors.E("object size mismatch")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.objects == nil {
+		s.objects = make(map[string]object)
+	}
+	s.objects[key] = object{
+		info: objectstore.ObjectInfo{
+			ContentType:  contentType,
+			Size:         size,
+			LastModified: time.Now(),
+		},
+		body: buf,
+	}
+	return nil
+}
+
+// Get implements objectstore.Store.
+func (s *InMemoryObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, objectstore.ObjectInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[key]
+	if !ok {
+		return nil, objectstore.ObjectInfo{}, errors.E(errors.CodeNotFound, "object not found")
+	}
+	return io.NopCloser(bytes.NewReader(obj.body)), obj.info, nil
+}
+
+// Delete implements objectstore.Store.
+func (s *InMemoryObjectStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+// Keys returns the keys of all the stored objects in order.
+func (s *InMemoryObjectStore) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
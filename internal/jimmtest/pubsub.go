@@ -0,0 +1,52 @@
+// Copyright 2024 Canonical.
+package jimmtest
+
+import (
+	"sync"
+)
+
+// A PublishedMessage is a message published to a Publisher.
+type PublishedMessage struct {
+	Model   string
+	Content interface{}
+}
+
+// Publisher implements the jimm.Publisher interface, recording the
+// published messages in memory, for use in tests in place of a
+// pubsub.Hub.
+type Publisher struct {
+	mu        sync.Mutex
+	published []PublishedMessage
+}
+
+// Publish implements jimm.Publisher. The returned channel is closed
+// immediately.
+func (p *Publisher) Publish(model string, content interface{}) <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = append(p.published, PublishedMessage{Model: model, Content: content})
+
+	done := make(chan struct{})
+	close(done)
+	return done
+}
+
+// Published returns the messages published so far, in the order they
+// were published.
+func (p *Publisher) Published() []PublishedMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PublishedMessage(nil), p.published...)
+}
+
+// Messages returns the content of the messages published so far, in
+// the order they were published.
+func (p *Publisher) Messages() []interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var messages []interface{}
+	for _, m := range p.published {
+		messages = append(messages, m.Content)
+	}
+	return messages
+}
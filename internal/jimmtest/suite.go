@@ -85,6 +85,8 @@ func (s *JIMMSuite) SetUpTest(c *gc.C) {
 		Pubsub:          &pubsub.Hub{MaxConcurrency: 10},
 		UUID:            ControllerUUID,
 		OpenFGAClient:   s.OFGAClient,
+		// The tests add the same juju controller under several names.
+		AllowDuplicateControllerUUIDs: true,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
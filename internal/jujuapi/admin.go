@@ -14,6 +14,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
 	"github.com/canonical/jimm/v3/pkg/api/params"
 )
 
@@ -102,7 +103,11 @@ func (r *controllerRoot) LoginWithSessionCookie(ctx context.Context) (jujuparams
 
 	r.mu.Lock()
 	r.user = user
+	// Session cookies are only issued to browsers, so requests on the
+	// connection come from the dashboard.
+	r.requestClass = jimmRPC.RequestClassPolling
 	r.mu.Unlock()
+	r.startSession(ctx, user)
 
 	// Get server version for LoginResult
 	srvVersion, err := r.jimm.EarliestControllerVersion(ctx)
@@ -138,6 +143,7 @@ func (r *controllerRoot) LoginWithSessionToken(ctx context.Context, req params.L
 	r.mu.Lock()
 	r.user = user
 	r.mu.Unlock()
+	r.startSession(ctx, user)
 
 	// Get server version for LoginResult
 	srvVersion, err := r.jimm.EarliestControllerVersion(ctx)
@@ -166,7 +172,9 @@ func (r *controllerRoot) LoginWithClientCredentials(ctx context.Context, req par
 
 	r.mu.Lock()
 	r.user = user
+	r.requestClass = jimmRPC.RequestClassAutomation
 	r.mu.Unlock()
+	r.startSession(ctx, user)
 
 	// Get server version for LoginResult
 	srvVersion, err := r.jimm.EarliestControllerVersion(ctx)
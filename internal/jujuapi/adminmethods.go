@@ -0,0 +1,50 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+
+	"github.com/juju/rpcreflect"
+)
+
+// addAdminMethod adds, or replaces, the given method in the root, as
+// AddMethod does, marking it as one that only JIMM administrators may
+// call. When JIMM serves its admin API on a listener of its own these
+// methods are not served to connections made to the public listener, see
+// ContextWithoutAdminMethods. A method restricted to JIMM administrators
+// must be added with addAdminMethod rather than AddMethod.
+func (r *controllerRoot) addAdminMethod(rootName string, version int, methodName string, mc rpcreflect.MethodCaller) {
+	r.AddMethod(rootName, version, methodName, mc)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.adminMethods == nil {
+		r.adminMethods = make(map[string]bool)
+	}
+	r.adminMethods[rootName+"."+methodName] = true
+}
+
+// isAdminMethod returns whether the given method was added with
+// addAdminMethod.
+func (r *controllerRoot) isAdminMethod(rootName, methodName string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.adminMethods[rootName+"."+methodName]
+}
+
+type withoutAdminMethodsKey struct{}
+
+// ContextWithoutAdminMethods returns a context for a connection to which
+// the administrative methods of the JIMM facade, those only JIMM
+// administrators may call, are not served. Calls to them fail with an
+// error with a code of CodeForbidden, whoever makes them.
+func ContextWithoutAdminMethods(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutAdminMethodsKey{}, true)
+}
+
+// adminMethodsDisabled returns whether the administrative methods of the
+// JIMM facade are not served to the connection with the given context.
+func adminMethodsDisabled(ctx context.Context) bool {
+	v, _ := ctx.Value(withoutAdminMethodsKey{}).(bool)
+	return v
+}
@@ -0,0 +1,265 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+func TestAdminMethodsDisabled(t *testing.T) {
+	c := qt.New(t)
+
+	r := newControllerRoot(nil, Params{}, "")
+	defer r.cleanup()
+	facadeInit["JIMM"](r)
+
+	// Every administrative method is a method of the JIMM facade.
+	c.Assert(r.adminMethods, qt.Not(qt.HasLen), 0)
+	for method := range r.adminMethods {
+		facade, name, _ := strings.Cut(method, ".")
+		c.Check(facade, qt.Equals, "JIMM")
+		_, err := r.FindMethod(facade, 4, name)
+		c.Check(err, qt.IsNil, qt.Commentf("%s", method))
+	}
+
+	c.Check(adminMethodsDisabled(context.Background()), qt.IsFalse)
+	r.adminMethodsDisabled = adminMethodsDisabled(ContextWithoutAdminMethods(context.Background()))
+	c.Assert(r.adminMethodsDisabled, qt.IsTrue)
+	for _, method := range []string{"AddController", "RemoveController", "ControllerCall", "PurgeIdentity", "FreezeModel", "GrantAuditLogAccess", "AddCloudToController"} {
+		_, err := r.FindMethod("JIMM", 4, method)
+		c.Check(err, qt.ErrorMatches, method+` is only available on the admin listener`)
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeForbidden)
+	}
+	_, err := r.FindMethod("JIMM", 4, "Whoami")
+	c.Check(err, qt.IsNil)
+}
+
+// TestAdminOnlyMethodsAreAdminMethods checks that every method of the
+// JIMM facade whose implementation rejects callers that are not JIMM or
+// controller administrators is added with addAdminMethod. The
+// implementations are found by reading the source of this package and of
+// the jimm package.
+func TestAdminOnlyMethodsAreAdminMethods(t *testing.T) {
+	c := qt.New(t)
+
+	r := newControllerRoot(nil, Params{}, "")
+	defer r.cleanup()
+	facadeInit["JIMM"](r)
+
+	rootFiles := parseSourceFiles(c, ".")
+	checker := adminOnlyChecker{
+		jimmMethods: sourceMethods(parseSourceFiles(c, "../jimm"), "JIMM"),
+	}
+	rootMethods := sourceMethods(rootFiles, "controllerRoot")
+
+	// Find the controllerRoot method implementing each method registered
+	// on the JIMM facade.
+	callers := make(map[string]string)
+	registered := make(map[string]string)
+	for _, f := range rootFiles {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				// <var> := rpc.Method(r.<method>)
+				if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+					return true
+				}
+				id, ok := n.Lhs[0].(*ast.Ident)
+				if !ok {
+					return true
+				}
+				call, ok := n.Rhs[0].(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 || !isSelector(call.Fun, "Method") {
+					return true
+				}
+				if sel, ok := call.Args[0].(*ast.SelectorExpr); ok {
+					callers[id.Name] = sel.Sel.Name
+				}
+			case *ast.CallExpr:
+				// r.AddMethod("JIMM", 4, "<name>", <var>)
+				if len(n.Args) != 4 || !(isSelector(n.Fun, "AddMethod") || isSelector(n.Fun, "addAdminMethod")) {
+					return true
+				}
+				facade, ok := n.Args[0].(*ast.BasicLit)
+				if !ok || facade.Value != `"JIMM"` {
+					return true
+				}
+				name, ok := n.Args[2].(*ast.BasicLit)
+				if !ok {
+					return true
+				}
+				if id, ok := n.Args[3].(*ast.Ident); ok {
+					method, err := strconv.Unquote(name.Value)
+					c.Assert(err, qt.IsNil)
+					registered[method] = id.Name
+				}
+			}
+			return true
+		})
+	}
+	c.Assert(registered, qt.Not(qt.HasLen), 0)
+
+	for method, v := range registered {
+		fd, ok := rootMethods[callers[v]]
+		if !c.Check(ok, qt.IsTrue, qt.Commentf("cannot find implementation of %s", method)) {
+			continue
+		}
+		if checker.adminOnly(fd, 0) {
+			c.Check(r.adminMethods["JIMM."+method], qt.IsTrue, qt.Commentf("%s is only available to administrators but is not added with addAdminMethod", method))
+		}
+	}
+}
+
+// parseSourceFiles parses the non-test Go source files in the given
+// directory.
+func parseSourceFiles(c *qt.C, dir string) []*ast.File {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	c.Assert(err, qt.IsNil)
+	var files []*ast.File
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// sourceMethods returns the methods, by name, declared on pointers to the
+// named type in the given files.
+func sourceMethods(files []*ast.File, typeName string) map[string]*ast.FuncDecl {
+	methods := make(map[string]*ast.FuncDecl)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv == nil || fd.Body == nil {
+				continue
+			}
+			star, ok := fd.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			if id, ok := star.X.(*ast.Ident); ok && id.Name == typeName {
+				methods[fd.Name.Name] = fd
+			}
+		}
+	}
+	return methods
+}
+
+// isSelector returns whether the given expression selects the named
+// field or method.
+func isSelector(e ast.Expr, name string) bool {
+	sel, ok := e.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == name
+}
+
+// adminOnlyChecker finds implementations that reject all callers other
+// than JIMM or controller administrators.
+type adminOnlyChecker struct {
+	jimmMethods map[string]*ast.FuncDecl
+}
+
+// adminOnly returns whether the given function rejects all callers other
+// than administrators. Only the statements at the top level of the
+// function are considered, along with those of the JIMM methods they
+// call, to a depth of two calls. An administrator check is one of:
+//
+//   - an if statement testing !<user>.JimmAdmin that returns an error with
+//     the code CodeUnauthorized;
+//   - a call to JIMM.checkJimmAdmin or JIMM.checkControllerAdminAccess;
+//   - a call to <user>.GetControllerAccess for JIMM's own controller.
+//
+// An if statement testing !<user>.JimmAdmin that does anything else means
+// the function serves other callers too.
+func (ch adminOnlyChecker) adminOnly(fd *ast.FuncDecl, depth int) bool {
+	for _, stmt := range fd.Body.List {
+		if is, ok := stmt.(*ast.IfStmt); ok {
+			if u, ok := is.Cond.(*ast.UnaryExpr); ok && u.Op == token.NOT && isSelector(u.X, "JimmAdmin") {
+				return returnsUnauthorized(is.Body)
+			}
+		}
+		found := false
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			if found {
+				return false
+			}
+			switch n := n.(type) {
+			case *ast.BlockStmt, *ast.FuncLit:
+				return false
+			case *ast.CallExpr:
+				sel, ok := n.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				switch sel.Sel.Name {
+				case "checkJimmAdmin", "checkControllerAdminAccess":
+					found = true
+					return false
+				case "GetControllerAccess":
+					if len(n.Args) == 2 {
+						if arg, ok := n.Args[1].(*ast.CallExpr); ok && isSelector(arg.Fun, "ResourceTag") {
+							found = true
+							return false
+						}
+					}
+				}
+				if depth >= 2 {
+					return true
+				}
+				// Follow calls to JIMM methods, made either as
+				// r.jimm.<method> or j.<method>.
+				var follow bool
+				switch x := sel.X.(type) {
+				case *ast.Ident:
+					follow = x.Name == "j"
+				case *ast.SelectorExpr:
+					follow = x.Sel.Name == "jimm"
+				}
+				if jfd, ok := ch.jimmMethods[sel.Sel.Name]; follow && ok && ch.adminOnly(jfd, depth+1) {
+					found = true
+					return false
+				}
+			}
+			return true
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// returnsUnauthorized returns whether the given block returns an error
+// with the code CodeUnauthorized.
+func returnsUnauthorized(b *ast.BlockStmt) bool {
+	for _, stmt := range b.List {
+		rs, ok := stmt.(*ast.ReturnStmt)
+		if !ok {
+			continue
+		}
+		found := false
+		ast.Inspect(rs, func(n ast.Node) bool {
+			if e, ok := n.(ast.Expr); ok && isSelector(e, "CodeUnauthorized") {
+				found = true
+			}
+			return !found
+		})
+		if found {
+			return true
+		}
+	}
+	return false
+}
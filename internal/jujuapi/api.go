@@ -6,9 +6,11 @@ package jujuapi
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmhttp"
+	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
 )
 
 // A Params object holds the parameters needed to configure the API
@@ -20,12 +22,61 @@ type Params struct {
 	// PublicDNSName is the name to advertise as the public address of
 	// the juju controller.
 	PublicDNSName string
+
+	// RedactedModelFields holds the model fields that are removed from
+	// model information returned to users with less than admin access
+	// to the model. If this is nil DefaultRedactedModelFields is used.
+	RedactedModelFields []string
+
+	// RedactedModelConfigKeys holds the model config keys that are
+	// removed from the model config and model information returned to
+	// users with less than admin access to the model. If this is nil
+	// DefaultRedactedModelConfigKeys is used.
+	RedactedModelConfigKeys []string
+
+	// FanOutSoftDeadline is the time after which API methods that query
+	// a number of controllers in parallel return the results that are
+	// available. Any entries for controllers that have not responded
+	// are returned with a "controller timed out" error. If this is zero
+	// all controllers are waited for until the request times out.
+	FanOutSoftDeadline time.Duration
+
+	// ModelSummariesDeadline is the time after which ListModelSummaries
+	// stops reading models and returns the summaries already read,
+	// flagged as incomplete. If this is zero every summary is read until
+	// the request times out.
+	ModelSummariesDeadline time.Duration
+
+	// ConfirmationPeriod is the time for which a confirmation of the
+	// user's identity allows sensitive operations, such as forcibly
+	// destroying models, removing controllers and reading the audit
+	// log. If this is zero sensitive operations do not require
+	// confirmation.
+	ConfirmationPeriod time.Duration
+
+	// ConnectionPolicy restricts the clients that may open websocket
+	// connections to the controller and model APIs. If this is nil all
+	// clients may connect.
+	ConnectionPolicy *jimmhttp.ConnectionPolicy
+
+	// FacadeDeprecations holds the facade versions scheduled for
+	// removal. Their use is counted in the facade request metrics and
+	// responses to requests proxied to models carry a deprecation
+	// warning.
+	FacadeDeprecations jimmRPC.FacadeDeprecations
+
+	// RequestLimiter limits the number of controller and model API
+	// requests processed concurrently, prioritising interactive
+	// requests over dashboard polling and automation. If this is nil
+	// requests are not limited.
+	RequestLimiter *jimmRPC.RequestLimiter
 }
 
 // APIHandler returns an http Handler for the /api endpoint.
 func APIHandler(ctx context.Context, jimm *jimm.JIMM, p Params) http.Handler {
 	return &jimmhttp.WSHandler{
-		Upgrader: websocketUpgrader,
+		Upgrader: controllerWebsocketUpgrader,
+		Policy:   p.ConnectionPolicy,
 		Server: &apiServer{
 			jimm:   jimm,
 			params: p,
@@ -38,12 +89,15 @@ func ModelHandler(ctx context.Context, jimm *jimm.JIMM, p Params) http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/{uuid}/api", &jimmhttp.WSHandler{
 		Upgrader: websocketUpgrader,
+		Policy:   p.ConnectionPolicy,
 		Server: &apiProxier{apiServer: apiServer{
-			jimm: jimm,
+			jimm:   jimm,
+			params: p,
 		}},
 	})
 	mux.Handle("/{uuid}/log", &jimmhttp.WSHandler{
 		Upgrader: websocketUpgrader,
+		Policy:   p.ConnectionPolicy,
 		Server: &streamProxier{apiServer: apiServer{
 			jimm: jimm,
 		}},
@@ -12,6 +12,7 @@ import (
 	"github.com/gorilla/websocket"
 	gc "gopkg.in/check.v1"
 
+	"github.com/canonical/jimm/v3/internal/cbor"
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	"github.com/canonical/jimm/v3/internal/jujuapi"
 )
@@ -62,3 +63,69 @@ func (s *apiSuite) TestModelCommandsModelNotFoundf(c *gc.C) {
 
 	c.Assert(response.StatusCode, gc.Equals, http.StatusNotFound)
 }
+
+func (s *apiSuite) TestControllerAPICBORSubprotocol(c *gc.C) {
+	serverURL, err := url.Parse(s.HTTP.URL)
+	c.Assert(err, gc.Equals, nil)
+	u := url.URL{
+		Scheme: "ws",
+		Host:   serverURL.Host,
+		Path:   "/api",
+	}
+
+	dialer := websocket.Dialer{Subprotocols: []string{jujuapi.CBORSubprotocol}}
+	conn, response, err := dialer.Dial(u.String(), nil)
+	c.Assert(err, gc.Equals, nil)
+	defer response.Body.Close()
+	defer conn.Close()
+	c.Assert(conn.Subprotocol(), gc.Equals, jujuapi.CBORSubprotocol)
+
+	req, err := cbor.Marshal(map[string]interface{}{
+		"request-id": 1,
+		"type":       "Pinger",
+		"version":    1,
+		"request":    "Ping",
+	})
+	c.Assert(err, gc.Equals, nil)
+	err = conn.WriteMessage(websocket.BinaryMessage, req)
+	c.Assert(err, gc.Equals, nil)
+
+	messageType, data, err := conn.ReadMessage()
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(messageType, gc.Equals, websocket.BinaryMessage)
+	resp, err := cbor.Decode(data)
+	c.Assert(err, gc.Equals, nil)
+	m, ok := resp.(map[string]interface{})
+	c.Assert(ok, gc.Equals, true)
+	c.Check(m["request-id"], gc.Equals, int64(1))
+	c.Check(m["error"], gc.IsNil)
+}
+
+func (s *apiSuite) TestControllerAPIDefaultsToJSON(c *gc.C) {
+	serverURL, err := url.Parse(s.HTTP.URL)
+	c.Assert(err, gc.Equals, nil)
+	u := url.URL{
+		Scheme: "ws",
+		Host:   serverURL.Host,
+		Path:   "/api",
+	}
+
+	conn, response, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	c.Assert(err, gc.Equals, nil)
+	defer response.Body.Close()
+	defer conn.Close()
+	c.Assert(conn.Subprotocol(), gc.Equals, "")
+
+	err = conn.WriteJSON(map[string]interface{}{
+		"request-id": 1,
+		"type":       "Pinger",
+		"version":    1,
+		"request":    "Ping",
+	})
+	c.Assert(err, gc.Equals, nil)
+	var resp map[string]interface{}
+	err = conn.ReadJSON(&resp)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(resp["request-id"], gc.Equals, float64(1))
+	c.Check(resp["error"], gc.IsNil)
+}
@@ -44,9 +44,11 @@ func (r *controllerRoot) Offer(ctx context.Context, args jujuparams.AddApplicati
 	result := jujuparams.ErrorResults{
 		Results: make([]jujuparams.ErrorResult, len(args.Offers)),
 	}
+	errs := newBulkErrors("jujuapi.Offer", len(args.Offers))
 	for i, addOfferParams := range args.Offers {
-		result.Results[i].Error = mapError(r.offer(ctx, addOfferParams))
+		result.Results[i].Error = errs.add(i, addOfferParams.OfferName, r.offer(ctx, addOfferParams))
 	}
+	errs.finish(ctx)
 	return result, nil
 }
 
@@ -160,9 +162,11 @@ func (r *controllerRoot) ModifyOfferAccess(ctx context.Context, args jujuparams.
 		Results: make([]jujuparams.ErrorResult, len(args.Changes)),
 	}
 
+	errs := newBulkErrors("jujuapi.ModifyOfferAccess", len(args.Changes))
 	for i, change := range args.Changes {
-		results.Results[i].Error = mapError(r.modifyOfferAccess(ctx, change))
+		results.Results[i].Error = errs.add(i, change.OfferURL, r.modifyOfferAccess(ctx, change))
 	}
+	errs.finish(ctx)
 	return results, nil
 }
 
@@ -195,9 +199,11 @@ func (r *controllerRoot) DestroyOffers(ctx context.Context, args jujuparams.Dest
 		Results: make([]jujuparams.ErrorResult, len(args.OfferURLs)),
 	}
 
+	errs := newBulkErrors("jujuapi.DestroyOffers", len(args.OfferURLs))
 	for i, offerURL := range args.OfferURLs {
-		results.Results[i].Error = mapError(r.jimm.DestroyOffer(ctx, r.user, offerURL, args.Force))
+		results.Results[i].Error = errs.add(i, offerURL, r.jimm.DestroyOffer(ctx, r.user, offerURL, args.Force))
 	}
+	errs.finish(ctx)
 	return results, nil
 }
 
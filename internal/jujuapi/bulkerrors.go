@@ -0,0 +1,129 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	stderrors "errors"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A bulkErrors aggregates the per-entry errors of a bulk facade method
+// so that every bulk method reports them in the same shape. Each error
+// has an apiparams.BulkErrorInfo attached as its Info, holding the index
+// and entity of the entry, the chain of causes of the error, whether the
+// entry may be retried and, once finish has been called, a summary of
+// the whole request.
+type bulkErrors struct {
+	op      errors.Op
+	summary apiparams.BulkErrorSummary
+	errs    []*jujuparams.Error
+}
+
+// newBulkErrors returns a bulkErrors for the bulk method with the given
+// op, called with the given number of entries.
+func newBulkErrors(op errors.Op, total int) *bulkErrors {
+	return &bulkErrors{
+		op: op,
+		summary: apiparams.BulkErrorSummary{
+			Total: total,
+		},
+	}
+}
+
+// add returns the API error reporting that the entry at the given index,
+// which is identified by the given entity, failed with the given error.
+// If the error is nil add returns nil.
+func (b *bulkErrors) add(index int, entity string, err error) *jujuparams.Error {
+	if err == nil {
+		return nil
+	}
+	e := mapError(err)
+	retryable := isRetryable(err)
+	e.Info = map[string]interface{}{
+		"index":     index,
+		"entity":    entity,
+		"retryable": retryable,
+		"causes":    errorCauses(err),
+	}
+	b.summary.Failed++
+	if retryable {
+		b.summary.Retryable++
+	}
+	if b.summary.Codes == nil {
+		b.summary.Codes = make(map[string]int)
+	}
+	b.summary.Codes[e.Code]++
+	b.errs = append(b.errs, e)
+	return e
+}
+
+// finish attaches the summary of the request to every error added and
+// logs it. It must be called once every entry has been processed.
+func (b *bulkErrors) finish(ctx context.Context) {
+	for _, e := range b.errs {
+		e.Info["summary"] = b.summary
+	}
+	if b.summary.Failed > 0 {
+		zapctx.Debug(ctx, "bulk request entries failed",
+			zap.String("op", string(b.op)),
+			zap.Int("total", b.summary.Total),
+			zap.Int("failed", b.summary.Failed),
+			zap.Int("retryable", b.summary.Retryable),
+			zap.Any("codes", b.summary.Codes),
+		)
+	}
+}
+
+// errorCauses returns the chain of errors that caused the given error,
+// outermost first. JIMM errors that only wrap another error without
+// adding an operation or message are omitted. Errors from outside JIMM
+// already include their causes in their messages, so the chain stops at
+// the first of them.
+func errorCauses(err error) []apiparams.ErrorCause {
+	var causes []apiparams.ErrorCause
+	for err != nil {
+		e, ok := err.(*errors.Error)
+		if !ok {
+			causes = append(causes, apiparams.ErrorCause{
+				Code:    jujuparams.ErrCode(err),
+				Message: err.Error(),
+			})
+			break
+		}
+		if e.Op != "" || e.Message != "" {
+			causes = append(causes, apiparams.ErrorCause{
+				Op:      string(e.Op),
+				Code:    string(e.Code),
+				Message: e.Message,
+			})
+		}
+		err = e.Err
+	}
+	return causes
+}
+
+// isRetryable reports whether the given error is a transient failure,
+// such that retrying the entry that failed with it may succeed.
+func isRetryable(err error) bool {
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	for e := err; e != nil; e = stderrors.Unwrap(e) {
+		switch errors.Code(jujuparams.ErrCode(e)) {
+		case errors.CodeTryAgain,
+			errors.CodeConnectionFailed,
+			errors.CodeDatabaseLocked,
+			errors.CodeUpgradeInProgress,
+			errors.CodeOpenFGARequestFailed:
+			return true
+		}
+	}
+	return false
+}
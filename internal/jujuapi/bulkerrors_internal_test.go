@@ -0,0 +1,74 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestBulkErrors(t *testing.T) {
+	c := qt.New(t)
+
+	const op = errors.Op("jujuapi.Test")
+	errs := newBulkErrors(op, 4)
+	c.Check(errs.add(0, "model-1", nil), qt.IsNil)
+
+	e1 := errs.add(1, "model-2", errors.E(op, errors.E(errors.Op("jimm.DestroyModel"), errors.CodeNotFound, "model not found")))
+	c.Assert(e1, qt.Not(qt.IsNil))
+	c.Check(e1.Message, qt.Equals, "model not found")
+	c.Check(e1.Code, qt.Equals, string(errors.CodeNotFound))
+
+	e2 := errs.add(2, "model-3", errors.E(op, errors.CodeConnectionFailed, fmt.Errorf("dial tcp: %w", context.DeadlineExceeded)))
+	c.Assert(e2, qt.Not(qt.IsNil))
+
+	e3 := errs.add(3, "model-4", errors.E(op, errors.E(errors.CodeTryAgain, "controller is under maintenance")))
+	c.Assert(e3, qt.Not(qt.IsNil))
+
+	errs.finish(context.Background())
+
+	summary := apiparams.BulkErrorSummary{
+		Total:     4,
+		Failed:    3,
+		Retryable: 2,
+		Codes: map[string]int{
+			string(errors.CodeNotFound):         1,
+			string(errors.CodeConnectionFailed): 1,
+			string(errors.CodeTryAgain):         1,
+		},
+	}
+	c.Check(e1.Info, qt.DeepEquals, map[string]interface{}{
+		"index":     1,
+		"entity":    "model-2",
+		"retryable": false,
+		"causes": []apiparams.ErrorCause{{
+			Op:   "jujuapi.Test",
+			Code: string(errors.CodeNotFound),
+		}, {
+			Op:      "jimm.DestroyModel",
+			Code:    string(errors.CodeNotFound),
+			Message: "model not found",
+		}},
+		"summary": summary,
+	})
+	c.Check(e2.Info, qt.DeepEquals, map[string]interface{}{
+		"index":     2,
+		"entity":    "model-3",
+		"retryable": true,
+		"causes": []apiparams.ErrorCause{{
+			Op:   "jujuapi.Test",
+			Code: string(errors.CodeConnectionFailed),
+		}, {
+			Message: "dial tcp: context deadline exceeded",
+		}},
+		"summary": summary,
+	})
+	c.Check(e3.Info["retryable"], qt.Equals, true)
+	c.Check(e3.Info["summary"], qt.DeepEquals, summary)
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/jsoncodec"
+
+	"github.com/canonical/jimm/v3/internal/cbor"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// CBORSubprotocol is the websocket subprotocol a client requests when
+// connecting to the controller API to have RPC messages exchanged in
+// CBOR (RFC 8949) binary messages rather than JSON text messages. The
+// messages have the same structure in either encoding. The binary
+// encoding is intended for clients that consume large volumes of data,
+// such as audit events and model summaries, where it reduces both the
+// bandwidth used and the cost of encoding and decoding the messages.
+// Clients that do not request it, and model connections, use JSON.
+const CBORSubprotocol = "jimm.cbor"
+
+// newCodec returns an RPC codec for the given websocket connection that
+// uses the encoding negotiated with the client.
+func newCodec(conn *websocket.Conn) rpc.Codec {
+	if conn.Subprotocol() == CBORSubprotocol {
+		return jsoncodec.New(&cborConn{conn: conn})
+	}
+	return jsoncodec.NewWebsocket(conn)
+}
+
+// A cborConn is a jsoncodec.JSONConn that sends and receives messages as
+// CBOR encoded binary websocket messages. Outgoing messages are encoded
+// directly from their JSON representation, incoming messages, which are
+// typically small requests, are converted to JSON before being decoded.
+type cborConn struct {
+	conn *websocket.Conn
+	// gorilla websockets can have at most one concurrent writer, and
+	// one concurrent reader.
+	writeMutex sync.Mutex
+	readMutex  sync.Mutex
+}
+
+// Send implements jsoncodec.JSONConn.
+func (c *cborConn) Send(msg interface{}) error {
+	data, err := cbor.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	return c.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// Receive implements jsoncodec.JSONConn.
+func (c *cborConn) Receive(msg interface{}) error {
+	c.readMutex.Lock()
+	messageType, data, err := c.conn.ReadMessage()
+	c.readMutex.Unlock()
+	if err != nil {
+		if websocket.IsCloseError(err,
+			websocket.CloseNormalClosure,
+			websocket.CloseGoingAway,
+			websocket.CloseNoStatusReceived,
+			websocket.CloseAbnormalClosure) {
+			return io.EOF
+		}
+		return err
+	}
+	if messageType != websocket.BinaryMessage {
+		return errors.E("unexpected text message on CBOR connection")
+	}
+	v, err := cbor.Decode(data)
+	if err != nil {
+		return err
+	}
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return errors.E(err)
+	}
+	if raw, ok := msg.(*json.RawMessage); ok {
+		*raw = buf
+		return nil
+	}
+	return json.Unmarshal(buf, msg)
+}
+
+// Close implements jsoncodec.JSONConn.
+func (c *cborConn) Close() error {
+	// Tell the other end we are closing.
+	c.writeMutex.Lock()
+	_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+	c.writeMutex.Unlock()
+	return c.conn.Close()
+}
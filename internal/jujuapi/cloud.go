@@ -17,6 +17,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 func init() {
@@ -71,9 +72,16 @@ func (r *controllerRoot) RevokeCredentials(ctx context.Context, args jujuparams.
 }
 
 // DefaultCloud implements the DefaultCloud method of the Cloud facade.
-// It returns a default cloud if there is only one cloud available.
+// It returns the default cloud of the user's identity domain, if one has
+// been configured.
 func (r *controllerRoot) DefaultCloud(ctx context.Context) (jujuparams.StringResult, error) {
-	return jujuparams.StringResult{}, errors.E(errors.Op("jujuapi.DefaultCloud"), errors.CodeNotFound, "no default cloud")
+	const op = errors.Op("jujuapi.DefaultCloud")
+
+	def, err := r.jimm.DefaultCloud(ctx, r.user)
+	if err != nil {
+		return jujuparams.StringResult{}, errors.E(op, err)
+	}
+	return jujuparams.StringResult{Result: names.NewCloudTag(def.CloudName).String()}, nil
 }
 
 // Cloud implements the Cloud method of the Cloud facade.
@@ -151,11 +159,11 @@ func (r *controllerRoot) RevokeCredentialsCheckModels(ctx context.Context, args
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 	results := make([]jujuparams.ErrorResult, len(args.Credentials))
+	errs := newBulkErrors("jujuapi.RevokeCredentialsCheckModels", len(args.Credentials))
 	for i, ent := range args.Credentials {
-		if err := r.revokeCredential(ctx, ent.Tag, ent.Force); err != nil {
-			results[i].Error = mapError(err)
-		}
+		results[i].Error = errs.add(i, ent.Tag, r.revokeCredential(ctx, ent.Tag, ent.Force))
 	}
+	errs.finish(ctx)
 	return jujuparams.ErrorResults{
 		Results: results,
 	}, nil
@@ -303,8 +311,12 @@ func userModelAccess(ctx context.Context, user *openfga.User, model names.ModelT
 }
 
 // CredentialContents implements the CredentialContents method of the Cloud (v5) facade.
-func (r *controllerRoot) CredentialContents(ctx context.Context, args jujuparams.CloudCredentialArgs) (jujuparams.CredentialContentResults, error) {
-	return getIdentityCredentials(ctx, r.user, r.jimm, args)
+// The request may specify the read consistency, in addition to the standard
+// parameters, to choose whether the validity of the credentials is read
+// from JIMM's store or from the controllers using them.
+func (r *controllerRoot) CredentialContents(ctx context.Context, args apiparams.ConsistentCloudCredentialArgs) (jujuparams.CredentialContentResults, error) {
+	ctx = r.withReadConsistency(ctx, args.Consistency)
+	return getIdentityCredentials(ctx, r.user, r.jimm, args.CloudCredentialArgs)
 }
 
 func getIdentityCredentials(ctx context.Context, user *openfga.User, j JIMM, args jujuparams.CloudCredentialArgs) (jujuparams.CredentialContentResults, error) {
@@ -324,16 +336,21 @@ func getIdentityCredentials(ctx context.Context, user *openfga.User, j JIMM, arg
 		if err != nil {
 			return nil, errors.E(err)
 		}
-		mas := make([]jujuparams.ModelAccess, len(c.Models))
-		for i, m := range c.Models {
+		var mas []jujuparams.ModelAccess
+		for _, m := range c.Models {
 			userModelAccess, err := userModelAccess(ctx, user, m.ResourceTag())
 			if err != nil {
 				return nil, errors.E(err)
 			}
-			mas[i] = jujuparams.ModelAccess{
+			if userModelAccess == "" {
+				// The credential may be used by models the user
+				// cannot see, don't reveal their names.
+				continue
+			}
+			mas = append(mas, jujuparams.ModelAccess{
 				Model:  m.Name,
 				Access: userModelAccess,
-			}
+			})
 		}
 		return &jujuparams.ControllerCredentialInfo{
 			Content: content,
@@ -382,17 +399,19 @@ func (r *controllerRoot) RemoveClouds(ctx context.Context, args jujuparams.Entit
 	result := jujuparams.ErrorResults{
 		Results: make([]jujuparams.ErrorResult, len(args.Entities)),
 	}
+	errs := newBulkErrors(op, len(args.Entities))
 	for i, entity := range args.Entities {
 		tag, err := names.ParseCloudTag(entity.Tag)
 		if err != nil {
-			result.Results[i].Error = mapError(errors.E(op, err))
+			result.Results[i].Error = errs.add(i, entity.Tag, errors.E(op, err))
 			continue
 		}
 		err = r.jimm.RemoveCloud(ctx, r.user, tag)
 		if err != nil {
-			result.Results[i].Error = mapError(errors.E(op, err))
+			result.Results[i].Error = errs.add(i, entity.Tag, errors.E(op, err))
 		}
 	}
+	errs.finish(ctx)
 	return result, nil
 }
 
@@ -403,9 +422,11 @@ func (r *controllerRoot) CheckCredentialsModels(ctx context.Context, args jujupa
 // ModifyCloudAccess changes the cloud access granted to users.
 func (r *controllerRoot) ModifyCloudAccess(ctx context.Context, args jujuparams.ModifyCloudAccessRequest) (jujuparams.ErrorResults, error) {
 	results := make([]jujuparams.ErrorResult, len(args.Changes))
+	errs := newBulkErrors("jujuapi.ModifyCloudAccess", len(args.Changes))
 	for i, change := range args.Changes {
-		results[i].Error = mapError(r.modifyCloudAccess(ctx, change))
+		results[i].Error = errs.add(i, change.CloudTag, r.modifyCloudAccess(ctx, change))
 	}
+	errs.finish(ctx)
 	return jujuparams.ErrorResults{
 		Results: results,
 	}, nil
@@ -450,16 +471,18 @@ func (r *controllerRoot) updateCredentials(ctx context.Context, args []jujuparam
 	results := jujuparams.UpdateCredentialResults{
 		Results: make([]jujuparams.UpdateCredentialResult, len(args)),
 	}
+	errs := newBulkErrors("jujuapi.UpdateCredentials", len(args))
 	for i, arg := range args {
 		var err error
 		models, err := r.updateCredential(ctx, arg, skipCheck, skipUpdate)
 		results.Results[i] = jujuparams.UpdateCredentialResult{
 			CredentialTag: arg.Tag,
-			Error:         mapError(err),
+			Error:         errs.add(i, arg.Tag, err),
 			Models:        models,
 		}
 		results.Results[i].CredentialTag = arg.Tag
 	}
+	errs.finish(ctx)
 	return results, nil
 }
 
@@ -481,12 +504,11 @@ func (r *controllerRoot) UpdateCloud(ctx context.Context, args jujuparams.Update
 	results := jujuparams.ErrorResults{
 		Results: make([]jujuparams.ErrorResult, len(args.Clouds)),
 	}
-	for i := range args.Clouds {
-		err := r.updateCloud()
-		if err != nil {
-			results.Results[i].Error = mapError(err)
-		}
+	errs := newBulkErrors("jujuapi.UpdateCloud", len(args.Clouds))
+	for i, cloud := range args.Clouds {
+		results.Results[i].Error = errs.add(i, cloud.Name, r.updateCloud())
 	}
+	errs.finish(ctx)
 	return results, nil
 }
 
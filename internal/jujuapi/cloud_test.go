@@ -19,6 +19,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 type cloudSuite struct {
@@ -332,6 +333,20 @@ func (s *cloudSuite) TestCheckCredentialsModelsInvalidCreds(c *gc.C) {
 		}},
 	}, &resp)
 	c.Assert(err, gc.Equals, nil)
+	c.Assert(resp.Results, gc.HasLen, 1)
+	c.Assert(resp.Results[0].Error, gc.NotNil)
+	var info apiparams.BulkErrorInfo
+	err = resp.Results[0].Error.UnmarshalInfo(&info)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(info.Index, gc.Equals, 0)
+	c.Check(info.Entity, gc.Equals, credTag.String())
+	c.Check(info.Retryable, gc.Equals, false)
+	c.Check(info.Summary, jc.DeepEquals, apiparams.BulkErrorSummary{
+		Total:  1,
+		Failed: 1,
+		Codes:  map[string]int{"": 1},
+	})
+	resp.Results[0].Error.Info = nil
 	c.Assert(resp, jc.DeepEquals, jujuparams.UpdateCredentialResults{
 		Results: []jujuparams.UpdateCredentialResult{{
 			CredentialTag: "cloudcred-" + jimmtest.TestCloudName + "_test@canonical.com_cred",
@@ -729,6 +744,49 @@ func (s *cloudSuite) TestCredentialContents(c *gc.C) {
 	}})
 }
 
+func (s *cloudSuite) TestCredentialContentsHidesInaccessibleModels(c *gc.C) {
+	conn := s.open(c, nil, "test")
+	defer conn.Close()
+	client := cloudapi.NewClient(conn)
+	credentialTag := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/test@canonical.com/cred3")
+	err := client.AddCredential(
+		credentialTag.String(),
+		cloud.NewCredential(
+			"userpass",
+			map[string]string{
+				"username": "test-user",
+				"password": "S3cret",
+			},
+		),
+	)
+	c.Assert(err, gc.Equals, nil)
+
+	mmclient := modelmanager.NewClient(conn)
+	mi, err := mmclient.CreateModel("model1", "test@canonical.com", jimmtest.TestCloudName, "", credentialTag, nil)
+	c.Assert(err, gc.Equals, nil)
+	err = s.OFGAClient.RemoveRelation(context.Background(), openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("test@canonical.com")),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(names.NewModelTag(mi.UUID)),
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	creds, err := client.CredentialContents(jimmtest.TestCloudName, "cred3", false)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(creds, jc.DeepEquals, []jujuparams.CredentialContentResult{{
+		Result: &jujuparams.ControllerCredentialInfo{
+			Content: jujuparams.CredentialContent{
+				Name:     "cred3",
+				Cloud:    jimmtest.TestCloudName,
+				AuthType: "userpass",
+				Attributes: map[string]string{
+					"username": "test-user",
+				},
+			},
+		},
+	}})
+}
+
 func (s *cloudSuite) TestCredentialContentsWithEmptyAttributes(c *gc.C) {
 	conn := s.open(c, nil, "test")
 	defer conn.Close()
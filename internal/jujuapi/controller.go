@@ -14,8 +14,10 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmversion "github.com/canonical/jimm/v3/version"
 )
 
@@ -54,13 +56,19 @@ func init() {
 // ControllerService defines the methods used to manage controllers.
 type ControllerService interface {
 	AddController(ctx context.Context, user *openfga.User, ctl *dbmodel.Controller) error
+	ControllerHealth(controllerName string) *apiparams.ControllerHealth
+	ControllerHealthStates(ctx context.Context, user *openfga.User) ([]apiparams.ControllerHealthState, error)
 	ControllerInfo(ctx context.Context, name string) (*dbmodel.Controller, error)
 	EarliestControllerVersion(ctx context.Context) (version.Number, error)
+	GetControllerBootstrapProfile(ctx context.Context, user *openfga.User, controllerName string, version int) (apiparams.ControllerBootstrapProfile, error)
 	ListControllers(ctx context.Context, user *openfga.User) ([]dbmodel.Controller, error)
 	GetControllerConfig(ctx context.Context, user *dbmodel.Identity) (*dbmodel.ControllerConfig, error)
 	SetControllerConfig(ctx context.Context, user *openfga.User, args jujuparams.ControllerConfigSet) error
 	RemoveController(ctx context.Context, user *openfga.User, controllerName string, force bool) error
 	SetControllerDeprecated(ctx context.Context, user *openfga.User, controllerName string, deprecated bool) error
+	SetControllerMaintenance(ctx context.Context, user *openfga.User, controllerName string, maintenance bool) (int, error)
+	SetControllerTiers(ctx context.Context, user *openfga.User, controllerName string, tiers []string) error
+	SetControllerTimeouts(ctx context.Context, user *openfga.User, controllerName string, timeouts jimm.ControllerTimeouts) error
 }
 
 // ConfigSet changes the value of specified controller configuration
@@ -133,7 +141,7 @@ func (r *controllerRoot) WatchModelSummaries(ctx context.Context) (jujuparams.Su
 	if err != nil {
 		return jujuparams.SummaryWatcherID{}, errors.E(op, err)
 	}
-	r.watchers.register(watcher)
+	r.watchers.register(id, watcher)
 
 	return jujuparams.SummaryWatcherID{
 		WatcherID: id,
@@ -172,7 +180,7 @@ func (r *controllerRoot) WatchAllModelSummaries(ctx context.Context) (jujuparams
 	if err != nil {
 		return jujuparams.SummaryWatcherID{}, errors.E(op, err)
 	}
-	r.watchers.register(watcher)
+	r.watchers.register(id, watcher)
 
 	return jujuparams.SummaryWatcherID{
 		WatcherID: id,
@@ -205,9 +213,13 @@ func (r *controllerRoot) allModels(ctx context.Context) (jujuparams.UserModelLis
 }
 
 // ModelStatus implements the ModelStatus command on the Controller facade.
-func (r *controllerRoot) ModelStatus(ctx context.Context, args jujuparams.Entities) (jujuparams.ModelStatusResults, error) {
+// The request may specify the read consistency, in addition to the
+// standard parameters, to choose whether the status is read from JIMM's
+// store or from the controllers hosting the models.
+func (r *controllerRoot) ModelStatus(ctx context.Context, args apiparams.ConsistentEntities) (jujuparams.ModelStatusResults, error) {
 	const op = errors.Op("jujuapi.ModelStatus")
 
+	ctx = r.withReadConsistency(ctx, args.Consistency)
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 	results := make([]jujuparams.ModelStatus, len(args.Entities))
@@ -307,13 +319,15 @@ func (r *controllerRoot) InitiateMigration(ctx context.Context, args jujuparams.
 	const op = errors.Op("jujuapi.InitiateMigration")
 
 	results := make([]jujuparams.InitiateMigrationResult, len(args.Specs))
+	errs := newBulkErrors(op, len(args.Specs))
 	for i, spec := range args.Specs {
 		result, err := r.jimm.InitiateMigration(ctx, r.user, spec)
 		if err != nil {
-			result.Error = mapError(errors.E(op, err))
+			result.Error = errs.add(i, spec.ModelTag, errors.E(op, err))
 		}
 		results[i] = result
 	}
+	errs.finish(ctx)
 
 	return jujuparams.InitiateMigrationResults{
 		Results: results,
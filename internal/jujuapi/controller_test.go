@@ -26,6 +26,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 	"github.com/canonical/jimm/v3/internal/jujuapi"
 	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmversion "github.com/canonical/jimm/v3/version"
 )
 
@@ -366,6 +367,21 @@ func TestInitiateMigration(t *testing.T) {
 			Results: []jujuparams.InitiateMigrationResult{{
 				Error: &jujuparams.Error{
 					Message: "a silly error",
+					Info: map[string]interface{}{
+						"index":     0,
+						"entity":    mt.String(),
+						"retryable": false,
+						"causes": []apiparams.ErrorCause{{
+							Op: "jujuapi.InitiateMigration",
+						}, {
+							Message: "a silly error",
+						}},
+						"summary": apiparams.BulkErrorSummary{
+							Total:  1,
+							Failed: 1,
+							Codes:  map[string]int{"": 1},
+						},
+					},
 				},
 			}},
 		},
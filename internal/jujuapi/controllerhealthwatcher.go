@@ -0,0 +1,116 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func init() {
+	facadeInit["ControllerHealthWatcher"] = func(r *controllerRoot) []int {
+		nextMethod := rpc.Method(r.ControllerHealthWatcherNext)
+		stopMethod := rpc.Method(r.ControllerHealthWatcherStop)
+
+		r.AddMethod("ControllerHealthWatcher", 1, "Next", nextMethod)
+		r.AddMethod("ControllerHealthWatcher", 1, "Stop", stopMethod)
+
+		return []int{1}
+	}
+}
+
+// ControllerHealthWatcherNext implements the Next method on the
+// ControllerHealthWatcher facade. The first call returns the state of
+// every controller immediately, subsequent calls block until the state
+// of a controller changes.
+func (r *controllerRoot) ControllerHealthWatcherNext(ctx context.Context, objID string) (apiparams.ControllerHealthWatcherNextResults, error) {
+	const op = errors.Op("jujuapi.ControllerHealthWatcherNext")
+
+	w, err := r.watchers.get(objID)
+	if err != nil {
+		return apiparams.ControllerHealthWatcherNextResults{}, errors.E(op, err)
+	}
+	chw, ok := w.(*controllerHealthWatcher)
+	if !ok {
+		return apiparams.ControllerHealthWatcherNextResults{}, errors.E(op, errors.CodeNotFound)
+	}
+	res, err := chw.Next(ctx)
+	if err != nil {
+		return apiparams.ControllerHealthWatcherNextResults{}, errors.E(op, err)
+	}
+	return res, nil
+}
+
+// ControllerHealthWatcherStop implements the Stop method on the
+// ControllerHealthWatcher facade.
+func (r *controllerRoot) ControllerHealthWatcherStop(ctx context.Context, objID string) error {
+	const op = errors.Op("jujuapi.ControllerHealthWatcherStop")
+
+	w, err := r.watchers.get(objID)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return w.Stop()
+}
+
+var (
+	defaultControllerHealthWatcherPeriod = 5 * time.Second
+)
+
+func newControllerHealthWatcher(period time.Duration, getStates func(context.Context) ([]apiparams.ControllerHealthState, error)) *controllerHealthWatcher {
+	return &controllerHealthWatcher{
+		getStates: getStates,
+		period:    period,
+		stopped:   make(chan struct{}),
+	}
+}
+
+// A controllerHealthWatcher polls the state of the controllers, returning
+// it from Next whenever it changes.
+type controllerHealthWatcher struct {
+	getStates func(context.Context) ([]apiparams.ControllerHealthState, error)
+	period    time.Duration
+	stopped   chan struct{}
+	stopOnce  sync.Once
+
+	mu   sync.Mutex
+	last []apiparams.ControllerHealthState
+	sent bool
+}
+
+func (w *controllerHealthWatcher) Next(ctx context.Context) (apiparams.ControllerHealthWatcherNextResults, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		states, err := w.getStates(ctx)
+		if err != nil {
+			return apiparams.ControllerHealthWatcherNextResults{}, err
+		}
+		if !w.sent || !reflect.DeepEqual(states, w.last) {
+			w.sent = true
+			w.last = states
+			return apiparams.ControllerHealthWatcherNextResults{Controllers: states}, nil
+		}
+		select {
+		case <-time.After(w.period):
+		case <-w.stopped:
+			return apiparams.ControllerHealthWatcherNextResults{}, errors.E(errors.CodeNotFound, "watcher stopped")
+		case <-ctx.Done():
+			return apiparams.ControllerHealthWatcherNextResults{}, ctx.Err()
+		}
+	}
+}
+
+func (w *controllerHealthWatcher) Stop() error {
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+	})
+	return nil
+}
@@ -4,13 +4,16 @@ package jujuapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
 	"github.com/go-macaroon-bakery/macaroon-bakery/v3/bakery"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
+	"github.com/juju/rpcreflect"
 	"github.com/rogpeppe/fastuuid"
 	"golang.org/x/oauth2"
 
@@ -18,12 +21,15 @@ import (
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimm/credentials"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
 	"github.com/canonical/jimm/v3/internal/pubsub"
+	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
@@ -36,12 +42,40 @@ type JIMM interface {
 	AddAuditLogEntry(ale *dbmodel.AuditLogEntry)
 	AddCloudToController(ctx context.Context, user *openfga.User, controllerName string, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
 	AddHostedCloud(ctx context.Context, user *openfga.User, tag names.CloudTag, cloud jujuparams.Cloud, force bool) error
+	AddModelACLTemplate(ctx context.Context, user *openfga.User, ownerGroup, grantGroup, access string) (apiparams.ModelACLTemplate, error)
+	AddModelDependency(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag, description string) (apiparams.ModelDependency, error)
+	AddModelPool(ctx context.Context, user *openfga.User, name string, cloud names.CloudTag, region string, credential names.CloudCredentialTag, size int, config map[string]interface{}) (apiparams.ModelPool, error)
+	AddNamespaceReservation(ctx context.Context, user *openfga.User, prefix, groupName string) error
 	AddServiceAccount(ctx context.Context, u *openfga.User, clientId string) error
+	AddServiceAccountToGroups(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error
+	AgentVersionReport(ctx context.Context, user *openfga.User) (apiparams.AgentVersionReport, error)
+	ApplyModelAccess(ctx context.Context, user *openfga.User, req apiparams.ApplyModelAccessRequest) (apiparams.ApplyModelAccessResponse, error)
+	ApproveAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) error
+	ArchiveAndDestroyModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (string, error)
+	AuditControllerAccess(ctx context.Context, user *openfga.User, controllerName string) (apiparams.AuditControllerAccessResponse, error)
+	CancelModelCreation(ctx context.Context, user *openfga.User, path string) error
+	CancelModelDestroy(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	ClearFaults(ctx context.Context, user *openfga.User) error
+	CompleteModelImport(ctx context.Context, user *openfga.User, id uint) (apiparams.ModelImport, error)
+	ConfirmIdentity(ctx context.Context, user *openfga.User, req apiparams.ConfirmIdentityRequest) (time.Time, error)
+	ControllerCall(ctx context.Context, user *openfga.User, controllerName, facade string, version int, method string, args json.RawMessage) (json.RawMessage, error)
 	CopyServiceAccountCredential(ctx context.Context, u *openfga.User, svcAcc *openfga.User, cloudCredentialTag names.CloudCredentialTag) (names.CloudCredentialTag, []jujuparams.UpdateCredentialModelResult, error)
 	CountIdentities(ctx context.Context, user *openfga.User) (int, error)
+	CreateModelToken(ctx context.Context, user *openfga.User, mt names.ModelTag, access, description string) (apiparams.ModelToken, string, error)
+	CrossModelRelationGraph(ctx context.Context, user *openfga.User, modelTag names.ModelTag) (apiparams.CrossModelRelationGraph, error)
+	DefaultCloud(ctx context.Context, user *openfga.User) (dbmodel.DomainDefaultCloud, error)
+	DenyAccessRequest(ctx context.Context, user *openfga.User, id uint, comment string) error
 	DestroyOffer(ctx context.Context, user *openfga.User, offerURL string, force bool) error
+	DryRunAddController(ctx context.Context, user *openfga.User, ctl *dbmodel.Controller) error
+	EndIdentitySession(ctx context.Context, s *dbmodel.IdentitySession) error
+	EnrolTOTP(ctx context.Context, user *openfga.User, replace bool) (apiparams.EnrolTOTPResponse, error)
+	ExportModel(ctx context.Context, user *openfga.User, modelRef string) (apiparams.ModelExport, error)
+	ExportModelAccess(ctx context.Context, user *openfga.User, req apiparams.ExportModelAccessRequest) (apiparams.ModelAccessDocument, error)
+	FreezeModel(ctx context.Context, user *openfga.User, mt names.ModelTag, until time.Time, reason string) (apiparams.ModelFreeze, error)
+	ExportModelBundle(ctx context.Context, user *openfga.User, mt names.ModelTag, opts jimm.BundleExportOptions) (string, error)
 	FindApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
 	FindAuditEvents(ctx context.Context, user *openfga.User, filter db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error)
+	FindOffers(ctx context.Context, user *openfga.User, req apiparams.FindOffersRequest) ([]apiparams.OfferDirectoryEntry, error)
 	ForEachCloud(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error
 	ForEachUserCloud(ctx context.Context, user *openfga.User, f func(*dbmodel.Cloud) error) error
 	ForEachUserCloudCredential(ctx context.Context, u *dbmodel.Identity, ct names.CloudTag, f func(cred *dbmodel.CloudCredential) error) error
@@ -51,39 +85,127 @@ type JIMM interface {
 	GetCloudCredential(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag) (*dbmodel.CloudCredential, error)
 	GetCloudCredentialAttributes(ctx context.Context, u *openfga.User, cred *dbmodel.CloudCredential, hidden bool) (attrs map[string]string, redacted []string, err error)
 	GetCredentialStore() credentials.CredentialStore
+	GetDeletedModel(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.DeletedModel, error)
 	GetJimmControllerAccess(ctx context.Context, user *openfga.User, tag names.UserTag) (string, error)
 	// FetchIdentity finds the user in jimm or returns a not-found error
 	FetchIdentity(ctx context.Context, username string) (*openfga.User, error)
+	GetOperation(ctx context.Context, user *openfga.User, id string) (apiparams.Operation, error)
+	GetModelSummary(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelSummary, error)
 	GetUserCloudAccess(ctx context.Context, user *openfga.User, cloud names.CloudTag) (string, error)
 	GetUserControllerAccess(ctx context.Context, user *openfga.User, controller names.ControllerTag) (string, error)
 	GetUserModelAccess(ctx context.Context, user *openfga.User, model names.ModelTag) (string, error)
+	GroupSyncStatus(ctx context.Context, user *openfga.User) (apiparams.GroupSyncStatusResponse, error)
 	GrantAuditLogAccess(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	GrantCloudAccess(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
+	GrantCloudCredentialAccess(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error
 	GrantModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
+	GrantModelGroupAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error
 	GrantOfferAccess(ctx context.Context, u *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) error
 	GrantServiceAccountAccess(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, tags []string) error
 	InitiateInternalMigration(ctx context.Context, user *openfga.User, modelTag names.ModelTag, targetController string) (jujuparams.InitiateMigrationResult, error)
 	InitiateMigration(ctx context.Context, user *openfga.User, spec jujuparams.MigrationSpec) (jujuparams.InitiateMigrationResult, error)
+	InjectFault(ctx context.Context, user *openfga.User, f faults.Fault) error
+	ListAccessRequests(ctx context.Context, user *openfga.User, status string) ([]apiparams.AccessRequest, error)
 	ListApplicationOffers(ctx context.Context, user *openfga.User, filters ...jujuparams.OfferFilter) ([]jujuparams.ApplicationOfferAdminDetailsV5, error)
+	ListControllerCertificates(ctx context.Context, user *openfga.User) (apiparams.ListControllerCertificatesResponse, error)
+	ListControllerModelCredentials(ctx context.Context, user *openfga.User) (apiparams.ListControllerModelCredentialsResponse, error)
+	ListCredentialPropagations(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, unconfirmed bool) ([]apiparams.CredentialPropagation, error)
+	ListDeletedModels(ctx context.Context, user *openfga.User) ([]apiparams.DeletedModel, error)
+	ListDomainDefaultClouds(ctx context.Context, user *openfga.User) ([]apiparams.DomainDefaultCloud, error)
+	ListFaults(ctx context.Context, user *openfga.User) ([]faults.Fault, error)
 	ListIdentities(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination) ([]openfga.User, error)
+	ListLeaders(ctx context.Context, user *openfga.User) ([]apiparams.LeaderInfo, error)
+	ListModelACLTemplates(ctx context.Context, user *openfga.User) ([]apiparams.ModelACLTemplate, error)
+	ListIdentitySessions(ctx context.Context, user *openfga.User, name string) (apiparams.ListIdentitySessionsResponse, error)
+	ListIdleModels(ctx context.Context, user *openfga.User, idleDays int) ([]apiparams.IdleModel, error)
+	ListModelMigrations(ctx context.Context, user *openfga.User, mt names.ModelTag, activeOnly bool) ([]apiparams.ModelMigration, error)
+	ListModelNetworkPolicies(ctx context.Context, user *openfga.User, unenforcedOnly bool) ([]apiparams.ModelNetworkPolicy, error)
+	ListModelPools(ctx context.Context, user *openfga.User) ([]apiparams.ModelPool, error)
+	ListModelTokens(ctx context.Context, user *openfga.User, mt names.ModelTag) ([]apiparams.ModelToken, error)
+	ListNamespaceReservations(ctx context.Context, user *openfga.User) ([]apiparams.NamespaceReservation, error)
+	ListOperations(ctx context.Context, user *openfga.User, all bool) ([]apiparams.Operation, error)
+	ListOutdatedCharms(ctx context.Context, user *openfga.User) ([]apiparams.OutdatedCharm, error)
+	ListPendingModelDestroys(ctx context.Context, user *openfga.User) ([]apiparams.PendingModelDestroy, error)
+	ListPlacementLatencies(ctx context.Context, user *openfga.User) (apiparams.PlacementLatencies, error)
 	ListResources(ctx context.Context, user *openfga.User, filter pagination.LimitOffsetPagination, namePrefixFilter, typeFilter string) ([]db.Resource, error)
+	ListScheduledJobs(ctx context.Context, user *openfga.User) ([]apiparams.ScheduledJob, error)
+	ListWebhookDeliveries(ctx context.Context, user *openfga.User, webhook string, limit int) ([]apiparams.WebhookDelivery, error)
+	MigrateModelExternal(ctx context.Context, user *openfga.User, req apiparams.MigrateModelExternalRequest) (apiparams.MigrateModelExternalResponse, error)
+	MigrationPrechecks(ctx context.Context, user *openfga.User, mt names.ModelTag, targetController string) (apiparams.MigrationPrecheckReport, error)
+	ModelControllerEndpoints(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelControllerEndpoints, error)
+	ModelDependencies(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelDependencies, error)
+	ModelMigrationStatus(ctx context.Context, user *openfga.User, mt names.ModelTag) (apiparams.ModelMigrationStatus, error)
+	ModelTimeline(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, limit int) (apiparams.ModelTimeline, error)
+	ModelResourceHistory(ctx context.Context, user *openfga.User, mt names.ModelTag, start, end time.Time, resolution string) (apiparams.ModelResourceHistory, error)
+	ModelsStatus(ctx context.Context, user *openfga.User, controllerName string) (*apiparams.ModelsStatusResponse, error)
+	NormalizeIdentities(ctx context.Context, user *openfga.User, dryRun bool) ([]apiparams.IdentityRename, error)
 	Offer(ctx context.Context, user *openfga.User, offer jimm.AddApplicationOfferParams) error
+	OfferConsumption(ctx context.Context, user *openfga.User, offerURL string) (apiparams.OfferConsumption, error)
+	PingControllers(ctx context.Context, user *openfga.User) (apiparams.PingControllersResponse, error)
+	PrepareModelImport(ctx context.Context, user *openfga.User, req apiparams.PrepareModelImportRequest) (apiparams.ModelImport, error)
+	ProvisionControllerServiceUser(ctx context.Context, user *openfga.User, controllerName string) (apiparams.ProvisionControllerServiceUserResponse, error)
 	PubSubHub() *pubsub.Hub
+	PurgeIdentity(ctx context.Context, user *openfga.User, name string, deleteAuditLog bool) (apiparams.PurgeIdentityResponse, error)
 	PurgeLogs(ctx context.Context, user *openfga.User, before time.Time) (int64, error)
+	RebalanceRecommendations(ctx context.Context, user *openfga.User, limit int) (apiparams.RebalanceReport, error)
+	RebindModelCredentials(ctx context.Context, user *openfga.User, departing names.UserTag, groupName string, dryRun bool) ([]apiparams.ModelCredentialRebind, error)
+	RecordIdentitySessionActivity(ctx context.Context, s *dbmodel.IdentitySession, t time.Time) error
+	ReloadTunables(ctx context.Context, user *openfga.User) ([]apiparams.TunableChange, error)
 	RemoveCloud(ctx context.Context, u *openfga.User, ct names.CloudTag) error
 	RemoveCloudFromController(ctx context.Context, u *openfga.User, controllerName string, ct names.CloudTag) error
 	RemoveController(ctx context.Context, user *openfga.User, controllerName string, force bool) error
+	RemoveDomainDefaultCloud(ctx context.Context, user *openfga.User, domain string) error
+	RemapCloudRegion(ctx context.Context, user *openfga.User, req apiparams.RemapCloudRegionRequest) (apiparams.RemapCloudRegionResponse, error)
+	RemoveModelACLTemplate(ctx context.Context, user *openfga.User, id uint) error
+	RemoveModelDependency(ctx context.Context, user *openfga.User, mt, dependsOn names.ModelTag) error
+	RemoveModelNetworkPolicy(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	RemoveModelPool(ctx context.Context, user *openfga.User, name string) error
+	RemoveModelPublicListing(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	RemoveNamespaceReservation(ctx context.Context, user *openfga.User, prefix string) error
+	RemoveOfferPublicListing(ctx context.Context, user *openfga.User, offerURL string) error
+	RemoveUserQuota(ctx context.Context, user *openfga.User, target names.UserTag) error
+	RemoveServiceAccountFromGroups(ctx context.Context, u *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error
+	RepairDenormalizedFields(ctx context.Context, user *openfga.User, req apiparams.RepairDenormalizedFieldsRequest) (apiparams.RepairDenormalizedFieldsResponse, error)
+	RequestAccess(ctx context.Context, user *openfga.User, target, access, reason string) (apiparams.AccessRequest, error)
+	ResolveModel(ctx context.Context, user *openfga.User, ref string) (names.ModelTag, error)
 	ResourceTag() names.ControllerTag
+	RestoreModel(ctx context.Context, user *openfga.User, mt names.ModelTag) error
+	ResyncModelAccess(ctx context.Context, user *openfga.User, req apiparams.ResyncModelAccessRequest) (apiparams.ResyncModelAccessResponse, error)
 	RevokeAuditLogAccess(ctx context.Context, user *openfga.User, targetUserTag names.UserTag) error
 	RevokeCloudAccess(ctx context.Context, user *openfga.User, ct names.CloudTag, ut names.UserTag, access string) error
 	RevokeCloudCredential(ctx context.Context, user *dbmodel.Identity, tag names.CloudCredentialTag, force bool) error
+	RevokeCloudCredentialAccess(ctx context.Context, user *openfga.User, tag names.CloudCredentialTag, entity string) error
 	RevokeModelAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, ut names.UserTag, access jujuparams.UserAccessPermission) error
+	RevokeModelGroupAccess(ctx context.Context, user *openfga.User, mt names.ModelTag, groupName string, access jujuparams.UserAccessPermission) error
+	RevokeModelToken(ctx context.Context, user *openfga.User, mt names.ModelTag, id uint) error
 	RevokeOfferAccess(ctx context.Context, user *openfga.User, offerURL string, ut names.UserTag, access jujuparams.OfferAccessPermission) (err error)
+	RotateCloudCredentials(ctx context.Context, user *openfga.User, req apiparams.RotateCloudCredentialsRequest) (apiparams.RotateCloudCredentialsResponse, error)
+	RotateControllerModelCredential(ctx context.Context, user *openfga.User, req apiparams.RotateControllerModelCredentialRequest) (apiparams.ControllerModelCredential, error)
+	RotateWebhookSecret(ctx context.Context, user *openfga.User, webhook string) (apiparams.WebhookSecret, error)
+	ScheduledJobHistory(ctx context.Context, user *openfga.User, name string, limit int) ([]apiparams.ScheduledJobRun, error)
+	SearchApplications(ctx context.Context, user *openfga.User, req apiparams.SearchApplicationsRequest) ([]apiparams.ApplicationSearchResult, error)
+	SetCostCenter(ctx context.Context, user *openfga.User, entity, costCenter string) error
+	SetDefaultCloudCredential(ctx context.Context, user *openfga.User, cloudTag names.CloudTag, credentialTag names.CloudCredentialTag) error
+	SetDomainDefaultCloud(ctx context.Context, user *openfga.User, domain, cloud, region string) error
+	SetModelNetworkPolicy(ctx context.Context, user *openfga.User, mt names.ModelTag, allowedEgress []string) (apiparams.ModelNetworkPolicy, error)
+	SetModelPublicListing(ctx context.Context, user *openfga.User, mt names.ModelTag, purpose, ownerGroup string) error
+	SetOfferConsumerLimit(ctx context.Context, user *openfga.User, offerURL string, limit int) error
+	SetOfferPublicListing(ctx context.Context, user *openfga.User, offerURL, purpose, ownerGroup string) error
+	SetScheduledJobEnabled(ctx context.Context, user *openfga.User, name string, enabled bool) error
+	SetUserQuota(ctx context.Context, user *openfga.User, target names.UserTag, req apiparams.SetUserQuotaRequest) error
+	StartIdentitySession(ctx context.Context, user *openfga.User, remoteAddress string) (*dbmodel.IdentitySession, error)
+	StoreOversizedResult(ctx context.Context, user *openfga.User, name, contentType string, data []byte) (string, error)
+	SyncGroups(ctx context.Context, user *openfga.User, req apiparams.SyncGroupsRequest) (apiparams.SyncGroupsResponse, error)
 	ToJAASTag(ctx context.Context, tag *ofganames.Tag, resolveUUIDs bool) (string, error)
+	TransferNamespaceReservation(ctx context.Context, user *openfga.User, prefix, groupName string) error
+	UnfreezeModel(ctx context.Context, user *openfga.User, mt names.ModelTag) error
 	UpdateApplicationOffer(ctx context.Context, controller *dbmodel.Controller, offerUUID string, removed bool) error
 	UpdateCloud(ctx context.Context, u *openfga.User, ct names.CloudTag, cloud jujuparams.Cloud) error
 	UpdateCloudCredential(ctx context.Context, u *openfga.User, args jimm.UpdateCloudCredentialArgs) ([]jujuparams.UpdateCredentialModelResult, error)
 	UserLogin(ctx context.Context, identityName string) (*openfga.User, error)
+	UsageReport(ctx context.Context, user *openfga.User) (apiparams.UsageReport, error)
+	UserQuota(ctx context.Context, user *openfga.User, target names.UserTag) (apiparams.UserQuota, error)
+	Whoami(ctx context.Context, user *openfga.User) (apiparams.IdentitySummary, error)
 }
 
 // controllerRoot is the root for endpoints served on controller connections.
@@ -94,6 +216,7 @@ type controllerRoot struct {
 	jimm     JIMM
 	watchers *watcherRegistry
 	pingF    func()
+	redactor modelRedactor
 
 	// mu protects the fields below it
 	mu                    sync.Mutex
@@ -112,17 +235,53 @@ type controllerRoot struct {
 
 	// identityId is the id of the identity attempting to login via a session cookie.
 	identityId string
+
+	// confirmedAt is the time the user last authenticated, as confirmed
+	// with ConfirmIdentity.
+	confirmedAt time.Time
+
+	// session is the session recorded for the logged in user, if any.
+	session *dbmodel.IdentitySession
+
+	// lastActivity is the time the user last made an API request.
+	lastActivity time.Time
+
+	// activityRecordedAt is the time the session's activity was last
+	// written to the database.
+	activityRecordedAt time.Time
+
+	// remoteAddress is the address the connection was made from.
+	remoteAddress string
+
+	// requestClass is the priority class of requests made on the
+	// connection, it is set when the user logs in.
+	requestClass jimmRPC.RequestClass
+
+	// readConsistency is the read consistency of requests made on the
+	// connection that do not specify one, it is set from the header of
+	// the request that opened the connection.
+	readConsistency apiparams.ReadConsistency
+
+	// adminMethodsDisabled is true if the administrative methods of the
+	// JIMM facade are not served on the connection, see
+	// ContextWithoutAdminMethods.
+	adminMethodsDisabled bool
+
+	// adminMethods holds the methods, as "<facade>.<method>", that only
+	// JIMM administrators may call, see addAdminMethod.
+	adminMethods map[string]bool
 }
 
 func newControllerRoot(j JIMM, p Params, identityId string) *controllerRoot {
 	watcherRegistry := &watcherRegistry{
-		watchers: make(map[string]*modelSummaryWatcher),
+		watchers: make(map[string]watcher),
 	}
 	r := &controllerRoot{
 		params:                p,
 		jimm:                  j,
 		watchers:              watcherRegistry,
 		pingF:                 func() {},
+		redactor:              newModelRedactor(p.RedactedModelFields, p.RedactedModelConfigKeys),
 		controllerUUIDMasking: true,
 		identityId:            identityId,
 	}
@@ -140,6 +299,71 @@ func newControllerRoot(j JIMM, p Params, identityId string) *controllerRoot {
 	return r
 }
 
+// withReadConsistency returns a context requesting the given read
+// consistency, or the connection's read consistency if c is empty.
+func (r *controllerRoot) withReadConsistency(ctx context.Context, c apiparams.ReadConsistency) context.Context {
+	if c == "" {
+		c = r.readConsistency
+	}
+	if c == "" {
+		return ctx
+	}
+	return jimm.ContextWithReadConsistency(ctx, c)
+}
+
+// FindMethod implements rpc.Root. Every request is counted in the facade
+// request metrics and, other than logins and pings, recorded as activity
+// in the user's session. The juju RPC protocol used by the controller API
+// has nowhere to put a deprecation warning, so deprecated facade versions
+// are only reported through the metrics. Requests subject to the request
+// concurrency limit wait for capacity before being processed. If the
+// administrative methods of the JIMM facade are disabled on the
+// connection calls to them are rejected. Identities authenticated by a
+// model token may only call the methods in modelTokenMethods.
+func (r *controllerRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	r.params.FacadeDeprecations.RecordRequest(rootName, version)
+	if rootName != "Admin" && rootName != "Pinger" {
+		r.recordActivity()
+	}
+	if r.adminMethodsDisabled && r.isAdminMethod(rootName, methodName) {
+		return nil, errors.E(errors.CodeForbidden, fmt.Sprintf("%s is only available on the admin listener", methodName))
+	}
+	if rootName != "Admin" && r.isModelToken() && !modelTokenMethods[rootName+"."+methodName] {
+		return nil, errors.E(errors.CodeForbidden, fmt.Sprintf("%s.%s is not available to model tokens", rootName, methodName))
+	}
+	mc, err := r.Root.FindMethod(rootName, version, methodName)
+	if err != nil || r.params.RequestLimiter == nil || !jimmRPC.RequestLimited(rootName, methodName) {
+		return mc, err
+	}
+	r.mu.Lock()
+	class := r.requestClass
+	r.mu.Unlock()
+	return limitedMethodCaller{
+		MethodCaller: mc,
+		limiter:      r.params.RequestLimiter,
+		class:        class,
+	}, nil
+}
+
+// limitedMethodCaller wraps an rpcreflect.MethodCaller so that calls
+// wait for capacity in the request limiter.
+type limitedMethodCaller struct {
+	rpcreflect.MethodCaller
+
+	limiter *jimmRPC.RequestLimiter
+	class   jimmRPC.RequestClass
+}
+
+// Call implements rpcreflect.MethodCaller.Call.
+func (c limitedMethodCaller) Call(ctx context.Context, objID string, arg reflect.Value) (reflect.Value, error) {
+	release, err := c.limiter.Acquire(ctx, c.class)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	defer release()
+	return c.MethodCaller.Call(ctx, objID, arg)
+}
+
 // masquarade allows a controller superuser to perform an action on behalf
 // of another user. masquarade checks that the authenticated user is a
 // controller user and that the requested is a valid JAAS user. If these
@@ -164,6 +388,28 @@ func (r *controllerRoot) masquerade(ctx context.Context, userTag string) (*openf
 	return user, nil
 }
 
+// requireConfirmation returns an error with the code
+// CodeConfirmationRequired if sensitive operations must be confirmed and
+// the user has not confirmed their identity, with ConfirmIdentity, within
+// the confirmation period.
+func (r *controllerRoot) requireConfirmation(operation string) error {
+	if r.params.ConfirmationPeriod <= 0 || r.confirmed() {
+		return nil
+	}
+	return errors.E(errors.CodeConfirmationRequired, fmt.Sprintf("%s requires confirmation, confirm your identity with ConfirmIdentity using a TOTP code or a session token issued in the last %s", operation, r.params.ConfirmationPeriod))
+}
+
+// confirmed reports whether the user has confirmed their identity within
+// the confirmation period.
+func (r *controllerRoot) confirmed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.confirmedAt.IsZero() {
+		return false
+	}
+	return r.params.ConfirmationPeriod <= 0 || time.Since(r.confirmedAt) < r.params.ConfirmationPeriod
+}
+
 // parseUserTag parses a names.UserTag and validates it is for an
 // identity-provider user.
 func parseUserTag(tag string) (names.UserTag, error) {
@@ -207,6 +453,14 @@ func (r *controllerRoot) newAuditLogger() jimm.DbAuditLogger {
 	return jimm.NewDbAuditLogger(r.jimm, r.getUser)
 }
 
+// isModelToken reports whether the currently logged in user is an
+// identity authenticated by a model token.
+func (r *controllerRoot) isModelToken() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.user != nil && dbmodel.IsModelTokenIdentity(r.user.Name)
+}
+
 // getUser implements jujuapi.root interface to return the currently logged in user.
 func (r *controllerRoot) getUser() names.UserTag {
 	r.mu.Lock()
@@ -4,12 +4,26 @@ package jujuapi_test
 
 import (
 	"context"
+	"encoding/json"
+	"testing"
+	"time"
 
+	qt "github.com/frankban/quicktest"
 	"github.com/juju/juju/api"
 	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version/v2"
 	gc "gopkg.in/check.v1"
+
+	"github.com/canonical/jimm/v3/internal/db"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jimmtest"
+	"github.com/canonical/jimm/v3/internal/jimmtest/mocks"
+	"github.com/canonical/jimm/v3/internal/jujuapi"
+	"github.com/canonical/jimm/v3/internal/openfga"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 type controllerrootSuite struct {
@@ -51,3 +65,128 @@ func (s *controllerrootSuite) TestUnimplementedRootFails(c *gc.C) {
 	err := conn.APICall("NoSuch", 1, "", "Method", nil, &resp)
 	c.Assert(err, gc.ErrorMatches, `no such request - method NoSuch\(1\).Method is not implemented \(not implemented\)`)
 }
+
+func TestSensitiveOperationConfirmation(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	var destroyed []string
+	var authTime time.Time
+	var replaced []bool
+	j := &jimmtest.JIMM{
+		ModelManager: mocks.ModelManager{
+			DestroyModel_: func(_ context.Context, _ *openfga.User, mt names.ModelTag, _, _ *bool, _, _ *time.Duration) error {
+				destroyed = append(destroyed, mt.Id())
+				return nil
+			},
+		},
+		FindAuditEvents_: func(context.Context, *openfga.User, db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error) {
+			return nil, nil
+		},
+		ConfirmIdentity_: func(context.Context, *openfga.User, apiparams.ConfirmIdentityRequest) (time.Time, error) {
+			return authTime, nil
+		},
+		EnrolTOTP_: func(_ context.Context, _ *openfga.User, replace bool) (apiparams.EnrolTOTPResponse, error) {
+			replaced = append(replaced, replace)
+			if !replace {
+				return apiparams.EnrolTOTPResponse{}, errors.E(errors.CodeAlreadyExists)
+			}
+			return apiparams.EnrolTOTPResponse{Secret: "SECRET"}, nil
+		},
+		ControllerCall_: func(context.Context, *openfga.User, string, string, int, string, json.RawMessage) (json.RawMessage, error) {
+			return json.RawMessage(`{}`), nil
+		},
+	}
+	cr := jujuapi.NewControllerRoot(j, jujuapi.Params{ConfirmationPeriod: time.Hour})
+	jujuapi.SetUser(cr, openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil))
+
+	force := true
+	mt1 := names.NewModelTag("00000002-0000-0000-0000-000000000001")
+	mt2 := names.NewModelTag("00000002-0000-0000-0000-000000000002")
+	destroy := func() jujuparams.ErrorResults {
+		res, err := cr.DestroyModels(ctx, jujuparams.DestroyModelsParams{
+			Models: []jujuparams.DestroyModelParams{{
+				ModelTag: mt1.String(),
+				Force:    &force,
+			}, {
+				ModelTag: mt2.String(),
+			}},
+		})
+		c.Assert(err, qt.IsNil)
+		return res
+	}
+
+	// Without confirmation sensitive operations are challenged, others
+	// proceed.
+	res := destroy()
+	c.Check(res.Results[0].Error, qt.Not(qt.IsNil))
+	c.Check(res.Results[0].Error.Code, qt.Equals, string(errors.CodeConfirmationRequired))
+	c.Check(res.Results[1].Error, qt.IsNil)
+	c.Check(destroyed, qt.DeepEquals, []string{mt2.Id()})
+	_, err := cr.FindAuditEvents(ctx, apiparams.FindAuditEventsRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConfirmationRequired)
+	c.Check(err, qt.ErrorMatches, `reading the audit log requires confirmation, .* in the last 1h0m0s`)
+	_, err = cr.RemoveController(ctx, apiparams.RemoveControllerRequest{Name: "controller-1"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConfirmationRequired)
+	_, err = cr.EnrolTOTP(ctx)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConfirmationRequired)
+	c.Check(err, qt.ErrorMatches, `enrolling a TOTP authenticator requires confirmation, .*`)
+	configSet := apiparams.ControllerCallRequest{
+		Controller: "controller-1",
+		Facade:     "Controller",
+		Version:    11,
+		Method:     "ConfigSet",
+		Params:     json.RawMessage(`{"config":{"max-debug-log-duration":"24h"}}`),
+	}
+	_, err = cr.ControllerCall(ctx, configSet)
+	c.Check(err, qt.ErrorMatches, `setting controller config requires confirmation, .*`)
+	_, err = cr.ControllerCall(ctx, apiparams.ControllerCallRequest{
+		Controller: "controller-1",
+		Facade:     "Controller",
+		Version:    11,
+		Method:     "ControllerConfig",
+	})
+	c.Check(err, qt.IsNil)
+
+	// A stale authentication does not confirm the identity.
+	authTime = time.Now().Add(-2 * time.Hour)
+	_, err = cr.ConfirmIdentity(ctx, apiparams.ConfirmIdentityRequest{SessionToken: "token"})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConfirmationRequired)
+	_, err = cr.FindAuditEvents(ctx, apiparams.FindAuditEventsRequest{})
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConfirmationRequired)
+
+	authTime = time.Now().Add(-time.Minute)
+	resp, err := cr.ConfirmIdentity(ctx, apiparams.ConfirmIdentityRequest{SessionToken: "token"})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.ConfirmedAt.Equal(authTime), qt.IsTrue)
+	c.Assert(resp.Expires, qt.Not(qt.IsNil))
+	c.Check(resp.Expires.Equal(authTime.Add(time.Hour)), qt.IsTrue)
+
+	destroyed = nil
+	res = destroy()
+	c.Check(res.Results[0].Error, qt.IsNil)
+	c.Check(destroyed, qt.DeepEquals, []string{mt1.Id(), mt2.Id()})
+	_, err = cr.FindAuditEvents(ctx, apiparams.FindAuditEventsRequest{})
+	c.Check(err, qt.IsNil)
+	enrolment, err := cr.EnrolTOTP(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(enrolment.Secret, qt.Equals, "SECRET")
+	c.Check(replaced, qt.DeepEquals, []bool{true})
+	_, err = cr.ControllerCall(ctx, configSet)
+	c.Check(err, qt.IsNil)
+}
+
+func TestSensitiveOperationConfirmationDisabled(t *testing.T) {
+	c := qt.New(t)
+
+	j := &jimmtest.JIMM{
+		FindAuditEvents_: func(context.Context, *openfga.User, db.AuditLogFilter) ([]dbmodel.AuditLogEntry, error) {
+			return nil, nil
+		},
+	}
+	cr := jujuapi.NewControllerRoot(j, jujuapi.Params{})
+	jujuapi.SetUser(cr, openfga.NewUser(&dbmodel.Identity{Name: "alice@canonical.com"}, nil))
+
+	_, err := cr.FindAuditEvents(context.Background(), apiparams.FindAuditEventsRequest{})
+	c.Check(err, qt.IsNil)
+}
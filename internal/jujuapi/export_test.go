@@ -4,6 +4,8 @@ package jujuapi
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
 
@@ -21,6 +23,26 @@ var (
 	AuditLogUpperLimit    = maxLimit
 )
 
+func RedactModelInfo(fields []string, mi *jujuparams.ModelInfo, access string) {
+	newModelRedactor(fields, []string{}).redactModelInfo(mi, access)
+}
+
+func RedactModelInfoConfig(configKeys []string, mi *jujuparams.ModelInfo, access string) {
+	newModelRedactor([]string{}, configKeys).redactModelInfo(mi, access)
+}
+
+func RedactModelGetResponse(configKeys []string, response json.RawMessage, access string) (json.RawMessage, error) {
+	return newModelRedactor([]string{}, configKeys).redactModelGetResponse(response, access)
+}
+
+func FanOut[T any](ctx context.Context, n int, softDeadline time.Duration, completeInBackground bool, f func(context.Context, int) (T, error)) ([]T, []error) {
+	return fanOut(ctx, n, fanOutParams{softDeadline: softDeadline, completeInBackground: completeInBackground}, f)
+}
+
+func RedactModelSummary(fields []string, ms *jujuparams.ModelSummary) {
+	newModelRedactor(fields, []string{}).redactModelSummary(ms)
+}
+
 func NewModelSummaryWatcher() *modelSummaryWatcher {
 	return &modelSummaryWatcher{
 		summaries: make(map[string]jujuparams.ModelAbstract),
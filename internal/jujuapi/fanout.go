@@ -0,0 +1,125 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// errControllerTimedOut is the error returned for any fan-out entry that
+// has not completed by the soft deadline.
+var errControllerTimedOut = errors.E("controller timed out")
+
+// fanOutParams holds the parameters for a fanOut call.
+type fanOutParams struct {
+	// softDeadline is the time after which fanOut returns, with any
+	// entries that have not completed failing with a "controller timed
+	// out" error. If this is zero fanOut waits for all entries to
+	// complete, or for the context to be canceled.
+	softDeadline time.Duration
+
+	// completeInBackground determines whether entries still running at
+	// the soft deadline are left to complete in the background, rather
+	// than being canceled. This allows slow controllers to populate any
+	// caches, such as the dialer's connection cache, for the benefit of
+	// later requests. Background entries are canceled after
+	// requestTimeout.
+	completeInBackground bool
+}
+
+// fanOut calls f for each of the n entries in parallel, running at most
+// maxRequestConcurrency at a time, and returns the value and error from
+// each call. If some entries take longer than the configured soft
+// deadline the results from the completed entries are returned and the
+// remaining entries have an error of errControllerTimedOut.
+func fanOut[T any](ctx context.Context, n int, p fanOutParams, f func(ctx context.Context, i int) (T, error)) ([]T, []error) {
+	values := make([]T, n)
+	errs := make([]error, n)
+	if n == 0 {
+		return values, errs
+	}
+
+	var workCtx context.Context
+	var cancel context.CancelFunc
+	if p.completeInBackground {
+		workCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), requestTimeout)
+	} else {
+		workCtx, cancel = context.WithCancel(ctx)
+	}
+
+	type result struct {
+		i   int
+		v   T
+		err error
+	}
+	// The channels are buffered so that entries that complete after
+	// fanOut has returned never block.
+	results := make(chan result, n)
+	sem := make(chan struct{}, maxRequestConcurrency)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			select {
+			case sem <- struct{}{}:
+			case <-workCtx.Done():
+				results <- result{i: i, err: workCtx.Err()}
+				return
+			}
+			defer func() { <-sem }()
+			v, err := f(workCtx, i)
+			results <- result{i: i, v: v, err: err}
+		}(i)
+	}
+
+	var deadline <-chan time.Time
+	if p.softDeadline > 0 {
+		t := time.NewTimer(p.softDeadline)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	done := make([]bool, n)
+	remaining := n
+wait:
+	for remaining > 0 {
+		select {
+		case r := <-results:
+			values[r.i], errs[r.i] = r.v, r.err
+			done[r.i] = true
+			remaining--
+		case <-deadline:
+			break wait
+		case <-ctx.Done():
+			break wait
+		}
+	}
+	if remaining == 0 {
+		cancel()
+		return values, errs
+	}
+
+	for i := range done {
+		if !done[i] {
+			errs[i] = errControllerTimedOut
+		}
+	}
+	zapctx.Warn(ctx, "fan-out returning partial results", zap.Int("timed-out", remaining), zap.Int("total", n))
+	if !p.completeInBackground {
+		cancel()
+		return values, errs
+	}
+	go func() {
+		defer cancel()
+		for ; remaining > 0; remaining-- {
+			if r := <-results; r.err != nil {
+				zapctx.Debug(workCtx, "background fan-out entry failed", zap.Error(r.err))
+			}
+		}
+	}()
+	return values, errs
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Canonical.
+
+package jujuapi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/jujuapi"
+)
+
+func TestFanOut(t *testing.T) {
+	c := qt.New(t)
+
+	values, errs := jujuapi.FanOut(context.Background(), 3, 0, false, func(_ context.Context, i int) (int, error) {
+		if i == 1 {
+			return 0, errors.New("test error")
+		}
+		return i * 10, nil
+	})
+	c.Check(values, qt.DeepEquals, []int{0, 0, 20})
+	c.Check(errs[0], qt.IsNil)
+	c.Check(errs[1], qt.ErrorMatches, "test error")
+	c.Check(errs[2], qt.IsNil)
+}
+
+func TestFanOutSoftDeadline(t *testing.T) {
+	c := qt.New(t)
+
+	for _, background := range []bool{false, true} {
+		release := make(chan struct{})
+		stragglerErr := make(chan error, 1)
+		values, errs := jujuapi.FanOut(context.Background(), 2, 100*time.Millisecond, background, func(ctx context.Context, i int) (string, error) {
+			if i == 0 {
+				return "fast", nil
+			}
+			select {
+			case <-release:
+				stragglerErr <- nil
+			case <-ctx.Done():
+				stragglerErr <- ctx.Err()
+			}
+			return "slow", nil
+		})
+		c.Check(values, qt.DeepEquals, []string{"fast", ""})
+		c.Check(errs[0], qt.IsNil)
+		c.Check(errs[1], qt.ErrorMatches, "controller timed out")
+
+		// Stragglers are canceled unless they are completing in the
+		// background.
+		if background {
+			close(release)
+		}
+		select {
+		case err := <-stragglerErr:
+			if background {
+				c.Check(err, qt.IsNil)
+			} else {
+				c.Check(err, qt.Equals, context.Canceled)
+			}
+		case <-time.After(5 * time.Second):
+			c.Fatal("straggler did not complete")
+		}
+	}
+}
+
+func TestFanOutCanceled(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, errs := jujuapi.FanOut(ctx, 1, 0, false, func(ctx context.Context, _ int) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	c.Check(errs[0], qt.Not(qt.IsNil))
+}
@@ -18,8 +18,11 @@ import (
 	"github.com/canonical/jimm/v3/internal/db"
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
+	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
 	"github.com/canonical/jimm/v3/pkg/api/params"
 	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 	"github.com/canonical/jimm/v3/version"
@@ -33,10 +36,18 @@ func init() {
 		grantAuditLogAccessMethod := rpc.Method(r.GrantAuditLogAccess)
 		importModelMethod := rpc.Method(r.ImportModel)
 		listControllersMethod := rpc.Method(r.ListControllers)
+		watchControllerHealthMethod := rpc.Method(r.WatchControllerHealth)
 		removeControllerMethod := rpc.Method(r.RemoveController)
 		revokeAuditLogAccessMethod := rpc.Method(r.RevokeAuditLogAccess)
 		setControllerDeprecatedMethod := rpc.Method(r.SetControllerDeprecated)
+		getControllerBootstrapProfileMethod := rpc.Method(r.GetControllerBootstrapProfile)
+		setControllerMaintenanceMethod := rpc.Method(r.SetControllerMaintenance)
+		setControllerTiersMethod := rpc.Method(r.SetControllerTiers)
+		setControllerTimeoutsMethod := rpc.Method(r.SetControllerTimeouts)
 		fullModelStatusMethod := rpc.Method(r.FullModelStatus)
+		destroyModelsDryRunMethod := rpc.Method(r.DestroyModelsDryRun)
+		modelMachinesMethod := rpc.Method(r.ModelMachines)
+		modelsStatusMethod := rpc.Method(r.ModelsStatus)
 		updateMigratedModelMethod := rpc.Method(r.UpdateMigratedModel)
 		addCloudToControllerMethod := rpc.Method(r.AddCloudToController)
 		removeCloudFromControllerMethod := rpc.Method(r.RemoveCloudFromController)
@@ -50,50 +61,284 @@ func init() {
 		checkRelationMethod := rpc.Method(r.CheckRelation)
 		listRelationshipTuplesMethod := rpc.Method(r.ListRelationshipTuples)
 		crossModelQueryMethod := rpc.Method(r.CrossModelQuery)
+		crossModelRelationGraphMethod := rpc.Method(r.CrossModelRelationGraph)
+		findOffersMethod := rpc.Method(r.FindOffers)
+		searchApplicationsMethod := rpc.Method(r.SearchApplications)
+		modelTimelineMethod := rpc.Method(r.ModelTimeline)
+		modelResourceHistoryMethod := rpc.Method(r.ModelResourceHistory)
+		exportModelBundleMethod := rpc.Method(r.ExportModelBundle)
+		userQuotaMethod := rpc.Method(r.UserQuota)
+		setUserQuotaMethod := rpc.Method(r.SetUserQuota)
+		removeUserQuotaMethod := rpc.Method(r.RemoveUserQuota)
+		setPublicListingMethod := rpc.Method(r.SetPublicListing)
+		removePublicListingMethod := rpc.Method(r.RemovePublicListing)
+		setOfferConsumerLimitMethod := rpc.Method(r.SetOfferConsumerLimit)
+		offerConsumptionMethod := rpc.Method(r.OfferConsumption)
+		setCostCenterMethod := rpc.Method(r.SetCostCenter)
+		usageReportMethod := rpc.Method(r.UsageReport)
+		whoamiMethod := rpc.Method(r.Whoami)
+		addNamespaceReservationMethod := rpc.Method(r.AddNamespaceReservation)
+		listNamespaceReservationsMethod := rpc.Method(r.ListNamespaceReservations)
+		transferNamespaceReservationMethod := rpc.Method(r.TransferNamespaceReservation)
+		removeNamespaceReservationMethod := rpc.Method(r.RemoveNamespaceReservation)
+		addModelACLTemplateMethod := rpc.Method(r.AddModelACLTemplate)
+		listModelACLTemplatesMethod := rpc.Method(r.ListModelACLTemplates)
+		removeModelACLTemplateMethod := rpc.Method(r.RemoveModelACLTemplate)
+		createModelTokenMethod := rpc.Method(r.CreateModelToken)
+		listModelTokensMethod := rpc.Method(r.ListModelTokens)
+		revokeModelTokenMethod := rpc.Method(r.RevokeModelToken)
+		freezeModelMethod := rpc.Method(r.FreezeModel)
+		unfreezeModelMethod := rpc.Method(r.UnfreezeModel)
+		setModelNetworkPolicyMethod := rpc.Method(r.SetModelNetworkPolicy)
+		removeModelNetworkPolicyMethod := rpc.Method(r.RemoveModelNetworkPolicy)
+		listModelNetworkPoliciesMethod := rpc.Method(r.ListModelNetworkPolicies)
+		addModelDependencyMethod := rpc.Method(r.AddModelDependency)
+		removeModelDependencyMethod := rpc.Method(r.RemoveModelDependency)
+		modelDependenciesMethod := rpc.Method(r.ModelDependencies)
+		remapCloudRegionMethod := rpc.Method(r.RemapCloudRegion)
+		rotateWebhookSecretMethod := rpc.Method(r.RotateWebhookSecret)
+		listWebhookDeliveriesMethod := rpc.Method(r.ListWebhookDeliveries)
+		exportModelMethod := rpc.Method(r.ExportModel)
+		prepareModelImportMethod := rpc.Method(r.PrepareModelImport)
+		migrateModelExternalMethod := rpc.Method(r.MigrateModelExternal)
+		completeModelImportMethod := rpc.Method(r.CompleteModelImport)
+		repairDenormalizedFieldsMethod := rpc.Method(r.RepairDenormalizedFields)
+		provisionControllerServiceUserMethod := rpc.Method(r.ProvisionControllerServiceUser)
+		getOperationMethod := rpc.Method(r.GetOperation)
+		listOperationsMethod := rpc.Method(r.ListOperations)
+		watchOperationMethod := rpc.Method(r.WatchOperation)
+		addModelPoolMethod := rpc.Method(r.AddModelPool)
+		removeModelPoolMethod := rpc.Method(r.RemoveModelPool)
+		listModelPoolsMethod := rpc.Method(r.ListModelPools)
+		listIdleModelsMethod := rpc.Method(r.ListIdleModels)
+		archiveAndDestroyModelMethod := rpc.Method(r.ArchiveAndDestroyModel)
+		requestAccessMethod := rpc.Method(r.RequestAccess)
+		listAccessRequestsMethod := rpc.Method(r.ListAccessRequests)
+		approveAccessRequestMethod := rpc.Method(r.ApproveAccessRequest)
+		denyAccessRequestMethod := rpc.Method(r.DenyAccessRequest)
 		purgeLogsMethod := rpc.Method(r.PurgeLogs)
+		resyncModelAccessMethod := rpc.Method(r.ResyncModelAccess)
+		auditControllerAccessMethod := rpc.Method(r.AuditControllerAccess)
 		migrateModel := rpc.Method(r.MigrateModel)
+		migrationPrechecksMethod := rpc.Method(r.MigrationPrechecks)
+		modelMigrationStatusMethod := rpc.Method(r.ModelMigrationStatus)
+		listModelMigrationsMethod := rpc.Method(r.ListModelMigrations)
+		rebalanceRecommendationsMethod := rpc.Method(r.RebalanceRecommendations)
+		rebindModelCredentialsMethod := rpc.Method(r.RebindModelCredentials)
+		listCredentialPropagationsMethod := rpc.Method(r.ListCredentialPropagations)
+		controllerCallMethod := rpc.Method(r.ControllerCall)
+		cancelModelCreationMethod := rpc.Method(r.CancelModelCreation)
+		cancelModelDestroyMethod := rpc.Method(r.CancelModelDestroy)
+		listPendingModelDestroysMethod := rpc.Method(r.ListPendingModelDestroys)
+		listDeletedModelsMethod := rpc.Method(r.ListDeletedModels)
+		getDeletedModelMethod := rpc.Method(r.GetDeletedModel)
+		restoreModelMethod := rpc.Method(r.RestoreModel)
+		listLeadersMethod := rpc.Method(r.ListLeaders)
+		listScheduledJobsMethod := rpc.Method(r.ListScheduledJobs)
+		scheduledJobHistoryMethod := rpc.Method(r.ScheduledJobHistory)
+		setScheduledJobEnabledMethod := rpc.Method(r.SetScheduledJobEnabled)
+		pingControllersMethod := rpc.Method(r.PingControllers)
+		listOutdatedCharmsMethod := rpc.Method(r.ListOutdatedCharms)
+		listPlacementLatenciesMethod := rpc.Method(r.ListPlacementLatencies)
+		setDomainDefaultCloudMethod := rpc.Method(r.SetDomainDefaultCloud)
+		removeDomainDefaultCloudMethod := rpc.Method(r.RemoveDomainDefaultCloud)
+		listDomainDefaultCloudsMethod := rpc.Method(r.ListDomainDefaultClouds)
+		purgeIdentityMethod := rpc.Method(r.PurgeIdentity)
+		getModelSummaryMethod := rpc.Method(r.GetModelSummary)
+		rotateCloudCredentialsMethod := rpc.Method(r.RotateCloudCredentials)
+		modelControllerEndpointsMethod := rpc.Method(r.ModelControllerEndpoints)
+		exportModelAccessMethod := rpc.Method(r.ExportModelAccess)
+		applyModelAccessMethod := rpc.Method(r.ApplyModelAccess)
+		agentVersionReportMethod := rpc.Method(r.AgentVersionReport)
+		normalizeIdentitiesMethod := rpc.Method(r.NormalizeIdentities)
+		listIdentitySessionsMethod := rpc.Method(r.ListIdentitySessions)
+		reloadTunablesMethod := rpc.Method(r.ReloadTunables)
+		listControllerModelCredentialsMethod := rpc.Method(r.ListControllerModelCredentials)
+		listControllerCertificatesMethod := rpc.Method(r.ListControllerCertificates)
+		rotateControllerModelCredentialMethod := rpc.Method(r.RotateControllerModelCredential)
+		syncGroupsMethod := rpc.Method(r.SyncGroups)
+		groupSyncStatusMethod := rpc.Method(r.GroupSyncStatus)
+		grantCloudCredentialAccessMethod := rpc.Method(r.GrantCloudCredentialAccess)
+		revokeCloudCredentialAccessMethod := rpc.Method(r.RevokeCloudCredentialAccess)
+		setDefaultCloudCredentialMethod := rpc.Method(r.SetDefaultCloudCredential)
 		addServiceAccountMethod := rpc.Method(r.AddServiceAccount)
 		copyServiceAccountCredentialMethod := rpc.Method(r.CopyServiceAccountCredential)
 		updateServiceAccountCredentials := rpc.Method(r.UpdateServiceAccountCredentials)
 		listServiceAccountCredentials := rpc.Method(r.ListServiceAccountCredentials)
 		grantServiceAccountAccess := rpc.Method(r.GrantServiceAccountAccess)
+		addServiceAccountToGroups := rpc.Method(r.AddServiceAccountToGroups)
+		removeServiceAccountFromGroups := rpc.Method(r.RemoveServiceAccountFromGroups)
 		version := rpc.Method(r.Version)
+		enrolTOTPMethod := rpc.Method(r.EnrolTOTP)
+		confirmIdentityMethod := rpc.Method(r.ConfirmIdentity)
+		injectFaultMethod := rpc.Method(r.InjectFault)
+		clearFaultsMethod := rpc.Method(r.ClearFaults)
+		listFaultsMethod := rpc.Method(r.ListFaults)
 
 		// JIMM Generic RPC
-		r.AddMethod("JIMM", 4, "AddController", addControllerMethod)
-		r.AddMethod("JIMM", 4, "DisableControllerUUIDMasking", disableControllerUUIDMaskingMethod)
+		r.addAdminMethod("JIMM", 4, "AddController", addControllerMethod)
+		r.addAdminMethod("JIMM", 4, "DisableControllerUUIDMasking", disableControllerUUIDMaskingMethod)
 		r.AddMethod("JIMM", 4, "FindAuditEvents", findAuditEventsMethod)
-		r.AddMethod("JIMM", 4, "FullModelStatus", fullModelStatusMethod)
-		r.AddMethod("JIMM", 4, "GrantAuditLogAccess", grantAuditLogAccessMethod)
-		r.AddMethod("JIMM", 4, "ImportModel", importModelMethod)
+		r.addAdminMethod("JIMM", 4, "FullModelStatus", fullModelStatusMethod)
+		r.AddMethod("JIMM", 4, "DestroyModelsDryRun", destroyModelsDryRunMethod)
+		r.AddMethod("JIMM", 4, "ModelMachines", modelMachinesMethod)
+		r.addAdminMethod("JIMM", 4, "ModelsStatus", modelsStatusMethod)
+		r.addAdminMethod("JIMM", 4, "GrantAuditLogAccess", grantAuditLogAccessMethod)
+		r.addAdminMethod("JIMM", 4, "ImportModel", importModelMethod)
 		r.AddMethod("JIMM", 4, "ListControllers", listControllersMethod)
-		r.AddMethod("JIMM", 4, "RemoveController", removeControllerMethod)
-		r.AddMethod("JIMM", 4, "RevokeAuditLogAccess", revokeAuditLogAccessMethod)
-		r.AddMethod("JIMM", 4, "SetControllerDeprecated", setControllerDeprecatedMethod)
-		r.AddMethod("JIMM", 4, "UpdateMigratedModel", updateMigratedModelMethod)
-		r.AddMethod("JIMM", 4, "AddCloudToController", addCloudToControllerMethod)
+		r.addAdminMethod("JIMM", 4, "WatchControllerHealth", watchControllerHealthMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveController", removeControllerMethod)
+		r.addAdminMethod("JIMM", 4, "RevokeAuditLogAccess", revokeAuditLogAccessMethod)
+		r.addAdminMethod("JIMM", 4, "SetControllerDeprecated", setControllerDeprecatedMethod)
+		r.addAdminMethod("JIMM", 4, "GetControllerBootstrapProfile", getControllerBootstrapProfileMethod)
+		r.addAdminMethod("JIMM", 4, "SetControllerMaintenance", setControllerMaintenanceMethod)
+		r.addAdminMethod("JIMM", 4, "SetControllerTiers", setControllerTiersMethod)
+		r.addAdminMethod("JIMM", 4, "SetControllerTimeouts", setControllerTimeoutsMethod)
+		r.addAdminMethod("JIMM", 4, "UpdateMigratedModel", updateMigratedModelMethod)
+		r.addAdminMethod("JIMM", 4, "AddCloudToController", addCloudToControllerMethod)
 		r.AddMethod("JIMM", 4, "RemoveCloudFromController", removeCloudFromControllerMethod)
-		r.AddMethod("JIMM", 4, "PurgeLogs", purgeLogsMethod)
+		r.addAdminMethod("JIMM", 4, "PurgeLogs", purgeLogsMethod)
+		r.addAdminMethod("JIMM", 4, "ResyncModelAccess", resyncModelAccessMethod)
+		r.addAdminMethod("JIMM", 4, "AuditControllerAccess", auditControllerAccessMethod)
 		r.AddMethod("JIMM", 4, "MigrateModel", migrateModel)
+		r.AddMethod("JIMM", 4, "MigrationPrechecks", migrationPrechecksMethod)
+		r.AddMethod("JIMM", 4, "ModelMigrationStatus", modelMigrationStatusMethod)
+		r.addAdminMethod("JIMM", 4, "ListModelMigrations", listModelMigrationsMethod)
+		r.addAdminMethod("JIMM", 4, "RebalanceRecommendations", rebalanceRecommendationsMethod)
+		r.addAdminMethod("JIMM", 4, "RebindModelCredentials", rebindModelCredentialsMethod)
+		r.AddMethod("JIMM", 4, "ListCredentialPropagations", listCredentialPropagationsMethod)
+		r.addAdminMethod("JIMM", 4, "ControllerCall", controllerCallMethod)
+		r.AddMethod("JIMM", 4, "CancelModelCreation", cancelModelCreationMethod)
+		r.AddMethod("JIMM", 4, "CancelModelDestroy", cancelModelDestroyMethod)
+		r.AddMethod("JIMM", 4, "ListPendingModelDestroys", listPendingModelDestroysMethod)
+		r.AddMethod("JIMM", 4, "ListDeletedModels", listDeletedModelsMethod)
+		r.AddMethod("JIMM", 4, "GetDeletedModel", getDeletedModelMethod)
+		r.addAdminMethod("JIMM", 4, "RestoreModel", restoreModelMethod)
+		r.addAdminMethod("JIMM", 4, "ListLeaders", listLeadersMethod)
+		r.addAdminMethod("JIMM", 4, "ListScheduledJobs", listScheduledJobsMethod)
+		r.addAdminMethod("JIMM", 4, "ScheduledJobHistory", scheduledJobHistoryMethod)
+		r.addAdminMethod("JIMM", 4, "SetScheduledJobEnabled", setScheduledJobEnabledMethod)
+		r.addAdminMethod("JIMM", 4, "PingControllers", pingControllersMethod)
+		r.addAdminMethod("JIMM", 4, "ListOutdatedCharms", listOutdatedCharmsMethod)
+		r.addAdminMethod("JIMM", 4, "ListPlacementLatencies", listPlacementLatenciesMethod)
+		r.addAdminMethod("JIMM", 4, "SetDomainDefaultCloud", setDomainDefaultCloudMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveDomainDefaultCloud", removeDomainDefaultCloudMethod)
+		r.addAdminMethod("JIMM", 4, "ListDomainDefaultClouds", listDomainDefaultCloudsMethod)
+		r.addAdminMethod("JIMM", 4, "PurgeIdentity", purgeIdentityMethod)
+		r.AddMethod("JIMM", 4, "GetModelSummary", getModelSummaryMethod)
+		r.addAdminMethod("JIMM", 4, "RotateCloudCredentials", rotateCloudCredentialsMethod)
+		r.AddMethod("JIMM", 4, "ModelControllerEndpoints", modelControllerEndpointsMethod)
+		r.AddMethod("JIMM", 4, "ExportModelAccess", exportModelAccessMethod)
+		r.AddMethod("JIMM", 4, "ApplyModelAccess", applyModelAccessMethod)
+		r.addAdminMethod("JIMM", 4, "AgentVersionReport", agentVersionReportMethod)
+		r.addAdminMethod("JIMM", 4, "NormalizeIdentities", normalizeIdentitiesMethod)
+		r.AddMethod("JIMM", 4, "ListIdentitySessions", listIdentitySessionsMethod)
+		r.addAdminMethod("JIMM", 4, "ReloadTunables", reloadTunablesMethod)
+		r.addAdminMethod("JIMM", 4, "ListControllerModelCredentials", listControllerModelCredentialsMethod)
+		r.addAdminMethod("JIMM", 4, "ListControllerCertificates", listControllerCertificatesMethod)
+		r.addAdminMethod("JIMM", 4, "RotateControllerModelCredential", rotateControllerModelCredentialMethod)
+		r.addAdminMethod("JIMM", 4, "SyncGroups", syncGroupsMethod)
+		r.addAdminMethod("JIMM", 4, "GroupSyncStatus", groupSyncStatusMethod)
+		r.AddMethod("JIMM", 4, "GrantCloudCredentialAccess", grantCloudCredentialAccessMethod)
+		r.AddMethod("JIMM", 4, "RevokeCloudCredentialAccess", revokeCloudCredentialAccessMethod)
+		r.AddMethod("JIMM", 4, "SetDefaultCloudCredential", setDefaultCloudCredentialMethod)
 		// JIMM ReBAC RPC
-		r.AddMethod("JIMM", 4, "AddGroup", addGroupMethod)
+		r.addAdminMethod("JIMM", 4, "AddGroup", addGroupMethod)
 		r.AddMethod("JIMM", 4, "GetGroup", getGroupMethod)
-		r.AddMethod("JIMM", 4, "RenameGroup", renameGroupMethod)
-		r.AddMethod("JIMM", 4, "RemoveGroup", removeGroupMethod)
-		r.AddMethod("JIMM", 4, "ListGroups", listGroupsMethod)
-		r.AddMethod("JIMM", 4, "AddRelation", addRelationMethod)
-		r.AddMethod("JIMM", 4, "RemoveRelation", removeRelationMethod)
+		r.addAdminMethod("JIMM", 4, "RenameGroup", renameGroupMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveGroup", removeGroupMethod)
+		r.addAdminMethod("JIMM", 4, "ListGroups", listGroupsMethod)
+		r.addAdminMethod("JIMM", 4, "AddRelation", addRelationMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveRelation", removeRelationMethod)
 		r.AddMethod("JIMM", 4, "CheckRelation", checkRelationMethod)
-		r.AddMethod("JIMM", 4, "ListRelationshipTuples", listRelationshipTuplesMethod)
+		r.addAdminMethod("JIMM", 4, "ListRelationshipTuples", listRelationshipTuplesMethod)
 		// JIMM Cross-model queries
 		r.AddMethod("JIMM", 4, "CrossModelQuery", crossModelQueryMethod)
+		r.addAdminMethod("JIMM", 4, "CrossModelRelationGraph", crossModelRelationGraphMethod)
+		r.AddMethod("JIMM", 4, "FindOffers", findOffersMethod)
+		r.AddMethod("JIMM", 4, "SearchApplications", searchApplicationsMethod)
+		r.AddMethod("JIMM", 4, "ModelTimeline", modelTimelineMethod)
+		r.AddMethod("JIMM", 4, "ModelResourceHistory", modelResourceHistoryMethod)
+		r.AddMethod("JIMM", 4, "ExportModelBundle", exportModelBundleMethod)
+		r.AddMethod("JIMM", 4, "UserQuota", userQuotaMethod)
+		r.addAdminMethod("JIMM", 4, "SetUserQuota", setUserQuotaMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveUserQuota", removeUserQuotaMethod)
+		r.AddMethod("JIMM", 4, "SetPublicListing", setPublicListingMethod)
+		r.AddMethod("JIMM", 4, "RemovePublicListing", removePublicListingMethod)
+		r.AddMethod("JIMM", 4, "SetOfferConsumerLimit", setOfferConsumerLimitMethod)
+		r.AddMethod("JIMM", 4, "OfferConsumption", offerConsumptionMethod)
+		r.addAdminMethod("JIMM", 4, "SetCostCenter", setCostCenterMethod)
+		r.addAdminMethod("JIMM", 4, "UsageReport", usageReportMethod)
+		r.AddMethod("JIMM", 4, "Whoami", whoamiMethod)
+		// JIMM Namespace reservations
+		r.AddMethod("JIMM", 4, "AddNamespaceReservation", addNamespaceReservationMethod)
+		r.addAdminMethod("JIMM", 4, "ListNamespaceReservations", listNamespaceReservationsMethod)
+		r.addAdminMethod("JIMM", 4, "TransferNamespaceReservation", transferNamespaceReservationMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveNamespaceReservation", removeNamespaceReservationMethod)
+		// JIMM Model ACL templates
+		r.addAdminMethod("JIMM", 4, "AddModelACLTemplate", addModelACLTemplateMethod)
+		r.addAdminMethod("JIMM", 4, "ListModelACLTemplates", listModelACLTemplatesMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveModelACLTemplate", removeModelACLTemplateMethod)
+		// JIMM Model tokens
+		r.AddMethod("JIMM", 4, "CreateModelToken", createModelTokenMethod)
+		r.AddMethod("JIMM", 4, "ListModelTokens", listModelTokensMethod)
+		r.AddMethod("JIMM", 4, "RevokeModelToken", revokeModelTokenMethod)
+		r.addAdminMethod("JIMM", 4, "FreezeModel", freezeModelMethod)
+		r.addAdminMethod("JIMM", 4, "UnfreezeModel", unfreezeModelMethod)
+		// JIMM Model network policies
+		r.addAdminMethod("JIMM", 4, "SetModelNetworkPolicy", setModelNetworkPolicyMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveModelNetworkPolicy", removeModelNetworkPolicyMethod)
+		r.addAdminMethod("JIMM", 4, "ListModelNetworkPolicies", listModelNetworkPoliciesMethod)
+		// JIMM Model dependencies
+		r.AddMethod("JIMM", 4, "AddModelDependency", addModelDependencyMethod)
+		r.AddMethod("JIMM", 4, "RemoveModelDependency", removeModelDependencyMethod)
+		r.AddMethod("JIMM", 4, "ModelDependencies", modelDependenciesMethod)
+		// JIMM Cloud region remapping
+		r.addAdminMethod("JIMM", 4, "RemapCloudRegion", remapCloudRegionMethod)
+		// JIMM Webhook signing
+		r.addAdminMethod("JIMM", 4, "RotateWebhookSecret", rotateWebhookSecretMethod)
+		r.addAdminMethod("JIMM", 4, "ListWebhookDeliveries", listWebhookDeliveriesMethod)
+		// JIMM Model export
+		r.AddMethod("JIMM", 4, "ExportModel", exportModelMethod)
+		r.addAdminMethod("JIMM", 4, "PrepareModelImport", prepareModelImportMethod)
+		r.AddMethod("JIMM", 4, "MigrateModelExternal", migrateModelExternalMethod)
+		r.addAdminMethod("JIMM", 4, "CompleteModelImport", completeModelImportMethod)
+		// JIMM Denormalized field repair
+		r.addAdminMethod("JIMM", 4, "RepairDenormalizedFields", repairDenormalizedFieldsMethod)
+		// JIMM Controller service users
+		r.addAdminMethod("JIMM", 4, "ProvisionControllerServiceUser", provisionControllerServiceUserMethod)
+		// JIMM Operations
+		r.AddMethod("JIMM", 4, "GetOperation", getOperationMethod)
+		r.addAdminMethod("JIMM", 4, "ListOperations", listOperationsMethod)
+		r.AddMethod("JIMM", 4, "WatchOperation", watchOperationMethod)
+		// JIMM Model pools
+		r.addAdminMethod("JIMM", 4, "AddModelPool", addModelPoolMethod)
+		r.addAdminMethod("JIMM", 4, "RemoveModelPool", removeModelPoolMethod)
+		r.addAdminMethod("JIMM", 4, "ListModelPools", listModelPoolsMethod)
+		// JIMM Idle models
+		r.addAdminMethod("JIMM", 4, "ListIdleModels", listIdleModelsMethod)
+		r.AddMethod("JIMM", 4, "ArchiveAndDestroyModel", archiveAndDestroyModelMethod)
+		// JIMM Access requests
+		r.AddMethod("JIMM", 4, "RequestAccess", requestAccessMethod)
+		r.AddMethod("JIMM", 4, "ListAccessRequests", listAccessRequestsMethod)
+		r.AddMethod("JIMM", 4, "ApproveAccessRequest", approveAccessRequestMethod)
+		r.AddMethod("JIMM", 4, "DenyAccessRequest", denyAccessRequestMethod)
 		// JIMM Service Accounts
 		r.AddMethod("JIMM", 4, "AddServiceAccount", addServiceAccountMethod)
 		r.AddMethod("JIMM", 4, "CopyServiceAccountCredential", copyServiceAccountCredentialMethod)
 		r.AddMethod("JIMM", 4, "UpdateServiceAccountCredentials", updateServiceAccountCredentials)
 		r.AddMethod("JIMM", 4, "ListServiceAccountCredentials", listServiceAccountCredentials)
 		r.AddMethod("JIMM", 4, "GrantServiceAccountAccess", grantServiceAccountAccess)
+		r.addAdminMethod("JIMM", 4, "AddServiceAccountToGroups", addServiceAccountToGroups)
+		r.addAdminMethod("JIMM", 4, "RemoveServiceAccountFromGroups", removeServiceAccountFromGroups)
 		r.AddMethod("JIMM", 4, "Version", version)
+		// JIMM Identity confirmation
+		r.AddMethod("JIMM", 4, "EnrolTOTP", enrolTOTPMethod)
+		r.AddMethod("JIMM", 4, "ConfirmIdentity", confirmIdentityMethod)
+		// JIMM fault injection
+		r.addAdminMethod("JIMM", 4, "InjectFault", injectFaultMethod)
+		r.addAdminMethod("JIMM", 4, "ClearFaults", clearFaultsMethod)
+		r.addAdminMethod("JIMM", 4, "ListFaults", listFaultsMethod)
 
 		return []int{4}
 	}
@@ -181,6 +426,13 @@ func (r *controllerRoot) AddController(ctx context.Context, req apiparams.AddCon
 		}
 	}
 
+	if err := jimmRPC.ValidateDialConfig(req.ProxyURL, req.SSHJumpHost, req.SSHJumpHostKey); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	if err := jimmRPC.ValidateTLSConfig(req.CACertificate, req.TLSMinVersion, req.TLSSystemCAFallback); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+
 	nphps, err := network.ParseProviderHostPorts(req.APIAddresses...)
 	if err != nil {
 		return apiparams.ControllerInfo{}, errors.E(op, errors.CodeBadRequest, err)
@@ -191,17 +443,45 @@ func (r *controllerRoot) AddController(ctx context.Context, req apiparams.AddCon
 			nphps[i].Scope = network.ScopePublic
 		}
 	}
+	// Client addresses are handed to clients, so must not be local to
+	// the machine JIMM runs on.
+	cphps, err := network.ParseProviderHostPorts(req.ClientAddresses...)
+	if err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, errors.CodeBadRequest, err)
+	}
+	for i := range cphps {
+		switch cphps[i].Scope {
+		case network.ScopeMachineLocal, network.ScopeLinkLocal:
+			return apiparams.ControllerInfo{}, errors.E(op, fmt.Sprintf("address %s: %s address cannot be used as a client address", cphps[i], cphps[i].Scope), errors.CodeBadRequest)
+		case network.ScopeUnknown:
+			cphps[i].Scope = network.ScopePublic
+		}
+	}
 
 	// TODO(ale8k): Don't build dbmodel here, do it as params to AddController.
 	ctl := dbmodel.Controller{
-		UUID:              req.UUID,
-		Name:              req.Name,
-		PublicAddress:     req.PublicAddress,
-		CACertificate:     req.CACertificate,
-		AdminIdentityName: req.Username,
-		AdminPassword:     req.Password,
-		TLSHostname:       req.TLSHostname,
-		Addresses:         dbmodel.HostPorts{jujuparams.FromProviderHostPorts(nphps)},
+		UUID:                req.UUID,
+		Name:                req.Name,
+		PublicAddress:       req.PublicAddress,
+		CACertificate:       req.CACertificate,
+		AdminIdentityName:   req.Username,
+		AdminPassword:       req.Password,
+		TLSHostname:         req.TLSHostname,
+		ProxyURL:            req.ProxyURL,
+		SSHJumpHost:         req.SSHJumpHost,
+		SSHJumpHostKey:      req.SSHJumpHostKey,
+		TLSMinVersion:       req.TLSMinVersion,
+		TLSSystemCAFallback: req.TLSSystemCAFallback,
+		Addresses:           dbmodel.HostPorts{jujuparams.FromProviderHostPorts(nphps)},
+	}
+	if len(cphps) > 0 {
+		ctl.ClientAddresses = dbmodel.HostPorts{jujuparams.FromProviderHostPorts(cphps)}
+	}
+	if req.DryRun {
+		if err := r.jimm.DryRunAddController(ctx, r.user, &ctl); err != nil {
+			return apiparams.ControllerInfo{}, errors.E(op, err)
+		}
+		return ctl.ToAPIControllerInfo(), nil
 	}
 	if err := r.jimm.AddController(ctx, r.user, &ctl); err != nil {
 		zapctx.Error(ctx, "failed to add controller", zaputil.Error(err))
@@ -242,17 +522,43 @@ func (r *controllerRoot) ListControllers(ctx context.Context) (apiparams.ListCon
 	}
 	controllersInfo := make([]apiparams.ControllerInfo, 0, len(dbControllers))
 	for _, ctl := range dbControllers {
-		controllersInfo = append(controllersInfo, ctl.ToAPIControllerInfo())
+		ci := ctl.ToAPIControllerInfo()
+		ci.Health = r.jimm.ControllerHealth(ctl.Name)
+		controllersInfo = append(controllersInfo, ci)
 	}
 	return apiparams.ListControllersResponse{
 		Controllers: controllersInfo,
 	}, nil
 }
 
+// WatchControllerHealth creates a watcher reporting the availability,
+// monitoring lease and health of every controller. The returned ID is
+// used with the ControllerHealthWatcher facade. Only JIMM administrators
+// may watch the controllers.
+func (r *controllerRoot) WatchControllerHealth(ctx context.Context) (apiparams.ControllerHealthWatcherID, error) {
+	const op = errors.Op("jujuapi.WatchControllerHealth")
+
+	if !r.user.JimmAdmin {
+		return apiparams.ControllerHealthWatcherID{}, errors.E(op, errors.CodeUnauthorized, "unauthorized")
+	}
+	if err := r.setupUUIDGenerator(); err != nil {
+		return apiparams.ControllerHealthWatcherID{}, errors.E(op, err)
+	}
+	id := fmt.Sprintf("%v", r.generator.Next())
+	w := newControllerHealthWatcher(defaultControllerHealthWatcherPeriod, func(ctx context.Context) ([]apiparams.ControllerHealthState, error) {
+		return r.jimm.ControllerHealthStates(ctx, r.user)
+	})
+	r.watchers.register(id, w)
+	return apiparams.ControllerHealthWatcherID{WatcherID: id}, nil
+}
+
 // RemoveController removes a controller.
 func (r *controllerRoot) RemoveController(ctx context.Context, req apiparams.RemoveControllerRequest) (apiparams.ControllerInfo, error) {
 	const op = errors.Op("jujuapi.RemoveController")
 
+	if err := r.requireConfirmation("removing a controller"); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
 	ctl, err := r.jimm.ControllerInfo(ctx, req.Name)
 	if err != nil {
 		return apiparams.ControllerInfo{}, errors.E(op, err)
@@ -278,6 +584,92 @@ func (r *controllerRoot) SetControllerDeprecated(ctx context.Context, req apipar
 	return ctl.ToAPIControllerInfo(), nil
 }
 
+// GetControllerBootstrapProfile returns the captured bootstrap profile
+// of a controller, from which an equivalent controller may be
+// re-bootstrapped.
+func (r *controllerRoot) GetControllerBootstrapProfile(ctx context.Context, req apiparams.GetControllerBootstrapProfileRequest) (apiparams.ControllerBootstrapProfile, error) {
+	const op = errors.Op("jujuapi.GetControllerBootstrapProfile")
+
+	profile, err := r.jimm.GetControllerBootstrapProfile(ctx, r.user, req.Name, req.Version)
+	if err != nil {
+		return apiparams.ControllerBootstrapProfile{}, errors.E(op, err)
+	}
+	return profile, nil
+}
+
+// SetControllerMaintenance puts a controller into, or takes it out of,
+// maintenance. Entering maintenance drains the client sessions proxied
+// to the controller.
+func (r *controllerRoot) SetControllerMaintenance(ctx context.Context, req apiparams.SetControllerMaintenanceRequest) (apiparams.SetControllerMaintenanceResponse, error) {
+	const op = errors.Op("jujuapi.SetControllerMaintenance")
+
+	n, err := r.jimm.SetControllerMaintenance(ctx, r.user, req.Name, req.Maintenance)
+	if err != nil {
+		return apiparams.SetControllerMaintenanceResponse{}, errors.E(op, err)
+	}
+	ctl, err := r.jimm.ControllerInfo(ctx, req.Name)
+	if err != nil {
+		return apiparams.SetControllerMaintenanceResponse{}, errors.E(op, err)
+	}
+	return apiparams.SetControllerMaintenanceResponse{
+		Controller:      ctl.ToAPIControllerInfo(),
+		DrainedSessions: n,
+	}, nil
+}
+
+// SetControllerTiers sets the tiers a controller belongs to.
+func (r *controllerRoot) SetControllerTiers(ctx context.Context, req apiparams.SetControllerTiersRequest) (apiparams.ControllerInfo, error) {
+	const op = errors.Op("jujuapi.SetControllerTiers")
+
+	if err := r.jimm.SetControllerTiers(ctx, r.user, req.Name, req.Tiers); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	ctl, err := r.jimm.ControllerInfo(ctx, req.Name)
+	if err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	return ctl.ToAPIControllerInfo(), nil
+}
+
+// SetControllerTimeouts sets the timeouts JIMM uses when communicating
+// with a controller.
+func (r *controllerRoot) SetControllerTimeouts(ctx context.Context, req apiparams.SetControllerTimeoutsRequest) (apiparams.ControllerInfo, error) {
+	const op = errors.Op("jujuapi.SetControllerTimeouts")
+
+	var timeouts jimm.ControllerTimeouts
+	var err error
+	if timeouts.Dial, err = parseTimeout("dial-timeout", req.DialTimeout); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	if timeouts.RPC, err = parseTimeout("rpc-timeout", req.RPCTimeout); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	if timeouts.KeepaliveInterval, err = parseTimeout("keepalive-interval", req.KeepaliveInterval); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	if err := r.jimm.SetControllerTimeouts(ctx, r.user, req.Name, timeouts); err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	ctl, err := r.jimm.ControllerInfo(ctx, req.Name)
+	if err != nil {
+		return apiparams.ControllerInfo{}, errors.E(op, err)
+	}
+	return ctl.ToAPIControllerInfo(), nil
+}
+
+// parseTimeout parses the named timeout value, an empty value is a zero
+// duration.
+func parseTimeout(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.E(err, errors.CodeBadRequest, fmt.Sprintf("invalid %s", name))
+	}
+	return d, nil
+}
+
 // maxLimit is the maximum number of audit-log entries that will be
 // returned from the audit log, no matter how many are requested.
 const maxLimit = 1000
@@ -329,6 +721,9 @@ func auditParamsToFilter(req apiparams.FindAuditEventsRequest) (db.AuditLogFilte
 // FindAuditEvents finds the audit-log entries that match the given filter.
 func (r *controllerRoot) FindAuditEvents(ctx context.Context, req apiparams.FindAuditEventsRequest) (apiparams.AuditEvents, error) {
 	const op = errors.Op("jujuapi.FindAuditEvents")
+	if err := r.requireConfirmation("reading the audit log"); err != nil {
+		return apiparams.AuditEvents{}, errors.E(op, err)
+	}
 	filter, err := auditParamsToFilter(req)
 	if err != nil {
 		return apiparams.AuditEvents{}, errors.E(op, err)
@@ -365,6 +760,64 @@ func (r *controllerRoot) GrantAuditLogAccess(ctx context.Context, req apiparams.
 	return nil
 }
 
+// GrantCloudCredentialAccess allows a user, or the members of a group,
+// to create models using a cloud credential without being able to read
+// its attributes. Only the owner of the credential, or a JIMM
+// administrator, can grant access.
+func (r *controllerRoot) GrantCloudCredentialAccess(ctx context.Context, req apiparams.CloudCredentialAccessRequest) error {
+	const op = errors.Op("jujuapi.GrantCloudCredentialAccess")
+
+	ct, err := names.ParseCloudCredentialTag(req.CloudCredentialTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.GrantCloudCredentialAccess(ctx, r.user, ct, req.Entity); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RevokeCloudCredentialAccess removes access granted with
+// GrantCloudCredentialAccess. Only the owner of the credential, or a
+// JIMM administrator, can revoke access.
+func (r *controllerRoot) RevokeCloudCredentialAccess(ctx context.Context, req apiparams.CloudCredentialAccessRequest) error {
+	const op = errors.Op("jujuapi.RevokeCloudCredentialAccess")
+
+	ct, err := names.ParseCloudCredentialTag(req.CloudCredentialTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.RevokeCloudCredentialAccess(ctx, r.user, ct, req.Entity); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// SetDefaultCloudCredential sets the cloud credential used for the
+// authenticated user's models on a cloud when a model is created without
+// a credential and the user has several credentials for the cloud. If no
+// credential is specified the user's default for the cloud is removed.
+func (r *controllerRoot) SetDefaultCloudCredential(ctx context.Context, req apiparams.SetDefaultCloudCredentialRequest) error {
+	const op = errors.Op("jujuapi.SetDefaultCloudCredential")
+
+	ct, err := names.ParseCloudTag(req.CloudTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	var cct names.CloudCredentialTag
+	if req.CredentialName != "" {
+		id := ct.Id() + "/" + r.user.Name + "/" + req.CredentialName
+		if !names.IsValidCloudCredential(id) {
+			return errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid credential name %q", req.CredentialName))
+		}
+		cct = names.NewCloudCredentialTag(id)
+	}
+	if err := r.jimm.SetDefaultCloudCredential(ctx, r.user, ct, cct); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
 // RevokeAuditLogAccess revokes access to the audit log at the specified
 // level from the specified user. The only currently supported level is
 // "read". Only controller admin users can revoke access to the audit log.
@@ -383,13 +836,83 @@ func (r *controllerRoot) RevokeAuditLogAccess(ctx context.Context, req apiparams
 	return nil
 }
 
+// DestroyModelsDryRun reports what would be destroyed by a DestroyModels
+// call with the same parameters, without destroying anything. This
+// allows clients to ask for confirmation before destroying models.
+func (r *controllerRoot) DestroyModelsDryRun(ctx context.Context, req apiparams.DestroyModelsDryRunRequest) (apiparams.DestroyModelsDryRunResponse, error) {
+	const op = errors.Op("jujuapi.DestroyModelsDryRun")
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	results := make([]apiparams.DestroyModelImpactResult, len(req.Models))
+	for i, model := range req.Models {
+		mt, err := r.jimm.ResolveModel(ctx, r.user, model.ModelTag)
+		if err != nil {
+			results[i].Error = mapError(errors.E(op, err))
+			continue
+		}
+		impact, err := r.jimm.DestroyModelImpact(ctx, r.user, mt, model.DestroyStorage)
+		if err != nil {
+			results[i].Error = mapError(errors.E(op, err))
+			continue
+		}
+		results[i].Result = impact
+	}
+	return apiparams.DestroyModelsDryRunResponse{
+		Results: results,
+	}, nil
+}
+
+// ModelMachines returns the placement and network details of the
+// machines in a model as recorded by JIMM, allowing network audits
+// without access to the model's controller.
+func (r *controllerRoot) ModelMachines(ctx context.Context, req apiparams.ModelMachinesRequest) (apiparams.ModelMachinesResponse, error) {
+	const op = errors.Op("jujuapi.ModelMachines")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ModelMachinesResponse{}, errors.E(op, err)
+	}
+	machines, err := r.jimm.ModelMachines(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ModelMachinesResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ModelMachinesResponse{
+		Machines: make([]apiparams.ModelMachine, len(machines)),
+	}
+	for i, m := range machines {
+		resp.Machines[i] = apiparams.ModelMachine{
+			ID:               m.MachineID,
+			InstanceID:       m.InstanceID,
+			Hostname:         m.Hostname,
+			Life:             m.Life,
+			AvailabilityZone: m.AvailabilityZone,
+			Addresses:        m.Addresses,
+			Subnets:          m.Subnets,
+		}
+	}
+	return resp, nil
+}
+
+// ModelsStatus returns an overview of the status of the controllers
+// known to JIMM and the models they host.
+func (r *controllerRoot) ModelsStatus(ctx context.Context, req apiparams.ModelsStatusRequest) (apiparams.ModelsStatusResponse, error) {
+	const op = errors.Op("jujuapi.ModelsStatus")
+
+	resp, err := r.jimm.ModelsStatus(ctx, r.user, req.Controller)
+	if err != nil {
+		return apiparams.ModelsStatusResponse{}, errors.E(op, err)
+	}
+	return *resp, nil
+}
+
 // FullModelStatus returns the full status of the juju model.
 func (r *controllerRoot) FullModelStatus(ctx context.Context, req apiparams.FullModelStatusRequest) (jujuparams.FullStatus, error) {
 	const op = errors.Op("jujuapi.FullModelStatus")
 
-	mt, err := names.ParseModelTag(req.ModelTag)
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
 	if err != nil {
-		return jujuparams.FullStatus{}, errors.E(op, err, errors.CodeBadRequest)
+		return jujuparams.FullStatus{}, errors.E(op, err)
 	}
 
 	status, err := r.jimm.FullModelStatus(ctx, r.user, mt, req.Patterns)
@@ -409,9 +932,9 @@ func (r *controllerRoot) UpdateMigratedModel(ctx context.Context, req apiparams.
 		return errors.E(op, errors.CodeUnauthorized, "unauthorized")
 	}
 
-	mt, err := names.ParseModelTag(req.ModelTag)
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
 	if err != nil {
-		return errors.E(op, err, errors.CodeBadRequest)
+		return errors.E(op, err)
 	}
 	err = r.jimm.UpdateMigratedModel(ctx, r.user, mt, req.TargetController)
 	if err != nil {
@@ -430,7 +953,7 @@ func (r *controllerRoot) ImportModel(ctx context.Context, req apiparams.ImportMo
 		return errors.E(op, err, errors.CodeBadRequest)
 	}
 
-	err = r.jimm.ImportModel(ctx, r.user, req.Controller, mt, req.Owner)
+	err = r.jimm.ImportModel(ctx, r.user, req.Controller, mt, req.Owner, req.Origin)
 	if err != nil {
 		return errors.E(op, err)
 	}
@@ -471,42 +994,1521 @@ func (r *controllerRoot) CrossModelQuery(ctx context.Context, req apiparams.Cros
 	}
 }
 
-// PurgeLogs removes all audit log entries older than the specified date.
-func (r *controllerRoot) PurgeLogs(ctx context.Context, req apiparams.PurgeLogsRequest) (apiparams.PurgeLogsResponse, error) {
-	const op = errors.Op("jujuapi.PurgeLogs")
+// CrossModelRelationGraph returns the graph of cross-model relations
+// between models, showing which models consume which application offers.
+// If a model tag is specified only the relations that model takes part in
+// are returned.
+func (r *controllerRoot) CrossModelRelationGraph(ctx context.Context, req apiparams.CrossModelRelationGraphRequest) (apiparams.CrossModelRelationGraph, error) {
+	const op = errors.Op("jujuapi.CrossModelRelationGraph")
 
-	deleted_count, err := r.jimm.PurgeLogs(ctx, r.user, req.Date)
+	var mt names.ModelTag
+	if req.ModelTag != "" {
+		var err error
+		mt, err = r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+		if err != nil {
+			return apiparams.CrossModelRelationGraph{}, errors.E(op, err)
+		}
+	}
+	graph, err := r.jimm.CrossModelRelationGraph(ctx, r.user, mt)
 	if err != nil {
-		return apiparams.PurgeLogsResponse{}, errors.E(op, err)
+		return apiparams.CrossModelRelationGraph{}, errors.E(op, err)
 	}
-	return apiparams.PurgeLogsResponse{
-		DeletedCount: deleted_count,
-	}, nil
+	return graph, nil
 }
 
-// MigrateModel is a JIMM specific method for migrating models between two controllers that
-// are already attached to JIMM. See InitiateMigration in controller.go to migrate a model
-// in a controller attached to JIMM to one not managed by JIMM.
-func (r *controllerRoot) MigrateModel(ctx context.Context, args apiparams.MigrateModelRequest) (jujuparams.InitiateMigrationResults, error) {
-	const op = errors.Op("jujuapi.MigrateModel")
+// FindOffers searches the directory of application offers known to JIMM
+// for the offers the authenticated user can read.
+func (r *controllerRoot) FindOffers(ctx context.Context, req apiparams.FindOffersRequest) (apiparams.FindOffersResponse, error) {
+	const op = errors.Op("jujuapi.FindOffers")
 
-	results := make([]jujuparams.InitiateMigrationResult, len(args.Specs))
-	for i, arg := range args.Specs {
-		mt, err := names.ParseModelTag(arg.ModelTag)
-		if err != nil {
-			results[i].Error = mapError(errors.E(op, err))
-			continue
-		}
-		result, err := r.jimm.InitiateInternalMigration(ctx, r.user, mt, arg.TargetController)
-		if err != nil {
-			result.Error = mapError(errors.E(op, err))
-		}
-		results[i] = result
+	if req.Owner != "" && !names.IsValidUser(req.Owner) {
+		return apiparams.FindOffersResponse{}, errors.E(op, errors.CodeBadRequest, "invalid owner "+req.Owner)
+	}
+	offers, err := r.jimm.FindOffers(ctx, r.user, req)
+	if err != nil {
+		return apiparams.FindOffersResponse{}, errors.E(op, err)
 	}
+	return apiparams.FindOffersResponse{Offers: offers}, nil
+}
 
-	return jujuparams.InitiateMigrationResults{
-		Results: results,
-	}, nil
+// SearchApplications searches all the models the authenticated user can
+// see for applications with an endpoint matching the given interface or
+// endpoint name.
+func (r *controllerRoot) SearchApplications(ctx context.Context, req apiparams.SearchApplicationsRequest) (apiparams.SearchApplicationsResponse, error) {
+	const op = errors.Op("jujuapi.SearchApplications")
+
+	results, err := r.jimm.SearchApplications(ctx, r.user, req)
+	if err != nil {
+		return apiparams.SearchApplicationsResponse{}, errors.E(op, err)
+	}
+	return apiparams.SearchApplicationsResponse{Results: results}, nil
+}
+
+// ListLeaders returns the JIMM replica currently running each of the
+// leader-elected background workers.
+func (r *controllerRoot) ListLeaders(ctx context.Context) (apiparams.ListLeadersResponse, error) {
+	const op = errors.Op("jujuapi.ListLeaders")
+
+	leaders, err := r.jimm.ListLeaders(ctx, r.user)
+	if err != nil {
+		return apiparams.ListLeadersResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListLeadersResponse{Leaders: leaders}, nil
+}
+
+// ListScheduledJobs returns the periodic jobs run by JIMM's scheduler.
+func (r *controllerRoot) ListScheduledJobs(ctx context.Context) (apiparams.ListScheduledJobsResponse, error) {
+	const op = errors.Op("jujuapi.ListScheduledJobs")
+
+	jobs, err := r.jimm.ListScheduledJobs(ctx, r.user)
+	if err != nil {
+		return apiparams.ListScheduledJobsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListScheduledJobsResponse{Jobs: jobs}, nil
+}
+
+// ScheduledJobHistory returns the most recent runs of a scheduled job.
+func (r *controllerRoot) ScheduledJobHistory(ctx context.Context, req apiparams.ScheduledJobHistoryRequest) (apiparams.ScheduledJobHistoryResponse, error) {
+	const op = errors.Op("jujuapi.ScheduledJobHistory")
+
+	runs, err := r.jimm.ScheduledJobHistory(ctx, r.user, req.Job, req.Limit)
+	if err != nil {
+		return apiparams.ScheduledJobHistoryResponse{}, errors.E(op, err)
+	}
+	return apiparams.ScheduledJobHistoryResponse{Runs: runs}, nil
+}
+
+// SetScheduledJobEnabled enables, or disables, a scheduled job.
+func (r *controllerRoot) SetScheduledJobEnabled(ctx context.Context, req apiparams.SetScheduledJobEnabledRequest) error {
+	const op = errors.Op("jujuapi.SetScheduledJobEnabled")
+
+	if err := r.jimm.SetScheduledJobEnabled(ctx, r.user, req.Job, req.Enabled); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// PingControllers probes every controller and reports how long each took
+// to respond, or why it could not be reached.
+func (r *controllerRoot) PingControllers(ctx context.Context) (apiparams.PingControllersResponse, error) {
+	const op = errors.Op("jujuapi.PingControllers")
+
+	resp, err := r.jimm.PingControllers(ctx, r.user)
+	if err != nil {
+		return apiparams.PingControllersResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ListOutdatedCharms returns the applications using an older revision of
+// a Charmhub charm than the latest released to Charmhub.
+func (r *controllerRoot) ListOutdatedCharms(ctx context.Context) (apiparams.ListOutdatedCharmsResponse, error) {
+	const op = errors.Op("jujuapi.ListOutdatedCharms")
+
+	charms, err := r.jimm.ListOutdatedCharms(ctx, r.user)
+	if err != nil {
+		return apiparams.ListOutdatedCharmsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListOutdatedCharmsResponse{Charms: charms}, nil
+}
+
+// ListPlacementLatencies returns the latest measured latencies of the
+// cloud regions and controllers used to place latency sensitive models.
+func (r *controllerRoot) ListPlacementLatencies(ctx context.Context) (apiparams.PlacementLatencies, error) {
+	const op = errors.Op("jujuapi.ListPlacementLatencies")
+
+	resp, err := r.jimm.ListPlacementLatencies(ctx, r.user)
+	if err != nil {
+		return apiparams.PlacementLatencies{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// SetDomainDefaultCloud sets the default cloud, and region, of an
+// identity domain.
+func (r *controllerRoot) SetDomainDefaultCloud(ctx context.Context, req apiparams.SetDomainDefaultCloudRequest) error {
+	const op = errors.Op("jujuapi.SetDomainDefaultCloud")
+
+	if err := r.jimm.SetDomainDefaultCloud(ctx, r.user, req.Domain, req.Cloud, req.Region); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveDomainDefaultCloud removes the default cloud of an identity
+// domain.
+func (r *controllerRoot) RemoveDomainDefaultCloud(ctx context.Context, req apiparams.RemoveDomainDefaultCloudRequest) error {
+	const op = errors.Op("jujuapi.RemoveDomainDefaultCloud")
+
+	if err := r.jimm.RemoveDomainDefaultCloud(ctx, r.user, req.Domain); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListDomainDefaultClouds returns the default clouds of all identity
+// domains.
+func (r *controllerRoot) ListDomainDefaultClouds(ctx context.Context) (apiparams.ListDomainDefaultCloudsResponse, error) {
+	const op = errors.Op("jujuapi.ListDomainDefaultClouds")
+
+	defs, err := r.jimm.ListDomainDefaultClouds(ctx, r.user)
+	if err != nil {
+		return apiparams.ListDomainDefaultCloudsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListDomainDefaultCloudsResponse{Defaults: defs}, nil
+}
+
+// PurgeIdentity permanently removes an identity and its personal data,
+// leaving an anonymous tombstone in the records that are kept.
+func (r *controllerRoot) PurgeIdentity(ctx context.Context, req apiparams.PurgeIdentityRequest) (apiparams.PurgeIdentityResponse, error) {
+	const op = errors.Op("jujuapi.PurgeIdentity")
+
+	resp, err := r.jimm.PurgeIdentity(ctx, r.user, req.Name, req.DeleteAuditLog)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// NormalizeIdentities renames the existing identities to their
+// normalized names.
+func (r *controllerRoot) NormalizeIdentities(ctx context.Context, req apiparams.NormalizeIdentitiesRequest) (apiparams.NormalizeIdentitiesResponse, error) {
+	const op = errors.Op("jujuapi.NormalizeIdentities")
+
+	renames, err := r.jimm.NormalizeIdentities(ctx, r.user, req.DryRun)
+	if err != nil {
+		return apiparams.NormalizeIdentitiesResponse{}, errors.E(op, err)
+	}
+	return apiparams.NormalizeIdentitiesResponse{Renames: renames}, nil
+}
+
+// ListIdentitySessions returns the last login and API activity of an
+// identity along with the sessions it has open.
+func (r *controllerRoot) ListIdentitySessions(ctx context.Context, req apiparams.ListIdentitySessionsRequest) (apiparams.ListIdentitySessionsResponse, error) {
+	const op = errors.Op("jujuapi.ListIdentitySessions")
+
+	resp, err := r.jimm.ListIdentitySessions(ctx, r.user, req.Identity)
+	if err != nil {
+		return resp, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ReloadTunables reloads the configuration that may be changed without
+// restarting JIMM and returns the settings that changed.
+func (r *controllerRoot) ReloadTunables(ctx context.Context) (apiparams.ReloadTunablesResponse, error) {
+	const op = errors.Op("jujuapi.ReloadTunables")
+
+	changes, err := r.jimm.ReloadTunables(ctx, r.user)
+	if err != nil {
+		return apiparams.ReloadTunablesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ReloadTunablesResponse{Changes: changes}, nil
+}
+
+// ListControllerModelCredentials returns the cloud credential used by the
+// controller model of every controller.
+func (r *controllerRoot) ListControllerModelCredentials(ctx context.Context) (apiparams.ListControllerModelCredentialsResponse, error) {
+	const op = errors.Op("jujuapi.ListControllerModelCredentials")
+
+	resp, err := r.jimm.ListControllerModelCredentials(ctx, r.user)
+	if err != nil {
+		return apiparams.ListControllerModelCredentialsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ListControllerCertificates returns the expiry of the certificates
+// presented by every controller, soonest first.
+func (r *controllerRoot) ListControllerCertificates(ctx context.Context) (apiparams.ListControllerCertificatesResponse, error) {
+	const op = errors.Op("jujuapi.ListControllerCertificates")
+
+	resp, err := r.jimm.ListControllerCertificates(ctx, r.user)
+	if err != nil {
+		return apiparams.ListControllerCertificatesResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// RotateControllerModelCredential replaces the cloud credential used by
+// the controller model of a controller and records when it expires.
+func (r *controllerRoot) RotateControllerModelCredential(ctx context.Context, req apiparams.RotateControllerModelCredentialRequest) (apiparams.ControllerModelCredential, error) {
+	const op = errors.Op("jujuapi.RotateControllerModelCredential")
+
+	resp, err := r.jimm.RotateControllerModelCredential(ctx, r.user, req)
+	if err != nil {
+		return apiparams.ControllerModelCredential{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ExportModelAccess returns a document declaring the access held on a
+// model, or on every model of an owner.
+func (r *controllerRoot) ExportModelAccess(ctx context.Context, req apiparams.ExportModelAccessRequest) (apiparams.ModelAccessDocument, error) {
+	const op = errors.Op("jujuapi.ExportModelAccess")
+
+	doc, err := r.jimm.ExportModelAccess(ctx, r.user, req)
+	if err != nil {
+		return apiparams.ModelAccessDocument{}, errors.E(op, err)
+	}
+	return doc, nil
+}
+
+// ApplyModelAccess makes the access held on models match a document, or
+// reports the changes that would be made if the request is a dry run.
+func (r *controllerRoot) ApplyModelAccess(ctx context.Context, req apiparams.ApplyModelAccessRequest) (apiparams.ApplyModelAccessResponse, error) {
+	const op = errors.Op("jujuapi.ApplyModelAccess")
+
+	resp, err := r.jimm.ApplyModelAccess(ctx, r.user, req)
+	if err != nil {
+		return apiparams.ApplyModelAccessResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// SyncGroups synchronises group memberships from the external directory,
+// or reports the changes that would be made if the request is a dry run.
+func (r *controllerRoot) SyncGroups(ctx context.Context, req apiparams.SyncGroupsRequest) (apiparams.SyncGroupsResponse, error) {
+	const op = errors.Op("jujuapi.SyncGroups")
+
+	resp, err := r.jimm.SyncGroups(ctx, r.user, req)
+	if err != nil {
+		return apiparams.SyncGroupsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// GroupSyncStatus returns the outcome of the most recent synchronisation
+// of group memberships from the external directory.
+func (r *controllerRoot) GroupSyncStatus(ctx context.Context) (apiparams.GroupSyncStatusResponse, error) {
+	const op = errors.Op("jujuapi.GroupSyncStatus")
+
+	resp, err := r.jimm.GroupSyncStatus(ctx, r.user)
+	if err != nil {
+		return apiparams.GroupSyncStatusResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ModelTimeline returns the significant events in the history of a
+// model, such as access and credential changes, migrations and
+// availability incidents.
+func (r *controllerRoot) ModelTimeline(ctx context.Context, req apiparams.ModelTimelineRequest) (apiparams.ModelTimeline, error) {
+	const op = errors.Op("jujuapi.ModelTimeline")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ModelTimeline{}, errors.E(op, err)
+	}
+	var start, end time.Time
+	if req.After != "" {
+		start, err = time.Parse(time.RFC3339, req.After)
+		if err != nil {
+			return apiparams.ModelTimeline{}, errors.E(op, err, errors.CodeBadRequest, `invalid "after" filter`)
+		}
+	}
+	if req.Before != "" {
+		end, err = time.Parse(time.RFC3339, req.Before)
+		if err != nil {
+			return apiparams.ModelTimeline{}, errors.E(op, err, errors.CodeBadRequest, `invalid "before" filter`)
+		}
+	}
+	if req.Limit < 0 {
+		return apiparams.ModelTimeline{}, errors.E(op, errors.CodeBadRequest, "invalid limit")
+	}
+	timeline, err := r.jimm.ModelTimeline(ctx, r.user, mt, start, end, req.Limit)
+	if err != nil {
+		return apiparams.ModelTimeline{}, errors.E(op, err)
+	}
+	return timeline, nil
+}
+
+// ModelResourceHistory returns series of the machine, core and unit
+// counts of a model over time, suitable for plotting trend graphs.
+func (r *controllerRoot) ModelResourceHistory(ctx context.Context, req apiparams.ModelResourceHistoryRequest) (apiparams.ModelResourceHistory, error) {
+	const op = errors.Op("jujuapi.ModelResourceHistory")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ModelResourceHistory{}, errors.E(op, err)
+	}
+	var start, end time.Time
+	if req.After != "" {
+		start, err = time.Parse(time.RFC3339, req.After)
+		if err != nil {
+			return apiparams.ModelResourceHistory{}, errors.E(op, err, errors.CodeBadRequest, `invalid "after" filter`)
+		}
+	}
+	if req.Before != "" {
+		end, err = time.Parse(time.RFC3339, req.Before)
+		if err != nil {
+			return apiparams.ModelResourceHistory{}, errors.E(op, err, errors.CodeBadRequest, `invalid "before" filter`)
+		}
+	}
+	history, err := r.jimm.ModelResourceHistory(ctx, r.user, mt, start, end, req.Resolution)
+	if err != nil {
+		return apiparams.ModelResourceHistory{}, errors.E(op, err)
+	}
+	return history, nil
+}
+
+// ExportModelBundle exports a model as a bundle by asking the controller
+// hosting the model to export it.
+func (r *controllerRoot) ExportModelBundle(ctx context.Context, req apiparams.ExportModelBundleRequest) (apiparams.ExportModelBundleResponse, error) {
+	const op = errors.Op("jujuapi.ExportModelBundle")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ExportModelBundleResponse{}, errors.E(op, err)
+	}
+	bundle, err := r.jimm.ExportModelBundle(ctx, r.user, mt, jimm.BundleExportOptions{
+		IncludeCharmDefaults:  req.IncludeCharmDefaults,
+		StripCloudConstraints: req.StripCloudConstraints,
+	})
+	if err != nil {
+		return apiparams.ExportModelBundleResponse{}, errors.E(op, err)
+	}
+	url, err := r.jimm.StoreOversizedResult(ctx, r.user, mt.Id()+"-bundle.yaml", "application/yaml", []byte(bundle))
+	if err != nil {
+		return apiparams.ExportModelBundleResponse{}, errors.E(op, err)
+	}
+	if url != "" {
+		return apiparams.ExportModelBundleResponse{DownloadURL: url}, nil
+	}
+	return apiparams.ExportModelBundleResponse{
+		Bundle: bundle,
+	}, nil
+}
+
+// UserQuota returns the quota limits and current usage for the models
+// owned by a user. If no user is specified the quota of the
+// authenticated user is returned.
+func (r *controllerRoot) UserQuota(ctx context.Context, req apiparams.UserQuotaRequest) (apiparams.UserQuota, error) {
+	const op = errors.Op("jujuapi.UserQuota")
+
+	ut := r.user.ResourceTag()
+	if req.UserTag != "" {
+		var err error
+		ut, err = parseUserTag(req.UserTag)
+		if err != nil {
+			return apiparams.UserQuota{}, errors.E(op, err, errors.CodeBadRequest)
+		}
+	}
+	quota, err := r.jimm.UserQuota(ctx, r.user, ut)
+	if err != nil {
+		return apiparams.UserQuota{}, errors.E(op, err)
+	}
+	return quota, nil
+}
+
+// SetUserQuota overrides the default quota limits for a user's models.
+func (r *controllerRoot) SetUserQuota(ctx context.Context, req apiparams.SetUserQuotaRequest) error {
+	const op = errors.Op("jujuapi.SetUserQuota")
+
+	ut, err := parseUserTag(req.UserTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.SetUserQuota(ctx, r.user, ut, req); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveUserQuota removes the quota limits set for a user, so that the
+// default limits apply to their models.
+func (r *controllerRoot) RemoveUserQuota(ctx context.Context, req apiparams.RemoveUserQuotaRequest) error {
+	const op = errors.Op("jujuapi.RemoveUserQuota")
+
+	ut, err := parseUserTag(req.UserTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.RemoveUserQuota(ctx, r.user, ut); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// SetPublicListing lists a model or application offer in the public
+// catalog, which describes it to unauthenticated clients.
+func (r *controllerRoot) SetPublicListing(ctx context.Context, req apiparams.SetPublicListingRequest) error {
+	const op = errors.Op("jujuapi.SetPublicListing")
+
+	switch {
+	case req.ModelTag != "" && req.OfferURL != "":
+		return errors.E(op, errors.CodeBadRequest, "specify either a model or an offer, not both")
+	case req.ModelTag != "":
+		mt, err := names.ParseModelTag(req.ModelTag)
+		if err != nil {
+			return errors.E(op, err, errors.CodeBadRequest)
+		}
+		if err := r.jimm.SetModelPublicListing(ctx, r.user, mt, req.Purpose, req.OwnerGroup); err != nil {
+			return errors.E(op, err)
+		}
+	case req.OfferURL != "":
+		if err := r.jimm.SetOfferPublicListing(ctx, r.user, req.OfferURL, req.Purpose, req.OwnerGroup); err != nil {
+			return errors.E(op, err)
+		}
+	default:
+		return errors.E(op, errors.CodeBadRequest, "missing model or offer")
+	}
+	return nil
+}
+
+// RemovePublicListing removes a model or application offer from the
+// public catalog.
+func (r *controllerRoot) RemovePublicListing(ctx context.Context, req apiparams.RemovePublicListingRequest) error {
+	const op = errors.Op("jujuapi.RemovePublicListing")
+
+	switch {
+	case req.ModelTag != "" && req.OfferURL != "":
+		return errors.E(op, errors.CodeBadRequest, "specify either a model or an offer, not both")
+	case req.ModelTag != "":
+		mt, err := names.ParseModelTag(req.ModelTag)
+		if err != nil {
+			return errors.E(op, err, errors.CodeBadRequest)
+		}
+		if err := r.jimm.RemoveModelPublicListing(ctx, r.user, mt); err != nil {
+			return errors.E(op, err)
+		}
+	case req.OfferURL != "":
+		if err := r.jimm.RemoveOfferPublicListing(ctx, r.user, req.OfferURL); err != nil {
+			return errors.E(op, err)
+		}
+	default:
+		return errors.E(op, errors.CodeBadRequest, "missing model or offer")
+	}
+	return nil
+}
+
+// SetOfferConsumerLimit limits the number of models that may consume an
+// application offer.
+func (r *controllerRoot) SetOfferConsumerLimit(ctx context.Context, req apiparams.SetOfferConsumerLimitRequest) error {
+	const op = errors.Op("jujuapi.SetOfferConsumerLimit")
+
+	if req.OfferURL == "" {
+		return errors.E(op, errors.CodeBadRequest, "missing offer")
+	}
+	if err := r.jimm.SetOfferConsumerLimit(ctx, r.user, req.OfferURL, req.Limit); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// OfferConsumption returns the consumption of an application offer
+// against its consumer limit.
+func (r *controllerRoot) OfferConsumption(ctx context.Context, req apiparams.OfferConsumptionRequest) (apiparams.OfferConsumption, error) {
+	const op = errors.Op("jujuapi.OfferConsumption")
+
+	if req.OfferURL == "" {
+		return apiparams.OfferConsumption{}, errors.E(op, errors.CodeBadRequest, "missing offer")
+	}
+	consumption, err := r.jimm.OfferConsumption(ctx, r.user, req.OfferURL)
+	if err != nil {
+		return apiparams.OfferConsumption{}, errors.E(op, err)
+	}
+	return consumption, nil
+}
+
+// SetCostCenter attaches a cost center to a user, group or model.
+func (r *controllerRoot) SetCostCenter(ctx context.Context, req apiparams.SetCostCenterRequest) error {
+	const op = errors.Op("jujuapi.SetCostCenter")
+
+	if err := r.jimm.SetCostCenter(ctx, r.user, req.Entity, req.CostCenter); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// UsageReport reports the resources used by every model, attributed to
+// cost centers.
+func (r *controllerRoot) UsageReport(ctx context.Context) (apiparams.UsageReport, error) {
+	const op = errors.Op("jujuapi.UsageReport")
+
+	report, err := r.jimm.UsageReport(ctx, r.user)
+	if err != nil {
+		return apiparams.UsageReport{}, errors.E(op, err)
+	}
+	return report, nil
+}
+
+// AgentVersionReport reports the agent versions running in every model,
+// attributed to cost centers.
+func (r *controllerRoot) AgentVersionReport(ctx context.Context) (apiparams.AgentVersionReport, error) {
+	const op = errors.Op("jujuapi.AgentVersionReport")
+
+	report, err := r.jimm.AgentVersionReport(ctx, r.user)
+	if err != nil {
+		return apiparams.AgentVersionReport{}, errors.E(op, err)
+	}
+	return report, nil
+}
+
+// Whoami returns the authenticated identity along with a summary of its
+// effective permissions.
+func (r *controllerRoot) Whoami(ctx context.Context) (apiparams.IdentitySummary, error) {
+	const op = errors.Op("jujuapi.Whoami")
+
+	summary, err := r.jimm.Whoami(ctx, r.user)
+	if err != nil {
+		return apiparams.IdentitySummary{}, errors.E(op, err)
+	}
+	return summary, nil
+}
+
+// InjectFault injects a fault into JIMM to test its resilience to
+// controller and database failures. This is only supported by JIMM
+// servers built with fault injection enabled.
+func (r *controllerRoot) InjectFault(ctx context.Context, req apiparams.Fault) error {
+	const op = errors.Op("jujuapi.InjectFault")
+
+	f := faults.Fault{
+		Point:  faults.Point(req.Point),
+		Target: req.Target,
+		Count:  req.Count,
+	}
+	if req.Delay != "" {
+		var err error
+		f.Delay, err = time.ParseDuration(req.Delay)
+		if err != nil {
+			return errors.E(op, err, errors.CodeBadRequest, "invalid delay")
+		}
+	}
+	if err := r.jimm.InjectFault(ctx, r.user, f); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ClearFaults removes all the faults injected into JIMM.
+func (r *controllerRoot) ClearFaults(ctx context.Context) error {
+	const op = errors.Op("jujuapi.ClearFaults")
+
+	if err := r.jimm.ClearFaults(ctx, r.user); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListFaults returns the faults injected into JIMM.
+func (r *controllerRoot) ListFaults(ctx context.Context) (apiparams.ListFaultsResponse, error) {
+	const op = errors.Op("jujuapi.ListFaults")
+
+	fs, err := r.jimm.ListFaults(ctx, r.user)
+	if err != nil {
+		return apiparams.ListFaultsResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ListFaultsResponse{
+		Faults: make([]apiparams.Fault, len(fs)),
+	}
+	for i, f := range fs {
+		resp.Faults[i] = apiparams.Fault{
+			Point:  string(f.Point),
+			Target: f.Target,
+			Count:  f.Count,
+		}
+		if f.Delay > 0 {
+			resp.Faults[i].Delay = f.Delay.String()
+		}
+	}
+	return resp, nil
+}
+
+// AddNamespaceReservation reserves the model names starting with a prefix
+// for the members of a group.
+func (r *controllerRoot) AddNamespaceReservation(ctx context.Context, req apiparams.AddNamespaceReservationRequest) error {
+	const op = errors.Op("jujuapi.AddNamespaceReservation")
+
+	if err := r.jimm.AddNamespaceReservation(ctx, r.user, req.Prefix, req.Group); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListNamespaceReservations returns all namespace reservations.
+func (r *controllerRoot) ListNamespaceReservations(ctx context.Context) (apiparams.ListNamespaceReservationsResponse, error) {
+	const op = errors.Op("jujuapi.ListNamespaceReservations")
+
+	reservations, err := r.jimm.ListNamespaceReservations(ctx, r.user)
+	if err != nil {
+		return apiparams.ListNamespaceReservationsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListNamespaceReservationsResponse{Reservations: reservations}, nil
+}
+
+// TransferNamespaceReservation transfers a namespace reservation to
+// another group.
+func (r *controllerRoot) TransferNamespaceReservation(ctx context.Context, req apiparams.TransferNamespaceReservationRequest) error {
+	const op = errors.Op("jujuapi.TransferNamespaceReservation")
+
+	if err := r.jimm.TransferNamespaceReservation(ctx, r.user, req.Prefix, req.Group); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveNamespaceReservation removes a namespace reservation.
+func (r *controllerRoot) RemoveNamespaceReservation(ctx context.Context, req apiparams.RemoveNamespaceReservationRequest) error {
+	const op = errors.Op("jujuapi.RemoveNamespaceReservation")
+
+	if err := r.jimm.RemoveNamespaceReservation(ctx, r.user, req.Prefix); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// AddModelACLTemplate adds a template granting a group access to every
+// new model owned by a member of another group.
+func (r *controllerRoot) AddModelACLTemplate(ctx context.Context, req apiparams.AddModelACLTemplateRequest) (apiparams.ModelACLTemplate, error) {
+	const op = errors.Op("jujuapi.AddModelACLTemplate")
+
+	t, err := r.jimm.AddModelACLTemplate(ctx, r.user, req.OwnerGroup, req.GrantGroup, req.Access)
+	if err != nil {
+		return apiparams.ModelACLTemplate{}, errors.E(op, err)
+	}
+	return t, nil
+}
+
+// ListModelACLTemplates returns all model ACL templates.
+func (r *controllerRoot) ListModelACLTemplates(ctx context.Context) (apiparams.ListModelACLTemplatesResponse, error) {
+	const op = errors.Op("jujuapi.ListModelACLTemplates")
+
+	templates, err := r.jimm.ListModelACLTemplates(ctx, r.user)
+	if err != nil {
+		return apiparams.ListModelACLTemplatesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelACLTemplatesResponse{Templates: templates}, nil
+}
+
+// RemoveModelACLTemplate removes a model ACL template.
+func (r *controllerRoot) RemoveModelACLTemplate(ctx context.Context, req apiparams.RemoveModelACLTemplateRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelACLTemplate")
+
+	if err := r.jimm.RemoveModelACLTemplate(ctx, r.user, req.ID); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// CreateModelToken creates a long-lived token granting access to a single
+// model.
+func (r *controllerRoot) CreateModelToken(ctx context.Context, req apiparams.CreateModelTokenRequest) (apiparams.CreateModelTokenResponse, error) {
+	const op = errors.Op("jujuapi.CreateModelToken")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.CreateModelTokenResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	t, token, err := r.jimm.CreateModelToken(ctx, r.user, mt, req.Access, req.Description)
+	if err != nil {
+		return apiparams.CreateModelTokenResponse{}, errors.E(op, err)
+	}
+	return apiparams.CreateModelTokenResponse{
+		ModelToken: t,
+		Token:      token,
+	}, nil
+}
+
+// ListModelTokens returns the tokens granting access to a model.
+func (r *controllerRoot) ListModelTokens(ctx context.Context, req apiparams.ListModelTokensRequest) (apiparams.ListModelTokensResponse, error) {
+	const op = errors.Op("jujuapi.ListModelTokens")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.ListModelTokensResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	tokens, err := r.jimm.ListModelTokens(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ListModelTokensResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelTokensResponse{Tokens: tokens}, nil
+}
+
+// RevokeModelToken revokes a model token.
+func (r *controllerRoot) RevokeModelToken(ctx context.Context, req apiparams.RevokeModelTokenRequest) error {
+	const op = errors.Op("jujuapi.RevokeModelToken")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.RevokeModelToken(ctx, r.user, mt, req.ID); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// FreezeModel freezes a model, after which mutating calls made to the
+// model are rejected.
+func (r *controllerRoot) FreezeModel(ctx context.Context, req apiparams.FreezeModelRequest) (apiparams.ModelFreeze, error) {
+	const op = errors.Op("jujuapi.FreezeModel")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.ModelFreeze{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	var until time.Time
+	if req.Until != nil {
+		until = *req.Until
+	}
+	f, err := r.jimm.FreezeModel(ctx, r.user, mt, until, req.Reason)
+	if err != nil {
+		return apiparams.ModelFreeze{}, errors.E(op, err)
+	}
+	return f, nil
+}
+
+// UnfreezeModel removes the freeze on a model.
+func (r *controllerRoot) UnfreezeModel(ctx context.Context, req apiparams.UnfreezeModelRequest) error {
+	const op = errors.Op("jujuapi.UnfreezeModel")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.UnfreezeModel(ctx, r.user, mt); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListPendingModelDestroys returns the models waiting to be destroyed at
+// the end of their destroy confirmation window.
+func (r *controllerRoot) ListPendingModelDestroys(ctx context.Context) (apiparams.ListPendingModelDestroysResponse, error) {
+	const op = errors.Op("jujuapi.ListPendingModelDestroys")
+
+	destroys, err := r.jimm.ListPendingModelDestroys(ctx, r.user)
+	if err != nil {
+		return apiparams.ListPendingModelDestroysResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListPendingModelDestroysResponse{Destroys: destroys}, nil
+}
+
+// CancelModelDestroy cancels the pending destroy of a model.
+func (r *controllerRoot) CancelModelDestroy(ctx context.Context, req apiparams.CancelModelDestroyRequest) error {
+	const op = errors.Op("jujuapi.CancelModelDestroy")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := r.jimm.CancelModelDestroy(ctx, r.user, mt); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListDeletedModels returns the retained records of the models removed
+// from JIMM that the authenticated user may inspect.
+func (r *controllerRoot) ListDeletedModels(ctx context.Context) (apiparams.ListDeletedModelsResponse, error) {
+	const op = errors.Op("jujuapi.ListDeletedModels")
+
+	models, err := r.jimm.ListDeletedModels(ctx, r.user)
+	if err != nil {
+		return apiparams.ListDeletedModelsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListDeletedModelsResponse{Models: models}, nil
+}
+
+// GetDeletedModel returns the retained record of a model removed from
+// JIMM.
+func (r *controllerRoot) GetDeletedModel(ctx context.Context, req apiparams.DeletedModelRequest) (apiparams.DeletedModel, error) {
+	const op = errors.Op("jujuapi.GetDeletedModel")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.DeletedModel{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	dm, err := r.jimm.GetDeletedModel(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.DeletedModel{}, errors.E(op, err)
+	}
+	return dm, nil
+}
+
+// RestoreModel restores a model removed from JIMM.
+func (r *controllerRoot) RestoreModel(ctx context.Context, req apiparams.DeletedModelRequest) error {
+	const op = errors.Op("jujuapi.RestoreModel")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.RestoreModel(ctx, r.user, mt); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// SetModelNetworkPolicy attaches a network policy to a model and applies
+// it to the model's controller where the provider supports it.
+func (r *controllerRoot) SetModelNetworkPolicy(ctx context.Context, req apiparams.SetModelNetworkPolicyRequest) (apiparams.ModelNetworkPolicy, error) {
+	const op = errors.Op("jujuapi.SetModelNetworkPolicy")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	p, err := r.jimm.SetModelNetworkPolicy(ctx, r.user, mt, req.AllowedEgress)
+	if err != nil {
+		return apiparams.ModelNetworkPolicy{}, errors.E(op, err)
+	}
+	return p, nil
+}
+
+// RemoveModelNetworkPolicy removes the network policy from a model.
+func (r *controllerRoot) RemoveModelNetworkPolicy(ctx context.Context, req apiparams.RemoveModelNetworkPolicyRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelNetworkPolicy")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return errors.E(op, err, errors.CodeBadRequest)
+	}
+	if err := r.jimm.RemoveModelNetworkPolicy(ctx, r.user, mt); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelNetworkPolicies lists the model network policies, optionally
+// only those that could not be enforced.
+func (r *controllerRoot) ListModelNetworkPolicies(ctx context.Context, req apiparams.ListModelNetworkPoliciesRequest) (apiparams.ListModelNetworkPoliciesResponse, error) {
+	const op = errors.Op("jujuapi.ListModelNetworkPolicies")
+
+	policies, err := r.jimm.ListModelNetworkPolicies(ctx, r.user, req.Unenforced)
+	if err != nil {
+		return apiparams.ListModelNetworkPoliciesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelNetworkPoliciesResponse{Policies: policies}, nil
+}
+
+// AddModelDependency declares that a model depends on another model.
+// Models that other models depend on are only destroyed with force.
+func (r *controllerRoot) AddModelDependency(ctx context.Context, req apiparams.AddModelDependencyRequest) (apiparams.ModelDependency, error) {
+	const op = errors.Op("jujuapi.AddModelDependency")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	dependsOn, err := r.jimm.ResolveModel(ctx, r.user, req.DependsOnModelTag)
+	if err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	dep, err := r.jimm.AddModelDependency(ctx, r.user, mt, dependsOn, req.Description)
+	if err != nil {
+		return apiparams.ModelDependency{}, errors.E(op, err)
+	}
+	return dep, nil
+}
+
+// RemoveModelDependency removes a model's dependency on another model.
+func (r *controllerRoot) RemoveModelDependency(ctx context.Context, req apiparams.RemoveModelDependencyRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelDependency")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	dependsOn, err := r.jimm.ResolveModel(ctx, r.user, req.DependsOnModelTag)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := r.jimm.RemoveModelDependency(ctx, r.user, mt, dependsOn); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ModelDependencies returns the dependencies of a model, the models that
+// depend on it and the models affected if it is destroyed.
+func (r *controllerRoot) ModelDependencies(ctx context.Context, req apiparams.ModelDependenciesRequest) (apiparams.ModelDependencies, error) {
+	const op = errors.Op("jujuapi.ModelDependencies")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ModelDependencies{}, errors.E(op, err)
+	}
+	deps, err := r.jimm.ModelDependencies(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ModelDependencies{}, errors.E(op, err)
+	}
+	return deps, nil
+}
+
+// AddModelPool adds a pool of pre-provisioned models kept ready to be
+// claimed when models are created.
+func (r *controllerRoot) AddModelPool(ctx context.Context, req apiparams.AddModelPoolRequest) (apiparams.ModelPool, error) {
+	const op = errors.Op("jujuapi.AddModelPool")
+
+	ct, err := names.ParseCloudTag(req.CloudTag)
+	if err != nil {
+		return apiparams.ModelPool{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	cct, err := names.ParseCloudCredentialTag(req.CloudCredentialTag)
+	if err != nil {
+		return apiparams.ModelPool{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	p, err := r.jimm.AddModelPool(ctx, r.user, req.Name, ct, req.CloudRegion, cct, req.Size, req.Config)
+	if err != nil {
+		return apiparams.ModelPool{}, errors.E(op, err)
+	}
+	return p, nil
+}
+
+// RemoveModelPool removes a pool of pre-provisioned models, destroying
+// the models ready in the pool.
+func (r *controllerRoot) RemoveModelPool(ctx context.Context, req apiparams.RemoveModelPoolRequest) error {
+	const op = errors.Op("jujuapi.RemoveModelPool")
+
+	if err := r.jimm.RemoveModelPool(ctx, r.user, req.Name); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// ListModelPools lists the pools of pre-provisioned models.
+func (r *controllerRoot) ListModelPools(ctx context.Context) (apiparams.ListModelPoolsResponse, error) {
+	const op = errors.Op("jujuapi.ListModelPools")
+
+	pools, err := r.jimm.ListModelPools(ctx, r.user)
+	if err != nil {
+		return apiparams.ListModelPoolsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelPoolsResponse{Pools: pools}, nil
+}
+
+// ListIdleModels lists the models that have had no unit changes and no
+// user connections for the requested number of days.
+func (r *controllerRoot) ListIdleModels(ctx context.Context, req apiparams.ListIdleModelsRequest) (apiparams.ListIdleModelsResponse, error) {
+	const op = errors.Op("jujuapi.ListIdleModels")
+
+	models, err := r.jimm.ListIdleModels(ctx, r.user, req.IdleDays)
+	if err != nil {
+		return apiparams.ListIdleModelsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListIdleModelsResponse{Models: models}, nil
+}
+
+// ArchiveAndDestroyModel stores the bundle of a model in the object store
+// and then destroys the model.
+func (r *controllerRoot) ArchiveAndDestroyModel(ctx context.Context, req apiparams.ArchiveAndDestroyModelRequest) (apiparams.ArchiveAndDestroyModelResponse, error) {
+	const op = errors.Op("jujuapi.ArchiveAndDestroyModel")
+
+	mt, err := names.ParseModelTag(req.ModelTag)
+	if err != nil {
+		return apiparams.ArchiveAndDestroyModelResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	key, err := r.jimm.ArchiveAndDestroyModel(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ArchiveAndDestroyModelResponse{}, errors.E(op, err)
+	}
+	return apiparams.ArchiveAndDestroyModelResponse{ArchiveKey: key}, nil
+}
+
+// RequestAccess records a request for access to a model or cloud, which
+// the administrators of the model or cloud may then approve or deny.
+func (r *controllerRoot) RequestAccess(ctx context.Context, req apiparams.RequestAccessRequest) (apiparams.AccessRequest, error) {
+	const op = errors.Op("jujuapi.RequestAccess")
+
+	ar, err := r.jimm.RequestAccess(ctx, r.user, req.Target, req.Access, req.Reason)
+	if err != nil {
+		return apiparams.AccessRequest{}, errors.E(op, err)
+	}
+	return ar, nil
+}
+
+// ListAccessRequests returns the access requests made by, or reviewable
+// by, the authenticated user.
+func (r *controllerRoot) ListAccessRequests(ctx context.Context, req apiparams.ListAccessRequestsRequest) (apiparams.ListAccessRequestsResponse, error) {
+	const op = errors.Op("jujuapi.ListAccessRequests")
+
+	requests, err := r.jimm.ListAccessRequests(ctx, r.user, req.Status)
+	if err != nil {
+		return apiparams.ListAccessRequestsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListAccessRequestsResponse{Requests: requests}, nil
+}
+
+// ApproveAccessRequest approves an access request, granting the requested
+// access.
+func (r *controllerRoot) ApproveAccessRequest(ctx context.Context, req apiparams.ReviewAccessRequestRequest) error {
+	const op = errors.Op("jujuapi.ApproveAccessRequest")
+
+	if err := r.jimm.ApproveAccessRequest(ctx, r.user, req.ID, req.Comment); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// DenyAccessRequest denies an access request.
+func (r *controllerRoot) DenyAccessRequest(ctx context.Context, req apiparams.ReviewAccessRequestRequest) error {
+	const op = errors.Op("jujuapi.DenyAccessRequest")
+
+	if err := r.jimm.DenyAccessRequest(ctx, r.user, req.ID, req.Comment); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// PurgeLogs removes all audit log entries older than the specified date.
+func (r *controllerRoot) PurgeLogs(ctx context.Context, req apiparams.PurgeLogsRequest) (apiparams.PurgeLogsResponse, error) {
+	const op = errors.Op("jujuapi.PurgeLogs")
+
+	deleted_count, err := r.jimm.PurgeLogs(ctx, r.user, req.Date)
+	if err != nil {
+		return apiparams.PurgeLogsResponse{}, errors.E(op, err)
+	}
+	return apiparams.PurgeLogsResponse{
+		DeletedCount: deleted_count,
+	}, nil
+}
+
+// ResyncModelAccess starts re-syncing JIMM's view of model access to the
+// controllers hosting the models, returning the ID of the operation
+// reporting the differences found.
+func (r *controllerRoot) ResyncModelAccess(ctx context.Context, req apiparams.ResyncModelAccessRequest) (apiparams.ResyncModelAccessResponse, error) {
+	const op = errors.Op("jujuapi.ResyncModelAccess")
+
+	resp, err := r.jimm.ResyncModelAccess(ctx, r.user, req)
+	if err != nil {
+		return apiparams.ResyncModelAccessResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// RemapCloudRegion remaps a cloud region in JIMM's records, for example
+// after the cloud provider renamed or split the region.
+func (r *controllerRoot) RemapCloudRegion(ctx context.Context, req apiparams.RemapCloudRegionRequest) (apiparams.RemapCloudRegionResponse, error) {
+	const op = errors.Op("jujuapi.RemapCloudRegion")
+
+	resp, err := r.jimm.RemapCloudRegion(ctx, r.user, req)
+	if err != nil {
+		return apiparams.RemapCloudRegionResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// RotateWebhookSecret rotates the secret the deliveries to a webhook are
+// signed with, returning the new secret.
+func (r *controllerRoot) RotateWebhookSecret(ctx context.Context, req apiparams.RotateWebhookSecretRequest) (apiparams.WebhookSecret, error) {
+	const op = errors.Op("jujuapi.RotateWebhookSecret")
+
+	secret, err := r.jimm.RotateWebhookSecret(ctx, r.user, req.Webhook)
+	if err != nil {
+		return apiparams.WebhookSecret{}, errors.E(op, err)
+	}
+	return secret, nil
+}
+
+// ListWebhookDeliveries lists the signed deliveries to a webhook, newest
+// first.
+func (r *controllerRoot) ListWebhookDeliveries(ctx context.Context, req apiparams.ListWebhookDeliveriesRequest) (apiparams.ListWebhookDeliveriesResponse, error) {
+	const op = errors.Op("jujuapi.ListWebhookDeliveries")
+
+	deliveries, err := r.jimm.ListWebhookDeliveries(ctx, r.user, req.Webhook, req.Limit)
+	if err != nil {
+		return apiparams.ListWebhookDeliveriesResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListWebhookDeliveriesResponse{Deliveries: deliveries}, nil
+}
+
+// ExportModel exports the JIMM metadata of a model so that it can be
+// imported by another JIMM deployment.
+func (r *controllerRoot) ExportModel(ctx context.Context, req apiparams.ExportModelRequest) (apiparams.ModelExport, error) {
+	const op = errors.Op("jujuapi.ExportModel")
+
+	exp, err := r.jimm.ExportModel(ctx, r.user, req.ModelTag)
+	if err != nil {
+		return apiparams.ModelExport{}, errors.E(op, err)
+	}
+	return exp, nil
+}
+
+// PrepareModelImport prepares the import of a model exported from
+// another JIMM deployment.
+func (r *controllerRoot) PrepareModelImport(ctx context.Context, req apiparams.PrepareModelImportRequest) (apiparams.ModelImport, error) {
+	const op = errors.Op("jujuapi.PrepareModelImport")
+
+	mi, err := r.jimm.PrepareModelImport(ctx, r.user, req)
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	return mi, nil
+}
+
+// MigrateModelExternal migrates a model to a controller managed by
+// another JIMM deployment that has prepared the model's import.
+func (r *controllerRoot) MigrateModelExternal(ctx context.Context, req apiparams.MigrateModelExternalRequest) (apiparams.MigrateModelExternalResponse, error) {
+	const op = errors.Op("jujuapi.MigrateModelExternal")
+
+	resp, err := r.jimm.MigrateModelExternal(ctx, r.user, req)
+	if err != nil {
+		return apiparams.MigrateModelExternalResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// CompleteModelImport completes the import of a model once its migration
+// has finished.
+func (r *controllerRoot) CompleteModelImport(ctx context.Context, req apiparams.CompleteModelImportRequest) (apiparams.ModelImport, error) {
+	const op = errors.Op("jujuapi.CompleteModelImport")
+
+	mi, err := r.jimm.CompleteModelImport(ctx, r.user, req.ID)
+	if err != nil {
+		return apiparams.ModelImport{}, errors.E(op, err)
+	}
+	return mi, nil
+}
+
+// RepairDenormalizedFields recomputes the denormalized fields held on
+// models and controllers and repairs those that have drifted.
+func (r *controllerRoot) RepairDenormalizedFields(ctx context.Context, req apiparams.RepairDenormalizedFieldsRequest) (apiparams.RepairDenormalizedFieldsResponse, error) {
+	const op = errors.Op("jujuapi.RepairDenormalizedFields")
+
+	resp, err := r.jimm.RepairDenormalizedFields(ctx, r.user, req)
+	if err != nil {
+		return apiparams.RepairDenormalizedFieldsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ProvisionControllerServiceUser provisions the JIMM service user on a
+// controller, which JIMM then uses for all its connections to the
+// controller.
+func (r *controllerRoot) ProvisionControllerServiceUser(ctx context.Context, req apiparams.ProvisionControllerServiceUserRequest) (apiparams.ProvisionControllerServiceUserResponse, error) {
+	const op = errors.Op("jujuapi.ProvisionControllerServiceUser")
+
+	resp, err := r.jimm.ProvisionControllerServiceUser(ctx, r.user, req.Controller)
+	if err != nil {
+		return apiparams.ProvisionControllerServiceUserResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// GetOperation returns the progress of a long-running operation.
+func (r *controllerRoot) GetOperation(ctx context.Context, req apiparams.GetOperationRequest) (apiparams.Operation, error) {
+	const op = errors.Op("jujuapi.GetOperation")
+
+	o, err := r.jimm.GetOperation(ctx, r.user, req.ID)
+	if err != nil {
+		return apiparams.Operation{}, errors.E(op, err)
+	}
+	return o, nil
+}
+
+// ListOperations lists the long-running operations started by the
+// authenticated user, or by every user if requested.
+func (r *controllerRoot) ListOperations(ctx context.Context, req apiparams.ListOperationsRequest) (apiparams.ListOperationsResponse, error) {
+	const op = errors.Op("jujuapi.ListOperations")
+
+	ops, err := r.jimm.ListOperations(ctx, r.user, req.All)
+	if err != nil {
+		return apiparams.ListOperationsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListOperationsResponse{Operations: ops}, nil
+}
+
+// WatchOperation creates a watcher reporting the progress of a
+// long-running operation. The returned ID is used with the
+// OperationWatcher facade.
+func (r *controllerRoot) WatchOperation(ctx context.Context, req apiparams.WatchOperationRequest) (apiparams.OperationWatcherID, error) {
+	const op = errors.Op("jujuapi.WatchOperation")
+
+	// Check the operation can be seen before watching it.
+	if _, err := r.jimm.GetOperation(ctx, r.user, req.ID); err != nil {
+		return apiparams.OperationWatcherID{}, errors.E(op, err)
+	}
+	if err := r.setupUUIDGenerator(); err != nil {
+		return apiparams.OperationWatcherID{}, errors.E(op, err)
+	}
+	id := fmt.Sprintf("%v", r.generator.Next())
+	w := newOperationWatcher(defaultOperationWatcherPeriod, func(ctx context.Context) (apiparams.Operation, error) {
+		return r.jimm.GetOperation(ctx, r.user, req.ID)
+	})
+	r.watchers.register(id, w)
+	return apiparams.OperationWatcherID{WatcherID: id}, nil
+}
+
+// AuditControllerAccess reports the access held directly on the
+// controllers that was not granted through JIMM.
+func (r *controllerRoot) AuditControllerAccess(ctx context.Context, req apiparams.AuditControllerAccessRequest) (apiparams.AuditControllerAccessResponse, error) {
+	const op = errors.Op("jujuapi.AuditControllerAccess")
+
+	resp, err := r.jimm.AuditControllerAccess(ctx, r.user, req.Controller)
+	if err != nil {
+		return apiparams.AuditControllerAccessResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// CancelModelCreation cancels the creation of a model that is still in
+// progress.
+func (r *controllerRoot) CancelModelCreation(ctx context.Context, req apiparams.CancelModelCreationRequest) error {
+	const op = errors.Op("jujuapi.CancelModelCreation")
+
+	if err := r.jimm.CancelModelCreation(ctx, r.user, req.Model); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// MigrateModel is a JIMM specific method for migrating models between two controllers that
+// are already attached to JIMM. See InitiateMigration in controller.go to migrate a model
+// in a controller attached to JIMM to one not managed by JIMM.
+func (r *controllerRoot) MigrateModel(ctx context.Context, args apiparams.MigrateModelRequest) (jujuparams.InitiateMigrationResults, error) {
+	const op = errors.Op("jujuapi.MigrateModel")
+
+	results := make([]jujuparams.InitiateMigrationResult, len(args.Specs))
+	errs := newBulkErrors(op, len(args.Specs))
+	for i, arg := range args.Specs {
+		mt, err := r.jimm.ResolveModel(ctx, r.user, arg.ModelTag)
+		if err != nil {
+			results[i].Error = errs.add(i, arg.ModelTag, errors.E(op, err))
+			continue
+		}
+		result, err := r.jimm.InitiateInternalMigration(ctx, r.user, mt, arg.TargetController)
+		if err != nil {
+			result.Error = errs.add(i, arg.ModelTag, errors.E(op, err))
+		}
+		results[i] = result
+	}
+	errs.finish(ctx)
+
+	return jujuparams.InitiateMigrationResults{
+		Results: results,
+	}, nil
+}
+
+// MigrationPrechecks checks whether a model can be migrated to another
+// controller attached to JIMM, without starting the migration, and
+// returns a report of the checks made.
+func (r *controllerRoot) MigrationPrechecks(ctx context.Context, args apiparams.MigrateModelInfo) (apiparams.MigrationPrecheckReport, error) {
+	const op = errors.Op("jujuapi.MigrationPrechecks")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, args.ModelTag)
+	if err != nil {
+		return apiparams.MigrationPrecheckReport{}, errors.E(op, err)
+	}
+	report, err := r.jimm.MigrationPrechecks(ctx, r.user, mt, args.TargetController)
+	if err != nil {
+		return apiparams.MigrationPrecheckReport{}, errors.E(op, err)
+	}
+	return report, nil
+}
+
+// ModelMigrationStatus returns the status of the most recent migration of
+// a model.
+func (r *controllerRoot) ModelMigrationStatus(ctx context.Context, args apiparams.ModelMigrationStatusRequest) (apiparams.ModelMigrationStatus, error) {
+	const op = errors.Op("jujuapi.ModelMigrationStatus")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, args.ModelTag)
+	if err != nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, err)
+	}
+	status, err := r.jimm.ModelMigrationStatus(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ModelMigrationStatus{}, errors.E(op, err)
+	}
+	return status, nil
+}
+
+// ModelControllerEndpoints returns the API endpoints and CA certificate of
+// the controller hosting a model.
+func (r *controllerRoot) ModelControllerEndpoints(ctx context.Context, args apiparams.ModelControllerEndpointsRequest) (apiparams.ModelControllerEndpoints, error) {
+	const op = errors.Op("jujuapi.ModelControllerEndpoints")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, args.ModelTag)
+	if err != nil {
+		return apiparams.ModelControllerEndpoints{}, errors.E(op, err)
+	}
+	endpoints, err := r.jimm.ModelControllerEndpoints(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ModelControllerEndpoints{}, errors.E(op, err)
+	}
+	return endpoints, nil
+}
+
+// GetModelSummary returns the summary of a single model, built entirely
+// from the information held by JIMM so that no controller is contacted.
+func (r *controllerRoot) GetModelSummary(ctx context.Context, args apiparams.GetModelSummaryRequest) (apiparams.ModelSummary, error) {
+	const op = errors.Op("jujuapi.GetModelSummary")
+
+	mt, err := r.jimm.ResolveModel(ctx, r.user, args.ModelTag)
+	if err != nil {
+		return apiparams.ModelSummary{}, errors.E(op, err)
+	}
+	summary, err := r.jimm.GetModelSummary(ctx, r.user, mt)
+	if err != nil {
+		return apiparams.ModelSummary{}, errors.E(op, err)
+	}
+	return summary, nil
+}
+
+// ListModelMigrations lists the model migrations JIMM has initiated
+// between its controllers, optionally restricted to a single model or to
+// the migrations that have not finished.
+func (r *controllerRoot) ListModelMigrations(ctx context.Context, args apiparams.ListModelMigrationsRequest) (apiparams.ListModelMigrationsResponse, error) {
+	const op = errors.Op("jujuapi.ListModelMigrations")
+
+	var mt names.ModelTag
+	if args.ModelTag != "" {
+		var err error
+		mt, err = r.jimm.ResolveModel(ctx, r.user, args.ModelTag)
+		if err != nil {
+			return apiparams.ListModelMigrationsResponse{}, errors.E(op, err)
+		}
+	}
+	migrations, err := r.jimm.ListModelMigrations(ctx, r.user, mt, args.Active)
+	if err != nil {
+		return apiparams.ListModelMigrationsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListModelMigrationsResponse{Migrations: migrations}, nil
+}
+
+// RebalanceRecommendations returns recommended model migrations that
+// would balance the models across the controllers attached to JIMM. The
+// recommendations may be passed to MigrateModel.
+func (r *controllerRoot) RebalanceRecommendations(ctx context.Context, args apiparams.RebalanceRecommendationsRequest) (apiparams.RebalanceReport, error) {
+	const op = errors.Op("jujuapi.RebalanceRecommendations")
+
+	report, err := r.jimm.RebalanceRecommendations(ctx, r.user, args.Limit)
+	if err != nil {
+		return apiparams.RebalanceReport{}, errors.E(op, err)
+	}
+	return report, nil
+}
+
+// RebindModelCredentials rebinds the models using cloud credentials owned
+// by a departing user to credentials shared with a group.
+func (r *controllerRoot) RebindModelCredentials(ctx context.Context, req apiparams.RebindModelCredentialsRequest) (apiparams.RebindModelCredentialsResponse, error) {
+	const op = errors.Op("jujuapi.RebindModelCredentials")
+
+	ut, err := parseUserTag(req.UserTag)
+	if err != nil {
+		return apiparams.RebindModelCredentialsResponse{}, errors.E(op, err, errors.CodeBadRequest)
+	}
+	if req.Group == "" {
+		return apiparams.RebindModelCredentialsResponse{}, errors.E(op, errors.CodeBadRequest, "missing group")
+	}
+	rebinds, err := r.jimm.RebindModelCredentials(ctx, r.user, ut, req.Group, req.DryRun)
+	if err != nil {
+		return apiparams.RebindModelCredentialsResponse{}, errors.E(op, err)
+	}
+	return apiparams.RebindModelCredentialsResponse{
+		DryRun:  req.DryRun,
+		Rebinds: rebinds,
+	}, nil
+}
+
+// RotateCloudCredentials rotates the cloud credentials for a cloud in a
+// single run, reporting the outcome for each credential.
+func (r *controllerRoot) RotateCloudCredentials(ctx context.Context, req apiparams.RotateCloudCredentialsRequest) (apiparams.RotateCloudCredentialsResponse, error) {
+	const op = errors.Op("jujuapi.RotateCloudCredentials")
+
+	resp, err := r.jimm.RotateCloudCredentials(ctx, r.user, req)
+	if err != nil {
+		return apiparams.RotateCloudCredentialsResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ListCredentialPropagations lists the controllers cloud credentials have
+// been copied to, optionally restricted to a single credential or to the
+// revoked credentials whose removal has not been confirmed.
+func (r *controllerRoot) ListCredentialPropagations(ctx context.Context, req apiparams.ListCredentialPropagationsRequest) (apiparams.ListCredentialPropagationsResponse, error) {
+	const op = errors.Op("jujuapi.ListCredentialPropagations")
+
+	var tag names.CloudCredentialTag
+	if req.CredentialTag != "" {
+		var err error
+		tag, err = names.ParseCloudCredentialTag(req.CredentialTag)
+		if err != nil {
+			return apiparams.ListCredentialPropagationsResponse{}, errors.E(op, err, errors.CodeBadRequest)
+		}
+	}
+	propagations, err := r.jimm.ListCredentialPropagations(ctx, r.user, tag, req.Unconfirmed)
+	if err != nil {
+		return apiparams.ListCredentialPropagationsResponse{}, errors.E(op, err)
+	}
+	return apiparams.ListCredentialPropagationsResponse{Propagations: propagations}, nil
+}
+
+// ControllerCall calls an allowed controller facade method on a controller
+// on behalf of a JIMM administrator. Setting a controller's configuration
+// requires the user's identity to have been confirmed.
+func (r *controllerRoot) ControllerCall(ctx context.Context, req apiparams.ControllerCallRequest) (apiparams.ControllerCallResponse, error) {
+	const op = errors.Op("jujuapi.ControllerCall")
+
+	if req.Controller == "" {
+		return apiparams.ControllerCallResponse{}, errors.E(op, errors.CodeBadRequest, "missing controller")
+	}
+	if req.Facade == "" || req.Method == "" {
+		return apiparams.ControllerCallResponse{}, errors.E(op, errors.CodeBadRequest, "missing facade or method")
+	}
+	if req.Facade == "Controller" && req.Method == "ConfigSet" {
+		if err := r.requireConfirmation("setting controller config"); err != nil {
+			return apiparams.ControllerCallResponse{}, errors.E(op, err)
+		}
+	}
+	result, err := r.jimm.ControllerCall(ctx, r.user, req.Controller, req.Facade, req.Version, req.Method, req.Params)
+	if err != nil {
+		return apiparams.ControllerCallResponse{}, errors.E(op, err)
+	}
+	return apiparams.ControllerCallResponse{Result: result}, nil
+}
+
+// EnrolTOTP enrols a new TOTP authenticator for the authenticated user,
+// with which they may confirm their identity before sensitive operations.
+// If a confirmation period is configured enrolling an authenticator
+// requires the user's identity to have been confirmed within the period,
+// with a recently issued session token or a code from the authenticator
+// being replaced, so that a hijacked connection cannot enrol an
+// authenticator of its own. Replacing an enrolled authenticator always
+// requires confirmation.
+func (r *controllerRoot) EnrolTOTP(ctx context.Context) (apiparams.EnrolTOTPResponse, error) {
+	const op = errors.Op("jujuapi.EnrolTOTP")
+
+	if err := r.requireConfirmation("enrolling a TOTP authenticator"); err != nil {
+		return apiparams.EnrolTOTPResponse{}, errors.E(op, err)
+	}
+	resp, err := r.jimm.EnrolTOTP(ctx, r.user, r.confirmed())
+	if err != nil {
+		if errors.ErrorCode(err) == errors.CodeAlreadyExists {
+			return apiparams.EnrolTOTPResponse{}, errors.E(op, errors.CodeConfirmationRequired, "replacing a TOTP authenticator requires confirmation, confirm your identity with ConfirmIdentity first")
+		}
+		return apiparams.EnrolTOTPResponse{}, errors.E(op, err)
+	}
+	return resp, nil
+}
+
+// ConfirmIdentity confirms the authenticated user's identity with a TOTP
+// code or a recently issued session token, allowing sensitive operations
+// on this connection for the server's confirmation period.
+func (r *controllerRoot) ConfirmIdentity(ctx context.Context, req apiparams.ConfirmIdentityRequest) (apiparams.ConfirmIdentityResponse, error) {
+	const op = errors.Op("jujuapi.ConfirmIdentity")
+
+	authTime, err := r.jimm.ConfirmIdentity(ctx, r.user, req)
+	if err != nil {
+		return apiparams.ConfirmIdentityResponse{}, errors.E(op, err)
+	}
+	resp := apiparams.ConfirmIdentityResponse{
+		ConfirmedAt: authTime,
+	}
+	if p := r.params.ConfirmationPeriod; p > 0 {
+		if time.Since(authTime) >= p {
+			return apiparams.ConfirmIdentityResponse{}, errors.E(op, errors.CodeConfirmationRequired, fmt.Sprintf("session token was issued more than %s ago, log in again", p))
+		}
+		expires := authTime.Add(p)
+		resp.Expires = &expires
+	}
+	r.mu.Lock()
+	if authTime.After(r.confirmedAt) {
+		r.confirmedAt = authTime
+	}
+	r.mu.Unlock()
+	return resp, nil
 }
 
 // Version is a method on the JIMM facade that returns information on the version of JIMM.
@@ -4,6 +4,7 @@ package jujuapi_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -79,6 +80,73 @@ func (s *jimmSuite) TestListControllers(c *gc.C) {
 	}})
 }
 
+func (s *jimmSuite) TestWatchControllerHealth(c *gc.C) {
+	s.AddController(c, "controller-2", s.APIInfo(c))
+
+	conn := s.open(c, nil, "bob")
+	client := api.NewClient(conn)
+	_, err := client.WatchControllerHealth()
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	conn.Close()
+
+	conn = s.open(c, nil, "alice")
+	defer conn.Close()
+	client = api.NewClient(conn)
+	id, err := client.WatchControllerHealth()
+	c.Assert(err, gc.Equals, nil)
+	states, err := client.ControllerHealthWatcherNext(id)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(states, gc.HasLen, 2)
+	c.Check(states[0].Name, gc.Equals, "controller-1")
+	c.Check(states[0].Status, gc.Equals, "available")
+	c.Check(states[1].Name, gc.Equals, "controller-2")
+	c.Check(states[1].Status, gc.Equals, "available")
+	err = client.ControllerHealthWatcherStop(id)
+	c.Assert(err, gc.Equals, nil)
+}
+
+func (s *jimmSuite) TestWatchOperation(c *gc.C) {
+	ctx := context.Background()
+	o := dbmodel.Operation{
+		UUID:         "00000000-0000-0000-0000-000000000001",
+		Kind:         "rotate-cloud-credentials",
+		IdentityName: "alice@canonical.com",
+		Status:       dbmodel.OperationRunning,
+	}
+	err := s.JIMM.Database.AddOperation(ctx, &o)
+	c.Assert(err, gc.Equals, nil)
+
+	conn := s.open(c, nil, "bob")
+	client := api.NewClient(conn)
+	_, err = client.WatchOperation(&apiparams.WatchOperationRequest{ID: o.UUID})
+	c.Check(err, gc.ErrorMatches, `operation not found.*`)
+	conn.Close()
+
+	conn = s.open(c, nil, "alice")
+	defer conn.Close()
+	client = api.NewClient(conn)
+	id, err := client.WatchOperation(&apiparams.WatchOperationRequest{ID: o.UUID})
+	c.Assert(err, gc.Equals, nil)
+	op, err := client.OperationWatcherNext(id)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(op.ID, gc.Equals, o.UUID)
+	c.Check(op.Status, gc.Equals, dbmodel.OperationRunning)
+
+	o.Status = dbmodel.OperationSucceeded
+	o.Progress = 100
+	o.EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	err = s.JIMM.Database.UpdateOperation(ctx, &o)
+	c.Assert(err, gc.Equals, nil)
+	op, err = client.OperationWatcherNext(id)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(op.Status, gc.Equals, dbmodel.OperationSucceeded)
+	c.Check(op.Progress, gc.Equals, 100)
+	_, err = client.OperationWatcherNext(id)
+	c.Check(err, gc.ErrorMatches, `operation finished.*`)
+	err = client.OperationWatcherStop(id)
+	c.Assert(err, gc.Equals, nil)
+}
+
 func (s *jimmSuite) TestListControllersUnauthorized(c *gc.C) {
 	s.AddController(c, "controller-0", s.APIInfo(c))
 	s.AddController(c, "controller-2", s.APIInfo(c))
@@ -249,6 +317,49 @@ func (s *jimmSuite) TestAddControllerCustomTLSHostname(c *gc.C) {
 
 }
 
+func (s *jimmSuite) TestAddControllerClientAddresses(c *gc.C) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	info := s.APIInfo(c)
+
+	acr := apiparams.AddControllerRequest{
+		UUID:            info.ControllerUUID,
+		Name:            "controller-2",
+		APIAddresses:    info.Addrs,
+		ClientAddresses: []string{"127.0.0.1:17070"},
+		CACertificate:   info.CACert,
+		Username:        info.Tag.Id(),
+		Password:        info.Password,
+	}
+	_, err := client.AddController(&acr)
+	c.Assert(err, gc.ErrorMatches, `address 127.0.0.1:17070: local-machine address cannot be used as a client address \(bad request\)`)
+
+	acr.ClientAddresses = []string{"controller.example.com"}
+	_, err = client.AddController(&acr)
+	c.Assert(err, gc.ErrorMatches, `.*missing port in address \(bad request\)`)
+
+	acr.ClientAddresses = []string{"controller.example.com:17070", "203.0.113.10:17070"}
+	ci, err := client.AddController(&acr)
+	c.Assert(err, gc.IsNil)
+	c.Check(ci.APIAddresses, jc.DeepEquals, info.Addrs)
+	c.Check(ci.ClientAddresses, jc.DeepEquals, []string{"controller.example.com:17070", "203.0.113.10:17070"})
+
+	ctl := dbmodel.Controller{Name: "controller-2"}
+	err = s.JIMM.Database.GetController(context.Background(), &ctl)
+	c.Assert(err, gc.IsNil)
+	ri := ctl.ToJujuRedirectInfoResult()
+	c.Assert(ri.Servers, gc.HasLen, 1)
+	c.Check(ri.Servers[0], jc.DeepEquals, []jujuparams.HostPort{{
+		Address: jujuparams.Address{Value: "controller.example.com", Type: "hostname", Scope: "public"},
+		Port:    17070,
+	}, {
+		Address: jujuparams.Address{Value: "203.0.113.10", Type: "ipv4", Scope: "public"},
+		Port:    17070,
+	}})
+}
+
 func (s *jimmSuite) TestRemoveController(c *gc.C) {
 	conn := s.open(c, nil, "alice")
 	defer conn.Close()
@@ -348,6 +459,79 @@ func (s *jimmSuite) TestSetControllerDeprecated(c *gc.C) {
 	c.Check(jujuparams.IsCodeUnauthorized(err), gc.Equals, true)
 }
 
+func (s *jimmSuite) TestSetControllerTimeouts(c *gc.C) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	ci, err := client.SetControllerTimeouts(&apiparams.SetControllerTimeoutsRequest{
+		Name:              "controller-1",
+		DialTimeout:       "45s",
+		KeepaliveInterval: "2m",
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(ci.DialTimeout, gc.Equals, "45s")
+	c.Check(ci.RPCTimeout, gc.Equals, "")
+	c.Check(ci.KeepaliveInterval, gc.Equals, "2m0s")
+
+	_, err = client.SetControllerTimeouts(&apiparams.SetControllerTimeoutsRequest{
+		Name:        "controller-1",
+		DialTimeout: "soon",
+	})
+	c.Check(jujuparams.ErrCode(err), gc.Equals, jujuparams.CodeBadRequest)
+
+	_, err = client.SetControllerTimeouts(&apiparams.SetControllerTimeoutsRequest{
+		Name:              "controller-1",
+		KeepaliveInterval: "10ms",
+	})
+	c.Check(err, gc.ErrorMatches, `keepalive interval must be at least 1s \(bad request\)`)
+
+	conn = s.open(c, nil, "bob")
+	defer conn.Close()
+	client = api.NewClient(conn)
+	_, err = client.SetControllerTimeouts(&apiparams.SetControllerTimeoutsRequest{
+		Name:        "controller-1",
+		DialTimeout: "45s",
+	})
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	c.Check(jujuparams.IsCodeUnauthorized(err), gc.Equals, true)
+}
+
+func (s *jimmSuite) TestSetControllerTiers(c *gc.C) {
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	ci, err := client.SetControllerTiers(&apiparams.SetControllerTiersRequest{
+		Name:  "controller-1",
+		Tiers: []string{"production", "gpu", "production"},
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(ci.Tiers, gc.DeepEquals, []string{"gpu", "production"})
+
+	_, err = client.SetControllerTiers(&apiparams.SetControllerTiersRequest{
+		Name:  "controller-1",
+		Tiers: []string{"Not A Tier"},
+	})
+	c.Check(err, gc.ErrorMatches, `invalid controller tier "Not A Tier" \(bad request\)`)
+
+	ci, err = client.SetControllerTiers(&apiparams.SetControllerTiersRequest{
+		Name: "controller-1",
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(ci.Tiers, gc.HasLen, 0)
+
+	conn = s.open(c, nil, "bob")
+	defer conn.Close()
+	client = api.NewClient(conn)
+	_, err = client.SetControllerTiers(&apiparams.SetControllerTiersRequest{
+		Name:  "controller-1",
+		Tiers: []string{"gpu"},
+	})
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	c.Check(jujuparams.IsCodeUnauthorized(err), gc.Equals, true)
+}
+
 func (s *jimmSuite) TestAuditLog(c *gc.C) {
 	conn := s.open(c, nil, "bob")
 	defer conn.Close()
@@ -558,6 +742,125 @@ func (s *jimmSuite) TestFullModelStatus(c *gc.C) {
 	})
 }
 
+func (s *jimmSuite) TestDestroyModelsDryRun(c *gc.C) {
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	destroyStorage := false
+	results, err := client.DestroyModelsDryRun(&apiparams.DestroyModelsDryRunRequest{
+		Models: []apiparams.DestroyModelDryRunParams{{
+			ModelTag:       "invalid-model-tag",
+			DestroyStorage: &destroyStorage,
+		}, {
+			ModelTag:       s.Model.ResourceTag().String(),
+			DestroyStorage: &destroyStorage,
+		}, {
+			ModelTag:       s.Model3.ResourceTag().String(),
+			DestroyStorage: &destroyStorage,
+		}},
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Check(results[0].Error, gc.ErrorMatches, `"invalid-model-tag" is not a valid tag`)
+	c.Check(results[1].Error, gc.IsNil)
+	c.Check(results[1].Result, jimmtest.CmpEquals(cmpopts.EquateEmpty()), &apiparams.DestroyModelImpact{
+		ModelTag: s.Model.ResourceTag().String(),
+	})
+	c.Check(results[2].Error, gc.ErrorMatches, `unauthorized`)
+
+	// The dry run must not have destroyed the model.
+	m := dbmodel.Model{UUID: s.Model.UUID}
+	err = s.JIMM.Database.GetModel(context.Background(), &m)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(m.Life, gc.Equals, s.Model.Life)
+}
+
+func (s *jimmSuite) TestModelMachines(c *gc.C) {
+	err := s.JIMM.Database.UpsertMachine(context.Background(), &dbmodel.Machine{
+		ModelID:          s.Model.ID,
+		MachineID:        "0",
+		InstanceID:       "i-0",
+		Life:             "alive",
+		AvailabilityZone: "zone-1",
+		Addresses: dbmodel.Addresses{{
+			Value: "10.0.0.4",
+			CIDR:  "10.0.0.0/24",
+			Type:  "ipv4",
+			Scope: "local-cloud",
+		}},
+		Subnets: dbmodel.Strings{"10.0.0.0/24"},
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	_, err = client.ModelMachines(&apiparams.ModelMachinesRequest{
+		ModelTag: "invalid-model-tag",
+	})
+	c.Check(err, gc.ErrorMatches, `"invalid-model-tag" is not a valid tag \(bad request\)`)
+
+	// bob only has read access to model-3.
+	_, err = client.ModelMachines(&apiparams.ModelMachinesRequest{
+		ModelTag: s.Model3.ResourceTag().String(),
+	})
+	c.Check(err, gc.ErrorMatches, `unauthorized.*`)
+
+	machines, err := client.ModelMachines(&apiparams.ModelMachinesRequest{
+		ModelTag: s.Model.ResourceTag().String(),
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(machines, jc.DeepEquals, []apiparams.ModelMachine{{
+		ID:               "0",
+		InstanceID:       "i-0",
+		Life:             "alive",
+		AvailabilityZone: "zone-1",
+		Addresses: []jujuparams.Address{{
+			Value: "10.0.0.4",
+			CIDR:  "10.0.0.0/24",
+			Type:  "ipv4",
+			Scope: "local-cloud",
+		}},
+		Subnets: []string{"10.0.0.0/24"},
+	}})
+}
+
+func (s *jimmSuite) TestModelsStatus(c *gc.C) {
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	_, err := api.NewClient(conn).ModelsStatus(&apiparams.ModelsStatusRequest{})
+	c.Check(err, gc.ErrorMatches, `unauthorized.*`)
+
+	conn = s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	resp, err := client.ModelsStatus(&apiparams.ModelsStatusRequest{
+		Controller: "controller-1",
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(resp.Controllers, gc.HasLen, 1)
+	c.Check(resp.Controllers[0].Name, gc.Equals, "controller-1")
+	c.Check(resp.Controllers[0].Available, gc.Equals, true)
+	c.Check(resp.Controllers[0].ModelCount, gc.Equals, int64(len(resp.Models)))
+	modelTags := make(map[string]bool)
+	for _, m := range resp.Models {
+		c.Check(m.Controller, gc.Equals, "controller-1")
+		c.Check(m.Health, gc.Equals, apiparams.WorkloadHealthy)
+		modelTags[m.ModelTag] = true
+	}
+	c.Check(modelTags[s.Model.ResourceTag().String()], gc.Equals, true)
+	c.Check(modelTags[s.Model2.ResourceTag().String()], gc.Equals, true)
+	c.Check(modelTags[s.Model3.ResourceTag().String()], gc.Equals, true)
+
+	_, err = client.ModelsStatus(&apiparams.ModelsStatusRequest{
+		Controller: "no-such-controller",
+	})
+	c.Check(err, gc.ErrorMatches, `controller not found.*`)
+}
+
 func (s *jimmSuite) TestUpdateMigratedModel(c *gc.C) {
 	s.AddController(c, "controller-2", s.APIInfo(c))
 
@@ -900,3 +1203,399 @@ func (s *jimmSuite) TestVersion(c *gc.C) {
 	c.Assert(versionInfo.Version, gc.Not(gc.Equals), "")
 	c.Assert(versionInfo.Commit, gc.Not(gc.Equals), "")
 }
+
+func (s *jimmSuite) TestCrossModelRelationGraph(c *gc.C) {
+	ctx := context.Background()
+	offer := dbmodel.ApplicationOffer{
+		ModelID:         s.Model2.ID,
+		ApplicationName: "db",
+		Name:            "db",
+		UUID:            "00000000-0000-0000-0000-0000-0000000000010",
+		URL:             "controller-1:charlie@canonical.com/model-2.db",
+		Connections: []dbmodel.ApplicationOfferConnection{{
+			SourceModelTag: s.Model3.ResourceTag().String(),
+			RelationID:     1,
+			IdentityName:   "charlie@canonical.com",
+			Endpoint:       "db",
+		}},
+	}
+	err := s.JIMM.Database.AddApplicationOffer(ctx, &offer)
+	c.Assert(err, gc.Equals, nil)
+
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	_, err = api.NewClient(bobConn).CrossModelRelationGraph(&apiparams.CrossModelRelationGraphRequest{})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	_, err = client.CrossModelRelationGraph(&apiparams.CrossModelRelationGraphRequest{ModelTag: "not-a-tag"})
+	c.Assert(err, gc.ErrorMatches, `.*"not-a-tag" is not a valid tag.*`)
+
+	graph, err := client.CrossModelRelationGraph(&apiparams.CrossModelRelationGraphRequest{
+		ModelTag: s.Model3.ResourceTag().String(),
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(graph.Relations, jc.DeepEquals, []apiparams.CrossModelRelation{{
+		OfferURL:          offer.URL,
+		OfferUUID:         offer.UUID,
+		OfferingModelTag:  s.Model2.ResourceTag().String(),
+		ConsumingModelTag: s.Model3.ResourceTag().String(),
+		RelationID:        1,
+		Endpoint:          "db",
+		Username:          "charlie@canonical.com",
+	}})
+	c.Assert(graph.Models, gc.HasLen, 2)
+	c.Check(graph.Models[0].Controller, gc.Equals, s.Model2.Controller.Name)
+}
+
+func (s *jimmSuite) TestListLeaders(c *gc.C) {
+	ctx := context.Background()
+	now := time.Now().UTC().Round(time.Millisecond)
+	acquired, err := s.JIMM.Database.AcquireLease(ctx, &dbmodel.Lease{
+		Name:    "jwks-rotator",
+		Holder:  "replica-1",
+		Expires: now.Add(time.Minute),
+	}, now)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(acquired, gc.Equals, true)
+
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	_, err = api.NewClient(bobConn).ListLeaders()
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	leaders, err := api.NewClient(conn).ListLeaders()
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(leaders, gc.HasLen, 1)
+	c.Check(leaders[0].Worker, gc.Equals, "jwks-rotator")
+	c.Check(leaders[0].Holder, gc.Equals, "replica-1")
+	c.Check(leaders[0].Expires.Equal(now.Add(time.Minute)), gc.Equals, true)
+}
+
+func (s *jimmSuite) TestGrantRevokeCloudCredentialAccess(c *gc.C) {
+	credTag := names.NewCloudCredentialTag(jimmtest.TestCloudName + "/bob@canonical.com/cred")
+
+	charlieConn := s.open(c, nil, "charlie")
+	defer charlieConn.Close()
+	charlieClient := api.NewClient(charlieConn)
+	createModel := func(name string) error {
+		var mi jujuparams.ModelInfo
+		return charlieConn.APICall("ModelManager", 9, "", "CreateModel", jujuparams.ModelCreateArgs{
+			Name:               name,
+			OwnerTag:           names.NewUserTag("charlie@canonical.com").String(),
+			CloudTag:           names.NewCloudTag(jimmtest.TestCloudName).String(),
+			CloudCredentialTag: credTag.String(),
+		}, &mi)
+	}
+
+	err := charlieClient.GrantCloudCredentialAccess(&apiparams.CloudCredentialAccessRequest{
+		CloudCredentialTag: credTag.String(),
+		Entity:             "user-charlie@canonical.com",
+	})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	err = createModel("model-2")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	bobClient := api.NewClient(bobConn)
+
+	err = bobClient.GrantCloudCredentialAccess(&apiparams.CloudCredentialAccessRequest{
+		CloudCredentialTag: "not-a-tag",
+		Entity:             "user-charlie@canonical.com",
+	})
+	c.Assert(err, gc.ErrorMatches, `.*\(bad request\)`)
+
+	err = bobClient.GrantCloudCredentialAccess(&apiparams.CloudCredentialAccessRequest{
+		CloudCredentialTag: credTag.String(),
+		Entity:             "user-charlie@canonical.com",
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	err = createModel("model-2")
+	c.Assert(err, gc.Equals, nil)
+
+	err = bobClient.RevokeCloudCredentialAccess(&apiparams.CloudCredentialAccessRequest{
+		CloudCredentialTag: credTag.String(),
+		Entity:             "user-charlie@canonical.com",
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	err = createModel("model-3")
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+}
+
+func (s *jimmSuite) TestModelTimeline(c *gc.C) {
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	_, err := api.NewClient(bobConn).ModelTimeline(&apiparams.ModelTimelineRequest{
+		ModelTag: s.Model3.ResourceTag().String(),
+	})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn := s.open(c, nil, "charlie")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	_, err = client.ModelTimeline(&apiparams.ModelTimelineRequest{ModelTag: "not-a-tag"})
+	c.Assert(err, gc.ErrorMatches, `.*"not-a-tag" is not a valid tag.*`)
+
+	_, err = client.ModelTimeline(&apiparams.ModelTimelineRequest{
+		ModelTag: s.Model2.ResourceTag().String(),
+		After:    "yesterday",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid "after" filter.*`)
+
+	timeline, err := client.ModelTimeline(&apiparams.ModelTimelineRequest{
+		ModelTag: s.Model2.ResourceTag().String(),
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(timeline.ModelTag, gc.Equals, s.Model2.ResourceTag().String())
+	c.Assert(timeline.Events, gc.Not(gc.HasLen), 0)
+	c.Check(timeline.Events[0].Type, gc.Equals, apiparams.ModelEventCreated)
+	c.Check(timeline.Events[0].Actor, gc.Equals, "user-charlie@canonical.com")
+}
+
+func (s *jimmSuite) TestExportModelBundle(c *gc.C) {
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	_, err := client.ExportModelBundle(&apiparams.ExportModelBundleRequest{ModelTag: "not-a-tag"})
+	c.Assert(err, gc.ErrorMatches, `.*"not-a-tag" is not a valid tag.*`)
+
+	_, err = client.ExportModelBundle(&apiparams.ExportModelBundleRequest{
+		ModelTag: s.Model2.ResourceTag().String(),
+	})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	// The model is exported by the hosting controller, which refuses to
+	// export a model without applications.
+	_, err = client.ExportModelBundle(&apiparams.ExportModelBundleRequest{
+		ModelTag:              s.Model3.ResourceTag().String(),
+		StripCloudConstraints: true,
+	})
+	c.Assert(err, gc.ErrorMatches, `.*nothing to export as there are no applications.*`)
+}
+
+func (s *jimmSuite) TestUserQuota(c *gc.C) {
+	conn := s.open(c, nil, "charlie")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	quota, err := client.UserQuota(&apiparams.UserQuotaRequest{})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(quota.UserTag, gc.Equals, "user-charlie@canonical.com")
+	c.Check(quota.Models.Used, gc.Equals, int64(2))
+	c.Check(quota.Models.Limit, gc.Equals, int64(0))
+
+	_, err = client.UserQuota(&apiparams.UserQuotaRequest{UserTag: "user-bob@canonical.com"})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	_, err = client.UserQuota(&apiparams.UserQuotaRequest{UserTag: "not-a-tag"})
+	c.Assert(err, gc.ErrorMatches, `.*"not-a-tag" is not a valid tag.*`)
+}
+
+func (s *jimmSuite) TestSetUserQuota(c *gc.C) {
+	models := int64(5)
+
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	bobClient := api.NewClient(bobConn)
+	err := bobClient.SetUserQuota(&apiparams.SetUserQuotaRequest{
+		UserTag: "user-charlie@canonical.com",
+		Models:  &models,
+	})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+	err = client.SetUserQuota(&apiparams.SetUserQuotaRequest{
+		UserTag: "user-charlie@canonical.com",
+		Models:  &models,
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	quota, err := client.UserQuota(&apiparams.UserQuotaRequest{UserTag: "user-charlie@canonical.com"})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(quota.Models.Limit, gc.Equals, int64(5))
+
+	err = client.RemoveUserQuota(&apiparams.RemoveUserQuotaRequest{UserTag: "user-charlie@canonical.com"})
+	c.Assert(err, gc.Equals, nil)
+	quota, err = client.UserQuota(&apiparams.UserQuotaRequest{UserTag: "user-charlie@canonical.com"})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(quota.Models.Limit, gc.Equals, int64(0))
+
+	err = client.SetUserQuota(&apiparams.SetUserQuotaRequest{UserTag: "not-a-tag"})
+	c.Assert(err, gc.ErrorMatches, `.*"not-a-tag" is not a valid tag.*`)
+}
+
+func (s *jimmSuite) TestSetPublicListing(c *gc.C) {
+	ctx := context.Background()
+	mt := s.Model.ResourceTag().String()
+
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	err := client.SetPublicListing(&apiparams.SetPublicListingRequest{})
+	c.Assert(err, gc.ErrorMatches, `missing model or offer \(bad request\)`)
+	err = client.SetPublicListing(&apiparams.SetPublicListingRequest{
+		ModelTag: mt,
+		OfferURL: "bob@canonical.com/model-1.offer",
+	})
+	c.Assert(err, gc.ErrorMatches, `specify either a model or an offer, not both \(bad request\)`)
+
+	err = client.SetPublicListing(&apiparams.SetPublicListingRequest{
+		ModelTag: mt,
+		Purpose:  "integration testing",
+	})
+	c.Assert(err, gc.Equals, nil)
+	catalog, err := s.JIMM.PublicCatalog(ctx)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(catalog.Models, gc.DeepEquals, []apiparams.PublicModel{{
+		Name:    "model-1",
+		Purpose: "integration testing",
+	}})
+
+	err = client.RemovePublicListing(&apiparams.RemovePublicListingRequest{ModelTag: mt})
+	c.Assert(err, gc.Equals, nil)
+	catalog, err = s.JIMM.PublicCatalog(ctx)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(catalog.Models, gc.HasLen, 0)
+}
+
+func (s *jimmSuite) TestNamespaceReservations(c *gc.C) {
+	ctx := context.Background()
+	_, err := s.JIMM.Database.AddGroup(ctx, "payments")
+	c.Assert(err, gc.Equals, nil)
+	_, err = s.JIMM.Database.AddGroup(ctx, "billing")
+	c.Assert(err, gc.Equals, nil)
+
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	bobClient := api.NewClient(bobConn)
+	err = bobClient.AddNamespaceReservation(&apiparams.AddNamespaceReservationRequest{
+		Prefix: "payments-*",
+		Group:  "payments",
+	})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+	_, err = bobClient.ListNamespaceReservations()
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+	err = client.AddNamespaceReservation(&apiparams.AddNamespaceReservationRequest{
+		Prefix: "payments-*",
+		Group:  "payments",
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	err = client.TransferNamespaceReservation(&apiparams.TransferNamespaceReservationRequest{
+		Prefix: "payments-*",
+		Group:  "billing",
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	reservations, err := client.ListNamespaceReservations()
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(reservations, gc.HasLen, 1)
+	c.Check(reservations[0].Prefix, gc.Equals, "payments-")
+	c.Check(reservations[0].Group, gc.Equals, "billing")
+
+	err = client.RemoveNamespaceReservation(&apiparams.RemoveNamespaceReservationRequest{
+		Prefix: "payments-",
+	})
+	c.Assert(err, gc.Equals, nil)
+	reservations, err = client.ListNamespaceReservations()
+	c.Assert(err, gc.Equals, nil)
+	c.Check(reservations, gc.HasLen, 0)
+}
+
+func (s *jimmSuite) TestAccessRequests(c *gc.C) {
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	bobClient := api.NewClient(bobConn)
+
+	_, err := bobClient.RequestAccess(&apiparams.RequestAccessRequest{
+		Target: s.Model3.ResourceTag().String(),
+		Access: "superuser",
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid model access "superuser" \(bad request\)`)
+
+	ar, err := bobClient.RequestAccess(&apiparams.RequestAccessRequest{
+		Target: s.Model3.ResourceTag().String(),
+		Access: "read",
+		Reason: "investigating an outage",
+	})
+	c.Assert(err, gc.Equals, nil)
+	c.Check(ar.Status, gc.Equals, "pending")
+
+	err = bobClient.ApproveAccessRequest(&apiparams.ReviewAccessRequestRequest{ID: ar.ID})
+	c.Assert(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn := s.open(c, nil, "charlie")
+	defer conn.Close()
+	client := api.NewClient(conn)
+	requests, err := client.ListAccessRequests(&apiparams.ListAccessRequestsRequest{Status: "pending"})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(requests, gc.HasLen, 1)
+	c.Check(requests[0].User, gc.Equals, "bob@canonical.com")
+	c.Check(requests[0].Reason, gc.Equals, "investigating an outage")
+
+	err = client.ApproveAccessRequest(&apiparams.ReviewAccessRequestRequest{ID: ar.ID})
+	c.Assert(err, gc.Equals, nil)
+	_, err = bobClient.ModelTimeline(&apiparams.ModelTimelineRequest{
+		ModelTag: s.Model3.ResourceTag().String(),
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	err = client.DenyAccessRequest(&apiparams.ReviewAccessRequestRequest{ID: ar.ID})
+	c.Assert(err, gc.ErrorMatches, `access request [0-9]+ has already been approved \(bad request\)`)
+}
+
+func (s *jimmSuite) TestControllerCall(c *gc.C) {
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	_, err := client.ControllerCall(&apiparams.ControllerCallRequest{
+		Controller: "controller-1",
+		Facade:     "Controller",
+		Version:    11,
+		Method:     "ControllerConfig",
+	})
+	c.Check(err, gc.ErrorMatches, `unauthorized \(unauthorized access\)`)
+
+	conn = s.open(c, nil, "alice")
+	defer conn.Close()
+	client = api.NewClient(conn)
+
+	_, err = client.ControllerCall(&apiparams.ControllerCallRequest{
+		Facade: "Controller",
+		Method: "ControllerConfig",
+	})
+	c.Check(err, gc.ErrorMatches, `missing controller \(bad request\)`)
+
+	_, err = client.ControllerCall(&apiparams.ControllerCallRequest{
+		Controller: "controller-1",
+		Facade:     "Controller",
+	})
+	c.Check(err, gc.ErrorMatches, `missing facade or method \(bad request\)`)
+
+	_, err = client.ControllerCall(&apiparams.ControllerCallRequest{
+		Controller: "controller-1",
+		Facade:     "UserManager",
+		Version:    3,
+		Method:     "AddUser",
+	})
+	c.Check(err, gc.ErrorMatches, `UserManager.AddUser may not be called on a controller \(bad request\)`)
+}
@@ -4,11 +4,15 @@ package jujuapi
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/names/v5"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
@@ -17,6 +21,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/openfga"
 	"github.com/canonical/jimm/v3/internal/servermon"
 	"github.com/canonical/jimm/v3/pkg/api/params"
+	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
 
 func init() {
@@ -60,6 +65,7 @@ type ModelManager interface {
 	AddModel(ctx context.Context, u *openfga.User, args *jimm.ModelCreateArgs) (_ *jujuparams.ModelInfo, err error)
 	ChangeModelCredential(ctx context.Context, user *openfga.User, modelTag names.ModelTag, cloudCredentialTag names.CloudCredentialTag) error
 	DestroyModel(ctx context.Context, u *openfga.User, mt names.ModelTag, destroyStorage *bool, force *bool, maxWait *time.Duration, timeout *time.Duration) error
+	DestroyModelImpact(ctx context.Context, u *openfga.User, mt names.ModelTag, destroyStorage *bool) (*params.DestroyModelImpact, error)
 	DumpModel(ctx context.Context, u *openfga.User, mt names.ModelTag, simplified bool) (string, error)
 	DumpModelDB(ctx context.Context, u *openfga.User, mt names.ModelTag) (map[string]interface{}, error)
 	ForEachModel(ctx context.Context, u *openfga.User, f func(*dbmodel.Model, jujuparams.UserAccessPermission) error) error
@@ -67,9 +73,10 @@ type ModelManager interface {
 	FullModelStatus(ctx context.Context, user *openfga.User, modelTag names.ModelTag, patterns []string) (*jujuparams.FullStatus, error)
 	GetModel(ctx context.Context, uuid string) (dbmodel.Model, error)
 	IdentityModelDefaults(ctx context.Context, user *dbmodel.Identity) (map[string]interface{}, error)
-	ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner string) error
+	ImportModel(ctx context.Context, user *openfga.User, controllerName string, modelTag names.ModelTag, newOwner, origin string) error
 	ModelDefaultsForCloud(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag) (jujuparams.ModelDefaultsResult, error)
 	ModelInfo(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelInfo, error)
+	ModelMachines(ctx context.Context, u *openfga.User, mt names.ModelTag) ([]dbmodel.Machine, error)
 	ModelStatus(ctx context.Context, u *openfga.User, mt names.ModelTag) (*jujuparams.ModelStatus, error)
 	QueryModelsJq(ctx context.Context, models []string, jqQuery string) (params.CrossModelQueryResponse, error)
 	SetModelDefaults(ctx context.Context, user *dbmodel.Identity, cloudTag names.CloudTag, region string, configs map[string]interface{}) error
@@ -96,6 +103,16 @@ func (r *controllerRoot) DumpModels(ctx context.Context, args jujuparams.DumpMod
 		results[i].Result, err = r.jimm.DumpModel(ctx, r.user, mt, args.Simplified)
 		if err != nil {
 			results[i].Error = mapError(errors.E(op, err))
+			continue
+		}
+		name := mt.Id() + "-dump.yaml"
+		url, err := r.jimm.StoreOversizedResult(ctx, r.user, name, "application/yaml", []byte(results[i].Result))
+		if err == nil && url != "" {
+			err = jimm.ResultTooLargeError(name, url)
+		}
+		if err != nil {
+			results[i].Result = ""
+			results[i].Error = mapError(errors.E(op, err))
 		}
 	}
 	return jujuparams.StringResults{
@@ -105,28 +122,66 @@ func (r *controllerRoot) DumpModels(ctx context.Context, args jujuparams.DumpMod
 
 // ListModelSummaries returns summaries for all the models that that
 // authenticated user has access to. The request parameter is ignored.
-func (r *controllerRoot) ListModelSummaries(ctx context.Context, _ jujuparams.ModelSummariesRequest) (jujuparams.ModelSummaryResults, error) {
+// Summaries are read from JIMM's database. If a model summaries deadline
+// is configured the summaries read by the deadline are returned, flagged
+// as incomplete, rather than failing the request. Summaries of models
+// hosted on controllers that are unavailable are flagged as stale.
+func (r *controllerRoot) ListModelSummaries(ctx context.Context, _ jujuparams.ModelSummariesRequest) (params.ModelSummaryResults, error) {
 	const op = errors.Op("jujuapi.ListModelSummaries")
 
-	var results []jujuparams.ModelSummaryResult
-	err := r.jimm.ForEachUserModel(ctx, r.user, func(m *dbmodel.Model, access jujuparams.UserAccessPermission) error {
+	summaryCtx := ctx
+	if r.params.ModelSummariesDeadline > 0 {
+		var cancel context.CancelFunc
+		summaryCtx, cancel = context.WithTimeout(ctx, r.params.ModelSummariesDeadline)
+		defer cancel()
+	}
+	errDeadline := errors.E("deadline exceeded")
+	var results []params.ModelSummaryResult
+	var stale bool
+	err := r.jimm.ForEachUserModel(summaryCtx, r.user, func(m *dbmodel.Model, access jujuparams.UserAccessPermission) error {
+		if summaryCtx.Err() != nil {
+			return errDeadline
+		}
 		// TODO(Kian) CSS-6040 Refactor the below to use a better abstraction for Postgres/OpenFGA to Juju types.
 		ms := m.ToJujuModelSummary()
 		ms.UserAccess = access
+		r.redactor.redactModelSummary(&ms)
 		if r.controllerUUIDMasking {
 			ms.ControllerUUID = r.params.ControllerUUID
 		}
-		result := jujuparams.ModelSummaryResult{
-			Result: &ms,
+		result := params.ModelSummaryResult{
+			ModelSummaryResult: jujuparams.ModelSummaryResult{
+				Result: &ms,
+			},
+		}
+		if m.Controller.UnavailableSince.Valid {
+			since := m.Controller.UnavailableSince.Time
+			result.StaleSince = &since
+			stale = true
 		}
 		results = append(results, result)
 		return nil
 	})
-	if err != nil {
-		return jujuparams.ModelSummaryResults{}, errors.E(op, err)
+	// Models whose access could not be determined after the deadline
+	// are skipped, so the results are incomplete even if the iteration
+	// completed.
+	incomplete := summaryCtx.Err() != nil && ctx.Err() == nil
+	if err != nil && !incomplete {
+		return params.ModelSummaryResults{}, errors.E(op, err)
 	}
-	return jujuparams.ModelSummaryResults{
-		Results: results,
+	if incomplete && len(results) == 0 {
+		return params.ModelSummaryResults{}, errors.E(op, errors.CodeTryAgain, "no model summaries read before the deadline")
+	}
+	if incomplete {
+		zapctx.Warn(ctx, "returning incomplete model summaries", zap.Int("count", len(results)), zap.NamedError("reason", err))
+		servermon.PartialModelSummariesCount.WithLabelValues("deadline").Inc()
+	}
+	if stale {
+		servermon.PartialModelSummariesCount.WithLabelValues("stale").Inc()
+	}
+	return params.ModelSummaryResults{
+		Results:    results,
+		Incomplete: incomplete,
 	}, nil
 }
 
@@ -136,30 +191,57 @@ func (r *controllerRoot) ListModels(ctx context.Context, _ jujuparams.Entity) (j
 	return r.allModels(ctx)
 }
 
-// ModelInfo implements the ModelManager facade's ModelInfo method.
-func (r *controllerRoot) ModelInfo(ctx context.Context, args jujuparams.Entities) (jujuparams.ModelInfoResults, error) {
+// ModelInfo implements the ModelManager facade's ModelInfo method. The
+// request may specify the read consistency, in addition to the standard
+// parameters, to choose whether the model info is read from JIMM's store
+// or from the controllers hosting the models.
+func (r *controllerRoot) ModelInfo(ctx context.Context, args params.ConsistentEntities) (jujuparams.ModelInfoResults, error) {
 	const op = errors.Op("jujuapi.ModelInfo")
 
+	ctx = r.withReadConsistency(ctx, args.Consistency)
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
-	results := make([]jujuparams.ModelInfoResult, len(args.Entities))
-	for i, arg := range args.Entities {
-		mt, err := names.ParseModelTag(arg.Tag)
+	// Models are queried in parallel so that a slow controller only
+	// delays the results for its own models. Queries still running at
+	// the soft deadline are left to complete so that the connection to
+	// the controller is cached for subsequent requests.
+	p := fanOutParams{
+		softDeadline:         r.params.FanOutSoftDeadline,
+		completeInBackground: true,
+	}
+	infos, errs := fanOut(ctx, len(args.Entities), p, func(ctx context.Context, i int) (*jujuparams.ModelInfo, error) {
+		mt, err := names.ParseModelTag(args.Entities[i].Tag)
 		if err != nil {
-			results[i].Error = mapError(errors.E(op, err, errors.CodeBadRequest))
-			continue
+			return nil, errors.E(op, err, errors.CodeBadRequest)
 		}
-		results[i].Result, err = r.jimm.ModelInfo(ctx, r.user, mt)
+		mi, err := r.jimm.ModelInfo(ctx, r.user, mt)
 		if err != nil {
 			if errors.ErrorCode(err) == errors.CodeNotFound {
 				// Map not-found errors to unauthorized, this is what juju
 				// does.
 				err = errors.E(op, errors.CodeUnauthorized, "unauthorized")
 			}
-			results[i].Error = mapError(errors.E(op, err))
-		} else if r.controllerUUIDMasking {
-			results[i].Result.ControllerUUID = r.params.ControllerUUID
+			return nil, errors.E(op, err)
+		}
+		// If the user's access cannot be determined treat them as a
+		// non-admin so that sensitive fields are redacted.
+		access, err := r.jimm.GetUserModelAccess(ctx, r.user, mt)
+		if err != nil {
+			zapctx.Warn(ctx, "cannot determine model access", zap.Error(err))
+		}
+		r.redactor.redactModelInfo(mi, access)
+		if r.controllerUUIDMasking {
+			mi.ControllerUUID = r.params.ControllerUUID
+		}
+		return mi, nil
+	})
+	results := make([]jujuparams.ModelInfoResult, len(args.Entities))
+	for i := range results {
+		if errs[i] != nil {
+			results[i].Error = mapError(errs[i])
+			continue
 		}
+		results[i].Result = infos[i]
 	}
 	return jujuparams.ModelInfoResults{
 		Results: results,
@@ -198,22 +280,30 @@ func (r *controllerRoot) DestroyModels(ctx context.Context, args jujuparams.Dest
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 	results := make([]jujuparams.ErrorResult, len(args.Models))
+	errs := newBulkErrors(op, len(args.Models))
 
 	for i, model := range args.Models {
 		mt, err := names.ParseModelTag(model.ModelTag)
 		if err != nil {
-			results[i].Error = mapError(errors.E(op, err, errors.CodeBadRequest))
+			results[i].Error = errs.add(i, model.ModelTag, errors.E(op, err, errors.CodeBadRequest))
 			continue
 		}
+		if model.Force != nil && *model.Force {
+			if err := r.requireConfirmation("forcibly destroying a model"); err != nil {
+				results[i].Error = errs.add(i, model.ModelTag, errors.E(op, err))
+				continue
+			}
+		}
 
 		if err := r.jimm.DestroyModel(ctx, r.user, mt, model.DestroyStorage, model.Force, model.MaxWait, model.Timeout); err != nil {
 			if errors.ErrorCode(err) != errors.CodeNotFound {
 				// It isn't an error to try and destroy an already
 				// destroyed model.
-				results[i].Error = mapError(errors.E(op, err))
+				results[i].Error = errs.add(i, model.ModelTag, errors.E(op, err))
 			}
 		}
 	}
+	errs.finish(ctx)
 
 	return jujuparams.ErrorResults{
 		Results: results,
@@ -221,36 +311,55 @@ func (r *controllerRoot) DestroyModels(ctx context.Context, args jujuparams.Dest
 }
 
 // ModifyModelAccess implements the ModelManager facade's ModifyModelAccess method.
+// As well as user tags, a change may target a group, using a tag of the
+// form group-<name>, in which case the access applies to all members of
+// the group.
 func (r *controllerRoot) ModifyModelAccess(ctx context.Context, args jujuparams.ModifyModelAccessRequest) (jujuparams.ErrorResults, error) {
 	const op = errors.Op("jujuapi.ModifyModelAccess")
 
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 	results := make([]jujuparams.ErrorResult, len(args.Changes))
+	errs := newBulkErrors(op, len(args.Changes))
 	for i, change := range args.Changes {
 		mt, err := names.ParseModelTag(change.ModelTag)
 		if err != nil {
-			results[i].Error = mapError(errors.E(op, err, errors.CodeBadRequest))
+			results[i].Error = errs.add(i, change.ModelTag, errors.E(op, err, errors.CodeBadRequest))
 			continue
 		}
-		user, err := parseUserTag(change.UserTag)
-		if err != nil {
-			results[i].Error = mapError(errors.E(op, err, errors.CodeBadRequest))
-			continue
-		}
-		switch change.Action {
-		case jujuparams.GrantModelAccess:
-			err = r.jimm.GrantModelAccess(ctx, r.user, mt, user, change.Access)
-		case jujuparams.RevokeModelAccess:
-			err = r.jimm.RevokeModelAccess(ctx, r.user, mt, user, change.Access)
-		default:
-			err = errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid action %q", change.Action))
+		if groupName, ok := strings.CutPrefix(change.UserTag, jimmnames.GroupTagKind+"-"); ok {
+			// The grantee is a group, access is granted to all of
+			// the group's members.
+			switch change.Action {
+			case jujuparams.GrantModelAccess:
+				err = r.jimm.GrantModelGroupAccess(ctx, r.user, mt, groupName, change.Access)
+			case jujuparams.RevokeModelAccess:
+				err = r.jimm.RevokeModelGroupAccess(ctx, r.user, mt, groupName, change.Access)
+			default:
+				err = errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid action %q", change.Action))
+			}
+		} else {
+			var user names.UserTag
+			user, err = parseUserTag(change.UserTag)
+			if err != nil {
+				results[i].Error = errs.add(i, change.ModelTag, errors.E(op, err, errors.CodeBadRequest))
+				continue
+			}
+			switch change.Action {
+			case jujuparams.GrantModelAccess:
+				err = r.jimm.GrantModelAccess(ctx, r.user, mt, user, change.Access)
+			case jujuparams.RevokeModelAccess:
+				err = r.jimm.RevokeModelAccess(ctx, r.user, mt, user, change.Access)
+			default:
+				err = errors.E(op, errors.CodeBadRequest, fmt.Sprintf("invalid action %q", change.Action))
+			}
 		}
 		if errors.ErrorCode(err) == errors.CodeNotFound {
 			err = errors.E(op, errors.CodeUnauthorized, "unauthorized")
 		}
-		results[i].Error = mapError(err)
+		results[i].Error = errs.add(i, change.ModelTag, err)
 	}
+	errs.finish(ctx)
 	return jujuparams.ErrorResults{
 		Results: results,
 	}, nil
@@ -273,6 +382,11 @@ func (r *controllerRoot) DumpModelsDB(ctx context.Context, args jujuparams.Entit
 		results[i].Result, err = r.jimm.DumpModelDB(ctx, r.user, mt)
 		if err != nil {
 			results[i].Error = mapError(errors.E(op, err))
+			continue
+		}
+		if err := r.checkDumpModelDBSize(ctx, mt, results[i].Result); err != nil {
+			results[i].Result = nil
+			results[i].Error = mapError(errors.E(op, err))
 		}
 	}
 	return jujuparams.MapResults{
@@ -280,15 +394,36 @@ func (r *controllerRoot) DumpModelsDB(ctx context.Context, args jujuparams.Entit
 	}
 }
 
+// checkDumpModelDBSize checks the size of a model database dump. If it
+// is too large to return inline it is stored and an error telling the
+// client where to download it from is returned.
+func (r *controllerRoot) checkDumpModelDBSize(ctx context.Context, mt names.ModelTag, dump map[string]interface{}) error {
+	data, err := json.Marshal(dump)
+	if err != nil {
+		return err
+	}
+	name := mt.Id() + "-db-dump.json"
+	url, err := r.jimm.StoreOversizedResult(ctx, r.user, name, "application/json", data)
+	if err != nil {
+		return err
+	}
+	if url != "" {
+		return jimm.ResultTooLargeError(name, url)
+	}
+	return nil
+}
+
 // ChangeModelCredential implements the ModelManager (v5) facade's
 // ChangeModelCredential method.
 func (r *controllerRoot) ChangeModelCredential(ctx context.Context, args jujuparams.ChangeModelCredentialsParams) (jujuparams.ErrorResults, error) {
 	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 	results := make([]jujuparams.ErrorResult, len(args.Models))
+	errs := newBulkErrors("jujuapi.ChangeModelCredential", len(args.Models))
 	for i, arg := range args.Models {
-		results[i].Error = mapError(r.changeModelCredential(ctx, arg))
+		results[i].Error = errs.add(i, arg.ModelTag, r.changeModelCredential(ctx, arg))
 	}
+	errs.finish(ctx)
 	return jujuparams.ErrorResults{
 		Results: results,
 	}, nil
@@ -320,14 +455,16 @@ func (r *controllerRoot) ValidateModelUpgrades(ctx context.Context, args jujupar
 	const op = errors.Op("jujuapi.ValidateModelUpgrades")
 
 	results := make([]jujuparams.ErrorResult, len(args.Models))
+	errs := newBulkErrors(op, len(args.Models))
 	for i, arg := range args.Models {
 		modelTag, err := names.ParseModelTag(arg.ModelTag)
 		if err != nil {
-			results[i].Error = mapError(errors.E(op, err, errors.CodeBadRequest))
+			results[i].Error = errs.add(i, arg.ModelTag, errors.E(op, err, errors.CodeBadRequest))
 			continue
 		}
-		results[i].Error = mapError(r.jimm.ValidateModelUpgrade(ctx, r.user, modelTag, args.Force))
+		results[i].Error = errs.add(i, arg.ModelTag, r.jimm.ValidateModelUpgrade(ctx, r.user, modelTag, args.Force))
 	}
+	errs.finish(ctx)
 	return jujuparams.ErrorResults{
 		Results: results,
 	}, nil
@@ -338,14 +475,16 @@ func (r *controllerRoot) SetModelDefaults(ctx context.Context, args jujuparams.S
 	const op = errors.Op("jujuapi.ModelDefaultsForClouds")
 
 	results := make([]jujuparams.ErrorResult, len(args.Config))
+	errs := newBulkErrors("jujuapi.SetModelDefaults", len(args.Config))
 	for i, config := range args.Config {
 		cloudTag, err := names.ParseCloudTag(config.CloudTag)
 		if err != nil {
-			results[i].Error = mapError(errors.E(op, err))
+			results[i].Error = errs.add(i, config.CloudTag, errors.E(op, err))
 			continue
 		}
-		results[i].Error = mapError(r.jimm.SetModelDefaults(ctx, r.user.Identity, cloudTag, config.CloudRegion, config.Config))
+		results[i].Error = errs.add(i, config.CloudTag, r.jimm.SetModelDefaults(ctx, r.user.Identity, cloudTag, config.CloudRegion, config.Config))
 	}
+	errs.finish(ctx)
 
 	return jujuparams.ErrorResults{
 		Results: results,
@@ -355,14 +494,16 @@ func (r *controllerRoot) SetModelDefaults(ctx context.Context, args jujuparams.S
 // UnsetModelDefaults removes the specified default model settings.
 func (r *controllerRoot) UnsetModelDefaults(ctx context.Context, args jujuparams.UnsetModelDefaults) (jujuparams.ErrorResults, error) {
 	results := make([]jujuparams.ErrorResult, len(args.Keys))
+	errs := newBulkErrors("jujuapi.UnsetModelDefaults", len(args.Keys))
 	for i, key := range args.Keys {
 		cloudTag, err := names.ParseCloudTag(key.CloudTag)
 		if err != nil {
-			results[i].Error = mapError(err)
+			results[i].Error = errs.add(i, key.CloudTag, err)
 			continue
 		}
-		results[i].Error = mapError(r.jimm.UnsetModelDefaults(ctx, r.user.Identity, cloudTag, key.CloudRegion, key.Keys))
+		results[i].Error = errs.add(i, key.CloudTag, r.jimm.UnsetModelDefaults(ctx, r.user.Identity, cloudTag, key.CloudRegion, key.Keys))
 	}
+	errs.finish(ctx)
 
 	return jujuparams.ErrorResults{
 		Results: results,
@@ -4,6 +4,7 @@ package jujuapi_test
 
 import (
 	"context"
+	"database/sql"
 	"sort"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/kubetest"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	"github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 const jujuVersion = "3.5.4"
@@ -126,6 +128,31 @@ func (s *modelManagerSuite) TestListModelSummaries(c *gc.C) {
 	}})
 }
 
+func (s *modelManagerSuite) TestListModelSummariesStale(c *gc.C) {
+	ctx := context.Background()
+	ctl := dbmodel.Controller{Name: s.Model.Controller.Name}
+	err := s.JIMM.Database.GetController(ctx, &ctl)
+	c.Assert(err, gc.Equals, nil)
+	unavailableSince := time.Now().UTC().Truncate(time.Millisecond)
+	ctl.UnavailableSince = sql.NullTime{Time: unavailableSince, Valid: true}
+	err = s.JIMM.Database.UpdateController(ctx, &ctl)
+	c.Assert(err, gc.Equals, nil)
+
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+
+	var results params.ModelSummaryResults
+	err = conn.APICall("ModelManager", 9, "", "ListModelSummaries", jujuparams.ModelSummariesRequest{}, &results)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(results.Incomplete, gc.Equals, false)
+	c.Assert(results.Results, gc.HasLen, 2)
+	for _, r := range results.Results {
+		c.Assert(r.Error, gc.IsNil)
+		c.Assert(r.StaleSince, gc.NotNil, gc.Commentf("model %s", r.Result.Name))
+		c.Check(r.StaleSince.Equal(unavailableSince), gc.Equals, true)
+	}
+}
+
 func (s *modelManagerSuite) TestListModelSummariesWithoutControllerUUIDMasking(c *gc.C) {
 	conn1 := s.open(c, nil, "charlie")
 	defer conn1.Close()
@@ -966,6 +993,58 @@ func (s *modelManagerSuite) TestGrantAndRevokeModel(c *gc.C) {
 	c.Assert(res[0].Error, gc.ErrorMatches, "unauthorized")
 }
 
+func (s *modelManagerSuite) TestGrantAndRevokeModelGroup(c *gc.C) {
+	groupTag := s.AddGroup(c, "test-group")
+	err := s.JIMM.OpenFGAClient.AddRelation(context.Background(), openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("charlie@canonical.com")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(groupTag),
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	conn := s.open(c, nil, "bob")
+	defer conn.Close()
+
+	conn2 := s.open(c, nil, "charlie")
+	defer conn2.Close()
+	client2 := modelmanager.NewClient(conn2)
+
+	res, err := client2.ModelInfo([]names.ModelTag{s.Model.ResourceTag()})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(res, gc.HasLen, 1)
+	c.Assert(res[0].Error, gc.ErrorMatches, "unauthorized")
+
+	modifyAccess := func(action jujuparams.ModelAction, access jujuparams.UserAccessPermission) {
+		var res jujuparams.ErrorResults
+		err := conn.APICall("ModelManager", 9, "", "ModifyModelAccess", jujuparams.ModifyModelAccessRequest{
+			Changes: []jujuparams.ModifyModelAccess{{
+				UserTag:  "group-test-group",
+				Action:   action,
+				Access:   access,
+				ModelTag: s.Model.Tag().String(),
+			}},
+		}, &res)
+		c.Assert(err, gc.Equals, nil)
+		c.Assert(res.Results, gc.HasLen, 1)
+		c.Assert(res.Results[0].Error, gc.IsNil)
+	}
+
+	modifyAccess(jujuparams.GrantModelAccess, jujuparams.ModelWriteAccess)
+
+	res, err = client2.ModelInfo([]names.ModelTag{s.Model.ResourceTag()})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(res, gc.HasLen, 1)
+	c.Assert(res[0].Error, gc.IsNil)
+	c.Assert(res[0].Result.UUID, gc.Equals, s.Model.UUID.String)
+
+	modifyAccess(jujuparams.RevokeModelAccess, jujuparams.ModelReadAccess)
+
+	res, err = client2.ModelInfo([]names.ModelTag{s.Model.ResourceTag()})
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(res, gc.HasLen, 1)
+	c.Assert(res[0].Error, gc.ErrorMatches, "unauthorized")
+}
+
 func (s *modelManagerSuite) TestUserRevokeOwnAccess(c *gc.C) {
 
 	conn := s.open(c, nil, "bob")
@@ -1048,6 +1127,15 @@ func (s *modelManagerSuite) TestModifyModelAccessErrors(c *gc.C) {
 			ModelTag: s.Model.Tag().String(),
 		},
 		expectError: `"not-a-user-tag" is not a valid tag`,
+	}, {
+		about: "no such group",
+		modifyModelAccess: jujuparams.ModifyModelAccess{
+			UserTag:  "group-no-such-group",
+			Action:   jujuparams.GrantModelAccess,
+			Access:   jujuparams.ModelReadAccess,
+			ModelTag: s.Model.Tag().String(),
+		},
+		expectError: `unauthorized`,
 	}, {
 		about: "unknown action",
 		modifyModelAccess: jujuparams.ModifyModelAccess{
@@ -41,7 +41,11 @@ func (r *controllerRoot) ModelSummaryWatcherNext(ctx context.Context, objID stri
 	if err != nil {
 		return jujuparams.SummaryWatcherNextResults{}, errors.E(op, err)
 	}
-	return w.Next()
+	msw, ok := w.(*modelSummaryWatcher)
+	if !ok {
+		return jujuparams.SummaryWatcherNextResults{}, errors.E(op, errors.CodeNotFound)
+	}
+	return msw.Next()
 }
 
 // ModelSummaryWatcherStop implements the Stop method on the
@@ -61,9 +65,14 @@ var (
 	defaultModelAccessWatcherPeriod = time.Minute
 )
 
+// A watcher is a watcher held in a watcherRegistry.
+type watcher interface {
+	Stop() error
+}
+
 type watcherRegistry struct {
 	mu       sync.RWMutex
-	watchers map[string]*modelSummaryWatcher
+	watchers map[string]watcher
 }
 
 func (r *watcherRegistry) stop() {
@@ -73,23 +82,23 @@ func (r *watcherRegistry) stop() {
 	for _, w := range r.watchers {
 		err := w.Stop()
 		if err != nil {
-			zapctx.Error(context.Background(), "failed to stop a model summary watcher", zaputil.Error(err))
+			zapctx.Error(context.Background(), "failed to stop a watcher", zaputil.Error(err))
 		}
 	}
 	r.watchers = nil
 }
 
-func (r *watcherRegistry) register(w *modelSummaryWatcher) {
+func (r *watcherRegistry) register(id string, w watcher) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if r.watchers == nil {
-		r.watchers = make(map[string]*modelSummaryWatcher)
+		r.watchers = make(map[string]watcher)
 	}
-	r.watchers[w.id] = w
+	r.watchers[id] = w
 }
 
-func (r *watcherRegistry) get(id string) (*modelSummaryWatcher, error) {
+func (r *watcherRegistry) get(id string) (watcher, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -100,7 +109,7 @@ func (r *watcherRegistry) get(id string) (*modelSummaryWatcher, error) {
 	return w, nil
 }
 
-func newModelSummaryWatcher(ctx context.Context, id string, pubsub *pubsub.Hub, modelGetterFunc func(context.Context) ([]string, error)) (*modelSummaryWatcher, error) {
+func newModelSummaryWatcher(ctx context.Context, id string, hub *pubsub.Hub, modelGetterFunc func(context.Context) ([]string, error)) (*modelSummaryWatcher, error) {
 	const op = errors.Op("jujuapi.newModelSummaryWatcher")
 
 	ctx, cancelContext := context.WithCancel(ctx)
@@ -122,7 +131,10 @@ func newModelSummaryWatcher(ctx context.Context, id string, pubsub *pubsub.Hub,
 		summaries: make(map[string]jujuparams.ModelAbstract),
 	}
 
-	cleanupFunction, err := pubsub.SubscribeMatch(accessWatcher.match, watcher.pubsubHandler)
+	cleanupFunction, err := hub.SubscribeMatchWithOptions(accessWatcher.match, watcher.pubsubHandler, pubsub.SubscribeOptions{
+		Name:    "model-summary-watcher-" + id,
+		OnEvict: watcher.evict,
+	})
 	if err != nil {
 		cancelContext()
 		return nil, errors.E(op, err)
@@ -142,6 +154,17 @@ type modelSummaryWatcher struct {
 
 	mu        sync.RWMutex
 	summaries map[string]jujuparams.ModelAbstract
+	// evicted is set when the watcher is evicted from the pubsub hub
+	// for not keeping up with the published summaries.
+	evicted bool
+}
+
+// evict is called when the watcher is evicted from the pubsub hub, the
+// summaries the watcher holds will no longer be updated.
+func (w *modelSummaryWatcher) evict() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.evicted = true
 }
 
 func (w *modelSummaryWatcher) pubsubHandler(model string, summaryI interface{}) {
@@ -165,6 +188,9 @@ func (w *modelSummaryWatcher) Next() (jujuparams.SummaryWatcherNextResults, erro
 	w.mu.RLock()
 	defer w.mu.RUnlock()
 
+	if w.evicted {
+		return jujuparams.SummaryWatcherNextResults{}, errors.E(errors.CodeTryAgain, "watcher evicted for not keeping up with model summaries")
+	}
 	summaries := make([]jujuparams.ModelAbstract, len(w.summaries))
 	i := 0
 	for _, summary := range w.summaries {
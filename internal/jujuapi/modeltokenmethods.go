@@ -0,0 +1,22 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+// modelTokenMethods holds the methods, as "<facade>.<method>", that may be
+// called by an identity authenticated by a model token. Each either takes
+// no resource or is limited by its access checks to the models the caller
+// can see, which for a model token is only the token's model. Calls to any
+// other method other than those of the Admin facade, used to log in, are
+// rejected.
+var modelTokenMethods = map[string]bool{
+	"Controller.ControllerVersion":    true,
+	"Controller.ModelStatus":          true,
+	"JIMM.GetModelSummary":            true,
+	"JIMM.Version":                    true,
+	"JIMM.Whoami":                     true,
+	"ModelManager.ListModelSummaries": true,
+	"ModelManager.ListModels":         true,
+	"ModelManager.ModelInfo":          true,
+	"ModelManager.ModelStatus":        true,
+	"Pinger.Ping":                     true,
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+func TestModelTokenMethods(t *testing.T) {
+	c := qt.New(t)
+
+	r := newControllerRoot(nil, Params{}, "")
+	defer r.cleanup()
+	versions := make(map[string]int)
+	for facade, init := range facadeInit {
+		if vs := init(r); len(vs) > 0 {
+			versions[facade] = vs[len(vs)-1]
+		}
+	}
+
+	r.user = openfga.NewUser(&dbmodel.Identity{Name: "model-token-1@" + dbmodel.ModelTokenDomain}, nil)
+
+	// Every method a model token may call exists.
+	for method := range modelTokenMethods {
+		facade, name, _ := strings.Cut(method, ".")
+		_, err := r.FindMethod(facade, versions[facade], name)
+		c.Check(err, qt.IsNil, qt.Commentf("%s", method))
+	}
+
+	for _, method := range []string{"JIMM.CreateModelToken", "JIMM.ListModelTokens", "Cloud.Clouds", "ModelManager.CreateModel", "ApplicationOffers.FindApplicationOffers"} {
+		facade, name, _ := strings.Cut(method, ".")
+		_, err := r.FindMethod(facade, versions[facade], name)
+		c.Check(err, qt.ErrorMatches, method+` is not available to model tokens`)
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeForbidden)
+	}
+
+	// Other identities are not affected.
+	r.user = openfga.NewUser(&dbmodel.Identity{Name: "bob@canonical.com"}, nil)
+	_, err := r.FindMethod("JIMM", versions["JIMM"], "CreateModelToken")
+	c.Check(err, qt.IsNil)
+}
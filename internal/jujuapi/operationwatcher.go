@@ -0,0 +1,121 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jujuapi/rpc"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func init() {
+	facadeInit["OperationWatcher"] = func(r *controllerRoot) []int {
+		nextMethod := rpc.Method(r.OperationWatcherNext)
+		stopMethod := rpc.Method(r.OperationWatcherStop)
+
+		r.AddMethod("OperationWatcher", 1, "Next", nextMethod)
+		r.AddMethod("OperationWatcher", 1, "Stop", stopMethod)
+
+		return []int{1}
+	}
+}
+
+// OperationWatcherNext implements the Next method on the
+// OperationWatcher facade. The first call returns the operation
+// immediately, subsequent calls block until its progress changes. Once
+// the finished operation has been returned subsequent calls fail with a
+// not found error.
+func (r *controllerRoot) OperationWatcherNext(ctx context.Context, objID string) (apiparams.OperationWatcherNextResults, error) {
+	const op = errors.Op("jujuapi.OperationWatcherNext")
+
+	w, err := r.watchers.get(objID)
+	if err != nil {
+		return apiparams.OperationWatcherNextResults{}, errors.E(op, err)
+	}
+	ow, ok := w.(*operationWatcher)
+	if !ok {
+		return apiparams.OperationWatcherNextResults{}, errors.E(op, errors.CodeNotFound)
+	}
+	res, err := ow.Next(ctx)
+	if err != nil {
+		return apiparams.OperationWatcherNextResults{}, errors.E(op, err)
+	}
+	return res, nil
+}
+
+// OperationWatcherStop implements the Stop method on the
+// OperationWatcher facade.
+func (r *controllerRoot) OperationWatcherStop(ctx context.Context, objID string) error {
+	const op = errors.Op("jujuapi.OperationWatcherStop")
+
+	w, err := r.watchers.get(objID)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	return w.Stop()
+}
+
+var (
+	defaultOperationWatcherPeriod = time.Second
+)
+
+func newOperationWatcher(period time.Duration, getOperation func(context.Context) (apiparams.Operation, error)) *operationWatcher {
+	return &operationWatcher{
+		getOperation: getOperation,
+		period:       period,
+		stopped:      make(chan struct{}),
+	}
+}
+
+// An operationWatcher polls an operation, returning it from Next
+// whenever its progress changes.
+type operationWatcher struct {
+	getOperation func(context.Context) (apiparams.Operation, error)
+	period       time.Duration
+	stopped      chan struct{}
+	stopOnce     sync.Once
+
+	mu   sync.Mutex
+	last apiparams.Operation
+	sent bool
+}
+
+func (w *operationWatcher) Next(ctx context.Context) (apiparams.OperationWatcherNextResults, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.sent && w.last.Status != dbmodel.OperationRunning {
+		return apiparams.OperationWatcherNextResults{}, errors.E(errors.CodeNotFound, "operation finished")
+	}
+	for {
+		o, err := w.getOperation(ctx)
+		if err != nil {
+			return apiparams.OperationWatcherNextResults{}, err
+		}
+		if !w.sent || !reflect.DeepEqual(o, w.last) {
+			w.sent = true
+			w.last = o
+			return apiparams.OperationWatcherNextResults{Operation: o}, nil
+		}
+		select {
+		case <-time.After(w.period):
+		case <-w.stopped:
+			return apiparams.OperationWatcherNextResults{}, errors.E(errors.CodeNotFound, "watcher stopped")
+		case <-ctx.Done():
+			return apiparams.OperationWatcherNextResults{}, ctx.Err()
+		}
+	}
+}
+
+func (w *operationWatcher) Stop() error {
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+	})
+	return nil
+}
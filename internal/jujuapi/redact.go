@@ -0,0 +1,210 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/version/v2"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// Model fields that can be redacted from the model information returned
+// to users with less than admin access to a model.
+const (
+	// RedactAgentVersionTag removes any pre-release tag, such as
+	// "beta1", from the model's agent version.
+	RedactAgentVersionTag = "agent-version-tag"
+
+	// RedactCloudCredential removes the model's cloud credential and its
+	// validity.
+	RedactCloudCredential = "cloud-credential"
+
+	// RedactMigration removes the status of any model migration.
+	RedactMigration = "migration"
+
+	// RedactSecretBackendConfig removes the configuration, which may
+	// contain access tokens, of the model's secret backends.
+	RedactSecretBackendConfig = "secret-backend-config"
+
+	// RedactStatusData removes the data attached to the model's status.
+	RedactStatusData = "status-data"
+)
+
+// DefaultRedactedModelFields holds the fields redacted when none are
+// configured.
+var DefaultRedactedModelFields = []string{
+	RedactAgentVersionTag,
+	RedactSecretBackendConfig,
+}
+
+var redactableModelFields = map[string]bool{
+	RedactAgentVersionTag:     true,
+	RedactCloudCredential:     true,
+	RedactMigration:           true,
+	RedactSecretBackendConfig: true,
+	RedactStatusData:          true,
+}
+
+// ValidateRedactedModelFields checks that all of the given fields can be
+// redacted.
+func ValidateRedactedModelFields(fields []string) error {
+	for _, f := range fields {
+		if !redactableModelFields[f] {
+			return errors.E(errors.CodeBadRequest, fmt.Sprintf("cannot redact unknown model field %q", f))
+		}
+	}
+	return nil
+}
+
+// DefaultRedactedModelConfigKeys holds the model config keys redacted
+// when none are configured. Proxy URLs may contain credentials and cloud
+// init user data may contain anything.
+var DefaultRedactedModelConfigKeys = []string{
+	"apt-ftp-proxy",
+	"apt-http-proxy",
+	"apt-https-proxy",
+	"cloudinit-userdata",
+	"ftp-proxy",
+	"http-proxy",
+	"https-proxy",
+	"juju-ftp-proxy",
+	"juju-http-proxy",
+	"juju-https-proxy",
+	"snap-http-proxy",
+	"snap-https-proxy",
+}
+
+// modelInfoConfigKeys holds the model config keys whose values are
+// returned as fields of the model info.
+var modelInfoConfigKeys = map[string]func(*jujuparams.ModelInfo){
+	"agent-version":  func(mi *jujuparams.ModelInfo) { mi.AgentVersion = nil },
+	"default-base":   func(mi *jujuparams.ModelInfo) { mi.DefaultBase = "" },
+	"default-series": func(mi *jujuparams.ModelInfo) { mi.DefaultSeries = "" },
+}
+
+// A modelRedactor removes sensitive fields and model config values from
+// the model information returned to users with less than admin access to
+// a model.
+type modelRedactor struct {
+	fields     map[string]bool
+	configKeys map[string]bool
+}
+
+// newModelRedactor creates a modelRedactor that redacts the given fields
+// and model config keys. If fields is nil the DefaultRedactedModelFields
+// are redacted, if configKeys is nil the DefaultRedactedModelConfigKeys
+// are redacted.
+func newModelRedactor(fields, configKeys []string) modelRedactor {
+	if fields == nil {
+		fields = DefaultRedactedModelFields
+	}
+	if configKeys == nil {
+		configKeys = DefaultRedactedModelConfigKeys
+	}
+	r := modelRedactor{
+		fields:     make(map[string]bool, len(fields)),
+		configKeys: make(map[string]bool, len(configKeys)),
+	}
+	for _, f := range fields {
+		r.fields[f] = true
+	}
+	for _, k := range configKeys {
+		r.configKeys[k] = true
+	}
+	return r
+}
+
+// redactModelGetResponse removes the configured model config keys from
+// the given response to a ModelConfig.ModelGet request unless access is
+// admin.
+func (r modelRedactor) redactModelGetResponse(response json.RawMessage, access string) (json.RawMessage, error) {
+	if access == string(jujuparams.ModelAdminAccess) || len(response) == 0 {
+		return response, nil
+	}
+	// The config values are left as they are, they are not decoded
+	// into a jujuparams.ModelConfigResults.
+	var results struct {
+		Config map[string]json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(response, &results); err != nil {
+		return nil, errors.E(err)
+	}
+	for k := range results.Config {
+		if r.configKeys[k] {
+			delete(results.Config, k)
+		}
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, errors.E(err)
+	}
+	return data, nil
+}
+
+// redactModelInfo removes the configured fields, and the fields holding
+// the configured model config keys, from the given model info unless
+// access is admin.
+func (r modelRedactor) redactModelInfo(mi *jujuparams.ModelInfo, access string) {
+	if mi == nil || access == string(jujuparams.ModelAdminAccess) {
+		return
+	}
+	for k, redact := range modelInfoConfigKeys {
+		if r.configKeys[k] {
+			redact(mi)
+		}
+	}
+	if r.fields[RedactAgentVersionTag] {
+		mi.AgentVersion = withoutVersionTag(mi.AgentVersion)
+	}
+	if r.fields[RedactCloudCredential] {
+		mi.CloudCredentialTag = ""
+		mi.CloudCredentialValidity = nil
+	}
+	if r.fields[RedactMigration] {
+		mi.Migration = nil
+	}
+	if r.fields[RedactSecretBackendConfig] && mi.SecretBackends != nil {
+		backends := make([]jujuparams.SecretBackendResult, len(mi.SecretBackends))
+		for i, b := range mi.SecretBackends {
+			b.Result.Config = nil
+			backends[i] = b
+		}
+		mi.SecretBackends = backends
+	}
+	if r.fields[RedactStatusData] {
+		mi.Status.Data = nil
+	}
+}
+
+// redactModelSummary removes the configured fields from the given model
+// summary unless the summary's user access is admin.
+func (r modelRedactor) redactModelSummary(ms *jujuparams.ModelSummary) {
+	if ms == nil || ms.UserAccess == jujuparams.ModelAdminAccess {
+		return
+	}
+	if r.fields[RedactAgentVersionTag] {
+		ms.AgentVersion = withoutVersionTag(ms.AgentVersion)
+	}
+	if r.fields[RedactCloudCredential] {
+		ms.CloudCredentialTag = ""
+	}
+	if r.fields[RedactMigration] {
+		ms.Migration = nil
+	}
+	if r.fields[RedactStatusData] {
+		ms.Status.Data = nil
+	}
+}
+
+// withoutVersionTag returns the given version without any pre-release
+// tag. The given version is not modified.
+func withoutVersionTag(v *version.Number) *version.Number {
+	if v == nil || v.Tag == "" {
+		return v
+	}
+	return &version.Number{Major: v.Major, Minor: v.Minor}
+}
@@ -92,6 +92,7 @@ func (r *controllerRoot) UpdateServiceAccountCredentials(ctx context.Context, re
 	results := jujuparams.UpdateCredentialResults{
 		Results: make([]jujuparams.UpdateCredentialResult, len(req.Credentials)),
 	}
+	errs := newBulkErrors(op, len(req.Credentials))
 	for i, credential := range req.Credentials {
 		var res []jujuparams.UpdateCredentialModelResult
 		var err error
@@ -109,11 +110,12 @@ func (r *controllerRoot) UpdateServiceAccountCredentials(ctx context.Context, re
 		}
 		results.Results[i] = jujuparams.UpdateCredentialResult{
 			CredentialTag: credential.Tag,
-			Error:         mapError(err),
+			Error:         errs.add(i, credential.Tag, err),
 			Models:        res,
 		}
 		results.Results[i].CredentialTag = credential.Tag
 	}
+	errs.finish(ctx)
 	return results, nil
 }
 
@@ -146,3 +148,47 @@ func (r *controllerRoot) GrantServiceAccountAccess(ctx context.Context, req apip
 
 	return r.jimm.GrantServiceAccountAccess(ctx, r.user, svcAccTag, req.Entities)
 }
+
+// AddServiceAccountToGroups adds the service account to the given groups.
+// The authenticated user must be both an administrator of the service
+// account and a JIMM administrator, as group membership may grant access
+// beyond the service account itself.
+func (r *controllerRoot) AddServiceAccountToGroups(ctx context.Context, req apiparams.ServiceAccountGroupsRequest) error {
+	const op = errors.Op("jujuapi.AddServiceAccountToGroups")
+
+	svcAccTag, err := r.authorizedServiceAccountTag(ctx, req.ClientID)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := r.jimm.AddServiceAccountToGroups(ctx, r.user, svcAccTag, req.Groups); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// RemoveServiceAccountFromGroups removes the service account from the given
+// groups. The authenticated user must be both an administrator of the
+// service account and a JIMM administrator.
+func (r *controllerRoot) RemoveServiceAccountFromGroups(ctx context.Context, req apiparams.ServiceAccountGroupsRequest) error {
+	const op = errors.Op("jujuapi.RemoveServiceAccountFromGroups")
+
+	svcAccTag, err := r.authorizedServiceAccountTag(ctx, req.ClientID)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	if err := r.jimm.RemoveServiceAccountFromGroups(ctx, r.user, svcAccTag, req.Groups); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// authorizedServiceAccountTag validates the incoming identity has
+// administrator permission on the service account and returns the service
+// account tag.
+func (r *controllerRoot) authorizedServiceAccountTag(ctx context.Context, clientID string) (jimmnames.ServiceAccountTag, error) {
+	svcAcc, err := r.getServiceAccount(ctx, clientID)
+	if err != nil {
+		return jimmnames.ServiceAccountTag{}, err
+	}
+	return jimmnames.NewServiceAccountTag(svcAcc.Name), nil
+}
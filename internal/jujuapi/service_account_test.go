@@ -19,6 +19,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jujuapi"
 	"github.com/canonical/jimm/v3/internal/openfga"
 	ofganames "github.com/canonical/jimm/v3/internal/openfga/names"
+	"github.com/canonical/jimm/v3/pkg/api"
 	"github.com/canonical/jimm/v3/pkg/api/params"
 	jimmnames "github.com/canonical/jimm/v3/pkg/names"
 )
@@ -385,6 +386,19 @@ func TestUpdateServiceAccountCredentials(t *testing.T) {
 					CredentialTag: "invalid-cred-name",
 					Error: &jujuparams.Error{
 						Message: `"invalid-cred-name" is not a valid tag`,
+						Info: map[string]interface{}{
+							"index":     0,
+							"entity":    "invalid-cred-name",
+							"retryable": false,
+							"causes": []params.ErrorCause{{
+								Message: `"invalid-cred-name" is not a valid tag`,
+							}},
+							"summary": params.BulkErrorSummary{
+								Total:  1,
+								Failed: 1,
+								Codes:  map[string]int{"": 1},
+							},
+						},
 					},
 					Models: nil,
 				},
@@ -726,6 +740,111 @@ func TestGrantServiceAccountAccess(t *testing.T) {
 	}
 }
 
+func TestServiceAccountGroups(t *testing.T) {
+	c := qt.New(t)
+
+	clientID := "fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount"
+	adminTuple := openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag("alice")),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(jimmnames.NewServiceAccountTag(clientID)),
+	}
+
+	tests := []struct {
+		about         string
+		params        params.ServiceAccountGroupsRequest
+		addTuples     []openfga.Tuple
+		expectedError string
+	}{{
+		about: "Valid request without domain",
+		params: params.ServiceAccountGroupsRequest{
+			ClientID: "fca1f605-736e-4d1f-bcd2-aecc726923be",
+			Groups:   []string{"ci-bots"},
+		},
+		addTuples: []openfga.Tuple{adminTuple},
+	}, {
+		about: "Valid request with domain",
+		params: params.ServiceAccountGroupsRequest{
+			ClientID: clientID,
+			Groups:   []string{"ci-bots"},
+		},
+		addTuples: []openfga.Tuple{adminTuple},
+	}, {
+		about: "Invalid service account ID",
+		params: params.ServiceAccountGroupsRequest{
+			ClientID: "_123_",
+			Groups:   []string{"ci-bots"},
+		},
+		expectedError: "invalid client ID",
+	}, {
+		about: "Unknown service account",
+		params: params.ServiceAccountGroupsRequest{
+			ClientID: "0b9c2a2e-5b1f-4c6f-9a3d-6c1d1b7e8f00@serviceaccount",
+			Groups:   []string{"ci-bots"},
+		},
+		addTuples:     []openfga.Tuple{adminTuple},
+		expectedError: "unauthorized",
+	}, {
+		about: "Missing service account administrator permission",
+		params: params.ServiceAccountGroupsRequest{
+			ClientID: clientID,
+			Groups:   []string{"ci-bots"},
+		},
+		expectedError: "unauthorized",
+	}}
+
+	for _, test := range tests {
+		test := test
+		c.Run(test.about, func(c *qt.C) {
+			ofgaClient, _, _, err := jimmtest.SetupTestOFGAClient(c.Name())
+			c.Assert(err, qt.IsNil)
+			var added, removed []string
+			jimm := &jimmtest.JIMM{
+				UserLogin_: func(ctx context.Context, email string) (*openfga.User, error) {
+					var u dbmodel.Identity
+					u.SetTag(names.NewUserTag(email))
+					return openfga.NewUser(&u, ofgaClient), nil
+				},
+				AddServiceAccountToGroups_: func(ctx context.Context, user *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error {
+					c.Check(svcAccTag.Id(), qt.Equals, clientID)
+					added = groups
+					return nil
+				},
+				RemoveServiceAccountFromGroups_: func(ctx context.Context, user *openfga.User, svcAccTag jimmnames.ServiceAccountTag, groups []string) error {
+					c.Check(svcAccTag.Id(), qt.Equals, clientID)
+					removed = groups
+					return nil
+				},
+			}
+			var u dbmodel.Identity
+			u.SetTag(names.NewUserTag("alice"))
+			user := openfga.NewUser(&u, ofgaClient)
+			cr := jujuapi.NewControllerRoot(jimm, jujuapi.Params{})
+			jujuapi.SetUser(cr, user)
+
+			if len(test.addTuples) > 0 {
+				err = ofgaClient.AddRelation(context.Background(), test.addTuples...)
+				c.Assert(err, qt.IsNil)
+			}
+
+			err = cr.AddServiceAccountToGroups(context.Background(), test.params)
+			if test.expectedError != "" {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				err = cr.RemoveServiceAccountFromGroups(context.Background(), test.params)
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+				c.Assert(added, qt.IsNil)
+				c.Assert(removed, qt.IsNil)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(added, qt.DeepEquals, test.params.Groups)
+			err = cr.RemoveServiceAccountFromGroups(context.Background(), test.params)
+			c.Assert(err, qt.IsNil)
+			c.Assert(removed, qt.DeepEquals, test.params.Groups)
+		})
+	}
+}
+
 // Integration tests below.
 type serviceAccountSuite struct {
 	websocketSuite
@@ -785,3 +904,60 @@ func (s *serviceAccountSuite) TestUpdateServiceAccountCredentialsIntegration(c *
 	c.Assert(err, gc.Equals, nil)
 	c.Assert(credResults, gc.DeepEquals, expectedResult)
 }
+
+func (s *serviceAccountSuite) TestServiceAccountGroupsIntegration(c *gc.C) {
+	ctx := context.Background()
+	clientID := "fca1f605-736e-4d1f-bcd2-aecc726923be@serviceaccount"
+	groupTag := s.AddGroup(c, "ci-bots")
+
+	// Alice is a JIMM administrator; bob is not.
+	for _, user := range []string{"alice@canonical.com", "bob@canonical.com"} {
+		err := s.JIMM.OpenFGAClient.AddRelation(ctx, openfga.Tuple{
+			Object:   ofganames.ConvertTag(names.NewUserTag(user)),
+			Relation: ofganames.AdministratorRelation,
+			Target:   ofganames.ConvertTag(jimmnames.NewServiceAccountTag(clientID)),
+		})
+		c.Assert(err, gc.IsNil)
+	}
+	memberTuple := openfga.Tuple{
+		Object:   ofganames.ConvertTag(names.NewUserTag(clientID)),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(groupTag),
+	}
+
+	bobConn := s.open(c, nil, "bob")
+	defer bobConn.Close()
+	err := api.NewClient(bobConn).AddServiceAccountToGroups(&params.ServiceAccountGroupsRequest{
+		ClientID: clientID,
+		Groups:   []string{"ci-bots"},
+	})
+	c.Assert(err, gc.ErrorMatches, "unauthorized.*")
+
+	conn := s.open(c, nil, "alice")
+	defer conn.Close()
+	client := api.NewClient(conn)
+
+	err = client.AddServiceAccountToGroups(&params.ServiceAccountGroupsRequest{
+		ClientID: clientID,
+		Groups:   []string{"no-such-group"},
+	})
+	c.Assert(err, gc.ErrorMatches, "group no-such-group not found.*")
+
+	err = client.AddServiceAccountToGroups(&params.ServiceAccountGroupsRequest{
+		ClientID: clientID,
+		Groups:   []string{"ci-bots"},
+	})
+	c.Assert(err, gc.IsNil)
+	ok, err := s.JIMM.OpenFGAClient.CheckRelation(ctx, memberTuple, false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, true)
+
+	err = client.RemoveServiceAccountFromGroups(&params.ServiceAccountGroupsRequest{
+		ClientID: clientID,
+		Groups:   []string{"ci-bots"},
+	})
+	c.Assert(err, gc.IsNil)
+	ok, err = s.JIMM.OpenFGAClient.CheckRelation(ctx, memberTuple, false)
+	c.Assert(err, gc.IsNil)
+	c.Assert(ok, gc.Equals, false)
+}
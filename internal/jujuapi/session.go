@@ -0,0 +1,91 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/openfga"
+)
+
+const (
+	// sessionActivityInterval is the minimum time between updates of
+	// the recorded activity of a session.
+	sessionActivityInterval = time.Minute
+
+	// sessionUpdateTimeout is the time allowed for recording the
+	// activity of a session.
+	sessionUpdateTimeout = 10 * time.Second
+)
+
+// startSession records a new session for the given user, who has just
+// logged in. Any session previously started on the connection is ended.
+// Failing to record the session does not prevent the login.
+func (r *controllerRoot) startSession(ctx context.Context, user *openfga.User) {
+	s, err := r.jimm.StartIdentitySession(ctx, user, r.remoteAddress)
+	if err != nil {
+		zapctx.Error(ctx, "failed to record session", zap.String("identity", user.Name), zap.Error(err))
+		return
+	}
+	r.endSession(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.session = s
+	r.lastActivity = s.LastActivity
+	r.activityRecordedAt = time.Now()
+}
+
+// recordActivity records that the user made an API request. The
+// activity is written to the database at most once every
+// sessionActivityInterval, the latest activity is always written when
+// the session ends.
+func (r *controllerRoot) recordActivity() {
+	now := time.Now()
+
+	r.mu.Lock()
+	if r.session == nil {
+		r.mu.Unlock()
+		return
+	}
+	r.lastActivity = now
+	if now.Sub(r.activityRecordedAt) < sessionActivityInterval {
+		r.mu.Unlock()
+		return
+	}
+	r.activityRecordedAt = now
+	s := *r.session
+	r.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sessionUpdateTimeout)
+		defer cancel()
+		if err := r.jimm.RecordIdentitySessionActivity(ctx, &s, now); err != nil {
+			zapctx.Error(ctx, "failed to record session activity", zap.String("identity", s.IdentityName), zap.Error(err))
+		}
+	}()
+}
+
+// endSession removes the session started on the connection, if there is
+// one, recording its latest activity.
+func (r *controllerRoot) endSession(ctx context.Context) {
+	r.mu.Lock()
+	if r.session == nil {
+		r.mu.Unlock()
+		return
+	}
+	s := *r.session
+	s.LastActivity = r.lastActivity
+	r.session = nil
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sessionUpdateTimeout)
+	defer cancel()
+	if err := r.jimm.EndIdentitySession(ctx, &s); err != nil {
+		zapctx.Error(ctx, "failed to end session", zap.String("identity", s.IdentityName), zap.Error(err))
+	}
+}
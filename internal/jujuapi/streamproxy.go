@@ -6,9 +6,12 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/loggo"
 	"github.com/juju/names/v5"
 	"github.com/juju/zaputil/zapctx"
 	"go.uber.org/zap"
@@ -21,7 +24,9 @@ import (
 )
 
 // A streamProxier serves the the /log endpoint by proxying
-// messages between the controller and client.
+// messages between the controller and client. The query parameters of
+// the client's request are passed on to the controller and any filter
+// they request on the log records is also applied by JIMM.
 type streamProxier struct {
 	// TODO(Kian): Refactor the apiServer to use the JIMM API rather than a concrete struct
 	// then we can write unit tests for the stream proxier.
@@ -82,6 +87,19 @@ func (s streamProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn)
 		return
 	}
 
+	query := jimmhttp.QueryFromContext(ctx)
+	var filter func(map[string]any) bool
+	if finalPath == "log" {
+		lf, err := newLogFilter(query)
+		if err != nil {
+			writeError(err.Error(), errors.CodeBadRequest)
+			return
+		}
+		if lf != nil {
+			filter = lf.allow
+		}
+	}
+
 	model, err := s.jimm.GetModel(ctx, uuid)
 	if err != nil {
 		writeError(err.Error(), errors.CodeModelNotFound)
@@ -96,14 +114,14 @@ func (s streamProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn)
 	}
 	defer api.Close()
 
-	controllerStream, err := api.ConnectStream(finalPath, nil)
+	controllerStream, err := api.ConnectStream(finalPath, query)
 	if err != nil {
 		zapctx.Error(ctx, "failed to connect stream", zap.Error(err))
 		writeError(fmt.Sprintf("failed to connect stream: %s", err.Error()), errors.CodeConnectionFailed)
 		return
 	}
 
-	jimmRPC.ProxyStreams(ctx, clientConn, controllerStream)
+	jimmRPC.ProxyFilteredStreams(ctx, clientConn, controllerStream, filter)
 }
 
 func checkPermission(ctx context.Context, path string, u *openfga.User, mt names.ModelTag) (bool, error) {
@@ -114,3 +132,59 @@ func checkPermission(ctx context.Context, path string, u *openfga.User, mt names
 		return false, errors.E("unknown endpoint " + path)
 	}
 }
+
+// A logFilter selects the debug-log records that are sent to a client.
+type logFilter struct {
+	level          loggo.Level
+	includeModules []string
+	excludeModules []string
+}
+
+// newLogFilter creates a logFilter from the query parameters of a
+// debug-log request, using the same parameters as juju. If the query
+// does not request any filtering a nil filter is returned.
+func newLogFilter(query url.Values) (*logFilter, error) {
+	var f logFilter
+	if v := query.Get("level"); v != "" {
+		level, ok := loggo.ParseLevel(v)
+		if !ok || level < loggo.TRACE || level > loggo.ERROR {
+			return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid log level %q", v))
+		}
+		f.level = level
+	}
+	f.includeModules = query["includeModule"]
+	f.excludeModules = query["excludeModule"]
+	if f.level == loggo.UNSPECIFIED && len(f.includeModules) == 0 && len(f.excludeModules) == 0 {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// allow reports whether the given message should be sent to the client.
+// Messages that are not log records, such as the initial error result,
+// are always sent.
+func (f *logFilter) allow(msg map[string]any) bool {
+	sev, ok := msg["sev"].(string)
+	if !ok {
+		return true
+	}
+	if level, ok := loggo.ParseLevel(sev); ok && level < f.level {
+		return false
+	}
+	module, _ := msg["mod"].(string)
+	if len(f.includeModules) > 0 && !matchLogModule(module, f.includeModules) {
+		return false
+	}
+	return !matchLogModule(module, f.excludeModules)
+}
+
+// matchLogModule reports whether the module is, or is a child of, one of
+// the given modules.
+func matchLogModule(module string, modules []string) bool {
+	for _, m := range modules {
+		if module == m || strings.HasPrefix(module, m+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Canonical.
+
+package jujuapi
+
+import (
+	"net/url"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLogFilter(t *testing.T) {
+	c := qt.New(t)
+
+	f, err := newLogFilter(url.Values{"replay": {"true"}})
+	c.Assert(err, qt.IsNil)
+	c.Check(f, qt.IsNil)
+
+	_, err = newLogFilter(url.Values{"level": {"LOUD"}})
+	c.Check(err, qt.ErrorMatches, `invalid log level "LOUD"`)
+
+	f, err = newLogFilter(url.Values{
+		"level":         {"INFO"},
+		"includeModule": {"juju.worker", "unit"},
+		"excludeModule": {"juju.worker.uniter"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	tests := []struct {
+		msg    map[string]any
+		expect bool
+	}{{
+		msg:    map[string]any{},
+		expect: true,
+	}, {
+		msg:    map[string]any{"sev": "INFO", "mod": "juju.worker"},
+		expect: true,
+	}, {
+		msg:    map[string]any{"sev": "ERROR", "mod": "unit.app-0.juju-log"},
+		expect: true,
+	}, {
+		msg:    map[string]any{"sev": "DEBUG", "mod": "juju.worker"},
+		expect: false,
+	}, {
+		msg:    map[string]any{"sev": "INFO", "mod": "juju.workers"},
+		expect: false,
+	}, {
+		msg:    map[string]any{"sev": "INFO", "mod": "juju.apiserver"},
+		expect: false,
+	}, {
+		msg:    map[string]any{"sev": "WARNING", "mod": "juju.worker.uniter.operation"},
+		expect: false,
+	}}
+	for _, test := range tests {
+		c.Check(f.allow(test.msg), qt.Equals, test.expect, qt.Commentf("%v", test.msg))
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/juju/juju/api"
 	"github.com/juju/juju/api/common"
+	"github.com/juju/loggo"
 	gc "gopkg.in/check.v1"
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
@@ -29,6 +30,17 @@ func (s *streamProxySuite) TestDebugLogs(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *streamProxySuite) TestDebugLogsFiltered(c *gc.C) {
+	conn := s.open(c, &api.Info{ModelTag: s.Model.ResourceTag()}, "bob")
+	defer conn.Close()
+	_, err := common.StreamDebugLog(context.TODO(), conn, common.DebugLogParams{
+		IncludeModule: []string{"juju.worker"},
+		ExcludeModule: []string{"juju.worker.uniter"},
+		Level:         loggo.WARNING,
+	})
+	c.Assert(err, gc.IsNil)
+}
+
 // TestDebugLogsError tests that an error is returned from JIMM
 // when a user doesn't have model access but tries to access model logs.
 // A user could craft a connection to immediately fetch logs, but using the Go client,
@@ -5,13 +5,13 @@ package jujuapi
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"net/http"
 	"regexp"
 	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/juju/juju/rpc"
-	"github.com/juju/juju/rpc/jsoncodec"
 	jujuparams "github.com/juju/juju/rpc/params"
 	"github.com/juju/zaputil"
 	"github.com/juju/zaputil/zapctx"
@@ -23,6 +23,7 @@ import (
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmhttp"
 	jimmRPC "github.com/canonical/jimm/v3/internal/rpc"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 const (
@@ -86,9 +87,13 @@ func (s *apiServer) Authenticate(ctx context.Context, w http.ResponseWriter, req
 func (s *apiServer) ServeWS(ctx context.Context, conn *websocket.Conn) {
 	identityId := auth.SessionIdentityFromContext(ctx)
 	controllerRoot := newControllerRoot(s.jimm, s.params, identityId)
+	controllerRoot.remoteAddress = conn.RemoteAddr().String()
+	controllerRoot.readConsistency = apiparams.ReadConsistency(jimmhttp.HeaderFromContext(ctx).Get(apiparams.ReadConsistencyHeader))
+	controllerRoot.adminMethodsDisabled = adminMethodsDisabled(ctx)
 	s.cleanup = controllerRoot.cleanup
 	Dblogger := controllerRoot.newAuditLogger()
 	serveRoot(ctx, controllerRoot, Dblogger, conn)
+	controllerRoot.endSession(ctx)
 }
 
 // Kill implements the rpc.Killer interface.
@@ -105,7 +110,7 @@ func serveRoot(ctx context.Context, root root, logger jimm.DbAuditLogger, wsConn
 	// Note that although NewConn accepts a `RecorderFactory` input, the call to conn.ServeRoot
 	// also accepts a `RecorderFactory` and will override anything set during the call to NewConn.
 	conn := rpc.NewConn(
-		jsoncodec.NewWebsocket(wsConn),
+		newCodec(wsConn),
 		nil,
 	)
 	rpcRecorderFactory := func() rpc.Recorder {
@@ -175,7 +180,7 @@ func modelInfoFromPath(path string) (uuid string, finalPath string, err error) {
 // requests to the appropriate Juju controller.
 func (s apiProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn) {
 	jwtGenerator := jimm.NewJWTGenerator(&s.jimm.Database, s.jimm, s.jimm.JWTService)
-	connectionFunc := controllerConnectionFunc(s, &jwtGenerator)
+	connectionFunc := controllerConnectionFunc(s, &jwtGenerator, newModelRedactor(s.params.RedactedModelFields, s.params.RedactedModelConfigKeys))
 	zapctx.Debug(ctx, "Starting proxier")
 	auditLogger := s.jimm.AddAuditLogEntry
 	proxyHelpers := jimmRPC.ProxyHelpers{
@@ -185,6 +190,8 @@ func (s apiProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn) {
 		AuditLog:                auditLogger,
 		LoginService:            s.jimm,
 		AuthenticatedIdentityID: auth.SessionIdentityFromContext(ctx),
+		FacadeDeprecations:      s.params.FacadeDeprecations,
+		RequestLimiter:          s.params.RequestLimiter,
 	}
 	if err := jimmRPC.ProxySockets(ctx, proxyHelpers); err != nil {
 		zapctx.Error(ctx, "failed to start jimm model proxy", zap.Error(err))
@@ -192,8 +199,10 @@ func (s apiProxier) ServeWS(ctx context.Context, clientConn *websocket.Conn) {
 }
 
 // controllerConnectionFunc returns a function that will be used to
-// connect to a controller when a client makes a request.
-func controllerConnectionFunc(s apiProxier, jwtGenerator *jimm.JWTGenerator) func(context.Context) (jimmRPC.WebsocketConnectionWithMetadata, error) {
+// connect to a controller when a client makes a request. The redactor
+// removes model config keys from the responses to ModelGet requests of
+// users with less than admin access to the model.
+func controllerConnectionFunc(s apiProxier, jwtGenerator *jimm.JWTGenerator, redactor modelRedactor) func(context.Context) (jimmRPC.WebsocketConnectionWithMetadata, error) {
 	return func(ctx context.Context) (jimmRPC.WebsocketConnectionWithMetadata, error) {
 		const op = errors.Op("proxy.controllerConnectionFunc")
 		path := jimmhttp.PathElementFromContext(ctx, "path")
@@ -213,6 +222,9 @@ func controllerConnectionFunc(s apiProxier, jwtGenerator *jimm.JWTGenerator) fun
 			zapctx.Error(ctx, "failed to find model", zap.String("uuid", uuid), zap.Error(err))
 			return jimmRPC.WebsocketConnectionWithMetadata{}, errors.E(err, errors.CodeNotFound)
 		}
+		if m.Controller.MaintenanceSince.Valid {
+			return jimmRPC.WebsocketConnectionWithMetadata{}, errors.E(op, jimm.ControllerMaintenanceError(&m.Controller))
+		}
 		jwtGenerator.SetTags(m.ResourceTag(), m.Controller.ResourceTag())
 		mt := m.ResourceTag()
 		zapctx.Debug(ctx, "Dialing Controller", zap.String("path", path))
@@ -221,11 +233,28 @@ func controllerConnectionFunc(s apiProxier, jwtGenerator *jimm.JWTGenerator) fun
 			zapctx.Error(ctx, "cannot dial controller", zap.String("controller", m.Controller.Name), zap.Error(err))
 			return jimmRPC.WebsocketConnectionWithMetadata{}, err
 		}
+		s.jimm.RecordModelConnection(ctx, &m)
 		fullModelName := m.Controller.Name + "/" + m.Name
+		drain, release := s.jimm.RegisterProxySession(&m.Controller)
 		return jimmRPC.WebsocketConnectionWithMetadata{
 			Conn:           controllerConn,
 			ControllerUUID: m.Controller.UUID,
 			ModelName:      fullModelName,
+			Drain:          drain,
+			DrainError:     jimm.ControllerMaintenanceError(&m.Controller),
+			Release:        release,
+			CheckRequest: func(ctx context.Context, facade, method string, params json.RawMessage) error {
+				if err := s.jimm.CheckModelFrozen(ctx, m.ID, facade, method); err != nil {
+					return err
+				}
+				return s.jimm.CheckCharmPolicy(ctx, facade, method, params)
+			},
+			ModifyResponse: func(ctx context.Context, facade, method string, response json.RawMessage) (json.RawMessage, error) {
+				if facade != "ModelConfig" || method != "ModelGet" {
+					return response, nil
+				}
+				return redactor.redactModelGetResponse(response, jwtGenerator.ModelAccess())
+			},
 		}, nil
 	}
 }
@@ -242,3 +271,12 @@ var websocketUpgrader = websocket.Upgrader{
 	ReadBufferSize:  websocketFrameSize,
 	WriteBufferSize: websocketFrameSize,
 }
+
+// controllerWebsocketUpgrader is the websocket upgrader for the
+// controller API, which additionally offers clients the CBOR encoding.
+var controllerWebsocketUpgrader = websocket.Upgrader{
+	CheckOrigin:     websocketUpgrader.CheckOrigin,
+	ReadBufferSize:  websocketFrameSize,
+	WriteBufferSize: websocketFrameSize,
+	Subprotocols:    []string{CBORSubprotocol},
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+import (
+	"context"
+
+	jujuerrors "github.com/juju/errors"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// ExportBundle exports the model the connection is made to as a bundle
+// in YAML format. If includeCharmDefaults is true the exported bundle
+// will include the default values of charm configuration settings.
+// ExportBundle uses the ExportBundle method on the Bundle facade.
+func (c Connection) ExportBundle(ctx context.Context, includeCharmDefaults bool) (string, error) {
+	const op = errors.Op("jujuclient.ExportBundle")
+
+	args := jujuparams.ExportBundleParams{
+		IncludeCharmDefaults: includeCharmDefaults,
+	}
+	var resp jujuparams.StringResult
+	if err := c.CallHighestFacadeVersion(ctx, "Bundle", []int{6}, "", "ExportBundle", &args, &resp); err != nil {
+		return "", errors.E(op, jujuerrors.Cause(err))
+	}
+	if resp.Error != nil {
+		return "", errors.E(op, resp.Error)
+	}
+	return resp.Result, nil
+}
@@ -129,6 +129,33 @@ func (c Connection) RevokeCredential(ctx context.Context, cred names.CloudCreden
 	return nil
 }
 
+// Credential retrieves the given credential from the controller. The
+// secret attributes of the credential are not returned. If the
+// controller does not have the credential an error with a code of
+// CodeNotFound is returned. Credential uses the Credential procedure on
+// the Cloud facade.
+func (c Connection) Credential(ctx context.Context, cred names.CloudCredentialTag) (jujuparams.CloudCredential, error) {
+	const op = errors.Op("jujuclient.Credential")
+	args := jujuparams.Entities{
+		Entities: []jujuparams.Entity{{
+			Tag: cred.String(),
+		}},
+	}
+	resp := jujuparams.CloudCredentialResults{
+		Results: make([]jujuparams.CloudCredentialResult, 1),
+	}
+	if err := c.CallHighestFacadeVersion(ctx, "Cloud", []int{7, 1}, "", "Credential", &args, &resp); err != nil {
+		return jujuparams.CloudCredential{}, errors.E(op, jujuerrors.Cause(err))
+	}
+	if resp.Results[0].Error != nil {
+		return jujuparams.CloudCredential{}, errors.E(op, resp.Results[0].Error)
+	}
+	if resp.Results[0].Result == nil {
+		return jujuparams.CloudCredential{}, errors.E(op, errors.CodeNotFound, "credential not found")
+	}
+	return *resp.Results[0].Result, nil
+}
+
 // Cloud retrieves information about the given cloud. Cloud uses the
 // Cloud procedure on the Cloud facade.
 func (c Connection) Cloud(ctx context.Context, tag names.CloudTag, cloud *jujuparams.Cloud) error {
@@ -11,6 +11,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/canonical/jimm/v3/internal/errors"
 	"github.com/canonical/jimm/v3/internal/jimmtest"
 )
 
@@ -145,8 +146,15 @@ func (s *cloudSuite) TestRevokeCredential(c *gc.C) {
 	c.Assert(err, gc.Equals, nil)
 	c.Assert(models, gc.HasLen, 0)
 
+	cc, err := s.API.Credential(context.Background(), tag)
+	c.Assert(err, gc.Equals, nil)
+	c.Check(cc.AuthType, gc.Equals, "userpass")
+
 	err = s.API.RevokeCredential(context.Background(), tag)
 	c.Assert(err, gc.Equals, nil)
+
+	_, err = s.API.Credential(context.Background(), tag)
+	c.Check(errors.ErrorCode(err), gc.Equals, errors.CodeNotFound)
 }
 
 func (s *cloudSuite) TestUpdateCredentialWithModels(c *gc.C) {
@@ -0,0 +1,61 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+import (
+	"context"
+
+	jujuerrors "github.com/juju/errors"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// ControllerCall calls the given method of a facade on the controller.
+// The arguments and response are passed through unchanged, so the
+// caller is responsible for ensuring they match the facade method.
+func (c Connection) ControllerCall(ctx context.Context, facade string, version int, method string, args, resp interface{}) error {
+	const op = errors.Op("jujuclient.ControllerCall")
+
+	if err := c.Call(ctx, facade, version, "", method, args, resp); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	return nil
+}
+
+// ControllerConfig returns the configuration of the controller. This
+// uses the ControllerConfig method on the Controller facade version 11,
+// or 9 if 11 is not available.
+func (c Connection) ControllerConfig(ctx context.Context) (map[string]interface{}, error) {
+	const op = errors.Op("jujuclient.ControllerConfig")
+	var resp jujuparams.ControllerConfigResult
+	if err := c.CallHighestFacadeVersion(ctx, "Controller", []int{11, 9}, "", "ControllerConfig", nil, &resp); err != nil {
+		return nil, errors.E(op, jujuerrors.Cause(err))
+	}
+	return resp.Config, nil
+}
+
+// GrantControllerAccess grants the given access level on the controller
+// to the given user. This uses the ModifyControllerAccess method on the
+// Controller facade version 11, or 9 if 11 is not available.
+func (c Connection) GrantControllerAccess(ctx context.Context, user names.UserTag, access string) error {
+	const op = errors.Op("jujuclient.GrantControllerAccess")
+	args := jujuparams.ModifyControllerAccessRequest{
+		Changes: []jujuparams.ModifyControllerAccess{{
+			UserTag: user.String(),
+			Action:  jujuparams.GrantControllerAccess,
+			Access:  access,
+		}},
+	}
+	resp := jujuparams.ErrorResults{
+		Results: make([]jujuparams.ErrorResult, 1),
+	}
+	if err := c.CallHighestFacadeVersion(ctx, "Controller", []int{11, 9}, "", "ModifyControllerAccess", &args, &resp); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	if resp.Results[0].Error != nil {
+		return errors.E(op, resp.Results[0].Error)
+	}
+	return nil
+}
@@ -29,6 +29,7 @@ import (
 
 	"github.com/canonical/jimm/v3/internal/dbmodel"
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/faults"
 	"github.com/canonical/jimm/v3/internal/jimm"
 	"github.com/canonical/jimm/v3/internal/jimmjwx"
 	"github.com/canonical/jimm/v3/internal/rpc"
@@ -52,6 +53,16 @@ type ControllerCredentialsStore interface {
 type Dialer struct {
 	ControllerCredentialsStore ControllerCredentialsStore
 	JWTService                 *jimmjwx.JWTService
+
+	// CallCeiling is the longest a call to a controller may wait for a
+	// response before the connection is considered stuck and is
+	// recycled, failing every call waiting on it. Watcher Next calls
+	// are exempt. If this is zero connections are never recycled.
+	CallCeiling time.Duration
+
+	// OnRecycle, if set, is called whenever a connection is recycled
+	// because a call exceeded the CallCeiling.
+	OnRecycle func(RecycledConnection)
 }
 
 func (d *Dialer) createLoginRequest(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, p map[string]string) (*jujuparams.LoginRequest, error) {
@@ -86,6 +97,9 @@ func (d *Dialer) createLoginRequest(ctx context.Context, ctl *dbmodel.Controller
 func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, requiredPermissions map[string]string) (jimm.API, error) {
 	const op = errors.Op("jujuclient.Dial")
 
+	if err := faults.Check(faults.ControllerConnection, ctl.Name); err != nil {
+		return nil, errors.E(op, err)
+	}
 	conn, err := rpc.Dial(ctx, ctl, modelTag, "", nil)
 	if err != nil {
 		return nil, err
@@ -101,7 +115,13 @@ func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag nam
 	}
 
 	var res jujuparams.LoginResult
-	if err := client.Call(ctx, "Admin", 3, "", "Login", loginRequest, &res); err != nil {
+	loginCtx := ctx
+	if ctl.RPCTimeout > 0 {
+		var cancel context.CancelFunc
+		loginCtx, cancel = context.WithTimeout(ctx, ctl.RPCTimeout)
+		defer cancel()
+	}
+	if err := client.Call(loginCtx, "Admin", 3, "", "Login", loginRequest, &res); err != nil {
 		client.Close()
 		return nil, errors.E(op, errors.CodeConnectionFailed, "authentication failed", err)
 	}
@@ -116,9 +136,11 @@ func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag nam
 	ctl.Addresses = dbmodel.HostPorts(res.Servers)
 	facades := make(map[string]bool)
 	bestFacadeVersions := make(map[string]int)
+	supportedFacades := make(map[string][]int)
 	for _, fv := range res.Facades {
 		sort.Sort(sort.Reverse(sort.IntSlice(fv.Versions)))
 		bestFacadeVersions[fv.Name] = fv.Versions[0]
+		supportedFacades[fv.Name] = fv.Versions
 		for _, v := range fv.Versions {
 			facades[fmt.Sprintf("%s\x1f%d", fv.Name, v)] = true
 		}
@@ -126,15 +148,26 @@ func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag nam
 
 	monitorC := make(chan struct{})
 	broken := new(uint32)
-	go pinger(client, ct.Id(), monitorC, broken)
+	go pinger(client, ct.Id(), pingIntervalFor(ctl), pingTimeoutFor(ctl), monitorC, broken)
+	calls := newCallTracker(ctl.Name)
+	if d.CallCeiling > 0 {
+		rc := RecycledConnection{
+			Controller:     ctl.Name,
+			ControllerUUID: ct.Id(),
+			Model:          modelTag.Id(),
+		}
+		go watchdog(client, calls, rc, d.CallCeiling, d.OnRecycle, monitorC, broken)
+	}
 	return &Connection{
 		ctx:                ctx,
 		client:             client,
 		userTag:            loginRequest.AuthTag,
 		facadeVersions:     facades,
 		bestFacadeVersions: bestFacadeVersions,
+		supportedFacades:   supportedFacades,
 		monitorC:           monitorC,
 		broken:             broken,
+		calls:              calls,
 		dialer:             d,
 		ctl:                ctl,
 		mt:                 modelTag,
@@ -145,10 +178,30 @@ func (d *Dialer) Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag nam
 const pingTimeout = 15 * time.Second
 const pingInterval = 30 * time.Second
 
-// pinger runs in the background ensuring the client connection is kept alive.
-func pinger(client *rpc.Client, controller string, doneC <-chan struct{}, broken *uint32) {
+// pingTimeoutFor returns the timeout to use for the ping requests sent to
+// the given controller.
+func pingTimeoutFor(ctl *dbmodel.Controller) time.Duration {
+	if ctl.RPCTimeout > 0 {
+		return ctl.RPCTimeout
+	}
+	return pingTimeout
+}
+
+// pingIntervalFor returns the interval at which connections to the given
+// controller are pinged.
+func pingIntervalFor(ctl *dbmodel.Controller) time.Duration {
+	if ctl.KeepaliveInterval > 0 {
+		return ctl.KeepaliveInterval
+	}
+	return pingInterval
+}
+
+// pinger runs in the background ensuring the client connection is kept
+// alive. The connection is pinged every interval, if a ping does not
+// succeed within timeout the connection is marked as broken.
+func pinger(client *rpc.Client, controller string, interval, timeout time.Duration, doneC <-chan struct{}, broken *uint32) {
 	doPing := func() bool {
-		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
 
 		durationObserver := servermon.DurationObserver(servermon.JujuPingDurationHistogram, controller)
@@ -161,7 +214,7 @@ func pinger(client *rpc.Client, controller string, doneC <-chan struct{}, broken
 		return true
 	}
 
-	t := time.NewTimer(pingInterval)
+	t := time.NewTimer(interval)
 	defer t.Stop()
 	for {
 		select {
@@ -173,6 +226,7 @@ func pinger(client *rpc.Client, controller string, doneC <-chan struct{}, broken
 				atomic.StoreUint32(broken, 1)
 				return
 			}
+			t.Reset(interval)
 		}
 	}
 }
@@ -189,9 +243,11 @@ type Connection struct {
 	userTag            string
 	facadeVersions     map[string]bool
 	bestFacadeVersions map[string]int
+	supportedFacades   map[string][]int
 
 	monitorC chan struct{}
 	broken   *uint32
+	calls    *callTracker
 
 	dialer      *Dialer
 	redialCount *atomic.Int32
@@ -241,8 +297,10 @@ func (c *Connection) redial(ctx context.Context, requiredPermissions map[string]
 	c.client = conn.client
 	c.userTag = conn.userTag
 	c.facadeVersions = conn.facadeVersions
+	c.supportedFacades = conn.supportedFacades
 	c.monitorC = conn.monitorC
 	c.broken = conn.broken
+	c.calls = conn.calls
 	return nil
 }
 
@@ -258,7 +316,17 @@ func (c *Connection) Call(ctx context.Context, facade string, version int, id, m
 	defer durationObserver()
 	defer servermon.ErrorCounter(servermon.JujuCallErrorCount, &err, labels...)
 
+	if c.ctl != nil {
+		if err := faults.Check(faults.ControllerConnection, c.ctl.Name); err != nil {
+			// Mark the connection broken, as a dropped connection
+			// would be.
+			atomic.StoreUint32(c.broken, 1)
+			return err
+		}
+	}
+	done := c.calls.start(facade, method)
 	err = c.client.Call(ctx, facade, version, id, method, args, resp)
+	done()
 	if err != nil {
 		if rpcErr, ok := err.(*rpc.Error); ok {
 			// if we get a permission check required error, we redial the controller
@@ -304,6 +372,12 @@ func (c *Connection) BestFacadeVersion(facade string) int {
 	return c.bestFacadeVersions[facade]
 }
 
+// SupportedFacadeVersions returns the versions of each facade supported
+// by the controller, newest first.
+func (c *Connection) SupportedFacadeVersions() map[string][]int {
+	return c.supportedFacades
+}
+
 // ModelTag returns the tag of the model the client is connected
 // to if there is one. It returns false for a controller-only connection.
 func (c *Connection) ModelTag() (names.ModelTag, bool) {
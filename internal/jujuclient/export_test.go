@@ -0,0 +1,12 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+var (
+	NewCallTracker = newCallTracker
+	Watchdog       = watchdog
+)
+
+func StartCall(t *callTracker, facade, method string) func() {
+	return t.start(facade, method)
+}
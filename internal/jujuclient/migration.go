@@ -0,0 +1,26 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+import (
+	"context"
+
+	jujuerrors "github.com/juju/errors"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// MigrationTargetPrechecks checks whether the controller the connection
+// is made to can accept the migration of the described model. A nil
+// error means the controller expects the migration to succeed.
+// MigrationTargetPrechecks uses the Prechecks method on the
+// MigrationTarget facade.
+func (c Connection) MigrationTargetPrechecks(ctx context.Context, info jujuparams.MigrationModelInfo) error {
+	const op = errors.Op("jujuclient.MigrationTargetPrechecks")
+
+	if err := c.CallHighestFacadeVersion(ctx, "MigrationTarget", []int{1, 2, 3}, "", "Prechecks", &info, nil); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	return nil
+}
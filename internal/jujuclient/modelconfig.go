@@ -0,0 +1,42 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+import (
+	"context"
+
+	jujuerrors "github.com/juju/errors"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// SetModelConfig sets the given configuration on the model the
+// connection is made to. SetModelConfig uses the ModelSet method on the
+// ModelConfig facade.
+func (c Connection) SetModelConfig(ctx context.Context, config map[string]interface{}) error {
+	const op = errors.Op("jujuclient.SetModelConfig")
+
+	args := jujuparams.ModelSet{
+		Config: config,
+	}
+	if err := c.CallHighestFacadeVersion(ctx, "ModelConfig", []int{3, 4}, "", "ModelSet", &args, nil); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	return nil
+}
+
+// UnsetModelConfig resets the given configuration keys of the model the
+// connection is made to to their default values. UnsetModelConfig uses
+// the ModelUnset method on the ModelConfig facade.
+func (c Connection) UnsetModelConfig(ctx context.Context, keys []string) error {
+	const op = errors.Op("jujuclient.UnsetModelConfig")
+
+	args := jujuparams.ModelUnset{
+		Keys: keys,
+	}
+	if err := c.CallHighestFacadeVersion(ctx, "ModelConfig", []int{3, 4}, "", "ModelUnset", &args, nil); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+import (
+	"context"
+
+	jujuerrors "github.com/juju/errors"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// UserInfo returns the local users defined on the controller, including
+// disabled users. UserInfo uses the UserInfo method on the UserManager
+// facade.
+func (c Connection) UserInfo(ctx context.Context) ([]jujuparams.UserInfo, error) {
+	const op = errors.Op("jujuclient.UserInfo")
+
+	args := jujuparams.UserInfoRequest{
+		IncludeDisabled: true,
+	}
+	var resp jujuparams.UserInfoResults
+	if err := c.CallHighestFacadeVersion(ctx, "UserManager", []int{3}, "", "UserInfo", &args, &resp); err != nil {
+		return nil, errors.E(op, jujuerrors.Cause(err))
+	}
+	users := make([]jujuparams.UserInfo, 0, len(resp.Results))
+	for _, r := range resp.Results {
+		if r.Error != nil {
+			return nil, errors.E(op, r.Error)
+		}
+		if r.Result != nil {
+			users = append(users, *r.Result)
+		}
+	}
+	return users, nil
+}
+
+// AddUser adds a local user with the given name and password to the
+// controller. If the user already exists an error with a code of
+// CodeAlreadyExists is returned. AddUser uses the AddUser method on the
+// UserManager facade.
+func (c Connection) AddUser(ctx context.Context, username, password string) error {
+	const op = errors.Op("jujuclient.AddUser")
+
+	args := jujuparams.AddUsers{
+		Users: []jujuparams.AddUser{{
+			Username: username,
+			Password: password,
+		}},
+	}
+	resp := jujuparams.AddUserResults{
+		Results: make([]jujuparams.AddUserResult, 1),
+	}
+	if err := c.CallHighestFacadeVersion(ctx, "UserManager", []int{3}, "", "AddUser", &args, &resp); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	if resp.Results[0].Error != nil {
+		return errors.E(op, resp.Results[0].Error)
+	}
+	return nil
+}
+
+// SetUserPassword sets the password of the given local user on the
+// controller. SetUserPassword uses the SetPassword method on the
+// UserManager facade.
+func (c Connection) SetUserPassword(ctx context.Context, username, password string) error {
+	const op = errors.Op("jujuclient.SetUserPassword")
+
+	args := jujuparams.EntityPasswords{
+		Changes: []jujuparams.EntityPassword{{
+			Tag:      names.NewUserTag(username).String(),
+			Password: password,
+		}},
+	}
+	resp := jujuparams.ErrorResults{
+		Results: make([]jujuparams.ErrorResult, 1),
+	}
+	if err := c.CallHighestFacadeVersion(ctx, "UserManager", []int{3}, "", "SetPassword", &args, &resp); err != nil {
+		return errors.E(op, jujuerrors.Cause(err))
+	}
+	if resp.Results[0].Error != nil {
+		return errors.E(op, resp.Results[0].Error)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Canonical.
+
+package jujuclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/rpc"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// A RecycledConnection describes a controller connection that was
+// recycled because a call made on it did not complete in time.
+type RecycledConnection struct {
+	// Controller and ControllerUUID identify the controller.
+	Controller     string
+	ControllerUUID string
+
+	// Model is the UUID of the model the connection was to, if any.
+	Model string
+
+	// Facade and Method identify the oldest outstanding call.
+	Facade string
+	Method string
+
+	// Elapsed is how long the oldest outstanding call had been waiting.
+	Elapsed time.Duration
+
+	// Inflight is the number of calls outstanding when the connection
+	// was recycled, all of which fail.
+	Inflight int
+}
+
+// A trackedCall is a call waiting for a response.
+type trackedCall struct {
+	facade string
+	method string
+	start  time.Time
+}
+
+// A callTracker keeps track of the calls waiting for a response on a
+// connection.
+type callTracker struct {
+	controller string
+
+	mu    sync.Mutex
+	seq   uint64
+	calls map[uint64]trackedCall
+}
+
+func newCallTracker(controller string) *callTracker {
+	return &callTracker{
+		controller: controller,
+		calls:      make(map[uint64]trackedCall),
+	}
+}
+
+// start records the start of a call and returns a function that must be
+// called when the call completes. Watcher Next calls block until there
+// are changes to report, however long that takes, so they are not
+// tracked.
+func (t *callTracker) start(facade, method string) func() {
+	if method == "Next" {
+		return func() {}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	id := t.seq
+	t.calls[id] = trackedCall{facade: facade, method: method, start: time.Now()}
+	servermon.JujuInflightCalls.WithLabelValues(t.controller).Inc()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.calls[id]; ok {
+			delete(t.calls, id)
+			servermon.JujuInflightCalls.WithLabelValues(t.controller).Dec()
+		}
+	}
+}
+
+// oldest returns the oldest call waiting for a response and the number
+// of waiting calls.
+func (t *callTracker) oldest() (trackedCall, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var oldest trackedCall
+	for _, c := range t.calls {
+		if oldest.start.IsZero() || c.start.Before(oldest.start) {
+			oldest = c
+		}
+	}
+	return oldest, len(t.calls)
+}
+
+// watchdog runs in the background checking the calls waiting for a
+// response on a connection. If any call has been waiting longer than the
+// ceiling the controller is assumed to be hung, or the TCP connection
+// half-open, and the connection is torn down so that the waiting calls
+// fail rather than piling up behind a connection that will never
+// respond. The connection is marked as broken so that it is replaced.
+func watchdog(client *rpc.Client, calls *callTracker, rc RecycledConnection, ceiling time.Duration, onRecycle func(RecycledConnection), doneC <-chan struct{}, broken *uint32) {
+	interval := ceiling / 4
+	if interval <= 0 {
+		interval = ceiling
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-doneC:
+			return
+		case <-t.C:
+			call, n := calls.oldest()
+			if n == 0 || time.Since(call.start) <= ceiling {
+				continue
+			}
+			rc.Facade = call.facade
+			rc.Method = call.method
+			rc.Elapsed = time.Since(call.start)
+			rc.Inflight = n
+
+			atomic.StoreUint32(broken, 1)
+			client.Abort(errors.E(errors.CodeConnectionFailed, fmt.Sprintf("connection to controller %s recycled, %s.%s call did not complete within %s", rc.Controller, rc.Facade, rc.Method, ceiling)))
+			servermon.JujuConnectionsRecycledCount.WithLabelValues(rc.Controller).Inc()
+			zapctx.Warn(context.Background(), "recycled stuck controller connection",
+				zap.String("controller", rc.Controller),
+				zap.String("facade", rc.Facade),
+				zap.String("method", rc.Method),
+				zap.Duration("elapsed", rc.Elapsed),
+				zap.Int("inflight", rc.Inflight),
+			)
+			if onRecycle != nil {
+				onRecycle(rc)
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Canonical.
+
+package jujuclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gorilla/websocket"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/jujuclient"
+	"github.com/canonical/jimm/v3/internal/rpc"
+)
+
+func TestWatchdogRecyclesStuckConnection(t *testing.T) {
+	c := qt.New(t)
+
+	// The server reads requests but never responds, as a hung
+	// controller would.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var u websocket.Upgrader
+		conn, err := u.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			var msg map[string]interface{}
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	client, err := rpc.Dialer{}.Dial(context.Background(), "ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	c.Assert(err, qt.IsNil)
+	defer client.Close()
+
+	calls := jujuclient.NewCallTracker("controller-1")
+	recycled := make(chan jujuclient.RecycledConnection, 1)
+	doneC := make(chan struct{})
+	defer close(doneC)
+	broken := new(uint32)
+	go jujuclient.Watchdog(client, calls, jujuclient.RecycledConnection{
+		Controller:     "controller-1",
+		ControllerUUID: "00000001-0000-0000-0000-000000000001",
+	}, 100*time.Millisecond, func(rc jujuclient.RecycledConnection) {
+		recycled <- rc
+	}, doneC, broken)
+
+	// Watcher Next calls block legitimately and are not tracked.
+	next := jujuclient.StartCall(calls, "AllWatcher", "Next")
+	defer next()
+
+	errc := make(chan error, 1)
+	go func() {
+		done := jujuclient.StartCall(calls, "ModelManager", "ModelInfo")
+		defer done()
+		errc <- client.Call(context.Background(), "ModelManager", 9, "", "ModelInfo", nil, nil)
+	}()
+
+	select {
+	case rc := <-recycled:
+		c.Check(rc.Controller, qt.Equals, "controller-1")
+		c.Check(rc.ControllerUUID, qt.Equals, "00000001-0000-0000-0000-000000000001")
+		c.Check(rc.Facade, qt.Equals, "ModelManager")
+		c.Check(rc.Method, qt.Equals, "ModelInfo")
+		c.Check(rc.Inflight, qt.Equals, 1)
+		c.Check(rc.Elapsed > 100*time.Millisecond, qt.IsTrue)
+	case <-time.After(5 * time.Second):
+		c.Fatal("connection not recycled")
+	}
+
+	err = <-errc
+	c.Check(err, qt.ErrorMatches, `connection to controller controller-1 recycled, ModelManager.ModelInfo call did not complete within 100ms`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeConnectionFailed)
+	c.Check(*broken, qt.Equals, uint32(1))
+	c.Check(client.IsBroken(), qt.IsTrue)
+}
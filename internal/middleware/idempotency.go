@@ -0,0 +1,217 @@
+// Copyright 2024 Canonical.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/auth"
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+)
+
+const (
+	// IdempotencyKeyHeader is the header in which clients supply an
+	// idempotency key with a mutating request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on responses that are replayed
+	// from an earlier request made with the same idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength is the maximum length of an idempotency
+	// key.
+	maxIdempotencyKeyLength = 255
+
+	// maxIdempotentRequestSize is the maximum size of the body of a
+	// request made with an idempotency key.
+	maxIdempotentRequestSize = 10 << 20
+)
+
+// An IdempotencyStore records requests made with idempotency keys and
+// their responses.
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(ctx context.Context, k *dbmodel.IdempotencyKey) (bool, error)
+	CompleteIdempotencyKey(ctx context.Context, k *dbmodel.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, k *dbmodel.IdempotencyKey) error
+}
+
+// Idempotency deduplicates mutating requests that carry an
+// Idempotency-Key header, so that clients can safely retry requests
+// after a timeout. The first request made by an identity with a key is
+// processed normally and its response recorded. Retries with the same
+// key, and an identical request, within the deduplication window are
+// answered with the recorded response without being processed again.
+// Reusing a key for a different request is rejected, as is a retry made
+// while the original request is still being processed. Responses with a
+// server error status are not recorded, so the request can be retried.
+// Requests without a key, or using a safe method such as GET, are not
+// affected.
+type Idempotency struct {
+	// Store records the requests and their responses.
+	Store IdempotencyStore
+
+	// Window is the time for which a key is remembered.
+	Window time.Duration
+
+	// Identity returns the name of the identity that made the request.
+	// If this is nil the identity authenticated by the earlier
+	// middleware is used. Requests with no identity are not
+	// deduplicated.
+	Identity func(*http.Request) string
+}
+
+// Handler returns a handler that deduplicates requests before passing
+// them to next.
+func (i *Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || i == nil || i.Window <= 0 || !mutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+		identity := i.identity(r)
+		if identity == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, "idempotency key too long", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentRequestSize))
+		if err != nil {
+			http.Error(w, "cannot read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(r, body)
+		now := time.Now()
+		k := dbmodel.IdempotencyKey{
+			CreatedAt:    now,
+			ExpiresAt:    now.Add(i.Window),
+			IdentityName: identity,
+			Key:          key,
+			RequestHash:  hash,
+		}
+		reserved, err := i.Store.ReserveIdempotencyKey(ctx, &k)
+		if err != nil {
+			zapctx.Error(ctx, "cannot reserve idempotency key", zap.Error(err))
+			http.Error(w, "cannot check idempotency key", http.StatusInternalServerError)
+			return
+		}
+		if !reserved {
+			replayIdempotentResponse(w, &k, hash)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		defer func() {
+			// Use a fresh context so the outcome is recorded even
+			// if the client has gone away.
+			ctx := context.WithoutCancel(ctx)
+			if rec.status == 0 || rec.status >= http.StatusInternalServerError {
+				if err := i.Store.DeleteIdempotencyKey(ctx, &k); err != nil {
+					zapctx.Error(ctx, "cannot release idempotency key", zap.Error(err))
+				}
+				return
+			}
+			k.StatusCode = rec.status
+			k.ContentType = rec.Header().Get("Content-Type")
+			k.Body = rec.body.Bytes()
+			if err := i.Store.CompleteIdempotencyKey(ctx, &k); err != nil {
+				zapctx.Error(ctx, "cannot record idempotent response", zap.Error(err))
+			}
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// identity returns the name of the identity that made the request.
+func (i *Idempotency) identity(r *http.Request) string {
+	if i.Identity != nil {
+		return i.Identity(r)
+	}
+	if identity := auth.SessionIdentityFromContext(r.Context()); identity != "" {
+		return identity
+	}
+	if user, err := IdentityFromContext(r.Context()); err == nil && user.Identity != nil {
+		return user.Name
+	}
+	return ""
+}
+
+// replayIdempotentResponse writes the response recorded for the given
+// key, if the request matches the one originally made with the key.
+func replayIdempotentResponse(w http.ResponseWriter, k *dbmodel.IdempotencyKey, hash string) {
+	switch {
+	case k.RequestHash != hash:
+		http.Error(w, "idempotency key has already been used for a different request", http.StatusUnprocessableEntity)
+	case !k.Completed:
+		http.Error(w, "a request with this idempotency key is in progress", http.StatusConflict)
+	default:
+		if k.ContentType != "" {
+			w.Header().Set("Content-Type", k.ContentType)
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(k.StatusCode)
+		_, _ = w.Write(k.Body)
+	}
+}
+
+// mutatingMethod reports whether requests with the given method may
+// change state.
+func mutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestHash returns a hash identifying the given request.
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	for _, s := range []string{r.Method, r.URL.Path, r.URL.RawQuery} {
+		_, _ = io.WriteString(h, s)
+		_, _ = h.Write([]byte{0})
+	}
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// A responseRecorder passes a response through to the client, recording
+// its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Canonical.
+package middleware_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/middleware"
+)
+
+// memoryIdempotencyStore is an in-memory middleware.IdempotencyStore.
+type memoryIdempotencyStore struct {
+	mu     sync.Mutex
+	nextID uint
+	keys   map[string]dbmodel.IdempotencyKey
+}
+
+func (s *memoryIdempotencyStore) ReserveIdempotencyKey(_ context.Context, k *dbmodel.IdempotencyKey) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		s.keys = make(map[string]dbmodel.IdempotencyKey)
+	}
+	id := k.IdentityName + "/" + k.Key
+	if existing, ok := s.keys[id]; ok && existing.ExpiresAt.After(k.CreatedAt) {
+		*k = existing
+		return false, nil
+	}
+	s.nextID++
+	k.ID = s.nextID
+	s.keys[id] = *k
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) CompleteIdempotencyKey(_ context.Context, k *dbmodel.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k.Completed = true
+	s.keys[k.IdentityName+"/"+k.Key] = *k
+	return nil
+}
+
+func (s *memoryIdempotencyStore) DeleteIdempotencyKey(_ context.Context, k *dbmodel.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, k.IdentityName+"/"+k.Key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	c := qt.New(t)
+
+	var calls int
+	status := http.StatusCreated
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, err := io.ReadAll(r.Body)
+		c.Assert(err, qt.IsNil)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"call":%d,"body":%q}`, calls, body)
+	})
+	idem := &middleware.Idempotency{
+		Store:  new(memoryIdempotencyStore),
+		Window: time.Hour,
+		Identity: func(r *http.Request) string {
+			return r.Header.Get("X-Test-Identity")
+		},
+	}
+	h := idem.Handler(next)
+
+	do := func(method, identity, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/rebac/v1/groups", strings.NewReader(body))
+		req.Header.Set("X-Test-Identity", identity)
+		if key != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// The first request is processed.
+	rr := do("POST", "alice", "key-1", "group-1")
+	c.Check(rr.Code, qt.Equals, http.StatusCreated)
+	c.Check(rr.Body.String(), qt.Equals, `{"call":1,"body":"group-1"}`)
+	c.Check(rr.Header().Get(middleware.IdempotentReplayedHeader), qt.Equals, "")
+
+	// A retry is answered with the recorded response.
+	rr = do("POST", "alice", "key-1", "group-1")
+	c.Check(rr.Code, qt.Equals, http.StatusCreated)
+	c.Check(rr.Body.String(), qt.Equals, `{"call":1,"body":"group-1"}`)
+	c.Check(rr.Header().Get("Content-Type"), qt.Equals, "application/json")
+	c.Check(rr.Header().Get(middleware.IdempotentReplayedHeader), qt.Equals, "true")
+	c.Check(calls, qt.Equals, 1)
+
+	// Reusing the key for a different request is rejected.
+	rr = do("POST", "alice", "key-1", "group-2")
+	c.Check(rr.Code, qt.Equals, http.StatusUnprocessableEntity)
+	c.Check(calls, qt.Equals, 1)
+
+	// Keys belong to an identity.
+	rr = do("POST", "bob", "key-1", "group-1")
+	c.Check(rr.Code, qt.Equals, http.StatusCreated)
+	c.Check(calls, qt.Equals, 2)
+
+	// Requests without a key, or with a safe method, are not
+	// deduplicated.
+	do("POST", "alice", "", "group-1")
+	do("POST", "alice", "", "group-1")
+	do("GET", "alice", "key-1", "")
+	c.Check(calls, qt.Equals, 5)
+
+	// Server errors are not recorded, so the request may be retried.
+	status = http.StatusInternalServerError
+	rr = do("DELETE", "alice", "key-2", "")
+	c.Check(rr.Code, qt.Equals, http.StatusInternalServerError)
+	status = http.StatusOK
+	rr = do("DELETE", "alice", "key-2", "")
+	c.Check(rr.Code, qt.Equals, http.StatusOK)
+	c.Check(calls, qt.Equals, 7)
+
+	// Keys that are too long are rejected.
+	rr = do("POST", "alice", strings.Repeat("k", 256), "group-1")
+	c.Check(rr.Code, qt.Equals, http.StatusBadRequest)
+	c.Check(calls, qt.Equals, 7)
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	c := qt.New(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+	idem := &middleware.Idempotency{
+		Store:    new(memoryIdempotencyStore),
+		Window:   time.Hour,
+		Identity: func(*http.Request) string { return "alice" },
+	}
+	h := idem.Handler(next)
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/rebac/v1/groups", strings.NewReader("group-1"))
+		req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+		return req
+	}
+	done := make(chan int)
+	go func() {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, newRequest())
+		done <- rr.Code
+	}()
+	<-started
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, newRequest())
+	c.Check(rr.Code, qt.Equals, http.StatusConflict)
+
+	close(release)
+	c.Check(<-done, qt.Equals, http.StatusOK)
+}
+
+func TestIdempotencyDisabled(t *testing.T) {
+	c := qt.New(t)
+
+	var calls int
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	idem := &middleware.Idempotency{
+		Store:    new(memoryIdempotencyStore),
+		Identity: func(*http.Request) string { return "alice" },
+	}
+	h := idem.Handler(next)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("POST", "/rebac/v1/groups", nil)
+		req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	c.Check(calls, qt.Equals, 2)
+}
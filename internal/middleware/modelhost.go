@@ -0,0 +1,82 @@
+// Copyright 2024 Canonical.
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// modelHostPaths holds the model endpoints that can be reached using a
+// model's DNS name.
+var modelHostPaths = []string{"/api", "/log", "/charms", "/applications"}
+
+var modelUUIDLabel = regexp.MustCompile(`^\w{8}-\w{4}-\w{4}-\w{4}-\w{12}$`)
+
+// ModelHostRouter provides middleware that routes requests addressed to a
+// per-model DNS name, such as <model-uuid>.models.example.com, to the
+// model's endpoints. This allows clients to connect to a model without
+// knowing the /model/<uuid>/... paths.
+type ModelHostRouter struct {
+	domain string
+}
+
+// NewModelHostRouter returns a new ModelHostRouter that routes requests
+// for hosts in the given domain. If domain is empty no requests are
+// routed.
+func NewModelHostRouter(domain string) *ModelHostRouter {
+	return &ModelHostRouter{domain: strings.ToLower(strings.Trim(domain, "."))}
+}
+
+// ModelUUID returns the model UUID encoded in the host the given request
+// is addressed to. The TLS server name (SNI) is used in preference to the
+// Host header when the request was received over TLS. If the request is
+// not addressed to a model host an empty string is returned.
+func (m *ModelHostRouter) ModelUUID(r *http.Request) string {
+	if m.domain == "" {
+		return ""
+	}
+	host := r.Host
+	if r.TLS != nil && r.TLS.ServerName != "" {
+		host = r.TLS.ServerName
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	label, ok := strings.CutSuffix(host, "."+m.domain)
+	if !ok || !modelUUIDLabel.MatchString(label) {
+		return ""
+	}
+	return label
+}
+
+// Handler rewrites requests for model endpoints addressed to a model host
+// to the equivalent /model/<uuid>/... path before passing them to h. All
+// other requests are passed to h unchanged.
+func (m *ModelHostRouter) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uuid := m.ModelUUID(r)
+		if uuid == "" || !isModelHostPath(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = "/model/" + uuid + r.URL.Path
+		if r.URL.RawPath != "" {
+			r2.URL.RawPath = "/model/" + uuid + r.URL.RawPath
+		}
+		r2.RequestURI = r2.URL.RequestURI()
+		h.ServeHTTP(w, r2)
+	})
+}
+
+func isModelHostPath(path string) bool {
+	for _, p := range modelHostPaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
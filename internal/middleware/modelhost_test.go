@@ -0,0 +1,102 @@
+// Copyright 2024 Canonical.
+package middleware_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/middleware"
+)
+
+func TestModelHostRouter(t *testing.T) {
+	const uuid = "00000002-0000-0000-0000-000000000001"
+	tests := []struct {
+		name         string
+		domain       string
+		host         string
+		serverName   string
+		path         string
+		expectedPath string
+	}{
+		{
+			name:         "model host api",
+			domain:       "models.example.com",
+			host:         uuid + ".models.example.com",
+			path:         "/api",
+			expectedPath: "/model/" + uuid + "/api",
+		},
+		{
+			name:         "model host with port",
+			domain:       "models.example.com",
+			host:         uuid + ".models.example.com:443",
+			path:         "/log",
+			expectedPath: "/model/" + uuid + "/log",
+		},
+		{
+			name:         "model host charms",
+			domain:       "models.example.com.",
+			host:         uuid + ".Models.Example.com",
+			path:         "/charms/foo",
+			expectedPath: "/model/" + uuid + "/charms/foo",
+		},
+		{
+			name:         "sni takes precedence over host",
+			domain:       "models.example.com",
+			host:         "jimm.example.com",
+			serverName:   uuid + ".models.example.com",
+			path:         "/api",
+			expectedPath: "/model/" + uuid + "/api",
+		},
+		{
+			name:         "non-model path is unchanged",
+			domain:       "models.example.com",
+			host:         uuid + ".models.example.com",
+			path:         "/metrics",
+			expectedPath: "/metrics",
+		},
+		{
+			name:         "other domain is unchanged",
+			domain:       "models.example.com",
+			host:         uuid + ".example.com",
+			path:         "/api",
+			expectedPath: "/api",
+		},
+		{
+			name:         "invalid uuid is unchanged",
+			domain:       "models.example.com",
+			host:         "not-a-uuid.models.example.com",
+			path:         "/api",
+			expectedPath: "/api",
+		},
+		{
+			name:         "no domain configured",
+			host:         uuid + ".models.example.com",
+			path:         "/api",
+			expectedPath: "/api",
+		},
+	}
+
+	for _, tt := range tests {
+		c := qt.New(t)
+		c.Run(tt.name, func(c *qt.C) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Host = tt.host
+			if tt.serverName != "" {
+				req.TLS = &tls.ConnectionState{ServerName: tt.serverName}
+			}
+			w := httptest.NewRecorder()
+
+			var path string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path = r.URL.Path
+			})
+
+			middleware.NewModelHostRouter(tt.domain).Handler(handler).ServeHTTP(w, req)
+			c.Assert(path, qt.Equals, tt.expectedPath)
+		})
+	}
+}
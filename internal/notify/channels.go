@@ -0,0 +1,174 @@
+// Copyright 2024 Canonical.
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A Webhook is a Channel that POSTs each event, encoded as JSON, to a
+// URL.
+type Webhook struct {
+	// URL is the URL events are posted to.
+	URL string
+
+	// Client is the HTTP client used to post events. If this is nil
+	// http.DefaultClient is used.
+	Client *http.Client
+
+	// Name is the name of the webhook, used to find its signing
+	// secret.
+	Name string
+
+	// Signer signs the events posted to the webhook. If this is nil,
+	// or the webhook has no name, events are not signed.
+	Signer Signer
+}
+
+// Send implements Channel.
+func (w *Webhook) Send(ctx context.Context, e Event) error {
+	const op = errors.Op("notify.Webhook.Send")
+	body, err := json.Marshal(e)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	var h http.Header
+	if w.Signer != nil && w.Name != "" {
+		h, err = w.Signer.SignDelivery(ctx, w.Name, body)
+		if err != nil {
+			return errors.E(op, err)
+		}
+	}
+	if err := post(ctx, w.Client, w.URL, body, h); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+// A Slack is a Channel that posts each event as a message to a Slack
+// compatible incoming webhook.
+type Slack struct {
+	// URL is the URL of the incoming webhook.
+	URL string
+
+	// Client is the HTTP client used to post events. If this is nil
+	// http.DefaultClient is used.
+	Client *http.Client
+}
+
+// Send implements Channel.
+func (s *Slack) Send(ctx context.Context, e Event) error {
+	const op = errors.Op("notify.Slack.Send")
+	msg := struct {
+		Text string `json:"text"`
+	}{
+		Text: e.String(),
+	}
+	if err := postJSON(ctx, s.Client, s.URL, msg); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return post(ctx, client, url, body, nil)
+}
+
+// post posts the given JSON encoded body to the given URL with the given
+// additional headers.
+func post(ctx context.Context, client *http.Client, url string, body []byte, h http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.E(fmt.Sprintf("webhook returned status %s", resp.Status))
+	}
+	return nil
+}
+
+// An SMTP is a Channel that emails each event.
+type SMTP struct {
+	// Address is the host:port of the SMTP server.
+	Address string
+
+	// Username and Password are the credentials used to authenticate
+	// with the SMTP server. If Username is empty no authentication is
+	// performed.
+	Username string
+	Password string
+
+	// From is the address emails are sent from.
+	From string
+
+	// To holds the addresses emails are sent to.
+	To []string
+}
+
+// Send implements Channel.
+func (s *SMTP) Send(ctx context.Context, e Event) error {
+	const op = errors.Op("notify.SMTP.Send")
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Address)
+		if err != nil {
+			return errors.E(op, err)
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(s.To, ", "))
+	fmt.Fprintf(&buf, "Subject: JIMM: %s\r\n", e.String())
+	fmt.Fprintf(&buf, "Date: %s\r\n", e.Time.Format("Mon, 02 Jan 2006 15:04:05 -0700"))
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&buf, "%s\r\n\r\nKind: %s\r\nTime: %s\r\n", e.Message, e.Kind, e.Time.Format("2006-01-02T15:04:05Z07:00"))
+	if e.Controller != "" {
+		fmt.Fprintf(&buf, "Controller: %s\r\n", e.Controller)
+	}
+	if e.Credential != "" {
+		fmt.Fprintf(&buf, "Credential: %s\r\n", e.Credential)
+	}
+
+	// net/smtp does not support contexts, send in the background so
+	// that cancellation is honoured.
+	errc := make(chan error, 1)
+	go func() {
+		errc <- smtp.SendMail(s.Address, auth, s.From, s.To, buf.Bytes())
+	}()
+	select {
+	case err := <-errc:
+		if err != nil {
+			return errors.E(op, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return errors.E(op, ctx.Err())
+	}
+}
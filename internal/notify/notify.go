@@ -0,0 +1,337 @@
+// Copyright 2024 Canonical.
+
+// Package notify sends notifications about operational incidents, such as
+// controllers becoming unavailable, to operators. Notifications are sent
+// to a set of configured channels, each of which may be restricted to
+// particular kinds of event and throttled to avoid flooding the
+// recipients.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// An EventKind is the kind of incident an event reports.
+type EventKind string
+
+const (
+	// ControllerUnavailable is sent when JIMM can no longer connect to
+	// a controller.
+	ControllerUnavailable EventKind = "controller-unavailable"
+
+	// ControllerAvailable is sent when JIMM can connect to a controller
+	// that was previously unavailable.
+	ControllerAvailable EventKind = "controller-available"
+
+	// CredentialUpdateFailed is sent when a cloud credential repeatedly
+	// fails to be updated on a controller.
+	CredentialUpdateFailed EventKind = "credential-update-failed"
+
+	// CredentialRemovalUnconfirmed is sent when a controller has not
+	// been confirmed to have removed its copy of a revoked cloud
+	// credential by the removal deadline.
+	CredentialRemovalUnconfirmed EventKind = "credential-removal-unconfirmed"
+
+	// ControllerCredentialExpiring is sent when the cloud credential
+	// used by a controller's controller model is about to expire.
+	ControllerCredentialExpiring EventKind = "controller-credential-expiring"
+
+	// ControllerCredentialInvalid is sent when the cloud credential used
+	// by a controller's controller model is reported invalid by the
+	// controller.
+	ControllerCredentialInvalid EventKind = "controller-credential-invalid"
+
+	// ControllerCertificateExpiring is sent when a TLS certificate
+	// presented by a controller is about to expire.
+	ControllerCertificateExpiring EventKind = "controller-certificate-expiring"
+
+	// DatabaseIndexMissing is sent when indexes required by JIMM's
+	// frequently run queries are missing from the database.
+	DatabaseIndexMissing EventKind = "database-index-missing"
+
+	// ControllerMonitorConflict is sent when a watcher finds that
+	// another watcher, possibly on another JIMM replica, has taken over
+	// monitoring its controller.
+	ControllerMonitorConflict EventKind = "controller-monitor-conflict"
+
+	// ModelMigrationAbandoned is sent when JIMM stops monitoring a model
+	// migration whose outcome could not be determined in time.
+	ModelMigrationAbandoned EventKind = "model-migration-abandoned"
+
+	// ModelIdle is sent when a model has not been used for longer than
+	// the configured idle period. The event names the model's owner so
+	// that it may be routed to them.
+	ModelIdle EventKind = "model-idle"
+
+	// ModelDestroyPending is sent when a model is scheduled to be
+	// destroyed at the end of its destroy confirmation window. The event
+	// names the model's owner so that it may be routed to them.
+	ModelDestroyPending EventKind = "model-destroy-pending"
+)
+
+// An Event is a notification about an incident.
+type Event struct {
+	// Kind is the kind of incident.
+	Kind EventKind `json:"kind"`
+
+	// Time is the time the incident happened.
+	Time time.Time `json:"time"`
+
+	// Controller is the name of the controller the incident concerns.
+	Controller string `json:"controller,omitempty"`
+
+	// Credential is the tag of the cloud credential the incident
+	// concerns, if any.
+	Credential string `json:"credential,omitempty"`
+
+	// Model is the tag of the model the incident concerns, if any.
+	Model string `json:"model,omitempty"`
+
+	// Owner is the name of the owner of the model the incident
+	// concerns, if any.
+	Owner string `json:"owner,omitempty"`
+
+	// Message is a human readable description of the incident.
+	Message string `json:"message"`
+}
+
+// subject returns the key identifying the subject of the event, used to
+// throttle repeated events about the same thing.
+func (e Event) subject() string {
+	return string(e.Kind) + "/" + e.Controller + "/" + e.Credential + "/" + e.Model
+}
+
+// String returns a one line summary of the event.
+func (e Event) String() string {
+	return fmt.Sprintf("[%s] %s", e.Kind, e.Message)
+}
+
+// A Channel delivers events to their recipients.
+type Channel interface {
+	Send(ctx context.Context, e Event) error
+}
+
+// Channel types supported in a ChannelConfig.
+const (
+	ChannelWebhook = "webhook"
+	ChannelSlack   = "slack"
+	ChannelSMTP    = "smtp"
+)
+
+// A ChannelConfig configures a notification channel.
+type ChannelConfig struct {
+	// Type is the type of the channel, one of "webhook", "slack" or
+	// "smtp".
+	Type string `json:"type"`
+
+	// Name is the name of a webhook channel. Events posted to a named
+	// webhook are signed once a signing secret has been created for
+	// it, see Signer.
+	Name string `json:"name,omitempty"`
+
+	// URL is the URL events are posted to by webhook and slack
+	// channels.
+	URL string `json:"url,omitempty"`
+
+	// SMTPAddress is the host:port of the SMTP server used by smtp
+	// channels.
+	SMTPAddress string `json:"smtp-address,omitempty"`
+
+	// SMTPUsername and SMTPPassword are the credentials used to
+	// authenticate with the SMTP server. If SMTPUsername is empty no
+	// authentication is performed.
+	SMTPUsername string `json:"smtp-username,omitempty"`
+	SMTPPassword string `json:"smtp-password,omitempty"`
+
+	// From is the address emails are sent from.
+	From string `json:"from,omitempty"`
+
+	// To holds the addresses emails are sent to.
+	To []string `json:"to,omitempty"`
+
+	// Events holds the kinds of event sent to the channel. If this is
+	// empty all events are sent.
+	Events []EventKind `json:"events,omitempty"`
+
+	// Throttle is the minimum interval between events of the same kind
+	// about the same subject sent to the channel, for example
+	// "15m". If this is empty all events are sent.
+	Throttle string `json:"throttle,omitempty"`
+}
+
+// sendTimeout is the maximum time allowed to send an event to a channel.
+const sendTimeout = 30 * time.Second
+
+// A Notifier sends events to a set of channels. A nil Notifier discards
+// all events.
+type Notifier struct {
+	channels []*channel
+
+	// webhooks holds the names of the named webhook channels.
+	webhooks []string
+
+	// now is the function used to get the current time.
+	now func() time.Time
+
+	wg sync.WaitGroup
+}
+
+type channel struct {
+	Channel
+	events   map[EventKind]bool
+	throttle time.Duration
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// New creates a Notifier sending events to channels created from the
+// given configurations. Events posted to webhooks are not signed.
+func New(configs []ChannelConfig) (*Notifier, error) {
+	return NewWithSigner(configs, nil)
+}
+
+// NewWithSigner creates a Notifier sending events to channels created
+// from the given configurations. Events posted to named webhooks are
+// signed with the given signer.
+func NewWithSigner(configs []ChannelConfig, signer Signer) (*Notifier, error) {
+	const op = errors.Op("notify.New")
+
+	n := &Notifier{now: time.Now}
+	names := make(map[string]bool)
+	for i, cfg := range configs {
+		var c Channel
+		switch cfg.Type {
+		case ChannelWebhook:
+			if cfg.URL == "" {
+				return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("channel %d: url not specified", i))
+			}
+			if cfg.Name != "" {
+				if names[cfg.Name] {
+					return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("channel %d: duplicate webhook name %q", i, cfg.Name))
+				}
+				names[cfg.Name] = true
+				n.webhooks = append(n.webhooks, cfg.Name)
+			}
+			c = &Webhook{URL: cfg.URL, Name: cfg.Name, Signer: signer}
+		case ChannelSlack:
+			if cfg.URL == "" {
+				return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("channel %d: url not specified", i))
+			}
+			c = &Slack{URL: cfg.URL}
+		case ChannelSMTP:
+			if cfg.SMTPAddress == "" || cfg.From == "" || len(cfg.To) == 0 {
+				return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("channel %d: smtp-address, from and to must be specified", i))
+			}
+			c = &SMTP{
+				Address:  cfg.SMTPAddress,
+				Username: cfg.SMTPUsername,
+				Password: cfg.SMTPPassword,
+				From:     cfg.From,
+				To:       cfg.To,
+			}
+		default:
+			return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("channel %d: unknown channel type %q", i, cfg.Type))
+		}
+		var throttle time.Duration
+		if cfg.Throttle != "" {
+			var err error
+			throttle, err = time.ParseDuration(cfg.Throttle)
+			if err != nil {
+				return nil, errors.E(op, errors.CodeBadRequest, fmt.Sprintf("channel %d: invalid throttle %q", i, cfg.Throttle))
+			}
+		}
+		n.AddChannel(c, throttle, cfg.Events...)
+	}
+	return n, nil
+}
+
+// AddChannel adds a channel to the notifier. Events of the same kind about
+// the same subject are sent at most once per throttle interval. If any
+// event kinds are specified only events of those kinds are sent to the
+// channel.
+func (n *Notifier) AddChannel(c Channel, throttle time.Duration, kinds ...EventKind) {
+	ch := &channel{
+		Channel:  c,
+		throttle: throttle,
+		sent:     make(map[string]time.Time),
+	}
+	if len(kinds) > 0 {
+		ch.events = make(map[EventKind]bool, len(kinds))
+		for _, k := range kinds {
+			ch.events[k] = true
+		}
+	}
+	n.channels = append(n.channels, ch)
+}
+
+// Notify sends the given event to all channels accepting it. Events are
+// sent in the background, failures are logged. If the event's time is not
+// set the current time is used.
+func (n *Notifier) Notify(ctx context.Context, e Event) {
+	if n == nil {
+		return
+	}
+	now := n.now()
+	if e.Time.IsZero() {
+		e.Time = now
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, ch := range n.channels {
+		if !ch.accept(e, now) {
+			continue
+		}
+		n.wg.Add(1)
+		go func(ch *channel) {
+			defer n.wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+			defer cancel()
+			if err := ch.Send(ctx, e); err != nil {
+				zapctx.Error(ctx, "cannot send notification", zap.String("kind", string(e.Kind)), zap.Error(err))
+			}
+		}(ch)
+	}
+}
+
+// Webhooks returns the names of the notifier's named webhook channels.
+func (n *Notifier) Webhooks() []string {
+	if n == nil {
+		return nil
+	}
+	return n.webhooks
+}
+
+// Wait waits for all events being sent to complete.
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// accept reports whether the given event should be sent to the channel
+// at the given time, recording it as sent if so.
+func (c *channel) accept(e Event, now time.Time) bool {
+	if c.events != nil && !c.events[e.Kind] {
+		return false
+	}
+	if c.throttle <= 0 {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := e.subject()
+	if t, ok := c.sent[key]; ok && now.Before(t.Add(c.throttle)) {
+		return false
+	}
+	c.sent[key] = now
+	return true
+}
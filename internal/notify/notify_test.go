@@ -0,0 +1,156 @@
+// Copyright 2024 Canonical.
+
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+)
+
+type recordingChannel struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (c *recordingChannel) Send(_ context.Context, e notify.Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, e)
+	return nil
+}
+
+func TestNilNotifier(t *testing.T) {
+	var n *notify.Notifier
+	n.Notify(context.Background(), notify.Event{Kind: notify.ControllerUnavailable})
+	n.Wait()
+}
+
+func TestNotifyFiltersAndThrottles(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	n, err := notify.New(nil)
+	c.Assert(err, qt.IsNil)
+	all := new(recordingChannel)
+	n.AddChannel(all, 0)
+	controllers := new(recordingChannel)
+	n.AddChannel(controllers, time.Hour, notify.ControllerUnavailable, notify.ControllerAvailable)
+
+	n.Notify(ctx, notify.Event{Kind: notify.ControllerUnavailable, Controller: "controller-1", Message: "1"})
+	n.Notify(ctx, notify.Event{Kind: notify.ControllerUnavailable, Controller: "controller-1", Message: "2"})
+	n.Notify(ctx, notify.Event{Kind: notify.ControllerUnavailable, Controller: "controller-2", Message: "3"})
+	n.Notify(ctx, notify.Event{Kind: notify.CredentialUpdateFailed, Controller: "controller-1", Message: "4"})
+	n.Wait()
+
+	c.Check(messages(all), qt.ContentEquals, []string{"1", "2", "3", "4"})
+	c.Check(messages(controllers), qt.ContentEquals, []string{"1", "3"})
+	for _, e := range all.events {
+		c.Check(e.Time.IsZero(), qt.IsFalse)
+	}
+}
+
+func messages(c *recordingChannel) []string {
+	var msgs []string
+	for _, e := range c.events {
+		msgs = append(msgs, e.Message)
+	}
+	return msgs
+}
+
+func TestWebhookAndSlack(t *testing.T) {
+	c := qt.New(t)
+
+	var mu sync.Mutex
+	bodies := make(map[string]map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		bodies[req.URL.Path] = body
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n, err := notify.New([]notify.ChannelConfig{{
+		Type: notify.ChannelWebhook,
+		URL:  srv.URL + "/webhook",
+	}, {
+		Type:   notify.ChannelSlack,
+		URL:    srv.URL + "/slack",
+		Events: []notify.EventKind{notify.ControllerAvailable},
+	}})
+	c.Assert(err, qt.IsNil)
+
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	n.Notify(context.Background(), notify.Event{
+		Kind:       notify.ControllerAvailable,
+		Time:       tm,
+		Controller: "controller-1",
+		Message:    "controller controller-1 is available",
+	})
+	n.Wait()
+
+	c.Check(bodies, qt.DeepEquals, map[string]map[string]interface{}{
+		"/webhook": {
+			"kind":       "controller-available",
+			"time":       "2024-01-02T03:04:05Z",
+			"controller": "controller-1",
+			"message":    "controller controller-1 is available",
+		},
+		"/slack": {
+			"text": "[controller-available] controller controller-1 is available",
+		},
+	})
+}
+
+func TestWebhookError(t *testing.T) {
+	c := qt.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	w := &notify.Webhook{URL: srv.URL}
+	err := w.Send(context.Background(), notify.Event{Kind: notify.ControllerUnavailable})
+	c.Check(err, qt.ErrorMatches, `webhook returned status 500 Internal Server Error`)
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		config      notify.ChannelConfig
+		expectError string
+	}{{
+		config:      notify.ChannelConfig{Type: "pager"},
+		expectError: `channel 0: unknown channel type "pager"`,
+	}, {
+		config:      notify.ChannelConfig{Type: notify.ChannelWebhook},
+		expectError: `channel 0: url not specified`,
+	}, {
+		config:      notify.ChannelConfig{Type: notify.ChannelSMTP, SMTPAddress: "localhost:25"},
+		expectError: `channel 0: smtp-address, from and to must be specified`,
+	}, {
+		config:      notify.ChannelConfig{Type: notify.ChannelSlack, URL: "http://example.com", Throttle: "often"},
+		expectError: `channel 0: invalid throttle "often"`,
+	}}
+	for _, test := range tests {
+		_, err := notify.New([]notify.ChannelConfig{test.config})
+		c.Check(err, qt.ErrorMatches, test.expectError)
+		c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeBadRequest)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2024 Canonical.
+
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// Headers carrying the signature of a webhook delivery.
+const (
+	// SignatureHeader holds the signatures of the delivery, as a comma
+	// separated list of v1=<signature> values. While a webhook's
+	// signing secret is being rotated the delivery is signed with both
+	// the new and the previous secret.
+	SignatureHeader = "X-JIMM-Signature"
+
+	// TimestampHeader holds the time the delivery was signed, in
+	// seconds since the Unix epoch.
+	TimestampHeader = "X-JIMM-Timestamp"
+
+	// NonceHeader holds a value unique to the delivery, allowing
+	// receivers to reject replayed deliveries.
+	NonceHeader = "X-JIMM-Nonce"
+)
+
+// DefaultSignatureTolerance is the maximum difference between the time a
+// delivery was signed and the time it is verified that is accepted by a
+// Verifier with no tolerance configured.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// A Signer signs webhook deliveries.
+type Signer interface {
+	// SignDelivery returns the headers signing a delivery of the given
+	// body to the named webhook. If the webhook has no signing secret
+	// no headers are returned.
+	SignDelivery(ctx context.Context, webhook string, body []byte) (http.Header, error)
+}
+
+// Signature returns the signature of a delivery of the given body signed
+// at the given time with the given nonce. The signature is the hex
+// encoded HMAC-SHA256, keyed with the secret, of the timestamp, nonce and
+// body separated by dots.
+func Signature(secret []byte, timestamp int64, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d.%s.", timestamp, nonce)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeaders returns the headers signing a delivery of the given
+// body with each of the given secrets.
+func SignatureHeaders(secrets [][]byte, timestamp int64, nonce string, body []byte) http.Header {
+	sigs := make([]string, len(secrets))
+	for i, secret := range secrets {
+		sigs[i] = "v1=" + Signature(secret, timestamp, nonce, body)
+	}
+	h := make(http.Header)
+	h.Set(SignatureHeader, strings.Join(sigs, ","))
+	h.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	h.Set(NonceHeader, nonce)
+	return h
+}
+
+// A Verifier verifies the signatures of webhook deliveries. Deliveries
+// signed too long ago, or whose nonce has already been seen, are
+// rejected so that captured deliveries cannot be replayed.
+// Verifier is provided for the receivers of JIMM's outbound deliveries.
+type Verifier struct {
+	// Secrets holds the secrets a delivery may be signed with. During
+	// a rotation this should hold both the new and the previous
+	// secret.
+	Secrets [][]byte
+
+	// Tolerance is the maximum difference between the time a delivery
+	// was signed and the time it is verified. If this is zero
+	// DefaultSignatureTolerance is used.
+	Tolerance time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// Verify verifies the signature of a delivery with the given headers and
+// body. If the delivery is not signed with one of the verifier's secrets,
+// was signed outside the tolerance or has been seen before an error with
+// a code of CodeUnauthorized is returned.
+func (v *Verifier) Verify(h http.Header, body []byte) error {
+	const op = errors.Op("notify.Verifier.Verify")
+
+	sigs, ts, nonce := h.Get(SignatureHeader), h.Get(TimestampHeader), h.Get(NonceHeader)
+	if sigs == "" || ts == "" || nonce == "" {
+		return errors.E(op, errors.CodeUnauthorized, "delivery not signed")
+	}
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.E(op, errors.CodeUnauthorized, "invalid delivery timestamp")
+	}
+	tolerance := v.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	now := time.Now()
+	signed := time.Unix(timestamp, 0)
+	if signed.Before(now.Add(-tolerance)) || signed.After(now.Add(tolerance)) {
+		return errors.E(op, errors.CodeUnauthorized, "delivery timestamp outside tolerance")
+	}
+	if !v.validSignature(sigs, timestamp, nonce, body) {
+		return errors.E(op, errors.CodeUnauthorized, "invalid delivery signature")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.seen == nil {
+		v.seen = make(map[string]time.Time)
+	}
+	// Nonces only need to be remembered while their deliveries are
+	// within the tolerance, older deliveries are rejected anyway.
+	for n, t := range v.seen {
+		if t.Before(now.Add(-tolerance)) {
+			delete(v.seen, n)
+		}
+	}
+	if _, ok := v.seen[nonce]; ok {
+		return errors.E(op, errors.CodeUnauthorized, "delivery nonce already used")
+	}
+	v.seen[nonce] = signed
+	return nil
+}
+
+// validSignature reports whether any of the given signatures is valid
+// for one of the verifier's secrets.
+func (v *Verifier) validSignature(sigs string, timestamp int64, nonce string, body []byte) bool {
+	for _, sig := range strings.Split(sigs, ",") {
+		sig, ok := strings.CutPrefix(strings.TrimSpace(sig), "v1=")
+		if !ok {
+			continue
+		}
+		for _, secret := range v.Secrets {
+			if hmac.Equal([]byte(sig), []byte(Signature(secret, timestamp, nonce, body))) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Canonical.
+
+package notify_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/notify"
+)
+
+type testSigner struct {
+	secrets [][]byte
+	nonce   int
+}
+
+func (s *testSigner) SignDelivery(_ context.Context, webhook string, body []byte) (http.Header, error) {
+	if webhook != "ops" {
+		return nil, nil
+	}
+	s.nonce++
+	return notify.SignatureHeaders(s.secrets, time.Now().Unix(), strconv.Itoa(s.nonce), body), nil
+}
+
+func TestVerifier(t *testing.T) {
+	c := qt.New(t)
+
+	body := []byte(`{"kind":"controller-unavailable"}`)
+	now := time.Now().Unix()
+	v := notify.Verifier{Secrets: [][]byte{[]byte("new"), []byte("old")}}
+
+	err := v.Verify(http.Header{}, body)
+	c.Check(err, qt.ErrorMatches, `delivery not signed`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	h := notify.SignatureHeaders([][]byte{[]byte("old")}, now, "nonce-1", body)
+	err = v.Verify(h, body)
+	c.Assert(err, qt.IsNil)
+
+	// A replayed delivery is rejected.
+	err = v.Verify(h, body)
+	c.Check(err, qt.ErrorMatches, `delivery nonce already used`)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeUnauthorized)
+
+	h = notify.SignatureHeaders([][]byte{[]byte("new")}, now, "nonce-2", body)
+	err = v.Verify(h, []byte(`{}`))
+	c.Check(err, qt.ErrorMatches, `invalid delivery signature`)
+
+	h = notify.SignatureHeaders([][]byte{[]byte("other")}, now, "nonce-2", body)
+	err = v.Verify(h, body)
+	c.Check(err, qt.ErrorMatches, `invalid delivery signature`)
+
+	h = notify.SignatureHeaders([][]byte{[]byte("new")}, now-int64(time.Hour/time.Second), "nonce-3", body)
+	err = v.Verify(h, body)
+	c.Check(err, qt.ErrorMatches, `delivery timestamp outside tolerance`)
+
+	h.Set(notify.TimestampHeader, "yesterday")
+	err = v.Verify(h, body)
+	c.Check(err, qt.ErrorMatches, `invalid delivery timestamp`)
+}
+
+func TestSignedWebhook(t *testing.T) {
+	c := qt.New(t)
+
+	v := notify.Verifier{Secrets: [][]byte{[]byte("secret")}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		c.Check(err, qt.IsNil)
+		if err := v.Verify(req.Header, body); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+
+	signer := &testSigner{secrets: [][]byte{[]byte("secret")}}
+	n, err := notify.NewWithSigner([]notify.ChannelConfig{{
+		Type: notify.ChannelWebhook,
+		Name: "ops",
+		URL:  srv.URL,
+	}}, signer)
+	c.Assert(err, qt.IsNil)
+	c.Check(n.Webhooks(), qt.DeepEquals, []string{"ops"})
+
+	w := &notify.Webhook{URL: srv.URL, Name: "ops", Signer: signer}
+	err = w.Send(context.Background(), notify.Event{Kind: notify.ControllerUnavailable})
+	c.Assert(err, qt.IsNil)
+
+	// Webhooks without a secret are not signed.
+	w = &notify.Webhook{URL: srv.URL, Name: "dev", Signer: signer}
+	err = w.Send(context.Background(), notify.Event{Kind: notify.ControllerUnavailable})
+	c.Check(err, qt.ErrorMatches, `webhook returned status 401 Unauthorized`)
+
+	_, err = notify.NewWithSigner([]notify.ChannelConfig{
+		{Type: notify.ChannelWebhook, Name: "ops", URL: srv.URL},
+		{Type: notify.ChannelWebhook, Name: "ops", URL: srv.URL},
+	}, signer)
+	c.Check(err, qt.ErrorMatches, `channel 1: duplicate webhook name "ops"`)
+}
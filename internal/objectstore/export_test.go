@@ -0,0 +1,6 @@
+// Copyright 2024 Canonical.
+package objectstore
+
+var (
+	SignRequest = signRequest
+)
@@ -0,0 +1,98 @@
+// Copyright 2024 Canonical.
+
+// Package objectstore provides storage for the artifacts JIMM produces,
+// such as audit exports and oversized results, in an S3-compatible
+// object store such as Amazon S3, MinIO or OpenStack Swift with the S3
+// API enabled.
+package objectstore
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// A Kind is a kind of artifact kept in the object store. The artifacts
+// of each kind are stored under their own prefix so that each kind may
+// have its own lifecycle policy.
+type Kind string
+
+const (
+	// KindResult is the kind of the results of expensive calls, such as
+	// model dumps, that were too large to return inline.
+	KindResult Kind = "results"
+
+	// KindAuditExport is the kind of the audit log entries exported
+	// before they are pruned.
+	KindAuditExport Kind = "audit-exports"
+
+	// KindModelArchive is the kind of the archived data of models.
+	KindModelArchive Kind = "model-archives"
+
+	// KindBackup is the kind of backup artifacts.
+	KindBackup Kind = "backups"
+)
+
+// Kinds returns all the kinds of artifact kept in the object store.
+func Kinds() []Kind {
+	return []Kind{KindResult, KindAuditExport, KindModelArchive, KindBackup}
+}
+
+// Key returns the key of the named artifact of the given kind.
+func Key(kind Kind, name string) string {
+	return string(kind) + "/" + name
+}
+
+// ObjectInfo holds the metadata of a stored object.
+type ObjectInfo struct {
+	// ContentType is the content type of the object.
+	ContentType string
+
+	// Size is the size of the object in bytes.
+	Size int64
+
+	// LastModified is the time the object was stored.
+	LastModified time.Time
+}
+
+// A Store stores objects identified by their keys.
+type Store interface {
+	// Put stores the object with the given key, replacing any object
+	// already stored with the key. The body must contain size bytes.
+	Put(ctx context.Context, key, contentType string, body io.Reader, size int64) error
+
+	// Get returns the body and metadata of the object with the given
+	// key, the body must be closed by the caller. If there is no such
+	// object an error with a code of CodeNotFound is returned.
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+
+	// Delete removes the object with the given key. Removing an object
+	// that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// A LifecyclePolicy holds the time after which the artifacts of each
+// kind expire and are removed by the object store. The artifacts of
+// kinds without a positive expiry are kept until JIMM removes them.
+type LifecyclePolicy map[Kind]time.Duration
+
+// Validate checks that the policy only names known kinds.
+func (p LifecyclePolicy) Validate() error {
+	const op = errors.Op("objectstore.LifecyclePolicy.Validate")
+
+	known := make(map[Kind]bool)
+	for _, k := range Kinds() {
+		known[k] = true
+	}
+	for k, expiry := range p {
+		if !known[k] {
+			return errors.E(op, errors.CodeBadRequest, "unknown artifact kind "+string(k))
+		}
+		if expiry < 0 {
+			return errors.E(op, errors.CodeBadRequest, "expiry of "+string(k)+" cannot be negative")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2024 Canonical.
+
+package objectstore_test
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/objectstore"
+)
+
+func TestKey(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(objectstore.Key(objectstore.KindResult, "abc"), qt.Equals, "results/abc")
+	c.Check(objectstore.Key(objectstore.KindAuditExport, "2024/01.jsonl"), qt.Equals, "audit-exports/2024/01.jsonl")
+	c.Check(objectstore.Key(objectstore.KindBackup, ""), qt.Equals, "backups/")
+}
+
+func TestLifecyclePolicyValidate(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(objectstore.LifecyclePolicy{
+		objectstore.KindResult:       24 * time.Hour,
+		objectstore.KindModelArchive: 0,
+	}.Validate(), qt.IsNil)
+
+	err := objectstore.LifecyclePolicy{
+		objectstore.Kind("bananas"): time.Hour,
+	}.Validate()
+	c.Check(err, qt.ErrorMatches, `unknown artifact kind bananas`)
+
+	err = objectstore.LifecyclePolicy{
+		objectstore.KindBackup: -time.Hour,
+	}.Validate()
+	c.Check(err, qt.ErrorMatches, `expiry of backups cannot be negative`)
+}
@@ -0,0 +1,448 @@
+// Copyright 2024 Canonical.
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+const (
+	// defaultRegion is the region used to sign requests if none is
+	// configured. S3-compatible stores that have no regions, such as
+	// MinIO, accept it.
+	defaultRegion = "us-east-1"
+
+	// credentialsTTL is the time for which the credentials used to sign
+	// requests are cached.
+	credentialsTTL = 5 * time.Minute
+
+	// unsignedPayload is the payload hash used for requests whose body
+	// is streamed, the body is protected by TLS instead.
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// emptyPayloadHash is the SHA-256 hash of an empty body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// Credentials hold the credentials used to access an S3-compatible
+// object store.
+type Credentials struct {
+	// AccessKeyID is the access key ID.
+	AccessKeyID string
+
+	// SecretAccessKey is the secret access key.
+	SecretAccessKey string
+
+	// SessionToken is the session token of temporary credentials, if
+	// any.
+	SessionToken string
+}
+
+// S3Params holds the parameters of an S3Store.
+type S3Params struct {
+	// Endpoint is the URL of the object store's S3 API, for example
+	// https://s3.eu-west-2.amazonaws.com.
+	Endpoint string
+
+	// Region is the region the requests are signed for. If this is
+	// empty us-east-1 is used.
+	Region string
+
+	// Bucket is the name of the bucket holding the objects.
+	Bucket string
+
+	// PathStyle addresses the bucket in the path of the request URLs
+	// rather than the host name. This is required by most MinIO and
+	// Swift deployments.
+	PathStyle bool
+
+	// Credentials returns the credentials used to sign requests. The
+	// credentials are cached for a few minutes so that rotated
+	// credentials are picked up.
+	Credentials func(context.Context) (Credentials, error)
+
+	// HTTPClient is the client used to make requests. If this is nil
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// An S3Store is a Store kept in a bucket of an S3-compatible object
+// store. Requests are signed with AWS signature version 4.
+type S3Store struct {
+	params   S3Params
+	endpoint *url.URL
+
+	// now returns the current time, it is replaced in tests.
+	now func() time.Time
+
+	// mu protects the fields below it.
+	mu             sync.Mutex
+	creds          Credentials
+	credsExpiresAt time.Time
+}
+
+// NewS3Store returns a Store kept in the bucket of the S3-compatible
+// object store described by the given parameters.
+func NewS3Store(p S3Params) (*S3Store, error) {
+	const op = errors.Op("objectstore.NewS3Store")
+
+	if p.Bucket == "" {
+		return nil, errors.E(op, errors.CodeServerConfiguration, "object store bucket not configured")
+	}
+	if p.Credentials == nil {
+		return nil, errors.E(op, errors.CodeServerConfiguration, "object store credentials not configured")
+	}
+	u, err := url.Parse(p.Endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, errors.E(op, errors.CodeServerConfiguration, fmt.Sprintf("invalid object store endpoint %q", p.Endpoint))
+	}
+	if p.Region == "" {
+		p.Region = defaultRegion
+	}
+	if p.HTTPClient == nil {
+		p.HTTPClient = http.DefaultClient
+	}
+	return &S3Store{
+		params:   p,
+		endpoint: u,
+		now:      time.Now,
+	}, nil
+}
+
+// Put implements Store.
+func (s *S3Store) Put(ctx context.Context, key, contentType string, body io.Reader, size int64) (err error) {
+	const op = errors.Op("objectstore.Put")
+
+	durationObserver := servermon.DurationObserver(servermon.ObjectStoreCallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.ObjectStoreCallErrorCount, &err, string(op))
+
+	header := make(http.Header)
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, "", header, body, size, unsignedPayload)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get implements Store.
+func (s *S3Store) Get(ctx context.Context, key string) (_ io.ReadCloser, _ ObjectInfo, err error) {
+	const op = errors.Op("objectstore.Get")
+
+	durationObserver := servermon.DurationObserver(servermon.ObjectStoreCallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.ObjectStoreCallErrorCount, &err, string(op))
+
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil, nil, 0, emptyPayloadHash)
+	if err != nil {
+		return nil, ObjectInfo{}, errors.E(op, err)
+	}
+	info := ObjectInfo{
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}
+	if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = t
+	}
+	return resp.Body, info, nil
+}
+
+// Delete implements Store.
+func (s *S3Store) Delete(ctx context.Context, key string) (err error) {
+	const op = errors.Op("objectstore.Delete")
+
+	durationObserver := servermon.DurationObserver(servermon.ObjectStoreCallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.ObjectStoreCallErrorCount, &err, string(op))
+
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil, nil, 0, emptyPayloadHash)
+	if errors.ErrorCode(err) == errors.CodeNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.E(op, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// lifecycleConfiguration is the lifecycle configuration of a bucket.
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LifecycleConfiguration"`
+	Rules   []lifecycleRule `xml:"Rule"`
+}
+
+// lifecycleRule is a rule in the lifecycle configuration of a bucket.
+type lifecycleRule struct {
+	ID         string `xml:"ID"`
+	Prefix     string `xml:"Filter>Prefix"`
+	Status     string `xml:"Status"`
+	ExpireDays int    `xml:"Expiration>Days"`
+}
+
+// ApplyLifecycle configures the bucket to expire the artifacts of each
+// kind in the given policy. Object stores expire objects in whole days,
+// so expiry times are rounded up to the next day. ApplyLifecycle replaces
+// any lifecycle configuration already set on the bucket, if the policy
+// has no positive expiry times the bucket is not changed.
+func (s *S3Store) ApplyLifecycle(ctx context.Context, policy LifecyclePolicy) (err error) {
+	const op = errors.Op("objectstore.ApplyLifecycle")
+
+	durationObserver := servermon.DurationObserver(servermon.ObjectStoreCallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.ObjectStoreCallErrorCount, &err, string(op))
+
+	if err := policy.Validate(); err != nil {
+		return errors.E(op, err)
+	}
+	var config lifecycleConfiguration
+	for _, kind := range Kinds() {
+		expiry := policy[kind]
+		if expiry <= 0 {
+			continue
+		}
+		days := int((expiry + 24*time.Hour - 1) / (24 * time.Hour))
+		config.Rules = append(config.Rules, lifecycleRule{
+			ID:         "jimm-" + string(kind),
+			Prefix:     Key(kind, ""),
+			Status:     "Enabled",
+			ExpireDays: days,
+		})
+	}
+	if len(config.Rules) == 0 {
+		return nil
+	}
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return errors.E(op, err)
+	}
+	sum := md5.Sum(body)
+	hash := sha256.Sum256(body)
+	header := make(http.Header)
+	header.Set("Content-Type", "application/xml")
+	header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	resp, err := s.do(ctx, http.MethodPut, "", "lifecycle=", header, bytes.NewReader(body), int64(len(body)), hex.EncodeToString(hash[:]))
+	if err != nil {
+		return errors.E(op, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// do makes a signed request for the object with the given key, or the
+// bucket if the key is empty. If the response has an error status the
+// response is closed and an error returned.
+func (s *S3Store) do(ctx context.Context, method, key, query string, header http.Header, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = s.url(key, query)
+	req.Host = ""
+	if body != nil {
+		req.ContentLength = size
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	signRequest(req, creds, s.params.Region, payloadHash, s.now())
+
+	resp, err := s.params.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.E(errors.CodeConnectionFailed, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// url returns the URL of the object with the given key, or of the bucket
+// if the key is empty.
+func (s *S3Store) url(key, query string) *url.URL {
+	u := *s.endpoint
+	p := strings.TrimSuffix(u.Path, "/")
+	if s.params.PathStyle {
+		p += "/" + s.params.Bucket
+	} else {
+		u.Host = s.params.Bucket + "." + u.Host
+	}
+	if key != "" || !s.params.PathStyle {
+		p += "/" + key
+	}
+	u.Path = p
+	u.RawPath = uriEncode(p, false)
+	u.RawQuery = query
+	return &u
+}
+
+// credentials returns the credentials used to sign requests.
+func (s *S3Store) credentials(ctx context.Context) (Credentials, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.now().Before(s.credsExpiresAt) {
+		return s.creds, nil
+	}
+	creds, err := s.params.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return Credentials{}, errors.E(errors.CodeServerConfiguration, "object store credentials not configured")
+	}
+	s.creds = creds
+	s.credsExpiresAt = s.now().Add(credentialsTTL)
+	return creds, nil
+}
+
+// s3Error is the body of an error response.
+type s3Error struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// responseError returns the error reported by the given response.
+func responseError(resp *http.Response) error {
+	var e s3Error
+	buf, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := xml.Unmarshal(buf, &e); err != nil || e.Code == "" {
+		e.Code = resp.Status
+	}
+	msg := "object store: " + e.Code
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return errors.E(errors.CodeNotFound, msg)
+	case http.StatusForbidden:
+		return errors.E(errors.CodeForbidden, msg)
+	}
+	return errors.E(msg)
+}
+
+// signRequest signs the given request with AWS signature version 4
+// using the given credentials, region and payload hash at time t.
+func signRequest(req *http.Request, creds Credentials, region, payloadHash string, t time.Time) {
+	t = t.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(lk, "x-amz-") || lk == "content-type" || lk == "content-md5" || lk == "range" {
+			headers[lk] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery returns the canonical form of the given query, with
+// the parameters sorted by name and value.
+func canonicalQuery(q url.Values) string {
+	params := make([]string, 0, len(q))
+	for k, vs := range q {
+		for _, v := range vs {
+			params = append(params, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// uriEncode percent-encodes every byte of s other than the unreserved
+// characters, and slashes unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			b.WriteString("%" + strings.ToUpper(strconv.FormatUint(uint64(c)|0x100, 16)[1:]))
+		}
+	}
+	return b.String()
+}
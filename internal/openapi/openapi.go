@@ -0,0 +1,267 @@
+// Copyright 2024 Canonical.
+
+// Package openapi generates an OpenAPI 3 document describing the JIMM
+// REST API. The schemas of request and response bodies are derived from
+// the Go types used to encode them, a field's description is taken from
+// its "description" struct tag.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// Version is the version of the OpenAPI specification the generated
+// documents conform to.
+const Version = "3.0.3"
+
+// An Endpoint describes a single REST API endpoint.
+type Endpoint struct {
+	// Method is the HTTP method of the endpoint.
+	Method string
+
+	// Path is the path of the endpoint.
+	Path string
+
+	// Summary is a short summary of what the endpoint does.
+	Summary string
+
+	// Description is a longer description of the endpoint.
+	Description string
+
+	// Status is the status code of a successful response. If this is
+	// zero http.StatusOK is used.
+	Status int
+
+	// Response is a value of the type encoded as JSON in a successful
+	// response. If this is nil the response has no JSON body.
+	Response interface{}
+}
+
+// A Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components,omitempty"`
+	schemas    map[reflect.Type]string
+}
+
+// Info holds the metadata of an API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// A PathItem holds the operations available on a path, keyed by the
+// lower case HTTP method.
+type PathItem map[string]*Operation
+
+// An Operation describes a single API operation on a path.
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// A Response describes a response from an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// A MediaType holds the schema of a response body.
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Components holds the schemas referenced from the rest of the document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// A Schema describes a JSON value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// NewDocument creates a new OpenAPI document describing the given
+// endpoints.
+func NewDocument(info Info, endpoints ...Endpoint) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		schemas: make(map[reflect.Type]string),
+	}
+	for _, ep := range endpoints {
+		doc.AddEndpoint(ep)
+	}
+	return doc
+}
+
+// AddEndpoint adds the given endpoint to the document.
+func (d *Document) AddEndpoint(ep Endpoint) {
+	status := ep.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	resp := Response{
+		Description: http.StatusText(status),
+	}
+	if ep.Response != nil {
+		resp.Content = map[string]MediaType{
+			"application/json": {Schema: d.schema(reflect.TypeOf(ep.Response))},
+		}
+	}
+	item := d.Paths[ep.Path]
+	if item == nil {
+		item = make(PathItem)
+		d.Paths[ep.Path] = item
+	}
+	item[strings.ToLower(ep.Method)] = &Operation{
+		Summary:     ep.Summary,
+		Description: ep.Description,
+		Responses: map[string]Response{
+			strconv.Itoa(status): resp,
+		},
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema returns the schema of the JSON encoding of values of the given
+// type. Named struct types are added to the document's components and
+// referenced.
+func (d *Document) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return &Schema{Ref: "#/components/schemas/" + d.component(t)}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case reflect.Struct:
+		return d.structSchema(t)
+	default:
+		// Interfaces may hold any value.
+		return &Schema{}
+	}
+}
+
+// component adds the schema of the given named struct type to the
+// document's components, if it is not already present, and returns the
+// name of the component.
+func (d *Document) component(t reflect.Type) string {
+	if name, ok := d.schemas[t]; ok {
+		return name
+	}
+	name := t.Name()
+	for _, other := range d.schemas {
+		if other == name {
+			name = t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:] + "." + t.Name()
+			break
+		}
+	}
+	if d.Components == nil {
+		d.Components = &Components{Schemas: make(map[string]*Schema)}
+	}
+	// Register the name before generating the schema so that recursive
+	// types refer to themselves.
+	d.schemas[t] = name
+	d.Components.Schemas[name] = d.structSchema(t)
+	return name
+}
+
+// structSchema returns the schema of the given struct type, following
+// the rules used by encoding/json.
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: make(map[string]*Schema),
+	}
+	d.addFields(s, t)
+	return s
+}
+
+func (d *Document) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				d.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs := d.schema(f.Type)
+		if desc := f.Tag.Get("description"); desc != "" {
+			if fs.Ref != "" {
+				// Siblings of $ref are ignored, so wrap the
+				// reference to attach the description.
+				fs = &Schema{Description: desc, AllOf: []*Schema{fs}}
+			} else {
+				fs.Description = desc
+			}
+		}
+		s.Properties[name] = fs
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler by serving the document as JSON.
+func (d *Document) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, d)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Canonical.
+
+package openapi_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/openapi"
+)
+
+type testItem struct {
+	Name    string            `json:"name" description:"The name of the item."`
+	Tags    []string          `json:"tags,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Created time.Time         `json:"created"`
+	Parent  *testItem         `json:"parent,omitempty" description:"The parent of the item."`
+	Secret  string            `json:"-"`
+}
+
+type testEmbedded struct {
+	Count int64 `json:"count"`
+}
+
+type testResponse struct {
+	testEmbedded
+	Items []testItem `json:"items"`
+	Data  []byte     `json:"data,omitempty"`
+	Any   interface{}
+}
+
+func TestDocument(t *testing.T) {
+	c := qt.New(t)
+
+	doc := openapi.NewDocument(openapi.Info{
+		Title:   "Test",
+		Version: "1.0.0",
+	}, openapi.Endpoint{
+		Method:   http.MethodGet,
+		Path:     "/items",
+		Summary:  "List items.",
+		Response: testResponse{},
+	}, openapi.Endpoint{
+		Method:  http.MethodDelete,
+		Path:    "/items",
+		Summary: "Remove items.",
+		Status:  http.StatusNoContent,
+	})
+
+	buf, err := json.Marshal(doc)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(buf), qt.JSONEquals, map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Test",
+			"version": "1.0.0",
+		},
+		"paths": map[string]interface{}{
+			"/items": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary": "List items.",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "OK",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{
+										"$ref": "#/components/schemas/testResponse",
+									},
+								},
+							},
+						},
+					},
+				},
+				"delete": map[string]interface{}{
+					"summary": "Remove items.",
+					"responses": map[string]interface{}{
+						"204": map[string]interface{}{
+							"description": "No Content",
+						},
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"testResponse": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"count": map[string]interface{}{"type": "integer", "format": "int64"},
+						"items": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]interface{}{"$ref": "#/components/schemas/testItem"},
+						},
+						"data": map[string]interface{}{"type": "string", "format": "byte"},
+						"Any":  map[string]interface{}{},
+					},
+					"required": []interface{}{"count", "items", "Any"},
+				},
+				"testItem": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{
+							"type":        "string",
+							"description": "The name of the item.",
+						},
+						"tags": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]interface{}{"type": "string"},
+						},
+						"labels": map[string]interface{}{
+							"type":                 "object",
+							"additionalProperties": map[string]interface{}{"type": "string"},
+						},
+						"created": map[string]interface{}{"type": "string", "format": "date-time"},
+						"parent": map[string]interface{}{
+							"description": "The parent of the item.",
+							"allOf": []interface{}{
+								map[string]interface{}{"$ref": "#/components/schemas/testItem"},
+							},
+						},
+					},
+					"required": []interface{}{"name", "created"},
+				},
+			},
+		},
+	})
+}
+
+func TestServeHTTP(t *testing.T) {
+	c := qt.New(t)
+
+	doc := openapi.NewDocument(openapi.Info{
+		Title:   "Test",
+		Version: "1.0.0",
+	})
+	doc.AddEndpoint(openapi.Endpoint{
+		Method:  http.MethodGet,
+		Path:    "/ping",
+		Summary: "Ping the server.",
+	})
+
+	srv := httptest.NewServer(doc)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+	c.Check(resp.StatusCode, qt.Equals, http.StatusOK)
+	c.Check(resp.Header.Get("Content-Type"), qt.Equals, "application/json")
+	buf, err := io.ReadAll(resp.Body)
+	c.Assert(err, qt.IsNil)
+	c.Check(string(buf), qt.JSONEquals, doc)
+}
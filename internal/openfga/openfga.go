@@ -17,7 +17,7 @@ import (
 
 var (
 	// resourceTypes contains a list of all resource kinds (i.e. tags) used throughout JIMM.
-	resourceTypes = [...]string{names.UserTagKind, names.ModelTagKind, names.ControllerTagKind, names.ApplicationOfferTagKind, jimmnames.GroupTagKind, jimmnames.ServiceAccountTagKind}
+	resourceTypes = [...]string{names.UserTagKind, names.ModelTagKind, names.ControllerTagKind, names.ApplicationOfferTagKind, jimmnames.GroupTagKind, jimmnames.ServiceAccountTagKind, names.CloudCredentialTagKind}
 )
 
 // Tuple represents a relation between an object and a target.
@@ -45,6 +45,8 @@ var (
 	ApplicationOfferType Kind = names.ApplicationOfferTagKind
 	// CloudType represents a cloud object.
 	CloudType Kind = names.CloudTagKind
+	// CloudCredentialType represents a cloud credential object.
+	CloudCredentialType Kind = names.CloudCredentialTagKind
 	// ControllerType represents a controller object.
 	ControllerType Kind = names.ControllerTagKind
 	// GroupType represents a group object.
@@ -267,6 +269,36 @@ func (o *OFGAClient) removeTuples(ctx context.Context, tuple Tuple) (err error)
 	}
 }
 
+// moveTuples iteratively reads through all the tuples matching the given
+// tuple, adds the tuple returned by rewrite for each of them and then
+// deletes them. Rewritten tuples that already exist are left as they are.
+func (o *OFGAClient) moveTuples(ctx context.Context, tuple Tuple, rewrite func(Tuple) Tuple) error {
+	pageSize := 50
+	for {
+		// As in removeTuples the returned tuples are deleted, so a fresh
+		// query is made for each page.
+		//nolint:gosec // The page size will not exceed int32.
+		tuples, ct, err := o.ReadRelatedObjects(ctx, tuple, int32(pageSize), "")
+		if err != nil {
+			return err
+		}
+		for _, t := range tuples {
+			err := o.AddRelation(ctx, rewrite(t))
+			if err != nil && !strings.Contains(err.Error(), "cannot write a tuple which already exists") {
+				return err
+			}
+		}
+		if len(tuples) > 0 {
+			if err := o.RemoveRelation(ctx, tuples...); err != nil {
+				return err
+			}
+		}
+		if ct == "" {
+			return nil
+		}
+	}
+}
+
 // AddControllerModel adds a relation between a controller and a model.
 func (o *OFGAClient) AddControllerModel(ctx context.Context, controller names.ControllerTag, model names.ModelTag) error {
 	return o.setResourceAccess(ctx, controller, model, ofganames.ControllerRelation)
@@ -340,6 +372,158 @@ func (o *OFGAClient) RemoveGroup(ctx context.Context, group jimmnames.GroupTag)
 	return nil
 }
 
+// RemoveUser removes all the relations of a user, both the access the
+// user has been granted and the user's group memberships.
+func (o *OFGAClient) RemoveUser(ctx context.Context, user names.UserTag) error {
+	// The OpenFGA Read API requires the type of the target to be
+	// specified along with the user, so each kind is read in turn.
+	kinds := append(resourceTypes[:], names.CloudTagKind)
+	for _, kind := range kinds {
+		kt, err := ofganames.BlankKindTag(kind)
+		if err != nil {
+			return errors.E(err)
+		}
+		if err := o.removeTuples(ctx, Tuple{
+			Object: ofganames.ConvertTag(user),
+			Target: kt,
+		}); err != nil {
+			return errors.E(err)
+		}
+	}
+	return nil
+}
+
+// RenameUser moves all the relations of a user, both the access the user
+// has been granted and the user's group memberships, to the user with
+// the new tag.
+func (o *OFGAClient) RenameUser(ctx context.Context, user, renamed names.UserTag) error {
+	kinds := append(resourceTypes[:], names.CloudTagKind)
+	for _, kind := range kinds {
+		kt, err := ofganames.BlankKindTag(kind)
+		if err != nil {
+			return errors.E(err)
+		}
+		err = o.moveTuples(ctx, Tuple{
+			Object: ofganames.ConvertTag(user),
+			Target: kt,
+		}, func(t Tuple) Tuple {
+			t.Object = ofganames.ConvertTag(renamed)
+			return t
+		})
+		if err != nil {
+			return errors.E(err)
+		}
+	}
+	return nil
+}
+
+// SetGroupModelAccess gives the members of the group the given access to
+// the model. The access is recorded against the group, rather than each
+// member, and so applies to members added to the group later. Note that
+// the action is idempotent.
+func (o *OFGAClient) SetGroupModelAccess(ctx context.Context, group jimmnames.GroupTag, model names.ModelTag, relation Relation) error {
+	err := o.AddRelation(ctx, Tuple{
+		Object:   ofganames.ConvertTagWithRelation(group, ofganames.MemberRelation),
+		Relation: relation,
+		Target:   ofganames.ConvertTag(model),
+	})
+	if err != nil {
+		// TODO we should opt to check against specific errors via checking their code/metadata.
+		if strings.Contains(err.Error(), "cannot write a tuple which already exists") {
+			return nil
+		}
+		return errors.E(err)
+	}
+	return nil
+}
+
+// UnsetGroupModelAccess removes the given accesses to the model from the
+// group. Access the members of the group hold directly is unaffected.
+// Note that the action is idempotent.
+func (o *OFGAClient) UnsetGroupModelAccess(ctx context.Context, group jimmnames.GroupTag, model names.ModelTag, relations ...Relation) error {
+	for _, relation := range relations {
+		err := o.RemoveRelation(ctx, Tuple{
+			Object:   ofganames.ConvertTagWithRelation(group, ofganames.MemberRelation),
+			Relation: relation,
+			Target:   ofganames.ConvertTag(model),
+		})
+		if err != nil {
+			// TODO we should opt to check against specific errors via checking their code/metadata.
+			if strings.Contains(err.Error(), "cannot delete a tuple which does not exist") {
+				continue
+			}
+			return errors.E(err)
+		}
+	}
+	return nil
+}
+
+// SetCloudCredentialAccess allows the given entity to add models using
+// the cloud credential. The entity is either a user or, when tagged with
+// the member relation, the members of a group. Note that the action is
+// idempotent.
+func (o *OFGAClient) SetCloudCredentialAccess(ctx context.Context, credential names.CloudCredentialTag, entity *Tag) error {
+	err := o.AddRelation(ctx, Tuple{
+		Object:   entity,
+		Relation: ofganames.CanAddModelRelation,
+		Target:   ofganames.ConvertTag(credential),
+	})
+	if err != nil {
+		// TODO we should opt to check against specific errors via checking their code/metadata.
+		if strings.Contains(err.Error(), "cannot write a tuple which already exists") {
+			return nil
+		}
+		return errors.E(err)
+	}
+	return nil
+}
+
+// UnsetCloudCredentialAccess removes the given entity's ability to add
+// models using the cloud credential. Note that the action is idempotent.
+func (o *OFGAClient) UnsetCloudCredentialAccess(ctx context.Context, credential names.CloudCredentialTag, entity *Tag) error {
+	err := o.RemoveRelation(ctx, Tuple{
+		Object:   entity,
+		Relation: ofganames.CanAddModelRelation,
+		Target:   ofganames.ConvertTag(credential),
+	})
+	if err != nil {
+		// TODO we should opt to check against specific errors via checking their code/metadata.
+		if strings.Contains(err.Error(), "cannot delete a tuple which does not exist") {
+			return nil
+		}
+		return errors.E(err)
+	}
+	return nil
+}
+
+// RemoveCloudCredential removes all access to a cloud credential.
+func (o *OFGAClient) RemoveCloudCredential(ctx context.Context, credential names.CloudCredentialTag) error {
+	if err := o.removeTuples(
+		ctx,
+		Tuple{
+			Target: ofganames.ConvertTag(credential),
+		},
+	); err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
+// RenameCloudCredential moves all access to a cloud credential to the
+// cloud credential with the new tag.
+func (o *OFGAClient) RenameCloudCredential(ctx context.Context, credential, renamed names.CloudCredentialTag) error {
+	err := o.moveTuples(ctx, Tuple{
+		Target: ofganames.ConvertTag(credential),
+	}, func(t Tuple) Tuple {
+		t.Target = ofganames.ConvertTag(renamed)
+		return t
+	})
+	if err != nil {
+		return errors.E(err)
+	}
+	return nil
+}
+
 // RemoveCloud removes a cloud.
 func (o *OFGAClient) RemoveCloud(ctx context.Context, cloud names.CloudTag) error {
 	if err := o.removeTuples(
@@ -360,6 +360,100 @@ func (s *openFGATestSuite) TestRemoveGroup(c *gc.C) {
 	c.Assert(allowed, gc.Equals, false)
 }
 
+func (s *openFGATestSuite) TestSetAndUnsetGroupModelAccess(c *gc.C) {
+	ctx := context.Background()
+	group := jimmnames.NewGroupTag(uuid.NewString())
+	model := names.NewModelTag(uuid.NewString())
+	alice := names.NewUserTag("alice@canonical.com")
+
+	err := s.ofgaClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group),
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	aliceWriter := openfga.Tuple{
+		Object:   ofganames.ConvertTag(alice),
+		Relation: ofganames.WriterRelation,
+		Target:   ofganames.ConvertTag(model),
+	}
+
+	err = s.ofgaClient.SetGroupModelAccess(ctx, group, model, ofganames.WriterRelation)
+	c.Assert(err, gc.Equals, nil)
+	// Setting the same access again is not an error.
+	err = s.ofgaClient.SetGroupModelAccess(ctx, group, model, ofganames.WriterRelation)
+	c.Assert(err, gc.Equals, nil)
+
+	allowed, err := s.ofgaClient.CheckRelation(ctx, aliceWriter, false)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(allowed, gc.Equals, true)
+
+	err = s.ofgaClient.UnsetGroupModelAccess(ctx, group, model, ofganames.WriterRelation, ofganames.AdministratorRelation)
+	c.Assert(err, gc.Equals, nil)
+
+	allowed, err = s.ofgaClient.CheckRelation(ctx, aliceWriter, false)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(allowed, gc.Equals, false)
+}
+
+func (s *openFGATestSuite) TestCloudCredentialAccess(c *gc.C) {
+	ctx := context.Background()
+	group := jimmnames.NewGroupTag(uuid.NewString())
+	credential := names.NewCloudCredentialTag("test-cloud/alice@canonical.com/cred-1")
+	bob := names.NewUserTag("bob@canonical.com")
+	eve := names.NewUserTag("eve@canonical.com")
+
+	err := s.ofgaClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(eve),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group),
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	bobAddModel := openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob),
+		Relation: ofganames.CanAddModelRelation,
+		Target:   ofganames.ConvertTag(credential),
+	}
+	eveAddModel := openfga.Tuple{
+		Object:   ofganames.ConvertTag(eve),
+		Relation: ofganames.CanAddModelRelation,
+		Target:   ofganames.ConvertTag(credential),
+	}
+
+	err = s.ofgaClient.SetCloudCredentialAccess(ctx, credential, ofganames.ConvertTag(bob))
+	c.Assert(err, gc.Equals, nil)
+	// Setting the same access again is not an error.
+	err = s.ofgaClient.SetCloudCredentialAccess(ctx, credential, ofganames.ConvertTag(bob))
+	c.Assert(err, gc.Equals, nil)
+	err = s.ofgaClient.SetCloudCredentialAccess(ctx, credential, ofganames.ConvertTagWithRelation(group, ofganames.MemberRelation))
+	c.Assert(err, gc.Equals, nil)
+
+	for _, check := range []openfga.Tuple{bobAddModel, eveAddModel} {
+		allowed, err := s.ofgaClient.CheckRelation(ctx, check, false)
+		c.Assert(err, gc.Equals, nil)
+		c.Assert(allowed, gc.Equals, true)
+	}
+
+	err = s.ofgaClient.UnsetCloudCredentialAccess(ctx, credential, ofganames.ConvertTag(bob))
+	c.Assert(err, gc.Equals, nil)
+	// Unsetting the same access again is not an error.
+	err = s.ofgaClient.UnsetCloudCredentialAccess(ctx, credential, ofganames.ConvertTag(bob))
+	c.Assert(err, gc.Equals, nil)
+
+	allowed, err := s.ofgaClient.CheckRelation(ctx, bobAddModel, false)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(allowed, gc.Equals, false)
+
+	err = s.ofgaClient.RemoveCloudCredential(ctx, credential)
+	c.Assert(err, gc.Equals, nil)
+
+	allowed, err = s.ofgaClient.CheckRelation(ctx, eveAddModel, false)
+	c.Assert(err, gc.Equals, nil)
+	c.Assert(allowed, gc.Equals, false)
+}
+
 func (s *openFGATestSuite) TestRemoveCloud(c *gc.C) {
 	cloud1 := names.NewCloudTag("cloud-1")
 
@@ -585,3 +679,82 @@ func (s *openFGATestSuite) TestListObjectsWithPeristedTuples(c *gc.C) {
 func Test(t *testing.T) {
 	gc.TestingT(t)
 }
+
+func (s *openFGATestSuite) TestRenameUser(c *gc.C) {
+	ctx := context.Background()
+	group := jimmnames.NewGroupTag(uuid.NewString())
+	model := names.NewModelTag(uuid.NewString())
+	credential := names.NewCloudCredentialTag("test-cloud/bob@external/cred-1")
+	bob := names.NewUserTag("bob@external")
+	renamed := names.NewUserTag("bob@canonical.com")
+	renamedCredential := names.NewCloudCredentialTag("test-cloud/bob@canonical.com/cred-1")
+
+	err := s.ofgaClient.AddRelation(ctx, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(model),
+	}, openfga.Tuple{
+		Object:   ofganames.ConvertTag(bob),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(credential),
+	}, openfga.Tuple{
+		// The renamed user already administers the model.
+		Object:   ofganames.ConvertTag(renamed),
+		Relation: ofganames.AdministratorRelation,
+		Target:   ofganames.ConvertTag(model),
+	})
+	c.Assert(err, gc.Equals, nil)
+
+	err = s.ofgaClient.RenameUser(ctx, bob, renamed)
+	c.Assert(err, gc.Equals, nil)
+	err = s.ofgaClient.RenameCloudCredential(ctx, credential, renamedCredential)
+	c.Assert(err, gc.Equals, nil)
+
+	for _, check := range []struct {
+		tuple   openfga.Tuple
+		allowed bool
+	}{{
+		tuple: openfga.Tuple{
+			Object:   ofganames.ConvertTag(renamed),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(group),
+		},
+		allowed: true,
+	}, {
+		tuple: openfga.Tuple{
+			Object:   ofganames.ConvertTag(renamed),
+			Relation: ofganames.AdministratorRelation,
+			Target:   ofganames.ConvertTag(model),
+		},
+		allowed: true,
+	}, {
+		tuple: openfga.Tuple{
+			Object:   ofganames.ConvertTag(renamed),
+			Relation: ofganames.AdministratorRelation,
+			Target:   ofganames.ConvertTag(renamedCredential),
+		},
+		allowed: true,
+	}, {
+		tuple: openfga.Tuple{
+			Object:   ofganames.ConvertTag(bob),
+			Relation: ofganames.MemberRelation,
+			Target:   ofganames.ConvertTag(group),
+		},
+		allowed: false,
+	}, {
+		tuple: openfga.Tuple{
+			Object:   ofganames.ConvertTag(bob),
+			Relation: ofganames.AdministratorRelation,
+			Target:   ofganames.ConvertTag(model),
+		},
+		allowed: false,
+	}} {
+		allowed, err := s.ofgaClient.CheckRelation(ctx, check.tuple, false)
+		c.Assert(err, gc.Equals, nil)
+		c.Check(allowed, gc.Equals, check.allowed)
+	}
+}
@@ -31,6 +31,26 @@ type User struct {
 	*dbmodel.Identity
 	client    *OFGAClient
 	JimmAdmin bool
+
+	// ModelScope is the model that an identity authenticated by a model
+	// token is limited to. Such an identity has no access to any other
+	// resource, including those on which access has been granted to all
+	// users. If ModelScope is not set the identity has no access at all.
+	// It has no effect on other identities.
+	ModelScope names.ModelTag
+}
+
+// inScope returns whether the given resource is within the scope of the
+// user, see ModelScope.
+func (u *User) inScope(resource *ofganames.Tag) bool {
+	if !dbmodel.IsModelTokenIdentity(u.Name) {
+		return true
+	}
+	if u.ModelScope.Id() == "" {
+		return false
+	}
+	scope := ofganames.ConvertTag(u.ModelScope)
+	return resource.Kind == scope.Kind && resource.ID == scope.ID
 }
 
 // IsAllowedAddModed returns true if the user is allowed to add a model on the
@@ -43,6 +63,17 @@ func (u *User) IsAllowedAddModel(ctx context.Context, resource names.CloudTag) (
 	return allowed, nil
 }
 
+// IsAllowedAddModelWithCredential returns true if the user has been
+// allowed to add models using the specified cloud credential. Note that
+// this does not take into account the owner of the credential.
+func (u *User) IsAllowedAddModelWithCredential(ctx context.Context, resource names.CloudCredentialTag) (bool, error) {
+	allowed, err := checkRelation(ctx, u, resource, ofganames.CanAddModelRelation)
+	if err != nil {
+		return false, errors.E(err)
+	}
+	return allowed, nil
+}
+
 // IsApplicationOfferConsumer returns true if user has consumer relation to the application offer.
 func (u *User) IsApplicationOfferConsumer(ctx context.Context, resource names.ApplicationOfferTag) (bool, error) {
 	isConsumer, err := checkRelation(ctx, u, resource, ofganames.ConsumerRelation)
@@ -251,9 +282,12 @@ func (u *User) ListModels(ctx context.Context, relation ofga.Relation) ([]string
 	if err != nil {
 		return nil, err
 	}
-	modelUUIDs := make([]string, len(entities))
-	for i, model := range entities {
-		modelUUIDs[i] = model.ID
+	modelUUIDs := make([]string, 0, len(entities))
+	for _, model := range entities {
+		if !u.inScope(&model) {
+			continue
+		}
+		modelUUIDs = append(modelUUIDs, model.ID)
 	}
 	return modelUUIDs, err
 }
@@ -264,13 +298,33 @@ func (u *User) ListApplicationOffers(ctx context.Context, relation ofga.Relation
 	if err != nil {
 		return nil, err
 	}
-	appOfferUUIDs := make([]string, len(entities))
-	for i, offer := range entities {
-		appOfferUUIDs[i] = offer.ID
+	appOfferUUIDs := make([]string, 0, len(entities))
+	for _, offer := range entities {
+		if !u.inScope(&offer) {
+			continue
+		}
+		appOfferUUIDs = append(appOfferUUIDs, offer.ID)
 	}
 	return appOfferUUIDs, err
 }
 
+// ListGroups returns a slice of the UUIDs of the groups this user is a
+// member of, either directly or through membership of another group.
+func (u *User) ListGroups(ctx context.Context) ([]string, error) {
+	entities, err := u.client.ListObjects(ctx, ofganames.ConvertTag(u.ResourceTag()), ofganames.MemberRelation, GroupType, nil)
+	if err != nil {
+		return nil, err
+	}
+	groupUUIDs := make([]string, 0, len(entities))
+	for _, group := range entities {
+		if !u.inScope(&group) {
+			continue
+		}
+		groupUUIDs = append(groupUUIDs, group.ID)
+	}
+	return groupUUIDs, err
+}
+
 type administratorT interface {
 	names.ControllerTag | names.ModelTag | names.ApplicationOfferTag | names.CloudTag
 
@@ -280,12 +334,16 @@ type administratorT interface {
 }
 
 func checkRelation[T ofganames.ResourceTagger](ctx context.Context, u *User, resource T, relation Relation) (bool, error) {
+	target := ofganames.ConvertTag(resource)
+	if !u.inScope(target) {
+		return false, nil
+	}
 	isAllowed, err := u.client.CheckRelation(
 		ctx,
 		Tuple{
 			Object:   ofganames.ConvertTag(u.ResourceTag()),
 			Relation: relation,
-			Target:   ofganames.ConvertTag(resource),
+			Target:   target,
 		},
 		true,
 	)
@@ -303,6 +361,9 @@ func CheckRelation(ctx context.Context, u *User, resource names.Tag, relation Re
 	var tag *ofganames.Tag
 	var err error
 	tag = ofganames.ConvertGenericTag(resource)
+	if !u.inScope(tag) {
+		return false, nil
+	}
 	isAllowed, err := u.client.CheckRelation(
 		ctx,
 		Tuple{
@@ -532,6 +532,45 @@ func (s *userTestSuite) TestListModels(c *gc.C) {
 	c.Assert(modelUUIDs, gc.DeepEquals, wantUUIDs)
 }
 
+func (s *userTestSuite) TestListGroups(c *gc.C) {
+	ctx := context.Background()
+
+	group1 := jimmnames.NewGroupTag(uuid.NewString())
+	group2 := jimmnames.NewGroupTag(uuid.NewString())
+	group3 := jimmnames.NewGroupTag(uuid.NewString())
+
+	adam := names.NewUserTag("adam")
+
+	// adam is a member of group1, and through it of group2, but not
+	// of group3.
+	tuples := []openfga.Tuple{{
+		Object:   ofganames.ConvertTag(adam),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group1),
+	}, {
+		Object:   ofganames.ConvertTagWithRelation(group1, ofganames.MemberRelation),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group2),
+	}, {
+		Object:   ofganames.ConvertTag(names.NewUserTag("eve")),
+		Relation: ofganames.MemberRelation,
+		Target:   ofganames.ConvertTag(group3),
+	}}
+	err := s.ofgaClient.AddRelation(ctx, tuples...)
+	c.Assert(err, gc.IsNil)
+
+	adamIdentity, err := dbmodel.NewIdentity(adam.Name())
+	c.Assert(err, gc.IsNil)
+
+	adamUser := openfga.NewUser(adamIdentity, s.ofgaClient)
+	groupUUIDs, err := adamUser.ListGroups(ctx)
+	c.Assert(err, gc.IsNil)
+	wantUUIDs := []string{group1.Id(), group2.Id()}
+	sort.Strings(wantUUIDs)
+	sort.Strings(groupUUIDs)
+	c.Assert(groupUUIDs, gc.DeepEquals, wantUUIDs)
+}
+
 func (s *userTestSuite) TestListApplicationOffers(c *gc.C) {
 	ctx := context.Background()
 
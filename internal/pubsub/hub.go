@@ -6,19 +6,111 @@
 package pubsub
 
 import (
+	"context"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/juju/utils/v2/parallel"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
 
 	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
 )
 
+// defaultSlowConsumerThreshold is the number of pending messages at
+// which a subscriber is reported as a slow consumer if the hub has no
+// SlowConsumerThreshold.
+const defaultSlowConsumerThreshold = 10
+
 // HandlerFunc takes two arguments - a model ID and the message about this model.
 type HandlerFunc func(string, interface{})
 
+// SubscribeOptions holds the optional parameters of a subscription.
+type SubscribeOptions struct {
+	// Name identifies the subscriber when the hub's subscribers are
+	// listed.
+	Name string
+
+	// OnEvict, if set, is called when the subscriber is evicted from
+	// the hub for being a slow consumer. No further messages are
+	// delivered to an evicted subscriber.
+	OnEvict func()
+}
+
 type subscriber struct {
+	id      int
+	name    string
+	created time.Time
 	matcher func(string) bool
 	handler HandlerFunc
+	onEvict func()
+
+	// pending is the number of messages published to the subscriber
+	// that the handler has not yet finished with.
+	pending atomic.Int64
+	// delivered is the number of messages the handler has finished
+	// with.
+	delivered atomic.Int64
+	// lastDelivered is the time, in unix nanoseconds, at which the
+	// handler last finished with a message.
+	lastDelivered atomic.Int64
+}
+
+// deliver calls the subscriber's handler with the given message and
+// records the delivery.
+func (s *subscriber) deliver(model string, content interface{}) {
+	s.handler(model, content)
+	s.pending.Add(-1)
+	s.delivered.Add(1)
+	s.lastDelivered.Store(time.Now().UnixNano())
+}
+
+// A SubscriberInfo describes a subscriber of a Hub.
+type SubscriberInfo struct {
+	// ID is the identifier of the subscriber within the hub.
+	ID int
+
+	// Name is the name given to the subscriber when it subscribed.
+	Name string
+
+	// Subscribed is the time the subscriber subscribed.
+	Subscribed time.Time
+
+	// Pending is the number of published messages the subscriber has
+	// not yet handled.
+	Pending int64
+
+	// Delivered is the number of messages the subscriber has handled.
+	Delivered int64
+
+	// LastDelivered is the time the subscriber last handled a message,
+	// it is zero if no messages have been handled.
+	LastDelivered time.Time
+
+	// Slow reports whether the subscriber has at least the hub's
+	// SlowConsumerThreshold messages pending.
+	Slow bool
+}
+
+// A TopicInfo holds the delivery metrics of the messages published
+// about a model.
+type TopicInfo struct {
+	// Model is the model the messages are about.
+	Model string
+
+	// Published is the number of messages published about the model.
+	Published int64
+
+	// Deliveries is the number of times a message about the model has
+	// been passed to a subscriber.
+	Deliveries int64
+
+	// LastPublished is the time the last message about the model was
+	// published.
+	LastPublished time.Time
 }
 
 // Hub implements a simple pubsub mechanism that passes published
@@ -29,11 +121,22 @@ type subscriber struct {
 type Hub struct {
 	MaxConcurrency int
 
+	// SlowConsumerThreshold is the number of pending messages at which
+	// a subscriber is reported as a slow consumer. If this is zero a
+	// default of 10 is used.
+	SlowConsumerThreshold int
+
+	// MaxPending is the maximum number of messages that may be pending
+	// for a subscriber. A subscriber that would exceed this is evicted
+	// from the hub. If this is zero subscribers are never evicted.
+	MaxPending int
+
 	mu          sync.Mutex
 	parallel    *parallel.Run
 	idx         int
-	subscribers map[int]subscriber
+	subscribers map[int]*subscriber
 	messages    map[string]interface{}
+	topics      map[string]*TopicInfo
 }
 
 func (h *Hub) setupParallel() {
@@ -61,12 +164,21 @@ func (h *Hub) Publish(model string, content interface{}) <-chan struct{} {
 		h.messages = make(map[string]interface{})
 	}
 	h.messages[model] = content
+	topic := h.topic(model)
+	topic.Published++
+	topic.LastPublished = time.Now()
 	for _, s := range h.subscribers {
 		if s.matcher(model) {
+			if h.MaxPending > 0 && s.pending.Load() >= int64(h.MaxPending) {
+				h.evict(s)
+				continue
+			}
+			topic.Deliveries++
+			s.pending.Add(1)
 			wait.Add(1)
 			h.parallel.Do(func() error {
 				defer wait.Done()
-				s.handler(model, content)
+				s.deliver(model, content)
 				return nil
 			})
 		}
@@ -80,6 +192,20 @@ func (h *Hub) Publish(model string, content interface{}) <-chan struct{} {
 	return done
 }
 
+// topic returns the metrics of the given model, creating them if
+// necessary. h.mu must be held.
+func (h *Hub) topic(model string) *TopicInfo {
+	if h.topics == nil {
+		h.topics = make(map[string]*TopicInfo)
+	}
+	t, ok := h.topics[model]
+	if !ok {
+		t = &TopicInfo{Model: model}
+		h.topics[model] = t
+	}
+	return t
+}
+
 // Subscribe to model information with a handler function. The handler
 // function will be called whenever any message about this model is
 // published.
@@ -94,6 +220,12 @@ func (h *Hub) Subscribe(model string, handler HandlerFunc) (func(), error) {
 // while the modelMatcher function is called. Any DB lookups or other
 // long operations for the matcher should be done out-of-band.
 func (h *Hub) SubscribeMatch(modelMatcher func(string) bool, handler HandlerFunc) (func(), error) {
+	return h.SubscribeMatchWithOptions(modelMatcher, handler, SubscribeOptions{})
+}
+
+// SubscribeMatchWithOptions is like SubscribeMatch but also takes the
+// optional parameters of the subscription.
+func (h *Hub) SubscribeMatchWithOptions(modelMatcher func(string) bool, handler HandlerFunc, opts SubscribeOptions) (func(), error) {
 	const op = errors.Op("pubsub.SubscribeMatch")
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -106,12 +238,16 @@ func (h *Hub) SubscribeMatch(modelMatcher func(string) bool, handler HandlerFunc
 	}
 	idx := h.idx
 	h.idx++
-	s := subscriber{
+	s := &subscriber{
+		id:      idx,
+		name:    opts.Name,
+		created: time.Now(),
 		matcher: modelMatcher,
 		handler: handler,
+		onEvict: opts.OnEvict,
 	}
 	if h.subscribers == nil {
-		h.subscribers = make(map[int]subscriber)
+		h.subscribers = make(map[int]*subscriber)
 	}
 	h.subscribers[idx] = s
 
@@ -119,7 +255,9 @@ func (h *Hub) SubscribeMatch(modelMatcher func(string) bool, handler HandlerFunc
 	// call the handler function if appropriate.
 	for model, content := range h.messages {
 		if modelMatcher(model) {
-			handler(model, content)
+			h.topic(model).Deliveries++
+			s.pending.Add(1)
+			s.deliver(model, content)
 		}
 	}
 
@@ -132,6 +270,96 @@ func (h *Hub) SubscribeMatch(modelMatcher func(string) bool, handler HandlerFunc
 	}, nil
 }
 
+// Subscribers returns the current subscribers of the hub, ordered by
+// ID.
+func (h *Hub) Subscribers() []SubscriberInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	threshold := int64(h.SlowConsumerThreshold)
+	if threshold <= 0 {
+		threshold = defaultSlowConsumerThreshold
+	}
+	infos := make([]SubscriberInfo, 0, len(h.subscribers))
+	for _, s := range h.subscribers {
+		info := SubscriberInfo{
+			ID:         s.id,
+			Name:       s.name,
+			Subscribed: s.created,
+			Pending:    s.pending.Load(),
+			Delivered:  s.delivered.Load(),
+		}
+		if t := s.lastDelivered.Load(); t != 0 {
+			info.LastDelivered = time.Unix(0, t)
+		}
+		info.Slow = info.Pending >= threshold
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// SlowConsumers returns the subscribers of the hub that have at least
+// SlowConsumerThreshold messages pending.
+func (h *Hub) SlowConsumers() []SubscriberInfo {
+	var slow []SubscriberInfo
+	for _, info := range h.Subscribers() {
+		if info.Slow {
+			slow = append(slow, info)
+		}
+	}
+	return slow
+}
+
+// Topics returns the delivery metrics of every model that has had a
+// message published about it, ordered by model.
+func (h *Hub) Topics() []TopicInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	topics := make([]TopicInfo, 0, len(h.topics))
+	for _, t := range h.topics {
+		topics = append(topics, *t)
+	}
+	sort.Slice(topics, func(i, j int) bool {
+		return topics[i].Model < topics[j].Model
+	})
+	return topics
+}
+
+// Evict removes the subscriber with the given ID from the hub, calling
+// its OnEvict function if it has one. Evict returns false if there is
+// no such subscriber.
+func (h *Hub) Evict(id int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.subscribers[id]
+	if !ok {
+		return false
+	}
+	h.evict(s)
+	return true
+}
+
+// evict removes the given subscriber from the hub. h.mu must be held.
+func (h *Hub) evict(s *subscriber) {
+	delete(h.subscribers, s.id)
+	servermon.PubsubEvictedSubscribersCount.Inc()
+	zapctx.Warn(context.Background(), "evicting slow pubsub subscriber",
+		zap.Int("id", s.id),
+		zap.String("name", s.name),
+		zap.Int64("pending", s.pending.Load()),
+	)
+	if s.onEvict != nil {
+		// The eviction function is called asynchronously so that
+		// it may use the hub.
+		go s.onEvict()
+	}
+}
+
 func modelMatches(matchModel string) func(string) bool {
 	return func(model string) bool {
 		return matchModel == model
@@ -118,6 +118,92 @@ func (s *hubSuite) TestSubscribeMatcher(c *gc.C) {
 
 }
 
+func (s *hubSuite) TestSubscribersAndTopics(c *gc.C) {
+	hub := &pubsub.Hub{}
+
+	unsubscribe, err := hub.SubscribeMatchWithOptions(func(string) bool { return true }, func(string, interface{}) {}, pubsub.SubscribeOptions{
+		Name: "all-models",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+	_, err = hub.Subscribe("model2", func(string, interface{}) {})
+	c.Assert(err, jc.ErrorIsNil)
+
+	assertPublish(c, hub, "model1", "message1")
+	assertPublish(c, hub, "model1", "message2")
+	assertPublish(c, hub, "model2", "message3")
+
+	subscribers := hub.Subscribers()
+	c.Assert(subscribers, gc.HasLen, 2)
+	c.Check(subscribers[0].Name, gc.Equals, "all-models")
+	c.Check(subscribers[0].Delivered, gc.Equals, int64(3))
+	c.Check(subscribers[0].Pending, gc.Equals, int64(0))
+	c.Check(subscribers[0].Slow, gc.Equals, false)
+	c.Check(subscribers[1].Name, gc.Equals, "")
+	c.Check(subscribers[1].Delivered, gc.Equals, int64(1))
+
+	topics := hub.Topics()
+	c.Assert(topics, gc.HasLen, 2)
+	c.Check(topics[0].Model, gc.Equals, "model1")
+	c.Check(topics[0].Published, gc.Equals, int64(2))
+	c.Check(topics[0].Deliveries, gc.Equals, int64(2))
+	c.Check(topics[1].Model, gc.Equals, "model2")
+	c.Check(topics[1].Published, gc.Equals, int64(1))
+	c.Check(topics[1].Deliveries, gc.Equals, int64(2))
+}
+
+func (s *hubSuite) TestSlowConsumerEviction(c *gc.C) {
+	hub := &pubsub.Hub{
+		SlowConsumerThreshold: 1,
+		MaxPending:            2,
+	}
+
+	block := make(chan struct{})
+	evicted := make(chan struct{})
+	_, err := hub.SubscribeMatchWithOptions(func(string) bool { return true }, func(string, interface{}) {
+		<-block
+	}, pubsub.SubscribeOptions{
+		Name:    "slow",
+		OnEvict: func() { close(evicted) },
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	hub.Publish("model1", "message1")
+	hub.Publish("model1", "message2")
+	slow := hub.SlowConsumers()
+	c.Assert(slow, gc.HasLen, 1)
+	c.Check(slow[0].Name, gc.Equals, "slow")
+	c.Check(slow[0].Pending, gc.Equals, int64(2))
+
+	// The third message would exceed MaxPending.
+	assertPublish(c, hub, "model1", "message3")
+	select {
+	case <-evicted:
+	case <-time.After(500 * time.Millisecond):
+		c.Fatal("subscriber not evicted")
+	}
+	c.Check(hub.Subscribers(), gc.HasLen, 0)
+	close(block)
+}
+
+func (s *hubSuite) TestEvict(c *gc.C) {
+	hub := &pubsub.Hub{}
+
+	messages := make(chan interface{}, 10)
+	_, err := hub.Subscribe("model1", func(_ string, content interface{}) {
+		messages <- content
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	subscribers := hub.Subscribers()
+	c.Assert(subscribers, gc.HasLen, 1)
+	c.Check(hub.Evict(subscribers[0].ID), gc.Equals, true)
+	c.Check(hub.Evict(subscribers[0].ID), gc.Equals, false)
+
+	assertPublish(c, hub, "model1", "message1")
+	assertMessage(c, messages, "")
+}
+
 type messageHub interface {
 	Publish(string, interface{}) <-chan struct{}
 }
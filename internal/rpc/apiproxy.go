@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -57,6 +58,29 @@ type WebsocketConnectionWithMetadata struct {
 	Conn           WebsocketConnection
 	ControllerUUID string
 	ModelName      string
+
+	// CheckRequest, if set, is called with the parameters of each
+	// request, other than those to the Admin facade, before it is sent
+	// to the controller. If it returns an error the request is not sent
+	// and the error is returned to the client.
+	CheckRequest func(ctx context.Context, facade, method string, params json.RawMessage) error
+
+	// ModifyResponse, if set, is called with each successful response
+	// from the controller, other than those to the Admin facade, and
+	// the facade and method of its request. The response it returns is
+	// sent to the client instead. If it returns an error the error is
+	// returned to the client.
+	ModifyResponse func(ctx context.Context, facade, method string, response json.RawMessage) (json.RawMessage, error)
+
+	// Drain, if set, is closed when the connection must be drained, for
+	// example because the controller is entering maintenance. Requests
+	// waiting for a response fail with DrainError, which should tell
+	// the client to retry, and the connection is closed.
+	Drain      <-chan struct{}
+	DrainError error
+
+	// Release, if set, is called once the proxy has stopped.
+	Release func()
 }
 
 // LoginService represents the LoginService interface used by the proxy.
@@ -78,6 +102,16 @@ type ProxyHelpers struct {
 	AuditLog                func(*dbmodel.AuditLogEntry)
 	LoginService            LoginService
 	AuthenticatedIdentityID string
+
+	// FacadeDeprecations holds the facade versions scheduled for
+	// removal. Responses to requests using these versions carry a
+	// deprecation warning.
+	FacadeDeprecations FacadeDeprecations
+
+	// RequestLimiter limits the number of requests proxied to
+	// controllers concurrently. If this is nil requests are not
+	// limited.
+	RequestLimiter *RequestLimiter
 }
 
 // ProxySockets will proxy requests from a client connection through to a controller
@@ -97,7 +131,11 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 		zapctx.Error(ctx, "Missing login service function")
 		return errors.E(op, "Missing login service function")
 	}
-	errChan := make(chan error, 2)
+	// Cancel the context once the proxy stops, so that any goroutine
+	// waiting for the connection to be drained finishes.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, 3)
 	msgInFlight := inflightMsgs{messages: make(map[uint64]*message)}
 	client := writeLockConn{conn: helpers.ConnClient}
 	// Note that the clProxy start method will create the connection to the desired controller only
@@ -112,6 +150,8 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 			loginService:            helpers.LoginService,
 			authenticatedIdentityID: helpers.AuthenticatedIdentityID,
 		},
+		deprecations:         helpers.FacadeDeprecations,
+		limiter:              helpers.RequestLimiter,
 		errChan:              errChan,
 		createControllerConn: helpers.ConnectController,
 	}
@@ -134,7 +174,11 @@ func ProxySockets(ctx context.Context, helpers ProxyHelpers) error {
 	// Normally the client would do this but we also do it here in case the
 	// connection to the controller fails and we want to trigger cleanup.
 	helpers.ConnClient.Close()
+	cancel()
 	clProxy.wg.Wait()
+	if clProxy.release != nil {
+		clProxy.release()
+	}
 	return err
 }
 
@@ -160,6 +204,7 @@ func (c *writeLockConn) sendMessage(responseObject any, request *message) {
 	msg := new(message)
 	msg.RequestID = request.RequestID
 	msg.Response = request.Response
+	addWarning(msg, request)
 	if responseObject != nil {
 		responseData, err := json.Marshal(responseObject)
 		if err != nil {
@@ -219,6 +264,9 @@ func (msgs *inflightMsgs) removeMessage(msgID uint64) {
 	msgs.mu.Unlock()
 
 	if ok {
+		if req.release != nil {
+			req.release()
+		}
 		servermon.JujuCallDurationHistogram.WithLabelValues(
 			req.Type,
 			req.Request,
@@ -227,6 +275,26 @@ func (msgs *inflightMsgs) removeMessage(msgID uint64) {
 	}
 }
 
+// removeAll deletes all the request messages still pending a response
+// and returns them in the order they were sent.
+func (msgs *inflightMsgs) removeAll() []*message {
+	msgs.mu.Lock()
+	defer msgs.mu.Unlock()
+
+	pending := make([]*message, 0, len(msgs.messages))
+	for _, msg := range msgs.messages {
+		if msg.release != nil {
+			msg.release()
+		}
+		pending = append(pending, msg)
+	}
+	msgs.messages = make(map[uint64]*message)
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestID < pending[j].RequestID
+	})
+	return pending
+}
+
 func (msgs *inflightMsgs) getMessage(key uint64) *message {
 	msgs.mu.Lock()
 	defer msgs.mu.Unlock()
@@ -258,17 +326,41 @@ func (p *modelProxy) sendError(socket *writeLockConn, req *message, err error) {
 	}
 	msg := createErrResponse(err, req)
 	if msg != nil {
+		addWarning(msg, req)
 		if err := socket.writeJson(msg); err != nil {
 			zapctx.Error(context.Background(), "failed to create err response message", zap.Error(err))
 		}
 	}
 	// An error message is a response back to the client.
 	servermon.JujuCallErrorCount.WithLabelValues(req.Type, req.Request, p.msgs.controllerUUID)
+	recordAuthorizationDenial(req, msg.ErrorCode)
 	if err := p.auditLogMessage(msg, true); err != nil {
 		zapctx.Error(context.Background(), "failed to audit log message", zap.Error(err))
 	}
 }
 
+// recordAuthorizationDenial records that the given request was denied if
+// the given error code, from the response to the request, shows the user
+// was not authorized to make it.
+func recordAuthorizationDenial(req *message, errorCode string) {
+	if req.Type == "" {
+		// Not a request.
+		return
+	}
+	switch errors.Code(errorCode) {
+	case errors.CodeUnauthorized, errors.CodeForbidden:
+		servermon.AuthorizationDenialCount.WithLabelValues(req.Type, req.Request).Inc()
+	}
+}
+
+// addWarning attaches the warning for the given request, if there is
+// one, to the given response.
+func addWarning(resp, req *message) {
+	if req.warning != nil {
+		resp.Warnings = append(resp.Warnings, *req.warning)
+	}
+}
+
 func (p *modelProxy) auditLogMessage(msg *message, isResponse bool) error {
 	ale := dbmodel.AuditLogEntry{
 		Time:           time.Now().UTC().Round(time.Millisecond),
@@ -331,6 +423,14 @@ type clientProxy struct {
 	errChan              chan error
 	createControllerConn func(context.Context) (WebsocketConnectionWithMetadata, error)
 	connectController    sync.Once
+	checkRequest         func(ctx context.Context, facade, method string, params json.RawMessage) error
+	deprecations         FacadeDeprecations
+	release              func()
+	limiter              *RequestLimiter
+
+	// requestClass is the priority class of requests made on the
+	// connection, it is set when the user logs in.
+	requestClass RequestClass
 }
 
 // start begins the client->controller proxier.
@@ -351,6 +451,9 @@ func (p *clientProxy) start(ctx context.Context) error {
 			return nil
 		}
 		zapctx.Debug(ctx, "Read message from client", zap.Any("message", msg))
+		if msg.isRequest() {
+			msg.warning = p.deprecations.RecordRequest(msg.Type, msg.Version)
+		}
 		err := p.makeControllerConnection(ctx)
 		if err != nil {
 			zapctx.Error(ctx, "error connecting to controller", zap.Error(err))
@@ -379,6 +482,19 @@ func (p *clientProxy) start(ctx context.Context) error {
 				msg = toController
 				p.msgs.addLoginMessage(toController)
 			}
+		} else if p.checkRequest != nil {
+			if err := p.checkRequest(ctx, msg.Type, msg.Request, msg.Params); err != nil {
+				p.sendError(p.src, msg, err)
+				continue
+			}
+		}
+		if msg.isRequest() && RequestLimited(msg.Type, msg.Request) {
+			release, err := p.limiter.Acquire(ctx, p.requestClass)
+			if err != nil {
+				p.sendError(p.src, msg, err)
+				continue
+			}
+			msg.release = release
 		}
 		p.msgs.addMessage(msg)
 		zapctx.Debug(ctx, "Writing to controller")
@@ -405,6 +521,8 @@ func (p *clientProxy) makeControllerConnection(ctx context.Context) error {
 
 		p.msgs.controllerUUID = connWithMetadata.ControllerUUID
 		p.modelName = connWithMetadata.ModelName
+		p.checkRequest = connWithMetadata.CheckRequest
+		p.release = connWithMetadata.Release
 		p.dst = &writeLockConn{conn: connWithMetadata.Conn}
 		controllerToClient := controllerProxy{
 			modelProxy: modelProxy{
@@ -416,20 +534,48 @@ func (p *clientProxy) makeControllerConnection(ctx context.Context) error {
 				modelName:      p.modelName,
 				conversationId: p.conversationId,
 			},
+			modifyResponse: connWithMetadata.ModifyResponse,
 		}
 		p.wg.Add(1)
 		go func() {
 			defer p.wg.Done()
 			p.errChan <- controllerToClient.start(ctx)
 		}()
+		if connWithMetadata.Drain != nil {
+			p.wg.Add(1)
+			go func() {
+				defer p.wg.Done()
+				select {
+				case <-connWithMetadata.Drain:
+					p.errChan <- p.drain(ctx, connWithMetadata.DrainError)
+				case <-ctx.Done():
+				}
+			}()
+		}
 	})
 	return createConnErr
 }
 
+// drain fails all the requests waiting for a response from the
+// controller with the given error and returns the error so that the
+// proxy stops.
+func (p *clientProxy) drain(ctx context.Context, err error) error {
+	const op = errors.Op("rpc.drain")
+	if err == nil {
+		err = errors.E(errors.CodeTryAgain, "connection drained")
+	}
+	zapctx.Info(ctx, "draining proxied connection", zap.String("model", p.modelName), zap.Error(err))
+	for _, msg := range p.msgs.removeAll() {
+		p.sendError(p.src, msg, err)
+	}
+	return errors.E(op, err)
+}
+
 // controllerProxy proxies messages from controller->client with the caveat that
 // it will retry client->controller messages that require further permissions.
 type controllerProxy struct {
 	modelProxy
+	modifyResponse func(ctx context.Context, facade, method string, response json.RawMessage) (json.RawMessage, error)
 }
 
 // start implements the controller->client proxier.
@@ -474,6 +620,19 @@ func (p *controllerProxy) start(ctx context.Context) error {
 				return fmt.Errorf("error modifying controller response: %w", err)
 			}
 		}
+		if req := p.msgs.getMessage(msg.RequestID); req != nil {
+			if p.modifyResponse != nil && req.Type != "Admin" && msg.Error == "" {
+				resp, err := p.modifyResponse(ctx, req.Type, req.Request, msg.Response)
+				if err != nil {
+					zapctx.Error(ctx, "failed to modify response", zap.Error(err))
+					p.handleError(req, err)
+					continue
+				}
+				msg.Response = resp
+			}
+			recordAuthorizationDenial(req, msg.ErrorCode)
+			addWarning(msg, req)
+		}
 		p.msgs.removeMessage(msg.RequestID)
 		if err := p.auditLogMessage(msg, true); err != nil {
 			zapctx.Error(context.Background(), "failed to audit log message", zap.Error(err))
@@ -690,6 +849,7 @@ func (p *clientProxy) handleAdminFacade(ctx context.Context, msg *message) (clie
 		if err != nil {
 			return errorFnc(err)
 		}
+		p.requestClass = RequestClassAutomation
 
 		return controllerLoginMessageFnc(user)
 	case "LoginWithSessionCookie":
@@ -697,6 +857,7 @@ func (p *clientProxy) handleAdminFacade(ctx context.Context, msg *message) (clie
 		if err != nil {
 			return errorFnc(err)
 		}
+		p.requestClass = RequestClassPolling
 
 		return controllerLoginMessageFnc(user)
 	case "Login":
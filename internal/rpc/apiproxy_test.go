@@ -34,6 +34,7 @@ type message struct {
 	ErrorCode string                 `json:"error-code,omitempty"`
 	ErrorInfo map[string]interface{} `json:"error-info,omitempty"`
 	Response  json.RawMessage        `json:"response,omitempty"`
+	Warnings  []apiparams.Warning    `json:"warnings,omitempty"`
 }
 
 func TestProxySocketsAdminFacade(t *testing.T) {
@@ -293,6 +294,161 @@ func TestProxySocketsAdminFacade(t *testing.T) {
 	}
 }
 
+func TestProxySocketsDeprecatedFacade(t *testing.T) {
+	c := qt.New(t)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+	clientWebsocket := newMockWebsocketConnection(10)
+	controllerWebsocket := newMockWebsocketConnection(10)
+
+	helpers := rpc.ProxyHelpers{
+		ConnClient: clientWebsocket,
+		TokenGen:   &mockTokenGenerator{},
+		ConnectController: func(ctx context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:           controllerWebsocket,
+				ModelName:      "test model",
+				ControllerUUID: uuid.NewString(),
+			}, nil
+		},
+		AuditLog:     func(*dbmodel.AuditLogEntry) {},
+		LoginService: &mockLoginService{},
+		FacadeDeprecations: rpc.FacadeDeprecations{{
+			Facade:     "ModelManager",
+			MaxVersion: 2,
+		}},
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := rpc.ProxySockets(ctx, helpers)
+		c.Check(err, qt.ErrorMatches, "Context cancelled")
+	}()
+
+	tests := []struct {
+		version        int
+		expectWarnings []apiparams.Warning
+	}{{
+		version: 2,
+		expectWarnings: []apiparams.Warning{{
+			Code:    apiparams.WarningDeprecatedFacade,
+			Message: "ModelManager facade version 2 is deprecated and will be removed, version 3 or later should be used",
+			Facade:  "ModelManager",
+			Version: 2,
+		}},
+	}, {
+		version: 9,
+	}}
+	for i, test := range tests {
+		req := message{
+			RequestID: uint64(i + 1),
+			Type:      "ModelManager",
+			Version:   test.version,
+			Request:   "ListModels",
+		}
+		data, err := json.Marshal(req)
+		c.Assert(err, qt.IsNil)
+		clientWebsocket.read <- data
+		select {
+		case data := <-controllerWebsocket.write:
+			c.Assert(string(data), qt.JSONEquals, req)
+		case <-time.After(2 * time.Second):
+			c.Fatal("timed out waiting for request")
+		}
+
+		data, err = json.Marshal(message{
+			RequestID: req.RequestID,
+			Response:  json.RawMessage(`{}`),
+		})
+		c.Assert(err, qt.IsNil)
+		controllerWebsocket.read <- data
+		select {
+		case data := <-clientWebsocket.write:
+			c.Check(string(data), qt.JSONEquals, message{
+				RequestID: req.RequestID,
+				Response:  json.RawMessage(`{}`),
+				Warnings:  test.expectWarnings,
+			})
+		case <-time.After(2 * time.Second):
+			c.Fatal("timed out waiting for response")
+		}
+	}
+	cancelFunc()
+	wg.Wait()
+}
+
+func TestProxySocketsDrain(t *testing.T) {
+	c := qt.New(t)
+
+	ctx := context.Background()
+	clientWebsocket := newMockWebsocketConnection(10)
+	controllerWebsocket := newMockWebsocketConnection(10)
+	drain := make(chan struct{})
+	released := make(chan struct{})
+
+	helpers := rpc.ProxyHelpers{
+		ConnClient: clientWebsocket,
+		TokenGen:   &mockTokenGenerator{},
+		ConnectController: func(ctx context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:           controllerWebsocket,
+				ModelName:      "test model",
+				ControllerUUID: uuid.NewString(),
+				Drain:          drain,
+				DrainError:     errors.E(errors.CodeTryAgain, "controller is under maintenance"),
+				Release:        func() { close(released) },
+			}, nil
+		},
+		AuditLog:     func(*dbmodel.AuditLogEntry) {},
+		LoginService: &mockLoginService{},
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		err := rpc.ProxySockets(ctx, helpers)
+		c.Check(err, qt.ErrorMatches, "controller is under maintenance")
+	}()
+
+	req := message{
+		RequestID: 1,
+		Type:      "Client",
+		Version:   6,
+		Request:   "FullStatus",
+	}
+	data, err := json.Marshal(req)
+	c.Assert(err, qt.IsNil)
+	clientWebsocket.read <- data
+	select {
+	case data := <-controllerWebsocket.write:
+		c.Assert(string(data), qt.JSONEquals, req)
+	case <-time.After(2 * time.Second):
+		c.Fatal("timed out waiting for request")
+	}
+
+	// Draining the connection fails the waiting request with a
+	// retriable error and stops the proxy.
+	close(drain)
+	select {
+	case data := <-clientWebsocket.write:
+		c.Check(string(data), qt.JSONEquals, message{
+			RequestID: 1,
+			Error:     "controller is under maintenance",
+			ErrorCode: string(errors.CodeTryAgain),
+		})
+	case <-time.After(2 * time.Second):
+		c.Fatal("timed out waiting for response")
+	}
+	wg.Wait()
+	select {
+	case <-released:
+	default:
+		c.Error("connection not released")
+	}
+}
+
 type mockLoginService struct {
 	err          error
 	email        string
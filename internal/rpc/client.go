@@ -71,6 +71,7 @@ type Client struct {
 
 	closing bool
 	broken  bool
+	aborted error
 	err     error
 }
 
@@ -98,6 +99,11 @@ func (c *Client) recv() {
 func (c *Client) handleError(err error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if c.aborted != nil {
+		// The connection was torn down deliberately, report why to
+		// any outstanding calls rather than the resulting read error.
+		err = c.aborted
+	}
 	if !c.closing {
 		// We haven't sent a close message yet, so try to send one.
 		cm := websocket.FormatCloseMessage(websocket.CloseProtocolError, err.Error())
@@ -179,6 +185,9 @@ func (c *Client) Call(ctx context.Context, facade string, version int, id, metho
 	if c.err != nil {
 		return c.err
 	}
+	if c.aborted != nil {
+		return c.aborted
+	}
 	c.reqID++
 	// For anyone else as curious as me, one would need to send over
 	// half a million messages per millisecond for a millennium before
@@ -264,6 +273,22 @@ func (c *Client) Close() error {
 	return c.err
 }
 
+// Abort immediately tears down the client connection without waiting for
+// outstanding requests to complete. Outstanding and future calls fail
+// with the given error. Abort is used when the server is unresponsive,
+// when a graceful Close could block indefinitely.
+func (c *Client) Abort(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil || c.aborted != nil {
+		return
+	}
+	c.aborted = err
+	c.closing = true
+	c.broken = true
+	c.conn.Close()
+}
+
 // IsBroken returns true if client has determined that it is no longer able
 // to send messages to the server.
 func (c *Client) IsBroken() bool {
@@ -298,6 +298,146 @@ func TestProxySockets(t *testing.T) {
 	<-errChan // Ensure go routines are cleaned up
 }
 
+func TestProxySocketsCheckRequest(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srvController := newServer(echo)
+
+	errChan := make(chan error)
+	srvJIMM := newServer(func(connClient *websocket.Conn) error {
+		testTokenGen := testTokenGenerator{}
+		f := func(context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			connController, err := srvController.dialer.DialWebsocket(ctx, srvController.URL, nil)
+			c.Check(err, qt.IsNil)
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:      connController,
+				ModelName: "TestName",
+				CheckRequest: func(_ context.Context, facade, method string, _ json.RawMessage) error {
+					if method == "Deny" {
+						return errors.E(errors.CodeModelFrozen, "model is frozen")
+					}
+					return nil
+				},
+			}, nil
+		}
+		auditLogger := func(ale *dbmodel.AuditLogEntry) {}
+		proxyHelpers := rpc.ProxyHelpers{
+			ConnClient:        connClient,
+			TokenGen:          &testTokenGen,
+			ConnectController: f,
+			AuditLog:          auditLogger,
+			LoginService:      &mockLoginService{},
+		}
+		err := rpc.ProxySockets(ctx, proxyHelpers)
+		c.Check(err, qt.IsNil)
+		errChan <- err
+		return err
+	})
+
+	defer srvController.Close()
+	defer srvJIMM.Close()
+	ws, err := srvJIMM.dialer.DialWebsocket(ctx, srvJIMM.URL, nil)
+	c.Assert(err, qt.IsNil)
+	defer ws.Close()
+
+	p := json.RawMessage(`{"Key":"TestVal"}`)
+	err = ws.WriteJSON(&rpc.Message{RequestID: 1, Type: "TestType", Request: "Deny", Params: p})
+	c.Assert(err, qt.IsNil)
+	var resp rpc.Message
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.RequestID, qt.Equals, uint64(1))
+	c.Check(resp.Error, qt.Equals, "model is frozen")
+	c.Check(resp.ErrorCode, qt.Equals, string(errors.CodeModelFrozen))
+
+	err = ws.WriteJSON(&rpc.Message{RequestID: 2, Type: "TestType", Request: "Allow", Params: p})
+	c.Assert(err, qt.IsNil)
+	resp = rpc.Message{}
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.RequestID, qt.Equals, uint64(2))
+	c.Check(resp.Response, qt.DeepEquals, p)
+	ws.Close()
+	<-errChan // Ensure go routines are cleaned up
+}
+
+func TestProxySocketsModifyResponse(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srvController := newServer(echo)
+
+	errChan := make(chan error)
+	srvJIMM := newServer(func(connClient *websocket.Conn) error {
+		testTokenGen := testTokenGenerator{}
+		f := func(context.Context) (rpc.WebsocketConnectionWithMetadata, error) {
+			connController, err := srvController.dialer.DialWebsocket(ctx, srvController.URL, nil)
+			c.Check(err, qt.IsNil)
+			return rpc.WebsocketConnectionWithMetadata{
+				Conn:      connController,
+				ModelName: "TestName",
+				ModifyResponse: func(_ context.Context, facade, method string, response json.RawMessage) (json.RawMessage, error) {
+					switch method {
+					case "Redact":
+						return json.RawMessage(`{"Key":"redacted"}`), nil
+					case "Fail":
+						return nil, errors.E(errors.CodeForbidden, "cannot modify response")
+					}
+					return response, nil
+				},
+			}, nil
+		}
+		auditLogger := func(ale *dbmodel.AuditLogEntry) {}
+		proxyHelpers := rpc.ProxyHelpers{
+			ConnClient:        connClient,
+			TokenGen:          &testTokenGen,
+			ConnectController: f,
+			AuditLog:          auditLogger,
+			LoginService:      &mockLoginService{},
+		}
+		err := rpc.ProxySockets(ctx, proxyHelpers)
+		c.Check(err, qt.IsNil)
+		errChan <- err
+		return err
+	})
+
+	defer srvController.Close()
+	defer srvJIMM.Close()
+	ws, err := srvJIMM.dialer.DialWebsocket(ctx, srvJIMM.URL, nil)
+	c.Assert(err, qt.IsNil)
+	defer ws.Close()
+
+	p := json.RawMessage(`{"Key":"TestVal"}`)
+	err = ws.WriteJSON(&rpc.Message{RequestID: 1, Type: "TestType", Request: "Redact", Params: p})
+	c.Assert(err, qt.IsNil)
+	var resp rpc.Message
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.RequestID, qt.Equals, uint64(1))
+	c.Check(resp.Response, qt.DeepEquals, json.RawMessage(`{"Key":"redacted"}`))
+
+	err = ws.WriteJSON(&rpc.Message{RequestID: 2, Type: "TestType", Request: "Fail", Params: p})
+	c.Assert(err, qt.IsNil)
+	resp = rpc.Message{}
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.RequestID, qt.Equals, uint64(2))
+	c.Check(resp.Error, qt.Equals, "cannot modify response")
+	c.Check(resp.ErrorCode, qt.Equals, string(errors.CodeForbidden))
+	c.Check(resp.Response, qt.HasLen, 0)
+
+	err = ws.WriteJSON(&rpc.Message{RequestID: 3, Type: "TestType", Request: "Keep", Params: p})
+	c.Assert(err, qt.IsNil)
+	resp = rpc.Message{}
+	err = ws.ReadJSON(&resp)
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.RequestID, qt.Equals, uint64(3))
+	c.Check(resp.Response, qt.DeepEquals, p)
+	ws.Close()
+	<-errChan // Ensure go routines are cleaned up
+}
+
 func TestProxySocketsControllerConnectionFails(t *testing.T) {
 	c := qt.New(t)
 	ctx := context.Background()
@@ -549,3 +689,33 @@ func echo(c *websocket.Conn) error {
 		}
 	}
 }
+
+func TestCallAborted(t *testing.T) {
+	c := qt.New(t)
+
+	srv := newServer(func(conn *websocket.Conn) error {
+		// Read requests but never respond, as a hung server would.
+		for {
+			var req map[string]interface{}
+			if err := conn.ReadJSON(&req); err != nil {
+				return err
+			}
+		}
+	})
+	defer srv.Close()
+	conn, err := srv.dialer.Dial(context.Background(), srv.URL, nil)
+	c.Assert(err, qt.IsNil)
+
+	errc := make(chan error)
+	go func() {
+		errc <- conn.Call(context.Background(), "Test", 1, "", "Test", "SUCCESS", nil)
+	}()
+	// Give the call a chance to be sent.
+	time.Sleep(50 * time.Millisecond)
+	conn.Abort(errors.E("connection stuck"))
+	c.Check(<-errc, qt.ErrorMatches, `connection stuck`)
+	c.Check(conn.IsBroken(), qt.IsTrue)
+
+	err = conn.Call(context.Background(), "Test", 1, "", "Test", "SUCCESS", nil)
+	c.Check(err, qt.ErrorMatches, `connection stuck`)
+}
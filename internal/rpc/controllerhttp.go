@@ -0,0 +1,78 @@
+// Copyright 2024 Canonical.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/juju/zaputil"
+	"github.com/juju/zaputil/zapctx"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// ControllerGet makes a GET request for the given path on the
+// controller's HTTP API server, using the controller's TLS and dial
+// configuration. The public address of the controller is tried first,
+// then each reachable controller address in turn until one responds. The
+// caller must close the body of the returned response.
+func ControllerGet(ctx context.Context, ctl *dbmodel.Controller, path string, header http.Header) (*http.Response, error) {
+	const op = errors.Op("rpc.ControllerGet")
+
+	tlsConfig, err := controllerTLSConfig(ctx, ctl)
+	if err != nil {
+		return nil, errors.E(op, err)
+	}
+	dialer := Dialer{TLSConfig: tlsConfig}
+	if err := configureDialer(ctl, &dialer); err != nil {
+		return nil, errors.E(op, err)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: dialer.TLSConfig,
+			Proxy:           dialer.Proxy,
+			DialContext:     dialer.NetDialContext,
+		},
+		Timeout: ctl.DialTimeout,
+	}
+	defer client.CloseIdleConnections()
+
+	var hosts []string
+	if ctl.PublicAddress != "" {
+		hosts = append(hosts, ctl.PublicAddress)
+	}
+	for _, hps := range ctl.Addresses {
+		for _, hp := range hps {
+			if maybeReachable(hp.Scope) {
+				hosts = append(hosts, fmt.Sprintf("%s:%d", hp.Value, hp.Port))
+			}
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, errors.E(op, "no addresses for controller")
+	}
+
+	for _, host := range hosts {
+		u := url.URL{Scheme: "https", Host: host, Path: path}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, errors.E(op, err)
+		}
+		for k, vv := range header {
+			req.Header[k] = vv
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return nil, errors.E(op, ctx.Err())
+		}
+		zapctx.Error(ctx, "failed to reach controller: continue to next addr", zaputil.Error(err))
+	}
+	return nil, errors.E(op, "couldn't reach a valid address for controller")
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Canonical.
+
+package rpc_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/rpc"
+)
+
+func TestControllerGet(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Path+" "+r.Header.Get("X-Test"))
+	}))
+	defer srv.Close()
+	srvURL, err := url.Parse(srv.URL)
+	c.Assert(err, qt.IsNil)
+	caCert := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}))
+	port, err := strconv.Atoi(srvURL.Port())
+	c.Assert(err, qt.IsNil)
+
+	header := http.Header{"X-Test": []string{"value"}}
+	check := func(c *qt.C, ctl *dbmodel.Controller) {
+		resp, err := rpc.ControllerGet(ctx, ctl, "/introspection/metrics", header)
+		c.Assert(err, qt.IsNil)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		c.Assert(err, qt.IsNil)
+		c.Check(string(body), qt.Equals, "/introspection/metrics value")
+	}
+
+	c.Run("public address", func(c *qt.C) {
+		check(c, &dbmodel.Controller{
+			PublicAddress: srvURL.Host,
+			CACertificate: caCert,
+		})
+	})
+
+	c.Run("falls back to addresses", func(c *qt.C) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closedURL, err := url.Parse(closed.URL)
+		c.Assert(err, qt.IsNil)
+		closed.Close()
+		check(c, &dbmodel.Controller{
+			PublicAddress: closedURL.Host,
+			CACertificate: caCert,
+			Addresses: dbmodel.HostPorts{{{
+				Address: jujuparams.Address{Value: srvURL.Hostname(), Type: "ipv4"},
+				Port:    port,
+			}}},
+		})
+	})
+
+	c.Run("untrusted certificate", func(c *qt.C) {
+		_, err := rpc.ControllerGet(ctx, &dbmodel.Controller{PublicAddress: srvURL.Host}, "/introspection/metrics", nil)
+		c.Check(err, qt.ErrorMatches, `couldn't reach a valid address for controller`)
+	})
+
+	c.Run("no addresses", func(c *qt.C) {
+		_, err := rpc.ControllerGet(ctx, &dbmodel.Controller{}, "/introspection/metrics", nil)
+		c.Check(err, qt.ErrorMatches, `no addresses for controller`)
+	})
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Canonical.
+
+package rpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// A FacadeDeprecation marks the versions of a facade that are scheduled
+// for removal.
+type FacadeDeprecation struct {
+	// Facade is the name of the facade.
+	Facade string
+
+	// MaxVersion is the highest deprecated version of the facade, every
+	// version up to and including it is deprecated.
+	MaxVersion int
+
+	// Message is the message sent to clients using a deprecated
+	// version. If this is empty a message naming the first supported
+	// version is used.
+	Message string
+}
+
+// FacadeDeprecations holds the facade versions scheduled for removal.
+type FacadeDeprecations []FacadeDeprecation
+
+// ParseFacadeDeprecations parses a comma separated list of deprecated
+// facade versions of the form <facade>:<max-version>, for example
+// "ModelManager:2,Client:5".
+func ParseFacadeDeprecations(s string) (FacadeDeprecations, error) {
+	var deprecations FacadeDeprecations
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		facade, version, ok := strings.Cut(f, ":")
+		if !ok || facade == "" {
+			return nil, errors.E(fmt.Sprintf("invalid facade deprecation %q", f))
+		}
+		v, err := strconv.Atoi(version)
+		if err != nil || v < 0 {
+			return nil, errors.E(fmt.Sprintf("invalid facade deprecation %q", f))
+		}
+		deprecations = append(deprecations, FacadeDeprecation{Facade: facade, MaxVersion: v})
+	}
+	return deprecations, nil
+}
+
+// Warning returns the warning to send to a client using the given facade
+// version, or nil if the version is not deprecated.
+func (d FacadeDeprecations) Warning(facade string, version int) *apiparams.Warning {
+	for _, fd := range d {
+		if fd.Facade != facade || version > fd.MaxVersion {
+			continue
+		}
+		msg := fd.Message
+		if msg == "" {
+			msg = fmt.Sprintf("%s facade version %d is deprecated and will be removed, version %d or later should be used", facade, version, fd.MaxVersion+1)
+		}
+		return &apiparams.Warning{
+			Code:    apiparams.WarningDeprecatedFacade,
+			Message: msg,
+			Facade:  facade,
+			Version: version,
+		}
+	}
+	return nil
+}
+
+// RecordRequest records a request using the given facade version in the
+// facade usage metrics and returns the warning to send to the client, if
+// any.
+func (d FacadeDeprecations) RecordRequest(facade string, version int) *apiparams.Warning {
+	w := d.Warning(facade, version)
+	servermon.FacadeRequestCount.WithLabelValues(facade, strconv.Itoa(version), strconv.FormatBool(w != nil)).Inc()
+	return w
+}
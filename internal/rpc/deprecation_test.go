@@ -0,0 +1,67 @@
+// Copyright 2024 Canonical.
+
+package rpc_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/rpc"
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestParseFacadeDeprecations(t *testing.T) {
+	c := qt.New(t)
+
+	d, err := rpc.ParseFacadeDeprecations("")
+	c.Assert(err, qt.IsNil)
+	c.Check(d, qt.HasLen, 0)
+
+	d, err = rpc.ParseFacadeDeprecations("ModelManager:2, Client:5")
+	c.Assert(err, qt.IsNil)
+	c.Check(d, qt.DeepEquals, rpc.FacadeDeprecations{{
+		Facade:     "ModelManager",
+		MaxVersion: 2,
+	}, {
+		Facade:     "Client",
+		MaxVersion: 5,
+	}})
+
+	_, err = rpc.ParseFacadeDeprecations("ModelManager")
+	c.Check(err, qt.ErrorMatches, `invalid facade deprecation "ModelManager"`)
+
+	_, err = rpc.ParseFacadeDeprecations("ModelManager:two")
+	c.Check(err, qt.ErrorMatches, `invalid facade deprecation "ModelManager:two"`)
+}
+
+func TestFacadeDeprecationsWarning(t *testing.T) {
+	c := qt.New(t)
+
+	d := rpc.FacadeDeprecations{{
+		Facade:     "ModelManager",
+		MaxVersion: 2,
+	}, {
+		Facade:     "Client",
+		MaxVersion: 5,
+		Message:    "upgrade your client",
+	}}
+
+	c.Check(d.Warning("ModelManager", 1), qt.DeepEquals, &apiparams.Warning{
+		Code:    apiparams.WarningDeprecatedFacade,
+		Message: "ModelManager facade version 1 is deprecated and will be removed, version 3 or later should be used",
+		Facade:  "ModelManager",
+		Version: 1,
+	})
+	c.Check(d.Warning("ModelManager", 3), qt.IsNil)
+	c.Check(d.Warning("Client", 5), qt.DeepEquals, &apiparams.Warning{
+		Code:    apiparams.WarningDeprecatedFacade,
+		Message: "upgrade your client",
+		Facade:  "Client",
+		Version: 5,
+	})
+	c.Check(d.Warning("Application", 1), qt.IsNil)
+
+	var none rpc.FacadeDeprecations
+	c.Check(none.Warning("ModelManager", 1), qt.IsNil)
+}
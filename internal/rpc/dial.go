@@ -5,12 +5,14 @@ package rpc
 import (
 	"context"
 	"crypto/tls"
-	"crypto/x509"
+	"database/sql"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/juju/juju/core/network"
@@ -27,6 +29,17 @@ import (
 type Dialer struct {
 	// TLSConfig is used to configure TLS for the client connection.
 	TLSConfig *tls.Config
+
+	// Proxy, if set, returns the proxy to use when dialing a request.
+	Proxy func(*http.Request) (*url.URL, error)
+
+	// NetDialContext, if set, is used to create the underlying network
+	// connections.
+	NetDialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// Timeout, if non-zero, is the maximum time allowed to establish the
+	// websocket connection.
+	Timeout time.Duration
 }
 
 // Dial establishes a new client RPC connection to the given URL.
@@ -44,8 +57,16 @@ func (d Dialer) DialWebsocket(ctx context.Context, url string, headers http.Head
 
 	dialer := websocket.Dialer{
 		TLSClientConfig: d.TLSConfig,
+		Proxy:           d.Proxy,
+		NetDialContext:  d.NetDialContext,
 	}
-	conn, resp, err := dialer.DialContext(context.Background(), url, headers)
+	dialCtx := context.Background()
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(dialCtx, d.Timeout)
+		defer cancel()
+	}
+	conn, resp, err := dialer.DialContext(dialCtx, url, headers)
 	if err != nil {
 		zapctx.Error(ctx, "BasicDial failed", zap.Error(err))
 		return nil, errors.E(op, err)
@@ -57,22 +78,30 @@ func (d Dialer) DialWebsocket(ctx context.Context, url string, headers http.Head
 // Dial connects to the controller/model and returns a raw websocket
 // that can be used as is.
 // It accepts the endpoints to dial, normally /api or /commands.
+// On success the CertificateExpiry of the given controller is updated
+// from the certificate presented by the controller.
 func Dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, finalPath string, headers http.Header) (*websocket.Conn, error) {
-	var tlsConfig *tls.Config
-	if ctl.CACertificate != "" {
-		cp := x509.NewCertPool()
-		ok := cp.AppendCertsFromPEM([]byte(ctl.CACertificate))
-		if !ok {
-			zapctx.Warn(ctx, "no CA certificates added")
-		}
-		tlsConfig = &tls.Config{
-			RootCAs:    cp,
-			ServerName: ctl.TLSHostname,
-			MinVersion: tls.VersionTLS12,
-		}
+	conn, err := dial(ctx, ctl, modelTag, finalPath, headers)
+	if err != nil {
+		return nil, err
+	}
+	if expiry, ok := certificateExpiry(conn); ok {
+		ctl.CertificateExpiry = sql.NullTime{Time: expiry, Valid: true}
+	}
+	return conn, nil
+}
+
+func dial(ctx context.Context, ctl *dbmodel.Controller, modelTag names.ModelTag, finalPath string, headers http.Header) (*websocket.Conn, error) {
+	tlsConfig, err := controllerTLSConfig(ctx, ctl)
+	if err != nil {
+		return nil, err
 	}
 	dialer := Dialer{
 		TLSConfig: tlsConfig,
+		Timeout:   ctl.DialTimeout,
+	}
+	if err := configureDialer(ctl, &dialer); err != nil {
+		return nil, err
 	}
 
 	if ctl.PublicAddress != "" {
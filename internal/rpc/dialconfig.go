@@ -0,0 +1,178 @@
+// Copyright 2024 Canonical.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// sshHandshakeTimeout is the maximum time allowed to connect and
+// authenticate to an SSH jump host.
+const sshHandshakeTimeout = 30 * time.Second
+
+// ValidateDialConfig checks that the outbound dial configuration for a
+// controller is usable. The proxyURL must be empty or use one of the
+// socks5, socks5h or http schemes. The jumpHost must be empty or have
+// the form user@host[:port], in which case jumpHostKey must contain the
+// jump host's public key in authorized_keys format. A proxy and a jump
+// host cannot be used together.
+func ValidateDialConfig(proxyURL, jumpHost, jumpHostKey string) error {
+	if proxyURL != "" && jumpHost != "" {
+		return errors.E(errors.CodeBadRequest, "cannot use both a proxy and an SSH jump host")
+	}
+	if proxyURL != "" {
+		if _, err := parseProxyURL(proxyURL); err != nil {
+			return err
+		}
+	}
+	if jumpHost != "" {
+		if _, _, err := parseSSHJumpHost(jumpHost); err != nil {
+			return err
+		}
+		if _, err := parseSSHHostKey(jumpHostKey); err != nil {
+			return err
+		}
+	} else if jumpHostKey != "" {
+		return errors.E(errors.CodeBadRequest, "SSH jump host key specified without an SSH jump host")
+	}
+	return nil
+}
+
+// configureDialer sets up the given dialer to use any proxy or SSH jump
+// host configured for the controller.
+func configureDialer(ctl *dbmodel.Controller, d *Dialer) error {
+	if ctl.ProxyURL != "" {
+		u, err := parseProxyURL(ctl.ProxyURL)
+		if err != nil {
+			return err
+		}
+		d.Proxy = http.ProxyURL(u)
+	}
+	if ctl.SSHJumpHost != "" {
+		dial, err := sshJumpHostDialer(ctl.SSHJumpHost, ctl.SSHJumpHostKey)
+		if err != nil {
+			return err
+		}
+		d.NetDialContext = dial
+	}
+	return nil
+}
+
+func parseProxyURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid proxy URL %q", s), err)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h", "http":
+	default:
+		return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("unsupported proxy scheme %q", u.Scheme))
+	}
+	if u.Host == "" {
+		return nil, errors.E(errors.CodeBadRequest, fmt.Sprintf("proxy URL %q has no host", s))
+	}
+	return u, nil
+}
+
+// parseSSHJumpHost splits a jump host of the form user@host[:port] into
+// the user and the address to dial, defaulting the port to 22.
+func parseSSHJumpHost(s string) (user, addr string, err error) {
+	user, hostport, ok := strings.Cut(s, "@")
+	if !ok || user == "" || hostport == "" {
+		return "", "", errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid SSH jump host %q, expected user@host[:port]", s))
+	}
+	if _, _, err := net.SplitHostPort(hostport); err != nil {
+		hostport = net.JoinHostPort(hostport, "22")
+	}
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil || host == "" {
+		return "", "", errors.E(errors.CodeBadRequest, fmt.Sprintf("invalid SSH jump host %q, expected user@host[:port]", s))
+	}
+	return user, hostport, nil
+}
+
+func parseSSHHostKey(s string) (ssh.PublicKey, error) {
+	if s == "" {
+		return nil, errors.E(errors.CodeBadRequest, "SSH jump host key not specified")
+	}
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(s))
+	if err != nil {
+		return nil, errors.E(errors.CodeBadRequest, "invalid SSH jump host key", err)
+	}
+	return key, nil
+}
+
+// sshJumpHostDialer returns a function that dials addresses by tunnelling
+// through the given SSH jump host. JIMM authenticates to the jump host
+// using the keys held by the SSH agent at SSH_AUTH_SOCK and only accepts
+// the given host key.
+func sshJumpHostDialer(jumpHost, jumpHostKey string) (func(ctx context.Context, network, addr string) (net.Conn, error), error) {
+	user, jumpAddr, err := parseSSHJumpHost(jumpHost)
+	if err != nil {
+		return nil, err
+	}
+	hostKey, err := parseSSHHostKey(jumpHostKey)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		const op = errors.Op("rpc.DialSSHJumpHost")
+
+		agentConn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+		if err != nil {
+			return nil, errors.E(op, errors.CodeConnectionFailed, "cannot connect to SSH agent", err)
+		}
+		defer agentConn.Close()
+
+		config := &ssh.ClientConfig{
+			User:            user,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers)},
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+			Timeout:         sshHandshakeTimeout,
+		}
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", jumpAddr)
+		if err != nil {
+			return nil, errors.E(op, errors.CodeConnectionFailed, err)
+		}
+		sshConn, chans, reqs, err := ssh.NewClientConn(conn, jumpAddr, config)
+		if err != nil {
+			conn.Close()
+			return nil, errors.E(op, errors.CodeConnectionFailed, err)
+		}
+		client := ssh.NewClient(sshConn, chans, reqs)
+		tunnel, err := client.DialContext(ctx, network, addr)
+		if err != nil {
+			client.Close()
+			return nil, errors.E(op, errors.CodeConnectionFailed, err)
+		}
+		return &sshTunnelConn{Conn: tunnel, client: client}, nil
+	}, nil
+}
+
+// An sshTunnelConn is a connection tunnelled through an SSH client. The
+// SSH client is closed along with the connection.
+type sshTunnelConn struct {
+	net.Conn
+	client *ssh.Client
+}
+
+// Close implements net.Conn.
+func (c *sshTunnelConn) Close() error {
+	err := c.Conn.Close()
+	c.client.Close()
+	return err
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Canonical.
+
+package rpc_test
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/rpc"
+)
+
+const testHostKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIFJ/9eKFZ9os4QG7VDHPpDJzlI29l00CwIZqq+kciFQ4 jump-host"
+
+func TestValidateDialConfig(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		about         string
+		proxyURL      string
+		jumpHost      string
+		jumpHostKey   string
+		expectedError string
+	}{{
+		about: "no dial configuration",
+	}, {
+		about:    "SOCKS5 proxy",
+		proxyURL: "socks5://proxy.example.com:1080",
+	}, {
+		about:    "HTTP CONNECT proxy",
+		proxyURL: "http://proxy.example.com:3128",
+	}, {
+		about:         "unsupported proxy scheme",
+		proxyURL:      "ftp://proxy.example.com",
+		expectedError: `unsupported proxy scheme "ftp"`,
+	}, {
+		about:         "proxy without host",
+		proxyURL:      "socks5://",
+		expectedError: `proxy URL "socks5://" has no host`,
+	}, {
+		about:       "SSH jump host",
+		jumpHost:    "jimm@bastion.example.com",
+		jumpHostKey: testHostKey,
+	}, {
+		about:       "SSH jump host with port",
+		jumpHost:    "jimm@bastion.example.com:2222",
+		jumpHostKey: testHostKey,
+	}, {
+		about:         "SSH jump host without user",
+		jumpHost:      "bastion.example.com",
+		jumpHostKey:   testHostKey,
+		expectedError: `invalid SSH jump host "bastion.example.com", expected user@host\[:port\]`,
+	}, {
+		about:         "SSH jump host without key",
+		jumpHost:      "jimm@bastion.example.com",
+		expectedError: `SSH jump host key not specified`,
+	}, {
+		about:         "invalid SSH jump host key",
+		jumpHost:      "jimm@bastion.example.com",
+		jumpHostKey:   "not-a-key",
+		expectedError: `invalid SSH jump host key`,
+	}, {
+		about:         "SSH jump host key without jump host",
+		jumpHostKey:   testHostKey,
+		expectedError: `SSH jump host key specified without an SSH jump host`,
+	}, {
+		about:         "proxy and SSH jump host",
+		proxyURL:      "socks5://proxy.example.com:1080",
+		jumpHost:      "jimm@bastion.example.com",
+		jumpHostKey:   testHostKey,
+		expectedError: `cannot use both a proxy and an SSH jump host`,
+	}}
+
+	for _, test := range tests {
+		test := test
+		c.Run(test.about, func(c *qt.C) {
+			err := rpc.ValidateDialConfig(test.proxyURL, test.jumpHost, test.jumpHostKey)
+			if test.expectedError == "" {
+				c.Assert(err, qt.IsNil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestDialThroughProxy(t *testing.T) {
+	c := qt.New(t)
+
+	srv := newServer(echo)
+	defer srv.Close()
+
+	var connects atomic.Int32
+	proxy := httptest.NewServer(connectProxy(&connects))
+	defer proxy.Close()
+
+	ctl := dbmodel.Controller{
+		PublicAddress: strings.TrimPrefix(srv.Server.URL, "https://"),
+		CACertificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})),
+		ProxyURL:      proxy.URL,
+	}
+	conn, err := rpc.Dial(context.Background(), &ctl, names.ModelTag{}, "", nil)
+	c.Assert(err, qt.IsNil)
+	defer conn.Close()
+	c.Check(connects.Load(), qt.Equals, int32(1))
+}
+
+// connectProxy returns a handler implementing a minimal HTTP CONNECT
+// proxy that counts the tunnels it establishes.
+func connectProxy(connects *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodConnect {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", req.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		connects.Add(1)
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n")); err != nil {
+			return
+		}
+		go func() {
+			_, _ = io.Copy(upstream, buf)
+		}()
+		_, _ = io.Copy(conn, upstream)
+	})
+}
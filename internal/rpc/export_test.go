@@ -2,3 +2,5 @@
 package rpc
 
 type Message message
+
+var EarliestExpiry = earliestExpiry
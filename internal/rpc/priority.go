@@ -0,0 +1,264 @@
+// Copyright 2024 Canonical.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/servermon"
+)
+
+// A RequestClass is the priority class of an API request.
+type RequestClass string
+
+const (
+	// RequestClassInteractive is the class of requests made by users
+	// with the juju and jimmctl clients. It is the class of any
+	// request that is not otherwise classified.
+	RequestClassInteractive RequestClass = "interactive"
+
+	// RequestClassPolling is the class of requests made by dashboards
+	// polling for changes.
+	RequestClassPolling RequestClass = "polling"
+
+	// RequestClassAutomation is the class of requests made by service
+	// accounts, which are typically bulk automated operations.
+	RequestClassAutomation RequestClass = "automation"
+)
+
+// requestClasses holds the request classes in order of priority, it is
+// used to break ties between classes.
+var requestClasses = []RequestClass{RequestClassInteractive, RequestClassPolling, RequestClassAutomation}
+
+// DefaultRequestWeights holds the weights used for request classes that
+// are not given a weight.
+var DefaultRequestWeights = map[RequestClass]int{
+	RequestClassInteractive: 8,
+	RequestClassPolling:     2,
+	RequestClassAutomation:  1,
+}
+
+// ParseRequestWeights parses a comma separated list of request class
+// weights of the form <class>:<weight>, for example
+// "interactive:8,polling:2,automation:1".
+func ParseRequestWeights(s string) (map[RequestClass]int, error) {
+	weights := make(map[RequestClass]int)
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		class, weight, ok := strings.Cut(f, ":")
+		if !ok || !validRequestClass(RequestClass(class)) {
+			return nil, errors.E(fmt.Sprintf("invalid request weight %q", f))
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil || w <= 0 {
+			return nil, errors.E(fmt.Sprintf("invalid request weight %q", f))
+		}
+		weights[RequestClass(class)] = w
+	}
+	return weights, nil
+}
+
+func validRequestClass(class RequestClass) bool {
+	for _, c := range requestClasses {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// RequestLimited returns whether requests to the given facade method
+// are subject to the request concurrency limit. Logins, pings and
+// watcher calls, which can block until there is a change, are not
+// limited.
+func RequestLimited(facade, method string) bool {
+	switch {
+	case facade == "Admin", facade == "Pinger":
+		return false
+	case strings.HasSuffix(facade, "Watcher"):
+		return false
+	default:
+		return true
+	}
+}
+
+// A RequestLimiter limits the number of API requests processed
+// concurrently. When the limit is reached requests wait in a queue per
+// class and are admitted using weighted fair queuing, so that, under
+// load, each class receives a share of the capacity in proportion to
+// its weight. A nil RequestLimiter does not limit requests.
+type RequestLimiter struct {
+	limit   int
+	weights map[RequestClass]int
+
+	mu sync.Mutex
+	// active is the number of requests currently admitted.
+	active int
+	// vtime is the virtual finish time of the last admitted request.
+	vtime float64
+	// queues holds the waiting requests for each class.
+	queues map[RequestClass]*requestQueue
+}
+
+// A requestQueue holds the requests of a class waiting to be admitted.
+type requestQueue struct {
+	waiters []*requestWaiter
+	// vtime is the virtual finish time of the last request queued.
+	vtime float64
+}
+
+// A requestWaiter is a request waiting to be admitted.
+type requestWaiter struct {
+	vtime    float64
+	ready    chan struct{}
+	admitted bool
+}
+
+// NewRequestLimiter returns a RequestLimiter that admits at most limit
+// concurrent requests, sharing capacity between the classes using the
+// given weights. Classes without a weight use the weight in
+// DefaultRequestWeights. If limit is not positive NewRequestLimiter
+// returns nil.
+func NewRequestLimiter(limit int, weights map[RequestClass]int) *RequestLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	l := RequestLimiter{
+		limit:   limit,
+		weights: make(map[RequestClass]int),
+		queues:  make(map[RequestClass]*requestQueue),
+	}
+	for _, class := range requestClasses {
+		w := weights[class]
+		if w <= 0 {
+			w = DefaultRequestWeights[class]
+		}
+		l.weights[class] = w
+		l.queues[class] = new(requestQueue)
+	}
+	return &l
+}
+
+// Acquire waits until a request of the given class may be processed
+// and returns a function that must be called once the request
+// completes. Requests with an unknown, or empty, class are
+// interactive. If the context is canceled while waiting an error is
+// returned.
+func (l *RequestLimiter) Acquire(ctx context.Context, class RequestClass) (func(), error) {
+	const op = errors.Op("rpc.Acquire")
+	if l == nil {
+		return func() {}, nil
+	}
+	if _, ok := l.queues[class]; !ok {
+		class = RequestClassInteractive
+	}
+
+	l.mu.Lock()
+	if l.active < l.limit && l.queued() == 0 {
+		l.active++
+		l.mu.Unlock()
+		return l.releaseFunc(), nil
+	}
+	q := l.queues[class]
+	w := requestWaiter{
+		vtime: max(l.vtime, q.vtime) + 1/float64(l.weights[class]),
+		ready: make(chan struct{}),
+	}
+	q.vtime = w.vtime
+	q.waiters = append(q.waiters, &w)
+	l.mu.Unlock()
+
+	servermon.QueuedRequests.WithLabelValues(string(class)).Inc()
+	defer servermon.QueuedRequests.WithLabelValues(string(class)).Dec()
+	start := time.Now()
+	defer func() {
+		servermon.RequestQueueDuration.WithLabelValues(string(class)).Observe(time.Since(start).Seconds())
+	}()
+
+	select {
+	case <-w.ready:
+		return l.releaseFunc(), nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w.admitted {
+		// The request was admitted at the same time as the context
+		// was canceled, give the capacity to the next request.
+		l.active--
+		l.admit()
+	} else {
+		q.remove(&w)
+	}
+	return nil, errors.E(op, errors.CodeTryAgain, "request canceled waiting for capacity", ctx.Err())
+}
+
+// releaseFunc returns a function that releases the capacity held by an
+// admitted request. Calling the function more than once has no further
+// effect.
+func (l *RequestLimiter) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.active--
+			l.admit()
+		})
+	}
+}
+
+// admit admits waiting requests, in order of their virtual finish time,
+// until the limit is reached. l.mu must be held.
+func (l *RequestLimiter) admit() {
+	for l.active < l.limit {
+		var next *requestQueue
+		for _, class := range requestClasses {
+			q := l.queues[class]
+			if len(q.waiters) == 0 {
+				continue
+			}
+			if next == nil || q.waiters[0].vtime < next.waiters[0].vtime {
+				next = q
+			}
+		}
+		if next == nil {
+			return
+		}
+		w := next.waiters[0]
+		next.waiters = next.waiters[1:]
+		w.admitted = true
+		close(w.ready)
+		l.vtime = w.vtime
+		l.active++
+	}
+}
+
+// queued returns the number of waiting requests. l.mu must be held.
+func (l *RequestLimiter) queued() int {
+	n := 0
+	for _, q := range l.queues {
+		n += len(q.waiters)
+	}
+	return n
+}
+
+// remove removes the given waiter from the queue.
+func (q *requestQueue) remove(w *requestWaiter) {
+	for i, qw := range q.waiters {
+		if qw == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Canonical.
+
+package rpc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/internal/errors"
+	"github.com/canonical/jimm/v3/internal/rpc"
+)
+
+func TestParseRequestWeights(t *testing.T) {
+	c := qt.New(t)
+
+	w, err := rpc.ParseRequestWeights("")
+	c.Assert(err, qt.IsNil)
+	c.Check(w, qt.HasLen, 0)
+
+	w, err = rpc.ParseRequestWeights("interactive:10, automation:2")
+	c.Assert(err, qt.IsNil)
+	c.Check(w, qt.DeepEquals, map[rpc.RequestClass]int{
+		rpc.RequestClassInteractive: 10,
+		rpc.RequestClassAutomation:  2,
+	})
+
+	_, err = rpc.ParseRequestWeights("batch:1")
+	c.Check(err, qt.ErrorMatches, `invalid request weight "batch:1"`)
+
+	_, err = rpc.ParseRequestWeights("polling:0")
+	c.Check(err, qt.ErrorMatches, `invalid request weight "polling:0"`)
+}
+
+func TestRequestLimited(t *testing.T) {
+	c := qt.New(t)
+
+	c.Check(rpc.RequestLimited("Client", "FullStatus"), qt.IsTrue)
+	c.Check(rpc.RequestLimited("Admin", "LoginWithSessionToken"), qt.IsFalse)
+	c.Check(rpc.RequestLimited("Pinger", "Ping"), qt.IsFalse)
+	c.Check(rpc.RequestLimited("AllWatcher", "Next"), qt.IsFalse)
+}
+
+func TestNilRequestLimiter(t *testing.T) {
+	c := qt.New(t)
+
+	l := rpc.NewRequestLimiter(0, nil)
+	c.Assert(l, qt.IsNil)
+	release, err := l.Acquire(context.Background(), rpc.RequestClassAutomation)
+	c.Assert(err, qt.IsNil)
+	release()
+}
+
+func TestRequestLimiterWeightedFairQueuing(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	l := rpc.NewRequestLimiter(1, map[rpc.RequestClass]int{
+		rpc.RequestClassInteractive: 2,
+		rpc.RequestClassAutomation:  1,
+	})
+	release, err := l.Acquire(ctx, rpc.RequestClassAutomation)
+	c.Assert(err, qt.IsNil)
+
+	admitted := make(chan rpc.RequestClass)
+	queue := func(class rpc.RequestClass) {
+		go func() {
+			release, err := l.Acquire(ctx, class)
+			if err != nil {
+				return
+			}
+			admitted <- class
+			release()
+		}()
+		// Wait for the request to be queued so that the order of
+		// arrival is known.
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i := 0; i < 3; i++ {
+		queue(rpc.RequestClassAutomation)
+	}
+	for i := 0; i < 4; i++ {
+		queue(rpc.RequestClassInteractive)
+	}
+
+	release()
+	var order []rpc.RequestClass
+	for i := 0; i < 7; i++ {
+		order = append(order, <-admitted)
+	}
+	// Interactive requests, with twice the weight, receive twice the
+	// share of capacity even though the automation requests arrived
+	// first.
+	c.Check(order, qt.DeepEquals, []rpc.RequestClass{
+		rpc.RequestClassInteractive,
+		rpc.RequestClassInteractive,
+		rpc.RequestClassAutomation,
+		rpc.RequestClassInteractive,
+		rpc.RequestClassInteractive,
+		rpc.RequestClassAutomation,
+		rpc.RequestClassAutomation,
+	})
+}
+
+func TestRequestLimiterCanceled(t *testing.T) {
+	c := qt.New(t)
+
+	l := rpc.NewRequestLimiter(1, nil)
+	release, err := l.Acquire(context.Background(), rpc.RequestClassInteractive)
+	c.Assert(err, qt.IsNil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx, rpc.RequestClassPolling)
+	c.Check(errors.ErrorCode(err), qt.Equals, errors.CodeTryAgain)
+
+	release()
+	release, err = l.Acquire(context.Background(), rpc.RequestClassPolling)
+	c.Assert(err, qt.IsNil)
+	release()
+}
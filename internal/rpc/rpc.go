@@ -10,6 +10,8 @@ package rpc
 import (
 	"encoding/json"
 	"time"
+
+	apiparams "github.com/canonical/jimm/v3/pkg/api/params"
 )
 
 // A message encodes a single message sent, or received, over an RPC
@@ -27,6 +29,14 @@ type message struct {
 	ErrorCode string                 `json:"error-code,omitempty"`
 	ErrorInfo map[string]interface{} `json:"error-info,omitempty"`
 	Response  json.RawMessage        `json:"response,omitempty"`
+	Warnings  []apiparams.Warning    `json:"warnings,omitempty"`
+
+	// warning holds the warning to attach to the response to a request.
+	warning *apiparams.Warning
+
+	// release releases the capacity held by the request in the request
+	// limiter, if any.
+	release func()
 }
 
 // isRequest returns whether the message is a request
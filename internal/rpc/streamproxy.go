@@ -15,9 +15,16 @@ import (
 // returned and then close both connections before waiting
 // on the second connection to ensure it is cleaned up.
 func ProxyStreams(ctx context.Context, src, dst base.Stream) {
+	ProxyFilteredStreams(ctx, src, dst, nil)
+}
+
+// ProxyFilteredStreams is like ProxyStreams except that messages read
+// from dst are only written to src if filter returns true for them. If
+// filter is nil every message is written.
+func ProxyFilteredStreams(ctx context.Context, src, dst base.Stream, filter func(map[string]any) bool) {
 	errChan := make(chan error, 2)
-	go func() { errChan <- proxy(src, dst) }()
-	go func() { errChan <- proxy(dst, src) }()
+	go func() { errChan <- proxy(src, dst, nil) }()
+	go func() { errChan <- proxy(dst, src, filter) }()
 	firstErr := <-errChan
 	if firstErr != nil {
 		zapctx.Error(ctx, "error from stream proxy", zap.Error(firstErr))
@@ -30,7 +37,7 @@ func ProxyStreams(ctx context.Context, src, dst base.Stream) {
 	}
 }
 
-func proxy(src base.Stream, dst base.Stream) error {
+func proxy(src base.Stream, dst base.Stream, filter func(map[string]any) bool) error {
 	for {
 		var data map[string]any
 		err := src.ReadJSON(&data)
@@ -40,6 +47,9 @@ func proxy(src base.Stream, dst base.Stream) error {
 			}
 			return nil
 		}
+		if filter != nil && !filter(data) {
+			continue
+		}
 		err = dst.WriteJSON(data)
 		if err != nil {
 			return err
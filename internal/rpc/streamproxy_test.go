@@ -105,3 +105,36 @@ func TestStreamProxyStoppedController(t *testing.T) {
 	ws.Close()
 	<-doneChan // Ensure go routines are cleaned up
 }
+
+func TestFilteredStreamProxy(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	doneChan := make(chan error)
+	stopped := false
+	srvController := newServer(func(c *websocket.Conn) error { return streamEcho(c, &stopped) })
+	srvJIMM := newServer(func(connClient *websocket.Conn) error {
+		connController, err := srvController.dialer.DialWebsocket(ctx, srvController.URL, nil)
+		c.Assert(err, qt.IsNil)
+		rpc.ProxyFilteredStreams(ctx, connClient, connController, func(msg map[string]any) bool {
+			return msg["Key"] != "Dropped"
+		})
+		doneChan <- nil
+		return nil
+	})
+	defer srvController.Close()
+	defer srvJIMM.Close()
+	ws, err := srvJIMM.dialer.DialWebsocket(ctx, srvJIMM.URL, nil)
+	c.Assert(err, qt.IsNil)
+	defer ws.Close()
+
+	// The dropped message is echoed by the controller but not passed
+	// back to the client, so the next message read is the echo of the
+	// following message.
+	err = ws.WriteJSON(json.RawMessage(`{"Key":"Dropped"}`))
+	c.Assert(err, qt.IsNil)
+	verifyEcho(c, ws, "")
+
+	ws.Close()
+	<-doneChan // Ensure go routines are cleaned up
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Canonical.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/zaputil/zapctx"
+	"go.uber.org/zap"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/errors"
+)
+
+// tlsVersions maps the supported values of a controller's TLS minimum
+// version to the corresponding TLS version.
+var tlsVersions = map[string]uint16{
+	"":    tls.VersionTLS12,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ValidateTLSConfig checks that the TLS configuration for a controller
+// is usable. The minVersion must be empty, "1.2" or "1.3". The system CA
+// fallback can only be enabled when a CA certificate is specified.
+func ValidateTLSConfig(caCertificate, minVersion string, systemCAFallback bool) error {
+	if _, ok := tlsVersions[minVersion]; !ok {
+		return errors.E(errors.CodeBadRequest, fmt.Sprintf("unsupported TLS version %q", minVersion))
+	}
+	if systemCAFallback && caCertificate == "" {
+		return errors.E(errors.CodeBadRequest, "system CA fallback specified without a CA certificate")
+	}
+	return nil
+}
+
+// controllerTLSConfig returns the TLS configuration to use when dialing
+// the given controller. If the controller has a CA certificate then only
+// certificates signed by that CA are accepted, unless the controller
+// allows falling back to the system CAs.
+func controllerTLSConfig(ctx context.Context, ctl *dbmodel.Controller) (*tls.Config, error) {
+	minVersion, ok := tlsVersions[ctl.TLSMinVersion]
+	if !ok {
+		return nil, errors.E(errors.CodeServerConfiguration, fmt.Sprintf("unsupported TLS version %q", ctl.TLSMinVersion))
+	}
+	config := &tls.Config{
+		ServerName: ctl.TLSHostname,
+		MinVersion: minVersion,
+	}
+	if ctl.CACertificate == "" {
+		return config, nil
+	}
+	cp := x509.NewCertPool()
+	if ctl.TLSSystemCAFallback {
+		var err error
+		cp, err = x509.SystemCertPool()
+		if err != nil {
+			zapctx.Warn(ctx, "cannot load system CA certificates", zap.Error(err))
+			cp = x509.NewCertPool()
+		}
+	}
+	if !cp.AppendCertsFromPEM([]byte(ctl.CACertificate)) {
+		zapctx.Warn(ctx, "no CA certificates added")
+	}
+	config.RootCAs = cp
+	return config, nil
+}
+
+// certificateExpiry returns the earliest expiry time of the certificates
+// in the chain presented by the server on the other end of the given
+// connection, including the CA certificate the chain was verified
+// against. If the connection does not use TLS false is returned.
+func certificateExpiry(conn *websocket.Conn) (time.Time, bool) {
+	tlsConn, ok := conn.UnderlyingConn().(*tls.Conn)
+	if !ok {
+		return time.Time{}, false
+	}
+	state := tlsConn.ConnectionState()
+	certs := state.PeerCertificates
+	for _, chain := range state.VerifiedChains {
+		certs = append(certs, chain...)
+	}
+	return earliestExpiry(certs)
+}
+
+// earliestExpiry returns the earliest expiry time of the given
+// certificates. If there are no certificates false is returned.
+func earliestExpiry(certs []*x509.Certificate) (time.Time, bool) {
+	var expiry time.Time
+	for _, cert := range certs {
+		if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+			expiry = cert.NotAfter
+		}
+	}
+	return expiry, !expiry.IsZero()
+}
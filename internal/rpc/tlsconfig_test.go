@@ -0,0 +1,116 @@
+// Copyright 2024 Canonical.
+
+package rpc_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/internal/dbmodel"
+	"github.com/canonical/jimm/v3/internal/rpc"
+)
+
+func TestValidateTLSConfig(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		about            string
+		caCertificate    string
+		minVersion       string
+		systemCAFallback bool
+		expectedError    string
+	}{{
+		about: "default configuration",
+	}, {
+		about:      "TLS 1.3",
+		minVersion: "1.3",
+	}, {
+		about:         "TLS 1.2 with CA certificate",
+		caCertificate: "ca-cert",
+		minVersion:    "1.2",
+	}, {
+		about:         "unsupported TLS version",
+		minVersion:    "1.1",
+		expectedError: `unsupported TLS version "1.1"`,
+	}, {
+		about:            "system CA fallback",
+		caCertificate:    "ca-cert",
+		systemCAFallback: true,
+	}, {
+		about:            "system CA fallback without CA certificate",
+		systemCAFallback: true,
+		expectedError:    `system CA fallback specified without a CA certificate`,
+	}}
+
+	for _, test := range tests {
+		test := test
+		c.Run(test.about, func(c *qt.C) {
+			err := rpc.ValidateTLSConfig(test.caCertificate, test.minVersion, test.systemCAFallback)
+			if test.expectedError == "" {
+				c.Assert(err, qt.IsNil)
+			} else {
+				c.Assert(err, qt.ErrorMatches, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestDialRecordsCertificateExpiry(t *testing.T) {
+	c := qt.New(t)
+
+	srv := newServer(echo)
+	defer srv.Close()
+
+	ctl := dbmodel.Controller{
+		PublicAddress: strings.TrimPrefix(srv.Server.URL, "https://"),
+		CACertificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})),
+		TLSMinVersion: "1.2",
+	}
+	conn, err := rpc.Dial(context.Background(), &ctl, names.ModelTag{}, "", nil)
+	c.Assert(err, qt.IsNil)
+	defer conn.Close()
+	c.Assert(ctl.CertificateExpiry.Valid, qt.IsTrue)
+	c.Check(ctl.CertificateExpiry.Time.Equal(srv.Certificate().NotAfter), qt.IsTrue)
+}
+
+func TestEarliestExpiry(t *testing.T) {
+	c := qt.New(t)
+
+	now := time.Now()
+	_, ok := rpc.EarliestExpiry(nil)
+	c.Check(ok, qt.IsFalse)
+	expiry, ok := rpc.EarliestExpiry([]*x509.Certificate{
+		{NotAfter: now.Add(90 * 24 * time.Hour)},
+		{NotAfter: now.Add(10 * 24 * time.Hour)},
+		{NotAfter: now.Add(365 * 24 * time.Hour)},
+	})
+	c.Check(ok, qt.IsTrue)
+	c.Check(expiry.Equal(now.Add(10*24*time.Hour)), qt.IsTrue)
+}
+
+func TestDialTLSMinVersion(t *testing.T) {
+	c := qt.New(t)
+
+	srv := httptest.NewUnstartedServer(handleWS(echo))
+	srv.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	srv.StartTLS()
+	defer srv.Close()
+
+	ctl := dbmodel.Controller{
+		PublicAddress: strings.TrimPrefix(srv.URL, "https://"),
+		CACertificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})),
+		TLSMinVersion: "1.3",
+	}
+	_, err := rpc.Dial(context.Background(), &ctl, names.ModelTag{}, "", nil)
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Check(ctl.CertificateExpiry.Valid, qt.IsFalse)
+}
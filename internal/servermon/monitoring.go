@@ -5,6 +5,8 @@
 package servermon
 
 import (
+	"database/sql"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -12,6 +14,13 @@ import (
 )
 
 var (
+	AuthenticationDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "auth",
+		Name:      "duration_seconds",
+		Help:      "Histogram of authentication time in seconds.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method"})
 	AuthenticationFailCount = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "jimm",
 		Subsystem: "auth",
@@ -24,6 +33,49 @@ var (
 		Name:      "success_total",
 		Help:      "The number of successful authentications.",
 	}, []string{"method"})
+	AuthorizationDenialCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "auth",
+		Name:      "authorization_denied_total",
+		Help:      "The number of requests denied because the user was not authorized.",
+	}, []string{"facade", "method"})
+	MacaroonDischargeDurationHistogram = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "auth",
+		Name:      "discharge_duration_seconds",
+		Help:      "Histogram of macaroon third party caveat discharge time in seconds.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	})
+	MacaroonDischargeCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "auth",
+		Name:      "discharge_total",
+		Help:      "The number of macaroon third party caveat discharges by result.",
+	}, []string{"result"})
+	CacheHitCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "cache",
+		Name:      "hit_total",
+		Help:      "The number of cache lookups that found a value.",
+	}, []string{"cache"})
+	CacheMissCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "cache",
+		Name:      "miss_total",
+		Help:      "The number of cache lookups that did not find a value.",
+	}, []string{"cache"})
+	CacheInvalidationLagHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "cache",
+		Name:      "invalidation_lag_seconds",
+		Help:      "Histogram of the time in seconds between a cache invalidation on another replica and it being applied locally.",
+	}, []string{"kind"})
+	CacheInvalidationsSentCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "cache",
+		Name:      "invalidations_sent_total",
+		Help:      "The number of cache invalidations broadcast to other replicas by result.",
+	}, []string{"result"})
 	DBQueryDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "jimm",
 		Subsystem: "db",
@@ -63,6 +115,19 @@ var (
 		Name:      "error_total",
 		Help:      "The number of vault call errors.",
 	}, []string{"method"})
+	ObjectStoreCallDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "objectstore",
+		Name:      "call_duration_seconds",
+		Help:      "Histogram of object store call time in seconds",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"method"})
+	ObjectStoreCallErrorCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "objectstore",
+		Name:      "error_total",
+		Help:      "The number of object store call errors.",
+	}, []string{"method"})
 	JujuCallDurationHistogram = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "jimm",
 		Subsystem: "juju",
@@ -83,12 +148,42 @@ var (
 		Name:      "error_total",
 		Help:      "The number of juju call errors.",
 	}, []string{"facade", "method", "controller"})
+	JujuInflightCalls = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "juju",
+		Name:      "inflight_calls",
+		Help:      "The number of juju calls waiting for a response.",
+	}, []string{"controller"})
+	JujuConnectionsRecycledCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "juju",
+		Name:      "connections_recycled_total",
+		Help:      "The number of controller connections recycled because a call did not complete in time.",
+	}, []string{"controller"})
+	ProxySessionsDrainedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "juju",
+		Name:      "proxy_sessions_drained_total",
+		Help:      "The number of proxied client sessions drained because their controller entered maintenance.",
+	}, []string{"controller"})
 	ConcurrentWebsocketConnections = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "jimm",
 		Subsystem: "websocket",
 		Name:      "concurrent_connections",
 		Help:      "The number of concurrent websocket connections",
 	})
+	RejectedWebsocketConnectionsCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "websocket",
+		Name:      "rejected_connections_total",
+		Help:      "The number of websocket connections rejected by the connection policy.",
+	}, []string{"reason"})
+	PubsubEvictedSubscribersCount = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "pubsub",
+		Name:      "evicted_subscribers_total",
+		Help:      "The number of pubsub subscribers evicted for being slow consumers.",
+	})
 	ModelsCreatedCount = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "jimm",
 		Subsystem: "websocket",
@@ -107,18 +202,96 @@ var (
 		Name:      "deltas_received_total",
 		Help:      "The number of watcher deltas received.",
 	}, []string{"controller"})
+	MonitorDeltasDeadLetteredCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "deltas_dead_lettered_total",
+		Help:      "The number of watcher deltas that could not be applied and were dead-lettered.",
+	}, []string{"controller", "kind"})
+	MonitorDeltasExportedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "deltas_exported_total",
+		Help:      "The number of watcher deltas exported to the external event stream by result.",
+	}, []string{"controller", "result"})
+	MonitorConflictCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "conflicts_total",
+		Help:      "The number of times a watcher found another watcher monitoring the same controller.",
+	}, []string{"controller"})
+	MonitorControllerEntities = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "controller_entities",
+		Help:      "The number of entities of each kind seen by the watcher on each controller.",
+	}, []string{"controller", "kind"})
+	MonitorModelEntities = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "model_entities",
+		Help:      "The number of entities of each kind seen by the watcher in each model.",
+	}, []string{"controller", "model", "kind"})
+	MonitorControllerModels = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "controller_models",
+		Help:      "The number of models tracked by the watcher on each controller.",
+	}, []string{"controller"})
+	MonitorModelCountCorrections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "model_count_corrections_total",
+		Help:      "The number of models added to or removed from those tracked by the watcher when reconciling with the database.",
+	}, []string{"controller", "direction"})
 	MonitorErrorsCount = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "jimm",
 		Subsystem: "monitor",
 		Name:      "errors_total",
 		Help:      "The number of monitoring errors found.",
 	}, []string{"controller"})
+	RetentionPrunedRowsCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "retention",
+		Name:      "pruned_rows_total",
+		Help:      "The number of rows pruned from each historical dataset.",
+	}, []string{"dataset"})
+	RetentionPruneErrorCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "retention",
+		Name:      "prune_errors_total",
+		Help:      "The number of failed attempts to prune each historical dataset.",
+	}, []string{"dataset"})
+	FacadeRequestCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "websocket",
+		Name:      "facade_requests_total",
+		Help:      "The number of websocket requests made to each facade version, and whether the version is deprecated.",
+	}, []string{"facade", "version", "deprecated"})
 	WebsocketRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "jimm",
 		Subsystem: "websocket",
 		Name:      "request_duration_seconds",
 		Help:      "The duration of a websocket request in seconds.",
 	}, []string{"type", "action"})
+	PartialModelSummariesCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "websocket",
+		Name:      "partial_model_summaries_total",
+		Help:      "The number of ListModelSummaries responses that were incomplete, or included stale summaries.",
+	}, []string{"reason"})
+	QueuedRequests = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "websocket",
+		Name:      "queued_requests",
+		Help:      "The number of websocket requests of each priority class waiting for capacity.",
+	}, []string{"class"})
+	RequestQueueDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "jimm",
+		Subsystem: "websocket",
+		Name:      "request_queue_duration_seconds",
+		Help:      "The time websocket requests of each priority class waited for capacity in seconds.",
+	}, []string{"class"})
 	ModelCount = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "jimm",
 		Subsystem: "system",
@@ -131,8 +304,117 @@ var (
 		Name:      "controller",
 		Help:      "The number of controllers managed by JIMM.",
 	})
+	ControllerCertificateExpiry = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "system",
+		Name:      "controller_certificate_expiry_timestamp_seconds",
+		Help:      "The earliest expiry time, as a Unix timestamp, of the certificate chain presented by each controller.",
+	}, []string{"controller"})
+	ControllerCertificateExpiring = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "system",
+		Name:      "controller_certificate_expiring",
+		Help:      "Set to 1 for each controller whose certificate expires within the warning period, 0 otherwise.",
+	}, []string{"controller"})
+	ControllerUnavailable = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "system",
+		Name:      "controller_unavailable",
+		Help:      "Set to 1 for each unavailable controller, labelled with the reason it is unavailable.",
+	}, []string{"controller", "reason"})
+	DBPoolMaxOpenConnections = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "max_open_connections",
+		Help:      "The maximum number of open connections to the database.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	DBPoolOpenConnections = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "open_connections",
+		Help:      "The number of open connections to the database.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	DBPoolInUseConnections = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "in_use_connections",
+		Help:      "The number of database connections currently in use.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	DBPoolIdleConnections = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "idle_connections",
+		Help:      "The number of idle database connections.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	DBPoolWaitCount = promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "wait_total",
+		Help:      "The number of times a query waited for a free database connection.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	DBPoolWaitDuration = promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "wait_duration_seconds_total",
+		Help:      "The total time spent waiting for a free database connection.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	DBPoolIdleClosedCount = promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: "jimm",
+		Subsystem: "db_pool",
+		Name:      "idle_closed_total",
+		Help:      "The number of database connections closed because the pool had too many idle connections, or they were idle for too long.",
+	}, dbPoolStat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed + s.MaxIdleTimeClosed) }))
 )
 
+// dbPool holds the database connection pool reported by the DBPool*
+// metrics.
+var dbPool atomic.Pointer[sql.DB]
+
+// SetDBPool sets the database connection pool whose utilization is
+// reported by the DBPool* metrics.
+func SetDBPool(db *sql.DB) {
+	dbPool.Store(db)
+}
+
+// dbPoolStat returns a function that reports the statistic extracted by
+// f from the current database connection pool. If no pool has been set
+// the statistic is reported as 0.
+func dbPoolStat(f func(sql.DBStats) float64) func() float64 {
+	return func() float64 {
+		db := dbPool.Load()
+		if db == nil {
+			return 0
+		}
+		return f(db.Stats())
+	}
+}
+
+// SetEntityLabels replaces the MonitorControllerEntities and
+// MonitorModelEntities gauges with gauges that carry the given additional
+// labels, the controller labels on both gauges and the model labels on
+// MonitorModelEntities. Values for the additional labels must follow the
+// standard label values in calls to WithLabelValues. SetEntityLabels
+// must be called before the gauges are used.
+func SetEntityLabels(controllerLabels, modelLabels []string) {
+	controllerEntities := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "controller_entities",
+		Help:      "The number of entities of each kind seen by the watcher on each controller.",
+	}, append([]string{"controller", "kind"}, controllerLabels...))
+	modelEntities := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "jimm",
+		Subsystem: "monitor",
+		Name:      "model_entities",
+		Help:      "The number of entities of each kind seen by the watcher in each model.",
+	}, append(append([]string{"controller", "model", "kind"}, controllerLabels...), modelLabels...))
+	prometheus.Unregister(MonitorControllerEntities)
+	prometheus.Unregister(MonitorModelEntities)
+	prometheus.MustRegister(controllerEntities, modelEntities)
+	MonitorControllerEntities = controllerEntities
+	MonitorModelEntities = modelEntities
+}
+
 // DurationObserver returns a function that, when run with `defer` will
 // record the duration of the parent function's execution.
 // Durations are observer as microseconds.
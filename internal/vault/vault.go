@@ -35,6 +35,11 @@ const (
 	oAuthSecretKey = "oauth-secret"
 )
 
+const (
+	accessKeyIDKey     = "access-key-id"
+	secretAccessKeyKey = "secret-access-key"
+)
+
 // A VaultStore stores cloud credential attributes and
 // controller credentials in vault.
 type VaultStore struct {
@@ -207,6 +212,61 @@ func (s *VaultStore) PutControllerCredentials(ctx context.Context, controllerNam
 	return nil
 }
 
+// GetObjectStoreCredentials retrieves the credentials used to access the
+// object store from a vault service.
+func (s *VaultStore) GetObjectStoreCredentials(ctx context.Context) (_ string, _ string, err error) {
+	const op = errors.Op("vault.GetObjectStoreCredentials")
+
+	durationObserver := servermon.DurationObserver(servermon.VaultCallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.VaultCallErrorCount, &err, string(op))
+
+	client, err := s.client(ctx)
+	if err != nil {
+		return "", "", errors.E(op, err)
+	}
+
+	secret, err := client.KVv2(s.KVPath).Get(ctx, s.objectStoreCredentialsPath())
+	if err != nil && goerr.Unwrap(err) != api.ErrSecretNotFound {
+		return "", "", errors.E(op, err)
+	}
+	if secret == nil || secret.Data == nil {
+		return "", "", nil
+	}
+	var accessKeyID, secretAccessKey string
+	if v, ok := secret.Data[accessKeyIDKey].(string); ok {
+		accessKeyID = v
+	}
+	if v, ok := secret.Data[secretAccessKeyKey].(string); ok {
+		secretAccessKey = v
+	}
+	return accessKeyID, secretAccessKey, nil
+}
+
+// PutObjectStoreCredentials stores the credentials used to access the
+// object store in a vault service.
+func (s *VaultStore) PutObjectStoreCredentials(ctx context.Context, accessKeyID, secretAccessKey string) (err error) {
+	const op = errors.Op("vault.PutObjectStoreCredentials")
+
+	durationObserver := servermon.DurationObserver(servermon.VaultCallDurationHistogram, string(op))
+	defer durationObserver()
+	defer servermon.ErrorCounter(servermon.VaultCallErrorCount, &err, string(op))
+
+	client, err := s.client(ctx)
+	if err != nil {
+		return errors.E(op, err)
+	}
+
+	data := map[string]interface{}{
+		accessKeyIDKey:     accessKeyID,
+		secretAccessKeyKey: secretAccessKey,
+	}
+	if _, err := client.KVv2(s.KVPath).Put(ctx, s.objectStoreCredentialsPath(), data); err != nil {
+		return errors.E(op, err)
+	}
+	return nil
+}
+
 // CleanupJWKS removes all secrets associated with the JWKS process.
 func (s *VaultStore) CleanupJWKS(ctx context.Context) (err error) {
 	const op = errors.Op("vault.CleanupJWKS")
@@ -517,3 +577,10 @@ func (s *VaultStore) path(tag names.CloudCredentialTag) string {
 func (s *VaultStore) controllerCredentialsPath(controllerName string) string {
 	return path.Join("creds", controllerName)
 }
+
+// objectStoreCredentialsPath returns the path to the object store
+// credentials. The path starts with a dot so that it cannot clash with
+// the credentials of a controller.
+func (s *VaultStore) objectStoreCredentialsPath() string {
+	return path.Join("creds", ".objectstore")
+}
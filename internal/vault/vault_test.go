@@ -122,6 +122,20 @@ func TestVaultControllerCredentialsStoreRoundTrip(t *testing.T) {
 	c.Check(p, qt.Equals, "")
 }
 
+func TestVaultObjectStoreCredentialsRoundTrip(t *testing.T) {
+	c := qt.New(t)
+
+	st := newStore(c)
+	ctx := context.Background()
+	err := st.PutObjectStoreCredentials(ctx, "access-key", "secret-key")
+	c.Assert(err, qt.IsNil)
+
+	accessKeyID, secretAccessKey, err := st.GetObjectStoreCredentials(ctx)
+	c.Assert(err, qt.IsNil)
+	c.Check(accessKeyID, qt.Equals, "access-key")
+	c.Check(secretAccessKey, qt.Equals, "secret-key")
+}
+
 func TestGetAndPutJWKS(t *testing.T) {
 	c := qt.New(t)
 	ctx := context.Background()
@@ -67,6 +67,53 @@ func (c *Client) ListControllers() ([]params.ControllerInfo, error) {
 	return resp.Controllers, err
 }
 
+// WatchControllerHealth starts a watcher reporting the state of the
+// controllers and returns its ID.
+func (c *Client) WatchControllerHealth() (string, error) {
+	var resp params.ControllerHealthWatcherID
+	err := c.caller.APICall("JIMM", 4, "", "WatchControllerHealth", nil, &resp)
+	return resp.WatcherID, err
+}
+
+// ControllerHealthWatcherNext returns the state of the controllers from
+// the watcher with the given ID. The first call returns immediately,
+// subsequent calls wait until the state of a controller changes.
+func (c *Client) ControllerHealthWatcherNext(id string) ([]params.ControllerHealthState, error) {
+	var resp params.ControllerHealthWatcherNextResults
+	err := c.caller.APICall("ControllerHealthWatcher", 1, id, "Next", nil, &resp)
+	return resp.Controllers, err
+}
+
+// ControllerHealthWatcherStop stops the watcher with the given ID.
+func (c *Client) ControllerHealthWatcherStop(id string) error {
+	return c.caller.APICall("ControllerHealthWatcher", 1, id, "Stop", nil, nil)
+}
+
+// ModelInfo returns information about the requested models using the
+// requested read consistency.
+func (c *Client) ModelInfo(req *params.ConsistentEntities) (jujuparams.ModelInfoResults, error) {
+	var resp jujuparams.ModelInfoResults
+	err := c.caller.APICall("ModelManager", 9, "", "ModelInfo", req, &resp)
+	return resp, err
+}
+
+// ModelStatus returns the status of the requested models using the
+// requested read consistency.
+func (c *Client) ModelStatus(req *params.ConsistentEntities) (jujuparams.ModelStatusResults, error) {
+	var resp jujuparams.ModelStatusResults
+	err := c.caller.APICall("Controller", 11, "", "ModelStatus", req, &resp)
+	return resp, err
+}
+
+// CredentialContents returns the contents of the requested credentials,
+// or all of the user's credentials if none are requested, using the
+// requested read consistency.
+func (c *Client) CredentialContents(req *params.ConsistentCloudCredentialArgs) (jujuparams.CredentialContentResults, error) {
+	var resp jujuparams.CredentialContentResults
+	err := c.caller.APICall("Cloud", 7, "", "CredentialContents", req, &resp)
+	return resp, err
+}
+
 // RemoveCloudFromController removes the specified cloud from a specific controller.
 func (c *Client) RemoveCloudFromController(req *params.RemoveCloudFromControllerRequest) error {
 	return c.caller.APICall("JIMM", 4, "", "RemoveCloudFromController", req, nil)
@@ -88,6 +135,24 @@ func (c *Client) RevokeAuditLogAccess(req *params.AuditLogAccessRequest) error {
 	return c.caller.APICall("JIMM", 4, "", "RevokeAuditLogAccess", req, nil)
 }
 
+// GrantCloudCredentialAccess allows a user or group to create models
+// using a cloud credential.
+func (c *Client) GrantCloudCredentialAccess(req *params.CloudCredentialAccessRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "GrantCloudCredentialAccess", req, nil)
+}
+
+// RevokeCloudCredentialAccess removes a user or group's ability to
+// create models using a cloud credential.
+func (c *Client) RevokeCloudCredentialAccess(req *params.CloudCredentialAccessRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RevokeCloudCredentialAccess", req, nil)
+}
+
+// SetDefaultCloudCredential sets, or removes, the cloud credential used
+// by default when creating models on a cloud.
+func (c *Client) SetDefaultCloudCredential(req *params.SetDefaultCloudCredentialRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetDefaultCloudCredential", req, nil)
+}
+
 // SetControllerDeprecated sets the deprecated status of a controller.
 func (c *Client) SetControllerDeprecated(req *params.SetControllerDeprecatedRequest) (params.ControllerInfo, error) {
 	var info params.ControllerInfo
@@ -95,6 +160,37 @@ func (c *Client) SetControllerDeprecated(req *params.SetControllerDeprecatedRequ
 	return info, err
 }
 
+// GetControllerBootstrapProfile returns a controller's bootstrap
+// profile, from which an equivalent controller may be re-bootstrapped.
+func (c *Client) GetControllerBootstrapProfile(req *params.GetControllerBootstrapProfileRequest) (*params.ControllerBootstrapProfile, error) {
+	var resp params.ControllerBootstrapProfile
+	err := c.caller.APICall("JIMM", 4, "", "GetControllerBootstrapProfile", req, &resp)
+	return &resp, err
+}
+
+// SetControllerMaintenance puts a controller into, or takes it out of,
+// maintenance.
+func (c *Client) SetControllerMaintenance(req *params.SetControllerMaintenanceRequest) (*params.SetControllerMaintenanceResponse, error) {
+	var resp params.SetControllerMaintenanceResponse
+	err := c.caller.APICall("JIMM", 4, "", "SetControllerMaintenance", req, &resp)
+	return &resp, err
+}
+
+// SetControllerTimeouts sets the timeouts JIMM uses when communicating
+// with a controller.
+func (c *Client) SetControllerTimeouts(req *params.SetControllerTimeoutsRequest) (params.ControllerInfo, error) {
+	var info params.ControllerInfo
+	err := c.caller.APICall("JIMM", 4, "", "SetControllerTimeouts", req, &info)
+	return info, err
+}
+
+// SetControllerTiers sets the tiers a controller belongs to.
+func (c *Client) SetControllerTiers(req *params.SetControllerTiersRequest) (params.ControllerInfo, error) {
+	var info params.ControllerInfo
+	err := c.caller.APICall("JIMM", 4, "", "SetControllerTiers", req, &info)
+	return info, err
+}
+
 // FullModelStatus returns the full status of the juju model.
 func (c *Client) FullModelStatus(req *params.FullModelStatusRequest) (jujuparams.FullStatus, error) {
 	var status jujuparams.FullStatus
@@ -102,6 +198,30 @@ func (c *Client) FullModelStatus(req *params.FullModelStatusRequest) (jujuparams
 	return status, err
 }
 
+// DestroyModelsDryRun reports what would be destroyed by destroying the
+// given models, without destroying them.
+func (c *Client) DestroyModelsDryRun(req *params.DestroyModelsDryRunRequest) ([]params.DestroyModelImpactResult, error) {
+	var resp params.DestroyModelsDryRunResponse
+	err := c.caller.APICall("JIMM", 4, "", "DestroyModelsDryRun", req, &resp)
+	return resp.Results, err
+}
+
+// ModelMachines returns the placement and network details of the
+// machines in a model.
+func (c *Client) ModelMachines(req *params.ModelMachinesRequest) ([]params.ModelMachine, error) {
+	var resp params.ModelMachinesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ModelMachines", req, &resp)
+	return resp.Machines, err
+}
+
+// ModelsStatus returns an overview of the status of the controllers and
+// models known to JIMM.
+func (c *Client) ModelsStatus(req *params.ModelsStatusRequest) (*params.ModelsStatusResponse, error) {
+	var resp params.ModelsStatusResponse
+	err := c.caller.APICall("JIMM", 4, "", "ModelsStatus", req, &resp)
+	return &resp, err
+}
+
 // ImportModel imports a model running on a controller.
 func (c *Client) ImportModel(req *params.ImportModelRequest) error {
 	return c.caller.APICall("JIMM", 4, "", "ImportModel", req, nil)
@@ -186,6 +306,537 @@ func (c *Client) CrossModelQuery(req *params.CrossModelQueryRequest) (*params.Cr
 	return &response, err
 }
 
+// FindOffers searches the directory of application offers for the
+// offers the user can read.
+func (c *Client) FindOffers(req *params.FindOffersRequest) (*params.FindOffersResponse, error) {
+	var response params.FindOffersResponse
+	err := c.caller.APICall("JIMM", 4, "", "FindOffers", req, &response)
+	return &response, err
+}
+
+// SearchApplications searches all models for applications with an
+// endpoint matching the given interface or endpoint name.
+func (c *Client) SearchApplications(req *params.SearchApplicationsRequest) (*params.SearchApplicationsResponse, error) {
+	var response params.SearchApplicationsResponse
+	err := c.caller.APICall("JIMM", 4, "", "SearchApplications", req, &response)
+	return &response, err
+}
+
+// CrossModelRelationGraph returns the graph of cross-model relations
+// between models.
+func (c *Client) CrossModelRelationGraph(req *params.CrossModelRelationGraphRequest) (*params.CrossModelRelationGraph, error) {
+	var response params.CrossModelRelationGraph
+	err := c.caller.APICall("JIMM", 4, "", "CrossModelRelationGraph", req, &response)
+	return &response, err
+}
+
+// ListLeaders returns the JIMM replica currently running each of the
+// leader-elected background workers.
+func (c *Client) ListLeaders() ([]params.LeaderInfo, error) {
+	var resp params.ListLeadersResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListLeaders", nil, &resp)
+	return resp.Leaders, err
+}
+
+// ListScheduledJobs returns the periodic jobs run by JIMM's scheduler.
+func (c *Client) ListScheduledJobs() ([]params.ScheduledJob, error) {
+	var resp params.ListScheduledJobsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListScheduledJobs", nil, &resp)
+	return resp.Jobs, err
+}
+
+// ScheduledJobHistory returns the most recent runs of a scheduled job.
+func (c *Client) ScheduledJobHistory(req *params.ScheduledJobHistoryRequest) ([]params.ScheduledJobRun, error) {
+	var resp params.ScheduledJobHistoryResponse
+	err := c.caller.APICall("JIMM", 4, "", "ScheduledJobHistory", req, &resp)
+	return resp.Runs, err
+}
+
+// SetScheduledJobEnabled enables, or disables, a scheduled job.
+func (c *Client) SetScheduledJobEnabled(req *params.SetScheduledJobEnabledRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetScheduledJobEnabled", req, nil)
+}
+
+// PingControllers probes every controller and reports how long each took
+// to respond, or why it could not be reached.
+func (c *Client) PingControllers() (*params.PingControllersResponse, error) {
+	var resp params.PingControllersResponse
+	err := c.caller.APICall("JIMM", 4, "", "PingControllers", nil, &resp)
+	return &resp, err
+}
+
+// ListOutdatedCharms returns the applications using an older revision of
+// a Charmhub charm than the latest released to Charmhub.
+func (c *Client) ListOutdatedCharms() (*params.ListOutdatedCharmsResponse, error) {
+	var resp params.ListOutdatedCharmsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListOutdatedCharms", nil, &resp)
+	return &resp, err
+}
+
+// ListPlacementLatencies returns the latest measured latencies of the
+// cloud regions and controllers used to place latency sensitive models.
+func (c *Client) ListPlacementLatencies() (*params.PlacementLatencies, error) {
+	var resp params.PlacementLatencies
+	err := c.caller.APICall("JIMM", 4, "", "ListPlacementLatencies", nil, &resp)
+	return &resp, err
+}
+
+// SetDomainDefaultCloud sets the cloud, and optionally region, used for
+// the models of identities in a domain that do not specify a cloud.
+func (c *Client) SetDomainDefaultCloud(req *params.SetDomainDefaultCloudRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetDomainDefaultCloud", req, nil)
+}
+
+// RemoveDomainDefaultCloud removes the default cloud of a domain.
+func (c *Client) RemoveDomainDefaultCloud(req *params.RemoveDomainDefaultCloudRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveDomainDefaultCloud", req, nil)
+}
+
+// ListDomainDefaultClouds returns the default clouds of all domains.
+func (c *Client) ListDomainDefaultClouds() ([]params.DomainDefaultCloud, error) {
+	var resp params.ListDomainDefaultCloudsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListDomainDefaultClouds", nil, &resp)
+	return resp.Defaults, err
+}
+
+// NormalizeIdentities renames the existing identities to their
+// normalized names, or with DryRun only reports the renames.
+func (c *Client) NormalizeIdentities(req *params.NormalizeIdentitiesRequest) (*params.NormalizeIdentitiesResponse, error) {
+	var resp params.NormalizeIdentitiesResponse
+	err := c.caller.APICall("JIMM", 4, "", "NormalizeIdentities", req, &resp)
+	return &resp, err
+}
+
+// PurgeIdentity permanently removes an identity and its personal data.
+func (c *Client) PurgeIdentity(req *params.PurgeIdentityRequest) (*params.PurgeIdentityResponse, error) {
+	var resp params.PurgeIdentityResponse
+	err := c.caller.APICall("JIMM", 4, "", "PurgeIdentity", req, &resp)
+	return &resp, err
+}
+
+// ListIdentitySessions returns the last login and API activity of an
+// identity along with the sessions it has open.
+func (c *Client) ListIdentitySessions(req *params.ListIdentitySessionsRequest) (*params.ListIdentitySessionsResponse, error) {
+	var resp params.ListIdentitySessionsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListIdentitySessions", req, &resp)
+	return &resp, err
+}
+
+// ReloadTunables reloads the configuration that may be changed without
+// restarting JIMM and returns the settings that changed.
+func (c *Client) ReloadTunables() ([]params.TunableChange, error) {
+	var resp params.ReloadTunablesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ReloadTunables", nil, &resp)
+	return resp.Changes, err
+}
+
+// ListIdleModels returns the models that have not been used for the
+// requested number of days.
+func (c *Client) ListIdleModels(req *params.ListIdleModelsRequest) (*params.ListIdleModelsResponse, error) {
+	var resp params.ListIdleModelsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListIdleModels", req, &resp)
+	return &resp, err
+}
+
+// ArchiveAndDestroyModel stores the bundle of a model in JIMM's object
+// store and then destroys the model.
+func (c *Client) ArchiveAndDestroyModel(req *params.ArchiveAndDestroyModelRequest) (*params.ArchiveAndDestroyModelResponse, error) {
+	var resp params.ArchiveAndDestroyModelResponse
+	err := c.caller.APICall("JIMM", 4, "", "ArchiveAndDestroyModel", req, &resp)
+	return &resp, err
+}
+
+// ListControllerCertificates returns the expiry of the certificates
+// presented by every controller, soonest first.
+func (c *Client) ListControllerCertificates() (*params.ListControllerCertificatesResponse, error) {
+	var resp params.ListControllerCertificatesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListControllerCertificates", nil, &resp)
+	return &resp, err
+}
+
+// ListControllerModelCredentials returns the cloud credential used by the
+// controller model of every controller.
+func (c *Client) ListControllerModelCredentials() (*params.ListControllerModelCredentialsResponse, error) {
+	var resp params.ListControllerModelCredentialsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListControllerModelCredentials", nil, &resp)
+	return &resp, err
+}
+
+// RotateControllerModelCredential replaces the cloud credential used by
+// the controller model of a controller and records when it expires.
+func (c *Client) RotateControllerModelCredential(req *params.RotateControllerModelCredentialRequest) (*params.ControllerModelCredential, error) {
+	var resp params.ControllerModelCredential
+	err := c.caller.APICall("JIMM", 4, "", "RotateControllerModelCredential", req, &resp)
+	return &resp, err
+}
+
+// ExportModelAccess returns a document declaring the access held on a
+// model, or on every model of an owner.
+func (c *Client) ExportModelAccess(req *params.ExportModelAccessRequest) (*params.ModelAccessDocument, error) {
+	var resp params.ModelAccessDocument
+	err := c.caller.APICall("JIMM", 4, "", "ExportModelAccess", req, &resp)
+	return &resp, err
+}
+
+// ApplyModelAccess makes the access held on models match a document, or
+// reports the changes that would be made if the request is a dry run.
+func (c *Client) ApplyModelAccess(req *params.ApplyModelAccessRequest) (*params.ApplyModelAccessResponse, error) {
+	var resp params.ApplyModelAccessResponse
+	err := c.caller.APICall("JIMM", 4, "", "ApplyModelAccess", req, &resp)
+	return &resp, err
+}
+
+// SyncGroups synchronises group memberships from the external directory,
+// or reports the changes that would be made if the request is a dry run.
+func (c *Client) SyncGroups(req *params.SyncGroupsRequest) (*params.SyncGroupsResponse, error) {
+	var resp params.SyncGroupsResponse
+	err := c.caller.APICall("JIMM", 4, "", "SyncGroups", req, &resp)
+	return &resp, err
+}
+
+// GroupSyncStatus returns the outcome of the most recent synchronisation
+// of group memberships from the external directory.
+func (c *Client) GroupSyncStatus() (*params.GroupSyncStatusResponse, error) {
+	var resp params.GroupSyncStatusResponse
+	err := c.caller.APICall("JIMM", 4, "", "GroupSyncStatus", nil, &resp)
+	return &resp, err
+}
+
+// ResyncModelAccess starts re-syncing JIMM's view of model access to the
+// controllers, the differences found are reported in the result of the
+// returned operation.
+func (c *Client) ResyncModelAccess(req *params.ResyncModelAccessRequest) (*params.ResyncModelAccessResponse, error) {
+	var response params.ResyncModelAccessResponse
+	err := c.caller.APICall("JIMM", 4, "", "ResyncModelAccess", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AuditControllerAccess reports the access held directly on the
+// controllers that was not granted through JIMM.
+func (c *Client) AuditControllerAccess(req *params.AuditControllerAccessRequest) (*params.AuditControllerAccessResponse, error) {
+	var response params.AuditControllerAccessResponse
+	err := c.caller.APICall("JIMM", 4, "", "AuditControllerAccess", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListPendingModelDestroys returns the models waiting to be destroyed at
+// the end of their destroy confirmation window.
+func (c *Client) ListPendingModelDestroys() (*params.ListPendingModelDestroysResponse, error) {
+	var resp params.ListPendingModelDestroysResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListPendingModelDestroys", nil, &resp)
+	return &resp, err
+}
+
+// CancelModelDestroy cancels the pending destroy of a model.
+func (c *Client) CancelModelDestroy(req *params.CancelModelDestroyRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "CancelModelDestroy", req, nil)
+}
+
+// ListDeletedModels returns the retained records of the models removed
+// from JIMM.
+func (c *Client) ListDeletedModels() (*params.ListDeletedModelsResponse, error) {
+	var resp params.ListDeletedModelsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListDeletedModels", nil, &resp)
+	return &resp, err
+}
+
+// GetDeletedModel returns the retained record of a model removed from
+// JIMM.
+func (c *Client) GetDeletedModel(req *params.DeletedModelRequest) (*params.DeletedModel, error) {
+	var resp params.DeletedModel
+	err := c.caller.APICall("JIMM", 4, "", "GetDeletedModel", req, &resp)
+	return &resp, err
+}
+
+// RestoreModel restores a model removed from JIMM.
+func (c *Client) RestoreModel(req *params.DeletedModelRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RestoreModel", req, nil)
+}
+
+// CancelModelCreation cancels the creation of a model that is still in
+// progress.
+func (c *Client) CancelModelCreation(req *params.CancelModelCreationRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "CancelModelCreation", req, nil)
+}
+
+// ExportModelBundle exports a model as a bundle.
+func (c *Client) ExportModelBundle(req *params.ExportModelBundleRequest) (*params.ExportModelBundleResponse, error) {
+	var response params.ExportModelBundleResponse
+	err := c.caller.APICall("JIMM", 4, "", "ExportModelBundle", req, &response)
+	return &response, err
+}
+
+// ModelTimeline returns the significant events in the history of a model.
+func (c *Client) ModelTimeline(req *params.ModelTimelineRequest) (*params.ModelTimeline, error) {
+	var response params.ModelTimeline
+	err := c.caller.APICall("JIMM", 4, "", "ModelTimeline", req, &response)
+	return &response, err
+}
+
+// ModelResourceHistory returns series of the resources used by a model
+// over time.
+func (c *Client) ModelResourceHistory(req *params.ModelResourceHistoryRequest) (*params.ModelResourceHistory, error) {
+	var response params.ModelResourceHistory
+	err := c.caller.APICall("JIMM", 4, "", "ModelResourceHistory", req, &response)
+	return &response, err
+}
+
+// UserQuota returns the quota limits and current usage for a user's
+// models.
+func (c *Client) UserQuota(req *params.UserQuotaRequest) (*params.UserQuota, error) {
+	var response params.UserQuota
+	err := c.caller.APICall("JIMM", 4, "", "UserQuota", req, &response)
+	return &response, err
+}
+
+// SetUserQuota overrides the default quota limits for a user's models.
+func (c *Client) SetUserQuota(req *params.SetUserQuotaRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetUserQuota", req, nil)
+}
+
+// RemoveUserQuota removes the quota limits set for a user.
+func (c *Client) RemoveUserQuota(req *params.RemoveUserQuotaRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveUserQuota", req, nil)
+}
+
+// SetPublicListing lists a model or application offer in the public
+// catalog.
+func (c *Client) SetPublicListing(req *params.SetPublicListingRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetPublicListing", req, nil)
+}
+
+// SetOfferConsumerLimit limits the number of models that may consume an
+// application offer.
+func (c *Client) SetOfferConsumerLimit(req *params.SetOfferConsumerLimitRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetOfferConsumerLimit", req, nil)
+}
+
+// OfferConsumption returns the consumption of an application offer
+// against its consumer limit.
+func (c *Client) OfferConsumption(req *params.OfferConsumptionRequest) (params.OfferConsumption, error) {
+	var resp params.OfferConsumption
+	err := c.caller.APICall("JIMM", 4, "", "OfferConsumption", req, &resp)
+	return resp, err
+}
+
+// RemovePublicListing removes a model or application offer from the
+// public catalog.
+func (c *Client) RemovePublicListing(req *params.RemovePublicListingRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemovePublicListing", req, nil)
+}
+
+// SetCostCenter attaches a cost center to a user, group or model.
+func (c *Client) SetCostCenter(req *params.SetCostCenterRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "SetCostCenter", req, nil)
+}
+
+// UsageReport reports the resources used by every model, attributed to
+// cost centers.
+func (c *Client) UsageReport() (*params.UsageReport, error) {
+	var resp params.UsageReport
+	err := c.caller.APICall("JIMM", 4, "", "UsageReport", nil, &resp)
+	return &resp, err
+}
+
+// AgentVersionReport reports the agent versions running in every model,
+// attributed to cost centers.
+func (c *Client) AgentVersionReport() (*params.AgentVersionReport, error) {
+	var resp params.AgentVersionReport
+	err := c.caller.APICall("JIMM", 4, "", "AgentVersionReport", nil, &resp)
+	return &resp, err
+}
+
+// Whoami returns the authenticated identity along with a summary of its
+// effective permissions.
+func (c *Client) Whoami() (*params.IdentitySummary, error) {
+	var resp params.IdentitySummary
+	err := c.caller.APICall("JIMM", 4, "", "Whoami", nil, &resp)
+	return &resp, err
+}
+
+// AddNamespaceReservation reserves the model names starting with a prefix
+// for the members of a group.
+func (c *Client) AddNamespaceReservation(req *params.AddNamespaceReservationRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "AddNamespaceReservation", req, nil)
+}
+
+// ListNamespaceReservations returns all namespace reservations.
+func (c *Client) ListNamespaceReservations() ([]params.NamespaceReservation, error) {
+	var resp params.ListNamespaceReservationsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListNamespaceReservations", nil, &resp)
+	return resp.Reservations, err
+}
+
+// TransferNamespaceReservation transfers a namespace reservation to
+// another group.
+func (c *Client) TransferNamespaceReservation(req *params.TransferNamespaceReservationRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "TransferNamespaceReservation", req, nil)
+}
+
+// RemoveNamespaceReservation removes a namespace reservation.
+func (c *Client) RemoveNamespaceReservation(req *params.RemoveNamespaceReservationRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveNamespaceReservation", req, nil)
+}
+
+// AddModelACLTemplate adds a template granting a group access to every
+// new model owned by a member of another group.
+func (c *Client) AddModelACLTemplate(req *params.AddModelACLTemplateRequest) (*params.ModelACLTemplate, error) {
+	var response params.ModelACLTemplate
+	err := c.caller.APICall("JIMM", 4, "", "AddModelACLTemplate", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListModelACLTemplates returns all model ACL templates.
+func (c *Client) ListModelACLTemplates() ([]params.ModelACLTemplate, error) {
+	var resp params.ListModelACLTemplatesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelACLTemplates", nil, &resp)
+	return resp.Templates, err
+}
+
+// RemoveModelACLTemplate removes a model ACL template.
+func (c *Client) RemoveModelACLTemplate(req *params.RemoveModelACLTemplateRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelACLTemplate", req, nil)
+}
+
+// CreateModelToken creates a long-lived token granting access to a single
+// model. The returned token is used in place of a session token when
+// logging in and cannot be retrieved again.
+func (c *Client) CreateModelToken(req *params.CreateModelTokenRequest) (*params.CreateModelTokenResponse, error) {
+	var response params.CreateModelTokenResponse
+	err := c.caller.APICall("JIMM", 4, "", "CreateModelToken", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListModelTokens returns the tokens granting access to a model.
+func (c *Client) ListModelTokens(req *params.ListModelTokensRequest) ([]params.ModelToken, error) {
+	var resp params.ListModelTokensResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelTokens", req, &resp)
+	return resp.Tokens, err
+}
+
+// RevokeModelToken revokes a model token.
+func (c *Client) RevokeModelToken(req *params.RevokeModelTokenRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RevokeModelToken", req, nil)
+}
+
+// FreezeModel freezes a model, after which mutating calls made to the
+// model are rejected.
+func (c *Client) FreezeModel(req *params.FreezeModelRequest) (*params.ModelFreeze, error) {
+	var response params.ModelFreeze
+	err := c.caller.APICall("JIMM", 4, "", "FreezeModel", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UnfreezeModel removes the freeze on a model.
+func (c *Client) UnfreezeModel(req *params.UnfreezeModelRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "UnfreezeModel", req, nil)
+}
+
+// SetModelNetworkPolicy attaches a network policy to a model and applies
+// it to the model's controller where possible.
+func (c *Client) SetModelNetworkPolicy(req *params.SetModelNetworkPolicyRequest) (*params.ModelNetworkPolicy, error) {
+	var response params.ModelNetworkPolicy
+	err := c.caller.APICall("JIMM", 4, "", "SetModelNetworkPolicy", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RemoveModelNetworkPolicy removes the network policy from a model.
+func (c *Client) RemoveModelNetworkPolicy(req *params.RemoveModelNetworkPolicyRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelNetworkPolicy", req, nil)
+}
+
+// ListModelNetworkPolicies lists the model network policies.
+func (c *Client) ListModelNetworkPolicies(req *params.ListModelNetworkPoliciesRequest) ([]params.ModelNetworkPolicy, error) {
+	var resp params.ListModelNetworkPoliciesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelNetworkPolicies", req, &resp)
+	return resp.Policies, err
+}
+
+// AddModelDependency declares that a model depends on another model.
+func (c *Client) AddModelDependency(req *params.AddModelDependencyRequest) (params.ModelDependency, error) {
+	var resp params.ModelDependency
+	err := c.caller.APICall("JIMM", 4, "", "AddModelDependency", req, &resp)
+	return resp, err
+}
+
+// RemoveModelDependency removes a model's dependency on another model.
+func (c *Client) RemoveModelDependency(req *params.RemoveModelDependencyRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelDependency", req, nil)
+}
+
+// ModelDependencies returns the dependencies of a model and the models
+// that depend on it.
+func (c *Client) ModelDependencies(req *params.ModelDependenciesRequest) (params.ModelDependencies, error) {
+	var resp params.ModelDependencies
+	err := c.caller.APICall("JIMM", 4, "", "ModelDependencies", req, &resp)
+	return resp, err
+}
+
+// AddModelPool adds a pool of pre-provisioned models.
+func (c *Client) AddModelPool(req *params.AddModelPoolRequest) (*params.ModelPool, error) {
+	var response params.ModelPool
+	err := c.caller.APICall("JIMM", 4, "", "AddModelPool", req, &response)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RemoveModelPool removes a pool of pre-provisioned models.
+func (c *Client) RemoveModelPool(req *params.RemoveModelPoolRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveModelPool", req, nil)
+}
+
+// ListModelPools lists the pools of pre-provisioned models.
+func (c *Client) ListModelPools() ([]params.ModelPool, error) {
+	var resp params.ListModelPoolsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelPools", nil, &resp)
+	return resp.Pools, err
+}
+
+// RequestAccess requests access to a model or cloud.
+func (c *Client) RequestAccess(req *params.RequestAccessRequest) (*params.AccessRequest, error) {
+	var response params.AccessRequest
+	err := c.caller.APICall("JIMM", 4, "", "RequestAccess", req, &response)
+	return &response, err
+}
+
+// ListAccessRequests returns the access requests made by, or reviewable
+// by, the authenticated user.
+func (c *Client) ListAccessRequests(req *params.ListAccessRequestsRequest) ([]params.AccessRequest, error) {
+	var resp params.ListAccessRequestsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListAccessRequests", req, &resp)
+	return resp.Requests, err
+}
+
+// ApproveAccessRequest approves an access request.
+func (c *Client) ApproveAccessRequest(req *params.ReviewAccessRequestRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "ApproveAccessRequest", req, nil)
+}
+
+// DenyAccessRequest denies an access request.
+func (c *Client) DenyAccessRequest(req *params.ReviewAccessRequestRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "DenyAccessRequest", req, nil)
+}
+
 // PurgeLogs purges logs from the database before the given date.
 func (c *Client) PurgeLogs(req *params.PurgeLogsRequest) (*params.PurgeLogsResponse, error) {
 	var response params.PurgeLogsResponse
@@ -200,6 +851,101 @@ func (c *Client) MigrateModel(req *params.MigrateModelRequest) (*jujuparams.Init
 	return &response, err
 }
 
+// MigrationPrechecks checks whether a model can be migrated between two
+// controllers that are attached to JIMM, without starting the migration.
+func (c *Client) MigrationPrechecks(req *params.MigrateModelInfo) (*params.MigrationPrecheckReport, error) {
+	var response params.MigrationPrecheckReport
+	err := c.caller.APICall("JIMM", 4, "", "MigrationPrechecks", req, &response)
+	return &response, err
+}
+
+// ModelMigrationStatus returns the status of the most recent migration
+// of a model.
+func (c *Client) ModelMigrationStatus(req *params.ModelMigrationStatusRequest) (*params.ModelMigrationStatus, error) {
+	var response params.ModelMigrationStatus
+	err := c.caller.APICall("JIMM", 4, "", "ModelMigrationStatus", req, &response)
+	return &response, err
+}
+
+// GetModelSummary returns the summary of a single model, served entirely
+// from JIMM without contacting the controller hosting the model.
+func (c *Client) GetModelSummary(req *params.GetModelSummaryRequest) (*params.ModelSummary, error) {
+	var response params.ModelSummary
+	err := c.caller.APICall("JIMM", 4, "", "GetModelSummary", req, &response)
+	return &response, err
+}
+
+// ModelControllerEndpoints returns the API endpoints and CA certificate
+// of the controller hosting a model.
+func (c *Client) ModelControllerEndpoints(req *params.ModelControllerEndpointsRequest) (*params.ModelControllerEndpoints, error) {
+	var response params.ModelControllerEndpoints
+	err := c.caller.APICall("JIMM", 4, "", "ModelControllerEndpoints", req, &response)
+	return &response, err
+}
+
+// ListModelMigrations lists the model migrations JIMM has initiated
+// between its controllers.
+func (c *Client) ListModelMigrations(req *params.ListModelMigrationsRequest) ([]params.ModelMigration, error) {
+	var response params.ListModelMigrationsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListModelMigrations", req, &response)
+	return response.Migrations, err
+}
+
+// RebalanceRecommendations returns recommended model migrations that
+// would balance the models across the controllers attached to JIMM.
+func (c *Client) RebalanceRecommendations(req *params.RebalanceRecommendationsRequest) (*params.RebalanceReport, error) {
+	var response params.RebalanceReport
+	err := c.caller.APICall("JIMM", 4, "", "RebalanceRecommendations", req, &response)
+	return &response, err
+}
+
+// ListCredentialPropagations lists the controllers cloud credentials have
+// been copied to.
+func (c *Client) ListCredentialPropagations(req *params.ListCredentialPropagationsRequest) (*params.ListCredentialPropagationsResponse, error) {
+	var response params.ListCredentialPropagationsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListCredentialPropagations", req, &response)
+	return &response, err
+}
+
+// RebindModelCredentials rebinds the models using cloud credentials owned
+// by a departing user to credentials shared with a group.
+func (c *Client) RebindModelCredentials(req *params.RebindModelCredentialsRequest) (*params.RebindModelCredentialsResponse, error) {
+	var response params.RebindModelCredentialsResponse
+	err := c.caller.APICall("JIMM", 4, "", "RebindModelCredentials", req, &response)
+	return &response, err
+}
+
+// RotateCloudCredentials rotates the cloud credentials for a cloud in a
+// single run.
+func (c *Client) RotateCloudCredentials(req *params.RotateCloudCredentialsRequest) (*params.RotateCloudCredentialsResponse, error) {
+	var response params.RotateCloudCredentialsResponse
+	err := c.caller.APICall("JIMM", 4, "", "RotateCloudCredentials", req, &response)
+	return &response, err
+}
+
+// ControllerCall calls an allowed controller facade method on a
+// controller.
+func (c *Client) ControllerCall(req *params.ControllerCallRequest) (*params.ControllerCallResponse, error) {
+	var response params.ControllerCallResponse
+	err := c.caller.APICall("JIMM", 4, "", "ControllerCall", req, &response)
+	return &response, err
+}
+
+// EnrolTOTP enrols a new TOTP authenticator for the authenticated user.
+func (c *Client) EnrolTOTP() (*params.EnrolTOTPResponse, error) {
+	var response params.EnrolTOTPResponse
+	err := c.caller.APICall("JIMM", 4, "", "EnrolTOTP", nil, &response)
+	return &response, err
+}
+
+// ConfirmIdentity confirms the authenticated user's identity, allowing
+// sensitive operations on the connection.
+func (c *Client) ConfirmIdentity(req *params.ConfirmIdentityRequest) (*params.ConfirmIdentityResponse, error) {
+	var response params.ConfirmIdentityResponse
+	err := c.caller.APICall("JIMM", 4, "", "ConfirmIdentity", req, &response)
+	return &response, err
+}
+
 // AddServiceAccount binds a service account to a user allowing them to manage it.
 func (c *Client) AddServiceAccount(req *params.AddServiceAccountRequest) error {
 	return c.caller.APICall("JIMM", 4, "", "AddServiceAccount", req, nil)
@@ -231,9 +977,148 @@ func (c *Client) GrantServiceAccountAccess(req *params.GrantServiceAccountAccess
 	return c.caller.APICall("JIMM", 4, "", "GrantServiceAccountAccess", req, nil)
 }
 
+// AddServiceAccountToGroups adds a service account to the given groups.
+func (c *Client) AddServiceAccountToGroups(req *params.ServiceAccountGroupsRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "AddServiceAccountToGroups", req, nil)
+}
+
+// RemoveServiceAccountFromGroups removes a service account from the given groups.
+func (c *Client) RemoveServiceAccountFromGroups(req *params.ServiceAccountGroupsRequest) error {
+	return c.caller.APICall("JIMM", 4, "", "RemoveServiceAccountFromGroups", req, nil)
+}
+
 // Version returns version info of the controller.
 func (c *Client) Version() (params.VersionResponse, error) {
 	var response params.VersionResponse
 	err := c.caller.APICall("JIMM", 4, "", "Version", nil, &response)
 	return response, err
 }
+
+// InjectFault injects a fault into JIMM to test its resilience to
+// controller and database failures. This is only supported by JIMM
+// servers built with fault injection enabled.
+func (c *Client) InjectFault(req *params.Fault) error {
+	return c.caller.APICall("JIMM", 4, "", "InjectFault", req, nil)
+}
+
+// ClearFaults removes all the faults injected into JIMM.
+func (c *Client) ClearFaults() error {
+	return c.caller.APICall("JIMM", 4, "", "ClearFaults", nil, nil)
+}
+
+// ListFaults returns the faults injected into JIMM.
+func (c *Client) ListFaults() (*params.ListFaultsResponse, error) {
+	var resp params.ListFaultsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListFaults", nil, &resp)
+	return &resp, err
+}
+
+// RemapCloudRegion remaps a cloud region in JIMM's records, for example
+// after the cloud provider renamed or split the region.
+func (c *Client) RemapCloudRegion(req *params.RemapCloudRegionRequest) (*params.RemapCloudRegionResponse, error) {
+	var resp params.RemapCloudRegionResponse
+	err := c.caller.APICall("JIMM", 4, "", "RemapCloudRegion", req, &resp)
+	return &resp, err
+}
+
+// RotateWebhookSecret rotates the secret the deliveries to a webhook are
+// signed with, returning the new secret.
+func (c *Client) RotateWebhookSecret(req *params.RotateWebhookSecretRequest) (*params.WebhookSecret, error) {
+	var resp params.WebhookSecret
+	err := c.caller.APICall("JIMM", 4, "", "RotateWebhookSecret", req, &resp)
+	return &resp, err
+}
+
+// ListWebhookDeliveries lists the signed deliveries to a webhook, newest
+// first.
+func (c *Client) ListWebhookDeliveries(req *params.ListWebhookDeliveriesRequest) (*params.ListWebhookDeliveriesResponse, error) {
+	var resp params.ListWebhookDeliveriesResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListWebhookDeliveries", req, &resp)
+	return &resp, err
+}
+
+// ExportModel exports the JIMM metadata of a model so that it can be
+// imported by another JIMM deployment.
+func (c *Client) ExportModel(req *params.ExportModelRequest) (*params.ModelExport, error) {
+	var resp params.ModelExport
+	err := c.caller.APICall("JIMM", 4, "", "ExportModel", req, &resp)
+	return &resp, err
+}
+
+// PrepareModelImport prepares the import of a model exported from
+// another JIMM deployment.
+func (c *Client) PrepareModelImport(req *params.PrepareModelImportRequest) (*params.ModelImport, error) {
+	var resp params.ModelImport
+	err := c.caller.APICall("JIMM", 4, "", "PrepareModelImport", req, &resp)
+	return &resp, err
+}
+
+// MigrateModelExternal migrates a model to a controller managed by
+// another JIMM deployment that has prepared the model's import.
+func (c *Client) MigrateModelExternal(req *params.MigrateModelExternalRequest) (*params.MigrateModelExternalResponse, error) {
+	var resp params.MigrateModelExternalResponse
+	err := c.caller.APICall("JIMM", 4, "", "MigrateModelExternal", req, &resp)
+	return &resp, err
+}
+
+// CompleteModelImport completes the import of a model once its migration
+// has finished.
+func (c *Client) CompleteModelImport(req *params.CompleteModelImportRequest) (*params.ModelImport, error) {
+	var resp params.ModelImport
+	err := c.caller.APICall("JIMM", 4, "", "CompleteModelImport", req, &resp)
+	return &resp, err
+}
+
+// RepairDenormalizedFields recomputes the denormalized fields held on
+// models and controllers and repairs those that have drifted.
+func (c *Client) RepairDenormalizedFields(req *params.RepairDenormalizedFieldsRequest) (*params.RepairDenormalizedFieldsResponse, error) {
+	var resp params.RepairDenormalizedFieldsResponse
+	err := c.caller.APICall("JIMM", 4, "", "RepairDenormalizedFields", req, &resp)
+	return &resp, err
+}
+
+// ProvisionControllerServiceUser provisions the JIMM service user on a
+// controller, which JIMM then uses for all its connections to the
+// controller.
+func (c *Client) ProvisionControllerServiceUser(req *params.ProvisionControllerServiceUserRequest) (*params.ProvisionControllerServiceUserResponse, error) {
+	var resp params.ProvisionControllerServiceUserResponse
+	err := c.caller.APICall("JIMM", 4, "", "ProvisionControllerServiceUser", req, &resp)
+	return &resp, err
+}
+
+// GetOperation returns the progress of a long-running operation.
+func (c *Client) GetOperation(req *params.GetOperationRequest) (*params.Operation, error) {
+	var resp params.Operation
+	err := c.caller.APICall("JIMM", 4, "", "GetOperation", req, &resp)
+	return &resp, err
+}
+
+// ListOperations lists the long-running operations started by the
+// authenticated user, or by every user if requested.
+func (c *Client) ListOperations(req *params.ListOperationsRequest) ([]params.Operation, error) {
+	var resp params.ListOperationsResponse
+	err := c.caller.APICall("JIMM", 4, "", "ListOperations", req, &resp)
+	return resp.Operations, err
+}
+
+// WatchOperation starts a watcher reporting the progress of a
+// long-running operation, returning the ID of the watcher.
+func (c *Client) WatchOperation(req *params.WatchOperationRequest) (string, error) {
+	var resp params.OperationWatcherID
+	err := c.caller.APICall("JIMM", 4, "", "WatchOperation", req, &resp)
+	return resp.WatcherID, err
+}
+
+// OperationWatcherNext returns the operation from the watcher with the
+// given ID. The first call returns immediately, subsequent calls wait
+// until the operation's progress changes.
+func (c *Client) OperationWatcherNext(id string) (*params.Operation, error) {
+	var resp params.OperationWatcherNextResults
+	err := c.caller.APICall("OperationWatcher", 1, id, "Next", nil, &resp)
+	return &resp.Operation, err
+}
+
+// OperationWatcherStop stops the watcher with the given ID.
+func (c *Client) OperationWatcherStop(id string) error {
+	return c.caller.APICall("OperationWatcher", 1, id, "Stop", nil, nil)
+}
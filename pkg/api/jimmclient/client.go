@@ -0,0 +1,339 @@
+// Copyright 2024 Canonical.
+
+// Package jimmclient provides a typed client for JIMM. The client manages
+// the websocket connection to JIMM, including authentication and
+// reconnection, and also provides access to JIMM's REST endpoints.
+package jimmclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	jujuapi "github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/client/modelmanager"
+	"github.com/juju/juju/rpc"
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/pkg/api"
+	"github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+const (
+	defaultRetries     = 3
+	defaultRetryDelay  = time.Second
+	defaultDialTimeout = 30 * time.Second
+)
+
+// Params holds the parameters used to connect to JIMM.
+type Params struct {
+	// Address is the host:port address of the JIMM server.
+	Address string
+
+	// CACert holds the PEM encoded CA certificate used to validate the
+	// JIMM server's certificate. If this is empty the system roots are
+	// used.
+	CACert string
+
+	// InsecureSkipVerify disables validation of the JIMM server's
+	// certificate. This should only be used for testing.
+	InsecureSkipVerify bool
+
+	// ClientID and ClientSecret hold the credentials of a service
+	// account. If ClientID is set the client logs in using the client
+	// credentials flow.
+	ClientID     string
+	ClientSecret string
+
+	// SessionToken holds the session token used to log in as a user if
+	// ClientID is not set.
+	SessionToken string
+
+	// HTTPClient is the client used to call the REST endpoints. The REST
+	// endpoints authenticate using the JIMM session cookie, so the client
+	// should hold a cookie jar containing a valid session. If this is nil
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// DialTimeout is the maximum time taken to connect to JIMM. If this
+	// is zero a default of 30 seconds is used.
+	DialTimeout time.Duration
+
+	// Retries is the number of times an operation that failed because
+	// the connection to JIMM could not be established, or was broken,
+	// is retried. If this is zero a default of 3 is used, negative values
+	// disable retries.
+	Retries int
+
+	// RetryDelay is the time to wait between retries. If this is zero a
+	// default of 1 second is used.
+	RetryDelay time.Duration
+}
+
+// A Client is a client for JIMM. The connection to JIMM is established
+// when the first websocket call is made and is re-established if it is
+// broken. A Client is safe for concurrent use.
+type Client struct {
+	p Params
+
+	mu   sync.Mutex
+	conn jujuapi.Connection
+}
+
+// New returns a new Client using the given parameters.
+func New(p Params) (*Client, error) {
+	if p.Address == "" {
+		return nil, fmt.Errorf("jimmclient: address not specified")
+	}
+	if p.ClientID == "" && p.SessionToken == "" {
+		return nil, fmt.Errorf("jimmclient: no credentials specified")
+	}
+	if p.HTTPClient == nil {
+		p.HTTPClient = http.DefaultClient
+	}
+	if p.DialTimeout == 0 {
+		p.DialTimeout = defaultDialTimeout
+	}
+	if p.Retries == 0 {
+		p.Retries = defaultRetries
+	}
+	if p.RetryDelay == 0 {
+		p.RetryDelay = defaultRetryDelay
+	}
+	return &Client{p: p}, nil
+}
+
+// Close closes the connection to JIMM, if there is one.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// CreateModel creates a new model with the given parameters and returns
+// information about the new model.
+func (c *Client) CreateModel(ctx context.Context, args *jujuparams.ModelCreateArgs) (base.ModelInfo, error) {
+	var info base.ModelInfo
+	err := c.call(ctx, func(conn jujuapi.Connection) error {
+		owner, err := names.ParseUserTag(args.OwnerTag)
+		if err != nil {
+			return err
+		}
+		var credential names.CloudCredentialTag
+		if args.CloudCredentialTag != "" {
+			credential, err = names.ParseCloudCredentialTag(args.CloudCredentialTag)
+			if err != nil {
+				return err
+			}
+		}
+		var cloud string
+		if args.CloudTag != "" {
+			ct, err := names.ParseCloudTag(args.CloudTag)
+			if err != nil {
+				return err
+			}
+			cloud = ct.Id()
+		}
+		info, err = modelmanager.NewClient(conn).CreateModel(args.Name, owner.Id(), cloud, args.CloudRegion, credential, args.Config)
+		return err
+	})
+	return info, err
+}
+
+// ListModelSummaries returns summaries of the models the given user has
+// access to. If all is true, and the authenticated user is a JIMM
+// administrator, all models are returned.
+func (c *Client) ListModelSummaries(ctx context.Context, user string, all bool) ([]base.UserModelSummary, error) {
+	var summaries []base.UserModelSummary
+	err := c.call(ctx, func(conn jujuapi.Connection) error {
+		var err error
+		summaries, err = modelmanager.NewClient(conn).ListModelSummaries(user, all)
+		return err
+	})
+	return summaries, err
+}
+
+// Grant grants the given user the given access level on the models with
+// the given UUIDs.
+func (c *Client) Grant(ctx context.Context, user, access string, modelUUIDs ...string) error {
+	return c.call(ctx, func(conn jujuapi.Connection) error {
+		return modelmanager.NewClient(conn).GrantModel(user, access, modelUUIDs...)
+	})
+}
+
+// AuditQuery returns the audit events matching the given request.
+func (c *Client) AuditQuery(ctx context.Context, req *params.FindAuditEventsRequest) (params.AuditEvents, error) {
+	var events params.AuditEvents
+	err := c.call(ctx, func(conn jujuapi.Connection) error {
+		var err error
+		events, err = api.NewClient(conn).FindAuditEvents(req)
+		return err
+	})
+	return events, err
+}
+
+// JIMM calls f with a client for the JIMM facade, giving access to the
+// JIMM API methods that have no typed method on Client. The call is
+// retried if the connection is broken.
+func (c *Client) JIMM(ctx context.Context, f func(*api.Client) error) error {
+	return c.call(ctx, func(conn jujuapi.Connection) error {
+		return f(api.NewClient(conn))
+	})
+}
+
+// Whoami returns the identity of the user authenticated by the session
+// cookie held in the client's HTTP client.
+func (c *Client) Whoami(ctx context.Context) (params.WhoamiResponse, error) {
+	var resp params.WhoamiResponse
+	err := c.getJSON(ctx, "/auth/whoami", &resp)
+	return resp, err
+}
+
+// call calls f with a connection to JIMM. If the connection cannot be
+// established, or is broken during the call, the call is retried with a
+// new connection.
+func (c *Client) call(ctx context.Context, f func(jujuapi.Connection) error) error {
+	var err error
+	for i := 0; ; i++ {
+		var conn jujuapi.Connection
+		conn, err = c.connection(ctx)
+		if err == nil {
+			err = f(conn)
+			if err == nil || !rpc.IsShutdownErr(err) && !conn.IsBroken() {
+				return err
+			}
+			c.discard(conn)
+		}
+		if i >= c.p.Retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.p.RetryDelay):
+		}
+	}
+}
+
+// connection returns the current connection to JIMM, dialing a new
+// connection if there isn't one.
+func (c *Client) connection(ctx context.Context) (jujuapi.Connection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		return c.conn, nil
+	}
+	var lp jujuapi.LoginProvider
+	if c.p.ClientID != "" {
+		lp = jujuapi.NewClientCredentialsLoginProvider(c.p.ClientID, c.p.ClientSecret)
+	} else {
+		lp = jujuapi.NewSessionTokenLoginProvider(c.p.SessionToken, io.Discard, nil)
+	}
+	opts := jujuapi.DefaultDialOpts()
+	opts.LoginProvider = lp
+	opts.DialTimeout = c.p.DialTimeout
+	opts.InsecureSkipVerify = c.p.InsecureSkipVerify
+	if deadline, ok := ctx.Deadline(); ok {
+		if d := time.Until(deadline); d < opts.DialTimeout {
+			opts.DialTimeout = d
+		}
+	}
+	conn, err := jujuapi.Open(&jujuapi.Info{
+		Addrs:  []string{c.p.Address},
+		CACert: c.p.CACert,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	return conn, nil
+}
+
+// discard closes the given connection and, if it is the current
+// connection, ensures the next call dials a new one.
+func (c *Client) discard(conn jujuapi.Connection) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	conn.Close()
+}
+
+// getJSON performs a GET request on the REST endpoint with the given path
+// and unmarshals the JSON response into v. Requests that fail before a
+// response is received, and those receiving a 5xx response, are retried.
+func (c *Client) getJSON(ctx context.Context, path string, v interface{}) error {
+	u := url.URL{
+		Scheme: "https",
+		Host:   c.p.Address,
+		Path:   path,
+	}
+	var err error
+	for i := 0; ; i++ {
+		var retry bool
+		retry, err = c.doGetJSON(ctx, u.String(), v)
+		if err == nil || !retry || i >= c.p.Retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.p.RetryDelay):
+		}
+	}
+}
+
+func (c *Client) doGetJSON(ctx context.Context, u string, v interface{}) (retry bool, _ error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.p.HTTPClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp.StatusCode >= 500, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    string(body),
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("jimmclient: cannot decode response: %w", err)
+	}
+	return false, nil
+}
+
+// An HTTPError is returned when a REST endpoint responds with an
+// unexpected status.
+type HTTPError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Message holds the start of the response body.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("jimmclient: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("jimmclient: %s: %s", http.StatusText(e.StatusCode), e.Message)
+}
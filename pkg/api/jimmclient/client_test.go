@@ -0,0 +1,93 @@
+// Copyright 2024 Canonical.
+
+package jimmclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+
+	"github.com/canonical/jimm/v3/pkg/api/jimmclient"
+	"github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestNewValidatesParams(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := jimmclient.New(jimmclient.Params{SessionToken: "token"})
+	c.Check(err, qt.ErrorMatches, `jimmclient: address not specified`)
+	_, err = jimmclient.New(jimmclient.Params{Address: "jimm.example.com:443"})
+	c.Check(err, qt.ErrorMatches, `jimmclient: no credentials specified`)
+	client, err := jimmclient.New(jimmclient.Params{Address: "jimm.example.com:443", ClientID: "id", ClientSecret: "secret"})
+	c.Assert(err, qt.IsNil)
+	c.Check(client.Close(), qt.IsNil)
+}
+
+func TestWhoami(t *testing.T) {
+	c := qt.New(t)
+
+	calls := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		c.Check(req.URL.Path, qt.Equals, "/auth/whoami")
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"display-name":"Alice","email":"alice@canonical.com"}`))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	c.Assert(err, qt.IsNil)
+
+	client, err := jimmclient.New(jimmclient.Params{
+		Address:      u.Host,
+		SessionToken: "token",
+		HTTPClient:   srv.Client(),
+		RetryDelay:   time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+
+	resp, err := client.Whoami(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Check(resp, qt.DeepEquals, params.WhoamiResponse{
+		DisplayName: "Alice",
+		Email:       "alice@canonical.com",
+	})
+	c.Check(calls, qt.Equals, 2)
+}
+
+func TestWhoamiUnauthorized(t *testing.T) {
+	c := qt.New(t)
+
+	calls := 0
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("no session"))
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	c.Assert(err, qt.IsNil)
+
+	client, err := jimmclient.New(jimmclient.Params{
+		Address:      u.Host,
+		SessionToken: "token",
+		HTTPClient:   srv.Client(),
+		RetryDelay:   time.Millisecond,
+	})
+	c.Assert(err, qt.IsNil)
+
+	_, err = client.Whoami(context.Background())
+	c.Check(err, qt.ErrorMatches, `jimmclient: Unauthorized: no session`)
+	var httpErr *jimmclient.HTTPError
+	c.Assert(err, qt.ErrorAs, &httpErr)
+	c.Check(httpErr.StatusCode, qt.Equals, http.StatusUnauthorized)
+	c.Check(calls, qt.Equals, 1)
+}
@@ -0,0 +1,607 @@
+// Copyright 2024 Canonical.
+
+// Package jimmtest provides an in-memory fake of the JIMM API for use in
+// the tests of projects that use JIMM, such as dashboards and CLI
+// plugins. The fake needs no database, controllers or other services,
+// its state is seeded using the methods on Server and it is driven
+// through an api.Client as the real JIMM would be.
+//
+// The fake implements the controller, group, model import and whoami
+// methods of the JIMM facade. Any other method, or a method that needs
+// to behave differently, can be provided with Server.Handle.
+package jimmtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	jujuparams "github.com/juju/juju/rpc/params"
+	"github.com/juju/names/v5"
+
+	"github.com/canonical/jimm/v3/pkg/api"
+	"github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+// Epoch is the time reported by the fake clock of a new Server.
+var Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// A User is an identity known to the fake JIMM.
+type User struct {
+	// Name is the name of the identity, for example
+	// "alice@canonical.com".
+	Name string
+
+	// DisplayName is the display name of the identity.
+	DisplayName string
+
+	// Admin is true if the identity is a JIMM administrator.
+	Admin bool
+
+	// Groups holds the names of the groups the identity is a member of.
+	Groups []string
+}
+
+// A Controller is a controller known to the fake JIMM.
+type Controller struct {
+	// Name is the name of the controller.
+	Name string
+
+	// UUID is the UUID of the controller. If this is empty when the
+	// controller is added a UUID is generated.
+	UUID string
+
+	// PublicAddress is the public address of the controller.
+	PublicAddress string
+
+	// CloudTag and CloudRegion are the cloud and region the controller
+	// is running in.
+	CloudTag    string
+	CloudRegion string
+
+	// AgentVersion is the version of the controller's agent.
+	AgentVersion string
+
+	// Tiers holds the tiers the controller belongs to.
+	Tiers []string
+
+	// Deprecated is true if the controller is deprecated.
+	Deprecated bool
+
+	// Unavailable is true if JIMM cannot connect to the controller.
+	// Only unavailable controllers can be removed without force.
+	Unavailable bool
+}
+
+// A Model is a model known to the fake JIMM.
+type Model struct {
+	// UUID is the UUID of the model. If this is empty when the model is
+	// added a UUID is generated.
+	UUID string
+
+	// Name is the name of the model.
+	Name string
+
+	// Owner is the name of the identity that owns the model.
+	Owner string
+
+	// Controller is the name of the controller hosting the model.
+	Controller string
+
+	// Origin records how the model came to be managed by JIMM, one of
+	// the params.ModelOrigin values. If this is empty when the model is
+	// added params.ModelOriginCreated is used.
+	Origin string
+}
+
+// A Handler handles a request to the fake JIMM made by the named user.
+// The request parameters are given in their JSON encoding. The returned
+// value is encoded as the response.
+type Handler func(user string, req json.RawMessage) (interface{}, error)
+
+// A Server is an in-memory fake of JIMM. A Server is safe for concurrent
+// use.
+type Server struct {
+	// Now returns the current time. NewServer sets it to return Epoch,
+	// it may be replaced to control the time seen by the fake.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	seq         int
+	users       map[string]User
+	controllers map[string]Controller
+	models      map[string]Model
+	groups      map[string]params.Group
+	handlers    map[string]Handler
+}
+
+// NewServer returns a new Server with no state.
+func NewServer() *Server {
+	return &Server{
+		Now:         func() time.Time { return Epoch },
+		users:       make(map[string]User),
+		controllers: make(map[string]Controller),
+		models:      make(map[string]Model),
+		groups:      make(map[string]params.Group),
+		handlers:    make(map[string]Handler),
+	}
+}
+
+// Client returns a JIMM API client that makes requests to the fake as
+// the named user.
+func (s *Server) Client(user string) *api.Client {
+	return api.NewClient(s.Caller(user))
+}
+
+// Caller returns an APICaller that makes requests to the fake as the
+// named user. The user does not need to have been added, an unknown user
+// is treated as an identity that is not an administrator and is not in
+// any groups.
+func (s *Server) Caller(user string) api.APICaller {
+	return caller{s: s, user: user}
+}
+
+// Handle sets the handler used for the named JIMM facade method,
+// replacing any built in behaviour.
+func (s *Server) Handle(request string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[request] = h
+}
+
+// AddUser adds, or replaces, the given identity.
+func (s *Server) AddUser(u User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[u.Name] = u
+}
+
+// AddController adds, or replaces, the given controller and returns it
+// as stored.
+func (s *Server) AddController(c Controller) Controller {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c.UUID == "" {
+		c.UUID = s.newUUID()
+	}
+	s.controllers[c.Name] = c
+	return c
+}
+
+// AddModel adds, or replaces, the given model and returns it as stored.
+func (s *Server) AddModel(m Model) Model {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if m.UUID == "" {
+		m.UUID = s.newUUID()
+	}
+	if m.Origin == "" {
+		m.Origin = params.ModelOriginCreated
+	}
+	s.models[m.UUID] = m
+	return m
+}
+
+// AddGroup adds a group with the given name and returns it.
+func (s *Server) AddGroup(name string) params.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addGroup(name)
+}
+
+// Controllers returns the controllers held by the fake, ordered by name.
+func (s *Server) Controllers() []Controller {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	controllers := make([]Controller, 0, len(s.controllers))
+	for _, c := range s.controllers {
+		controllers = append(controllers, c)
+	}
+	sort.Slice(controllers, func(i, j int) bool { return controllers[i].Name < controllers[j].Name })
+	return controllers
+}
+
+// Models returns the models held by the fake, ordered by UUID.
+func (s *Server) Models() []Model {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	models := make([]Model, 0, len(s.models))
+	for _, m := range s.models {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].UUID < models[j].UUID })
+	return models
+}
+
+// Groups returns the groups held by the fake, ordered by name.
+func (s *Server) Groups() []params.Group {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedGroups()
+}
+
+// newUUID returns a new UUID. The UUIDs are generated in sequence so
+// that tests are repeatable. The mutex must be held.
+func (s *Server) newUUID() string {
+	s.seq++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", s.seq)
+}
+
+// addGroup adds a group with the given name. The mutex must be held.
+func (s *Server) addGroup(name string) params.Group {
+	now := s.Now().Format(time.RFC3339)
+	g := params.Group{
+		UUID:      s.newUUID(),
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	s.groups[name] = g
+	return g
+}
+
+// sortedGroups returns the groups ordered by name. The mutex must be
+// held.
+func (s *Server) sortedGroups() []params.Group {
+	groups := make([]params.Group, 0, len(s.groups))
+	for _, g := range s.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// user returns the named identity. The mutex must be held.
+func (s *Server) user(name string) User {
+	u, ok := s.users[name]
+	if !ok {
+		u = User{Name: name}
+	}
+	return u
+}
+
+// call handles a request made by the named user.
+func (s *Server) call(user, request string, req json.RawMessage) (interface{}, error) {
+	s.mu.Lock()
+	h := s.handlers[request]
+	s.mu.Unlock()
+	if h != nil {
+		return h(user, req)
+	}
+	f := builtinHandlers[request]
+	if f == nil {
+		return nil, errorf(jujuparams.CodeNotImplemented, "%s not implemented by fake JIMM", request)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return f(s, s.user(user), req)
+}
+
+// A builtinHandler handles a request to the fake, the mutex is held.
+type builtinHandler func(s *Server, u User, req json.RawMessage) (interface{}, error)
+
+var builtinHandlers = map[string]builtinHandler{
+	"AddController":           (*Server).addController,
+	"AddGroup":                (*Server).addGroupRequest,
+	"GetGroup":                (*Server).getGroup,
+	"ImportModel":             (*Server).importModel,
+	"ListControllers":         (*Server).listControllers,
+	"ListGroups":              (*Server).listGroups,
+	"RemoveController":        (*Server).removeController,
+	"RemoveGroup":             (*Server).removeGroup,
+	"RenameGroup":             (*Server).renameGroup,
+	"SetControllerDeprecated": (*Server).setControllerDeprecated,
+	"Whoami":                  (*Server).whoami,
+}
+
+func (s *Server) addController(u User, req json.RawMessage) (interface{}, error) {
+	var args params.AddControllerRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	if args.Name == "" {
+		return nil, errorf(jujuparams.CodeBadRequest, "name not specified")
+	}
+	if _, ok := s.controllers[args.Name]; ok {
+		return nil, errorf(jujuparams.CodeAlreadyExists, "controller %s already exists", args.Name)
+	}
+	c := Controller{
+		Name:          args.Name,
+		UUID:          args.UUID,
+		PublicAddress: args.PublicAddress,
+	}
+	if c.UUID == "" {
+		c.UUID = s.newUUID()
+	}
+	s.controllers[c.Name] = c
+	return s.controllerInfo(c), nil
+}
+
+func (s *Server) listControllers(u User, _ json.RawMessage) (interface{}, error) {
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	var resp params.ListControllersResponse
+	for _, name := range sortedKeys(s.controllers) {
+		resp.Controllers = append(resp.Controllers, s.controllerInfo(s.controllers[name]))
+	}
+	return resp, nil
+}
+
+func (s *Server) removeController(u User, req json.RawMessage) (interface{}, error) {
+	var args params.RemoveControllerRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	c, ok := s.controllers[args.Name]
+	if !ok {
+		return nil, errorf(jujuparams.CodeNotFound, "controller not found")
+	}
+	if !(args.Force || c.Unavailable) {
+		return nil, errorf(params.CodeStillAlive, "controller is still alive")
+	}
+	for uuid, m := range s.models {
+		if m.Controller == c.Name {
+			delete(s.models, uuid)
+		}
+	}
+	delete(s.controllers, c.Name)
+	return s.controllerInfo(c), nil
+}
+
+func (s *Server) setControllerDeprecated(u User, req json.RawMessage) (interface{}, error) {
+	var args params.SetControllerDeprecatedRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	c, ok := s.controllers[args.Name]
+	if !ok {
+		return nil, errorf(jujuparams.CodeNotFound, "controller not found")
+	}
+	c.Deprecated = args.Deprecated
+	s.controllers[c.Name] = c
+	return s.controllerInfo(c), nil
+}
+
+func (s *Server) controllerInfo(c Controller) params.ControllerInfo {
+	status := "available"
+	switch {
+	case c.Unavailable:
+		status = "unavailable"
+	case c.Deprecated:
+		status = "deprecated"
+	}
+	return params.ControllerInfo{
+		Name:          c.Name,
+		UUID:          c.UUID,
+		PublicAddress: c.PublicAddress,
+		CloudTag:      c.CloudTag,
+		CloudRegion:   c.CloudRegion,
+		AgentVersion:  c.AgentVersion,
+		Tiers:         slices.Clone(c.Tiers),
+		Status:        jujuparams.EntityStatus{Status: status},
+	}
+}
+
+func (s *Server) addGroupRequest(u User, req json.RawMessage) (interface{}, error) {
+	var args params.AddGroupRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	if _, ok := s.groups[args.Name]; ok {
+		return nil, errorf(jujuparams.CodeAlreadyExists, "group %s already exists", args.Name)
+	}
+	return params.AddGroupResponse{Group: s.addGroup(args.Name)}, nil
+}
+
+func (s *Server) getGroup(u User, req json.RawMessage) (interface{}, error) {
+	var args params.GetGroupRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	for _, g := range s.groups {
+		if (args.UUID != "" && g.UUID == args.UUID) || (args.Name != "" && g.Name == args.Name) {
+			return params.GetGroupResponse{Group: g}, nil
+		}
+	}
+	return nil, errorf(jujuparams.CodeNotFound, "group not found")
+}
+
+func (s *Server) renameGroup(u User, req json.RawMessage) (interface{}, error) {
+	var args params.RenameGroupRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	g, ok := s.groups[args.Name]
+	if !ok {
+		return nil, errorf(jujuparams.CodeNotFound, "group not found")
+	}
+	if _, ok := s.groups[args.NewName]; ok {
+		return nil, errorf(jujuparams.CodeAlreadyExists, "group %s already exists", args.NewName)
+	}
+	delete(s.groups, g.Name)
+	g.Name = args.NewName
+	g.UpdatedAt = s.Now().Format(time.RFC3339)
+	s.groups[g.Name] = g
+	return nil, nil
+}
+
+func (s *Server) removeGroup(u User, req json.RawMessage) (interface{}, error) {
+	var args params.RemoveGroupRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	if _, ok := s.groups[args.Name]; !ok {
+		return nil, errorf(jujuparams.CodeNotFound, "group not found")
+	}
+	delete(s.groups, args.Name)
+	return nil, nil
+}
+
+func (s *Server) listGroups(u User, req json.RawMessage) (interface{}, error) {
+	var args params.ListGroupsRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	groups := s.sortedGroups()
+	if args.Offset > 0 {
+		groups = groups[min(args.Offset, len(groups)):]
+	}
+	if args.Limit > 0 {
+		groups = groups[:min(args.Limit, len(groups))]
+	}
+	return params.ListGroupResponse{Groups: groups}, nil
+}
+
+func (s *Server) importModel(u User, req json.RawMessage) (interface{}, error) {
+	var args params.ImportModelRequest
+	if err := decode(req, &args); err != nil {
+		return nil, err
+	}
+	if !u.Admin {
+		return nil, errUnauthorized()
+	}
+	origin := args.Origin
+	switch origin {
+	case "":
+		origin = params.ModelOriginAdopted
+	case params.ModelOriginAdopted, params.ModelOriginMigrated, params.ModelOriginRestored:
+	default:
+		return nil, errorf(jujuparams.CodeBadRequest, "invalid model origin %q", origin)
+	}
+	mt, err := names.ParseModelTag(args.ModelTag)
+	if err != nil {
+		return nil, errorf(jujuparams.CodeBadRequest, "invalid model tag")
+	}
+	if _, ok := s.controllers[args.Controller]; !ok {
+		return nil, errorf(jujuparams.CodeNotFound, "controller not found")
+	}
+	if _, ok := s.models[mt.Id()]; ok {
+		return nil, errorf(jujuparams.CodeAlreadyExists, "model already exists")
+	}
+	owner := u.Name
+	if args.Owner != "" {
+		owner = args.Owner
+	}
+	s.models[mt.Id()] = Model{
+		UUID:       mt.Id(),
+		Owner:      owner,
+		Controller: args.Controller,
+		Origin:     origin,
+	}
+	return nil, nil
+}
+
+func (s *Server) whoami(u User, _ json.RawMessage) (interface{}, error) {
+	resp := params.IdentitySummary{
+		UserTag:         names.NewUserTag(u.Name).String(),
+		DisplayName:     u.DisplayName,
+		Groups:          slices.Clone(u.Groups),
+		ControllerAdmin: u.Admin,
+	}
+	if resp.Groups == nil {
+		resp.Groups = []string{}
+	}
+	controllers := make(map[string]bool)
+	for _, m := range s.models {
+		if u.Admin || m.Owner == u.Name {
+			resp.Models++
+			controllers[m.Controller] = true
+		}
+	}
+	resp.Controllers = len(controllers)
+	if u.Admin {
+		resp.Controllers = len(s.controllers)
+	}
+	return resp, nil
+}
+
+// A caller is an api.APICaller that makes requests to a Server as a
+// user.
+type caller struct {
+	s    *Server
+	user string
+}
+
+// APICall implements api.APICaller. Requests and responses are encoded
+// as JSON, as they would be on the wire, so that the fake cannot share
+// state with its callers.
+func (c caller) APICall(objType string, version int, id, request string, args, response interface{}) error {
+	if objType != "JIMM" {
+		return errorf(jujuparams.CodeNotImplemented, "unknown object type %q", objType)
+	}
+	req, err := json.Marshal(args)
+	if err != nil {
+		return errorf(jujuparams.CodeBadRequest, "cannot encode request: %s", err)
+	}
+	resp, err := c.s.call(c.user, request, req)
+	if err != nil {
+		return err
+	}
+	if response == nil || resp == nil {
+		return nil
+	}
+	buf, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("cannot encode response: %w", err)
+	}
+	return json.Unmarshal(buf, response)
+}
+
+// decode decodes the JSON encoded request into v.
+func decode(req json.RawMessage, v interface{}) error {
+	if len(req) == 0 || string(req) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(req, v); err != nil {
+		return errorf(jujuparams.CodeBadRequest, "cannot decode request: %s", err)
+	}
+	return nil
+}
+
+func errorf(code, format string, args ...interface{}) error {
+	return &jujuparams.Error{
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func errUnauthorized() error {
+	return errorf(jujuparams.CodeUnauthorized, "unauthorized")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
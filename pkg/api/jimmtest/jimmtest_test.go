@@ -0,0 +1,157 @@
+// Copyright 2024 Canonical.
+
+package jimmtest_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	jujuparams "github.com/juju/juju/rpc/params"
+
+	"github.com/canonical/jimm/v3/pkg/api/jimmtest"
+	"github.com/canonical/jimm/v3/pkg/api/params"
+)
+
+func TestControllers(t *testing.T) {
+	c := qt.New(t)
+
+	s := jimmtest.NewServer()
+	s.AddUser(jimmtest.User{Name: "admin@canonical.com", Admin: true})
+	s.AddController(jimmtest.Controller{Name: "controller-1", Unavailable: true})
+	admin := s.Client("admin@canonical.com")
+
+	info, err := admin.AddController(&params.AddControllerRequest{
+		Name:          "controller-2",
+		PublicAddress: "controller-2.example.com:443",
+	})
+	c.Assert(err, qt.IsNil)
+	c.Check(info.UUID, qt.Equals, "00000000-0000-0000-0000-000000000002")
+
+	_, err = admin.AddController(&params.AddControllerRequest{Name: "controller-2"})
+	c.Check(jujuparams.IsCodeAlreadyExists(err), qt.IsTrue, qt.Commentf("%v", err))
+
+	_, err = admin.SetControllerDeprecated(&params.SetControllerDeprecatedRequest{Name: "controller-2", Deprecated: true})
+	c.Assert(err, qt.IsNil)
+
+	controllers, err := admin.ListControllers()
+	c.Assert(err, qt.IsNil)
+	c.Check(controllers, qt.DeepEquals, []params.ControllerInfo{{
+		Name:   "controller-1",
+		UUID:   "00000000-0000-0000-0000-000000000001",
+		Status: jujuparams.EntityStatus{Status: "unavailable"},
+	}, {
+		Name:          "controller-2",
+		UUID:          "00000000-0000-0000-0000-000000000002",
+		PublicAddress: "controller-2.example.com:443",
+		Status:        jujuparams.EntityStatus{Status: "deprecated"},
+	}})
+
+	_, err = admin.RemoveController(&params.RemoveControllerRequest{Name: "controller-2"})
+	c.Check(err, qt.ErrorMatches, `controller is still alive`)
+	_, err = admin.RemoveController(&params.RemoveControllerRequest{Name: "controller-1"})
+	c.Assert(err, qt.IsNil)
+	c.Check(s.Controllers(), qt.HasLen, 1)
+
+	_, err = s.Client("bob@canonical.com").ListControllers()
+	c.Check(jujuparams.IsCodeUnauthorized(err), qt.IsTrue, qt.Commentf("%v", err))
+}
+
+func TestGroups(t *testing.T) {
+	c := qt.New(t)
+
+	s := jimmtest.NewServer()
+	s.AddUser(jimmtest.User{Name: "admin@canonical.com", Admin: true})
+	admin := s.Client("admin@canonical.com")
+
+	g, err := admin.AddGroup(&params.AddGroupRequest{Name: "group-1"})
+	c.Assert(err, qt.IsNil)
+	c.Check(g.Group, qt.DeepEquals, params.Group{
+		UUID:      "00000000-0000-0000-0000-000000000001",
+		Name:      "group-1",
+		CreatedAt: "2024-01-01T00:00:00Z",
+		UpdatedAt: "2024-01-01T00:00:00Z",
+	})
+	s.AddGroup("group-2")
+
+	s.Now = func() time.Time { return jimmtest.Epoch.Add(time.Hour) }
+	err = admin.RenameGroup(&params.RenameGroupRequest{Name: "group-1", NewName: "group-3"})
+	c.Assert(err, qt.IsNil)
+
+	resp, err := admin.GetGroup(&params.GetGroupRequest{UUID: g.UUID})
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Name, qt.Equals, "group-3")
+	c.Check(resp.UpdatedAt, qt.Equals, "2024-01-01T01:00:00Z")
+
+	groups, err := admin.ListGroups(&params.ListGroupsRequest{Limit: 1, Offset: 1})
+	c.Assert(err, qt.IsNil)
+	c.Check(groups, qt.HasLen, 1)
+	c.Check(groups[0].Name, qt.Equals, "group-3")
+
+	err = admin.RemoveGroup(&params.RemoveGroupRequest{Name: "group-2"})
+	c.Assert(err, qt.IsNil)
+	err = admin.RemoveGroup(&params.RemoveGroupRequest{Name: "group-2"})
+	c.Check(jujuparams.IsCodeNotFound(err), qt.IsTrue, qt.Commentf("%v", err))
+}
+
+func TestImportModelAndWhoami(t *testing.T) {
+	c := qt.New(t)
+
+	s := jimmtest.NewServer()
+	s.AddUser(jimmtest.User{Name: "admin@canonical.com", Admin: true})
+	s.AddUser(jimmtest.User{Name: "alice@canonical.com", DisplayName: "Alice", Groups: []string{"group-1"}})
+	s.AddController(jimmtest.Controller{Name: "controller-1"})
+	s.AddController(jimmtest.Controller{Name: "controller-2"})
+	s.AddModel(jimmtest.Model{Name: "model-1", Owner: "alice@canonical.com", Controller: "controller-1"})
+
+	err := s.Client("admin@canonical.com").ImportModel(&params.ImportModelRequest{
+		Controller: "controller-2",
+		ModelTag:   "model-00000001-0000-0000-0000-000000000001",
+		Owner:      "alice@canonical.com",
+		Origin:     params.ModelOriginMigrated,
+	})
+	c.Assert(err, qt.IsNil)
+	err = s.Client("admin@canonical.com").ImportModel(&params.ImportModelRequest{
+		Controller: "controller-2",
+		ModelTag:   "model-00000001-0000-0000-0000-000000000002",
+		Origin:     "stolen",
+	})
+	c.Check(err, qt.ErrorMatches, `invalid model origin "stolen"`)
+
+	models := s.Models()
+	c.Assert(models, qt.HasLen, 2)
+	c.Check(models[0].Origin, qt.Equals, params.ModelOriginCreated)
+	c.Check(models[1], qt.DeepEquals, jimmtest.Model{
+		UUID:       "00000001-0000-0000-0000-000000000001",
+		Owner:      "alice@canonical.com",
+		Controller: "controller-2",
+		Origin:     params.ModelOriginMigrated,
+	})
+
+	summary, err := s.Client("alice@canonical.com").Whoami()
+	c.Assert(err, qt.IsNil)
+	c.Check(summary, qt.DeepEquals, &params.IdentitySummary{
+		UserTag:     "user-alice@canonical.com",
+		DisplayName: "Alice",
+		Groups:      []string{"group-1"},
+		Models:      2,
+		Controllers: 2,
+	})
+}
+
+func TestHandle(t *testing.T) {
+	c := qt.New(t)
+
+	s := jimmtest.NewServer()
+	s.Handle("Version", func(user string, req json.RawMessage) (interface{}, error) {
+		c.Check(user, qt.Equals, "alice@canonical.com")
+		return params.VersionResponse{Version: "v3.0.0"}, nil
+	})
+	resp, err := s.Client("alice@canonical.com").Version()
+	c.Assert(err, qt.IsNil)
+	c.Check(resp.Version, qt.Equals, "v3.0.0")
+
+	_, err = s.Client("alice@canonical.com").UsageReport()
+	c.Check(jujuparams.IsCodeNotImplemented(err), qt.IsTrue, qt.Commentf("%v", err))
+}
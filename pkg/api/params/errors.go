@@ -3,5 +3,64 @@
 package params
 
 const (
-	CodeStillAlive = "still alive"
+	CodeStillAlive             = "still alive"
+	CodeModelCreationCancelled = "model creation cancelled"
+	CodeModelFrozen            = "model frozen"
+	CodeModelHasDependents     = "model has dependents"
+	CodeConfirmationRequired   = "confirmation required"
+	CodeResultTooLarge         = "result too large"
 )
+
+// BulkErrorInfo is the additional information attached to each per-entry
+// error returned by a bulk facade method, such as DestroyModels or
+// ModifyModelAccess. It may be read with the UnmarshalInfo method of the
+// error.
+type BulkErrorInfo struct {
+	// Index is the index of the entry in the request.
+	Index int `json:"index"`
+
+	// Entity identifies the entry, usually by its tag.
+	Entity string `json:"entity,omitempty"`
+
+	// Retryable is true if the entry failed in a way that retrying it
+	// may succeed, for example because a controller could not be
+	// reached.
+	Retryable bool `json:"retryable"`
+
+	// Causes holds the chain of errors that caused the entry to fail,
+	// outermost first.
+	Causes []ErrorCause `json:"causes,omitempty"`
+
+	// Summary summarises the outcome of every entry in the request.
+	Summary BulkErrorSummary `json:"summary"`
+}
+
+// An ErrorCause is an error in the chain of errors that caused an entry
+// in a bulk request to fail.
+type ErrorCause struct {
+	// Op is the operation that failed, if known.
+	Op string `json:"op,omitempty"`
+
+	// Code is the error code of the failure, if any.
+	Code string `json:"code,omitempty"`
+
+	// Message describes the failure.
+	Message string `json:"message,omitempty"`
+}
+
+// BulkErrorSummary summarises the outcome of a bulk request.
+type BulkErrorSummary struct {
+	// Total is the number of entries in the request.
+	Total int `json:"total"`
+
+	// Failed is the number of entries that failed.
+	Failed int `json:"failed"`
+
+	// Retryable is the number of failed entries that may succeed if
+	// retried.
+	Retryable int `json:"retryable"`
+
+	// Codes holds the number of failed entries with each error code.
+	// Failures without a code are counted under the empty code.
+	Codes map[string]int `json:"codes,omitempty"`
+}
@@ -3,6 +3,7 @@
 package params
 
 import (
+	"encoding/json"
 	"time"
 
 	jujuparams "github.com/juju/juju/rpc/params"
@@ -51,6 +52,12 @@ type AddControllerRequest struct {
 	// controller.
 	APIAddresses []string `json:"api-addresses,omitempty"`
 
+	// ClientAddresses contains the API addresses, in the form host:port,
+	// given to clients that connect to the controller directly if they
+	// differ from APIAddresses. This is used when JIMM connects to the
+	// controller over a private network that clients cannot reach.
+	ClientAddresses []string `json:"client-addresses,omitempty"`
+
 	// CACertificate contains the CA certificate to use to validate the
 	// connection to the controller. This is not needed if certificate is
 	// signed by a public CA.
@@ -63,6 +70,59 @@ type AddControllerRequest struct {
 	// Password contains the password that JIMM should use to connect to
 	// the controller.
 	Password string `json:"password"`
+
+	// ProxyURL contains the URL of a SOCKS5 (socks5://) or HTTP CONNECT
+	// (http://) proxy JIMM should use to connect to the controller.
+	ProxyURL string `json:"proxy-url,omitempty"`
+
+	// SSHJumpHost contains the address, in the form user@host[:port],
+	// of an SSH host JIMM should tunnel through to connect to the
+	// controller. JIMM authenticates to the jump host using the keys
+	// held by its SSH agent.
+	SSHJumpHost string `json:"ssh-jump-host,omitempty"`
+
+	// SSHJumpHostKey contains the public key, in authorized_keys format,
+	// of the SSH jump host. This must be set if SSHJumpHost is set.
+	SSHJumpHostKey string `json:"ssh-jump-host-key,omitempty"`
+
+	// TLSMinVersion contains the minimum TLS version, either "1.2" or
+	// "1.3", JIMM accepts when connecting to the controller. The
+	// default is "1.2".
+	TLSMinVersion string `json:"tls-min-version,omitempty"`
+
+	// TLSSystemCAFallback, if true, makes JIMM accept controller
+	// certificates signed by the system CAs as well as those signed by
+	// CACertificate. By default only CACertificate is trusted when it
+	// is set.
+	TLSSystemCAFallback bool `json:"tls-system-ca-fallback,omitempty"`
+
+	// DryRun, if true, performs the validation and capability detection
+	// of adding the controller, and returns the controller that would
+	// be added, without storing anything.
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// CloudCredentialAccessRequest is the request used to grant or revoke
+// the ability to create models using a cloud credential.
+type CloudCredentialAccessRequest struct {
+	// CloudCredentialTag is the tag of the cloud credential being shared.
+	CloudCredentialTag string `json:"cloud-credential-tag"`
+
+	// Entity is the tag of the user, or group (group-<name>), whose
+	// access is being modified.
+	Entity string `json:"entity"`
+}
+
+// SetDefaultCloudCredentialRequest is the request used to set the cloud
+// credential used by default for the user's models on a cloud.
+type SetDefaultCloudCredentialRequest struct {
+	// CloudTag is the tag of the cloud.
+	CloudTag string `json:"cloud-tag"`
+
+	// CredentialName is the name of the user's credential for the
+	// cloud to use by default. If this is empty the user's default for
+	// the cloud is removed.
+	CredentialName string `json:"credential-name,omitempty"`
 }
 
 // AuditLogAccessRequest is the request used to modify a user's access
@@ -157,11 +217,51 @@ type ControllerInfo struct {
 	// controller.
 	APIAddresses []string `json:"api-addresses,omitempty"`
 
+	// ClientAddresses contains the API addresses given to clients that
+	// connect to the controller directly, if they differ from
+	// APIAddresses.
+	ClientAddresses []string `json:"client-addresses,omitempty"`
+
 	// CACertificate contains the CA certificate to use to validate the
 	// connection to the controller. This is not needed if certificate is
 	// signed by a public CA.
 	CACertificate string `json:"ca-certificate,omitempty"`
 
+	// ProxyURL contains the URL of the proxy JIMM uses to connect to the
+	// controller, if any, without any credentials it contains.
+	ProxyURL string `json:"proxy-url,omitempty"`
+
+	// SSHJumpHost contains the address of the SSH jump host JIMM uses to
+	// connect to the controller, if any.
+	SSHJumpHost string `json:"ssh-jump-host,omitempty"`
+
+	// DialTimeout contains the timeout for connecting to the controller,
+	// if configured.
+	DialTimeout string `json:"dial-timeout,omitempty"`
+
+	// RPCTimeout contains the timeout for the login and keepalive
+	// requests JIMM sends to the controller, if configured.
+	RPCTimeout string `json:"rpc-timeout,omitempty"`
+
+	// KeepaliveInterval contains the interval at which JIMM checks its
+	// connections to the controller, if configured.
+	KeepaliveInterval string `json:"keepalive-interval,omitempty"`
+
+	// Tiers contains the tiers the controller belongs to.
+	Tiers []string `json:"tiers,omitempty"`
+
+	// TLSMinVersion contains the minimum TLS version JIMM accepts when
+	// connecting to the controller, if configured.
+	TLSMinVersion string `json:"tls-min-version,omitempty"`
+
+	// TLSSystemCAFallback reports whether JIMM accepts controller
+	// certificates signed by the system CAs as well as CACertificate.
+	TLSSystemCAFallback bool `json:"tls-system-ca-fallback,omitempty"`
+
+	// CertificateExpiry contains the expiry time of the certificate
+	// last presented by the controller, if known.
+	CertificateExpiry *time.Time `json:"certificate-expiry,omitempty"`
+
 	// CloudTag is the tag of the cloud this controller is running in.
 	CloudTag string `json:"cloud-tag,omitempty"`
 
@@ -175,9 +275,97 @@ type ControllerInfo struct {
 	// The version of the juju agent running on the controller.
 	AgentVersion string `json:"agent-version"`
 
+	// ServiceUserPending is true if JIMM failed to provision its
+	// service user on the controller when it was added and still
+	// connects with the credentials the controller was added with.
+	ServiceUserPending bool `json:"service-user-pending,omitempty"`
+
 	// Status contains the current status of the controller. The status
-	// will either be "available", "deprecated", or "unavailable".
+	// will either be "available", "deprecated", or "unavailable". The
+	// status of an unavailable controller holds the error encountered
+	// connecting to it.
 	Status jujuparams.EntityStatus `json:"status"`
+
+	// UnavailableReason categorises why the controller is unavailable,
+	// one of "dial-timeout", "auth-failure", "tls-error",
+	// "api-incompatible" or "unknown", if it is unavailable.
+	UnavailableReason string `json:"unavailable-reason,omitempty"`
+
+	// Health contains the health of the controller as observed by JIMM,
+	// if known.
+	Health *ControllerHealth `json:"health,omitempty"`
+}
+
+// ControllerHealth holds the rolling health of a controller, calculated
+// from the requests JIMM makes to the controller and the lag of the
+// watcher on the controller.
+type ControllerHealth struct {
+	// Score is the health score of the controller, between 0 (unusable)
+	// and 1 (healthy).
+	Score float64 `json:"score"`
+
+	// Degraded is true if the controller is considered degraded. New
+	// models are placed on degraded controllers only if there is no
+	// healthy controller for the cloud region.
+	Degraded bool `json:"degraded,omitempty"`
+
+	// ErrorRate is the proportion of recent requests to the controller
+	// that failed.
+	ErrorRate float64 `json:"error-rate"`
+
+	// LatencyMS is the recent average latency, in milliseconds, of
+	// requests to the controller.
+	LatencyMS int64 `json:"latency-ms"`
+
+	// WatcherLagMS is the recent average time, in milliseconds, taken to
+	// process the changes reported by the controller's watcher.
+	WatcherLagMS int64 `json:"watcher-lag-ms"`
+}
+
+// ControllerHealthState holds the availability and health of a
+// controller, as reported by the controller health watcher.
+type ControllerHealthState struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Status is the status of the controller, one of "available",
+	// "maintenance", "deprecated" or "unavailable".
+	Status string `json:"status"`
+
+	// Since is the time the controller became unavailable, if it is
+	// unavailable.
+	Since *time.Time `json:"since,omitempty"`
+
+	// UnavailableReason categorises why the controller is unavailable,
+	// if it is.
+	UnavailableReason string `json:"unavailable-reason,omitempty"`
+
+	// Monitor identifies the JIMM replica holding the lease to monitor
+	// the controller, if any.
+	Monitor string `json:"monitor,omitempty"`
+
+	// MonitorHeartbeat is the time the monitoring replica last renewed
+	// its lease on the controller.
+	MonitorHeartbeat *time.Time `json:"monitor-heartbeat,omitempty"`
+
+	// Health contains the health of the controller as observed by JIMM,
+	// if known.
+	Health *ControllerHealth `json:"health,omitempty"`
+}
+
+// ControllerHealthWatcherID holds the ID of a controller health watcher
+// created by WatchControllerHealth.
+type ControllerHealthWatcherID struct {
+	// WatcherID is the ID to pass to the Next and Stop methods of the
+	// ControllerHealthWatcher facade.
+	WatcherID string `json:"watcher-id"`
+}
+
+// ControllerHealthWatcherNextResults holds the result of a Next call on
+// the ControllerHealthWatcher facade.
+type ControllerHealthWatcherNextResults struct {
+	// Controllers holds the state of every controller.
+	Controllers []ControllerHealthState `json:"controllers"`
 }
 
 // A FindAuditEventsRequest finds audit events that match the specified
@@ -240,17 +428,566 @@ type SetControllerDeprecatedRequest struct {
 	Deprecated bool `json:"deprecated"`
 }
 
+// A SetControllerMaintenanceRequest is the request sent in a
+// SetControllerMaintenance method.
+type SetControllerMaintenanceRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Maintenance specifies whether the controller enters or leaves
+	// maintenance.
+	Maintenance bool `json:"maintenance"`
+}
+
+// SetControllerMaintenanceResponse holds the response to a
+// SetControllerMaintenance request.
+type SetControllerMaintenanceResponse struct {
+	// Controller holds the updated controller information.
+	Controller ControllerInfo `json:"controller" yaml:"controller"`
+
+	// DrainedSessions is the number of client sessions proxied to the
+	// controller that were drained when it entered maintenance.
+	DrainedSessions int `json:"drained-sessions" yaml:"drained-sessions"`
+}
+
+// GetControllerBootstrapProfileRequest holds a request for a controller's
+// bootstrap profile.
+type GetControllerBootstrapProfileRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Version is the version of the profile to get. If this is zero the
+	// latest version is returned.
+	Version int `json:"version,omitempty"`
+}
+
+// ControllerBootstrapProfile holds the captured configuration of a
+// controller, sufficient to re-bootstrap an equivalent controller.
+type ControllerBootstrapProfile struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Version is the version of the profile.
+	Version int `json:"version" yaml:"version"`
+
+	// CapturedAt is the time the profile was captured.
+	CapturedAt time.Time `json:"captured-at" yaml:"captured-at"`
+
+	// AgentVersion is the version of juju the controller was running.
+	AgentVersion string `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+
+	// CloudName and CloudRegion are the cloud and region hosting the
+	// controller.
+	CloudName   string `json:"cloud-name" yaml:"cloud-name"`
+	CloudRegion string `json:"cloud-region,omitempty" yaml:"cloud-region,omitempty"`
+
+	// ControllerConfig holds the configuration of the controller.
+	ControllerConfig map[string]interface{} `json:"controller-config" yaml:"controller-config"`
+
+	// Cloud holds the definition of the cloud hosting the controller.
+	Cloud jujuparams.Cloud `json:"cloud" yaml:"cloud"`
+}
+
+// SetControllerTimeoutsRequest is the request used to configure the
+// timeouts JIMM uses when communicating with a controller. Timeouts are
+// specified as Go durations, such as "45s". An empty value restores the
+// default behaviour.
+type SetControllerTimeoutsRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// DialTimeout is the maximum time allowed to connect to the
+	// controller.
+	DialTimeout string `json:"dial-timeout,omitempty"`
+
+	// RPCTimeout is the maximum time JIMM waits for responses to the
+	// login and keepalive requests it sends to the controller.
+	RPCTimeout string `json:"rpc-timeout,omitempty"`
+
+	// KeepaliveInterval is the interval at which JIMM checks that its
+	// connections to the controller are alive.
+	KeepaliveInterval string `json:"keepalive-interval,omitempty"`
+}
+
+// SetControllerTiersRequest is the request used to set the tiers a
+// controller belongs to. Models may request a tier by setting the
+// "jimm-controller-tier" model config attribute.
+type SetControllerTiersRequest struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Tiers are the tiers the controller belongs to. An empty list
+	// removes the controller from all tiers.
+	Tiers []string `json:"tiers,omitempty"`
+}
+
 // FullModelStatusRequest is the request that is sent in a FullModelStatus method.
 type FullModelStatusRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
 	ModelTag string
 	Patterns []string
 }
 
+// ExportModelBundleRequest is the request sent to export a model as a
+// bundle.
+type ExportModelBundleRequest struct {
+	// ModelTag identifies the model to export by its tag, its UUID or a
+	// path of the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+
+	// IncludeCharmDefaults includes the default values of charm
+	// configuration settings in the bundle.
+	IncludeCharmDefaults bool `json:"include-charm-defaults,omitempty"`
+
+	// StripCloudConstraints removes constraints that are specific to the
+	// cloud hosting the model, such as instance types and availability
+	// zones, from the bundle.
+	StripCloudConstraints bool `json:"strip-cloud-constraints,omitempty"`
+}
+
+// ExportModelBundleResponse holds an exported model bundle.
+type ExportModelBundleResponse struct {
+	// Bundle contains the bundle in YAML format.
+	Bundle string `json:"bundle"`
+
+	// DownloadURL is set, and Bundle is empty, if the bundle was too
+	// large to return inline. The bundle may be downloaded from the URL
+	// until it expires.
+	DownloadURL string `json:"download-url,omitempty"`
+}
+
+// DestroyModelsDryRunRequest holds the models that would be destroyed
+// by a DestroyModels call. No model is modified by a dry run.
+type DestroyModelsDryRunRequest struct {
+	Models []DestroyModelDryRunParams `json:"models"`
+}
+
+// DestroyModelDryRunParams holds the parameters for a dry run of
+// destroying a single model.
+type DestroyModelDryRunParams struct {
+	// ModelTag identifies the model that would be destroyed by its tag,
+	// its UUID or a path of the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+
+	// DestroyStorage holds the destroy-storage value that would be
+	// passed to DestroyModels. If this is nil any storage in the model
+	// would prevent it from being destroyed.
+	DestroyStorage *bool `json:"destroy-storage,omitempty"`
+}
+
+// DestroyModelsDryRunResponse holds the result of a DestroyModelsDryRun
+// call, there is one result for each requested model.
+type DestroyModelsDryRunResponse struct {
+	Results []DestroyModelImpactResult `json:"results"`
+}
+
+// DestroyModelImpactResult holds either the impact of destroying a model
+// or the error encountered determining it.
+type DestroyModelImpactResult struct {
+	Result *DestroyModelImpact `json:"result,omitempty"`
+	Error  *jujuparams.Error   `json:"error,omitempty"`
+}
+
+// DestroyModelImpact describes what would be affected by destroying a
+// model.
+type DestroyModelImpact struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// MachineCount is the number of machines that would be destroyed.
+	MachineCount int `json:"machine-count"`
+
+	// ApplicationCount is the number of applications that would be
+	// destroyed.
+	ApplicationCount int `json:"application-count"`
+
+	// UnitCount is the number of units that would be destroyed.
+	UnitCount int `json:"unit-count"`
+
+	// Storage holds the storage in the model and whether it would be
+	// detached or destroyed.
+	Storage []StorageImpact `json:"storage,omitempty"`
+
+	// StorageDecisionRequired is true if the model has storage and no
+	// destroy-storage value was given, in which case the model would
+	// not be destroyed.
+	StorageDecisionRequired bool `json:"storage-decision-required,omitempty"`
+
+	// Offers holds the offers from the model that have consumers, these
+	// relations would be broken.
+	Offers []OfferImpact `json:"offers,omitempty"`
+
+	// DependentModels holds the names, in the form <owner>/<name>, of
+	// the models that depend on the model, either directly or through
+	// other models. A model with dependents is only destroyed with
+	// force.
+	DependentModels []string `json:"dependent-models,omitempty"`
+}
+
+// Storage actions reported in a StorageImpact.
+const (
+	StorageActionDestroy = "destroy"
+	StorageActionDetach  = "detach"
+)
+
+// StorageImpact describes what would happen to a single volume or
+// filesystem if its model were destroyed.
+type StorageImpact struct {
+	// Kind is either "volume" or "filesystem".
+	Kind string `json:"kind"`
+
+	// ID is the juju ID of the storage.
+	ID string `json:"id"`
+
+	// ProviderID is the ID of the storage in the cloud provider.
+	ProviderID string `json:"provider-id,omitempty"`
+
+	// Detachable is true if the storage can outlive its machine.
+	Detachable bool `json:"detachable"`
+
+	// Action is the action that would be taken, either "destroy" or
+	// "detach". It is empty if StorageDecisionRequired is set.
+	Action string `json:"action,omitempty"`
+}
+
+// OfferImpact describes an offer that has consumers which would be
+// broken if its model were destroyed.
+type OfferImpact struct {
+	// OfferURL is the URL of the offer.
+	OfferURL string `json:"offer-url"`
+
+	// ConsumingModelTags holds the tags of the models with relations
+	// to the offer.
+	ConsumingModelTags []string `json:"consuming-model-tags"`
+
+	// ConnectionCount is the number of relations to the offer.
+	ConnectionCount int `json:"connection-count"`
+}
+
+// ModelMachinesRequest is the request sent in a ModelMachines call.
+type ModelMachinesRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+}
+
+// ModelMachinesResponse holds the machines in a model.
+type ModelMachinesResponse struct {
+	Machines []ModelMachine `json:"machines"`
+}
+
+// ModelMachine holds the placement and network details of a machine in
+// a model.
+type ModelMachine struct {
+	// ID is the ID of the machine in the model.
+	ID string `json:"id"`
+
+	// InstanceID is the ID of the machine's instance in the cloud.
+	InstanceID string `json:"instance-id,omitempty"`
+
+	// Hostname is the hostname of the machine.
+	Hostname string `json:"hostname,omitempty"`
+
+	// Life holds the life status of the machine.
+	Life string `json:"life"`
+
+	// AvailabilityZone is the availability zone the machine is in.
+	AvailabilityZone string `json:"availability-zone,omitempty"`
+
+	// Addresses holds the network addresses of the machine.
+	Addresses []jujuparams.Address `json:"addresses,omitempty"`
+
+	// Subnets holds the CIDRs of the subnets the machine is in.
+	Subnets []string `json:"subnets,omitempty"`
+}
+
+// ModelsStatusRequest is the request sent in a ModelsStatus call.
+type ModelsStatusRequest struct {
+	// Controller is the name of the controller to report on. If this
+	// is empty all controllers are reported on.
+	Controller string `json:"controller,omitempty"`
+}
+
+// Entities counted in model summaries by JIMM in addition to those
+// counted by Juju. A count of one of these entities is only included in
+// a model summary if it is non-zero.
+const (
+	// Containers is the number of machines in the model that are
+	// containers.
+	Containers jujuparams.CountedEntity = "containers"
+
+	// Offers is the number of application offers made from the model.
+	Offers jujuparams.CountedEntity = "offers"
+
+	// Relations is the number of relations in the model.
+	Relations jujuparams.CountedEntity = "relations"
+
+	// ActiveUnits is the number of units in the model with an active
+	// workload status.
+	ActiveUnits jujuparams.CountedEntity = "active-units"
+
+	// BlockedUnits is the number of units in the model with a blocked
+	// workload status.
+	BlockedUnits jujuparams.CountedEntity = "blocked-units"
+
+	// ErrorUnits is the number of units in the model with an error
+	// workload status.
+	ErrorUnits jujuparams.CountedEntity = "error-units"
+)
+
+// Model workload health values.
+const (
+	WorkloadHealthy   = "healthy"
+	WorkloadDegraded  = "degraded"
+	WorkloadUnhealthy = "unhealthy"
+)
+
+// ModelsStatusResponse holds an overview of the status of the
+// controllers and models known to JIMM.
+type ModelsStatusResponse struct {
+	Controllers []ControllerStatusOverview `json:"controllers"`
+	Models      []ModelStatusOverview      `json:"models"`
+}
+
+// ControllerStatusOverview holds an overview of the status of a
+// controller.
+type ControllerStatusOverview struct {
+	// Name is the name of the controller.
+	Name string `json:"name"`
+
+	// Available holds whether JIMM can currently contact the
+	// controller.
+	Available bool `json:"available"`
+
+	// UnavailableSince holds the time the controller became
+	// unavailable, if it is unavailable.
+	UnavailableSince *time.Time `json:"unavailable-since,omitempty"`
+
+	// UnavailableReason categorises why the controller is unavailable,
+	// if it is.
+	UnavailableReason string `json:"unavailable-reason,omitempty"`
+
+	// Deprecated holds whether the controller is deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// AgentVersion holds the version of the controller.
+	AgentVersion string `json:"agent-version,omitempty"`
+
+	// ModelCount is the number of models on the controller.
+	ModelCount int64 `json:"model-count"`
+
+	// MachineCount is the number of machines in the models on the
+	// controller.
+	MachineCount int64 `json:"machine-count"`
+}
+
+// ModelStatusOverview holds an overview of the status of a model.
+type ModelStatusOverview struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name"`
+
+	// Owner is the name of the owner of the model.
+	Owner string `json:"owner"`
+
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller"`
+
+	// Life holds the life status of the model.
+	Life string `json:"life"`
+
+	// Status holds the status of the model.
+	Status string `json:"status,omitempty"`
+
+	// MachineCount is the number of machines in the model.
+	MachineCount int64 `json:"machine-count"`
+
+	// ContainerCount is the number of machines in the model that are
+	// containers, these are included in MachineCount.
+	ContainerCount int64 `json:"container-count"`
+
+	// UnitCount is the number of units in the model.
+	UnitCount int64 `json:"unit-count"`
+
+	// OfferCount is the number of application offers made from the
+	// model.
+	OfferCount int64 `json:"offer-count"`
+
+	// RelationCount is the number of relations in the model.
+	RelationCount int64 `json:"relation-count"`
+
+	// UnhealthyUnitCount is the number of units in the model with a
+	// workload status of error or blocked.
+	UnhealthyUnitCount int64 `json:"unhealthy-unit-count"`
+
+	// WorkloadStatus holds the most severe workload status of the units
+	// in the model.
+	WorkloadStatus string `json:"workload-status,omitempty"`
+
+	// Health holds the workload health of the model, derived from the
+	// workload status of its units. This is one of WorkloadHealthy,
+	// WorkloadDegraded or WorkloadUnhealthy.
+	Health string `json:"health"`
+
+	// Origin holds how the model came to be managed by JIMM. It is
+	// omitted for models added before origins were recorded.
+	Origin *ModelOrigin `json:"origin,omitempty"`
+}
+
+// ModelControllerEndpointsRequest holds a request for the endpoints of
+// the controller hosting a model.
+type ModelControllerEndpointsRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+}
+
+// ModelControllerEndpoints holds the details needed to connect directly
+// to the controller hosting a model.
+type ModelControllerEndpoints struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// ControllerUUID is the UUID of the controller hosting the model.
+	ControllerUUID string `json:"controller-uuid" yaml:"controller-uuid"`
+
+	// Endpoints holds the host:port addresses of the controller's API,
+	// the controller's public address first if it has one.
+	Endpoints []string `json:"endpoints" yaml:"endpoints"`
+
+	// CACertificate holds the CA certificate of the controller's API, it
+	// is empty if the controller's certificate is signed by a public CA.
+	CACertificate string `json:"ca-certificate,omitempty" yaml:"ca-certificate,omitempty"`
+}
+
+// GetModelSummaryRequest holds a request for the summary of a model.
+type GetModelSummaryRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+}
+
+// ModelSummary holds the summary of a single model, built entirely from
+// the information held by JIMM.
+type ModelSummary struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+
+	// Owner is the name of the owner of the model.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Type is the type of the model.
+	Type string `json:"type" yaml:"type"`
+
+	// Cloud is the name of the cloud hosting the model.
+	Cloud string `json:"cloud" yaml:"cloud"`
+
+	// CloudRegion is the name of the cloud region hosting the model.
+	CloudRegion string `json:"cloud-region,omitempty" yaml:"cloud-region,omitempty"`
+
+	// CloudCredential is the tag of the cloud credential used by the
+	// model.
+	CloudCredential string `json:"cloud-credential,omitempty" yaml:"cloud-credential,omitempty"`
+
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// ControllerAvailable holds whether JIMM can currently contact the
+	// controller hosting the model.
+	ControllerAvailable bool `json:"controller-available" yaml:"controller-available"`
+
+	// AgentVersion holds the agent version of the model.
+	AgentVersion string `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+
+	// Access holds the requesting user's access level to the model.
+	Access string `json:"access" yaml:"access"`
+
+	// Life holds the life status of the model.
+	Life string `json:"life" yaml:"life"`
+
+	// Status holds the status of the model.
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// StatusInfo holds the message associated with the status of the
+	// model.
+	StatusInfo string `json:"status-info,omitempty" yaml:"status-info,omitempty"`
+
+	// StatusSince holds the time the status of the model last changed.
+	StatusSince *time.Time `json:"status-since,omitempty" yaml:"status-since,omitempty"`
+
+	// CoreCount is the number of CPU cores used by the model's
+	// machines.
+	CoreCount int64 `json:"core-count" yaml:"core-count"`
+
+	// MachineCount is the number of machines in the model.
+	MachineCount int64 `json:"machine-count" yaml:"machine-count"`
+
+	// ContainerCount is the number of machines in the model that are
+	// containers, these are included in MachineCount.
+	ContainerCount int64 `json:"container-count" yaml:"container-count"`
+
+	// UnitCount is the number of units in the model.
+	UnitCount int64 `json:"unit-count" yaml:"unit-count"`
+
+	// ActiveUnitCount is the number of units in the model with an active
+	// workload status.
+	ActiveUnitCount int64 `json:"active-unit-count" yaml:"active-unit-count"`
+
+	// BlockedUnitCount is the number of units in the model with a
+	// blocked workload status.
+	BlockedUnitCount int64 `json:"blocked-unit-count" yaml:"blocked-unit-count"`
+
+	// ErrorUnitCount is the number of units in the model with an error
+	// workload status.
+	ErrorUnitCount int64 `json:"error-unit-count" yaml:"error-unit-count"`
+
+	// OfferCount is the number of application offers made from the
+	// model.
+	OfferCount int64 `json:"offer-count" yaml:"offer-count"`
+
+	// RelationCount is the number of relations in the model.
+	RelationCount int64 `json:"relation-count" yaml:"relation-count"`
+
+	// WorkloadStatus holds the most severe workload status of the units
+	// in the model.
+	WorkloadStatus string `json:"workload-status,omitempty" yaml:"workload-status,omitempty"`
+
+	// Health holds the workload health of the model, one of
+	// WorkloadHealthy, WorkloadDegraded or WorkloadUnhealthy.
+	Health string `json:"health" yaml:"health"`
+
+	// Migration holds the migration of the model that JIMM is
+	// currently tracking, if there is one.
+	Migration *ModelMigration `json:"migration,omitempty" yaml:"migration,omitempty"`
+
+	// Origin holds how the model came to be managed by JIMM. It is
+	// omitted for models added before origins were recorded.
+	Origin *ModelOrigin `json:"origin,omitempty" yaml:"origin,omitempty"`
+
+	// LastUnitChange holds the time a unit was last added to, or removed
+	// from, the model.
+	LastUnitChange *time.Time `json:"last-unit-change,omitempty" yaml:"last-unit-change,omitempty"`
+
+	// LastConnection holds the time a user last connected to the model
+	// through JIMM.
+	LastConnection *time.Time `json:"last-connection,omitempty" yaml:"last-connection,omitempty"`
+}
+
 // UpdateMigratedModelRequest holds a request to check
 // if the specified model has been migrated to the specified controller
 // and update the model accordingly.
 type UpdateMigratedModelRequest struct {
-	// ModelTag holds the tag of the model that has been migrated.
+	// ModelTag identifies the model that has been migrated by its tag,
+	// its UUID or a path of the form <owner>/<name>.
 	ModelTag string `json:"model-tag"`
 	// TargetController holds the name of the controller the
 	// model has been migrated to.
@@ -270,6 +1007,43 @@ type ImportModelRequest struct {
 	// Owner specifies the new owner of the model after import.
 	// Can be empty to skip switching the owner.
 	Owner string `json:"owner"`
+
+	// Origin records how the model came to be on the controller, one of
+	// ModelOriginAdopted, ModelOriginMigrated or ModelOriginRestored. If
+	// this is empty ModelOriginAdopted is used.
+	Origin string `json:"origin,omitempty"`
+}
+
+// Kinds of model origin.
+const (
+	// ModelOriginCreated is the origin of models created through JIMM.
+	ModelOriginCreated = "created"
+
+	// ModelOriginAdopted is the origin of models that already existed
+	// on a controller and were imported into JIMM.
+	ModelOriginAdopted = "adopted"
+
+	// ModelOriginMigrated is the origin of models that were migrated to
+	// a JIMM controller from a controller outside JIMM and then
+	// imported.
+	ModelOriginMigrated = "migrated"
+
+	// ModelOriginRestored is the origin of models that were restored
+	// from a backup and then imported.
+	ModelOriginRestored = "restored"
+)
+
+// ModelOrigin holds how a model came to be managed by JIMM.
+type ModelOrigin struct {
+	// Kind is the kind of origin, see the ModelOrigin* constants.
+	Kind string `json:"kind"`
+
+	// Identity is the name of the identity that created or imported
+	// the model.
+	Identity string `json:"identity,omitempty"`
+
+	// Time is the time the model was created or imported.
+	Time time.Time `json:"time"`
 }
 
 // Authorisation request parameters / responses:
@@ -324,6 +1098,8 @@ type Group struct {
 	Name      string `json:"name" yaml:"name"`
 	CreatedAt string `json:"created_at" yaml:"created_at"`
 	UpdatedAt string `json:"updated_at" yaml:"updated_at"`
+	// CostCenter is the cost center attached to the group.
+	CostCenter string `json:"cost_center,omitempty" yaml:"cost_center,omitempty"`
 }
 
 // ListGroupResponse returns the group tuples currently residing within OpenFGA.
@@ -408,32 +1184,1296 @@ type PurgeLogsResponse struct {
 	DeletedCount int64 `json:"deleted-count" yaml:"deleted-count"`
 }
 
-// MigrateModelInfo represents a single migration where a source model
-// target controller must be specified with both the source model and
-// target controller residing within JIMM.
-type MigrateModelInfo struct {
-	// ModelTag is a tag of the form "model-<UIID>"
-	ModelTag string `json:"model-tag"`
-	// TargetController is the controller name of the form "<name>"
-	TargetController string `json:"target-controller"`
+// ResyncModelAccessRequest is the request used to re-sync model access
+// to the controllers.
+type ResyncModelAccessRequest struct {
+	// Controller, if set, restricts the re-sync to the models on the
+	// named controller.
+	Controller string `json:"controller,omitempty"`
+	// Apply fixes the differences found. If this is false the
+	// differences are only reported.
+	Apply bool `json:"apply,omitempty"`
 }
 
-// MigrateModelRequest allows for multiple migration requests to be made.
-type MigrateModelRequest struct {
-	Specs []MigrateModelInfo `json:"specs"`
-}
+// ResyncModelAccessResponse is the response returned by the
+// ResyncModelAccess method, and the result of the operation performing
+// the re-sync.
+type ResyncModelAccessResponse struct {
+	// Applied is true if the differences found are fixed.
+	Applied bool `json:"applied,omitempty" yaml:"applied,omitempty"`
 
-// LoginDeviceResponse holds the details to complete a LoginDevice flow.
-type LoginDeviceResponse struct {
-	// VerificationURI holds the URI that the user must navigate to
-	// when entering their "user-code" to consent to this authorisation
-	// request.
-	VerificationURI string `json:"verification-uri" yaml:"verification-uri"`
-	// UserCode holds the one-time use user consent code.
-	UserCode string `json:"user-code" yaml:"user-code"`
+	// ModelsChecked is the number of models whose access was checked.
+	ModelsChecked int `json:"models-checked,omitempty" yaml:"models-checked,omitempty"`
+
+	// Differences holds the differences found between JIMM's view of
+	// model access and the access held on the controllers.
+	Differences []ModelAccessDifference `json:"differences,omitempty" yaml:"differences,omitempty"`
+
+	// Errors holds any errors encountered checking or fixing access.
+	Errors []ModelAccessResyncError `json:"errors,omitempty" yaml:"errors,omitempty"`
+
+	// Operation is the ID of the operation performing the re-sync, the
+	// results of which are in the operation's result.
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
 }
 
-// GetDeviceSessionTokenResponse returns a session token to be used against
+// ModelAccessDifference describes a user whose access to a model on a
+// controller differs from the access JIMM expects.
+type ModelAccessDifference struct {
+	Controller string `json:"controller" yaml:"controller"`
+	ModelTag   string `json:"model-tag" yaml:"model-tag"`
+	User       string `json:"user" yaml:"user"`
+
+	// ExpectedAccess is the access JIMM expects the user to have, it
+	// is empty if the user should not have access.
+	ExpectedAccess string `json:"expected-access,omitempty" yaml:"expected-access,omitempty"`
+
+	// ControllerAccess is the access the user had on the controller,
+	// it is empty if the user had no access.
+	ControllerAccess string `json:"controller-access,omitempty" yaml:"controller-access,omitempty"`
+}
+
+// A ModelAccessDocument declares the access users and groups hold on a
+// set of models. It is the document exported by ExportModelAccess and
+// applied by ApplyModelAccess, so that model access may be kept under
+// version control.
+type ModelAccessDocument struct {
+	// Models holds the access declared for each model.
+	Models []ModelAccessSpec `json:"models" yaml:"models"`
+}
+
+// A ModelAccessSpec declares the access users and groups hold directly
+// on a model. The access of the model's owner is not managed by the
+// document.
+type ModelAccessSpec struct {
+	// Model identifies the model, either by a path of the form
+	// <owner>/<name>, its UUID or its tag.
+	Model string `json:"model" yaml:"model"`
+
+	// Users holds the access, "read", "write" or "admin", of each user
+	// keyed by user name.
+	Users map[string]string `json:"users,omitempty" yaml:"users,omitempty"`
+
+	// Groups holds the access, "read", "write" or "admin", of each
+	// group keyed by group name.
+	Groups map[string]string `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// ExportModelAccessRequest holds a request to export the access to a
+// model, or to every model of an owner, as a ModelAccessDocument.
+// Exactly one of ModelTag and Owner must be set.
+type ExportModelAccessRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag,omitempty"`
+
+	// Owner is the name of the identity whose models are exported.
+	Owner string `json:"owner,omitempty"`
+}
+
+// ApplyModelAccessRequest holds a request to make the access to models
+// match a ModelAccessDocument.
+type ApplyModelAccessRequest struct {
+	// Document is the document to apply.
+	Document ModelAccessDocument `json:"document"`
+
+	// DryRun, if true, reports the changes that would be made without
+	// making them.
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// A ModelAccessChange describes a change to the access a user or group
+// holds on a model.
+type ModelAccessChange struct {
+	// Model is the path, <owner>/<name>, of the model.
+	Model string `json:"model" yaml:"model"`
+
+	// Kind is the kind of entity, "user" or "group".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Name is the name of the user or group.
+	Name string `json:"name" yaml:"name"`
+
+	// From is the access held before the change, it is empty if the
+	// entity held no access.
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+
+	// To is the access held after the change, it is empty if the
+	// entity's access is removed.
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+// ApplyModelAccessResponse holds the outcome of applying a
+// ModelAccessDocument.
+type ApplyModelAccessResponse struct {
+	// DryRun is true if no changes were made.
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
+
+	// Changes holds the changes made, or that would be made in a dry
+	// run, ordered by model, kind and name. Applying a document that
+	// already matches makes no changes.
+	Changes []ModelAccessChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// ModelAccessResyncError holds an error encountered re-syncing or
+// auditing model access. ModelTag is empty if the error affected a
+// whole controller.
+type ModelAccessResyncError struct {
+	Controller string `json:"controller" yaml:"controller"`
+	ModelTag   string `json:"model-tag,omitempty" yaml:"model-tag,omitempty"`
+	Error      string `json:"error" yaml:"error"`
+}
+
+// AuditControllerAccessRequest is the request used to audit the access
+// held directly on the controllers.
+type AuditControllerAccessRequest struct {
+	// Controller, if set, restricts the audit to the named controller.
+	Controller string `json:"controller,omitempty"`
+}
+
+// AuditControllerAccessResponse is the response returned by the
+// AuditControllerAccess method.
+type AuditControllerAccessResponse struct {
+	// ControllersChecked is the number of controllers whose users were
+	// checked.
+	ControllersChecked int `json:"controllers-checked" yaml:"controllers-checked"`
+
+	// ModelsChecked is the number of models whose access was checked.
+	ModelsChecked int `json:"models-checked" yaml:"models-checked"`
+
+	// Findings holds the access found on the controllers that was not
+	// granted through JIMM. Nothing is changed on the controllers.
+	Findings []ControllerAccessFinding `json:"findings,omitempty" yaml:"findings,omitempty"`
+
+	// Errors holds any errors encountered checking access.
+	Errors []ModelAccessResyncError `json:"errors,omitempty" yaml:"errors,omitempty"`
+}
+
+// ControllerAccessFinding describes a user holding access on a controller
+// that is unknown to JIMM. ModelTag is empty if the finding is a local
+// user of the controller.
+type ControllerAccessFinding struct {
+	Controller string `json:"controller" yaml:"controller"`
+	ModelTag   string `json:"model-tag,omitempty" yaml:"model-tag,omitempty"`
+	User       string `json:"user" yaml:"user"`
+	Access     string `json:"access,omitempty" yaml:"access,omitempty"`
+	Reason     string `json:"reason" yaml:"reason"`
+}
+
+// CrossModelRelationGraphRequest is the request used to fetch the graph
+// of cross-model relations between models.
+type CrossModelRelationGraphRequest struct {
+	// ModelTag, if set, restricts the graph to the relations in which
+	// the given model offers or consumes an application. The model may
+	// be identified by its tag, its UUID or a path of the form
+	// <owner>/<name>.
+	ModelTag string `json:"model-tag,omitempty"`
+}
+
+// CrossModelRelationGraph is the graph of cross-model relations between
+// models. The nodes of the graph are models and the edges are
+// connections from consuming models to application offers.
+type CrossModelRelationGraph struct {
+	// Models contains the models that take part in the relations.
+	Models []CrossModelRelationGraphModel `json:"models"`
+
+	// Relations contains the cross-model relations between the models.
+	Relations []CrossModelRelation `json:"relations"`
+}
+
+// CrossModelRelationGraphModel describes a model in the cross-model
+// relation graph.
+type CrossModelRelationGraphModel struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// Name is the name of the model. This is empty if the model is not
+	// managed by JIMM.
+	Name string `json:"name,omitempty"`
+
+	// Owner is the owner of the model. This is empty if the model is
+	// not managed by JIMM.
+	Owner string `json:"owner,omitempty"`
+
+	// Controller is the name of the controller hosting the model. This
+	// is empty if the model is not managed by JIMM.
+	Controller string `json:"controller,omitempty"`
+}
+
+// CrossModelRelation describes a connection from a consuming model to an
+// application offer.
+type CrossModelRelation struct {
+	// OfferURL is the URL of the application offer.
+	OfferURL string `json:"offer-url"`
+
+	// OfferUUID is the UUID of the application offer.
+	OfferUUID string `json:"offer-uuid"`
+
+	// OfferingModelTag is the tag of the model hosting the offer.
+	OfferingModelTag string `json:"offering-model-tag"`
+
+	// ConsumingModelTag is the tag of the model consuming the offer.
+	ConsumingModelTag string `json:"consuming-model-tag"`
+
+	// RelationID is the ID of the relation in the offering model.
+	RelationID int `json:"relation-id"`
+
+	// Endpoint is the offer endpoint used by the relation.
+	Endpoint string `json:"endpoint"`
+
+	// Username is the user that made the connection.
+	Username string `json:"username"`
+}
+
+// UserQuotaRequest is the request used to fetch a user's quota.
+type UserQuotaRequest struct {
+	// UserTag is the tag of the user whose quota is returned. If this is
+	// empty the quota of the authenticated user is returned.
+	UserTag string `json:"user-tag,omitempty"`
+}
+
+// UserQuota holds the quota limits and current usage for the models
+// owned by a user.
+type UserQuota struct {
+	// UserTag is the tag of the user.
+	UserTag string `json:"user-tag" yaml:"user-tag"`
+
+	// Models holds the number of models owned by the user.
+	Models QuotaUsage `json:"models" yaml:"models"`
+
+	// Machines holds the total number of machines in the user's models.
+	Machines QuotaUsage `json:"machines" yaml:"machines"`
+
+	// Cores holds the total number of cores in the user's models.
+	Cores QuotaUsage `json:"cores" yaml:"cores"`
+}
+
+// SetUserQuotaRequest is the request used to override the default quota
+// limits for a user's models. Limits that are not set use the default, a
+// limit of zero means the resource is not limited.
+type SetUserQuotaRequest struct {
+	// UserTag is the tag of the user whose quota is set.
+	UserTag string `json:"user-tag"`
+
+	// Models is the maximum number of models the user may own.
+	Models *int64 `json:"models,omitempty"`
+
+	// Machines is the maximum total number of machines in the user's
+	// models.
+	Machines *int64 `json:"machines,omitempty"`
+
+	// Cores is the maximum total number of cores in the user's models.
+	Cores *int64 `json:"cores,omitempty"`
+}
+
+// RemoveUserQuotaRequest is the request used to remove the quota limits
+// set for a user, so that the default limits apply.
+type RemoveUserQuotaRequest struct {
+	// UserTag is the tag of the user whose quota is removed.
+	UserTag string `json:"user-tag"`
+}
+
+// IdentitySummary holds the authenticated identity along with a summary
+// of its effective permissions, returned by a Whoami call.
+type IdentitySummary struct {
+	// UserTag is the tag of the authenticated identity.
+	UserTag string `json:"user-tag" yaml:"user-tag"`
+
+	// DisplayName is the display name of the identity.
+	DisplayName string `json:"display-name" yaml:"display-name"`
+
+	// Groups holds the names of the groups the identity is a member of,
+	// either directly or through membership of another group.
+	Groups []string `json:"groups" yaml:"groups"`
+
+	// ControllerAdmin reports whether the identity is a JIMM
+	// administrator.
+	ControllerAdmin bool `json:"controller-admin" yaml:"controller-admin"`
+
+	// Quota holds the quota limits and current usage for the models
+	// owned by the identity.
+	Quota UserQuota `json:"quota" yaml:"quota"`
+
+	// Models is the number of models the identity can read.
+	Models int `json:"models" yaml:"models"`
+
+	// Controllers is the number of controllers hosting models the
+	// identity can read. JIMM administrators can access every
+	// controller.
+	Controllers int `json:"controllers" yaml:"controllers"`
+
+	// Clouds is the number of clouds the identity can add models to.
+	Clouds int `json:"clouds" yaml:"clouds"`
+
+	// LastLogin is the time the identity last logged in, if it has
+	// logged in.
+	LastLogin *time.Time `json:"last-login,omitempty" yaml:"last-login,omitempty"`
+
+	// LastActivity is the time the identity last made an API request,
+	// if it has made one.
+	LastActivity *time.Time `json:"last-activity,omitempty" yaml:"last-activity,omitempty"`
+
+	// ActiveSessions is the number of API sessions the identity has
+	// open.
+	ActiveSessions int `json:"active-sessions" yaml:"active-sessions"`
+}
+
+// ListIdentitySessionsRequest holds the parameters for listing the
+// sessions of an identity.
+type ListIdentitySessionsRequest struct {
+	// Identity is the name of the identity whose sessions are listed.
+	Identity string `json:"identity" yaml:"identity"`
+}
+
+// ListIdentitySessionsResponse holds the activity and open sessions of
+// an identity.
+type ListIdentitySessionsResponse struct {
+	// Identity is the tag of the identity.
+	Identity string `json:"identity" yaml:"identity"`
+
+	// LastLogin is the time the identity last logged in, if it has
+	// logged in.
+	LastLogin *time.Time `json:"last-login,omitempty" yaml:"last-login,omitempty"`
+
+	// LastActivity is the time the identity last made an API request,
+	// if it has made one.
+	LastActivity *time.Time `json:"last-activity,omitempty" yaml:"last-activity,omitempty"`
+
+	// Sessions holds the API sessions the identity has open, oldest
+	// first.
+	Sessions []IdentitySession `json:"sessions" yaml:"sessions"`
+}
+
+// IdentitySession describes an API session an identity has open.
+type IdentitySession struct {
+	// ID is the ID of the session.
+	ID uint `json:"id" yaml:"id"`
+
+	// StartedAt is the time the identity logged in to the session.
+	StartedAt time.Time `json:"started-at" yaml:"started-at"`
+
+	// LastActivity is the time the identity last made an API request
+	// in the session.
+	LastActivity time.Time `json:"last-activity" yaml:"last-activity"`
+
+	// RemoteAddress is the address the session's connection was made
+	// from.
+	RemoteAddress string `json:"remote-address,omitempty" yaml:"remote-address,omitempty"`
+}
+
+// QuotaUsage holds the limit and current usage of a resource.
+type QuotaUsage struct {
+	// Limit is the maximum allowed usage. A limit of zero means the
+	// resource is not limited.
+	Limit int64 `json:"limit" yaml:"limit"`
+
+	// Used is the current usage.
+	Used int64 `json:"used" yaml:"used"`
+}
+
+// NamespaceReservation holds a model name prefix reserved for the
+// members of a group.
+type NamespaceReservation struct {
+	// Prefix is the reserved model name prefix.
+	Prefix string `json:"prefix" yaml:"prefix"`
+
+	// Group is the name of the group owning the reservation.
+	Group string `json:"group" yaml:"group"`
+
+	// CreatedAt is the time the reservation was made.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// AddNamespaceReservationRequest holds a request to reserve a model name
+// prefix for the members of a group.
+type AddNamespaceReservationRequest struct {
+	// Prefix is the model name prefix to reserve. A trailing "*" is
+	// ignored, so "payments-*" reserves the prefix "payments-".
+	Prefix string `json:"prefix"`
+
+	// Group is the name of the group that will own the reservation.
+	Group string `json:"group"`
+}
+
+// ListNamespaceReservationsResponse holds the response of a
+// ListNamespaceReservations request.
+type ListNamespaceReservationsResponse struct {
+	Reservations []NamespaceReservation `json:"reservations"`
+}
+
+// TransferNamespaceReservationRequest holds a request to transfer a
+// namespace reservation to another group.
+type TransferNamespaceReservationRequest struct {
+	// Prefix is the reserved model name prefix.
+	Prefix string `json:"prefix"`
+
+	// Group is the name of the group that will own the reservation.
+	Group string `json:"group"`
+}
+
+// RemoveNamespaceReservationRequest holds a request to remove a namespace
+// reservation.
+type RemoveNamespaceReservationRequest struct {
+	// Prefix is the reserved model name prefix.
+	Prefix string `json:"prefix"`
+}
+
+// ModelACLTemplate holds a default grant applied to every new model
+// owned by a member of a group.
+type ModelACLTemplate struct {
+	// ID is the ID of the template.
+	ID uint `json:"id" yaml:"id"`
+
+	// OwnerGroup is the name of the group whose members' new models
+	// the template applies to.
+	OwnerGroup string `json:"owner-group" yaml:"owner-group"`
+
+	// GrantGroup is the name of the group granted access to the models.
+	GrantGroup string `json:"grant-group" yaml:"grant-group"`
+
+	// Access is the model access granted to GrantGroup, one of "read",
+	// "write" or "admin".
+	Access string `json:"access" yaml:"access"`
+
+	// CreatedAt is the time the template was added.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// AddModelACLTemplateRequest holds a request to add a model ACL
+// template.
+type AddModelACLTemplateRequest struct {
+	// OwnerGroup is the name of the group whose members' new models
+	// the template applies to.
+	OwnerGroup string `json:"owner-group"`
+
+	// GrantGroup is the name of the group granted access to the models.
+	GrantGroup string `json:"grant-group"`
+
+	// Access is the model access granted to GrantGroup.
+	Access string `json:"access"`
+}
+
+// ListModelACLTemplatesResponse holds the response of a
+// ListModelACLTemplates request.
+type ListModelACLTemplatesResponse struct {
+	Templates []ModelACLTemplate `json:"templates"`
+}
+
+// RemoveModelACLTemplateRequest holds a request to remove a model ACL
+// template.
+type RemoveModelACLTemplateRequest struct {
+	// ID is the ID of the template to remove.
+	ID uint `json:"id"`
+}
+
+// ModelToken holds the details of a long-lived token granting access to
+// a single model.
+type ModelToken struct {
+	// ID is the ID of the token.
+	ID uint `json:"id" yaml:"id"`
+
+	// ModelTag is the tag of the model the token grants access to.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Identity is the name of the identity the token authenticates as.
+	Identity string `json:"identity" yaml:"identity"`
+
+	// Access is the model access granted by the token, one of "read",
+	// "write" or "admin".
+	Access string `json:"access" yaml:"access"`
+
+	// Description describes the purpose of the token.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// CreatedBy is the name of the user that created the token.
+	CreatedBy string `json:"created-by" yaml:"created-by"`
+
+	// CreatedAt is the time the token was created.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// CreateModelTokenRequest holds a request to create a model token.
+type CreateModelTokenRequest struct {
+	// ModelTag is the tag of the model the token grants access to.
+	ModelTag string `json:"model-tag"`
+
+	// Access is the model access granted by the token.
+	Access string `json:"access"`
+
+	// Description describes the purpose of the token.
+	Description string `json:"description,omitempty"`
+}
+
+// CreateModelTokenResponse holds the response of a CreateModelToken
+// request.
+type CreateModelTokenResponse struct {
+	// ModelToken holds the details of the new token.
+	ModelToken ModelToken `json:"model-token"`
+
+	// Token is the token to use as a session token when logging in. It
+	// cannot be retrieved again.
+	Token string `json:"token"`
+}
+
+// ListModelTokensRequest holds a request to list the tokens granting
+// access to a model.
+type ListModelTokensRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// ListModelTokensResponse holds the response of a ListModelTokens
+// request.
+type ListModelTokensResponse struct {
+	Tokens []ModelToken `json:"tokens"`
+}
+
+// RevokeModelTokenRequest holds a request to revoke a model token.
+type RevokeModelTokenRequest struct {
+	// ModelTag is the tag of the model the token grants access to.
+	ModelTag string `json:"model-tag"`
+
+	// ID is the ID of the token to revoke.
+	ID uint `json:"id"`
+}
+
+// ModelFreeze holds the details of a frozen model, in which mutating
+// calls are rejected.
+type ModelFreeze struct {
+	// ModelTag is the tag of the frozen model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// FrozenBy is the name of the user that froze the model.
+	FrozenBy string `json:"frozen-by" yaml:"frozen-by"`
+
+	// Reason describes why the model is frozen.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// CreatedAt is the time the model was frozen.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+
+	// Until is the time the freeze expires. If this is nil the model is
+	// frozen until it is unfrozen.
+	Until *time.Time `json:"until,omitempty" yaml:"until,omitempty"`
+}
+
+// FreezeModelRequest holds a request to freeze a model.
+type FreezeModelRequest struct {
+	// ModelTag is the tag of the model to freeze.
+	ModelTag string `json:"model-tag"`
+
+	// Reason describes why the model is frozen.
+	Reason string `json:"reason,omitempty"`
+
+	// Until is the time the freeze expires. If this is nil the model is
+	// frozen until it is unfrozen.
+	Until *time.Time `json:"until,omitempty"`
+}
+
+// UnfreezeModelRequest holds a request to unfreeze a model.
+type UnfreezeModelRequest struct {
+	// ModelTag is the tag of the model to unfreeze.
+	ModelTag string `json:"model-tag"`
+}
+
+// PendingModelDestroy holds the details of a model waiting to be
+// destroyed at the end of its destroy confirmation window.
+type PendingModelDestroy struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+
+	// Owner is the name of the model's owner.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// RequestedBy is the name of the user that requested the destroy.
+	RequestedBy string `json:"requested-by" yaml:"requested-by"`
+
+	// RequestedAt is the time the destroy was requested.
+	RequestedAt time.Time `json:"requested-at" yaml:"requested-at"`
+
+	// DestroyAt is the time after which the model is destroyed.
+	DestroyAt time.Time `json:"destroy-at" yaml:"destroy-at"`
+}
+
+// ListPendingModelDestroysResponse holds the models waiting to be
+// destroyed.
+type ListPendingModelDestroysResponse struct {
+	// Destroys holds the pending model destroys, the earliest first.
+	Destroys []PendingModelDestroy `json:"destroys"`
+}
+
+// CancelModelDestroyRequest holds a request to cancel the pending
+// destroy of a model.
+type CancelModelDestroyRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of the
+	// form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+}
+
+// ModelNetworkPolicy holds the network policy attached to a model.
+type ModelNetworkPolicy struct {
+	// ModelTag is the tag of the model the policy is attached to.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// AllowedEgress holds the CIDRs of the destinations traffic from the
+	// model is allowed to reach.
+	AllowedEgress []string `json:"allowed-egress" yaml:"allowed-egress"`
+
+	// SetBy is the name of the user that last set the policy.
+	SetBy string `json:"set-by" yaml:"set-by"`
+
+	// UpdatedAt is the time the policy was last set.
+	UpdatedAt time.Time `json:"updated-at" yaml:"updated-at"`
+
+	// Enforced reports whether the policy has been applied to the
+	// controller hosting the model.
+	Enforced bool `json:"enforced" yaml:"enforced"`
+
+	// EnforcementError describes why the policy is not enforced, if it
+	// is not.
+	EnforcementError string `json:"enforcement-error,omitempty" yaml:"enforcement-error,omitempty"`
+}
+
+// SetModelNetworkPolicyRequest holds a request to attach a network policy
+// to a model.
+type SetModelNetworkPolicyRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// AllowedEgress holds the CIDRs of the destinations traffic from the
+	// model is allowed to reach.
+	AllowedEgress []string `json:"allowed-egress"`
+}
+
+// RemoveModelNetworkPolicyRequest holds a request to remove the network
+// policy from a model.
+type RemoveModelNetworkPolicyRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// ListModelNetworkPoliciesRequest holds a request to list the model
+// network policies.
+type ListModelNetworkPoliciesRequest struct {
+	// Unenforced restricts the list to the policies that could not be
+	// enforced.
+	Unenforced bool `json:"unenforced,omitempty"`
+}
+
+// ListModelNetworkPoliciesResponse holds the response to a
+// ListModelNetworkPolicies request.
+type ListModelNetworkPoliciesResponse struct {
+	// Policies holds the model network policies.
+	Policies []ModelNetworkPolicy `json:"policies" yaml:"policies"`
+}
+
+// ModelPool holds a pool of pre-provisioned models kept ready to be
+// claimed when a model is created.
+type ModelPool struct {
+	// Name is the name of the pool.
+	Name string `json:"name" yaml:"name"`
+
+	// CloudTag is the tag of the cloud hosting the pooled models.
+	CloudTag string `json:"cloud-tag" yaml:"cloud-tag"`
+
+	// CloudRegion is the cloud region hosting the pooled models.
+	CloudRegion string `json:"cloud-region" yaml:"cloud-region"`
+
+	// CloudCredentialTag is the tag of the credential the pooled models
+	// are created with.
+	CloudCredentialTag string `json:"cloud-credential-tag" yaml:"cloud-credential-tag"`
+
+	// Config holds the template model configuration the pooled models
+	// are created with.
+	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+
+	// Size is the number of models kept ready in the pool.
+	Size int `json:"size" yaml:"size"`
+
+	// Ready is the number of models currently ready in the pool.
+	Ready int `json:"ready" yaml:"ready"`
+
+	// CreatedAt is the time the pool was created.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// AddModelPoolRequest holds a request to add a pool of pre-provisioned
+// models.
+type AddModelPoolRequest struct {
+	// Name is the name of the pool.
+	Name string `json:"name"`
+
+	// CloudTag is the tag of the cloud to host the pooled models.
+	CloudTag string `json:"cloud-tag"`
+
+	// CloudRegion is the cloud region to host the pooled models.
+	CloudRegion string `json:"cloud-region"`
+
+	// CloudCredentialTag is the tag of the credential to create the
+	// pooled models with.
+	CloudCredentialTag string `json:"cloud-credential-tag"`
+
+	// Config holds the template model configuration to create the
+	// pooled models with.
+	Config map[string]interface{} `json:"config,omitempty"`
+
+	// Size is the number of models to keep ready in the pool.
+	Size int `json:"size"`
+}
+
+// RemoveModelPoolRequest holds a request to remove a pool of
+// pre-provisioned models.
+type RemoveModelPoolRequest struct {
+	// Name is the name of the pool.
+	Name string `json:"name"`
+}
+
+// ListModelPoolsResponse holds the response to a request to list the
+// model pools.
+type ListModelPoolsResponse struct {
+	// Pools holds the model pools.
+	Pools []ModelPool `json:"pools" yaml:"pools"`
+}
+
+// AccessRequest holds a request made by a user for access to a model or
+// cloud.
+type AccessRequest struct {
+	// ID is the ID of the request.
+	ID uint `json:"id" yaml:"id"`
+
+	// User is the name of the user requesting access.
+	User string `json:"user" yaml:"user"`
+
+	// Target is the tag of the model or cloud access is requested for.
+	Target string `json:"target" yaml:"target"`
+
+	// Access is the requested access level.
+	Access string `json:"access" yaml:"access"`
+
+	// Reason is the reason given by the user for requesting access.
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+
+	// Status is the state of the request, one of "pending", "approved"
+	// or "denied".
+	Status string `json:"status" yaml:"status"`
+
+	// Reviewer is the name of the user that approved or denied the
+	// request.
+	Reviewer string `json:"reviewer,omitempty" yaml:"reviewer,omitempty"`
+
+	// ReviewComment is the comment given when the request was approved
+	// or denied.
+	ReviewComment string `json:"review-comment,omitempty" yaml:"review-comment,omitempty"`
+
+	// CreatedAt is the time the request was made.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+
+	// ReviewedAt is the time the request was approved or denied.
+	ReviewedAt *time.Time `json:"reviewed-at,omitempty" yaml:"reviewed-at,omitempty"`
+}
+
+// RequestAccessRequest holds a request for access to a model or cloud.
+type RequestAccessRequest struct {
+	// Target is the tag of the model or cloud to request access to.
+	Target string `json:"target"`
+
+	// Access is the access level requested. For models this is one of
+	// "read", "write" or "admin", for clouds one of "add-model" or
+	// "admin".
+	Access string `json:"access"`
+
+	// Reason is the reason access is needed, which is shown to the
+	// users reviewing the request.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListAccessRequestsRequest holds a request to list the access requests
+// made by, or reviewable by, the authenticated user.
+type ListAccessRequestsRequest struct {
+	// Status, if set, only lists the requests in the given state.
+	Status string `json:"status,omitempty"`
+}
+
+// ListAccessRequestsResponse holds the response of a ListAccessRequests
+// request.
+type ListAccessRequestsResponse struct {
+	Requests []AccessRequest `json:"requests"`
+}
+
+// ReviewAccessRequestRequest holds a request to approve or deny an access
+// request.
+type ReviewAccessRequestRequest struct {
+	// ID is the ID of the access request.
+	ID uint `json:"id"`
+
+	// Comment is an optional comment recorded with the decision.
+	Comment string `json:"comment,omitempty"`
+}
+
+// The types of access request event.
+const (
+	AccessRequestCreated  = "access-request-created"
+	AccessRequestApproved = "access-request-approved"
+	AccessRequestDenied   = "access-request-denied"
+)
+
+// An AccessRequestEvent is sent to the configured access request webhook
+// when an access request is made, approved or denied.
+type AccessRequestEvent struct {
+	// Type is the type of the event.
+	Type string `json:"type"`
+
+	// Request is the access request the event is about.
+	Request AccessRequest `json:"request"`
+
+	// Reviewers holds the names of the users that may approve or deny
+	// the request. It is only set for AccessRequestCreated events.
+	Reviewers []string `json:"reviewers,omitempty"`
+}
+
+// Warning codes.
+const (
+	// WarningDeprecatedFacade is the code of warnings sent in response
+	// to requests that use a facade version scheduled for removal.
+	WarningDeprecatedFacade = "deprecated-facade"
+)
+
+// A Warning is a structured warning attached, in a "warnings" field, to
+// the responses to websocket requests proxied to a model.
+type Warning struct {
+	// Code identifies the kind of warning.
+	Code string `json:"code"`
+
+	// Message is a human readable description of the warning.
+	Message string `json:"message"`
+
+	// Facade is the facade the warning applies to, if any.
+	Facade string `json:"facade,omitempty"`
+
+	// Version is the facade version the warning applies to, if any.
+	Version int `json:"version,omitempty"`
+}
+
+// ModelTimelineRequest is the request used to fetch the timeline of
+// significant events for a model.
+type ModelTimelineRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+
+	// After is used to filter the timeline to only contain events that
+	// happened after a certain time. If this is specified it must
+	// contain an RFC3339 encoded time value.
+	After string `json:"after,omitempty"`
+
+	// Before is used to filter the timeline to only contain events that
+	// happened before a certain time. If this is specified it must
+	// contain an RFC3339 encoded time value.
+	Before string `json:"before,omitempty"`
+
+	// Limit is the maximum number of events to return, the most recent
+	// events are returned. A value of zero returns all events.
+	Limit int `json:"limit,omitempty"`
+}
+
+// Types of model timeline events.
+const (
+	ModelEventCreated            = "created"
+	ModelEventImported           = "imported"
+	ModelEventCredentialChanged  = "credential-changed"
+	ModelEventAccessChanged      = "access-changed"
+	ModelEventMigrationRequested = "migration-requested"
+	ModelEventAvailability       = "availability"
+	ModelEventDestroyRequested   = "destroy-requested"
+)
+
+// ModelTimeline holds the significant events in the history of a model,
+// ordered from the oldest to the most recent.
+type ModelTimeline struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+
+	// Events contains the events in the model's history.
+	Events []ModelTimelineEvent `json:"events"`
+}
+
+// ModelTimelineEvent is a single event in a model's timeline.
+type ModelTimelineEvent struct {
+	// Time is the time the event happened.
+	Time time.Time `json:"time"`
+
+	// Type is the type of the event, see the ModelEvent* constants.
+	Type string `json:"type"`
+
+	// Actor is the tag of the user that caused the event, if known.
+	Actor string `json:"actor,omitempty"`
+
+	// Detail is a human readable description of the event.
+	Detail string `json:"detail,omitempty"`
+}
+
+// LeaderInfo holds the current holder of the lease for a leader-elected
+// background worker.
+type LeaderInfo struct {
+	// Worker is the name of the background worker.
+	Worker string `json:"worker"`
+
+	// Holder identifies the JIMM replica holding the lease.
+	Holder string `json:"holder"`
+
+	// Expires is the time the lease expires unless it is renewed.
+	Expires time.Time `json:"expires"`
+}
+
+// ListLeadersResponse holds the response of a ListLeaders request.
+type ListLeadersResponse struct {
+	Leaders []LeaderInfo `json:"leaders"`
+}
+
+// CancelModelCreationRequest holds a request to cancel the creation of
+// a model that is still in progress.
+type CancelModelCreationRequest struct {
+	// Model is the path of the model being created, of the form
+	// <owner>/<name>. The model has no UUID until it is created.
+	Model string `json:"model"`
+}
+
+// MigrateModelInfo represents a single migration where a source model
+// target controller must be specified with both the source model and
+// target controller residing within JIMM.
+type MigrateModelInfo struct {
+	// ModelTag identifies the model to migrate by its tag, its UUID or
+	// a path of the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+	// TargetController is the controller name of the form "<name>"
+	TargetController string `json:"target-controller"`
+}
+
+// MigrateModelRequest allows for multiple migration requests to be made.
+type MigrateModelRequest struct {
+	Specs []MigrateModelInfo `json:"specs"`
+}
+
+// MigrationPrecheck holds the result of a single check made before a
+// model migration.
+type MigrationPrecheck struct {
+	// Name is the name of the check, for example "cloud-region".
+	Name string `json:"name" yaml:"name"`
+	// Passed is true if the check found no reason for the migration
+	// to fail.
+	Passed bool `json:"passed" yaml:"passed"`
+	// Message describes the outcome of the check.
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// MigrationPrecheckReport holds the results of the checks made before
+// migrating a model to a controller.
+type MigrationPrecheckReport struct {
+	// ModelTag is the tag of the model to be migrated.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// TargetController is the name of the controller the model would
+	// be migrated to.
+	TargetController string `json:"target-controller" yaml:"target-controller"`
+	// Passed is true if every check passed.
+	Passed bool `json:"passed" yaml:"passed"`
+	// Checks holds the result of each check.
+	Checks []MigrationPrecheck `json:"checks" yaml:"checks"`
+}
+
+// ModelMigrationStatusRequest holds a request for the status of a
+// model's migration.
+type ModelMigrationStatusRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// ModelMigrationStatus holds the status of the most recent migration of
+// a model.
+type ModelMigrationStatus struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+	// Active is true if a migration of the model is in progress.
+	Active bool `json:"active" yaml:"active"`
+	// Status is the status message of the most recent migration, it is
+	// empty if the model has never been migrated.
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+	// Start is the time the most recent migration started.
+	Start *time.Time `json:"start,omitempty" yaml:"start,omitempty"`
+	// End is the time the most recent migration finished.
+	End *time.Time `json:"end,omitempty" yaml:"end,omitempty"`
+}
+
+// ModelMigration holds the details of a model migration between two of
+// JIMM's controllers, as tracked by JIMM.
+type ModelMigration struct {
+	// ID is JIMM's ID of the migration.
+	ID uint `json:"id" yaml:"id"`
+	// ModelTag is the tag of the migrating model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// MigrationID is the ID of the migration on the source controller.
+	MigrationID string `json:"migration-id" yaml:"migration-id"`
+	// SourceController is the name of the controller the model is
+	// migrating from.
+	SourceController string `json:"source-controller" yaml:"source-controller"`
+	// TargetController is the name of the controller the model is
+	// migrating to.
+	TargetController string `json:"target-controller" yaml:"target-controller"`
+	// InitiatedBy is the name of the user that initiated the migration.
+	InitiatedBy string `json:"initiated-by" yaml:"initiated-by"`
+	// Status is the status of the migration, one of "running",
+	// "completed", "aborted" or "abandoned".
+	Status string `json:"status" yaml:"status"`
+	// Phase is the most recent migration status reported by the source
+	// controller.
+	Phase string `json:"phase,omitempty" yaml:"phase,omitempty"`
+	// Error describes why the migration was aborted or abandoned.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// Started is the time the migration was initiated.
+	Started time.Time `json:"started" yaml:"started"`
+	// Ended is the time JIMM found the migration had finished.
+	Ended *time.Time `json:"ended,omitempty" yaml:"ended,omitempty"`
+}
+
+// ListModelMigrationsRequest holds a request to list the model migrations
+// tracked by JIMM.
+type ListModelMigrationsRequest struct {
+	// ModelTag, if set, restricts the list to the migrations of the
+	// model.
+	ModelTag string `json:"model-tag,omitempty"`
+	// Active restricts the list to the migrations that have not
+	// finished.
+	Active bool `json:"active,omitempty"`
+}
+
+// ListModelMigrationsResponse holds the response to a ListModelMigrations
+// request.
+type ListModelMigrationsResponse struct {
+	// Migrations holds the migrations, most recent first.
+	Migrations []ModelMigration `json:"migrations" yaml:"migrations"`
+}
+
+// RebalanceRecommendationsRequest holds a request for recommended model
+// migrations that would balance the models across JIMM's controllers.
+type RebalanceRecommendationsRequest struct {
+	// Limit, if greater than zero, is the maximum number of migrations
+	// recommended.
+	Limit int `json:"limit,omitempty"`
+}
+
+// ControllerLoad describes the load on a controller before and after the
+// recommended migrations.
+type ControllerLoad struct {
+	// Name is the name of the controller.
+	Name string `json:"name" yaml:"name"`
+	// Deprecated is true if the controller is deprecated, all models
+	// are recommended to be moved off deprecated controllers.
+	Deprecated bool `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	// Models is the number of models the controller currently hosts.
+	Models int `json:"models" yaml:"models"`
+	// Machines is the number of machines in the models the controller
+	// currently hosts.
+	Machines int64 `json:"machines" yaml:"machines"`
+	// ModelsAfter is the number of models the controller would host
+	// after the recommended migrations.
+	ModelsAfter int `json:"models-after" yaml:"models-after"`
+	// MachinesAfter is the number of machines in the models the
+	// controller would host after the recommended migrations.
+	MachinesAfter int64 `json:"machines-after" yaml:"machines-after"`
+}
+
+// RebalanceRecommendation is a single recommended model migration.
+type RebalanceRecommendation struct {
+	// ModelTag is the tag of the model to migrate.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// Model is the path of the model, of the form <owner>/<name>.
+	Model string `json:"model" yaml:"model"`
+	// SourceController is the name of the controller currently hosting
+	// the model.
+	SourceController string `json:"source-controller" yaml:"source-controller"`
+	// TargetController is the name of the controller the model should
+	// be migrated to.
+	TargetController string `json:"target-controller" yaml:"target-controller"`
+	// Machines is the number of machines in the model.
+	Machines int64 `json:"machines" yaml:"machines"`
+	// Reason describes why the migration is recommended.
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// RebalanceReport holds the recommended model migrations and their
+// expected impact on the load of each controller.
+type RebalanceReport struct {
+	// MaxControllerModels is the maximum number of models a controller
+	// may host, zero if there is no maximum.
+	MaxControllerModels int `json:"max-controller-models,omitempty" yaml:"max-controller-models,omitempty"`
+	// Controllers holds the load on each available controller.
+	Controllers []ControllerLoad `json:"controllers" yaml:"controllers"`
+	// Recommendations holds the recommended migrations, in the order
+	// they should be performed.
+	Recommendations []RebalanceRecommendation `json:"recommendations" yaml:"recommendations"`
+}
+
+// FindOffersRequest holds a request to search the directory of
+// application offers. Only offers matching every specified criterion are
+// returned.
+type FindOffersRequest struct {
+	// Query, if specified, matches offers whose name, application name
+	// or description contain the given text, ignoring case.
+	Query string `json:"query,omitempty"`
+	// Interface, if specified, matches offers with an endpoint using
+	// the given interface, for example "postgresql_client".
+	Interface string `json:"interface,omitempty"`
+	// Owner, if specified, matches offers in models owned by the given
+	// user.
+	Owner string `json:"owner,omitempty"`
+	// Cloud, if specified, matches offers in models hosted on the given
+	// cloud.
+	Cloud string `json:"cloud,omitempty"`
+	// Limit, if greater than zero, is the maximum number of offers
+	// returned.
+	Limit int `json:"limit,omitempty"`
+}
+
+// OfferDirectoryEntry describes an application offer in the directory.
+type OfferDirectoryEntry struct {
+	// OfferURL is the URL of the offer.
+	OfferURL string `json:"offer-url" yaml:"offer-url"`
+	// OfferUUID is the UUID of the offer.
+	OfferUUID string `json:"offer-uuid" yaml:"offer-uuid"`
+	// OfferName is the name of the offer.
+	OfferName string `json:"offer-name" yaml:"offer-name"`
+	// ApplicationName is the name of the offered application.
+	ApplicationName string `json:"application-name" yaml:"application-name"`
+	// Description is the description of the offered application.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Interfaces holds the interfaces of the offer's endpoints.
+	Interfaces []string `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+	// Owner is the owner of the model containing the offer.
+	Owner string `json:"owner" yaml:"owner"`
+	// ModelName is the name of the model containing the offer.
+	ModelName string `json:"model-name" yaml:"model-name"`
+	// Cloud is the cloud hosting the model containing the offer.
+	Cloud string `json:"cloud" yaml:"cloud"`
+	// CloudRegion is the cloud region hosting the model containing the
+	// offer.
+	CloudRegion string `json:"cloud-region,omitempty" yaml:"cloud-region,omitempty"`
+	// Access is the user's access level to the offer.
+	Access string `json:"access" yaml:"access"`
+	// ActiveConnections is the number of active relations to the offer.
+	ActiveConnections int `json:"active-connections" yaml:"active-connections"`
+	// TotalConnections is the number of relations to the offer.
+	TotalConnections int `json:"total-connections" yaml:"total-connections"`
+}
+
+// FindOffersResponse holds the offers found in the directory.
+type FindOffersResponse struct {
+	Offers []OfferDirectoryEntry `json:"offers" yaml:"offers"`
+}
+
+// ControllerPingResult holds the result of probing a single controller.
+type ControllerPingResult struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+	// Reachable is true if JIMM logged in to the controller and the
+	// controller supports the facades JIMM requires.
+	Reachable bool `json:"reachable" yaml:"reachable"`
+	// LatencyMS is the time, in milliseconds, taken to connect to and
+	// probe the controller.
+	LatencyMS int64 `json:"latency-ms" yaml:"latency-ms"`
+	// AgentVersion is the agent version reported by the controller.
+	AgentVersion string `json:"agent-version,omitempty" yaml:"agent-version,omitempty"`
+	// Error describes why the controller could not be probed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// PingControllersResponse holds the results of probing every controller.
+type PingControllersResponse struct {
+	// Results holds the result for each controller, ordered by
+	// controller name.
+	Results []ControllerPingResult `json:"results" yaml:"results"`
+}
+
+// SyncGroupsRequest holds the parameters of a synchronisation of group
+// memberships from the external directory.
+type SyncGroupsRequest struct {
+	// DryRun, if true, reports the changes the synchronisation would
+	// make without making them.
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
+
+	// Force, if true, makes the synchronisation even if it would remove
+	// more group memberships than JIMM is configured to allow.
+	Force bool `json:"force,omitempty" yaml:"force,omitempty"`
+}
+
+// GroupMembershipChange describes a change to the membership of a group
+// made by a group synchronisation.
+type GroupMembershipChange struct {
+	// Group is the name of the group.
+	Group string `json:"group" yaml:"group"`
+	// Member is the name of the identity added to or removed from the
+	// group.
+	Member string `json:"member" yaml:"member"`
+	// Action is either "add" or "remove".
+	Action string `json:"action" yaml:"action"`
+}
+
+// SyncGroupsResponse holds the outcome of a group synchronisation.
+type SyncGroupsResponse struct {
+	// DryRun is true if no changes were made.
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
+	// Time is the time the synchronisation started.
+	Time time.Time `json:"time" yaml:"time"`
+	// CreatedGroups holds the names of the groups created, or that
+	// would be created in a dry run.
+	CreatedGroups []string `json:"created-groups,omitempty" yaml:"created-groups,omitempty"`
+	// Changes holds the membership changes made, or that would be made
+	// in a dry run, ordered by group and member.
+	Changes []GroupMembershipChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	// Errors holds the problems synchronising individual groups and
+	// members, these do not stop the rest of the synchronisation.
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Rebinds holds the models of departed users that were rebound, or
+	// that would be rebound in a dry run, to credentials shared with the
+	// configured credential group.
+	Rebinds []ModelCredentialRebind `json:"rebinds,omitempty" yaml:"rebinds,omitempty"`
+}
+
+// GroupSyncStatusResponse holds the outcome of the most recent group
+// synchronisation that was not a dry run.
+type GroupSyncStatusResponse struct {
+	// Directory is the name of the directory groups are synchronised
+	// from.
+	Directory string `json:"directory" yaml:"directory"`
+	// Time is the time the synchronisation started.
+	Time time.Time `json:"time" yaml:"time"`
+	// CreatedGroups holds the names of the groups created.
+	CreatedGroups []string `json:"created-groups,omitempty" yaml:"created-groups,omitempty"`
+	// Added and Removed are the number of group memberships added and
+	// removed.
+	Added   int `json:"added" yaml:"added"`
+	Removed int `json:"removed" yaml:"removed"`
+	// Errors holds the problems synchronising individual groups and
+	// members.
+	Errors []string `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Error holds the error that stopped the synchronisation, if any.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// LoginDeviceResponse holds the details to complete a LoginDevice flow.
+type LoginDeviceResponse struct {
+	// VerificationURI holds the URI that the user must navigate to
+	// when entering their "user-code" to consent to this authorisation
+	// request.
+	VerificationURI string `json:"verification-uri" yaml:"verification-uri"`
+	// UserCode holds the one-time use user consent code.
+	UserCode string `json:"user-code" yaml:"user-code"`
+}
+
+// GetDeviceSessionTokenResponse returns a session token to be used against
 // LoginWithSessionToken for authentication. The session token will be base64
 // encoded.
 type GetDeviceSessionTokenResponse struct {
@@ -443,76 +2483,1608 @@ type GetDeviceSessionTokenResponse struct {
 	SessionToken string `json:"session-token" yaml:"session-token"`
 }
 
-// LoginWithSessionTokenRequest accepts a session token minted by JIMM and logs
-// the user in.
-//
-// The login response for this login request type is that of jujuparams.LoginResult,
-// such that the behaviour of previous macroon based authentication is unchanged.
-// However, on unauthenticated requests, the error is different and is not a macaroon
-// discharge request.
-type LoginWithSessionTokenRequest struct {
-	// SessionToken is a base64 encoded JWT capable of authenticating
-	// a user. The JWT contains the users email address in the subject,
-	// and this is used to identify this user.
-	SessionToken string `json:"session-token"`
+// LoginWithSessionTokenRequest accepts a session token minted by JIMM and logs
+// the user in.
+//
+// The login response for this login request type is that of jujuparams.LoginResult,
+// such that the behaviour of previous macroon based authentication is unchanged.
+// However, on unauthenticated requests, the error is different and is not a macaroon
+// discharge request.
+type LoginWithSessionTokenRequest struct {
+	// SessionToken is a base64 encoded JWT capable of authenticating
+	// a user. The JWT contains the users email address in the subject,
+	// and this is used to identify this user.
+	SessionToken string `json:"session-token"`
+}
+
+// Service Account related request parameters
+
+// LoginWithClientCredentialsRequest holds the client id and secret used
+// to authenticate with JIMM.
+type LoginWithClientCredentialsRequest struct {
+	ClientID     string `json:"client-id"`
+	ClientSecret string `json:"client-secret"`
+}
+
+// AddServiceAccountRequest holds a request to add a service account.
+type AddServiceAccountRequest struct {
+	// ClientID holds the client id of the service account.
+	ClientID string `json:"client-id"`
+}
+
+// CopyServiceAccountCredentialRequest holds a request to copy a user cloud-credential to a service account.
+type CopyServiceAccountCredentialRequest struct {
+	jujuparams.CloudCredentialArg
+	// ClientID holds the client id of the service account.
+	ClientID string `json:"client-id"`
+}
+
+// UpdateServiceAccountCredentialsRequest holds a request to update
+// a service accounts cloud credentials.
+type UpdateServiceAccountCredentialsRequest struct {
+	jujuparams.UpdateCredentialArgs
+	// ClientID holds the client id of the service account.
+	ClientID string `json:"client-id"`
+}
+
+// ListServiceAccountCredentialsRequest holds a request to list
+// a service accounts cloud credentials.
+type ListServiceAccountCredentialsRequest struct {
+	jujuparams.CloudCredentialArgs
+	// ClientID holds the client id of the service account.
+	ClientID string `json:"client-id"`
+}
+
+// ListServiceAccountCredentialsRequest holds a request to list
+// a service accounts cloud credentials.
+type GrantServiceAccountAccess struct {
+	// Entities holds a slice of entities (identities and groups)
+	// that should have administration access to the desired clientID.
+	Entities []string `json:"entities"`
+	// ClientID holds the client id of the service account.
+	ClientID string `json:"client-id"`
+}
+
+// ServiceAccountGroupsRequest holds a request to add a service account
+// to, or remove a service account from, a set of groups.
+type ServiceAccountGroupsRequest struct {
+	// ClientID holds the client id of the service account.
+	ClientID string `json:"client-id"`
+	// Groups holds the names of the groups.
+	Groups []string `json:"groups"`
+}
+
+// WhoamiResponse holds the response for a /auth/whoami call.
+type WhoamiResponse struct {
+	DisplayName string `json:"display-name" yaml:"display-name" description:"The display name of the user."`
+	Email       string `json:"email" yaml:"email" description:"The email address of the user."`
+}
+
+// VersionResponse holds the response for a version call.
+type VersionResponse struct {
+	Version string `json:"version" yaml:"version"`
+	Commit  string `json:"commit" yaml:"commit"`
+}
+
+// EnrolTOTPResponse holds the details of a TOTP authenticator enrolled
+// for the authenticated user.
+type EnrolTOTPResponse struct {
+	// Secret is the base32 encoded secret shared with the
+	// authenticator.
+	Secret string `json:"secret" yaml:"secret"`
+
+	// URI is the otpauth URI with which the secret is added to an
+	// authenticator application, typically shown as a QR code.
+	URI string `json:"uri" yaml:"uri"`
+}
+
+// ConfirmIdentityRequest holds a request to confirm the authenticated
+// user's identity before performing sensitive operations. Exactly one of
+// TOTPCode and SessionToken must be specified.
+type ConfirmIdentityRequest struct {
+	// TOTPCode is a code from the user's enrolled TOTP authenticator.
+	TOTPCode string `json:"totp-code,omitempty"`
+
+	// SessionToken is a session token obtained by logging in again.
+	SessionToken string `json:"session-token,omitempty"`
+}
+
+// ConfirmIdentityResponse holds the result of confirming the
+// authenticated user's identity.
+type ConfirmIdentityResponse struct {
+	// ConfirmedAt is the time the user last authenticated.
+	ConfirmedAt time.Time `json:"confirmed-at" yaml:"confirmed-at"`
+
+	// Expires is the time after which sensitive operations require the
+	// identity to be confirmed again. This is not set if the server does
+	// not require confirmation.
+	Expires *time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+}
+
+// ControllerModelCredential describes the cloud credential used by a
+// controller's own controller model, typically the credential the
+// controller was bootstrapped with.
+type ControllerModelCredential struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Credential is the tag of the cloud credential used by the
+	// controller model.
+	Credential string `json:"credential,omitempty" yaml:"credential,omitempty"`
+
+	// Valid reports whether the controller considers the credential
+	// valid. This is not set if the validity has not been checked.
+	Valid *bool `json:"valid,omitempty" yaml:"valid,omitempty"`
+
+	// CheckedAt is the time the credential was last checked on the
+	// controller.
+	CheckedAt *time.Time `json:"checked-at,omitempty" yaml:"checked-at,omitempty"`
+
+	// ExpiresAt is the time the credential expires, if known.
+	ExpiresAt *time.Time `json:"expires-at,omitempty" yaml:"expires-at,omitempty"`
+
+	// RotatedAt is the time the credential was last rotated through
+	// JIMM.
+	RotatedAt *time.Time `json:"rotated-at,omitempty" yaml:"rotated-at,omitempty"`
+
+	// Expiring is true if the credential expires within the warning
+	// period configured on the server, or has already expired.
+	Expiring bool `json:"expiring,omitempty" yaml:"expiring,omitempty"`
+}
+
+// ListControllerModelCredentialsResponse holds the credentials used by
+// the controller models of every controller.
+type ListControllerModelCredentialsResponse struct {
+	// Credentials holds the credential of each controller, ordered by
+	// controller name.
+	Credentials []ControllerModelCredential `json:"credentials" yaml:"credentials"`
+}
+
+// ControllerCertificate describes the expiry of the TLS certificates
+// presented by a controller.
+type ControllerCertificate struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Expiry is the earliest expiry time of the certificates in the
+	// chain presented by the controller when JIMM last connected to it.
+	// This is not set if JIMM has not yet connected to the controller.
+	Expiry *time.Time `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+
+	// DaysToExpiry is the number of whole days until Expiry, negative if
+	// the certificate has already expired.
+	DaysToExpiry *int `json:"days-to-expiry,omitempty" yaml:"days-to-expiry,omitempty"`
+
+	// Expiring is true if the certificate expires within the warning
+	// period configured on the server, or has already expired.
+	Expiring bool `json:"expiring,omitempty" yaml:"expiring,omitempty"`
+}
+
+// ListControllerCertificatesResponse holds the certificate expiry of
+// every controller.
+type ListControllerCertificatesResponse struct {
+	// Certificates holds the certificate expiry of each controller,
+	// ordered by expiry with the soonest first. Controllers whose
+	// expiry is not known are listed last.
+	Certificates []ControllerCertificate `json:"certificates" yaml:"certificates"`
+}
+
+// RotateControllerModelCredentialRequest holds a request to rotate the
+// cloud credential used by a controller's controller model.
+type RotateControllerModelCredentialRequest struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller"`
+
+	// Credential holds the new content of the credential. If this is
+	// not specified the content of the credential is left unchanged and
+	// only the expiry is updated.
+	Credential *jujuparams.CloudCredential `json:"credential,omitempty"`
+
+	// ExpiresAt is the time the new credential expires, if known.
+	ExpiresAt *time.Time `json:"expires-at,omitempty"`
+}
+
+// IdleModel describes a model that has not been used for a while.
+type IdleModel struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+
+	// Owner is the name of the model's owner.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// LastUnitChange is the time the units of the model last changed,
+	// if a change has been seen.
+	LastUnitChange *time.Time `json:"last-unit-change,omitempty" yaml:"last-unit-change,omitempty"`
+
+	// LastConnection is the time a user last connected to the model
+	// through JIMM, if a connection has been seen.
+	LastConnection *time.Time `json:"last-connection,omitempty" yaml:"last-connection,omitempty"`
+
+	// IdleDays is the number of whole days since the model was last
+	// active.
+	IdleDays int `json:"idle-days" yaml:"idle-days"`
+}
+
+// ListIdleModelsRequest holds a request to list the idle models.
+type ListIdleModelsRequest struct {
+	// IdleDays is the number of days without activity after which a
+	// model is idle. If this is zero the period configured on the
+	// server is used.
+	IdleDays int `json:"idle-days,omitempty"`
+}
+
+// ListIdleModelsResponse holds the idle models.
+type ListIdleModelsResponse struct {
+	// Models holds the idle models, the longest idle first.
+	Models []IdleModel `json:"models" yaml:"models"`
+}
+
+// ArchiveAndDestroyModelRequest holds a request to archive a model and
+// then destroy it.
+type ArchiveAndDestroyModelRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// ArchiveAndDestroyModelResponse holds the result of archiving and
+// destroying a model.
+type ArchiveAndDestroyModelResponse struct {
+	// ArchiveKey is the key of the model's archived bundle in JIMM's
+	// object store.
+	ArchiveKey string `json:"archive-key" yaml:"archive-key"`
+}
+
+// SetCostCenterRequest holds a request to attach a cost center to a user,
+// group or model.
+type SetCostCenterRequest struct {
+	// Entity is the tag of the user, group or model, for example
+	// "user-alice@canonical.com", "group-finance" or
+	// "model-alice@canonical.com/mymodel".
+	Entity string `json:"entity"`
+
+	// CostCenter is the cost center, or billing code, to attach. If
+	// this is empty any cost center attached to the entity is removed.
+	CostCenter string `json:"cost-center"`
+}
+
+// ModelUsageReport holds the resources used by a single model and the
+// cost center they are attributed to.
+type ModelUsageReport struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+	// Owner is the name of the model's owner.
+	Owner string `json:"owner" yaml:"owner"`
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+	// Cloud is the name of the cloud hosting the model.
+	Cloud string `json:"cloud" yaml:"cloud"`
+	// Region is the name of the cloud region hosting the model.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	// Machines is the number of machines in the model.
+	Machines int64 `json:"machines" yaml:"machines"`
+	// Cores is the number of cores in the model.
+	Cores int64 `json:"cores" yaml:"cores"`
+	// Units is the number of units in the model.
+	Units int64 `json:"units" yaml:"units"`
+	// CostCenter is the cost center the model is attributed to. This is
+	// empty if no cost center applies.
+	CostCenter string `json:"cost-center,omitempty" yaml:"cost-center,omitempty"`
+	// CostCenterSource is the kind of entity the cost center was taken
+	// from, one of "model", "user" or "group".
+	CostCenterSource string `json:"cost-center-source,omitempty" yaml:"cost-center-source,omitempty"`
+}
+
+// CostCenterUsage holds the total resources used by the models attributed
+// to a cost center.
+type CostCenterUsage struct {
+	// CostCenter is the cost center. This is empty for the models that
+	// are not attributed to any cost center.
+	CostCenter string `json:"cost-center" yaml:"cost-center"`
+	// Models is the number of models.
+	Models int64 `json:"models" yaml:"models"`
+	// Machines is the total number of machines in the models.
+	Machines int64 `json:"machines" yaml:"machines"`
+	// Cores is the total number of cores in the models.
+	Cores int64 `json:"cores" yaml:"cores"`
+	// Units is the total number of units in the models.
+	Units int64 `json:"units" yaml:"units"`
+}
+
+// UsageReport holds the resources used by every model, attributed to cost
+// centers.
+type UsageReport struct {
+	// Time is the time the report was generated.
+	Time time.Time `json:"time" yaml:"time"`
+	// Models holds the usage of each model, ordered by owner and model
+	// name.
+	Models []ModelUsageReport `json:"models" yaml:"models"`
+	// CostCenters holds the total usage of each cost center, ordered by
+	// cost center.
+	CostCenters []CostCenterUsage `json:"cost-centers" yaml:"cost-centers"`
+}
+
+// ModelAgentVersions holds the agent versions running in a model.
+type ModelAgentVersions struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+	// Owner is the name of the model's owner.
+	Owner string `json:"owner" yaml:"owner"`
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+	// ControllerVersion is the agent version of the controller hosting
+	// the model.
+	ControllerVersion string `json:"controller-version" yaml:"controller-version"`
+	// ModelVersion is the agent version of the model.
+	ModelVersion string `json:"model-version" yaml:"model-version"`
+	// AgentVersions holds the number of machine and unit agents in the
+	// model running each version.
+	AgentVersions map[string]int64 `json:"agent-versions,omitempty" yaml:"agent-versions,omitempty"`
+	// OutdatedAgents is the number of machine and unit agents running
+	// an older version than the controller.
+	OutdatedAgents int64 `json:"outdated-agents" yaml:"outdated-agents"`
+	// Outdated reports whether the model, or any of its agents, runs an
+	// older version than the controller.
+	Outdated bool `json:"outdated" yaml:"outdated"`
+	// CostCenter is the cost center the model is attributed to, see
+	// ModelUsageReport.
+	CostCenter string `json:"cost-center,omitempty" yaml:"cost-center,omitempty"`
+}
+
+// CostCenterAgentVersions holds the agent versions running in the models
+// attributed to a cost center.
+type CostCenterAgentVersions struct {
+	// CostCenter is the cost center. This is empty for the models that
+	// are not attributed to any cost center.
+	CostCenter string `json:"cost-center" yaml:"cost-center"`
+	// Models is the number of models.
+	Models int64 `json:"models" yaml:"models"`
+	// OutdatedModels is the number of outdated models.
+	OutdatedModels int64 `json:"outdated-models" yaml:"outdated-models"`
+	// AgentVersions holds the number of machine and unit agents in the
+	// models running each version.
+	AgentVersions map[string]int64 `json:"agent-versions,omitempty" yaml:"agent-versions,omitempty"`
+	// OutdatedAgents is the number of machine and unit agents running
+	// an older version than their controller.
+	OutdatedAgents int64 `json:"outdated-agents" yaml:"outdated-agents"`
+}
+
+// AgentVersionReport holds the agent versions running in every model,
+// attributed to cost centers.
+type AgentVersionReport struct {
+	// Time is the time the report was generated.
+	Time time.Time `json:"time" yaml:"time"`
+	// Models holds the agent versions of each model, ordered by owner
+	// and model name.
+	Models []ModelAgentVersions `json:"models" yaml:"models"`
+	// CostCenters holds the agent versions of each cost center, ordered
+	// by cost center.
+	CostCenters []CostCenterAgentVersions `json:"cost-centers" yaml:"cost-centers"`
+}
+
+// Resolutions of model resource history series.
+const (
+	ResourceResolutionAuto = ""
+	ResourceResolutionHour = "hour"
+	ResourceResolutionDay  = "day"
+)
+
+// ModelResourceHistoryRequest is the request used to fetch the history
+// of the resources used by a model.
+type ModelResourceHistoryRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+
+	// After is the start of the time range to return. If this is
+	// specified it must contain an RFC3339 encoded time value. The
+	// default is seven days before Before.
+	After string `json:"after,omitempty"`
+
+	// Before is the end of the time range to return. If this is
+	// specified it must contain an RFC3339 encoded time value. The
+	// default is the current time.
+	Before string `json:"before,omitempty"`
+
+	// Resolution is the resolution of the returned series, either "hour"
+	// or "day". If this is empty the resolution is chosen from the
+	// length of the time range.
+	Resolution string `json:"resolution,omitempty"`
+}
+
+// ModelResourceHistory holds series of the resources used by a model
+// over time, suitable for plotting. The series all have the same
+// length, the nth element of each holds the value at the nth time.
+type ModelResourceHistory struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// Resolution is the resolution of the series, either "hour" or
+	// "day". Each value is the maximum recorded in the period starting
+	// at the corresponding time.
+	Resolution string `json:"resolution" yaml:"resolution"`
+	// Times holds the start of the period of each value, in ascending
+	// order. Periods in which no snapshot was recorded are omitted.
+	Times []time.Time `json:"times" yaml:"times"`
+	// Machines holds the number of machines in the model.
+	Machines []int64 `json:"machines" yaml:"machines"`
+	// Cores holds the number of cores in the model.
+	Cores []int64 `json:"cores" yaml:"cores"`
+	// Units holds the number of units in the model.
+	Units []int64 `json:"units" yaml:"units"`
+}
+
+// Fault describes a fault injected into JIMM to test its resilience to
+// controller and database failures. Faults can only be injected into
+// JIMM servers built with fault injection enabled.
+type Fault struct {
+	// Point is the point at which the fault is injected, one of
+	// "controller-connection", "watcher-delta" or "database-write".
+	Point string `json:"point" yaml:"point"`
+
+	// Target is the controller name, or for database-write faults the
+	// table name, the fault applies to. If this is empty the fault
+	// applies to all controllers or tables.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// Delay is the duration, for example "30s", by which watcher deltas
+	// are delayed. This is only used by watcher-delta faults.
+	Delay string `json:"delay,omitempty" yaml:"delay,omitempty"`
+
+	// Count is the number of times the fault is injected before it is
+	// removed. If this is zero the fault is injected until cleared.
+	Count int `json:"count,omitempty" yaml:"count,omitempty"`
+}
+
+// ListFaultsResponse holds the response of a ListFaults request.
+type ListFaultsResponse struct {
+	// Faults holds the injected faults.
+	Faults []Fault `json:"faults" yaml:"faults"`
+}
+
+// SetPublicListingRequest holds a request to list a model or application
+// offer in the public catalog. Exactly one of ModelTag and OfferURL must
+// be set.
+type SetPublicListingRequest struct {
+	// ModelTag is the tag of the model to list.
+	ModelTag string `json:"model-tag,omitempty"`
+	// OfferURL is the URL of the application offer to list.
+	OfferURL string `json:"offer-url,omitempty"`
+	// Purpose describes what the model or offer is for.
+	Purpose string `json:"purpose,omitempty"`
+	// OwnerGroup is the name of the group responsible for the model or
+	// offer.
+	OwnerGroup string `json:"owner-group,omitempty"`
+}
+
+// RemovePublicListingRequest holds a request to remove a model or
+// application offer from the public catalog. Exactly one of ModelTag and
+// OfferURL must be set.
+type RemovePublicListingRequest struct {
+	// ModelTag is the tag of the model to remove.
+	ModelTag string `json:"model-tag,omitempty"`
+	// OfferURL is the URL of the application offer to remove.
+	OfferURL string `json:"offer-url,omitempty"`
+}
+
+// PublicCatalog holds the sanitized descriptions of the models and
+// application offers listed in the public catalog.
+type PublicCatalog struct {
+	// Models holds the listed models.
+	Models []PublicModel `json:"models" yaml:"models"`
+	// Offers holds the listed application offers.
+	Offers []PublicOffer `json:"offers" yaml:"offers"`
+}
+
+// PublicModel describes a model listed in the public catalog.
+type PublicModel struct {
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+	// Purpose describes what the model is for.
+	Purpose string `json:"purpose,omitempty" yaml:"purpose,omitempty"`
+	// OwnerGroup is the name of the group responsible for the model.
+	OwnerGroup string `json:"owner-group,omitempty" yaml:"owner-group,omitempty"`
+}
+
+// PublicOffer describes an application offer listed in the public
+// catalog.
+type PublicOffer struct {
+	// Name is the name of the offer.
+	Name string `json:"name" yaml:"name"`
+	// Purpose describes what the offer is for.
+	Purpose string `json:"purpose,omitempty" yaml:"purpose,omitempty"`
+	// OwnerGroup is the name of the group responsible for the offer.
+	OwnerGroup string `json:"owner-group,omitempty" yaml:"owner-group,omitempty"`
+	// Interfaces holds the relation interfaces of the offer's endpoints,
+	// in alphabetical order.
+	Interfaces []string `json:"interfaces" yaml:"interfaces"`
+}
+
+// RebindModelCredentialsRequest holds a request to rebind the models
+// using cloud credentials owned by a departing user to credentials shared
+// with a group.
+type RebindModelCredentialsRequest struct {
+	// UserTag is the tag of the departing user.
+	UserTag string `json:"user-tag"`
+	// Group is the name of the group whose shared credentials the models
+	// are rebound to.
+	Group string `json:"group"`
+	// DryRun reports the models that would be rebound without changing
+	// them.
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// RebindModelCredentialsResponse holds the outcome of rebinding the
+// models of a departing user.
+type RebindModelCredentialsResponse struct {
+	// DryRun is true if no models were changed.
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
+	// Rebinds holds the models that were rebound, or that would be
+	// rebound in a dry run, ordered by model tag.
+	Rebinds []ModelCredentialRebind `json:"rebinds" yaml:"rebinds"`
+}
+
+// ModelCredentialRebind describes the rebinding of a model to a
+// different cloud credential.
+type ModelCredentialRebind struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+	// FromCredential is the tag of the credential the model used.
+	FromCredential string `json:"from-credential" yaml:"from-credential"`
+	// ToCredential is the tag of the credential the model is rebound to.
+	// This is empty if no suitable credential was found.
+	ToCredential string `json:"to-credential,omitempty" yaml:"to-credential,omitempty"`
+	// Error holds the reason the model could not be rebound, if any.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// RotateCloudCredentialsRequest holds a request to rotate the cloud
+// credentials for a cloud.
+type RotateCloudCredentialsRequest struct {
+	// CloudTag is the tag of the cloud whose credentials are rotated.
+	CloudTag string `json:"cloud-tag"`
+	// AuthType, if set, restricts the rotation to credentials with the
+	// auth type.
+	AuthType string `json:"auth-type,omitempty"`
+	// Rotations holds the attribute values to replace. If this is empty
+	// the rotation hook configured for the cloud's provider computes the
+	// new attributes of every credential.
+	Rotations []CredentialAttributeRotation `json:"rotations,omitempty"`
+	// Concurrency is the maximum number of credentials updated at once.
+	// If this is zero a default is used.
+	Concurrency int `json:"concurrency,omitempty"`
+	// DryRun reports the credentials that would be rotated without
+	// changing them.
+	DryRun bool `json:"dry-run,omitempty"`
+	// Async starts the rotation as an operation and returns its ID
+	// without waiting for the rotation to finish. The operation's
+	// result is the response the synchronous rotation would return.
+	Async bool `json:"async,omitempty"`
+}
+
+// A CredentialAttributeRotation replaces one value of a credential
+// attribute with another.
+type CredentialAttributeRotation struct {
+	// Attribute is the name of the credential attribute.
+	Attribute string `json:"attribute"`
+	// Old is the value being rotated out.
+	Old string `json:"old"`
+	// New is the value replacing it.
+	New string `json:"new"`
+}
+
+// RotateCloudCredentialsResponse holds the outcome of rotating the
+// credentials for a cloud.
+type RotateCloudCredentialsResponse struct {
+	// DryRun is true if no credentials were changed.
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
+	// Results holds the outcome for each credential that was rotated, or
+	// that would be rotated in a dry run, ordered by credential tag.
+	Results []CredentialRotationResult `json:"results" yaml:"results"`
+	// Unchanged is the number of credentials for the cloud that did not
+	// need rotating.
+	Unchanged int `json:"unchanged" yaml:"unchanged"`
+	// Operation is the ID of the operation performing an asynchronous
+	// rotation, the results of which are in the operation's result.
+	Operation string `json:"operation,omitempty" yaml:"operation,omitempty"`
+}
+
+// CredentialRotationResult holds the outcome of rotating a single cloud
+// credential.
+type CredentialRotationResult struct {
+	// CredentialTag is the tag of the credential.
+	CredentialTag string `json:"credential-tag" yaml:"credential-tag"`
+	// Attributes holds the names of the attributes that were changed, in
+	// alphabetical order.
+	Attributes []string `json:"attributes" yaml:"attributes"`
+	// Error holds the reason the credential could not be rotated, if
+	// any.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// CredentialPropagation records that a cloud credential has been copied
+// to a controller.
+type CredentialPropagation struct {
+	// CredentialTag is the tag of the cloud credential.
+	CredentialTag string `json:"credential-tag" yaml:"credential-tag"`
+	// Controller is the name of the controller the credential was
+	// copied to.
+	Controller string `json:"controller" yaml:"controller"`
+	// ControllerUUID is the UUID of the controller.
+	ControllerUUID string `json:"controller-uuid" yaml:"controller-uuid"`
+	// PropagatedAt is the time the credential was last copied to the
+	// controller.
+	PropagatedAt time.Time `json:"propagated-at" yaml:"propagated-at"`
+	// JIMMVersion is the version of JIMM that last copied the
+	// credential.
+	JIMMVersion string `json:"jimm-version" yaml:"jimm-version"`
+	// RevokedAt is the time the credential was revoked, if it has been.
+	RevokedAt *time.Time `json:"revoked-at,omitempty" yaml:"revoked-at,omitempty"`
+	// RemovalConfirmedAt is the time the controller confirmed that its
+	// copy of the revoked credential was removed, if it has.
+	RemovalConfirmedAt *time.Time `json:"removal-confirmed-at,omitempty" yaml:"removal-confirmed-at,omitempty"`
+	// VerificationAttempts is the number of times the removal of the
+	// revoked credential has been verified without being confirmed.
+	VerificationAttempts int `json:"verification-attempts,omitempty" yaml:"verification-attempts,omitempty"`
+	// LastVerifiedAt is the time the removal of the revoked credential
+	// was last verified, if it has been.
+	LastVerifiedAt *time.Time `json:"last-verified-at,omitempty" yaml:"last-verified-at,omitempty"`
+	// VerificationError holds the reason the last verification did not
+	// confirm the removal.
+	VerificationError string `json:"verification-error,omitempty" yaml:"verification-error,omitempty"`
+}
+
+// ListCredentialPropagationsRequest holds a request to list the
+// controllers cloud credentials have been copied to.
+type ListCredentialPropagationsRequest struct {
+	// CredentialTag, if set, restricts the list to the propagations of
+	// the credential.
+	CredentialTag string `json:"credential-tag,omitempty"`
+	// Unconfirmed restricts the list to the propagations of revoked
+	// credentials whose removal has not been confirmed.
+	Unconfirmed bool `json:"unconfirmed,omitempty"`
+}
+
+// ListCredentialPropagationsResponse holds the response to a
+// ListCredentialPropagations request.
+type ListCredentialPropagationsResponse struct {
+	// Propagations holds the propagations, ordered by credential and
+	// controller.
+	Propagations []CredentialPropagation `json:"propagations" yaml:"propagations"`
+}
+
+// ControllerCallRequest holds a request to call a controller facade
+// method on a controller.
+type ControllerCallRequest struct {
+	// Controller is the name of the controller to call.
+	Controller string `json:"controller"`
+	// Facade is the name of the controller facade.
+	Facade string `json:"facade"`
+	// Version is the version of the facade.
+	Version int `json:"version"`
+	// Method is the name of the facade method.
+	Method string `json:"method"`
+	// Params holds the arguments of the method, they are passed to the
+	// controller unchanged.
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// ControllerCallResponse holds the response to a controller facade call.
+type ControllerCallResponse struct {
+	// Result holds the controller's response, unchanged.
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// OutdatedCharm describes an application using an older revision of a
+// Charmhub charm than the latest released to Charmhub.
+type OutdatedCharm struct {
+	// Controller is the name of the controller hosting the model.
+	Controller string `json:"controller" yaml:"controller"`
+	// ModelUUID is the UUID of the model containing the application.
+	ModelUUID string `json:"model-uuid" yaml:"model-uuid"`
+	// Model is the name of the model containing the application.
+	Model string `json:"model" yaml:"model"`
+	// Application is the name of the application.
+	Application string `json:"application" yaml:"application"`
+	// Charm is the name of the charm.
+	Charm string `json:"charm" yaml:"charm"`
+	// Revision is the revision of the charm used by the application.
+	Revision int `json:"revision" yaml:"revision"`
+	// LatestRevision is the latest revision of the charm released to
+	// Charmhub.
+	LatestRevision int `json:"latest-revision" yaml:"latest-revision"`
+}
+
+// ListOutdatedCharmsResponse holds the response to a ListOutdatedCharms
+// request.
+type ListOutdatedCharmsResponse struct {
+	// Charms holds the applications using outdated charms.
+	Charms []OutdatedCharm `json:"charms" yaml:"charms"`
+}
+
+// LatencyMeasurement holds the result of the latest probe of an endpoint
+// by JIMM.
+type LatencyMeasurement struct {
+	// Address is the address that was probed.
+	Address string `json:"address" yaml:"address"`
+	// LatencyMS is the time taken to connect to the address, in
+	// milliseconds.
+	LatencyMS int64 `json:"latency-ms" yaml:"latency-ms"`
+	// Time is the time the probe was made.
+	Time time.Time `json:"time" yaml:"time"`
+	// Error holds the error from the probe, if it failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// RegionLatency holds the measured latency of a cloud region's endpoint.
+type RegionLatency struct {
+	// Cloud is the name of the cloud.
+	Cloud string `json:"cloud" yaml:"cloud"`
+	// Region is the name of the region.
+	Region string `json:"region" yaml:"region"`
+	// Measurement holds the latest probe of the region's endpoint.
+	Measurement LatencyMeasurement `json:"measurement" yaml:"measurement"`
+}
+
+// ControllerLatency holds the measured latency of a controller.
+type ControllerLatency struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+	// Measurement holds the latest probe of the controller.
+	Measurement LatencyMeasurement `json:"measurement" yaml:"measurement"`
+}
+
+// PlacementLatencies holds the response to a ListPlacementLatencies
+// request, the latencies used to place latency sensitive models.
+type PlacementLatencies struct {
+	// Regions holds the latencies of the cloud regions.
+	Regions []RegionLatency `json:"regions" yaml:"regions"`
+	// Controllers holds the latencies of the controllers.
+	Controllers []ControllerLatency `json:"controllers" yaml:"controllers"`
+}
+
+// DomainDefaultCloud holds the default cloud of an identity domain.
+type DomainDefaultCloud struct {
+	// Domain is the identity domain, for example "canonical.com".
+	Domain string `json:"domain" yaml:"domain"`
+	// Cloud is the name of the default cloud.
+	Cloud string `json:"cloud" yaml:"cloud"`
+	// Region is the name of the default region, if any.
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+}
+
+// SetDomainDefaultCloudRequest holds a request to set the default cloud
+// of an identity domain.
+type SetDomainDefaultCloudRequest struct {
+	// Domain is the identity domain, with or without a leading "@".
+	Domain string `json:"domain"`
+	// Cloud is the name of the default cloud.
+	Cloud string `json:"cloud"`
+	// Region is the name of the default region. If this is empty the
+	// region is chosen as if the domain had no default.
+	Region string `json:"region,omitempty"`
+}
+
+// RemoveDomainDefaultCloudRequest holds a request to remove the default
+// cloud of an identity domain.
+type RemoveDomainDefaultCloudRequest struct {
+	// Domain is the identity domain, with or without a leading "@".
+	Domain string `json:"domain"`
+}
+
+// ListDomainDefaultCloudsResponse holds the response to a
+// ListDomainDefaultClouds request.
+type ListDomainDefaultCloudsResponse struct {
+	// Defaults holds the default clouds of the identity domains.
+	Defaults []DomainDefaultCloud `json:"defaults" yaml:"defaults"`
+}
+
+// PurgeIdentityRequest holds a request to permanently remove an identity
+// and its personal data.
+type PurgeIdentityRequest struct {
+	// Name is the name of the identity to purge.
+	Name string `json:"name"`
+	// DeleteAuditLog requests that the audit log entries of the
+	// identity are deleted. Otherwise they are kept and attributed to
+	// the tombstone that replaces the identity.
+	DeleteAuditLog bool `json:"delete-audit-log,omitempty"`
+}
+
+// PurgeIdentityResponse holds the response to a PurgeIdentity request.
+type PurgeIdentityResponse struct {
+	// Tombstone is the anonymous name that replaces the identity in the
+	// records that are kept.
+	Tombstone string `json:"tombstone" yaml:"tombstone"`
+	// CloudCredentials holds the tags of the identity's cloud
+	// credentials that were removed.
+	CloudCredentials []string `json:"cloud-credentials,omitempty" yaml:"cloud-credentials,omitempty"`
+	// AuditLogEntries is the number of the identity's audit log entries
+	// that were anonymized or deleted.
+	AuditLogEntries int64 `json:"audit-log-entries" yaml:"audit-log-entries"`
 }
 
-// Service Account related request parameters
+// NormalizeIdentitiesRequest holds a request to rename the existing
+// identities to their normalized names.
+type NormalizeIdentitiesRequest struct {
+	// DryRun requests that the renames are reported without being made.
+	DryRun bool `json:"dry-run,omitempty"`
+}
 
-// LoginWithClientCredentialsRequest holds the client id and secret used
-// to authenticate with JIMM.
-type LoginWithClientCredentialsRequest struct {
-	ClientID     string `json:"client-id"`
-	ClientSecret string `json:"client-secret"`
+// An IdentityRename describes the rename of an identity to its normalized
+// name.
+type IdentityRename struct {
+	// From is the name of the identity before the rename.
+	From string `json:"from" yaml:"from"`
+	// To is the normalized name of the identity.
+	To string `json:"to" yaml:"to"`
+	// Error holds the reason the identity could not be renamed, if it
+	// was not.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
-// AddServiceAccountRequest holds a request to add a service account.
-type AddServiceAccountRequest struct {
-	// ClientID holds the client id of the service account.
-	ClientID string `json:"client-id"`
+// NormalizeIdentitiesResponse holds the response to a NormalizeIdentities
+// request.
+type NormalizeIdentitiesResponse struct {
+	// Renames holds the renames of the identities whose names are not
+	// normalized.
+	Renames []IdentityRename `json:"renames" yaml:"renames"`
 }
 
-// CopyServiceAccountCredentialRequest holds a request to copy a user cloud-credential to a service account.
-type CopyServiceAccountCredentialRequest struct {
-	jujuparams.CloudCredentialArg
-	// ClientID holds the client id of the service account.
-	ClientID string `json:"client-id"`
+// A TunableChange describes a change to a setting made when the tunables
+// were reloaded.
+type TunableChange struct {
+	// Name is the name of the setting.
+	Name string `json:"name" yaml:"name"`
+	// Old is the value of the setting before the reload.
+	Old string `json:"old" yaml:"old"`
+	// New is the value of the setting after the reload.
+	New string `json:"new" yaml:"new"`
 }
 
-// UpdateServiceAccountCredentialsRequest holds a request to update
-// a service accounts cloud credentials.
-type UpdateServiceAccountCredentialsRequest struct {
-	jujuparams.UpdateCredentialArgs
-	// ClientID holds the client id of the service account.
-	ClientID string `json:"client-id"`
+// ReloadTunablesResponse holds the response to a ReloadTunables request.
+type ReloadTunablesResponse struct {
+	// Changes holds the settings changed by the reload.
+	Changes []TunableChange `json:"changes,omitempty" yaml:"changes,omitempty"`
 }
 
-// ListServiceAccountCredentialsRequest holds a request to list
-// a service accounts cloud credentials.
-type ListServiceAccountCredentialsRequest struct {
+// SetOfferConsumerLimitRequest holds a request to limit the number of
+// models that may consume an application offer.
+type SetOfferConsumerLimitRequest struct {
+	// OfferURL is the URL of the application offer.
+	OfferURL string `json:"offer-url"`
+	// Limit is the maximum number of consuming models. Zero removes the
+	// offer's limit.
+	Limit int `json:"limit"`
+}
+
+// OfferConsumptionRequest holds a request for the consumption of an
+// application offer.
+type OfferConsumptionRequest struct {
+	// OfferURL is the URL of the application offer.
+	OfferURL string `json:"offer-url"`
+}
+
+// OfferConsumption holds the consumption of an application offer against
+// its consumer limit.
+type OfferConsumption struct {
+	// OfferURL is the URL of the application offer.
+	OfferURL string `json:"offer-url" yaml:"offer-url"`
+	// Consumers is the number of models consuming the offer.
+	Consumers int `json:"consumers" yaml:"consumers"`
+	// ConsumingModels holds the tags of the models consuming the offer.
+	ConsumingModels []string `json:"consuming-models,omitempty" yaml:"consuming-models,omitempty"`
+	// Limit is the maximum number of models that may consume the offer,
+	// zero if there is no limit.
+	Limit int `json:"limit" yaml:"limit"`
+}
+
+// SearchApplicationsRequest holds a request to search for applications,
+// across all models, by the interface or name of their endpoints. At
+// least one of Interface and Endpoint must be specified.
+type SearchApplicationsRequest struct {
+	// Interface, if specified, matches applications with an endpoint
+	// using the given interface, for example "postgresql_client".
+	Interface string `json:"interface,omitempty"`
+	// Endpoint, if specified, matches applications with an endpoint of
+	// the given name.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Role, if specified, matches only endpoints with the given role,
+	// "provider", "requirer" or "peer".
+	Role string `json:"role,omitempty"`
+	// ExposedOnly, if true, matches only applications that are exposed
+	// or offered for consumption.
+	ExposedOnly bool `json:"exposed-only,omitempty"`
+}
+
+// ApplicationSearchResult describes an application endpoint found by a
+// search.
+type ApplicationSearchResult struct {
+	// Controller is the name of the controller hosting the application.
+	Controller string `json:"controller" yaml:"controller"`
+	// ModelUUID is the UUID of the model containing the application.
+	ModelUUID string `json:"model-uuid" yaml:"model-uuid"`
+	// ModelName is the name of the model containing the application.
+	ModelName string `json:"model-name" yaml:"model-name"`
+	// Owner is the owner of the model containing the application.
+	Owner string `json:"owner" yaml:"owner"`
+	// Application is the name of the application.
+	Application string `json:"application" yaml:"application"`
+	// CharmURL is the URL of the application's charm, if known.
+	CharmURL string `json:"charm-url,omitempty" yaml:"charm-url,omitempty"`
+	// Exposed is true if the application is exposed.
+	Exposed bool `json:"exposed" yaml:"exposed"`
+	// Endpoint is the name of the matching endpoint.
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Role is the role of the matching endpoint.
+	Role string `json:"role" yaml:"role"`
+	// Interface is the interface of the matching endpoint.
+	Interface string `json:"interface" yaml:"interface"`
+	// OfferURLs holds the URLs of the offers of the application that
+	// include the matching endpoint.
+	OfferURLs []string `json:"offer-urls,omitempty" yaml:"offer-urls,omitempty"`
+}
+
+// SearchApplicationsResponse holds the results of an application search.
+type SearchApplicationsResponse struct {
+	Results []ApplicationSearchResult `json:"results" yaml:"results"`
+}
+
+// DeletedModel holds the details of a model that has been removed from
+// JIMM and whose record is retained so that it may be restored.
+type DeletedModel struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+
+	// Owner is the name of the model's owner.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Controller is the name of the controller that hosted the model.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Cloud and CloudRegion are the cloud and region that hosted the
+	// model.
+	Cloud       string `json:"cloud" yaml:"cloud"`
+	CloudRegion string `json:"cloud-region" yaml:"cloud-region"`
+
+	// CloudCredential is the path of the cloud credential used by the
+	// model.
+	CloudCredential string `json:"cloud-credential" yaml:"cloud-credential"`
+
+	// Type is the type of the model.
+	Type string `json:"type" yaml:"type"`
+
+	// Life is the life status of the model when it was removed.
+	Life string `json:"life" yaml:"life"`
+
+	// CostCenter is the cost center the model was attributed to.
+	CostCenter string `json:"cost-center,omitempty" yaml:"cost-center,omitempty"`
+
+	// Origin is the kind of origin of the model.
+	Origin string `json:"origin,omitempty" yaml:"origin,omitempty"`
+
+	// CreatedAt is the time the model was added to JIMM.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+
+	// DeletedAt is the time the model was removed from JIMM.
+	DeletedAt time.Time `json:"deleted-at" yaml:"deleted-at"`
+}
+
+// ListDeletedModelsResponse holds the models removed from JIMM whose
+// records are retained.
+type ListDeletedModelsResponse struct {
+	// Models holds the deleted models, the most recently deleted first.
+	Models []DeletedModel `json:"models"`
+}
+
+// DeletedModelRequest holds a request referring to a model that has been
+// removed from JIMM.
+type DeletedModelRequest struct {
+	// ModelTag is the tag of the removed model.
+	ModelTag string `json:"model-tag"`
+}
+
+// A ReadConsistency determines where JIMM reads the data returned by a
+// read request from.
+type ReadConsistency string
+
+const (
+	// ReadConsistencyCached requests that data is read from JIMM's
+	// store. Cached reads are fast but may lag behind the state of the
+	// controller hosting the resource.
+	ReadConsistencyCached ReadConsistency = "cached"
+
+	// ReadConsistencyFresh requests that data is read from the
+	// controller hosting the resource.
+	ReadConsistencyFresh ReadConsistency = "fresh"
+)
+
+// ReadConsistencyHeader is the HTTP header that may be sent when opening
+// an API connection to set the read consistency of requests on the
+// connection that do not specify one.
+const ReadConsistencyHeader = "Jimm-Read-Consistency"
+
+// ConsistentEntities holds the parameters of the ModelInfo and
+// ModelStatus requests along with the read consistency to use. If no
+// consistency is specified the connection's read consistency is used,
+// and if that is not set the models are read from their controllers.
+type ConsistentEntities struct {
+	jujuparams.Entities
+
+	// Consistency holds the requested read consistency.
+	Consistency ReadConsistency `json:"consistency,omitempty"`
+}
+
+// ModelSummaryResults holds the response of a ListModelSummaries
+// request. As well as the standard results it reports whether the
+// results are incomplete, which happens when JIMM could not read every
+// model summary before its deadline.
+type ModelSummaryResults struct {
+	Results []ModelSummaryResult `json:"results"`
+
+	// Incomplete is true if the results do not include every model the
+	// user has access to.
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// ModelSummaryResult holds the summary of a single model, or the error
+// encountered reading it, and whether the summary may be out of date.
+type ModelSummaryResult struct {
+	jujuparams.ModelSummaryResult
+
+	// StaleSince is set if the summary may be out of date because the
+	// controller hosting the model has been unavailable, in which case
+	// it holds the time the controller became unavailable.
+	StaleSince *time.Time `json:"stale-since,omitempty"`
+}
+
+// ConsistentCloudCredentialArgs holds the parameters of the
+// CredentialContents request along with the read consistency to use. If
+// no consistency is specified the connection's read consistency is used,
+// and if that is not set the credentials are read from JIMM's store. A
+// fresh read takes the validity of each credential from a controller
+// hosting a model that uses it.
+type ConsistentCloudCredentialArgs struct {
 	jujuparams.CloudCredentialArgs
-	// ClientID holds the client id of the service account.
-	ClientID string `json:"client-id"`
+
+	// Consistency holds the requested read consistency.
+	Consistency ReadConsistency `json:"consistency,omitempty"`
 }
 
-// ListServiceAccountCredentialsRequest holds a request to list
-// a service accounts cloud credentials.
-type GrantServiceAccountAccess struct {
-	// Entities holds a slice of entities (identities and groups)
-	// that should have administration access to the desired clientID.
-	Entities []string `json:"entities"`
-	// ClientID holds the client id of the service account.
-	ClientID string `json:"client-id"`
+// ScheduledJob holds the details of a periodic job run by JIMM's
+// scheduler.
+type ScheduledJob struct {
+	// Name is the name of the job.
+	Name string `json:"name" yaml:"name"`
+
+	// Description describes what the job does.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Schedule is the cron expression, or @every <duration>, the job
+	// runs on.
+	Schedule string `json:"schedule" yaml:"schedule"`
+
+	// Jitter is the maximum random delay added to each run of the job.
+	Jitter time.Duration `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+
+	// Enabled reports whether the job is run.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// NextRun is the time the job is next due to run, not including any
+	// jitter. It is not set for disabled jobs.
+	NextRun *time.Time `json:"next-run,omitempty" yaml:"next-run,omitempty"`
+
+	// LastRun holds the most recent run of the job, if it has run.
+	LastRun *ScheduledJobRun `json:"last-run,omitempty" yaml:"last-run,omitempty"`
 }
 
-// WhoamiResponse holds the response for a /auth/whoami call.
-type WhoamiResponse struct {
-	DisplayName string `json:"display-name" yaml:"display-name"`
-	Email       string `json:"email" yaml:"email"`
+// ScheduledJobRun holds the details of a single run of a scheduled job.
+type ScheduledJobRun struct {
+	// Job is the name of the job.
+	Job string `json:"job" yaml:"job"`
+
+	// Holder identifies the JIMM replica that ran the job.
+	Holder string `json:"holder,omitempty" yaml:"holder,omitempty"`
+
+	// StartedAt is the time the run started.
+	StartedAt time.Time `json:"started-at" yaml:"started-at"`
+
+	// FinishedAt is the time the run finished, it is not set for runs
+	// still in progress, or whose replica stopped during the run.
+	FinishedAt *time.Time `json:"finished-at,omitempty" yaml:"finished-at,omitempty"`
+
+	// Error holds the error the run failed with, if it failed.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
-// VersionResponse holds the response for a version call.
-type VersionResponse struct {
-	Version string `json:"version" yaml:"version"`
-	Commit  string `json:"commit" yaml:"commit"`
+// ListScheduledJobsResponse holds the response of a ListScheduledJobs
+// request.
+type ListScheduledJobsResponse struct {
+	Jobs []ScheduledJob `json:"jobs" yaml:"jobs"`
+}
+
+// ScheduledJobHistoryRequest holds a request for the run history of a
+// scheduled job.
+type ScheduledJobHistoryRequest struct {
+	// Job is the name of the job.
+	Job string `json:"job"`
+
+	// Limit is the maximum number of runs returned, if it is zero a
+	// default limit is used.
+	Limit int `json:"limit,omitempty"`
+}
+
+// ScheduledJobHistoryResponse holds the response of a
+// ScheduledJobHistory request.
+type ScheduledJobHistoryResponse struct {
+	// Runs holds the runs of the job, the most recent first.
+	Runs []ScheduledJobRun `json:"runs" yaml:"runs"`
+}
+
+// SetScheduledJobEnabledRequest holds a request to enable, or disable, a
+// scheduled job.
+type SetScheduledJobEnabledRequest struct {
+	// Job is the name of the job.
+	Job string `json:"job"`
+
+	// Enabled is whether the job should be run.
+	Enabled bool `json:"enabled"`
+}
+
+// A ModelDependency describes a model's declared dependency on another
+// model.
+type ModelDependency struct {
+	// ModelTag is the tag of the dependent model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// ModelName is the name of the dependent model, in the form
+	// <owner>/<name>.
+	ModelName string `json:"model-name" yaml:"model-name"`
+
+	// DependsOnModelTag is the tag of the model depended on.
+	DependsOnModelTag string `json:"depends-on-model-tag" yaml:"depends-on-model-tag"`
+
+	// DependsOnModelName is the name of the model depended on, in the
+	// form <owner>/<name>.
+	DependsOnModelName string `json:"depends-on-model-name" yaml:"depends-on-model-name"`
+
+	// Description describes why the model depends on the other model.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// CreatedBy is the name of the user that declared the dependency.
+	CreatedBy string `json:"created-by" yaml:"created-by"`
+
+	// CreatedAt is the time the dependency was declared.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// AddModelDependencyRequest holds a request to declare that a model
+// depends on another model.
+type AddModelDependencyRequest struct {
+	// ModelTag is the tag of the dependent model.
+	ModelTag string `json:"model-tag"`
+
+	// DependsOnModelTag is the tag of the model depended on.
+	DependsOnModelTag string `json:"depends-on-model-tag"`
+
+	// Description describes why the model depends on the other model.
+	Description string `json:"description,omitempty"`
+}
+
+// RemoveModelDependencyRequest holds a request to remove a model's
+// dependency on another model.
+type RemoveModelDependencyRequest struct {
+	// ModelTag is the tag of the dependent model.
+	ModelTag string `json:"model-tag"`
+
+	// DependsOnModelTag is the tag of the model depended on.
+	DependsOnModelTag string `json:"depends-on-model-tag"`
+}
+
+// ModelDependenciesRequest holds a request for the dependencies of a
+// model.
+type ModelDependenciesRequest struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag"`
+}
+
+// ModelDependencies describes the place of a model in the model
+// dependency topology.
+type ModelDependencies struct {
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// DependsOn holds the dependencies declared by the model.
+	DependsOn []ModelDependency `json:"depends-on" yaml:"depends-on"`
+
+	// Dependents holds the dependencies other models have declared on
+	// the model.
+	Dependents []ModelDependency `json:"dependents" yaml:"dependents"`
+
+	// AffectedModels holds the names, in the form <owner>/<name>, of
+	// every model that depends on the model either directly or through
+	// other models. These are the models affected if the model is
+	// destroyed.
+	AffectedModels []string `json:"affected-models,omitempty" yaml:"affected-models,omitempty"`
+}
+
+// RemapCloudRegionRequest holds a request to remap a cloud region in
+// JIMM's records, for example after the cloud provider renamed or split
+// the region.
+type RemapCloudRegionRequest struct {
+	// Cloud is the name of the cloud.
+	Cloud string `json:"cloud"`
+
+	// Region is the name of the region being remapped.
+	Region string `json:"region"`
+
+	// NewRegion is the name of the region to remap to. Every
+	// controller serving the region must know the new region.
+	NewRegion string `json:"new-region"`
+
+	// Models holds the UUIDs of the models to move to the new region
+	// when the region is split. If it is empty the whole region is
+	// remapped and the old name is kept as an alias of the new region.
+	Models []string `json:"models,omitempty"`
+}
+
+// RemapCloudRegionResponse holds the response of a RemapCloudRegion
+// request.
+type RemapCloudRegionResponse struct {
+	// Region is the name of the region the models were remapped to.
+	Region string `json:"region" yaml:"region"`
+
+	// Models holds the UUIDs of the models remapped.
+	Models []string `json:"models" yaml:"models"`
+
+	// Alias is the old name of the region, kept as an alias of the new
+	// region, if the whole region was remapped.
+	Alias string `json:"alias,omitempty" yaml:"alias,omitempty"`
+}
+
+// RotateWebhookSecretRequest holds the request to rotate the secret the
+// deliveries to a webhook are signed with.
+type RotateWebhookSecretRequest struct {
+	// Webhook is the name of the webhook.
+	Webhook string `json:"webhook"`
+}
+
+// WebhookSecret holds a webhook's signing secret, returned only when the
+// secret is rotated.
+type WebhookSecret struct {
+	// Webhook is the name of the webhook.
+	Webhook string `json:"webhook" yaml:"webhook"`
+
+	// Secret is the hex encoded signing secret.
+	Secret string `json:"secret" yaml:"secret"`
+
+	// RotatedAt is the time the secret was rotated. Deliveries are also
+	// signed with the previous secret for a grace period after this.
+	RotatedAt time.Time `json:"rotated-at" yaml:"rotated-at"`
+}
+
+// ListWebhookDeliveriesRequest holds the request to list the signed
+// deliveries to a webhook.
+type ListWebhookDeliveriesRequest struct {
+	// Webhook is the name of the webhook.
+	Webhook string `json:"webhook"`
+
+	// Limit is the maximum number of deliveries to list, newest first.
+	// If this is not positive all recorded deliveries are listed.
+	Limit int `json:"limit,omitempty"`
+}
+
+// WebhookDelivery describes a signed delivery to a webhook.
+type WebhookDelivery struct {
+	// Webhook is the name of the webhook.
+	Webhook string `json:"webhook" yaml:"webhook"`
+
+	// Nonce is the value unique to the delivery.
+	Nonce string `json:"nonce" yaml:"nonce"`
+
+	// Timestamp is the time the delivery was signed, in seconds since
+	// the Unix epoch.
+	Timestamp int64 `json:"timestamp" yaml:"timestamp"`
+
+	// Signature is the value of the signature header of the delivery.
+	Signature string `json:"signature" yaml:"signature"`
+
+	// CreatedAt is the time the delivery was recorded.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+}
+
+// ListWebhookDeliveriesResponse holds the response of a
+// ListWebhookDeliveries request.
+type ListWebhookDeliveriesResponse struct {
+	// Deliveries holds the deliveries, newest first.
+	Deliveries []WebhookDelivery `json:"deliveries" yaml:"deliveries"`
+}
+
+// ExportModelRequest holds the request to export a model's JIMM metadata
+// so that the model can be imported by another JIMM deployment.
+type ExportModelRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+}
+
+// A ModelExport holds the JIMM metadata of a model being moved to
+// another JIMM deployment. It is produced by ExportModel on the source
+// JIMM and given to PrepareModelImport on the target JIMM.
+type ModelExport struct {
+	// SourceJIMM is the tag of the JIMM the model is exported from.
+	SourceJIMM string `json:"source-jimm" yaml:"source-jimm"`
+
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// Name is the name of the model.
+	Name string `json:"name" yaml:"name"`
+
+	// Owner is the name of the model's owner.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Cloud is the name of the model's cloud.
+	Cloud string `json:"cloud" yaml:"cloud"`
+
+	// CloudRegion is the name of the model's cloud region.
+	CloudRegion string `json:"cloud-region" yaml:"cloud-region"`
+
+	// CloudCredential is the name of the model's cloud credential.
+	// The credential itself is moved with the model by the migration.
+	CloudCredential string `json:"cloud-credential" yaml:"cloud-credential"`
+
+	// Access holds the access users and groups hold directly on the
+	// model, other than its owner.
+	Access ModelAccessSpec `json:"access" yaml:"access"`
+
+	// ExportedAt is the time the model was exported.
+	ExportedAt time.Time `json:"exported-at" yaml:"exported-at"`
+}
+
+// PrepareModelImportRequest holds the request to prepare the import of a
+// model exported from another JIMM deployment.
+type PrepareModelImportRequest struct {
+	// Export is the model's export from the source JIMM.
+	Export ModelExport `json:"export"`
+
+	// Controller is the name of the controller the model is migrated
+	// to.
+	Controller string `json:"controller"`
+
+	// Identities maps the names of users in the source JIMM to their
+	// names in the target JIMM. Users not in the map keep their names.
+	Identities map[string]string `json:"identities,omitempty"`
+
+	// Groups maps the names of groups in the source JIMM to their
+	// names in the target JIMM. Groups not in the map keep their names.
+	Groups map[string]string `json:"groups,omitempty"`
+}
+
+// A ModelImport describes the import of a model exported from another
+// JIMM deployment.
+type ModelImport struct {
+	// ID is the ID of the import.
+	ID uint `json:"id" yaml:"id"`
+
+	// ModelTag is the tag of the model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// SourceJIMM is the tag of the JIMM the model is exported from.
+	SourceJIMM string `json:"source-jimm" yaml:"source-jimm"`
+
+	// Controller is the name of the controller the model is migrated
+	// to.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Owner is the name of the model's owner in the target JIMM.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Access holds the access users and groups will hold on the model
+	// in the target JIMM.
+	Access ModelAccessSpec `json:"access" yaml:"access"`
+
+	// Status is the status of the import, "prepared" or "completed".
+	Status string `json:"status" yaml:"status"`
+
+	// TargetInfo holds the details the source JIMM uses to migrate the
+	// model to the controller. It is only returned when the import is
+	// prepared and holds the controller's admin credentials, so it must
+	// be handled as a secret.
+	TargetInfo *jujuparams.MigrationTargetInfo `json:"target-info,omitempty" yaml:"target-info,omitempty"`
+
+	// CreatedAt is the time the import was prepared.
+	CreatedAt time.Time `json:"created-at" yaml:"created-at"`
+
+	// CompletedAt is the time the import was completed.
+	CompletedAt *time.Time `json:"completed-at,omitempty" yaml:"completed-at,omitempty"`
+}
+
+// MigrateModelExternalRequest holds the request to migrate a model to a
+// controller managed by another JIMM deployment, which has prepared the
+// model's import.
+type MigrateModelExternalRequest struct {
+	// ModelTag identifies the model by its tag, its UUID or a path of
+	// the form <owner>/<name>.
+	ModelTag string `json:"model-tag"`
+
+	// Import is the import prepared by the target JIMM.
+	Import ModelImport `json:"import"`
+}
+
+// MigrateModelExternalResponse holds the response of a
+// MigrateModelExternal request.
+type MigrateModelExternalResponse struct {
+	// ModelTag is the tag of the migrating model.
+	ModelTag string `json:"model-tag" yaml:"model-tag"`
+
+	// MigrationID is the ID of the migration on the source controller.
+	MigrationID string `json:"migration-id" yaml:"migration-id"`
+}
+
+// CompleteModelImportRequest holds the request to complete the import of
+// a model once its migration to the target controller has finished.
+type CompleteModelImportRequest struct {
+	// ID is the ID of the import.
+	ID uint `json:"id"`
+}
+
+// RepairDenormalizedFieldsRequest holds the request to recompute the
+// denormalized fields held on models and controllers.
+type RepairDenormalizedFieldsRequest struct {
+	// DryRun reports the repairs without making them.
+	DryRun bool `json:"dry-run,omitempty"`
+}
+
+// A DenormalizedFieldRepair describes a denormalized field that did not
+// match the value recomputed from its authoritative source.
+type DenormalizedFieldRepair struct {
+	// Kind is the kind of the repaired entity, "model" or
+	// "controller".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Name is the name of the repaired entity, models are named by a
+	// path of the form <owner>/<name>.
+	Name string `json:"name" yaml:"name"`
+
+	// Field is the name of the repaired field.
+	Field string `json:"field" yaml:"field"`
+
+	// From is the field's stored value.
+	From string `json:"from" yaml:"from"`
+
+	// To is the field's recomputed value.
+	To string `json:"to" yaml:"to"`
+}
+
+// RepairDenormalizedFieldsResponse holds the response of a
+// RepairDenormalizedFields request.
+type RepairDenormalizedFieldsResponse struct {
+	// DryRun is true if no repairs were made.
+	DryRun bool `json:"dry-run,omitempty" yaml:"dry-run,omitempty"`
+
+	// Repairs holds the repairs made, or that would be made in a dry
+	// run, ordered by kind, name and field.
+	Repairs []DenormalizedFieldRepair `json:"repairs,omitempty" yaml:"repairs,omitempty"`
+}
+
+// ProvisionControllerServiceUserRequest holds a request to provision the
+// JIMM service user on a controller.
+type ProvisionControllerServiceUserRequest struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller"`
+}
+
+// ProvisionControllerServiceUserResponse holds the response of a
+// ProvisionControllerServiceUser request.
+type ProvisionControllerServiceUserResponse struct {
+	// Controller is the name of the controller.
+	Controller string `json:"controller" yaml:"controller"`
+
+	// Username is the name of the service user JIMM now connects to
+	// the controller as.
+	Username string `json:"username" yaml:"username"`
+}
+
+// An Operation describes a long-running operation started through JIMM.
+type Operation struct {
+	// ID is the ID of the operation. Anyone knowing the ID may follow
+	// the operation's progress, so it should only be shared with those
+	// who should.
+	ID string `json:"id" yaml:"id"`
+
+	// Kind is the kind of the operation, for example
+	// "rotate-cloud-credentials".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// StartedBy is the name of the user that started the operation.
+	StartedBy string `json:"started-by" yaml:"started-by"`
+
+	// Status is the status of the operation, one of "running",
+	// "succeeded" or "failed".
+	Status string `json:"status" yaml:"status"`
+
+	// Progress is the percentage of the operation that has been done.
+	Progress int `json:"progress" yaml:"progress"`
+
+	// Steps holds the steps of the operation completed so far, oldest
+	// first.
+	Steps []OperationStep `json:"steps,omitempty" yaml:"steps,omitempty"`
+
+	// Error holds the reason the operation failed, if it did.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Result holds the result of the operation once it has succeeded.
+	// The result has the type of the response of the synchronous form
+	// of the operation.
+	Result map[string]interface{} `json:"result,omitempty" yaml:"result,omitempty"`
+
+	// Started is the time the operation started.
+	Started time.Time `json:"started" yaml:"started"`
+
+	// Ended is the time the operation ended, if it has.
+	Ended *time.Time `json:"ended,omitempty" yaml:"ended,omitempty"`
+}
+
+// An OperationStep is an entry in the step log of an operation.
+type OperationStep struct {
+	// Time is the time the step was completed.
+	Time time.Time `json:"time" yaml:"time"`
+
+	// Message describes the step.
+	Message string `json:"message" yaml:"message"`
+}
+
+// GetOperationRequest holds a request to get an operation.
+type GetOperationRequest struct {
+	// ID is the ID of the operation.
+	ID string `json:"id"`
+}
+
+// ListOperationsRequest holds a request to list operations.
+type ListOperationsRequest struct {
+	// All lists the operations started by every user, rather than only
+	// those started by the requesting user. Only JIMM administrators may
+	// list every operation.
+	All bool `json:"all,omitempty"`
+}
+
+// ListOperationsResponse holds the response of a ListOperations
+// request.
+type ListOperationsResponse struct {
+	// Operations holds the operations, most recently started first.
+	// The steps of the operations are not included.
+	Operations []Operation `json:"operations" yaml:"operations"`
+}
+
+// WatchOperationRequest holds a request to watch the progress of an
+// operation.
+type WatchOperationRequest struct {
+	// ID is the ID of the operation.
+	ID string `json:"id"`
+}
+
+// OperationWatcherID holds the ID of an operation watcher created by
+// WatchOperation.
+type OperationWatcherID struct {
+	// WatcherID is the ID to pass to the Next and Stop methods of the
+	// OperationWatcher facade.
+	WatcherID string `json:"watcher-id"`
+}
+
+// OperationWatcherNextResults holds the result of a Next call on the
+// OperationWatcher facade.
+type OperationWatcherNextResults struct {
+	// Operation holds the operation as it was when it changed.
+	Operation Operation `json:"operation"`
 }